@@ -5,10 +5,14 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/coopernurse/pulserpc/pkg/generator"
+	"github.com/coopernurse/pulserpc/pkg/idl"
 	"github.com/coopernurse/pulserpc/pkg/parser"
+	"github.com/coopernurse/pulserpc/pkg/sqlimport"
 	"github.com/coopernurse/pulserpc/pkg/webui"
 )
 
@@ -17,14 +21,40 @@ func main() {
 	registerPlugins()
 
 	// Define global flags
-	var validate = flag.Bool("validate", false, "Validate the IDL after parsing")
+	var validate = flag.Bool("validate", false, "Validate the IDL after parsing (always done before -plugin generates code)")
+	var lint = flag.String("lint", "", "Comma separated rule=budget lint rules the IDL must keep within, e.g. 'max-methods=20,max-fields=30,max-params=5'; [nolint=\"rule\"] exempts a declaration")
 	var toJSON = flag.String("to-json", "", "Write parsed IDL as JSON to the specified file")
 	var fromJSON = flag.String("from-json", "", "Read JSON file and generate IDL text on STDOUT")
+	var fromSQL = flag.String("from-sql", "", "Read a SQL DDL file and generate IDL structs for its tables on STDOUT")
+	var sqlNamespace = flag.String("sql-namespace", "", "Namespace for IDL generated by -from-sql (defaults to the file name)")
 	var pluginName = flag.String("plugin", "", "Code generation plugin to use (e.g., python-client-server)")
 	var uiMode = flag.Bool("ui", false, "Start the embedded web UI server")
 	var uiPort = flag.Int("ui-port", 8080, "Port for the web UI server (default: 8080)")
 	_ = flag.String("dir", "", "Output directory for generated code") // Available to plugins via FlagSet
 	_ = flag.Bool("generate-test-files", false, "Generate test files (test_server.*, test_client.*)")
+	_ = flag.Bool("generate-test-vectors", false, "Generate testvectors.json with canonical request/response pairs for every method")
+	_ = flag.Bool("generate-test-harness", false, "Generate unit tests (go test, pytest, JUnit 5, xUnit) that call every method of your handlers in-process")
+	_ = flag.Bool("generate-test-builders", false, "Generate test data builders (Go, Python, Java, C#) that fill the required fields of every struct with valid defaults")
+	_ = flag.Bool("generate-contract-tests", false, "Generate consumer contract tests from the @example blocks of the IDL that call the service at $PULSERPC_CONTRACT_URL")
+	_ = flag.Bool("generate-shadow-client", false, "Generate a ShadowTransport that mirrors client calls to a second server and reports mismatching results")
+	_ = flag.Bool("generate-outbox-client", false, "Generate an OutboxTransport that queues calls to a file while the server is unreachable and sends them when it recovers")
+	_ = flag.Bool("generate-broker-transport", false, "Generate a BrokerTransport (Go, Python) that carries calls over a message broker's request/reply, such as NATS or AMQP")
+	_ = flag.Bool("generate-serverless-adapter", false, "Generate AWS Lambda (API Gateway proxy) and Cloud Functions adapters (Go, Python, C#) that serve calls through the same code as the HTTP server")
+	_ = flag.Bool("generate-fault-injection", false, "Let the generated servers (Go, Python, TypeScript) inject per-method latency, errors and malformed responses from a JSON fault config")
+	_ = flag.Bool("generate-admin-endpoint", false, "Give the generated servers (Go, Python, TypeScript) a token-protected admin endpoint reporting handlers, per-method call counts and latency, and the IDL checksum")
+	_ = flag.Bool("generate-patch-helpers", false, "Generate helpers that diff two values of a struct and apply changed-fields-only patches, where null clears an optional field")
+	_ = flag.Bool("generate-index-files", false, "Generate an index of every namespace's types: doc.go (Go), re-exports in __init__.py (Python, with -py-packages), package-info.java and <namespace>Types.java (Java) and GlobalUsings.cs (C#)")
+	_ = flag.Bool("optional-presence", false, "Generate optional struct fields (Go, C#) as tri-state values that tell an absent field from an explicit null")
+	_ = flag.Bool("dependency-manifest", false, "Also write the generated code's third-party dependencies with exact versions (Go dependencies.mod, Python requirements.txt, C# Dependencies.props, Java dependencies.xml)")
+	_ = flag.String("dependency-versions", "", "Comma separated name=version overrides of dependency versions, e.g. 'pytest=8.2.0,com.google.code.gson:gson=2.11.0'")
+	_ = flag.String("dependency-mirror", "", "URL prefix of an internal mirror the dependency manifests and generated build files (pom.xml, test .csproj files, Rust .cargo/config.toml) resolve packages from instead of the public registries")
+	_ = flag.String("style", "", "Comma separated key=value code style of the generated Python, TypeScript, Java and C#: indent=N, quotes=single|double (Python), braces=same-line|next-line (Java, C#) and getters=get|record (Java), e.g. 'indent=2,braces=next-line'")
+	_ = flag.String("template-dir", "", "Directory of templates that replace the embedded ones of the Go, Python, TypeScript, Java and C# generators, at the same relative paths, e.g. go/server.go.tmpl (see 'pulse templates')")
+	_ = flag.Bool("sbom", false, "Also write sbom.cdx.json, a CycloneDX SBOM of the runtime files and third-party dependencies shipped with the generated code")
+	_ = flag.String("idl-json", "idl.json", "Path, relative to -dir, of the IDL JSON document the generated Go, Python, TypeScript, Java and Rust servers return from pulserpc-idl, or 'none' to embed it in the server instead of writing it")
+	_ = flag.Bool("generate-repo-files", false, "Also write .gitattributes marking the generated files linguist-generated, so code review tools collapse their diffs, and an .editorconfig matching their code style into the output directory")
+	var verify = flag.Bool("verify", false, "Compile the generated code with the target toolchain and fail if it does not build")
+	_ = flag.Bool("offline", false, "Fail -verify instead of letting the toolchains download modules, packages or crates, for air-gapped builds")
 
 	// Register flags for all plugins
 	allPlugins := getAllPlugins()
@@ -34,6 +64,24 @@ func main() {
 
 	flag.Parse()
 
+	// Handle plugin help mode
+	if flag.Arg(0) == "help" {
+		handleHelp(flag.Args()[1:])
+		return
+	}
+
+	// Handle IDL diff mode
+	if flag.Arg(0) == "diff" {
+		handleDiff(flag.Args()[1:])
+		return
+	}
+
+	// Handle template listing and export mode
+	if flag.Arg(0) == "templates" {
+		handleTemplates(flag.Args()[1:])
+		return
+	}
+
 	// Handle UI server mode - must be checked early
 	if *uiMode {
 		server := webui.NewServer(*uiPort)
@@ -44,6 +92,16 @@ func main() {
 		return
 	}
 
+	// Reject plugin flags the selected plugin does not read. An unknown plugin is
+	// reported when generation starts.
+	selected, ok := generator.Get(*pluginName)
+	if *pluginName == "" || ok {
+		if err := generator.CheckFlags(flag.CommandLine, selected, allPlugins); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Check for mutual exclusivity
 	if *toJSON != "" && *fromJSON != "" {
 		fmt.Fprintf(os.Stderr, "error: -to-json and -from-json cannot be used together\n")
@@ -55,6 +113,17 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *fromSQL != "" && (*pluginName != "" || *toJSON != "" || *fromJSON != "") {
+		fmt.Fprintf(os.Stderr, "error: -from-sql cannot be used with -plugin, -to-json or -from-json\n")
+		os.Exit(1)
+	}
+
+	// Handle SQL DDL input mode
+	if *fromSQL != "" {
+		handleSQLInput(*fromSQL, *sqlNamespace)
+		return
+	}
+
 	// Handle JSON input mode
 	if *fromJSON != "" {
 		handleJSONInput(*fromJSON)
@@ -90,17 +159,22 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Validate if flag is set
-	if *validate {
+	// Validate if flag is set. Code is only generated from a valid IDL.
+	if *validate || *pluginName != "" {
 		if err := parser.ValidateIDL(idl); err != nil {
 			fmt.Fprintf(os.Stderr, "error: validation failed: %v\n", err)
 			os.Exit(1)
 		}
 	}
 
+	// Lint if flag is set
+	if *lint != "" {
+		handleLint(idl, *lint)
+	}
+
 	// Handle plugin generation mode
 	if *pluginName != "" {
-		handlePluginGeneration(*pluginName, idl)
+		handlePluginGeneration(*pluginName, idl, *verify)
 		return
 	}
 
@@ -114,6 +188,90 @@ func main() {
 	prettyPrintIDL(idl)
 }
 
+// handleLint exits with the lint issues of the IDL, if it has any
+func handleLint(idl *parser.IDL, spec string) {
+	cfg, err := parser.ParseLintConfig(spec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: -lint: %v\n", err)
+		os.Exit(1)
+	}
+	issues := parser.Lint(idl, cfg)
+	if len(issues) == 0 {
+		return
+	}
+	for _, issue := range issues {
+		fmt.Fprintf(os.Stderr, "%s:%s\n", issue.Pos.Filename, issue)
+	}
+	fmt.Fprintf(os.Stderr, "error: lint failed with %d issue(s)\n", len(issues))
+	os.Exit(1)
+}
+
+// handleDiff prints the changes between two versions of an IDL and exits with
+// status 1 if any of them would break clients or servers of the old version
+func handleDiff(args []string) {
+	if len(args) != 2 {
+		fmt.Fprintf(os.Stderr, "usage: pulse diff <old-idl-file> <new-idl-file>\n")
+		os.Exit(1)
+	}
+	idls := make([]*parser.IDL, 2)
+	for i, filename := range args {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: failed to read file %s: %v\n", filename, err)
+			os.Exit(1)
+		}
+		idl, err := parser.ParseIDL(filename, string(content))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		idls[i] = idl
+	}
+	changes := parser.Diff(idls[0], idls[1])
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if parser.HasBreakingChanges(changes) {
+		fmt.Fprintf(os.Stderr, "error: %s has breaking changes\n", args[1])
+		os.Exit(1)
+	}
+}
+
+// handleTemplates lists the embedded code generation templates, or with export writes
+// them under a directory as the starting point of a -template-dir
+func handleTemplates(args []string) {
+	if len(args) == 0 {
+		for _, name := range generator.TemplateNames() {
+			fmt.Println(name)
+		}
+		return
+	}
+	if args[0] != "export" || len(args) < 2 {
+		fmt.Fprintf(os.Stderr, "usage: pulse templates [export <dir> [template...]]\n")
+		os.Exit(1)
+	}
+	dir, names := args[1], args[2:]
+	if len(names) == 0 {
+		names = generator.TemplateNames()
+	}
+	for _, name := range names {
+		content, err := generator.EmbeddedTemplate(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: unknown template %s\n", name)
+			os.Exit(1)
+		}
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
 func handleJSONInput(jsonFile string) {
 	// Read JSON file
 	content, err := os.ReadFile(jsonFile)
@@ -123,20 +281,39 @@ func handleJSONInput(jsonFile string) {
 	}
 
 	// Unmarshal JSON
-	var idl parser.IDL
-	if err := json.Unmarshal(content, &idl); err != nil {
+	var doc parser.IDL
+	if err := json.Unmarshal(content, &doc); err != nil {
 		fmt.Fprintf(os.Stderr, "error: failed to parse JSON: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Always validate JSON input
-	if err := parser.ValidateIDL(&idl); err != nil {
+	if err := parser.ValidateIDL(&doc); err != nil {
 		fmt.Fprintf(os.Stderr, "error: validation failed: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Generate IDL text on STDOUT
-	fmt.Print(generateIDLText(&idl))
+	fmt.Print(idl.Format(&doc))
+}
+
+func handleSQLInput(sqlFile string, namespace string) {
+	content, err := os.ReadFile(sqlFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: failed to read SQL file %s: %v\n", sqlFile, err)
+		os.Exit(1)
+	}
+
+	if namespace == "" {
+		namespace = strings.TrimSuffix(filepath.Base(sqlFile), filepath.Ext(sqlFile))
+	}
+
+	text, err := sqlimport.Import(namespace, string(content))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: failed to import %s: %v\n", sqlFile, err)
+		os.Exit(1)
+	}
+	fmt.Print(text)
 }
 
 func handleJSONOutput(idl *parser.IDL, outputFile string) {
@@ -217,165 +394,11 @@ func prettyPrintIDL(idl *parser.IDL) {
 	}
 }
 
-// generateIDLText converts a parsed IDL structure back to IDL text format
-func generateIDLText(idl *parser.IDL) string {
-	var sb strings.Builder
-
-	// Group elements by namespace
-	namespaceInterfaces := make(map[string][]*parser.Interface)
-	namespaceStructs := make(map[string][]*parser.Struct)
-	namespaceEnums := make(map[string][]*parser.Enum)
-
-	// Collect all elements by namespace
-	for _, iface := range idl.Interfaces {
-		ns := iface.Namespace
-		namespaceInterfaces[ns] = append(namespaceInterfaces[ns], iface)
-	}
-
-	for _, s := range idl.Structs {
-		ns := s.Namespace
-		namespaceStructs[ns] = append(namespaceStructs[ns], s)
-	}
-
-	for _, enum := range idl.Enums {
-		ns := enum.Namespace
-		namespaceEnums[ns] = append(namespaceEnums[ns], enum)
-	}
-
-	// Collect all unique namespaces (excluding empty string)
-	allNamespaces := make(map[string]bool)
-	for ns := range namespaceInterfaces {
-		if ns != "" {
-			allNamespaces[ns] = true
-		}
-	}
-	for ns := range namespaceStructs {
-		if ns != "" {
-			allNamespaces[ns] = true
-		}
-	}
-	for ns := range namespaceEnums {
-		if ns != "" {
-			allNamespaces[ns] = true
-		}
-	}
-
-	// Output elements without namespace first (no namespace declaration)
-	if ifaces, ok := namespaceInterfaces[""]; ok {
-		for _, iface := range ifaces {
-			writeInterface(&sb, iface)
-		}
-	}
-	if structs, ok := namespaceStructs[""]; ok {
-		for _, s := range structs {
-			writeStruct(&sb, s)
-		}
-	}
-	if enums, ok := namespaceEnums[""]; ok {
-		for _, enum := range enums {
-			writeEnum(&sb, enum)
-		}
-	}
-
-	// Output elements with namespaces, grouped by namespace
-	for ns := range allNamespaces {
-		// Output namespace declaration
-		fmt.Fprintf(&sb, "namespace %s\n\n", ns)
-
-		// Output all interfaces in this namespace
-		if ifaces, ok := namespaceInterfaces[ns]; ok {
-			for _, iface := range ifaces {
-				writeInterface(&sb, iface)
-			}
-		}
-
-		// Output all structs in this namespace
-		if structs, ok := namespaceStructs[ns]; ok {
-			for _, s := range structs {
-				writeStruct(&sb, s)
-			}
-		}
-
-		// Output all enums in this namespace
-		if enums, ok := namespaceEnums[ns]; ok {
-			for _, enum := range enums {
-				writeEnum(&sb, enum)
-			}
-		}
-	}
-
-	return sb.String()
-}
-
-func writeInterface(sb *strings.Builder, iface *parser.Interface) {
-	if iface.Comment != "" {
-		writeComment(sb, iface.Comment)
-	}
-	fmt.Fprintf(sb, "interface %s {\n", iface.Name)
-	for _, method := range iface.Methods {
-		fmt.Fprintf(sb, "  %s(", method.Name)
-		for i, param := range method.Parameters {
-			if i > 0 {
-				sb.WriteString(", ")
-			}
-			fmt.Fprintf(sb, "%s %s", param.Name, param.Type.String())
-		}
-		fmt.Fprintf(sb, ") %s\n", method.ReturnType.String())
-	}
-	sb.WriteString("}\n\n")
-}
-
-func writeStruct(sb *strings.Builder, s *parser.Struct) {
-	if s.Comment != "" {
-		writeComment(sb, s.Comment)
-	}
-	if s.Extends != "" {
-		fmt.Fprintf(sb, "struct %s extends %s {\n", s.Name, s.Extends)
-	} else {
-		fmt.Fprintf(sb, "struct %s {\n", s.Name)
-	}
-	for _, field := range s.Fields {
-		if field.Comment != "" {
-			writeComment(sb, field.Comment)
-		}
-		optional := ""
-		if field.Optional {
-			optional = " [optional]"
-		}
-		fmt.Fprintf(sb, "  %s %s%s\n", field.Name, field.Type.String(), optional)
-	}
-	sb.WriteString("}\n\n")
-}
-
-func writeEnum(sb *strings.Builder, enum *parser.Enum) {
-	if enum.Comment != "" {
-		writeComment(sb, enum.Comment)
-	}
-	fmt.Fprintf(sb, "enum %s {\n", enum.Name)
-	for _, value := range enum.Values {
-		if value.Comment != "" {
-			writeComment(sb, value.Comment)
-		}
-		fmt.Fprintf(sb, "  %s\n", value.Name)
-	}
-	sb.WriteString("}\n\n")
-}
-
-func writeComment(sb *strings.Builder, comment string) {
-	lines := strings.Split(comment, "\n")
-	for _, line := range lines {
-		fmt.Fprintf(sb, "// %s\n", line)
-	}
-}
-
 // registerPlugins registers all available code generation plugins
 func registerPlugins() {
-	generator.Register(generator.NewPythonClientServer())
-	generator.Register(generator.NewTSClientServer())
-	generator.Register(generator.NewCSharpClientServer())
-	generator.Register(generator.NewJavaClientServer())
-	generator.Register(generator.NewGoClientServer())
-	// Add more plugins here as they are implemented
+	for _, plugin := range generator.Builtin() {
+		generator.Register(plugin)
+	}
 }
 
 // getAllPlugins returns a slice of all registered plugins
@@ -390,8 +413,38 @@ func getAllPlugins() []generator.Plugin {
 	return plugins
 }
 
+// handleHelp prints the flags a plugin reads, with the usage they were registered
+// with, or the available plugins when no plugin is named
+func handleHelp(args []string) {
+	names := generator.List()
+	sort.Strings(names)
+	if len(args) == 0 {
+		fmt.Println("usage: pulse help <plugin>")
+		fmt.Printf("available plugins: %s\n", strings.Join(names, ", "))
+		return
+	}
+
+	plugin, ok := generator.Get(args[0])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "error: unknown plugin %q\n", args[0])
+		fmt.Fprintf(os.Stderr, "available plugins: %s\n", strings.Join(names, ", "))
+		os.Exit(1)
+	}
+
+	// Copy the plugin's flags from the global FlagSet so shared flags keep their usage
+	fs := flag.NewFlagSet(plugin.Name(), flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	for _, name := range generator.PluginFlags(plugin) {
+		if f := flag.CommandLine.Lookup(name); f != nil {
+			fs.Var(f.Value, f.Name, f.Usage)
+		}
+	}
+	fmt.Printf("usage: pulse -plugin %s [flags] <idl-file>\n\nflags:\n", plugin.Name())
+	fs.PrintDefaults()
+}
+
 // handlePluginGeneration routes IDL to the specified plugin for code generation
-func handlePluginGeneration(pluginName string, idl *parser.IDL) {
+func handlePluginGeneration(pluginName string, idl *parser.IDL, verify bool) {
 	plugin, ok := generator.Get(pluginName)
 	if !ok {
 		fmt.Fprintf(os.Stderr, "error: unknown plugin %q\n", pluginName)
@@ -404,4 +457,11 @@ func handlePluginGeneration(pluginName string, idl *parser.IDL) {
 		fmt.Fprintf(os.Stderr, "error: plugin %q failed: %v\n", pluginName, err)
 		os.Exit(1)
 	}
+
+	if verify {
+		if err := generator.Verify(plugin, flag.CommandLine); err != nil {
+			fmt.Fprintf(os.Stderr, "error: verification failed: %v\n", err)
+			os.Exit(1)
+		}
+	}
 }
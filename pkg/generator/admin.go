@@ -0,0 +1,214 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// The -generate-admin-endpoint flag gives the Go, Python and TypeScript servers
+// an admin endpoint for live debugging, GET /_pulserpc/admin. It is off until the
+// server is given a bearer token with EnableAdmin / enable_admin / enableAdmin,
+// and requests without that token get HTTP 401. It reports the registered
+// interfaces with the type of their handler, the calls, errors and handler
+// latency of every method since the endpoint was enabled (the runtime's
+// MethodMetrics), and the checksum of the idl.json the server was generated
+// with, so a running server can be matched to its contract. The generated test
+// servers enable it with the token in the PULSERPC_ADMIN_TOKEN environment
+// variable.
+
+// adminPath is the path of the admin endpoint
+const adminPath = "/_pulserpc/admin"
+
+// adminTokenEnvVar names the admin token the generated test servers use
+const adminTokenEnvVar = "PULSERPC_ADMIN_TOKEN"
+
+// adminEndpointRequested reports whether the -generate-admin-endpoint flag is set
+func adminEndpointRequested(fs *flag.FlagSet) bool {
+	f := fs.Lookup("generate-admin-endpoint")
+	return f != nil && f.Value.String() == "true"
+}
+
+// idlChecksum returns "sha256:" and the hex SHA-256 of the idl.json written for idl.
+// It is empty if the IDL cannot be encoded, which fails generation when idl.json is
+// written anyway.
+func idlChecksum(idl *parser.IDL) string {
+	data, err := json.MarshalIndent(idl, "", "  ")
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// adminMethods returns the Interface.method names of every method, inherited ones
+// included, which the admin endpoint counts calls of
+func adminMethods(interfaces []*parser.Interface) []string {
+	var methods []string
+	for _, iface := range interfaces {
+		for _, method := range iface.Methods {
+			methods = append(methods, iface.Name+"."+method.Name)
+		}
+	}
+	return methods
+}
+
+// writeAdminServerGo writes the adminMethods table, EnableAdmin and handleAdmin of the
+// Go server
+func writeAdminServerGo(sb *strings.Builder, idl *parser.IDL) {
+	sb.WriteString("// adminMethods are the methods the admin endpoint reports calls of\n")
+	sb.WriteString("var adminMethods = []string{\n")
+	for _, method := range adminMethods(idl.Interfaces) {
+		fmt.Fprintf(sb, "	%q,\n", method)
+	}
+	sb.WriteString("}\n\n")
+	sb.WriteString("// idlChecksum is the SHA-256 of the idl.json this server was generated with\n")
+	fmt.Fprintf(sb, "const idlChecksum = %q\n\n", idlChecksum(idl))
+
+	fmt.Fprintf(sb, `// EnableAdmin serves the admin endpoint, GET %s, to requests that carry
+// "Authorization: Bearer <token>". It reports the registered interfaces and the types of
+// their handlers, the calls, errors and handler latency of every method since EnableAdmin
+// was called, and the checksum of the IDL the server was generated with.
+func (s *PulseRPCServer) EnableAdmin(token string) error {
+	if token == "" {
+		return fmt.Errorf("admin token must not be empty")
+	}
+	s.adminToken = token
+	s.metrics = NewMethodMetrics(adminMethods)
+	return nil
+}
+
+// handleAdmin serves the admin endpoint
+func (s *PulseRPCServer) handleAdmin(w http.ResponseWriter, r *http.Request) {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+s.adminToken)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	names := make([]string, 0, len(s.handlers))
+	for name := range s.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	interfaces := make([]map[string]string, 0, len(names))
+	for _, name := range names {
+		interfaces = append(interfaces, map[string]string{"name": name, "handler": fmt.Sprintf("%%T", s.handlers[name])})
+	}
+	started := s.metrics.Started()
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"idlChecksum":   idlChecksum,
+		"startedAt":     started.UTC().Format(time.RFC3339),
+		"uptimeSeconds": time.Since(started).Seconds(),
+		"interfaces":    interfaces,
+		"methods":       s.metrics.Snapshot(),
+	})
+}
+
+`, adminPath)
+}
+
+// writeAdminConstantsPy writes the ADMIN_METHODS table and IDL_CHECKSUM of the Python server
+func writeAdminConstantsPy(sb *strings.Builder, idl *parser.IDL) {
+	sb.WriteString("# The methods the admin endpoint reports calls of\n")
+	sb.WriteString("ADMIN_METHODS = [\n")
+	for _, method := range adminMethods(idl.Interfaces) {
+		fmt.Fprintf(sb, "    '%s',\n", method)
+	}
+	sb.WriteString("]\n\n")
+	sb.WriteString("# The SHA-256 of the idl.json this server was generated with\n")
+	fmt.Fprintf(sb, "IDL_CHECKSUM = '%s'\n\n\n", idlChecksum(idl))
+}
+
+// writeAdminServerPy writes enable_admin and _handle_admin of the Python server
+func writeAdminServerPy(sb *strings.Builder) {
+	fmt.Fprintf(sb, `    def enable_admin(self, token: str) -> None:
+        """Serve the admin endpoint, GET %s, to requests that carry
+        "Authorization: Bearer <token>". It reports the registered interfaces and the types
+        of their handlers, the calls, errors and handler latency of every method since
+        enable_admin was called, and the checksum of the IDL the server was generated with."""
+        if not token:
+            raise ValueError("admin token must not be empty")
+        self._admin_token = token
+        self._metrics = MethodMetrics(ADMIN_METHODS)
+
+    def _handle_admin(self, headers: Any) -> Tuple[int, Dict[str, str], bytes]:
+        """Serve the admin endpoint"""
+        expected = f"Bearer {self._admin_token}".encode('utf-8')
+        if not hmac.compare_digest((headers.get('Authorization') or '').encode('utf-8'), expected):
+            return 401, {'WWW-Authenticate': 'Bearer'}, b'Unauthorized'
+        interfaces = [
+            {'name': name, 'handler': f"{type(handler).__module__}.{type(handler).__qualname__}"}
+            for name, handler in sorted(self.handlers.items())
+        ]
+        started = self._metrics.started
+        report = {
+            'idlChecksum': IDL_CHECKSUM,
+            'startedAt': time.strftime('%%Y-%%m-%%dT%%H:%%M:%%SZ', time.gmtime(started)),
+            'uptimeSeconds': time.time() - started,
+            'interfaces': interfaces,
+            'methods': self._metrics.snapshot(),
+        }
+        return 200, {'Content-Type': 'application/json', 'Cache-Control': 'no-store'}, json.dumps(report).encode('utf-8')
+
+`, adminPath)
+}
+
+// writeAdminConstantsTs writes the ADMIN_METHODS table and IDL_CHECKSUM of the
+// TypeScript server
+func writeAdminConstantsTs(sb *strings.Builder, idl *parser.IDL) {
+	sb.WriteString("// The methods the admin endpoint reports calls of\n")
+	sb.WriteString("const ADMIN_METHODS = [\n")
+	for _, method := range adminMethods(idl.Interfaces) {
+		fmt.Fprintf(sb, "  '%s',\n", method)
+	}
+	sb.WriteString("];\n\n")
+	sb.WriteString("// The SHA-256 of the idl.json this server was generated with\n")
+	fmt.Fprintf(sb, "const IDL_CHECKSUM = '%s';\n\n", idlChecksum(idl))
+}
+
+// writeAdminServerTs writes enableAdmin and handleAdmin of the TypeScript server
+func writeAdminServerTs(sb *strings.Builder) {
+	fmt.Fprintf(sb, `  // Serves the admin endpoint, GET %s, to requests that carry
+  // "Authorization: Bearer <token>". It reports the registered interfaces and the types
+  // of their handlers, the calls, errors and handler latency of every method since
+  // enableAdmin was called, and the checksum of the IDL the server was generated with.
+  enableAdmin(token: string): void {
+    if (!token) {
+      throw new Error('admin token must not be empty');
+    }
+    this.adminToken = token;
+    this.metrics = new MethodMetrics(ADMIN_METHODS);
+  }
+
+  private handleAdmin(headers: http.IncomingHttpHeaders, res: http.ServerResponse): void {
+    const given = Buffer.from(headers.authorization || '');
+    const expected = Buffer.from('Bearer ' + this.adminToken);
+    if (given.length !== expected.length || !timingSafeEqual(given, expected)) {
+      res.writeHead(401, { 'WWW-Authenticate': 'Bearer' });
+      res.end('Unauthorized');
+      return;
+    }
+    const metrics = this.metrics!;
+    const interfaces = [...this.handlers.keys()].sort().map((name) => ({
+      name,
+      handler: this.handlers.get(name)?.constructor?.name || typeof this.handlers.get(name),
+    }));
+    res.writeHead(200, { 'Content-Type': 'application/json', 'Cache-Control': 'no-store' });
+    res.end(JSON.stringify({
+      idlChecksum: IDL_CHECKSUM,
+      startedAt: metrics.startedAt.toISOString().replace(/\.\d{3}Z$/, 'Z'),
+      uptimeSeconds: (Date.now() - metrics.startedAt.getTime()) / 1000,
+      interfaces,
+      methods: metrics.snapshot(),
+    }));
+  }
+
+`, adminPath)
+}
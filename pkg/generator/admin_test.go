@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+const adminIDL = `namespace shop
+interface Catalog {
+  count() int
+}`
+
+func TestAdminEndpointGenerated(t *testing.T) {
+	idl, err := parser.ParseIDL("shop.pulse", adminIDL)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+
+	checksum := idlChecksum(idl)
+	if !strings.HasPrefix(checksum, "sha256:") || len(checksum) != len("sha256:")+64 {
+		t.Fatalf("unexpected IDL checksum %q", checksum)
+	}
+
+	tests := []struct {
+		plugin Plugin
+		want   map[string][]string
+	}{
+		{
+			plugin: NewGoClientServer(),
+			want: map[string][]string{
+				"server.go":               {"func (s *PulseRPCServer) EnableAdmin(token string) error {", `if r.URL.Path == "/_pulserpc/admin" && s.metrics != nil {`, `const idlChecksum = "` + checksum + `"`, `s.metrics.Record(interfaceName+"."+methodName, time.Since(started), err != nil)`},
+				"cmd/test_server/main.go": {`if token := os.Getenv("PULSERPC_ADMIN_TOKEN"); token != "" {`},
+			},
+		},
+		{
+			plugin: NewPythonClientServer(),
+			want: map[string][]string{
+				"server.py":      {"def enable_admin(self, token: str) -> None:", "if method == 'GET' and url.path == '/_pulserpc/admin' and self._metrics is not None:", "IDL_CHECKSUM = '" + checksum + "'", "    'Catalog.count',\n"},
+				"test_server.py": {`server.enable_admin(os.environ["PULSERPC_ADMIN_TOKEN"])`},
+			},
+		},
+		{
+			plugin: NewTSClientServer(),
+			want: map[string][]string{
+				"server.ts":      {"enableAdmin(token: string): void {", "if (url.pathname === '/_pulserpc/admin' && this.metrics) {", "const IDL_CHECKSUM = '" + checksum + "';", "this.metrics?.record(`${interfaceName}.${methodName}`, Date.now() - started, failed);"},
+				"test_server.ts": {"server.enableAdmin(process.env.PULSERPC_ADMIN_TOKEN);"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		for _, enabled := range []bool{true, false} {
+			tmpDir := t.TempDir()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.String("dir", "", "output dir")
+			fs.Bool("generate-test-files", false, "generate test files")
+			fs.Bool("generate-admin-endpoint", false, "generate admin endpoint")
+			tt.plugin.RegisterFlags(fs)
+			if err := fs.Set("dir", tmpDir); err != nil {
+				t.Fatalf("failed to set dir flag: %v", err)
+			}
+			if err := fs.Set("generate-test-files", "true"); err != nil {
+				t.Fatalf("failed to set generate-test-files flag: %v", err)
+			}
+			if enabled {
+				if err := fs.Set("generate-admin-endpoint", "true"); err != nil {
+					t.Fatalf("failed to set generate-admin-endpoint flag: %v", err)
+				}
+			}
+			if err := tt.plugin.Generate(idl, fs); err != nil {
+				t.Fatalf("%s: Generate failed: %v", tt.plugin.Name(), err)
+			}
+			// The checksum is that of the idl.json written next to the server
+			idlJSON, err := os.ReadFile(filepath.Join(tmpDir, "idl.json"))
+			if err != nil {
+				t.Fatalf("%s: expected idl.json: %v", tt.plugin.Name(), err)
+			}
+			if sum := sha256.Sum256(idlJSON); "sha256:"+hex.EncodeToString(sum[:]) != checksum {
+				t.Errorf("%s: IDL checksum %s does not match idl.json", tt.plugin.Name(), checksum)
+			}
+			for file, wants := range tt.want {
+				content, err := os.ReadFile(filepath.Join(tmpDir, file))
+				if err != nil {
+					t.Fatalf("%s: expected %s: %v", tt.plugin.Name(), file, err)
+				}
+				for _, want := range wants {
+					if got := strings.Contains(string(content), want); got != enabled {
+						t.Errorf("%s: %s contains %q = %v, want %v", tt.plugin.Name(), file, want, got, enabled)
+					}
+				}
+			}
+		}
+	}
+}
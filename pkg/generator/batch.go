@@ -0,0 +1,665 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Client batches: every client can send several calls in one JSON-RPC batch request
+// and get back a BatchResult per call, holding either the call's typed result or its
+// typed error, so one failing call does not fail the others and callers never match
+// response ids themselves. A call is added to a batch by making it with a batch call
+// option, as CallWithMeta does with the response metadata option: the client method
+// validates its params and queues its request instead of sending it. Once the batch
+// is sent, the call is made again and the transport hands it the response matched to
+// its request id, so the result is decoded, validated and bound to [errordata] by
+// the same client code as a single call. Transports that cannot send batches make
+// the calls one at a time. Servers are unchanged: they already handle batches.
+
+// writeBatchClientGo writes the Batch API of the Go client
+func writeBatchClientGo(sb *strings.Builder) {
+	sb.WriteString("// Batch collects client calls and sends them in one JSON-RPC batch request. Calls are\n")
+	sb.WriteString("// added with Batched, which returns the BatchResult the call's typed result or error\n")
+	sb.WriteString("// is stored in once Send returns:\n")
+	sb.WriteString("//\n")
+	sb.WriteString("//	batch := NewBatch(transport)\n")
+	sb.WriteString("//	sum := Batched(batch, func(opt CallOption) (int, error) { return calc.Add(1, 2, opt) })\n")
+	sb.WriteString("//	err := batch.Send()\n")
+	sb.WriteString("//\n")
+	sb.WriteString("// A call that fails does not fail the others. A Batch is not safe for concurrent use.\n")
+	sb.WriteString("type Batch struct {\n")
+	sb.WriteString("	transport Transport\n")
+	sb.WriteString("	calls     []*batchCall\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("// BatchResult is the outcome of one call of a Batch: its typed result, or the error the\n")
+	sb.WriteString("// call returned, such as an *RPCError from its own response\n")
+	sb.WriteString("type BatchResult[T any] struct {\n")
+	sb.WriteString("	Result T\n")
+	sb.WriteString("	Err    error\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("// BatchRequest is a call sent as a member of a batch request\n")
+	sb.WriteString("type BatchRequest struct {\n")
+	sb.WriteString("	Method  string\n")
+	sb.WriteString("	Params  []interface{}\n")
+	sb.WriteString("	Options CallOptions\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("// BatchTransport is implemented by transports that send several calls in one JSON-RPC\n")
+	sb.WriteString("// batch request. CallBatch returns the response to each request in the order of\n")
+	sb.WriteString("// requests, nil where the server sent none; options apply to the batch request as a whole.\n")
+	sb.WriteString("type BatchTransport interface {\n")
+	sb.WriteString("	CallBatch(requests []BatchRequest, options CallOptions) ([]map[string]interface{}, error)\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("// batchCall is a call added to a Batch: the request it queued, and once the batch is\n")
+	sb.WriteString("// sent, the response matched to it\n")
+	sb.WriteString("type batchCall struct {\n")
+	sb.WriteString("	request  BatchRequest\n")
+	sb.WriteString("	queued   bool\n")
+	sb.WriteString("	answered bool\n")
+	sb.WriteString("	response map[string]interface{}\n")
+	sb.WriteString("	err      error\n")
+	sb.WriteString("	replay   func()\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("// errBatchQueued stops a client method once its request is queued in a batch\n")
+	sb.WriteString("var errBatchQueued = fmt.Errorf(\"call queued in batch\")\n\n")
+	sb.WriteString("// queue records the request of a call instead of sending it\n")
+	sb.WriteString("func (bc *batchCall) queue(method string, params []interface{}, options CallOptions) {\n")
+	sb.WriteString("	options.batch = nil\n")
+	sb.WriteString("	bc.request = BatchRequest{Method: method, Params: params, Options: options}\n")
+	sb.WriteString("	bc.queued = true\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("// take returns the response matched to the call's request. Later requests of the call,\n")
+	sb.WriteString("// such as the polls of an [async] method, go to the transport.\n")
+	sb.WriteString("func (bc *batchCall) take() (map[string]interface{}, error) {\n")
+	sb.WriteString("	bc.answered = false\n")
+	sb.WriteString("	if bc.err != nil {\n")
+	sb.WriteString("		return nil, bc.err\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	if bc.response == nil {\n")
+	sb.WriteString("		return nil, fmt.Errorf(\"no response to %s in batch\", bc.request.Method)\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	return checkRPCResponse(bc.response)\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("// NewBatch creates an empty Batch whose calls are sent through transport\n")
+	sb.WriteString("func NewBatch(transport Transport) *Batch {\n")
+	sb.WriteString("	return &Batch{transport: transport}\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("// Batched adds a client call to batch by making it with an option that queues its\n")
+	sb.WriteString("// request. A call that fails before sending anything, such as on invalid params, has\n")
+	sb.WriteString("// its BatchResult set right away and is not sent.\n")
+	sb.WriteString("func Batched[T any](batch *Batch, call func(opt CallOption) (T, error)) *BatchResult[T] {\n")
+	sb.WriteString("	res := &BatchResult[T]{}\n")
+	sb.WriteString("	bc := &batchCall{}\n")
+	sb.WriteString("	opt := func(o *CallOptions) { o.batch = bc }\n")
+	sb.WriteString("	result, err := call(opt)\n")
+	sb.WriteString("	if !bc.queued {\n")
+	sb.WriteString("		res.Result, res.Err = result, err\n")
+	sb.WriteString("		return res\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	bc.replay = func() { res.Result, res.Err = call(opt) }\n")
+	sb.WriteString("	batch.calls = append(batch.calls, bc)\n")
+	sb.WriteString("	return res\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("// Len returns the number of calls waiting to be sent\n")
+	sb.WriteString("func (b *Batch) Len() int {\n")
+	sb.WriteString("	return len(b.calls)\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("// Send sends the queued calls in one request and sets the BatchResult of each from its\n")
+	sb.WriteString("// own response. opts apply to the request as a whole, such as its timeout and headers.\n")
+	sb.WriteString("// Send returns an error only when the request as a whole failed, and then also sets it\n")
+	sb.WriteString("// as the error of every call. A transport that is not a BatchTransport, or whose server\n")
+	sb.WriteString("// reports it takes no batches, makes the calls one at a time, each with its own options.\n")
+	sb.WriteString("// The batch is empty afterwards.\n")
+	sb.WriteString("func (b *Batch) Send(opts ...CallOption) error {\n")
+	sb.WriteString("	calls := b.calls\n")
+	sb.WriteString("	b.calls = nil\n")
+	sb.WriteString("	var err error\n")
+	sb.WriteString("	if t, ok := b.transport.(BatchTransport); ok && len(calls) > 0 && sendsBatches(b.transport) {\n")
+	sb.WriteString("		requests := make([]BatchRequest, len(calls))\n")
+	sb.WriteString("		for i, bc := range calls {\n")
+	sb.WriteString("			requests[i] = bc.request\n")
+	sb.WriteString("		}\n")
+	sb.WriteString("		var responses []map[string]interface{}\n")
+	sb.WriteString("		responses, err = t.CallBatch(requests, newCallOptions(opts))\n")
+	sb.WriteString("		for i, bc := range calls {\n")
+	sb.WriteString("			bc.answered = true\n")
+	sb.WriteString("			if err != nil {\n")
+	sb.WriteString("				bc.err = err\n")
+	sb.WriteString("			} else {\n")
+	sb.WriteString("				bc.response = responses[i]\n")
+	sb.WriteString("			}\n")
+	sb.WriteString("		}\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	for _, bc := range calls {\n")
+	sb.WriteString("		bc.replay()\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	return err\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeCallBatchGo writes HTTPTransport.CallBatch
+func writeCallBatchGo(sb *strings.Builder) {
+	sb.WriteString("// CallBatch sends requests as one JSON-RPC batch request and returns the response to\n")
+	sb.WriteString("// each, matched by request id\n")
+	sb.WriteString("func (t *HTTPTransport) CallBatch(requests []BatchRequest, options CallOptions) ([]map[string]interface{}, error) {\n")
+	sb.WriteString("	ids := make([]string, len(requests))\n")
+	sb.WriteString("	batch := make([]map[string]interface{}, len(requests))\n")
+	sb.WriteString("	for i, r := range requests {\n")
+	sb.WriteString("		ids[i] = newRequestID()\n")
+	sb.WriteString("		batch[i] = map[string]interface{}{\n")
+	sb.WriteString("			\"jsonrpc\": \"2.0\",\n")
+	sb.WriteString("			\"method\":  r.Method,\n")
+	sb.WriteString("			\"params\":  requestParams(r.Params, r.Options),\n")
+	sb.WriteString("			\"id\":      ids[i],\n")
+	sb.WriteString("		}\n")
+	sb.WriteString("	}\n\n")
+	sb.WriteString("	jsonData, err := t.marshal(batch)\n")
+	sb.WriteString("	if err != nil {\n")
+	sb.WriteString("		return nil, fmt.Errorf(\"failed to marshal request: %w\", err)\n")
+	sb.WriteString("	}\n\n")
+	sb.WriteString("	ctx := context.Background()\n")
+	sb.WriteString("	if options.Timeout > 0 {\n")
+	sb.WriteString("		var cancel context.CancelFunc\n")
+	sb.WriteString("		ctx, cancel = context.WithTimeout(ctx, options.Timeout)\n")
+	sb.WriteString("		defer cancel()\n")
+	sb.WriteString("	}\n\n")
+	sb.WriteString("	resp, err := t.post(ctx, jsonData, options)\n")
+	sb.WriteString("	if err != nil {\n")
+	sb.WriteString("		return nil, err\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	defer resp.Body.Close()\n")
+	sb.WriteString("	body, err := io.ReadAll(resp.Body)\n")
+	sb.WriteString("	if err != nil {\n")
+	sb.WriteString("		return nil, &TransportError{StatusCode: resp.StatusCode, Err: err}\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	var members []map[string]interface{}\n")
+	sb.WriteString("	if err := json.Unmarshal(body, &members); err != nil {\n")
+	sb.WriteString("		// A batch rejected as a whole gets a single error response\n")
+	sb.WriteString("		if _, rpcErr := decodeRPCResponse(resp.StatusCode, bytes.NewReader(body)); rpcErr != nil {\n")
+	sb.WriteString("			return nil, rpcErr\n")
+	sb.WriteString("		}\n")
+	sb.WriteString("		return nil, fmt.Errorf(\"failed to decode batch response: %w\", err)\n")
+	sb.WriteString("	}\n\n")
+	sb.WriteString("	byID := make(map[string]map[string]interface{}, len(members))\n")
+	sb.WriteString("	for _, member := range members {\n")
+	sb.WriteString("		if id, ok := member[\"id\"].(string); ok {\n")
+	sb.WriteString("			byID[id] = member\n")
+	sb.WriteString("		}\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	responses := make([]map[string]interface{}, len(requests))\n")
+	sb.WriteString("	for i, id := range ids {\n")
+	sb.WriteString("		responses[i] = byID[id]\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	return responses, nil\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeBatchClientPy writes the Batch API of the Python client, after the Transport ABC
+func writeBatchClientPy(sb *strings.Builder) {
+	sb.WriteString(`@dataclass
+class BatchRequest:
+    """A call sent as a member of a batch request"""
+    method: str
+    params: list
+    options: CallOptions
+
+
+@dataclass
+class BatchResult(Generic[T]):
+    """The outcome of one call of a Batch: its typed result, or the exception the call
+    raised, such as an RPCError from its own response"""
+    result: Optional[T] = None
+    error: Optional[Exception] = None
+
+
+class _BatchQueued(Exception):
+    """Stops a client method once its request is queued in a batch"""
+
+
+class _BatchCall(Transport):
+    """The transport of a call added to a Batch. It queues the call's request the first
+    time and, once the batch is sent, returns the response matched to it. Later requests
+    of the call, such as the polls of an [async] method, go to transport."""
+
+    def __init__(self, transport: Transport):
+        self.transport = transport
+        self.request: Optional[BatchRequest] = None
+        self.answered = False
+        self.response: Optional[dict] = None
+        self.error: Optional[Exception] = None
+
+    def call(self, method: str, params: list) -> dict:
+        return self.call_with_options(method, params, CallOptions())
+
+    def call_with_options(self, method: str, params: list, options: CallOptions) -> dict:
+        if self.request is None:
+            self.request = BatchRequest(method, params, options)
+            raise _BatchQueued()
+        if not self.answered:
+            return self.transport.call_with_options(method, params, options)
+        self.answered = False
+        if self.error is not None:
+            raise self.error
+        if self.response is None:
+            raise RPCError(-32603, f"No response to {method} in batch", None)
+        return self.response
+
+
+class Batch:
+    """Collects client calls and sends them in one JSON-RPC batch request. add() takes a
+    client method and its arguments, as call_with_meta does, and returns the BatchResult
+    the call's result or error is stored in once send() returns:
+
+        batch = Batch(transport)
+        total = batch.add(calc.add, 1, 2)
+        batch.send()
+
+    A call that fails does not fail the others. A Batch is not thread-safe.
+    """
+
+    def __init__(self, transport: Transport):
+        self.transport = transport
+        self._calls: List[Tuple[_BatchCall, Callable[[], None]]] = []
+
+    def __len__(self) -> int:
+        """The number of calls waiting to be sent"""
+        return len(self._calls)
+
+    def add(self, method: Callable[..., T], *args: Any, **kwargs: Any) -> BatchResult[T]:
+        """Add a call of a client method to the batch. The method is called on a copy of its
+        client whose transport queues the request. A call that fails before sending anything,
+        such as on invalid params, has its BatchResult set right away and is not sent."""
+        call = _BatchCall(self.transport)
+        client = copy.copy(method.__self__)
+        client.transport = call
+        bound = getattr(client, method.__name__)
+        res: BatchResult[T] = BatchResult()
+
+        def run() -> None:
+            try:
+                res.result = bound(*args, **kwargs)
+            except Exception as e:
+                res.error = e
+
+        run()
+        if isinstance(res.error, _BatchQueued):
+            res.error = None
+            self._calls.append((call, run))
+        return res
+
+    def send(self, timeout: Optional[float] = None, headers: Optional[Dict[str, str]] = None) -> None:
+        """Send the queued calls in one request and set the BatchResult of each from its own
+        response. timeout and headers apply to the request as a whole. If the request as a
+        whole fails, its error is raised and also set as the error of every call. A transport
+        without call_batch, or whose server reports it takes no batches, makes the calls one
+        at a time. The batch is empty afterwards."""
+        calls, self._calls = self._calls, []
+        call_batch = getattr(self.transport, 'call_batch', None)
+        if call_batch is not None and calls and not _sends_batches(self.transport):
+            call_batch = None
+        error: Optional[Exception] = None
+        if call_batch is not None and calls:
+            responses: List[Optional[dict]] = [None] * len(calls)
+            try:
+                responses = call_batch([call.request for call, _ in calls],
+                                       CallOptions(timeout=timeout, headers=headers or {}))
+            except Exception as e:
+                error = e
+            for (call, _), response in zip(calls, responses):
+                call.answered = True
+                call.response = response
+                call.error = error
+        for _, run in calls:
+            run()
+        if error is not None:
+            raise error
+
+
+`)
+}
+
+// writeCallBatchPy writes HTTPTransport.call_batch
+func writeCallBatchPy(sb *strings.Builder) {
+	sb.WriteString(`    def call_batch(self, requests: List[BatchRequest], options: CallOptions) -> List[Optional[dict]]:
+        """Send requests as one JSON-RPC batch request and return the response to each,
+        matched by request id, None where the server sent none. options apply to the batch
+        request as a whole."""
+        ids = [str(uuid.uuid4()) for _ in requests]
+        batch = [{'jsonrpc': '2.0', 'method': r.method, 'params': r.options.request_params(r.params), 'id': request_id}
+                 for r, request_id in zip(requests, ids)]
+        response_body = self._post(self._dumps(batch), options)
+        members = json.loads(response_body.decode('utf-8'))
+        if not isinstance(members, list):
+            # A batch rejected as a whole gets a single error response
+            self._decode_response(response_body)
+            raise TransportError("Unexpected response to batch request")
+        by_id = {m.get('id'): m for m in members if isinstance(m, dict)}
+        return [by_id.get(request_id) for request_id in ids]
+
+`)
+}
+
+// writeBatchClientTs writes the Batch API of the TypeScript client, after the
+// Transport class
+func writeBatchClientTs(sb *strings.Builder, packagePrefix string) {
+	optionsName := applyPackagePrefix("CallOptions", packagePrefix)
+	requestName := applyPackagePrefix("BatchRequest", packagePrefix)
+	resultName := applyPackagePrefix("BatchResult", packagePrefix)
+	className := applyPackagePrefix("Batch", packagePrefix)
+
+	sb.WriteString("/** A call sent as a member of a batch request */\n")
+	fmt.Fprintf(sb, "export interface %s {\n", requestName)
+	sb.WriteString("  method: string;\n")
+	sb.WriteString("  params: any[];\n")
+	fmt.Fprintf(sb, "  options: %s;\n", optionsName)
+	sb.WriteString("}\n\n")
+	sb.WriteString("/**\n")
+	sb.WriteString(" * The outcome of one call of a batch: its typed result, or the error the call threw,\n")
+	sb.WriteString(" * such as an RPCError from its own response.\n")
+	sb.WriteString(" */\n")
+	fmt.Fprintf(sb, "export interface %s<T> {\n", resultName)
+	sb.WriteString("  result?: T;\n")
+	sb.WriteString("  error?: Error;\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("/**\n")
+	sb.WriteString(" * Collects client calls and sends them in one JSON-RPC batch request. add() makes a\n")
+	sb.WriteString(" * call with options that queue its request, as callWithMeta does with its options,\n")
+	sb.WriteString(" * and returns a promise of the call's result that settles once send() has run:\n")
+	sb.WriteString(" *\n")
+	fmt.Fprintf(sb, " *   const batch = new %s(transport);\n", className)
+	sb.WriteString(" *   const sum = batch.add((options) => calc.add(1, 2, options));\n")
+	sb.WriteString(" *   await batch.send();\n")
+	sb.WriteString(" *   const { result, error } = await sum;\n")
+	sb.WriteString(" *\n")
+	sb.WriteString(" * A call that fails does not fail the others.\n")
+	sb.WriteString(" */\n")
+	fmt.Fprintf(sb, "export class %s {\n", className)
+	fmt.Fprintf(sb, "  private queued: { request: %s; resolve: (response: any) => void; reject: (err: any) => void }[] = [];\n\n", requestName)
+	fmt.Fprintf(sb, "  constructor(private transport: %s) {}\n\n", applyPackagePrefix("Transport", packagePrefix))
+	sb.WriteString("  /** The number of calls waiting to be sent */\n")
+	sb.WriteString("  get length(): number {\n")
+	sb.WriteString("    return this.queued.length;\n")
+	sb.WriteString("  }\n\n")
+	sb.WriteString("  /**\n")
+	sb.WriteString("   * Adds a client call to the batch. A call that fails before sending anything, such as\n")
+	sb.WriteString("   * on invalid params, settles right away and is not sent.\n")
+	sb.WriteString("   */\n")
+	fmt.Fprintf(sb, "  add<T>(call: (options: %s) => Promise<T>): Promise<%s<T>> {\n", optionsName, resultName)
+	sb.WriteString("    return call({ batch: this }).then((result) => ({ result }), (error) => ({ error }));\n")
+	sb.WriteString("  }\n\n")
+	sb.WriteString("  /**\n")
+	sb.WriteString("   * Queues the request of a call added with add(). The promise resolves to the response\n")
+	sb.WriteString("   * matched to the request once the batch is sent.\n")
+	sb.WriteString("   */\n")
+	fmt.Fprintf(sb, "  queue(method: string, params: any[], options: %s): Promise<any> {\n", optionsName)
+	sb.WriteString("    const { batch, ...requestOptions } = options;\n")
+	sb.WriteString("    return new Promise((resolve, reject) => {\n")
+	sb.WriteString("      this.queued.push({ request: { method, params, options: requestOptions }, resolve, reject });\n")
+	sb.WriteString("    });\n")
+	sb.WriteString("  }\n\n")
+	sb.WriteString("  /**\n")
+	sb.WriteString("   * Sends the queued calls in one request and settles the result of each from its own\n")
+	sb.WriteString("   * response. options apply to the request as a whole, such as its timeout and headers.\n")
+	sb.WriteString("   * If the request as a whole fails, send rejects with its error, which also becomes the\n")
+	sb.WriteString("   * error of every call. A transport without callBatch, or whose server reports it takes\n")
+	sb.WriteString("   * no batches, makes the calls one at a time. The batch is empty afterwards.\n")
+	sb.WriteString("   */\n")
+	fmt.Fprintf(sb, "  async send(options: %s = {}): Promise<void> {\n", optionsName)
+	sb.WriteString("    const queued = this.queued;\n")
+	sb.WriteString("    this.queued = [];\n")
+	sb.WriteString("    const transport: any = this.transport;\n")
+	sb.WriteString("    if (typeof transport.callBatch !== 'function' || (queued.length > 0 && !(await sendsBatches(transport)))) {\n")
+	sb.WriteString("      for (const q of queued) {\n")
+	sb.WriteString("        await this.transport.callWithOptions(q.request.method, q.request.params, q.request.options).then(q.resolve, q.reject);\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("      return;\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    if (queued.length === 0) {\n")
+	sb.WriteString("      return;\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    let responses: any[];\n")
+	sb.WriteString("    try {\n")
+	sb.WriteString("      responses = await transport.callBatch(queued.map((q) => q.request), options);\n")
+	sb.WriteString("    } catch (err) {\n")
+	sb.WriteString("      queued.forEach((q) => q.reject(err));\n")
+	sb.WriteString("      throw err;\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    queued.forEach((q, i) => {\n")
+	sb.WriteString("      if (responses[i] === undefined) {\n")
+	sb.WriteString("        q.reject(new RPCError(-32603, `No response to ${q.request.method} in batch`, undefined));\n")
+	sb.WriteString("      } else {\n")
+	sb.WriteString("        q.resolve(responses[i]);\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("    });\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeCallBatchTs writes HTTPTransport.callBatch
+func writeCallBatchTs(sb *strings.Builder, packagePrefix string) {
+	sb.WriteString("  /**\n")
+	sb.WriteString("   * Sends requests as one JSON-RPC batch request and returns the response to each,\n")
+	sb.WriteString("   * matched by request id, undefined where the server sent none. options apply to the\n")
+	sb.WriteString("   * batch request as a whole.\n")
+	sb.WriteString("   */\n")
+	fmt.Fprintf(sb, "  async callBatch(requests: %s[], options: %s): Promise<any[]> {\n",
+		applyPackagePrefix("BatchRequest", packagePrefix), applyPackagePrefix("CallOptions", packagePrefix))
+	sb.WriteString("    const ids = requests.map(() => crypto.randomUUID());\n")
+	sb.WriteString("    const batch = requests.map((r, i) => ({\n")
+	sb.WriteString("      jsonrpc: '2.0',\n")
+	sb.WriteString("      method: r.method,\n")
+	sb.WriteString("      params: requestParams(r.params, r.options),\n")
+	sb.WriteString("      id: ids[i],\n")
+	sb.WriteString("    }));\n")
+	sb.WriteString("    const [response, responseBody] = await this.post(this.stringify(batch), options);\n")
+	sb.WriteString("    let members: any;\n")
+	sb.WriteString("    try {\n")
+	sb.WriteString("      members = JSON.parse(responseBody);\n")
+	sb.WriteString("    } catch {\n")
+	sb.WriteString("      members = undefined;\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    if (!Array.isArray(members)) {\n")
+	sb.WriteString("      // A batch rejected as a whole gets a single error response\n")
+	sb.WriteString("      this.decodeResponse(response.status, response.statusText, responseBody);\n")
+	fmt.Fprintf(sb, "      throw new %s('Unexpected response to batch request');\n", applyPackagePrefix("TransportError", packagePrefix))
+	sb.WriteString("    }\n")
+	sb.WriteString("    const byId = new Map(members.map((member: any) => [member?.id, member]));\n")
+	sb.WriteString("    return ids.map((id) => byId.get(id));\n")
+	sb.WriteString("  }\n\n")
+}
+
+// writeBatchClientCs writes the Batch API of the C# client
+func writeBatchClientCs(sb *strings.Builder) {
+	sb.WriteString(`/// <summary>
+/// A call sent as a member of a batch request
+/// </summary>
+public sealed record BatchRequest(string Method, object[] Parameters, CallOptions Options);
+
+/// <summary>
+/// The outcome of one call of a Batch: its typed result, or the exception the call threw,
+/// such as an RPCError from its own response
+/// </summary>
+public sealed record BatchResult<T>(T? Result, Exception? Error);
+
+/// <summary>
+/// Implemented by transports that send several calls in one JSON-RPC batch request.
+/// CallBatchAsync returns the response to each request in the order of requests, null
+/// where the server sent none; options apply to the batch request as a whole.
+/// </summary>
+public interface IBatchTransport
+{
+    Task<IReadOnlyList<Dictionary<string, object?>?>> CallBatchAsync(IReadOnlyList<BatchRequest> requests, CallOptions options);
+}
+
+/// <summary>
+/// Collects client calls and sends them in one JSON-RPC batch request. The calls of a
+/// client made WithBatch are queued, and Add turns each into a task of its BatchResult
+/// that completes once SendAsync has run:
+/// <c>var sum = batch.Add(calc.WithBatch(batch).addAsync(1, 2)); await batch.SendAsync();</c>
+/// A call that fails does not fail the others. A Batch is not safe for concurrent use.
+/// </summary>
+public sealed class Batch : ITransport
+{
+    private readonly ITransport _transport;
+    private readonly List<(BatchRequest Request, TaskCompletionSource<Dictionary<string, object?>> Response)> _queued = new();
+
+    public Batch(ITransport transport)
+    {
+        _transport = transport;
+    }
+
+    /// <summary>The number of calls waiting to be sent</summary>
+    public int Count => _queued.Count;
+
+    /// <summary>
+    /// Returns a task of the result of a call made WithBatch, holding its error instead of
+    /// throwing it
+    /// </summary>
+    public async Task<BatchResult<T>> Add<T>(Task<T> call)
+    {
+        try
+        {
+            return new BatchResult<T>(await call, null);
+        }
+        catch (Exception e)
+        {
+            return new BatchResult<T>(default, e);
+        }
+    }
+
+    public Task<Dictionary<string, object?>> CallAsync(string method, object[] parameters)
+    {
+        return CallAsync(method, parameters, CallOptions.None);
+    }
+
+    /// <summary>
+    /// Queues the request of a call made WithBatch. The task completes with the response
+    /// matched to the request once the batch is sent.
+    /// </summary>
+    public Task<Dictionary<string, object?>> CallAsync(string method, object[] parameters, CallOptions options)
+    {
+        var response = new TaskCompletionSource<Dictionary<string, object?>>(TaskCreationOptions.RunContinuationsAsynchronously);
+        _queued.Add((new BatchRequest(method, parameters, options with { Batch = null }), response));
+        return response.Task;
+    }
+
+    /// <summary>
+    /// Sends the queued calls in one request and completes the result of each from its own
+    /// response. options apply to the request as a whole, such as its timeout and headers.
+    /// If the request as a whole fails, SendAsync throws its error, which also becomes the
+    /// error of every call. A transport that is not an IBatchTransport, or whose server
+    /// reports it takes no batches, makes the calls one at a time. The batch is empty
+    /// afterwards.
+    /// </summary>
+    public async Task SendAsync(CallOptions? options = null)
+    {
+        var queued = _queued.ToList();
+        _queued.Clear();
+        if (_transport is not IBatchTransport batchTransport || (queued.Count > 0 && !await SendsBatchesAsync()))
+        {
+            foreach (var (request, response) in queued)
+            {
+                try
+                {
+                    response.SetResult(await _transport.CallAsync(request.Method, request.Parameters, request.Options));
+                }
+                catch (Exception e)
+                {
+                    response.SetException(e);
+                }
+            }
+            return;
+        }
+        if (queued.Count == 0)
+        {
+            return;
+        }
+        IReadOnlyList<Dictionary<string, object?>?> responses;
+        try
+        {
+            responses = await batchTransport.CallBatchAsync(queued.Select(q => q.Request).ToList(), options ?? CallOptions.None);
+        }
+        catch (Exception e)
+        {
+            foreach (var (_, response) in queued)
+            {
+                response.SetException(e);
+            }
+            throw;
+        }
+        for (var i = 0; i < queued.Count; i++)
+        {
+            var (request, response) = queued[i];
+            try
+            {
+                var member = responses[i] ?? throw new RPCError(-32603, "Internal error", $"No response to {request.Method} in batch");
+                response.SetResult(HttpTransport.CheckResponse(member));
+            }
+            catch (RPCError e)
+            {
+                response.SetException(e);
+            }
+        }
+    }
+
+    // Whether the calls go in one batch request: unless the server reports it takes no
+    // batches. When the server cannot be asked the batch request is sent anyway, so its
+    // error is the batch's.
+    private async Task<bool> SendsBatchesAsync()
+    {
+        if (_transport is not ICapabilitiesTransport capabilitiesTransport)
+        {
+            return true;
+        }
+        try
+        {
+            return (await capabilitiesTransport.CapabilitiesAsync()).Batch;
+        }
+        catch (Exception)
+        {
+            return true;
+        }
+    }
+}
+
+`)
+}
+
+// writeCallBatchCs writes HttpTransport.CallBatchAsync
+func writeCallBatchCs(sb *strings.Builder) {
+	sb.WriteString(`    /// <summary>
+    /// Sends requests as one JSON-RPC batch request and returns the response to each,
+    /// matched by request id, null where the server sent none. options apply to the batch
+    /// request as a whole.
+    /// </summary>
+    public async Task<IReadOnlyList<Dictionary<string, object?>?>> CallBatchAsync(IReadOnlyList<BatchRequest> requests, CallOptions options)
+    {
+        var ids = requests.Select(_ => Guid.NewGuid().ToString()).ToList();
+        var batch = requests.Select((r, i) => new Dictionary<string, object?>
+        {
+            { "jsonrpc", "2.0" },
+            { "method", r.Method },
+            { "params", r.Options.RequestParams(r.Parameters) },
+            { "id", ids[i] }
+        }).ToList();
+
+        var responseJson = await PostAsync(Serialize(batch), options);
+        using var document = JsonDocument.Parse(responseJson);
+        if (document.RootElement.ValueKind != JsonValueKind.Array)
+        {
+            // A batch rejected as a whole gets a single error response
+            CheckResponse(JsonSerializer.Deserialize<Dictionary<string, object?>>(responseJson));
+            throw new RPCError(-32603, "Internal error", "Unexpected response to batch request");
+        }
+        var byId = new Dictionary<string, Dictionary<string, object?>>();
+        foreach (var member in document.RootElement.EnumerateArray())
+        {
+            if (member.ValueKind == JsonValueKind.Object && member.TryGetProperty("id", out var id) && id.ValueKind == JsonValueKind.String)
+            {
+                byId[id.GetString()!] = JsonSerializer.Deserialize<Dictionary<string, object?>>(member.GetRawText())!;
+            }
+        }
+        return ids.Select(id => byId.TryGetValue(id, out var member) ? member : null).ToList();
+    }
+
+`)
+}
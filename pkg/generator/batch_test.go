@@ -0,0 +1,192 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBatchClientGenerated(t *testing.T) {
+	tests := []struct {
+		plugin Plugin
+		args   []string
+		files  map[string][]string
+	}{
+		{NewGoClientServer(), nil, map[string][]string{
+			"client.go": {
+				"func Batched[T any](batch *Batch, call func(opt CallOption) (T, error)) *BatchResult[T] {",
+				"func (t *HTTPTransport) CallBatch(requests []BatchRequest, options CallOptions) ([]map[string]interface{}, error) {",
+				"\tif bc := options.batch; bc != nil && !bc.queued {\n",
+			},
+		}},
+		{NewPythonClientServer(), nil, map[string][]string{
+			"client.py": {
+				"class Batch:",
+				"def call_batch(self, requests: List[BatchRequest], options: CallOptions) -> List[Optional[dict]]:",
+			},
+		}},
+		{NewTSClientServer(), nil, map[string][]string{
+			"client.ts": {
+				"export class Batch {",
+				"async callBatch(requests: BatchRequest[], options: CallOptions): Promise<any[]> {",
+				"await callTransport(this.transport, ",
+			},
+		}},
+		{NewCSharpClientServer(), nil, map[string][]string{
+			"Client.cs": {
+				"public sealed class Batch : ITransport",
+				"public CalculatorClient WithBatch(Batch batch) => WithOptions(_options with { Batch = batch });",
+				"(_options.Batch ?? _transport).CallAsync(",
+				// Each call completes from its own member of the batch response
+				"response.SetResult(HttpTransport.CheckResponse(member));",
+				"return new BatchResult<T>(default, e);",
+			},
+		}},
+		{NewJavaClientServer(), []string{"-base-package=com.example"}, map[string][]string{
+			"src/main/java/com/bitmechanic/pulserpc/Batch.java":          {"public final class Batch implements Transport {"},
+			"src/main/java/com/bitmechanic/pulserpc/BatchTransport.java": {"List<Response> callBatch(List<Request> requests, CallOptions options) throws Exception;"},
+			"src/main/java/com/example/calc/CalculatorClient.java":       {"public CalculatorClient withBatch(Batch batch) {"},
+		}},
+	}
+	for _, tt := range tests {
+		dir := generateForTest(t, tt.plugin, batchCalcIDL, tt.args...)
+		for file, wants := range tt.files {
+			content := readGenerated(t, dir, file)
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("%s: %s does not contain %q", tt.plugin.Name(), file, want)
+				}
+			}
+		}
+	}
+}
+
+const batchCalcIDL = `namespace calc
+
+interface Calculator {
+  add(a int, b int) int
+  divide(a int, b int) int
+}`
+
+// batchWant is the output of the checks below: one batch request, then the result or
+// error code of add(1, 2), divide(4, 0) and divide(9, 3)
+const batchWant = "1\n3\nerror 1001\n3"
+
+const batchGoMain = `package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	calc "example.com/calc"
+)
+
+type calculator struct{}
+
+func (calculator) Add(ctx context.Context, a int, b int) (int, error) {
+	return a + b, nil
+}
+
+func (calculator) Divide(ctx context.Context, a int, b int) (int, error) {
+	if b == 0 {
+		return 0, calc.NewRPCError(1001, "division by zero")
+	}
+	return a / b, nil
+}
+
+func main() {
+	server := calc.NewPulseRPCServer("localhost", 0)
+	server.RegisterCalculator(calculator{})
+	batches := 0
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+			batches++
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		server.ServeHTTP(w, r)
+	}))
+	defer httpServer.Close()
+
+	transport := calc.NewHTTPTransport(httpServer.URL, nil)
+	client := calc.NewCalculatorClient(transport)
+	batch := calc.NewBatch(transport)
+	results := []*calc.BatchResult[int]{
+		calc.Batched(batch, func(opt calc.CallOption) (int, error) { return client.Add(1, 2, opt) }),
+		calc.Batched(batch, func(opt calc.CallOption) (int, error) { return client.Divide(4, 0, opt) }),
+		calc.Batched(batch, func(opt calc.CallOption) (int, error) { return client.Divide(9, 3, opt) }),
+	}
+	if err := batch.Send(); err != nil {
+		panic(err)
+	}
+	fmt.Println(batches)
+	for _, res := range results {
+		if rpcErr, ok := res.Err.(*calc.RPCError); ok {
+			fmt.Println("error", rpcErr.Code)
+		} else if res.Err != nil {
+			fmt.Println(res.Err)
+		} else {
+			fmt.Println(res.Result)
+		}
+	}
+}
+`
+
+// TestBatchGoResults sends a batch from a Go client in which one call fails, and
+// checks that each call gets its own result or error
+func TestBatchGoResults(t *testing.T) {
+	dir := generateForTest(t, NewGoClientServer(), batchCalcIDL)
+	if out := runGoCheck(t, dir, "example.com/calc", batchGoMain); out != batchWant {
+		t.Errorf("got:\n%s\nwant:\n%s", out, batchWant)
+	}
+}
+
+const batchPythonCheck = `import http.client
+from client import Batch, CalculatorClient, HTTPTransport
+from pulserpc import RPCError
+from server import PulseRPCServer
+
+class Calculator:
+    def add(self, a, b):
+        return a + b
+
+    def divide(self, a, b):
+        if b == 0:
+            raise RPCError(1001, 'division by zero')
+        return a // b
+
+server = PulseRPCServer()
+server.register('Calculator', Calculator())
+posts = []
+
+class LocalTransport(HTTPTransport):
+    """Posts to the server in this process instead of over a socket"""
+
+    def _post(self, json_data, options):
+        posts.append(json_data)
+        headers = http.client.HTTPMessage()
+        headers['Content-Type'] = 'application/json'
+        _, _, body = server.handle_http('POST', '/', headers, json_data)
+        return body
+
+transport = LocalTransport('http://localhost')
+client = CalculatorClient(transport)
+batch = Batch(transport)
+results = [batch.add(client.add, 1, 2), batch.add(client.divide, 4, 0), batch.add(client.divide, 9, 3)]
+batch.send()
+print(sum(1 for post in posts if post.lstrip().startswith(b'[')))
+for res in results:
+    print(f'error {res.error.code}' if isinstance(res.error, RPCError) else res.error or res.result)
+`
+
+// TestBatchPythonResults sends a batch from a Python client in which one call fails,
+// and checks that each call gets its own result or error
+func TestBatchPythonResults(t *testing.T) {
+	dir := generateForTest(t, NewPythonClientServer(), batchCalcIDL)
+	if out := runPythonCheck(t, dir, batchPythonCheck); out != batchWant {
+		t.Errorf("got:\n%s\nwant:\n%s", out, batchWant)
+	}
+}
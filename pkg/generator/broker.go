@@ -0,0 +1,29 @@
+package generator
+
+import (
+	"flag"
+)
+
+// The -generate-broker-transport flag emits a BrokerTransport next to the Go and
+// Python clients. It carries the JSON-RPC envelope over a message broker's
+// request/reply (NATS request-reply, AMQP reply-to queues) through a requester
+// function the caller wires to their broker client, so the generated code has no
+// broker dependency. Servers answer broker messages with HandleMessage /
+// handle_message, which runs the same dispatch as the HTTP endpoint.
+
+// brokerTransportRequested reports whether the -generate-broker-transport flag is set
+func brokerTransportRequested(fs *flag.FlagSet) bool {
+	f := fs.Lookup("generate-broker-transport")
+	return f != nil && f.Value.String() == "true"
+}
+
+// brokerView is the view model for the BrokerTransport templates
+type brokerView struct {
+	// Package is the Go package of the generated client
+	Package string
+	// RuntimeImport is the Go runtime package to dot-import when namespaces are
+	// split into packages
+	RuntimeImport string
+	// Packaged is true when Python output is a package using relative imports
+	Packaged bool
+}
@@ -0,0 +1,431 @@
+package generator
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/naming"
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// The -generate-test-builders flag emits test data builders that fill the
+// required fields of every struct with valid defaults, so test setup names only
+// the fields a test is about. Go gets a New<Name>ForTest function per struct,
+// Python a make_<name> function taking keyword overrides, Java a <Name>Builder
+// with a<Name>(), with<Field> methods and build(), and C# a static TestData class
+// of New<Name> methods. A string defaults to the field's name, an int to 1, a
+// float to 1.5, a bool to true, an enum to its first value, a list or map to an
+// empty one and a struct to what its own builder returns; the validator rejects
+// cycles of required struct fields, so the builders always return. Optional
+// fields are left unset. Go and C# builders of mutable structs take overrides
+// that change the value before it is returned; [immutable] values can't be
+// changed, so their builders take none. TypeScript and Rust have no builders yet.
+
+// testBuildersRequested reports whether the -generate-test-builders flag is set
+func testBuildersRequested(fs *flag.FlagSet) bool {
+	f := fs.Lookup("generate-test-builders")
+	return f != nil && f.Value.String() == "true"
+}
+
+// builderEnumValue returns the first value of the enum named name
+func builderEnumValue(name string, enumMap map[string]*parser.Enum) (string, bool) {
+	e, ok := enumMap[name]
+	if !ok {
+		e, ok = enumMap[GetBaseName(name)]
+	}
+	if !ok || len(e.Values) == 0 {
+		return "", false
+	}
+	return e.Values[0].Name, true
+}
+
+// builderScalar returns the default of an int or float field
+func builderScalar(builtIn string) (string, bool) {
+	switch builtIn {
+	case "int":
+		return "1", true
+	case "float":
+		return "1.5", true
+	}
+	return "", false
+}
+
+// generateTestBuildersGo generates <namespace>_builders.go with a New<Name>ForTest
+// function for each struct of a namespace
+func generateTestBuildersGo(namespace, packageName string, types *NamespaceTypes, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, layout *goPackageLayout, presence bool) string {
+	// Record the packages the builders name, so only those are imported
+	imports := make(map[string]bool)
+	qualifier := layout.qualifier(namespace, structMap, enumMap)
+	qualify := func(typeName string) string {
+		name := qualifier(typeName)
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			imports[layout.importPath(name[:i])] = true
+		}
+		return name
+	}
+	usesRuntime := false
+
+	var body strings.Builder
+	for _, s := range types.Structs {
+		structName := GetBaseName(s.Name)
+		if s.IsImmutable() {
+			var args []string
+			for _, field := range parser.ResolveStructFields(s, structMap) {
+				value, ok := goBuilderValue(field, structMap, enumMap, qualify)
+				switch {
+				case field.Optional && presence:
+					value = "Optional[" + mapTypeToQualifiedGoType(field.Type, structMap, enumMap, false, qualify) + "]{}"
+					usesRuntime = true
+				case field.Optional || !ok:
+					value = "nil"
+				}
+				args = append(args, value)
+			}
+			fmt.Fprintf(&body, "// New%sForTest returns %s %s with valid defaults in its required fields.\n", structName, builderArticle(structName), structName)
+			fmt.Fprintf(&body, "// %s is immutable, so call New%s for other values.\n", structName, structName)
+			fmt.Fprintf(&body, "func New%sForTest() %s {\n", structName, structName)
+			fmt.Fprintf(&body, "\treturn New%s(%s)\n", structName, strings.Join(args, ", "))
+			body.WriteString("}\n\n")
+			continue
+		}
+
+		fmt.Fprintf(&body, "// New%sForTest returns %s %s with valid defaults in its required fields,\n", structName, builderArticle(structName), structName)
+		body.WriteString("// changed by each of overrides in turn\n")
+		fmt.Fprintf(&body, "func New%sForTest(overrides ...func(*%s)) %s {\n", structName, structName, structName)
+		fmt.Fprintf(&body, "\tv := %s{\n", structName)
+		if s.Extends != "" {
+			fmt.Fprintf(&body, "\t\t%s: %sForTest(),\n", getGoStructOrEnumTypeName(s.Extends, structMap, enumMap), goConstructorName(qualify(s.Extends)))
+		}
+		for _, field := range s.Fields {
+			if field.Optional {
+				continue
+			}
+			if value, ok := goBuilderValue(field, structMap, enumMap, qualify); ok {
+				fmt.Fprintf(&body, "\t\t%s: %s,\n", goFieldName(s, field), value)
+			}
+		}
+		body.WriteString("\t}\n")
+		body.WriteString("\tfor _, override := range overrides {\n")
+		body.WriteString("\t\toverride(&v)\n")
+		body.WriteString("\t}\n")
+		body.WriteString("\treturn v\n")
+		body.WriteString("}\n\n")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
+	fmt.Fprintf(&sb, "package %s\n\n", packageName)
+	if layout != nil && (usesRuntime || len(imports) > 0) {
+		sb.WriteString("import (\n")
+		if usesRuntime {
+			fmt.Fprintf(&sb, "\t. \"%s\"\n", layout.importPath(goRuntimePackage))
+		}
+		paths := make([]string, 0, len(imports))
+		for path := range imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			fmt.Fprintf(&sb, "\t\"%s\"\n", path)
+		}
+		sb.WriteString(")\n\n")
+	}
+	sb.WriteString(strings.TrimSuffix(body.String(), "\n"))
+	return sb.String()
+}
+
+// goBuilderValue returns the Go expression of the default of a required field, or
+// false when the field is left unset
+func goBuilderValue(field *parser.Field, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, qualify func(string) string) (string, bool) {
+	t := field.Type
+	switch {
+	case t.IsBuiltIn() && t.BuiltIn == "string":
+		return fmt.Sprintf("%q", field.Name), true
+	case t.IsBuiltIn() && t.BuiltIn == "bool":
+		return "true", true
+	case t.IsBuiltIn():
+		return builderScalar(t.BuiltIn)
+	case t.IsArray() || t.IsMap():
+		return mapTypeToQualifiedGoType(t, structMap, enumMap, false, qualify) + "{}", true
+	}
+	if nested := lookupStruct(t.UserDefined, structMap); nested != nil {
+		return goConstructorName(qualify(t.UserDefined)) + "ForTest()", true
+	}
+	if value, ok := builderEnumValue(t.UserDefined, enumMap); ok {
+		return qualify(t.UserDefined) + naming.SnakeToPascal(value), true
+	}
+	return "", false
+}
+
+// generateTestBuildersPy generates builders.py with a make_<name> function for
+// each struct. Structs are dicts, so a builder returns the dict of the required
+// fields updated with the keyword overrides; imported namespaces prefix the
+// function name, as in make_inc_response.
+func generateTestBuildersPy(namespaceMap map[string]*NamespaceTypes, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) string {
+	var sb strings.Builder
+	sb.WriteString("# Generated by pulserpc - do not edit\n\n")
+	sb.WriteString("from typing import Any, Dict\n")
+	for _, namespace := range sortedNamespaces(namespaceMap) {
+		for _, s := range namespaceMap[namespace].Structs {
+			sb.WriteString("\n\n")
+			fmt.Fprintf(&sb, "def %s(**overrides: Any) -> Dict[str, Any]:\n", pyBuilderName(s.Name))
+			fmt.Fprintf(&sb, "    \"\"\"Return %s %s with valid defaults in its required fields, updated with overrides\"\"\"\n", builderArticle(s.Name), s.Name)
+			var items []string
+			for _, field := range parser.ResolveStructFields(s, structMap) {
+				if field.Optional {
+					continue
+				}
+				if value, ok := pyBuilderValue(field, structMap, enumMap); ok {
+					items = append(items, fmt.Sprintf("        '%s': %s,\n", field.Name, value))
+				}
+			}
+			if len(items) == 0 {
+				sb.WriteString("    value: Dict[str, Any] = {}\n")
+			} else {
+				sb.WriteString("    value: Dict[str, Any] = {\n")
+				sb.WriteString(strings.Join(items, ""))
+				sb.WriteString("    }\n")
+			}
+			sb.WriteString("    value.update(overrides)\n")
+			sb.WriteString("    return value\n")
+		}
+	}
+	return sb.String()
+}
+
+// pyBuilderName returns the name of the builder function of a struct:
+// "Person" -> "make_person", "inc.Response" -> "make_inc_response"
+func pyBuilderName(structName string) string {
+	return "make_" + naming.ToSnake(strings.ReplaceAll(structName, ".", "_"))
+}
+
+// pyBuilderValue returns the Python expression of the default of a required field, or
+// false when the field is left unset
+func pyBuilderValue(field *parser.Field, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) (string, bool) {
+	t := field.Type
+	switch {
+	case t.IsBuiltIn() && t.BuiltIn == "string":
+		return "'" + field.Name + "'", true
+	case t.IsBuiltIn() && t.BuiltIn == "bool":
+		return "True", true
+	case t.IsBuiltIn():
+		return builderScalar(t.BuiltIn)
+	case t.IsArray():
+		return "[]", true
+	case t.IsMap():
+		return "{}", true
+	}
+	if nested := lookupStruct(t.UserDefined, structMap); nested != nil {
+		return pyBuilderName(nested.Name) + "()", true
+	}
+	if value, ok := builderEnumValue(t.UserDefined, enumMap); ok {
+		return "'" + value + "'", true
+	}
+	return "", false
+}
+
+// builderArticle returns the indefinite article of a struct name: "a" for
+// "Person", "an" for "Order"
+func builderArticle(structName string) string {
+	if strings.ContainsAny(structName[:1], "AEIOUaeiou") {
+		return "an"
+	}
+	return "a"
+}
+
+// javaBuilderMethod returns the name of the static method starting a builder:
+// "Person" -> "aPerson", "Order" -> "anOrder"
+func javaBuilderMethod(structName string) string {
+	return builderArticle(structName) + structName
+}
+
+// generateTestBuilderJava generates the <Name>Builder class of a struct, in the
+// struct's package
+func generateTestBuilderJava(s *parser.Struct, packageName string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, basePackage string) string {
+	className := GetBaseName(s.Name)
+	builderName := className + "Builder"
+	fields := parser.ResolveStructFields(s, structMap)
+
+	var sb strings.Builder
+	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
+	fmt.Fprintf(&sb, "package %s;\n\n", packageName)
+	sb.WriteString("/**\n")
+	fmt.Fprintf(&sb, " * Builds %s values for tests. %s() starts from valid defaults for the\n", className, javaBuilderMethod(className))
+	sb.WriteString(" * required fields, which the with methods override.\n")
+	sb.WriteString(" */\n")
+	fmt.Fprintf(&sb, "public class %s {\n", builderName)
+	for _, field := range fields {
+		fieldType := getJavaTypeWithPackage(field.Type, enumMap, basePackage, packageName)
+		fieldName := naming.LowerFirst(field.Name)
+		if value, ok := javaBuilderValue(field, structMap, enumMap, basePackage, packageName); ok && !field.Optional {
+			fmt.Fprintf(&sb, "    private %s %s = %s;\n", fieldType, fieldName, value)
+		} else {
+			fmt.Fprintf(&sb, "    private %s %s;\n", fieldType, fieldName)
+		}
+	}
+	if len(fields) > 0 {
+		sb.WriteString("\n")
+	}
+
+	fmt.Fprintf(&sb, "    private %s() {\n    }\n\n", builderName)
+	fmt.Fprintf(&sb, "    public static %s %s() {\n", builderName, javaBuilderMethod(className))
+	fmt.Fprintf(&sb, "        return new %s();\n", builderName)
+	sb.WriteString("    }\n\n")
+
+	for _, field := range fields {
+		fieldType := getJavaTypeWithPackage(field.Type, enumMap, basePackage, packageName)
+		fieldName := naming.LowerFirst(field.Name)
+		fmt.Fprintf(&sb, "    public %s with%s(%s %s) {\n", builderName, naming.UpperFirst(fieldName), fieldType, fieldName)
+		fmt.Fprintf(&sb, "        this.%s = %s;\n", fieldName, fieldName)
+		sb.WriteString("        return this;\n")
+		sb.WriteString("    }\n\n")
+	}
+
+	fmt.Fprintf(&sb, "    public %s build() {\n", className)
+	if s.IsImmutable() {
+		args := make([]string, 0, len(fields))
+		for _, field := range fields {
+			args = append(args, "this."+naming.LowerFirst(field.Name))
+		}
+		fmt.Fprintf(&sb, "        return new %s(%s);\n", className, strings.Join(args, ", "))
+	} else {
+		fmt.Fprintf(&sb, "        %s value = new %s();\n", className, className)
+		for _, field := range fields {
+			fieldName := naming.LowerFirst(field.Name)
+			fmt.Fprintf(&sb, "        value.set%s(this.%s);\n", naming.UpperFirst(fieldName), fieldName)
+		}
+		sb.WriteString("        return value;\n")
+	}
+	sb.WriteString("    }\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// javaBuilderValue returns the Java expression of the default of a required field, or
+// false when the field is left unset
+func javaBuilderValue(field *parser.Field, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, basePackage, packageName string) (string, bool) {
+	t := field.Type
+	switch {
+	case t.IsBuiltIn() && t.BuiltIn == "string":
+		return fmt.Sprintf("%q", field.Name), true
+	case t.IsBuiltIn() && t.BuiltIn == "bool":
+		return "true", true
+	case t.IsBuiltIn():
+		return builderScalar(t.BuiltIn)
+	case t.IsArray():
+		return "new java.util.ArrayList<>()", true
+	case t.IsMap():
+		return "new java.util.HashMap<>()", true
+	}
+	typeName := getJavaTypeWithPackage(t, enumMap, basePackage, packageName)
+	if nested := lookupStruct(t.UserDefined, structMap); nested != nil {
+		return fmt.Sprintf("%sBuilder.%s().build()", typeName, javaBuilderMethod(GetBaseName(nested.Name))), true
+	}
+	if value, ok := builderEnumValue(t.UserDefined, enumMap); ok {
+		// Java enum constants keep the IDL value names
+		return typeName + "." + value, true
+	}
+	return "", false
+}
+
+// generateTestDataCs generates TestData.cs, a static class with a New<Name>
+// method for each struct
+func generateTestDataCs(namespaceMap map[string]*NamespaceTypes, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) string {
+	namespaces := sortedNamespaces(namespaceMap)
+	var sb strings.Builder
+	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
+	sb.WriteString("using System;\n")
+	sb.WriteString("using System.Collections.Generic;\n")
+	for _, ns := range namespaces {
+		fmt.Fprintf(&sb, "using %s;\n", ns)
+	}
+	sb.WriteString("\n")
+	sb.WriteString("namespace PulseRPC\n")
+	sb.WriteString("{\n")
+	sb.WriteString("    /// <summary>\n")
+	sb.WriteString("    /// Values of the IDL structs for tests, with valid defaults in their required fields\n")
+	sb.WriteString("    /// </summary>\n")
+	sb.WriteString("    public static class TestData\n")
+	sb.WriteString("    {\n")
+	first := true
+	for _, ns := range namespaces {
+		for _, s := range namespaceMap[ns].Structs {
+			if !first {
+				sb.WriteString("\n")
+			}
+			first = false
+			writeTestDataMethodCs(&sb, s, structMap, enumMap)
+		}
+	}
+	sb.WriteString("    }\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// writeTestDataMethodCs writes the New<Name> method of a struct. Properties of
+// [immutable] classes are init-only, so their method takes no configure action.
+func writeTestDataMethodCs(sb *strings.Builder, s *parser.Struct, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) {
+	className := GetBaseName(s.Name)
+	var inits []string
+	for _, field := range parser.ResolveStructFields(s, structMap) {
+		if field.Optional {
+			continue
+		}
+		if value, ok := csBuilderValue(field, structMap, enumMap); ok {
+			inits = append(inits, fmt.Sprintf("%s = %s,", naming.SnakeToPascal(field.Name), value))
+		}
+	}
+
+	if s.IsImmutable() {
+		fmt.Fprintf(sb, "        // Returns %s %s with valid defaults in its required fields\n", builderArticle(className), className)
+		fmt.Fprintf(sb, "        public static %s New%s()\n", className, className)
+		sb.WriteString("        {\n")
+		fmt.Fprintf(sb, "            return new %s\n", className)
+		writeObjectInitializerCs(sb, "            ", inits, ";")
+		sb.WriteString("        }\n")
+		return
+	}
+	fmt.Fprintf(sb, "        // Returns %s %s with valid defaults in its required fields, after configure has changed it\n", builderArticle(className), className)
+	fmt.Fprintf(sb, "        public static %s New%s(Action<%s>? configure = null)\n", className, className, className)
+	sb.WriteString("        {\n")
+	fmt.Fprintf(sb, "            var value = new %s\n", className)
+	writeObjectInitializerCs(sb, "            ", inits, ";")
+	sb.WriteString("            configure?.Invoke(value);\n")
+	sb.WriteString("            return value;\n")
+	sb.WriteString("        }\n")
+}
+
+// writeObjectInitializerCs writes the braces of a C# object initializer holding
+// inits, followed by end
+func writeObjectInitializerCs(sb *strings.Builder, indent string, inits []string, end string) {
+	sb.WriteString(indent + "{\n")
+	for _, init := range inits {
+		sb.WriteString(indent + "    " + init + "\n")
+	}
+	sb.WriteString(indent + "}" + end + "\n")
+}
+
+// csBuilderValue returns the C# expression of the default of a required field, or
+// false when the field is left unset
+func csBuilderValue(field *parser.Field, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) (string, bool) {
+	t := field.Type
+	switch {
+	case t.IsBuiltIn() && t.BuiltIn == "string":
+		return fmt.Sprintf("%q", field.Name), true
+	case t.IsBuiltIn() && t.BuiltIn == "bool":
+		return "true", true
+	case t.IsBuiltIn():
+		return builderScalar(t.BuiltIn)
+	case t.IsArray() || t.IsMap():
+		return "new " + mapTypeToCsType(t, structMap, enumMap, false) + "()", true
+	}
+	if nested := lookupStruct(t.UserDefined, structMap); nested != nil {
+		return "New" + GetBaseName(nested.Name) + "()", true
+	}
+	if value, ok := builderEnumValue(t.UserDefined, enumMap); ok {
+		return getStructOrEnumTypeName(t.UserDefined, structMap, enumMap) + "." + value, true
+	}
+	return "", false
+}
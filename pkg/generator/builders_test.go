@@ -0,0 +1,153 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const buildersTestIDL = `namespace shop
+
+enum Size {
+    small
+    large
+}
+
+typedef Tags []string
+
+struct Entity {
+    id  string
+}
+
+struct Item extends Entity {
+    name    string
+    price   float
+    size    Size
+    qty     int
+    gift    bool
+    note    string  [optional]
+    tags    Tags
+    extras  map[string]int
+}
+
+struct Money [immutable] {
+    amount    float
+    currency  string
+    memo      string  [optional]
+}
+
+struct Order {
+    item     Item
+    items    []Item
+    history  []Order  [optional]
+    total    Money
+}
+
+interface Shop {
+    place(order Order) Order
+}
+`
+
+// buildersArg turns on the test data builders
+const buildersArg = "-generate-test-builders=true"
+
+// builtOrder is the JSON, with sorted keys, of the order both the Go and the
+// Python builders return when the item's name is overridden
+const builtOrder = `{"item":{"extras":{},"gift":true,"id":"id","name":"pen","price":1.5,"qty":1,"size":"small","tags":[]},"items":[],"total":{"amount":1.5,"currency":"currency"}}`
+
+const buildersGoMain = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	shop "example.com/shop"
+)
+
+func main() {
+	order := shop.NewOrderForTest(func(o *shop.Order) { o.Item.Name = "pen" })
+	data, _ := json.Marshal(order)
+	// Decode and encode again to sort the keys
+	var sorted map[string]interface{}
+	_ = json.Unmarshal(data, &sorted)
+	data, _ = json.Marshal(sorted)
+	fmt.Println(string(data))
+}
+`
+
+// TestTestBuildersGo checks the Go builders fill required fields and apply overrides
+func TestTestBuildersGo(t *testing.T) {
+	dir := generateForTest(t, NewGoClientServer(), buildersTestIDL, buildersArg)
+	if got := runGoCheck(t, dir, "example.com/shop", buildersGoMain); got != builtOrder {
+		t.Errorf("got:\n%s\nwant:\n%s", got, builtOrder)
+	}
+}
+
+const buildersPythonCheck = `import json
+from builders import make_item, make_order
+from client import ALL_ENUMS, ALL_STRUCTS
+from pulserpc import validate_struct
+
+order = make_order(item=make_item(name='pen'))
+validate_struct(order, 'Order', ALL_STRUCTS['Order'], ALL_STRUCTS, ALL_ENUMS)
+print(json.dumps(order, sort_keys=True, separators=(',', ':')))
+`
+
+// TestTestBuildersPython checks the Python builders return valid structs equal to
+// the Go builders' values
+func TestTestBuildersPython(t *testing.T) {
+	dir := generateForTest(t, NewPythonClientServer(), buildersTestIDL, buildersArg)
+	if got := runPythonCheck(t, dir, buildersPythonCheck); got != builtOrder {
+		t.Errorf("got:\n%s\nwant:\n%s", got, builtOrder)
+	}
+}
+
+// TestTestBuildersJavaAndCSharp checks the Java builder classes and the C#
+// TestData class
+func TestTestBuildersJavaAndCSharp(t *testing.T) {
+	javaDir := filepath.Join(generateForTest(t, NewJavaClientServer(), buildersTestIDL, buildersArg, "-base-package=com.example"), "src", "main", "java", "com", "example", "shop")
+	order := readGenerated(t, javaDir, "OrderBuilder.java")
+	for _, want := range []string{
+		"public static OrderBuilder anOrder() {",
+		"private Item item = ItemBuilder.anItem().build();",
+		"private java.util.List<Item> items = new java.util.ArrayList<>();",
+		"private java.util.List<Order> history;",
+		"public OrderBuilder withHistory(java.util.List<Order> history) {",
+		"value.setTotal(this.total);",
+	} {
+		if !strings.Contains(order, want) {
+			t.Errorf("OrderBuilder.java missing %q:\n%s", want, order)
+		}
+	}
+	item := readGenerated(t, javaDir, "ItemBuilder.java")
+	for _, want := range []string{
+		`private String id = "id";`,
+		"private Size size = Size.small;",
+		"private String note;",
+		"value.setId(this.id);",
+	} {
+		if !strings.Contains(item, want) {
+			t.Errorf("ItemBuilder.java missing %q:\n%s", want, item)
+		}
+	}
+	if money := readGenerated(t, javaDir, "MoneyBuilder.java"); !strings.Contains(money, "return new Money(this.amount, this.currency, this.memo);") {
+		t.Errorf("MoneyBuilder.java should build with the all-args constructor:\n%s", money)
+	}
+
+	testData := readGenerated(t, generateForTest(t, NewCSharpClientServer(), buildersTestIDL, buildersArg), "TestData.cs")
+	for _, want := range []string{
+		"public static Order NewOrder(Action<Order>? configure = null)",
+		"Item = NewItem(),",
+		"Size = Size.small,",
+		"Tags = new List<string>(),",
+		"Extras = new Dictionary<string, int>(),",
+		"public static Money NewMoney()",
+	} {
+		if !strings.Contains(testData, want) {
+			t.Errorf("TestData.cs missing %q:\n%s", want, testData)
+		}
+	}
+	if strings.Contains(testData, "Note =") {
+		t.Errorf("TestData.cs should leave optional fields unset:\n%s", testData)
+	}
+}
@@ -0,0 +1,454 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Response caching: a [readonly] method annotated [cache="60s"] gets caching
+// headers on its GET route. Every server tags successful GET responses of such
+// a method with a strong ETag, the quoted hex SHA-256 of the response body, and
+// Cache-Control: max-age=<seconds>, and answers a request whose If-None-Match
+// lists that ETag with 304 Not Modified and no body. The handler still runs, so
+// the saving is in bytes sent and parsed, not in server work. Errors are never
+// tagged. The Go, Python and TypeScript clients can send calls to these methods
+// as conditional GET requests (SetConditionalRequests, conditional_requests=True,
+// setConditionalRequests): the transport keeps the last response and ETag of each
+// URL and reuses the response on 304. The C# and Java clients keep calling them
+// over POST.
+
+// usesCachedMethods reports whether any method has a [cache] annotation
+func usesCachedMethods(interfaces []*parser.Interface) bool {
+	for _, iface := range interfaces {
+		for _, method := range iface.Methods {
+			if cacheControl(method) != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cacheControl returns the Cache-Control header of the GET responses of a method, or ""
+// if it has no [cache] annotation
+func cacheControl(method *parser.Method) string {
+	maxAge, ok := method.CacheMaxAge()
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("max-age=%d", maxAge/time.Second)
+}
+
+// cachedRoutes returns the GET routes of the [cache] methods in declaration order
+func cachedRoutes(interfaces []*parser.Interface) []restRoute {
+	var routes []restRoute
+	for _, route := range collectRESTRoutes(interfaces) {
+		if cacheControl(route.Method) != "" {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// writeCacheHelpersGo writes the ETag helpers of the Go server
+func writeCacheHelpersGo(sb *strings.Builder) {
+	sb.WriteString(`// responseETag returns the strong ETag of a response body: its quoted hex SHA-256
+func responseETag(body []byte) string {
+	return fmt.Sprintf("\"%x\"", sha256.Sum256(body))
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, or is "*". Weak
+// validators (W/"...") match by their opaque tag, as the weak comparison requires.
+func etagMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+`)
+}
+
+// writeConditionalRequestsGo writes the table of [cache] methods and the conditional GET
+// support of the Go HTTPTransport
+func writeConditionalRequestsGo(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("// cachedMethods maps the [cache] methods to the GET paths they are served at\n")
+	sb.WriteString("var cachedMethods = map[string]string{\n")
+	for _, route := range cachedRoutes(interfaces) {
+		fmt.Fprintf(sb, "	%q: %q,\n", route.RPCMethod(), route.Path())
+	}
+	sb.WriteString("}\n\n")
+
+	sb.WriteString(`// maxCachedResponses bounds the responses an HTTPTransport keeps for conditional requests
+const maxCachedResponses = 256
+
+// cachedResponse is a response body kept with its ETag
+type cachedResponse struct {
+	etag string
+	body []byte
+}
+
+// responseCache keeps the last response of each URL, forgetting the oldest URL when full
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+	order   []string
+}
+
+func (c *responseCache) get(target string) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[target]
+	return entry, ok
+}
+
+func (c *responseCache) put(target string, entry cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[target]; !ok {
+		if len(c.order) >= maxCachedResponses {
+			delete(c.entries, c.order[0])
+			c.order = c.order[1:]
+		}
+		c.order = append(c.order, target)
+	}
+	c.entries[target] = entry
+}
+
+// SetConditionalRequests controls conditional requests for [cache] methods. When on, their
+// calls are sent as HTTP GET with the params in the query string, and the transport keeps
+// the last response and ETag of each URL, sends the ETag in If-None-Match and reuses the
+// kept response when the server answers 304 Not Modified.
+func (t *HTTPTransport) SetConditionalRequests(enabled bool) {
+	t.cache = nil
+	if enabled {
+		t.cache = &responseCache{entries: make(map[string]cachedResponse)}
+	}
+}
+
+// conditionalGet calls a [cache] method over HTTP GET, revalidating the response kept for
+// the same URL
+func (t *HTTPTransport) conditionalGet(path string, params []interface{}, options CallOptions) (map[string]interface{}, error) {
+	query := url.Values{}
+	for i, name := range options.ParamNames {
+		addQueryParam(query, name, JSONValue(params[i]))
+	}
+	target := t.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	ctx := options.baseContext()
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range options.Headers {
+		req.Header.Set(k, v)
+	}
+	if options.Timeout > 0 {
+		req.Header.Set(DeadlineHeader, DeadlineHeaderValue(options.Timeout))
+	}
+	if t.signer != nil {
+		// Servers verify GET requests with an empty body
+		if err := t.signer(req, nil); err != nil {
+			return nil, fmt.Errorf("failed to sign request: %w", err)
+		}
+	}
+	cached, ok := t.cache.get(target)
+	if ok {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, &TransportError{Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified && ok {
+		return decodeRPCResponse(http.StatusOK, bytes.NewReader(cached.body))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &TransportError{StatusCode: resp.StatusCode, Err: err}
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" && resp.StatusCode == http.StatusOK {
+		t.cache.put(target, cachedResponse{etag: etag, body: body})
+	}
+	return decodeRPCResponse(resp.StatusCode, bytes.NewReader(body))
+}
+
+// addQueryParam adds a param in its JSON form to a query string: arrays as repeated keys,
+// and nothing for null
+func addQueryParam(query url.Values, name string, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+	case []interface{}:
+		for _, elem := range v {
+			addQueryParam(query, name, elem)
+		}
+	case float64:
+		query.Add(name, strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		query.Add(name, fmt.Sprint(v))
+	}
+}
+
+`)
+}
+
+// writeCachedMethodsPy writes the CACHED_METHODS table of the Python client
+func writeCachedMethodsPy(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("# GET paths of the [cache] methods, which HTTPTransport can call with conditional requests\n")
+	sb.WriteString("CACHED_METHODS = {\n")
+	for _, route := range cachedRoutes(interfaces) {
+		fmt.Fprintf(sb, "    '%s': '%s',\n", route.RPCMethod(), route.Path())
+	}
+	sb.WriteString("}\n\n")
+	sb.WriteString("# The number of responses an HTTPTransport keeps for conditional requests\n")
+	sb.WriteString("MAX_CACHED_RESPONSES = 256\n\n\n")
+}
+
+// writeConditionalGetPy writes _conditional_get of the Python HTTPTransport
+func writeConditionalGetPy(sb *strings.Builder) {
+	sb.WriteString(`    def _conditional_get(self, path: str, params: list, options: CallOptions) -> dict:
+        """Call a [cache] method over HTTP GET with its params in the query string, sending the
+        ETag of the response kept for the same URL in If-None-Match and reusing that response
+        when the server answers 304 Not Modified"""
+        query = []
+        for name, value in zip(options.param_names, params):
+            for v in (value if isinstance(value, list) else [value]):
+                if v is not None:
+                    query.append((name, ('true' if v else 'false') if isinstance(v, bool) else str(v)))
+        target = self.base_url + path
+        if query:
+            target += '?' + urllib.parse.urlencode(query)
+
+        req = urllib.request.Request(target, method='GET')
+        for key, value in self.headers.items():
+            req.add_header(key, value)
+        for key, value in options.headers.items():
+            req.add_header(key, value)
+        if self.signer is not None:
+            # Servers verify GET requests with an empty body
+            for key, value in self.signer(b'').items():
+                req.add_header(key, value)
+        with self._cache_lock:
+            cached = self._cache.get(target)
+        if cached is not None:
+            req.add_header('If-None-Match', cached[0])
+        timeout = self._call_timeout(req, options)
+
+        status, headers, body = self._send(req, timeout)
+        if status == 304 and cached is not None:
+            return self._decode_response(cached[1])
+        etag = headers.get('ETag')
+        if status == 200 and etag:
+            with self._cache_lock:
+                if target not in self._cache and len(self._cache) >= MAX_CACHED_RESPONSES:
+                    del self._cache[next(iter(self._cache))]
+                self._cache[target] = (etag, body)
+        return self._decode_response(body)
+
+`)
+}
+
+// writeCacheHelpersPy writes the ETag helpers of the Python server
+func writeCacheHelpersPy(sb *strings.Builder) {
+	sb.WriteString(`def _response_etag(body: bytes) -> str:
+    """Return the strong ETag of a response body: its quoted hex SHA-256"""
+    return '"' + hashlib.sha256(body).hexdigest() + '"'
+
+
+def _etag_matches(header: Optional[str], etag: str) -> bool:
+    """Report whether an If-None-Match header lists etag, or is "*". Weak validators (W/"...")
+    match by their opaque tag, as the weak comparison requires."""
+    if not header:
+        return False
+    for candidate in header.split(','):
+        candidate = candidate.strip()
+        if candidate.startswith('W/'):
+            candidate = candidate[2:]
+        if candidate == '*' or candidate == etag:
+            return True
+    return False
+
+
+`)
+}
+
+// writeCacheHelpersTs writes the ETag helpers of the TypeScript server
+func writeCacheHelpersTs(sb *strings.Builder) {
+	sb.WriteString(`// Returns the strong ETag of a response body: its quoted hex SHA-256
+function responseETag(body: string): string {
+  return '"' + createHash('sha256').update(body, 'utf8').digest('hex') + '"';
+}
+
+// Reports whether an If-None-Match header lists etag, or is "*". Weak validators (W/"...")
+// match by their opaque tag, as the weak comparison requires.
+function etagMatches(header: string | undefined, etag: string): boolean {
+  if (!header) {
+    return false;
+  }
+  return header.split(',').some((candidate) => {
+    candidate = candidate.trim().replace(/^W\//, '');
+    return candidate === '*' || candidate === etag;
+  });
+}
+
+`)
+}
+
+// writeCachedMethodsTs writes the CACHED_METHODS table of the TypeScript client
+func writeCachedMethodsTs(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("// GET paths of the [cache] methods, which HTTPTransport can call with conditional requests\n")
+	sb.WriteString("const CACHED_METHODS: { [method: string]: string } = {\n")
+	for _, route := range cachedRoutes(interfaces) {
+		fmt.Fprintf(sb, "  '%s': '%s',\n", route.RPCMethod(), route.Path())
+	}
+	sb.WriteString("};\n\n")
+	sb.WriteString("// The number of responses an HTTPTransport keeps for conditional requests\n")
+	sb.WriteString("const MAX_CACHED_RESPONSES = 256;\n\n")
+}
+
+// writeConditionalRequestsTs writes setConditionalRequests and conditionalGet of the
+// TypeScript HTTPTransport
+func writeConditionalRequestsTs(sb *strings.Builder, optionsName string) {
+	sb.WriteString(`  /**
+   * Controls conditional requests for [cache] methods. When on, their calls are sent as
+   * HTTP GET with the params in the query string, and the transport keeps the last
+   * response and ETag of each URL, sends the ETag in If-None-Match and reuses the kept
+   * response when the server answers 304 Not Modified.
+   */
+  setConditionalRequests(enabled: boolean): void {
+    this.cache = enabled ? new Map() : null;
+  }
+
+`)
+	fmt.Fprintf(sb, "  // Calls a [cache] method over HTTP GET, revalidating the response kept for the same URL\n")
+	fmt.Fprintf(sb, "  private async conditionalGet(path: string, params: any[], options: %s): Promise<any> {\n", optionsName)
+	sb.WriteString(`    const query = new URLSearchParams();
+    (options.paramNames || []).forEach((name, i) => {
+      for (const value of Array.isArray(params[i]) ? params[i] : [params[i]]) {
+        if (value !== null && value !== undefined) {
+          query.append(name, String(value));
+        }
+      }
+    });
+    const search = query.toString();
+    const target = this.baseUrl + path + (search ? '?' + search : '');
+
+    const headers: Record<string, string> = { ...this.headers, ...options.headers };
+    if (this.signer !== null) {
+      // Servers verify GET requests with an empty body
+      Object.assign(headers, await this.signer(''));
+    }
+    const cache = this.cache as Map<string, { etag: string; body: string }>;
+    const cached = cache.get(target);
+    if (cached !== undefined) {
+      headers['If-None-Match'] = cached.etag;
+    }
+    const timeoutMs = callTimeoutMs(options, headers);
+
+    const [response, responseBody] = await this.send(target, {
+      method: 'GET',
+      headers: headers,
+      signal: timeoutMs !== undefined ? AbortSignal.timeout(timeoutMs) : undefined,
+    });
+    if (response.status === 304 && cached !== undefined) {
+      return this.decodeResponse(200, '', cached.body);
+    }
+    const etag = response.headers.get('ETag');
+    if (response.status === 200 && etag) {
+      if (!cache.has(target) && cache.size >= MAX_CACHED_RESPONSES) {
+        cache.delete(cache.keys().next().value as string);
+      }
+      cache.set(target, { etag, body: responseBody });
+    }
+    return this.decodeResponse(response.status, response.statusText, responseBody);
+  }
+
+`)
+}
+
+// writeCacheHelpersCs writes the ETag helpers of the C# server
+func writeCacheHelpersCs(sb *strings.Builder) {
+	sb.WriteString(`    // Returns the strong ETag of a response body: its quoted hex SHA-256
+    private static string ResponseETag(byte[] body)
+    {
+        return "\"" + Convert.ToHexString(System.Security.Cryptography.SHA256.HashData(body)).ToLowerInvariant() + "\"";
+    }
+
+    // Reports whether an If-None-Match header lists etag, or is "*". Weak validators (W/"...")
+    // match by their opaque tag, as the weak comparison requires.
+    private static bool ETagMatches(string header, string etag)
+    {
+        foreach (var part in header.Split(','))
+        {
+            var candidate = part.Trim();
+            if (candidate.StartsWith("W/"))
+            {
+                candidate = candidate.Substring(2);
+            }
+            if (candidate == "*" || candidate == etag)
+            {
+                return true;
+            }
+        }
+        return false;
+    }
+
+`)
+}
+
+// writeCacheHelpersJava writes the ETag helpers of the Java server
+func writeCacheHelpersJava(sb *strings.Builder) {
+	sb.WriteString(`    // Returns the strong ETag of a response body: its quoted hex SHA-256
+    private static String responseETag(byte[] body) {
+        try {
+            StringBuilder etag = new StringBuilder("\"");
+            for (byte b : java.security.MessageDigest.getInstance("SHA-256").digest(body)) {
+                etag.append(String.format("%02x", b));
+            }
+            return etag.append('"').toString();
+        } catch (java.security.NoSuchAlgorithmException e) {
+            // Every Java platform implements SHA-256
+            throw new IllegalStateException(e);
+        }
+    }
+
+    // Reports whether an If-None-Match header lists etag, or is "*". Weak validators (W/"...")
+    // match by their opaque tag, as the weak comparison requires.
+    private static boolean etagMatches(String header, String etag) {
+        if (header == null) {
+            return false;
+        }
+        for (String part : header.split(",")) {
+            String candidate = part.trim();
+            if (candidate.startsWith("W/")) {
+                candidate = candidate.substring(2);
+            }
+            if (candidate.equals("*") || candidate.equals(etag)) {
+                return true;
+            }
+        }
+        return false;
+    }
+
+`)
+}
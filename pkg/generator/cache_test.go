@@ -0,0 +1,143 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestCacheControl(t *testing.T) {
+	idl, err := parser.ParseIDL("shop.pulse", "interface Catalog {\n  get(id string) string [readonly] [cache=\"90s\"]\n  list() []string [readonly] [cache=\"2m\"]\n  count() int [readonly]\n}")
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	want := map[string]string{"get": "max-age=90", "list": "max-age=120", "count": ""}
+	for _, method := range idl.Interfaces[0].Methods {
+		if got := cacheControl(method); got != want[method.Name] {
+			t.Errorf("cacheControl(%s) = %q, want %q", method.Name, got, want[method.Name])
+		}
+	}
+	routes := cachedRoutes(idl.Interfaces)
+	if len(routes) != 2 || routes[0].Path() != "/Catalog/get" || routes[1].Path() != "/Catalog/list" {
+		t.Errorf("cachedRoutes = %v, want /Catalog/get and /Catalog/list", routes)
+	}
+}
+
+func TestResponseCachingGenerated(t *testing.T) {
+	tests := []struct {
+		name    string
+		idl     string
+		enabled bool
+	}{
+		{"cache methods", "interface Catalog {\n  get(id string) string [readonly] [cache=\"60s\"]\n  count() int [readonly]\n}", true},
+		{"no cache methods", "interface Catalog {\n  count() int [readonly]\n}", false},
+	}
+	plugins := []struct {
+		plugin Plugin
+		file   string
+		want   []string
+	}{
+		{
+			plugin: NewGoClientServer(),
+			file:   "server.go",
+			want: []string{
+				"\t\"crypto/sha256\"\n",
+				"\t\tcacheControl: \"max-age=60\",\n",
+				"if etagMatches(r.Header.Get(\"If-None-Match\"), etag) {",
+			},
+		},
+		{
+			plugin: NewGoClientServer(),
+			file:   "client.go",
+			want: []string{
+				"var cachedMethods = map[string]string{\n\t\"Catalog.get\": \"/Catalog/get\",\n}",
+				"func (t *HTTPTransport) SetConditionalRequests(enabled bool) {",
+			},
+		},
+		{
+			plugin: NewPythonClientServer(),
+			file:   "server.py",
+			want: []string{
+				"import hashlib\n",
+				"        'cache_control': 'max-age=60',\n",
+				"def _etag_matches(header: Optional[str], etag: str) -> bool:",
+			},
+		},
+		{
+			plugin: NewPythonClientServer(),
+			file:   "client.py",
+			want: []string{
+				"CACHED_METHODS = {\n    'Catalog.get': '/Catalog/get',\n}",
+				"def _conditional_get(self, path: str, params: list, options: CallOptions) -> dict:",
+			},
+		},
+		{
+			plugin: NewTSClientServer(),
+			file:   "server.ts",
+			want: []string{
+				"import { createHash } from 'crypto';\n",
+				"    cacheControl: 'max-age=60',\n",
+				"if (etagMatches(headers['if-none-match'], etag)) {",
+			},
+		},
+		{
+			plugin: NewTSClientServer(),
+			file:   "client.ts",
+			want: []string{
+				"const CACHED_METHODS: { [method: string]: string } = {\n  'Catalog.get': '/Catalog/get',\n};",
+				"  setConditionalRequests(enabled: boolean): void {",
+			},
+		},
+		{
+			plugin: NewCSharpClientServer(),
+			file:   "Server.cs",
+			want: []string{
+				"            }, \"max-age=60\") },\n",
+				"if (ETagMatches(context.Request.Headers[\"If-None-Match\"].ToString(), etag))",
+			},
+		},
+		{
+			plugin: NewJavaClientServer(),
+			file:   "src/main/java/com/example/Server.java",
+			want: []string{
+				"new ReadOnlyRoute(\"Catalog.get\", new String[] {\"id\"}, new String[] {\"string\"}, \"max-age=60\")",
+				"exchange.sendResponseHeaders(304, -1);",
+			},
+		},
+	}
+	for _, tt := range tests {
+		idl, err := parser.ParseIDL("shop.pulse", tt.idl)
+		if err != nil {
+			t.Fatalf("%s: ParseIDL failed: %v", tt.name, err)
+		}
+		for _, p := range plugins {
+			tmpDir := t.TempDir()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.String("dir", "", "output dir")
+			p.plugin.RegisterFlags(fs)
+			for name, value := range map[string]string{"dir": tmpDir, "base-package": "com.example"} {
+				if fs.Lookup(name) != nil {
+					if err := fs.Set(name, value); err != nil {
+						t.Fatalf("failed to set %s flag: %v", name, err)
+					}
+				}
+			}
+			if err := p.plugin.Generate(idl, fs); err != nil {
+				t.Fatalf("%s: %s: Generate failed: %v", tt.name, p.plugin.Name(), err)
+			}
+			content, err := os.ReadFile(filepath.Join(tmpDir, p.file))
+			if err != nil {
+				t.Fatalf("%s: expected %s: %v", p.plugin.Name(), p.file, err)
+			}
+			for _, want := range p.want {
+				if got := strings.Contains(string(content), want); got != tt.enabled {
+					t.Errorf("%s: %s: %s contains %q = %v, want %v", tt.name, p.plugin.Name(), p.file, want, got, tt.enabled)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package generator
+
+import "strings"
+
+// Canonical JSON: every runtime has a CanonicalJSON helper (canonical_json,
+// canonicalJson, CanonicalJson.encode, CanonicalJson.Encode, canonical_json) that
+// encodes a value as RFC 8785 JSON: keys sorted by UTF-16 code units, no
+// whitespace and numbers formatted as JavaScript does, so equal values are equal
+// bytes in every language. RequestHash is built on it. Every server and HTTP
+// transport can also encode whole messages that way, off until enabled with
+// SetCanonicalJSON (Go), canonical_json=True (Python), setCanonicalJson(true)
+// (TypeScript and Java), CanonicalJson = true (C#) or set_canonical_json(true)
+// (Rust): a signature over a canonical request body can be recomputed from the
+// decoded request, and golden responses are byte-stable across server languages.
+// Canonical encoding is slower, and Go's skips the HTML escaping of encoding/json.
+
+// writeCanonicalServerGo writes SetCanonicalJSON and the writeResponse method the Go
+// server encodes its responses with
+func writeCanonicalServerGo(sb *strings.Builder) {
+	sb.WriteString(`// SetCanonicalJSON controls whether responses are encoded as canonical JSON (RFC 8785,
+// see CanonicalJSON), so equal responses are equal bytes whatever language the server is
+// written in. It is off by default.
+func (s *PulseRPCServer) SetCanonicalJSON(enabled bool) {
+	s.canonicalJSON = enabled
+}
+
+// writeResponse encodes the response into buf, as canonical JSON if SetCanonicalJSON is on
+func (s *PulseRPCServer) writeResponse(buf *bytes.Buffer, response *rpcResponse) error {
+	if !s.canonicalJSON {
+		return writeResponse(buf, response)
+	}
+	data, err := CanonicalJSON(response.envelope())
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+`)
+}
+
+// writeCanonicalClientGo writes SetCanonicalJSON and the marshal method the Go
+// HTTPTransport encodes its requests with
+func writeCanonicalClientGo(sb *strings.Builder) {
+	sb.WriteString(`// SetCanonicalJSON controls whether requests are encoded as canonical JSON (RFC 8785,
+// see CanonicalJSON), so a server in any language can recompute the signed body from the
+// request it decoded. Call it before the first call.
+func (t *HTTPTransport) SetCanonicalJSON(enabled bool) {
+	t.canonicalJSON = enabled
+}
+
+// marshal encodes a request body, as canonical JSON if SetCanonicalJSON is on
+func (t *HTTPTransport) marshal(v interface{}) ([]byte, error) {
+	if t.canonicalJSON {
+		return CanonicalJSON(v)
+	}
+	return json.Marshal(v)
+}
+
+`)
+}
+
+// writeCanonicalDumpsPy writes the _dumps method the Python server and HTTPTransport
+// encode messages with. what names the messages.
+func writeCanonicalDumpsPy(sb *strings.Builder, what string) {
+	sb.WriteString("    def _dumps(self, value: Any) -> bytes:\n")
+	sb.WriteString("        \"\"\"Encode " + what + " as JSON, or as canonical JSON (RFC 8785) if canonical_json is set\"\"\"\n")
+	sb.WriteString("        if self.canonical_json:\n")
+	sb.WriteString("            return canonical_json(value).encode('utf-8')\n")
+	sb.WriteString("        return json.dumps(value).encode('utf-8')\n\n")
+}
+
+// writeCanonicalTs writes setCanonicalJson and the stringify method the TypeScript
+// server and HTTPTransport encode messages with. what names the messages.
+func writeCanonicalTs(sb *strings.Builder, what string) {
+	sb.WriteString("  /**\n")
+	sb.WriteString("   * Controls whether " + what + " are encoded as canonical JSON (RFC 8785, see\n")
+	sb.WriteString("   * canonicalJson), so equal messages are equal bytes in every language. Off by default.\n")
+	sb.WriteString("   */\n")
+	sb.WriteString("  setCanonicalJson(enabled: boolean): void {\n")
+	sb.WriteString("    this.canonical = enabled;\n")
+	sb.WriteString("  }\n\n")
+	sb.WriteString("  // Encodes value as JSON, or as canonical JSON when setCanonicalJson is on\n")
+	sb.WriteString("  private stringify(value: any): string {\n")
+	sb.WriteString("    return this.canonical ? canonicalJson(value) : JSON.stringify(value);\n")
+	sb.WriteString("  }\n\n")
+}
+
+// writeCanonicalServerJava writes setCanonicalJson and the toJson method the Java
+// server encodes its responses with
+func writeCanonicalServerJava(sb *strings.Builder) {
+	sb.WriteString(`    /**
+     * Controls whether responses are encoded as canonical JSON (RFC 8785, see
+     * CanonicalJson), so equal responses are equal bytes whatever language the server is
+     * written in. It is off by default.
+     */
+    public void setCanonicalJson(boolean enabled) {
+        this.canonicalJson = enabled;
+    }
+
+    // Encodes a response as JSON, or as canonical JSON if setCanonicalJson is on. Handler
+    // results are decoded back to maps and lists first, which CanonicalJson encodes.
+    private String toJson(Object value) {
+        if (!canonicalJson) {
+            return jsonParser.toJson(value);
+        }
+        return CanonicalJson.encode(jsonParser.fromJson(jsonParser.toJson(value), Object.class));
+    }
+
+`)
+}
+
+// writeCanonicalServerCs writes the WriteMessage method the C# server writes its
+// responses with
+func writeCanonicalServerCs(sb *strings.Builder) {
+	sb.WriteString(`    // Writes the envelope of a response, as canonical JSON if CanonicalJson is on
+    private void WriteMessage(System.IO.MemoryStream output, RpcResponse response)
+    {
+        if (!CanonicalJson)
+        {
+            WriteEnvelope(output, response);
+            return;
+        }
+        using var envelope = new System.IO.MemoryStream();
+        WriteEnvelope(envelope, response);
+        using var document = JsonDocument.Parse(envelope.ToArray());
+        output.Write(System.Text.Encoding.UTF8.GetBytes(global::PulseRPC.CanonicalJson.Encode(document.RootElement)));
+    }
+
+`)
+}
+
+// writeCanonicalTransportCs writes the Serialize method the C# HttpTransport encodes
+// its requests with
+func writeCanonicalTransportCs(sb *strings.Builder) {
+	sb.WriteString(`    // Encodes a request body as JSON, or as canonical JSON if CanonicalJson is on
+    private byte[] Serialize(object value)
+    {
+        if (CanonicalJson)
+        {
+            return System.Text.Encoding.UTF8.GetBytes(global::PulseRPC.CanonicalJson.Encode(value, _jsonOptions));
+        }
+        return JsonSerializer.SerializeToUtf8Bytes(value, _jsonOptions);
+    }
+
+`)
+}
@@ -0,0 +1,143 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+const canonicalTestIDL = `namespace geo
+
+struct Point {
+    y     float
+    x     float
+    label string
+}
+
+interface Geo {
+    origin() Point
+}
+`
+
+const canonicalRequest = `{"jsonrpc": "2.0", "id": 1, "method": "Geo.origin", "params": []}`
+
+// canonicalResponse is the response both servers must write byte for byte: keys
+// sorted, no whitespace, numbers as JavaScript formats them and no HTML escaping
+const canonicalResponse = `{"id":1,"jsonrpc":"2.0","result":{"label":"<é>","x":0.5,"y":1e+21}}`
+
+const canonicalGoMain = `package main
+
+import (
+	"context"
+	"fmt"
+
+	geo "example.com/geo"
+)
+
+type handler struct{}
+
+func (handler) Origin(ctx context.Context) (geo.Point, error) {
+	return geo.Point{Y: 1e21, X: 0.5, Label: "<é>"}, nil
+}
+
+func main() {
+	server := geo.NewPulseRPCServer("localhost", 0)
+	server.RegisterGeo(handler{})
+	server.SetCanonicalJSON(true)
+	fmt.Println(string(server.HandleMessage([]byte(` + "`" + canonicalRequest + "`" + `))))
+}
+`
+
+// TestCanonicalJSONGoServer checks a Go server in canonical mode writes canonicalResponse
+func TestCanonicalJSONGoServer(t *testing.T) {
+	dir := generateForTest(t, NewGoClientServer(), canonicalTestIDL)
+	if got := runGoCheck(t, dir, "example.com/geo", canonicalGoMain); got != canonicalResponse {
+		t.Errorf("got:\n%s\nwant:\n%s", got, canonicalResponse)
+	}
+}
+
+const canonicalPythonCheck = `import sys
+from server import PulseRPCServer
+
+class Geo:
+    def origin(self):
+        return {'y': 1e21, 'x': 0.5, 'label': '<é>'}
+
+server = PulseRPCServer(canonical_json=True)
+server.register('Geo', Geo())
+sys.stdout.buffer.write(server.handle_message(b'` + canonicalRequest + `'))
+`
+
+// TestCanonicalJSONPythonServer checks a Python server in canonical mode writes the
+// same bytes as the Go server
+func TestCanonicalJSONPythonServer(t *testing.T) {
+	dir := generateForTest(t, NewPythonClientServer(), canonicalTestIDL)
+	if got := runPythonCheck(t, dir, canonicalPythonCheck); got != canonicalResponse {
+		t.Errorf("got:\n%s\nwant:\n%s", got, canonicalResponse)
+	}
+}
+
+// TestCanonicalJSONOptions checks the C#, Java and Rust servers and transports, which
+// cannot be run here, offer canonical mode and encode messages through it
+func TestCanonicalJSONOptions(t *testing.T) {
+	tests := []struct {
+		plugin Plugin
+		args   []string
+		files  map[string][]string
+	}{
+		{
+			plugin: NewCSharpClientServer(),
+			files: map[string][]string{
+				"Server.cs": {
+					"public bool CanonicalJson { get; set; }",
+					"WriteMessage(output, response);",
+					"global::PulseRPC.CanonicalJson.Encode(document.RootElement)",
+				},
+				"Client.cs": {
+					"public bool CanonicalJson { get; set; }",
+					"var body = Serialize(request);",
+					"await PostAsync(Serialize(batch), options);",
+				},
+			},
+		},
+		{
+			plugin: NewJavaClientServer(),
+			args:   []string{"-base-package=com.example.server"},
+			files: map[string][]string{
+				"src/main/java/com/example/server/Server.java": {
+					"public void setCanonicalJson(boolean enabled) {",
+					"byte[] body = toJson(response).getBytes(",
+					"return CanonicalJson.encode(jsonParser.fromJson(jsonParser.toJson(value), Object.class));",
+				},
+				"src/main/java/com/bitmechanic/pulserpc/HTTPTransport.java": {
+					"public void setCanonicalJson(boolean enabled) {",
+					"String body = post(encode(request), options);",
+					"String body = post(encode(requests), options);",
+				},
+			},
+		},
+		{
+			plugin: NewRustClientServer(),
+			files: map[string][]string{
+				"src/server.rs": {
+					"pub fn set_canonical_json(&mut self, enabled: bool) {",
+					"self.dispatcher.set_canonical_json(enabled);",
+				},
+				"src/pulserpc/transport.rs": {
+					"pub fn set_canonical_json(&mut self, enabled: bool) {",
+					"canonical_json(&request).map_err(Error::Decode)?",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		dir := generateForTest(t, tt.plugin, canonicalTestIDL, tt.args...)
+		for file, wants := range tt.files {
+			data := readGenerated(t, dir, file)
+			for _, want := range wants {
+				if !strings.Contains(data, want) {
+					t.Errorf("%s: %s is missing %q", tt.plugin.Name(), file, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Capability negotiation: every server answers the built-in pulserpc-capabilities
+// method, like pulserpc-idl, with the optional protocol features it supports:
+//
+//	{"protocolVersion": "2.0", "batch": true, "notifications": true,
+//	 "compression": false, "msgpack": false, "streaming": false}
+//
+// compression is true when the IDL has [compress] methods and the server compresses
+// them, which the Rust server does not. No server speaks msgpack or streams responses
+// yet; the fields are there so clients can check for them once one does. HTTP
+// transports ask once, remember the answer and use it to decide whether a Batch is
+// sent as one batch request or as one call at a time, so clients need no setting for
+// servers that reject batches. A server that does not know pulserpc-capabilities, such
+// as one generated before it was added, is taken to support what JSON-RPC 2.0 does:
+// batches and notifications. A transport that cannot reach the server remembers
+// nothing and asks again next time.
+
+// capability is an optional protocol feature and whether a server supports it
+type capability struct {
+	Name      string
+	Supported bool
+}
+
+// serverCapabilities returns the features reported by a server generated from
+// interfaces, in the order they are reported. compresses is whether the server
+// compresses the responses of [compress] methods.
+func serverCapabilities(interfaces []*parser.Interface, compresses bool) []capability {
+	return []capability{
+		{"batch", true},
+		{"notifications", true},
+		{"compression", compresses && usesCompressedMethods(interfaces)},
+		{"msgpack", false},
+		{"streaming", false},
+	}
+}
+
+// writeServerCapabilitiesPy writes the SERVER_CAPABILITIES document of the Python server
+func writeServerCapabilitiesPy(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("# The result of the built-in pulserpc-capabilities method: the optional protocol\n")
+	sb.WriteString("# features this server supports\n")
+	sb.WriteString("SERVER_CAPABILITIES = {\n")
+	sb.WriteString("    'protocolVersion': '2.0',\n")
+	for _, c := range serverCapabilities(interfaces, true) {
+		supported := "False"
+		if c.Supported {
+			supported = "True"
+		}
+		fmt.Fprintf(sb, "    '%s': %s,\n", c.Name, supported)
+	}
+	sb.WriteString("}\n\n\n")
+}
+
+// writeCapabilitiesClientPy writes the Capabilities dataclass of the Python client and
+// the check Batch.send makes before sending a batch request
+func writeCapabilitiesClientPy(sb *strings.Builder) {
+	sb.WriteString(`@dataclass
+class Capabilities:
+    """The optional protocol features a server reports from the built-in
+    pulserpc-capabilities method. The defaults are those of a server without it: the
+    batches and notifications of JSON-RPC 2.0."""
+    protocol_version: str = '2.0'
+    batch: bool = True
+    notifications: bool = True
+    compression: bool = False
+    msgpack: bool = False
+    streaming: bool = False
+
+
+def _sends_batches(transport: Any) -> bool:
+    """Whether the calls of a Batch go to transport in one batch request: unless the
+    server reports it takes no batches. When the server cannot be asked the batch request
+    is sent anyway, so its error is the batch's."""
+    capabilities = getattr(transport, 'capabilities', None)
+    if capabilities is None:
+        return True
+    try:
+        return capabilities().batch
+    except TransportError:
+        return True
+
+
+`)
+}
+
+// writeCapabilitiesTransportPy writes HTTPTransport.capabilities
+func writeCapabilitiesTransportPy(sb *strings.Builder) {
+	sb.WriteString(`    def capabilities(self) -> Capabilities:
+        """Ask the server for its optional protocol features the first time, and return the
+        same answer afterwards. A server that does not know pulserpc-capabilities is taken to
+        have baseline JSON-RPC 2.0 ones. A TransportError, such as for a server that cannot be
+        reached, is raised and not remembered."""
+        with self._capabilities_lock:
+            if self._capabilities is None:
+                try:
+                    result = self.call('pulserpc-capabilities', []).get('result') or {}
+                except TransportError:
+                    raise
+                except RPCError:
+                    result = {}
+                self._capabilities = Capabilities(
+                    protocol_version=result.get('protocolVersion', '2.0'),
+                    batch=result.get('batch', True),
+                    notifications=result.get('notifications', True),
+                    compression=result.get('compression', False),
+                    msgpack=result.get('msgpack', False),
+                    streaming=result.get('streaming', False),
+                )
+            return self._capabilities
+
+`)
+}
+
+// writeServerCapabilitiesTs writes the SERVER_CAPABILITIES document of the TypeScript server
+func writeServerCapabilitiesTs(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("// The result of the built-in pulserpc-capabilities method: the optional protocol\n")
+	sb.WriteString("// features this server supports\n")
+	sb.WriteString("const SERVER_CAPABILITIES = {\n")
+	sb.WriteString("  protocolVersion: '2.0',\n")
+	for _, c := range serverCapabilities(interfaces, true) {
+		fmt.Fprintf(sb, "  %s: %t,\n", c.Name, c.Supported)
+	}
+	sb.WriteString("};\n\n")
+}
+
+// writeCapabilitiesClientTs writes the Capabilities interface of the TypeScript client
+// and the check Batch.send makes before sending a batch request
+func writeCapabilitiesClientTs(sb *strings.Builder, packagePrefix string) {
+	name := applyPackagePrefix("Capabilities", packagePrefix)
+	sb.WriteString("/** The optional protocol features a server reports from the built-in pulserpc-capabilities method */\n")
+	fmt.Fprintf(sb, "export interface %s {\n", name)
+	sb.WriteString("  protocolVersion: string;\n")
+	sb.WriteString("  batch: boolean;\n")
+	sb.WriteString("  notifications: boolean;\n")
+	sb.WriteString("  compression: boolean;\n")
+	sb.WriteString("  msgpack: boolean;\n")
+	sb.WriteString("  streaming: boolean;\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("// The capabilities of a server without pulserpc-capabilities: the batches and\n")
+	sb.WriteString("// notifications of JSON-RPC 2.0\n")
+	fmt.Fprintf(sb, "const BASELINE_CAPABILITIES: %s = {\n", name)
+	sb.WriteString("  protocolVersion: '2.0',\n")
+	sb.WriteString("  batch: true,\n")
+	sb.WriteString("  notifications: true,\n")
+	sb.WriteString("  compression: false,\n")
+	sb.WriteString("  msgpack: false,\n")
+	sb.WriteString("  streaming: false,\n")
+	sb.WriteString("};\n\n")
+	sb.WriteString(`// Whether the calls of a Batch go to transport in one batch request: unless the server
+// reports it takes no batches. When the server cannot be asked the batch request is
+// sent anyway, so its error is the batch's.
+async function sendsBatches(transport: any): Promise<boolean> {
+  if (typeof transport.capabilities !== 'function') {
+    return true;
+  }
+  try {
+    return (await transport.capabilities()).batch;
+  } catch {
+    return true;
+  }
+}
+
+`)
+}
+
+// writeCapabilitiesTransportTs writes HTTPTransport.capabilities
+func writeCapabilitiesTransportTs(sb *strings.Builder, packagePrefix string) {
+	name := applyPackagePrefix("Capabilities", packagePrefix)
+	errorClassName := applyPackagePrefix("TransportError", packagePrefix)
+	sb.WriteString("  /**\n")
+	sb.WriteString("   * Asks the server for its optional protocol features the first time, and returns the\n")
+	sb.WriteString("   * same answer afterwards. A server that does not know pulserpc-capabilities is taken to\n")
+	sb.WriteString("   * have baseline JSON-RPC 2.0 ones. Other errors, such as for a server that cannot be\n")
+	sb.WriteString("   * reached, reject and are not remembered.\n")
+	sb.WriteString("   */\n")
+	fmt.Fprintf(sb, "  capabilities(): Promise<%s> {\n", name)
+	sb.WriteString("    if (this.capabilitiesPromise === undefined) {\n")
+	sb.WriteString("      this.capabilitiesPromise = this.call('pulserpc-capabilities', []).then(\n")
+	sb.WriteString("        (response) => ({ ...BASELINE_CAPABILITIES, ...(response?.result ?? {}) }),\n")
+	sb.WriteString("        (err) => {\n")
+	fmt.Fprintf(sb, "          if (err instanceof RPCError && !(err instanceof %s)) {\n", errorClassName)
+	sb.WriteString("            return BASELINE_CAPABILITIES;\n")
+	sb.WriteString("          }\n")
+	sb.WriteString("          this.capabilitiesPromise = undefined;\n")
+	sb.WriteString("          throw err;\n")
+	sb.WriteString("        },\n")
+	sb.WriteString("      );\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    return this.capabilitiesPromise;\n")
+	sb.WriteString("  }\n\n")
+}
+
+// writeServerCapabilitiesCs writes the ServerCapabilities document of the C# server
+func writeServerCapabilitiesCs(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("    // The result of the built-in pulserpc-capabilities method: the optional protocol\n")
+	sb.WriteString("    // features this server supports\n")
+	sb.WriteString("    private static readonly Dictionary<string, object> ServerCapabilities = new Dictionary<string, object>\n")
+	sb.WriteString("    {\n")
+	sb.WriteString("        { \"protocolVersion\", \"2.0\" },\n")
+	for _, c := range serverCapabilities(interfaces, true) {
+		fmt.Fprintf(sb, "        { %q, %t },\n", c.Name, c.Supported)
+	}
+	sb.WriteString("    };\n\n")
+}
+
+// writeCapabilitiesClientCs writes the Capabilities record and ICapabilitiesTransport
+// of the C# client
+func writeCapabilitiesClientCs(sb *strings.Builder) {
+	sb.WriteString(`/// <summary>
+/// The optional protocol features a server reports from the built-in
+/// pulserpc-capabilities method
+/// </summary>
+public sealed record Capabilities(string ProtocolVersion, bool Batch, bool Notifications, bool Compression, bool Msgpack, bool Streaming)
+{
+    /// <summary>
+    /// Those of a server without pulserpc-capabilities: the batches and notifications of
+    /// JSON-RPC 2.0
+    /// </summary>
+    public static readonly Capabilities Baseline = new Capabilities("2.0", true, true, false, false, false);
+}
+
+/// <summary>
+/// Implemented by transports that can ask the server for its Capabilities, such as
+/// HttpTransport
+/// </summary>
+public interface ICapabilitiesTransport
+{
+    Task<Capabilities> CapabilitiesAsync();
+}
+
+`)
+}
+
+// writeCapabilitiesTransportCs writes HttpTransport.CapabilitiesAsync
+func writeCapabilitiesTransportCs(sb *strings.Builder) {
+	sb.WriteString(`    /// <summary>
+    /// Asks the server for its optional protocol features the first time, and returns the
+    /// same answer afterwards. A server that does not know pulserpc-capabilities is taken to
+    /// have baseline JSON-RPC 2.0 ones. Other errors, such as for a server that cannot be
+    /// reached, are thrown and not remembered.
+    /// </summary>
+    public async Task<Capabilities> CapabilitiesAsync()
+    {
+        if (_capabilities is Capabilities known)
+        {
+            return known;
+        }
+        Capabilities capabilities;
+        try
+        {
+            var response = await CallAsync("pulserpc-capabilities", Array.Empty<object>());
+            var result = response.TryGetValue("result", out var value) && value is JsonElement element && element.ValueKind == JsonValueKind.Object
+                ? element
+                : JsonSerializer.SerializeToElement(new Dictionary<string, object>());
+            var baseline = Capabilities.Baseline;
+            capabilities = new Capabilities(
+                result.TryGetProperty("protocolVersion", out var version) && version.ValueKind == JsonValueKind.String ? version.GetString()! : baseline.ProtocolVersion,
+                CapabilityFlag(result, "batch", baseline.Batch),
+                CapabilityFlag(result, "notifications", baseline.Notifications),
+                CapabilityFlag(result, "compression", baseline.Compression),
+                CapabilityFlag(result, "msgpack", baseline.Msgpack),
+                CapabilityFlag(result, "streaming", baseline.Streaming));
+        }
+        catch (RPCError)
+        {
+            capabilities = Capabilities.Baseline;
+        }
+        _capabilities = capabilities;
+        return capabilities;
+    }
+
+    private static bool CapabilityFlag(JsonElement result, string name, bool fallback)
+    {
+        return result.TryGetProperty(name, out var flag) && (flag.ValueKind == JsonValueKind.True || flag.ValueKind == JsonValueKind.False)
+            ? flag.GetBoolean()
+            : fallback;
+    }
+
+`)
+}
+
+// writeServerCapabilitiesJava writes the SERVER_CAPABILITIES document of the Java server
+func writeServerCapabilitiesJava(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("    // The result of the built-in pulserpc-capabilities method: the optional protocol\n")
+	sb.WriteString("    // features this server supports\n")
+	sb.WriteString("    private static final Map<String, Object> SERVER_CAPABILITIES = Map.of(\n")
+	sb.WriteString("        \"protocolVersion\", \"2.0\"")
+	for _, c := range serverCapabilities(interfaces, true) {
+		fmt.Fprintf(sb, ",\n        %q, %t", c.Name, c.Supported)
+	}
+	sb.WriteString("\n    );\n\n")
+}
@@ -0,0 +1,157 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestServerCapabilities(t *testing.T) {
+	tests := []struct {
+		name        string
+		idl         string
+		compresses  bool
+		compression bool
+	}{
+		{"compress methods", "interface Catalog {\n  list() []string [compress]\n}", true, true},
+		{"compress methods, server without compression", "interface Catalog {\n  list() []string [compress]\n}", false, false},
+		{"no compress methods", "interface Catalog {\n  count() int\n}", true, false},
+	}
+	for _, tt := range tests {
+		idl, err := parser.ParseIDL("shop.pulse", tt.idl)
+		if err != nil {
+			t.Fatalf("%s: ParseIDL failed: %v", tt.name, err)
+		}
+		want := []capability{
+			{"batch", true},
+			{"notifications", true},
+			{"compression", tt.compression},
+			{"msgpack", false},
+			{"streaming", false},
+		}
+		got := serverCapabilities(idl.Interfaces, tt.compresses)
+		if len(got) != len(want) {
+			t.Fatalf("%s: serverCapabilities = %v, want %v", tt.name, got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s: serverCapabilities[%d] = %v, want %v", tt.name, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestCapabilitiesGenerated(t *testing.T) {
+	idl, err := parser.ParseIDL("shop.pulse", "interface Catalog {\n  list() []string [compress]\n}")
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	plugins := []struct {
+		plugin Plugin
+		file   string
+		want   []string
+	}{
+		{
+			plugin: NewGoClientServer(),
+			file:   "server.go",
+			want: []string{
+				"\tif method == \"pulserpc-capabilities\" {\n",
+				"var serverCapabilities = map[string]interface{}{\n",
+				"\t\"compression\":     true,\n",
+			},
+		},
+		{
+			plugin: NewGoClientServer(),
+			file:   "client.go",
+			want: []string{
+				"func (t *HTTPTransport) Capabilities() (Capabilities, error) {",
+				"ok && len(calls) > 0 && sendsBatches(b.transport) {",
+			},
+		},
+		{
+			plugin: NewPythonClientServer(),
+			file:   "server.py",
+			want: []string{
+				"if method == \"pulserpc-capabilities\":",
+				"SERVER_CAPABILITIES = {\n    'protocolVersion': '2.0',\n    'batch': True,\n",
+			},
+		},
+		{
+			plugin: NewPythonClientServer(),
+			file:   "client.py",
+			want: []string{
+				"def capabilities(self) -> Capabilities:",
+				"not _sends_batches(self.transport):",
+			},
+		},
+		{
+			plugin: NewTSClientServer(),
+			file:   "server.ts",
+			want: []string{
+				"if (method === 'pulserpc-capabilities') {",
+				"const SERVER_CAPABILITIES = {\n  protocolVersion: '2.0',\n  batch: true,\n",
+			},
+		},
+		{
+			plugin: NewTSClientServer(),
+			file:   "client.ts",
+			want: []string{
+				"capabilities(): Promise<Capabilities> {",
+				"!(await sendsBatches(transport))",
+			},
+		},
+		{
+			plugin: NewCSharpClientServer(),
+			file:   "Server.cs",
+			want: []string{
+				"if (method == \"pulserpc-capabilities\")",
+				"        { \"compression\", true },\n",
+			},
+		},
+		{
+			plugin: NewCSharpClientServer(),
+			file:   "Client.cs",
+			want: []string{
+				"public class HttpTransport : ITransport, IBatchTransport, ICapabilitiesTransport",
+				"!await SendsBatchesAsync()",
+			},
+		},
+		{
+			plugin: NewJavaClientServer(),
+			file:   "src/main/java/com/example/Server.java",
+			want: []string{
+				"if (\"pulserpc-capabilities\".equals(method)) {",
+				"private static final Map<String, Object> SERVER_CAPABILITIES = Map.of(",
+			},
+		},
+	}
+	for _, p := range plugins {
+		tmpDir := t.TempDir()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		p.plugin.RegisterFlags(fs)
+		for name, value := range map[string]string{"dir": tmpDir, "base-package": "com.example"} {
+			if fs.Lookup(name) != nil {
+				if err := fs.Set(name, value); err != nil {
+					t.Fatalf("failed to set %s flag: %v", name, err)
+				}
+			}
+		}
+		if err := p.plugin.Generate(idl, fs); err != nil {
+			t.Fatalf("%s: Generate failed: %v", p.plugin.Name(), err)
+		}
+		content, err := os.ReadFile(filepath.Join(tmpDir, p.file))
+		if err != nil {
+			t.Fatalf("%s: expected %s: %v", p.plugin.Name(), p.file, err)
+		}
+		for _, want := range p.want {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s: %s does not contain %q", p.plugin.Name(), p.file, want)
+			}
+		}
+	}
+}
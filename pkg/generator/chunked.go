@@ -0,0 +1,34 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Chunked results: the handler of a method marked [chunked], which returns an
+// array, produces the elements one at a time instead of building the array. Go
+// handlers take an emit func after their params and return only an error; Python
+// handlers may return any iterable, such as a generator; TypeScript handlers any
+// Iterable. No transport streams responses yet, so the servers collect the
+// elements and send the array in one response as usual, and clients see no
+// difference. Emitting after the call's deadline has passed fails in Go, which
+// stops the handler early. C# and Java handlers return the whole array.
+
+// usesChunkedMethods reports whether any method is [chunked]
+func usesChunkedMethods(interfaces []*parser.Interface) bool {
+	for _, iface := range interfaces {
+		for _, method := range iface.Methods {
+			if method.IsChunked() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// goChunkedEmitType returns the type of the emit func the Go handler of a
+// [chunked] method takes
+func goChunkedEmitType(method *parser.Method, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) string {
+	return fmt.Sprintf("func(%s) error", mapTypeToGoType(method.ReturnType.Array, structMap, enumMap, false))
+}
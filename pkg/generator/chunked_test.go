@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+const chunkedTestIDL = `namespace feed
+
+struct Item {
+    id  int
+}
+
+interface Feed {
+    items(count int) []Item [chunked]
+    names() []string [chunked]
+}
+`
+
+const chunkedGoMain = `package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	feed "example.com/feed"
+)
+
+type handler struct{}
+
+func (handler) Items(ctx context.Context, count int, emit func(feed.Item) error) error {
+	for i := 0; i < count; i++ {
+		if err := emit(feed.Item{Id: i}); err != nil {
+			return err
+		}
+	}
+	if count > 2 {
+		return errors.New("too many")
+	}
+	return nil
+}
+
+func (handler) Names(ctx context.Context, emit func(string) error) error {
+	return nil
+}
+
+func main() {
+	server := feed.NewPulseRPCServer("localhost", 0)
+	server.RegisterFeed(handler{})
+	for _, call := range []struct {
+		method string
+		params []interface{}
+	}{
+		{"Feed.items", []interface{}{2.0}},
+		{"Feed.names", []interface{}{}},
+		{"Feed.items", []interface{}{3.0}},
+	} {
+		response := server.HandleRequest(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": call.method, "params": call.params})
+		if response["error"] != nil {
+			fmt.Println("error")
+			continue
+		}
+		data, err := json.Marshal(response["result"])
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(data))
+	}
+}
+`
+
+// TestChunkedGoServer builds a program that serves a [chunked] method from a Go
+// handler that emits its elements
+func TestChunkedGoServer(t *testing.T) {
+	dir := generateForTest(t, NewGoClientServer(), chunkedTestIDL)
+	if out, want := runGoCheck(t, dir, "example.com/feed", chunkedGoMain), "[{\"id\":0},{\"id\":1}]\n[]\nerror"; out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+const chunkedPythonCheck = `import json
+from server import PulseRPCServer
+
+class Feed:
+    def items(self, count):
+        for i in range(count):
+            yield {'id': i}
+        if count > 2:
+            raise ValueError('too many')
+
+    def names(self):
+        return iter(())
+
+server = PulseRPCServer()
+server.register('Feed', Feed())
+for method, params in [('Feed.items', [2]), ('Feed.names', []), ('Feed.items', [3])]:
+    response = server.handle_request({'jsonrpc': '2.0', 'id': 1, 'method': method, 'params': params})
+    print('error' if 'error' in response else json.dumps(response['result'], separators=(',', ':')))
+`
+
+// TestChunkedPythonServer serves a [chunked] method from a Python generator
+func TestChunkedPythonServer(t *testing.T) {
+	dir := generateForTest(t, NewPythonClientServer(), chunkedTestIDL)
+	if out, want := runPythonCheck(t, dir, chunkedPythonCheck), "[{\"id\":0},{\"id\":1}]\n[]\nerror"; out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestChunkedSignatures(t *testing.T) {
+	tests := []struct {
+		plugin Plugin
+		file   string
+		want   string
+	}{
+		{NewGoClientServer(), "server.go", "Items(ctx context.Context, count int, emit func(Item) error) error\n"},
+		{NewTSClientServer(), "server.ts", "if (methodDef.chunked) {\n        // [chunked] handlers may return any Iterable, such as a generator\n        result = Array.from(result);"},
+		{NewTSClientServer(), "methods.ts", "chunked: true,"},
+	}
+	for _, tt := range tests {
+		if data := readGenerated(t, generateForTest(t, tt.plugin, chunkedTestIDL), tt.file); !strings.Contains(data, tt.want) {
+			t.Errorf("%s: %s does not contain %q", tt.plugin.Name(), tt.file, tt.want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/naming"
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Generated structs can be deep-copied, so callers can change a copy of an RPC
+// result without aliasing the original. Go structs get a Clone method, C# classes
+// a Clone method backed by a protected copy constructor, and Java classes a copy
+// constructor and a copy method. Python and TypeScript structs are plain dicts and
+// objects, so their runtimes provide clone_struct and cloneStruct instead.
+
+// isStructType reports whether a user-defined type name refers to a struct
+// rather than an enum
+func isStructType(name string, structMap map[string]*parser.Struct) bool {
+	if _, ok := structMap[name]; ok {
+		return true
+	}
+	_, ok := structMap[GetBaseName(name)]
+	return ok
+}
+
+// needsDeepCopy reports whether copying a value of type t by assignment would
+// share state with the original
+func needsDeepCopy(t *parser.Type, optional bool, structMap map[string]*parser.Struct) bool {
+	if t.IsArray() || t.IsMap() {
+		return true
+	}
+	if t.IsUserDefined() && isStructType(t.UserDefined, structMap) {
+		return true
+	}
+	return optional
+}
+
+// writeCloneMethodGo writes the Clone method of a Go struct. The shallow copy
+// covers scalar fields; every field holding a pointer, slice, map or nested
+// struct is then copied again. With presence, optional fields are Optional values
+// whose Value is copied like a required field.
+func writeCloneMethodGo(sb *strings.Builder, s *parser.Struct, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, qualify func(string) string, presence bool) {
+	structName := GetBaseName(s.Name)
+	sb.WriteString("// Clone returns a deep copy of v that shares no pointers, slices or maps with it\n")
+	fmt.Fprintf(sb, "func (v *%s) Clone() *%s {\n", structName, structName)
+	sb.WriteString("\tif v == nil {\n\t\treturn nil\n\t}\n")
+	sb.WriteString("\tc := *v\n")
+	if s.Extends != "" {
+		parentName := getGoStructOrEnumTypeName(s.Extends, structMap, enumMap)
+		fmt.Fprintf(sb, "\tc.%s = *v.%s.Clone()\n", parentName, parentName)
+	}
+	for _, field := range s.Fields {
+		name := goFieldName(s, field)
+		if field.Optional && presence {
+			if needsDeepCopy(field.Type, false, structMap) {
+				writeCloneGo(sb, "\t", "c."+name+".Value", "v."+name+".Value", field.Type, false, 0, structMap, enumMap, qualify)
+			}
+			continue
+		}
+		if !needsDeepCopy(field.Type, field.Optional, structMap) {
+			continue
+		}
+		writeCloneGo(sb, "\t", "c."+name, "v."+name, field.Type, field.Optional, 0, structMap, enumMap, qualify)
+	}
+	sb.WriteString("\treturn &c\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeCloneGo writes statements assigning a deep copy of src to dst. src must be
+// addressable so that nested structs can be cloned through their pointer receiver.
+func writeCloneGo(sb *strings.Builder, indent, dst, src string, t *parser.Type, optional bool, depth int, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, qualify func(string) string) {
+	switch {
+	case optional && (t.IsArray() || t.IsMap()):
+		// Optional arrays and maps are pointers to them
+		s, c := fmt.Sprintf("s%d", depth), fmt.Sprintf("c%d", depth)
+		fmt.Fprintf(sb, "%sif %s != nil {\n", indent, src)
+		fmt.Fprintf(sb, "%s\t%s := *%s\n", indent, s, src)
+		fmt.Fprintf(sb, "%s\tvar %s %s\n", indent, c, mapTypeToQualifiedGoType(t, structMap, enumMap, false, qualify))
+		writeCloneGo(sb, indent+"\t", c, s, t, false, depth+1, structMap, enumMap, qualify)
+		fmt.Fprintf(sb, "%s\t%s = &%s\n", indent, dst, c)
+		fmt.Fprintf(sb, "%s}\n", indent)
+	case t.IsUserDefined() && isStructType(t.UserDefined, structMap):
+		if optional {
+			fmt.Fprintf(sb, "%s%s = %s.Clone()\n", indent, dst, src)
+		} else {
+			fmt.Fprintf(sb, "%s%s = *%s.Clone()\n", indent, dst, src)
+		}
+	case t.IsArray():
+		goType := mapTypeToQualifiedGoType(t, structMap, enumMap, false, qualify)
+		fmt.Fprintf(sb, "%sif %s != nil {\n", indent, src)
+		fmt.Fprintf(sb, "%s\t%s = make(%s, len(%s))\n", indent, dst, goType, src)
+		if needsDeepCopy(t.Array, false, structMap) {
+			i := fmt.Sprintf("i%d", depth)
+			fmt.Fprintf(sb, "%s\tfor %s := range %s {\n", indent, i, src)
+			writeCloneGo(sb, indent+"\t\t", dst+"["+i+"]", src+"["+i+"]", t.Array, false, depth+1, structMap, enumMap, qualify)
+			fmt.Fprintf(sb, "%s\t}\n", indent)
+		} else {
+			fmt.Fprintf(sb, "%s\tcopy(%s, %s)\n", indent, dst, src)
+		}
+		fmt.Fprintf(sb, "%s}\n", indent)
+	case t.IsMap():
+		goType := mapTypeToQualifiedGoType(t, structMap, enumMap, false, qualify)
+		k, e := fmt.Sprintf("k%d", depth), fmt.Sprintf("e%d", depth)
+		fmt.Fprintf(sb, "%sif %s != nil {\n", indent, src)
+		fmt.Fprintf(sb, "%s\t%s = make(%s, len(%s))\n", indent, dst, goType, src)
+		fmt.Fprintf(sb, "%s\tfor %s, %s := range %s {\n", indent, k, e, src)
+		if t.MapValue.IsArray() || t.MapValue.IsMap() {
+			// Clone into a variable so that nil values keep their key
+			c := fmt.Sprintf("c%d", depth)
+			fmt.Fprintf(sb, "%s\t\tvar %s %s\n", indent, c, mapTypeToQualifiedGoType(t.MapValue, structMap, enumMap, false, qualify))
+			writeCloneGo(sb, indent+"\t\t", c, e, t.MapValue, false, depth+1, structMap, enumMap, qualify)
+			fmt.Fprintf(sb, "%s\t\t%s[%s] = %s\n", indent, dst, k, c)
+		} else if needsDeepCopy(t.MapValue, false, structMap) {
+			writeCloneGo(sb, indent+"\t\t", dst+"["+k+"]", e, t.MapValue, false, depth+1, structMap, enumMap, qualify)
+		} else {
+			fmt.Fprintf(sb, "%s\t\t%s[%s] = %s\n", indent, dst, k, e)
+		}
+		fmt.Fprintf(sb, "%s\t}\n", indent)
+		fmt.Fprintf(sb, "%s}\n", indent)
+	case optional:
+		fmt.Fprintf(sb, "%sif %s != nil {\n", indent, src)
+		fmt.Fprintf(sb, "%s\tp := *%s\n", indent, src)
+		fmt.Fprintf(sb, "%s\t%s = &p\n", indent, dst)
+		fmt.Fprintf(sb, "%s}\n", indent)
+	default:
+		fmt.Fprintf(sb, "%s%s = %s\n", indent, dst, src)
+	}
+}
+
+// writeCloneMembersCs writes the copy constructor and Clone method of a C#
+// class. Clone is virtual in root classes and overridden with a covariant return
+// type in subclasses, so it always returns the runtime type's copy. With presence,
+// optional properties are Optional values and only a held value is copied.
+func writeCloneMembersCs(sb *strings.Builder, s *parser.Struct, structMap map[string]*parser.Struct, prefix string, presence bool) {
+	structName := GetBaseName(s.Name)
+	fmt.Fprintf(sb, "%s    protected %s(%s other)", prefix, structName, structName)
+	if s.Extends != "" {
+		sb.WriteString(" : base(other)")
+	}
+	sb.WriteString("\n" + prefix + "    {\n")
+	for _, field := range s.Fields {
+		propName := naming.SnakeToPascal(field.Name)
+		if field.Optional && presence && needsDeepCopy(field.Type, false, structMap) {
+			fmt.Fprintf(sb, "%s        %s = other.%s.HasValue ? %s : other.%s;\n", prefix, propName, propName, cloneExprCs("other."+propName+".Value", field.Type, false, 0, structMap), propName)
+			continue
+		}
+		fmt.Fprintf(sb, "%s        %s = %s;\n", prefix, propName, cloneExprCs("other."+propName, field.Type, field.Optional, 0, structMap))
+	}
+	sb.WriteString(prefix + "    }\n\n")
+
+	fmt.Fprintf(sb, "%s    // Returns a deep copy that shares no lists, dictionaries or structs with this value\n", prefix)
+	if s.Extends != "" {
+		fmt.Fprintf(sb, "%s    public override %s Clone() => new %s(this);\n", prefix, structName, structName)
+	} else {
+		fmt.Fprintf(sb, "%s    public virtual %s Clone() => new %s(this);\n", prefix, structName, structName)
+	}
+}
+
+// cloneExprCs returns a C# expression evaluating to a deep copy of src. Null
+// lists, dictionaries and structs stay null.
+func cloneExprCs(src string, t *parser.Type, optional bool, depth int, structMap map[string]*parser.Struct) string {
+	switch {
+	case t.IsUserDefined() && isStructType(t.UserDefined, structMap):
+		if optional {
+			return src + "?.Clone()"
+		}
+		return src + "?.Clone()!"
+	case t.IsArray():
+		if !needsDeepCopy(t.Array, false, structMap) {
+			return src + "?.ToList()!"
+		}
+		e := fmt.Sprintf("e%d", depth)
+		return fmt.Sprintf("%s?.Select(%s => %s).ToList()!", src, e, cloneExprCs(e, t.Array, false, depth+1, structMap))
+	case t.IsMap():
+		kv := fmt.Sprintf("kv%d", depth)
+		return fmt.Sprintf("%s?.ToDictionary(%s => %s.Key, %s => %s)!", src, kv, kv, kv, cloneExprCs(kv+".Value", t.MapValue, false, depth+1, structMap))
+	}
+	return src
+}
+
+// writeCopyMembersJava writes the no-arg constructor, unless the class is
+// [immutable], the copy constructor and the copy method of a Java struct class.
+// Subclasses override copy with a covariant return type.
+func writeCopyMembersJava(sb *strings.Builder, s *parser.Struct, structMap map[string]*parser.Struct, basePackage, packageName string) {
+	className := GetBaseName(s.Name)
+	if !s.IsImmutable() {
+		// Immutable classes have their own constructors
+		fmt.Fprintf(sb, "    public %s() {\n    }\n\n", className)
+	}
+
+	fmt.Fprintf(sb, "    public %s(%s other) {\n", className, className)
+	if s.Extends != "" {
+		sb.WriteString("        super(other);\n")
+	}
+	for _, field := range s.Fields {
+		fieldName := naming.LowerFirst(field.Name)
+		fmt.Fprintf(sb, "        this.%s = %s;\n", fieldName, cloneExprJava("other."+fieldName, field.Type, 0, structMap, basePackage, packageName))
+	}
+	sb.WriteString("    }\n\n")
+
+	sb.WriteString("    // Returns a deep copy that shares no lists, maps or structs with this value\n")
+	if s.Extends != "" {
+		sb.WriteString("    @Override\n")
+	}
+	fmt.Fprintf(sb, "    public %s copy() {\n", className)
+	fmt.Fprintf(sb, "        return new %s(this);\n", className)
+	sb.WriteString("    }\n\n")
+}
+
+// cloneExprJava returns a Java expression evaluating to a deep copy of src. Null
+// lists, maps and structs stay null. Generic types are spelled out so the
+// expression type-checks without relying on inference from the assignment.
+func cloneExprJava(src string, t *parser.Type, depth int, structMap map[string]*parser.Struct, basePackage, packageName string) string {
+	switch {
+	case t.IsUserDefined() && isStructType(t.UserDefined, structMap):
+		return fmt.Sprintf("%s == null ? null : %s.copy()", src, src)
+	case t.IsArray():
+		elemType := getJavaTypeWithPackageForGeneric(t.Array, basePackage, packageName)
+		if !needsDeepCopy(t.Array, false, structMap) {
+			return fmt.Sprintf("%s == null ? null : new java.util.ArrayList<%s>(%s)", src, elemType, src)
+		}
+		e := fmt.Sprintf("e%d", depth)
+		return fmt.Sprintf("%s == null ? null : %s.stream().map(%s -> %s).collect(java.util.stream.Collectors.toCollection(java.util.ArrayList<%s>::new))",
+			src, src, e, cloneExprJava(e, t.Array, depth+1, structMap, basePackage, packageName), elemType)
+	case t.IsMap():
+		valueType := getJavaTypeWithPackageForGeneric(t.MapValue, basePackage, packageName)
+		if !needsDeepCopy(t.MapValue, false, structMap) {
+			return fmt.Sprintf("%s == null ? null : new java.util.LinkedHashMap<String, %s>(%s)", src, valueType, src)
+		}
+		m, kv := fmt.Sprintf("m%d", depth), fmt.Sprintf("kv%d", depth)
+		return fmt.Sprintf("%s == null ? null : %s.entrySet().stream().collect(java.util.LinkedHashMap<String, %s>::new, (%s, %s) -> %s.put(%s.getKey(), %s), java.util.Map::putAll)",
+			src, src, valueType, m, kv, m, kv, cloneExprJava(kv+".getValue()", t.MapValue, depth+1, structMap, basePackage, packageName))
+	}
+	return src
+}
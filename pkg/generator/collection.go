@@ -0,0 +1,311 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Collection generates a Postman collection or an Insomnia export with one request
+// per interface method, pre-filled with IDL-valid params. The base URL and the auth
+// header value are environment variables so one collection works against any server.
+type Collection struct {
+}
+
+// NewCollection creates a new Collection plugin instance
+func NewCollection() *Collection {
+	return &Collection{}
+}
+
+// Name returns the plugin identifier
+func (p *Collection) Name() string {
+	return "collection"
+}
+
+// RegisterFlags registers CLI flags for this plugin
+func (p *Collection) RegisterFlags(fs *flag.FlagSet) {
+	fs.String("collection-format", "postman", "Collection format to generate: 'postman' or 'insomnia'")
+	fs.String("collection-url", "http://localhost:8080", "Default value of the baseUrl environment variable")
+	fs.String("collection-auth-header", "Authorization", "Name of the header set to the authToken environment variable")
+}
+
+// Environment variables referenced by the generated requests
+const (
+	collectionURLVar   = "baseUrl"
+	collectionTokenVar = "authToken"
+)
+
+// collectionFolder is an interface and the sample requests of its methods
+type collectionFolder struct {
+	Name        string
+	Description string
+	Requests    []collectionRequest
+}
+
+// collectionRequest is the sample request of one method
+type collectionRequest struct {
+	Interface string
+	Method    string
+	// Body is the JSON-RPC request, indented for display in the request editor
+	Body string
+}
+
+// Generate writes postman_collection.json and postman_environment.json, or
+// insomnia.json
+func (p *Collection) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
+	outputDir := ""
+	if dirFlag := fs.Lookup("dir"); dirFlag != nil {
+		outputDir = dirFlag.Value.String()
+	}
+	format := fs.Lookup("collection-format").Value.String()
+	if format != "postman" && format != "insomnia" {
+		return fmt.Errorf("invalid collection-format value: %s (must be 'postman' or 'insomnia')", format)
+	}
+	url := fs.Lookup("collection-url").Value.String()
+	authHeader := fs.Lookup("collection-auth-header").Value.String()
+
+	name := idl.RootNamespace
+	if name == "" {
+		name = "PulseRPC"
+	}
+	var folders []collectionFolder
+	for i, iface := range buildHarnessInterfaces(idl, func(s string) string { return s }) {
+		folder := collectionFolder{Name: iface.Name, Description: idl.Interfaces[i].Comment}
+		for _, call := range iface.Calls {
+			var body bytes.Buffer
+			if err := json.Indent(&body, []byte(call.JSON), "", "  "); err != nil {
+				return fmt.Errorf("failed to format request for %s.%s: %w", iface.Name, call.Method, err)
+			}
+			folder.Requests = append(folder.Requests, collectionRequest{
+				Interface: iface.Name,
+				Method:    call.Method,
+				Body:      body.String(),
+			})
+		}
+		folders = append(folders, folder)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if format == "insomnia" {
+		return writeCollectionJSON(filepath.Join(outputDir, "insomnia.json"), insomniaExport(name, url, authHeader, folders))
+	}
+	if err := writeCollectionJSON(filepath.Join(outputDir, "postman_collection.json"), postmanCollection(name, url, authHeader, folders)); err != nil {
+		return err
+	}
+	return writeCollectionJSON(filepath.Join(outputDir, "postman_environment.json"), postmanEnvironment(name, url))
+}
+
+// writeCollectionJSON writes v as indented JSON. HTML escaping is off so the
+// {{variable}} references and request bodies read as typed.
+func writeCollectionJSON(path string, v interface{}) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+	if err := writeGeneratedFile(path, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// Postman collection format v2.1:
+// https://schema.getpostman.com/json/collection/v2.1.0/collection.json
+
+type postmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+type postmanVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Type  string `json:"type,omitempty"`
+}
+
+type postmanItem struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Item        []postmanItem   `json:"item,omitempty"`
+	Request     *postmanRequest `json:"request,omitempty"`
+}
+
+type postmanRequest struct {
+	Method string            `json:"method"`
+	Header []postmanVariable `json:"header"`
+	Body   postmanBody       `json:"body"`
+	URL    postmanURL        `json:"url"`
+}
+
+type postmanBody struct {
+	Mode    string                       `json:"mode"`
+	Raw     string                       `json:"raw"`
+	Options map[string]map[string]string `json:"options"`
+}
+
+type postmanURL struct {
+	Raw  string   `json:"raw"`
+	Host []string `json:"host"`
+}
+
+type postmanCollectionFile struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanVariable `json:"variable"`
+}
+
+type postmanEnvironmentFile struct {
+	Name   string                  `json:"name"`
+	Values []postmanEnvironmentVar `json:"values"`
+}
+
+type postmanEnvironmentVar struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
+
+// postmanCollection returns a collection with a folder per interface. The collection
+// variables hold defaults so requests work before an environment is selected.
+func postmanCollection(name, url, authHeader string, folders []collectionFolder) postmanCollectionFile {
+	collection := postmanCollectionFile{
+		Info: postmanInfo{
+			Name:   name,
+			Schema: "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
+		},
+		Variable: []postmanVariable{
+			{Key: collectionURLVar, Value: url},
+			{Key: collectionTokenVar, Value: ""},
+		},
+	}
+	for _, folder := range folders {
+		item := postmanItem{Name: folder.Name, Description: folder.Description}
+		for _, req := range folder.Requests {
+			item.Item = append(item.Item, postmanItem{
+				Name: req.Method,
+				Request: &postmanRequest{
+					Method: "POST",
+					Header: []postmanVariable{
+						{Key: "Content-Type", Value: "application/json"},
+						{Key: authHeader, Value: "{{" + collectionTokenVar + "}}"},
+					},
+					Body: postmanBody{
+						Mode:    "raw",
+						Raw:     req.Body,
+						Options: map[string]map[string]string{"raw": {"language": "json"}},
+					},
+					URL: postmanURL{
+						Raw:  "{{" + collectionURLVar + "}}",
+						Host: []string{"{{" + collectionURLVar + "}}"},
+					},
+				},
+			})
+		}
+		collection.Item = append(collection.Item, item)
+	}
+	return collection
+}
+
+// postmanEnvironment returns an environment defining the variables used by the
+// collection
+func postmanEnvironment(name, url string) postmanEnvironmentFile {
+	return postmanEnvironmentFile{
+		Name: name,
+		Values: []postmanEnvironmentVar{
+			{Key: collectionURLVar, Value: url, Type: "default", Enabled: true},
+			{Key: collectionTokenVar, Value: "", Type: "secret", Enabled: true},
+		},
+	}
+}
+
+// Insomnia export format v4, a flat list of resources linked by parentId
+
+type insomniaExportFile struct {
+	Type         string             `json:"_type"`
+	ExportFormat int                `json:"__export_format"`
+	ExportSource string             `json:"__export_source"`
+	Resources    []insomniaResource `json:"resources"`
+}
+
+type insomniaResource struct {
+	ID          string            `json:"_id"`
+	Type        string            `json:"_type"`
+	ParentID    *string           `json:"parentId"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Scope       string            `json:"scope,omitempty"`
+	Data        map[string]string `json:"data,omitempty"`
+	Method      string            `json:"method,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Body        *insomniaBody     `json:"body,omitempty"`
+	Headers     []insomniaHeader  `json:"headers,omitempty"`
+}
+
+type insomniaBody struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type insomniaHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// insomniaExport returns a workspace with a base environment and a request group
+// per interface. Resource ids are derived from names so regenerating the export and
+// importing it again updates the existing requests.
+func insomniaExport(name, url, authHeader string, folders []collectionFolder) insomniaExportFile {
+	workspaceID := "wrk_" + nonScenarioChars.ReplaceAllString(name, "_")
+	export := insomniaExportFile{
+		Type:         "export",
+		ExportFormat: 4,
+		ExportSource: "pulserpc",
+		Resources: []insomniaResource{
+			{ID: workspaceID, Type: "workspace", Name: name, Scope: "collection"},
+			{
+				ID:       "env_" + nonScenarioChars.ReplaceAllString(name, "_"),
+				Type:     "environment",
+				ParentID: &workspaceID,
+				Name:     "Base Environment",
+				Data:     map[string]string{collectionURLVar: url, collectionTokenVar: ""},
+			},
+		},
+	}
+	for _, folder := range folders {
+		folderID := "fld_" + nonScenarioChars.ReplaceAllString(folder.Name, "_")
+		export.Resources = append(export.Resources, insomniaResource{
+			ID:          folderID,
+			Type:        "request_group",
+			ParentID:    &workspaceID,
+			Name:        folder.Name,
+			Description: folder.Description,
+		})
+		for _, req := range folder.Requests {
+			export.Resources = append(export.Resources, insomniaResource{
+				ID:       "req_" + nonScenarioChars.ReplaceAllString(req.Interface+"_"+req.Method, "_"),
+				Type:     "request",
+				ParentID: &folderID,
+				Name:     req.Method,
+				Method:   "POST",
+				URL:      "{{ _." + collectionURLVar + " }}",
+				Body:     &insomniaBody{MimeType: "application/json", Text: req.Body},
+				Headers: []insomniaHeader{
+					{Name: "Content-Type", Value: "application/json"},
+					{Name: authHeader, Value: "{{ _." + collectionTokenVar + " }}"},
+				},
+			})
+		}
+	}
+	return export
+}
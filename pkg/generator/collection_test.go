@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func runCollection(t *testing.T, format string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	p := NewCollection()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("dir", tmpDir, "output dir")
+	p.RegisterFlags(fs)
+	for name, value := range map[string]string{
+		"collection-format":      format,
+		"collection-auth-header": "X-Api-Key",
+	} {
+		if err := fs.Set(name, value); err != nil {
+			t.Fatalf("failed to set %s flag: %v", name, err)
+		}
+	}
+	if err := p.Generate(loadTestIDL, fs); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	return tmpDir
+}
+
+func TestCollectionInsomnia(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(runCollection(t, "insomnia"), "insomnia.json"))
+	if err != nil {
+		t.Fatalf("failed to read insomnia.json: %v", err)
+	}
+	var export insomniaExportFile
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatalf("invalid export: %v", err)
+	}
+
+	ids := map[string]string{}
+	var requests []string
+	for _, res := range export.Resources {
+		ids[res.ID] = res.Type
+		if res.Type != "request" {
+			continue
+		}
+		if res.ParentID == nil || ids[*res.ParentID] != "request_group" {
+			t.Errorf("request %s is not in a request group", res.Name)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal([]byte(res.Body.Text), &body); err != nil {
+			t.Errorf("request %s has an invalid body: %v", res.Name, err)
+		}
+		requests = append(requests, body["method"].(string))
+		if res.Headers[1].Name != "X-Api-Key" || res.Headers[1].Value != "{{ _.authToken }}" {
+			t.Errorf("request %s has auth header %+v", res.Name, res.Headers[1])
+		}
+	}
+	if len(requests) != 2 || requests[0] != "Catalog.find_product" || requests[1] != "Catalog.ping" {
+		t.Errorf("requests = %v, want one per method", requests)
+	}
+}
+
+func TestCollectionInvalidFormat(t *testing.T) {
+	p := NewCollection()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("dir", t.TempDir(), "output dir")
+	p.RegisterFlags(fs)
+	if err := fs.Set("collection-format", "bruno"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Generate(loadTestIDL, fs); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,362 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Server composition: services generated from different IDLs can be served on
+// one port. A generated server composes another with Compose/compose: calls to
+// its endpoint for an interface it doesn't serve itself go to the composed
+// server in-process, and pulserpc-idl answers with the idl.json of both merged
+// (MergeIDLDocuments, merge_idl_documents, IdlMerger.merge). Mount/mount serves
+// another server's whole HTTP handling under a path prefix instead, for
+// services that should keep separate endpoints and IDL documents. The Go,
+// Python, Java and C# servers support both; the runtimes hold the routing and
+// the IDL merge, and the generated server only says which methods are its own.
+
+// writeComposeServerGo writes the Compose, Mount, ServeHTTP, Handles and
+// IDLDocument methods of the Go server
+func writeComposeServerGo(sb *strings.Builder, interfaces []*parser.Interface, idlDoc idlJSONDocument) {
+	if idlDoc.Embedded() {
+		sb.WriteString("// idlJSON is the IDL's JSON document\n")
+		sb.WriteString("var idlJSON = []byte(" + idlDoc.StringLiteral() + ")\n\n")
+	} else {
+		sb.WriteString("//go:embed " + idlDoc.Path + "\n")
+		sb.WriteString("var idlJSON []byte\n\n")
+	}
+
+	sb.WriteString("// Compose serves the interfaces of server from this server's endpoint, so services\n")
+	sb.WriteString("// generated from different IDLs share one port. Calls for an interface registered\n")
+	sb.WriteString("// here stay here, and pulserpc-idl answers with the IDLs of both merged.\n")
+	sb.WriteString("func (s *PulseRPCServer) Compose(server ComposedServer) {\n")
+	sb.WriteString("	s.composition.Add(server)\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// Mount serves handler, such as the server of another IDL, under path. Requests\n")
+	sb.WriteString("// to path and below go to handler with path removed from their URL path.\n")
+	sb.WriteString("func (s *PulseRPCServer) Mount(path string, handler http.Handler) {\n")
+	sb.WriteString("	s.composition.Mount(path, handler)\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// ServeHTTP serves one HTTP request, making the server an http.Handler. Requests under\n")
+	sb.WriteString("// the path of a mounted handler go to it. Cloud Functions use it as the function entry point:\n")
+	sb.WriteString("//\n")
+	sb.WriteString("//	functions.HTTP(\"PulseRPC\", server.ServeHTTP)\n")
+	sb.WriteString("func (s *PulseRPCServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {\n")
+	sb.WriteString("	if handler, routed, ok := s.composition.Route(r); ok {\n")
+	sb.WriteString("		handler.ServeHTTP(w, routed)\n")
+	sb.WriteString("		return\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	s.handleRequest(w, r)\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// Handles reports whether method is served by this server: a method of a registered\n")
+	sb.WriteString("// interface, or of a composed server\n")
+	sb.WriteString("func (s *PulseRPCServer) Handles(method string) bool {\n")
+	sb.WriteString("	return s.handlesOwn(method) || s.composition.Find(method) != nil\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// handlesOwn reports whether method is a method of an interface registered with this server\n")
+	sb.WriteString("func (s *PulseRPCServer) handlesOwn(method string) bool {\n")
+	if usesWireNames(interfaces) {
+		sb.WriteString("	if name, ok := wireMethods[method]; ok {\n")
+		sb.WriteString("		method = name\n")
+		sb.WriteString("	}\n")
+	}
+	sb.WriteString("	interfaceName, methodName, ok := strings.Cut(method, \".\")\n")
+	sb.WriteString("	if !ok || methodDefs[interfaceName][methodName] == nil {\n")
+	sb.WriteString("		return false\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	if _, ok := s.handlers[interfaceName]; ok {\n")
+	sb.WriteString("		return true\n")
+	sb.WriteString("	}\n")
+	if usesInterfaceInheritance(interfaces) {
+		sb.WriteString("	for _, sub := range subInterfaces[interfaceName] {\n")
+		sb.WriteString("		if _, ok := s.handlers[sub]; ok {\n")
+		sb.WriteString("			return true\n")
+		sb.WriteString("		}\n")
+		sb.WriteString("	}\n")
+	}
+	sb.WriteString("	return false\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// IDLDocument returns the idl.json document of the server, merged with those of\n")
+	sb.WriteString("// composed servers\n")
+	sb.WriteString("func (s *PulseRPCServer) IDLDocument() (map[string]interface{}, error) {\n")
+	sb.WriteString("	var doc map[string]interface{}\n")
+	sb.WriteString("	if err := json.Unmarshal(idlJSON, &doc); err != nil {\n")
+	sb.WriteString("		return nil, err\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	return s.composition.MergeIDL(doc)\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// responseFromMap returns a response HandleRequestContext returned, such as a composed\n")
+	sb.WriteString("// server's, as the response of one call\n")
+	sb.WriteString("func responseFromMap(response map[string]interface{}) *rpcResponse {\n")
+	sb.WriteString("	if response == nil {\n")
+	sb.WriteString("		return nil\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	converted := &rpcResponse{ID: response[\"id\"], Result: response[\"result\"]}\n")
+	sb.WriteString("	converted.Meta, _ = response[\"meta\"].(map[string]interface{})\n")
+	sb.WriteString("	if failure, ok := response[\"error\"].(map[string]interface{}); ok {\n")
+	sb.WriteString("		converted.Error = &rpcError{Data: failure[\"data\"]}\n")
+	sb.WriteString("		converted.Error.Message, _ = failure[\"message\"].(string)\n")
+	sb.WriteString("		switch code := failure[\"code\"].(type) {\n")
+	sb.WriteString("		case int:\n")
+	sb.WriteString("			converted.Error.Code = code\n")
+	sb.WriteString("		case float64:\n")
+	sb.WriteString("			converted.Error.Code = int(code)\n")
+	sb.WriteString("		}\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	return converted\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeComposeServerPy writes the compose, mount, handles and idl_document
+// methods of the Python server
+func writeComposeServerPy(sb *strings.Builder, interfaces []*parser.Interface, idlDoc idlJSONDocument) {
+	sb.WriteString("    def compose(self, server: Any) -> None:\n")
+	sb.WriteString("        \"\"\"Serve the interfaces of server, the PulseRPCServer of another IDL, from this\n")
+	sb.WriteString("        server's endpoint, so the services share one port. Calls for an interface\n")
+	sb.WriteString("        registered here stay here, and pulserpc-idl answers with the IDLs of both merged.\"\"\"\n")
+	sb.WriteString("        self._composition.add(server)\n\n")
+
+	sb.WriteString("    def mount(self, path: str, server: Any) -> None:\n")
+	sb.WriteString("        \"\"\"Serve server, the PulseRPCServer of another IDL, under path. Requests to path\n")
+	sb.WriteString("        and below go to its handle_http() with path removed from their target.\"\"\"\n")
+	sb.WriteString("        self._composition.mount(path, server)\n\n")
+
+	sb.WriteString("    def handles(self, method: str) -> bool:\n")
+	sb.WriteString("        \"\"\"Report whether method is served by this server: a method of a registered\n")
+	sb.WriteString("        interface, or of a composed server\"\"\"\n")
+	sb.WriteString("        return self._handles_own(method) or self._composition.find(method) is not None\n\n")
+
+	sb.WriteString("    def _handles_own(self, method: str) -> bool:\n")
+	sb.WriteString("        \"\"\"Report whether method is a method of an interface registered with this server\"\"\"\n")
+	if usesWireNames(interfaces) {
+		sb.WriteString("        method = WIRE_METHODS.get(method, method)\n")
+	}
+	sb.WriteString("        interface_name, _, method_name = method.partition('.')\n")
+	sb.WriteString("        if method_name not in METHOD_DEFS.get(interface_name, {}):\n")
+	sb.WriteString("            return False\n")
+	if usesInterfaceInheritance(interfaces) {
+		sb.WriteString("        return interface_name in self.handlers or any(sub in self.handlers for sub in SUB_INTERFACES.get(interface_name, []))\n\n")
+	} else {
+		sb.WriteString("        return interface_name in self.handlers\n\n")
+	}
+
+	sb.WriteString("    def idl_document(self) -> Dict[str, Any]:\n")
+	sb.WriteString("        \"\"\"Return the idl.json document of the server, merged with those of composed servers\"\"\"\n")
+	if idlDoc.Embedded() {
+		sb.WriteString("        return self._composition.merge_idl(json.loads(" + idlDoc.StringLiteral() + "))\n\n")
+		return
+	}
+	elems := "'" + strings.Join(strings.Split(idlDoc.Path, "/"), "', '") + "'"
+	sb.WriteString("        with open(os.path.join(os.path.dirname(os.path.abspath(__file__)), " + elems + "), 'r', encoding='utf-8') as f:\n")
+	sb.WriteString("            return self._composition.merge_idl(json.load(f))\n\n")
+}
+
+// writeComposeServerJava writes the compose, handles and idlDocument methods of
+// the Java Server. Java mounts a Server under a path by constructing it on the
+// HttpServer of another with a context path.
+func writeComposeServerJava(sb *strings.Builder, interfaces []*parser.Interface, idlDoc idlJSONDocument) {
+	sb.WriteString("    /**\n")
+	sb.WriteString("     * Serves the interfaces of service, such as the Server of another IDL, from this\n")
+	sb.WriteString("     * Server's endpoint, so the services share one port. Calls for an interface registered\n")
+	sb.WriteString("     * here stay here, and pulserpc-idl answers with the IDLs of both merged.\n")
+	sb.WriteString("     */\n")
+	sb.WriteString("    public void compose(ComposedService service) {\n")
+	sb.WriteString("        composition.add(service);\n")
+	sb.WriteString("    }\n\n")
+
+	sb.WriteString("    /**\n")
+	sb.WriteString("     * The HttpServer this Server serves on, which the Servers of other IDLs can be\n")
+	sb.WriteString("     * constructed on with their own path.\n")
+	sb.WriteString("     */\n")
+	sb.WriteString("    public HttpServer getHttpServer() {\n")
+	sb.WriteString("        return server;\n")
+	sb.WriteString("    }\n\n")
+
+	sb.WriteString("    /**\n")
+	sb.WriteString("     * Whether method is served by this Server: a method of a registered interface, or\n")
+	sb.WriteString("     * of a composed service.\n")
+	sb.WriteString("     */\n")
+	sb.WriteString("    @Override\n")
+	sb.WriteString("    public boolean handles(String method) {\n")
+	sb.WriteString("        return handlesOwn(method) || composition.find(method) != null;\n")
+	sb.WriteString("    }\n\n")
+
+	sb.WriteString("    // Whether method is a method of an interface registered with this Server\n")
+	sb.WriteString("    private boolean handlesOwn(String method) {\n")
+	if usesWireNames(interfaces) {
+		sb.WriteString("        method = WIRE_METHODS.getOrDefault(method, method);\n")
+	}
+	sb.WriteString("        if (!ParamNames.BY_METHOD.containsKey(method)) {\n")
+	sb.WriteString("            return false;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        String interfaceName = method.substring(0, method.indexOf('.'));\n")
+	if usesInterfaceInheritance(interfaces) {
+		sb.WriteString("        if (interfaceHandlers.containsKey(interfaceName)) {\n")
+		sb.WriteString("            return true;\n")
+		sb.WriteString("        }\n")
+		sb.WriteString("        for (String sub : SUB_INTERFACES.getOrDefault(interfaceName, List.of())) {\n")
+		sb.WriteString("            if (interfaceHandlers.containsKey(sub)) {\n")
+		sb.WriteString("                return true;\n")
+		sb.WriteString("            }\n")
+		sb.WriteString("        }\n")
+		sb.WriteString("        return false;\n")
+	} else {
+		sb.WriteString("        return interfaceHandlers.containsKey(interfaceName);\n")
+	}
+	sb.WriteString("    }\n\n")
+
+	sb.WriteString("    /**\n")
+	sb.WriteString("     * The idl.json document of this Server, merged with those of composed services.\n")
+	if idlDoc.Embedded() {
+		sb.WriteString("     * It is embedded in the Server class.\n")
+		sb.WriteString("     */\n")
+		sb.WriteString("    @Override\n")
+		sb.WriteString("    @SuppressWarnings(\"unchecked\")\n")
+		sb.WriteString("    public Map<String, Object> idlDocument() throws IOException {\n")
+		sb.WriteString("        String idlJson = String.join(\"\",\n")
+		chunks := javaStringChunks(idlDoc.Compact())
+		for i, chunk := range chunks {
+			sb.WriteString("            " + chunk)
+			if i < len(chunks)-1 {
+				sb.WriteString(",")
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("        );\n")
+		sb.WriteString("        return composition.mergeIdl((Map<String, Object>) jsonParser.fromJson(idlJson, Map.class));\n")
+		sb.WriteString("    }\n\n")
+	} else {
+		if idlDoc.Path == idlJSONDefaultPath {
+			sb.WriteString("     * It is read from next to the Server class in the classpath, or from its root.\n")
+		} else {
+			sb.WriteString("     * It is read from " + idlDoc.Path + " next to the Server class in the classpath.\n")
+		}
+		sb.WriteString("     */\n")
+		sb.WriteString("    @Override\n")
+		sb.WriteString("    @SuppressWarnings(\"unchecked\")\n")
+		sb.WriteString("    public Map<String, Object> idlDocument() throws IOException {\n")
+		sb.WriteString("        InputStream is = Server.class.getResourceAsStream(\"" + idlDoc.Path + "\");\n")
+		if idlDoc.Path == idlJSONDefaultPath {
+			sb.WriteString("        if (is == null) {\n")
+			sb.WriteString("            is = Server.class.getResourceAsStream(\"/idl.json\");\n")
+			sb.WriteString("        }\n")
+		}
+		sb.WriteString("        if (is == null) {\n")
+		sb.WriteString("            throw new FileNotFoundException(\"" + idlDoc.Path + " not found in classpath\");\n")
+		sb.WriteString("        }\n")
+		sb.WriteString("        try (InputStream in = is) {\n")
+		sb.WriteString("            String idlJson = new String(in.readAllBytes(), java.nio.charset.StandardCharsets.UTF_8);\n")
+		sb.WriteString("            return composition.mergeIdl((Map<String, Object>) jsonParser.fromJson(idlJson, Map.class));\n")
+		sb.WriteString("        }\n")
+		sb.WriteString("    }\n\n")
+	}
+
+	sb.WriteString("    // The path of a request relative to the context path the Server serves at\n")
+	sb.WriteString("    private static String routePath(HttpExchange exchange) {\n")
+	sb.WriteString("        String path = exchange.getRequestURI().getPath();\n")
+	sb.WriteString("        String context = exchange.getHttpContext().getPath();\n")
+	sb.WriteString("        if (context.length() <= 1 || !path.startsWith(context)) {\n")
+	sb.WriteString("            return path;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        String rest = path.substring(context.length());\n")
+	sb.WriteString("        return rest.startsWith(\"/\") ? rest : \"/\" + rest;\n")
+	sb.WriteString("    }\n\n")
+}
+
+// javaStringChunks returns s as Java string literals of at most 8000 characters
+// each, as a class file limits a string constant to 65535 bytes of UTF-8
+func javaStringChunks(s string) []string {
+	var chunks []string
+	runes := []rune(s)
+	for len(runes) > 0 {
+		n := min(len(runes), 8000)
+		literal, _ := json.Marshal(string(runes[:n]))
+		chunks = append(chunks, string(literal))
+		runes = runes[n:]
+	}
+	return chunks
+}
+
+// writeComposeServerCs writes the Compose, Mount, Handles and IdlJson members of
+// the C# server
+func writeComposeServerCs(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("    /// <summary>\n")
+	sb.WriteString("    /// Serves the interfaces of another server, such as the PulseRPCServer of another IDL,\n")
+	sb.WriteString("    /// from this server's endpoint, so the services share one port:\n")
+	sb.WriteString("    /// Compose(other.Handles, other.HandleRequestAsync, () => other.IdlJson). Calls for an\n")
+	sb.WriteString("    /// interface registered here stay here, and pulserpc-idl answers with the IDLs of both merged.\n")
+	sb.WriteString("    /// </summary>\n")
+	sb.WriteString("    public void Compose(Func<string, bool> handles, Func<JsonElement, Task<Dictionary<string, object?>?>> handleRequestAsync, Func<string> idlJson)\n")
+	sb.WriteString("    {\n")
+	sb.WriteString("        _composition.Add(new ComposedService(handles, handleRequestAsync, idlJson));\n")
+	sb.WriteString("    }\n\n")
+
+	sb.WriteString("    /// <summary>\n")
+	sb.WriteString("    /// Serves another server under path when RunAsync starts, given its MapEndpoints, such as\n")
+	sb.WriteString("    /// Mount(\"/users\", users.MapEndpoints). Call before RunAsync.\n")
+	sb.WriteString("    /// </summary>\n")
+	sb.WriteString("    public void Mount(string path, Action<IEndpointRouteBuilder, string> mapEndpoints)\n")
+	sb.WriteString("    {\n")
+	sb.WriteString("        _mounts.Add((\"/\" + path.Trim('/'), mapEndpoints));\n")
+	sb.WriteString("    }\n\n")
+
+	sb.WriteString("    /// <summary>\n")
+	sb.WriteString("    /// Whether method is served by this server: a method of a registered interface, or of a\n")
+	sb.WriteString("    /// composed server.\n")
+	sb.WriteString("    /// </summary>\n")
+	sb.WriteString("    public bool Handles(string method)\n")
+	sb.WriteString("    {\n")
+	sb.WriteString("        return HandlesOwn(method) || _composition.Find(method) != null;\n")
+	sb.WriteString("    }\n\n")
+
+	sb.WriteString("    // Whether method is a method of an interface registered with this server\n")
+	sb.WriteString("    private bool HandlesOwn(string method)\n")
+	sb.WriteString("    {\n")
+	if usesWireNames(interfaces) {
+		sb.WriteString("        if (WireMethods.TryGetValue(method, out var wireTarget)) method = wireTarget;\n")
+	}
+	sb.WriteString("        var parts = method.Split('.', 2);\n")
+	sb.WriteString("        if (parts.Length != 2 || !IdlData.METHOD_DEFS.TryGetValue(parts[0], out var methods) || !methods.ContainsKey(parts[1]))\n")
+	sb.WriteString("        {\n")
+	sb.WriteString("            return false;\n")
+	sb.WriteString("        }\n")
+	if usesInterfaceInheritance(interfaces) {
+		sb.WriteString("        return _handlers.ContainsKey(parts[0]) || (SubInterfaces.TryGetValue(parts[0], out var subs) && subs.Any(_handlers.ContainsKey));\n")
+	} else {
+		sb.WriteString("        return _handlers.ContainsKey(parts[0]);\n")
+	}
+	sb.WriteString("    }\n\n")
+
+	sb.WriteString("    /// <summary>\n")
+	sb.WriteString("    /// The idl.json document of this server, merged with those of composed servers.\n")
+	sb.WriteString("    /// </summary>\n")
+	sb.WriteString("    public string IdlJson => _composition.MergeIdl(_idlJson);\n\n")
+
+	sb.WriteString("    // A response HandleRequestAsync returned, such as a composed server's, as the response of one call\n")
+	sb.WriteString("    private static RpcResponse? ResponseFromDictionary(Dictionary<string, object?>? response)\n")
+	sb.WriteString("    {\n")
+	sb.WriteString("        if (response == null)\n")
+	sb.WriteString("        {\n")
+	sb.WriteString("            return null;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        response.TryGetValue(\"id\", out var id);\n")
+	sb.WriteString("        response.TryGetValue(\"result\", out var result);\n")
+	sb.WriteString("        var meta = response.TryGetValue(\"meta\", out var metaObj) ? metaObj as IDictionary<string, object?> : null;\n")
+	sb.WriteString("        if (response.TryGetValue(\"error\", out var errorObj) && errorObj is Dictionary<string, object?> error)\n")
+	sb.WriteString("        {\n")
+	sb.WriteString("            error.TryGetValue(\"data\", out var data);\n")
+	sb.WriteString("            var code = error.TryGetValue(\"code\", out var codeObj) ? Convert.ToInt32(codeObj, CultureInfo.InvariantCulture) : -32603;\n")
+	sb.WriteString("            var message = error.TryGetValue(\"message\", out var messageObj) ? messageObj?.ToString() ?? \"\" : \"\";\n")
+	sb.WriteString("            return new RpcResponse(id, Error: new RpcError(code, message, data));\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        return new RpcResponse(id, result, Meta: meta);\n")
+	sb.WriteString("    }\n\n")
+}
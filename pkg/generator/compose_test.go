@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestServerCompositionGenerated(t *testing.T) {
+	idl, err := parser.ParseIDL("shop.pulse", `namespace shop
+
+interface Catalog {
+  get(id string) string
+}
+
+interface Store extends Catalog [wire="v1.store"] {
+  buy(id string) bool
+}`)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	tests := []struct {
+		plugin Plugin
+		files  map[string][]string
+	}{
+		{NewGoClientServer(), map[string][]string{
+			"compose.go": {"type ComposedServer interface {", "func MergeIDLDocuments(docs ...map[string]interface{}) map[string]interface{} {"},
+			"server.go": {
+				"\t_ \"embed\"\n",
+				"//go:embed idl.json\nvar idlJSON []byte\n",
+				"func (s *PulseRPCServer) Compose(server ComposedServer) {",
+				"func (s *PulseRPCServer) Mount(path string, handler http.Handler) {",
+				"\tif name, ok := wireMethods[method]; ok {\n\t\tmethod = name\n\t}\n\tinterfaceName, methodName, ok := strings.Cut(method, \".\")\n",
+				"\tfor _, sub := range subInterfaces[interfaceName] {\n\t\tif _, ok := s.handlers[sub]; ok {\n",
+				"\t\tif composed := s.composition.Find(method); composed != nil {\n\t\t\treturn responseFromMap(composed.HandleRequestContext(ctx, requestJson))\n",
+				"\tmux.Handle(\"/\", s)\n",
+			},
+		}},
+		{NewPythonClientServer(), map[string][]string{
+			"pulserpc/compose.py": {"def merge_idl_documents(*docs: Dict[str, Any]) -> Dict[str, Any]:"},
+			"server.py": {
+				"        self._composition = Composition()\n",
+				"    def compose(self, server: Any) -> None:\n",
+				"        method = WIRE_METHODS.get(method, method)\n        interface_name, _, method_name = method.partition('.')\n",
+				"any(sub in self.handlers for sub in SUB_INTERFACES.get(interface_name, []))",
+				"                return composed.handle_request(request_json)\n",
+				"        mounted = self._composition.route(target)\n",
+			},
+		}},
+		{NewCSharpClientServer(), map[string][]string{
+			"PulseRPC/Composition.cs": {"public static string MergeIdlDocuments(IEnumerable<string> idlJsons)"},
+			"Server.cs": {
+				"public void Compose(Func<string, bool> handles, Func<JsonElement, Task<Dictionary<string, object?>?>> handleRequestAsync, Func<string> idlJson)",
+				"public void Mount(string path, Action<IEndpointRouteBuilder, string> mapEndpoints)",
+				"public void MapEndpoints(IEndpointRouteBuilder endpoints, string path = \"/\")",
+				"if (WireMethods.TryGetValue(method, out var wireTarget)) method = wireTarget;\n        var parts = method.Split('.', 2);\n",
+				"public string IdlJson => _composition.MergeIdl(_idlJson);",
+				"if (!HandlesOwn(method) && _composition.Find(method) is ComposedService composed)",
+			},
+		}},
+		{NewJavaClientServer(), map[string][]string{
+			"src/main/java/com/bitmechanic/pulserpc/ComposedService.java": {"public interface ComposedService {"},
+			"src/main/resources/com/example/idl.json":                     {"\"name\": \"Store\""},
+			"src/main/java/com/example/Server.java": {
+				"public class Server implements ComposedService {",
+				"public Server(HttpServer server, String path, JsonParser jsonParser, java.util.concurrent.Executor executor) {",
+				"public void compose(ComposedService service) {",
+				"        method = WIRE_METHODS.getOrDefault(method, method);\n        if (!ParamNames.BY_METHOD.containsKey(method)) {\n",
+				"InputStream is = Server.class.getResourceAsStream(\"idl.json\");",
+				"ReadOnlyRoute.BY_PATH.get(routePath(exchange))",
+				"                return composed.handleRequest(request);\n",
+			},
+		}},
+	}
+	for _, tt := range tests {
+		tmpDir := t.TempDir()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		tt.plugin.RegisterFlags(fs)
+		for name, value := range map[string]string{"dir": tmpDir, "base-package": "com.example"} {
+			if fs.Lookup(name) != nil {
+				if err := fs.Set(name, value); err != nil {
+					t.Fatalf("failed to set %s flag: %v", name, err)
+				}
+			}
+		}
+		if err := tt.plugin.Generate(idl, fs); err != nil {
+			t.Fatalf("%s: Generate failed: %v", tt.plugin.Name(), err)
+		}
+		for file, wants := range tt.files {
+			content, err := os.ReadFile(filepath.Join(tmpDir, file))
+			if err != nil {
+				t.Fatalf("%s: expected %s: %v", tt.plugin.Name(), file, err)
+			}
+			for _, want := range wants {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("%s: %s does not contain %q", tt.plugin.Name(), file, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Response compression: a method annotated [compress] has its responses gzipped
+// by every server when the request's Accept-Encoding lists gzip, and
+// [compress="4096"] leaves responses under 4096 bytes as they are. Other methods
+// are never compressed, so small hot-path responses don't pay for it. A batch is
+// compressed when any call in it is to a [compress] method, with the smallest
+// threshold among them. Responses of [compress] methods carry Vary:
+// Accept-Encoding whether compressed or not. Servers keep a table of the names
+// the methods are called by, [wire] names included. Go and TypeScript clients
+// accept gzip on every call through net/http and fetch, and the Java transport
+// does too. Python and C# clients send Accept-Encoding: gzip when the IDL has
+// [compress] methods and decode gzip responses themselves, so a C# HttpClient
+// passed in without automatic decompression still gets them. The Rust server
+// ignores the annotation and the Rust client never asks for gzip. The tables and
+// helpers are only generated when the IDL uses the annotation.
+
+// compressedMethod pairs a name a [compress] method is called by with the response
+// size in bytes from which its responses are gzipped
+type compressedMethod struct {
+	Name      string
+	Threshold int
+}
+
+// compressedMethods returns the [compress] methods, inherited ones included, by
+// their Interface.method name and by their [wire] name if they have one
+func compressedMethods(interfaces []*parser.Interface) []compressedMethod {
+	var methods []compressedMethod
+	for _, iface := range interfaces {
+		for _, method := range iface.Methods {
+			threshold, ok := method.CompressThreshold()
+			if !ok {
+				continue
+			}
+			canonical := iface.Name + "." + method.Name
+			methods = append(methods, compressedMethod{Name: canonical, Threshold: threshold})
+			if wire := iface.RPCName(method); wire != canonical {
+				methods = append(methods, compressedMethod{Name: wire, Threshold: threshold})
+			}
+		}
+	}
+	return methods
+}
+
+// usesCompressedMethods reports whether any method has a [compress] annotation
+func usesCompressedMethods(interfaces []*parser.Interface) bool {
+	return len(compressedMethods(interfaces)) > 0
+}
+
+// writeCompressionGo writes the compressedMethods table and the gzip helpers of the Go server
+func writeCompressionGo(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("// compressedMethods maps the names [compress] methods are called by to the response\n")
+	sb.WriteString("// size in bytes from which their responses are gzipped\n")
+	sb.WriteString("var compressedMethods = map[string]int{\n")
+	for _, m := range compressedMethods(interfaces) {
+		fmt.Fprintf(sb, "	%q: %d,\n", m.Name, m.Threshold)
+	}
+	sb.WriteString("}\n\n")
+
+	sb.WriteString(`// compressedCallsKey is the context key of the compressedCalls of an HTTP request
+type compressedCallsKey struct{}
+
+// compressedCalls records whether a message called [compress] methods, and the smallest
+// threshold among them
+type compressedCalls struct {
+	called    bool
+	threshold int
+}
+
+// noteCompressedCall records a call of method in the compressedCalls of ctx, if it has
+// them and method is a [compress] method
+func noteCompressedCall(ctx context.Context, method string) {
+	threshold, ok := compressedMethods[method]
+	calls, _ := ctx.Value(compressedCallsKey{}).(*compressedCalls)
+	if !ok || calls == nil {
+		return
+	}
+	if !calls.called || threshold < calls.threshold {
+		calls.threshold = threshold
+	}
+	calls.called = true
+}
+
+// compressResponse returns body gzipped, and sets Content-Encoding, if it is at least
+// threshold bytes and the request accepts gzip. The response varies by Accept-Encoding
+// either way.
+func compressResponse(w http.ResponseWriter, r *http.Request, threshold int, body []byte) []byte {
+	w.Header().Add("Vary", "Accept-Encoding")
+	if len(body) < threshold || !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+		return body
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(body)
+	gz.Close()
+	w.Header().Set("Content-Encoding", "gzip")
+	return buf.Bytes()
+}
+
+// acceptsGzip reports whether an Accept-Encoding header lists gzip, or *, with a
+// non-zero q value
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.TrimSpace(coding)
+		if !strings.EqualFold(coding, "gzip") && coding != "*" {
+			continue
+		}
+		name, value, ok := strings.Cut(params, "=")
+		if !ok || strings.TrimSpace(name) != "q" {
+			return true
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return err == nil && q > 0
+	}
+	return false
+}
+
+`)
+}
+
+// writeCompressionPy writes the COMPRESSED_METHODS table and the gzip helpers of the Python server
+func writeCompressionPy(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("# Names [compress] methods are called by, mapped to the response size in bytes from\n")
+	sb.WriteString("# which their responses are gzipped\n")
+	sb.WriteString("COMPRESSED_METHODS = {\n")
+	for _, m := range compressedMethods(interfaces) {
+		fmt.Fprintf(sb, "    '%s': %d,\n", m.Name, m.Threshold)
+	}
+	sb.WriteString("}\n\n")
+
+	sb.WriteString(`# Gzip thresholds of the [compress] methods called by the HTTP request being served
+_compressed_calls: ContextVar[Optional[List[int]]] = ContextVar('_compressed_calls', default=None)
+
+
+def _note_compressed_call(method: Any) -> None:
+    """Record a call of method for the HTTP request being served if it is a [compress] method"""
+    calls = _compressed_calls.get()
+    if calls is not None and isinstance(method, str) and method in COMPRESSED_METHODS:
+        calls.append(COMPRESSED_METHODS[method])
+
+
+def _compress_response(request_headers: Any, response_headers: Dict[str, str], body: bytes, threshold: int) -> Tuple[Dict[str, str], bytes]:
+    """Return body gzipped, with Content-Encoding added to response_headers, if it is at least
+    threshold bytes and the request accepts gzip. The response varies by Accept-Encoding either way."""
+    response_headers = {**response_headers, 'Vary': 'Accept-Encoding'}
+    if len(body) < threshold or not _accepts_gzip(request_headers.get('Accept-Encoding')):
+        return response_headers, body
+    return {**response_headers, 'Content-Encoding': 'gzip'}, gzip.compress(body)
+
+
+def _accepts_gzip(header: Optional[str]) -> bool:
+    """Report whether an Accept-Encoding header lists gzip, or *, with a non-zero q value"""
+    for part in (header or '').split(','):
+        coding, _, params = part.partition(';')
+        coding = coding.strip()
+        if coding.lower() != 'gzip' and coding != '*':
+            continue
+        name, sep, value = params.partition('=')
+        if not sep or name.strip() != 'q':
+            return True
+        try:
+            return float(value) > 0
+        except ValueError:
+            return False
+    return False
+
+
+`)
+}
+
+// writeDecodeBodyPy writes the gzip decoding helper of the Python HTTPTransport
+func writeDecodeBodyPy(sb *strings.Builder) {
+	sb.WriteString(`def _decode_body(headers: Any, body: bytes) -> bytes:
+    """Return a response body, gunzipped if its Content-Encoding is gzip"""
+    if (headers.get('Content-Encoding') or '').lower() == 'gzip':
+        return gzip.decompress(body)
+    return body
+
+
+`)
+}
+
+// writeCompressionTs writes the COMPRESSED_METHODS table and the gzip helpers of the TypeScript server
+func writeCompressionTs(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("// Names [compress] methods are called by, mapped to the response size in bytes from\n")
+	sb.WriteString("// which their responses are gzipped\n")
+	sb.WriteString("const COMPRESSED_METHODS: Record<string, number> = {\n")
+	for _, m := range compressedMethods(interfaces) {
+		fmt.Fprintf(sb, "  '%s': %d,\n", m.Name, m.Threshold)
+	}
+	sb.WriteString("};\n\n")
+	sb.WriteString(`// The smallest gzip threshold of the [compress] methods a request or batch calls, or
+// null if it calls none
+function compressThreshold(data: any): number | null {
+  let threshold: number | null = null;
+  for (const call of Array.isArray(data) ? data : [data]) {
+    const methodThreshold = call && typeof call.method === 'string' ? COMPRESSED_METHODS[call.method] : undefined;
+    if (methodThreshold !== undefined && (threshold === null || methodThreshold < threshold)) {
+      threshold = methodThreshold;
+    }
+  }
+  return threshold;
+}
+
+// Whether an Accept-Encoding header lists gzip, or *, with a non-zero q value
+function acceptsGzip(header: string | undefined): boolean {
+  for (const part of (header || '').split(',')) {
+    const [coding, ...params] = part.split(';').map((s) => s.trim());
+    if (coding.toLowerCase() !== 'gzip' && coding !== '*') {
+      continue;
+    }
+    const q = params.find((p) => p.startsWith('q='));
+    return q === undefined || parseFloat(q.slice(2)) > 0;
+  }
+  return false;
+}
+
+// Writes a JSON response, gzipped with Content-Encoding if threshold is not null, body
+// is at least threshold bytes and the request accepts gzip. Responses of [compress]
+// methods vary by Accept-Encoding either way.
+function writeCompressible(requestHeaders: http.IncomingHttpHeaders, res: http.ServerResponse, status: number, headers: http.OutgoingHttpHeaders, body: string, threshold: number | null): void {
+  if (threshold !== null) {
+    headers = { ...headers, 'Vary': 'Accept-Encoding' };
+    const bytes = Buffer.from(body, 'utf8');
+    if (bytes.length >= threshold && acceptsGzip(requestHeaders['accept-encoding'] as string | undefined)) {
+      res.writeHead(status, { ...headers, 'Content-Encoding': 'gzip' });
+      res.end(gzipSync(bytes));
+      return;
+    }
+  }
+  res.writeHead(status, headers);
+  res.end(body);
+}
+
+`)
+}
+
+// writeCompressionJava writes the COMPRESSED_METHODS table and the gzip helpers of the Java server
+func writeCompressionJava(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("    // Names [compress] methods are called by, mapped to the response size in bytes from\n")
+	sb.WriteString("    // which their responses are gzipped\n")
+	sb.WriteString("    private static final Map<String, Integer> COMPRESSED_METHODS = Map.ofEntries(\n")
+	methods := compressedMethods(interfaces)
+	for i, m := range methods {
+		sep := ","
+		if i == len(methods)-1 {
+			sep = ""
+		}
+		fmt.Fprintf(sb, "        Map.entry(%q, %d)%s\n", m.Name, m.Threshold, sep)
+	}
+	sb.WriteString("    );\n\n")
+
+	sb.WriteString(`    // Returns body gzipped, and sets Content-Encoding, if method is a [compress] method, body
+    // is at least its threshold and the request accepts gzip. Responses of [compress] methods
+    // vary by Accept-Encoding either way.
+    private static byte[] compressResponse(HttpExchange exchange, String method, byte[] body) throws IOException {
+        Integer threshold = COMPRESSED_METHODS.get(method);
+        if (threshold == null) {
+            return body;
+        }
+        exchange.getResponseHeaders().add("Vary", "Accept-Encoding");
+        if (body.length < threshold || !acceptsGzip(exchange.getRequestHeaders().getFirst("Accept-Encoding"))) {
+            return body;
+        }
+        ByteArrayOutputStream out = new ByteArrayOutputStream();
+        try (java.util.zip.GZIPOutputStream gz = new java.util.zip.GZIPOutputStream(out)) {
+            gz.write(body);
+        }
+        exchange.getResponseHeaders().set("Content-Encoding", "gzip");
+        return out.toByteArray();
+    }
+
+    // Whether an Accept-Encoding header lists gzip, or *, with a non-zero q value
+    private static boolean acceptsGzip(String header) {
+        if (header == null) {
+            return false;
+        }
+        for (String part : header.split(",")) {
+            String[] codingAndParams = part.split(";", 2);
+            String coding = codingAndParams[0].trim();
+            if (!coding.equalsIgnoreCase("gzip") && !coding.equals("*")) {
+                continue;
+            }
+            String[] param = codingAndParams.length < 2 ? new String[0] : codingAndParams[1].split("=", 2);
+            if (param.length < 2 || !param[0].trim().equals("q")) {
+                return true;
+            }
+            try {
+                return Double.parseDouble(param[1].trim()) > 0;
+            } catch (NumberFormatException e) {
+                return false;
+            }
+        }
+        return false;
+    }
+
+`)
+}
+
+// writeCompressedMethodsCs writes the CompressedMethods table of the C# server
+func writeCompressedMethodsCs(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("    // Names [compress] methods are called by, mapped to the response size in bytes from\n")
+	sb.WriteString("    // which their responses are gzipped\n")
+	sb.WriteString("    private static readonly Dictionary<string, int> CompressedMethods = new Dictionary<string, int>\n")
+	sb.WriteString("    {\n")
+	for _, m := range compressedMethods(interfaces) {
+		fmt.Fprintf(sb, "        { %q, %d },\n", m.Name, m.Threshold)
+	}
+	sb.WriteString("    };\n\n")
+}
+
+// writeCompressionCs writes the gzip helpers of the C# server
+func writeCompressionCs(sb *strings.Builder) {
+	sb.WriteString(`    // Writes body gzipped, and sets Content-Encoding, if a [compress] method was called,
+    // compressThreshold being the smallest threshold among them, body is at least that long and
+    // the request accepts gzip. Responses of [compress] methods vary by Accept-Encoding either way.
+    private static async Task WriteJsonBytes(HttpContext context, System.IO.MemoryStream body, int? compressThreshold)
+    {
+        if (compressThreshold is not int threshold)
+        {
+            await WriteJsonBytes(context, body);
+            return;
+        }
+        context.Response.Headers.Append("Vary", "Accept-Encoding");
+        if (body.Length < threshold || !AcceptsGzip(context.Request.Headers["Accept-Encoding"].ToString()))
+        {
+            await WriteJsonBytes(context, body);
+            return;
+        }
+        using var compressed = new System.IO.MemoryStream();
+        using (var gzip = new System.IO.Compression.GZipStream(compressed, System.IO.Compression.CompressionLevel.Fastest, true))
+        {
+            gzip.Write(body.GetBuffer(), 0, (int)body.Length);
+        }
+        context.Response.Headers["Content-Encoding"] = "gzip";
+        await WriteJsonBytes(context, compressed);
+    }
+
+    // The smallest gzip threshold of the [compress] methods a request or batch calls, or null
+    // if it calls none
+    private static int? CompressThreshold(JsonElement request)
+    {
+        int? threshold = null;
+        var calls = request.ValueKind == JsonValueKind.Array ? request.EnumerateArray().ToArray() : new[] { request };
+        foreach (var call in calls)
+        {
+            if (call.ValueKind == JsonValueKind.Object && call.TryGetProperty("method", out var method) && method.ValueKind == JsonValueKind.String
+                && CompressedMethods.TryGetValue(method.GetString()!, out var methodThreshold) && (threshold == null || methodThreshold < threshold))
+            {
+                threshold = methodThreshold;
+            }
+        }
+        return threshold;
+    }
+
+    // Whether an Accept-Encoding header lists gzip, or *, with a non-zero q value
+    private static bool AcceptsGzip(string header)
+    {
+        foreach (var part in header.Split(','))
+        {
+            var codingAndParams = part.Split(';', 2);
+            var coding = codingAndParams[0].Trim();
+            if (!coding.Equals("gzip", StringComparison.OrdinalIgnoreCase) && coding != "*")
+            {
+                continue;
+            }
+            var param = codingAndParams.Length < 2 ? Array.Empty<string>() : codingAndParams[1].Split('=', 2);
+            if (param.Length < 2 || param[0].Trim() != "q")
+            {
+                return true;
+            }
+            return double.TryParse(param[1].Trim(), NumberStyles.Float, CultureInfo.InvariantCulture, out var q) && q > 0;
+        }
+        return false;
+    }
+
+`)
+}
@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestCompressedMethods(t *testing.T) {
+	idl, err := parser.ParseIDL("shop.pulse", "interface Catalog [wire=\"v1.catalog\"] {\n  list() []string [compress]\n  search(q string) []string [compress=\"4096\"] [wire=\"find\"]\n  count() int\n}")
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	want := []compressedMethod{
+		{"Catalog.list", 0},
+		{"v1.catalog.list", 0},
+		{"Catalog.search", 4096},
+		{"find", 4096},
+	}
+	got := compressedMethods(idl.Interfaces)
+	if len(got) != len(want) {
+		t.Fatalf("compressedMethods = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("compressedMethods[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestResponseCompressionGenerated(t *testing.T) {
+	tests := []struct {
+		name    string
+		idl     string
+		enabled bool
+	}{
+		{"compress methods", "interface Catalog {\n  list() []string [compress=\"2048\"]\n  count() int\n}", true},
+		{"no compress methods", "interface Catalog {\n  count() int\n}", false},
+	}
+	plugins := []struct {
+		plugin Plugin
+		file   string
+		want   []string
+	}{
+		{
+			plugin: NewGoClientServer(),
+			file:   "server.go",
+			want: []string{
+				"\t\"compress/gzip\"\n",
+				"var compressedMethods = map[string]int{\n\t\"Catalog.list\": 2048,\n}",
+				"\tif calls.called {\n\t\tresponse = compressResponse(w, r, calls.threshold, response)\n\t}\n",
+			},
+		},
+		{
+			plugin: NewPythonClientServer(),
+			file:   "server.py",
+			want: []string{
+				"import gzip\n",
+				"COMPRESSED_METHODS = {\n    'Catalog.list': 2048,\n}",
+				"def _accepts_gzip(header: Optional[str]) -> bool:",
+			},
+		},
+		{
+			plugin: NewPythonClientServer(),
+			file:   "client.py",
+			want: []string{
+				"req.add_header('Accept-Encoding', 'gzip')",
+				"def _decode_body(headers: Any, body: bytes) -> bytes:",
+			},
+		},
+		{
+			plugin: NewTSClientServer(),
+			file:   "server.ts",
+			want: []string{
+				"import { gzipSync } from 'zlib';\n",
+				"const COMPRESSED_METHODS: Record<string, number> = {\n  'Catalog.list': 2048,\n};",
+				"response, compressThreshold(data));",
+			},
+		},
+		{
+			plugin: NewCSharpClientServer(),
+			file:   "Server.cs",
+			want: []string{
+				"        { \"Catalog.list\", 2048 },\n",
+				"var compressThreshold = CompressThreshold(requestJson);",
+				"private static bool AcceptsGzip(string header)",
+			},
+		},
+		{
+			plugin: NewCSharpClientServer(),
+			file:   "Client.cs",
+			want: []string{
+				"httpRequest.Headers.AcceptEncoding.Add(new StringWithQualityHeaderValue(\"gzip\"));",
+				"if (response.Content.Headers.ContentEncoding.Contains(\"gzip\"))",
+			},
+		},
+		{
+			plugin: NewJavaClientServer(),
+			file:   "src/main/java/com/example/Server.java",
+			want: []string{
+				"Map.entry(\"Catalog.list\", 2048)",
+				"private static byte[] compressResponse(HttpExchange exchange, String method, byte[] body) throws IOException {",
+			},
+		},
+	}
+	for _, tt := range tests {
+		idl, err := parser.ParseIDL("shop.pulse", tt.idl)
+		if err != nil {
+			t.Fatalf("%s: ParseIDL failed: %v", tt.name, err)
+		}
+		for _, p := range plugins {
+			tmpDir := t.TempDir()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.String("dir", "", "output dir")
+			p.plugin.RegisterFlags(fs)
+			for name, value := range map[string]string{"dir": tmpDir, "base-package": "com.example"} {
+				if fs.Lookup(name) != nil {
+					if err := fs.Set(name, value); err != nil {
+						t.Fatalf("failed to set %s flag: %v", name, err)
+					}
+				}
+			}
+			if err := p.plugin.Generate(idl, fs); err != nil {
+				t.Fatalf("%s: %s: Generate failed: %v", tt.name, p.plugin.Name(), err)
+			}
+			content, err := os.ReadFile(filepath.Join(tmpDir, p.file))
+			if err != nil {
+				t.Fatalf("%s: expected %s: %v", p.plugin.Name(), p.file, err)
+			}
+			for _, want := range p.want {
+				if got := strings.Contains(string(content), want); got != tt.enabled {
+					t.Errorf("%s: %s: %s contains %q = %v, want %v", tt.name, p.plugin.Name(), p.file, want, got, tt.enabled)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package generator
+
+import (
+	"strings"
+)
+
+// Concurrent use of generated clients: every HTTP transport is safe to share between
+// threads, goroutines or pending promises, and gives each call a random UUID as its
+// JSON-RPC id, so concurrent calls never share one. The test clients written by
+// -generate-test-files check this by making concurrentTestCalls calls of the built-in
+// pulserpc-idl method at once through one transport and requiring a distinct id on
+// every response.
+
+// concurrentTestCalls is the number of calls the test clients make at once
+const concurrentTestCalls = "32"
+
+// writeConcurrentCallsTestGo writes the Go test client function that makes concurrent
+// calls through one transport
+func writeConcurrentCallsTestGo(sb *strings.Builder) {
+	sb.WriteString(`
+// testConcurrentCalls calls pulserpc-idl from many goroutines at once through one
+// transport and returns a message if a call fails or two responses share an id
+func testConcurrentCalls(transport Transport) []string {
+	const calls = ` + concurrentTestCalls + `
+	ids := make([]interface{}, calls)
+	errs := make([]error, calls)
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			response, err := transport.Call("pulserpc-idl", nil)
+			ids[i], errs[i] = response["id"], err
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[interface{}]bool)
+	for i := range ids {
+		if errs[i] != nil {
+			return []string{fmt.Sprintf("concurrent calls failed: %v", errs[i])}
+		}
+		if ids[i] == nil || seen[ids[i]] {
+			return []string{fmt.Sprintf("concurrent calls: response id %v is missing or shared", ids[i])}
+		}
+		seen[ids[i]] = true
+	}
+	fmt.Printf("✓ %d concurrent calls passed\n", calls)
+	return nil
+}
+`)
+}
+
+// writeConcurrentCallsTestRust writes the Rust test client function that makes
+// concurrent calls through one transport. HttpTransport fails a call whose response
+// id differs from the request id, so a shared id shows up as a failed call.
+func writeConcurrentCallsTestRust(sb *strings.Builder) {
+	sb.WriteString(`
+/// Calls pulserpc-idl from many threads at once through one transport and returns
+/// a message if a call fails
+fn test_concurrent_calls(transport: Arc<dyn Transport>) -> Vec<String> {
+    let calls = ` + concurrentTestCalls + `;
+    let handles: Vec<_> = (0..calls)
+        .map(|_| {
+            let transport = transport.clone();
+            thread::spawn(move || transport.call("pulserpc-idl", Vec::new()))
+        })
+        .collect();
+    for handle in handles {
+        match handle.join() {
+            Ok(Ok(_)) => {}
+            Ok(Err(err)) => return vec![format!("concurrent calls failed: {}", err)],
+            Err(_) => return vec!["concurrent calls failed: a thread panicked".to_string()],
+        }
+    }
+    println!("✓ {} concurrent calls passed", calls);
+    Vec::new()
+}
+`)
+}
+
+// writeConcurrentCallsTestPy writes the Python test client function that makes
+// concurrent calls through one transport
+func writeConcurrentCallsTestPy(sb *strings.Builder) {
+	sb.WriteString(`def test_concurrent_calls(transport) -> list:
+    """Call pulserpc-idl from many threads at once through one transport and return a
+    message if a call fails or two responses share an id"""
+    calls = ` + concurrentTestCalls + `
+    try:
+        with ThreadPoolExecutor(max_workers=calls) as executor:
+            responses = list(executor.map(lambda _: transport.call('pulserpc-idl', []), range(calls)))
+    except Exception as e:
+        return [f"concurrent calls failed: {e}"]
+    ids = [response.get('id') for response in responses]
+    if None in ids or len(set(ids)) != calls:
+        return [f"concurrent calls: response ids are missing or shared: {ids}"]
+    print(f"✓ {calls} concurrent calls passed")
+    return []
+
+
+`)
+}
+
+// writeConcurrentCallsTestTs writes the TypeScript test client function that makes
+// concurrent calls through one transport
+func writeConcurrentCallsTestTs(sb *strings.Builder) {
+	sb.WriteString(`// Calls pulserpc-idl many times at once through one transport and returns a message
+// if a call fails or two responses share an id
+async function testConcurrentCalls(transport: { call(method: string, params: any[]): Promise<any> }): Promise<string[]> {
+  const calls = ` + concurrentTestCalls + `;
+  let responses: any[];
+  try {
+    responses = await Promise.all(Array.from({ length: calls }, () => transport.call('pulserpc-idl', [])));
+  } catch (err: any) {
+    return [` + "`concurrent calls failed: ${err.message}`" + `];
+  }
+  const ids = responses.map((response) => response.id);
+  if (ids.some((id) => id === undefined || id === null) || new Set(ids).size !== calls) {
+    return [` + "`concurrent calls: response ids are missing or shared: ${ids}`" + `];
+  }
+  console.log(` + "`✓ ${calls} concurrent calls passed`" + `);
+  return [];
+}
+
+`)
+}
+
+// writeConcurrentCallsTestCs writes the C# test client method that makes concurrent
+// calls through one transport
+func writeConcurrentCallsTestCs(sb *strings.Builder) {
+	sb.WriteString(`
+    // Calls pulserpc-idl many times at once through one transport and returns a message
+    // if a call fails or two responses share an id
+    private static async Task<List<string>> TestConcurrentCalls(ITransport transport)
+    {
+        const int calls = ` + concurrentTestCalls + `;
+        Dictionary<string, object?>[] responses;
+        try
+        {
+            var tasks = new List<Task<Dictionary<string, object?>>>();
+            for (var i = 0; i < calls; i++)
+            {
+                tasks.Add(Task.Run(() => transport.CallAsync("pulserpc-idl", Array.Empty<object>())));
+            }
+            responses = await Task.WhenAll(tasks);
+        }
+        catch (Exception e)
+        {
+            return new List<string> { $"concurrent calls failed: {e.Message}" };
+        }
+        var ids = new HashSet<string>();
+        foreach (var response in responses)
+        {
+            var id = response.TryGetValue("id", out var value) ? value?.ToString() : null;
+            if (string.IsNullOrEmpty(id) || !ids.Add(id))
+            {
+                return new List<string> { $"concurrent calls: response id '{id}' is missing or shared" };
+            }
+        }
+        Console.WriteLine($"✓ {calls} concurrent calls passed");
+        return new List<string>();
+    }
+`)
+}
+
+// writeConcurrentCallsTestJava writes the Java test client method that makes
+// concurrent calls through one transport
+func writeConcurrentCallsTestJava(sb *strings.Builder) {
+	sb.WriteString(`
+    // Calls pulserpc-idl from many threads at once through one transport and returns
+    // whether every call succeeded with a response id of its own
+    private static boolean testConcurrentCalls(Transport transport) {
+        final int calls = ` + concurrentTestCalls + `;
+        java.util.concurrent.ExecutorService executor = java.util.concurrent.Executors.newFixedThreadPool(calls);
+        try {
+            java.util.List<java.util.concurrent.Future<Response>> futures = new java.util.ArrayList<>();
+            for (int i = 0; i < calls; i++) {
+                futures.add(executor.submit(() -> transport.call(
+                    new Request("pulserpc-idl", new Object[0], java.util.UUID.randomUUID().toString()))));
+            }
+            java.util.Set<Object> ids = new java.util.HashSet<>();
+            for (java.util.concurrent.Future<Response> future : futures) {
+                Object id = future.get().getId();
+                if (id == null || !ids.add(id)) {
+                    System.err.println("✗ concurrent calls: response id " + id + " is missing or shared");
+                    return false;
+                }
+            }
+        } catch (Exception e) {
+            System.err.println("✗ concurrent calls failed: " + e.getMessage());
+            return false;
+        } finally {
+            executor.shutdown();
+        }
+        System.out.println("✓ " + calls + " concurrent calls passed");
+        return true;
+    }
+`)
+}
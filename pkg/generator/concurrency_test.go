@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestConcurrentClientsGenerated(t *testing.T) {
+	idl, err := parser.ParseIDL("shop.pulse", "namespace shop\n\ninterface Orders {\n  place(sku string) string\n}")
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	tests := []struct {
+		plugin Plugin
+		files  map[string][]string
+	}{
+		{NewGoClientServer(), map[string][]string{
+			"client.go":               {"\trequestID := newRequestID()\n", "func newRequestID() string {"},
+			"broker.go":               {"\t\t\"id\":      newRequestID(),\n"},
+			"cmd/test_client/main.go": {"errors = append(errors, testConcurrentCalls(transport)...)"},
+		}},
+		{NewPythonClientServer(), map[string][]string{
+			"test_client.py": {"errors.extend(test_concurrent_calls(transport))"},
+		}},
+		{NewTSClientServer(), map[string][]string{
+			"test_client.ts": {"errors.push(...(await testConcurrentCalls(transport)));"},
+		}},
+		{NewCSharpClientServer(), map[string][]string{
+			"Client.cs":     {"_httpClient = httpClient ?? SharedHttpClient;"},
+			"TestClient.cs": {"errors.AddRange(await TestConcurrentCalls(transport));"},
+		}},
+		{NewJavaClientServer(), map[string][]string{
+			"src/test/java/com/example/TestClient.java": {"if (!testConcurrentCalls(transport)) {"},
+		}},
+	}
+	for _, tt := range tests {
+		tmpDir := t.TempDir()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		fs.Bool("generate-test-files", false, "generate test files")
+		fs.Bool("generate-broker-transport", false, "generate broker transport")
+		tt.plugin.RegisterFlags(fs)
+		for name, value := range map[string]string{"dir": tmpDir, "base-package": "com.example", "generate-test-files": "true", "generate-broker-transport": "true"} {
+			if fs.Lookup(name) != nil {
+				if err := fs.Set(name, value); err != nil {
+					t.Fatalf("failed to set %s flag: %v", name, err)
+				}
+			}
+		}
+		if err := tt.plugin.Generate(idl, fs); err != nil {
+			t.Fatalf("%s: Generate failed: %v", tt.plugin.Name(), err)
+		}
+		for file, wants := range tt.files {
+			content, err := os.ReadFile(filepath.Join(tmpDir, file))
+			if err != nil {
+				t.Fatalf("%s: expected %s: %v", tt.plugin.Name(), file, err)
+			}
+			for _, want := range wants {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("%s: %s does not contain %q", tt.plugin.Name(), file, want)
+				}
+			}
+		}
+	}
+}
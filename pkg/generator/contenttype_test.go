@@ -0,0 +1,193 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+const contentTypeIDL = `namespace calc
+
+interface Calculator {
+  add(a int, b int) int
+}`
+
+// The checks below post Calculator.add to a strict and a lenient server with each of
+// these Content-Types, in order, and print the HTTP status and error code (0 if none).
+// An empty Content-Type means the header is not sent.
+//
+//	""
+//	"application/x-www-form-urlencoded"
+//	"application/json; charset=utf-8"
+//	"application/json; charset=latin-1"
+//	"application/vnd.calc+json"
+const contentTypeWant = `strict 415 -32600
+strict 415 -32600
+strict 200 0
+strict 415 -32600
+strict 200 0
+lenient 200 0
+lenient 200 0
+lenient 200 0
+lenient 200 0
+lenient 200 0`
+
+const contentTypeGoMain = `package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+
+	calc "example.com/calc"
+)
+
+type calculator struct{}
+
+func (calculator) Add(ctx context.Context, a int, b int) (int, error) {
+	return a + b, nil
+}
+
+func main() {
+	contentTypes := []string{"", "application/x-www-form-urlencoded", "application/json; charset=utf-8", "application/json; charset=latin-1", "application/vnd.calc+json"}
+	for _, strict := range []bool{true, false} {
+		server := calc.NewPulseRPCServer("localhost", 0)
+		server.RegisterCalculator(calculator{})
+		server.SetStrictContentType(strict)
+		mode := map[bool]string{true: "strict", false: "lenient"}[strict]
+		for _, contentType := range contentTypes {
+			r := httptest.NewRequest("POST", "/", strings.NewReader(` + "`" + `{"jsonrpc":"2.0","method":"Calculator.add","params":[1,2],"id":1}` + "`" + `))
+			if contentType != "" {
+				r.Header.Set("Content-Type", contentType)
+			}
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, r)
+			var response struct {
+				Error *struct{ Code int } ` + "`json:\"error\"`" + `
+			}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			code := 0
+			if response.Error != nil {
+				code = response.Error.Code
+			}
+			fmt.Println(mode, w.Code, code)
+		}
+	}
+}
+`
+
+func TestContentTypeGo(t *testing.T) {
+	dir := generateForTest(t, NewGoClientServer(), contentTypeIDL)
+	if out := runGoCheck(t, dir, "example.com/calc", contentTypeGoMain); out != contentTypeWant {
+		t.Errorf("got:\n%s\nwant:\n%s", out, contentTypeWant)
+	}
+}
+
+const contentTypePythonCheck = `import http.client
+import json
+from server import PulseRPCServer
+
+class Calculator:
+    def add(self, a, b):
+        return a + b
+
+content_types = ['', 'application/x-www-form-urlencoded', 'application/json; charset=utf-8', 'application/json; charset=latin-1', 'application/vnd.calc+json']
+for strict in (True, False):
+    server = PulseRPCServer(strict_content_type=strict)
+    server.register('Calculator', Calculator())
+    for content_type in content_types:
+        headers = http.client.HTTPMessage()
+        if content_type:
+            headers['Content-Type'] = content_type
+        body = b'{"jsonrpc":"2.0","method":"Calculator.add","params":[1,2],"id":1}'
+        status, _, response = server.handle_http('POST', '/', headers, body)
+        error = json.loads(response).get('error')
+        print('strict' if strict else 'lenient', status, error['code'] if error else 0)
+`
+
+func TestContentTypePython(t *testing.T) {
+	dir := generateForTest(t, NewPythonClientServer(), contentTypeIDL)
+	if out := runPythonCheck(t, dir, contentTypePythonCheck); out != contentTypeWant {
+		t.Errorf("got:\n%s\nwant:\n%s", out, contentTypeWant)
+	}
+}
+
+const contentTypeCSharpProgram = `using System.Text.Json;
+using PulseRPC;
+
+var builder = WebApplication.CreateBuilder();
+builder.WebHost.UseUrls("http://127.0.0.1:0");
+builder.Logging.ClearProviders();
+var app = builder.Build();
+foreach (var strict in new[] { true, false })
+{
+    var server = new PulseRPCServer { StrictContentType = strict };
+    server.RegisterCalculator(new Calculator());
+    server.MapEndpoints(app, strict ? "/strict" : "/lenient");
+}
+await app.StartAsync();
+
+using var http = new HttpClient { BaseAddress = new Uri(app.Urls.First()) };
+var contentTypes = new[] { "", "application/x-www-form-urlencoded", "application/json; charset=utf-8", "application/json; charset=latin-1", "application/vnd.calc+json" };
+foreach (var mode in new[] { "strict", "lenient" })
+{
+    foreach (var contentType in contentTypes)
+    {
+        var content = new ByteArrayContent(System.Text.Encoding.UTF8.GetBytes("{\"jsonrpc\":\"2.0\",\"method\":\"Calculator.add\",\"params\":[1,2],\"id\":1}"));
+        if (contentType != "")
+        {
+            content.Headers.TryAddWithoutValidation("Content-Type", contentType);
+        }
+        var response = await http.PostAsync("/" + mode, content);
+        using var body = JsonDocument.Parse(await response.Content.ReadAsStringAsync());
+        var code = body.RootElement.TryGetProperty("error", out var error) ? error.GetProperty("code").GetInt32() : 0;
+        Console.WriteLine($"{mode} {(int)response.StatusCode} {code}");
+    }
+}
+await app.StopAsync();
+
+class Calculator : ICalculator
+{
+    public int add(int a, int b) => a + b;
+}
+`
+
+func TestContentTypeCSharp(t *testing.T) {
+	dir := generateForTest(t, NewCSharpClientServer(), contentTypeIDL)
+	if out := runDotnetCheck(t, dir, contentTypeCSharpProgram); out != contentTypeWant {
+		t.Errorf("got:\n%s\nwant:\n%s", out, contentTypeWant)
+	}
+}
+
+// TestContentTypeGenerated checks that the servers that cannot be run by these tests
+// only check the Content-Type in strict mode
+func TestContentTypeGenerated(t *testing.T) {
+	tests := []struct {
+		plugin Plugin
+		args   []string
+		files  map[string][]string
+	}{
+		{NewJavaClientServer(), []string{"-base-package=com.example"}, map[string][]string{
+			"src/main/java/com/example/Server.java": {`String problem = strictContentType ? checkContentType(exchange.getRequestHeaders().getFirst("Content-Type")) : null;`},
+		}},
+		{NewTSClientServer(), nil, map[string][]string{
+			"server.ts": {"const problem = this.strictContentType ? checkContentType(req.headers['content-type']) : null;"},
+		}},
+		{NewRustClientServer(), nil, map[string][]string{
+			"src/server.rs":         {"pub fn set_strict_content_type(&mut self, strict: bool) {", "Arc::new(self.dispatcher), self.strict_content_type)"},
+			"src/pulserpc/serve.rs": {"let problem = if strict_content_type {"},
+		}},
+	}
+	for _, tt := range tests {
+		dir := generateForTest(t, tt.plugin, contentTypeIDL, tt.args...)
+		for file, wants := range tt.files {
+			content := readGenerated(t, dir, file)
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("%s: %s does not contain %q", tt.plugin.Name(), file, want)
+				}
+			}
+		}
+	}
+}
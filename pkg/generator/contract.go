@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// The -generate-contract-tests flag emits consumer contract tests from the @example
+// blocks of the IDL, in each language's test framework (go test, pytest, node:test,
+// xUnit, JUnit 5). Each test sends the params of one example to the service at the
+// URL in PULSERPC_CONTRACT_URL through the generated HTTP transport, and fails if
+// the call fails or its result is not equal to the example's result as JSON. The
+// tests are skipped when the variable is not set, so they can live in a consumer's
+// regular test suite and only run against a provider when one is given.
+//
+// Nothing is written for an IDL without examples.
+
+// contractURLEnv is the environment variable contract tests read the service URL from
+const contractURLEnv = "PULSERPC_CONTRACT_URL"
+
+// contractTestsRequested reports whether the -generate-contract-tests flag is set
+func contractTestsRequested(fs *flag.FlagSet) bool {
+	f := fs.Lookup("generate-contract-tests")
+	return f != nil && f.Value.String() == "true"
+}
+
+// contractView is the view model of a contract test file
+type contractView struct {
+	// URLEnv is the environment variable the service URL is read from
+	URLEnv     string
+	Interfaces []contractInterface
+}
+
+// contractInterface is an interface with examples
+type contractInterface struct {
+	// Name is the interface name
+	Name string
+	// Ident is the interface's base name converted for use in identifiers
+	Ident string
+	Calls []contractCall
+}
+
+// contractCall is the call of one example
+type contractCall struct {
+	// Method is the JSON-RPC method name
+	Method string
+	// Ident is the method name and the number of the example, such as add_example_1,
+	// converted for use in identifiers
+	Ident string
+	// Params is the example's params as a JSON array in parameter order, and
+	// Result its result as JSON. Both are double quoted string literals, valid in
+	// every target language since JSON text has no control characters.
+	Params string
+	Result string
+}
+
+// buildContractView returns the examples of every interface that has any. ident
+// converts interface base names and method names to identifiers.
+func buildContractView(idl *parser.IDL, ident func(string) string) contractView {
+	view := contractView{URLEnv: contractURLEnv}
+	for _, iface := range idl.Interfaces {
+		ci := contractInterface{Name: iface.Name, Ident: ident(GetBaseName(iface.Name))}
+		for _, method := range iface.Methods {
+			for i, example := range method.Examples {
+				params, _ := json.Marshal(example.ParamList(method))
+				ci.Calls = append(ci.Calls, contractCall{
+					Method: iface.RPCName(method),
+					Ident:  ident(fmt.Sprintf("%s_example_%d", method.Name, i+1)),
+					Params: strconv.Quote(string(params)),
+					Result: strconv.Quote(string(example.Result)),
+				})
+			}
+		}
+		if len(ci.Calls) > 0 {
+			view.Interfaces = append(view.Interfaces, ci)
+		}
+	}
+	return view
+}
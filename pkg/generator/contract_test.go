@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/naming"
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func contractTestIDL() *parser.IDL {
+	return &parser.IDL{
+		RootNamespace: "calc",
+		Interfaces: []*parser.Interface{
+			{
+				Name: "Calculator",
+				Methods: []*parser.Method{
+					{
+						Name: "scale",
+						Parameters: []*parser.Parameter{
+							{Name: "value", Type: &parser.Type{BuiltIn: "float"}},
+							{Name: "unit", Type: &parser.Type{BuiltIn: "string"}, Optional: true},
+							{Name: "factor", Type: &parser.Type{BuiltIn: "int"}, Optional: true},
+						},
+						ReturnType: &parser.Type{BuiltIn: "float"},
+						Examples: []*parser.Example{
+							{Params: json.RawMessage(`[1.5, "m"]`), Result: json.RawMessage(`1.5`)},
+							{Params: json.RawMessage(`{"factor": 2, "value": 1.5}`), Result: json.RawMessage(`3`)},
+						},
+					},
+				},
+			},
+			{
+				Name:    "Health",
+				Methods: []*parser.Method{{Name: "ping", ReturnType: &parser.Type{BuiltIn: "bool"}}},
+			},
+		},
+	}
+}
+
+func TestBuildContractView(t *testing.T) {
+	view := buildContractView(contractTestIDL(), naming.SnakeToPascal)
+	if view.URLEnv != "PULSERPC_CONTRACT_URL" {
+		t.Errorf("URLEnv = %q", view.URLEnv)
+	}
+	// Interfaces without examples are left out
+	if len(view.Interfaces) != 1 || view.Interfaces[0].Ident != "Calculator" {
+		t.Fatalf("expected only Calculator, got %+v", view.Interfaces)
+	}
+	calls := view.Interfaces[0].Calls
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(calls))
+	}
+	want := []contractCall{
+		{Method: "Calculator.scale", Ident: "ScaleExample1", Params: `"[1.5,\"m\"]"`, Result: `"1.5"`},
+		// Named params are sent by position, with null for a skipped optional one
+		{Method: "Calculator.scale", Ident: "ScaleExample2", Params: `"[1.5,null,2]"`, Result: `"3"`},
+	}
+	for i, call := range calls {
+		if call != want[i] {
+			t.Errorf("call %d = %+v, want %+v", i, call, want[i])
+		}
+	}
+}
+
+func TestContractTestsFlag(t *testing.T) {
+	tmpDir := t.TempDir()
+	p := NewGoClientServer()
+	fs := newTestFlagSet(t, p, tmpDir, "-generate-contract-tests=true")
+	if err := p.Generate(contractTestIDL(), fs); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	code, err := os.ReadFile(filepath.Join(tmpDir, "contract_test.go"))
+	if err != nil {
+		t.Fatalf("failed to read contract_test.go: %v", err)
+	}
+	for _, want := range []string{
+		"func TestCalculatorContract(t *testing.T) {",
+		`t.Run("ScaleExample2", func(t *testing.T) {`,
+		`contractCall(t, "Calculator.scale", "[1.5,null,2]", "3")`,
+		`os.Getenv("PULSERPC_CONTRACT_URL")`,
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("contract_test.go missing %q", want)
+		}
+	}
+	if strings.Contains(string(code), "Health") {
+		t.Errorf("contract_test.go has tests for Health, which has no examples")
+	}
+
+	// Nothing is written for an IDL without examples
+	emptyDir := t.TempDir()
+	if err := fs.Set("dir", emptyDir); err != nil {
+		t.Fatalf("failed to set dir flag: %v", err)
+	}
+	idl := contractTestIDL()
+	idl.Interfaces[0].Methods[0].Examples = nil
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(emptyDir, "contract_test.go")); !os.IsNotExist(err) {
+		t.Errorf("expected no contract_test.go without examples, got %v", err)
+	}
+}
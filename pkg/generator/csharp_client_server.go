@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 
+	"github.com/coopernurse/pulserpc/pkg/naming"
 	"github.com/coopernurse/pulserpc/pkg/parser"
 	"github.com/coopernurse/pulserpc/pkg/runtime"
 )
@@ -33,10 +35,34 @@ func (p *CSharpClientServer) RegisterFlags(fs *flag.FlagSet) {
 	if fs.Lookup("base-dir") == nil {
 		fs.String("base-dir", "", "Base directory for namespace packages/modules (defaults to -dir if not specified)")
 	}
+	registerNamespaceDirsFlag(fs)
+	fs.String("visibility", "public", "Visibility of generated types: 'public' or 'internal' (hides them from other assemblies)")
+}
+
+// csharpPublicTypePattern matches the declaration of a public class, record,
+// interface, enum or struct, nested or not
+var csharpPublicTypePattern = regexp.MustCompile(`(?m)^(\s*)public ((?:(?:static|sealed|abstract|partial|readonly) )*(?:class|record|interface|enum|struct) )`)
+
+// applyCSharpVisibility declares the types in code internal when visibility is
+// "internal". Every generated type changes together, so no public member exposes an
+// internal type. The runtime library in PulseRPC/ stays public; it does not refer
+// to generated types.
+func applyCSharpVisibility(code, visibility string) string {
+	if visibility != "internal" {
+		return code
+	}
+	return csharpPublicTypePattern.ReplaceAllString(code, "${1}internal $2")
 }
 
 // Generate generates C# HTTP server and client code from the parsed IDL
-func (p *CSharpClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
+func (p *CSharpClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) (err error) {
+	ts, err := templatesFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	defer recoverTemplateError(&err)
+	start := repoFilesStart()
+
 	// Access the -dir flag value
 	dirFlag := fs.Lookup("dir")
 	outputDir := ""
@@ -51,6 +77,27 @@ func (p *CSharpClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 		baseDir = baseDirFlag.Value.String()
 	}
 
+	// Types are emitted by base name, so names must be unique across namespaces
+	if err := CheckBaseNameCollisions(idl); err != nil {
+		return err
+	}
+
+	visibility := "public"
+	if f := fs.Lookup("visibility"); f != nil && f.Value.String() != "" {
+		visibility = f.Value.String()
+	}
+	if visibility != "public" && visibility != "internal" {
+		return fmt.Errorf("invalid -visibility %q: must be 'public' or 'internal'", visibility)
+	}
+	style, err := parseCodeStyle(fs, "csharp")
+	if err != nil {
+		return err
+	}
+	if usesEncryptedFields(idl) {
+		// Generating without the hooks would send the fields in plaintext
+		return fmt.Errorf("[encrypted] fields are not supported by the C# generator yet")
+	}
+
 	// Build type registries
 	structMap := make(map[string]*parser.Struct)
 	enumMap := make(map[string]*parser.Enum)
@@ -84,20 +131,35 @@ func (p *CSharpClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 	sort.Strings(namespaces)
 
 	// Generate Contract.cs (shared interfaces and IdlData)
-	contractCode := generateContractCs(idl, structMap, enumMap, namespaceMap)
+	contractCode := generateContractCs(ts, idl, structMap, enumMap, namespaceMap)
 	contractPath := filepath.Join(outputDir, "Contract.cs")
-	if err := os.WriteFile(contractPath, []byte(contractCode), 0644); err != nil {
+	if err := writeGeneratedFile(contractPath, []byte(applyCSharpVisibility(contractCode, visibility))); err != nil {
 		return fmt.Errorf("failed to write Contract.cs: %w", err)
 	}
 
+	// Generate GlobalUsings.cs, importing every namespace into the project
+	if indexFilesRequested(fs) {
+		usingsCode := generateGlobalUsingsCs(buildNamespaceIndex(namespaceMap))
+		if err := writeGeneratedFile(filepath.Join(outputDir, "GlobalUsings.cs"), []byte(usingsCode)); err != nil {
+			return fmt.Errorf("failed to write GlobalUsings.cs: %w", err)
+		}
+	}
+
 	// Generate one file per namespace
+	nsDirs, err := loadNamespaceDirs(fs, baseDir, namespaceMap)
+	if err != nil {
+		return err
+	}
 	for namespace, types := range namespaceMap {
 		if namespace == "" {
 			continue // Skip types without namespace (shouldn't happen with required namespaces)
 		}
-		namespaceCode := generateNamespaceCs(namespace, namespaces, types, structMap, enumMap)
-		namespacePath := filepath.Join(baseDir, snakeToPascalCase(namespace)+".cs")
-		if err := os.WriteFile(namespacePath, []byte(namespaceCode), 0644); err != nil {
+		namespaceCode := generateNamespaceCs(namespace, namespaces, types, structMap, enumMap, optionalPresenceRequested(fs))
+		namespacePath := filepath.Join(nsDirs.Dir(namespace), naming.SnakeToPascal(namespace)+".cs")
+		if err := os.MkdirAll(filepath.Dir(namespacePath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s.cs: %w", namespace, err)
+		}
+		if err := writeGeneratedFile(namespacePath, []byte(applyCSharpVisibility(namespaceCode, visibility))); err != nil {
 			return fmt.Errorf("failed to write %s.cs: %w", namespace, err)
 		}
 	}
@@ -109,55 +171,180 @@ func (p *CSharpClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 	}
 
 	// Generate Server.cs
-	serverCode := generateServerCs(idl, namespaceMap, string(jsonData))
+	serverCode := generateServerCs(ts, idl, namespaceMap, string(jsonData))
 	serverPath := filepath.Join(outputDir, "Server.cs")
-	if err := os.WriteFile(serverPath, []byte(serverCode), 0644); err != nil {
+	if err := writeGeneratedFile(serverPath, []byte(applyCSharpVisibility(serverCode, visibility))); err != nil {
 		return fmt.Errorf("failed to write Server.cs: %w", err)
 	}
 
 	// Generate Client.cs
-	clientCode := generateClientCs(idl, structMap, enumMap, namespaceMap)
+	clientCode := generateClientCs(ts, idl, structMap, enumMap, namespaceMap)
 	clientPath := filepath.Join(outputDir, "Client.cs")
-	if err := os.WriteFile(clientPath, []byte(clientCode), 0644); err != nil {
+	if err := writeGeneratedFile(clientPath, []byte(applyCSharpVisibility(clientCode, visibility))); err != nil {
 		return fmt.Errorf("failed to write Client.cs: %w", err)
 	}
 
+	// Generate Discovery.cs next to the client
+	discoveryCode := ts.renderString("csharp/Discovery.cs.tmpl", discoveryView{})
+	if err := writeGeneratedFile(filepath.Join(outputDir, "Discovery.cs"), []byte(applyCSharpVisibility(discoveryCode, visibility))); err != nil {
+		return fmt.Errorf("failed to write Discovery.cs: %w", err)
+	}
+
+	// Generate Signing.cs next to the client
+	signingCode := ts.renderString("csharp/Signing.cs.tmpl", signingView{})
+	if err := writeGeneratedFile(filepath.Join(outputDir, "Signing.cs"), []byte(applyCSharpVisibility(signingCode, visibility))); err != nil {
+		return fmt.Errorf("failed to write Signing.cs: %w", err)
+	}
+
+	// Generate Retry.cs next to the client
+	retryCode := ts.renderString("csharp/Retry.cs.tmpl", retryView{Methods: idempotentMethods(idl)})
+	if err := writeGeneratedFile(filepath.Join(outputDir, "Retry.cs"), []byte(applyCSharpVisibility(retryCode, visibility))); err != nil {
+		return fmt.Errorf("failed to write Retry.cs: %w", err)
+	}
+
+	// Generate ShadowTransport.cs next to the client
+	if shadowClientRequested(fs) {
+		shadowCode := ts.renderString("csharp/ShadowTransport.cs.tmpl", shadowView{})
+		if err := writeGeneratedFile(filepath.Join(outputDir, "ShadowTransport.cs"), []byte(applyCSharpVisibility(shadowCode, visibility))); err != nil {
+			return fmt.Errorf("failed to write ShadowTransport.cs: %w", err)
+		}
+	}
+
+	// Generate Outbox.cs next to the client
+	if outboxClientRequested(fs) {
+		outboxCode := ts.renderString("csharp/Outbox.cs.tmpl", outboxView{})
+		if err := writeGeneratedFile(filepath.Join(outputDir, "Outbox.cs"), []byte(applyCSharpVisibility(outboxCode, visibility))); err != nil {
+			return fmt.Errorf("failed to write Outbox.cs: %w", err)
+		}
+	}
+
+	// Generate Patch.cs, shared by the client and the server
+	if patchHelpersRequested(fs) {
+		patchCode := ts.renderString("csharp/Patch.cs.tmpl", patchView{})
+		if err := writeGeneratedFile(filepath.Join(outputDir, "Patch.cs"), []byte(applyCSharpVisibility(patchCode, visibility))); err != nil {
+			return fmt.Errorf("failed to write Patch.cs: %w", err)
+		}
+	}
+
+	// Generate TestData.cs with a test data builder per struct
+	if testBuildersRequested(fs) {
+		testDataCode := generateTestDataCs(namespaceMap, structMap, enumMap)
+		if err := writeGeneratedFile(filepath.Join(outputDir, "TestData.cs"), []byte(applyCSharpVisibility(testDataCode, visibility))); err != nil {
+			return fmt.Errorf("failed to write TestData.cs: %w", err)
+		}
+	}
+
+	// Generate Serverless.cs next to the server
+	serverless := serverlessAdapterRequested(fs)
+	if serverless {
+		serverlessCode := ts.renderString("csharp/Serverless.cs.tmpl", serverlessView{})
+		if err := writeGeneratedFile(filepath.Join(outputDir, "Serverless.cs"), []byte(applyCSharpVisibility(serverlessCode, visibility))); err != nil {
+			return fmt.Errorf("failed to write Serverless.cs: %w", err)
+		}
+	}
+
+	// Write testvectors.json if -generate-test-vectors is set
+	hasTestVectors, err := writeTestVectorsIfRequested(idl, fs, outputDir)
+	if err != nil {
+		return err
+	}
+
 	// Check if generate-test-files flag is set
 	generateTestFilesFlag := fs.Lookup("generate-test-files")
 	generateTestServer := generateTestFilesFlag != nil && generateTestFilesFlag.Value.String() == "true"
+	harness := testHarnessRequested(fs)
+	contractView := buildContractView(idl, naming.SnakeToPascal)
+	contracts := contractTestsRequested(fs) && len(contractView.Interfaces) > 0
+	versions, err := dependencyVersions(fs)
+	if err != nil {
+		return err
+	}
+	mirror, err := dependencyMirrorFlag(fs)
+	if err != nil {
+		return err
+	}
+	if dependencyManifestRequested(fs) {
+		if err := writeCSharpDependencyManifest(outputDir, csharpDependencies(versions, harness || contracts), mirror); err != nil {
+			return err
+		}
+	}
+	if sbomRequested(fs) {
+		if err := writeSBOM(outputDir, idl, "csharp", "PulseRPC", csharpDependencies(versions, harness || contracts), "Microsoft.AspNetCore.App"); err != nil {
+			return err
+		}
+	}
 	if generateTestServer {
 		// Generate TestServer.cs
 		testServerCode := generateTestServerCs(idl, namespaces, structMap, enumMap)
 		testServerPath := filepath.Join(outputDir, "TestServer.cs")
-		if err := os.WriteFile(testServerPath, []byte(testServerCode), 0644); err != nil {
+		if err := writeGeneratedFile(testServerPath, []byte(applyCSharpVisibility(testServerCode, visibility))); err != nil {
 			return fmt.Errorf("failed to write TestServer.cs: %w", err)
 		}
 
 		// Generate TestClient.cs
-		testClientCode := generateTestClientCs(idl, namespaces, structMap, enumMap)
+		testClientCode := generateTestClientCs(idl, namespaces, structMap, enumMap, hasTestVectors)
 		testClientPath := filepath.Join(outputDir, "TestClient.cs")
-		if err := os.WriteFile(testClientPath, []byte(testClientCode), 0644); err != nil {
+		if err := writeGeneratedFile(testClientPath, []byte(applyCSharpVisibility(testClientCode, visibility))); err != nil {
 			return fmt.Errorf("failed to write TestClient.cs: %w", err)
 		}
 
 		// Generate TestServer.csproj
-		testServerProjCode := generateTestServerCsproj()
+		testServerProjCode := generateTestServerCsproj(ts, harness, contracts)
 		testServerProjPath := filepath.Join(outputDir, "TestServer.csproj")
-		if err := os.WriteFile(testServerProjPath, []byte(testServerProjCode), 0644); err != nil {
+		if err := writeGeneratedFile(testServerProjPath, []byte(testServerProjCode)); err != nil {
 			return fmt.Errorf("failed to write TestServer.csproj: %w", err)
 		}
 
 		// Generate TestClient.csproj
-		testClientProjCode := generateTestClientCsproj()
+		testClientProjCode := generateTestClientCsproj(ts, harness, contracts, serverless)
 		testClientProjPath := filepath.Join(outputDir, "TestClient.csproj")
-		if err := os.WriteFile(testClientProjPath, []byte(testClientProjCode), 0644); err != nil {
+		if err := writeGeneratedFile(testClientProjPath, []byte(testClientProjCode)); err != nil {
 			return fmt.Errorf("failed to write TestClient.csproj: %w", err)
 		}
 	}
 
+	// Generate the xUnit handler harness and its test project
+	if harness {
+		view := csharpHarnessView{Namespaces: namespaces, Interfaces: buildHarnessInterfaces(idl, naming.SnakeToPascal)}
+		harnessCode := ts.renderString("csharp/HarnessTests.cs.tmpl", view)
+		if err := writeGeneratedFile(filepath.Join(outputDir, "HarnessTests.cs"), []byte(harnessCode)); err != nil {
+			return fmt.Errorf("failed to write HarnessTests.cs: %w", err)
+		}
+		handlersCode := ts.renderString("csharp/HarnessHandlers.cs.tmpl", view)
+		if err := writeSkeletonFile(filepath.Join(outputDir, "HarnessHandlers.cs"), []byte(applyCSharpVisibility(handlersCode, visibility))); err != nil {
+			return fmt.Errorf("failed to write HarnessHandlers.cs: %w", err)
+		}
+		if err := writeGeneratedFile(filepath.Join(outputDir, "HarnessTests.csproj"), []byte(generateHarnessCsproj(ts, versions, contracts, mirror))); err != nil {
+			return fmt.Errorf("failed to write HarnessTests.csproj: %w", err)
+		}
+	}
+
+	// Generate the xUnit contract tests from the IDL examples and their test project
+	if contracts {
+		contractCode := ts.renderString("csharp/ContractTests.cs.tmpl", contractView)
+		if err := writeGeneratedFile(filepath.Join(outputDir, csharpContractFile), []byte(contractCode)); err != nil {
+			return fmt.Errorf("failed to write ContractTests.cs: %w", err)
+		}
+		if err := writeGeneratedFile(filepath.Join(outputDir, "ContractTests.csproj"), []byte(generateContractCsproj(ts, versions, harness, mirror))); err != nil {
+			return fmt.Errorf("failed to write ContractTests.csproj: %w", err)
+		}
+	}
+
+	if err := restyleGeneratedFiles("csharp", style, append([]string{outputDir}, nsDirs.All()...)...); err != nil {
+		return err
+	}
+	if repoFilesRequested(fs) {
+		return writeRepoFiles("csharp", style, start, append([]string{outputDir}, nsDirs.All()...)...)
+	}
 	return nil
 }
 
+// csharpHarnessView is the view model for HarnessTests.cs and HarnessHandlers.cs
+type csharpHarnessView struct {
+	Namespaces []string
+	Interfaces []harnessInterface
+}
+
 // copyRuntimeFiles copies the C# runtime library files to the output directory
 // Uses embedded runtime files from the binary
 func (p *CSharpClientServer) copyRuntimeFiles(outputDir string) error {
@@ -165,11 +352,12 @@ func (p *CSharpClientServer) copyRuntimeFiles(outputDir string) error {
 }
 
 // generateNamespaceCs generates a C# file for a single namespace
-func generateNamespaceCs(namespace string, allNamespaces []string, types *NamespaceTypes, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) string {
+func generateNamespaceCs(namespace string, allNamespaces []string, types *NamespaceTypes, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, presence bool) string {
 	var sb strings.Builder
 
 	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
 	sb.WriteString("using System.Collections.Generic;\n")
+	sb.WriteString("using System.Linq;\n")
 	sb.WriteString("using System.Text.Json.Serialization;\n")
 	sb.WriteString("using PulseRPC;\n")
 
@@ -188,14 +376,17 @@ func generateNamespaceCs(namespace string, allNamespaces []string, types *Namesp
 	sb.WriteString("\n")
 
 	// Generate struct classes
-	generateStructClassesCs(&sb, types.Structs, structMap, enumMap, "    ")
+	generateStructClassesCs(&sb, types.Structs, structMap, enumMap, "    ", presence)
 	sb.WriteString("\n")
 
 	// Generate IDL-specific type definitions for this namespace
 	sb.WriteString(fmt.Sprintf("    // IDL-specific type definitions for namespace: %s\n", namespace))
 	sb.WriteString(fmt.Sprintf("    public static class %sIdl\n", namespace))
 	sb.WriteString("    {\n")
-	sb.WriteString("        public static readonly Dictionary<string, Dictionary<string, object>> ALL_STRUCTS = new Dictionary<string, Dictionary<string, object>>\n")
+	sb.WriteString("        // Built on first use, so loading the assembly doesn't pay for a large IDL\n")
+	sb.WriteString("        public static Dictionary<string, Dictionary<string, object>> ALL_STRUCTS => _allStructs.Value;\n")
+	sb.WriteString("        public static Dictionary<string, Dictionary<string, object>> ALL_ENUMS => _allEnums.Value;\n\n")
+	sb.WriteString("        private static readonly System.Lazy<Dictionary<string, Dictionary<string, object>>> _allStructs = new System.Lazy<Dictionary<string, Dictionary<string, object>>>(() => new Dictionary<string, Dictionary<string, object>>\n")
 	sb.WriteString("        {\n")
 	for _, s := range types.Structs {
 		sb.WriteString(fmt.Sprintf("            { \"%s\", new Dictionary<string, object>\n", s.Name))
@@ -215,14 +406,17 @@ func generateNamespaceCs(namespace string, allNamespaces []string, types *Namesp
 			if field.Optional {
 				sb.WriteString("                        { \"optional\", true },\n")
 			}
+			if field.IsSensitive() {
+				sb.WriteString("                        { \"sensitive\", true },\n")
+			}
 			sb.WriteString("                    },\n")
 		}
 		sb.WriteString("                }},\n")
 		sb.WriteString("            }},\n")
 	}
-	sb.WriteString("        };\n\n")
+	sb.WriteString("        });\n\n")
 
-	sb.WriteString("        public static readonly Dictionary<string, Dictionary<string, object>> ALL_ENUMS = new Dictionary<string, Dictionary<string, object>>\n")
+	sb.WriteString("        private static readonly System.Lazy<Dictionary<string, Dictionary<string, object>>> _allEnums = new System.Lazy<Dictionary<string, Dictionary<string, object>>>(() => new Dictionary<string, Dictionary<string, object>>\n")
 	sb.WriteString("        {\n")
 	for _, e := range types.Enums {
 		sb.WriteString(fmt.Sprintf("            { \"%s\", new Dictionary<string, object>\n", e.Name))
@@ -238,7 +432,7 @@ func generateNamespaceCs(namespace string, allNamespaces []string, types *Namesp
 		sb.WriteString("                }},\n")
 		sb.WriteString("            }},\n")
 	}
-	sb.WriteString("        };\n")
+	sb.WriteString("        });\n")
 	sb.WriteString("    }\n")
 	sb.WriteString("}\n")
 
@@ -304,6 +498,16 @@ func mapTypeToCsType(t *parser.Type, structMap map[string]*parser.Struct, enumMa
 	return "object"
 }
 
+// mapParamTypeToCsType maps a parameter's type to C#. Optional parameters are
+// nullable so that a left-out parameter can be passed as null.
+func mapParamTypeToCsType(param *parser.Parameter, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) string {
+	t := mapTypeToCsType(param.Type, structMap, enumMap, param.Optional)
+	if param.Optional && !strings.HasSuffix(t, "?") {
+		t += "?"
+	}
+	return t
+}
+
 // getStructClassName returns the C# class name for a struct
 // Handles qualified names (e.g., "inc.Response" -> "Response")
 func getStructClassName(structName string, structMap map[string]*parser.Struct) string {
@@ -361,19 +565,6 @@ func getStructOrEnumTypeName(typeName string, structMap map[string]*parser.Struc
 	return baseName
 }
 
-// snakeToPascalCase converts snake_case to PascalCase
-// Example: "to_repeat" -> "ToRepeat"
-func snakeToPascalCase(s string) string {
-	parts := strings.Split(s, "_")
-	result := ""
-	for _, part := range parts {
-		if len(part) > 0 {
-			result += strings.ToUpper(part[:1]) + part[1:]
-		}
-	}
-	return result
-}
-
 // generateEnumTypesCs generates C# enum types for all enums in the namespace
 func generateEnumTypesCs(sb *strings.Builder, enums []*parser.Enum, prefix string) {
 	for _, e := range enums {
@@ -403,7 +594,7 @@ func generateEnumTypesCs(sb *strings.Builder, enums []*parser.Enum, prefix strin
 }
 
 // generateStructClassesCs generates C# classes for all structs in the namespace
-func generateStructClassesCs(sb *strings.Builder, structs []*parser.Struct, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, prefix string) {
+func generateStructClassesCs(sb *strings.Builder, structs []*parser.Struct, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, prefix string, presence bool) {
 	for _, s := range structs {
 		if s.Comment != "" {
 			lines := strings.Split(strings.TrimSpace(s.Comment), "\n")
@@ -441,112 +632,176 @@ func generateStructClassesCs(sb *strings.Builder, structs []*parser.Struct, stru
 
 			// Property type
 			csType := mapTypeToCsType(field.Type, structMap, enumMap, field.Optional)
+			if field.Optional && presence {
+				// Absent is the default value, so it is left out of the JSON
+				fmt.Fprintf(sb, "%s    [JsonIgnore(Condition = JsonIgnoreCondition.WhenWritingDefault)]\n", prefix)
+				csType = "Optional<" + csType + ">"
+			}
 
 			// Property name in PascalCase
-			propName := snakeToPascalCase(field.Name)
+			propName := naming.SnakeToPascal(field.Name)
 
 			// Generate property
+			// Immutable properties are set by object initializers and the JSON
+			// deserializer only
+			accessor := "set"
+			if s.IsImmutable() {
+				accessor = "init"
+			}
 			sb.WriteString(prefix + "    public ")
-			fmt.Fprintf(sb, "%s %s { get; set; }\n\n", csType, propName)
+			fmt.Fprintf(sb, "%s %s { get; %s; }\n\n", csType, propName, accessor)
+		}
+
+		writeCloneMembersCs(sb, s, structMap, prefix, presence)
+		if needsRedaction(s, structMap) {
+			sb.WriteString("\n")
+			writeToStringCs(sb, s, prefix)
 		}
 
 		sb.WriteString(prefix + "}\n\n")
 	}
 }
 
-func generateContractCs(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, namespaceMap map[string]*NamespaceTypes) string {
-	var sb strings.Builder
-
-	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
-	sb.WriteString("using System.Collections.Generic;\n")
-	sb.WriteString("using PulseRPC;\n\n")
+// csContractView is the view model for Contract.cs
+type csContractView struct {
+	Namespaces  []string
+	MethodTable string
+	Interfaces  []csInterfaceView
+}
 
-	// Import from namespace files
-	namespaces := make([]string, 0, len(namespaceMap))
-	for ns := range namespaceMap {
-		if ns != "" {
-			namespaces = append(namespaces, ns)
-		}
-	}
-	sort.Strings(namespaces)
+// csInterfaceView is the view model for the C# interface of an IDL interface
+type csInterfaceView struct {
+	Name         string
+	CommentLines []string
+	// Extends are the extended interfaces, which declare the inherited methods
+	Extends string
+	Methods []csMethodView
+}
 
-	for _, ns := range namespaces {
-		sb.WriteString(fmt.Sprintf("using %s;\n", ns))
-	}
-	sb.WriteString("\n")
+type csMethodView struct {
+	Name       string
+	ReturnType string
+	Params     []csParamView
+}
 
-	sb.WriteString("namespace PulseRPC\n")
-	sb.WriteString("{\n")
+type csParamView struct {
+	Name string
+	Type string
+}
 
-	// Merge ALL_STRUCTS and ALL_ENUMS from all namespaces
-	sb.WriteString("    public static class IdlData\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        public static Dictionary<string, Dictionary<string, object>> ALL_STRUCTS = new Dictionary<string, Dictionary<string, object>>();\n")
-	sb.WriteString("        public static Dictionary<string, Dictionary<string, object>> ALL_ENUMS = new Dictionary<string, Dictionary<string, object>>();\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        static IdlData()\n")
-	sb.WriteString("        {\n")
-	for _, ns := range namespaces {
-		sb.WriteString(fmt.Sprintf("            foreach (var kvp in %s.%sIdl.ALL_STRUCTS) ALL_STRUCTS[kvp.Key] = kvp.Value;\n", ns, ns))
-		sb.WriteString(fmt.Sprintf("            foreach (var kvp in %s.%sIdl.ALL_ENUMS) ALL_ENUMS[kvp.Key] = kvp.Value;\n", ns, ns))
+// newCsInterfaceView returns the view of the C# interface of iface
+func newCsInterfaceView(iface *parser.Interface, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) csInterfaceView {
+	view := csInterfaceView{Name: iface.Name}
+	if iface.Comment != "" {
+		view.CommentLines = strings.Split(strings.TrimSpace(iface.Comment), "\n")
 	}
-	sb.WriteString("        }\n")
-	sb.WriteString("    }\n\n")
-
-	// Generate interface definitions
-	for _, iface := range idl.Interfaces {
-		writeInterfaceStubCs(&sb, iface, structMap, enumMap)
+	parents := make([]string, len(iface.Extends))
+	for i, parent := range iface.Extends {
+		parents[i] = "I" + parent
 	}
-
-	sb.WriteString("}\n")
-
-	return sb.String()
-}
-
-// generateServerCs generates the Server.cs file with HTTP server and interface stubs
-// This is a large function - implementing step by step
-func generateServerCs(idl *parser.IDL, namespaceMap map[string]*NamespaceTypes, idlJson string) string {
-	var sb strings.Builder
-
-	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
-	sb.WriteString("using System;\n")
-	sb.WriteString("using System.Collections.Generic;\n")
-	sb.WriteString("using System.Linq;\n")
-	sb.WriteString("using System.Net;\n")
-	sb.WriteString("using System.Text.Json;\n")
-	sb.WriteString("using System.Text.Json.Serialization;\n")
-	sb.WriteString("using System.Threading.Tasks;\n")
-	sb.WriteString("using Microsoft.AspNetCore.Builder;\n")
-	sb.WriteString("using Microsoft.AspNetCore.Http;\n")
-	sb.WriteString("using Microsoft.Extensions.Logging;\n")
-	sb.WriteString("using Microsoft.Extensions.DependencyInjection;\n")
-	sb.WriteString("using PulseRPC;\n\n")
-
-	// Import from namespace files
-	namespaces := make([]string, 0, len(namespaceMap))
-	for ns := range namespaceMap {
-		if ns != "" {
-			namespaces = append(namespaces, ns)
+	view.Extends = strings.Join(parents, ", ")
+	for _, method := range iface.OwnMethods() {
+		mv := csMethodView{Name: method.Name, ReturnType: "object"}
+		if method.ReturnType != nil {
+			mv.ReturnType = mapTypeToCsType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
+		}
+		for _, param := range method.Parameters {
+			mv.Params = append(mv.Params, csParamView{Name: param.Name, Type: mapParamTypeToCsType(param, structMap, enumMap)})
 		}
+		view.Methods = append(view.Methods, mv)
 	}
-	sort.Strings(namespaces)
+	return view
+}
 
-	for _, ns := range namespaces {
-		// Namespace files define static classes like "checkoutIdl" in the "PulseRPC" namespace
-		sb.WriteString(fmt.Sprintf("using static %s.%sIdl;\n", ns, ns))
-		sb.WriteString(fmt.Sprintf("using %s;\n", ns))
+// generateContractCs generates the Contract.cs file with the merged registries and
+// the interface of each IDL interface
+func generateContractCs(ts *templateSet, idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, namespaceMap map[string]*NamespaceTypes) string {
+	view := csContractView{
+		Namespaces:  sortedNamespaces(namespaceMap),
+		MethodTable: capture(func(sb *strings.Builder) { writeMethodTableCs(sb, idl.Interfaces) }),
 	}
-	sb.WriteString("\n")
-
-	sb.WriteString("namespace PulseRPC\n")
-	sb.WriteString("{\n")
-
-	// Generate PulseRPCServer class
-	writePulseRPCServerCs(&sb, idl, idlJson)
+	for _, iface := range idl.Interfaces {
+		view.Interfaces = append(view.Interfaces, newCsInterfaceView(iface, structMap, enumMap))
+	}
+	return ts.renderString("csharp/Contract.cs.tmpl", view)
+}
 
-	sb.WriteString("}\n")
+// csServerView is the view model for Server.cs
+type csServerView struct {
+	Namespaces []string
+	Interfaces []csInterfaceView
+	// IDLJSON is the IDL document as a C# verbatim string literal
+	IDLJSON       string
+	SubInterfaces []subInterfaceView
+	RESTRoutes    []restRouteView
+	// ParamTuple is the element type of a ReadOnlyRoute's parameter list
+	ParamTuple string
+	// WriteJSON is the statement that writes a buffered response
+	WriteJSON string
+
+	// IDL features that add code to the server
+	AsyncJobs            bool
+	Cached               bool
+	Compressed           bool
+	InterfaceInheritance bool
+	OptionalParams       bool
+	WireNames            bool
+
+	// Sections written by the generators of optional features, empty when unused
+	RequestMeta       string
+	WireMethods       string
+	CompressedMethods string
+	Capabilities      string
+	Compose           string
+	Canonical         string
+	Compression       string
+	JobsServer        string
+	CacheHelpers      string
+}
 
-	return sb.String()
+// generateServerCs generates the Server.cs file with the PulseRPCServer class
+func generateServerCs(ts *templateSet, idl *parser.IDL, namespaceMap map[string]*NamespaceTypes, idlJson string) string {
+	interfaces := idl.Interfaces
+	view := csServerView{
+		Namespaces:           sortedNamespaces(namespaceMap),
+		IDLJSON:              escapeCSharpVerbatimString(idlJson),
+		SubInterfaces:        newSubInterfaceViews(interfaces),
+		RESTRoutes:           newRESTRouteViews(interfaces, writeTypeDictCs),
+		ParamTuple:           "(string Name, Dictionary<string, object> Type)",
+		WriteJSON:            "await WriteJsonBytes(context, output);",
+		AsyncJobs:            usesAsyncMethods(interfaces),
+		Cached:               usesCachedMethods(interfaces),
+		Compressed:           usesCompressedMethods(interfaces),
+		InterfaceInheritance: usesInterfaceInheritance(interfaces),
+		OptionalParams:       usesOptionalParams(interfaces),
+		WireNames:            usesWireNames(interfaces),
+	}
+	for _, iface := range interfaces {
+		view.Interfaces = append(view.Interfaces, csInterfaceView{Name: iface.Name})
+	}
+	if view.OptionalParams {
+		view.ParamTuple = "(string Name, Dictionary<string, object> Type, bool Optional)"
+	}
+
+	view.RequestMeta = capture(writeRequestMetaCs)
+	view.Capabilities = capture(func(sb *strings.Builder) { writeServerCapabilitiesCs(sb, interfaces) })
+	view.Compose = capture(func(sb *strings.Builder) { writeComposeServerCs(sb, interfaces) })
+	view.Canonical = capture(writeCanonicalServerCs)
+	if view.WireNames {
+		view.WireMethods = capture(func(sb *strings.Builder) { writeWireMethodsCs(sb, interfaces) })
+	}
+	if view.Compressed {
+		view.WriteJSON = "await WriteJsonBytes(context, output, compressThreshold);"
+		view.CompressedMethods = capture(func(sb *strings.Builder) { writeCompressedMethodsCs(sb, interfaces) })
+		view.Compression = capture(writeCompressionCs)
+	}
+	if view.AsyncJobs {
+		view.JobsServer = capture(writeJobsServerCs)
+	}
+	if view.Cached {
+		view.CacheHelpers = capture(writeCacheHelpersCs)
+	}
+	return ts.renderString("csharp/Server.cs.tmpl", view)
 }
 
 // escapeCSharpVerbatimString escapes a string for use as a C# verbatim string literal
@@ -567,1025 +822,232 @@ func escapeCSharpVerbatimString(s string) string {
 	return sb.String()
 }
 
-// writeInterfaceStubCs generates an interface for an IDL interface
-func writeInterfaceStubCs(sb *strings.Builder, iface *parser.Interface, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) {
-	if iface.Comment != "" {
-		lines := strings.Split(strings.TrimSpace(iface.Comment), "\n")
-		for _, line := range lines {
-			fmt.Fprintf(sb, "// %s\n", line)
+// writeParamOptionsCs writes the optional flag and default of an optional
+// parameter as entries of its parameter definition. A JSON default is also a
+// valid C# literal.
+func writeParamOptionsCs(sb *strings.Builder, indent string, param *parser.Parameter) {
+	if !param.Optional {
+		return
+	}
+	fmt.Fprintf(sb, "%s{ \"optional\", true },\n", indent)
+	if value, ok := defaultJSON(param); ok {
+		fmt.Fprintf(sb, "%s{ \"default\", %s },\n", indent, value)
+	}
+}
+
+// writeParameterDeserializationCs writes C# code to determine the Type for parameter deserialization
+// GetCSharpType converts a type definition to a C# Type object for deserialization
+func GetCSharpType(typeDef map[string]interface{}, allStructs map[string]*parser.Struct, allEnums map[string]*parser.Enum) string {
+	if builtIn, ok := typeDef["builtIn"].(string); ok {
+		switch builtIn {
+		case "string":
+			return "typeof(string)"
+		case "int":
+			return "typeof(int)"
+		case "float":
+			return "typeof(double)"
+		case "bool":
+			return "typeof(bool)"
+		default:
+			return "typeof(object)"
 		}
+	} else if _, ok := typeDef["array"]; ok {
+		return "typeof(object[])" // Will be handled as List<T> in actual code
+	} else if _, ok := typeDef["mapValue"]; ok {
+		return "typeof(Dictionary<string, object>)"
+	} else if userDefined, ok := typeDef["userDefined"].(string); ok {
+		// For user-defined types, return the type name directly
+		return userDefined
 	}
-	fmt.Fprintf(sb, "public interface I%s\n", iface.Name)
-	sb.WriteString("{\n")
+	return "typeof(object)"
+}
 
-	for _, method := range iface.Methods {
-		// Return type
-		returnType := "object"
-		if method.ReturnType != nil {
-			returnType = mapTypeToCsType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
+// csClientView is the view model for Client.cs
+type csClientView struct {
+	Namespaces []string
+	Interfaces []csClientInterfaceView
+
+	// IDL features that add code to the client
+	AsyncJobs  bool
+	Compressed bool
+
+	// Sections written by the generators of optional features, empty when unused
+	JobsClient            string
+	LoggedCall            string
+	CanonicalTransport    string
+	CallBatch             string
+	CapabilitiesTransport string
+	BatchClient           string
+	CapabilitiesClient    string
+	ErrorDataClasses      string
+	APIClient             string
+}
+
+// csClientInterfaceView is the view model for the client class of an IDL interface
+type csClientInterfaceView struct {
+	Name      string
+	AsyncJobs bool
+	Methods   []csClientMethodView
+}
+
+// csClientMethodView is the view model for the synchronous and async methods of a
+// client class
+type csClientMethodView struct {
+	Name    string
+	RPCName string
+	// ReturnType is object? for methods without a return type
+	ReturnType     string
+	ReturnOptional bool
+	// Deserialize is set when the result is converted to ReturnType
+	Deserialize bool
+	Params      string // "string name, int? limit = null"
+	Args        string // "name, limit"
+	// Call are the statements that make the call and set response
+	Call []string
+	// ErrorDataClass is the RPCError subclass of the method's [errordata] struct, if any
+	ErrorDataClass string
+}
+
+// newCsClientMethodView returns the view of the client methods of method
+func newCsClientMethodView(iface *parser.Interface, method *parser.Method, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) csClientMethodView {
+	view := csClientMethodView{
+		Name:           method.Name,
+		RPCName:        iface.RPCName(method),
+		ReturnType:     "object?",
+		ReturnOptional: method.ReturnOptional,
+		Deserialize:    method.ReturnType != nil,
+	}
+	if method.ReturnType != nil {
+		view.ReturnType = mapTypeToCsType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
+	}
+	params := make([]string, len(method.Parameters))
+	names := make([]string, len(method.Parameters))
+	for i, param := range method.Parameters {
+		params[i] = mapParamTypeToCsType(param, structMap, enumMap) + " " + param.Name
+		if param.Optional {
+			// Left as null, the server uses the parameter's default
+			params[i] += " = null"
 		}
-		fmt.Fprintf(sb, "    %s %s(", returnType, method.Name)
+		names[i] = param.Name
+	}
+	view.Params = strings.Join(params, ", ")
+	view.Args = strings.Join(names, ", ")
 
-		// Parameters
-		for i, param := range method.Parameters {
-			if i > 0 {
-				sb.WriteString(", ")
-			}
-			paramType := mapTypeToCsType(param.Type, structMap, enumMap, false)
-			fmt.Fprintf(sb, "%s %s", paramType, param.Name)
+	callOptions := "_options"
+	if len(names) > 0 {
+		callOptions = fmt.Sprintf("_options with { ParamNames = new[] { %s } }", quotedList(names))
+	}
+	view.Call = []string{
+		fmt.Sprintf("response = await (_options.Batch ?? _transport).CallAsync(method, parameters, %s);", callOptions),
+		"_options.CaptureMeta(response);",
+	}
+	if method.IsAsync() {
+		view.Call = append(view.Call, "response = await JobPoller.AwaitAsync(_transport, response, _options);")
+	}
+	if name := method.ErrorData(); name != "" {
+		// Throw errors whose data is the [errordata] struct as its RPCError subclass
+		view.ErrorDataClass = errorDataClass(name)
+	} else {
+		view.Call[0] = "var " + view.Call[0]
+	}
+	return view
+}
+
+// generateClientCs generates the Client.cs file with transport abstraction and client classes
+func generateClientCs(ts *templateSet, idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, namespaceMap map[string]*NamespaceTypes) string {
+	interfaces := idl.Interfaces
+	view := csClientView{
+		Namespaces: sortedNamespaces(namespaceMap),
+		AsyncJobs:  usesAsyncMethods(interfaces),
+		Compressed: usesCompressedMethods(interfaces),
+	}
+	for _, iface := range interfaces {
+		iv := csClientInterfaceView{Name: iface.Name, AsyncJobs: usesAsyncMethods([]*parser.Interface{iface})}
+		for _, method := range iface.Methods {
+			iv.Methods = append(iv.Methods, newCsClientMethodView(iface, method, structMap, enumMap))
 		}
-		sb.WriteString(");\n")
+		view.Interfaces = append(view.Interfaces, iv)
 	}
-	sb.WriteString("}\n\n")
+
+	view.LoggedCall = capture(writeLoggedCallCs)
+	view.CanonicalTransport = capture(writeCanonicalTransportCs)
+	view.CallBatch = capture(writeCallBatchCs)
+	view.CapabilitiesTransport = capture(writeCapabilitiesTransportCs)
+	view.BatchClient = capture(writeBatchClientCs)
+	view.CapabilitiesClient = capture(writeCapabilitiesClientCs)
+	view.ErrorDataClasses = capture(func(sb *strings.Builder) {
+		writeErrorDataClassesCs(sb, errorDataStructs(interfaces), structMap, enumMap)
+	})
+	if view.AsyncJobs {
+		view.JobsClient = capture(writeJobsClientCs)
+	}
+	if usesAPIClientFacade(interfaces) {
+		view.APIClient = capture(func(sb *strings.Builder) { writeAPIClientCs(sb, interfaces) })
+	}
+	return ts.renderString("csharp/Client.cs.tmpl", view)
 }
 
-// writePulseRPCServerCs generates the PulseRPCServer class
-func writePulseRPCServerCs(sb *strings.Builder, idl *parser.IDL, idlJson string) {
-	sb.WriteString("public class PulseRPCServer\n")
-	sb.WriteString("{\n")
-	sb.WriteString("    private static readonly string _idlJson = ")
-	sb.WriteString(escapeCSharpVerbatimString(idlJson))
-	sb.WriteString(";\n\n")
-	sb.WriteString("    private Dictionary<string, object> _handlers = new Dictionary<string, object>();\n")
-	sb.WriteString("    private WebApplication? _app;\n")
-	sb.WriteString("    private ILogger<PulseRPCServer>? _logger;\n\n")
-
-	sb.WriteString("    public PulseRPCServer(ILogger<PulseRPCServer>? logger = null)\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        _logger = logger;\n")
-	sb.WriteString("    }\n\n")
-	sb.WriteString("    public void Register<T>(string interfaceName, T implementation) where T : class\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        _handlers[interfaceName] = implementation!;\n")
-	sb.WriteString("        _logger?.LogInformation(\"Registered handler for interface: {InterfaceName}\", interfaceName);\n")
-	sb.WriteString("    }\n\n")
+// generateTestServerCs generates TestServer.cs with concrete implementations of all interfaces
+func generateTestServerCs(idl *parser.IDL, allNamespaces []string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) string {
+	var sb strings.Builder
+
+	sb.WriteString("// Generated by pulserpc - do not edit\n")
+	sb.WriteString("// Test server implementation for integration testing\n\n")
+	sb.WriteString("using System;\n")
+	sb.WriteString("using System.Collections.Generic;\n")
+	sb.WriteString("using System.Linq;\n")
+	sb.WriteString("using System.Threading.Tasks;\n")
+	sb.WriteString("using Microsoft.Extensions.Logging;\n")
+	sb.WriteString("using Microsoft.Extensions.DependencyInjection;\n")
+	sb.WriteString("using PulseRPC;\n")
+
+	for _, ns := range allNamespaces {
+		sb.WriteString(fmt.Sprintf("using %s;\n", ns))
+	}
+	sb.WriteString("\n")
 
-	// Generate typed Register methods for each interface
+	// Generate implementation classes for each interface
 	for _, iface := range idl.Interfaces {
-		fmt.Fprintf(sb, "    public void Register%s(I%s implementation)\n", iface.Name, iface.Name)
-		sb.WriteString("    {\n")
-		fmt.Fprintf(sb, "        this.Register(\"%s\", implementation);\n", iface.Name)
-		sb.WriteString("    }\n\n")
+		writeTestInterfaceImplCs(&sb, iface, structMap, enumMap)
 	}
 
-	sb.WriteString("    public async Task RunAsync(string host = \"localhost\", int port = 8080)\n")
+	// Generate main entry point
+	sb.WriteString("public class Program\n")
+	sb.WriteString("{\n")
+	sb.WriteString("    public static async Task Main(string[] args)\n")
 	sb.WriteString("    {\n")
-	sb.WriteString("        var builder = WebApplication.CreateBuilder(new WebApplicationOptions\n")
+	sb.WriteString("        // Configure logging\n")
+	sb.WriteString("        using var loggerFactory = LoggerFactory.Create(builder =>\n")
 	sb.WriteString("        {\n")
-	sb.WriteString("            WebRootPath = null,\n")
-	sb.WriteString("            Args = new[] { $\"--urls=http://{host}:{port}\" }\n")
+	sb.WriteString("            builder.AddConsole().SetMinimumLevel(LogLevel.Debug);\n")
 	sb.WriteString("        });\n")
-	sb.WriteString("        _app = builder.Build();\n")
-	sb.WriteString("        // Get logger from app services if not already set\n")
-	sb.WriteString("        if (_logger == null)\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            _logger = _app.Services.GetService<ILogger<PulseRPCServer>>();\n")
-	sb.WriteString("        }\n\n")
-	sb.WriteString("        _app.MapPost(\"/\", async (HttpContext context) =>\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            await HandleRequest(context);\n")
-	sb.WriteString("        });\n\n")
-	sb.WriteString("        Console.WriteLine($\"PulseRPC server listening on http://{host}:{port}\");\n")
-	sb.WriteString("        await _app.RunAsync();\n")
-	sb.WriteString("    }\n\n")
-
-	sb.WriteString("    private async Task HandleRequest(HttpContext context)\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        if (context.Request.Method != \"POST\")\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            context.Response.StatusCode = 405;\n")
-	sb.WriteString("            await context.Response.WriteAsJsonAsync(new { error = \"Method Not Allowed\" });\n")
-	sb.WriteString("            return;\n")
-	sb.WriteString("        }\n\n")
-	sb.WriteString("        JsonElement requestJson;\n")
-	sb.WriteString("        try\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            requestJson = await JsonSerializer.DeserializeAsync<JsonElement>(context.Request.Body);\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("        catch (Exception e)\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            await WriteErrorResponse(context, null, -32700, \"Parse error\", $\"Invalid JSON: {e.Message}\");\n")
-	sb.WriteString("            return;\n")
-	sb.WriteString("        }\n\n")
-	sb.WriteString("        object? response;\n")
-	sb.WriteString("        if (requestJson.ValueKind == JsonValueKind.Array)\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            // Batch request\n")
-	sb.WriteString("            var responses = new List<object?>();\n")
-	sb.WriteString("            foreach (var req in requestJson.EnumerateArray())\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                var reqDict = ConvertJsonElementToDict(req);\n")
-	sb.WriteString("                var resp = await HandleSingleRequest(reqDict);\n")
-	sb.WriteString("                if (resp != null) responses.Add(resp);\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("            if (responses.Count == 0)\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                context.Response.StatusCode = 204;\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("            else\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                await context.Response.WriteAsJsonAsync(responses);\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("        else\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            var reqDict = ConvertJsonElementToDict(requestJson);\n")
-	sb.WriteString("            response = await HandleSingleRequest(reqDict);\n")
-	sb.WriteString("            if (response == null)\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                context.Response.StatusCode = 204;\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("            else\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                await context.Response.WriteAsJsonAsync(response);\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("    }\n\n")
-	sb.WriteString("    private Dictionary<string, object?> ConvertJsonElementToDict(JsonElement element)\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        var dict = new Dictionary<string, object?>();\n")
-	sb.WriteString("        foreach (var prop in element.EnumerateObject())\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            dict[prop.Name] = ConvertJsonElementValue(prop.Value);\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("        return dict;\n")
-	sb.WriteString("    }\n\n")
-	sb.WriteString("    private string? ExtractStringValue(object? value)\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        if (value is string str)\n")
-	sb.WriteString("            return str;\n")
-	sb.WriteString("        if (value is JsonElement jsonElement && jsonElement.ValueKind == JsonValueKind.String)\n")
-	sb.WriteString("            return jsonElement.GetString();\n")
-	sb.WriteString("        return null;\n")
-	sb.WriteString("    }\n\n")
-	sb.WriteString("    private object? ConvertJsonElementValue(JsonElement element)\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        switch (element.ValueKind)\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            case JsonValueKind.String:\n")
-	sb.WriteString("                return element.GetString();\n")
-	sb.WriteString("            case JsonValueKind.Number:\n")
-	sb.WriteString("                if (element.TryGetInt32(out var intVal))\n")
-	sb.WriteString("                    return intVal;\n")
-	sb.WriteString("                if (element.TryGetInt64(out var longVal))\n")
-	sb.WriteString("                    return longVal;\n")
-	sb.WriteString("                return element.GetDouble();\n")
-	sb.WriteString("            case JsonValueKind.True:\n")
-	sb.WriteString("                return true;\n")
-	sb.WriteString("            case JsonValueKind.False:\n")
-	sb.WriteString("                return false;\n")
-	sb.WriteString("            case JsonValueKind.Null:\n")
-	sb.WriteString("                return null;\n")
-	sb.WriteString("            case JsonValueKind.Array:\n")
-	sb.WriteString("                var list = new List<object?>();\n")
-	sb.WriteString("                foreach (var item in element.EnumerateArray())\n")
-	sb.WriteString("                {\n")
-	sb.WriteString("                    list.Add(ConvertJsonElementValue(item));\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("                return list;\n")
-	sb.WriteString("            case JsonValueKind.Object:\n")
-	sb.WriteString("                return ConvertJsonElementToDict(element);\n")
-	sb.WriteString("            default:\n")
-	sb.WriteString("                return element;\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("    }\n\n")
-	sb.WriteString("    private void ConvertEnumIntsToStrings(Dictionary<string, object?> dict, string structName, Dictionary<string, Dictionary<string, object>> allStructs, Dictionary<string, Dictionary<string, object>> allEnums)\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        var structDef = Types.FindStruct(structName, allStructs);\n")
-	sb.WriteString("        foreach (var kvp in dict.ToList())\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            var key = kvp.Key;\n")
-	sb.WriteString("            var value = kvp.Value;\n")
-	sb.WriteString("            if (value is Dictionary<string, object?> nestedDict)\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                // Determine the nested struct name\n")
-	sb.WriteString("                string nestedStructName = null;\n")
-	sb.WriteString("                if (structDef != null && structDef.TryGetValue(\"fields\", out var fieldsObj) && fieldsObj is System.Collections.IList fields)\n")
-	sb.WriteString("                {\n")
-	sb.WriteString("                    foreach (Dictionary<string, object> field in fields)\n")
-	sb.WriteString("                    {\n")
-	sb.WriteString("                        if (field.TryGetValue(\"name\", out var nameObj) && nameObj?.ToString() == key)\n")
-	sb.WriteString("                        {\n")
-	sb.WriteString("                            if (field.TryGetValue(\"type\", out var typeObj) && typeObj is Dictionary<string, object> typeDict && typeDict.TryGetValue(\"userDefined\", out var userTypeObj) && userTypeObj is string userType)\n")
-	sb.WriteString("                            {\n")
-	sb.WriteString("                                nestedStructName = Types.FindStruct(userType, allStructs) != null ? userType : null;\n")
-	sb.WriteString("                            }\n")
-	sb.WriteString("                            break;\n")
-	sb.WriteString("                        }\n")
-	sb.WriteString("                    }\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("                if (nestedStructName != null)\n")
-	sb.WriteString("                {\n")
-	sb.WriteString("                    ConvertEnumIntsToStrings(nestedDict, nestedStructName, allStructs, allEnums);\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("                else\n")
-	sb.WriteString("                {\n")
-	sb.WriteString("                    ConvertEnumIntsToStrings(nestedDict, null, allStructs, allEnums);\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("            else if (value is System.Collections.IList list)\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                for (int i = 0; i < list.Count; i++)\n")
-	sb.WriteString("                {\n")
-	sb.WriteString("                    if (list[i] is Dictionary<string, object?> listDict)\n")
-	sb.WriteString("                    {\n")
-	sb.WriteString("                        ConvertEnumIntsToStrings(listDict, structName, allStructs, allEnums);\n")
-	sb.WriteString("                    }\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("            else if (value is int intVal && structDef != null)\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                // Check if this field is an enum type before converting\n")
-	sb.WriteString("                string enumTypeName = null;\n")
-	sb.WriteString("                if (structDef.TryGetValue(\"fields\", out var fieldsObj) && fieldsObj is System.Collections.IList fields)\n")
-	sb.WriteString("                {\n")
-	sb.WriteString("                    foreach (Dictionary<string, object> field in fields)\n")
-	sb.WriteString("                    {\n")
-	sb.WriteString("                        if (field.TryGetValue(\"name\", out var nameObj) && nameObj?.ToString() == key)\n")
-	sb.WriteString("                        {\n")
-	sb.WriteString("                            if (field.TryGetValue(\"type\", out var typeObj) && typeObj is Dictionary<string, object> typeDict && typeDict.TryGetValue(\"userDefined\", out var userTypeObj) && userTypeObj is string userType)\n")
-	sb.WriteString("                            {\n")
-	sb.WriteString("                                if (Types.FindEnum(userType, allEnums) != null)\n")
-	sb.WriteString("                                {\n")
-	sb.WriteString("                                    enumTypeName = userType;\n")
-	sb.WriteString("                                }\n")
-	sb.WriteString("                            }\n")
-	sb.WriteString("                            break;\n")
-	sb.WriteString("                        }\n")
-	sb.WriteString("                    }\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("                if (enumTypeName != null && allEnums.TryGetValue(enumTypeName, out var enumDef))\n")
-	sb.WriteString("                {\n")
-	sb.WriteString("                    if (enumDef.TryGetValue(\"values\", out var valuesObj) && valuesObj is System.Collections.IList enumValues && intVal >= 0 && intVal < enumValues.Count)\n")
-	sb.WriteString("                    {\n")
-	sb.WriteString("                        var enumValue = enumValues[intVal];\n")
-	sb.WriteString("                        if (enumValue is Dictionary<string, object> enumValueDict && enumValueDict.TryGetValue(\"name\", out var nameObj))\n")
-	sb.WriteString("                        {\n")
-	sb.WriteString("                            dict[key] = nameObj?.ToString();\n")
-	sb.WriteString("                        }\n")
-	sb.WriteString("                        else if (enumValue is string enumName)\n")
-	sb.WriteString("                        {\n")
-	sb.WriteString("                            dict[key] = enumName;\n")
-	sb.WriteString("                        }\n")
-	sb.WriteString("                    }\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("    }\n\n")
-
-	// HandleSingleRequest method
-	writeHandleSingleRequestCs(sb, idl)
-
-	sb.WriteString("}\n")
-}
-
-// writeHandleSingleRequestCs generates the HandleSingleRequest method
-func writeHandleSingleRequestCs(sb *strings.Builder, idl *parser.IDL) {
-	sb.WriteString("    private async Task<Dictionary<string, object?>?> HandleSingleRequest(Dictionary<string, object?> requestJson)\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        // Validate JSON-RPC 2.0 structure\n")
-	sb.WriteString("        if (!requestJson.TryGetValue(\"jsonrpc\", out var jsonrpcObj))\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            _logger?.LogWarning(\"Missing jsonrpc field\");\n")
-	sb.WriteString("            return ErrorResponse(null, -32600, \"Invalid Request\", \"jsonrpc field is required\");\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("        var jsonrpc = ExtractStringValue(jsonrpcObj);\n")
-	sb.WriteString("        if (jsonrpc != \"2.0\")\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            _logger?.LogWarning(\"Invalid JSON-RPC version: {JsonRpc}\", jsonrpc ?? \"null\");\n")
-	sb.WriteString("            return ErrorResponse(null, -32600, \"Invalid Request\", \"jsonrpc must be '2.0'\");\n")
-	sb.WriteString("        }\n\n")
-	sb.WriteString("        if (!requestJson.TryGetValue(\"method\", out var methodObj))\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            _logger?.LogWarning(\"Missing method field\");\n")
-	sb.WriteString("            return ErrorResponse(null, -32600, \"Invalid Request\", \"method field is required\");\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("        var method = ExtractStringValue(methodObj);\n")
-	sb.WriteString("        if (method == null)\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            _logger?.LogWarning(\"Invalid method in request: {Method}\", methodObj?.ToString() ?? \"null\");\n")
-	sb.WriteString("            return ErrorResponse(null, -32600, \"Invalid Request\", \"method must be a string\");\n")
-	sb.WriteString("        }\n\n")
-	sb.WriteString("        requestJson.TryGetValue(\"params\", out var paramsObj);\n")
-	sb.WriteString("        requestJson.TryGetValue(\"id\", out var requestId);\n")
-	sb.WriteString("        bool isNotification = !requestJson.ContainsKey(\"id\");\n")
-	sb.WriteString("        _logger?.LogInformation(\"Received request: method={Method}, id={RequestId}, isNotification={IsNotification}\", method, requestId, isNotification);\n")
-	sb.WriteString("        _logger?.LogDebug(\"Request params: {Params}\", paramsObj != null ? JsonSerializer.Serialize(paramsObj) : \"null\");\n\n")
-
-	sb.WriteString("        // Special case: pulserpc-idl method\n")
-	sb.WriteString("        if (method == \"pulserpc-idl\")\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            _logger?.LogDebug(\"Handling pulserpc-idl request\");\n")
-	sb.WriteString("            try\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                var idlDoc = JsonSerializer.Deserialize<object>(_idlJson);\n")
-	sb.WriteString("                if (isNotification) return null;\n")
-	sb.WriteString("                return new Dictionary<string, object?>\n")
-	sb.WriteString("                {\n")
-	sb.WriteString("                    { \"jsonrpc\", \"2.0\" },\n")
-	sb.WriteString("                    { \"result\", idlDoc },\n")
-	sb.WriteString("                    { \"id\", requestId }\n")
-	sb.WriteString("                };\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("            catch (Exception e)\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                _logger?.LogError(e, \"Failed to deserialize IDL JSON\");\n")
-	sb.WriteString("                return ErrorResponse(requestId, -32603, \"Internal error\", $\"Failed to deserialize IDL JSON: {e.Message}\");\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("        }\n\n")
-
-	sb.WriteString("        // Parse method name: interface.method\n")
-	sb.WriteString("        var parts = method.Split('.', 2);\n")
-	sb.WriteString("        if (parts.Length != 2)\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            _logger?.LogWarning(\"Invalid method format: {Method}\", method);\n")
-	sb.WriteString("            return ErrorResponse(requestId, -32601, \"Method not found\", $\"Invalid method format: {method}\");\n")
-	sb.WriteString("        }\n\n")
-	sb.WriteString("        var interfaceName = parts[0];\n")
-	sb.WriteString("        var methodName = parts[1];\n")
-	sb.WriteString("        _logger?.LogDebug(\"Parsed method: interface={InterfaceName}, method={MethodName}\", interfaceName, methodName);\n\n")
-
-	sb.WriteString("        // Find handler\n")
-	sb.WriteString("        if (!_handlers.TryGetValue(interfaceName, out var handler))\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            _logger?.LogWarning(\"Interface not registered: {InterfaceName}\", interfaceName);\n")
-	sb.WriteString("            return ErrorResponse(requestId, -32601, \"Method not found\", $\"Interface '{interfaceName}' not registered\");\n")
-	sb.WriteString("        }\n\n")
-
-	// Method lookup and invocation
-	writeMethodLookupAndInvokeCs(sb, idl)
-
-	sb.WriteString("    }\n\n")
-
-	sb.WriteString("    private Dictionary<string, object?> ErrorResponse(object? requestId, int code, string message, object? data = null)\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        var error = new Dictionary<string, object?> { { \"code\", code }, { \"message\", message } };\n")
-	sb.WriteString("        if (data != null) error[\"data\"] = data;\n")
-	sb.WriteString("        return new Dictionary<string, object?>\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            { \"jsonrpc\", \"2.0\" },\n")
-	sb.WriteString("            { \"error\", error },\n")
-	sb.WriteString("            { \"id\", requestId }\n")
-	sb.WriteString("        };\n")
-	sb.WriteString("    }\n\n")
-
-	sb.WriteString("    private async Task WriteErrorResponse(HttpContext context, object? requestId, int code, string message, object? data = null)\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        await context.Response.WriteAsJsonAsync(ErrorResponse(requestId, code, message, data));\n")
-	sb.WriteString("    }\n")
-}
-
-// writeDeserializeParamCs writes C# code to deserialize a parameter value to its typed object
-// writeMethodLookupAndInvokeCs generates method lookup and invocation code
-func writeMethodLookupAndInvokeCs(sb *strings.Builder, idl *parser.IDL) {
-	sb.WriteString("        // Find method definition\n")
-	sb.WriteString("        Dictionary<string, object>? methodDef = null;\n\n")
-
-	for i, iface := range idl.Interfaces {
-		if i == 0 {
-			fmt.Fprintf(sb, "        if (interfaceName == \"%s\")\n", iface.Name)
-		} else {
-			fmt.Fprintf(sb, "        else if (interfaceName == \"%s\")\n", iface.Name)
-		}
-		sb.WriteString("        {\n")
-		sb.WriteString("            var interfaceMethods = new Dictionary<string, Dictionary<string, object>>\n")
-		sb.WriteString("            {\n")
-		for _, method := range iface.Methods {
-			fmt.Fprintf(sb, "                { \"%s\", new Dictionary<string, object>\n", method.Name)
-			sb.WriteString("                {\n")
-			sb.WriteString("                    { \"parameters\", new List<Dictionary<string, object>>\n")
-			sb.WriteString("                    {\n")
-			for _, param := range method.Parameters {
-				sb.WriteString("                        new Dictionary<string, object>\n")
-				sb.WriteString("                        {\n")
-				fmt.Fprintf(sb, "                            { \"name\", \"%s\" },\n", param.Name)
-				sb.WriteString("                            { \"type\", ")
-				writeTypeDictCs(sb, param.Type)
-				sb.WriteString(" },\n")
-				sb.WriteString("                        },\n")
-			}
-			sb.WriteString("                    }},\n")
-			sb.WriteString("                    { \"returnType\", ")
-			writeTypeDictCs(sb, method.ReturnType)
-			sb.WriteString(" },\n")
-			sb.WriteString("                    { \"returnOptional\", ")
-			if method.ReturnOptional {
-				sb.WriteString("true")
-			} else {
-				sb.WriteString("false")
-			}
-			sb.WriteString(" },\n")
-			sb.WriteString("                }},\n")
-		}
-		sb.WriteString("            };\n")
-		sb.WriteString("            methodDef = interfaceMethods.TryGetValue(methodName, out var def) ? def : null;\n")
-		sb.WriteString("        }\n")
-	}
-	sb.WriteString("\n")
-
-	sb.WriteString("        if (methodDef == null)\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            _logger?.LogWarning(\"Method not found: {InterfaceName}.{MethodName}\", interfaceName, methodName);\n")
-	sb.WriteString("            return ErrorResponse(requestId, -32601, \"Method not found\", $\"Method '{methodName}' not found in interface '{interfaceName}'\");\n")
-	sb.WriteString("        }\n\n")
-
-	sb.WriteString("        // Validate params\n")
-	sb.WriteString("        var paramsList = paramsObj as System.Collections.IList ?? new List<object>();\n")
-	sb.WriteString("        var expectedParams = (methodDef[\"parameters\"] as System.Collections.IList) ?? new List<object>();\n")
-	sb.WriteString("        _logger?.LogDebug(\"Validating params: expected={ExpectedCount}, got={ActualCount}\", expectedParams.Count, paramsList.Count);\n")
-	sb.WriteString("        if (paramsList.Count != expectedParams.Count)\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            _logger?.LogWarning(\"Parameter count mismatch: expected={ExpectedCount}, got={ActualCount}\", expectedParams.Count, paramsList.Count);\n")
-	sb.WriteString("            return ErrorResponse(requestId, -32602, \"Invalid params\", $\"Expected {expectedParams.Count} parameters, got {paramsList.Count}\");\n")
-	sb.WriteString("        }\n\n")
-
-	sb.WriteString("        // Validate each param\n")
-	sb.WriteString("        for (int i = 0; i < paramsList.Count; i++)\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            var paramValue = paramsList[i];\n")
-	sb.WriteString("            var paramDef = (expectedParams[i] as Dictionary<string, object>)!;\n")
-	sb.WriteString("            var paramName = paramDef.TryGetValue(\"name\", out var name) ? name?.ToString() : $\"parameter {i}\";\n")
-	sb.WriteString("            _logger?.LogDebug(\"Validating parameter {Index} ({ParamName})\", i, paramName);\n")
-	sb.WriteString("            try\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                var typeDef = (Dictionary<string, object>)paramDef[\"type\"];\n")
-	sb.WriteString("                // Convert enum objects/values to strings for validation\n")
-	sb.WriteString("                object? valueToValidate = paramValue;\n")
-	sb.WriteString("                if (typeDef.TryGetValue(\"userDefined\", out var userTypeObj) && userTypeObj is string userType)\n")
-	sb.WriteString("                {\n")
-	sb.WriteString("                    var enumDef = Types.FindEnum(userType, IdlData.ALL_ENUMS);\n")
-	sb.WriteString("                    if (enumDef != null && paramValue != null)\n")
-	sb.WriteString("                    {\n")
-	sb.WriteString("                        if (paramValue is System.Text.Json.JsonElement jsonElem)\n")
-	sb.WriteString("                        {\n")
-	sb.WriteString("                            // Handle JsonElement enum values (could be string or number)\n")
-	sb.WriteString("                            if (jsonElem.ValueKind == System.Text.Json.JsonValueKind.String)\n")
-	sb.WriteString("                            {\n")
-	sb.WriteString("                                valueToValidate = jsonElem.GetString();\n")
-	sb.WriteString("                            }\n")
-	sb.WriteString("                            else if (jsonElem.ValueKind == System.Text.Json.JsonValueKind.Number && jsonElem.TryGetInt32(out var enumInt))\n")
-	sb.WriteString("                            {\n")
-	sb.WriteString("                                // Convert integer enum value to string by looking up in enum definition\n")
-	sb.WriteString("                                if (enumDef.TryGetValue(\"values\", out var valuesObj) && valuesObj is System.Collections.IList enumValues && enumInt >= 0 && enumInt < enumValues.Count)\n")
-	sb.WriteString("                                {\n")
-	sb.WriteString("                                    var enumValue = enumValues[enumInt];\n")
-	sb.WriteString("                                    if (enumValue is Dictionary<string, object> enumValueDict && enumValueDict.TryGetValue(\"name\", out var nameObj))\n")
-	sb.WriteString("                                    {\n")
-	sb.WriteString("                                        valueToValidate = nameObj?.ToString();\n")
-	sb.WriteString("                                    }\n")
-	sb.WriteString("                                    else if (enumValue is string enumName)\n")
-	sb.WriteString("                                    {\n")
-	sb.WriteString("                                        valueToValidate = enumName;\n")
-	sb.WriteString("                                    }\n")
-	sb.WriteString("                                    else\n")
-	sb.WriteString("                                    {\n")
-	sb.WriteString("                                        // Fallback: use the integer as string\n")
-	sb.WriteString("                                        valueToValidate = enumInt.ToString();\n")
-	sb.WriteString("                                    }\n")
-	sb.WriteString("                                }\n")
-	sb.WriteString("                                else\n")
-	sb.WriteString("                                {\n")
-	sb.WriteString("                                    // Enum definition structure doesn't match expected format, use integer as string\n")
-	sb.WriteString("                                    valueToValidate = enumInt.ToString();\n")
-	sb.WriteString("                                }\n")
-	sb.WriteString("                            }\n")
-	sb.WriteString("                        }\n")
-	sb.WriteString("                        else if (paramValue is int enumIntVal)\n")
-	sb.WriteString("                        {\n")
-	sb.WriteString("                            // Convert integer enum value to string by looking up in enum definition\n")
-	sb.WriteString("                            if (enumDef.TryGetValue(\"values\", out var valuesObj) && valuesObj is System.Collections.IList enumValues && enumIntVal >= 0 && enumIntVal < enumValues.Count)\n")
-	sb.WriteString("                            {\n")
-	sb.WriteString("                                var enumValue = enumValues[enumIntVal];\n")
-	sb.WriteString("                                if (enumValue is Dictionary<string, object> enumValueDict && enumValueDict.TryGetValue(\"name\", out var nameObj))\n")
-	sb.WriteString("                                {\n")
-	sb.WriteString("                                    valueToValidate = nameObj?.ToString();\n")
-	sb.WriteString("                                }\n")
-	sb.WriteString("                                else if (enumValue is string enumName)\n")
-	sb.WriteString("                                {\n")
-	sb.WriteString("                                    valueToValidate = enumName;\n")
-	sb.WriteString("                                }\n")
-	sb.WriteString("                                else\n")
-	sb.WriteString("                                {\n")
-	sb.WriteString("                                    // Fallback: use the integer as string\n")
-	sb.WriteString("                                    valueToValidate = enumIntVal.ToString();\n")
-	sb.WriteString("                                }\n")
-	sb.WriteString("                            }\n")
-	sb.WriteString("                            else\n")
-	sb.WriteString("                            {\n")
-	sb.WriteString("                                // Enum definition structure doesn't match expected format, use integer as string\n")
-	sb.WriteString("                                valueToValidate = enumIntVal.ToString();\n")
-	sb.WriteString("                            }\n")
-	sb.WriteString("                        }\n")
-	sb.WriteString("                        else if (!(paramValue is string))\n")
-	sb.WriteString("                        {\n")
-	sb.WriteString("                            // Convert enum object to string representation\n")
-	sb.WriteString("                            valueToValidate = paramValue.ToString();\n")
-	sb.WriteString("                        }\n")
-	sb.WriteString("                    }\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("                Validation.ValidateType(valueToValidate, typeDef, IdlData.ALL_STRUCTS, IdlData.ALL_ENUMS, false);\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("            catch (Exception e)\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                _logger?.LogError(e, \"Parameter validation failed: parameter {Index} ({ParamName})\", i, paramName);\n")
-	sb.WriteString("                return ErrorResponse(requestId, -32602, \"Invalid params\", $\"Parameter {i} ({paramName}) validation failed: {e.Message}\");\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("        }\n\n")
-
-	sb.WriteString("        // Invoke handler using reflection\n")
-	sb.WriteString("        var jsonOptions = new JsonSerializerOptions\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            PropertyNameCaseInsensitive = true\n")
-	sb.WriteString("        };\n")
-	sb.WriteString("        jsonOptions.Converters.Add(new JsonStringEnumConverter());\n")
-	sb.WriteString("        object? result;\n")
-	sb.WriteString("        try\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            _logger?.LogDebug(\"Invoking method {InterfaceName}.{MethodName}\", interfaceName, methodName);\n")
-	sb.WriteString("            var handlerType = handler.GetType();\n")
-	sb.WriteString("            var methodInfo = handlerType.GetMethod(methodName);\n")
-	sb.WriteString("            if (methodInfo == null)\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                _logger?.LogError(\"Method not found via reflection: {InterfaceName}.{MethodName}\", interfaceName, methodName);\n")
-	sb.WriteString("                return ErrorResponse(requestId, -32601, \"Method not found\", $\"Method '{methodName}' not found on interface '{interfaceName}'\");\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("            // Deserialize parameters to expected types using method parameter types\n")
-	sb.WriteString("            var paramInfos = methodInfo.GetParameters();\n")
-	sb.WriteString("            var deserializedParams = new object[paramsList.Count];\n")
-	sb.WriteString("            for (int i = 0; i < paramsList.Count; i++)\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                var paramValue = paramsList[i];\n")
-	sb.WriteString("                var paramType = paramInfos[i].ParameterType;\n")
-	sb.WriteString("                _logger?.LogDebug(\"Deserializing parameter {Index} to type {ParamType}\", i, paramType.Name);\n")
-	sb.WriteString("                string paramJson;\n")
-	sb.WriteString("                if (paramValue is System.Text.Json.JsonElement jsonElement)\n")
-	sb.WriteString("                {\n")
-	sb.WriteString("                    paramJson = jsonElement.GetRawText();\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("                else\n")
-	sb.WriteString("                {\n")
-	sb.WriteString("                    paramJson = JsonSerializer.Serialize(paramValue);\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("                deserializedParams[i] = JsonSerializer.Deserialize(paramJson, paramType, jsonOptions);\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("            _logger?.LogDebug(\"Calling method {InterfaceName}.{MethodName} with {ParamCount} parameters\", interfaceName, methodName, deserializedParams.Length);\n")
-	sb.WriteString("            result = methodInfo.Invoke(handler, deserializedParams);\n")
-	sb.WriteString("            if (result is Task task)\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                await task;\n")
-	sb.WriteString("                var resultProperty = task.GetType().GetProperty(\"Result\");\n")
-	sb.WriteString("                result = resultProperty?.GetValue(task);\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("            _logger?.LogDebug(\"Method {InterfaceName}.{MethodName} completed successfully\", interfaceName, methodName);\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("        catch (RPCError rpcErr)\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            _logger?.LogWarning(\"RPCError from {InterfaceName}.{MethodName}: {Code} - {Message}\", interfaceName, methodName, rpcErr.Code, rpcErr.Message);\n")
-	sb.WriteString("            return ErrorResponse(requestId, rpcErr.Code, rpcErr.Message, rpcErr.Data);\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("        catch (Exception e)\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            _logger?.LogError(e, \"Exception invoking {InterfaceName}.{MethodName}: {Message}\", interfaceName, methodName, e.Message);\n")
-	sb.WriteString("            return ErrorResponse(requestId, -32603, \"Internal error\", $\"Exception: {e.Message}\\nStackTrace: {e.StackTrace}\");\n")
-	sb.WriteString("        }\n\n")
-
-	sb.WriteString("        // Validate response\n")
-	sb.WriteString("        if (methodDef.TryGetValue(\"returnType\", out var returnTypeObj) && returnTypeObj is Dictionary<string, object> returnType)\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            _logger?.LogDebug(\"Validating response for {InterfaceName}.{MethodName}\", interfaceName, methodName);\n")
-	sb.WriteString("            try\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                var returnOptional = methodDef.TryGetValue(\"returnOptional\", out var opt) && opt is bool optBool && optBool;\n")
-	sb.WriteString("                // Convert struct objects to dictionaries and enum objects to strings for validation\n")
-	sb.WriteString("                object? valueToValidate = result;\n")
-	sb.WriteString("                if (returnType.TryGetValue(\"userDefined\", out var returnUserTypeObj) && returnUserTypeObj is string returnUserType)\n")
-	sb.WriteString("                {\n")
-	sb.WriteString("                    var structDef = Types.FindStruct(returnUserType, IdlData.ALL_STRUCTS);\n")
-	sb.WriteString("                    if (structDef != null && result != null && !(result is Dictionary<string, object?>))\n")
-	sb.WriteString("                    {\n")
-	sb.WriteString("                        // Serialize struct object to JSON, then convert JsonElement to dictionary with proper type conversion\n")
-	sb.WriteString("                        var structResultJson = JsonSerializer.Serialize(result, jsonOptions);\n")
-	sb.WriteString("                        var structJsonElement = JsonSerializer.Deserialize<JsonElement>(structResultJson);\n")
-	sb.WriteString("                        valueToValidate = ConvertJsonElementToDict(structJsonElement);\n")
-	sb.WriteString("                        // Convert enum integers to strings for validation\n")
-	sb.WriteString("                        if (valueToValidate is Dictionary<string, object?> structDict)\n")
-	sb.WriteString("                        {\n")
-	sb.WriteString("                            ConvertEnumIntsToStrings(structDict, returnUserType, IdlData.ALL_STRUCTS, IdlData.ALL_ENUMS);\n")
-	sb.WriteString("                        }\n")
-	sb.WriteString("                    }\n")
-	sb.WriteString("                    else\n")
-	sb.WriteString("                    {\n")
-	sb.WriteString("                        var enumDef = Types.FindEnum(returnUserType, IdlData.ALL_ENUMS);\n")
-	sb.WriteString("                        if (enumDef != null && result != null && !(result is string) && !(result is System.Text.Json.JsonElement))\n")
-	sb.WriteString("                        {\n")
-	sb.WriteString("                            // Convert enum object to string representation\n")
-	sb.WriteString("                            valueToValidate = result.ToString();\n")
-	sb.WriteString("                        }\n")
-	sb.WriteString("                    }\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("                // Handle arrays of structs or enums - convert elements to dictionaries/strings for validation\n")
-	sb.WriteString("                else if (returnType.TryGetValue(\"array\", out var arrayObj) && arrayObj is Dictionary<string, object> elementType)\n")
-	sb.WriteString("                {\n")
-	sb.WriteString("                    if (result != null && elementType.TryGetValue(\"userDefined\", out var elementUserTypeObj) && elementUserTypeObj is string elementUserType)\n")
-	sb.WriteString("                    {\n")
-	sb.WriteString("                        var structDef = Types.FindStruct(elementUserType, IdlData.ALL_STRUCTS);\n")
-	sb.WriteString("                        if (structDef != null && result is System.Collections.IList resultEnum)\n")
-	sb.WriteString("                        {\n")
-	sb.WriteString("                            // Convert each struct object in the array to a dictionary for validation\n")
-	sb.WriteString("                            var convertedList = new List<object?>();\n")
-	sb.WriteString("                            foreach (var item in resultEnum)\n")
-	sb.WriteString("                            {\n")
-	sb.WriteString("                                if (item != null && !(item is Dictionary<string, object?>))\n")
-	sb.WriteString("                                {\n")
-	sb.WriteString("                                    var itemJson = JsonSerializer.Serialize(item, jsonOptions);\n")
-	sb.WriteString("                                    var itemJsonElement = JsonSerializer.Deserialize<JsonElement>(itemJson);\n")
-	sb.WriteString("                                    var itemDict = ConvertJsonElementToDict(itemJsonElement);\n")
-	sb.WriteString("                                    if (itemDict is Dictionary<string, object?> dict)\n")
-	sb.WriteString("                                    {\n")
-	sb.WriteString("                                        ConvertEnumIntsToStrings(dict, elementUserType, IdlData.ALL_STRUCTS, IdlData.ALL_ENUMS);\n")
-	sb.WriteString("                                    }\n")
-	sb.WriteString("                                    convertedList.Add(itemDict);\n")
-	sb.WriteString("                                }\n")
-	sb.WriteString("                                else\n")
-	sb.WriteString("                                {\n")
-	sb.WriteString("                                    convertedList.Add(item);\n")
-	sb.WriteString("                                }\n")
-	sb.WriteString("                            }\n")
-	sb.WriteString("                            valueToValidate = convertedList;\n")
-	sb.WriteString("                        }\n")
-	sb.WriteString("                        else\n")
-	sb.WriteString("                        {\n")
-	sb.WriteString("                            var enumDef = Types.FindEnum(elementUserType, IdlData.ALL_ENUMS);\n")
-	sb.WriteString("                            if (enumDef != null && result is System.Collections.IList enumList)\n")
-	sb.WriteString("                            {\n")
-	sb.WriteString("                                // Convert each enum object in the array to a string for validation\n")
-	sb.WriteString("                                var convertedEnumList = new List<object?>();\n")
-	sb.WriteString("                                foreach (var item in enumList)\n")
-	sb.WriteString("                                {\n")
-	sb.WriteString("                                    if (item != null && !(item is string) && !(item is System.Text.Json.JsonElement))\n")
-	sb.WriteString("                                    {\n")
-	sb.WriteString("                                        convertedEnumList.Add(item.ToString());\n")
-	sb.WriteString("                                    }\n")
-	sb.WriteString("                                    else\n")
-	sb.WriteString("                                    {\n")
-	sb.WriteString("                                        convertedEnumList.Add(item);\n")
-	sb.WriteString("                                    }\n")
-	sb.WriteString("                                }\n")
-	sb.WriteString("                                valueToValidate = convertedEnumList;\n")
-	sb.WriteString("                            }\n")
-	sb.WriteString("                        }\n")
-	sb.WriteString("                    }\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("                Validation.ValidateType(valueToValidate, returnType, IdlData.ALL_STRUCTS, IdlData.ALL_ENUMS, returnOptional);\n")
-	sb.WriteString("                _logger?.LogDebug(\"Response validation passed for {InterfaceName}.{MethodName}\", interfaceName, methodName);\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("            catch (Exception e)\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                _logger?.LogError(e, \"Response validation failed for {InterfaceName}.{MethodName}\", interfaceName, methodName);\n")
-	sb.WriteString("                return ErrorResponse(requestId, -32603, \"Internal error\", $\"Response validation failed: {e.Message}\");\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("        }\n\n")
-
-	sb.WriteString("        // Return success response\n")
-	sb.WriteString("        if (isNotification) return null;\n")
-	sb.WriteString("        // Serialize result to JSON for proper response\n")
-	sb.WriteString("        var resultJson = JsonSerializer.Serialize(result, jsonOptions);\n")
-	sb.WriteString("        return new Dictionary<string, object?>\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            { \"jsonrpc\", \"2.0\" },\n")
-	sb.WriteString("            { \"result\", JsonSerializer.Deserialize<object>(resultJson, jsonOptions) },\n")
-	sb.WriteString("            { \"id\", requestId }\n")
-	sb.WriteString("        };\n")
-}
-
-// writeParameterDeserializationCs writes C# code to determine the Type for parameter deserialization
-// GetCSharpType converts a type definition to a C# Type object for deserialization
-func GetCSharpType(typeDef map[string]interface{}, allStructs map[string]*parser.Struct, allEnums map[string]*parser.Enum) string {
-	if builtIn, ok := typeDef["builtIn"].(string); ok {
-		switch builtIn {
-		case "string":
-			return "typeof(string)"
-		case "int":
-			return "typeof(int)"
-		case "float":
-			return "typeof(double)"
-		case "bool":
-			return "typeof(bool)"
-		default:
-			return "typeof(object)"
-		}
-	} else if _, ok := typeDef["array"]; ok {
-		return "typeof(object[])" // Will be handled as List<T> in actual code
-	} else if _, ok := typeDef["mapValue"]; ok {
-		return "typeof(Dictionary<string, object>)"
-	} else if userDefined, ok := typeDef["userDefined"].(string); ok {
-		// For user-defined types, return the type name directly
-		return userDefined
-	}
-	return "typeof(object)"
-}
-
-// generateClientCs generates the Client.cs file with transport abstraction and client classes
-func generateClientCs(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, namespaceMap map[string]*NamespaceTypes) string {
-	var sb strings.Builder
-
-	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
-	sb.WriteString("using System;\n")
-	sb.WriteString("using System.Collections.Generic;\n")
-	sb.WriteString("using System.Linq;\n")
-	sb.WriteString("using System.Net.Http;\n")
-	sb.WriteString("using System.Text.Json;\n")
-	sb.WriteString("using System.Text.Json.Serialization;\n")
-	sb.WriteString("using System.Threading.Tasks;\n")
-	sb.WriteString("using PulseRPC;\n\n")
-
-	// Import from namespace files
-	namespaces := make([]string, 0, len(namespaceMap))
-	for ns := range namespaceMap {
-		if ns != "" {
-			namespaces = append(namespaces, ns)
-		}
-	}
-	sort.Strings(namespaces)
-
-	for _, ns := range namespaces {
-		// Namespace files define static classes like "checkoutIdl" in the "PulseRPC" namespace
-		sb.WriteString(fmt.Sprintf("using static %s.%sIdl;\n", ns, ns))
-		sb.WriteString(fmt.Sprintf("using %s;\n", ns))
-	}
-	sb.WriteString("\n")
-
-	sb.WriteString("namespace PulseRPC\n")
-	sb.WriteString("{\n")
-
-	// Generate ITransport interface
-	writeITransportCs(&sb)
-
-	// Generate HttpTransport
-	writeHttpTransportCs(&sb)
-
-	// Generate client classes for each interface
-	for _, iface := range idl.Interfaces {
-		writeInterfaceClientCs(&sb, iface, structMap, enumMap)
-	}
-
-	sb.WriteString("}\n")
-
-	return sb.String()
-}
-
-// writeITransportCs generates the ITransport interface
-func writeITransportCs(sb *strings.Builder) {
-	sb.WriteString("public interface ITransport\n")
-	sb.WriteString("{\n")
-	sb.WriteString("    Task<Dictionary<string, object?>> CallAsync(string method, object[] parameters);\n")
-	sb.WriteString("}\n\n")
-}
-
-// writeHttpTransportCs generates the HttpTransport class
-func writeHttpTransportCs(sb *strings.Builder) {
-	sb.WriteString("public class HttpTransport : ITransport\n")
-	sb.WriteString("{\n")
-	sb.WriteString("    private static readonly JsonSerializerOptions _jsonOptions = new JsonSerializerOptions\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        PropertyNamingPolicy = JsonNamingPolicy.CamelCase\n")
-	sb.WriteString("    };\n\n")
-	sb.WriteString("    static HttpTransport()\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        _jsonOptions.Converters.Add(new JsonStringEnumConverter());\n")
-	sb.WriteString("    }\n\n")
-	sb.WriteString("    private readonly HttpClient _httpClient;\n")
-	sb.WriteString("    private readonly string _baseUrl;\n\n")
-	sb.WriteString("    public HttpTransport(string baseUrl, Dictionary<string, string>? headers = null)\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        _baseUrl = baseUrl.TrimEnd('/');\n")
-	sb.WriteString("        _httpClient = new HttpClient();\n")
-	sb.WriteString("        if (headers != null)\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            foreach (var header in headers)\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                _httpClient.DefaultRequestHeaders.Add(header.Key, header.Value);\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("    }\n\n")
-	sb.WriteString("    public async Task<Dictionary<string, object?>> CallAsync(string method, object[] parameters)\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        var requestId = Guid.NewGuid().ToString();\n")
-	sb.WriteString("        var request = new Dictionary<string, object?>\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            { \"jsonrpc\", \"2.0\" },\n")
-	sb.WriteString("            { \"method\", method },\n")
-	sb.WriteString("            { \"params\", parameters },\n")
-	sb.WriteString("            { \"id\", requestId }\n")
-	sb.WriteString("        };\n\n")
-	sb.WriteString("        var json = JsonSerializer.Serialize(request, _jsonOptions);\n")
-	sb.WriteString("        var content = new StringContent(json, System.Text.Encoding.UTF8, \"application/json\");\n\n")
-	sb.WriteString("        var response = await _httpClient.PostAsync(_baseUrl, content);\n")
-	sb.WriteString("        response.EnsureSuccessStatusCode();\n\n")
-	sb.WriteString("        var responseJson = await response.Content.ReadAsStringAsync();\n")
-	sb.WriteString("        var responseDict = JsonSerializer.Deserialize<Dictionary<string, object?>>(responseJson);\n\n")
-	sb.WriteString("        if (responseDict != null && responseDict.TryGetValue(\"error\", out var errorObj) && errorObj != null)\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            // errorObj might be JsonElement or Dictionary<string, object?>\n")
-	sb.WriteString("            var code = -32603;\n")
-	sb.WriteString("            var message = \"Unknown error\";\n")
-	sb.WriteString("            object? data = null;\n")
-	sb.WriteString("            if (errorObj is System.Text.Json.JsonElement errorElem)\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                if (errorElem.TryGetProperty(\"code\", out var codeProp)) code = codeProp.GetInt32();\n")
-	sb.WriteString("                if (errorElem.TryGetProperty(\"message\", out var msgProp)) message = msgProp.GetString() ?? \"Unknown error\";\n")
-	sb.WriteString("                if (errorElem.TryGetProperty(\"data\", out var dataProp)) data = dataProp;\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("            else if (errorObj is Dictionary<string, object?> errorDict)\n")
-	sb.WriteString("            {\n")
-	sb.WriteString("                if (errorDict.TryGetValue(\"code\", out var codeObj)) code = Convert.ToInt32(codeObj);\n")
-	sb.WriteString("                if (errorDict.TryGetValue(\"message\", out var msgObj)) message = msgObj?.ToString() ?? \"Unknown error\";\n")
-	sb.WriteString("                if (errorDict.TryGetValue(\"data\", out var dataObj)) data = dataObj;\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("            throw new RPCError(code, message, data);\n")
-	sb.WriteString("        }\n\n")
-	sb.WriteString("        return responseDict ?? new Dictionary<string, object?>();\n")
-	sb.WriteString("    }\n")
-	sb.WriteString("}\n\n")
-}
-
-// writeInterfaceClientCs generates a client class for an interface that implements the interface
-func writeInterfaceClientCs(sb *strings.Builder, iface *parser.Interface, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) {
-	clientClassName := iface.Name + "Client"
-	fmt.Fprintf(sb, "public class %s : I%s\n", clientClassName, iface.Name)
-	sb.WriteString("{\n")
-	sb.WriteString("    private readonly ITransport _transport;\n\n")
-	sb.WriteString("    public " + clientClassName + "(ITransport transport)\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        _transport = transport;\n")
-	sb.WriteString("    }\n\n")
-
-	// Generate methods for each interface method
-	for _, method := range iface.Methods {
-		writeClientMethodImplCs(sb, iface, method, structMap, enumMap)
-		sb.WriteString("\n")
-	}
-
-	sb.WriteString("}\n\n")
-}
-
-// writeClientMethodImplCs generates a synchronous method implementation for a client class
-func writeClientMethodImplCs(sb *strings.Builder, iface *parser.Interface, method *parser.Method, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) {
-	// Return type
-	var returnTypeStr string
-	if method.ReturnType != nil {
-		returnTypeStr = mapTypeToCsType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
-	} else {
-		returnTypeStr = "object?"
-	}
-
-	// Generate synchronous method that implements the interface
-	fmt.Fprintf(sb, "    public %s %s(", returnTypeStr, method.Name)
-
-	// Parameters
-	for i, param := range method.Parameters {
-		if i > 0 {
-			sb.WriteString(", ")
-		}
-		paramType := mapTypeToCsType(param.Type, structMap, enumMap, false)
-		sb.WriteString(paramType)
-		sb.WriteString(" ")
-		fmt.Fprintf(sb, "%s", param.Name)
-	}
-	sb.WriteString(")\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        var task = ")
-	fmt.Fprintf(sb, "%sAsync(", method.Name)
-	for i, param := range method.Parameters {
-		if i > 0 {
-			sb.WriteString(", ")
-		}
-		fmt.Fprintf(sb, "%s", param.Name)
-	}
-	sb.WriteString(");\n")
-	sb.WriteString("        return task.GetAwaiter().GetResult();\n")
-	sb.WriteString("    }\n")
-
-	// Generate async version as well for convenience
-	sb.WriteString("\n")
-	fmt.Fprintf(sb, "    public async Task<%s> %sAsync(", returnTypeStr, method.Name)
-
-	// Parameters for async
-	for i, param := range method.Parameters {
-		if i > 0 {
-			sb.WriteString(", ")
-		}
-		paramType := mapTypeToCsType(param.Type, structMap, enumMap, false)
-		sb.WriteString(paramType)
-		sb.WriteString(" ")
-		fmt.Fprintf(sb, "%s", param.Name)
-	}
-	sb.WriteString(")\n")
-	sb.WriteString("    {\n")
-
-	// Create parameters array for transport
-	fmt.Fprintf(sb, "        var method = \"%s.%s\";\n", iface.Name, method.Name)
-	sb.WriteString("        var parameters = new object[] { ")
-	for i, param := range method.Parameters {
-		if i > 0 {
-			sb.WriteString(", ")
-		}
-		fmt.Fprintf(sb, "%s", param.Name)
-	}
-	sb.WriteString(" };\n\n")
-
-	sb.WriteString("        var response = await _transport.CallAsync(method, parameters);\n")
-	sb.WriteString("        if (!response.TryGetValue(\"result\", out var result)) {\n")
-	if method.ReturnOptional {
-		sb.WriteString("            return default;\n")
-	} else {
-		sb.WriteString("            throw new RPCError(-32603, \"Internal error\", \"Missing result in response\");\n")
-	}
-	sb.WriteString("        }\n\n")
-
-	// Deserialize response to typed object
-	if method.ReturnType != nil {
-		sb.WriteString("        // Deserialize to return type\n")
-		sb.WriteString("        string resultJsonStr;\n")
-		sb.WriteString("        if (result is System.Text.Json.JsonElement jsonElement)\n")
-		sb.WriteString("        {\n")
-		sb.WriteString("            resultJsonStr = jsonElement.GetRawText();\n")
-		sb.WriteString("        }\n")
-		sb.WriteString("        else\n")
-		sb.WriteString("        {\n")
-		sb.WriteString("            resultJsonStr = JsonSerializer.Serialize(result);\n")
-		sb.WriteString("        }\n")
-
-		returnTypeStr = mapTypeToCsType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
-		sb.WriteString("        var clientJsonOptions = new JsonSerializerOptions\n")
-		sb.WriteString("        {\n")
-		sb.WriteString("            PropertyNameCaseInsensitive = true\n")
-		sb.WriteString("        };\n")
-		sb.WriteString("        clientJsonOptions.Converters.Add(new JsonStringEnumConverter());\n")
-		sb.WriteString("        return JsonSerializer.Deserialize<")
-		fmt.Fprintf(sb, "%s", returnTypeStr)
-		sb.WriteString(">(resultJsonStr, clientJsonOptions);\n")
-	} else {
-		sb.WriteString("        return result;\n")
-	}
-	sb.WriteString("    }\n")
-}
-
-// generateTestServerCs generates TestServer.cs with concrete implementations of all interfaces
-func generateTestServerCs(idl *parser.IDL, allNamespaces []string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) string {
-	var sb strings.Builder
-
-	sb.WriteString("// Generated by pulserpc - do not edit\n")
-	sb.WriteString("// Test server implementation for integration testing\n\n")
-	sb.WriteString("using System;\n")
-	sb.WriteString("using System.Collections.Generic;\n")
-	sb.WriteString("using System.Linq;\n")
-	sb.WriteString("using System.Threading.Tasks;\n")
-	sb.WriteString("using Microsoft.Extensions.Logging;\n")
-	sb.WriteString("using Microsoft.Extensions.DependencyInjection;\n")
-	sb.WriteString("using PulseRPC;\n")
-
-	for _, ns := range allNamespaces {
-		sb.WriteString(fmt.Sprintf("using %s;\n", ns))
-	}
-	sb.WriteString("\n")
-
-	// Generate implementation classes for each interface
-	for _, iface := range idl.Interfaces {
-		writeTestInterfaceImplCs(&sb, iface, structMap, enumMap)
-	}
-
-	// Generate main entry point
-	sb.WriteString("public class Program\n")
-	sb.WriteString("{\n")
-	sb.WriteString("    public static async Task Main(string[] args)\n")
-	sb.WriteString("    {\n")
-	sb.WriteString("        // Configure logging\n")
-	sb.WriteString("        using var loggerFactory = LoggerFactory.Create(builder =>\n")
-	sb.WriteString("        {\n")
-	sb.WriteString("            builder.AddConsole().SetMinimumLevel(LogLevel.Debug);\n")
-	sb.WriteString("        });\n")
-	sb.WriteString("        var logger = loggerFactory.CreateLogger<PulseRPCServer>();\n\n")
-	sb.WriteString("        var server = new PulseRPCServer(logger);\n")
-	for _, iface := range idl.Interfaces {
-		implName := iface.Name + "Impl"
-		fmt.Fprintf(&sb, "        server.Register%s(new %s());\n", iface.Name, implName)
-	}
-	sb.WriteString("        await server.RunAsync(\"0.0.0.0\", 8080);\n")
-	sb.WriteString("    }\n")
-	sb.WriteString("}\n")
+	sb.WriteString("        var logger = loggerFactory.CreateLogger<PulseRPCServer>();\n\n")
+	sb.WriteString("        var server = new PulseRPCServer(logger);\n")
+	for _, iface := range idl.Interfaces {
+		implName := iface.Name + "Impl"
+		fmt.Fprintf(&sb, "        server.Register%s(new %s());\n", iface.Name, implName)
+	}
+	sb.WriteString("        await server.RunAsync(\"0.0.0.0\", 8080);\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("}\n")
 
 	return sb.String()
 }
 
 // generateTestClientCs generates TestClient.cs test program
-func generateTestClientCs(idl *parser.IDL, allNamespaces []string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) string {
+func generateTestClientCs(idl *parser.IDL, allNamespaces []string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, testVectors bool) string {
 	var sb strings.Builder
 
 	sb.WriteString("// Generated by pulserpc - do not edit\n")
 	sb.WriteString("// Test client program for integration testing\n\n")
 	sb.WriteString("using System;\n")
 	sb.WriteString("using System.Collections.Generic;\n")
+	if testVectors {
+		sb.WriteString("using System.IO;\n")
+		sb.WriteString("using System.Net.Http;\n")
+		sb.WriteString("using System.Text;\n")
+		sb.WriteString("using System.Text.Json;\n")
+	}
 	sb.WriteString("using System.Threading.Tasks;\n")
 	sb.WriteString("using PulseRPC;\n")
 
@@ -1610,6 +1072,11 @@ func generateTestClientCs(idl *parser.IDL, allNamespaces []string, structMap map
 		}
 	}
 
+	sb.WriteString("        errors.AddRange(await TestConcurrentCalls(transport));\n\n")
+	if testVectors {
+		sb.WriteString("        errors.AddRange(await RunTestVectors(baseUrl));\n\n")
+	}
+
 	sb.WriteString("        if (errors.Count > 0)\n")
 	sb.WriteString("        {\n")
 	sb.WriteString("            Console.WriteLine($\"FAILED: {errors.Count} test(s) failed\");\n")
@@ -1625,67 +1092,199 @@ func generateTestClientCs(idl *parser.IDL, allNamespaces []string, structMap map
 	sb.WriteString("            Environment.Exit(0);\n")
 	sb.WriteString("        }\n")
 	sb.WriteString("    }\n")
+	writeConcurrentCallsTestCs(&sb)
+	if testVectors {
+		writeTestVectorReplayCs(&sb)
+	}
 	sb.WriteString("}\n")
 
 	return sb.String()
 }
 
-// generateTestServerCsproj generates TestServer.csproj project file
-// Note: .NET SDK automatically includes all .cs files in the project directory,
-// so we exclude Client.cs and TestClient.cs to avoid duplicate class definitions.
-func generateTestServerCsproj() string {
-	var sb strings.Builder
+// writeTestVectorReplayCs generates the methods that replay testvectors.json
+// against the server and compare each response with the expected one
+func writeTestVectorReplayCs(sb *strings.Builder) {
+	sb.WriteString("\n")
+	sb.WriteString("    // Replays testvectors.json and returns a message for each mismatch\n")
+	sb.WriteString("    private static async Task<List<string>> RunTestVectors(string baseUrl)\n")
+	sb.WriteString("    {\n")
+	sb.WriteString("        var failures = new List<string>();\n")
+	sb.WriteString("        JsonElement vectors;\n")
+	sb.WriteString("        try\n")
+	sb.WriteString("        {\n")
+	sb.WriteString("            using var doc = JsonDocument.Parse(File.ReadAllText(\"testvectors.json\"));\n")
+	sb.WriteString("            vectors = doc.RootElement.GetProperty(\"vectors\").Clone();\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        catch (Exception ex)\n")
+	sb.WriteString("        {\n")
+	sb.WriteString("            failures.Add($\"testvectors.json: {ex.Message}\");\n")
+	sb.WriteString("            return failures;\n")
+	sb.WriteString("        }\n\n")
+	sb.WriteString("        using var http = new HttpClient();\n")
+	sb.WriteString("        foreach (var v in vectors.EnumerateArray())\n")
+	sb.WriteString("        {\n")
+	sb.WriteString("            var name = v.GetProperty(\"name\").GetString();\n")
+	sb.WriteString("            try\n")
+	sb.WriteString("            {\n")
+	sb.WriteString("                var content = new StringContent(v.GetProperty(\"request\").GetRawText(), Encoding.UTF8, \"application/json\");\n")
+	sb.WriteString("                using var resp = await http.PostAsync(baseUrl, content);\n")
+	sb.WriteString("                using var actual = JsonDocument.Parse(await resp.Content.ReadAsStringAsync());\n")
+	sb.WriteString("                var msg = CompareTestVector(v.GetProperty(\"response\"), actual.RootElement);\n")
+	sb.WriteString("                if (msg != \"\")\n")
+	sb.WriteString("                {\n")
+	sb.WriteString("                    failures.Add($\"vector {name}: {msg}\");\n")
+	sb.WriteString("                    continue;\n")
+	sb.WriteString("                }\n")
+	sb.WriteString("                Console.WriteLine($\"✓ vector {name} passed\");\n")
+	sb.WriteString("            }\n")
+	sb.WriteString("            catch (Exception ex)\n")
+	sb.WriteString("            {\n")
+	sb.WriteString("                failures.Add($\"vector {name}: {ex.Message}\");\n")
+	sb.WriteString("            }\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        return failures;\n")
+	sb.WriteString("    }\n\n")
 
-	sb.WriteString("<Project Sdk=\"Microsoft.NET.Sdk\">\n\n")
-	sb.WriteString("  <PropertyGroup>\n")
-	sb.WriteString("    <TargetFramework>net8.0</TargetFramework>\n")
-	sb.WriteString("    <ImplicitUsings>enable</ImplicitUsings>\n")
-	sb.WriteString("    <Nullable>enable</Nullable>\n")
-	sb.WriteString("    <LangVersion>latest</LangVersion>\n")
-	sb.WriteString("    <OutputType>Exe</OutputType>\n")
-	sb.WriteString("  </PropertyGroup>\n\n")
+	sb.WriteString("    // Returns an empty string if actual satisfies expected\n")
+	sb.WriteString("    private static string CompareTestVector(JsonElement expected, JsonElement actual)\n")
+	sb.WriteString("    {\n")
+	sb.WriteString("        if (!VectorValuesEqual(VectorProperty(expected, \"id\"), VectorProperty(actual, \"id\")))\n")
+	sb.WriteString("            return $\"expected id {VectorProperty(expected, \"id\")}, got {VectorProperty(actual, \"id\")}\";\n")
+	sb.WriteString("        var actualError = VectorProperty(actual, \"error\");\n")
+	sb.WriteString("        if (expected.TryGetProperty(\"error\", out var expectedError))\n")
+	sb.WriteString("        {\n")
+	sb.WriteString("            if (actualError.ValueKind != JsonValueKind.Object || !VectorValuesEqual(expectedError.GetProperty(\"code\"), VectorProperty(actualError, \"code\")))\n")
+	sb.WriteString("                return $\"expected error code {expectedError.GetProperty(\"code\")}, got {actual.GetRawText()}\";\n")
+	sb.WriteString("            return \"\";\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        if (actualError.ValueKind != JsonValueKind.Undefined)\n")
+	sb.WriteString("            return $\"unexpected error {actualError.GetRawText()}\";\n")
+	sb.WriteString("        if (expected.TryGetProperty(\"result\", out var expectedResult) && !VectorValuesEqual(expectedResult, VectorProperty(actual, \"result\")))\n")
+	sb.WriteString("            return $\"expected result {expectedResult.GetRawText()}, got {actual.GetRawText()}\";\n")
+	sb.WriteString("        return \"\";\n")
+	sb.WriteString("    }\n\n")
 
-	sb.WriteString("  <ItemGroup>\n")
-	sb.WriteString("    <FrameworkReference Include=\"Microsoft.AspNetCore.App\" />\n")
-	sb.WriteString("  </ItemGroup>\n\n")
+	sb.WriteString("    private static JsonElement VectorProperty(JsonElement element, string name)\n")
+	sb.WriteString("    {\n")
+	sb.WriteString("        return element.ValueKind == JsonValueKind.Object && element.TryGetProperty(name, out var value) ? value : default;\n")
+	sb.WriteString("    }\n\n")
 
-	sb.WriteString("  <ItemGroup>\n")
-	sb.WriteString("    <Compile Remove=\"Client.cs\" />\n")
-	sb.WriteString("    <Compile Remove=\"TestClient.cs\" />\n")
-	sb.WriteString("  </ItemGroup>\n\n")
+	sb.WriteString("    // Compares JSON values ignoring key order; numbers compare numerically and a missing value equals null\n")
+	sb.WriteString("    private static bool VectorValuesEqual(JsonElement expected, JsonElement actual)\n")
+	sb.WriteString("    {\n")
+	sb.WriteString("        var expectedKind = expected.ValueKind == JsonValueKind.Undefined ? JsonValueKind.Null : expected.ValueKind;\n")
+	sb.WriteString("        var actualKind = actual.ValueKind == JsonValueKind.Undefined ? JsonValueKind.Null : actual.ValueKind;\n")
+	sb.WriteString("        if (expectedKind != actualKind) return false;\n")
+	sb.WriteString("        switch (expectedKind)\n")
+	sb.WriteString("        {\n")
+	sb.WriteString("            case JsonValueKind.Number:\n")
+	sb.WriteString("                return expected.GetDouble() == actual.GetDouble();\n")
+	sb.WriteString("            case JsonValueKind.String:\n")
+	sb.WriteString("                return expected.GetString() == actual.GetString();\n")
+	sb.WriteString("            case JsonValueKind.Array:\n")
+	sb.WriteString("                if (expected.GetArrayLength() != actual.GetArrayLength()) return false;\n")
+	sb.WriteString("                for (int i = 0; i < expected.GetArrayLength(); i++)\n")
+	sb.WriteString("                {\n")
+	sb.WriteString("                    if (!VectorValuesEqual(expected[i], actual[i])) return false;\n")
+	sb.WriteString("                }\n")
+	sb.WriteString("                return true;\n")
+	sb.WriteString("            case JsonValueKind.Object:\n")
+	sb.WriteString("                var count = 0;\n")
+	sb.WriteString("                foreach (var prop in expected.EnumerateObject())\n")
+	sb.WriteString("                {\n")
+	sb.WriteString("                    if (!actual.TryGetProperty(prop.Name, out var value) || !VectorValuesEqual(prop.Value, value)) return false;\n")
+	sb.WriteString("                    count++;\n")
+	sb.WriteString("                }\n")
+	sb.WriteString("                foreach (var _ in actual.EnumerateObject()) count--;\n")
+	sb.WriteString("                return count == 0;\n")
+	sb.WriteString("            default:\n")
+	sb.WriteString("                return true;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("    }\n")
+}
 
-	sb.WriteString("</Project>\n")
+// csharpTestProject is the view model for the generated test project files
+type csharpTestProject struct {
+	Packages []nugetPackage
+	Exclude  []string
+	// RestoreSources replaces the NuGet package sources; see -dependency-mirror
+	RestoreSources string
+}
 
-	return sb.String()
+type nugetPackage struct {
+	Name    string
+	Version string
 }
 
-// generateTestClientCsproj generates TestClient.csproj project file
-// Note: .NET SDK automatically includes all .cs files in the project directory,
-// so we exclude Server.cs and TestServer.cs to avoid duplicate class definitions.
-func generateTestClientCsproj() string {
-	var sb strings.Builder
+// csharpHarnessFiles are the files of the xUnit harness, which the other test
+// projects must not compile since they do not reference xUnit
+var csharpHarnessFiles = []string{"HarnessTests.cs", "HarnessHandlers.cs"}
 
-	sb.WriteString("<Project Sdk=\"Microsoft.NET.Sdk\">\n\n")
-	sb.WriteString("  <PropertyGroup>\n")
-	sb.WriteString("    <TargetFramework>net8.0</TargetFramework>\n")
-	sb.WriteString("    <ImplicitUsings>enable</ImplicitUsings>\n")
-	sb.WriteString("    <Nullable>enable</Nullable>\n")
-	sb.WriteString("    <LangVersion>latest</LangVersion>\n")
-	sb.WriteString("    <OutputType>Exe</OutputType>\n")
-	sb.WriteString("  </PropertyGroup>\n\n")
+// csharpContractFile holds the xUnit contract tests, which only their own test
+// project compiles
+const csharpContractFile = "ContractTests.cs"
 
-	sb.WriteString("  <ItemGroup>\n")
-	sb.WriteString("    <FrameworkReference Include=\"Microsoft.AspNetCore.App\" />\n")
-	sb.WriteString("  </ItemGroup>\n\n")
+// csharpClientFiles are Client.cs and the transports built on it, which the test
+// server project leaves out along with the client
+var csharpClientFiles = []string{"Client.cs", "Discovery.cs", "Retry.cs", "ShadowTransport.cs", "Outbox.cs"}
+
+// generateTestServerCsproj generates TestServer.csproj project file
+// Note: .NET SDK automatically includes all .cs files in the project directory,
+// so we exclude the client files and TestClient.cs to avoid duplicate class definitions.
+func generateTestServerCsproj(ts *templateSet, harness, contracts bool) string {
+	project := csharpTestProject{Exclude: append(append([]string{}, csharpClientFiles...), "TestClient.cs")}
+	if harness {
+		project.Exclude = append(project.Exclude, csharpHarnessFiles...)
+	}
+	if contracts {
+		project.Exclude = append(project.Exclude, csharpContractFile)
+	}
+	return ts.renderString("csharp/test.csproj.tmpl", project)
+}
 
-	sb.WriteString("  <ItemGroup>\n")
-	sb.WriteString("    <Compile Remove=\"Server.cs\" />\n")
-	sb.WriteString("    <Compile Remove=\"TestServer.cs\" />\n")
-	sb.WriteString("  </ItemGroup>\n\n")
+// generateTestClientCsproj generates TestClient.csproj project file
+// Note: .NET SDK automatically includes all .cs files in the project directory,
+// so we exclude Server.cs and TestServer.cs to avoid duplicate class definitions.
+// Serverless.cs extends the server, so it is excluded with it.
+func generateTestClientCsproj(ts *templateSet, harness, contracts, serverless bool) string {
+	project := csharpTestProject{Exclude: []string{"Server.cs", "TestServer.cs"}}
+	if serverless {
+		project.Exclude = append(project.Exclude, "Serverless.cs")
+	}
+	if harness {
+		project.Exclude = append(project.Exclude, csharpHarnessFiles...)
+	}
+	if contracts {
+		project.Exclude = append(project.Exclude, csharpContractFile)
+	}
+	return ts.renderString("csharp/test.csproj.tmpl", project)
+}
 
-	sb.WriteString("</Project>\n")
+// generateHarnessCsproj generates HarnessTests.csproj, an xUnit v3 test project for
+// the harness. It leaves out the test server and client, which each define Program,
+// and the contract tests, which have their own project.
+func generateHarnessCsproj(ts *templateSet, versions map[string]string, contracts bool, mirror dependencyMirror) string {
+	project := csharpTestProject{Exclude: []string{"TestServer.cs", "TestClient.cs"}, RestoreSources: mirror.URL("nuget")}
+	if contracts {
+		project.Exclude = append(project.Exclude, csharpContractFile)
+	}
+	for _, d := range csharpHarnessDependencies(versions) {
+		project.Packages = append(project.Packages, nugetPackage{Name: d.Name, Version: d.Version})
+	}
+	return ts.renderString("csharp/test.csproj.tmpl", project)
+}
 
-	return sb.String()
+// generateContractCsproj generates ContractTests.csproj, an xUnit v3 test project for
+// the contract tests, leaving out the test programs and the harness
+func generateContractCsproj(ts *templateSet, versions map[string]string, harness bool, mirror dependencyMirror) string {
+	project := csharpTestProject{Exclude: []string{"TestServer.cs", "TestClient.cs"}, RestoreSources: mirror.URL("nuget")}
+	if harness {
+		project.Exclude = append(project.Exclude, csharpHarnessFiles...)
+	}
+	for _, d := range csharpHarnessDependencies(versions) {
+		project.Packages = append(project.Packages, nugetPackage{Name: d.Name, Version: d.Version})
+	}
+	return ts.renderString("csharp/test.csproj.tmpl", project)
 }
 
 // writeTestInterfaceImplCs generates a concrete implementation class for an interface
@@ -1717,7 +1316,7 @@ func writeTestMethodImplCs(sb *strings.Builder, iface *parser.Interface, method
 		if i > 0 {
 			sb.WriteString(", ")
 		}
-		paramType := mapTypeToCsType(param.Type, structMap, enumMap, false)
+		paramType := mapParamTypeToCsType(param, structMap, enumMap)
 		fmt.Fprintf(sb, "%s %s", paramType, param.Name)
 	}
 	sb.WriteString(")\n")
@@ -1869,7 +1468,7 @@ func writeMinimalStructInstanceCs(sb *strings.Builder, typeName string, structDe
 
 		fieldName := field.Name
 		// Convert to PascalCase for C#
-		csFieldName := snakeToPascalCase(fieldName)
+		csFieldName := naming.SnakeToPascal(fieldName)
 
 		// Generate appropriate default value based on field type
 		if field.Type.IsBuiltIn() {
@@ -1906,7 +1505,7 @@ func writeMinimalStructInstanceCs(sb *strings.Builder, typeName string, structDe
 					if nestedField.Optional {
 						continue
 					}
-					nestedCsFieldName := snakeToPascalCase(nestedField.Name)
+					nestedCsFieldName := naming.SnakeToPascal(nestedField.Name)
 					if nestedField.Type.IsBuiltIn() {
 						switch nestedField.Type.BuiltIn {
 						case "string":
@@ -2038,14 +1637,14 @@ func writeStructInstanceCs(sb *strings.Builder, structDef *parser.Struct, struct
 			if fieldCount > 0 {
 				sb.WriteString(",\n")
 			}
-			propName := snakeToPascalCase(field.Name)
+			propName := naming.SnakeToPascal(field.Name)
 			fmt.Fprintf(sb, "            %s = null", propName)
 			fieldCount++
 		} else if !field.Optional {
 			if fieldCount > 0 {
 				sb.WriteString(",\n")
 			}
-			propName := snakeToPascalCase(field.Name)
+			propName := naming.SnakeToPascal(field.Name)
 			fmt.Fprintf(sb, "            %s = ", propName)
 			writeTestFieldValueCs(sb, field.Type, structMap, enumMap)
 			fieldCount++
@@ -0,0 +1,179 @@
+package generator
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestCSharpGeneratorInternalVisibility(t *testing.T) {
+	idl := &parser.IDL{
+		Structs: []*parser.Struct{
+			{Name: "Book", Fields: []*parser.Field{{Name: "title", Type: &parser.Type{BuiltIn: "string"}}}},
+		},
+		Enums: []*parser.Enum{
+			{Name: "Status", Values: []*parser.EnumValue{{Name: "ok"}}},
+		},
+		Interfaces: []*parser.Interface{
+			{
+				Name: "Library",
+				Methods: []*parser.Method{
+					{Name: "get", ReturnType: &parser.Type{UserDefined: "Book"}},
+				},
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	p := NewCSharpClientServer()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("dir", "", "output dir")
+	p.RegisterFlags(fs)
+	if err := fs.Set("dir", tmpDir); err != nil {
+		t.Fatalf("failed to set dir flag: %v", err)
+	}
+	if err := fs.Set("visibility", "internal"); err != nil {
+		t.Fatalf("failed to set visibility flag: %v", err)
+	}
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	publicType := regexp.MustCompile(`(?m)^\s*public (\w+ )*(class|record|interface|enum|struct) `)
+	files, err := filepath.Glob(filepath.Join(tmpDir, "*.cs"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("expected generated .cs files, got %v (%v)", files, err)
+	}
+	for _, path := range files {
+		name := filepath.Base(path)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		if loc := publicType.Find(content); loc != nil {
+			t.Errorf("%s: expected no public types, found %q", name, strings.TrimSpace(string(loc)))
+		}
+		if !strings.Contains(string(content), "internal ") {
+			t.Errorf("%s: expected internal types", name)
+		}
+	}
+
+	fs.Set("visibility", "protected")
+	if err := p.Generate(idl, fs); err == nil || !strings.Contains(err.Error(), "invalid -visibility") {
+		t.Errorf("expected an invalid -visibility error, got %v", err)
+	}
+}
+
+func TestCSharpGeneratorLazyMetadata(t *testing.T) {
+	idl := largeIDL(t, 500, 500)
+
+	tmpDir := t.TempDir()
+	p := NewCSharpClientServer()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("dir", "", "output dir")
+	p.RegisterFlags(fs)
+	if err := fs.Set("dir", tmpDir); err != nil {
+		t.Fatalf("failed to set dir flag: %v", err)
+	}
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	// Every struct table is built inside a Lazy factory, so loading the assembly
+	// doesn't run it; ALL_STRUCTS reads the Lazy on first use
+	ns, err := os.ReadFile(filepath.Join(tmpDir, "Big.cs"))
+	if err != nil {
+		t.Fatalf("expected Big.cs: %v", err)
+	}
+	nsCode := string(ns)
+	lazy := strings.Index(nsCode, "_allStructs = new System.Lazy<")
+	if lazy < 0 {
+		t.Fatalf("Big.cs should build ALL_STRUCTS lazily:\n%s", nsCode[:min(len(nsCode), 2000)])
+	}
+	if first := strings.Index(nsCode, `{ "Item0", new Dictionary<string, object>`); first < lazy {
+		t.Errorf("struct metadata should be inside the Lazy factory (entry at %d, Lazy at %d)", first, lazy)
+	}
+	if !strings.Contains(nsCode, "ALL_STRUCTS => _allStructs.Value;") {
+		t.Errorf("Big.cs ALL_STRUCTS should read the Lazy value")
+	}
+
+	files, err := filepath.Glob(filepath.Join(tmpDir, "*.cs"))
+	if err != nil {
+		t.Fatalf("failed to list .cs files: %v", err)
+	}
+	eager := regexp.MustCompile(`static (readonly )?Dictionary<string, Dictionary<string, object>> ALL_(STRUCTS|ENUMS) = |static IdlData\(\)`)
+	for _, path := range files {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		if loc := eager.Find(content); loc != nil {
+			t.Errorf("%s builds metadata eagerly: %q", filepath.Base(path), loc)
+		}
+	}
+}
+
+// csharpStartupProgram creates a server for the IDL and reports how many of the
+// generated Lazy metadata tables exist, how many were built by startup and how many
+// after the first use of the metadata, and how long startup and that first use took
+const csharpStartupProgram = `using System.Diagnostics;
+using System.Reflection;
+using PulseRPC;
+
+var clock = Stopwatch.StartNew();
+var server = new PulseRPCServer();
+var startup = clock.Elapsed;
+
+var tables = typeof(PulseRPCServer).Assembly.GetTypes()
+    .SelectMany(t => t.GetFields(BindingFlags.Static | BindingFlags.Public | BindingFlags.NonPublic))
+    .Where(f => f.FieldType.IsGenericType && f.FieldType.GetGenericTypeDefinition() == typeof(Lazy<>))
+    .ToList();
+int Built() => tables.Count(f => (bool)f.FieldType.GetProperty("IsValueCreated")!.GetValue(f.GetValue(null))!);
+var atStartup = Built();
+
+clock.Restart();
+var entries = IdlData.ALL_STRUCTS.Count + IdlData.ALL_ENUMS.Count + IdlData.METHOD_DEFS.Count;
+var firstUse = clock.Elapsed;
+Console.WriteLine($"tables {tables.Count}");
+Console.WriteLine($"built at startup {atStartup}");
+Console.WriteLine($"built after use {Built()}");
+Console.WriteLine($"entries {entries}");
+Console.WriteLine($"startup {startup.TotalMilliseconds:F2} ms");
+Console.WriteLine($"first use {firstUse.TotalMilliseconds:F2} ms");
+`
+
+// TestCSharpLazyMetadataStartup runs a server generated for a large IDL and checks that
+// its metadata is not built at startup, and that building it, the time startup saves,
+// takes longer than startup itself. Run with -v to see the times.
+func TestCSharpLazyMetadataStartup(t *testing.T) {
+	dir := t.TempDir()
+	p := NewCSharpClientServer()
+	if err := p.Generate(largeIDL(t, 500, 500), newTestFlagSet(t, p, dir)); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	out := runDotnetCheck(t, dir, csharpStartupProgram)
+	t.Log(out)
+	if !strings.Contains(out, "built at startup 0\n") {
+		t.Errorf("metadata should not be built at startup:\n%s", out)
+	}
+	if !strings.Contains(out, "built after use 5\n") || !strings.Contains(out, "entries 502\n") {
+		t.Errorf("expected 5 tables holding 500 structs, 1 enum and 1 interface:\n%s", out)
+	}
+	var startup, firstUse float64
+	times := strings.Index(out, "\nstartup ")
+	if times < 0 {
+		t.Fatalf("no times in output:\n%s", out)
+	}
+	if _, err := fmt.Sscanf(out[times:], "\nstartup %f ms\nfirst use %f ms", &startup, &firstUse); err != nil {
+		t.Fatalf("invalid times in output: %v\n%s", err, out)
+	}
+	if startup >= firstUse {
+		t.Errorf("startup took %.2f ms, no less than the %.2f ms the metadata takes to build", startup, firstUse)
+	}
+}
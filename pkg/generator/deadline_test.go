@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestDeadlinePropagationGenerated(t *testing.T) {
+	idl, err := parser.ParseIDL("shop.pulse", "namespace shop\n\ninterface Orders {\n  place(sku string) string\n}")
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	tests := []struct {
+		plugin Plugin
+		files  map[string][]string
+	}{
+		{NewGoClientServer(), map[string][]string{
+			"deadline.go": {"const DeadlineHeader = \"X-PulseRPC-Deadline\""},
+			"server.go":   {"\tctx, cancel := RequestContext(r)\n", "s.dispatch(ctx, handler, interfaceName, methodName, params)"},
+			"client.go":   {"func WithDeadline(ctx context.Context) CallOption {", "req.Header.Set(DeadlineHeader, DeadlineHeaderValue(options.Timeout))"},
+		}},
+		{NewPythonClientServer(), map[string][]string{
+			"pulserpc/deadline.py": {"DEADLINE_HEADER = 'X-PulseRPC-Deadline'"},
+			"server.py":            {"with deadline_scope(headers.get(DEADLINE_HEADER)):"},
+			"client.py":            {"timeout = options.timeout if options.timeout is not None else remaining_time()"},
+		}},
+		{NewTSClientServer(), map[string][]string{
+			"pulserpc/deadline.ts": {"export const DEADLINE_HEADER = 'X-PulseRPC-Deadline';"},
+			"server.ts":            {"req.on('end', () => runWithDeadline(req.headers, () => {"},
+			"client.ts":            {"const timeoutMs = options.timeoutMs || remainingTimeMs();"},
+		}},
+		{NewCSharpClientServer(), map[string][]string{
+			"PulseRPC/Deadline.cs": {"public const string Header = \"X-PulseRPC-Deadline\";"},
+			"Server.cs":            {"using var deadline = Deadline.Begin(context.Request.Headers[Deadline.Header]);"},
+			"Client.cs":            {"var callTimeout = options.Timeout ?? Deadline.Remaining;"},
+		}},
+		{NewJavaClientServer(), map[string][]string{
+			"src/main/java/com/bitmechanic/pulserpc/Deadline.java": {"public static final String HEADER = \"X-PulseRPC-Deadline\";"},
+			"src/main/java/com/example/Server.java":                {"try (Deadline.Scope deadline = Deadline.begin(exchange.getRequestHeaders().getFirst(Deadline.HEADER));"},
+		}},
+	}
+	for _, tt := range tests {
+		tmpDir := t.TempDir()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		tt.plugin.RegisterFlags(fs)
+		for name, value := range map[string]string{"dir": tmpDir, "base-package": "com.example"} {
+			if fs.Lookup(name) != nil {
+				if err := fs.Set(name, value); err != nil {
+					t.Fatalf("failed to set %s flag: %v", name, err)
+				}
+			}
+		}
+		if err := tt.plugin.Generate(idl, fs); err != nil {
+			t.Fatalf("%s: Generate failed: %v", tt.plugin.Name(), err)
+		}
+		for file, wants := range tt.files {
+			content, err := os.ReadFile(filepath.Join(tmpDir, file))
+			if err != nil {
+				t.Fatalf("%s: expected %s: %v", tt.plugin.Name(), file, err)
+			}
+			for _, want := range wants {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("%s: %s does not contain %q", tt.plugin.Name(), file, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Client debug logging: every HTTP transport can log each call with its method,
+// duration, and request and response JSON, for troubleshooting an integration
+// without a proxy. The params and result are masked with the same [sensitive] field
+// marks as formatted values, using the method's parameter and return types, so the
+// log never holds a sensitive value. Logging goes through each language's usual
+// logger at debug level and is off unless that level is enabled: slog in Go, the
+// logging module in Python, an ILogger in C# and java.util.logging in Java.
+// TypeScript has no standard logger, so its transport takes a log function.
+
+// writeDebugLogGo writes SetLogger and the logging CallWithOptions of the Go
+// HTTPTransport, which wraps its call method
+func writeDebugLogGo(sb *strings.Builder) {
+	sb.WriteString("// SetLogger logs every call at debug level with its method, duration, and request and\n")
+	sb.WriteString("// response JSON in which [sensitive] fields are masked as \"***\". Calls are logged only\n")
+	sb.WriteString("// while logger is enabled for slog.LevelDebug, so its level is the verbosity switch;\n")
+	sb.WriteString("// nil turns logging off. Call SetLogger before the first call.\n")
+	sb.WriteString("func (t *HTTPTransport) SetLogger(logger *slog.Logger) {\n")
+	sb.WriteString("	t.logger = logger\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("// CallWithOptions performs a JSON-RPC 2.0 call over HTTP with per-call options\n")
+	sb.WriteString("func (t *HTTPTransport) CallWithOptions(method string, params []interface{}, options CallOptions) (map[string]interface{}, error) {\n")
+	sb.WriteString("	if t.logger == nil || !t.logger.Enabled(context.Background(), slog.LevelDebug) {\n")
+	sb.WriteString("		return t.call(method, params, options)\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	start := time.Now()\n")
+	sb.WriteString("	response, err := t.call(method, params, options)\n")
+	sb.WriteString("	logCall(t.logger, method, params, options, response, err, time.Since(start))\n")
+	sb.WriteString("	return response, err\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("// logCall logs a call at debug level, with its params and result redacted according\n")
+	sb.WriteString("// to the method's parameter and return types\n")
+	sb.WriteString("func logCall(logger *slog.Logger, method string, params []interface{}, options CallOptions, response map[string]interface{}, err error, elapsed time.Duration) {\n")
+	sb.WriteString("	var paramDefs []interface{}\n")
+	sb.WriteString("	var returnType map[string]interface{}\n")
+	sb.WriteString("	if i := strings.LastIndex(method, \".\"); i >= 0 {\n")
+	sb.WriteString("		methodDef := methodDefs[method[:i]][method[i+1:]]\n")
+	sb.WriteString("		paramDefs, _ = methodDef[\"parameters\"].([]interface{})\n")
+	sb.WriteString("		returnType, _ = methodDef[\"returnType\"].(map[string]interface{})\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	redacted := make([]interface{}, len(params))\n")
+	sb.WriteString("	for i, param := range params {\n")
+	sb.WriteString("		redacted[i] = JSONValue(param)\n")
+	sb.WriteString("		if i < len(paramDefs) {\n")
+	sb.WriteString("			paramDef, _ := paramDefs[i].(map[string]interface{})\n")
+	sb.WriteString("			paramType, _ := paramDef[\"type\"].(map[string]interface{})\n")
+	sb.WriteString("			redacted[i] = RedactJSON(redacted[i], paramType, ALL_STRUCTS)\n")
+	sb.WriteString("		}\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	encode := func(v interface{}) string {\n")
+	sb.WriteString("		b, err := json.Marshal(v)\n")
+	sb.WriteString("		if err != nil {\n")
+	sb.WriteString("			return fmt.Sprintf(\"<%v>\", err)\n")
+	sb.WriteString("		}\n")
+	sb.WriteString("		return string(b)\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	attrs := []slog.Attr{\n")
+	sb.WriteString("		slog.String(\"method\", method),\n")
+	sb.WriteString("		slog.Duration(\"duration\", elapsed),\n")
+	sb.WriteString("		slog.String(\"request\", encode(requestParams(redacted, options))),\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	if err != nil {\n")
+	sb.WriteString("		attrs = append(attrs, slog.String(\"error\", err.Error()))\n")
+	sb.WriteString("	} else {\n")
+	sb.WriteString("		logged := make(map[string]interface{}, len(response))\n")
+	sb.WriteString("		for k, v := range response {\n")
+	sb.WriteString("			logged[k] = v\n")
+	sb.WriteString("		}\n")
+	sb.WriteString("		logged[\"result\"] = RedactJSON(response[\"result\"], returnType, ALL_STRUCTS)\n")
+	sb.WriteString("		attrs = append(attrs, slog.String(\"response\", encode(logged)))\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	logger.LogAttrs(context.Background(), slog.LevelDebug, \"pulserpc call\", attrs...)\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeLogCallPy writes the module logger of the Python client and _log_call, which
+// logs a call of HTTPTransport
+func writeLogCallPy(sb *strings.Builder) {
+	sb.WriteString(`# Logs every HTTPTransport call at DEBUG level, with [sensitive] fields masked
+logger = logging.getLogger('pulserpc.client')
+
+
+def _log_call(method: str, params: list, options: CallOptions, response: Optional[dict],
+              error: Optional[Exception], elapsed: float) -> None:
+    """Log a call with its method, duration, and request and response JSON, with the
+    params and result redacted according to the method's parameter and return types"""
+    interface, _, name = method.rpartition('.')
+    method_def = METHOD_DEFS.get(interface, {}).get(name, {})
+    param_defs = method_def.get('parameters', [])
+    redacted = [redact_value(param, param_defs[i]['type'], ALL_STRUCTS) if i < len(param_defs) else param
+                for i, param in enumerate(params)]
+    request = json.dumps(options.request_params(redacted), default=str)
+    if error is not None:
+        logger.debug('pulserpc call method=%s duration=%.3fms request=%s error=%s',
+                     method, elapsed * 1000, request, error)
+        return
+    logged = dict(response or {})
+    logged['result'] = redact_value(logged.get('result'), method_def.get('returnType', {}), ALL_STRUCTS)
+    logger.debug('pulserpc call method=%s duration=%.3fms request=%s response=%s',
+                 method, elapsed * 1000, request, json.dumps(logged, default=str))
+
+
+`)
+}
+
+// writeLoggedCallPy writes the body of HTTPTransport.call_with_options, which logs
+// the call made by _call when the logger is enabled for DEBUG
+func writeLoggedCallPy(sb *strings.Builder) {
+	sb.WriteString(`        if not logger.isEnabledFor(logging.DEBUG):
+            return self._call(method, params, options)
+        start = time.monotonic()
+        try:
+            response = self._call(method, params, options)
+        except Exception as e:
+            _log_call(method, params, options, None, e, time.monotonic() - start)
+            raise
+        _log_call(method, params, options, response, None, time.monotonic() - start)
+        return response
+
+    def _call(self, method: str, params: list, options: CallOptions) -> dict:
+        """Perform a call without logging it"""
+`)
+}
+
+// writeLogCallTs writes logCall, which logs a call of the TypeScript HTTPTransport
+func writeLogCallTs(sb *strings.Builder, packagePrefix string) {
+	sb.WriteString("/**\n")
+	sb.WriteString(" * Logs a call with its method, duration, and request and response JSON, with the\n")
+	sb.WriteString(" * params and result redacted according to the method's parameter and return types.\n")
+	sb.WriteString(" */\n")
+	fmt.Fprintf(sb, "function logCall(log: (line: string) => void, method: string, params: any[], options: %s,\n", applyPackagePrefix("CallOptions", packagePrefix))
+	sb.WriteString("                 response: any, error: any, elapsedMs: number): void {\n")
+	sb.WriteString("  const dot = method.lastIndexOf('.');\n")
+	fmt.Fprintf(sb, "  const methodDef = dot >= 0 ? %s[method.slice(0, dot)]?.[method.slice(dot + 1)] : undefined;\n", applyPackagePrefix("METHOD_DEFS", packagePrefix))
+	sb.WriteString("  const paramDefs: any[] = methodDef?.parameters ?? [];\n")
+	sb.WriteString("  const redacted = params.map((param, i) => (i < paramDefs.length ? redactValue(param, paramDefs[i].type, ALL_STRUCTS) : param));\n")
+	sb.WriteString("  const line = `pulserpc call method=${method} duration=${elapsedMs.toFixed(3)}ms request=${JSON.stringify(requestParams(redacted, options))}`;\n")
+	sb.WriteString("  if (error !== undefined) {\n")
+	sb.WriteString("    log(`${line} error=${error instanceof Error ? error.message : String(error)}`);\n")
+	sb.WriteString("    return;\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  const logged = { ...response, result: redactValue(response?.result, methodDef?.returnType ?? {}, ALL_STRUCTS) };\n")
+	sb.WriteString("  log(`${line} response=${JSON.stringify(logged)}`);\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeLoggedCallTs writes setDebugLog and the callWithOptions of the TypeScript
+// HTTPTransport, which logs the call made by sendCall while a log function is set
+func writeLoggedCallTs(sb *strings.Builder, optionsName string) {
+	sb.WriteString("  /**\n")
+	sb.WriteString("   * Logs every call with its method, duration, and request and response JSON, in which\n")
+	sb.WriteString("   * [sensitive] fields are masked as \"***\". log defaults to console.debug; null turns\n")
+	sb.WriteString("   * logging off, which is the default.\n")
+	sb.WriteString("   */\n")
+	sb.WriteString("  setDebugLog(log: ((line: string) => void) | null = console.debug): void {\n")
+	sb.WriteString("    this.debugLog = log;\n")
+	sb.WriteString("  }\n\n")
+
+	fmt.Fprintf(sb, "  async callWithOptions(method: string, params: any[], options: %s): Promise<any> {\n", optionsName)
+	sb.WriteString("    const log = this.debugLog;\n")
+	sb.WriteString("    if (log === null) {\n")
+	sb.WriteString("      return this.sendCall(method, params, options);\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    const start = performance.now();\n")
+	sb.WriteString("    let response: any;\n")
+	sb.WriteString("    try {\n")
+	sb.WriteString("      response = await this.sendCall(method, params, options);\n")
+	sb.WriteString("    } catch (err) {\n")
+	sb.WriteString("      logCall(log, method, params, options, undefined, err, performance.now() - start);\n")
+	sb.WriteString("      throw err;\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    logCall(log, method, params, options, response, undefined, performance.now() - start);\n")
+	sb.WriteString("    return response;\n")
+	sb.WriteString("  }\n\n")
+}
+
+// writeLoggedCallCs writes the Logger property and the CallAsync of the C#
+// HttpTransport, which logs the call made by SendCallAsync while Logger is enabled
+// for Debug. The declaration of SendCallAsync is written last, so its body follows.
+func writeLoggedCallCs(sb *strings.Builder) {
+	sb.WriteString("    /// <summary>\n")
+	sb.WriteString("    /// Logs every call at Debug level with its method, duration, and request and response\n")
+	sb.WriteString("    /// JSON, in which [sensitive] fields are masked as \"***\". Calls are logged only while\n")
+	sb.WriteString("    /// the logger is enabled for LogLevel.Debug, so its level is the verbosity switch.\n")
+	sb.WriteString("    /// </summary>\n")
+	sb.WriteString("    public ILogger? Logger { get; set; }\n\n")
+	sb.WriteString("    public async Task<Dictionary<string, object?>> CallAsync(string method, object[] parameters, CallOptions options)\n")
+	sb.WriteString("    {\n")
+	sb.WriteString("        var logger = Logger;\n")
+	sb.WriteString("        if (logger == null || !logger.IsEnabled(LogLevel.Debug))\n")
+	sb.WriteString("        {\n")
+	sb.WriteString("            return await SendCallAsync(method, parameters, options);\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        var stopwatch = System.Diagnostics.Stopwatch.StartNew();\n")
+	sb.WriteString("        try\n")
+	sb.WriteString("        {\n")
+	sb.WriteString("            var response = await SendCallAsync(method, parameters, options);\n")
+	sb.WriteString("            LogCall(logger, method, parameters, options, response, null, stopwatch.Elapsed);\n")
+	sb.WriteString("            return response;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        catch (Exception e)\n")
+	sb.WriteString("        {\n")
+	sb.WriteString("            LogCall(logger, method, parameters, options, null, e, stopwatch.Elapsed);\n")
+	sb.WriteString("            throw;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("    }\n\n")
+	sb.WriteString("    // Logs a call with its params and result redacted according to the method's parameter\n")
+	sb.WriteString("    // and return types\n")
+	sb.WriteString("    private static void LogCall(ILogger logger, string method, object[] parameters, CallOptions options,\n")
+	sb.WriteString("        Dictionary<string, object?>? response, Exception? error, TimeSpan elapsed)\n")
+	sb.WriteString("    {\n")
+	sb.WriteString("        Dictionary<string, object>? methodDef = null;\n")
+	sb.WriteString("        var dot = method.LastIndexOf('.');\n")
+	sb.WriteString("        if (dot >= 0 && IdlData.METHOD_DEFS.TryGetValue(method[..dot], out var methods))\n")
+	sb.WriteString("        {\n")
+	sb.WriteString("            methods.TryGetValue(method[(dot + 1)..], out methodDef);\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        var paramDefs = methodDef?[\"parameters\"] as System.Collections.IList;\n")
+	sb.WriteString("        var redacted = new object?[parameters.Length];\n")
+	sb.WriteString("        for (var i = 0; i < parameters.Length; i++)\n")
+	sb.WriteString("        {\n")
+	sb.WriteString("            var node = JsonSerializer.SerializeToNode(parameters[i], _jsonOptions);\n")
+	sb.WriteString("            if (paramDefs != null && i < paramDefs.Count && paramDefs[i] is Dictionary<string, object> paramDef && paramDef[\"type\"] is Dictionary<string, object> paramType)\n")
+	sb.WriteString("            {\n")
+	sb.WriteString("                Redaction.Redact(node, paramType, IdlData.ALL_STRUCTS);\n")
+	sb.WriteString("            }\n")
+	sb.WriteString("            redacted[i] = node;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        var request = JsonSerializer.Serialize(options.RequestParams(redacted), _jsonOptions);\n")
+	sb.WriteString("        if (error != null)\n")
+	sb.WriteString("        {\n")
+	sb.WriteString("            logger.LogDebug(\"pulserpc call method={Method} duration={Duration}ms request={Request} error={Error}\",\n")
+	sb.WriteString("                method, elapsed.TotalMilliseconds, request, error.Message);\n")
+	sb.WriteString("            return;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        var logged = JsonSerializer.SerializeToNode(response, _jsonOptions);\n")
+	sb.WriteString("        if (logged is JsonObject obj && methodDef?[\"returnType\"] is Dictionary<string, object> returnType)\n")
+	sb.WriteString("        {\n")
+	sb.WriteString("            Redaction.Redact(obj[\"result\"], returnType, IdlData.ALL_STRUCTS);\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        logger.LogDebug(\"pulserpc call method={Method} duration={Duration}ms request={Request} response={Response}\",\n")
+	sb.WriteString("            method, elapsed.TotalMilliseconds, request, logged?.ToJsonString());\n")
+	sb.WriteString("    }\n\n")
+	sb.WriteString("    private async Task<Dictionary<string, object?>> SendCallAsync(string method, object[] parameters, CallOptions options)\n")
+}
@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestDebugLoggingGenerated(t *testing.T) {
+	idl, err := parser.ParseIDL("shop.pulse", "namespace shop\n\nstruct Card {\n  number string [sensitive]\n}\n\ninterface Orders {\n  pay(card Card) string\n}")
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	tests := []struct {
+		plugin Plugin
+		files  map[string][]string
+	}{
+		{NewGoClientServer(), map[string][]string{
+			"redact.go": {"func RedactJSON(value interface{}, typeDef map[string]interface{}, allStructs StructMap) interface{} {"},
+			"client.go": {"func (t *HTTPTransport) SetLogger(logger *slog.Logger) {", "RedactJSON(response[\"result\"], returnType, ALL_STRUCTS)"},
+		}},
+		{NewPythonClientServer(), map[string][]string{
+			"client.py": {"logger = logging.getLogger('pulserpc.client')", "if not logger.isEnabledFor(logging.DEBUG):"},
+		}},
+		{NewTSClientServer(), map[string][]string{
+			"client.ts": {"setDebugLog(", "import { redactValue } from './pulserpc/types';"},
+		}},
+		{NewCSharpClientServer(), map[string][]string{
+			"Contract.cs": {"METHOD_DEFS"},
+			"Client.cs":   {"public ILogger? Logger { get; set; }", "!logger.IsEnabled(LogLevel.Debug)"},
+		}},
+		{NewJavaClientServer(), map[string][]string{
+			"src/main/java/com/bitmechanic/pulserpc/HTTPTransport.java": {"if (!CallLog.LOGGER.isLoggable(Level.FINE)) {"},
+			"src/main/java/com/bitmechanic/pulserpc/Redaction.java":     {"public static final String REDACTED = \"***\";"},
+			"src/main/resources/idl.json":                               {"\"sensitive\""},
+		}},
+	}
+	for _, tt := range tests {
+		tmpDir := t.TempDir()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		tt.plugin.RegisterFlags(fs)
+		for name, value := range map[string]string{"dir": tmpDir, "base-package": "com.example"} {
+			if fs.Lookup(name) != nil {
+				if err := fs.Set(name, value); err != nil {
+					t.Fatalf("failed to set %s flag: %v", name, err)
+				}
+			}
+		}
+		if err := tt.plugin.Generate(idl, fs); err != nil {
+			t.Fatalf("%s: Generate failed: %v", tt.plugin.Name(), err)
+		}
+		for file, wants := range tt.files {
+			content, err := os.ReadFile(filepath.Join(tmpDir, file))
+			if err != nil {
+				t.Fatalf("%s: expected %s: %v", tt.plugin.Name(), file, err)
+			}
+			for _, want := range wants {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("%s: %s does not contain %q", tt.plugin.Name(), file, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// In-flight deduplication: every runtime has a RequestHash helper (request_hash,
+// requestHash) that hashes the canonical JSON of a call's method and params the
+// same way in every language. When the IDL has [idempotent] or [readonly]
+// methods, the Go and Python servers can use it to deduplicate identical calls
+// to those methods that arrive while the first is still running: the later
+// calls wait and get a copy of its response, with their own id, instead of
+// running the handler again. It is off until enabled with SetDeduplicateInFlight
+// or deduplicate_in_flight=True. The TypeScript server handles one call at a
+// time, so it has nothing to deduplicate.
+
+// usesIdempotentMethods reports whether any method is [idempotent] or [readonly]
+func usesIdempotentMethods(interfaces []*parser.Interface) bool {
+	for _, iface := range interfaces {
+		for _, method := range iface.Methods {
+			if method.IsIdempotent() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// goDispatchMethod names the Go server method that calls from HTTP and HandleMessage
+// are handled by
+func goDispatchMethod(interfaces []*parser.Interface) string {
+	if usesIdempotentMethods(interfaces) {
+		return "handleDeduplicated"
+	}
+	return "handleSingleRequest"
+}
+
+// writeDedupeServerGo writes the deduplicatedMethods table, SetDeduplicateInFlight
+// and handleDeduplicated of the Go server
+func writeDedupeServerGo(sb *strings.Builder, idl *parser.IDL) {
+	sb.WriteString("// deduplicatedMethods are the [idempotent] and [readonly] methods whose identical\n")
+	sb.WriteString("// in-flight calls share one response when SetDeduplicateInFlight is on\n")
+	sb.WriteString("var deduplicatedMethods = map[string]bool{\n")
+	for _, method := range idempotentMethods(idl) {
+		fmt.Fprintf(sb, "	%q: true,\n", method)
+	}
+	sb.WriteString("}\n\n")
+
+	sb.WriteString(`// SetDeduplicateInFlight controls deduplication of identical in-flight calls. When on, a
+// call to an [idempotent] or [readonly] method with the same RequestHash as a call that is
+// still being handled waits for that call and gets a copy of its response, instead of
+// running the handler again.
+func (s *PulseRPCServer) SetDeduplicateInFlight(enabled bool) {
+	s.inFlight = nil
+	if enabled {
+		s.inFlight = &InFlight{}
+	}
+}
+
+// handleDeduplicated handles one request, sharing the response of an identical call in
+// flight when deduplication is on
+func (s *PulseRPCServer) handleDeduplicated(ctx context.Context, requestJson map[string]interface{}) *rpcResponse {
+	method, _ := requestJson["method"].(string)
+	requestID, hasID := requestJson["id"]
+	if s.inFlight == nil || !hasID || !deduplicatedMethods[method] {
+		return s.handleSingleRequest(ctx, requestJson)
+	}
+	key, err := RequestHash(method, requestJson["params"])
+	if err != nil {
+		return s.handleSingleRequest(ctx, requestJson)
+	}
+	value, shared := s.inFlight.Do(key, func() interface{} {
+		return s.handleSingleRequest(ctx, requestJson)
+	})
+	response, _ := value.(*rpcResponse)
+	if !shared || response == nil {
+		return response
+	}
+	copied := *response
+	copied.ID = requestID
+	return &copied
+}
+
+`)
+}
+
+// writeDedupeMethodsPy writes the DEDUPLICATED_METHODS table of the Python server
+func writeDedupeMethodsPy(sb *strings.Builder, idl *parser.IDL) {
+	sb.WriteString("# The [idempotent] and [readonly] methods whose identical in-flight calls share one\n")
+	sb.WriteString("# response when deduplicate_in_flight is on\n")
+	sb.WriteString("DEDUPLICATED_METHODS = frozenset([\n")
+	for _, method := range idempotentMethods(idl) {
+		fmt.Fprintf(sb, "    '%s',\n", method)
+	}
+	sb.WriteString("])\n\n\n")
+}
+
+// writeDedupeServerPy writes _handle_deduplicated of the Python server
+func writeDedupeServerPy(sb *strings.Builder) {
+	sb.WriteString(`    def _handle_deduplicated(self, request_json: Any) -> Optional[Dict[str, Any]]:
+        """Handle one request, sharing the response of an identical call in flight when
+        deduplicate_in_flight is on"""
+        method = request_json.get('method') if isinstance(request_json, dict) else None
+        if (self._in_flight is None or not isinstance(method, str) or method not in DEDUPLICATED_METHODS
+                or 'id' not in request_json):
+            return self.handle_request(request_json)
+        try:
+            key = request_hash(method, request_json.get('params'))
+        except (TypeError, ValueError):
+            return self.handle_request(request_json)
+        response, shared = self._in_flight.do(key, lambda: self.handle_request(request_json))
+        if not shared or response is None:
+            return response
+        return {**response, 'id': request_json['id']}
+
+`)
+}
@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestDeduplicationGenerated(t *testing.T) {
+	tests := []struct {
+		name    string
+		idl     string
+		enabled bool
+	}{
+		{"idempotent methods", "interface Catalog {\n  count() int [idempotent]\n  reset() bool\n}", true},
+		{"readonly methods", "interface Catalog {\n  count() int [readonly]\n}", true},
+		{"no idempotent methods", "interface Catalog {\n  reset() bool\n}", false},
+	}
+	plugins := []struct {
+		plugin Plugin
+		file   string
+		want   []string
+	}{
+		{
+			plugin: NewGoClientServer(),
+			file:   "server.go",
+			want: []string{
+				"\t\"Catalog.count\": true,\n",
+				"func (s *PulseRPCServer) SetDeduplicateInFlight(enabled bool) {",
+				"s.encodeResponse(buf, method, requestBytes, s.handleDeduplicated(ctx, requestJson))",
+			},
+		},
+		{
+			plugin: NewPythonClientServer(),
+			file:   "server.py",
+			want: []string{
+				"DEDUPLICATED_METHODS = frozenset([\n    'Catalog.count',\n])",
+				"deduplicate_in_flight: bool = False):",
+				"self._handle_deduplicated(request_json))",
+			},
+		},
+	}
+	for _, tt := range tests {
+		idl, err := parser.ParseIDL("shop.pulse", tt.idl)
+		if err != nil {
+			t.Fatalf("%s: ParseIDL failed: %v", tt.name, err)
+		}
+		for _, p := range plugins {
+			tmpDir := t.TempDir()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.String("dir", "", "output dir")
+			p.plugin.RegisterFlags(fs)
+			if err := fs.Set("dir", tmpDir); err != nil {
+				t.Fatalf("failed to set dir flag: %v", err)
+			}
+			if err := p.plugin.Generate(idl, fs); err != nil {
+				t.Fatalf("%s: %s: Generate failed: %v", tt.name, p.plugin.Name(), err)
+			}
+			content, err := os.ReadFile(filepath.Join(tmpDir, p.file))
+			if err != nil {
+				t.Fatalf("%s: expected %s: %v", p.plugin.Name(), p.file, err)
+			}
+			for _, want := range p.want {
+				if got := strings.Contains(string(content), want); got != tt.enabled {
+					t.Errorf("%s: %s: %s contains %q = %v, want %v", tt.name, p.plugin.Name(), p.file, want, got, tt.enabled)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package generator
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Dependency manifests: with -dependency-manifest the Go, Python, C# and Java
+// plugins also write the third-party packages the generated code needs, pinned to
+// exact versions in the format of the language's build tool, so security scanners
+// and reproducible builds see them: dependencies.mod (a go.mod require block),
+// requirements.txt, Dependencies.props (MSBuild items to import into a project)
+// and dependencies.xml (a Maven <dependencies> fragment). Every dependency has a
+// default version that -dependency-versions overrides, and the test projects the
+// plugins write (pom.xml, HarnessTests.csproj) use the same versions. TypeScript
+// output needs nothing beyond Node.js, so the TypeScript plugin writes no manifest.
+// A Rust crate cannot build without its Cargo.toml, so the Rust plugin always
+// writes one with the crates its runtime uses, at the versions set here.
+//
+// -dependency-mirror points the manifests and the generated build files at an
+// internal mirror instead of the public registries, for builds that cannot reach
+// them. It is a URL prefix under which the mirror serves each registry at a fixed
+// path; see mirrorPaths.
+
+// defaultDependencyVersions holds the version of every dependency a plugin may
+// write, keyed by Go module path, PyPI, NuGet or crates.io package name, or Maven
+// groupId:artifactId
+var defaultDependencyVersions = map[string]string{
+	"go.uber.org/mock":            "v0.5.0",
+	"github.com/stretchr/testify": "v1.9.0",
+	"pytest":                      "8.3.3",
+	"Microsoft.NET.Test.Sdk":      "17.12.0",
+	"xunit.v3":                    "2.0.3",
+	"xunit.runner.visualstudio":   "3.1.1",
+	"com.fasterxml.jackson.core:jackson-databind": "2.15.2",
+	"com.google.code.gson:gson":                   "2.10.1",
+	"org.junit.jupiter:junit-jupiter":             "5.10.2",
+	"junit:junit":                                 "4.13.2",
+	"serde":                                       "1.0.215",
+	"serde_json":                                  "1.0.133",
+	"reqwest":                                     "0.12.19",
+	"hyper":                                       "1.6.0",
+	"hyper-util":                                  "0.1.13",
+	"http-body-util":                              "0.1.3",
+	"bytes":                                       "1.10.1",
+	"tokio":                                       "1.47.1",
+	"uuid":                                        "1.17.0",
+}
+
+// mirrorPaths are the paths, relative to the -dependency-mirror prefix, at which
+// the mirror serves each registry
+var mirrorPaths = map[string]string{
+	"go":    "go",
+	"pypi":  "pypi/simple",
+	"nuget": "nuget/v3/index.json",
+	"maven": "maven2",
+	"cargo": "cargo/",
+}
+
+// dependencyMirror is the -dependency-mirror URL prefix, or "" for the public
+// registries
+type dependencyMirror string
+
+// dependencyMirrorFlag returns the -dependency-mirror prefix without trailing
+// slashes, checking that it is an http or https URL
+func dependencyMirrorFlag(fs *flag.FlagSet) (dependencyMirror, error) {
+	f := fs.Lookup("dependency-mirror")
+	if f == nil || f.Value.String() == "" {
+		return "", nil
+	}
+	prefix := strings.TrimRight(f.Value.String(), "/")
+	u, err := url.Parse(prefix)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid dependency-mirror %q (expected an http or https URL prefix)", f.Value.String())
+	}
+	return dependencyMirror(prefix), nil
+}
+
+// URL returns the URL the mirror serves registry at, or "" without a mirror
+func (m dependencyMirror) URL(registry string) string {
+	if m == "" {
+		return ""
+	}
+	return string(m) + "/" + mirrorPaths[registry]
+}
+
+// dependency is a third-party package of the generated code
+type dependency struct {
+	Name    string
+	Version string
+	// Test is true for packages only the generated tests need
+	Test bool
+}
+
+// GroupID returns the Maven groupId of a groupId:artifactId name
+func (d dependency) GroupID() string {
+	group, _, _ := strings.Cut(d.Name, ":")
+	return group
+}
+
+// ArtifactID returns the Maven artifactId of a groupId:artifactId name
+func (d dependency) ArtifactID() string {
+	_, artifact, _ := strings.Cut(d.Name, ":")
+	return artifact
+}
+
+// dependencyManifestRequested reports whether the -dependency-manifest flag is set
+func dependencyManifestRequested(fs *flag.FlagSet) bool {
+	f := fs.Lookup("dependency-manifest")
+	return f != nil && f.Value.String() == "true"
+}
+
+// dependencyVersions returns the default dependency versions with the overrides of
+// -dependency-versions, a comma separated list of name=version pairs
+func dependencyVersions(fs *flag.FlagSet) (map[string]string, error) {
+	versions := make(map[string]string, len(defaultDependencyVersions))
+	for name, version := range defaultDependencyVersions {
+		versions[name] = version
+	}
+	f := fs.Lookup("dependency-versions")
+	if f == nil {
+		return versions, nil
+	}
+	for _, pair := range strings.Split(f.Value.String(), ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, version, ok := strings.Cut(pair, "=")
+		name, version = strings.TrimSpace(name), strings.TrimSpace(version)
+		if !ok || name == "" || version == "" {
+			return nil, fmt.Errorf("invalid dependency-versions entry %q (expected name=version)", pair)
+		}
+		if _, known := defaultDependencyVersions[name]; !known {
+			known := make([]string, 0, len(defaultDependencyVersions))
+			for n := range defaultDependencyVersions {
+				known = append(known, n)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("unknown dependency %q in dependency-versions (known: %s)", name, strings.Join(known, ", "))
+		}
+		versions[name] = version
+	}
+	return versions, nil
+}
+
+// goDependencies returns the modules the generated Go code imports
+func goDependencies(versions map[string]string, goMocks string) []dependency {
+	var deps []dependency
+	switch goMocks {
+	case "gomock":
+		deps = append(deps, dependency{Name: "go.uber.org/mock", Version: versions["go.uber.org/mock"], Test: true})
+	case "testify":
+		deps = append(deps, dependency{Name: "github.com/stretchr/testify", Version: versions["github.com/stretchr/testify"], Test: true})
+	}
+	return deps
+}
+
+// pythonDependencies returns the packages the generated Python code imports
+func pythonDependencies(versions map[string]string, harness bool) []dependency {
+	var deps []dependency
+	if harness {
+		deps = append(deps, dependency{Name: "pytest", Version: versions["pytest"], Test: true})
+	}
+	return deps
+}
+
+// csharpHarnessDependencies returns the NuGet packages of the xUnit harness and contract
+// test projects
+func csharpHarnessDependencies(versions map[string]string) []dependency {
+	var deps []dependency
+	for _, name := range []string{"Microsoft.NET.Test.Sdk", "xunit.v3", "xunit.runner.visualstudio"} {
+		deps = append(deps, dependency{Name: name, Version: versions[name], Test: true})
+	}
+	return deps
+}
+
+// csharpDependencies returns the NuGet packages the generated C# code uses. The
+// server also needs the ASP.NET Core shared framework, which is not a package.
+func csharpDependencies(versions map[string]string, harness bool) []dependency {
+	if harness {
+		return csharpHarnessDependencies(versions)
+	}
+	return nil
+}
+
+// javaDependencies returns the Maven artifacts the generated Java code uses
+func javaDependencies(versions map[string]string, jsonLib string, junit5 bool) []dependency {
+	var deps []dependency
+	switch jsonLib {
+	case "jackson":
+		deps = append(deps, dependency{Name: "com.fasterxml.jackson.core:jackson-databind", Version: versions["com.fasterxml.jackson.core:jackson-databind"]})
+	case "gson":
+		deps = append(deps, dependency{Name: "com.google.code.gson:gson", Version: versions["com.google.code.gson:gson"]})
+	}
+	if junit5 {
+		deps = append(deps, dependency{Name: "org.junit.jupiter:junit-jupiter", Version: versions["org.junit.jupiter:junit-jupiter"], Test: true})
+	}
+	return deps
+}
+
+// writeGoDependencyManifest writes dependencies.mod, a require block for go.mod.
+// go.sum is left to go mod tidy, which checks the modules it downloads.
+func writeGoDependencyManifest(outputDir string, deps []dependency, mirror dependencyMirror) error {
+	var sb strings.Builder
+	sb.WriteString("// Generated by pulserpc - do not edit\n")
+	if mirror != "" {
+		// go.mod cannot name a proxy, so the manifest tells the reader which one to use
+		fmt.Fprintf(&sb, "// Download the modules from the mirror: GOPROXY=%s GOSUMDB=off\n", mirror.URL("go"))
+	}
+	if len(deps) == 0 {
+		sb.WriteString("// The generated code imports only the Go standard library.\n")
+	} else {
+		sb.WriteString("// Modules the generated code imports. Copy the require block into go.mod,\n")
+		sb.WriteString("// or run go get <module>@<version> for each, then go mod tidy for go.sum.\n\n")
+		sb.WriteString("require (\n")
+		for _, d := range deps {
+			fmt.Fprintf(&sb, "\t%s %s\n", d.Name, d.Version)
+		}
+		sb.WriteString(")\n")
+	}
+	return writeDependencyManifest(filepath.Join(outputDir, "dependencies.mod"), sb.String())
+}
+
+// writePythonDependencyManifest writes requirements.txt with exact version pins
+func writePythonDependencyManifest(outputDir string, deps []dependency, mirror dependencyMirror) error {
+	var sb strings.Builder
+	sb.WriteString("# Generated by pulserpc - do not edit\n")
+	if mirror != "" {
+		fmt.Fprintf(&sb, "--index-url %s\n", mirror.URL("pypi"))
+	}
+	if len(deps) == 0 {
+		sb.WriteString("# The generated code imports only the Python standard library.\n")
+	}
+	for _, d := range deps {
+		fmt.Fprintf(&sb, "%s==%s\n", d.Name, d.Version)
+	}
+	return writeDependencyManifest(filepath.Join(outputDir, "requirements.txt"), sb.String())
+}
+
+// writeCSharpDependencyManifest writes Dependencies.props, which a project adds
+// with <Import Project="Dependencies.props" />. A mirror replaces the package
+// sources of the project that imports it.
+func writeCSharpDependencyManifest(outputDir string, deps []dependency, mirror dependencyMirror) error {
+	var sb strings.Builder
+	sb.WriteString("<!-- Generated by pulserpc - do not edit -->\n")
+	sb.WriteString("<Project>\n\n")
+	if mirror != "" {
+		sb.WriteString("  <PropertyGroup>\n")
+		fmt.Fprintf(&sb, "    <RestoreSources>%s</RestoreSources>\n", mirror.URL("nuget"))
+		sb.WriteString("  </PropertyGroup>\n\n")
+	}
+	sb.WriteString("  <ItemGroup>\n")
+	sb.WriteString("    <FrameworkReference Include=\"Microsoft.AspNetCore.App\" />\n")
+	sb.WriteString("  </ItemGroup>\n")
+	// Packages only the generated tests need are grouped apart, so a project
+	// that copies the items can leave them out
+	for _, test := range []bool{false, true} {
+		var group []dependency
+		for _, d := range deps {
+			if d.Test == test {
+				group = append(group, d)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+		if test {
+			sb.WriteString("\n  <ItemGroup Label=\"Tests\">\n")
+		} else {
+			sb.WriteString("\n  <ItemGroup>\n")
+		}
+		for _, d := range group {
+			fmt.Fprintf(&sb, "    <PackageReference Include=\"%s\" Version=\"[%s]\" />\n", d.Name, d.Version)
+		}
+		sb.WriteString("  </ItemGroup>\n")
+	}
+	sb.WriteString("\n</Project>\n")
+	return writeDependencyManifest(filepath.Join(outputDir, "Dependencies.props"), sb.String())
+}
+
+// writeJavaDependencyManifest writes dependencies.xml, a <dependencies> element to
+// paste into a pom.xml. The element cannot hold repositories, so a mirror is
+// named in a comment; the generated pom.xml lists it.
+func writeJavaDependencyManifest(outputDir string, deps []dependency, mirror dependencyMirror) error {
+	var sb strings.Builder
+	sb.WriteString("<!-- Generated by pulserpc - do not edit -->\n")
+	if mirror != "" {
+		fmt.Fprintf(&sb, "<!-- Resolve these from the mirror repository %s -->\n", mirror.URL("maven"))
+	}
+	sb.WriteString("<dependencies>\n")
+	for _, d := range deps {
+		sb.WriteString("    <dependency>\n")
+		fmt.Fprintf(&sb, "        <groupId>%s</groupId>\n", d.GroupID())
+		fmt.Fprintf(&sb, "        <artifactId>%s</artifactId>\n", d.ArtifactID())
+		fmt.Fprintf(&sb, "        <version>%s</version>\n", d.Version)
+		if d.Test {
+			sb.WriteString("        <scope>test</scope>\n")
+		}
+		sb.WriteString("    </dependency>\n")
+	}
+	sb.WriteString("</dependencies>\n")
+	return writeDependencyManifest(filepath.Join(outputDir, "dependencies.xml"), sb.String())
+}
+
+// writeDependencyManifest writes a manifest file, wrapping errors with its name
+func writeDependencyManifest(path, content string) error {
+	if err := writeGeneratedFile(path, []byte(content)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
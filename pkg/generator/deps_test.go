@@ -0,0 +1,191 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestDependencyVersions(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("dependency-versions", "", "dependency versions")
+
+	versions, err := dependencyVersions(fs)
+	if err != nil {
+		t.Fatalf("dependencyVersions failed: %v", err)
+	}
+	if versions["pytest"] != defaultDependencyVersions["pytest"] {
+		t.Errorf("expected the default pytest version, got %q", versions["pytest"])
+	}
+
+	if err := fs.Set("dependency-versions", " pytest=8.2.0 , com.google.code.gson:gson=2.11.0"); err != nil {
+		t.Fatalf("failed to set dependency-versions flag: %v", err)
+	}
+	versions, err = dependencyVersions(fs)
+	if err != nil {
+		t.Fatalf("dependencyVersions failed: %v", err)
+	}
+	if versions["pytest"] != "8.2.0" || versions["com.google.code.gson:gson"] != "2.11.0" {
+		t.Errorf("expected overridden versions, got pytest=%q gson=%q", versions["pytest"], versions["com.google.code.gson:gson"])
+	}
+	if defaultDependencyVersions["pytest"] == "8.2.0" {
+		t.Error("overrides must not change the defaults")
+	}
+
+	for value, want := range map[string]string{
+		"pytest":      "expected name=version",
+		"pytest=":     "expected name=version",
+		"gson=2.11.0": `unknown dependency "gson"`,
+	} {
+		if err := fs.Set("dependency-versions", value); err != nil {
+			t.Fatalf("failed to set dependency-versions flag: %v", err)
+		}
+		if _, err := dependencyVersions(fs); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected an error containing %q, got %v", value, want, err)
+		}
+	}
+}
+
+func TestDependencyManifests(t *testing.T) {
+	idl := &parser.IDL{
+		RootNamespace: "catalog",
+		Interfaces: []*parser.Interface{
+			{
+				Name: "Catalog",
+				Methods: []*parser.Method{
+					{Name: "ping", ReturnType: &parser.Type{BuiltIn: "string"}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		plugin Plugin
+		flags  map[string]string
+		file   string
+		want   []string
+	}{
+		{
+			plugin: NewGoClientServer(),
+			flags:  map[string]string{"go-mocks": "gomock"},
+			file:   "dependencies.mod",
+			want:   []string{"require (\n\tgo.uber.org/mock v0.5.0\n)\n"},
+		},
+		{
+			plugin: NewPythonClientServer(),
+			flags:  map[string]string{"generate-test-harness": "true", "dependency-versions": "pytest=8.2.0"},
+			file:   "requirements.txt",
+			want:   []string{"pytest==8.2.0\n"},
+		},
+		{
+			plugin: NewPythonClientServer(),
+			file:   "requirements.txt",
+			want:   []string{"# The generated code imports only the Python standard library.\n"},
+		},
+		{
+			plugin: NewCSharpClientServer(),
+			flags:  map[string]string{"generate-test-harness": "true"},
+			file:   "Dependencies.props",
+			want: []string{
+				`<FrameworkReference Include="Microsoft.AspNetCore.App" />`,
+				`<ItemGroup Label="Tests">`,
+				`<PackageReference Include="xunit.v3" Version="[2.0.3]" />`,
+			},
+		},
+		{
+			plugin: NewJavaClientServer(),
+			flags:  map[string]string{"base-package": "com.example", "json-lib": "gson", "generate-test-harness": "true"},
+			file:   "dependencies.xml",
+			want: []string{
+				"<groupId>com.google.code.gson</groupId>\n        <artifactId>gson</artifactId>\n        <version>2.10.1</version>\n    </dependency>",
+				"<artifactId>junit-jupiter</artifactId>\n        <version>5.10.2</version>\n        <scope>test</scope>",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tmpDir := t.TempDir()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		fs.Bool("generate-test-harness", false, "generate test harness")
+		fs.Bool("dependency-manifest", false, "write dependency manifests")
+		fs.String("dependency-versions", "", "dependency versions")
+		tt.plugin.RegisterFlags(fs)
+		flags := map[string]string{"dir": tmpDir, "dependency-manifest": "true"}
+		for name, value := range tt.flags {
+			flags[name] = value
+		}
+		for name, value := range flags {
+			if err := fs.Set(name, value); err != nil {
+				t.Fatalf("%s: failed to set %s flag: %v", tt.plugin.Name(), name, err)
+			}
+		}
+		if err := tt.plugin.Generate(idl, fs); err != nil {
+			t.Fatalf("%s: Generate failed: %v", tt.plugin.Name(), err)
+		}
+		content, err := os.ReadFile(filepath.Join(tmpDir, tt.file))
+		if err != nil {
+			t.Fatalf("%s: expected %s: %v", tt.plugin.Name(), tt.file, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s: %s missing %q:\n%s", tt.plugin.Name(), tt.file, want, content)
+			}
+		}
+	}
+}
+
+func TestDependencyMirror(t *testing.T) {
+	idl := &parser.IDL{
+		RootNamespace: "catalog",
+		Interfaces: []*parser.Interface{
+			{Name: "Catalog", Methods: []*parser.Method{{Name: "ping", ReturnType: &parser.Type{BuiltIn: "string"}}}},
+		},
+	}
+	tests := []struct {
+		plugin Plugin
+		file   string
+		want   string
+	}{
+		{NewGoClientServer(), "dependencies.mod", "GOPROXY=https://mirror.internal/repo/go GOSUMDB=off"},
+		{NewPythonClientServer(), "requirements.txt", "--index-url https://mirror.internal/repo/pypi/simple\n"},
+		{NewCSharpClientServer(), "Dependencies.props", "<RestoreSources>https://mirror.internal/repo/nuget/v3/index.json</RestoreSources>"},
+		{NewCSharpClientServer(), "HarnessTests.csproj", "<RestoreSources>https://mirror.internal/repo/nuget/v3/index.json</RestoreSources>"},
+		{NewJavaClientServer(), "pom.xml", "<url>https://mirror.internal/repo/maven2</url>"},
+		{NewRustClientServer(), ".cargo/config.toml", "registry = \"sparse+https://mirror.internal/repo/cargo/\""},
+	}
+	for _, tt := range tests {
+		tmpDir := t.TempDir()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", tmpDir, "output dir")
+		fs.Bool("generate-test-harness", true, "generate test harness")
+		fs.Bool("dependency-manifest", true, "write dependency manifests")
+		fs.String("dependency-versions", "", "dependency versions")
+		fs.String("dependency-mirror", "https://mirror.internal/repo/", "dependency mirror")
+		tt.plugin.RegisterFlags(fs)
+		if f := fs.Lookup("base-package"); f != nil {
+			if err := fs.Set("base-package", "com.example"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := tt.plugin.Generate(idl, fs); err != nil {
+			t.Fatalf("%s: Generate failed: %v", tt.plugin.Name(), err)
+		}
+		content, err := os.ReadFile(filepath.Join(tmpDir, tt.file))
+		if err != nil {
+			t.Fatalf("%s: expected %s: %v", tt.plugin.Name(), tt.file, err)
+		}
+		if !strings.Contains(string(content), tt.want) {
+			t.Errorf("%s: %s missing %q:\n%s", tt.plugin.Name(), tt.file, tt.want, content)
+		}
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("dependency-mirror", "mirror.internal/repo", "dependency mirror")
+	if _, err := dependencyMirrorFlag(fs); err == nil || !strings.Contains(err.Error(), "expected an http or https URL prefix") {
+		t.Errorf("expected an invalid mirror error, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package generator
+
+// Every client gets a DiscoveryTransport, written next to it as discovery.* (or
+// DiscoveryTransport.java), so clients can be constructed from a logical service
+// name instead of a URL. The transport asks a Resolver for the service's endpoints,
+// caches them for a TTL and rotates through the ones with the lowest priority. A
+// DNS SRV resolver is built in; Consul, Kubernetes and other registries plug in by
+// implementing the Resolver interface.
+
+// discoveryView is the view model for the discovery templates
+type discoveryView struct {
+	// Package is the Go or Java package of the generated client
+	Package string
+	// RuntimeImport is the Go runtime package to dot-import when namespaces are
+	// split into packages
+	RuntimeImport string
+	// TypesImport is the Go types package, holding RequestSigner, to dot-import
+	// when the client has a package of its own
+	TypesImport string
+	// Packaged is true when Python output is a package using relative imports
+	Packaged bool
+	// The TypeScript names below carry the -package prefix
+	CallOptions    string
+	RequestSigner  string
+	Transport      string
+	HTTPTransport  string
+	TransportError string
+	Endpoint       string
+	Resolver       string
+	SRVResolver    string
+	ClassName      string
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/naming"
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Typed dispatch: the Go server switches on the interface and method of a call,
+// decodes the params into their declared types and calls the handler method with
+// the signature of the generated interface directly. Register<Iface> takes the
+// generated interface, so a handler that drifts from the IDL fails to compile, and
+// is the way to register handlers. The untyped Register checks the handler against
+// the generated interface with implementsInterface and panics on a mismatch, so
+// every registered handler can be called without reflection.
+
+// goDispatchInterfaceView is the view model for the dispatch cases of an interface
+type goDispatchInterfaceView struct {
+	Name string
+	// Methods include the inherited ones, which a handler of Name also serves
+	Methods []goDispatchMethodView
+}
+
+// goDispatchMethodView is the view model for the dispatch case of one method
+type goDispatchMethodView struct {
+	Name       string
+	RPCName    string // "Interface.method"
+	ParamTypes []string
+	Args       string // "ctx, p0, p1"
+	Targets    string // "&p0, &p1"
+	// ReturnType is empty for methods without a result
+	ReturnType string
+	// ElemType is the array element a [chunked] method emits instead of returning
+	ElemType string
+	// Shape is the method as the generated interface declares it, in an interface
+	// literal
+	Shape string
+}
+
+// newGoDispatchInterfaceViews returns the dispatch cases of interfaces
+func newGoDispatchInterfaceViews(interfaces []*parser.Interface, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) []goDispatchInterfaceView {
+	var views []goDispatchInterfaceView
+	for _, iface := range interfaces {
+		iv := goDispatchInterfaceView{Name: iface.Name}
+		for _, method := range iface.Methods {
+			iv.Methods = append(iv.Methods, newGoDispatchMethodView(iface, method, structMap, enumMap))
+		}
+		views = append(views, iv)
+	}
+	return views
+}
+
+// newGoDispatchMethodView returns the dispatch case of method
+func newGoDispatchMethodView(iface *parser.Interface, method *parser.Method, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) goDispatchMethodView {
+	view := goDispatchMethodView{
+		Name:    naming.SnakeToPascal(method.Name),
+		RPCName: iface.Name + "." + method.Name,
+	}
+	paramList := []string{"context.Context"}
+	args := []string{"ctx"}
+	targets := make([]string, len(method.Parameters))
+	for i, param := range method.Parameters {
+		paramType := mapTypeToGoType(param.Type, structMap, enumMap, param.Optional)
+		view.ParamTypes = append(view.ParamTypes, paramType)
+		paramList = append(paramList, paramType)
+		args = append(args, fmt.Sprintf("p%d", i))
+		targets[i] = fmt.Sprintf("&p%d", i)
+	}
+	view.Args = strings.Join(args, ", ")
+	view.Targets = strings.Join(targets, ", ")
+
+	params := strings.Join(paramList, ", ")
+	switch {
+	case method.IsChunked():
+		view.ElemType = mapTypeToGoType(method.ReturnType.Array, structMap, enumMap, false)
+		view.Shape = fmt.Sprintf("interface{ %s(%s, func(%s) error) error }", view.Name, params, view.ElemType)
+	case method.ReturnType == nil:
+		view.Shape = fmt.Sprintf("interface{ %s(%s) error }", view.Name, params)
+	default:
+		view.ReturnType = mapTypeToGoType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
+		view.Shape = fmt.Sprintf("interface{ %s(%s) (%s, error) }", view.Name, params, view.ReturnType)
+	}
+	return view
+}
@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const dispatchTestIDL = `namespace shop
+
+struct Item {
+    name  string
+    price float
+}
+
+interface Shop {
+    find(name string) Item
+    rename(item Item, name string) []Item
+    items(count int) []Item [chunked]
+}
+`
+
+// dispatchCheckTest runs inside the generated package, so it can call dispatch and
+// see which handlers Register accepts
+const dispatchCheckTest = `package shop
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// typed has the interface's own signatures and is registered with RegisterShop
+type typed struct{}
+
+func (typed) Find(ctx context.Context, name string) (Item, error) {
+	if name == "" {
+		return Item{}, &RPCError{Code: 1001, Message: "not found"}
+	}
+	return Item{Name: name, Price: 2.5}, nil
+}
+func (typed) Rename(ctx context.Context, item Item, name string) ([]Item, error) {
+	return []Item{item, {Name: name, Price: item.Price}}, nil
+}
+func (typed) Items(ctx context.Context, count int, emit func(Item) error) error {
+	for i := 0; i < count; i++ {
+		if err := emit(Item{Name: "x"}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// legacy takes no context, which only reflection could call
+type legacy struct{}
+
+func (legacy) Find(name string) (*Item, error) { return &Item{Name: name}, nil }
+func (legacy) Rename(item Item, name string) ([]Item, error) { return nil, nil }
+func (legacy) Items(count int, emit func(Item) error) error { return nil }
+
+// registerPanics reports whether Register rejects handler for interfaceName
+func registerPanics(server *PulseRPCServer, interfaceName string, handler interface{}) (panicked bool) {
+	defer func() { panicked = recover() != nil }()
+	server.Register(interfaceName, handler)
+	return false
+}
+
+func TestDispatch(t *testing.T) {
+	server := NewPulseRPCServer("localhost", 0)
+	server.RegisterShop(typed{})
+	ctx := context.Background()
+	calls := []struct {
+		method string
+		params []interface{}
+		want   string
+	}{
+		{"find", []interface{}{"pen"}, "{pen 2.5}"},
+		{"rename", []interface{}{map[string]interface{}{"name": "pen", "price": 2.5}, "ink"}, "[{pen 2.5} {ink 2.5}]"},
+		{"items", []interface{}{2.0}, "[{x 0} {x 0}]"},
+	}
+	for _, call := range calls {
+		result, handled, err := server.dispatch(ctx, typed{}, "Shop", call.method, call.params)
+		if !handled || err != nil {
+			t.Fatalf("%s: handled %v, err %v", call.method, handled, err)
+		}
+		if got := fmt.Sprint(result); got != call.want {
+			t.Errorf("%s: got %s, want %s", call.method, got, call.want)
+		}
+	}
+
+	_, handled, err := server.dispatch(ctx, typed{}, "Shop", "find", []interface{}{""})
+	if rpcErr, ok := err.(*RPCError); !handled || !ok || rpcErr.Code != 1001 {
+		t.Errorf("typed error: handled %v, err %v", handled, err)
+	}
+
+	if _, handled, _ := server.dispatch(ctx, legacy{}, "Shop", "find", []interface{}{"pen"}); handled {
+		t.Errorf("legacy handler should not be dispatched")
+	}
+
+	// The untyped Register only takes handlers of the generated interface
+	if registerPanics(server, "Shop", typed{}) {
+		t.Errorf("Register rejected a handler of the generated interface")
+	}
+	if !registerPanics(server, "Shop", legacy{}) {
+		t.Errorf("Register accepted a handler without the generated signatures")
+	}
+	if !registerPanics(server, "Store", typed{}) {
+		t.Errorf("Register accepted an interface the IDL does not have")
+	}
+}
+`
+
+// TestTypedDispatchGo runs a test in the generated package that calls handlers
+// through dispatch and registers handlers of other shapes
+func TestTypedDispatchGo(t *testing.T) {
+	dir := generateForTest(t, NewGoClientServer(), dispatchTestIDL)
+	writeGoModule(t, dir, "example.com/shop")
+	if err := os.WriteFile(filepath.Join(dir, "dispatch_check_test.go"), []byte(dispatchCheckTest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGo(t, dir, "test", "-run", "TestDispatch", ".")
+}
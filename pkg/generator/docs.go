@@ -0,0 +1,640 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/naming"
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// The docs plugin renders the IDL as an API reference for the people calling it,
+// in one Markdown (api.md) or HTML (api.html) page. Every interface, struct, enum
+// and typedef gets a section with its comment, and types link to their sections.
+// Each method lists its params and result, how it is called with the generated
+// client of each language, and a sample JSON-RPC request and response: its first
+// @example block, or the values the examples plugin generates. Interfaces and
+// methods show their [stability] as a badge and their [owner], a method taking
+// those of its interface when it has none of its own.
+//
+// The sections are written through docsRenderer, so both formats share one walk
+// of the IDL and only differ in markup.
+
+// Docs generates an API reference from the IDL
+type Docs struct {
+}
+
+// NewDocs creates a new Docs plugin instance
+func NewDocs() *Docs {
+	return &Docs{}
+}
+
+// Name returns the plugin identifier
+func (p *Docs) Name() string {
+	return "docs"
+}
+
+// RegisterFlags registers CLI flags for this plugin
+func (p *Docs) RegisterFlags(fs *flag.FlagSet) {
+	fs.String("docs-format", "markdown", "Format of the API reference: markdown (api.md) or html (api.html)")
+}
+
+// Generate writes api.md or api.html to the output directory
+func (p *Docs) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
+	outputDir := ""
+	if dirFlag := fs.Lookup("dir"); dirFlag != nil {
+		outputDir = dirFlag.Value.String()
+	}
+	format := fs.Lookup("docs-format").Value.String()
+	fileName := ""
+	switch format {
+	case "markdown":
+		fileName = "api.md"
+	case "html":
+		fileName = "api.html"
+	default:
+		return fmt.Errorf("invalid docs-format value: %q (must be markdown or html)", format)
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := writeGeneratedFile(filepath.Join(outputDir, fileName), []byte(BuildDocs(idl, format))); err != nil {
+		return fmt.Errorf("failed to write %s: %w", fileName, err)
+	}
+	return nil
+}
+
+// docsSpan is a run of text, set as code or as a badge and linked to the section
+// with the anchor Anchor when those are set
+type docsSpan struct {
+	Text   string
+	Code   bool
+	Badge  bool
+	Anchor string
+}
+
+// docsRenderer writes the markup of one format
+type docsRenderer interface {
+	heading(level int, anchor string, spans ...docsSpan)
+	// comment writes a doc comment, whose blank lines separate paragraphs
+	comment(text string)
+	paragraph(spans ...docsSpan)
+	list(items [][]docsSpan)
+	table(header []string, rows [][][]docsSpan)
+	code(language, code string)
+	String() string
+}
+
+// BuildDocs returns the API reference of the IDL in format, "markdown" or "html"
+func BuildDocs(idl *parser.IDL, format string) string {
+	title := "API Reference"
+	if idl.RootNamespace != "" {
+		title = idl.RootNamespace + " API Reference"
+	}
+	var r docsRenderer
+	if format == "html" {
+		r = newHTMLDocs(title)
+	} else {
+		r = &markdownDocs{}
+	}
+	w := newDocsWriter(idl, r)
+	w.write(title)
+	return r.String()
+}
+
+// docsWriter walks the IDL and writes its sections through a renderer
+type docsWriter struct {
+	idl       *parser.IDL
+	r         docsRenderer
+	structMap map[string]*parser.Struct
+	enumMap   map[string]*parser.Enum
+	typedefs  map[string]*parser.Typedef
+	examples  map[string]MethodExample
+}
+
+func newDocsWriter(idl *parser.IDL, r docsRenderer) *docsWriter {
+	w := &docsWriter{
+		idl:       idl,
+		r:         r,
+		structMap: make(map[string]*parser.Struct),
+		enumMap:   make(map[string]*parser.Enum),
+		typedefs:  make(map[string]*parser.Typedef),
+		examples:  make(map[string]MethodExample),
+	}
+	for _, s := range idl.Structs {
+		w.structMap[s.Name] = s
+	}
+	for _, e := range idl.Enums {
+		w.enumMap[e.Name] = e
+	}
+	for _, td := range idl.Typedefs {
+		w.typedefs[td.Name] = td
+	}
+	// The first example of each method is shown
+	for _, example := range BuildExamples(idl).Examples {
+		if _, ok := w.examples[example.Method]; !ok {
+			w.examples[example.Method] = example
+		}
+	}
+	return w
+}
+
+func (w *docsWriter) write(title string) {
+	w.r.heading(1, "", docsSpan{Text: title})
+
+	var contents [][]docsSpan
+	sections := []struct {
+		title  string
+		anchor string
+		count  int
+	}{
+		{"Interfaces", "interfaces", len(w.idl.Interfaces)},
+		{"Structs", "structs", len(w.idl.Structs)},
+		{"Enums", "enums", len(w.idl.Enums)},
+		{"Typedefs", "typedefs", len(w.idl.Typedefs)},
+	}
+	for _, section := range sections {
+		if section.count > 0 {
+			contents = append(contents, []docsSpan{{Text: section.title, Anchor: section.anchor}})
+		}
+	}
+	w.r.list(contents)
+
+	if len(w.idl.Interfaces) > 0 {
+		w.r.heading(2, "interfaces", docsSpan{Text: "Interfaces"})
+		for _, iface := range w.idl.Interfaces {
+			w.writeInterface(iface)
+		}
+	}
+	if len(w.idl.Structs) > 0 {
+		w.r.heading(2, "structs", docsSpan{Text: "Structs"})
+		for _, s := range w.idl.Structs {
+			w.writeStruct(s)
+		}
+	}
+	if len(w.idl.Enums) > 0 {
+		w.r.heading(2, "enums", docsSpan{Text: "Enums"})
+		for _, e := range w.idl.Enums {
+			w.writeEnum(e)
+		}
+	}
+	if len(w.idl.Typedefs) > 0 {
+		w.r.heading(2, "typedefs", docsSpan{Text: "Typedefs"})
+		for _, td := range w.idl.Typedefs {
+			w.r.heading(3, docsAnchor("typedef", td.Name), docsSpan{Text: td.Name})
+			w.r.comment(td.Comment)
+			w.r.paragraph(docsSpan{Text: "Type: "}, w.typeSpan(td.Type, false))
+		}
+	}
+}
+
+func (w *docsWriter) writeInterface(iface *parser.Interface) {
+	w.r.heading(3, docsAnchor("interface", iface.Name), docsSpan{Text: iface.Name})
+	w.r.comment(iface.Comment)
+	var owner, stability string
+	if a := iface.Annotation(parser.AnnotationOwner); a != nil {
+		owner = a.Value
+	}
+	if a := iface.Annotation(parser.AnnotationStability); a != nil {
+		stability = a.Value
+	}
+	if spans := docsOwnership(owner, stability); len(spans) > 0 {
+		w.r.paragraph(spans...)
+	}
+	if len(iface.Extends) > 0 {
+		spans := []docsSpan{{Text: "Extends "}}
+		for i, parent := range iface.Extends {
+			if i > 0 {
+				spans = append(spans, docsSpan{Text: ", "})
+			}
+			spans = append(spans, docsSpan{Text: parent, Code: true, Anchor: docsAnchor("interface", parent)})
+		}
+		w.r.paragraph(spans...)
+	}
+
+	var methods [][]docsSpan
+	for _, method := range iface.Methods {
+		methods = append(methods, []docsSpan{{Text: method.Name, Code: true, Anchor: docsAnchor("method", iface.Name+"."+method.Name)}})
+	}
+	w.r.list(methods)
+
+	for _, method := range iface.Methods {
+		w.writeMethod(iface, method)
+	}
+}
+
+func (w *docsWriter) writeMethod(iface *parser.Interface, method *parser.Method) {
+	rpcName := iface.RPCName(method)
+	w.r.heading(4, docsAnchor("method", iface.Name+"."+method.Name), docsSpan{Text: rpcName, Code: true})
+	if spans := docsOwnership(w.idl.MethodOwner(iface, method), w.idl.MethodStability(iface, method)); len(spans) > 0 {
+		w.r.paragraph(spans...)
+	}
+
+	var notes []docsSpan
+	if method.InheritedFrom != "" {
+		notes = append(notes, docsSpan{Text: "Inherited from "}, docsSpan{Text: method.InheritedFrom, Code: true, Anchor: docsAnchor("interface", method.InheritedFrom)}, docsSpan{Text: ". "})
+	}
+	// [owner] and [stability] are shown above, with those of the interface
+	var annotations []docsSpan
+	for _, a := range method.Annotations {
+		if a.Name == parser.AnnotationOwner || a.Name == parser.AnnotationStability {
+			continue
+		}
+		if len(annotations) > 0 {
+			annotations = append(annotations, docsSpan{Text: " "})
+		}
+		annotations = append(annotations, docsSpan{Text: docsAnnotation(a), Code: true})
+	}
+	if len(annotations) > 0 {
+		notes = append(notes, docsSpan{Text: "Annotations: "})
+		notes = append(notes, annotations...)
+	}
+	if len(notes) > 0 {
+		w.r.paragraph(notes...)
+	}
+
+	if len(method.Parameters) > 0 {
+		rows := make([][][]docsSpan, len(method.Parameters))
+		for i, param := range method.Parameters {
+			rows[i] = [][]docsSpan{{{Text: param.Name, Code: true}}, {w.typeSpan(param.Type, param.Optional)}}
+		}
+		w.r.table([]string{"Param", "Type"}, rows)
+	}
+	if method.ReturnType != nil {
+		w.r.paragraph(docsSpan{Text: "Returns "}, w.typeSpan(method.ReturnType, method.ReturnOptional))
+	} else {
+		w.r.paragraph(docsSpan{Text: "Returns nothing"})
+	}
+	if name := method.ErrorData(); name != "" {
+		w.r.paragraph(docsSpan{Text: "Error data: "}, w.typeSpan(&parser.Type{UserDefined: name}, false))
+	}
+
+	var signatures [][][]docsSpan
+	for _, sig := range w.signatures(iface, method) {
+		signatures = append(signatures, [][]docsSpan{{{Text: sig[0]}}, {{Text: sig[1], Code: true}}})
+	}
+	w.r.table([]string{"Language", "Client"}, signatures)
+
+	if example, ok := w.examples[rpcName]; ok {
+		w.r.paragraph(docsSpan{Text: "Example request:"})
+		w.r.code("json", docsJSON(example.Request))
+		w.r.paragraph(docsSpan{Text: "Example response:"})
+		w.r.code("json", docsJSON(example.Response))
+	}
+}
+
+// signatures returns, per language, how the method is called with its generated
+// client: the method's declaration in typed languages, a call in the others
+func (w *docsWriter) signatures(iface *parser.Interface, method *parser.Method) [][2]string {
+	name := GetBaseName(iface.Name)
+	var names []string
+	for _, param := range method.Parameters {
+		names = append(names, param.Name)
+	}
+	args := strings.Join(names, ", ")
+
+	var goParams []string
+	for _, param := range method.Parameters {
+		goParams = append(goParams, param.Name+" "+mapTypeToGoType(param.Type, w.structMap, w.enumMap, param.Optional))
+	}
+	goParams = append(goParams, "opts ...CallOption")
+	goResult := "error"
+	if method.ReturnType != nil {
+		goResult = "(" + mapTypeToGoType(method.ReturnType, w.structMap, w.enumMap, method.ReturnOptional) + ", error)"
+	}
+
+	var javaParams, csParams []string
+	for _, param := range method.Parameters {
+		javaParams = append(javaParams, getJavaParamType(param, w.enumMap, "", "")+" "+param.Name)
+		csParams = append(csParams, mapParamTypeToCsType(param, w.structMap, w.enumMap)+" "+param.Name)
+	}
+	javaResult, csResult := "void", "Task"
+	if method.ReturnType != nil {
+		javaResult = getJavaTypeWithPackage(method.ReturnType, w.enumMap, "", "")
+		csResult = "Task<" + mapTypeToCsType(method.ReturnType, w.structMap, w.enumMap, method.ReturnOptional) + ">"
+	}
+
+	return [][2]string{
+		{"Go", fmt.Sprintf("func (c *%sClient) %s(%s) %s", name, naming.SnakeToPascal(method.Name), strings.Join(goParams, ", "), goResult)},
+		{"Python", fmt.Sprintf("client.%s(%s)", method.Name, args)},
+		{"TypeScript", fmt.Sprintf("await client.%s(%s)", method.Name, args)},
+		{"Java", docsJavaPackage.ReplaceAllString(fmt.Sprintf("%s %s(%s)", javaResult, method.Name, strings.Join(javaParams, ", ")), "$1")},
+		{"C#", fmt.Sprintf("%s %sAsync(%s)", csResult, method.Name, strings.Join(csParams, ", "))},
+		{"Rust", fmt.Sprintf("fn %s(&self%s) -> Result<%s, Error>", rustIdent(method.Name), prefixParams(rustParamList(method)), rustReturnType(method))},
+	}
+}
+
+func (w *docsWriter) writeStruct(s *parser.Struct) {
+	w.r.heading(3, docsAnchor("struct", s.Name), docsSpan{Text: s.Name})
+	w.r.comment(s.Comment)
+	if s.Extends != "" {
+		w.r.paragraph(docsSpan{Text: "Extends "}, docsSpan{Text: s.Extends, Code: true, Anchor: docsAnchor("struct", s.Extends)}, docsSpan{Text: ", whose fields it also has"})
+	}
+	if len(s.Fields) == 0 {
+		return
+	}
+	rows := make([][][]docsSpan, len(s.Fields))
+	for i, field := range s.Fields {
+		description := []docsSpan{{Text: strings.Join(strings.Fields(field.Comment), " ")}}
+		for _, a := range field.Annotations {
+			description = append(description, docsSpan{Text: " "}, docsSpan{Text: docsAnnotation(a), Code: true})
+		}
+		rows[i] = [][]docsSpan{{{Text: field.Name, Code: true}}, {w.typeSpan(field.Type, field.Optional)}, description}
+	}
+	w.r.table([]string{"Field", "Type", "Description"}, rows)
+}
+
+func (w *docsWriter) writeEnum(e *parser.Enum) {
+	w.r.heading(3, docsAnchor("enum", e.Name), docsSpan{Text: e.Name})
+	w.r.comment(e.Comment)
+	rows := make([][][]docsSpan, len(e.Values))
+	for i, v := range e.Values {
+		rows[i] = [][]docsSpan{{{Text: v.Name, Code: true}}, {{Text: strings.Join(strings.Fields(v.Comment), " ")}}}
+	}
+	w.r.table([]string{"Value", "Description"}, rows)
+}
+
+// typeSpan returns the IDL notation of a type, linked to the section of the struct,
+// enum or typedef it refers to
+func (w *docsWriter) typeSpan(t *parser.Type, optional bool) docsSpan {
+	span := docsSpan{Code: true}
+	var write func(t *parser.Type)
+	write = func(t *parser.Type) {
+		switch {
+		case t == nil:
+		case t.Alias != "":
+			span.Text += t.Alias
+			if span.Anchor == "" && w.typedefs[t.Alias] != nil {
+				span.Anchor = docsAnchor("typedef", t.Alias)
+			}
+		case t.IsArray():
+			span.Text += "[]"
+			write(t.Array)
+		case t.IsMap():
+			span.Text += "map[string]"
+			write(t.MapValue)
+		case t.IsUserDefined():
+			span.Text += t.UserDefined
+			if w.structMap[t.UserDefined] != nil {
+				span.Anchor = docsAnchor("struct", t.UserDefined)
+			} else if w.enumMap[t.UserDefined] != nil {
+				span.Anchor = docsAnchor("enum", t.UserDefined)
+			}
+		default:
+			span.Text += t.BuiltIn
+		}
+	}
+	write(t)
+	if optional {
+		span.Text += " [optional]"
+	}
+	return span
+}
+
+// docsJavaPackage matches the package a Java type name is qualified with, which
+// the signatures leave out
+var docsJavaPackage = regexp.MustCompile(`\.?(?:[a-z][a-z0-9_]*\.)+([A-Z])`)
+
+// docsAnchor returns the anchor of the section of a declaration of the given kind
+func docsAnchor(kind, name string) string {
+	return kind + "-" + strings.ToLower(strings.ReplaceAll(name, ".", "-"))
+}
+
+// docsOwnership returns the spans that show a [stability] as a badge and an [owner],
+// or none if both are empty
+func docsOwnership(owner, stability string) []docsSpan {
+	var spans []docsSpan
+	if stability != "" {
+		spans = append(spans, docsSpan{Text: stability, Badge: true})
+	}
+	if owner != "" {
+		if len(spans) > 0 {
+			spans = append(spans, docsSpan{Text: " "})
+		}
+		spans = append(spans, docsSpan{Text: "Owner: "}, docsSpan{Text: owner, Code: true})
+	}
+	return spans
+}
+
+// docsAnnotation returns an annotation as written in the IDL
+func docsAnnotation(a *parser.Annotation) string {
+	if a.Value == "" {
+		return "[" + a.Name + "]"
+	}
+	return fmt.Sprintf("[%s=%q]", a.Name, a.Value)
+}
+
+// docsJSON returns v as indented JSON
+func docsJSON(v interface{}) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// docsParagraphs splits a comment into paragraphs at blank lines, joining the lines
+// of each
+func docsParagraphs(text string) []string {
+	var paragraphs, lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(lines) > 0 {
+				paragraphs = append(paragraphs, strings.Join(lines, " "))
+				lines = nil
+			}
+			continue
+		}
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	if len(lines) > 0 {
+		paragraphs = append(paragraphs, strings.Join(lines, " "))
+	}
+	return paragraphs
+}
+
+// markdownDocs writes GitHub flavored Markdown. Comments are written as they are,
+// so Markdown in them is rendered.
+type markdownDocs struct {
+	sb strings.Builder
+}
+
+func (m *markdownDocs) heading(level int, anchor string, spans ...docsSpan) {
+	if m.sb.Len() > 0 {
+		m.sb.WriteString("\n")
+	}
+	if anchor != "" {
+		fmt.Fprintf(&m.sb, "<a id=\"%s\"></a>\n\n", anchor)
+	}
+	m.sb.WriteString(strings.Repeat("#", level) + " " + m.inline(spans, false) + "\n")
+}
+
+func (m *markdownDocs) comment(text string) {
+	for _, p := range docsParagraphs(text) {
+		m.sb.WriteString("\n" + p + "\n")
+	}
+}
+
+func (m *markdownDocs) paragraph(spans ...docsSpan) {
+	m.sb.WriteString("\n" + m.inline(spans, false) + "\n")
+}
+
+func (m *markdownDocs) list(items [][]docsSpan) {
+	if len(items) == 0 {
+		return
+	}
+	m.sb.WriteString("\n")
+	for _, item := range items {
+		m.sb.WriteString("- " + m.inline(item, false) + "\n")
+	}
+}
+
+func (m *markdownDocs) table(header []string, rows [][][]docsSpan) {
+	m.sb.WriteString("\n| " + strings.Join(header, " | ") + " |\n|")
+	for range header {
+		m.sb.WriteString("---|")
+	}
+	m.sb.WriteString("\n")
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = m.inline(cell, true)
+		}
+		m.sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+}
+
+func (m *markdownDocs) code(language, code string) {
+	fmt.Fprintf(&m.sb, "\n```%s\n%s\n```\n", language, code)
+}
+
+func (m *markdownDocs) String() string {
+	return m.sb.String()
+}
+
+// inline returns spans as Markdown. In table cells, pipes are escaped.
+func (m *markdownDocs) inline(spans []docsSpan, cell bool) string {
+	var sb strings.Builder
+	for _, span := range spans {
+		text := span.Text
+		if span.Code {
+			fence := "`"
+			for strings.Contains(text, fence) {
+				fence += "`"
+			}
+			if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+				text = " " + text + " "
+			}
+			text = fence + text + fence
+		}
+		if span.Badge {
+			text = "**`" + text + "`**"
+		}
+		if cell {
+			text = strings.ReplaceAll(text, "|", "\\|")
+		}
+		if span.Anchor != "" {
+			text = "[" + text + "](#" + span.Anchor + ")"
+		}
+		sb.WriteString(text)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// htmlDocs writes a standalone HTML page. Comments are escaped and written as
+// paragraphs.
+type htmlDocs struct {
+	sb strings.Builder
+}
+
+func newHTMLDocs(title string) *htmlDocs {
+	h := &htmlDocs{}
+	h.sb.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	h.sb.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	fmt.Fprintf(&h.sb, "<title>%s</title>\n", html.EscapeString(title))
+	h.sb.WriteString("<style>\n" + embeddedTemplates.renderString("docs/api.css.tmpl", nil) + "</style>\n")
+	h.sb.WriteString("</head>\n<body>\n")
+	return h
+}
+
+func (h *htmlDocs) heading(level int, anchor string, spans ...docsSpan) {
+	id := ""
+	if anchor != "" {
+		id = fmt.Sprintf(" id=\"%s\"", html.EscapeString(anchor))
+	}
+	fmt.Fprintf(&h.sb, "<h%d%s>%s</h%d>\n", level, id, h.inline(spans), level)
+}
+
+func (h *htmlDocs) comment(text string) {
+	for _, p := range docsParagraphs(text) {
+		h.sb.WriteString("<p>" + html.EscapeString(p) + "</p>\n")
+	}
+}
+
+func (h *htmlDocs) paragraph(spans ...docsSpan) {
+	h.sb.WriteString("<p>" + h.inline(spans) + "</p>\n")
+}
+
+func (h *htmlDocs) list(items [][]docsSpan) {
+	if len(items) == 0 {
+		return
+	}
+	h.sb.WriteString("<ul>\n")
+	for _, item := range items {
+		h.sb.WriteString("<li>" + h.inline(item) + "</li>\n")
+	}
+	h.sb.WriteString("</ul>\n")
+}
+
+func (h *htmlDocs) table(header []string, rows [][][]docsSpan) {
+	h.sb.WriteString("<table>\n<thead><tr>")
+	for _, title := range header {
+		h.sb.WriteString("<th>" + html.EscapeString(title) + "</th>")
+	}
+	h.sb.WriteString("</tr></thead>\n<tbody>\n")
+	for _, row := range rows {
+		h.sb.WriteString("<tr>")
+		for _, cell := range row {
+			h.sb.WriteString("<td>" + h.inline(cell) + "</td>")
+		}
+		h.sb.WriteString("</tr>\n")
+	}
+	h.sb.WriteString("</tbody>\n</table>\n")
+}
+
+func (h *htmlDocs) code(language, code string) {
+	fmt.Fprintf(&h.sb, "<pre><code class=\"language-%s\">%s</code></pre>\n", language, html.EscapeString(code))
+}
+
+func (h *htmlDocs) String() string {
+	return h.sb.String() + "</body>\n</html>\n"
+}
+
+// inline returns spans as escaped HTML
+func (h *htmlDocs) inline(spans []docsSpan) string {
+	var sb strings.Builder
+	for _, span := range spans {
+		text := html.EscapeString(span.Text)
+		if span.Code {
+			text = "<code>" + text + "</code>"
+		}
+		if span.Badge {
+			text = "<span class=\"badge badge-" + html.EscapeString(span.Text) + "\">" + text + "</span>"
+		}
+		if span.Anchor != "" {
+			text = "<a href=\"#" + html.EscapeString(span.Anchor) + "\">" + text + "</a>"
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
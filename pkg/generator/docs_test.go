@@ -0,0 +1,130 @@
+package generator
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+const docsTestIDL = `namespace shop
+
+// An order of <items>
+//
+// Orders are immutable once placed.
+struct Order {
+    // The items | in order
+    items  []Item
+    note   string  [optional]
+}
+
+struct Item {
+    sku  string
+}
+
+// How an order is shipped
+enum Shipping {
+    // Within a day
+    express
+    standard
+}
+
+interface Orders {
+    place(items []Item, shipping Shipping [optional]) Order [idempotent]
+}
+`
+
+func TestBuildDocsMarkdown(t *testing.T) {
+	idl, err := parser.ParseIDL("test.pulse", docsTestIDL)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	doc := BuildDocs(idl, "markdown")
+	for _, want := range []string{
+		"# shop API Reference\n",
+		"<a id=\"struct-order\"></a>\n\n### Order\n\nAn order of <items>\n\nOrders are immutable once placed.\n",
+		// Pipes in table cells are escaped
+		"| `items` | [`[]Item`](#struct-item) | The items \\| in order |\n",
+		"| `note` | `string [optional]` |  |\n",
+		"| `express` | Within a day |\n",
+		"- [`place`](#method-orders-place)\n",
+		"| `shipping` | [`Shipping [optional]`](#enum-shipping) |\n",
+		"Annotations: `[idempotent]`\n",
+		"Returns [`Order`](#struct-order)\n",
+		"| Go | `func (c *OrdersClient) Place(items []Item, shipping *Shipping, opts ...CallOption) (Order, error)` |\n",
+		"| Java | `Order place(List<Item> items, Shipping shipping)` |\n",
+		"\"method\": \"Orders.place\"",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("api.md lacks %q:\n%s", want, doc)
+		}
+	}
+}
+
+func TestBuildDocsHTML(t *testing.T) {
+	idl, err := parser.ParseIDL("test.pulse", docsTestIDL)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	doc := BuildDocs(idl, "html")
+	for _, want := range []string{
+		"<title>shop API Reference</title>\n",
+		"<h3 id=\"struct-order\">Order</h3>\n<p>An order of &lt;items&gt;</p>\n<p>Orders are immutable once placed.</p>\n",
+		"<td><a href=\"#struct-item\"><code>[]Item</code></a></td>",
+		"<code>fn place(&amp;self, items: Vec&lt;Item&gt;, shipping: Option&lt;Shipping&gt;) -&gt; Result&lt;Order, Error&gt;</code>",
+		"</body>\n</html>\n",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("api.html lacks %q:\n%s", want, doc)
+		}
+	}
+}
+
+func TestBuildDocsOwnership(t *testing.T) {
+	idl, err := parser.ParseIDL("test.pulse", `namespace shop
+
+interface Orders [owner="team-orders"] [stability="stable"] {
+    place(sku string) string [idempotent]
+    split(orderId string) []string [stability="experimental"] [owner="team-fulfillment"]
+}
+`)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	doc := BuildDocs(idl, "markdown")
+	for _, want := range []string{
+		"### Orders\n\n**`stable`** Owner: `team-orders`\n",
+		// A method without its own annotations shows those of its interface
+		"#### `Orders.place`\n\n**`stable`** Owner: `team-orders`\n\nAnnotations: `[idempotent]`\n",
+		"#### `Orders.split`\n\n**`experimental`** Owner: `team-fulfillment`\n\n| Param |",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("api.md lacks %q:\n%s", want, doc)
+		}
+	}
+
+	doc = BuildDocs(idl, "html")
+	for _, want := range []string{
+		"<p><span class=\"badge badge-experimental\">experimental</span> Owner: <code>team-fulfillment</code></p>\n",
+		".badge-experimental {",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("api.html lacks %q:\n%s", want, doc)
+		}
+	}
+}
+
+func TestDocsInvalidFormat(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("dir", t.TempDir(), "output dir")
+	plugin := NewDocs()
+	plugin.RegisterFlags(fs)
+	if err := fs.Set("docs-format", "pdf"); err != nil {
+		t.Fatal(err)
+	}
+	err := plugin.Generate(&parser.IDL{}, fs)
+	if err == nil || !strings.Contains(err.Error(), "docs-format") {
+		t.Fatalf("expected a docs-format error, got %v", err)
+	}
+}
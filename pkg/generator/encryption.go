@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Encrypted fields: the value of a field annotated [encrypted] is replaced in the
+// JSON-RPC payload by a JSON string produced by an application supplied
+// FieldCipher, typically envelope encryption with a KMS data key. The cipher is
+// given the field as "Struct.field" and the JSON encoding of its value. Every
+// type registry marks such fields with encrypted: true, and the runtimes walk a
+// value by its type definition to encrypt or decrypt them. Clients encrypt params
+// after validating them and decrypt results before validating them; servers
+// decrypt params before validating them and encrypt results after, so handlers,
+// validation and response meta hooks only ever see plain values. Null fields are
+// sent as null. The walk only runs for the methods listed in the generated
+// encryptedMethods table, whose params or results can hold an encrypted field,
+// and none of it is generated for IDLs without [encrypted] fields. The C# and
+// Java generators reject such IDLs until they implement the hooks.
+
+// encryptionRuntimeNamesPy are the runtime names Python modules import when the IDL
+// has [encrypted] fields
+var encryptionRuntimeNamesPy = []string{"FieldCipher", "decrypt_fields", "encrypt_fields"}
+
+// usesEncryptedFields reports whether any struct field is [encrypted]
+func usesEncryptedFields(idl *parser.IDL) bool {
+	for _, s := range idl.Structs {
+		for _, field := range s.Fields {
+			if field.IsEncrypted() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// typeNeedsEncryption reports whether a value of type t can hold an [encrypted] field
+func typeNeedsEncryption(t *parser.Type, structMap map[string]*parser.Struct) bool {
+	return t != nil && typeHoldsEncryptedField(t, structMap, map[string]bool{})
+}
+
+func typeHoldsEncryptedField(t *parser.Type, structMap map[string]*parser.Struct, seen map[string]bool) bool {
+	switch {
+	case t.IsArray():
+		return typeHoldsEncryptedField(t.Array, structMap, seen)
+	case t.IsMap():
+		return typeHoldsEncryptedField(t.MapValue, structMap, seen)
+	case t.IsUserDefined():
+		if s := lookupStruct(t.UserDefined, structMap); s != nil {
+			return structHoldsEncryptedField(s, structMap, seen)
+		}
+	}
+	return false
+}
+
+func structHoldsEncryptedField(s *parser.Struct, structMap map[string]*parser.Struct, seen map[string]bool) bool {
+	if seen[s.Name] {
+		return false
+	}
+	seen[s.Name] = true
+	if s.Extends != "" {
+		if parent := lookupStruct(s.Extends, structMap); parent != nil && structHoldsEncryptedField(parent, structMap, seen) {
+			return true
+		}
+	}
+	for _, field := range s.Fields {
+		if field.IsEncrypted() || typeHoldsEncryptedField(field.Type, structMap, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// paramsNeedEncryption reports whether any param of method can hold an [encrypted] field
+func paramsNeedEncryption(method *parser.Method, structMap map[string]*parser.Struct) bool {
+	for _, param := range method.Parameters {
+		if typeNeedsEncryption(param.Type, structMap) {
+			return true
+		}
+	}
+	return false
+}
+
+// methodNeedsEncryption reports whether the params or result of method can hold
+// an [encrypted] field
+func methodNeedsEncryption(method *parser.Method, structMap map[string]*parser.Struct) bool {
+	return paramsNeedEncryption(method, structMap) || typeNeedsEncryption(method.ReturnType, structMap)
+}
+
+// interfaceNeedsEncryption reports whether any method of iface has params or a
+// result that can hold an [encrypted] field
+func interfaceNeedsEncryption(iface *parser.Interface, structMap map[string]*parser.Struct) bool {
+	for _, method := range iface.Methods {
+		if methodNeedsEncryption(method, structMap) {
+			return true
+		}
+	}
+	return false
+}
+
+// encryptedMethods returns the Interface.method names, inherited methods
+// included, whose params or result can hold an [encrypted] field
+func encryptedMethods(idl *parser.IDL) []string {
+	structMap := make(map[string]*parser.Struct, len(idl.Structs))
+	for _, s := range idl.Structs {
+		structMap[s.Name] = s
+	}
+	var methods []string
+	for _, iface := range idl.Interfaces {
+		for _, method := range iface.Methods {
+			if methodNeedsEncryption(method, structMap) {
+				methods = append(methods, iface.Name+"."+method.Name)
+			}
+		}
+	}
+	return methods
+}
+
+// writeEncryptedMethodsGo writes the encryptedMethods table of the Go server
+func writeEncryptedMethodsGo(sb *strings.Builder, idl *parser.IDL) {
+	sb.WriteString("// encryptedMethods lists the methods whose params or result can hold an\n")
+	sb.WriteString("// [encrypted] field\n")
+	sb.WriteString("var encryptedMethods = map[string]bool{\n")
+	for _, name := range encryptedMethods(idl) {
+		fmt.Fprintf(sb, "	%q: true,\n", name)
+	}
+	sb.WriteString("}\n\n")
+}
+
+// writeEncryptedMethodsPy writes the ENCRYPTED_METHODS set of the Python server
+func writeEncryptedMethodsPy(sb *strings.Builder, idl *parser.IDL) {
+	sb.WriteString("# Methods whose params or result can hold an [encrypted] field\n")
+	sb.WriteString("ENCRYPTED_METHODS = {\n")
+	for _, name := range encryptedMethods(idl) {
+		fmt.Fprintf(sb, "    '%s',\n", name)
+	}
+	sb.WriteString("}\n\n\n")
+}
+
+// writeEncryptedMethodsTs writes the ENCRYPTED_METHODS set of the TypeScript server
+func writeEncryptedMethodsTs(sb *strings.Builder, idl *parser.IDL) {
+	sb.WriteString("// Methods whose params or result can hold an [encrypted] field\n")
+	sb.WriteString("const ENCRYPTED_METHODS: Set<string> = new Set([\n")
+	for _, name := range encryptedMethods(idl) {
+		fmt.Fprintf(sb, "  '%s',\n", name)
+	}
+	sb.WriteString("]);\n\n")
+}
@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+const encryptedFieldsIDL = `namespace vault
+struct Base {
+  ssn string [encrypted]
+}
+struct Patient extends Base {
+  name string
+}
+struct Ward {
+  patients []Patient
+}
+interface Records {
+  save(patient Patient) bool
+  ward(name string) Ward
+  count() int
+}`
+
+func TestEncryptedMethods(t *testing.T) {
+	idl, err := parser.ParseIDL("vault.pulse", encryptedFieldsIDL)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	if !usesEncryptedFields(idl) {
+		t.Fatal("expected the IDL to use encrypted fields")
+	}
+	want := []string{"Records.save", "Records.ward"}
+	if got := encryptedMethods(idl); !reflect.DeepEqual(got, want) {
+		t.Errorf("encryptedMethods = %v, want %v", got, want)
+	}
+}
+
+func TestEncryptedFieldsGenerated(t *testing.T) {
+	idl, err := parser.ParseIDL("vault.pulse", encryptedFieldsIDL)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+
+	tests := []struct {
+		plugin Plugin
+		want   map[string][]string
+	}{
+		{
+			plugin: NewGoClientServer(),
+			want: map[string][]string{
+				"server.go": {`"Records.save": true,`, `"Records.ward": true,`, "func (s *PulseRPCServer) SetFieldCipher(cipher FieldCipher) {"},
+				"client.go": {"func (c *RecordsClient) SetFieldCipher(cipher FieldCipher) {", "EncryptFields(paramInterface, paramType, ALL_STRUCTS, c.cipher)"},
+				"vault.go":  {`"encrypted": true,`},
+			},
+		},
+		{
+			plugin: NewPythonClientServer(),
+			want: map[string][]string{
+				"server.py": {"ENCRYPTED_METHODS = {\n    'Records.save',\n    'Records.ward',\n}", "field_cipher: Optional[FieldCipher] = None"},
+				"client.py": {"def __init__(self, transport: Transport, field_cipher: Optional[FieldCipher] = None):", "result = decrypt_fields("},
+				"vault.py":  {"'encrypted': True,"},
+			},
+		},
+		{
+			plugin: NewTSClientServer(),
+			want: map[string][]string{
+				"server.ts": {"const ENCRYPTED_METHODS: Set<string> = new Set([\n  'Records.save',\n  'Records.ward',\n]);", "setFieldCipher(cipher: FieldCipher): void {"},
+				"client.ts": {"import { FieldCipher, decryptFields, encryptFields } from './pulserpc/encryption';", "encryptFields(value, expectedParams[i].type, ALL_STRUCTS, this.fieldCipher)"},
+				"vault.ts":  {"encrypted: true,"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tmpDir := t.TempDir()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		tt.plugin.RegisterFlags(fs)
+		if err := fs.Set("dir", tmpDir); err != nil {
+			t.Fatalf("failed to set dir flag: %v", err)
+		}
+		if err := tt.plugin.Generate(idl, fs); err != nil {
+			t.Fatalf("%s: Generate failed: %v", tt.plugin.Name(), err)
+		}
+		for file, wants := range tt.want {
+			content, err := os.ReadFile(filepath.Join(tmpDir, file))
+			if err != nil {
+				t.Fatalf("%s: expected %s: %v", tt.plugin.Name(), file, err)
+			}
+			for _, want := range wants {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("%s: %s missing %q", tt.plugin.Name(), file, want)
+				}
+			}
+		}
+	}
+}
+
+func TestEncryptedFieldsUnsupported(t *testing.T) {
+	idl, err := parser.ParseIDL("vault.pulse", encryptedFieldsIDL)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	for _, plugin := range []Plugin{NewCSharpClientServer(), NewJavaClientServer()} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		plugin.RegisterFlags(fs)
+		for name, value := range map[string]string{"dir": t.TempDir(), "base-package": "com.example"} {
+			if fs.Lookup(name) != nil {
+				if err := fs.Set(name, value); err != nil {
+					t.Fatalf("failed to set %s flag: %v", name, err)
+				}
+			}
+		}
+		err := plugin.Generate(idl, fs)
+		if err == nil || !strings.Contains(err.Error(), "[encrypted] fields are not supported") {
+			t.Errorf("%s: expected an unsupported [encrypted] error, got %v", plugin.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Typed error data: a method annotated [errordata="OutOfStock"] declares the struct
+// the data of its error responses holds. Clients decode the data of an RPCError
+// raised by such a method into that struct when it validates against it (Go,
+// Python, TypeScript) or deserializes into it (C#, Java), and otherwise leave the
+// error as it was. Go keeps returning *RPCError, with Data holding a *OutOfStock.
+// The other languages throw an OutOfStockError, a subclass of RPCError whose data
+// is the struct, so existing handlers of RPCError keep working. Servers are
+// unchanged: handlers raise RPCError with the struct as its data, as before.
+
+// errorDataStructs returns the structs named by [errordata] annotations, sorted
+func errorDataStructs(interfaces []*parser.Interface) []string {
+	seen := make(map[string]bool)
+	var structs []string
+	for _, iface := range interfaces {
+		for _, method := range iface.Methods {
+			if name := method.ErrorData(); name != "" && !seen[name] {
+				seen[name] = true
+				structs = append(structs, name)
+			}
+		}
+	}
+	sort.Strings(structs)
+	return structs
+}
+
+// errorDataClass returns the name of the RPCError subclass for an [errordata] struct
+func errorDataClass(structName string) string {
+	return GetBaseName(structName) + "Error"
+}
+
+// writeBindErrorDataGo writes the Go client helper that decodes the data of an
+// RPCError into an [errordata] struct
+func writeBindErrorDataGo(sb *strings.Builder) {
+	sb.WriteString(`// bindErrorData decodes the data of an RPCError into T, the struct named by the
+// method's [errordata] annotation, and sets Data to the *T. Data that does not
+// validate against the struct is left as decoded from JSON.
+func bindErrorData[T any](err error, structName string) error {
+	rpcErr, ok := err.(*RPCError)
+	if !ok || rpcErr.Data == nil {
+		return err
+	}
+	dataJSON, jsonErr := json.Marshal(rpcErr.Data)
+	if jsonErr != nil {
+		return err
+	}
+	var dataInterface interface{}
+	json.Unmarshal(dataJSON, &dataInterface)
+	structType := map[string]interface{}{"userDefined": structName}
+	if ValidateType(dataInterface, structType, ALL_STRUCTS, ALL_ENUMS, false) != nil {
+		return err
+	}
+	data := new(T)
+	if json.Unmarshal(dataJSON, data) != nil {
+		return err
+	}
+	rpcErr.Data = data
+	return err
+}
+
+`)
+}
+
+// writeErrorDataClassesPy writes the Python RPCError subclasses of the [errordata]
+// structs and the helper that raises them
+func writeErrorDataClassesPy(sb *strings.Builder, structs []string) {
+	for _, name := range structs {
+		fmt.Fprintf(sb, "class %s(RPCError):\n", errorDataClass(name))
+		fmt.Fprintf(sb, "    \"\"\"RPCError raised by methods annotated [errordata=\"%s\"] when the error data\n", name)
+		fmt.Fprintf(sb, "    is a valid %s, which data holds\"\"\"\n\n", name)
+		fmt.Fprintf(sb, "    struct_name = '%s'\n\n\n", name)
+	}
+	sb.WriteString(`def _bind_error_data(error: RPCError, error_class: type) -> Optional[RPCError]:
+    """Return error as an error_class when its data validates against the struct named
+    by error_class.struct_name, otherwise None"""
+    if error.data is None:
+        return None
+    try:
+        validate_type(error.data, {'userDefined': error_class.struct_name}, ALL_STRUCTS, ALL_ENUMS, False)
+    except Exception:
+        return None
+    return error_class(error.code, error.message, error.data)
+
+
+`)
+}
+
+// writeErrorDataClassesTs writes the TypeScript RPCError subclasses of the [errordata]
+// structs and the helper that throws them
+func writeErrorDataClassesTs(sb *strings.Builder, structs []string, packagePrefix string) {
+	for _, name := range structs {
+		fmt.Fprintf(sb, "// Thrown by methods annotated [errordata=\"%s\"] when the error data is a valid\n", name)
+		fmt.Fprintf(sb, "// %s, which data holds\n", name)
+		fmt.Fprintf(sb, "export class %s extends RPCError {\n", applyPackagePrefix(errorDataClass(name), packagePrefix))
+		fmt.Fprintf(sb, "  static readonly structName = '%s';\n\n", name)
+		sb.WriteString("  constructor(cause: RPCError) {\n")
+		sb.WriteString("    super(cause.code, '', cause.data);\n")
+		sb.WriteString("    this.message = cause.message;\n")
+		sb.WriteString("  }\n")
+		sb.WriteString("}\n\n")
+	}
+	sb.WriteString(`type ErrorDataClass = { new (cause: RPCError): RPCError; readonly structName: string };
+
+// Returns err as an errorClass when it is an RPCError whose data validates against the
+// struct named by errorClass.structName, otherwise err
+function bindErrorData(err: unknown, errorClass: ErrorDataClass): unknown {
+  if (!(err instanceof RPCError) || err.data === undefined || err.data === null) {
+    return err;
+  }
+  try {
+    validateType(err.data, { userDefined: errorClass.structName }, ALL_STRUCTS, ALL_ENUMS, false);
+  } catch {
+    return err;
+  }
+  return new errorClass(err);
+}
+
+`)
+}
+
+// writeErrorDataClassesCs writes the C# RPCError subclasses of the [errordata] structs
+func writeErrorDataClassesCs(sb *strings.Builder, structs []string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) {
+	for _, name := range structs {
+		className := errorDataClass(name)
+		structType := mapTypeToCsType(&parser.Type{UserDefined: name}, structMap, enumMap, false)
+		sb.WriteString("/// <summary>\n")
+		fmt.Fprintf(sb, "/// Thrown by methods annotated [errordata=\"%s\"] when the error data deserializes\n", name)
+		fmt.Fprintf(sb, "/// into a %s, which Data holds\n", structType)
+		sb.WriteString("/// </summary>\n")
+		fmt.Fprintf(sb, "public class %s : RPCError\n", className)
+		sb.WriteString("{\n")
+		fmt.Fprintf(sb, "    public new %s Data { get; }\n\n", structType)
+		fmt.Fprintf(sb, "    public %s(int code, string message, %s data) : base(code, message, data)\n", className, structType)
+		sb.WriteString("    {\n")
+		sb.WriteString("        Data = data;\n")
+		sb.WriteString("    }\n\n")
+		fmt.Fprintf(sb, "    internal static bool TryBind(RPCError error, out %s typed)\n", className)
+		sb.WriteString("    {\n")
+		sb.WriteString("        typed = null!;\n")
+		sb.WriteString("        if (error.Data == null)\n")
+		sb.WriteString("        {\n")
+		sb.WriteString("            return false;\n")
+		sb.WriteString("        }\n")
+		sb.WriteString("        var dataJson = error.Data is JsonElement element ? element.GetRawText() : JsonSerializer.Serialize(error.Data);\n")
+		sb.WriteString("        var options = new JsonSerializerOptions { PropertyNameCaseInsensitive = true };\n")
+		sb.WriteString("        options.Converters.Add(new JsonStringEnumConverter());\n")
+		sb.WriteString("        try\n")
+		sb.WriteString("        {\n")
+		fmt.Fprintf(sb, "            var data = JsonSerializer.Deserialize<%s>(dataJson, options);\n", structType)
+		sb.WriteString("            if (data == null)\n")
+		sb.WriteString("            {\n")
+		sb.WriteString("                return false;\n")
+		sb.WriteString("            }\n")
+		fmt.Fprintf(sb, "            typed = new %s(error.Code, error.Message, data);\n", className)
+		sb.WriteString("            return true;\n")
+		sb.WriteString("        }\n")
+		sb.WriteString("        catch (JsonException)\n")
+		sb.WriteString("        {\n")
+		sb.WriteString("            return false;\n")
+		sb.WriteString("        }\n")
+		sb.WriteString("    }\n")
+		sb.WriteString("}\n\n")
+	}
+}
+
+// generateErrorDataClassJava generates the Java RPCError subclass of an [errordata]
+// struct, written next to the struct
+func generateErrorDataClassJava(structName string, packageName string) string {
+	var sb strings.Builder
+	className := errorDataClass(structName)
+	structType := GetBaseName(structName)
+	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
+	fmt.Fprintf(&sb, "package %s;\n\n", packageName)
+	sb.WriteString("import com.bitmechanic.pulserpc.*;\n\n")
+	sb.WriteString("/**\n")
+	fmt.Fprintf(&sb, " * Thrown by methods annotated [errordata=\"%s\"] when the error data converts into\n", structName)
+	fmt.Fprintf(&sb, " * a {@link %s}, which getData() returns\n", structType)
+	sb.WriteString(" */\n")
+	fmt.Fprintf(&sb, "public class %s extends RPCError {\n\n", className)
+	fmt.Fprintf(&sb, "    private final %s data;\n\n", structType)
+	fmt.Fprintf(&sb, "    public %s(int code, String message, %s data) {\n", className, structType)
+	sb.WriteString("        super(code, message, data);\n")
+	sb.WriteString("        this.data = data;\n")
+	sb.WriteString("    }\n\n")
+	sb.WriteString("    @Override\n")
+	fmt.Fprintf(&sb, "    public %s getData() {\n", structType)
+	sb.WriteString("        return data;\n")
+	sb.WriteString("    }\n\n")
+	sb.WriteString("    /**\n")
+	fmt.Fprintf(&sb, "     * Returns error as a %s when its data converts into a %s, otherwise error\n", className, structType)
+	sb.WriteString("     */\n")
+	sb.WriteString("    public static RPCError bind(RPCError error, JsonParser jsonParser) {\n")
+	fmt.Fprintf(&sb, "        if (error.getData() == null || error instanceof %s) {\n", className)
+	sb.WriteString("            return error;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        try {\n")
+	fmt.Fprintf(&sb, "            %s data = jsonParser.convert(error.getData(), %s.class);\n", structType, structType)
+	fmt.Fprintf(&sb, "            return data == null ? error : new %s(error.getCode(), error.getMessage(), data);\n", className)
+	sb.WriteString("        } catch (RuntimeException e) {\n")
+	sb.WriteString("            return error;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
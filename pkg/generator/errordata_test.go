@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestErrorDataStructs(t *testing.T) {
+	idl, err := parser.ParseIDL("shop.pulse", "namespace shop\n\nstruct OutOfStock {\n  sku string\n}\n\nstruct Declined {\n  reason string\n}\n\ninterface Orders {\n  place(sku string) string [errordata=\"OutOfStock\"]\n  pay(id string) bool [errordata=\"Declined\"]\n  reserve(sku string) bool [errordata=\"OutOfStock\"]\n  cancel(id string) bool\n}")
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	got := errorDataStructs(idl.Interfaces)
+	if strings.Join(got, ",") != "Declined,OutOfStock" {
+		t.Errorf("errorDataStructs = %v, want [Declined OutOfStock]", got)
+	}
+	if name := errorDataClass("inc.OutOfStock"); name != "OutOfStockError" {
+		t.Errorf("errorDataClass = %q, want OutOfStockError", name)
+	}
+}
+
+func TestErrorDataGenerated(t *testing.T) {
+	tests := []struct {
+		name    string
+		idl     string
+		enabled bool
+	}{
+		{"errordata methods", "namespace shop\n\nstruct OutOfStock {\n  sku string\n}\n\ninterface Orders {\n  place(sku string) string [errordata=\"OutOfStock\"]\n}", true},
+		{"no errordata methods", "namespace shop\n\nstruct OutOfStock {\n  sku string\n}\n\ninterface Orders {\n  place(sku string) string\n}", false},
+	}
+	plugins := []struct {
+		plugin Plugin
+		file   string
+		want   []string
+	}{
+		{
+			plugin: NewGoClientServer(),
+			file:   "client.go",
+			want: []string{
+				"func bindErrorData[T any](err error, structName string) error {",
+				"\t\terr = bindErrorData[OutOfStock](err, \"OutOfStock\")\n",
+			},
+		},
+		{
+			plugin: NewPythonClientServer(),
+			file:   "client.py",
+			want: []string{
+				"class OutOfStockError(RPCError):",
+				"            typed = _bind_error_data(e, OutOfStockError)\n",
+				"            raise _bind_error_data(rpc_error, OutOfStockError) or rpc_error\n",
+			},
+		},
+		{
+			plugin: NewTSClientServer(),
+			file:   "client.ts",
+			want: []string{
+				"export class OutOfStockError extends RPCError {",
+				"      throw bindErrorData(err, OutOfStockError);\n",
+				"      throw bindErrorData(new RPCError(code, message, data), OutOfStockError);\n",
+			},
+		},
+		{
+			plugin: NewCSharpClientServer(),
+			file:   "Client.cs",
+			want: []string{
+				"public class OutOfStockError : RPCError",
+				"        catch (RPCError e) when (OutOfStockError.TryBind(e, out var typed))\n",
+			},
+		},
+		{
+			plugin: NewJavaClientServer(),
+			file:   "src/main/java/com/example/shop/OrdersClient.java",
+			want: []string{
+				"                throw OutOfStockError.bind((RPCError) e, jsonParser);\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		idl, err := parser.ParseIDL("shop.pulse", tt.idl)
+		if err != nil {
+			t.Fatalf("%s: ParseIDL failed: %v", tt.name, err)
+		}
+		for _, p := range plugins {
+			tmpDir := t.TempDir()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.String("dir", "", "output dir")
+			p.plugin.RegisterFlags(fs)
+			for name, value := range map[string]string{"dir": tmpDir, "base-package": "com.example"} {
+				if fs.Lookup(name) != nil {
+					if err := fs.Set(name, value); err != nil {
+						t.Fatalf("failed to set %s flag: %v", name, err)
+					}
+				}
+			}
+			if err := p.plugin.Generate(idl, fs); err != nil {
+				t.Fatalf("%s: %s: Generate failed: %v", tt.name, p.plugin.Name(), err)
+			}
+			content, err := os.ReadFile(filepath.Join(tmpDir, p.file))
+			if err != nil {
+				t.Fatalf("%s: expected %s: %v", p.plugin.Name(), p.file, err)
+			}
+			for _, want := range p.want {
+				if got := strings.Contains(string(content), want); got != tt.enabled {
+					t.Errorf("%s: %s: %s contains %q = %v, want %v", tt.name, p.plugin.Name(), p.file, want, got, tt.enabled)
+				}
+			}
+			if p.plugin.Name() == "java-client-server" {
+				_, err := os.Stat(filepath.Join(tmpDir, "src/main/java/com/example/shop/OutOfStockError.java"))
+				if got := err == nil; got != tt.enabled {
+					t.Errorf("%s: OutOfStockError.java written = %v, want %v", tt.name, got, tt.enabled)
+				}
+			}
+		}
+	}
+}
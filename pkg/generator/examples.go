@@ -0,0 +1,159 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// examplesFileName is the file written by the examples plugin
+const examplesFileName = "examples.json"
+
+// examplesVersion is bumped whenever the file layout changes incompatibly
+const examplesVersion = 1
+
+// ExampleFile is the document of sample requests and responses written by the
+// examples plugin, for documentation tooling to render next to each method
+type ExampleFile struct {
+	Version  int             `json:"version"`
+	Examples []MethodExample `json:"examples"`
+}
+
+// MethodExample is a JSON-RPC request and a successful response for one method.
+// Every struct field is filled in, optional ones included, so readers see the full
+// shape of the types; enums use their first value.
+type MethodExample struct {
+	Method   string                 `json:"method"`
+	Request  map[string]interface{} `json:"request"`
+	Response map[string]interface{} `json:"response"`
+}
+
+// Examples generates examples.json with a sample request and response for every
+// method of the IDL
+type Examples struct {
+}
+
+// NewExamples creates a new Examples plugin instance
+func NewExamples() *Examples {
+	return &Examples{}
+}
+
+// Name returns the plugin identifier
+func (p *Examples) Name() string {
+	return "examples"
+}
+
+// RegisterFlags registers CLI flags for this plugin
+func (p *Examples) RegisterFlags(fs *flag.FlagSet) {
+}
+
+// Generate writes examples.json to the output directory
+func (p *Examples) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
+	outputDir := ""
+	if dirFlag := fs.Lookup("dir"); dirFlag != nil {
+		outputDir = dirFlag.Value.String()
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(BuildExamples(idl)); err != nil {
+		return fmt.Errorf("failed to marshal examples: %w", err)
+	}
+	if err := writeGeneratedFile(filepath.Join(outputDir, examplesFileName), buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", examplesFileName, err)
+	}
+	return nil
+}
+
+// BuildExamples returns a sample request and response for every method in the IDL.
+// Methods with @example blocks get one entry per block, with its params and result;
+// the others get generated values.
+func BuildExamples(idl *parser.IDL) *ExampleFile {
+	b := newTestVectorBuilder(idl)
+	file := &ExampleFile{Version: examplesVersion, Examples: []MethodExample{}}
+	for _, iface := range idl.Interfaces {
+		id := 0
+		add := func(name string, params interface{}, result interface{}) {
+			id++
+			file.Examples = append(file.Examples, MethodExample{
+				Method: name,
+				Request: map[string]interface{}{
+					"jsonrpc": "2.0",
+					"method":  name,
+					"params":  params,
+					"id":      id,
+				},
+				Response: map[string]interface{}{
+					"jsonrpc": "2.0",
+					"result":  result,
+					"id":      id,
+				},
+			})
+		}
+		for _, method := range iface.Methods {
+			name := iface.RPCName(method)
+			for _, example := range method.Examples {
+				add(name, example.Params, example.Result)
+			}
+			if len(method.Examples) > 0 {
+				continue
+			}
+			params := make([]interface{}, len(method.Parameters))
+			for j, param := range method.Parameters {
+				params[j] = b.exampleValue(param.Type, map[string]bool{})
+			}
+			add(name, params, b.exampleValue(method.ReturnType, map[string]bool{}))
+		}
+	}
+	return file
+}
+
+// exampleValue is validValue with optional struct fields included. An optional
+// field whose struct is already being expanded is omitted, so recursive types such
+// as trees produce a single level.
+func (b *testVectorBuilder) exampleValue(t *parser.Type, expanding map[string]bool) interface{} {
+	switch {
+	case t.IsArray():
+		return []interface{}{b.exampleValue(t.Array, expanding)}
+	case t.IsMap():
+		return map[string]interface{}{"key": b.exampleValue(t.MapValue, expanding)}
+	}
+	s := b.structFor(t)
+	if s == nil {
+		return b.validValue(t)
+	}
+
+	expanding[s.Name] = true
+	defer delete(expanding, s.Name)
+	obj := make(map[string]interface{})
+	for _, field := range parser.ResolveStructFields(s, b.structs) {
+		if field.Optional && b.refersTo(field.Type, expanding) {
+			continue
+		}
+		obj[field.Name] = b.exampleValue(field.Type, expanding)
+	}
+	return obj
+}
+
+// refersTo reports whether t is, or is a collection of, a struct in names
+func (b *testVectorBuilder) refersTo(t *parser.Type, names map[string]bool) bool {
+	for t.IsArray() || t.IsMap() {
+		if t.IsArray() {
+			t = t.Array
+		} else {
+			t = t.MapValue
+		}
+	}
+	s := b.structFor(t)
+	return s != nil && names[s.Name]
+}
@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestBuildExamplesRecursiveStruct(t *testing.T) {
+	idl := &parser.IDL{
+		Structs: []*parser.Struct{{
+			Name: "tree.Node",
+			Fields: []*parser.Field{
+				{Name: "label", Type: &parser.Type{BuiltIn: "string"}},
+				{Name: "kind", Type: &parser.Type{UserDefined: "tree.Kind"}, Optional: true},
+				{Name: "children", Type: &parser.Type{Array: &parser.Type{UserDefined: "tree.Node"}}, Optional: true},
+			},
+		}},
+		Enums: []*parser.Enum{{
+			Name:   "tree.Kind",
+			Values: []*parser.EnumValue{{Name: "leaf"}, {Name: "branch"}},
+		}},
+		Interfaces: []*parser.Interface{{
+			Name: "tree.Trees",
+			Methods: []*parser.Method{{
+				Name:       "save",
+				Parameters: []*parser.Parameter{{Name: "root", Type: &parser.Type{UserDefined: "tree.Node"}}},
+				ReturnType: &parser.Type{Array: &parser.Type{UserDefined: "tree.Node"}},
+			}},
+		}},
+	}
+
+	examples := BuildExamples(idl).Examples
+	if len(examples) != 1 || examples[0].Method != "tree.Trees.save" {
+		t.Fatalf("examples = %+v, want one for tree.Trees.save", examples)
+	}
+	node := map[string]interface{}{"label": "test", "kind": "leaf"}
+	if got := examples[0].Request["params"]; !reflect.DeepEqual(got, []interface{}{node}) {
+		t.Errorf("params = %v, want %v", got, []interface{}{node})
+	}
+	if got := examples[0].Response["result"]; !reflect.DeepEqual(got, []interface{}{node}) {
+		t.Errorf("result = %v, want %v", got, []interface{}{node})
+	}
+}
+
+func TestBuildExamplesFromIDL(t *testing.T) {
+	idl, err := parser.ParseIDL("calc.pulse", `namespace calc
+interface Calc {
+  @example(params=[1, 2], result=3)
+  @example(params={"a": -1, "b": 1}, result=0)
+  add(a int, b int) int
+  neg(a int) int
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	examples := BuildExamples(idl).Examples
+	if len(examples) != 3 {
+		t.Fatalf("examples = %+v, want two for add and one for neg", examples)
+	}
+	encoded, err := json.Marshal(examples[1].Request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"id":2,"jsonrpc":"2.0","method":"Calc.add","params":{"a":-1,"b":1}}`; string(encoded) != want {
+		t.Errorf("request = %s, want %s", encoded, want)
+	}
+	if got := examples[2].Request["params"]; !reflect.DeepEqual(got, []interface{}{1}) {
+		t.Errorf("generated params = %v, want [1]", got)
+	}
+}
@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// API client facade: every client also gets an ApiClient (APIClient in Go) that
+// takes one transport and holds a client for each interface of the IDL under the
+// interface's name, so callers build one object instead of one client per
+// interface: api.Catalog.getProduct(...). The facade is left out when an interface
+// is itself named Api, whose client would have the same name.
+
+// usesAPIClientFacade reports whether the ApiClient facade can be generated
+func usesAPIClientFacade(interfaces []*parser.Interface) bool {
+	if len(interfaces) == 0 {
+		return false
+	}
+	for _, iface := range interfaces {
+		if strings.EqualFold(GetBaseName(iface.Name), "api") {
+			return false
+		}
+	}
+	return true
+}
+
+// writeAPIClientGo writes the APIClient facade of the Go client
+func writeAPIClientGo(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("// APIClient holds a client for every interface of the IDL, all calling through one transport\n")
+	sb.WriteString("type APIClient struct {\n")
+	for _, iface := range interfaces {
+		fmt.Fprintf(sb, "	%s *%sClient\n", iface.Name, iface.Name)
+	}
+	sb.WriteString("}\n\n")
+	sb.WriteString("// NewAPIClient creates an APIClient whose interface clients call through transport\n")
+	sb.WriteString("func NewAPIClient(transport Transport) *APIClient {\n")
+	sb.WriteString("	return &APIClient{\n")
+	for _, iface := range interfaces {
+		fmt.Fprintf(sb, "		%s: New%sClient(transport),\n", iface.Name, iface.Name)
+	}
+	sb.WriteString("	}\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeAPIClientPy writes the ApiClient facade of the Python client
+func writeAPIClientPy(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("class ApiClient:\n")
+	sb.WriteString("    \"\"\"A client for every interface of the IDL, all calling through one transport.\"\"\"\n\n")
+	sb.WriteString("    def __init__(self, transport: Transport):\n")
+	for _, iface := range interfaces {
+		fmt.Fprintf(sb, "        self.%s = %sClient(transport)\n", iface.Name, iface.Name)
+	}
+	sb.WriteString("\n\n")
+}
+
+// writeAPIClientTs writes the ApiClient facade of the TypeScript client
+func writeAPIClientTs(sb *strings.Builder, interfaces []*parser.Interface, packagePrefix string) {
+	sb.WriteString("/** A client for every interface of the IDL, all calling through one transport. */\n")
+	fmt.Fprintf(sb, "export class %s {\n", applyPackagePrefix("ApiClient", packagePrefix))
+	for _, iface := range interfaces {
+		fmt.Fprintf(sb, "  readonly %s: %s;\n", iface.Name, applyPackagePrefix(iface.Name+"Client", packagePrefix))
+	}
+	sb.WriteString("\n")
+	fmt.Fprintf(sb, "  constructor(transport: %s) {\n", applyPackagePrefix("Transport", packagePrefix))
+	for _, iface := range interfaces {
+		fmt.Fprintf(sb, "    this.%s = new %s(transport);\n", iface.Name, applyPackagePrefix(iface.Name+"Client", packagePrefix))
+	}
+	sb.WriteString("  }\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeAPIClientCs writes the ApiClient facade of the C# client
+func writeAPIClientCs(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("/// <summary>\n")
+	sb.WriteString("/// A client for every interface of the IDL, all calling through one transport.\n")
+	sb.WriteString("/// </summary>\n")
+	sb.WriteString("public class ApiClient\n")
+	sb.WriteString("{\n")
+	for _, iface := range interfaces {
+		fmt.Fprintf(sb, "    public %sClient %s { get; }\n", iface.Name, iface.Name)
+	}
+	sb.WriteString("\n")
+	sb.WriteString("    public ApiClient(ITransport transport)\n")
+	sb.WriteString("    {\n")
+	for _, iface := range interfaces {
+		fmt.Fprintf(sb, "        %s = new %sClient(transport);\n", iface.Name, iface.Name)
+	}
+	sb.WriteString("    }\n")
+	sb.WriteString("}\n\n")
+}
+
+// generateAPIClientJava generates ApiClient.java in the base package, importing the
+// interface clients from their namespace packages
+func generateAPIClientJava(interfaces []*parser.Interface, basePackage string) string {
+	var sb strings.Builder
+
+	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
+	fmt.Fprintf(&sb, "package %s;\n\n", basePackage)
+	sb.WriteString("import com.bitmechanic.pulserpc.*;\n")
+	imports := make(map[string]bool)
+	for _, iface := range interfaces {
+		if ns := GetNamespaceFromType(iface.Name, iface.Namespace); ns != "" {
+			imports[basePackage+"."+strings.ToLower(ns)+"."+GetBaseName(iface.Name)+"Client"] = true
+		}
+	}
+	writeJavaImports(&sb, imports)
+	if len(imports) == 0 {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("/**\n")
+	sb.WriteString(" * A client for every interface of the IDL, all calling through one transport\n")
+	sb.WriteString(" */\n")
+	sb.WriteString("public class ApiClient {\n")
+	for _, iface := range interfaces {
+		name := GetBaseName(iface.Name)
+		fmt.Fprintf(&sb, "    public final %sClient %s;\n", name, name)
+	}
+	sb.WriteString("\n")
+	sb.WriteString("    public ApiClient(Transport transport, JsonParser jsonParser) {\n")
+	for _, iface := range interfaces {
+		name := GetBaseName(iface.Name)
+		fmt.Fprintf(&sb, "        this.%s = new %sClient(transport, jsonParser);\n", name, name)
+	}
+	sb.WriteString("    }\n")
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
@@ -0,0 +1,23 @@
+package generator
+
+import (
+	"flag"
+)
+
+// The -generate-fault-injection flag lets the Go, Python and TypeScript servers
+// inject faults from a JSON config: per-method latency, error responses and
+// malformed responses, drawn per call from a seeded random source. The config
+// format and the draw live in the runtime's faults file; the server loads a
+// config with LoadFaults / load_faults / loadFaults, and the generated test
+// servers load the one named by the PULSERPC_FAULTS environment variable.
+// Injected errors skip the handler, and malformed responses are cut in half so
+// they are not valid JSON. [readonly] GET routes are served without faults.
+
+// faultsEnvVar names the fault config the generated test servers load
+const faultsEnvVar = "PULSERPC_FAULTS"
+
+// faultInjectionRequested reports whether the -generate-fault-injection flag is set
+func faultInjectionRequested(fs *flag.FlagSet) bool {
+	f := fs.Lookup("generate-fault-injection")
+	return f != nil && f.Value.String() == "true"
+}
@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+const faultsIDL = `namespace shop
+interface Catalog {
+  count() int
+}`
+
+func TestFaultInjectionGenerated(t *testing.T) {
+	idl, err := parser.ParseIDL("shop.pulse", faultsIDL)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+
+	tests := []struct {
+		plugin Plugin
+		want   map[string][]string
+	}{
+		{
+			plugin: NewGoClientServer(),
+			want: map[string][]string{
+				"server.go":               {"func (s *PulseRPCServer) LoadFaults(path string) error {", "return s.handleFaultyCall(ctx, buf, method, requestJson, requestBytes)"},
+				"cmd/test_server/main.go": {`if path := os.Getenv("PULSERPC_FAULTS"); path != "" {`},
+			},
+		},
+		{
+			plugin: NewPythonClientServer(),
+			want: map[string][]string{
+				"server.py":      {"from pulserpc import Composition, DEADLINE_HEADER, FaultConfig, LENIENT, RPCError, STRICT, canonical_json, check_int_literals, deadline_scope, normalize_ints, validate_type", "def load_faults(self, path: str) -> None:", "return self._handle_faulty_call("},
+				"test_server.py": {`server.load_faults(os.environ["PULSERPC_FAULTS"])`},
+			},
+		},
+		{
+			plugin: NewTSClientServer(),
+			want: map[string][]string{
+				"server.ts":      {"import { Fault, FaultConfig } from './pulserpc/faults';", "loadFaults(file: string): void {", "req.on('end', () => runWithDeadline(req.headers, () => this.withInjectedLatency(chunks, () => {"},
+				"test_server.ts": {"server.loadFaults(process.env.PULSERPC_FAULTS);"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		for _, enabled := range []bool{true, false} {
+			tmpDir := t.TempDir()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.String("dir", "", "output dir")
+			fs.Bool("generate-test-files", false, "generate test files")
+			fs.Bool("generate-fault-injection", false, "generate fault injection")
+			tt.plugin.RegisterFlags(fs)
+			if err := fs.Set("dir", tmpDir); err != nil {
+				t.Fatalf("failed to set dir flag: %v", err)
+			}
+			if err := fs.Set("generate-test-files", "true"); err != nil {
+				t.Fatalf("failed to set generate-test-files flag: %v", err)
+			}
+			if enabled {
+				if err := fs.Set("generate-fault-injection", "true"); err != nil {
+					t.Fatalf("failed to set generate-fault-injection flag: %v", err)
+				}
+			}
+			if err := tt.plugin.Generate(idl, fs); err != nil {
+				t.Fatalf("%s: Generate failed: %v", tt.plugin.Name(), err)
+			}
+			for file, wants := range tt.want {
+				content, err := os.ReadFile(filepath.Join(tmpDir, file))
+				if err != nil {
+					t.Fatalf("%s: expected %s: %v", tt.plugin.Name(), file, err)
+				}
+				for _, want := range wants {
+					if got := strings.Contains(string(content), want); got != enabled {
+						t.Errorf("%s: %s contains %q = %v, want %v", tt.plugin.Name(), file, want, got, enabled)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package generator
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The CLI accepts a flag only when the selected plugin reads it. A plugin reads the
+// flags it registers in RegisterFlags and the shared flags it lists in SharedFlags;
+// shared flags are defined once by the CLI, such as -dir or -generate-test-files.
+// A flag no plugin reads belongs to the CLI itself (-validate, -plugin, ...) and is
+// always accepted.
+
+// SharedFlagger is implemented by plugins that read flags defined by the CLI rather
+// than registered by the plugin
+type SharedFlagger interface {
+	SharedFlags() []string
+}
+
+// clientServerSharedFlags are the shared flags every client-server plugin reads
+var clientServerSharedFlags = []string{
+	"dir",
+	"generate-test-files",
+	"generate-test-vectors",
+	"generate-contract-tests",
+	"generate-shadow-client",
+	"generate-outbox-client",
+	"generate-patch-helpers",
+	"generate-repo-files",
+	"sbom",
+	"verify",
+	"offline",
+	"template-dir",
+}
+
+// SharedFlags returns the shared flags the Go plugin reads
+func (p *GoClientServer) SharedFlags() []string {
+	return append([]string{
+		"idl-json",
+		"generate-test-harness",
+		"generate-test-builders",
+		"generate-broker-transport",
+		"generate-serverless-adapter",
+		"generate-fault-injection",
+		"generate-admin-endpoint",
+		"generate-index-files",
+		"optional-presence",
+		"dependency-manifest",
+		"dependency-versions",
+		"dependency-mirror",
+	}, clientServerSharedFlags...)
+}
+
+// SharedFlags returns the shared flags the Python plugin reads
+func (p *PythonClientServer) SharedFlags() []string {
+	return append([]string{
+		"idl-json",
+		"generate-test-harness",
+		"generate-test-builders",
+		"generate-broker-transport",
+		"generate-serverless-adapter",
+		"generate-fault-injection",
+		"generate-admin-endpoint",
+		"generate-index-files",
+		"dependency-manifest",
+		"dependency-versions",
+		"dependency-mirror",
+		"style",
+	}, clientServerSharedFlags...)
+}
+
+// SharedFlags returns the shared flags the TypeScript plugin reads
+func (p *TSClientServer) SharedFlags() []string {
+	return append([]string{
+		"idl-json",
+		"generate-fault-injection",
+		"generate-admin-endpoint",
+		"style",
+	}, clientServerSharedFlags...)
+}
+
+// SharedFlags returns the shared flags the C# plugin reads
+func (p *CSharpClientServer) SharedFlags() []string {
+	return append([]string{
+		"generate-test-harness",
+		"generate-test-builders",
+		"generate-serverless-adapter",
+		"generate-index-files",
+		"optional-presence",
+		"dependency-manifest",
+		"dependency-versions",
+		"dependency-mirror",
+		"style",
+	}, clientServerSharedFlags...)
+}
+
+// SharedFlags returns the shared flags the Java plugin reads
+func (p *JavaClientServer) SharedFlags() []string {
+	return append([]string{
+		"idl-json",
+		"generate-test-harness",
+		"generate-test-builders",
+		"generate-index-files",
+		"dependency-manifest",
+		"dependency-versions",
+		"dependency-mirror",
+		"style",
+	}, clientServerSharedFlags...)
+}
+
+// SharedFlags returns the shared flags the Rust plugin reads. Cargo.toml is always
+// written, so -dependency-versions applies without -dependency-manifest.
+func (p *RustClientServer) SharedFlags() []string {
+	return []string{
+		"dir",
+		"generate-test-files",
+		"generate-test-vectors",
+		"dependency-versions",
+		"dependency-mirror",
+		"generate-repo-files",
+		"idl-json",
+		"sbom",
+		"verify",
+		"offline",
+	}
+}
+
+// SharedFlags returns the shared flags the protobuf plugin reads
+func (p *Protobuf) SharedFlags() []string {
+	return []string{"dir"}
+}
+
+// SharedFlags returns the shared flags the graphql plugin reads
+func (p *GraphQL) SharedFlags() []string {
+	return []string{"dir"}
+}
+
+// SharedFlags returns the shared flags the docs plugin reads
+func (p *Docs) SharedFlags() []string {
+	return []string{"dir"}
+}
+
+// SharedFlags returns the shared flags the js-browser-client plugin reads
+func (p *JSBrowserClient) SharedFlags() []string {
+	return []string{"dir", "generate-repo-files", "verify", "offline"}
+}
+
+// SharedFlags returns the shared flags the load-test plugin reads
+func (p *LoadTest) SharedFlags() []string {
+	return []string{"dir"}
+}
+
+// SharedFlags returns the shared flags the collection plugin reads
+func (p *Collection) SharedFlags() []string {
+	return []string{"dir"}
+}
+
+// SharedFlags returns the shared flags the examples plugin reads
+func (p *Examples) SharedFlags() []string {
+	return []string{"dir"}
+}
+
+// SharedFlags returns the shared flags the routes plugin reads
+func (p *Routes) SharedFlags() []string {
+	return []string{"dir"}
+}
+
+// SharedFlags returns the shared flags the json-schema plugin reads
+func (p *JSONSchema) SharedFlags() []string {
+	return []string{"dir"}
+}
+
+// PluginFlags returns the names of the flags p reads, sorted: the flags it
+// registers and the shared flags it lists if it implements SharedFlagger
+func PluginFlags(p Plugin) []string {
+	fs := flag.NewFlagSet(p.Name(), flag.ContinueOnError)
+	p.RegisterFlags(fs)
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) {
+		names = append(names, f.Name)
+	})
+	if s, ok := p.(SharedFlagger); ok {
+		for _, name := range s.SharedFlags() {
+			if fs.Lookup(name) == nil {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckFlags returns an error for the first flag set in fs that selected does not
+// read but another of plugins does, listing the flags selected supports. A nil
+// selected means no plugin was chosen, so no plugin flag may be set.
+func CheckFlags(fs *flag.FlagSet, selected Plugin, plugins []Plugin) error {
+	readers := make(map[string][]string)
+	for _, p := range plugins {
+		for _, name := range PluginFlags(p) {
+			readers[name] = append(readers[name], p.Name())
+		}
+	}
+	supported := make(map[string]bool)
+	if selected != nil {
+		for _, name := range PluginFlags(selected) {
+			supported[name] = true
+		}
+	}
+
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if err != nil || supported[f.Name] || len(readers[f.Name]) == 0 {
+			return
+		}
+		users := append([]string(nil), readers[f.Name]...)
+		sort.Strings(users)
+		if selected == nil {
+			err = fmt.Errorf("flag -%s requires -plugin (it is read by %s)", f.Name, strings.Join(users, ", "))
+			return
+		}
+		err = fmt.Errorf("flag -%s is not supported by plugin %q (it is read by %s)\nflags supported by %s: %s\nrun 'pulse help %s' for their descriptions",
+			f.Name, selected.Name(), strings.Join(users, ", "), selected.Name(), formatFlagNames(PluginFlags(selected)), selected.Name())
+	})
+	return err
+}
+
+// formatFlagNames returns names as a comma separated list of -name
+func formatFlagNames(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	flags := make([]string, len(names))
+	for i, name := range names {
+		flags[i] = "-" + name
+	}
+	return strings.Join(flags, ", ")
+}
@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestPluginFlags(t *testing.T) {
+	got := strings.Join(PluginFlags(NewRoutes()), ",")
+	if got != "dir,routes-default-timeout" {
+		t.Errorf("PluginFlags(routes) = %s, want dir,routes-default-timeout", got)
+	}
+	flags := PluginFlags(NewTSClientServer())
+	for _, want := range []string{"package", "base-dir", "dir", "generate-fault-injection", "style", "verify"} {
+		if !strings.Contains(","+strings.Join(flags, ",")+",", ","+want+",") {
+			t.Errorf("PluginFlags(ts-client-server) = %v, missing %s", flags, want)
+		}
+	}
+}
+
+func TestCheckFlags(t *testing.T) {
+	plugins := []Plugin{NewPythonClientServer(), NewJavaClientServer(), NewRoutes()}
+	tests := []struct {
+		name     string
+		selected Plugin
+		args     []string
+		wantErr  string
+	}{
+		{"own and shared flags", NewJavaClientServer(), []string{"-base-package", "com.example", "-dir", "out", "-generate-test-files"}, ""},
+		{"cli flags", NewRoutes(), []string{"-validate", "-dir", "out"}, ""},
+		{"flag of another plugin", NewPythonClientServer(), []string{"-base-package", "com.example"},
+			"flag -base-package is not supported by plugin \"python-client-server\" (it is read by java-client-server)"},
+		{"shared flag the plugin does not read", NewRoutes(), []string{"-generate-test-files"},
+			"flags supported by routes: -dir, -routes-default-timeout\nrun 'pulse help routes'"},
+		{"plugin flag without -plugin", nil, []string{"-dir", "out"}, "flag -dir requires -plugin"},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Bool("validate", false, "validate")
+		fs.String("dir", "", "output dir")
+		fs.Bool("generate-test-files", false, "generate test files")
+		for _, p := range plugins {
+			p.RegisterFlags(fs)
+		}
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatalf("%s: Parse failed: %v", tt.name, err)
+		}
+		err := CheckFlags(fs, tt.selected, plugins)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
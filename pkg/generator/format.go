@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Formatter rewrites a generated source file into its canonical layout.
+// It returns an error if the source is malformed.
+type Formatter func(filename string, src []byte) ([]byte, error)
+
+var (
+	formattersMu sync.RWMutex
+	formatters   = map[string]Formatter{
+		".go": formatGoSource,
+		".py": formatPythonSource,
+	}
+)
+
+// RegisterFormatter sets the formatter applied to generated files with the given
+// extension (e.g. ".ts"), replacing any existing one. A nil formatter disables
+// formatting for that extension.
+func RegisterFormatter(ext string, f Formatter) {
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+	if f == nil {
+		delete(formatters, ext)
+		return
+	}
+	formatters[ext] = f
+}
+
+func lookupFormatter(ext string) Formatter {
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+	return formatters[ext]
+}
+
+// writeGeneratedFile runs content through the formatter registered for the file's
+// extension and writes the result. Malformed output fails generation instead of
+// leaving a broken file behind.
+func writeGeneratedFile(path string, content []byte) error {
+	if f := lookupFormatter(filepath.Ext(path)); f != nil {
+		formatted, err := f(filepath.Base(path), content)
+		if err != nil {
+			return fmt.Errorf("generated code for %s is malformed (this is a generator bug): %w", path, err)
+		}
+		content = formatted
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// formatGoSource syntax checks src with go/parser and formats it with go/format
+func formatGoSource(filename string, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	if _, err := parser.ParseFile(fset, filename, src, parser.ParseComments|parser.AllErrors); err != nil {
+		return nil, describeGoSyntaxError(src, err)
+	}
+	return format.Source(src)
+}
+
+// describeGoSyntaxError adds the offending source line to the first parse error
+func describeGoSyntaxError(src []byte, err error) error {
+	var list scanner.ErrorList
+	if !errors.As(err, &list) || len(list) == 0 {
+		return err
+	}
+	lines := strings.Split(string(src), "\n")
+	line := list[0].Pos.Line
+	if line < 1 || line > len(lines) {
+		return err
+	}
+	return fmt.Errorf("%w\n\t%d | %s", err, line, lines[line-1])
+}
+
+// formatPythonSource applies the whitespace rules black enforces that can be
+// applied line by line: no trailing whitespace, no leading blank lines, at most
+// two consecutive blank lines, and exactly one newline at the end of the file.
+func formatPythonSource(filename string, src []byte) ([]byte, error) {
+	_ = filename
+	var out bytes.Buffer
+	blank := 0
+	for _, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			blank++
+			continue
+		}
+		if out.Len() > 0 {
+			if blank > 2 {
+				blank = 2
+			}
+			out.WriteString(strings.Repeat("\n", blank))
+		}
+		blank = 0
+		out.WriteString(line)
+		out.WriteString("\n")
+	}
+	return out.Bytes(), nil
+}
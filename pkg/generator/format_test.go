@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteGeneratedFileFormatsGo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "types.go")
+	src := "package generated\n\ntype Book struct {\nTitle string `json:\"title\"`\n    Pages int `json:\"pages\"`\n}\n"
+
+	if err := writeGeneratedFile(path, []byte(src)); err != nil {
+		t.Fatalf("writeGeneratedFile failed: %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	want := "package generated\n\ntype Book struct {\n\tTitle string `json:\"title\"`\n\tPages int    `json:\"pages\"`\n}\n"
+	if string(got) != want {
+		t.Errorf("expected gofmt output:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestWriteGeneratedFileRejectsMalformedGo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.go")
+	src := "package generated\n\nfunc handle() {\n\tif true {\n}\n\t}\n}\n"
+
+	err := writeGeneratedFile(path, []byte(src))
+	if err == nil {
+		t.Fatal("expected an error for malformed Go")
+	}
+	for _, want := range []string{"server.go:7:1", "7 | }", "generator bug"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got: %v", want, err)
+		}
+	}
+	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+		t.Errorf("expected no file to be written for malformed output")
+	}
+}
+
+func TestFormatPythonSource(t *testing.T) {
+	src := "\n\nimport json  \n\n\n\n\nclass A:\n    \"\"\"Doc.\n    \n    More.\n    \"\"\"\n    pass\n\n\n"
+	got, err := formatPythonSource("a.py", []byte(src))
+	if err != nil {
+		t.Fatalf("formatPythonSource failed: %v", err)
+	}
+	want := "import json\n\n\nclass A:\n    \"\"\"Doc.\n\n    More.\n    \"\"\"\n    pass\n"
+	if string(got) != want {
+		t.Errorf("expected:\n%q\ngot:\n%q", want, got)
+	}
+}
+
+func TestRegisterFormatter(t *testing.T) {
+	RegisterFormatter(".ts", func(filename string, src []byte) ([]byte, error) {
+		return []byte(strings.ToUpper(string(src))), nil
+	})
+	defer RegisterFormatter(".ts", nil)
+
+	path := filepath.Join(t.TempDir(), "client.ts")
+	if err := writeGeneratedFile(path, []byte("export {};\n")); err != nil {
+		t.Fatalf("writeGeneratedFile failed: %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	if string(got) != "EXPORT {};\n" {
+		t.Errorf("expected registered formatter to run, got %q", got)
+	}
+}
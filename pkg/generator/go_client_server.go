@@ -1,14 +1,15 @@
 package generator
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
+	"go/token"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/coopernurse/pulserpc/pkg/naming"
 	"github.com/coopernurse/pulserpc/pkg/parser"
 	"github.com/coopernurse/pulserpc/pkg/runtime"
 )
@@ -33,10 +34,31 @@ func (p *GoClientServer) RegisterFlags(fs *flag.FlagSet) {
 	if fs.Lookup("base-dir") == nil {
 		fs.String("base-dir", "", "Base directory for namespace packages/modules (defaults to -dir if not specified)")
 	}
+	// Register go-module flag for import paths between generated packages
+	fs.String("go-module", "", "Go module path of the generated code, used for import paths (e.g., github.com/acme/api)")
+	// Register go-packages flag for splitting namespaces into separate packages
+	fs.Bool("go-packages", false, "Generate each IDL namespace into its own Go package (requires -go-module)")
+	// Register go-package flag for naming the generated package
+	fs.String("go-package", "", "Go package name of the generated code (defaults to the root namespace, or the last element of -go-module with -go-packages)")
+	// Register go-split flag for splitting the root package into types, client and server
+	fs.Bool("go-split", false, "Generate the types, client and server into types/, client/ and server/ packages (requires -go-module, implies -go-packages)")
+	// Register go-mocks flag for generating mocks of the server interfaces
+	fs.String("go-mocks", "", "Also generate mocks.go with mocks of the server interfaces: 'gomock' (go.uber.org/mock) or 'testify'")
 }
 
+// defaultGoTestModule is the module path the generated test programs import the
+// generated package from when -go-module is not set
+const defaultGoTestModule = "pulserpc_test_go"
+
 // Generate generates Go HTTP server and client code from the parsed IDL
-func (p *GoClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
+func (p *GoClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) (err error) {
+	start := repoFilesStart()
+	ts, err := templatesFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	defer recoverTemplateError(&err)
+
 	// Access the -dir flag value
 	dirFlag := fs.Lookup("dir")
 	outputDir := ""
@@ -44,6 +66,11 @@ func (p *GoClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 		outputDir = dirFlag.Value.String()
 	}
 
+	// Types are emitted by base name, so names must be unique across namespaces
+	if err := CheckBaseNameCollisions(idl); err != nil {
+		return err
+	}
+
 	// Build type registries
 	structMap := make(map[string]*parser.Struct)
 	enumMap := make(map[string]*parser.Enum)
@@ -62,29 +89,111 @@ func (p *GoClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 	// Group types by namespace
 	namespaceMap := GroupTypesByNamespace(idl)
 
-	// Get the primary namespace for package name
-	primaryNs := ""
-
-	for ns := range namespaceMap {
-		if ns != "" {
-			primaryNs = ns
-			break
+	// Get the primary namespace for package name: the root file's namespace,
+	// falling back to the first namespace in sorted order
+	primaryNs := idl.RootNamespace
+	if primaryNs == "" {
+		if namespaces := sortedNamespaces(namespaceMap); len(namespaces) > 0 {
+			primaryNs = namespaces[0]
 		}
 	}
 	if primaryNs == "" {
 		primaryNs = "generated"
 	}
 
-	// Generate one file per namespace
-	allStructs := "var ALL_STRUCTS = StructMap{}\nvar ALL_ENUMS = EnumMap{}\n\n"
-	allStructsPath := filepath.Join(outputDir, "all_types.go")
-	allStructsContent := fmt.Sprintf("// Generated by pulserpc - do not edit\n\npackage %s\n\n%s", primaryNs, allStructs)
-	if err := os.WriteFile(allStructsPath, []byte(allStructsContent), 0644); err != nil {
+	// Get go-module flag (import path of the generated code)
+	goModule := ""
+	if goModuleFlag := fs.Lookup("go-module"); goModuleFlag != nil {
+		goModule = goModuleFlag.Value.String()
+	}
+
+	goMocks := ""
+	if goMocksFlag := fs.Lookup("go-mocks"); goMocksFlag != nil {
+		goMocks = goMocksFlag.Value.String()
+	}
+	if goMocks != "" && goMocks != "gomock" && goMocks != "testify" {
+		return fmt.Errorf("invalid go-mocks value: %s (must be 'gomock' or 'testify')", goMocks)
+	}
+
+	goPackage := ""
+	if goPackageFlag := fs.Lookup("go-package"); goPackageFlag != nil {
+		goPackage = goPackageFlag.Value.String()
+	}
+	if goPackage != "" && (!token.IsIdentifier(goPackage) || goPackage == "_") {
+		return fmt.Errorf("invalid go-package value: %s (must be a Go identifier)", goPackage)
+	}
+
+	// Split the root package into types, client and server packages if
+	// requested. They need the runtime in a package of its own, so this
+	// implies -go-packages.
+	goSplitFlag := fs.Lookup("go-split")
+	goSplit := goSplitFlag != nil && goSplitFlag.Value.String() == "true"
+	if goSplit {
+		if goModule == "" {
+			return fmt.Errorf("go-module flag is required when go-split is set")
+		}
+		if goPackage != "" {
+			return fmt.Errorf("go-package cannot be combined with go-split, whose packages are named %s, %s and %s", goTypesPackage, goClientPackage, goServerPackage)
+		}
+		for _, pkg := range []string{goTypesPackage, goClientPackage, goServerPackage} {
+			if _, exists := namespaceMap[pkg]; exists {
+				return fmt.Errorf("namespace %q conflicts with the Go %s package directory", pkg, pkg)
+			}
+		}
+	}
+
+	// Split namespaces into their own packages if requested. The root package
+	// keeps the server and client and re-exports the namespace types.
+	var layout *goPackageLayout
+	goPackagesFlag := fs.Lookup("go-packages")
+	if goSplit || (goPackagesFlag != nil && goPackagesFlag.Value.String() == "true") {
+		if goModule == "" {
+			return fmt.Errorf("go-module flag is required when go-packages is set")
+		}
+		if _, exists := namespaceMap[goRuntimePackage]; exists {
+			return fmt.Errorf("namespace %q conflicts with the Go runtime package directory", goRuntimePackage)
+		}
+		layout = newGoPackageLayout(goModule)
+		layout.split = goSplit
+		primaryNs = layout.rootPackage
+	}
+	if goPackage != "" {
+		primaryNs = goPackage
+	}
+
+	// Files of the root package go to the types, client and server packages
+	// when it is split; otherwise they all stay in outputDir
+	typesPackage, clientPackage, serverPackage := primaryNs, primaryNs, primaryNs
+	typesDir, clientDir, serverDir := outputDir, outputDir, outputDir
+	if goSplit {
+		typesPackage, clientPackage, serverPackage = goTypesPackage, goClientPackage, goServerPackage
+		typesDir = filepath.Join(outputDir, goTypesPackage)
+		clientDir = filepath.Join(outputDir, goClientPackage)
+		serverDir = filepath.Join(outputDir, goServerPackage)
+		for _, dir := range []string{typesDir, clientDir, serverDir} {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create package directory: %w", err)
+			}
+		}
+	}
+
+	// Generate all_types.go with the merged type registries
+	allStructsContent := generateAllTypesGo(typesPackage, namespaceMap, layout)
+	allStructsPath := filepath.Join(typesDir, "all_types.go")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := writeGeneratedFile(allStructsPath, []byte(allStructsContent)); err != nil {
 		return fmt.Errorf("failed to write all_types.go: %w", err)
 	}
 
-	// Copy runtime library files directly into outputDir
-	if err := p.copyRuntimeFiles(outputDir, primaryNs); err != nil {
+	// Copy runtime library files directly into outputDir, or into their own
+	// package when namespaces are split so every package can import them
+	if layout != nil {
+		if err := p.copyRuntimeFiles(filepath.Join(outputDir, goRuntimePackage), goRuntimePackage); err != nil {
+			return fmt.Errorf("failed to copy runtime files: %w", err)
+		}
+	} else if err := p.copyRuntimeFiles(outputDir, primaryNs); err != nil {
 		return fmt.Errorf("failed to copy runtime files: %w", err)
 	}
 
@@ -93,35 +202,152 @@ func (p *GoClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 		if namespace == "" {
 			continue // Skip types without namespace (shouldn't happen with required namespaces)
 		}
-		namespaceCode := generateNamespaceGo(namespace, primaryNs, types, structMap, enumMap)
 		namespacePath := filepath.Join(outputDir, namespace+".go")
-		if err := os.WriteFile(namespacePath, []byte(namespaceCode), 0644); err != nil {
+		packageName := primaryNs
+		if layout != nil {
+			packageName = layout.packageName(namespace)
+			namespacePath = filepath.Join(outputDir, packageName, namespace+".go")
+			if err := os.MkdirAll(filepath.Dir(namespacePath), 0755); err != nil {
+				return fmt.Errorf("failed to create package directory: %w", err)
+			}
+		}
+		namespaceCode := generateNamespaceGo(ts, namespace, packageName, types, structMap, enumMap, layout, optionalPresenceRequested(fs))
+		if err := writeGeneratedFile(namespacePath, []byte(namespaceCode)); err != nil {
 			return fmt.Errorf("failed to write %s.go: %w", namespace, err)
 		}
+
+		// Generate <namespace>_builders.go next to the namespace's types
+		if testBuildersRequested(fs) {
+			buildersCode := generateTestBuildersGo(namespace, packageName, types, structMap, enumMap, layout, optionalPresenceRequested(fs))
+			buildersPath := filepath.Join(filepath.Dir(namespacePath), namespace+"_builders.go")
+			if err := writeGeneratedFile(buildersPath, []byte(buildersCode)); err != nil {
+				return fmt.Errorf("failed to write %s_builders.go: %w", namespace, err)
+			}
+		}
+	}
+
+	idlDoc, err := newIDLJSONDocument(idl, fs)
+	if err != nil {
+		return err
 	}
 
 	// Generate server.go
-	serverCode := generateServerGo(idl, structMap, enumMap, primaryNs, namespaceMap)
-	serverPath := filepath.Join(outputDir, "server.go")
-	if err := os.WriteFile(serverPath, []byte(serverCode), 0644); err != nil {
+	serverCode := generateServerGo(ts, idl, structMap, enumMap, serverPackage, namespaceMap, layout, faultInjectionRequested(fs), adminEndpointRequested(fs), idlDoc)
+	serverPath := filepath.Join(serverDir, "server.go")
+	if err := writeGeneratedFile(serverPath, []byte(serverCode)); err != nil {
 		return fmt.Errorf("failed to write server.go: %w", err)
 	}
 
+	// Generate mocks.go next to the interfaces in server.go
+	if goMocks != "" {
+		mocksCode := generateMocksGo(ts, idl, structMap, enumMap, serverPackage, goMocks, layout)
+		if err := writeGeneratedFile(filepath.Join(serverDir, "mocks.go"), []byte(mocksCode)); err != nil {
+			return fmt.Errorf("failed to write mocks.go: %w", err)
+		}
+	}
+
+	// Generate doc.go, the package comment indexing the types of every namespace
+	if indexFilesRequested(fs) {
+		docCode := generateDocGo(typesPackage, buildNamespaceIndex(namespaceMap))
+		if err := writeGeneratedFile(filepath.Join(typesDir, "doc.go"), []byte(docCode)); err != nil {
+			return fmt.Errorf("failed to write doc.go: %w", err)
+		}
+	}
+
+	// Generate methods.go, shared by the client and the server, or one copy
+	// in each of their packages when they are split
+	methodsCode := generateMethodTableGo(clientPackage, idl.Interfaces)
+	if err := writeGeneratedFile(filepath.Join(clientDir, "methods.go"), []byte(methodsCode)); err != nil {
+		return fmt.Errorf("failed to write methods.go: %w", err)
+	}
+	if goSplit {
+		methodsCode := generateMethodTableGo(serverPackage, idl.Interfaces)
+		if err := writeGeneratedFile(filepath.Join(serverDir, "methods.go"), []byte(methodsCode)); err != nil {
+			return fmt.Errorf("failed to write methods.go: %w", err)
+		}
+	}
+
 	// Generate client.go
-	clientCode := generateClientGo(idl, structMap, enumMap, primaryNs, namespaceMap)
-	clientPath := filepath.Join(outputDir, "client.go")
-	if err := os.WriteFile(clientPath, []byte(clientCode), 0644); err != nil {
+	clientCode := generateClientGo(ts, idl, structMap, enumMap, clientPackage, namespaceMap, layout)
+	clientPath := filepath.Join(clientDir, "client.go")
+	if err := writeGeneratedFile(clientPath, []byte(clientCode)); err != nil {
 		return fmt.Errorf("failed to write client.go: %w", err)
 	}
 
-	// Write IDL JSON document for pulserpc-idl RPC method
-	jsonData, err := json.MarshalIndent(idl, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal IDL to JSON: %w", err)
+	// Files next to the client dot-import the runtime when it is its own package
+	runtimeImport := ""
+	if layout != nil {
+		runtimeImport = layout.importPath(goRuntimePackage)
+	}
+
+	// Generate discovery.go next to the client
+	discoveryCode := ts.renderString("go/discovery.go.tmpl", discoveryView{Package: clientPackage, RuntimeImport: runtimeImport, TypesImport: layout.typesImport()})
+	if err := writeGeneratedFile(filepath.Join(clientDir, "discovery.go"), []byte(discoveryCode)); err != nil {
+		return fmt.Errorf("failed to write discovery.go: %w", err)
+	}
+
+	// Generate signing.go, shared by the client and the server
+	signingCode := ts.renderString("go/signing.go.tmpl", signingView{Package: typesPackage})
+	if err := writeGeneratedFile(filepath.Join(typesDir, "signing.go"), []byte(signingCode)); err != nil {
+		return fmt.Errorf("failed to write signing.go: %w", err)
+	}
+
+	// Generate retry.go next to the client
+	retryCode := ts.renderString("go/retry.go.tmpl", retryView{Package: clientPackage, Methods: idempotentMethods(idl)})
+	if err := writeGeneratedFile(filepath.Join(clientDir, "retry.go"), []byte(retryCode)); err != nil {
+		return fmt.Errorf("failed to write retry.go: %w", err)
+	}
+
+	// Generate shadow.go next to the client
+	if shadowClientRequested(fs) {
+		shadowCode := ts.renderString("go/shadow.go.tmpl", shadowView{Package: clientPackage, RuntimeImport: runtimeImport})
+		if err := writeGeneratedFile(filepath.Join(clientDir, "shadow.go"), []byte(shadowCode)); err != nil {
+			return fmt.Errorf("failed to write shadow.go: %w", err)
+		}
+	}
+
+	// Generate outbox.go next to the client
+	if outboxClientRequested(fs) {
+		outboxCode := ts.renderString("go/outbox.go.tmpl", outboxView{Package: clientPackage})
+		if err := writeGeneratedFile(filepath.Join(clientDir, "outbox.go"), []byte(outboxCode)); err != nil {
+			return fmt.Errorf("failed to write outbox.go: %w", err)
+		}
+	}
+
+	// Generate broker.go next to the client
+	if brokerTransportRequested(fs) {
+		brokerCode := ts.renderString("go/broker.go.tmpl", brokerView{Package: clientPackage, RuntimeImport: runtimeImport})
+		if err := writeGeneratedFile(filepath.Join(clientDir, "broker.go"), []byte(brokerCode)); err != nil {
+			return fmt.Errorf("failed to write broker.go: %w", err)
+		}
+	}
+
+	// Generate patch.go, shared by the client and the server
+	if patchHelpersRequested(fs) {
+		patchCode := ts.renderString("go/patch.go.tmpl", patchView{Package: typesPackage, RuntimeImport: runtimeImport})
+		if err := writeGeneratedFile(filepath.Join(typesDir, "patch.go"), []byte(patchCode)); err != nil {
+			return fmt.Errorf("failed to write patch.go: %w", err)
+		}
+	}
+
+	// Generate serverless.go next to the server
+	if serverlessAdapterRequested(fs) {
+		serverlessCode := ts.renderString("go/serverless.go.tmpl", serverlessView{Package: serverPackage})
+		if err := writeGeneratedFile(filepath.Join(serverDir, "serverless.go"), []byte(serverlessCode)); err != nil {
+			return fmt.Errorf("failed to write serverless.go: %w", err)
+		}
+	}
+
+	// Write IDL JSON document for pulserpc-idl RPC method next to server.go,
+	// which embeds it
+	if err := idlDoc.Write(serverDir); err != nil {
+		return err
 	}
-	jsonPath := filepath.Join(outputDir, "idl.json")
-	if err := os.WriteFile(jsonPath, jsonData, 0644); err != nil {
-		return fmt.Errorf("failed to write idl.json: %w", err)
+
+	// Write testvectors.json if -generate-test-vectors is set
+	hasTestVectors, err := writeTestVectorsIfRequested(idl, fs, outputDir)
+	if err != nil {
+		return err
 	}
 
 	// Check if generate-test-files flag is set
@@ -131,31 +357,414 @@ func (p *GoClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 	// Generate test server and client if flag is set
 	if generateTestServer {
 		// Generate cmd/test_server/main.go
-		testServerCode := generateTestServerGo(idl, structMap, enumMap)
+		testImportPath := defaultGoTestModule
+		if goModule != "" {
+			testImportPath = goModule
+		}
+		serverImports, clientImports := []string{testImportPath}, []string{testImportPath}
+		if goSplit {
+			serverImports = []string{layout.importPath(goServerPackage)}
+			clientImports = []string{layout.importPath(goClientPackage)}
+			if goMethodsUseTypes(idl.Interfaces) {
+				serverImports = append(serverImports, layout.typesImport())
+				clientImports = append(clientImports, layout.typesImport())
+			}
+		}
+		testServerCode := generateTestServerGo(idl, structMap, enumMap, serverImports, faultInjectionRequested(fs), adminEndpointRequested(fs))
 		testServerDir := filepath.Join(outputDir, "cmd", "test_server")
 		if err := os.MkdirAll(testServerDir, 0755); err != nil {
 			return fmt.Errorf("failed to create test_server directory: %w", err)
 		}
 		testServerPath := filepath.Join(testServerDir, "main.go")
-		if err := os.WriteFile(testServerPath, []byte(testServerCode), 0644); err != nil {
+		if err := writeGeneratedFile(testServerPath, []byte(testServerCode)); err != nil {
 			return fmt.Errorf("failed to write test_server/main.go: %w", err)
 		}
 
 		// Generate cmd/test_client/main.go
-		testClientCode := generateTestClientGo(idl, structMap, enumMap)
+		testClientCode := generateTestClientGo(idl, structMap, enumMap, clientImports, hasTestVectors, optionalPresenceRequested(fs))
 		testClientDir := filepath.Join(outputDir, "cmd", "test_client")
 		if err := os.MkdirAll(testClientDir, 0755); err != nil {
 			return fmt.Errorf("failed to create test_client directory: %w", err)
 		}
 		testClientPath := filepath.Join(testClientDir, "main.go")
-		if err := os.WriteFile(testClientPath, []byte(testClientCode), 0644); err != nil {
+		if err := writeGeneratedFile(testClientPath, []byte(testClientCode)); err != nil {
 			return fmt.Errorf("failed to write test_client/main.go: %w", err)
 		}
 	}
 
+	// Generate the handler test harness in an external test package of the
+	// package holding the server
+	if testHarnessRequested(fs) {
+		importPath := defaultGoTestModule
+		if goModule != "" {
+			importPath = goModule
+		}
+		if goSplit {
+			importPath = layout.importPath(goServerPackage)
+		}
+		view := goHarnessView{
+			Package:    serverPackage,
+			ImportPath: importPath,
+			Interfaces: buildHarnessInterfaces(idl, naming.SnakeToPascal),
+		}
+		harnessCode := ts.renderString("go/harness_test.go.tmpl", view)
+		if err := writeGeneratedFile(filepath.Join(serverDir, "harness_test.go"), []byte(harnessCode)); err != nil {
+			return fmt.Errorf("failed to write harness_test.go: %w", err)
+		}
+		handlersCode := ts.renderString("go/handlers_test.go.tmpl", view)
+		if err := writeSkeletonFile(filepath.Join(serverDir, "handlers_test.go"), []byte(handlersCode)); err != nil {
+			return fmt.Errorf("failed to write handlers_test.go: %w", err)
+		}
+	}
+
+	// Generate contract tests from the IDL examples next to the client
+	if view := buildContractView(idl, naming.SnakeToPascal); contractTestsRequested(fs) && len(view.Interfaces) > 0 {
+		importPath := defaultGoTestModule
+		if goModule != "" {
+			importPath = goModule
+		}
+		if goSplit {
+			importPath = layout.importPath(goClientPackage)
+		}
+		contractCode := ts.renderString("go/contract_test.go.tmpl", goContractView{
+			Package:      clientPackage,
+			ImportPath:   importPath,
+			contractView: view,
+		})
+		if err := writeGeneratedFile(filepath.Join(clientDir, "contract_test.go"), []byte(contractCode)); err != nil {
+			return fmt.Errorf("failed to write contract_test.go: %w", err)
+		}
+	}
+
+	if dependencyManifestRequested(fs) || sbomRequested(fs) {
+		versions, err := dependencyVersions(fs)
+		if err != nil {
+			return err
+		}
+		mirror, err := dependencyMirrorFlag(fs)
+		if err != nil {
+			return err
+		}
+		deps := goDependencies(versions, goMocks)
+		if dependencyManifestRequested(fs) {
+			if err := writeGoDependencyManifest(outputDir, deps, mirror); err != nil {
+				return err
+			}
+		}
+		if sbomRequested(fs) {
+			runtimeDir := ""
+			if layout != nil {
+				runtimeDir = goRuntimePackage
+			}
+			if err := writeSBOM(outputDir, idl, "go", runtimeDir, deps); err != nil {
+				return err
+			}
+		}
+	}
+
+	if repoFilesRequested(fs) {
+		return writeRepoFiles("go", defaultCodeStyles["go"], start, outputDir)
+	}
 	return nil
 }
 
+// goHarnessView is the view model for harness_test.go and handlers_test.go
+type goHarnessView struct {
+	Package    string
+	ImportPath string
+	Interfaces []harnessInterface
+}
+
+// goContractView is the view model for contract_test.go
+type goContractView struct {
+	Package    string
+	ImportPath string
+	contractView
+}
+
+// goRuntimePackage is the package (and directory) name of the runtime library
+// when namespaces are generated into separate packages
+const goRuntimePackage = "pulserpc"
+
+// Package (and directory) names of the generated code when -go-split divides the
+// root package into the types, the client and the server
+const (
+	goTypesPackage  = "types"
+	goClientPackage = "client"
+	goServerPackage = "server"
+)
+
+// goPackageLayout describes generated Go code that is split into one package per
+// namespace under a module path. A nil layout means every namespace shares a
+// single flat package, and all of its methods are safe to call on nil.
+type goPackageLayout struct {
+	modulePath  string
+	rootPackage string
+	// split puts the root package's files into types, client and server
+	// packages, the latter two dot-importing the types
+	split bool
+}
+
+// newGoPackageLayout creates a layout for the given module path. The root
+// package name is derived from the last element of the module path.
+func newGoPackageLayout(modulePath string) *goPackageLayout {
+	modulePath = strings.TrimSuffix(modulePath, "/")
+	root := modulePath
+	if idx := strings.LastIndex(root, "/"); idx >= 0 {
+		root = root[idx+1:]
+	}
+	return &goPackageLayout{
+		modulePath:  modulePath,
+		rootPackage: toGoPackageName(root),
+	}
+}
+
+// toGoPackageName converts an arbitrary name into a valid lowercase Go package name
+func toGoPackageName(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	pkg := sb.String()
+	if pkg == "" || (pkg[0] >= '0' && pkg[0] <= '9') {
+		pkg = "pkg" + pkg
+	}
+	return pkg
+}
+
+// packageName returns the Go package (and directory) name for a namespace
+func (l *goPackageLayout) packageName(namespace string) string {
+	return toGoPackageName(namespace)
+}
+
+// importPath returns the import path of a package directory under the module
+func (l *goPackageLayout) importPath(dir string) string {
+	return l.modulePath + "/" + dir
+}
+
+// registryPrefix returns the package qualifier for a namespace's type registries
+func (l *goPackageLayout) registryPrefix(namespace string) string {
+	if l == nil {
+		return ""
+	}
+	return l.packageName(namespace) + "."
+}
+
+// typesImport returns the import path of the types package when the code is
+// split, or "" when the types share a package with the client and server
+func (l *goPackageLayout) typesImport() string {
+	if l == nil || !l.split {
+		return ""
+	}
+	return l.importPath(goTypesPackage)
+}
+
+// goMethodsUseTypes reports whether any method param or return type names a
+// struct, enum or typedef, so that code holding only method signatures, like the
+// mocks, uses the split types package it would otherwise import in vain
+func goMethodsUseTypes(interfaces []*parser.Interface) bool {
+	var usesTypes func(t *parser.Type) bool
+	usesTypes = func(t *parser.Type) bool {
+		switch {
+		case t == nil:
+			return false
+		case t.IsUserDefined() || t.Alias != "":
+			return true
+		case t.IsArray():
+			return usesTypes(t.Array)
+		case t.IsMap():
+			return usesTypes(t.MapValue)
+		}
+		return false
+	}
+	for _, iface := range interfaces {
+		for _, method := range iface.Methods {
+			if usesTypes(method.ReturnType) {
+				return true
+			}
+			for _, param := range method.Parameters {
+				if usesTypes(param.Type) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// imports returns the runtime and namespace package imports used by root package
+// files (server.go, client.go)
+func (l *goPackageLayout) imports(namespaceMap map[string]*NamespaceTypes) []string {
+	if l == nil {
+		return nil
+	}
+	imports := []string{fmt.Sprintf(". %q", l.importPath(goRuntimePackage))}
+	if l.split {
+		imports = append(imports, fmt.Sprintf(". %q", l.typesImport()))
+	}
+	for _, ns := range sortedNamespaces(namespaceMap) {
+		imports = append(imports, fmt.Sprintf("%q", l.importPath(l.packageName(ns))))
+	}
+	return imports
+}
+
+// qualifier returns a function naming user-defined types as seen from the package
+// of currentNamespace, prefixing types from other namespaces with their package
+func (l *goPackageLayout) qualifier(currentNamespace string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) func(string) string {
+	return func(typeName string) string {
+		baseName := getGoStructOrEnumTypeName(typeName, structMap, enumMap)
+		ns := goTypeNamespace(typeName, structMap, enumMap)
+		if l == nil || ns == "" || ns == currentNamespace {
+			return baseName
+		}
+		return l.packageName(ns) + "." + baseName
+	}
+}
+
+// goTypeNamespace returns the namespace that defines a user-defined type
+func goTypeNamespace(typeName string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) string {
+	if s, ok := structMap[typeName]; ok {
+		return GetNamespaceFromType(s.Name, s.Namespace)
+	}
+	if e, ok := enumMap[typeName]; ok {
+		return GetNamespaceFromType(e.Name, e.Namespace)
+	}
+	return GetNamespaceFromType(typeName, "")
+}
+
+// referencedNamespacesGo returns the sorted namespaces, other than namespace,
+// whose types are referenced by the given structs and typedefs
+func referencedNamespacesGo(namespace string, structs []*parser.Struct, typedefs []*parser.Typedef, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) []string {
+	seen := make(map[string]bool)
+	add := func(typeName string) {
+		if ns := goTypeNamespace(typeName, structMap, enumMap); ns != "" && ns != namespace {
+			seen[ns] = true
+		}
+	}
+	var walk func(t *parser.Type)
+	walk = func(t *parser.Type) {
+		switch {
+		case t == nil:
+		case t.Alias != "":
+			add(t.Alias)
+		case t.IsArray():
+			walk(t.Array)
+		case t.IsMap():
+			walk(t.MapValue)
+		case t.IsUserDefined():
+			add(t.UserDefined)
+		}
+	}
+	for _, s := range structs {
+		if s.Extends != "" {
+			add(s.Extends)
+		}
+		for _, field := range s.Fields {
+			walk(field.Type)
+		}
+	}
+	for _, td := range typedefs {
+		walk(td.Type.Array)
+		walk(td.Type.MapValue)
+	}
+	namespaces := make([]string, 0, len(seen))
+	for ns := range seen {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// sortedNamespaces returns the non-empty namespaces of namespaceMap in sorted order
+func sortedNamespaces(namespaceMap map[string]*NamespaceTypes) []string {
+	namespaces := make([]string, 0, len(namespaceMap))
+	for ns := range namespaceMap {
+		if ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// generateAllTypesGo generates all_types.go holding the merged type registries.
+// With a package layout it also re-exports every namespace type from the root
+// package so server, client, and handler code can refer to them unqualified.
+func generateAllTypesGo(packageName string, namespaceMap map[string]*NamespaceTypes, layout *goPackageLayout) string {
+	var sb strings.Builder
+
+	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
+	fmt.Fprintf(&sb, "package %s\n\n", packageName)
+
+	if layout == nil {
+		sb.WriteString("var ALL_STRUCTS = StructMap{}\nvar ALL_ENUMS = EnumMap{}\n\n")
+		return sb.String()
+	}
+
+	namespaces := sortedNamespaces(namespaceMap)
+	sb.WriteString("import (\n")
+	fmt.Fprintf(&sb, "	. \"%s\"\n", layout.importPath(goRuntimePackage))
+	for _, ns := range namespaces {
+		if len(namespaceMap[ns].Structs) > 0 || len(namespaceMap[ns].Enums) > 0 || len(namespaceMap[ns].Typedefs) > 0 {
+			fmt.Fprintf(&sb, "	\"%s\"\n", layout.importPath(layout.packageName(ns)))
+		}
+	}
+	sb.WriteString(")\n\n")
+	sb.WriteString("var ALL_STRUCTS = StructMap{}\nvar ALL_ENUMS = EnumMap{}\n\n")
+
+	for _, ns := range namespaces {
+		types := namespaceMap[ns]
+		if len(types.Structs) == 0 && len(types.Enums) == 0 && len(types.Typedefs) == 0 {
+			continue
+		}
+		pkg := layout.packageName(ns)
+		fmt.Fprintf(&sb, "// Types re-exported from package %s\n", pkg)
+		sb.WriteString("type (\n")
+		for _, e := range types.Enums {
+			enumName := GetBaseName(e.Name)
+			fmt.Fprintf(&sb, "	%s = %s.%s\n", enumName, pkg, enumName)
+		}
+		for _, s := range types.Structs {
+			structName := GetBaseName(s.Name)
+			fmt.Fprintf(&sb, "	%s = %s.%s\n", structName, pkg, structName)
+		}
+		for _, td := range types.Typedefs {
+			typedefName := GetBaseName(td.Name)
+			fmt.Fprintf(&sb, "	%s = %s.%s\n", typedefName, pkg, typedefName)
+		}
+		sb.WriteString(")\n\n")
+
+		if usesImmutableStructs(types.Structs) {
+			sb.WriteString("var (\n")
+			for _, s := range types.Structs {
+				if s.IsImmutable() {
+					constructor := "New" + GetBaseName(s.Name)
+					fmt.Fprintf(&sb, "	%s = %s.%s\n", constructor, pkg, constructor)
+				}
+			}
+			sb.WriteString(")\n\n")
+		}
+
+		if len(types.Enums) > 0 {
+			sb.WriteString("const (\n")
+			for _, e := range types.Enums {
+				enumName := GetBaseName(e.Name)
+				for _, val := range e.Values {
+					constName := enumName + naming.SnakeToPascal(val.Name)
+					fmt.Fprintf(&sb, "	%s = %s.%s\n", constName, pkg, constName)
+				}
+			}
+			sb.WriteString(")\n\n")
+		}
+	}
+
+	return sb.String()
+}
+
 // copyRuntimeFiles copies the Go runtime library files to the output directory
 // Uses embedded runtime files from the binary
 func (p *GoClientServer) copyRuntimeFiles(outputDir string, packageName string) error {
@@ -182,21 +791,14 @@ func (p *GoClientServer) copyRuntimeFiles(outputDir string, packageName string)
 	return nil
 }
 
-// snakeToCamelCase converts snake_case to CamelCase
-// Example: "to_repeat" -> "ToRepeat"
-func snakeToCamelCase(s string) string {
-	parts := strings.Split(s, "_")
-	result := ""
-	for _, part := range parts {
-		if len(part) > 0 {
-			result += strings.ToUpper(part[:1]) + part[1:]
-		}
-	}
-	return result
-}
-
 // mapTypeToGoType maps an IDL type to a Go type string
 func mapTypeToGoType(t *parser.Type, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, optional bool) string {
+	return mapTypeToQualifiedGoType(t, structMap, enumMap, optional, nil)
+}
+
+// mapTypeToQualifiedGoType maps an IDL type to a Go type string, using qualify to
+// name user-defined types. A nil qualify uses the unqualified base name.
+func mapTypeToQualifiedGoType(t *parser.Type, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, optional bool, qualify func(string) string) string {
 	if t.IsBuiltIn() {
 		var goType string
 		switch t.BuiltIn {
@@ -215,14 +817,35 @@ func mapTypeToGoType(t *parser.Type, structMap map[string]*parser.Struct, enumMa
 			return "*" + goType
 		}
 		return goType
+	} else if t.Alias != "" {
+		// Typedefs are generated as defined types named after the typedef
+		aliasName := GetBaseName(t.Alias)
+		if qualify != nil {
+			aliasName = qualify(t.Alias)
+		}
+		if optional {
+			return "*" + aliasName
+		}
+		return aliasName
 	} else if t.IsArray() {
-		elementType := mapTypeToGoType(t.Array, structMap, enumMap, false)
+		// Optional arrays and maps are pointers too, so that an empty one is sent
+		// as [] or {} rather than dropped by omitempty like an absent one
+		elementType := mapTypeToQualifiedGoType(t.Array, structMap, enumMap, false, qualify)
+		if optional {
+			return "*[]" + elementType
+		}
 		return "[]" + elementType
 	} else if t.IsMap() {
-		valueType := mapTypeToGoType(t.MapValue, structMap, enumMap, false)
+		valueType := mapTypeToQualifiedGoType(t.MapValue, structMap, enumMap, false, qualify)
+		if optional {
+			return "*map[string]" + valueType
+		}
 		return "map[string]" + valueType
 	} else if t.IsUserDefined() {
 		typeName := getGoStructOrEnumTypeName(t.UserDefined, structMap, enumMap)
+		if qualify != nil {
+			typeName = qualify(t.UserDefined)
+		}
 		if optional {
 			return "*" + typeName
 		}
@@ -272,19 +895,42 @@ func writeTypeDictGo(sb *strings.Builder, t *parser.Type) {
 	sb.WriteString("}")
 }
 
-// generateNamespaceGo generates a Go file for a single namespace
-func generateNamespaceGo(namespace string, primaryNs string, types *NamespaceTypes, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) string {
+// generateNamespaceGo generates a Go file for a single namespace.
+// When layout is non-nil the file is its own package and references to types in
+// other namespaces are qualified with their package name.
+func generateNamespaceGo(ts *templateSet, namespace string, packageName string, types *NamespaceTypes, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, layout *goPackageLayout, presence bool) string {
 	var sb strings.Builder
 
 	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
-	sb.WriteString(fmt.Sprintf("package %s\n\n", primaryNs))
+	sb.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+
+	// [immutable] structs encode their unexported fields with encoding/json
+	immutable := usesImmutableStructs(types.Structs)
+	var qualify func(string) string
+	if layout != nil {
+		qualify = layout.qualifier(namespace, structMap, enumMap)
+		sb.WriteString("import (\n")
+		if immutable {
+			sb.WriteString("	\"encoding/json\"\n\n")
+		}
+		fmt.Fprintf(&sb, "	. \"%s\"\n", layout.importPath(goRuntimePackage))
+		for _, ns := range referencedNamespacesGo(namespace, types.Structs, types.Typedefs, structMap, enumMap) {
+			fmt.Fprintf(&sb, "	\"%s\"\n", layout.importPath(layout.packageName(ns)))
+		}
+		sb.WriteString(")\n\n")
+	} else if immutable {
+		sb.WriteString("import \"encoding/json\"\n\n")
+	}
 
 	// Generate enum types first (they may be referenced by structs)
-	generateEnumTypesGo(&sb, types.Enums)
+	generateEnumTypesGo(ts, &sb, types.Enums)
 	sb.WriteString("\n")
 
+	// Generate typedefs as defined types
+	generateTypedefTypesGo(&sb, types.Typedefs, structMap, enumMap, qualify)
+
 	// Generate struct types
-	generateStructTypesGo(&sb, types.Structs, structMap, enumMap)
+	generateStructTypesGo(&sb, types.Structs, structMap, enumMap, qualify, presence)
 	sb.WriteString("\n")
 
 	// Generate IDL-specific type definitions for this namespace
@@ -306,6 +952,12 @@ func generateNamespaceGo(namespace string, primaryNs string, types *NamespaceTyp
 			if field.Optional {
 				sb.WriteString("				\"optional\": true,\n")
 			}
+			if field.IsSensitive() {
+				sb.WriteString("				\"sensitive\": true,\n")
+			}
+			if field.IsEncrypted() {
+				sb.WriteString("				\"encrypted\": true,\n")
+			}
 			sb.WriteString("			},\n")
 		}
 		sb.WriteString("		},\n")
@@ -315,846 +967,480 @@ func generateNamespaceGo(namespace string, primaryNs string, types *NamespaceTyp
 
 	sb.WriteString(fmt.Sprintf("var %s_ALL_ENUMS = EnumMap{\n", nsUpper))
 	for _, e := range types.Enums {
-		sb.WriteString(fmt.Sprintf("	\"%s\": EnumDef{\n", e.Name))
-		sb.WriteString("		\"values\": []interface{}{\n")
-		for _, val := range e.Values {
-			sb.WriteString("			map[string]interface{}{\n")
-			sb.WriteString(fmt.Sprintf("				\"name\": \"%s\",\n", val.Name))
-			sb.WriteString("			},\n")
-		}
-		sb.WriteString("		},\n")
-		sb.WriteString("	},\n")
-	}
-	sb.WriteString("}\n")
-
-	return sb.String()
-}
-
-// generateEnumTypesGo generates Go enum types for all enums in the namespace
-func generateEnumTypesGo(sb *strings.Builder, enums []*parser.Enum) {
-	for _, e := range enums {
-		if e.Comment != "" {
-			lines := strings.Split(strings.TrimSpace(e.Comment), "\n")
-			for _, line := range lines {
-				fmt.Fprintf(sb, "// %s\n", line)
-			}
-		}
-		enumName := GetBaseName(e.Name)
-		fmt.Fprintf(sb, "type %s string\n\n", enumName)
-		fmt.Fprintf(sb, "const (\n")
-		for i, val := range e.Values {
-			if i == 0 {
-				fmt.Fprintf(sb, "	%s%s %s = \"%s\"\n", enumName, snakeToCamelCase(val.Name), enumName, val.Name)
-			} else {
-				fmt.Fprintf(sb, "	%s%s %s = \"%s\"\n", enumName, snakeToCamelCase(val.Name), enumName, val.Name)
-			}
-		}
-		sb.WriteString(")\n\n")
-	}
-}
-
-// generateStructTypesGo generates Go struct types for all structs in the namespace
-func generateStructTypesGo(sb *strings.Builder, structs []*parser.Struct, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) {
-	for _, s := range structs {
-		if s.Comment != "" {
-			lines := strings.Split(strings.TrimSpace(s.Comment), "\n")
-			for _, line := range lines {
-				fmt.Fprintf(sb, "// %s\n", line)
-			}
-		}
-
-		structName := GetBaseName(s.Name)
-		fmt.Fprintf(sb, "type %s struct {\n", structName)
-
-		// Handle inheritance via embedding
-		if s.Extends != "" {
-			parentName := getGoStructOrEnumTypeName(s.Extends, structMap, enumMap)
-			fmt.Fprintf(sb, "	%s\n", parentName)
-		}
-
-		// Generate fields
-		for _, field := range s.Fields {
-			if field.Comment != "" {
-				lines := strings.Split(strings.TrimSpace(field.Comment), "\n")
-				for _, line := range lines {
-					fmt.Fprintf(sb, "	// %s\n", line)
-				}
-			}
-
-			// JSON tag (IDL uses snake_case, Go uses CamelCase)
-			fieldName := snakeToCamelCase(field.Name)
-			goType := mapTypeToGoType(field.Type, structMap, enumMap, field.Optional)
-			jsonTag := field.Name
-			if field.Optional {
-				jsonTag += ",omitempty"
-			}
-			fmt.Fprintf(sb, "	%s %s `json:\"%s\"`\n", fieldName, goType, jsonTag)
-		}
-
-		sb.WriteString("}\n\n")
-	}
-}
-
-// generateServerGo generates the server.go file with HTTP server and interface stubs
-func generateServerGo(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, primaryNs string, namespaceMap map[string]*NamespaceTypes) string {
-	var sb strings.Builder
-
-	sb.WriteString("//go:build !client_only\n")
-	sb.WriteString("// +build !client_only\n\n")
-	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
-	sb.WriteString(fmt.Sprintf("package %s\n\n", primaryNs))
-	sb.WriteString("import (\n")
-	sb.WriteString("	\"encoding/json\"\n")
-	sb.WriteString("	\"fmt\"\n")
-	sb.WriteString("	\"io\"\n")
-	sb.WriteString("	\"net/http\"\n")
-	sb.WriteString("	\"os\"\n")
-	sb.WriteString("	\"path/filepath\"\n")
-	sb.WriteString("	\"reflect\"\n")
-	sb.WriteString("	\"strings\"\n")
-	sb.WriteString(")\n\n")
-
-	// Import from namespace files
-	namespaces := make([]string, 0, len(namespaceMap))
-	for ns := range namespaceMap {
-		if ns != "" {
-			namespaces = append(namespaces, ns)
-		}
-	}
-	sort.Strings(namespaces)
-
-	// Merge ALL_STRUCTS and ALL_ENUMS from all namespaces
-	sb.WriteString("// Merge ALL_STRUCTS and ALL_ENUMS from all namespaces\n")
-	sb.WriteString("func init() {\n")
-	for _, ns := range namespaces {
-		nsUpper := strings.ToUpper(strings.ReplaceAll(ns, ".", "_"))
-		sb.WriteString(fmt.Sprintf("	for k, v := range %s_ALL_STRUCTS {\n", nsUpper))
-		sb.WriteString("		ALL_STRUCTS[k] = v\n")
-		sb.WriteString("	}\n")
-		sb.WriteString(fmt.Sprintf("	for k, v := range %s_ALL_ENUMS {\n", nsUpper))
-		sb.WriteString("		ALL_ENUMS[k] = v\n")
-		sb.WriteString("	}\n")
-	}
-	sb.WriteString("}\n\n")
-
-	// Generate interface stubs
-	for _, iface := range idl.Interfaces {
-		writeInterfaceStubGo(&sb, iface, structMap, enumMap)
-	}
-
-	// Generate PulseRPCServer
-	writePulseRPCServerGo(&sb, idl)
-
-	return sb.String()
-}
-
-// writeInterfaceStubGo generates a Go interface for an IDL interface
-func writeInterfaceStubGo(sb *strings.Builder, iface *parser.Interface, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) {
-	if iface.Comment != "" {
-		lines := strings.Split(strings.TrimSpace(iface.Comment), "\n")
-		for _, line := range lines {
-			fmt.Fprintf(sb, "// %s\n", line)
-		}
-	}
-	fmt.Fprintf(sb, "type %s interface {\n", iface.Name)
-
-	for _, method := range iface.Methods {
-		methodName := snakeToCamelCase(method.Name)
-		fmt.Fprintf(sb, "	%s(", methodName)
-
-		// Parameters
-		for i, param := range method.Parameters {
-			if i > 0 {
-				sb.WriteString(", ")
-			}
-			paramType := mapTypeToGoType(param.Type, structMap, enumMap, false)
-			fmt.Fprintf(sb, "%s %s", param.Name, paramType)
-		}
-		sb.WriteString(") ")
-
-		// Return type
-		if method.ReturnType != nil {
-			returnType := mapTypeToGoType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
-			sb.WriteString(returnType)
-		} else {
-			sb.WriteString("error")
-		}
-		sb.WriteString("\n")
-	}
-
-	sb.WriteString("}\n\n")
-}
-
-// writePulseRPCServerGo generates the PulseRPCServer struct and methods
-func writePulseRPCServerGo(sb *strings.Builder, idl *parser.IDL) {
-	sb.WriteString("// PulseRPCServer is an HTTP server for JSON-RPC 2.0 requests\n")
-	sb.WriteString("type PulseRPCServer struct {\n")
-	sb.WriteString("	host     string\n")
-	sb.WriteString("	port     int\n")
-	sb.WriteString("	handlers map[string]interface{}\n")
-	sb.WriteString("	server   *http.Server\n")
-	sb.WriteString("}\n\n")
-
-	sb.WriteString("// NewPulseRPCServer creates a new PulseRPCServer\n")
-	sb.WriteString("func NewPulseRPCServer(host string, port int) *PulseRPCServer {\n")
-	sb.WriteString("	return &PulseRPCServer{\n")
-	sb.WriteString("		host:     host,\n")
-	sb.WriteString("		port:     port,\n")
-	sb.WriteString("		handlers: make(map[string]interface{}),\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("}\n\n")
-
-	sb.WriteString("// Register registers an interface implementation\n")
-	sb.WriteString("func (s *PulseRPCServer) Register(interfaceName string, implementation interface{}) {\n")
-	sb.WriteString("	s.handlers[interfaceName] = implementation\n")
-	sb.WriteString("}\n\n")
-
-	sb.WriteString("// ServeForever starts the HTTP server and serves forever\n")
-	sb.WriteString("func (s *PulseRPCServer) ServeForever() error {\n")
-	sb.WriteString("	mux := http.NewServeMux()\n")
-	sb.WriteString("	mux.HandleFunc(\"/\", s.handleRequest)\n")
-	sb.WriteString("	addr := fmt.Sprintf(\"%s:%d\", s.host, s.port)\n")
-	sb.WriteString("	s.server = &http.Server{\n")
-	sb.WriteString("		Addr:    addr,\n")
-	sb.WriteString("		Handler: mux,\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("	fmt.Printf(\"PulseRPC server listening on http://%s\\n\", addr)\n")
-	sb.WriteString("	return s.server.ListenAndServe()\n")
-	sb.WriteString("}\n\n")
-
-	// Generate handleRequest method
-	writeServerHandleRequestGo(sb, idl.Interfaces)
-
-	// Generate helper methods
-	writeServerHelperMethodsGo(sb)
-}
-
-// writeServerHandleRequestGo generates the handleRequest method
-func writeServerHandleRequestGo(sb *strings.Builder, interfaces []*parser.Interface) {
-	sb.WriteString("func (s *PulseRPCServer) handleRequest(w http.ResponseWriter, r *http.Request) {\n")
-	sb.WriteString("	if r.Method != http.MethodPost {\n")
-	sb.WriteString("		http.Error(w, \"Method Not Allowed\", http.StatusMethodNotAllowed)\n")
-	sb.WriteString("		return\n")
-	sb.WriteString("	}\n\n")
-
-	sb.WriteString("	body, err := io.ReadAll(r.Body)\n")
-	sb.WriteString("	if err != nil {\n")
-	sb.WriteString("		s.sendErrorResponse(w, nil, -32700, \"Parse error\", fmt.Sprintf(\"Failed to read body: %v\", err))\n")
-	sb.WriteString("		return\n")
-	sb.WriteString("	}\n\n")
-
-	sb.WriteString("	var requestData interface{}\n")
-	sb.WriteString("	if err := json.Unmarshal(body, &requestData); err != nil {\n")
-	sb.WriteString("		s.sendErrorResponse(w, nil, -32700, \"Parse error\", fmt.Sprintf(\"Invalid JSON: %v\", err))\n")
-	sb.WriteString("		return\n")
-	sb.WriteString("	}\n\n")
-
-	sb.WriteString("	// Handle batch requests\n")
-	sb.WriteString("	if requests, ok := requestData.([]interface{}); ok {\n")
-	sb.WriteString("		if len(requests) == 0 {\n")
-	sb.WriteString("			s.sendErrorResponse(w, nil, -32600, \"Invalid Request\", \"Empty batch array\")\n")
-	sb.WriteString("			return\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("		var responses []interface{}\n")
-	sb.WriteString("		for _, req := range requests {\n")
-	sb.WriteString("			if reqMap, ok := req.(map[string]interface{}); ok {\n")
-	sb.WriteString("				resp := s.handleSingleRequest(reqMap)\n")
-	sb.WriteString("				if resp != nil {\n")
-	sb.WriteString("					responses = append(responses, resp)\n")
-	sb.WriteString("				}\n")
-	sb.WriteString("			}\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("		if len(responses) == 0 {\n")
-	sb.WriteString("			w.WriteHeader(http.StatusNoContent)\n")
-	sb.WriteString("			return\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("		w.Header().Set(\"Content-Type\", \"application/json\")\n")
-	sb.WriteString("		json.NewEncoder(w).Encode(responses)\n")
-	sb.WriteString("		return\n")
-	sb.WriteString("	}\n\n")
-
-	sb.WriteString("	// Handle single request\n")
-	sb.WriteString("	if reqMap, ok := requestData.(map[string]interface{}); ok {\n")
-	sb.WriteString("		response := s.handleSingleRequest(reqMap)\n")
-	sb.WriteString("		if response == nil {\n")
-	sb.WriteString("			w.WriteHeader(http.StatusNoContent)\n")
-	sb.WriteString("			return\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("		w.Header().Set(\"Content-Type\", \"application/json\")\n")
-	sb.WriteString("		json.NewEncoder(w).Encode(response)\n")
-	sb.WriteString("	} else {\n")
-	sb.WriteString("		s.sendErrorResponse(w, nil, -32600, \"Invalid Request\", \"Request must be an object or array\")\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("}\n\n")
-
-	sb.WriteString("func (s *PulseRPCServer) handleSingleRequest(requestJson map[string]interface{}) map[string]interface{} {\n")
-	sb.WriteString("	// Validate JSON-RPC 2.0 structure\n")
-	sb.WriteString("	jsonrpc, _ := requestJson[\"jsonrpc\"].(string)\n")
-	sb.WriteString("	if jsonrpc != \"2.0\" {\n")
-	sb.WriteString("		return s.errorResponse(nil, -32600, \"Invalid Request\", \"jsonrpc must be '2.0'\")\n")
-	sb.WriteString("	}\n\n")
-
-	sb.WriteString("	method, ok := requestJson[\"method\"].(string)\n")
-	sb.WriteString("	if !ok {\n")
-	sb.WriteString("		return s.errorResponse(nil, -32600, \"Invalid Request\", \"method must be a string\")\n")
-	sb.WriteString("	}\n\n")
-
-	sb.WriteString("	params, _ := requestJson[\"params\"].([]interface{})\n")
-	sb.WriteString("	requestID := requestJson[\"id\"]\n")
-	sb.WriteString("	_, isNotification := requestJson[\"id\"]\n")
-	sb.WriteString("	isNotification = !isNotification\n\n")
-
-	// Handle pulserpc-idl
-	sb.WriteString("	// Special case: pulserpc-idl method\n")
-	sb.WriteString("	if method == \"pulserpc-idl\" {\n")
-	sb.WriteString("		serverDir, _ := os.Getwd()\n")
-	sb.WriteString("		idlJsonPath := filepath.Join(serverDir, \"idl.json\")\n")
-	sb.WriteString("		idlData, err := os.ReadFile(idlJsonPath)\n")
-	sb.WriteString("		if err != nil {\n")
-	sb.WriteString("			return s.errorResponse(requestID, -32603, \"Internal error\", fmt.Sprintf(\"Failed to load IDL JSON: %v\", err))\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("		var idlDoc interface{}\n")
-	sb.WriteString("		if err := json.Unmarshal(idlData, &idlDoc); err != nil {\n")
-	sb.WriteString("			return s.errorResponse(requestID, -32603, \"Internal error\", fmt.Sprintf(\"Failed to parse IDL JSON: %v\", err))\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("		if isNotification {\n")
-	sb.WriteString("			return nil\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("		return map[string]interface{}{\n")
-	sb.WriteString("			\"jsonrpc\": \"2.0\",\n")
-	sb.WriteString("			\"result\": idlDoc,\n")
-	sb.WriteString("			\"id\":     requestID,\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("	}\n\n")
-
-	// Parse method name
-	sb.WriteString("	// Parse method name: interface.method\n")
-	sb.WriteString("	parts := strings.Split(method, \".\")\n")
-	sb.WriteString("	if len(parts) != 2 {\n")
-	sb.WriteString("		return s.errorResponse(requestID, -32601, \"Method not found\", fmt.Sprintf(\"Invalid method format: %s\", method))\n")
-	sb.WriteString("	}\n\n")
-
-	sb.WriteString("	interfaceName := parts[0]\n")
-	sb.WriteString("	methodName := parts[1]\n\n")
-
-	sb.WriteString("	// Find handler\n")
-	sb.WriteString("	handler, ok := s.handlers[interfaceName]\n")
-	sb.WriteString("	if !ok {\n")
-	sb.WriteString("		return s.errorResponse(requestID, -32601, \"Method not found\", fmt.Sprintf(\"Interface '%s' not registered\", interfaceName))\n")
-	sb.WriteString("	}\n\n")
-
-	// Generate method lookup
-	writeInterfaceMethodLookupGo(sb, interfaces)
-
-	sb.WriteString("	if methodDef == nil {\n")
-	sb.WriteString("		return s.errorResponse(requestID, -32601, \"Method not found\", fmt.Sprintf(\"Method '%s' not found in interface '%s'\", methodName, interfaceName))\n")
-	sb.WriteString("	}\n\n")
-
-	// Validate params
-	sb.WriteString("	// Validate params\n")
-	sb.WriteString("	if params == nil {\n")
-	sb.WriteString("		params = []interface{}{}\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("	expectedParams, _ := methodDef[\"parameters\"].([]interface{})\n")
-	sb.WriteString("	if len(params) != len(expectedParams) {\n")
-	sb.WriteString("		return s.errorResponse(requestID, -32602, \"Invalid params\", fmt.Sprintf(\"Expected %d parameters, got %d\", len(expectedParams), len(params)))\n")
-	sb.WriteString("	}\n\n")
-
-	sb.WriteString("	// Validate each param\n")
-	sb.WriteString("	for i, paramValue := range params {\n")
-	sb.WriteString("		paramDef, _ := expectedParams[i].(map[string]interface{})\n")
-	sb.WriteString("		paramType, _ := paramDef[\"type\"].(map[string]interface{})\n")
-	sb.WriteString("		if err := ValidateType(paramValue, paramType, ALL_STRUCTS, ALL_ENUMS, false); err != nil {\n")
-	sb.WriteString("			paramName, _ := paramDef[\"name\"].(string)\n")
-	sb.WriteString("			return s.errorResponse(requestID, -32602, \"Invalid params\", fmt.Sprintf(\"Parameter %d (%s) validation failed: %v\", i, paramName, err))\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("	}\n\n")
-
-	// Invoke handler - use reflection to call method
-	sb.WriteString("	// Invoke handler using reflection\n")
-	sb.WriteString("	result, err := s.invokeHandler(handler, interfaceName, methodName, params)\n")
-	sb.WriteString("	if err != nil {\n")
-	sb.WriteString("		if rpcErr, ok := err.(*RPCError); ok {\n")
-	sb.WriteString("			return s.errorResponse(requestID, rpcErr.Code, rpcErr.Message, rpcErr.Data)\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("		return s.errorResponse(requestID, -32603, \"Internal error\", fmt.Sprintf(\"%v\", err))\n")
-	sb.WriteString("	}\n\n")
-
-	// Validate response
-	sb.WriteString("	// Validate response\n")
-	sb.WriteString("	returnType, _ := methodDef[\"returnType\"].(map[string]interface{})\n")
-	sb.WriteString("	returnOptional, _ := methodDef[\"returnOptional\"].(bool)\n")
-	sb.WriteString("	if returnType != nil {\n")
-	sb.WriteString("		// Convert result to interface{} for validation\n")
-	sb.WriteString("		var resultInterface interface{}\n")
-	sb.WriteString("		if result != nil {\n")
-	sb.WriteString("			// Marshal and unmarshal to convert to map[string]interface{}\n")
-	sb.WriteString("			resultJSON, _ := json.Marshal(result)\n")
-	sb.WriteString("			json.Unmarshal(resultJSON, &resultInterface)\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("		if err := ValidateType(resultInterface, returnType, ALL_STRUCTS, ALL_ENUMS, returnOptional); err != nil {\n")
-	sb.WriteString("			return s.errorResponse(requestID, -32603, \"Internal error\", fmt.Sprintf(\"Response validation failed: %v\", err))\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("	}\n\n")
-
-	sb.WriteString("	// Return success response\n")
-	sb.WriteString("	if isNotification {\n")
-	sb.WriteString("		return nil\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("	return map[string]interface{}{\n")
-	sb.WriteString("		\"jsonrpc\": \"2.0\",\n")
-	sb.WriteString("		\"result\": result,\n")
-	sb.WriteString("		\"id\":     requestID,\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("}\n\n")
-}
-
-// writeInterfaceMethodLookupGo generates code to find method definitions
-func writeInterfaceMethodLookupGo(sb *strings.Builder, interfaces []*parser.Interface) {
-	sb.WriteString("	// Find method definition\n")
-	sb.WriteString("	var methodDef map[string]interface{}\n\n")
-	for i, iface := range interfaces {
-		if i == 0 {
-			fmt.Fprintf(sb, "	if interfaceName == \"%s\" {\n", iface.Name)
-		} else {
-			fmt.Fprintf(sb, "	} else if interfaceName == \"%s\" {\n", iface.Name)
-		}
-		sb.WriteString("		interfaceMethods := map[string]map[string]interface{}{\n")
-		for _, method := range iface.Methods {
-			fmt.Fprintf(sb, "			\"%s\": {\n", method.Name)
-			sb.WriteString("				\"parameters\": []interface{}{\n")
-			for _, param := range method.Parameters {
-				sb.WriteString("					map[string]interface{}{\n")
-				fmt.Fprintf(sb, "						\"name\": \"%s\",\n", param.Name)
-				sb.WriteString("						\"type\": ")
-				writeTypeDictGo(sb, param.Type)
-				sb.WriteString(",\n")
-				sb.WriteString("					},\n")
-			}
-			sb.WriteString("				},\n")
-			sb.WriteString("				\"returnType\": ")
-			writeTypeDictGo(sb, method.ReturnType)
-			sb.WriteString(",\n")
-			if method.ReturnOptional {
-				sb.WriteString("				\"returnOptional\": true,\n")
-			} else {
-				sb.WriteString("				\"returnOptional\": false,\n")
-			}
+		sb.WriteString(fmt.Sprintf("	\"%s\": EnumDef{\n", e.Name))
+		sb.WriteString("		\"values\": []interface{}{\n")
+		for _, val := range e.Values {
+			sb.WriteString("			map[string]interface{}{\n")
+			sb.WriteString(fmt.Sprintf("				\"name\": \"%s\",\n", val.Name))
 			sb.WriteString("			},\n")
 		}
-		sb.WriteString("		}\n")
-		sb.WriteString("		methodDef = interfaceMethods[methodName]\n")
+		sb.WriteString("		},\n")
+		sb.WriteString("	},\n")
 	}
-	sb.WriteString("	}\n\n")
-}
-
-// writeServerHelperMethodsGo generates helper methods for the server
-func writeServerHelperMethodsGo(sb *strings.Builder) {
-	sb.WriteString("func (s *PulseRPCServer) sendErrorResponse(w http.ResponseWriter, requestID interface{}, code int, message string, data interface{}) {\n")
-	sb.WriteString("	response := s.errorResponse(requestID, code, message, data)\n")
-	sb.WriteString("	w.Header().Set(\"Content-Type\", \"application/json\")\n")
-	sb.WriteString("	json.NewEncoder(w).Encode(response)\n")
-	sb.WriteString("}\n\n")
+	sb.WriteString("}\n")
 
-	sb.WriteString("func (s *PulseRPCServer) errorResponse(requestID interface{}, code int, message string, data interface{}) map[string]interface{} {\n")
-	sb.WriteString("	error := map[string]interface{}{\n")
-	sb.WriteString("		\"code\":    code,\n")
-	sb.WriteString("		\"message\": message,\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("	if data != nil {\n")
-	sb.WriteString("		error[\"data\"] = data\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("	return map[string]interface{}{\n")
-	sb.WriteString("		\"jsonrpc\": \"2.0\",\n")
-	sb.WriteString("		\"error\":   error,\n")
-	sb.WriteString("		\"id\":      requestID,\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("}\n\n")
+	return sb.String()
+}
 
-	// invokeHandler uses type assertions and reflection to call methods
-	writeInvokeHandlerGo(sb)
+// generateEnumTypesGo generates Go enum types for all enums in the namespace
+func generateEnumTypesGo(ts *templateSet, sb *strings.Builder, enums []*parser.Enum) {
+	views := make([]enumView, 0, len(enums))
+	for _, e := range enums {
+		typeName := GetBaseName(e.Name)
+		views = append(views, newEnumView(e, typeName, func(value string) string {
+			return typeName + naming.SnakeToPascal(value)
+		}))
+	}
+	ts.render(sb, "go/enums.go.tmpl", views)
 }
 
-// writeInvokeHandlerGo generates the invokeHandler method with interface-specific calls
-func writeInvokeHandlerGo(sb *strings.Builder) {
-	sb.WriteString("func (s *PulseRPCServer) invokeHandler(handler interface{}, interfaceName, methodName string, params []interface{}) (interface{}, error) {\n")
-	sb.WriteString("	// Convert params from JSON (interface{}) to typed values\n")
-	sb.WriteString("	// This is a simplified approach - in practice, you'd unmarshal to the correct types\n")
-	sb.WriteString("	\n")
-	sb.WriteString("	// Use reflection to call methods dynamically\n")
-	sb.WriteString("	handlerValue := reflect.ValueOf(handler)\n")
-	sb.WriteString("	handlerType := handlerValue.Type()\n")
-	sb.WriteString("	\n")
-	sb.WriteString("	// Find method by name (Go methods are exported, convert snake_case to camelCase)\n")
-	sb.WriteString("	methodNameCamel := \"\"\n")
-	sb.WriteString("	if len(methodName) > 0 {\n")
-	sb.WriteString("		parts := strings.Split(methodName, \"_\")\n")
-	sb.WriteString("		for i, part := range parts {\n")
-	sb.WriteString("			if i == 0 {\n")
-	sb.WriteString("				methodNameCamel += strings.ToUpper(part[:1]) + part[1:]\n")
-	sb.WriteString("			} else {\n")
-	sb.WriteString("				methodNameCamel += strings.ToUpper(part[:1]) + part[1:]\n")
-	sb.WriteString("			}\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("	\n")
-	sb.WriteString("	// Try to find the method\n")
-	sb.WriteString("	var method reflect.Method\n")
-	sb.WriteString("	found := false\n")
-	sb.WriteString("	for i := 0; i < handlerType.NumMethod(); i++ {\n")
-	sb.WriteString("		m := handlerType.Method(i)\n")
-	sb.WriteString("		if m.Name == methodNameCamel {\n")
-	sb.WriteString("			method = m\n")
-	sb.WriteString("			found = true\n")
-	sb.WriteString("			break\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("	\n")
-	sb.WriteString("	if !found {\n")
-	sb.WriteString("		return nil, fmt.Errorf(\"method %s not found on interface %s\", methodName, interfaceName)\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("	\n")
-	sb.WriteString("	// Convert params to the types expected by the method\n")
-	sb.WriteString("	// This is simplified - in practice, you'd need to unmarshal JSON to the correct types\n")
-	sb.WriteString("	methodType := method.Type\n")
-	sb.WriteString("	numIn := methodType.NumIn()\n")
-	sb.WriteString("	args := make([]reflect.Value, numIn-1) // -1 because first param is receiver\n")
-	sb.WriteString("	\n")
-	sb.WriteString("	for i := 1; i < numIn; i++ {\n") // Start at 1 to skip receiver
-	sb.WriteString("		paramType := methodType.In(i)\n")
-	sb.WriteString("		paramValue := params[i-1]\n")
-	sb.WriteString("		\n")
-	sb.WriteString("		// Convert paramValue to paramType using JSON unmarshaling\n")
-	sb.WriteString("		paramJSON, _ := json.Marshal(paramValue)\n")
-	sb.WriteString("		paramPtr := reflect.New(paramType)\n")
-	sb.WriteString("		if err := json.Unmarshal(paramJSON, paramPtr.Interface()); err != nil {\n")
-	sb.WriteString("			return nil, fmt.Errorf(\"failed to convert parameter %d: %w\", i-1, err)\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("		args[i-1] = paramPtr.Elem()\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("	\n")
-	sb.WriteString("	// Call the method\n")
-	sb.WriteString("	results := method.Func.Call(append([]reflect.Value{handlerValue}, args...))\n")
-	sb.WriteString("	\n")
-	sb.WriteString("	// Handle return values\n")
-	sb.WriteString("	if len(results) == 0 {\n")
-	sb.WriteString("		return nil, nil\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("	if len(results) == 1 {\n")
-	sb.WriteString("		if err, ok := results[0].Interface().(error); ok && err != nil {\n")
-	sb.WriteString("			return nil, err\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("		return results[0].Interface(), nil\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("	// Multiple return values: result and error\n")
-	sb.WriteString("	result := results[0].Interface()\n")
-	sb.WriteString("	if err, ok := results[1].Interface().(error); ok && err != nil {\n")
-	sb.WriteString("		return nil, err\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("	return result, nil\n")
-	sb.WriteString("}\n\n")
+// generateTypedefTypesGo generates a Go defined type for each typedef in the namespace
+func generateTypedefTypesGo(sb *strings.Builder, typedefs []*parser.Typedef, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, qualify func(string) string) {
+	for _, td := range typedefs {
+		if td.Comment != "" {
+			for _, line := range strings.Split(strings.TrimSpace(td.Comment), "\n") {
+				fmt.Fprintf(sb, "// %s\n", line)
+			}
+		}
+		// Map the typedef's body, not the typedef itself, which has Alias unset
+		fmt.Fprintf(sb, "type %s %s\n\n", GetBaseName(td.Name), mapTypeToQualifiedGoType(td.Type, structMap, enumMap, false, qualify))
+	}
 }
 
-// generateClientGo generates the client.go file with transport abstraction and client classes
-func generateClientGo(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, primaryNs string, namespaceMap map[string]*NamespaceTypes) string {
-	var sb strings.Builder
+// generateStructTypesGo generates Go struct types for all structs in the namespace
+func generateStructTypesGo(sb *strings.Builder, structs []*parser.Struct, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, qualify func(string) string, presence bool) {
+	for _, s := range structs {
+		if s.Comment != "" {
+			lines := strings.Split(strings.TrimSpace(s.Comment), "\n")
+			for _, line := range lines {
+				fmt.Fprintf(sb, "// %s\n", line)
+			}
+		}
 
-	sb.WriteString("//go:build !server_only\n")
-	sb.WriteString("// +build !server_only\n\n")
-	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
-	sb.WriteString(fmt.Sprintf("package %s\n\n", primaryNs))
-	sb.WriteString("import (\n")
-	sb.WriteString("	\"bytes\"\n")
-	sb.WriteString("	\"encoding/json\"\n")
-	sb.WriteString("	\"fmt\"\n")
-	sb.WriteString("	\"net/http\"\n")
-	sb.WriteString("	\"strings\"\n")
-	sb.WriteString(")\n\n")
+		structName := GetBaseName(s.Name)
+		fmt.Fprintf(sb, "type %s struct {\n", structName)
 
-	// Merge ALL_STRUCTS and ALL_ENUMS (same as server)
-	sb.WriteString("// Merge ALL_STRUCTS and ALL_ENUMS from all namespaces\n")
-	sb.WriteString("func init() {\n")
-	namespaces := make([]string, 0, len(namespaceMap))
-	for ns := range namespaceMap {
-		if ns != "" {
-			namespaces = append(namespaces, ns)
+		// Handle inheritance via embedding
+		if s.Extends != "" {
+			parentName := getGoStructOrEnumTypeName(s.Extends, structMap, enumMap)
+			if qualify != nil {
+				parentName = qualify(s.Extends)
+			}
+			fmt.Fprintf(sb, "	%s\n", parentName)
 		}
-	}
-	sort.Strings(namespaces)
-	for _, ns := range namespaces {
-		nsUpper := strings.ToUpper(strings.ReplaceAll(ns, ".", "_"))
-		sb.WriteString(fmt.Sprintf("	for k, v := range %s_ALL_STRUCTS {\n", nsUpper))
-		sb.WriteString("		ALL_STRUCTS[k] = v\n")
-		sb.WriteString("	}\n")
-		sb.WriteString(fmt.Sprintf("	for k, v := range %s_ALL_ENUMS {\n", nsUpper))
-		sb.WriteString("		ALL_ENUMS[k] = v\n")
-		sb.WriteString("	}\n")
-	}
-	sb.WriteString("}\n\n")
 
-	// Generate Transport interface
-	writeTransportInterfaceGo(&sb)
+		// Generate fields
+		for _, field := range s.Fields {
+			if field.Comment != "" {
+				lines := strings.Split(strings.TrimSpace(field.Comment), "\n")
+				for _, line := range lines {
+					fmt.Fprintf(sb, "	// %s\n", line)
+				}
+			}
+
+			// JSON tag (IDL uses snake_case, Go uses CamelCase)
+			fieldName := goFieldName(s, field)
+			goType, jsonTag := goFieldType(field, structMap, enumMap, qualify, presence)
+			if s.IsImmutable() {
+				// Unexported fields are encoded through JSONFields
+				fmt.Fprintf(sb, "	%s %s\n", fieldName, goType)
+				continue
+			}
+			if field.IsSensitive() {
+				fmt.Fprintf(sb, "	%s %s `json:\"%s\" pulse:\"sensitive\"`\n", fieldName, goType, jsonTag)
+				continue
+			}
+			fmt.Fprintf(sb, "	%s %s `json:\"%s\"`\n", fieldName, goType, jsonTag)
+		}
 
-	// Generate HTTPTransport
-	writeHTTPTransportGo(&sb)
+		sb.WriteString("}\n\n")
 
-	// Generate client classes for each interface
-	for _, iface := range idl.Interfaces {
-		writeInterfaceClientGo(&sb, iface, structMap, enumMap)
+		if s.IsImmutable() {
+			writeImmutableMembersGo(sb, s, structMap, enumMap, qualify, presence)
+		}
+		writeCloneMethodGo(sb, s, structMap, enumMap, qualify, presence)
+		if needsRedaction(s, structMap) {
+			writeStringMethodGo(sb, s)
+		}
 	}
-
-	return sb.String()
 }
 
-// writeTransportInterfaceGo generates the Transport interface
-func writeTransportInterfaceGo(sb *strings.Builder) {
-	sb.WriteString("// Transport is an interface for making JSON-RPC 2.0 calls\n")
-	sb.WriteString("type Transport interface {\n")
-	sb.WriteString("	Call(method string, params []interface{}) (map[string]interface{}, error)\n")
-	sb.WriteString("}\n\n")
+// goServerView is the view model for server.go
+type goServerView struct {
+	Package string
+	// Imports are the runtime, types and namespace packages of a package layout
+	Imports []string
+	// Registries are the prefixes of the namespaces' _ALL_STRUCTS and _ALL_ENUMS
+	Registries []string
+	Interfaces []goInterfaceView
+	// DispatchMethod is the server method that handles one decoded request
+	DispatchMethod string
+	AdminPath      string
+	SubInterfaces  []subInterfaceView
+	RESTRoutes     []restRouteView
+	LegacyRoutes   []legacyRouteView
+	// DispatchInterfaces are the cases of the typed dispatch switch
+	DispatchInterfaces []goDispatchInterfaceView
+	// Capabilities are reported by the built-in pulserpc-capabilities method
+	Capabilities []capability
+
+	// Options and IDL features that add code to the server
+	Faults               bool
+	Admin                bool
+	AsyncJobs            bool
+	Cached               bool
+	Compressed           bool
+	LegacyEncodings      bool
+	EncryptedFields      bool
+	Idempotent           bool
+	Chunked              bool
+	InterfaceInheritance bool
+	OptionalParams       bool
+	WireNames            bool
+
+	// Sections written by the generators of optional features, empty when unused
+	Canonical        string
+	Dedupe           string
+	AdminServer      string
+	Compose          string
+	JobsServer       string
+	WireMethods      string
+	Compression      string
+	EncryptedMethods string
+	CacheHelpers     string
 }
 
-// writeHTTPTransportGo generates the HTTPTransport struct
-func writeHTTPTransportGo(sb *strings.Builder) {
-	sb.WriteString("// HTTPTransport implements Transport using HTTP\n")
-	sb.WriteString("type HTTPTransport struct {\n")
-	sb.WriteString("	baseURL string\n")
-	sb.WriteString("	headers map[string]string\n")
-	sb.WriteString("	client  *http.Client\n")
-	sb.WriteString("}\n\n")
-
-	sb.WriteString("// NewHTTPTransport creates a new HTTPTransport\n")
-	sb.WriteString("func NewHTTPTransport(baseURL string, headers map[string]string) *HTTPTransport {\n")
-	sb.WriteString("	if headers == nil {\n")
-	sb.WriteString("		headers = make(map[string]string)\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("	return &HTTPTransport{\n")
-	sb.WriteString("		baseURL: strings.TrimSuffix(baseURL, \"/\"),\n")
-	sb.WriteString("		headers: headers,\n")
-	sb.WriteString("		client:  &http.Client{},\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("}\n\n")
-
-	sb.WriteString("// Call performs a JSON-RPC 2.0 call over HTTP\n")
-	sb.WriteString("func (t *HTTPTransport) Call(method string, params []interface{}) (map[string]interface{}, error) {\n")
-	sb.WriteString("	requestID := fmt.Sprintf(\"%d\", len(method)+len(params))\n")
-	sb.WriteString("	request := map[string]interface{}{\n")
-	sb.WriteString("		\"jsonrpc\": \"2.0\",\n")
-	sb.WriteString("		\"method\":  method,\n")
-	sb.WriteString("		\"params\":  params,\n")
-	sb.WriteString("		\"id\":      requestID,\n")
-	sb.WriteString("	}\n\n")
+// goInterfaceView is the view model for the Go interface of an IDL interface
+type goInterfaceView struct {
+	Name         string
+	CommentLines []string
+	// Extends are the embedded parent interfaces, which declare the inherited methods
+	Extends []string
+	Methods []goMethodView
+}
 
-	sb.WriteString("	jsonData, err := json.Marshal(request)\n")
-	sb.WriteString("	if err != nil {\n")
-	sb.WriteString("		return nil, fmt.Errorf(\"failed to marshal request: %w\", err)\n")
-	sb.WriteString("	}\n\n")
+// goMethodView is the view model for a method of a Go interface
+type goMethodView struct {
+	Name   string
+	Params []goParamView
+	// ReturnType is empty for methods without a result
+	ReturnType string
+	// EmitType is the emit func a [chunked] method takes instead of returning its array
+	EmitType string
+}
 
-	sb.WriteString("	req, err := http.NewRequest(\"POST\", t.baseURL, bytes.NewBuffer(jsonData))\n")
-	sb.WriteString("	if err != nil {\n")
-	sb.WriteString("		return nil, fmt.Errorf(\"failed to create request: %w\", err)\n")
-	sb.WriteString("	}\n\n")
+type goParamView struct {
+	Name string
+	Type string
+}
 
-	sb.WriteString("	req.Header.Set(\"Content-Type\", \"application/json\")\n")
-	sb.WriteString("	for k, v := range t.headers {\n")
-	sb.WriteString("		req.Header.Set(k, v)\n")
-	sb.WriteString("	}\n\n")
+// generateServerGo generates the server.go file with HTTP server and interface stubs
+func generateServerGo(ts *templateSet, idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, primaryNs string, namespaceMap map[string]*NamespaceTypes, layout *goPackageLayout, faults bool, admin bool, idlDoc idlJSONDocument) string {
+	interfaces := idl.Interfaces
+	view := goServerView{
+		Package:              primaryNs,
+		Imports:              layout.imports(namespaceMap),
+		DispatchMethod:       goDispatchMethod(interfaces),
+		AdminPath:            adminPath,
+		SubInterfaces:        newSubInterfaceViews(interfaces),
+		Faults:               faults,
+		Admin:                admin,
+		AsyncJobs:            usesAsyncMethods(interfaces),
+		Cached:               usesCachedMethods(interfaces),
+		Compressed:           usesCompressedMethods(interfaces),
+		LegacyEncodings:      usesLegacyEncodings(interfaces),
+		EncryptedFields:      usesEncryptedFields(idl),
+		Idempotent:           usesIdempotentMethods(interfaces),
+		Chunked:              usesChunkedMethods(interfaces),
+		InterfaceInheritance: usesInterfaceInheritance(interfaces),
+		OptionalParams:       usesOptionalParams(interfaces),
+		WireNames:            usesWireNames(interfaces),
+	}
+	for _, ns := range sortedNamespaces(namespaceMap) {
+		view.Registries = append(view.Registries, layout.registryPrefix(ns)+strings.ToUpper(strings.ReplaceAll(ns, ".", "_")))
+	}
+	for _, iface := range interfaces {
+		view.Interfaces = append(view.Interfaces, newGoInterfaceView(iface, structMap, enumMap))
+	}
+	view.RESTRoutes = newRESTRouteViews(interfaces, writeTypeDictGo)
+	view.LegacyRoutes = newLegacyRouteViews(interfaces, writeTypeDictGo)
+
+	view.DispatchInterfaces = newGoDispatchInterfaceViews(interfaces, structMap, enumMap)
+	view.Capabilities = serverCapabilities(interfaces, true)
+	view.Canonical = capture(writeCanonicalServerGo)
+	view.Compose = capture(func(sb *strings.Builder) { writeComposeServerGo(sb, interfaces, idlDoc) })
+	if view.Idempotent {
+		view.Dedupe = capture(func(sb *strings.Builder) { writeDedupeServerGo(sb, idl) })
+	}
+	if admin {
+		view.AdminServer = capture(func(sb *strings.Builder) { writeAdminServerGo(sb, idl) })
+	}
+	if view.AsyncJobs {
+		view.JobsServer = capture(writeJobsServerGo)
+	}
+	if view.WireNames {
+		view.WireMethods = capture(func(sb *strings.Builder) { writeWireMethodsGo(sb, interfaces) })
+	}
+	if view.Compressed {
+		view.Compression = capture(func(sb *strings.Builder) { writeCompressionGo(sb, interfaces) })
+	}
+	if view.EncryptedFields {
+		view.EncryptedMethods = capture(func(sb *strings.Builder) { writeEncryptedMethodsGo(sb, idl) })
+	}
+	if view.Cached {
+		view.CacheHelpers = capture(writeCacheHelpersGo)
+	}
+	return ts.renderString("go/server.go.tmpl", view)
+}
 
-	sb.WriteString("	resp, err := t.client.Do(req)\n")
-	sb.WriteString("	if err != nil {\n")
-	sb.WriteString("		return nil, fmt.Errorf(\"HTTP request failed: %w\", err)\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("	defer resp.Body.Close()\n\n")
+// newGoInterfaceView builds the view of the Go interface for an IDL interface. Methods
+// take the call's context first, which carries its deadline and RequestMeta, and return
+// an error after the result, such as an *RPCError for the caller.
+func newGoInterfaceView(iface *parser.Interface, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) goInterfaceView {
+	view := goInterfaceView{Name: iface.Name, Extends: iface.Extends}
+	if iface.Comment != "" {
+		view.CommentLines = strings.Split(strings.TrimSpace(iface.Comment), "\n")
+	}
+	for _, method := range iface.OwnMethods() {
+		mv := goMethodView{Name: naming.SnakeToPascal(method.Name)}
+		for _, param := range method.Parameters {
+			mv.Params = append(mv.Params, goParamView{Name: param.Name, Type: mapTypeToGoType(param.Type, structMap, enumMap, param.Optional)})
+		}
+		if method.IsChunked() {
+			mv.EmitType = goChunkedEmitType(method, structMap, enumMap)
+		} else if method.ReturnType != nil {
+			mv.ReturnType = mapTypeToGoType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
+		}
+		view.Methods = append(view.Methods, mv)
+	}
+	return view
+}
 
-	sb.WriteString("	var response map[string]interface{}\n")
-	sb.WriteString("	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {\n")
-	sb.WriteString("		return nil, fmt.Errorf(\"failed to decode response: %w\", err)\n")
-	sb.WriteString("	}\n\n")
+// goMocksView is the view model for mocks.go
+type goMocksView struct {
+	Package string
+	// Framework is "gomock" or "testify"
+	Framework string
+	// TypesImport is the types package the mocks dot-import when the code is
+	// split into types, client and server packages
+	TypesImport string
+	Interfaces  []goMockInterfaceView
+}
 
-	sb.WriteString("	if errObj, ok := response[\"error\"].(map[string]interface{}); ok {\n")
-	sb.WriteString("		code, _ := errObj[\"code\"].(float64)\n")
-	sb.WriteString("		message, _ := errObj[\"message\"].(string)\n")
-	sb.WriteString("		data := errObj[\"data\"]\n")
-	sb.WriteString("		return nil, &RPCError{\n")
-	sb.WriteString("			Code:    int(code),\n")
-	sb.WriteString("			Message: message,\n")
-	sb.WriteString("			Data:    data,\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("	}\n\n")
+type goMockInterfaceView struct {
+	Name    string
+	Methods []goMockMethodView
+}
 
-	sb.WriteString("	return response, nil\n")
-	sb.WriteString("}\n\n")
+// goMockMethodView holds a method's signature pieces. Parameters are named
+// arg0, arg1, ... so they cannot collide with the mock's own identifiers; arg0 is
+// the call's context.
+type goMockMethodView struct {
+	Interface      string
+	Name           string
+	Params         string // arg0 int, arg1 string
+	ParamTypes     string // int, string
+	Args           string // arg0, arg1
+	CallArgs       string // , arg0, arg1
+	RecorderParams string // arg0, arg1 interface{}
+	ReturnType     string
+	Results        string // (string, error), or error for chunked and void methods
+	ReturnsError   bool   // Results has an error after ReturnType
 }
 
-// writeInterfaceClientGo generates a client struct for an interface
-func writeInterfaceClientGo(sb *strings.Builder, iface *parser.Interface, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) {
-	if iface.Comment != "" {
-		lines := strings.Split(strings.TrimSpace(iface.Comment), "\n")
-		for _, line := range lines {
-			fmt.Fprintf(sb, "// %s\n", line)
+// generateMocksGo generates mocks of the server interfaces for gomock or testify/mock
+func generateMocksGo(ts *templateSet, idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, primaryNs string, framework string, layout *goPackageLayout) string {
+	view := goMocksView{Package: primaryNs, Framework: framework}
+	if goMethodsUseTypes(idl.Interfaces) {
+		view.TypesImport = layout.typesImport()
+	}
+	for _, iface := range idl.Interfaces {
+		iv := goMockInterfaceView{Name: iface.Name}
+		for _, method := range iface.Methods {
+			params := []string{"arg0 context.Context"}
+			types := []string{"context.Context"}
+			args := []string{"arg0"}
+			for i, param := range method.Parameters {
+				paramType := mapTypeToGoType(param.Type, structMap, enumMap, param.Optional)
+				arg := fmt.Sprintf("arg%d", i+1)
+				params = append(params, arg+" "+paramType)
+				types = append(types, paramType)
+				args = append(args, arg)
+			}
+			returnType := "error"
+			if method.ReturnType != nil {
+				returnType = mapTypeToGoType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
+			}
+			if method.IsChunked() {
+				arg := fmt.Sprintf("arg%d", len(method.Parameters)+1)
+				emitType := goChunkedEmitType(method, structMap, enumMap)
+				params = append(params, arg+" "+emitType)
+				types = append(types, emitType)
+				args = append(args, arg)
+				returnType = "error"
+			}
+			mv := goMockMethodView{
+				Interface:  iface.Name,
+				Name:       naming.SnakeToPascal(method.Name),
+				Params:     strings.Join(params, ", "),
+				ParamTypes: strings.Join(types, ", "),
+				Args:       strings.Join(args, ", "),
+				ReturnType: returnType,
+				Results:    returnType,
+			}
+			if !method.IsChunked() && method.ReturnType != nil {
+				mv.Results = "(" + returnType + ", error)"
+				mv.ReturnsError = true
+			}
+			mv.CallArgs = ", " + mv.Args
+			mv.RecorderParams = mv.Args + " interface{}"
+			iv.Methods = append(iv.Methods, mv)
 		}
+		view.Interfaces = append(view.Interfaces, iv)
 	}
+	return ts.renderString("go/mocks.go.tmpl", view)
+}
 
-	clientName := iface.Name + "Client"
-	fmt.Fprintf(sb, "// %s is a client for the %s interface\n", clientName, iface.Name)
-	fmt.Fprintf(sb, "type %s struct {\n", clientName)
-	sb.WriteString("	transport Transport\n")
-	sb.WriteString("}\n\n")
+// writeParamOptionsGo writes the optional flag and default of an optional
+// parameter as entries of its parameter definition. A default stands in for a
+// decoded param, so numbers are float64 as json.Unmarshal gives them.
+func writeParamOptionsGo(sb *strings.Builder, indent string, param *parser.Parameter) {
+	if !param.Optional {
+		return
+	}
+	fmt.Fprintf(sb, "%s\"optional\": true,\n", indent)
+	if value, ok := defaultJSON(param); ok {
+		if param.Type.IsBuiltIn() && (param.Type.BuiltIn == "int" || param.Type.BuiltIn == "float") {
+			value = "float64(" + value + ")"
+		}
+		fmt.Fprintf(sb, "%s\"default\":  %s,\n", indent, value)
+	}
+}
 
-	fmt.Fprintf(sb, "// New%s creates a new %s\n", clientName, clientName)
-	fmt.Fprintf(sb, "func New%s(transport Transport) *%s {\n", clientName, clientName)
-	fmt.Fprintf(sb, "	return &%s{transport: transport}\n", clientName)
-	sb.WriteString("}\n\n")
+// goClientView is the view model for client.go
+type goClientView struct {
+	Package    string
+	Imports    []string
+	Registries []string
+	Clients    []goClientInterfaceView
+	AsyncJobs  bool
+	Cached     bool
+
+	// Sections written by the generators of optional features, empty when unused
+	BatchClient         string
+	JobsClient          string
+	CanonicalClient     string
+	DebugLog            string
+	CallBatch           string
+	ConditionalRequests string
+	BindErrorData       string
+	APIClient           string
+}
 
-	// Generate methods
-	for _, method := range iface.Methods {
-		writeClientMethodGo(sb, iface, method, structMap, enumMap)
-	}
+// goClientInterfaceView is the view model for the client of an IDL interface
+type goClientInterfaceView struct {
+	Name         string
+	CommentLines []string
+	// Encrypted reports that calls carry [encrypted] fields, so the client takes a FieldCipher
+	Encrypted bool
+	Methods   []goClientMethodView
 }
 
-// writeClientMethodGo generates a method implementation for a client struct
-func writeClientMethodGo(sb *strings.Builder, iface *parser.Interface, method *parser.Method, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) {
-	methodName := snakeToCamelCase(method.Name)
-	fmt.Fprintf(sb, "// %s calls %s.%s\n", methodName, iface.Name, method.Name)
-	fmt.Fprintf(sb, "func (c *%sClient) %s(", iface.Name, methodName)
+// goClientMethodView is the view model for a client method
+type goClientMethodView struct {
+	Interface string
+	Name      string
+	IDLName   string
+	RPCName   string
+	Params    []goParamView
+	// ParamNames is the quoted list of parameter names sent with named params
+	ParamNames     string
+	ReturnType     string
+	ReturnOptional bool
+	OptionalParams bool
+	EncryptParams  bool
+	DecryptResult  bool
+	Async          bool
+	// ErrorData is the IDL struct bound to the data of errors, and ErrorDataType its Go type
+	ErrorData     string
+	ErrorDataType string
+}
 
-	// Parameters
-	for i, param := range method.Parameters {
-		if i > 0 {
-			sb.WriteString(", ")
+// generateClientGo generates the client.go file with the transports and a client per interface
+func generateClientGo(ts *templateSet, idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, primaryNs string, namespaceMap map[string]*NamespaceTypes, layout *goPackageLayout) string {
+	interfaces := idl.Interfaces
+	view := goClientView{
+		Package:         primaryNs,
+		Imports:         layout.imports(namespaceMap),
+		AsyncJobs:       usesAsyncMethods(interfaces),
+		Cached:          usesCachedMethods(interfaces),
+		BatchClient:     capture(writeBatchClientGo),
+		CanonicalClient: capture(writeCanonicalClientGo),
+		DebugLog:        capture(writeDebugLogGo),
+		CallBatch:       capture(writeCallBatchGo),
+	}
+	for _, ns := range sortedNamespaces(namespaceMap) {
+		view.Registries = append(view.Registries, layout.registryPrefix(ns)+strings.ToUpper(strings.ReplaceAll(ns, ".", "_")))
+	}
+	if view.AsyncJobs {
+		view.JobsClient = capture(writeJobsClientGo)
+	}
+	if view.Cached {
+		view.ConditionalRequests = capture(func(sb *strings.Builder) { writeConditionalRequestsGo(sb, interfaces) })
+	}
+	if len(errorDataStructs(interfaces)) > 0 {
+		view.BindErrorData = capture(writeBindErrorDataGo)
+	}
+	for _, iface := range interfaces {
+		cv := goClientInterfaceView{Name: iface.Name, Encrypted: interfaceNeedsEncryption(iface, structMap)}
+		if iface.Comment != "" {
+			cv.CommentLines = strings.Split(strings.TrimSpace(iface.Comment), "\n")
+		}
+		for _, method := range iface.Methods {
+			cv.Methods = append(cv.Methods, newGoClientMethodView(iface, method, structMap, enumMap))
 		}
-		paramType := mapTypeToGoType(param.Type, structMap, enumMap, false)
-		fmt.Fprintf(sb, "%s %s", param.Name, paramType)
+		view.Clients = append(view.Clients, cv)
 	}
-	sb.WriteString(") ")
-
-	// Return type
-	if method.ReturnType != nil {
-		returnType := mapTypeToGoType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
-		fmt.Fprintf(sb, "(%s, error)", returnType)
-	} else {
-		sb.WriteString("error")
+	if usesAPIClientFacade(interfaces) {
+		view.APIClient = capture(func(sb *strings.Builder) { writeAPIClientGo(sb, interfaces) })
 	}
-	sb.WriteString(" {\n")
+	return ts.renderString("go/client.go.tmpl", view)
+}
 
-	// Build params array
-	sb.WriteString("	params := []interface{}{\n")
-	for _, param := range method.Parameters {
-		fmt.Fprintf(sb, "		%s,\n", param.Name)
+// newGoClientMethodView builds the view of a client method, which validates its params
+// and result against the IDL and decodes the result into its Go type.
+func newGoClientMethodView(iface *parser.Interface, method *parser.Method, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) goClientMethodView {
+	mv := goClientMethodView{
+		Interface:      iface.Name,
+		Name:           naming.SnakeToPascal(method.Name),
+		IDLName:        method.Name,
+		RPCName:        iface.RPCName(method),
+		ReturnOptional: method.ReturnOptional,
+		OptionalParams: method.RequiredParams() < len(method.Parameters),
+		EncryptParams:  paramsNeedEncryption(method, structMap),
+		Async:          method.IsAsync(),
 	}
-	sb.WriteString("	}\n\n")
-
-	// Validate parameters
-	sb.WriteString("	// Validate parameters\n")
-	sb.WriteString("	methodDef := map[string]interface{}{\n")
-	sb.WriteString("		\"parameters\": []interface{}{\n")
-	for _, param := range method.Parameters {
-		sb.WriteString("			map[string]interface{}{\n")
-		fmt.Fprintf(sb, "				\"name\": \"%s\",\n", param.Name)
-		sb.WriteString("				\"type\": ")
-		writeTypeDictGo(sb, param.Type)
-		sb.WriteString(",\n")
-		sb.WriteString("			},\n")
-	}
-	sb.WriteString("		},\n")
-	sb.WriteString("	}\n")
-	sb.WriteString("	expectedParams, _ := methodDef[\"parameters\"].([]interface{})\n")
-	sb.WriteString("	for i, paramValue := range params {\n")
-	sb.WriteString("		paramDef, _ := expectedParams[i].(map[string]interface{})\n")
-	sb.WriteString("		paramType, _ := paramDef[\"type\"].(map[string]interface{})\n")
-	sb.WriteString("		// Convert param to interface{} for validation\n")
-	sb.WriteString("		var paramInterface interface{}\n")
-	sb.WriteString("		paramJSON, _ := json.Marshal(paramValue)\n")
-	sb.WriteString("		json.Unmarshal(paramJSON, &paramInterface)\n")
-	sb.WriteString("		if err := ValidateType(paramInterface, paramType, ALL_STRUCTS, ALL_ENUMS, false); err != nil {\n")
-	sb.WriteString("			paramName, _ := paramDef[\"name\"].(string)\n")
-	sb.WriteString("			var zero ")
-	if method.ReturnType != nil {
-		returnType := mapTypeToGoType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
-		sb.WriteString(returnType)
-	} else {
-		sb.WriteString("interface{}")
+	names := make([]string, len(method.Parameters))
+	for i, param := range method.Parameters {
+		mv.Params = append(mv.Params, goParamView{Name: param.Name, Type: mapTypeToGoType(param.Type, structMap, enumMap, param.Optional)})
+		names[i] = param.Name
 	}
-	sb.WriteString("\n")
-	sb.WriteString("			return zero, fmt.Errorf(\"parameter %d (%s) validation failed: %w\", i, paramName, err)\n")
-	sb.WriteString("		}\n")
-	sb.WriteString("	}\n\n")
-
-	// Call transport
-	fmt.Fprintf(sb, "	methodName := \"%s.%s\"\n", iface.Name, method.Name)
-	sb.WriteString("	response, err := c.transport.Call(methodName, params)\n")
-	sb.WriteString("	if err != nil {\n")
-	if method.ReturnType != nil {
-		sb.WriteString("		var zero ")
-		returnType := mapTypeToGoType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
-		sb.WriteString(returnType)
-		sb.WriteString("\n")
-		sb.WriteString("		return zero, err\n")
-	} else {
-		sb.WriteString("		return err\n")
+	if len(names) > 0 {
+		mv.ParamNames = quotedList(names)
 	}
-	sb.WriteString("	}\n\n")
-
-	// Extract and validate result
 	if method.ReturnType != nil {
-		sb.WriteString("	result, ok := response[\"result\"]\n")
-		sb.WriteString("	if !ok {\n")
-		if method.ReturnOptional {
-			sb.WriteString("		var zero ")
-			returnType := mapTypeToGoType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
-			sb.WriteString(returnType)
-			sb.WriteString("\n")
-			sb.WriteString("		return zero, nil\n")
-		} else {
-			sb.WriteString("		var zero ")
-			returnType := mapTypeToGoType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
-			sb.WriteString(returnType)
-			sb.WriteString("\n")
-			sb.WriteString("		return zero, fmt.Errorf(\"missing result in response\")\n")
-		}
-		sb.WriteString("	}\n\n")
-
-		sb.WriteString("	// Validate result\n")
-		sb.WriteString("	returnType := ")
-		writeTypeDictGo(sb, method.ReturnType)
-		sb.WriteString("\n")
-		sb.WriteString("	returnOptional := ")
-		if method.ReturnOptional {
-			sb.WriteString("true\n")
-		} else {
-			sb.WriteString("false\n")
-		}
-		sb.WriteString("	var resultInterface interface{}\n")
-		sb.WriteString("	resultJSON, _ := json.Marshal(result)\n")
-		sb.WriteString("	json.Unmarshal(resultJSON, &resultInterface)\n")
-		sb.WriteString("	if err := ValidateType(resultInterface, returnType, ALL_STRUCTS, ALL_ENUMS, returnOptional); err != nil {\n")
-		sb.WriteString("		var zero ")
-		goReturnType := mapTypeToGoType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
-		sb.WriteString(goReturnType)
-		sb.WriteString("\n")
-		sb.WriteString("		return zero, fmt.Errorf(\"response validation failed: %w\", err)\n")
-		sb.WriteString("	}\n\n")
-
-		// Deserialize result to typed value
-		goReturnType2 := mapTypeToGoType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
-		sb.WriteString("	var typedResult ")
-		sb.WriteString(goReturnType2)
-		sb.WriteString("\n")
-		sb.WriteString("	if err := json.Unmarshal(resultJSON, &typedResult); err != nil {\n")
-		sb.WriteString("		var zero ")
-		sb.WriteString(goReturnType2)
-		sb.WriteString("\n")
-		sb.WriteString("		return zero, fmt.Errorf(\"failed to unmarshal result: %w\", err)\n")
-		sb.WriteString("	}\n")
-		sb.WriteString("	return typedResult, nil\n")
-	} else {
-		sb.WriteString("	return nil\n")
+		mv.ReturnType = mapTypeToGoType(method.ReturnType, structMap, enumMap, method.ReturnOptional)
+		mv.DecryptResult = typeNeedsEncryption(method.ReturnType, structMap)
 	}
-	sb.WriteString("}\n\n")
+	if name := method.ErrorData(); name != "" {
+		mv.ErrorData = name
+		mv.ErrorDataType = strings.TrimPrefix(mapTypeToGoType(&parser.Type{UserDefined: name}, structMap, enumMap, false), "*")
+	}
+	return mv
 }
 
 // generateTestServerGo generates test_server.go with concrete implementations
-func generateTestServerGo(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) string {
+func generateTestServerGo(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, importPaths []string, faults bool, admin bool) string {
 	var sb strings.Builder
 
 	sb.WriteString("// Generated by pulserpc - do not edit\n")
@@ -1178,13 +1464,19 @@ func generateTestServerGo(idl *parser.IDL, structMap map[string]*parser.Struct,
 	}
 
 	sb.WriteString("import (\n")
+	sb.WriteString("	\"context\"\n")
 	if needsMath {
 		sb.WriteString("	\"math\"\n")
 	}
+	if faults || admin {
+		sb.WriteString("	\"os\"\n")
+	}
 	if needsStrings {
 		sb.WriteString("	\"strings\"\n")
 	}
-	fmt.Fprintf(&sb, "	. \"pulserpc_test_go\"\n")
+	for _, importPath := range importPaths {
+		fmt.Fprintf(&sb, "	. \"%s\"\n", importPath)
+	}
 	sb.WriteString(")\n\n")
 
 	// Generate implementation structs for each interface
@@ -1197,7 +1489,21 @@ func generateTestServerGo(idl *parser.IDL, structMap map[string]*parser.Struct,
 	fmt.Fprintf(&sb, "	server := NewPulseRPCServer(\"0.0.0.0\", 8080)\n")
 	for _, iface := range idl.Interfaces {
 		implName := iface.Name + "Impl"
-		fmt.Fprintf(&sb, "	server.Register(\"%s\", &%s{})\n", iface.Name, implName)
+		fmt.Fprintf(&sb, "	server.Register%s(&%s{})\n", iface.Name, implName)
+	}
+	if faults {
+		fmt.Fprintf(&sb, "	if path := os.Getenv(\"%s\"); path != \"\" {\n", faultsEnvVar)
+		sb.WriteString("		if err := server.LoadFaults(path); err != nil {\n")
+		sb.WriteString("			panic(err)\n")
+		sb.WriteString("		}\n")
+		sb.WriteString("	}\n")
+	}
+	if admin {
+		fmt.Fprintf(&sb, "	if token := os.Getenv(\"%s\"); token != \"\" {\n", adminTokenEnvVar)
+		sb.WriteString("		if err := server.EnableAdmin(token); err != nil {\n")
+		sb.WriteString("			panic(err)\n")
+		sb.WriteString("		}\n")
+		sb.WriteString("	}\n")
 	}
 	sb.WriteString("	if err := server.ServeForever(); err != nil {\n")
 	sb.WriteString("		panic(err)\n")
@@ -1220,16 +1526,21 @@ func writeTestInterfaceImplGo(sb *strings.Builder, iface *parser.Interface, stru
 
 // writeTestMethodImplGo generates a test method implementation
 func writeTestMethodImplGo(sb *strings.Builder, iface *parser.Interface, method *parser.Method, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) {
-	methodName := snakeToCamelCase(method.Name)
-	fmt.Fprintf(sb, "func (i *%sImpl) %s(", iface.Name, methodName)
+	methodName := naming.SnakeToPascal(method.Name)
+	// Handlers take the call's context first, so the server calls them through its
+	// typed dispatch instead of reflection
+	fmt.Fprintf(sb, "func (i *%sImpl) %s(ctx context.Context", iface.Name, methodName)
 
 	// Parameters
-	for i, param := range method.Parameters {
-		if i > 0 {
-			sb.WriteString(", ")
-		}
-		paramType := mapTypeToGoType(param.Type, structMap, enumMap, false)
-		fmt.Fprintf(sb, "%s %s", param.Name, paramType)
+	for _, param := range method.Parameters {
+		paramType := mapTypeToGoType(param.Type, structMap, enumMap, param.Optional)
+		fmt.Fprintf(sb, ", %s %s", param.Name, paramType)
+	}
+	if method.IsChunked() {
+		fmt.Fprintf(sb, ", emit %s) error {\n", goChunkedEmitType(method, structMap, enumMap))
+		sb.WriteString("	return nil\n")
+		sb.WriteString("}\n\n")
+		return
 	}
 	sb.WriteString(") ")
 
@@ -1317,8 +1628,10 @@ func writeTestMethodImplGo(sb *strings.Builder, iface *parser.Interface, method
 	sb.WriteString("}\n\n")
 }
 
-// generateTestClientGo generates test_client.go test program
-func generateTestClientGo(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) string {
+// generateTestClientGo generates test_client.go test program. When testVectors is
+// true the client also replays testvectors.json from its working directory.
+// presence is -optional-presence, which sets the type of optional struct fields.
+func generateTestClientGo(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, importPaths []string, testVectors bool, presence bool) string {
 	var sb strings.Builder
 
 	sb.WriteString("// Generated by pulserpc - do not edit\n")
@@ -1326,11 +1639,20 @@ func generateTestClientGo(idl *parser.IDL, structMap map[string]*parser.Struct,
 	sb.WriteString("package main\n\n")
 	sb.WriteString("import (\n")
 	sb.WriteString("	\"bytes\"\n")
+	if testVectors {
+		sb.WriteString("	\"encoding/json\"\n")
+	}
 	sb.WriteString("	\"fmt\"\n")
 	sb.WriteString("	\"net/http\"\n")
 	sb.WriteString("	\"os\"\n")
+	if testVectors {
+		sb.WriteString("	\"reflect\"\n")
+	}
+	sb.WriteString("	\"sync\"\n")
 	sb.WriteString("	\"time\"\n")
-	fmt.Fprintf(&sb, "	. \"pulserpc_test_go\"\n")
+	for _, importPath := range importPaths {
+		fmt.Fprintf(&sb, "	. \"%s\"\n", importPath)
+	}
 	sb.WriteString(")\n\n")
 
 	sb.WriteString("func waitForServer(url string, timeout time.Duration) bool {\n")
@@ -1378,10 +1700,15 @@ func generateTestClientGo(idl *parser.IDL, structMap map[string]*parser.Struct,
 	for _, iface := range idl.Interfaces {
 		clientVar := strings.ToLower(iface.Name) + "Client"
 		for _, method := range iface.Methods {
-			writeTestClientCallGo(&sb, iface, method, clientVar, structMap, enumMap)
+			writeTestClientCallGo(&sb, iface, method, clientVar, structMap, enumMap, presence)
 		}
 	}
 
+	sb.WriteString("	errors = append(errors, testConcurrentCalls(transport)...)\n\n")
+	if testVectors {
+		sb.WriteString("	errors = append(errors, runTestVectors(serverURL)...)\n\n")
+	}
+
 	sb.WriteString("	fmt.Println()\n")
 	sb.WriteString("	if len(errors) > 0 {\n")
 	sb.WriteString("		fmt.Fprintf(os.Stderr, \"FAILED: %d test(s) failed:\\n\", len(errors))\n")
@@ -1394,12 +1721,82 @@ func generateTestClientGo(idl *parser.IDL, structMap map[string]*parser.Struct,
 	sb.WriteString("		os.Exit(0)\n")
 	sb.WriteString("	}\n")
 	sb.WriteString("}\n")
+	writeConcurrentCallsTestGo(&sb)
+
+	if testVectors {
+		writeTestVectorReplayGo(&sb)
+	}
 
 	return sb.String()
 }
 
+// writeTestVectorReplayGo generates the functions that replay testvectors.json
+// against the server and compare each response with the expected one
+func writeTestVectorReplayGo(sb *strings.Builder) {
+	sb.WriteString("\n// runTestVectors replays testvectors.json and returns a message for each mismatch\n")
+	sb.WriteString("func runTestVectors(serverURL string) []string {\n")
+	sb.WriteString("	data, err := os.ReadFile(\"testvectors.json\")\n")
+	sb.WriteString("	if err != nil {\n")
+	sb.WriteString("		return []string{fmt.Sprintf(\"testvectors.json: %v\", err)}\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	var file struct {\n")
+	sb.WriteString("		Vectors []struct {\n")
+	sb.WriteString("			Name     string                 `json:\"name\"`\n")
+	sb.WriteString("			Request  json.RawMessage        `json:\"request\"`\n")
+	sb.WriteString("			Response map[string]interface{} `json:\"response\"`\n")
+	sb.WriteString("		} `json:\"vectors\"`\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	if err := json.Unmarshal(data, &file); err != nil {\n")
+	sb.WriteString("		return []string{fmt.Sprintf(\"testvectors.json: %v\", err)}\n")
+	sb.WriteString("	}\n\n")
+	sb.WriteString("	failures := []string{}\n")
+	sb.WriteString("	for _, v := range file.Vectors {\n")
+	sb.WriteString("		// The request is sent as written, so numbers such as 1.0 keep their form\n")
+	sb.WriteString("		resp, err := http.Post(serverURL, \"application/json\", bytes.NewReader(v.Request))\n")
+	sb.WriteString("		if err != nil {\n")
+	sb.WriteString("			failures = append(failures, fmt.Sprintf(\"vector %s: %v\", v.Name, err))\n")
+	sb.WriteString("			continue\n")
+	sb.WriteString("		}\n")
+	sb.WriteString("		var actual map[string]interface{}\n")
+	sb.WriteString("		err = json.NewDecoder(resp.Body).Decode(&actual)\n")
+	sb.WriteString("		resp.Body.Close()\n")
+	sb.WriteString("		if err != nil {\n")
+	sb.WriteString("			failures = append(failures, fmt.Sprintf(\"vector %s: invalid response: %v\", v.Name, err))\n")
+	sb.WriteString("			continue\n")
+	sb.WriteString("		}\n")
+	sb.WriteString("		if msg := compareTestVector(v.Response, actual); msg != \"\" {\n")
+	sb.WriteString("			failures = append(failures, fmt.Sprintf(\"vector %s: %s\", v.Name, msg))\n")
+	sb.WriteString("			continue\n")
+	sb.WriteString("		}\n")
+	sb.WriteString("		fmt.Printf(\"✓ vector %s passed\\n\", v.Name)\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	return failures\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// compareTestVector returns an empty string if actual satisfies expected\n")
+	sb.WriteString("func compareTestVector(expected, actual map[string]interface{}) string {\n")
+	sb.WriteString("	if !reflect.DeepEqual(expected[\"id\"], actual[\"id\"]) {\n")
+	sb.WriteString("		return fmt.Sprintf(\"expected id %v, got %v\", expected[\"id\"], actual[\"id\"])\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	if expectedErr, ok := expected[\"error\"].(map[string]interface{}); ok {\n")
+	sb.WriteString("		actualErr, ok := actual[\"error\"].(map[string]interface{})\n")
+	sb.WriteString("		if !ok || actualErr[\"code\"] != expectedErr[\"code\"] {\n")
+	sb.WriteString("			return fmt.Sprintf(\"expected error code %v, got %v\", expectedErr[\"code\"], actual)\n")
+	sb.WriteString("		}\n")
+	sb.WriteString("		return \"\"\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	if actualErr, ok := actual[\"error\"]; ok {\n")
+	sb.WriteString("		return fmt.Sprintf(\"unexpected error %v\", actualErr)\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	if expectedResult, ok := expected[\"result\"]; ok && !reflect.DeepEqual(expectedResult, actual[\"result\"]) {\n")
+	sb.WriteString("		return fmt.Sprintf(\"expected result %v, got %v\", expectedResult, actual[\"result\"])\n")
+	sb.WriteString("	}\n")
+	sb.WriteString("	return \"\"\n")
+	sb.WriteString("}\n")
+}
+
 // writeTestClientCallGo generates a test call for a method
-func writeTestClientCallGo(sb *strings.Builder, iface *parser.Interface, method *parser.Method, clientVar string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) {
+func writeTestClientCallGo(sb *strings.Builder, iface *parser.Interface, method *parser.Method, clientVar string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, presence bool) {
 	testName := fmt.Sprintf("%s.%s", iface.Name, method.Name)
 	fmt.Fprintf(sb, "	// Test %s\n", testName)
 	sb.WriteString("	func() {\n")
@@ -1412,12 +1809,17 @@ func writeTestClientCallGo(sb *strings.Builder, iface *parser.Interface, method
 	// Generate test parameters
 	params := make([]string, 0)
 	for _, param := range method.Parameters {
-		paramValue := generateTestParamValueGo(param.Type, param.Name, structMap, enumMap)
+		if param.Optional {
+			// Optional parameters are pointers; nil leaves them to the server
+			params = append(params, "nil")
+			continue
+		}
+		paramValue := generateTestParamValueGo(param.Type, param.Name, structMap, enumMap, presence)
 		params = append(params, paramValue)
 	}
 
 	// Generate method call
-	methodName := snakeToCamelCase(method.Name)
+	methodName := naming.SnakeToPascal(method.Name)
 	if len(params) > 0 {
 		fmt.Fprintf(sb, "		result, err := %s.%s(%s)\n", clientVar, methodName, strings.Join(params, ", "))
 	} else {
@@ -1460,7 +1862,7 @@ func writeTestClientCallGo(sb *strings.Builder, iface *parser.Interface, method
 }
 
 // generateTestParamValueGo generates a test parameter value
-func generateTestParamValueGo(t *parser.Type, paramName string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) string {
+func generateTestParamValueGo(t *parser.Type, paramName string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, presence bool) string {
 	if t.IsBuiltIn() {
 		switch t.BuiltIn {
 		case "string":
@@ -1500,28 +1902,34 @@ func generateTestParamValueGo(t *parser.Type, paramName string, structMap map[st
 		// Check if it's a struct
 		if structMap[t.UserDefined] != nil {
 			s := structMap[t.UserDefined]
+			if s.IsImmutable() {
+				// Immutable structs are built by their constructor, which takes every field
+				args := []string{}
+				for _, field := range parser.ResolveStructFields(s, structMap) {
+					switch {
+					case !field.Optional:
+						args = append(args, generateTestParamValueGo(field.Type, field.Name, structMap, enumMap, presence))
+					case presence:
+						args = append(args, "Optional["+mapTypeToGoType(field.Type, structMap, enumMap, false)+"]{}")
+					default:
+						args = append(args, "nil")
+					}
+				}
+				return goConstructorName(GetBaseName(t.UserDefined)) + "(" + strings.Join(args, ", ") + ")"
+			}
 			// Build struct literal
 			fields := []string{}
 			for _, field := range s.Fields {
-				if field.Optional && field.Name == "email" {
-					// Special case: set email to nil for putPerson test
-					fields = append(fields, fmt.Sprintf("%s: nil", snakeToCamelCase(field.Name)))
-				} else if !field.Optional {
-					fieldValue := generateTestParamValueGo(field.Type, field.Name, structMap, enumMap)
-					fields = append(fields, fmt.Sprintf("%s: %s", snakeToCamelCase(field.Name), fieldValue))
+				if !field.Optional {
+					fieldValue := generateTestParamValueGo(field.Type, field.Name, structMap, enumMap, presence)
+					fields = append(fields, fmt.Sprintf("%s: %s", naming.SnakeToPascal(field.Name), fieldValue))
 				}
 			}
-			// Handle inheritance
-			if s.Extends != "" {
-				baseName := GetBaseName(s.Extends)
-				if baseStruct := structMap[baseName]; baseStruct != nil {
-					for _, field := range baseStruct.Fields {
-						if !field.Optional {
-							fieldValue := generateTestParamValueGo(field.Type, field.Name, structMap, enumMap)
-							fields = append(fields, fmt.Sprintf("%s: %s", snakeToCamelCase(field.Name), fieldValue))
-						}
-					}
-				}
+			// Go cannot set promoted fields in a composite literal, so the fields a
+			// struct inherits go in a literal of the parent it embeds
+			if parent := parser.ExtendedStruct(s, structMap); parent != nil {
+				parentValue := generateTestParamValueGo(&parser.Type{UserDefined: parent.Name}, paramName, structMap, enumMap, presence)
+				fields = append(fields, fmt.Sprintf("%s: %s", GetBaseName(parent.Name), parentValue))
 			}
 			// Special handling for RepeatRequest
 			if t.UserDefined == "RepeatRequest" || GetBaseName(t.UserDefined) == "RepeatRequest" {
@@ -1529,7 +1937,8 @@ func generateTestParamValueGo(t *parser.Type, paramName string, structMap map[st
 			}
 			// Special handling for Person
 			if t.UserDefined == "Person" || GetBaseName(t.UserDefined) == "Person" {
-				return "Person{PersonId: \"person123\", FirstName: \"John\", LastName: \"Doe\", Email: nil}"
+				// Email is left out for the [optional] enforcement test
+				return "Person{PersonId: \"person123\", FirstName: \"John\", LastName: \"Doe\"}"
 			}
 			structName := GetBaseName(t.UserDefined)
 			return structName + "{" + strings.Join(fields, ", ") + "}"
@@ -1542,7 +1951,7 @@ func generateTestParamValueGo(t *parser.Type, paramName string, structMap map[st
 				}
 				enumName := GetBaseName(t.UserDefined)
 				valName := e.Values[0].Name
-				return fmt.Sprintf("%s%s", enumName, snakeToCamelCase(valName))
+				return fmt.Sprintf("%s%s", enumName, naming.SnakeToPascal(valName))
 			}
 			return "nil"
 		}
@@ -0,0 +1,906 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestGoGeneratorNamespacePackages(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	idl := &parser.IDL{
+		Structs: []*parser.Struct{
+			{
+				Name:      "inc.Response",
+				Namespace: "inc",
+				Fields:    []*parser.Field{{Name: "status", Type: &parser.Type{UserDefined: "inc.Status"}}},
+			},
+			{
+				Name:      "RepeatResponse",
+				Namespace: "conform",
+				Extends:   "inc.Response",
+				Fields:    []*parser.Field{{Name: "items", Type: &parser.Type{Array: &parser.Type{BuiltIn: "string"}}}},
+			},
+		},
+		Enums: []*parser.Enum{
+			{
+				Name:      "inc.Status",
+				Namespace: "inc",
+				Values:    []*parser.EnumValue{{Name: "ok"}},
+			},
+		},
+	}
+
+	p := NewGoClientServer()
+	fs := newTestFlagSet(t, p, tmpDir)
+	if err := fs.Set("go-packages", "true"); err != nil {
+		t.Fatalf("failed to set go-packages flag: %v", err)
+	}
+	if err := fs.Set("go-module", "example.com/acme/api"); err != nil {
+		t.Fatalf("failed to set go-module flag: %v", err)
+	}
+
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	conformCode, err := os.ReadFile(filepath.Join(tmpDir, "conform", "conform.go"))
+	if err != nil {
+		t.Fatalf("expected conform package file: %v", err)
+	}
+	for _, want := range []string{"package conform\n", "\"example.com/acme/api/inc\"", "\tinc.Response\n"} {
+		if !strings.Contains(string(conformCode), want) {
+			t.Errorf("conform.go missing %q:\n%s", want, conformCode)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "pulserpc", "types.go")); err != nil {
+		t.Errorf("expected runtime package in pulserpc/: %v", err)
+	}
+
+	allTypes, err := os.ReadFile(filepath.Join(tmpDir, "all_types.go"))
+	if err != nil {
+		t.Fatalf("expected all_types.go: %v", err)
+	}
+	for _, want := range []string{"package api\n", "RepeatResponse = conform.RepeatResponse", "StatusOk = inc.StatusOk"} {
+		if !strings.Contains(string(allTypes), want) {
+			t.Errorf("all_types.go missing %q:\n%s", want, allTypes)
+		}
+	}
+
+	discovery, err := os.ReadFile(filepath.Join(tmpDir, "discovery.go"))
+	if err != nil {
+		t.Fatalf("expected discovery.go: %v", err)
+	}
+	if !strings.Contains(string(discovery), `. "example.com/acme/api/pulserpc"`) {
+		t.Errorf("discovery.go should dot-import the runtime package:\n%s", discovery)
+	}
+}
+
+func TestGoGeneratorNamespacePackagesRequireModule(t *testing.T) {
+	p := NewGoClientServer()
+	fs := newTestFlagSet(t, p, t.TempDir())
+	if err := fs.Set("go-packages", "true"); err != nil {
+		t.Fatalf("failed to set go-packages flag: %v", err)
+	}
+	err := p.Generate(&parser.IDL{}, fs)
+	if err == nil || !strings.Contains(err.Error(), "go-module") {
+		t.Fatalf("expected go-module required error, got %v", err)
+	}
+}
+
+const goSplitIDL = `namespace shop
+
+struct Item {
+    name  string
+    price float
+}
+
+interface Catalog {
+    get(name string) Item
+}
+`
+
+const goSplitMain = `package main
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+
+	"example.com/shop/client"
+	"example.com/shop/server"
+	"example.com/shop/types"
+)
+
+type catalog struct{}
+
+func (catalog) Get(ctx context.Context, name string) (types.Item, error) {
+	return types.Item{Name: name, Price: 2.5}, nil
+}
+
+func main() {
+	s := server.NewPulseRPCServer("localhost", 0)
+	s.RegisterCatalog(catalog{})
+	httpServer := httptest.NewServer(s)
+	defer httpServer.Close()
+
+	c := client.NewCatalogClient(client.NewHTTPTransport(httpServer.URL, nil))
+	item, err := c.Get("pen")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(item.Name, item.Price)
+}
+`
+
+// TestGoGeneratorSplitPackages builds a program that serves and calls an
+// interface through the types, client and server packages of -go-split
+func TestGoGeneratorSplitPackages(t *testing.T) {
+	tmpDir := generateForTest(t, NewGoClientServer(), goSplitIDL, "-go-split", "-go-module=example.com/shop", "-generate-test-harness=true")
+
+	for _, file := range []string{"types/all_types.go", "client/client.go", "client/methods.go", "server/server.go", "server/methods.go", "server/idl.json", "server/harness_test.go"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, file)); err != nil {
+			t.Errorf("expected %s: %v", file, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "server.go")); err == nil {
+		t.Errorf("server.go should not be written to the root package")
+	}
+
+	if out, want := runGoCheck(t, tmpDir, "example.com/shop", goSplitMain), "pen 2.5"; out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestGoGeneratorPackageName(t *testing.T) {
+	tmpDir := t.TempDir()
+	p := NewGoClientServer()
+	fs := newTestFlagSet(t, p, tmpDir)
+	if err := fs.Set("go-package", "shopapi"); err != nil {
+		t.Fatalf("failed to set go-package flag: %v", err)
+	}
+	idl := &parser.IDL{
+		RootNamespace: "shop",
+		Structs:       []*parser.Struct{{Name: "Item", Namespace: "shop"}},
+	}
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	for _, file := range []string{"shop.go", "server.go", "client.go", "types.go"} {
+		code, err := os.ReadFile(filepath.Join(tmpDir, file))
+		if err != nil {
+			t.Fatalf("expected %s: %v", file, err)
+		}
+		if !strings.Contains(string(code), "package shopapi\n") {
+			t.Errorf("%s should be in package shopapi", file)
+		}
+	}
+
+	for _, flags := range []map[string]string{
+		{"go-package": "shop-api"},
+		{"go-split": "true"},
+		{"go-split": "true", "go-module": "example.com/shop", "go-package": "shopapi"},
+	} {
+		fs := newTestFlagSet(t, p, t.TempDir())
+		for name, value := range flags {
+			if err := fs.Set(name, value); err != nil {
+				t.Fatalf("failed to set %s flag: %v", name, err)
+			}
+		}
+		if err := p.Generate(idl, fs); err == nil {
+			t.Errorf("expected an error for %v", flags)
+		}
+	}
+}
+
+func TestGoGeneratorReadOnlyRoutes(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	idl := &parser.IDL{
+		Interfaces: []*parser.Interface{
+			{
+				Name: "Catalog",
+				Methods: []*parser.Method{
+					{
+						Name:        "getProduct",
+						Parameters:  []*parser.Parameter{{Name: "id", Type: &parser.Type{BuiltIn: "string"}}},
+						ReturnType:  &parser.Type{BuiltIn: "string"},
+						Annotations: []*parser.Annotation{{Name: parser.AnnotationReadOnly}},
+					},
+					{
+						Name:       "deleteProduct",
+						Parameters: []*parser.Parameter{{Name: "id", Type: &parser.Type{BuiltIn: "string"}}},
+						ReturnType: &parser.Type{BuiltIn: "bool"},
+					},
+				},
+			},
+		},
+	}
+
+	p := NewGoClientServer()
+	fs := newTestFlagSet(t, p, tmpDir)
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	serverCode, err := os.ReadFile(filepath.Join(tmpDir, "server.go"))
+	if err != nil {
+		t.Fatalf("expected server.go: %v", err)
+	}
+	for _, want := range []string{"\"/Catalog/getProduct\": {", "method: \"Catalog.getProduct\""} {
+		if !strings.Contains(string(serverCode), want) {
+			t.Errorf("server.go missing %q", want)
+		}
+	}
+	if strings.Contains(string(serverCode), "\"/Catalog/deleteProduct\"") {
+		t.Errorf("server.go should not route non-readonly method deleteProduct over GET")
+	}
+}
+
+func TestGoGeneratorMocks(t *testing.T) {
+	idl := &parser.IDL{
+		RootNamespace: "catalog",
+		Interfaces: []*parser.Interface{
+			{
+				Name: "Catalog",
+				Methods: []*parser.Method{
+					{
+						Name:           "find_product",
+						Parameters:     []*parser.Parameter{{Name: "id", Type: &parser.Type{BuiltIn: "string"}}, {Name: "limit", Type: &parser.Type{BuiltIn: "int"}}},
+						ReturnType:     &parser.Type{BuiltIn: "string"},
+						ReturnOptional: true,
+					},
+					{
+						Name:       "ping",
+						ReturnType: &parser.Type{BuiltIn: "bool"},
+					},
+				},
+			},
+		},
+	}
+
+	cases := map[string][]string{
+		"gomock": {
+			"\"go.uber.org/mock/gomock\"",
+			"var _ Catalog = (*MockCatalog)(nil)",
+			"func (m *MockCatalog) FindProduct(arg0 context.Context, arg1 string, arg2 int) (*string, error) {",
+			"ret := m.ctrl.Call(m, \"FindProduct\", arg0, arg1, arg2)",
+			"ret1, _ := ret[1].(error)\n\treturn ret0, ret1",
+			"func (mr *MockCatalogMockRecorder) FindProduct(arg0, arg1, arg2 interface{}) *gomock.Call {",
+			"func (mr *MockCatalogMockRecorder) Ping(arg0 interface{}) *gomock.Call {",
+			"reflect.TypeOf((*MockCatalog)(nil).Ping), arg0)",
+		},
+		"testify": {
+			"\"github.com/stretchr/testify/mock\"",
+			"var _ Catalog = (*MockCatalog)(nil)",
+			"args := m.Called(arg0, arg1, arg2)",
+			"if fn, ok := args.Get(0).(func(context.Context, string, int) (*string, error)); ok {",
+			"return ret0, args.Error(1)",
+			"func (m *MockCatalog) Ping(arg0 context.Context) (bool, error) {",
+			"t.Cleanup(func() { m.AssertExpectations(t) })",
+		},
+	}
+	for framework, wants := range cases {
+		tmpDir := t.TempDir()
+		p := NewGoClientServer()
+		fs := newTestFlagSet(t, p, tmpDir)
+		if err := fs.Set("go-mocks", framework); err != nil {
+			t.Fatalf("failed to set go-mocks flag: %v", err)
+		}
+		if err := p.Generate(idl, fs); err != nil {
+			t.Fatalf("%s: Generate failed: %v", framework, err)
+		}
+		mocks, err := os.ReadFile(filepath.Join(tmpDir, "mocks.go"))
+		if err != nil {
+			t.Fatalf("%s: expected mocks.go: %v", framework, err)
+		}
+		if !strings.HasPrefix(string(mocks), "//go:build !client_only\n") {
+			t.Errorf("%s: mocks.go should share server.go's build constraint", framework)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(mocks), want) {
+				t.Errorf("%s: mocks.go missing %q:\n%s", framework, want, mocks)
+			}
+		}
+	}
+
+	p := NewGoClientServer()
+	fs := newTestFlagSet(t, p, t.TempDir())
+	if err := fs.Set("go-mocks", "mockery"); err != nil {
+		t.Fatalf("failed to set go-mocks flag: %v", err)
+	}
+	if err := p.Generate(idl, fs); err == nil || !strings.Contains(err.Error(), "invalid go-mocks value") {
+		t.Errorf("expected invalid go-mocks error, got %v", err)
+	}
+}
+
+func TestGoGeneratorCloneKeepsNilMapValues(t *testing.T) {
+	address := &parser.Struct{Name: "Address", Fields: []*parser.Field{{Name: "city", Type: &parser.Type{BuiltIn: "string"}}}}
+	item := &parser.Struct{
+		Name: "Item",
+		Fields: []*parser.Field{
+			{Name: "backup", Type: &parser.Type{UserDefined: "Address"}, Optional: true},
+			{Name: "groups", Type: &parser.Type{MapValue: &parser.Type{Array: &parser.Type{UserDefined: "Address"}}}},
+		},
+	}
+	structMap := map[string]*parser.Struct{"Address": address, "Item": item}
+
+	var sb strings.Builder
+	generateStructTypesGo(&sb, []*parser.Struct{item}, structMap, map[string]*parser.Enum{}, nil, false)
+	code := sb.String()
+
+	for _, want := range []string{
+		"func (v *Item) Clone() *Item {",
+		"\tc.Backup = v.Backup.Clone()\n",
+		"\t\t\tvar c0 []Address\n",
+		"\t\t\tc.Groups[k0] = c0\n",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Clone missing %q:\n%s", want, code)
+		}
+	}
+}
+
+func TestGoGeneratorRedactsInheritedAndNestedSensitiveFields(t *testing.T) {
+	sensitive := []*parser.Annotation{{Name: parser.AnnotationSensitive}}
+	credentials := &parser.Struct{Name: "Credentials", Fields: []*parser.Field{{Name: "password", Type: &parser.Type{BuiltIn: "string"}, Annotations: sensitive}}}
+	login := &parser.Struct{Name: "Login", Extends: "Credentials", Fields: []*parser.Field{{Name: "username", Type: &parser.Type{BuiltIn: "string"}}}}
+	session := &parser.Struct{Name: "Session", Fields: []*parser.Field{{Name: "logins", Type: &parser.Type{Array: &parser.Type{UserDefined: "Login"}}}}}
+	plain := &parser.Struct{Name: "Plain", Fields: []*parser.Field{{Name: "id", Type: &parser.Type{BuiltIn: "string"}}}}
+	structMap := map[string]*parser.Struct{"Credentials": credentials, "Login": login, "Session": session, "Plain": plain}
+
+	var sb strings.Builder
+	generateStructTypesGo(&sb, []*parser.Struct{credentials, login, session, plain}, structMap, map[string]*parser.Enum{}, nil, false)
+	code := sb.String()
+
+	for _, want := range []string{
+		"Password string `json:\"password\" pulse:\"sensitive\"`",
+		"func (v Credentials) String() string {",
+		"func (v Login) String() string {",
+		"func (v Session) String() string {",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q:\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "func (v Plain) String()") {
+		t.Errorf("Plain has no sensitive fields and should not get a String method")
+	}
+}
+
+func TestGoGeneratorOptionalPresence(t *testing.T) {
+	address := &parser.Struct{Name: "Address", Fields: []*parser.Field{{Name: "city", Type: &parser.Type{BuiltIn: "string"}}}}
+	customer := &parser.Struct{
+		Name: "Customer",
+		Fields: []*parser.Field{
+			{Name: "nickname", Type: &parser.Type{BuiltIn: "string"}, Optional: true},
+			{Name: "home", Type: &parser.Type{UserDefined: "Address"}, Optional: true},
+		},
+	}
+	structMap := map[string]*parser.Struct{"Address": address, "Customer": customer}
+
+	var sb strings.Builder
+	generateStructTypesGo(&sb, []*parser.Struct{customer}, structMap, map[string]*parser.Enum{}, nil, true)
+	code := sb.String()
+
+	for _, want := range []string{
+		"Nickname Optional[string] `json:\"nickname,omitzero\"`",
+		"Home Optional[Address] `json:\"home,omitzero\"`",
+		"\tc.Home.Value = *v.Home.Value.Clone()\n",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code missing %q:\n%s", want, code)
+		}
+	}
+}
+
+func TestGoGeneratorInterfaceInheritance(t *testing.T) {
+	tmpDir := t.TempDir()
+	idl, err := parser.ParseIDL("shop.pulse", `namespace shop
+interface Health {
+  ping() string
+}
+interface Catalog extends Health {
+  getName(id string) string
+}`)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+
+	p := NewGoClientServer()
+	fs := newTestFlagSet(t, p, tmpDir)
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	serverCode, err := os.ReadFile(filepath.Join(tmpDir, "server.go"))
+	if err != nil {
+		t.Fatalf("expected server.go: %v", err)
+	}
+	for _, want := range []string{
+		"type Catalog interface {\n\tHealth\n\tGetName(ctx context.Context, id string) (string, error)\n}",
+		"\"Health\": {\"Catalog\"},",
+		"for _, sub := range subInterfaces[interfaceName] {",
+	} {
+		if !strings.Contains(string(serverCode), want) {
+			t.Errorf("server.go missing %q", want)
+		}
+	}
+
+	clientCode, err := os.ReadFile(filepath.Join(tmpDir, "client.go"))
+	if err != nil {
+		t.Fatalf("expected client.go: %v", err)
+	}
+	if !strings.Contains(string(clientCode), "\"Catalog.ping\"") {
+		t.Errorf("client.go should call inherited methods through the extending interface")
+	}
+}
+
+func TestGoGeneratorTypedefs(t *testing.T) {
+	tmpDir := t.TempDir()
+	idl, err := parser.ParseIDL("shop.pulse", `namespace shop
+// Free-form labels
+typedef Tags []string
+typedef Headers map[string]string
+struct Order {
+  tags Tags
+  headers Headers [optional]
+}
+interface Catalog {
+  retag(tags Tags) Tags
+}`)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+
+	p := NewGoClientServer()
+	fs := newTestFlagSet(t, p, tmpDir)
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	nsCode, err := os.ReadFile(filepath.Join(tmpDir, "shop.go"))
+	if err != nil {
+		t.Fatalf("expected shop.go: %v", err)
+	}
+	for _, want := range []string{
+		"// Free-form labels\ntype Tags []string\n",
+		"type Headers map[string]string\n",
+		"Tags    Tags     `json:\"tags\"`",
+		// Optional typedefs are pointers, so an empty one is not dropped by omitempty
+		"Headers *Headers `json:\"headers,omitempty\"`",
+	} {
+		if !strings.Contains(string(nsCode), want) {
+			t.Errorf("shop.go missing %q", want)
+		}
+	}
+
+	serverCode, err := os.ReadFile(filepath.Join(tmpDir, "server.go"))
+	if err != nil {
+		t.Fatalf("expected server.go: %v", err)
+	}
+	if !strings.Contains(string(serverCode), "Retag(ctx context.Context, tags Tags) (Tags, error)") {
+		t.Errorf("server.go should use the typedef name in method signatures")
+	}
+}
+
+func TestGoGeneratorOptionalParams(t *testing.T) {
+	tmpDir := t.TempDir()
+	idl, err := parser.ParseIDL("shop.pulse", `namespace shop
+enum Order {
+  asc
+  desc
+}
+interface Search {
+  find(query string, limit int [default="10"], order Order [default="desc"], cursor string [optional]) []string
+}`)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+
+	p := NewGoClientServer()
+	fs := newTestFlagSet(t, p, tmpDir)
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	serverCode, err := os.ReadFile(filepath.Join(tmpDir, "server.go"))
+	if err != nil {
+		t.Fatalf("expected server.go: %v", err)
+	}
+	for _, want := range []string{
+		"Find(ctx context.Context, query string, limit *int, order *Order, cursor *string) ([]string, error)",
+		`fmt.Sprintf("%d to %d", required, len(expectedParams))`,
+	} {
+		if !strings.Contains(string(serverCode), want) {
+			t.Errorf("server.go missing %q", want)
+		}
+	}
+
+	methodsCode, err := os.ReadFile(filepath.Join(tmpDir, "methods.go"))
+	if err != nil {
+		t.Fatalf("expected methods.go: %v", err)
+	}
+	for _, want := range []string{`"optional": true,`, `"default":  float64(10),`, `"default":  "desc",`} {
+		if !strings.Contains(string(methodsCode), want) {
+			t.Errorf("methods.go missing %q", want)
+		}
+	}
+
+	clientCode, err := os.ReadFile(filepath.Join(tmpDir, "client.go"))
+	if err != nil {
+		t.Fatalf("expected client.go: %v", err)
+	}
+	if !strings.Contains(string(clientCode), "Find(query string, limit *int, order *Order, cursor *string, opts ...CallOption)") {
+		t.Errorf("client.go should take optional parameters as pointers")
+	}
+}
+
+const goOptionalDefaultsMain = `package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	shop "example.com/shop"
+)
+
+type search struct{}
+
+func (search) Find(ctx context.Context, query string, limit *int, ratio *float64, order *shop.Order) (string, error) {
+	return fmt.Sprintf("%s %d %g %s", query, *limit, *ratio, *order), nil
+}
+
+func main() {
+	server := shop.NewPulseRPCServer("localhost", 0)
+	server.RegisterSearch(search{})
+	for _, params := range []string{` + "`" + `["books"]` + "`" + `, ` + "`" + `["books", 5, null, "asc"]` + "`" + `, ` + "`" + `{"query": "books", "ratio": 2}` + "`" + `} {
+		var decoded interface{}
+		json.Unmarshal([]byte(params), &decoded)
+		response := server.HandleRequest(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "Search.find", "params": decoded})
+		data, _ := json.Marshal(response)
+		fmt.Println(string(data))
+	}
+}
+`
+
+// TestGoOptionalParamDefaults checks that a server fills in the int, float and
+// enum defaults of params a call leaves out
+func TestGoOptionalParamDefaults(t *testing.T) {
+	tmpDir := generateForTest(t, NewGoClientServer(), `namespace shop
+enum Order {
+  asc
+  desc
+}
+interface Search {
+  find(query string, limit int [default="20"], ratio float [default="0.5"], order Order [default="desc"]) string
+}`)
+	out := runGoCheck(t, tmpDir, "example.com/shop", goOptionalDefaultsMain)
+	want := strings.Join([]string{
+		`{"id":1,"jsonrpc":"2.0","result":"books 20 0.5 desc"}`,
+		`{"id":1,"jsonrpc":"2.0","result":"books 5 0.5 asc"}`,
+		`{"id":1,"jsonrpc":"2.0","result":"books 20 2 desc"}`,
+	}, "\n")
+	if out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+const goOptionalZeroMain = `package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	shop "example.com/shop"
+)
+
+type profiles struct{}
+
+func (profiles) Save(ctx context.Context, p shop.Profile) (shop.Profile, error) {
+	return p, nil
+}
+
+func main() {
+	zero, no := 0, false
+	labels, attrs, tags := []string{}, map[string]int{}, shop.Tags{}
+	for _, p := range []shop.Profile{
+		{Name: "zero", Count: &zero, Active: &no, Labels: &labels, Attrs: &attrs, Tags: &tags},
+		{Name: "absent"},
+	} {
+		data, err := json.Marshal(p)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(data))
+		var decoded shop.Profile
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			panic(err)
+		}
+		fmt.Println(reflect.DeepEqual(p, decoded))
+	}
+
+	// The server decodes the zero values into non-nil pointers and sends them back
+	server := shop.NewPulseRPCServer("localhost", 0)
+	server.RegisterProfiles(profiles{})
+	var params interface{}
+	json.Unmarshal([]byte(` + "`" + `[{"name": "zero", "count": 0, "active": false, "labels": [], "attrs": {}, "tags": []}]` + "`" + `), &params)
+	response := server.HandleRequest(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "Profiles.save", "params": params})
+	data, _ := json.Marshal(response["result"])
+	fmt.Println(string(data))
+}
+`
+
+// TestGoOptionalZeroValues checks that optional fields holding zero values, or
+// empty arrays and maps, survive encoding, decoding and a server round trip
+func TestGoOptionalZeroValues(t *testing.T) {
+	tmpDir := generateForTest(t, NewGoClientServer(), `namespace shop
+typedef Tags []string
+struct Profile {
+  name string
+  count int [optional]
+  active bool [optional]
+  labels []string [optional]
+  attrs map[string]int [optional]
+  tags Tags [optional]
+}
+interface Profiles {
+  save(p Profile) Profile
+}`)
+	out := runGoCheck(t, tmpDir, "example.com/shop", goOptionalZeroMain)
+	zero := `{"name":"zero","count":0,"active":false,"labels":[],"attrs":{},"tags":[]}`
+	want := zero + "\ntrue\n" + `{"name":"absent"}` + "\ntrue\n" + zero
+	if out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestGoGeneratorNamedParams(t *testing.T) {
+	tmpDir := t.TempDir()
+	idl, err := parser.ParseIDL("shop.pulse", `namespace shop
+interface Search {
+  find(query string, limit int [optional]) []string
+}`)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+
+	p := NewGoClientServer()
+	fs := newTestFlagSet(t, p, tmpDir)
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	serverCode, err := os.ReadFile(filepath.Join(tmpDir, "server.go"))
+	if err != nil {
+		t.Fatalf("expected server.go: %v", err)
+	}
+	for _, want := range []string{
+		"func paramsByName(named map[string]interface{}, expectedParams []interface{}) ([]interface{}, error)",
+		`requestJson["params"].(map[string]interface{})`,
+	} {
+		if !strings.Contains(string(serverCode), want) {
+			t.Errorf("server.go missing %q", want)
+		}
+	}
+
+	clientCode, err := os.ReadFile(filepath.Join(tmpDir, "client.go"))
+	if err != nil {
+		t.Fatalf("expected client.go: %v", err)
+	}
+	for _, want := range []string{
+		"func WithNamedParams() CallOption",
+		`options.ParamNames = []string{"query", "limit"}`,
+		`"params":  requestParams(params, options),`,
+	} {
+		if !strings.Contains(string(clientCode), want) {
+			t.Errorf("client.go missing %q", want)
+		}
+	}
+}
+
+func TestGoGeneratorResponseMeta(t *testing.T) {
+	tmpDir := t.TempDir()
+	idl, err := parser.ParseIDL("shop.pulse", `namespace shop
+interface Search {
+  find(query string) []string
+}`)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+
+	p := NewGoClientServer()
+	fs := newTestFlagSet(t, p, tmpDir)
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	serverCode, err := os.ReadFile(filepath.Join(tmpDir, "server.go"))
+	if err != nil {
+		t.Fatalf("expected server.go: %v", err)
+	}
+	for _, want := range []string{
+		"func (s *PulseRPCServer) SetResponseMeta(hook func(ResponseMetaCall) map[string]interface{})",
+		"response.Meta = meta",
+	} {
+		if !strings.Contains(string(serverCode), want) {
+			t.Errorf("server.go missing %q", want)
+		}
+	}
+
+	clientCode, err := os.ReadFile(filepath.Join(tmpDir, "client.go"))
+	if err != nil {
+		t.Fatalf("expected client.go: %v", err)
+	}
+	for _, want := range []string{
+		"func CallWithMeta[T any](call func(opt CallOption) (T, error)) (CallResult[T], error)",
+		`response["meta"].(map[string]interface{})`,
+	} {
+		if !strings.Contains(string(clientCode), want) {
+			t.Errorf("client.go missing %q", want)
+		}
+	}
+}
+
+func TestGoGeneratorWireNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	idl, err := parser.ParseIDL("users.pulse", `namespace users
+interface Users [wire="v1.user"] {
+  get(id string) string
+  remove(id string) bool [wire="v1.user.delete"]
+}`)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+
+	p := NewGoClientServer()
+	fs := newTestFlagSet(t, p, tmpDir)
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	serverCode, err := os.ReadFile(filepath.Join(tmpDir, "server.go"))
+	if err != nil {
+		t.Fatalf("expected server.go: %v", err)
+	}
+	for _, want := range []string{
+		"var wireMethods = map[string]string{",
+		`"v1.user.get":    "Users.get",`,
+		`"v1.user.delete": "Users.remove",`,
+		"if name, ok := wireMethods[method]; ok {",
+	} {
+		if !strings.Contains(string(serverCode), want) {
+			t.Errorf("server.go missing %q", want)
+		}
+	}
+
+	clientCode, err := os.ReadFile(filepath.Join(tmpDir, "client.go"))
+	if err != nil {
+		t.Fatalf("expected client.go: %v", err)
+	}
+	for _, want := range []string{
+		`methodName := "v1.user.get"`,
+		`methodName := "v1.user.delete"`,
+	} {
+		if !strings.Contains(string(clientCode), want) {
+			t.Errorf("client.go missing %q", want)
+		}
+	}
+}
+
+func TestGoGeneratorAsyncMethods(t *testing.T) {
+	tmpDir := t.TempDir()
+	idl, err := parser.ParseIDL("shop.pulse", `namespace shop
+interface Search {
+  find(query string) []string
+  export(name string) string [async]
+}`)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+
+	p := NewGoClientServer()
+	fs := newTestFlagSet(t, p, tmpDir)
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	serverCode, err := os.ReadFile(filepath.Join(tmpDir, "server.go"))
+	if err != nil {
+		t.Fatalf("expected server.go: %v", err)
+	}
+	for _, want := range []string{
+		`if method == "pulserpc-job" {`,
+		"return s.startJob(requestJson, requestID, isNotification)",
+		`methodDef["async"] == true`,
+	} {
+		if !strings.Contains(string(serverCode), want) {
+			t.Errorf("server.go missing %q", want)
+		}
+	}
+
+	clientCode, err := os.ReadFile(filepath.Join(tmpDir, "client.go"))
+	if err != nil {
+		t.Fatalf("expected client.go: %v", err)
+	}
+	if n := strings.Count(string(clientCode), "awaitJob(c.transport, response, options)"); n != 1 {
+		t.Errorf("expected only the async method to await its job, found %d calls", n)
+	}
+}
+
+func TestGoGeneratorTransportWarmup(t *testing.T) {
+	tmpDir := t.TempDir()
+	idl, err := parser.ParseIDL("shop.pulse", `namespace shop
+interface Search {
+  find(query string) []string
+}`)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+
+	p := NewGoClientServer()
+	fs := newTestFlagSet(t, p, tmpDir)
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	clientCode, err := os.ReadFile(filepath.Join(tmpDir, "client.go"))
+	if err != nil {
+		t.Fatalf("expected client.go: %v", err)
+	}
+	for _, want := range []string{
+		"func (t *HTTPTransport) Warmup(timeout time.Duration, ping bool) error",
+		`t.CallWithOptions("pulserpc-idl", nil, CallOptions{Timeout: timeout})`,
+		`http.NewRequestWithContext(ctx, "OPTIONS", t.baseURL, nil)`,
+	} {
+		if !strings.Contains(string(clientCode), want) {
+			t.Errorf("client.go missing %q", want)
+		}
+	}
+}
+
+func TestGoGeneratorAPIClient(t *testing.T) {
+	tests := []struct {
+		name   string
+		idl    string
+		facade bool
+	}{
+		{"all interfaces", "namespace shop\ninterface Search {\n  find(query string) []string\n}\ninterface Cart {\n  add(sku string) bool\n}", true},
+		{"interface named Api", "namespace shop\ninterface Api {\n  find(query string) []string\n}", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			idl, err := parser.ParseIDL("shop.pulse", tt.idl)
+			if err != nil {
+				t.Fatalf("ParseIDL failed: %v", err)
+			}
+
+			p := NewGoClientServer()
+			fs := newTestFlagSet(t, p, tmpDir)
+			if err := p.Generate(idl, fs); err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+
+			clientCode, err := os.ReadFile(filepath.Join(tmpDir, "client.go"))
+			if err != nil {
+				t.Fatalf("expected client.go: %v", err)
+			}
+			code := string(clientCode)
+			if got := strings.Contains(code, "type APIClient struct"); got != tt.facade {
+				t.Fatalf("APIClient generated = %v, want %v", got, tt.facade)
+			}
+			if tt.facade && !strings.Contains(code, "Cart:   NewCartClient(transport),") {
+				t.Errorf("APIClient does not create the Cart client:\n%s", code[strings.Index(code, "type APIClient"):])
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package generator
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+	"github.com/coopernurse/pulserpc/pkg/runtime"
+)
+
+// update rewrites the golden files instead of comparing against them:
+//
+//	go test ./pkg/generator -run TestGolden -update
+var update = flag.Bool("update", false, "update golden files in testdata/golden")
+
+// goldenFixture is an IDL file rendered by every plugin
+type goldenFixture struct {
+	name string
+	idl  string
+	// flags are set on every plugin's FlagSet before generating
+	flags map[string]string
+}
+
+var goldenFixtures = []goldenFixture{
+	{
+		name:  "conform",
+		idl:   "../../examples/conform.pulse",
+		flags: map[string]string{"generate-test-files": "true", "generate-test-vectors": "true", "generate-test-harness": "true", "generate-contract-tests": "true", "generate-shadow-client": "true", "generate-outbox-client": "true", "generate-broker-transport": "true", "generate-serverless-adapter": "true", "generate-fault-injection": "true", "generate-admin-endpoint": "true", "generate-patch-helpers": "true", "optional-presence": "true"},
+	},
+	{
+		name: "book",
+		idl:  "../../examples/book.pulse",
+	},
+}
+
+// goldenPlugin is a plugin under test and the runtime it copies into the output
+type goldenPlugin struct {
+	plugin  Plugin
+	runtime string
+	flags   map[string]string
+}
+
+func goldenPlugins() []goldenPlugin {
+	return []goldenPlugin{
+		{plugin: NewGoClientServer(), runtime: "go", flags: map[string]string{"generate-index-files": "true"}},
+		{plugin: NewPythonClientServer(), runtime: "python"},
+		{plugin: NewTSClientServer(), runtime: "ts"},
+		{plugin: NewCSharpClientServer(), runtime: "csharp", flags: map[string]string{"generate-index-files": "true"}},
+		{plugin: NewJavaClientServer(), runtime: "java", flags: map[string]string{"base-package": "com.example.server", "generate-index-files": "true"}},
+		{plugin: NewRustClientServer(), runtime: "rust"},
+		{plugin: NewLoadTest()},
+		{plugin: NewCollection()},
+		{plugin: NewExamples()},
+		{plugin: NewRoutes()},
+		{plugin: NewJSONSchema()},
+		{plugin: NewJSBrowserClient()},
+		{plugin: NewProtobuf()},
+		{plugin: NewGraphQL(), flags: map[string]string{"graphql-go-import": "example.com/gen/api"}},
+		{plugin: NewDocs()},
+	}
+}
+
+// TestGolden renders every plugin against the fixture IDLs and compares the output
+// with testdata/golden/<fixture>/<plugin>. Runtime library files copied into the
+// output are skipped since they are checked in under pkg/runtime already.
+func TestGolden(t *testing.T) {
+	for _, fixture := range goldenFixtures {
+		content, err := os.ReadFile(fixture.idl)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", fixture.idl, err)
+		}
+		idl, err := parser.ParseIDL(fixture.idl, string(content))
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", fixture.idl, err)
+		}
+
+		for _, gp := range goldenPlugins() {
+			fixture, gp := fixture, gp
+			t.Run(fixture.name+"/"+gp.plugin.Name(), func(t *testing.T) {
+				outDir := t.TempDir()
+				fs := flag.NewFlagSet("golden", flag.ContinueOnError)
+				fs.String("dir", "", "output dir")
+				fs.Bool("generate-test-files", false, "generate test files")
+				fs.Bool("generate-test-vectors", false, "generate test vectors")
+				fs.Bool("generate-test-harness", false, "generate test harness")
+				fs.Bool("generate-contract-tests", false, "generate contract tests")
+				fs.Bool("generate-shadow-client", false, "generate shadow client")
+				fs.Bool("generate-outbox-client", false, "generate outbox client")
+				fs.Bool("generate-broker-transport", false, "generate broker transport")
+				fs.Bool("generate-serverless-adapter", false, "generate serverless adapter")
+				fs.Bool("generate-fault-injection", false, "generate fault injection")
+				fs.Bool("generate-admin-endpoint", false, "generate admin endpoint")
+				fs.Bool("generate-patch-helpers", false, "generate patch helpers")
+				fs.Bool("generate-index-files", false, "generate index files")
+				fs.Bool("optional-presence", false, "optional presence")
+				gp.plugin.RegisterFlags(fs)
+				setGoldenFlags(t, fs, map[string]string{"dir": outDir})
+				setGoldenFlags(t, fs, fixture.flags)
+				setGoldenFlags(t, fs, gp.flags)
+
+				if err := gp.plugin.Generate(idl, fs); err != nil {
+					t.Fatalf("generate failed: %v", err)
+				}
+
+				actual := readGoldenTree(t, outDir, runtimeFileNames(t, gp.runtime))
+				goldenDir := filepath.Join("testdata", "golden", fixture.name, gp.plugin.Name())
+				if *update {
+					writeGoldenTree(t, goldenDir, actual)
+					return
+				}
+				compareGoldenTree(t, goldenDir, actual)
+			})
+		}
+	}
+}
+
+func setGoldenFlags(t *testing.T, fs *flag.FlagSet, values map[string]string) {
+	t.Helper()
+	for name, value := range values {
+		if err := fs.Set(name, value); err != nil {
+			t.Fatalf("failed to set -%s: %v", name, err)
+		}
+	}
+}
+
+// runtimeFileNames returns the base names of the files in an embedded runtime
+func runtimeFileNames(t *testing.T, lang string) map[string]bool {
+	t.Helper()
+	if lang == "" {
+		return nil
+	}
+	files, err := runtime.GetRuntimeFiles(lang)
+	if err != nil {
+		t.Fatalf("failed to list %s runtime: %v", lang, err)
+	}
+	names := make(map[string]bool, len(files))
+	for name := range files {
+		names[filepath.Base(name)] = true
+	}
+	return names
+}
+
+// readGoldenTree returns the files under dir keyed by slash-separated relative path,
+// skipping files named like a runtime library file
+func readGoldenTree(t *testing.T, dir string, skip map[string]bool) map[string][]byte {
+	t.Helper()
+	files := make(map[string][]byte)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || skip[info.Name()] {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", dir, err)
+	}
+	return files
+}
+
+func writeGoldenTree(t *testing.T, dir string, files map[string][]byte) {
+	t.Helper()
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("failed to clear %s: %v", dir, err)
+	}
+	for rel, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+}
+
+func compareGoldenTree(t *testing.T, dir string, actual map[string][]byte) {
+	t.Helper()
+	if _, err := os.Stat(dir); err != nil {
+		t.Fatalf("golden directory %s is missing; run with -update to create it", dir)
+	}
+	expected := readGoldenTree(t, dir, nil)
+
+	for _, rel := range sortedKeys(expected) {
+		got, ok := actual[rel]
+		if !ok {
+			t.Errorf("%s: expected file was not generated", rel)
+			continue
+		}
+		if !bytes.Equal(expected[rel], got) {
+			t.Errorf("%s: output differs from golden file: %s", rel, firstDiff(expected[rel], got))
+		}
+	}
+	for _, rel := range sortedKeys(actual) {
+		if _, ok := expected[rel]; !ok {
+			t.Errorf("%s: generated file has no golden file", rel)
+		}
+	}
+	if t.Failed() {
+		t.Logf("if the change is intended, run: go test ./pkg/generator -run TestGolden -update")
+	}
+}
+
+func sortedKeys(files map[string][]byte) []string {
+	keys := make([]string, 0, len(files))
+	for k := range files {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// firstDiff describes the first line that differs between expected and actual
+func firstDiff(expected, actual []byte) string {
+	expectedLines := strings.Split(string(expected), "\n")
+	actualLines := strings.Split(string(actual), "\n")
+	for i := 0; i < len(expectedLines) || i < len(actualLines); i++ {
+		var e, a string
+		if i < len(expectedLines) {
+			e = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			a = actualLines[i]
+		}
+		if e != a || i >= len(expectedLines) || i >= len(actualLines) {
+			return fmt.Sprintf("line %d\n  want: %q\n  got:  %q", i+1, e, a)
+		}
+	}
+	return "no line differs"
+}
@@ -0,0 +1,407 @@
+package generator
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/naming"
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// The graphql plugin exposes the IDL over GraphQL. It writes schema.graphql and,
+// with -graphql-go-import, resolvers.go, a scaffold that resolves the fields with
+// the handlers of the Go server:
+//
+//   - Structs become object types. Those passed as params, directly or through
+//     their fields, also become <Name>Input input types, as GraphQL arguments
+//     cannot be object types. A struct that extends another repeats its fields.
+//   - Enums become enums with the same values, which are also their JSON form.
+//   - [readonly] methods become Query fields and the others Mutation fields,
+//     named <interface><Method>, e.g. catalogGetProduct, with the params as
+//     arguments. [optional] params, fields and results are nullable.
+//   - Maps become the JSON scalar, as GraphQL has no map type.
+//
+// GraphQL names are global, so types are named by their base name and names that
+// collide across namespaces are an error. IDL ints become Int, which GraphQL
+// limits to 32 bits.
+
+// GraphQL generates a GraphQL schema and a Go resolver scaffold
+type GraphQL struct {
+}
+
+// NewGraphQL creates a new GraphQL plugin instance
+func NewGraphQL() *GraphQL {
+	return &GraphQL{}
+}
+
+// Name returns the plugin identifier
+func (p *GraphQL) Name() string {
+	return "graphql"
+}
+
+// RegisterFlags registers CLI flags for this plugin
+func (p *GraphQL) RegisterFlags(fs *flag.FlagSet) {
+	fs.String("graphql-go-import", "", "Import path of the package generated by go-client-server; when set, resolvers.go is written, resolving the fields with its handlers")
+	fs.String("graphql-go-package", "graphqlapi", "Package name of resolvers.go")
+}
+
+// Generate writes schema.graphql and, with -graphql-go-import, resolvers.go
+func (p *GraphQL) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
+	outputDir := ""
+	if dirFlag := fs.Lookup("dir"); dirFlag != nil {
+		outputDir = dirFlag.Value.String()
+	}
+	if err := CheckBaseNameCollisions(idl); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	schema, err := BuildGraphQLSchema(idl)
+	if err != nil {
+		return err
+	}
+	if err := writeGeneratedFile(filepath.Join(outputDir, "schema.graphql"), []byte(schema)); err != nil {
+		return fmt.Errorf("failed to write schema.graphql: %w", err)
+	}
+
+	importPath := fs.Lookup("graphql-go-import").Value.String()
+	if importPath == "" {
+		return nil
+	}
+	pkg := fs.Lookup("graphql-go-package").Value.String()
+	if !isGoIdentifier(pkg) {
+		return fmt.Errorf("invalid graphql-go-package value: %q (must be a Go package name)", pkg)
+	}
+	code := generateGraphQLResolversGo(idl, pkg, importPath)
+	if err := writeGeneratedFile(filepath.Join(outputDir, "resolvers.go"), []byte(code)); err != nil {
+		return fmt.Errorf("failed to write resolvers.go: %w", err)
+	}
+	return nil
+}
+
+// isGoIdentifier reports whether s is a valid Go identifier
+func isGoIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		letter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if !letter && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// graphQLFieldName returns the name of the Query or Mutation field of a method
+func graphQLFieldName(iface *parser.Interface, method *parser.Method) string {
+	return naming.LowerFirst(GetBaseName(iface.Name)) + naming.SnakeToPascal(method.Name)
+}
+
+// graphQLSchemaBuilder writes the types of an IDL as GraphQL
+type graphQLSchemaBuilder struct {
+	structs map[string]*parser.Struct
+	enums   map[string]*parser.Enum
+	// inputs holds the structs that need an input type
+	inputs map[string]bool
+	// usesJSON is set once a type maps to the JSON scalar
+	usesJSON bool
+}
+
+// BuildGraphQLSchema returns the GraphQL schema of the IDL. It fails when the name of
+// an input type is taken by a struct or enum.
+func BuildGraphQLSchema(idl *parser.IDL) (string, error) {
+	b := &graphQLSchemaBuilder{
+		structs: make(map[string]*parser.Struct),
+		enums:   make(map[string]*parser.Enum),
+		inputs:  make(map[string]bool),
+	}
+	for _, s := range idl.Structs {
+		b.structs[s.Name] = s
+	}
+	for _, e := range idl.Enums {
+		b.enums[e.Name] = e
+	}
+	for _, iface := range idl.Interfaces {
+		for _, method := range iface.Methods {
+			for _, param := range method.Parameters {
+				b.markInputs(param.Type)
+			}
+		}
+	}
+	for _, s := range idl.Structs {
+		if !b.inputs[s.Name] {
+			continue
+		}
+		input := GetBaseName(s.Name) + "Input"
+		for _, other := range idl.Structs {
+			if GetBaseName(other.Name) == input {
+				return "", fmt.Errorf("struct %s is passed as a param, and its input type %s collides with struct %s", s.Name, input, other.Name)
+			}
+		}
+		for _, e := range idl.Enums {
+			if GetBaseName(e.Name) == input {
+				return "", fmt.Errorf("struct %s is passed as a param, and its input type %s collides with enum %s", s.Name, input, e.Name)
+			}
+		}
+	}
+
+	var body strings.Builder
+	for _, e := range idl.Enums {
+		body.WriteString("\n")
+		writeGraphQLDescription(&body, "", e.Comment)
+		fmt.Fprintf(&body, "enum %s {\n", GetBaseName(e.Name))
+		for _, v := range e.Values {
+			writeGraphQLDescription(&body, "  ", v.Comment)
+			fmt.Fprintf(&body, "  %s\n", v.Name)
+		}
+		body.WriteString("}\n")
+	}
+	for _, s := range idl.Structs {
+		b.writeStruct(&body, s, false)
+	}
+	for _, s := range idl.Structs {
+		if b.inputs[s.Name] {
+			b.writeStruct(&body, s, true)
+		}
+	}
+
+	var queries, mutations []string
+	for _, iface := range idl.Interfaces {
+		for _, method := range iface.Methods {
+			field := b.operationField(iface, method)
+			if method.IsReadOnly() {
+				queries = append(queries, field)
+			} else {
+				mutations = append(mutations, field)
+			}
+		}
+	}
+	if len(queries) == 0 {
+		// A schema needs a Query type with at least one field
+		queries = append(queries, "  \"Always null: the IDL has no [readonly] methods, and a schema needs a Query type\"\n  _empty: Boolean\n")
+	}
+	body.WriteString("\ntype Query {\n" + strings.Join(queries, "") + "}\n")
+	if len(mutations) > 0 {
+		body.WriteString("\ntype Mutation {\n" + strings.Join(mutations, "") + "}\n")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Generated by pulserpc - do not edit\n")
+	if b.usesJSON {
+		sb.WriteString("\n\"A JSON value, used for maps\"\nscalar JSON\n")
+	}
+	sb.WriteString(body.String())
+	return sb.String(), nil
+}
+
+// markInputs marks the structs a param of type t holds as needing input types
+func (b *graphQLSchemaBuilder) markInputs(t *parser.Type) {
+	switch {
+	case t == nil:
+	case t.IsArray():
+		b.markInputs(t.Array)
+	case t.IsMap():
+		b.markInputs(t.MapValue)
+	case t.IsUserDefined():
+		s, ok := b.structs[t.UserDefined]
+		if !ok || b.inputs[s.Name] {
+			return
+		}
+		b.inputs[s.Name] = true
+		for _, field := range parser.ResolveStructFields(s, b.structs) {
+			b.markInputs(field.Type)
+		}
+	}
+}
+
+// writeStruct writes the object type of a struct, or its input type if input
+func (b *graphQLSchemaBuilder) writeStruct(sb *strings.Builder, s *parser.Struct, input bool) {
+	sb.WriteString("\n")
+	writeGraphQLDescription(sb, "", s.Comment)
+	if input {
+		fmt.Fprintf(sb, "input %sInput {\n", GetBaseName(s.Name))
+	} else {
+		fmt.Fprintf(sb, "type %s {\n", GetBaseName(s.Name))
+	}
+	for _, field := range parser.ResolveStructFields(s, b.structs) {
+		writeGraphQLDescription(sb, "  ", field.Comment)
+		fmt.Fprintf(sb, "  %s: %s\n", field.Name, b.typeRef(field.Type, field.Optional, input))
+	}
+	sb.WriteString("}\n")
+}
+
+// operationField returns the Query or Mutation field of a method
+func (b *graphQLSchemaBuilder) operationField(iface *parser.Interface, method *parser.Method) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "  \"Calls %s\"\n", iface.RPCName(method))
+	sb.WriteString("  " + graphQLFieldName(iface, method))
+	if len(method.Parameters) > 0 {
+		args := make([]string, len(method.Parameters))
+		for i, param := range method.Parameters {
+			args[i] = param.Name + ": " + b.typeRef(param.Type, param.Optional, true)
+		}
+		sb.WriteString("(" + strings.Join(args, ", ") + ")")
+	}
+	fmt.Fprintf(&sb, ": %s\n", b.typeRef(method.ReturnType, method.ReturnOptional, false))
+	return sb.String()
+}
+
+// typeRef returns the GraphQL type of a value of type t; input selects the input
+// types of structs
+func (b *graphQLSchemaBuilder) typeRef(t *parser.Type, optional, input bool) string {
+	var ref string
+	switch {
+	case t == nil:
+		b.usesJSON = true
+		ref = "JSON"
+	case t.IsArray():
+		ref = "[" + b.typeRef(t.Array, false, input) + "]"
+	case t.IsMap():
+		b.usesJSON = true
+		ref = "JSON"
+	case t.IsUserDefined():
+		if _, ok := b.structs[t.UserDefined]; ok && input {
+			ref = GetBaseName(t.UserDefined) + "Input"
+		} else if _, ok := b.enums[t.UserDefined]; ok || b.structs[t.UserDefined] != nil {
+			ref = GetBaseName(t.UserDefined)
+		} else {
+			// An unresolved name, such as an interface, holds any value
+			b.usesJSON = true
+			ref = "JSON"
+		}
+	default:
+		switch t.BuiltIn {
+		case "int":
+			ref = "Int"
+		case "float":
+			ref = "Float"
+		case "bool":
+			ref = "Boolean"
+		default:
+			ref = "String"
+		}
+	}
+	if optional {
+		return ref
+	}
+	return ref + "!"
+}
+
+// writeGraphQLDescription writes comment as a block string description
+func writeGraphQLDescription(sb *strings.Builder, indent, comment string) {
+	if comment == "" {
+		return
+	}
+	sb.WriteString(indent + "\"\"\"\n")
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.ReplaceAll(line, `"""`, `\"""`)
+		sb.WriteString(strings.TrimRight(indent+line, " ") + "\n")
+	}
+	sb.WriteString(indent + "\"\"\"\n")
+}
+
+// generateGraphQLResolversGo returns resolvers.go: a Resolver holding a handler per
+// interface, with the resolve functions of the Query and Mutation fields. The
+// functions take the arguments as decoded by the GraphQL server, so any GraphQL
+// library can call them.
+func generateGraphQLResolversGo(idl *parser.IDL, pkg, importPath string) string {
+	structMap := make(map[string]*parser.Struct)
+	enumMap := make(map[string]*parser.Enum)
+	for _, s := range idl.Structs {
+		structMap[s.Name] = s
+	}
+	for _, e := range idl.Enums {
+		enumMap[e.Name] = e
+	}
+	qualify := func(name string) string { return "api." + GetBaseName(name) }
+
+	var sb strings.Builder
+	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
+	fmt.Fprintf(&sb, "// Package %s resolves the Query and Mutation fields of schema.graphql by calling\n", pkg)
+	sb.WriteString("// the handlers of the PulseRPC server. It does not depend on a GraphQL library:\n")
+	sb.WriteString("// register the functions Query and Mutation return as the resolvers of the fields\n")
+	sb.WriteString("// of the GraphQL server you use.\n")
+	fmt.Fprintf(&sb, "package %s\n\n", pkg)
+	sb.WriteString("import (\n\t\"context\"\n\t\"encoding/json\"\n\t\"fmt\"\n\n")
+	fmt.Fprintf(&sb, "\tapi %q\n)\n\n", importPath)
+
+	sb.WriteString("// FieldResolver resolves a field from its arguments by name, as decoded by the\n")
+	sb.WriteString("// GraphQL server\n")
+	sb.WriteString("type FieldResolver func(ctx context.Context, args map[string]interface{}) (interface{}, error)\n\n")
+
+	sb.WriteString("// Resolver resolves the fields with the handlers of the interfaces. The fields of an\n")
+	sb.WriteString("// interface without a handler resolve to an error.\n")
+	sb.WriteString("type Resolver struct {\n")
+	for _, iface := range idl.Interfaces {
+		name := GetBaseName(iface.Name)
+		fmt.Fprintf(&sb, "\t%s api.%s\n", name, name)
+	}
+	sb.WriteString("}\n\n")
+
+	var queries, mutations []string
+	var resolvers strings.Builder
+	for _, iface := range idl.Interfaces {
+		name := GetBaseName(iface.Name)
+		for _, method := range iface.Methods {
+			field := graphQLFieldName(iface, method)
+			entry := fmt.Sprintf("\t\t%q: r.%s,\n", field, field)
+			if method.IsReadOnly() {
+				queries = append(queries, entry)
+			} else {
+				mutations = append(mutations, entry)
+			}
+
+			fmt.Fprintf(&resolvers, "// %s resolves the %s field by calling %s\n", field, field, iface.RPCName(method))
+			fmt.Fprintf(&resolvers, "func (r *Resolver) %s(ctx context.Context, args map[string]interface{}) (interface{}, error) {\n", field)
+			fmt.Fprintf(&resolvers, "\tif r.%s == nil {\n\t\treturn nil, fmt.Errorf(\"no handler for interface %s\")\n\t}\n", name, name)
+			args := []string{"ctx"}
+			for _, param := range method.Parameters {
+				goType := mapTypeToQualifiedGoType(param.Type, structMap, enumMap, param.Optional, qualify)
+				fmt.Fprintf(&resolvers, "\tvar %s %s\n", param.Name, goType)
+				fmt.Fprintf(&resolvers, "\tif err := decodeArg(args, %q, &%s); err != nil {\n\t\treturn nil, err\n\t}\n", param.Name, param.Name)
+				args = append(args, param.Name)
+			}
+			call := fmt.Sprintf("r.%s.%s(%s)", name, naming.SnakeToPascal(method.Name), strings.Join(args, ", "))
+			if method.ReturnType == nil {
+				fmt.Fprintf(&resolvers, "\treturn nil, %s\n}\n\n", call)
+			} else {
+				fmt.Fprintf(&resolvers, "\treturn %s\n}\n\n", call)
+			}
+		}
+	}
+
+	sb.WriteString("// Query returns the resolvers of the Query fields by field name\n")
+	sb.WriteString("func (r *Resolver) Query() map[string]FieldResolver {\n\treturn map[string]FieldResolver{\n")
+	sb.WriteString(strings.Join(queries, ""))
+	sb.WriteString("\t}\n}\n\n")
+	sb.WriteString("// Mutation returns the resolvers of the Mutation fields by field name\n")
+	sb.WriteString("func (r *Resolver) Mutation() map[string]FieldResolver {\n\treturn map[string]FieldResolver{\n")
+	sb.WriteString(strings.Join(mutations, ""))
+	sb.WriteString("\t}\n}\n\n")
+	sb.WriteString(resolvers.String())
+
+	sb.WriteString("// decodeArg decodes the argument called name into v through its JSON form, leaving v\n")
+	sb.WriteString("// unchanged when the argument is absent or null\n")
+	sb.WriteString(`func decodeArg(args map[string]interface{}, name string, v interface{}) error {
+	value, ok := args[name]
+	if !ok || value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(data, v)
+	}
+	if err != nil {
+		return fmt.Errorf("argument %s: %w", name, err)
+	}
+	return nil
+}
+`)
+	return sb.String()
+}
@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+const graphQLTestIDL = `namespace shop
+
+// An item of an order
+struct Item {
+    sku    string
+    count  int
+}
+
+struct Order {
+    items  []Item
+    note   string   [optional]
+    attrs  map[string]string
+}
+
+enum State {
+    open
+    shipped
+}
+
+interface Orders {
+    find(id int) Order [optional] [readonly]
+    place(items []Item, state State [optional]) Order
+}
+`
+
+func TestBuildGraphQLSchema(t *testing.T) {
+	idl, err := parser.ParseIDL("test.pulse", graphQLTestIDL)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	schema, err := BuildGraphQLSchema(idl)
+	if err != nil {
+		t.Fatalf("BuildGraphQLSchema failed: %v", err)
+	}
+	for _, want := range []string{
+		"scalar JSON\n",
+		"enum State {\n  open\n  shipped\n}\n",
+		"\"\"\"\nAn item of an order\n\"\"\"\ntype Item {\n  sku: String!\n  count: Int!\n}\n",
+		"type Order {\n  items: [Item!]!\n  note: String\n  attrs: JSON!\n}\n",
+		"input ItemInput {\n  sku: String!\n  count: Int!\n}\n",
+		"type Query {\n  \"Calls Orders.find\"\n  ordersFind(id: Int!): Order\n}\n",
+		"type Mutation {\n  \"Calls Orders.place\"\n  ordersPlace(items: [ItemInput!]!, state: State): Order!\n}\n",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("schema lacks %q:\n%s", want, schema)
+		}
+	}
+	// Order is only returned, so it needs no input type
+	if strings.Contains(schema, "OrderInput") {
+		t.Errorf("schema has an input type for Order:\n%s", schema)
+	}
+}
+
+func TestBuildGraphQLSchemaWithoutQueries(t *testing.T) {
+	idl, err := parser.ParseIDL("test.pulse", strings.Replace(graphQLTestIDL, " [readonly]", "", 1))
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	schema, err := BuildGraphQLSchema(idl)
+	if err != nil {
+		t.Fatalf("BuildGraphQLSchema failed: %v", err)
+	}
+	if !strings.Contains(schema, "type Query {\n") || !strings.Contains(schema, "  _empty: Boolean\n") {
+		t.Errorf("schema lacks the placeholder Query field:\n%s", schema)
+	}
+}
+
+func TestBuildGraphQLSchemaInputCollision(t *testing.T) {
+	idl, err := parser.ParseIDL("test.pulse", graphQLTestIDL+"\nstruct ItemInput {\n    sku string\n}\n")
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	_, err = BuildGraphQLSchema(idl)
+	if err == nil || !strings.Contains(err.Error(), "ItemInput") {
+		t.Fatalf("expected an input type collision error, got %v", err)
+	}
+}
+
+// TestGraphQLResolversCompile builds the resolver scaffold against the package
+// generated by go-client-server
+func TestGraphQLResolversCompile(t *testing.T) {
+	dir := t.TempDir()
+	writeGoModule(t, dir, "example.com/gen")
+	file := filepath.Join(dir, "test.pulse")
+	if err := os.WriteFile(file, []byte(graphQLTestIDL), 0644); err != nil {
+		t.Fatal(err)
+	}
+	generateFileForTest(t, NewGoClientServer(), file, filepath.Join(dir, "api"))
+	generateFileForTest(t, NewGraphQL(), file, filepath.Join(dir, "graphqlapi"), "-graphql-go-import=example.com/gen/api")
+	runGo(t, dir, "vet", "./...")
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"strconv"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// The -generate-test-harness flag emits unit tests in each language's test framework
+// (go test, pytest, JUnit 5, xUnit) that register the user's handler with the
+// generated server and call every method in-process with IDL-valid arguments. The
+// server validates each result against the IDL, so a call fails the test if the
+// handler returns an error or a result of the wrong shape.
+//
+// Each harness is split into two files: the tests themselves, which are regenerated
+// like any other output, and a skeleton with one handler factory per interface,
+// which is only written if it does not exist yet so the user can edit it. A factory
+// that returns null skips its interface's tests.
+
+// testHarnessRequested reports whether the -generate-test-harness flag is set
+func testHarnessRequested(fs *flag.FlagSet) bool {
+	f := fs.Lookup("generate-test-harness")
+	return f != nil && f.Value.String() == "true"
+}
+
+// harnessInterface is an interface exercised by a test harness
+type harnessInterface struct {
+	// Name is the interface name the handler is registered under
+	Name string
+	// Ident is the interface's base name converted for use in identifiers
+	Ident string
+	Calls []harnessCall
+}
+
+// harnessCall is a sample call of one method
+type harnessCall struct {
+	Method string
+	// Ident is the method name converted for use in identifiers
+	Ident string
+	// JSON is a JSON-RPC request with IDL-valid params. It never contains single
+	// quotes, backticks or backslashes, so it can be embedded in a Go raw string or a
+	// Python single quoted string as is.
+	JSON string
+	// Request is JSON as a double quoted string literal, valid in Java and C#
+	Request string
+}
+
+// buildHarnessInterfaces returns a sample call of every method of every interface.
+// ident converts interface base names and method names to identifiers.
+func buildHarnessInterfaces(idl *parser.IDL, ident func(string) string) []harnessInterface {
+	b := newTestVectorBuilder(idl)
+	var interfaces []harnessInterface
+	for _, iface := range idl.Interfaces {
+		hi := harnessInterface{Name: iface.Name, Ident: ident(GetBaseName(iface.Name))}
+		for i, method := range iface.Methods {
+			params := make([]interface{}, len(method.Parameters))
+			for j, param := range method.Parameters {
+				params[j] = b.validValue(param.Type)
+			}
+			request, _ := json.Marshal(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"method":  iface.RPCName(method),
+				"params":  params,
+				"id":      i + 1,
+			})
+			hi.Calls = append(hi.Calls, harnessCall{
+				Method:  method.Name,
+				Ident:   ident(method.Name),
+				JSON:    string(request),
+				Request: strconv.Quote(string(request)),
+			})
+		}
+		interfaces = append(interfaces, hi)
+	}
+	return interfaces
+}
+
+// writeSkeletonFile writes a generated file that the user is expected to edit. An
+// existing file is left untouched.
+func writeSkeletonFile(path string, content []byte) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return writeGeneratedFile(path, content)
+}
@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestTestHarnessKeepsEditedHandlers(t *testing.T) {
+	idl := &parser.IDL{
+		RootNamespace: "catalog",
+		Interfaces: []*parser.Interface{
+			{
+				Name: "Catalog",
+				Methods: []*parser.Method{
+					{
+						Name:       "find_product",
+						Parameters: []*parser.Parameter{{Name: "id", Type: &parser.Type{BuiltIn: "string"}}},
+						ReturnType: &parser.Type{BuiltIn: "string"},
+					},
+				},
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	p := NewGoClientServer()
+	fs := newTestFlagSet(t, p, tmpDir)
+	if err := fs.Set("generate-test-harness", "true"); err != nil {
+		t.Fatalf("failed to set generate-test-harness flag: %v", err)
+	}
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+
+	harness, err := os.ReadFile(filepath.Join(tmpDir, "harness_test.go"))
+	if err != nil {
+		t.Fatalf("failed to read harness_test.go: %v", err)
+	}
+	for _, want := range []string{
+		"func TestCatalogHandler(t *testing.T) {",
+		"handler := newCatalogHandler()",
+		"server.RegisterCatalog(handler)",
+		"t.Run(\"find_product\", func(t *testing.T) {",
+		"`{\"id\":1,\"jsonrpc\":\"2.0\",\"method\":\"Catalog.find_product\",\"params\":[\"test\"]}`",
+	} {
+		if !strings.Contains(string(harness), want) {
+			t.Errorf("harness_test.go missing %q", want)
+		}
+	}
+
+	handlersPath := filepath.Join(tmpDir, "handlers_test.go")
+	handlers, err := os.ReadFile(handlersPath)
+	if err != nil {
+		t.Fatalf("failed to read handlers_test.go: %v", err)
+	}
+	if !strings.Contains(string(handlers), "func newCatalogHandler() catalog.Catalog {") {
+		t.Errorf("handlers_test.go missing the Catalog factory:\n%s", handlers)
+	}
+
+	// Regenerating must not overwrite the user's handlers
+	edited := strings.Replace(string(handlers), "return nil", "return &catalogImpl{}", 1)
+	if err := os.WriteFile(handlersPath, []byte(edited), 0644); err != nil {
+		t.Fatalf("failed to edit handlers_test.go: %v", err)
+	}
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("second generate failed: %v", err)
+	}
+	after, err := os.ReadFile(handlersPath)
+	if err != nil {
+		t.Fatalf("failed to read handlers_test.go: %v", err)
+	}
+	if string(after) != edited {
+		t.Errorf("handlers_test.go was overwritten:\n%s", after)
+	}
+}
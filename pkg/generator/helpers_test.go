@@ -0,0 +1,183 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// newTestFlagSet returns the flags of plugin with -dir set to dir, parsed from args
+// (such as "-go-module=example.com/shop"). The shared flags the plugin reads are
+// defined as strings, so bool flags are set with "=true".
+func newTestFlagSet(t *testing.T, plugin Plugin, dir string, args ...string) *flag.FlagSet {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("dir", dir, "output dir")
+	if shared, ok := plugin.(SharedFlagger); ok {
+		for _, name := range shared.SharedFlags() {
+			if fs.Lookup(name) == nil {
+				fs.String(name, "", name)
+			}
+		}
+	}
+	plugin.RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("%s: invalid flags %v: %v", plugin.Name(), args, err)
+	}
+	return fs
+}
+
+// generateForTest runs plugin on the IDL source idl with the flags in args and
+// returns the output directory
+func generateForTest(t *testing.T, plugin Plugin, idl string, args ...string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "test.pulse")
+	if err := os.WriteFile(file, []byte(idl), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	generateFileForTest(t, plugin, file, dir, args...)
+	return dir
+}
+
+// generateFileForTest parses and validates the IDL file, which may import others
+// next to it, and runs plugin on it with output to dir and the flags in args
+func generateFileForTest(t *testing.T, plugin Plugin, file, dir string, args ...string) {
+	t.Helper()
+	src, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idl, err := parser.ParseIDL(file, string(src))
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	if err := parser.ValidateIDL(idl); err != nil {
+		t.Fatalf("ValidateIDL failed: %v", err)
+	}
+	if err := plugin.Generate(idl, newTestFlagSet(t, plugin, dir, args...)); err != nil {
+		t.Fatalf("%s: Generate failed: %v", plugin.Name(), err)
+	}
+}
+
+// readGenerated returns the content of a generated file below dir
+func readGenerated(t *testing.T, dir, file string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		t.Fatalf("expected %s: %v", file, err)
+	}
+	return string(content)
+}
+
+// writeGoModule makes the generated Go code in dir the module named module
+func writeGoModule(t *testing.T, dir, module string) {
+	t.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not available")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module "+module+"\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// runGo runs the go tool with args in dir and returns its output, failing the test if it fails
+func runGo(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+// runGoCheck makes the generated Go code in dir the module named module, runs program
+// as its cmd/check command and returns the trimmed output. The test is skipped when
+// the go tool is missing.
+func runGoCheck(t *testing.T, dir, module, program string) string {
+	t.Helper()
+	writeGoModule(t, dir, module)
+	if err := os.MkdirAll(filepath.Join(dir, "cmd", "check"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cmd", "check", "main.go"), []byte(program), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(runGo(t, dir, "run", "./cmd/check"))
+}
+
+// runPythonCheck runs script with python3 in dir and returns the trimmed output. The
+// test is skipped when python3 is missing.
+func runPythonCheck(t *testing.T, dir, script string) string {
+	t.Helper()
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+	cmd := exec.Command("python3", "-c", script)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("python check failed: %v\n%s", err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// runDotnetCheck compiles program with the generated C# code in dir as an ASP.NET Core
+// application, runs it and returns the trimmed output. The test is skipped when the
+// .NET SDK is missing or in short mode, since a build takes several seconds.
+func runDotnetCheck(t *testing.T, dir, program string) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping dotnet build in short mode")
+	}
+	dotnet, err := exec.LookPath("dotnet")
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		dotnet = filepath.Join(home, ".dotnet", "dotnet")
+		if root := os.Getenv("DOTNET_ROOT"); root != "" {
+			dotnet = filepath.Join(root, "dotnet")
+		}
+		if _, err := os.Stat(dotnet); err != nil {
+			t.Skip("dotnet not available")
+		}
+	}
+	project := t.TempDir()
+	csproj := `<Project Sdk="Microsoft.NET.Sdk.Web">
+  <PropertyGroup>
+    <OutputType>Exe</OutputType>
+    <TargetFramework>net8.0</TargetFramework>
+    <ImplicitUsings>enable</ImplicitUsings>
+    <Nullable>enable</Nullable>
+  </PropertyGroup>
+  <ItemGroup>
+    <Compile Include="` + filepath.Join(dir, "**", "*.cs") + `" />
+  </ItemGroup>
+</Project>
+`
+	if err := os.WriteFile(filepath.Join(project, "check.csproj"), []byte(csproj), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(project, "Program.cs"), []byte(program), 0644); err != nil {
+		t.Fatal(err)
+	}
+	env := append(os.Environ(), "DOTNET_CLI_TELEMETRY_OPTOUT=1", "DOTNET_NOLOGO=1", "DOTNET_SKIP_FIRST_TIME_EXPERIENCE=1")
+	build := exec.Command(dotnet, "build", project, "-o", filepath.Join(project, "out"), "-v", "q")
+	build.Env = env
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("dotnet build failed: %v\n%s", err, out)
+	}
+	cmd := exec.Command(dotnet, filepath.Join(project, "out", "check.dll"))
+	cmd.Env = env
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("dotnet check failed: %v\n%s", err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// -idl-json sets where the Go, Python, TypeScript, Java and Rust plugins write the
+// IDL's JSON document, which the generated server returns from pulserpc-idl. The
+// default is idl.json in the output directory. A different path, relative to the
+// output directory, lets the servers of several IDLs be generated into one
+// artifact without overwriting each other's document; -idl-json none writes no
+// file and embeds the document in the generated server instead. The C# server
+// always embeds it.
+
+// idlJSONDefaultPath is the path of the IDL's JSON document unless -idl-json is set
+const idlJSONDefaultPath = "idl.json"
+
+// idlJSONNone is the -idl-json value that embeds the document instead of writing it
+const idlJSONNone = "none"
+
+// idlJSONDocument is the IDL's JSON document and where the generated server reads
+// it from
+type idlJSONDocument struct {
+	// Path is the slash separated path of the file relative to the output
+	// directory, or empty if the document is embedded in the server
+	Path string
+	// Data is the document, indented as written to the file
+	Data []byte
+}
+
+// newIDLJSONDocument encodes idl and resolves -idl-json
+func newIDLJSONDocument(idl *parser.IDL, fs *flag.FlagSet) (idlJSONDocument, error) {
+	filePath, err := idlJSONPath(fs)
+	if err != nil {
+		return idlJSONDocument{}, err
+	}
+	data, err := json.MarshalIndent(idl, "", "  ")
+	if err != nil {
+		return idlJSONDocument{}, fmt.Errorf("failed to marshal IDL to JSON: %w", err)
+	}
+	return idlJSONDocument{Path: filePath, Data: data}, nil
+}
+
+// idlJSONPath returns the path -idl-json sets, or empty for -idl-json none. The
+// path must stay inside the output directory, no element may start with '.' or
+// '_', which go:embed skips, and it may not hold quotes or backslashes, so it can
+// be written into string literals as it is.
+func idlJSONPath(fs *flag.FlagSet) (string, error) {
+	value := idlJSONDefaultPath
+	if f := fs.Lookup("idl-json"); f != nil && f.Value.String() != "" {
+		value = f.Value.String()
+	}
+	if value == idlJSONNone {
+		return "", nil
+	}
+	cleaned := path.Clean(filepath.ToSlash(value))
+	if path.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("invalid idl-json value: %q (must be a path relative to the output directory, or %q)", value, idlJSONNone)
+	}
+	if strings.ContainsAny(cleaned, "'\"\\`") {
+		return "", fmt.Errorf("invalid idl-json value: %q (may not contain quotes or backslashes)", value)
+	}
+	for _, elem := range strings.Split(cleaned, "/") {
+		if strings.HasPrefix(elem, ".") || strings.HasPrefix(elem, "_") {
+			return "", fmt.Errorf("invalid idl-json value: %q (path elements may not start with '.' or '_')", value)
+		}
+	}
+	return cleaned, nil
+}
+
+// Embedded reports whether the document is embedded in the server rather than
+// written to a file
+func (d idlJSONDocument) Embedded() bool {
+	return d.Path == ""
+}
+
+// Write writes the document to its path under dir, unless it is embedded
+func (d idlJSONDocument) Write(dir string) error {
+	if d.Embedded() {
+		return nil
+	}
+	target := filepath.Join(dir, filepath.FromSlash(d.Path))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", d.Path, err)
+	}
+	if err := writeGeneratedFile(target, d.Data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", d.Path, err)
+	}
+	return nil
+}
+
+// Compact returns the document without indentation, as embedded in servers
+func (d idlJSONDocument) Compact() string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, d.Data); err != nil {
+		return string(d.Data)
+	}
+	return buf.String()
+}
+
+// StringLiteral returns the compact document as a double quoted string literal,
+// which is valid in Go, Python, JavaScript and TypeScript
+func (d idlJSONDocument) StringLiteral() string {
+	data, _ := json.Marshal(d.Compact())
+	return string(data)
+}
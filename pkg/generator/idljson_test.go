@@ -0,0 +1,110 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const idlJSONTestIDL = `namespace shop
+
+// A "quoted" comment
+interface Catalog {
+  get(id string) string
+}`
+
+// javaIDLJSONArgs are the flags the Java plugin needs besides -idl-json
+var javaIDLJSONArgs = []string{"-base-package=com.example"}
+
+func TestIDLJSONPath(t *testing.T) {
+	tests := []struct {
+		plugin Plugin
+		args   []string
+		files  []string
+		server string
+		wants  []string
+	}{
+		{NewGoClientServer(), nil, []string{"api/shop.json"}, "server.go", []string{"//go:embed api/shop.json\nvar idlJSON []byte\n"}},
+		{NewPythonClientServer(), nil, []string{"api/shop.json"}, "server.py", []string{"os.path.join(os.path.dirname(os.path.abspath(__file__)), 'api', 'shop.json')"}},
+		{NewTSClientServer(), nil, []string{"api/shop.json"}, "server.ts", []string{"path.join(serverDir, 'api', 'shop.json')"}},
+		{NewRustClientServer(), nil, []string{"api/shop.json"}, "src/lib.rs", []string{"include_str!(\"../api/shop.json\")"}},
+		{NewJavaClientServer(), javaIDLJSONArgs, []string{"src/main/resources/idl.json", "src/main/resources/com/example/api/shop.json"}, "src/main/java/com/example/Server.java", []string{"Server.class.getResourceAsStream(\"api/shop.json\")"}},
+	}
+	for _, tt := range tests {
+		dir := generateForTest(t, tt.plugin, idlJSONTestIDL, append([]string{"-idl-json=api/shop.json"}, tt.args...)...)
+		for _, file := range tt.files {
+			if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+				t.Errorf("%s: expected %s: %v", tt.plugin.Name(), file, err)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, "idl.json")); err == nil {
+			t.Errorf("%s: idl.json was written although -idl-json is set", tt.plugin.Name())
+		}
+		server := readGenerated(t, dir, tt.server)
+		for _, want := range tt.wants {
+			if !strings.Contains(server, want) {
+				t.Errorf("%s: %s does not contain %q", tt.plugin.Name(), tt.server, want)
+			}
+		}
+	}
+}
+
+func TestIDLJSONEmbedded(t *testing.T) {
+	tests := []struct {
+		plugin Plugin
+		args   []string
+		server string
+		want   string
+	}{
+		{NewGoClientServer(), nil, "server.go", "var idlJSON = []byte(\"{"},
+		{NewPythonClientServer(), nil, "server.py", "self._composition.merge_idl(json.loads(\"{"},
+		{NewTSClientServer(), nil, "server.ts", "const idlDoc = JSON.parse(\"{"},
+		{NewRustClientServer(), nil, "src/lib.rs", "pub const IDL_JSON: &str = r#\"{"},
+		{NewJavaClientServer(), javaIDLJSONArgs, "src/main/java/com/example/Server.java", "String idlJson = String.join(\"\",\n            \"{"},
+	}
+	for _, tt := range tests {
+		dir := generateForTest(t, tt.plugin, idlJSONTestIDL, append([]string{"-idl-json=none"}, tt.args...)...)
+		var jsonFiles []string
+		filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err == nil && filepath.Base(path) == "idl.json" {
+				jsonFiles = append(jsonFiles, path)
+			}
+			return nil
+		})
+		if len(jsonFiles) > 0 {
+			t.Errorf("%s: -idl-json none wrote %v", tt.plugin.Name(), jsonFiles)
+		}
+		if server := readGenerated(t, dir, tt.server); !strings.Contains(server, tt.want) {
+			t.Errorf("%s: %s does not contain %q", tt.plugin.Name(), tt.server, tt.want)
+		}
+	}
+}
+
+// TestIDLJSONEmbeddedCompiles builds a Go server with the document embedded as a
+// string literal
+func TestIDLJSONEmbeddedCompiles(t *testing.T) {
+	dir := generateForTest(t, NewGoClientServer(), idlJSONTestIDL, "-idl-json=none")
+	writeGoModule(t, dir, "example.com/shop")
+	runGo(t, dir, "vet", "./...")
+}
+
+func TestIDLJSONPathInvalid(t *testing.T) {
+	for _, value := range []string{"/tmp/idl.json", "../idl.json", ".", "api/.idl.json", "_idl.json", "it's.json"} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("idl-json", value, "IDL JSON path")
+		if _, err := idlJSONPath(fs); err == nil {
+			t.Errorf("expected an error for -idl-json %q", value)
+		}
+	}
+}
+
+func TestRustRawString(t *testing.T) {
+	if got := rustRawString(`{"a":"b"}`); got != `r#"{"a":"b"}"#` {
+		t.Errorf("unexpected raw string %s", got)
+	}
+	if got := rustRawString(`"#"##`); got != `r###""#"##"###` {
+		t.Errorf("unexpected raw string %s", got)
+	}
+}
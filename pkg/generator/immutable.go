@@ -0,0 +1,374 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/naming"
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Structs annotated [immutable] are value objects whose fields are set once, when
+// a value is constructed or decoded. Go structs get unexported fields with
+// getters, a New<Name> constructor and MarshalJSON/UnmarshalJSON through a
+// <name>JSON struct that JSONFields returns to the runtime. Java classes get
+// final fields, an all-args constructor and no setters; C# properties get init
+// accessors. Python structs stay dicts on the wire, and the namespace module adds
+// a frozen dataclass per immutable struct with from_dict and to_dict. The
+// validator makes a struct and its parent agree on [immutable].
+
+// usesImmutableStructs reports whether any struct is annotated [immutable]
+func usesImmutableStructs(structs []*parser.Struct) bool {
+	for _, s := range structs {
+		if s.IsImmutable() {
+			return true
+		}
+	}
+	return false
+}
+
+// typeReachesImmutable reports whether a value of type t can hold an [immutable]
+// struct, directly or through lists, maps and the fields of other structs
+func typeReachesImmutable(t *parser.Type, structMap map[string]*parser.Struct, seen map[string]bool) bool {
+	switch {
+	case t == nil:
+		return false
+	case t.IsArray():
+		return typeReachesImmutable(t.Array, structMap, seen)
+	case t.IsMap():
+		return typeReachesImmutable(t.MapValue, structMap, seen)
+	case t.IsUserDefined():
+		s := lookupStruct(t.UserDefined, structMap)
+		if s == nil || seen[s.Name] {
+			return false
+		}
+		seen[s.Name] = true
+		if s.IsImmutable() {
+			return true
+		}
+		for _, field := range parser.ResolveStructFields(s, structMap) {
+			if typeReachesImmutable(field.Type, structMap, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// goFieldName returns the name of a field in the Go struct of s: unexported for
+// [immutable] structs, whose getters take the exported name
+func goFieldName(s *parser.Struct, field *parser.Field) string {
+	if s.IsImmutable() {
+		return goUnexportedName(field.Name)
+	}
+	return naming.SnakeToPascal(field.Name)
+}
+
+// goUnexportedName returns the unexported Go identifier of an IDL field, with an
+// underscore appended to keywords
+func goUnexportedName(name string) string {
+	ident := naming.LowerFirst(naming.SnakeToPascal(name))
+	if token.IsKeyword(ident) {
+		return ident + "_"
+	}
+	return ident
+}
+
+// goFieldType returns the Go type and json tag of a struct field
+func goFieldType(field *parser.Field, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, qualify func(string) string, presence bool) (string, string) {
+	goType := mapTypeToQualifiedGoType(field.Type, structMap, enumMap, field.Optional, qualify)
+	jsonTag := field.Name
+	if field.Optional && presence {
+		goType = "Optional[" + mapTypeToQualifiedGoType(field.Type, structMap, enumMap, false, qualify) + "]"
+		jsonTag += ",omitzero"
+	} else if field.Optional {
+		jsonTag += ",omitempty"
+	}
+	return goType, jsonTag
+}
+
+// goConstructorName returns the name of the New<Name> constructor of a Go struct
+// type name, which may be qualified with its package
+func goConstructorName(typeName string) string {
+	if i := strings.LastIndex(typeName, "."); i >= 0 {
+		return typeName[:i+1] + "New" + typeName[i+1:]
+	}
+	return "New" + typeName
+}
+
+// writeImmutableMembersGo writes the constructor, getters and JSON methods of an
+// [immutable] Go struct. The constructor takes the fields of the parents first;
+// the embedded parent is built with its own constructor, which keeps the parent's
+// fields unexported even when it lives in another package.
+func writeImmutableMembersGo(sb *strings.Builder, s *parser.Struct, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, qualify func(string) string, presence bool) {
+	structName := GetBaseName(s.Name)
+	jsonName := naming.LowerFirst(structName) + "JSON"
+	fields := parser.ResolveStructFields(s, structMap)
+
+	var params, args []string
+	for _, field := range fields {
+		goType, _ := goFieldType(field, structMap, enumMap, qualify, presence)
+		params = append(params, goUnexportedName(field.Name)+" "+goType)
+	}
+	var inits []string
+	inherited := len(fields) - len(s.Fields)
+	if s.Extends != "" {
+		parentType := getGoStructOrEnumTypeName(s.Extends, structMap, enumMap)
+		if qualify != nil {
+			parentType = qualify(s.Extends)
+		}
+		for _, field := range fields[:inherited] {
+			args = append(args, goUnexportedName(field.Name))
+		}
+		inits = append(inits, fmt.Sprintf("%s: %s(%s)", getGoStructOrEnumTypeName(s.Extends, structMap, enumMap), goConstructorName(parentType), strings.Join(args, ", ")))
+	}
+	for _, field := range s.Fields {
+		name := goUnexportedName(field.Name)
+		inits = append(inits, name+": "+name)
+	}
+	fmt.Fprintf(sb, "// New%s returns a %s holding the given field values\n", structName, structName)
+	fmt.Fprintf(sb, "func New%s(%s) %s {\n", structName, strings.Join(params, ", "), structName)
+	fmt.Fprintf(sb, "\treturn %s{%s}\n", structName, strings.Join(inits, ", "))
+	sb.WriteString("}\n\n")
+
+	for _, field := range s.Fields {
+		goType, _ := goFieldType(field, structMap, enumMap, qualify, presence)
+		getter := naming.SnakeToPascal(field.Name)
+		if field.Comment != "" {
+			for _, line := range strings.Split(strings.TrimSpace(field.Comment), "\n") {
+				fmt.Fprintf(sb, "// %s\n", line)
+			}
+		} else {
+			fmt.Fprintf(sb, "// %s returns the %s field\n", getter, field.Name)
+		}
+		fmt.Fprintf(sb, "func (v %s) %s() %s {\n", structName, getter, goType)
+		fmt.Fprintf(sb, "\treturn v.%s\n", goUnexportedName(field.Name))
+		sb.WriteString("}\n\n")
+	}
+
+	fmt.Fprintf(sb, "// %s holds the fields of %s as they are encoded\n", jsonName, structName)
+	fmt.Fprintf(sb, "type %s struct {\n", jsonName)
+	var values, decoded []string
+	for _, field := range fields {
+		goType, jsonTag := goFieldType(field, structMap, enumMap, qualify, presence)
+		exported := naming.SnakeToPascal(field.Name)
+		if field.IsSensitive() {
+			fmt.Fprintf(sb, "\t%s %s `json:\"%s\" pulse:\"sensitive\"`\n", exported, goType, jsonTag)
+		} else {
+			fmt.Fprintf(sb, "\t%s %s `json:\"%s\"`\n", exported, goType, jsonTag)
+		}
+		values = append(values, fmt.Sprintf("%s: v.%s()", exported, exported))
+		decoded = append(decoded, "f."+exported)
+	}
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// JSONFields returns the fields of v in a struct that encodes as v does\n")
+	fmt.Fprintf(sb, "func (v %s) JSONFields() interface{} {\n", structName)
+	fmt.Fprintf(sb, "\treturn %s{%s}\n", jsonName, strings.Join(values, ", "))
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// MarshalJSON encodes v as a JSON object\n")
+	fmt.Fprintf(sb, "func (v %s) MarshalJSON() ([]byte, error) {\n", structName)
+	sb.WriteString("\treturn json.Marshal(v.JSONFields())\n")
+	sb.WriteString("}\n\n")
+
+	fmt.Fprintf(sb, "// UnmarshalJSON sets v from a JSON object, as New%s does\n", structName)
+	fmt.Fprintf(sb, "func (v *%s) UnmarshalJSON(data []byte) error {\n", structName)
+	fmt.Fprintf(sb, "\tvar f %s\n", jsonName)
+	sb.WriteString("\tif err := json.Unmarshal(data, &f); err != nil {\n\t\treturn err\n\t}\n")
+	fmt.Fprintf(sb, "\t*v = New%s(%s)\n", structName, strings.Join(decoded, ", "))
+	sb.WriteString("\treturn nil\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeImmutableConstructorsJava writes the constructors of an [immutable] Java
+// class: a protected no-arg one that JSON libraries use before they set the final
+// fields, and a public one taking the fields of the parents and then its own
+func writeImmutableConstructorsJava(sb *strings.Builder, s *parser.Struct, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, basePackage, packageName string) {
+	className := GetBaseName(s.Name)
+	fields := parser.ResolveStructFields(s, structMap)
+	if len(fields) > 0 {
+		fmt.Fprintf(sb, "    protected %s() {\n", className)
+		for _, field := range s.Fields {
+			fieldType := getJavaTypeWithPackage(field.Type, enumMap, basePackage, packageName)
+			fmt.Fprintf(sb, "        this.%s = %s;\n", naming.LowerFirst(field.Name), javaDefaultValue(fieldType))
+		}
+		sb.WriteString("    }\n\n")
+	}
+
+	var params, args []string
+	for _, field := range fields {
+		params = append(params, getJavaTypeWithPackage(field.Type, enumMap, basePackage, packageName)+" "+naming.LowerFirst(field.Name))
+	}
+	fmt.Fprintf(sb, "    public %s(%s) {\n", className, strings.Join(params, ", "))
+	if s.Extends != "" {
+		for _, field := range fields[:len(fields)-len(s.Fields)] {
+			args = append(args, naming.LowerFirst(field.Name))
+		}
+		fmt.Fprintf(sb, "        super(%s);\n", strings.Join(args, ", "))
+	}
+	for _, field := range s.Fields {
+		fieldName := naming.LowerFirst(field.Name)
+		fmt.Fprintf(sb, "        this.%s = %s;\n", fieldName, fieldName)
+	}
+	sb.WriteString("    }\n\n")
+}
+
+// javaDefaultValue returns the value a Java field of the given type holds before
+// it is set
+func javaDefaultValue(javaType string) string {
+	switch javaType {
+	case "int", "double":
+		return "0"
+	case "boolean":
+		return "false"
+	}
+	return "null"
+}
+
+// writeImmutableClassesPy writes a frozen dataclass for each [immutable] struct of
+// a Python namespace module. A class repeats the fields of its parents rather than
+// subclassing them, so required fields can come before the optional ones, which
+// default to None. Sensitive fields are left out of repr. Nested immutable structs
+// of the same namespace are converted by from_dict; to_dict converts any value
+// back with plain_value.
+func writeImmutableClassesPy(sb *strings.Builder, namespace string, structs []*parser.Struct, structMap map[string]*parser.Struct) {
+	for _, s := range structs {
+		if !s.IsImmutable() {
+			continue
+		}
+		className := GetBaseName(s.Name)
+		var required, optional []*parser.Field
+		for _, field := range parser.ResolveStructFields(s, structMap) {
+			if field.Optional {
+				optional = append(optional, field)
+			} else {
+				required = append(required, field)
+			}
+		}
+
+		sb.WriteString("\n\n\n@dataclass(frozen=True)\n")
+		fmt.Fprintf(sb, "class %s:\n", className)
+		if s.Comment != "" {
+			fmt.Fprintf(sb, "    \"\"\"%s\"\"\"\n", strings.ReplaceAll(strings.TrimSpace(s.Comment), "\n", "\n    "))
+		} else {
+			fmt.Fprintf(sb, "    \"\"\"The [immutable] struct %s\"\"\"\n", s.Name)
+		}
+		sb.WriteString("\n")
+		for _, field := range required {
+			if field.IsSensitive() {
+				fmt.Fprintf(sb, "    %s: %s = dataclass_field(repr=False)\n", field.Name, pythonTypeHint(field.Type, namespace, structMap))
+			} else {
+				fmt.Fprintf(sb, "    %s: %s\n", field.Name, pythonTypeHint(field.Type, namespace, structMap))
+			}
+		}
+		for _, field := range optional {
+			if field.IsSensitive() {
+				fmt.Fprintf(sb, "    %s: Optional[%s] = dataclass_field(default=None, repr=False)\n", field.Name, pythonTypeHint(field.Type, namespace, structMap))
+			} else {
+				fmt.Fprintf(sb, "    %s: Optional[%s] = None\n", field.Name, pythonTypeHint(field.Type, namespace, structMap))
+			}
+		}
+
+		sb.WriteString("\n    @classmethod\n")
+		fmt.Fprintf(sb, "    def from_dict(cls, data: Dict[str, Any]) -> '%s':\n", className)
+		fmt.Fprintf(sb, "        \"\"\"Return the %s held in a decoded JSON object\"\"\"\n", className)
+		sb.WriteString("        return cls(\n")
+		for _, field := range required {
+			fmt.Fprintf(sb, "            %s=%s,\n", field.Name, pythonFromJSON(fmt.Sprintf("data['%s']", field.Name), field.Type, false, namespace, structMap, 0))
+		}
+		for _, field := range optional {
+			fmt.Fprintf(sb, "            %s=%s,\n", field.Name, pythonFromJSON(fmt.Sprintf("data.get('%s')", field.Name), field.Type, true, namespace, structMap, 0))
+		}
+		sb.WriteString("        )\n\n")
+
+		sb.WriteString("    def to_dict(self) -> Dict[str, Any]:\n")
+		sb.WriteString("        \"\"\"Return this value as a JSON object, leaving out unset optional fields\"\"\"\n")
+		sb.WriteString("        data: Dict[str, Any] = {\n")
+		for _, field := range required {
+			fmt.Fprintf(sb, "            '%s': plain_value(self.%s),\n", field.Name, field.Name)
+		}
+		sb.WriteString("        }\n")
+		for _, field := range optional {
+			fmt.Fprintf(sb, "        if self.%s is not None:\n", field.Name)
+			fmt.Fprintf(sb, "            data['%s'] = plain_value(self.%s)\n", field.Name, field.Name)
+		}
+		sb.WriteString("        return data\n")
+	}
+}
+
+// pythonImmutableClass returns the dataclass name of a user-defined type when it
+// is an [immutable] struct of the given namespace, or empty
+func pythonImmutableClass(name string, namespace string, structMap map[string]*parser.Struct) string {
+	s := lookupStruct(name, structMap)
+	if s == nil || !s.IsImmutable() || s.Namespace != namespace {
+		return ""
+	}
+	return GetBaseName(s.Name)
+}
+
+// pythonTypeHint returns the type hint of a dataclass field. Mutable structs are
+// dicts, and immutable structs of other namespaces stay dicts too.
+func pythonTypeHint(t *parser.Type, namespace string, structMap map[string]*parser.Struct) string {
+	switch {
+	case t.IsBuiltIn():
+		switch t.BuiltIn {
+		case "string":
+			return "str"
+		case "bool":
+			return "bool"
+		}
+		return t.BuiltIn
+	case t.IsArray():
+		return "List[" + pythonTypeHint(t.Array, namespace, structMap) + "]"
+	case t.IsMap():
+		return "Dict[str, " + pythonTypeHint(t.MapValue, namespace, structMap) + "]"
+	case t.IsUserDefined():
+		if class := pythonImmutableClass(t.UserDefined, namespace, structMap); class != "" {
+			return "'" + class + "'"
+		}
+		if lookupStruct(t.UserDefined, structMap) != nil {
+			return "Dict[str, Any]"
+		}
+		return "str"
+	}
+	return "Any"
+}
+
+// pythonFromJSON returns a Python expression converting src, a decoded JSON value
+// of type t, into the value a dataclass field holds
+func pythonFromJSON(src string, t *parser.Type, optional bool, namespace string, structMap map[string]*parser.Struct, depth int) string {
+	if !pythonNeedsConversion(t, namespace, structMap) {
+		return src
+	}
+	var expr string
+	e := fmt.Sprintf("e%d", depth)
+	switch {
+	case t.IsArray():
+		expr = fmt.Sprintf("[%s for %s in %s]", pythonFromJSON(e, t.Array, false, namespace, structMap, depth+1), e, src)
+	case t.IsMap():
+		k := fmt.Sprintf("k%d", depth)
+		expr = fmt.Sprintf("{%s: %s for %s, %s in %s.items()}", k, pythonFromJSON(e, t.MapValue, false, namespace, structMap, depth+1), k, e, src)
+	default:
+		expr = pythonImmutableClass(t.UserDefined, namespace, structMap) + ".from_dict(" + src + ")"
+	}
+	if optional {
+		return fmt.Sprintf("None if %s is None else %s", src, expr)
+	}
+	return expr
+}
+
+// pythonNeedsConversion reports whether a decoded JSON value of type t holds
+// dataclasses of the namespace
+func pythonNeedsConversion(t *parser.Type, namespace string, structMap map[string]*parser.Struct) bool {
+	switch {
+	case t.IsArray():
+		return pythonNeedsConversion(t.Array, namespace, structMap)
+	case t.IsMap():
+		return pythonNeedsConversion(t.MapValue, namespace, structMap)
+	case t.IsUserDefined():
+		return pythonImmutableClass(t.UserDefined, namespace, structMap) != ""
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+const immutableTestIDL = `namespace bank
+
+struct Entity [immutable] {
+    id  string
+}
+
+// Money is an amount in a currency
+struct Money extends Entity [immutable] {
+    amount    int
+    currency  string
+    note      string    [optional]
+    pin       string    [sensitive]
+    tags      []string
+}
+
+struct Wallet {
+    balance  Money
+}
+
+interface Bank {
+    deposit(money Money) Wallet
+}
+`
+
+const immutableGoMain = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	bank "example.com/bank"
+)
+
+func main() {
+	note := "gift"
+	money := bank.NewMoney("m1", 5, "EUR", &note, "1234", []string{"a"})
+	data, err := json.Marshal(bank.Wallet{Balance: money})
+	if err != nil {
+		panic(err)
+	}
+	var wallet bank.Wallet
+	if err := json.Unmarshal(data, &wallet); err != nil {
+		panic(err)
+	}
+	fmt.Println(string(data))
+	fmt.Println(wallet.Balance.Id(), wallet.Balance.Amount(), *wallet.Balance.Note(), wallet.Balance.Pin())
+	fmt.Println(money)
+}
+`
+
+// TestImmutableGoRoundTrip builds a program against the generated Go package and
+// checks that values keep their fields through JSON and mask sensitive ones
+func TestImmutableGoRoundTrip(t *testing.T) {
+	dir := generateForTest(t, NewGoClientServer(), immutableTestIDL)
+	out := runGoCheck(t, dir, "example.com/bank", immutableGoMain)
+	runGo(t, dir, "vet", "./...")
+	lines := strings.Split(out, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	if want := `{"balance":{"id":"m1","amount":5,"currency":"EUR","note":"gift","pin":"1234","tags":["a"]}}`; lines[0] != want {
+		t.Errorf("encoded %s, want %s", lines[0], want)
+	}
+	if want := "m1 5 gift 1234"; lines[1] != want {
+		t.Errorf("decoded %q, want %q", lines[1], want)
+	}
+	if strings.Contains(lines[2], "1234") || !strings.Contains(lines[2], `"amount":5`) {
+		t.Errorf("String did not mask the sensitive field: %s", lines[2])
+	}
+}
+
+func TestImmutableJavaAndCSharp(t *testing.T) {
+	tests := []struct {
+		plugin Plugin
+		args   []string
+		file   string
+		wants  []string
+		absent []string
+	}{
+		{
+			NewJavaClientServer(),
+			[]string{"-base-package=com.example"},
+			"src/main/java/com/example/bank/Money.java",
+			[]string{
+				"private final int amount;",
+				"super(id);",
+			},
+			[]string{"public void setAmount("},
+		},
+		{
+			NewCSharpClientServer(),
+			nil,
+			"Bank.cs",
+			[]string{"public int Amount { get; init; }"},
+			[]string{"public int Amount { get; set; }"},
+		},
+	}
+	for _, tt := range tests {
+		data := readGenerated(t, generateForTest(t, tt.plugin, immutableTestIDL, tt.args...), tt.file)
+		for _, want := range tt.wants {
+			if !strings.Contains(data, want) {
+				t.Errorf("%s: %s does not contain %q", tt.plugin.Name(), tt.file, want)
+			}
+		}
+		for _, absent := range tt.absent {
+			if strings.Contains(data, absent) {
+				t.Errorf("%s: %s contains %q", tt.plugin.Name(), tt.file, absent)
+			}
+		}
+	}
+}
+
+const immutablePythonCheck = `import dataclasses
+import bank
+
+money = bank.Money.from_dict({'id': 'm1', 'amount': 5, 'currency': 'EUR', 'pin': '1234', 'tags': ['a']})
+assert money.amount == 5 and money.note is None
+assert money.to_dict() == {'id': 'm1', 'amount': 5, 'currency': 'EUR', 'pin': '1234', 'tags': ['a']}
+assert '1234' not in repr(money)
+try:
+    money.amount = 6
+    raise SystemExit('assignment to a frozen dataclass succeeded')
+except dataclasses.FrozenInstanceError:
+    pass
+print('ok')
+`
+
+// TestImmutablePythonDataclass runs the generated dataclass through python3
+func TestImmutablePythonDataclass(t *testing.T) {
+	dir := generateForTest(t, NewPythonClientServer(), immutableTestIDL)
+	if out := runPythonCheck(t, dir, immutablePythonCheck); out != "ok" {
+		t.Fatalf("python check printed:\n%s", out)
+	}
+}
@@ -0,0 +1,263 @@
+package generator
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The -generate-index-files flag writes an index of the generated types of every
+// IDL namespace, so a large API can be browsed from one import instead of a file
+// per type: doc.go holds a package comment listing the types by namespace (Go),
+// the output package's __init__.py re-exports the type registries, clients and
+// server (Python, needs -py-packages), every namespace package gets a
+// package-info.java and a <namespace>Types class listing its classes (Java), and
+// GlobalUsings.cs imports the namespace of every struct and enum (C#). The index
+// files hold no behavior of their own.
+
+// indexFilesRequested reports whether the -generate-index-files flag is set
+func indexFilesRequested(fs *flag.FlagSet) bool {
+	f := fs.Lookup("generate-index-files")
+	return f != nil && f.Value.String() == "true"
+}
+
+// indexEntry is a generated type listed in an index file
+type indexEntry struct {
+	// Name is the base name of the type
+	Name string
+	// Summary is the first sentence of the type's IDL comment, if any
+	Summary string
+}
+
+// namespaceIndex lists the types of one IDL namespace by kind, sorted by name
+type namespaceIndex struct {
+	Namespace  string
+	Structs    []indexEntry
+	Enums      []indexEntry
+	Interfaces []indexEntry
+}
+
+// buildNamespaceIndex lists the structs, enums and interfaces of every namespace in
+// namespace order
+func buildNamespaceIndex(namespaceMap map[string]*NamespaceTypes) []namespaceIndex {
+	var index []namespaceIndex
+	for _, ns := range sortedNamespaces(namespaceMap) {
+		types := namespaceMap[ns]
+		entry := namespaceIndex{Namespace: ns}
+		for _, s := range types.Structs {
+			entry.Structs = append(entry.Structs, indexEntry{GetBaseName(s.Name), commentSummary(s.Comment)})
+		}
+		for _, e := range types.Enums {
+			entry.Enums = append(entry.Enums, indexEntry{GetBaseName(e.Name), commentSummary(e.Comment)})
+		}
+		for _, iface := range types.Interfaces {
+			entry.Interfaces = append(entry.Interfaces, indexEntry{GetBaseName(iface.Name), commentSummary(iface.Comment)})
+		}
+		for _, entries := range [][]indexEntry{entry.Structs, entry.Enums, entry.Interfaces} {
+			sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+		}
+		index = append(index, entry)
+	}
+	return index
+}
+
+// commentSummary returns the first sentence of an IDL comment on one line
+func commentSummary(comment string) string {
+	paragraph, _, _ := strings.Cut(strings.TrimSpace(comment), "\n\n")
+	summary := strings.Join(strings.Fields(paragraph), " ")
+	if i := strings.Index(summary, ". "); i >= 0 {
+		summary = summary[:i+1]
+	}
+	return summary
+}
+
+// generateDocGo generates doc.go, the package comment of the Go package that lists
+// the generated types of every namespace with doc links to them
+func generateDocGo(packageName string, index []namespaceIndex) string {
+	var sb strings.Builder
+	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
+	fmt.Fprintf(&sb, "// Package %s holds the client, server and types generated from the IDL.\n", packageName)
+	sb.WriteString("// The types of each IDL namespace are listed below.\n")
+	for _, ns := range index {
+		fmt.Fprintf(&sb, "//\n// # Namespace %s\n", ns.Namespace)
+		writeDocListGo(&sb, "Structs", ns.Structs, "")
+		writeDocListGo(&sb, "Enums", ns.Enums, "")
+		writeDocListGo(&sb, "Interfaces, called through the client of the same name", ns.Interfaces, "Client")
+	}
+	fmt.Fprintf(&sb, "package %s\n", packageName)
+	return sb.String()
+}
+
+// writeDocListGo writes one list of doc.go. A non-empty clientSuffix also links the
+// client type of each entry.
+func writeDocListGo(sb *strings.Builder, title string, entries []indexEntry, clientSuffix string) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "//\n// %s:\n//\n", title)
+	for _, entry := range entries {
+		fmt.Fprintf(sb, "//   - [%s]", entry.Name)
+		if clientSuffix != "" {
+			fmt.Fprintf(sb, " and [%s%s]", entry.Name, clientSuffix)
+		}
+		if entry.Summary != "" {
+			fmt.Fprintf(sb, ": %s", entry.Summary)
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// generateIndexPy generates the __init__.py of a -py-packages output directory, which
+// re-exports the type registries of every namespace, the clients, the errors of the
+// [errordata] structs and the server
+func generateIndexPy(index []namespaceIndex, apiClient bool, errorStructs []string) string {
+	var sb strings.Builder
+	var exports []string
+	sb.WriteString("# Generated by pulserpc - do not edit\n")
+	sb.WriteString("\"\"\"Client, server and type registries generated from the IDL, by IDL namespace.\"\"\"\n\n")
+	for _, ns := range index {
+		upper := strings.ToUpper(ns.Namespace)
+		fmt.Fprintf(&sb, "# Namespace %s\n", ns.Namespace)
+		writeIndexCommentPy(&sb, "structs", ns.Structs)
+		writeIndexCommentPy(&sb, "enums", ns.Enums)
+		writeIndexCommentPy(&sb, "interfaces", ns.Interfaces)
+		fmt.Fprintf(&sb, "from .%s import ALL_STRUCTS as %s_STRUCTS, ALL_ENUMS as %s_ENUMS\n\n", ns.Namespace, upper, upper)
+		exports = append(exports, upper+"_STRUCTS", upper+"_ENUMS")
+	}
+
+	clients := []string{"CallOptions", "HTTPTransport", "Transport", "TransportError"}
+	servers := []string{"PulseRPCServer"}
+	for _, ns := range index {
+		for _, iface := range ns.Interfaces {
+			clients = append(clients, iface.Name+"Client")
+			servers = append(servers, iface.Name)
+		}
+	}
+	if apiClient {
+		clients = append(clients, "ApiClient")
+	}
+	for _, name := range errorStructs {
+		clients = append(clients, errorDataClass(name))
+	}
+	sort.Strings(clients)
+	sort.Strings(servers)
+	fmt.Fprintf(&sb, "from .client import %s\n", strings.Join(clients, ", "))
+	fmt.Fprintf(&sb, "from .server import %s\n\n", strings.Join(servers, ", "))
+	exports = append(append(exports, clients...), servers...)
+
+	sb.WriteString("__all__ = [\n")
+	for _, name := range exports {
+		fmt.Fprintf(&sb, "    '%s',\n", name)
+	}
+	sb.WriteString("]\n")
+	return sb.String()
+}
+
+// writeIndexCommentPy writes the names of one kind of type as a comment of __init__.py
+func writeIndexCommentPy(sb *strings.Builder, kind string, entries []indexEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name
+	}
+	fmt.Fprintf(sb, "#   %s: %s\n", kind, strings.Join(names, ", "))
+}
+
+// generatePackageInfoJava generates package-info.java, the Javadoc of a namespace
+// package that links every generated class of the namespace
+func generatePackageInfoJava(ns namespaceIndex, fullPackage string) string {
+	var sb strings.Builder
+	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
+	sb.WriteString("/**\n")
+	fmt.Fprintf(&sb, " * Types generated from IDL namespace {@code %s}.\n", ns.Namespace)
+	writeJavadocListJava(&sb, "Structs", ns.Structs, "")
+	writeJavadocListJava(&sb, "Enums", ns.Enums, "")
+	writeJavadocListJava(&sb, "Interfaces, called through the client of the same name", ns.Interfaces, "Client")
+	sb.WriteString(" *\n")
+	fmt.Fprintf(&sb, " * @see %sTypes\n", ns.Namespace)
+	sb.WriteString(" */\n")
+	fmt.Fprintf(&sb, "package %s;\n", fullPackage)
+	return sb.String()
+}
+
+// writeJavadocListJava writes one list of package-info.java. A non-empty clientSuffix
+// also links the client class of each entry.
+func writeJavadocListJava(sb *strings.Builder, title string, entries []indexEntry, clientSuffix string) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, " *\n * <p>%s:\n * <ul>\n", title)
+	for _, entry := range entries {
+		fmt.Fprintf(sb, " *   <li>{@link %s}", entry.Name)
+		if clientSuffix != "" {
+			fmt.Fprintf(sb, " and {@link %s%s}", entry.Name, clientSuffix)
+		}
+		if entry.Summary != "" {
+			fmt.Fprintf(sb, ": %s", javadocEscape(entry.Summary))
+		}
+		sb.WriteString("</li>\n")
+	}
+	sb.WriteString(" * </ul>\n")
+}
+
+// javadocEscape escapes the characters of a comment that Javadoc would read as HTML
+// or as the end of the comment
+func javadocEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "*/", "*&#47;").Replace(s)
+}
+
+// generateTypesJava generates <namespace>Types.java, which lists the generated
+// classes of a namespace so they can be found, or registered, from one class
+func generateTypesJava(ns namespaceIndex, fullPackage string) string {
+	var sb strings.Builder
+	className := ns.Namespace + "Types"
+	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
+	fmt.Fprintf(&sb, "package %s;\n\n", fullPackage)
+	sb.WriteString("import java.util.List;\n\n")
+	fmt.Fprintf(&sb, "// Every class generated from IDL namespace %s\n", ns.Namespace)
+	fmt.Fprintf(&sb, "public final class %s {\n", className)
+	var clients []indexEntry
+	for _, iface := range ns.Interfaces {
+		clients = append(clients, indexEntry{Name: iface.Name + "Client"})
+	}
+	writeClassListJava(&sb, "STRUCTS", ns.Structs)
+	writeClassListJava(&sb, "ENUMS", ns.Enums)
+	writeClassListJava(&sb, "INTERFACES", ns.Interfaces)
+	writeClassListJava(&sb, "CLIENTS", clients)
+	fmt.Fprintf(&sb, "\n    private %s() {\n    }\n", className)
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// writeClassListJava writes a List<Class<?>> constant of <namespace>Types
+func writeClassListJava(sb *strings.Builder, name string, entries []indexEntry) {
+	classes := make([]string, len(entries))
+	for i, entry := range entries {
+		classes[i] = entry.Name + ".class"
+	}
+	fmt.Fprintf(sb, "    public static final List<Class<?>> %s = List.of(%s);\n", name, strings.Join(classes, ", "))
+}
+
+// generateGlobalUsingsCs generates GlobalUsings.cs, which imports every namespace so
+// code compiled with the generated files can use their structs and enums unqualified.
+// Interfaces and clients are already in the PulseRPC namespace.
+func generateGlobalUsingsCs(index []namespaceIndex) string {
+	var sb strings.Builder
+	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
+	sb.WriteString("// The namespaces of the generated structs and enums, imported into every file of the project\n")
+	for _, ns := range index {
+		var names []string
+		for _, entry := range append(append([]indexEntry{}, ns.Structs...), ns.Enums...) {
+			names = append(names, entry.Name)
+		}
+		if len(names) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n// %s: %s\n", ns.Namespace, strings.Join(names, ", "))
+		fmt.Fprintf(&sb, "global using %s;\n", ns.Namespace)
+	}
+	return sb.String()
+}
@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestCommentSummary(t *testing.T) {
+	cases := map[string]string{
+		"":                                     "",
+		"simply returns s":                     "simply returns s",
+		"a second interface\nthat spans lines": "a second interface that spans lines",
+		"Adds numbers. Ignores NaN.":           "Adds numbers.",
+		"First paragraph\n\nSecond paragraph":  "First paragraph",
+		"Version 1.2 of the API":               "Version 1.2 of the API",
+	}
+	for in, want := range cases {
+		if got := commentSummary(in); got != want {
+			t.Errorf("commentSummary(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestIndexFilesPython(t *testing.T) {
+	idl, err := parser.ParseIDL("shop.pulse", "namespace shop\n\nenum Color {\n  red\n}\n\nstruct Item {\n  itemId string\n}\n\ninterface Catalog {\n  get(itemId string) Item\n}")
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	p := NewPythonClientServer()
+
+	outputDir := filepath.Join(t.TempDir(), "shop_api")
+	fs := newTestFlagSet(t, p, outputDir, "-generate-index-files=true")
+	err = p.Generate(idl, fs)
+	if err == nil || !strings.Contains(err.Error(), "requires py-packages") {
+		t.Fatalf("expected py-packages error, got %v", err)
+	}
+
+	if err := fs.Set("py-packages", "true"); err != nil {
+		t.Fatalf("failed to set py-packages flag: %v", err)
+	}
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(outputDir, "__init__.py"))
+	if err != nil {
+		t.Fatalf("expected __init__.py: %v", err)
+	}
+	for _, want := range []string{
+		"#   structs: Item\n#   enums: Color\n#   interfaces: Catalog\n",
+		"from .shop import ALL_STRUCTS as SHOP_STRUCTS, ALL_ENUMS as SHOP_ENUMS\n",
+		"from .client import ApiClient, CallOptions, CatalogClient, HTTPTransport, Transport, TransportError\n",
+		"from .server import Catalog, PulseRPCServer\n",
+		"    'CatalogClient',\n",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("__init__.py does not contain %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestIndexFilesNotRequested(t *testing.T) {
+	idl, err := parser.ParseIDL("shop.pulse", "namespace shop\n\nstruct Item {\n  itemId string\n}")
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	tests := []struct {
+		plugin Plugin
+		file   string
+	}{
+		{NewGoClientServer(), "doc.go"},
+		{NewCSharpClientServer(), "GlobalUsings.cs"},
+		{NewJavaClientServer(), "src/main/java/com/example/shop/package-info.java"},
+		{NewJavaClientServer(), "src/main/java/com/example/shop/shopTypes.java"},
+	}
+	for _, tt := range tests {
+		tmpDir := t.TempDir()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		tt.plugin.RegisterFlags(fs)
+		for name, value := range map[string]string{"dir": tmpDir, "base-package": "com.example"} {
+			if fs.Lookup(name) != nil {
+				if err := fs.Set(name, value); err != nil {
+					t.Fatalf("failed to set %s flag: %v", name, err)
+				}
+			}
+		}
+		if err := tt.plugin.Generate(idl, fs); err != nil {
+			t.Fatalf("%s: Generate failed: %v", tt.plugin.Name(), err)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, tt.file)); err == nil {
+			t.Errorf("%s: %s written without -generate-index-files", tt.plugin.Name(), tt.file)
+		}
+	}
+}
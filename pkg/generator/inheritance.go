@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Interface inheritance: the parser copies inherited methods into each
+// interface that extends another, marked with InheritedFrom, so clients, method
+// lookups, test servers and mocks see the full method set without changes. The
+// typed interface declarations (Go interfaces, C# and Java interfaces, Python
+// ABCs, TypeScript interfaces) extend their parents and only declare their own
+// methods. Servers accept a call under either name: "Store.get" is served by the
+// Store handler, and "Catalog.get" falls back to the handler of an interface that
+// extends Catalog when no Catalog handler is registered. The fallback tables are
+// only generated when the IDL uses inheritance.
+
+// usesInterfaceInheritance reports whether any interface extends another
+func usesInterfaceInheritance(interfaces []*parser.Interface) bool {
+	for _, iface := range interfaces {
+		if len(iface.Extends) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// interfaceInheritor lists the interfaces that inherit the methods of an
+// extended interface, directly or through other interfaces
+type interfaceInheritor struct {
+	Name string
+	Subs []string
+}
+
+// subInterfaces returns every extended interface with the interfaces that
+// inherit from it, both in IDL order
+func subInterfaces(interfaces []*parser.Interface) []interfaceInheritor {
+	byName := make(map[string]*parser.Interface, len(interfaces))
+	for _, iface := range interfaces {
+		byName[iface.Name] = iface
+	}
+	subs := make(map[string][]string)
+	for _, iface := range interfaces {
+		seen := map[string]bool{iface.Name: true}
+		pending := append([]string(nil), iface.Extends...)
+		for len(pending) > 0 {
+			name := pending[0]
+			pending = pending[1:]
+			if seen[name] || byName[name] == nil {
+				continue
+			}
+			seen[name] = true
+			subs[name] = append(subs[name], iface.Name)
+			pending = append(pending, byName[name].Extends...)
+		}
+	}
+
+	var result []interfaceInheritor
+	for _, iface := range interfaces {
+		if len(subs[iface.Name]) > 0 {
+			result = append(result, interfaceInheritor{Name: iface.Name, Subs: subs[iface.Name]})
+		}
+	}
+	return result
+}
+
+// subInterfaceView is the view model for the interfaces that inherit the methods of
+// Name, as listed by the servers' sub-interface tables
+type subInterfaceView struct {
+	Name string
+	Subs string // "Sub", "SubSub"
+}
+
+// newSubInterfaceViews returns the views of subInterfaces(interfaces)
+func newSubInterfaceViews(interfaces []*parser.Interface) []subInterfaceView {
+	var views []subInterfaceView
+	for _, inh := range subInterfaces(interfaces) {
+		views = append(views, subInterfaceView{Name: inh.Name, Subs: quotedList(inh.Subs)})
+	}
+	return views
+}
+
+// interfacesParentsFirst orders interfaces so that each one follows the
+// interfaces it extends, keeping IDL order otherwise. Python needs base classes
+// defined before the classes that derive from them.
+func interfacesParentsFirst(interfaces []*parser.Interface) []*parser.Interface {
+	byName := make(map[string]*parser.Interface, len(interfaces))
+	for _, iface := range interfaces {
+		byName[iface.Name] = iface
+	}
+	ordered := make([]*parser.Interface, 0, len(interfaces))
+	added := make(map[string]bool, len(interfaces))
+	var add func(iface *parser.Interface)
+	add = func(iface *parser.Interface) {
+		if added[iface.Name] {
+			return
+		}
+		added[iface.Name] = true
+		for _, parent := range iface.Extends {
+			if p := byName[parent]; p != nil {
+				add(p)
+			}
+		}
+		ordered = append(ordered, iface)
+	}
+	for _, iface := range interfaces {
+		add(iface)
+	}
+	return ordered
+}
+
+// quotedList returns names as a comma separated list of double-quoted strings
+func quotedList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = strconv.Quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
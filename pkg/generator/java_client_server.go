@@ -1,13 +1,14 @@
 package generator
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/coopernurse/pulserpc/pkg/naming"
 	"github.com/coopernurse/pulserpc/pkg/parser"
 	"github.com/coopernurse/pulserpc/pkg/runtime"
 )
@@ -36,10 +37,21 @@ func (p *JavaClientServer) RegisterFlags(fs *flag.FlagSet) {
 	fs.String("base-package", "", "Base package name for generated Java classes (required, e.g., com.example.server)")
 	// Register json-lib flag for choosing between Jackson and GSON
 	fs.String("json-lib", "jackson", "JSON library to use: 'jackson' or 'gson'")
+	// Register legacy-root-copies flag for emitting un-packaged Server.java/Client.java
+	fs.Bool("legacy-root-copies", false, "Also write un-packaged Server.java and Client.java at the output root (legacy layout)")
+	// Register request-executor flag for serving requests on an Executor
+	fs.Bool("request-executor", false, "Run Server requests on an Executor, a virtual thread per request on JDK 21+ (a cached thread pool before), instead of the HttpServer default")
 }
 
 // Generate generates Java HTTP server and client code from the parsed IDL
-func (p *JavaClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
+func (p *JavaClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) (err error) {
+	ts, err := templatesFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	defer recoverTemplateError(&err)
+	start := repoFilesStart()
+
 	// Access the -dir flag value
 	dirFlag := fs.Lookup("dir")
 	outputDir := ""
@@ -58,6 +70,10 @@ func (p *JavaClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 	if basePackage == "" {
 		return fmt.Errorf("base-package flag is required for Java code generation")
 	}
+	if usesEncryptedFields(idl) {
+		// Generating without the hooks would send the fields in plaintext
+		return fmt.Errorf("[encrypted] fields are not supported by the Java generator yet")
+	}
 
 	// Get json-lib flag
 	jsonLibFlag := fs.Lookup("json-lib")
@@ -68,6 +84,10 @@ func (p *JavaClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 	if jsonLib != "jackson" && jsonLib != "gson" {
 		return fmt.Errorf("invalid json-lib value: %s (must be 'jackson' or 'gson')", jsonLib)
 	}
+	style, err := parseCodeStyle(fs, "java")
+	if err != nil {
+		return err
+	}
 
 	// Build type registries
 	structMap := make(map[string]*parser.Struct)
@@ -92,6 +112,12 @@ func (p *JavaClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 	// Group types by namespace
 	namespaceMap := GroupTypesByNamespace(idl)
 
+	// Structs named by [errordata] get an RPCError subclass next to them
+	errorDataUsed := make(map[string]bool)
+	for _, name := range errorDataStructs(idl.Interfaces) {
+		errorDataUsed[name] = true
+	}
+
 	// Generate separate files for each type with proper package structure
 	for namespace, types := range namespaceMap {
 		if namespace == "" {
@@ -108,52 +134,64 @@ func (p *JavaClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 
 		// Generate enum files
 		for _, enum := range types.Enums {
-			enumCode := generateEnumFile(enum, fullPackage)
+			enumCode := generateEnumFile(ts, enum, fullPackage)
 			enumName := GetBaseName(enum.Name)
 			enumPath := filepath.Join(packageDir, enumName+".java")
 			if err := os.MkdirAll(filepath.Dir(enumPath), 0755); err != nil {
 				return fmt.Errorf("failed to create package directory: %w", err)
 			}
-			if err := os.WriteFile(enumPath, []byte(enumCode), 0644); err != nil {
+			if err := writeGeneratedFile(enumPath, []byte(enumCode)); err != nil {
 				return fmt.Errorf("failed to write %s: %w", enumPath, err)
 			}
 		}
 
 		// Generate struct files (need to handle inheritance)
 		for _, structDef := range types.Structs {
-			structCode := generateStructFile(structDef, fullPackage, structMap, enumMap, jsonLib, basePackage)
+			structCode := generateStructFile(structDef, fullPackage, structMap, enumMap, jsonLib, basePackage, style.Getters)
 			structName := GetBaseName(structDef.Name)
 			structPath := filepath.Join(packageDir, structName+".java")
 			if err := os.MkdirAll(filepath.Dir(structPath), 0755); err != nil {
 				return fmt.Errorf("failed to create package directory: %w", err)
 			}
-			if err := os.WriteFile(structPath, []byte(structCode), 0644); err != nil {
+			if err := writeGeneratedFile(structPath, []byte(structCode)); err != nil {
 				return fmt.Errorf("failed to write %s: %w", structPath, err)
 			}
+			if testBuildersRequested(fs) {
+				builderPath := filepath.Join(packageDir, structName+"Builder.java")
+				if err := writeGeneratedFile(builderPath, []byte(generateTestBuilderJava(structDef, fullPackage, structMap, enumMap, basePackage))); err != nil {
+					return fmt.Errorf("failed to write %s: %w", builderPath, err)
+				}
+			}
+			if errorDataUsed[structDef.Name] {
+				errorPath := filepath.Join(packageDir, errorDataClass(structDef.Name)+".java")
+				if err := writeGeneratedFile(errorPath, []byte(generateErrorDataClassJava(structDef.Name, fullPackage))); err != nil {
+					return fmt.Errorf("failed to write %s: %w", errorPath, err)
+				}
+			}
 		}
 
 		// Generate interface files
 		for _, iface := range types.Interfaces {
-			interfaceCode := generateInterfaceFile(iface, fullPackage, structMap, enumMap, basePackage)
+			interfaceCode := generateInterfaceFile(ts, iface, fullPackage, enumMap, basePackage)
 			interfaceName := GetBaseName(iface.Name)
 			interfacePath := filepath.Join(packageDir, interfaceName+".java")
 			if err := os.MkdirAll(filepath.Dir(interfacePath), 0755); err != nil {
 				return fmt.Errorf("failed to create package directory: %w", err)
 			}
-			if err := os.WriteFile(interfacePath, []byte(interfaceCode), 0644); err != nil {
+			if err := writeGeneratedFile(interfacePath, []byte(interfaceCode)); err != nil {
 				return fmt.Errorf("failed to write %s: %w", interfacePath, err)
 			}
 		}
 
 		// Generate client files for each interface
 		for _, iface := range types.Interfaces {
-			clientCode := generateInterfaceClient(iface, fullPackage, enumMap, jsonLib, basePackage)
+			clientCode := generateInterfaceClient(ts, iface, fullPackage, enumMap, jsonLib, basePackage)
 			interfaceName := GetBaseName(iface.Name)
 			clientPath := filepath.Join(packageDir, interfaceName+"Client.java")
 			if err := os.MkdirAll(filepath.Dir(clientPath), 0755); err != nil {
 				return fmt.Errorf("failed to create package directory: %w", err)
 			}
-			if err := os.WriteFile(clientPath, []byte(clientCode), 0644); err != nil {
+			if err := writeGeneratedFile(clientPath, []byte(clientCode)); err != nil {
 				return fmt.Errorf("failed to write %s: %w", clientPath, err)
 			}
 		}
@@ -164,42 +202,144 @@ func (p *JavaClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 		if err := os.MkdirAll(filepath.Dir(nsIdlPath), 0755); err != nil {
 			return fmt.Errorf("failed to create package directory: %w", err)
 		}
-		if err := os.WriteFile(nsIdlPath, []byte(nsIdlCode), 0644); err != nil {
+		if err := writeGeneratedFile(nsIdlPath, []byte(nsIdlCode)); err != nil {
 			return fmt.Errorf("failed to write %s: %w", nsIdlPath, err)
 		}
 	}
 
+	// Generate package-info.java and <namespace>Types.java indexing each namespace package
+	if indexFilesRequested(fs) {
+		for _, ns := range buildNamespaceIndex(namespaceMap) {
+			fullPackage := basePackage
+			if packageName := strings.ToLower(ns.Namespace); packageName != strings.ToLower(basePackage) {
+				fullPackage = basePackage + "." + packageName
+			}
+			packageDir := filepath.Join(outputDir, "src/main/java", strings.ReplaceAll(fullPackage, ".", string(filepath.Separator)))
+			if err := writeGeneratedFile(filepath.Join(packageDir, "package-info.java"), []byte(generatePackageInfoJava(ns, fullPackage))); err != nil {
+				return fmt.Errorf("failed to write package-info.java: %w", err)
+			}
+			typesPath := filepath.Join(packageDir, ns.Namespace+"Types.java")
+			if err := writeGeneratedFile(typesPath, []byte(generateTypesJava(ns, fullPackage))); err != nil {
+				return fmt.Errorf("failed to write %s: %w", typesPath, err)
+			}
+		}
+	}
+
 	// Register Server.java and Client.java in the base package
-	serverCodePkg := generateServerJava(idl, structMap, namespaceMap, basePackage, basePackage)
+	requestExecutorFlag := fs.Lookup("request-executor")
+	requestExecutor := requestExecutorFlag != nil && requestExecutorFlag.Value.String() == "true"
+	idlDoc, err := newIDLJSONDocument(idl, fs)
+	if err != nil {
+		return err
+	}
+	serverCodePkg := generateServerJava(ts, idl, basePackage, basePackage, requestExecutor, idlDoc)
 	// Server and Client belong in the base package
 	basePackageDir := filepath.Join(outputDir, "src/main/java", strings.ReplaceAll(basePackage, ".", string(filepath.Separator)))
 	if err := os.MkdirAll(basePackageDir, 0755); err != nil {
 		return fmt.Errorf("failed to create base package directory: %w", err)
 	}
 	serverPath := filepath.Join(basePackageDir, "Server.java")
-	if err := os.WriteFile(serverPath, []byte(serverCodePkg), 0644); err != nil {
+	if err := writeGeneratedFile(serverPath, []byte(serverCodePkg)); err != nil {
 		return fmt.Errorf("failed to write Server.java: %w", err)
 	}
 
 	// Generate Client.java
-	clientCodePkg := generateClientJava(idl, namespaceMap, basePackage, basePackage)
+	clientCodePkg := generateClientJava(ts, basePackage)
 	clientPath := filepath.Join(basePackageDir, "Client.java")
-	if err := os.WriteFile(clientPath, []byte(clientCodePkg), 0644); err != nil {
+	if err := writeGeneratedFile(clientPath, []byte(clientCodePkg)); err != nil {
 		return fmt.Errorf("failed to write Client.java: %w", err)
 	}
 
-	// Write IDL JSON document for pulserpc-idl RPC method
-	jsonData, err := json.MarshalIndent(idl, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal IDL to JSON: %w", err)
+	// Generate ApiClient.java next to Client.java
+	if usesAPIClientFacade(idl.Interfaces) {
+		apiClientCode := generateAPIClientJava(idl.Interfaces, basePackage)
+		if err := writeGeneratedFile(filepath.Join(basePackageDir, "ApiClient.java"), []byte(apiClientCode)); err != nil {
+			return fmt.Errorf("failed to write ApiClient.java: %w", err)
+		}
+	}
+
+	// Generate DiscoveryTransport.java next to Client.java
+	discoveryCode := ts.renderString("java/DiscoveryTransport.java.tmpl", discoveryView{Package: basePackage})
+	if err := writeGeneratedFile(filepath.Join(basePackageDir, "DiscoveryTransport.java"), []byte(discoveryCode)); err != nil {
+		return fmt.Errorf("failed to write DiscoveryTransport.java: %w", err)
 	}
-	resourcesDir := filepath.Join(dirFlag.Value.String(), "src/main/resources")
-	if err := os.MkdirAll(resourcesDir, 0755); err != nil {
-		return fmt.Errorf("failed to create resources directory: %w", err)
+
+	// Generate RequestSigning.java next to Client.java
+	signingCode := ts.renderString("java/RequestSigning.java.tmpl", signingView{Package: basePackage})
+	if err := writeGeneratedFile(filepath.Join(basePackageDir, "RequestSigning.java"), []byte(signingCode)); err != nil {
+		return fmt.Errorf("failed to write RequestSigning.java: %w", err)
+	}
+
+	// Generate RetryTransport.java next to Client.java
+	retryCode := ts.renderString("java/RetryTransport.java.tmpl", retryView{Package: basePackage, Methods: idempotentMethods(idl)})
+	if err := writeGeneratedFile(filepath.Join(basePackageDir, "RetryTransport.java"), []byte(retryCode)); err != nil {
+		return fmt.Errorf("failed to write RetryTransport.java: %w", err)
+	}
+
+	// Generate ShadowTransport.java next to Client.java
+	if shadowClientRequested(fs) {
+		shadowCode := ts.renderString("java/ShadowTransport.java.tmpl", shadowView{Package: basePackage})
+		if err := writeGeneratedFile(filepath.Join(basePackageDir, "ShadowTransport.java"), []byte(shadowCode)); err != nil {
+			return fmt.Errorf("failed to write ShadowTransport.java: %w", err)
+		}
+	}
+
+	// Generate OutboxTransport.java next to Client.java
+	if outboxClientRequested(fs) {
+		outboxCode := ts.renderString("java/OutboxTransport.java.tmpl", outboxView{Package: basePackage})
+		if err := writeGeneratedFile(filepath.Join(basePackageDir, "OutboxTransport.java"), []byte(outboxCode)); err != nil {
+			return fmt.Errorf("failed to write OutboxTransport.java: %w", err)
+		}
+	}
+
+	// Generate StructPatch.java, shared by the client and the server
+	if patchHelpersRequested(fs) {
+		view := patchView{Package: basePackage}
+		for _, namespace := range sortedNamespaces(namespaceMap) {
+			view.IdlClasses = append(view.IdlClasses, basePackage+"."+strings.ToLower(namespace)+"."+namespace+"Idl")
+		}
+		patchCode := ts.renderString("java/StructPatch.java.tmpl", view)
+		if err := writeGeneratedFile(filepath.Join(basePackageDir, "StructPatch.java"), []byte(patchCode)); err != nil {
+			return fmt.Errorf("failed to write StructPatch.java: %w", err)
+		}
+	}
+
+	// Legacy layouts expected un-packaged copies at the output root. These collide
+	// with the packaged classes when the whole tree is compiled, so they are opt-in.
+	legacyRootCopiesFlag := fs.Lookup("legacy-root-copies")
+	if legacyRootCopiesFlag != nil && legacyRootCopiesFlag.Value.String() == "true" {
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		rootServerCode := generateServerJava(ts, idl, basePackage, "", requestExecutor, idlDoc)
+		if err := os.WriteFile(filepath.Join(outputDir, "Server.java"), []byte(rootServerCode), 0644); err != nil {
+			return fmt.Errorf("failed to write root Server.java: %w", err)
+		}
+		rootClientCode := generateClientJava(ts, "")
+		if err := os.WriteFile(filepath.Join(outputDir, "Client.java"), []byte(rootClientCode), 0644); err != nil {
+			return fmt.Errorf("failed to write root Client.java: %w", err)
+		}
 	}
-	jsonPath := filepath.Join(resourcesDir, "idl.json")
-	if err := os.WriteFile(jsonPath, jsonData, 0644); err != nil {
-		return fmt.Errorf("failed to write idl.json: %w", err)
+
+	// Write IDL JSON document for pulserpc-idl RPC method. The runtime reads the
+	// copy at the root of the classpath for its int checks and call logging; the
+	// Server loads the copy next to its class, at the -idl-json path, so the
+	// Servers of several IDLs can share a classpath.
+	if !idlDoc.Embedded() {
+		resourcesDir := filepath.Join(dirFlag.Value.String(), "src/main/resources")
+		rootDoc := idlJSONDocument{Path: idlJSONDefaultPath, Data: idlDoc.Data}
+		if err := rootDoc.Write(resourcesDir); err != nil {
+			return err
+		}
+		if err := idlDoc.Write(filepath.Join(resourcesDir, strings.ReplaceAll(basePackage, ".", string(filepath.Separator)))); err != nil {
+			return err
+		}
+	}
+
+	// Write testvectors.json if -generate-test-vectors is set
+	hasTestVectors, err := writeTestVectorsIfRequested(idl, fs, dirFlag.Value.String())
+	if err != nil {
+		return err
 	}
 
 	// Check if generate-test-files flag is set
@@ -215,46 +355,138 @@ func (p *JavaClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 			if ifaceNamespace != "" {
 				ifacePackage = basePackage + "." + strings.ToLower(ifaceNamespace)
 			}
-			implCode := generateTestInterfaceImplFile(iface, ifacePackage, structMap, enumMap, jsonLib, basePackage)
+			implCode := generateTestInterfaceImplFile(iface, ifacePackage, structMap, enumMap, jsonLib, basePackage, style.Getters)
 			implName := GetBaseName(iface.Name) + "Impl"
 			implPath := filepath.Join(dirFlag.Value.String(), "src/main/java", strings.ReplaceAll(ifacePackage, ".", string(filepath.Separator)), implName+".java")
 			if err := os.MkdirAll(filepath.Dir(implPath), 0755); err != nil {
 				return fmt.Errorf("failed to create package directory: %w", err)
 			}
-			if err := os.WriteFile(implPath, []byte(implCode), 0644); err != nil {
+			if err := writeGeneratedFile(implPath, []byte(implCode)); err != nil {
 				return fmt.Errorf("failed to write %s: %w", implPath, err)
 			}
 		}
 
 		// Generate TestServer.java in base package
-		testServerCode := generateTestServerJava(idl, jsonLib, basePackage, namespaceMap)
+		testServerCode := generateTestServerJava(ts, idl, jsonLib, basePackage, namespaceMap)
 		testServerDir := filepath.Join(dirFlag.Value.String(), "src/test/java", strings.ReplaceAll(basePackage, ".", string(filepath.Separator)))
 		if err := os.MkdirAll(testServerDir, 0755); err != nil {
 			return fmt.Errorf("failed to create test java directory: %w", err)
 		}
 		testServerPath := filepath.Join(testServerDir, "TestServer.java")
-		if err := os.WriteFile(testServerPath, []byte(testServerCode), 0644); err != nil {
+		if err := writeGeneratedFile(testServerPath, []byte(testServerCode)); err != nil {
 			return fmt.Errorf("failed to write TestServer.java: %w", err)
 		}
 
 		// Generate TestClient.java in base package
-		testClientCode := generateTestClientJava(idl, structMap, enumMap, jsonLib, basePackage, namespaceMap)
+		testClientCode := generateTestClientJava(idl, structMap, enumMap, jsonLib, basePackage, namespaceMap, hasTestVectors)
 		testClientPath := filepath.Join(testServerDir, "TestClient.java")
-		if err := os.WriteFile(testClientPath, []byte(testClientCode), 0644); err != nil {
+		if err := writeGeneratedFile(testClientPath, []byte(testClientCode)); err != nil {
 			return fmt.Errorf("failed to write TestClient.java: %w", err)
 		}
 
-		// Generate pom.xml
-		pomCode := generatePomXml(jsonLib)
+	}
+
+	// Generate a JUnit 5 handler test per interface, next to the test server
+	harness := testHarnessRequested(fs)
+	if harness {
+		testDir := filepath.Join(dirFlag.Value.String(), "src/test/java", strings.ReplaceAll(basePackage, ".", string(filepath.Separator)))
+		if err := os.MkdirAll(testDir, 0755); err != nil {
+			return fmt.Errorf("failed to create test java directory: %w", err)
+		}
+		view := javaHarnessView{
+			Package:         basePackage,
+			JSONParserClass: "GsonJsonParser",
+			Interfaces:      buildHarnessInterfaces(idl, naming.SnakeToPascal),
+		}
+		if jsonLib == "jackson" {
+			view.JSONParserClass = "JacksonJsonParser"
+		}
+		for _, iface := range view.Interfaces {
+			view.Interface = iface
+			testCode := ts.renderString("java/HandlerTest.java.tmpl", view)
+			testPath := filepath.Join(testDir, iface.Ident+"HandlerTest.java")
+			if err := writeGeneratedFile(testPath, []byte(testCode)); err != nil {
+				return fmt.Errorf("failed to write %s: %w", testPath, err)
+			}
+		}
+		handlersCode := ts.renderString("java/Handlers.java.tmpl", view)
+		if err := writeSkeletonFile(filepath.Join(testDir, "Handlers.java"), []byte(handlersCode)); err != nil {
+			return fmt.Errorf("failed to write Handlers.java: %w", err)
+		}
+	}
+
+	// Generate JUnit 5 contract tests from the IDL examples
+	contractView := buildContractView(idl, naming.SnakeToPascal)
+	contracts := contractTestsRequested(fs) && len(contractView.Interfaces) > 0
+	if contracts {
+		testDir := filepath.Join(dirFlag.Value.String(), "src/test/java", strings.ReplaceAll(basePackage, ".", string(filepath.Separator)))
+		if err := os.MkdirAll(testDir, 0755); err != nil {
+			return fmt.Errorf("failed to create test java directory: %w", err)
+		}
+		view := javaContractView{Package: basePackage, JSONParserClass: "GsonJsonParser", contractView: contractView}
+		if jsonLib == "jackson" {
+			view.JSONParserClass = "JacksonJsonParser"
+		}
+		contractCode := ts.renderString("java/ContractTest.java.tmpl", view)
+		if err := writeGeneratedFile(filepath.Join(testDir, "ContractTest.java"), []byte(contractCode)); err != nil {
+			return fmt.Errorf("failed to write ContractTest.java: %w", err)
+		}
+	}
+
+	versions, err := dependencyVersions(fs)
+	if err != nil {
+		return err
+	}
+	mirror, err := dependencyMirrorFlag(fs)
+	if err != nil {
+		return err
+	}
+	if dependencyManifestRequested(fs) {
+		if err := writeJavaDependencyManifest(dirFlag.Value.String(), javaDependencies(versions, jsonLib, harness || contracts), mirror); err != nil {
+			return err
+		}
+	}
+	if sbomRequested(fs) {
+		runtimeDir := filepath.Join("src/main/java", getRuntimePackageDirName())
+		if err := writeSBOM(outputDir, idl, "java", runtimeDir, javaDependencies(versions, jsonLib, harness || contracts)); err != nil {
+			return err
+		}
+	}
+
+	// Generate pom.xml
+	if generateTestServer || harness || contracts {
+		pomCode := generatePomXml(ts, jsonLib, harness || contracts, versions, mirror)
 		pomPath := filepath.Join(dirFlag.Value.String(), "pom.xml")
-		if err := os.WriteFile(pomPath, []byte(pomCode), 0644); err != nil {
+		if err := writeGeneratedFile(pomPath, []byte(pomCode)); err != nil {
 			return fmt.Errorf("failed to write pom.xml: %w", err)
 		}
 	}
 
+	if err := restyleGeneratedFiles("java", style, outputDir); err != nil {
+		return err
+	}
+	if repoFilesRequested(fs) {
+		return writeRepoFiles("java", style, start, outputDir)
+	}
 	return nil
 }
 
+// javaHarnessView is the view model for the <Interface>HandlerTest.java files and
+// Handlers.java. Interface is the interface of the test being rendered.
+type javaHarnessView struct {
+	Package         string
+	JSONParserClass string
+	Interfaces      []harnessInterface
+	Interface       harnessInterface
+}
+
+// javaContractView is the view model for ContractTest.java
+type javaContractView struct {
+	Package         string
+	JSONParserClass string
+	contractView
+}
+
 // copyRuntimeFiles copies the Java runtime library files to the output directory
 // Selectively copies files based on json-lib flag
 func (p *JavaClientServer) copyRuntimeFiles(outputDir string, jsonLib string) error {
@@ -284,28 +516,18 @@ func getRuntimePackageDirName() string {
 }
 
 // generateEnumFile generates a Java enum file
-func generateEnumFile(enum *parser.Enum, packageName string) string {
-	var sb strings.Builder
-
-	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
-	sb.WriteString(fmt.Sprintf("package %s;\n\n", packageName))
-
-	enumName := GetBaseName(enum.Name)
-	sb.WriteString(fmt.Sprintf("public enum %s {\n", enumName))
-	for i, value := range enum.Values {
-		fmt.Fprintf(&sb, "    %s", value.Name)
-		if i < len(enum.Values)-1 {
-			sb.WriteString(",")
-		}
-		sb.WriteString("\n")
-	}
-	sb.WriteString("}\n")
-
-	return sb.String()
+func generateEnumFile(ts *templateSet, enum *parser.Enum, packageName string) string {
+	return ts.renderString("java/Enum.java.tmpl", struct {
+		Package string
+		Enum    enumView
+	}{
+		Package: packageName,
+		Enum:    newEnumView(enum, GetBaseName(enum.Name), func(value string) string { return value }),
+	})
 }
 
 // generateStructFile generates a Java struct file
-func generateStructFile(structDef *parser.Struct, packageName string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, jsonLib string, basePackage string) string {
+func generateStructFile(structDef *parser.Struct, packageName string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, jsonLib string, basePackage string, getters string) string {
 	var sb strings.Builder
 
 	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
@@ -321,7 +543,6 @@ func generateStructFile(structDef *parser.Struct, packageName string, structMap
 
 	// Add imports for types from other packages
 	imports := make(map[string]bool)
-	_ = structMap
 	className := GetBaseName(structDef.Name)
 
 	// Check if struct extends another struct
@@ -342,12 +563,7 @@ func generateStructFile(structDef *parser.Struct, packageName string, structMap
 	}
 
 	// Write imports
-	for imp := range imports {
-		sb.WriteString(fmt.Sprintf("import %s;\n", imp))
-	}
-	if len(imports) > 0 {
-		sb.WriteString("\n")
-	}
+	writeJavaImports(&sb, imports)
 
 	// Generate class declaration
 	if structDef.Extends != "" {
@@ -371,7 +587,7 @@ func generateStructFile(structDef *parser.Struct, packageName string, structMap
 	// Generate fields
 	for _, field := range structDef.Fields {
 		fieldType := getJavaTypeWithPackage(field.Type, enumMap, basePackage, packageName)
-		fieldName := toCamelCase(field.Name)
+		fieldName := naming.LowerFirst(field.Name)
 
 		// Add JSON annotation based on library
 		switch jsonLib {
@@ -381,21 +597,37 @@ func generateStructFile(structDef *parser.Struct, packageName string, structMap
 			fmt.Fprintf(&sb, "    @SerializedName(\"%s\")\n", field.Name)
 		}
 
-		fmt.Fprintf(&sb, "    private %s %s;\n\n", fieldType, fieldName)
+		if structDef.IsImmutable() {
+			fmt.Fprintf(&sb, "    private final %s %s;\n\n", fieldType, fieldName)
+		} else {
+			fmt.Fprintf(&sb, "    private %s %s;\n\n", fieldType, fieldName)
+		}
+	}
+
+	// Generate constructors and copy()
+	if structDef.IsImmutable() {
+		writeImmutableConstructorsJava(&sb, structDef, structMap, enumMap, basePackage, packageName)
+	}
+	writeCopyMembersJava(&sb, structDef, structMap, basePackage, packageName)
+	if needsRedaction(structDef, structMap) {
+		writeToStringJava(&sb, structDef, structMap, getters)
 	}
 
 	// Generate getters and setters
 	for _, field := range structDef.Fields {
 		fieldType := getJavaTypeWithPackage(field.Type, enumMap, basePackage, packageName)
-		fieldName := toCamelCase(field.Name)
-		capitalizedName := capitalizeFirst(fieldName)
+		fieldName := naming.LowerFirst(field.Name)
+		capitalizedName := naming.UpperFirst(fieldName)
 
 		// Getter
-		fmt.Fprintf(&sb, "    public %s get%s() {\n", fieldType, capitalizedName)
+		fmt.Fprintf(&sb, "    public %s %s() {\n", fieldType, getGetterName(fieldName, getters))
 		fmt.Fprintf(&sb, "        return %s;\n", fieldName)
 		sb.WriteString("    }\n\n")
 
-		// Setter
+		// Setter, left out of immutable classes
+		if structDef.IsImmutable() {
+			continue
+		}
 		fmt.Fprintf(&sb, "    public void set%s(%s %s) {\n", capitalizedName, fieldType, fieldName)
 		fmt.Fprintf(&sb, "        this.%s = %s;\n", fieldName, fieldName)
 		sb.WriteString("    }\n\n")
@@ -406,176 +638,142 @@ func generateStructFile(structDef *parser.Struct, packageName string, structMap
 	return sb.String()
 }
 
-// generateInterfaceFile generates a Java interface file
-func generateInterfaceFile(iface *parser.Interface, packageName string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, basePackage string) string {
-	var sb strings.Builder
-
-	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
-	sb.WriteString(fmt.Sprintf("package %s;\n\n", packageName))
-
-	// Add imports for types from other packages
-	imports := make(map[string]bool)
-	_ = structMap
-	interfaceName := GetBaseName(iface.Name)
+// javaInterfaceView is the view model for the interface and client files of an IDL
+// interface
+type javaInterfaceView struct {
+	Package string
+	Name    string
+	// Imports are the types of other packages the interface's own methods use
+	Imports []string
+	// Extends is the comma separated list of parent interfaces
+	Extends string
+	Async   bool
+	Jackson bool
+	Methods []javaMethodView
+}
 
-	// Check method parameter and return types for imports
-	for _, method := range iface.Methods {
-		if method.ReturnType != nil {
-			addTypeImports(method.ReturnType, basePackage, packageName, imports)
-		}
-		for _, param := range method.Parameters {
-			addTypeImports(param.Type, basePackage, packageName, imports)
-		}
-	}
+// javaMethodView is the view model for a method of an interface or its client
+type javaMethodView struct {
+	Name string
+	// ReturnType is "void" for methods without a result
+	ReturnType string
+	// ResultType is the boxed result type used for decoding, empty without a result
+	ResultType     string
+	ReturnOptional bool
+	Params         []javaParamView
+	// Args is the comma separated list of parameter names
+	Args string
+	// ParamNames is the quoted list of parameter names sent with named params, empty
+	// for methods without parameters
+	ParamNames string
+	RPCName    string
+	Async      bool
+	Jackson    bool
+	// ErrorClass is the RPCError subclass of the method's [errordata] struct
+	ErrorClass string
+	// Overloads leave out trailing optional parameters, which the server replaces
+	// with their defaults
+	Overloads []javaOverloadView
+}
 
-	// Write imports
-	for imp := range imports {
-		sb.WriteString(fmt.Sprintf("import %s;\n", imp))
-	}
-	if len(imports) > 0 {
-		sb.WriteString("\n")
-	}
+type javaParamView struct {
+	Name string
+	Type string
+}
 
-	// Generate interface declaration
-	fmt.Fprintf(&sb, "public interface %s {\n", interfaceName)
+// javaOverloadView is the view model for an overload that passes null for the
+// left out parameters
+type javaOverloadView struct {
+	Params []javaParamView
+	Args   string
+}
 
-	// Generate methods
-	for _, method := range iface.Methods {
-		returnType := "void"
+// newJavaInterfaceView builds the view of an interface for the file in packageName.
+// The interface declares its own methods, the client all of them.
+func newJavaInterfaceView(iface *parser.Interface, methods []*parser.Method, packageName string, enumMap map[string]*parser.Enum, jsonLib string, basePackage string) javaInterfaceView {
+	view := javaInterfaceView{
+		Package: packageName,
+		Name:    GetBaseName(iface.Name),
+		Async:   usesAsyncMethods([]*parser.Interface{iface}),
+		Jackson: jsonLib == "jackson",
+	}
+	for _, method := range methods {
+		mv := javaMethodView{
+			Name:           method.Name,
+			ReturnType:     "void",
+			ReturnOptional: method.ReturnOptional,
+			RPCName:        iface.RPCName(method),
+			Async:          method.IsAsync(),
+			Jackson:        view.Jackson,
+		}
 		if method.ReturnType != nil {
-			returnType = getJavaTypeWithPackage(method.ReturnType, enumMap, basePackage, packageName)
+			mv.ReturnType = getJavaTypeWithPackage(method.ReturnType, enumMap, basePackage, packageName)
+			var sb strings.Builder
+			writeJavaType(&sb, method.ReturnType, enumMap, basePackage, packageName)
+			mv.ResultType = sb.String()
 		}
-
-		fmt.Fprintf(&sb, "    public %s %s(", returnType, method.Name)
-
-		// Parameters
+		names := make([]string, len(method.Parameters))
 		for i, param := range method.Parameters {
-			if i > 0 {
-				sb.WriteString(", ")
-			}
-			paramType := getJavaTypeWithPackage(param.Type, enumMap, basePackage, packageName)
-			fmt.Fprintf(&sb, "%s %s", paramType, param.Name)
+			mv.Params = append(mv.Params, javaParamView{Name: param.Name, Type: getJavaParamType(param, enumMap, basePackage, packageName)})
+			names[i] = param.Name
 		}
-		sb.WriteString(");\n\n")
-	}
-
-	sb.WriteString("}\n")
-
-	return sb.String()
-}
-
-// generateInterfaceClient generates a client class for an interface
-func generateInterfaceClient(iface *parser.Interface, packageName string, enumMap map[string]*parser.Enum, jsonLib string, basePackage string) string {
-	var sb strings.Builder
-
-	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
-	sb.WriteString(fmt.Sprintf("package %s;\n\n", packageName))
-
-	// Add imports
-	sb.WriteString("import com.bitmechanic.pulserpc.*;\n")
-	if jsonLib == "jackson" {
-		sb.WriteString("import com.fasterxml.jackson.core.type.TypeReference;\n")
-	}
-
-	interfaceName := GetBaseName(iface.Name)
-	clientName := interfaceName + "Client"
-
-	// Generate class declaration - interface is in same package, so no qualification needed
-	sb.WriteString("public class ")
-	sb.WriteString(clientName)
-	sb.WriteString(" implements ")
-	sb.WriteString(interfaceName)
-	sb.WriteString(" {\n")
-	sb.WriteString("    private final Transport transport;\n")
-	sb.WriteString("    private final JsonParser jsonParser;\n\n")
-
-	// Constructor
-	sb.WriteString("    public ")
-	sb.WriteString(clientName)
-	sb.WriteString("(Transport transport, JsonParser jsonParser) {\n")
-	sb.WriteString("        this.transport = transport;\n")
-	sb.WriteString("        this.jsonParser = jsonParser;\n")
-	sb.WriteString("    }\n\n")
-
-	// Generate methods
-	for _, method := range iface.Methods {
-		returnType := "void"
-		if method.ReturnType != nil {
-			returnType = getJavaTypeWithPackage(method.ReturnType, enumMap, basePackage, packageName)
+		mv.Args = strings.Join(names, ", ")
+		if len(names) > 0 {
+			mv.ParamNames = quotedList(names)
 		}
-
-		fmt.Fprintf(&sb, "    @Override\n")
-		fmt.Fprintf(&sb, "    public %s %s(", returnType, method.Name)
-
-		// Parameters
-		for i, param := range method.Parameters {
-			if i > 0 {
-				sb.WriteString(", ")
-			}
-			paramType := getJavaTypeWithPackage(param.Type, enumMap, basePackage, packageName)
-			fmt.Fprintf(&sb, "%s %s", paramType, param.Name)
+		if name := method.ErrorData(); name != "" {
+			// Errors whose data is the [errordata] struct are thrown as its RPCError subclass
+			mv.ErrorClass = getJavaTypeWithPackage(&parser.Type{UserDefined: name}, enumMap, basePackage, packageName) + "Error"
 		}
-		sb.WriteString(") {\n")
-
-		// Method implementation
-		sb.WriteString("        try {\n")
-		fmt.Fprintf(&sb, "            String method = \"%s.%s\";\n", interfaceName, method.Name)
-
-		// Build parameters array
-		sb.WriteString("            Object[] params = new Object[] { ")
-		for i, param := range method.Parameters {
-			if i > 0 {
-				sb.WriteString(", ")
+		for n := method.RequiredParams(); n < len(method.Parameters); n++ {
+			args := make([]string, len(method.Parameters))
+			for i := range args {
+				args[i] = "null"
+				if i < n {
+					args[i] = names[i]
+				}
 			}
-			fmt.Fprintf(&sb, "%s", param.Name)
+			mv.Overloads = append(mv.Overloads, javaOverloadView{Params: mv.Params[:n], Args: strings.Join(args, ", ")})
 		}
-		sb.WriteString(" };\n\n")
-
-		// Create request and call transport
-		sb.WriteString("            Request rpcRequest = new Request(method, params, java.util.UUID.randomUUID().toString());\n")
-		sb.WriteString("            Response response = transport.call(rpcRequest);\n\n")
+		view.Methods = append(view.Methods, mv)
+	}
+	return view
+}
 
-		// Handle return value
+// generateInterfaceFile generates a Java interface file. Inherited methods are declared
+// by the parent interfaces.
+func generateInterfaceFile(ts *templateSet, iface *parser.Interface, packageName string, enumMap map[string]*parser.Enum, basePackage string) string {
+	view := newJavaInterfaceView(iface, iface.OwnMethods(), packageName, enumMap, "", basePackage)
+	imports := make(map[string]bool)
+	for _, method := range iface.OwnMethods() {
 		if method.ReturnType != nil {
-			sb.WriteString("            if (response.getResult() == null) {\n")
-			if method.ReturnOptional {
-				sb.WriteString("                return null;\n")
-			} else {
-				sb.WriteString("                throw new RPCError(-32603, \"Internal error\", \"Missing result in response\");\n")
-			}
-			sb.WriteString("            }\n\n")
-
-			// Deserialize result
-			sb.WriteString("            String resultJson = jsonParser.toJson(response.getResult());\n")
-			if jsonLib == "jackson" {
-				// Jackson: Use TypeReference wrapped in a Type
-				sb.WriteString("            com.fasterxml.jackson.core.type.TypeReference<")
-				writeJavaType(&sb, method.ReturnType, enumMap, basePackage, packageName)
-				sb.WriteString("> typeRef = new com.fasterxml.jackson.core.type.TypeReference<")
-				writeJavaType(&sb, method.ReturnType, enumMap, basePackage, packageName)
-				sb.WriteString(">() {};\n")
-				sb.WriteString("            return jsonParser.fromJson(resultJson, typeRef.getType());\n")
-			} else {
-				// Gson uses TypeToken
-				sb.WriteString("            java.lang.reflect.Type type = new com.google.gson.reflect.TypeToken<")
-				writeJavaType(&sb, method.ReturnType, enumMap, basePackage, packageName)
-				sb.WriteString(">(){}.getType();\n")
-				sb.WriteString("            return jsonParser.fromJson(resultJson, type);\n")
-			}
+			addTypeImports(method.ReturnType, basePackage, packageName, imports)
 		}
-
-		sb.WriteString("        } catch (Exception e) {\n")
-		sb.WriteString("            if (e instanceof RPCError) {\n")
-		sb.WriteString("                throw (RPCError) e;\n")
-		sb.WriteString("            }\n")
-		sb.WriteString("            throw new RPCError(-32603, \"Internal error\", e.getMessage());\n")
-		sb.WriteString("        }\n")
-		sb.WriteString("    }\n\n")
+		for _, param := range method.Parameters {
+			addTypeImports(param.Type, basePackage, packageName, imports)
+		}
+	}
+	parents := make([]string, len(iface.Extends))
+	for i, parent := range iface.Extends {
+		addTypeImports(&parser.Type{UserDefined: parent}, basePackage, packageName, imports)
+		parents[i] = GetBaseName(parent)
+	}
+	view.Extends = strings.Join(parents, ", ")
+	for imp := range imports {
+		view.Imports = append(view.Imports, imp)
 	}
+	sort.Strings(view.Imports)
+	return ts.renderString("java/Interface.java.tmpl", view)
+}
 
-	sb.WriteString("}\n")
+// generateInterfaceClient generates a client class for an interface
+func generateInterfaceClient(ts *templateSet, iface *parser.Interface, packageName string, enumMap map[string]*parser.Enum, jsonLib string, basePackage string) string {
+	return ts.renderString("java/InterfaceClient.java.tmpl", newJavaInterfaceView(iface, iface.Methods, packageName, enumMap, jsonLib, basePackage))
+}
 
-	return sb.String()
+// generateClientJava generates the Client.java file
+func generateClientJava(ts *templateSet, packageDecl string) string {
+	return ts.renderString("java/Client.java.tmpl", struct{ PackageDecl string }{packageDecl})
 }
 
 // Helper functions for type handling
@@ -625,6 +823,15 @@ func getJavaTypeWithPackage(t *parser.Type, enumMap map[string]*parser.Enum, bas
 	return "Object"
 }
 
+// getJavaParamType returns the Java type of a method parameter. Optional parameters
+// use boxed types so that a left-out parameter can be passed as null.
+func getJavaParamType(param *parser.Parameter, enumMap map[string]*parser.Enum, basePackage string, currentPackage string) string {
+	if param.Optional {
+		return getJavaTypeWithPackageForGeneric(param.Type, basePackage, currentPackage)
+	}
+	return getJavaTypeWithPackage(param.Type, enumMap, basePackage, currentPackage)
+}
+
 // getJavaTypeWithPackageForGeneric returns Java type name for use in generics (uses boxed types for primitives)
 func getJavaTypeWithPackageForGeneric(t *parser.Type, basePackage string, currentPackage string) string {
 	if t.IsBuiltIn() {
@@ -746,6 +953,9 @@ func generateNamespaceJava(namespace string, types *NamespaceTypes, enumMap map[
 			if field.Optional {
 				sb.WriteString("                f.put(\"optional\", true);\n")
 			}
+			if field.IsSensitive() {
+				sb.WriteString("                f.put(\"sensitive\", true);\n")
+			}
 			sb.WriteString("                fields.add(f);\n")
 			sb.WriteString("            }\n")
 		}
@@ -817,7 +1027,7 @@ func generateStructClassJava(sb *strings.Builder, structDef *parser.Struct, enum
 	// Generate fields
 	for _, field := range structDef.Fields {
 		fieldType := getJavaType(field.Type, enumMap)
-		fieldName := toCamelCase(field.Name)
+		fieldName := naming.LowerFirst(field.Name)
 
 		// Add JSON annotation based on library
 		switch jsonLib {
@@ -833,8 +1043,8 @@ func generateStructClassJava(sb *strings.Builder, structDef *parser.Struct, enum
 	// Generate getters and setters
 	for _, field := range structDef.Fields {
 		fieldType := getJavaType(field.Type, enumMap)
-		fieldName := toCamelCase(field.Name)
-		capitalizedName := capitalizeFirst(fieldName)
+		fieldName := naming.LowerFirst(field.Name)
+		capitalizedName := naming.UpperFirst(fieldName)
 
 		// Getter
 		fmt.Fprintf(sb, "    public %s get%s() {\n", fieldType, capitalizedName)
@@ -850,405 +1060,138 @@ func generateStructClassJava(sb *strings.Builder, structDef *parser.Struct, enum
 	sb.WriteString("}\n")
 }
 
-// generateServerJava generates the Server.java file
-func generateServerJava(idl *parser.IDL, _ map[string]*parser.Struct, namespaceMap map[string]*NamespaceTypes, basePackage string, packageDecl string) string {
-	_ = namespaceMap
-	var sb strings.Builder
+// javaServerView is the view model for Server.java
+type javaServerView struct {
+	PackageDecl string
+	// Imports are the interfaces declared in namespace packages, in sorted order
+	Imports         []string
+	RequestExecutor bool
+	ReadOnlyRoutes  []javaRouteView
+	SubInterfaces   []subInterfaceView
+	MethodTable     []javaMethodTableView
+
+	// IDL features that add code to the server
+	AsyncJobs            bool
+	Cached               bool
+	Compressed           bool
+	InterfaceInheritance bool
+	OptionalParams       bool
+	WireNames            bool
+
+	// Sections written by the generators of optional features, empty when unused
+	Canonical    string
+	Compose      string
+	Capabilities string
+	WireMethods  string
+	Compression  string
+	JobsServer   string
+	CacheHelpers string
+}
 
-	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
-	if packageDecl != "" {
-		sb.WriteString(fmt.Sprintf("package %s;\n\n", packageDecl))
+// javaRouteView is the view model for the GET route of a [readonly] method
+type javaRouteView struct {
+	Path      string
+	RPCMethod string
+	// ParamNames and ParamTypes are quoted lists, e.g. "id", "limit"
+	ParamNames   string
+	ParamTypes   string
+	CacheControl string
+}
+
+// javaMethodTableView is the view model for the entry of a method in the server's
+// parameter tables
+type javaMethodTableView struct {
+	// Key is the method's "Interface.method" name
+	Key        string
+	ParamNames string
+	// Optional reports that the method has optional parameters, taking Required
+	// params and Defaults, the Java literal of each parameter's default or null
+	Optional bool
+	Required int
+	Defaults string
+}
+
+// generateServerJava generates the Server.java file. With requestExecutor, requests run
+// on the Server's defaultExecutor() unless an Executor is passed in.
+func generateServerJava(ts *templateSet, idl *parser.IDL, basePackage string, packageDecl string, requestExecutor bool, idlDoc idlJSONDocument) string {
+	interfaces := idl.Interfaces
+	view := javaServerView{
+		PackageDecl:          packageDecl,
+		RequestExecutor:      requestExecutor,
+		SubInterfaces:        newSubInterfaceViews(interfaces),
+		AsyncJobs:            usesAsyncMethods(interfaces),
+		Cached:               usesCachedMethods(interfaces),
+		Compressed:           usesCompressedMethods(interfaces),
+		InterfaceInheritance: usesInterfaceInheritance(interfaces),
+		OptionalParams:       usesOptionalParams(interfaces),
+		WireNames:            usesWireNames(interfaces),
+		Canonical:            capture(writeCanonicalServerJava),
+		Compose:              capture(func(sb *strings.Builder) { writeComposeServerJava(sb, interfaces, idlDoc) }),
+		Capabilities:         capture(func(sb *strings.Builder) { writeServerCapabilitiesJava(sb, interfaces) }),
 	}
-	sb.WriteString("import com.bitmechanic.pulserpc.*;\n")
-	sb.WriteString("import com.sun.net.httpserver.HttpServer;\n")
-	sb.WriteString("import com.sun.net.httpserver.HttpExchange;\n")
-	sb.WriteString("import java.io.*;\n")
-	sb.WriteString("import java.net.*;\n")
-	sb.WriteString("import java.util.*;\n")
-	sb.WriteString("import java.lang.reflect.*;\n\n")
-
-	// Add imports for interfaces
+
 	imports := make(map[string]bool)
-	for _, iface := range idl.Interfaces {
-		ifaceNamespace := GetNamespaceFromType(iface.Name, iface.Namespace)
-		if ifaceNamespace != "" {
-			ifacePackage := basePackage + "." + strings.ToLower(ifaceNamespace)
-			ifaceName := GetBaseName(iface.Name)
-			imports[ifacePackage+"."+ifaceName] = true
+	for _, iface := range interfaces {
+		if ns := GetNamespaceFromType(iface.Name, iface.Namespace); ns != "" {
+			imports[basePackage+"."+strings.ToLower(ns)+"."+GetBaseName(iface.Name)] = true
 		}
 	}
 	for imp := range imports {
-		sb.WriteString(fmt.Sprintf("import %s;\n", imp))
+		view.Imports = append(view.Imports, imp)
 	}
-	if len(imports) > 0 {
-		sb.WriteString("\n")
-	}
-
-	sb.WriteString("public class Server {\n")
-	sb.WriteString("    private final HttpServer server;\n")
-	sb.WriteString("    private final JsonParser jsonParser;\n")
-	sb.WriteString("    private final Map<String, Object> interfaceHandlers;\n\n")
-
-	// Constructor
-	sb.WriteString("    public Server(int port, JsonParser jsonParser) throws IOException {\n")
-	sb.WriteString("        this.jsonParser = jsonParser;\n")
-	sb.WriteString("        this.server = HttpServer.create(new InetSocketAddress(port), 0);\n")
-	sb.WriteString("        this.server.createContext(\"/\", this::handleRequest);\n")
-	sb.WriteString("        this.interfaceHandlers = new HashMap<>();\n")
-	sb.WriteString("    }\n\n")
-
-	// Register interface implementation
-	sb.WriteString("    public void register(String interfaceName, Object implementation) {\n")
-	sb.WriteString("        interfaceHandlers.put(interfaceName, implementation);\n")
-	sb.WriteString("    }\n\n")
-
-	// Start method
-	sb.WriteString("    public void start() {\n")
-	sb.WriteString("        server.start();\n")
-	sb.WriteString("        System.out.println(\"Server started on port \" + server.getAddress().getPort());\n")
-	sb.WriteString("    }\n\n")
-
-	// Stop method
-	sb.WriteString("    public void stop() {\n")
-	sb.WriteString("        server.stop(0);\n")
-	sb.WriteString("    }\n\n")
-
-	// Handle request method
-	sb.WriteString("    private void handleRequest(HttpExchange exchange) throws IOException {\n")
-	sb.WriteString("        try {\n")
-	sb.WriteString("            if (!\"POST\".equals(exchange.getRequestMethod())) {\n")
-	sb.WriteString("                sendError(exchange, -32600, \"Invalid Request - only POST allowed\");\n")
-	sb.WriteString("                return;\n")
-	sb.WriteString("            }\n\n")
-	sb.WriteString("            // Read request body\n")
-	sb.WriteString("            String requestBody = new String(exchange.getRequestBody().readAllBytes());\n\n")
-	sb.WriteString("            // Parse JSON-RPC request\n")
-	sb.WriteString("            Map<String, Object> request = jsonParser.fromJson(requestBody, Map.class);\n\n")
-	sb.WriteString("            // Handle the request\n")
-	sb.WriteString("            Map<String, Object> response = handleJsonRpcRequest(request);\n\n")
-	sb.WriteString("            // Send response\n")
-	sb.WriteString("            String responseBody = jsonParser.toJson(response);\n")
-	sb.WriteString("            exchange.getResponseHeaders().set(\"Content-Type\", \"application/json\");\n")
-	sb.WriteString("            exchange.sendResponseHeaders(200, responseBody.getBytes().length);\n")
-	sb.WriteString("            try (OutputStream os = exchange.getResponseBody()) {\n")
-	sb.WriteString("                os.write(responseBody.getBytes());\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("        } catch (Exception e) {\n")
-	sb.WriteString("            sendError(exchange, -32603, \"Internal error: \" + e.getMessage());\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("    }\n\n")
+	sort.Strings(view.Imports)
 
-	// Error response helper
-	sb.WriteString("    private void sendError(HttpExchange exchange, int code, String message) throws IOException {\n")
-	sb.WriteString("        Map<String, Object> error = Map.of(\n")
-	sb.WriteString("            \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("            \"error\", Map.of(\n")
-	sb.WriteString("                \"code\", code,\n")
-	sb.WriteString("                \"message\", message\n")
-	sb.WriteString("            ),\n")
-	sb.WriteString("            \"id\", null\n")
-	sb.WriteString("        );\n")
-	sb.WriteString("        String errorBody = jsonParser.toJson(error);\n")
-	sb.WriteString("        exchange.getResponseHeaders().set(\"Content-Type\", \"application/json\");\n")
-	sb.WriteString("        exchange.sendResponseHeaders(200, errorBody.getBytes().length);\n")
-	sb.WriteString("        try (OutputStream os = exchange.getResponseBody()) {\n")
-	sb.WriteString("            os.write(errorBody.getBytes());\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("    }\n\n")
-
-	// Handle JSON-RPC request
-	sb.WriteString("    private Map<String, Object> handleJsonRpcRequest(Map<String, Object> request) {\n")
-	sb.WriteString("        // Validate jsonrpc field\n")
-	sb.WriteString("        Object jsonrpc = request.get(\"jsonrpc\");\n")
-	sb.WriteString("        if (jsonrpc == null || !\"2.0\".equals(jsonrpc)) {\n")
-	sb.WriteString("            Object id = request.get(\"id\");\n")
-	sb.WriteString("            return Map.of(\n")
-	sb.WriteString("                \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("                \"error\", Map.of(\n")
-	sb.WriteString("                    \"code\", -32600,\n")
-	sb.WriteString("                    \"message\", \"Invalid Request - jsonrpc must be '2.0'\"\n")
-	sb.WriteString("                ),\n")
-	sb.WriteString("                \"id\", id\n")
-	sb.WriteString("            );\n")
-	sb.WriteString("        }\n\n")
-	sb.WriteString("        String method = (String) request.get(\"method\");\n")
-	sb.WriteString("        Object id = request.get(\"id\");\n")
-	sb.WriteString("        Object params = request.get(\"params\");\n\n")
-	sb.WriteString("        if (\"pulserpc-idl\".equals(method)) {\n")
-	sb.WriteString("            // Return IDL definition - read from idl.json in resources\n")
-	sb.WriteString("            try {\n")
-	sb.WriteString("                InputStream is = Server.class.getResourceAsStream(\"/idl.json\");\n")
-	sb.WriteString("                if (is == null) {\n")
-	sb.WriteString("                    return Map.of(\n")
-	sb.WriteString("                        \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("                        \"error\", Map.of(\n")
-	sb.WriteString("                            \"code\", -32603,\n")
-	sb.WriteString("                            \"message\", \"Failed to load IDL: /idl.json not found in classpath\"\n")
-	sb.WriteString("                        ),\n")
-	sb.WriteString("                        \"id\", id\n")
-	sb.WriteString("                    );\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("                String idlJson = new String(is.readAllBytes());\n")
-	sb.WriteString("                Object idlDoc = jsonParser.fromJson(idlJson, Object.class);\n")
-	sb.WriteString("                return Map.of(\n")
-	sb.WriteString("                    \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("                    \"result\", idlDoc,\n")
-	sb.WriteString("                    \"id\", id\n")
-	sb.WriteString("                );\n")
-	sb.WriteString("            } catch (Exception e) {\n")
-	sb.WriteString("                return Map.of(\n")
-	sb.WriteString("                    \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("                    \"error\", Map.of(\n")
-	sb.WriteString("                        \"code\", -32603,\n")
-	sb.WriteString("                        \"message\", \"Failed to load IDL: \" + e.getMessage()\n")
-	sb.WriteString("                    ),\n")
-	sb.WriteString("                    \"id\", id\n")
-	sb.WriteString("                );\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("        }\n\n")
-	sb.WriteString("        // Parse method name: interface.method\n")
-	sb.WriteString("        String[] parts = method.split(\"\\\\.\", 2);\n")
-	sb.WriteString("        if (parts.length != 2) {\n")
-	sb.WriteString("            return Map.of(\n")
-	sb.WriteString("                \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("                \"error\", Map.of(\n")
-	sb.WriteString("                    \"code\", -32601,\n")
-	sb.WriteString("                    \"message\", \"Invalid method format: \" + method\n")
-	sb.WriteString("                ),\n")
-	sb.WriteString("                \"id\", id\n")
-	sb.WriteString("            );\n")
-	sb.WriteString("        }\n\n")
-	sb.WriteString("        String interfaceName = parts[0];\n")
-	sb.WriteString("        String methodName = parts[1];\n\n")
-	sb.WriteString("        // Find interface handler\n")
-	sb.WriteString("        Object handler = interfaceHandlers.get(interfaceName);\n")
-	sb.WriteString("        if (handler == null) {\n")
-	sb.WriteString("            return Map.of(\n")
-	sb.WriteString("                \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("                \"error\", Map.of(\n")
-	sb.WriteString("                    \"code\", -32601,\n")
-	sb.WriteString("                    \"message\", \"Interface not found: \" + interfaceName\n")
-	sb.WriteString("                ),\n")
-	sb.WriteString("                \"id\", id\n")
-	sb.WriteString("            );\n")
-	sb.WriteString("        }\n\n")
-	sb.WriteString("                // Invoke method using reflection\n")
-	sb.WriteString("        try {\n")
-	sb.WriteString("            // Handle null params (methods with no parameters)\n")
-	sb.WriteString("            List<?> paramList;\n")
-	sb.WriteString("            if (params == null) {\n")
-	sb.WriteString("                paramList = new ArrayList<>();\n")
-	sb.WriteString("            } else if (params instanceof List) {\n")
-	sb.WriteString("                paramList = (List<?>) params;\n")
-	sb.WriteString("            } else {\n")
-	sb.WriteString("                return Map.of(\n")
-	sb.WriteString("                    \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("                    \"error\", Map.of(\n")
-	sb.WriteString("                        \"code\", -32602,\n")
-	sb.WriteString("                        \"message\", \"Invalid params: must be an array\"\n")
-	sb.WriteString("                    ),\n")
-	sb.WriteString("                    \"id\", id\n")
-	sb.WriteString("                );\n")
-	sb.WriteString("            }\n\n")
-	sb.WriteString("            Class<?> handlerClass = handler.getClass();\n")
-	sb.WriteString("            Method[] methods = handlerClass.getMethods();\n")
-	sb.WriteString("            Method targetMethod = null;\n")
-	sb.WriteString("            boolean methodNameFound = false;\n")
-	sb.WriteString("            for (Method m : methods) {\n")
-	sb.WriteString("                if (m.getName().equals(methodName)) {\n")
-	sb.WriteString("                    methodNameFound = true;\n")
-	sb.WriteString("                    if (m.getParameterCount() == paramList.size()) {\n")
-	sb.WriteString("                        targetMethod = m;\n")
-	sb.WriteString("                        break;\n")
-	sb.WriteString("                    }\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("            }\n\n")
-	sb.WriteString("            if (!methodNameFound) {\n")
-	sb.WriteString("                return Map.of(\n")
-	sb.WriteString("                    \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("                    \"error\", Map.of(\n")
-	sb.WriteString("                        \"code\", -32601,\n")
-	sb.WriteString("                        \"message\", \"Method not found: \" + method\n")
-	sb.WriteString("                    ),\n")
-	sb.WriteString("                    \"id\", id\n")
-	sb.WriteString("                );\n")
-	sb.WriteString("            }\n\n")
-	sb.WriteString("            if (targetMethod == null) {\n")
-	sb.WriteString("                return Map.of(\n")
-	sb.WriteString("                    \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("                    \"error\", Map.of(\n")
-	sb.WriteString("                        \"code\", -32602,\n")
-	sb.WriteString("                        \"message\", \"Invalid params: parameter count mismatch for \" + method\n")
-	sb.WriteString("                    ),\n")
-	sb.WriteString("                    \"id\", id\n")
-	sb.WriteString("                );\n")
-	sb.WriteString("            }\n\n")
-	sb.WriteString("            // Deserialize parameters using generic types\n")
-	sb.WriteString("            java.lang.reflect.Type[] paramTypes = targetMethod.getGenericParameterTypes();\n")
-	sb.WriteString("            Object[] deserializedParams = new Object[paramList.size()];\n")
-	sb.WriteString("            try {\n")
-	sb.WriteString("                for (int i = 0; i < paramList.size(); i++) {\n")
-	sb.WriteString("                    String paramJson = jsonParser.toJson(paramList.get(i));\n")
-	sb.WriteString("                    deserializedParams[i] = jsonParser.fromJson(paramJson, paramTypes[i]);\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("            } catch (Exception deserEx) {\n")
-	sb.WriteString("                // Deserialization errors should return -32602 (Invalid params)\n")
-	sb.WriteString("                return Map.of(\n")
-	sb.WriteString("                    \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("                    \"error\", Map.of(\n")
-	sb.WriteString("                        \"code\", -32602,\n")
-	sb.WriteString("                        \"message\", \"Invalid params: \" + deserEx.getMessage()\n")
-	sb.WriteString("                    ),\n")
-	sb.WriteString("                    \"id\", id\n")
-	sb.WriteString("                );\n")
-	sb.WriteString("            }\n\n")
-	sb.WriteString("            // Invoke method\n")
-	sb.WriteString("            Object result = targetMethod.invoke(handler, deserializedParams);\n\n")
-	sb.WriteString("            // Return response (use HashMap to allow null result values)\n")
-	sb.WriteString("            Map<String, Object> response = new HashMap<>();\n")
-	sb.WriteString("            response.put(\"jsonrpc\", \"2.0\");\n")
-	sb.WriteString("            response.put(\"result\", result);\n")
-	sb.WriteString("            response.put(\"id\", id);\n")
-	sb.WriteString("            return response;\n")
-	sb.WriteString("        } catch (java.lang.reflect.InvocationTargetException ite) {\n")
-	sb.WriteString("            // Unwrap InvocationTargetException to get the actual exception\n")
-	sb.WriteString("            Throwable cause = ite.getCause();\n")
-	sb.WriteString("            if (cause instanceof RPCError) {\n")
-	sb.WriteString("                RPCError rpcErr = (RPCError) cause;\n")
-	sb.WriteString("                return Map.of(\n")
-	sb.WriteString("                    \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("                    \"error\", Map.of(\n")
-	sb.WriteString("                        \"code\", rpcErr.getCode(),\n")
-	sb.WriteString("                        \"message\", rpcErr.getMessage(),\n")
-	sb.WriteString("                        \"data\", rpcErr.getData()\n")
-	sb.WriteString("                    ),\n")
-	sb.WriteString("                    \"id\", id\n")
-	sb.WriteString("                );\n")
-	sb.WriteString("            } else {\n")
-	sb.WriteString("                // Print stack trace for unexpected exceptions\n")
-	sb.WriteString("                System.err.println(\"Exception in method \" + method + \":\");\n")
-	sb.WriteString("                if (cause != null) {\n")
-	sb.WriteString("                    cause.printStackTrace();\n")
-	sb.WriteString("                } else {\n")
-	sb.WriteString("                    ite.printStackTrace();\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("                return Map.of(\n")
-	sb.WriteString("                    \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("                    \"error\", Map.of(\n")
-	sb.WriteString("                        \"code\", -32603,\n")
-	sb.WriteString("                        \"message\", \"Internal error: \" + (cause != null ? cause.getMessage() : ite.getMessage())\n")
-	sb.WriteString("                    ),\n")
-	sb.WriteString("                    \"id\", id\n")
-	sb.WriteString("                );\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("        } catch (RPCError rpcErr) {\n")
-	sb.WriteString("            // RPCError is expected and can be thrown by implementations\n")
-	sb.WriteString("            return Map.of(\n")
-	sb.WriteString("                \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("                \"error\", Map.of(\n")
-	sb.WriteString("                    \"code\", rpcErr.getCode(),\n")
-	sb.WriteString("                    \"message\", rpcErr.getMessage(),\n")
-	sb.WriteString("                    \"data\", rpcErr.getData()\n")
-	sb.WriteString("                ),\n")
-	sb.WriteString("                \"id\", id\n")
-	sb.WriteString("            );\n")
-	sb.WriteString("        } catch (Exception e) {\n")
-	sb.WriteString("            // Print stack trace for unexpected exceptions\n")
-	sb.WriteString("            System.err.println(\"Exception in method \" + method + \":\");\n")
-	sb.WriteString("            e.printStackTrace();\n")
-	sb.WriteString("            return Map.of(\n")
-	sb.WriteString("                \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("                \"error\", Map.of(\n")
-	sb.WriteString("                    \"code\", -32603,\n")
-	sb.WriteString("                    \"message\", \"Internal error: \" + e.getMessage()\n")
-	sb.WriteString("                ),\n")
-	sb.WriteString("                \"id\", id\n")
-	sb.WriteString("            );\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("    }\n")
-
-	sb.WriteString("}\n")
-
-	return sb.String()
-}
-
-// generateClientJava generates the Client.java file
-func generateClientJava(_ *parser.IDL, namespaceMap map[string]*NamespaceTypes, basePackage string, packageDecl string) string {
-	var sb strings.Builder
-
-	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
-	if packageDecl != "" {
-		sb.WriteString(fmt.Sprintf("package %s;\n\n", packageDecl))
-	}
-	sb.WriteString("import com.bitmechanic.pulserpc.*;\n")
-	sb.WriteString("import java.io.*;\n")
-	sb.WriteString("import java.net.*;\n")
-	sb.WriteString("import java.net.http.*;\n")
-	sb.WriteString("import java.net.http.HttpRequest.*;\n")
-	sb.WriteString("import java.net.http.HttpResponse.*;\n")
-	sb.WriteString("import java.util.*;\n")
-	sb.WriteString("import java.util.concurrent.*;\n\n")
-
-	sb.WriteString("public class Client {\n")
-	sb.WriteString("    private final HttpClient httpClient;\n")
-	sb.WriteString("    private final String baseUrl;\n")
-	sb.WriteString("    private final JsonParser jsonParser;\n")
-	sb.WriteString("    private final Map<String, Map<String, Object>> allStructs;\n")
-	sb.WriteString("    private final Map<String, Map<String, Object>> allEnums;\n\n")
-
-	// Constructor
-	sb.WriteString("    public Client(String baseUrl, JsonParser jsonParser) {\n")
-	sb.WriteString("        this.httpClient = HttpClient.newHttpClient();\n")
-	sb.WriteString("        this.baseUrl = baseUrl;\n")
-	sb.WriteString("        this.jsonParser = jsonParser;\n")
-	sb.WriteString("        this.allStructs = new HashMap<>();\n")
-	sb.WriteString("        this.allEnums = new HashMap<>();\n\n")
-
-	// Collect all structs and enums from namespace IDL classes
-	for namespace := range namespaceMap {
-		if namespace != "" {
-			nsPackage := basePackage + "." + strings.ToLower(namespace)
-			sb.WriteString(fmt.Sprintf("        this.allStructs.putAll(%s.%sIdl.ALL_STRUCTS);\n", nsPackage, namespace))
-			sb.WriteString(fmt.Sprintf("        this.allEnums.putAll(%s.%sIdl.ALL_ENUMS);\n", nsPackage, namespace))
+	for _, route := range collectRESTRoutes(interfaces) {
+		names := make([]string, 0, len(route.Method.Parameters))
+		types := make([]string, 0, len(route.Method.Parameters))
+		for _, param := range route.Method.Parameters {
+			names = append(names, param.Name)
+			types = append(types, param.Type.String())
+		}
+		view.ReadOnlyRoutes = append(view.ReadOnlyRoutes, javaRouteView{
+			Path:         route.Path(),
+			RPCMethod:    route.RPCMethod(),
+			ParamNames:   quotedList(names),
+			ParamTypes:   quotedList(types),
+			CacheControl: cacheControl(route.Method),
+		})
+	}
+	// A JSON default is also a valid Java literal, and the server converts it to the
+	// parameter's type like any other parameter value
+	for _, iface := range interfaces {
+		for _, method := range iface.Methods {
+			names := make([]string, len(method.Parameters))
+			defaults := make([]string, len(method.Parameters))
+			for i, param := range method.Parameters {
+				names[i] = param.Name
+				defaults[i] = "null"
+				if value, ok := defaultJSON(param); ok {
+					defaults[i] = value
+				}
+			}
+			view.MethodTable = append(view.MethodTable, javaMethodTableView{
+				Key:        iface.Name + "." + method.Name,
+				ParamNames: quotedList(names),
+				Optional:   method.RequiredParams() < len(method.Parameters),
+				Required:   method.RequiredParams(),
+				Defaults:   strings.Join(defaults, ", "),
+			})
 		}
 	}
 
-	sb.WriteString("    }\n\n")
-
-	// Call method
-	sb.WriteString("    @SuppressWarnings(\"unchecked\")\n")
-	sb.WriteString("    public Map<String, Object> call(String method, Map<String, Object> params) throws Exception {\n")
-	sb.WriteString("        Map<String, Object> request = Map.of(\n")
-	sb.WriteString("            \"jsonrpc\", \"2.0\",\n")
-	sb.WriteString("            \"method\", method,\n")
-	sb.WriteString("            \"params\", params,\n")
-	sb.WriteString("            \"id\", 1\n")
-	sb.WriteString("        );\n\n")
-	sb.WriteString("        String requestBody = jsonParser.toJson(request);\n\n")
-	sb.WriteString("        HttpRequest httpRequest = HttpRequest.newBuilder()\n")
-	sb.WriteString("            .uri(URI.create(baseUrl))\n")
-	sb.WriteString("            .header(\"Content-Type\", \"application/json\")\n")
-	sb.WriteString("            .POST(HttpRequest.BodyPublishers.ofString(requestBody))\n")
-	sb.WriteString("            .build();\n\n")
-	sb.WriteString("        HttpResponse<String> response = httpClient.send(httpRequest, HttpResponse.BodyHandlers.ofString());\n\n")
-	sb.WriteString("        if (response.statusCode() != 200) {\n")
-	sb.WriteString("            throw new RuntimeException(\"HTTP error: \" + response.statusCode());\n")
-	sb.WriteString("        }\n\n")
-	sb.WriteString("        Map<String, Object> jsonResponse = jsonParser.fromJson(response.body(), Map.class);\n\n")
-	sb.WriteString("        if (jsonResponse.containsKey(\"error\")) {\n")
-	sb.WriteString("            Map<String, Object> error = (Map<String, Object>) jsonResponse.get(\"error\");\n")
-	sb.WriteString("            throw new RPCError(\n")
-	sb.WriteString("                ((Number) error.get(\"code\")).intValue(),\n")
-	sb.WriteString("                (String) error.get(\"message\"),\n")
-	sb.WriteString("                error.get(\"data\")\n")
-	sb.WriteString("            );\n")
-	sb.WriteString("        }\n\n")
-	sb.WriteString("        return (Map<String, Object>) jsonResponse.get(\"result\");\n")
-	sb.WriteString("    }\n")
-
-	sb.WriteString("}\n")
-
-	return sb.String()
+	if view.WireNames {
+		view.WireMethods = capture(func(sb *strings.Builder) { writeWireMethodsJava(sb, interfaces) })
+	}
+	if view.Compressed {
+		view.Compression = capture(func(sb *strings.Builder) { writeCompressionJava(sb, interfaces) })
+	}
+	if view.AsyncJobs {
+		view.JobsServer = capture(func(sb *strings.Builder) { writeJobsServerJava(sb, interfaces) })
+	}
+	if view.Cached {
+		view.CacheHelpers = capture(writeCacheHelpersJava)
+	}
+	return ts.renderString("java/Server.java.tmpl", view)
 }
 
 // Helper functions
@@ -1336,30 +1279,17 @@ func writeTypeDictJava(sb *strings.Builder, typeDef *parser.Type) {
 	}
 }
 
-func toCamelCase(s string) string {
-	if s == "" {
-		return s
-	}
-	// Simple camelCase conversion - first letter lowercase
-	return strings.ToLower(s[:1]) + s[1:]
-}
-
-func capitalizeFirst(s string) string {
-	if s == "" {
-		return s
+// getGetterName generates the getter method name for a field: "get" + naming.UpperFirst(fieldName),
+// or the field name itself for record-style getters (-style getters=record)
+func getGetterName(fieldName string, getters string) string {
+	if getters == "record" {
+		return fieldName
 	}
-	// Capitalize first letter
-	return strings.ToUpper(s[:1]) + s[1:]
-}
-
-// getGetterName generates the getter method name for a field
-// This matches how struct generation creates getters: "get" + capitalizeFirst(fieldName)
-func getGetterName(fieldName string) string {
-	return "get" + capitalizeFirst(fieldName)
+	return "get" + naming.UpperFirst(fieldName)
 }
 
 // generateTestInterfaceImplFile generates a separate implementation file for an interface
-func generateTestInterfaceImplFile(iface *parser.Interface, packageName string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, jsonLib string, basePackage string) string {
+func generateTestInterfaceImplFile(iface *parser.Interface, packageName string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, jsonLib string, basePackage string, getters string) string {
 	_ = jsonLib
 	var sb strings.Builder
 
@@ -1388,12 +1318,7 @@ func generateTestInterfaceImplFile(iface *parser.Interface, packageName string,
 		}
 	}
 
-	for imp := range imports {
-		sb.WriteString(fmt.Sprintf("import %s;\n", imp))
-	}
-	if len(imports) > 0 {
-		sb.WriteString("\n")
-	}
+	writeJavaImports(&sb, imports)
 
 	implName := interfaceName + "Impl"
 	if interfacePackage != packageName {
@@ -1417,13 +1342,13 @@ func generateTestInterfaceImplFile(iface *parser.Interface, packageName string,
 			if i > 0 {
 				sb.WriteString(", ")
 			}
-			paramType := getJavaTypeWithPackage(param.Type, enumMap, basePackage, packageName)
+			paramType := getJavaParamType(param, enumMap, basePackage, packageName)
 			fmt.Fprintf(&sb, "%s %s", paramType, param.Name)
 		}
 		sb.WriteString(") {\n")
 
 		// Generate implementation based on method name (similar to C# version)
-		writeTestMethodBody(&sb, iface, method, structMap, enumMap, basePackage, packageName)
+		writeTestMethodBody(&sb, iface, method, structMap, enumMap, basePackage, packageName, getters)
 
 		sb.WriteString("    }\n\n")
 	}
@@ -1434,7 +1359,7 @@ func generateTestInterfaceImplFile(iface *parser.Interface, packageName string,
 }
 
 // writeTestMethodBody generates the body of a test method implementation
-func writeTestMethodBody(sb *strings.Builder, iface *parser.Interface, method *parser.Method, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, basePackage string, packageName string) {
+func writeTestMethodBody(sb *strings.Builder, iface *parser.Interface, method *parser.Method, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, basePackage string, packageName string, getters string) {
 	_ = structMap
 	interfaceName := iface.Name
 	methodName := method.Name
@@ -1470,10 +1395,10 @@ func writeTestMethodBody(sb *strings.Builder, iface *parser.Interface, method *p
 			return
 		case "repeat":
 			respType := getJavaTypeWithPackage(method.ReturnType, enumMap, basePackage, packageName)
-			sb.WriteString("        String toRepeat = req1.getTo_repeat();\n")
-			sb.WriteString("        if (req1.getForce_uppercase()) toRepeat = toRepeat.toUpperCase();\n")
+			fmt.Fprintf(sb, "        String toRepeat = req1.%s();\n", getGetterName("to_repeat", getters))
+			fmt.Fprintf(sb, "        if (req1.%s()) toRepeat = toRepeat.toUpperCase();\n", getGetterName("force_uppercase", getters))
 			sb.WriteString("        java.util.List<String> items = new java.util.ArrayList<>();\n")
-			sb.WriteString("        for (int i = 0; i < req1.getCount(); i++) {\n")
+			fmt.Fprintf(sb, "        for (int i = 0; i < req1.%s(); i++) {\n", getGetterName("count", getters))
 			sb.WriteString("            items.add(toRepeat);\n")
 			sb.WriteString("        }\n")
 			fmt.Fprintf(sb, "        %s response = new %s();\n", respType, respType)
@@ -1481,7 +1406,7 @@ func writeTestMethodBody(sb *strings.Builder, iface *parser.Interface, method *p
 			statusEnumType := getJavaTypeWithPackage(&parser.Type{UserDefined: "inc.Status"}, enumMap, basePackage, packageName)
 			sb.WriteString(statusEnumType)
 			sb.WriteString(".ok);\n")
-			sb.WriteString("        response.setCount(req1.getCount());\n")
+			fmt.Fprintf(sb, "        response.setCount(req1.%s());\n", getGetterName("count", getters))
 			sb.WriteString("        response.setItems(items);\n")
 			sb.WriteString("        return response;\n")
 			return
@@ -1499,7 +1424,7 @@ func writeTestMethodBody(sb *strings.Builder, iface *parser.Interface, method *p
 			sb.WriteString("        return result;\n")
 			return
 		case "putPerson":
-			sb.WriteString("        return p.getPersonId();\n")
+			fmt.Fprintf(sb, "        return p.%s();\n", getGetterName("personId", getters))
 			return
 		}
 	case "B":
@@ -1535,16 +1460,29 @@ func writeTestMethodBody(sb *strings.Builder, iface *parser.Interface, method *p
 	}
 }
 
+// testServerJavaView is the view model for TestServer.java
+type testServerJavaView struct {
+	Package         string
+	Imports         []string
+	JSONParserClass string
+	Registrations   []testServerRegistrationView
+}
+
+// testServerRegistrationView registers one interface implementation with the test server
+type testServerRegistrationView struct {
+	Interface string
+	// Impl is the fully qualified implementation class name
+	Impl string
+}
+
 // generateTestServerJava generates TestServer.java
-func generateTestServerJava(idl *parser.IDL, jsonLib string, basePackage string, namespaceMap map[string]*NamespaceTypes) string {
+func generateTestServerJava(ts *templateSet, idl *parser.IDL, jsonLib string, basePackage string, namespaceMap map[string]*NamespaceTypes) string {
 	_ = namespaceMap
-	var sb strings.Builder
-
-	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
-	sb.WriteString(fmt.Sprintf("package %s;\n\n", basePackage))
-	sb.WriteString("import com.bitmechanic.pulserpc.*;\n")
+	view := testServerJavaView{Package: basePackage, JSONParserClass: "GsonJsonParser"}
+	if jsonLib == "jackson" {
+		view.JSONParserClass = "JacksonJsonParser"
+	}
 
-	// Add imports for interface implementations
 	imports := make(map[string]bool)
 	for _, iface := range idl.Interfaces {
 		ifaceNamespace := GetNamespaceFromType(iface.Name, iface.Namespace)
@@ -1552,63 +1490,20 @@ func generateTestServerJava(idl *parser.IDL, jsonLib string, basePackage string,
 		if ifaceNamespace != "" {
 			ifacePackage = basePackage + "." + strings.ToLower(ifaceNamespace)
 		}
-		implName := GetBaseName(iface.Name) + "Impl"
-		imports[ifacePackage+"."+implName] = true
+		impl := ifacePackage + "." + GetBaseName(iface.Name) + "Impl"
+		imports[impl] = true
+		view.Registrations = append(view.Registrations, testServerRegistrationView{Interface: GetBaseName(iface.Name), Impl: impl})
 	}
 	for imp := range imports {
-		sb.WriteString(fmt.Sprintf("import %s;\n", imp))
-	}
-	if len(imports) > 0 {
-		sb.WriteString("\n")
-	}
-
-	sb.WriteString("public class TestServer extends Server {\n")
-	sb.WriteString("    public TestServer(int port, JsonParser jsonParser) throws Exception {\n")
-	sb.WriteString("        super(port, jsonParser);\n")
-	sb.WriteString("    }\n\n")
-
-	sb.WriteString("    public static void main(String[] args) {\n")
-	sb.WriteString("        try {\n")
-	switch jsonLib {
-	case "jackson":
-		sb.WriteString("            JsonParser jsonParser = new JacksonJsonParser();\n")
-	default:
-		sb.WriteString("            JsonParser jsonParser = new GsonJsonParser();\n")
-	}
-	sb.WriteString("            TestServer server = new TestServer(8080, jsonParser);\n\n")
-
-	// Register interface implementations
-	for _, iface := range idl.Interfaces {
-		ifaceNamespace := GetNamespaceFromType(iface.Name, iface.Namespace)
-		ifacePackage := basePackage
-		if ifaceNamespace != "" {
-			ifacePackage = basePackage + "." + strings.ToLower(ifaceNamespace)
-		}
-		implName := GetBaseName(iface.Name) + "Impl"
-		interfaceName := GetBaseName(iface.Name)
-		fmt.Fprintf(&sb, "            server.register(\"%s\", new %s.%s());\n", interfaceName, ifacePackage, implName)
+		view.Imports = append(view.Imports, imp)
 	}
+	sort.Strings(view.Imports)
 
-	sb.WriteString("            server.start();\n")
-	sb.WriteString("            System.out.println(\"Test server started on port 8080\");\n")
-	sb.WriteString("            // Keep server running indefinitely\n")
-	sb.WriteString("            Object lock = new Object();\n")
-	sb.WriteString("            synchronized (lock) {\n")
-	sb.WriteString("                lock.wait();\n")
-	sb.WriteString("            }\n")
-	sb.WriteString("        } catch (Exception e) {\n")
-	sb.WriteString("            System.err.println(\"Fatal error: \" + e.getMessage());\n")
-	sb.WriteString("            e.printStackTrace();\n")
-	sb.WriteString("            System.exit(1);\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("    }\n")
-	sb.WriteString("}\n")
-
-	return sb.String()
+	return ts.renderString("java/TestServer.java.tmpl", view)
 }
 
 // generateTestClientJava generates TestClient.java
-func generateTestClientJava(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, jsonLib string, basePackage string, namespaceMap map[string]*NamespaceTypes) string {
+func generateTestClientJava(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, jsonLib string, basePackage string, namespaceMap map[string]*NamespaceTypes, testVectors bool) string {
 	_ = namespaceMap
 	var sb strings.Builder
 
@@ -1637,12 +1532,7 @@ func generateTestClientJava(idl *parser.IDL, structMap map[string]*parser.Struct
 		clientName := GetBaseName(iface.Name) + "Client"
 		imports[ifacePackage+"."+clientName] = true
 	}
-	for imp := range imports {
-		sb.WriteString(fmt.Sprintf("import %s;\n", imp))
-	}
-	if len(imports) > 0 {
-		sb.WriteString("\n")
-	}
+	writeJavaImports(&sb, imports)
 
 	sb.WriteString("public class TestClient {\n")
 	sb.WriteString("    public static void main(String[] args) throws Exception {\n")
@@ -1691,16 +1581,154 @@ func generateTestClientJava(idl *parser.IDL, structMap map[string]*parser.Struct
 		sb.WriteString("\n")
 	}
 
+	sb.WriteString("        if (!testConcurrentCalls(transport)) {\n")
+	sb.WriteString("            System.exit(1);\n")
+	sb.WriteString("        }\n")
+	if testVectors {
+		sb.WriteString("        int vectorFailures = runTestVectors(baseUrl, jsonParser);\n")
+		sb.WriteString("        if (vectorFailures > 0) {\n")
+		sb.WriteString("            System.err.println(\"FAILED: \" + vectorFailures + \" test vector(s) failed\");\n")
+		sb.WriteString("            System.exit(1);\n")
+		sb.WriteString("        }\n")
+	}
 	sb.WriteString("        System.out.println(\"Test client completed\");\n")
 	sb.WriteString("    }\n")
+	writeConcurrentCallsTestJava(&sb)
+	if testVectors {
+		writeTestVectorReplayJava(&sb)
+	}
 	sb.WriteString("}\n")
 
 	return sb.String()
 }
 
+// writeTestVectorReplayJava generates the methods that replay testvectors.json
+// against the server and compare each response with the expected one
+func writeTestVectorReplayJava(sb *strings.Builder) {
+	sb.WriteString("\n")
+	sb.WriteString("    // Replays testvectors.json and returns the number of mismatches\n")
+	sb.WriteString("    @SuppressWarnings(\"unchecked\")\n")
+	sb.WriteString("    private static int runTestVectors(String baseUrl, JsonParser jsonParser) throws Exception {\n")
+	sb.WriteString("        String data = new String(java.nio.file.Files.readAllBytes(java.nio.file.Paths.get(\"testvectors.json\")), java.nio.charset.StandardCharsets.UTF_8);\n")
+	sb.WriteString("        java.util.Map<String, Object> file = jsonParser.fromJson(data, java.util.Map.class);\n")
+	sb.WriteString("        java.net.http.HttpClient http = java.net.http.HttpClient.newHttpClient();\n")
+	sb.WriteString("        int failures = 0;\n")
+	sb.WriteString("        for (Object item : (java.util.List<Object>) file.get(\"vectors\")) {\n")
+	sb.WriteString("            java.util.Map<String, Object> v = (java.util.Map<String, Object>) item;\n")
+	sb.WriteString("            String name = (String) v.get(\"name\");\n")
+	sb.WriteString("            String msg;\n")
+	sb.WriteString("            try {\n")
+	sb.WriteString("                java.net.http.HttpRequest request = java.net.http.HttpRequest.newBuilder(java.net.URI.create(baseUrl))\n")
+	sb.WriteString("                    .header(\"Content-Type\", \"application/json\")\n")
+	sb.WriteString("                    .POST(java.net.http.HttpRequest.BodyPublishers.ofString(jsonParser.toJson(normalizeVectorValue(v.get(\"request\")))))\n")
+	sb.WriteString("                    .build();\n")
+	sb.WriteString("                String body = http.send(request, java.net.http.HttpResponse.BodyHandlers.ofString()).body();\n")
+	sb.WriteString("                msg = compareTestVector((java.util.Map<String, Object>) v.get(\"response\"), jsonParser.fromJson(body, java.util.Map.class));\n")
+	sb.WriteString("            } catch (Exception e) {\n")
+	sb.WriteString("                msg = e.toString();\n")
+	sb.WriteString("            }\n")
+	sb.WriteString("            if (msg.isEmpty()) {\n")
+	sb.WriteString("                System.out.println(\"✓ vector \" + name + \" passed\");\n")
+	sb.WriteString("            } else {\n")
+	sb.WriteString("                System.err.println(\"✗ vector \" + name + \": \" + msg);\n")
+	sb.WriteString("                failures++;\n")
+	sb.WriteString("            }\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        return failures;\n")
+	sb.WriteString("    }\n\n")
+
+	sb.WriteString("    // Returns an empty string if actual satisfies expected\n")
+	sb.WriteString("    @SuppressWarnings(\"unchecked\")\n")
+	sb.WriteString("    private static String compareTestVector(java.util.Map<String, Object> expected, java.util.Map<String, Object> actual) {\n")
+	sb.WriteString("        if (!vectorValuesEqual(expected.get(\"id\"), actual.get(\"id\"))) {\n")
+	sb.WriteString("            return \"expected id \" + expected.get(\"id\") + \", got \" + actual.get(\"id\");\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        if (expected.containsKey(\"error\")) {\n")
+	sb.WriteString("            Object expectedCode = ((java.util.Map<String, Object>) expected.get(\"error\")).get(\"code\");\n")
+	sb.WriteString("            Object actualError = actual.get(\"error\");\n")
+	sb.WriteString("            if (!(actualError instanceof java.util.Map) || !vectorValuesEqual(expectedCode, ((java.util.Map<String, Object>) actualError).get(\"code\"))) {\n")
+	sb.WriteString("                return \"expected error code \" + expectedCode + \", got \" + actual;\n")
+	sb.WriteString("            }\n")
+	sb.WriteString("            return \"\";\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        if (actual.containsKey(\"error\")) {\n")
+	sb.WriteString("            return \"unexpected error \" + actual.get(\"error\");\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        if (expected.containsKey(\"result\") && !vectorValuesEqual(expected.get(\"result\"), actual.get(\"result\"))) {\n")
+	sb.WriteString("            return \"expected result \" + expected.get(\"result\") + \", got \" + actual.get(\"result\");\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        return \"\";\n")
+	sb.WriteString("    }\n\n")
+
+	sb.WriteString("    // Compares JSON values ignoring key order; numbers compare numerically\n")
+	sb.WriteString("    @SuppressWarnings(\"unchecked\")\n")
+	sb.WriteString("    private static boolean vectorValuesEqual(Object expected, Object actual) {\n")
+	sb.WriteString("        if (expected instanceof Number && actual instanceof Number) {\n")
+	sb.WriteString("            return ((Number) expected).doubleValue() == ((Number) actual).doubleValue();\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        if (expected instanceof java.util.List && actual instanceof java.util.List) {\n")
+	sb.WriteString("            java.util.List<Object> e = (java.util.List<Object>) expected;\n")
+	sb.WriteString("            java.util.List<Object> a = (java.util.List<Object>) actual;\n")
+	sb.WriteString("            if (e.size() != a.size()) return false;\n")
+	sb.WriteString("            for (int i = 0; i < e.size(); i++) {\n")
+	sb.WriteString("                if (!vectorValuesEqual(e.get(i), a.get(i))) return false;\n")
+	sb.WriteString("            }\n")
+	sb.WriteString("            return true;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        if (expected instanceof java.util.Map && actual instanceof java.util.Map) {\n")
+	sb.WriteString("            java.util.Map<String, Object> e = (java.util.Map<String, Object>) expected;\n")
+	sb.WriteString("            java.util.Map<String, Object> a = (java.util.Map<String, Object>) actual;\n")
+	sb.WriteString("            if (!e.keySet().equals(a.keySet())) return false;\n")
+	sb.WriteString("            for (java.util.Map.Entry<String, Object> entry : e.entrySet()) {\n")
+	sb.WriteString("                if (!vectorValuesEqual(entry.getValue(), a.get(entry.getKey()))) return false;\n")
+	sb.WriteString("            }\n")
+	sb.WriteString("            return true;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        return java.util.Objects.equals(expected, actual);\n")
+	sb.WriteString("    }\n\n")
+
+	sb.WriteString("    // Some JSON libraries read every number as a double; restore integral values\n")
+	sb.WriteString("    // so requests are sent exactly as written in testvectors.json\n")
+	sb.WriteString("    @SuppressWarnings(\"unchecked\")\n")
+	sb.WriteString("    private static Object normalizeVectorValue(Object value) {\n")
+	sb.WriteString("        if (value instanceof Double && (Double) value == Math.rint((Double) value) && !((Double) value).isInfinite()) {\n")
+	sb.WriteString("            return ((Double) value).longValue();\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        if (value instanceof java.util.List) {\n")
+	sb.WriteString("            java.util.List<Object> out = new java.util.ArrayList<>();\n")
+	sb.WriteString("            for (Object item : (java.util.List<Object>) value) out.add(normalizeVectorValue(item));\n")
+	sb.WriteString("            return out;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        if (value instanceof java.util.Map) {\n")
+	sb.WriteString("            java.util.Map<String, Object> out = new java.util.LinkedHashMap<>();\n")
+	sb.WriteString("            for (java.util.Map.Entry<String, Object> entry : ((java.util.Map<String, Object>) value).entrySet()) {\n")
+	sb.WriteString("                out.put(entry.getKey(), normalizeVectorValue(entry.getValue()));\n")
+	sb.WriteString("            }\n")
+	sb.WriteString("            return out;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        return value;\n")
+	sb.WriteString("    }\n")
+}
+
+// writeJavaImports writes one import statement per entry in sorted order, so
+// generated files are identical across runs
+func writeJavaImports(sb *strings.Builder, imports map[string]bool) {
+	if len(imports) == 0 {
+		return
+	}
+	names := make([]string, 0, len(imports))
+	for imp := range imports {
+		names = append(names, imp)
+	}
+	sort.Strings(names)
+	for _, imp := range names {
+		fmt.Fprintf(sb, "import %s;\n", imp)
+	}
+	sb.WriteString("\n")
+}
+
 // writeTestParamValue generates a test parameter value
 func writeTestParamValue(sb *strings.Builder, param *parser.Parameter, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, basePackage string, currentPackage string, _ string) {
-	_ = structMap
 	if param.Type.IsBuiltIn() {
 		switch param.Type.BuiltIn {
 		case "string":
@@ -1740,6 +1768,13 @@ func writeTestParamValue(sb *strings.Builder, param *parser.Parameter, structMap
 			} else {
 				sb.WriteString("null")
 			}
+		} else if s := lookupStruct(param.Type.UserDefined, structMap); s != nil && s.IsImmutable() {
+			// Immutable structs have no public no-arg constructor
+			args := []string{}
+			for _, field := range parser.ResolveStructFields(s, structMap) {
+				args = append(args, javaDefaultValue(getJavaTypeWithPackage(field.Type, enumMap, basePackage, currentPackage)))
+			}
+			fmt.Fprintf(sb, "new %s(%s)", fullTypeName, strings.Join(args, ", "))
 		} else {
 			// It's a struct
 			fmt.Fprintf(sb, "new %s()", fullTypeName)
@@ -1750,69 +1785,19 @@ func writeTestParamValue(sb *strings.Builder, param *parser.Parameter, structMap
 }
 
 // generatePomXml generates pom.xml for Maven builds
-func generatePomXml(jsonLib string) string {
-	var sb strings.Builder
-
-	sb.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
-	sb.WriteString("<project xmlns=\"http://maven.apache.org/POM/4.0.0\"\n")
-	sb.WriteString("         xmlns:xsi=\"http://www.w3.org/2001/XMLSchema-instance\"\n")
-	sb.WriteString("         xsi:schemaLocation=\"http://maven.apache.org/POM/4.0.0\n")
-	sb.WriteString("                             http://maven.apache.org/xsd/maven-4.0.0.xsd\">\n")
-	sb.WriteString("    <modelVersion>4.0.0</modelVersion>\n\n")
-	sb.WriteString("    <groupId>com.example</groupId>\n")
-	sb.WriteString("    <artifactId>pulserpc-test</artifactId>\n")
-	sb.WriteString("    <version>1.0.0</version>\n")
-	sb.WriteString("    <packaging>jar</packaging>\n\n")
-	sb.WriteString("    <properties>\n")
-	sb.WriteString("        <maven.compiler.source>11</maven.compiler.source>\n")
-	sb.WriteString("        <maven.compiler.target>11</maven.compiler.target>\n")
-	sb.WriteString("        <project.build.sourceEncoding>UTF-8</project.build.sourceEncoding>\n")
-	sb.WriteString("    </properties>\n\n")
-	sb.WriteString("    <dependencies>\n")
-
-	switch jsonLib {
-	case "jackson":
-		sb.WriteString("        <dependency>\n")
-		sb.WriteString("            <groupId>com.fasterxml.jackson.core</groupId>\n")
-		sb.WriteString("            <artifactId>jackson-databind</artifactId>\n")
-		sb.WriteString("            <version>2.15.2</version>\n")
-		sb.WriteString("        </dependency>\n")
-	case "gson":
-		sb.WriteString("        <dependency>\n")
-		sb.WriteString("            <groupId>com.google.code.gson</groupId>\n")
-		sb.WriteString("            <artifactId>gson</artifactId>\n")
-		sb.WriteString("            <version>2.10.1</version>\n")
-		sb.WriteString("        </dependency>\n")
-	}
-
-	sb.WriteString("        <dependency>\n")
-	sb.WriteString("            <groupId>junit</groupId>\n")
-	sb.WriteString("            <artifactId>junit</artifactId>\n")
-	sb.WriteString("            <version>4.13.2</version>\n")
-	sb.WriteString("            <scope>test</scope>\n")
-	sb.WriteString("        </dependency>\n")
-	sb.WriteString("    </dependencies>\n\n")
-	sb.WriteString("    <build>\n")
-	sb.WriteString("        <plugins>\n")
-	sb.WriteString("            <plugin>\n")
-	sb.WriteString("                <groupId>org.apache.maven.plugins</groupId>\n")
-	sb.WriteString("                <artifactId>maven-compiler-plugin</artifactId>\n")
-	sb.WriteString("                <version>3.11.0</version>\n")
-	sb.WriteString("                <configuration>\n")
-	sb.WriteString("                    <source>11</source>\n")
-	sb.WriteString("                    <target>11</target>\n")
-	sb.WriteString("                </configuration>\n")
-	sb.WriteString("            </plugin>\n")
-	sb.WriteString("            <plugin>\n")
-	sb.WriteString("                <groupId>org.codehaus.mojo</groupId>\n")
-	sb.WriteString("                <artifactId>exec-maven-plugin</artifactId>\n")
-	sb.WriteString("                <version>3.1.0</version>\n")
-	sb.WriteString("            </plugin>\n")
-	sb.WriteString("        </plugins>\n")
-	sb.WriteString("    </build>\n")
-	sb.WriteString("</project>\n")
+// pomView is the view model for pom.xml
+type pomView struct {
+	JSONLib string
+	// JUnit5 replaces JUnit 4 with JUnit Jupiter and a surefire version that runs it
+	JUnit5 bool
+	// Versions are the dependency versions by groupId:artifactId; see dependencyVersions
+	Versions map[string]string
+	// Mirror is the Maven repository that replaces Maven Central; see -dependency-mirror
+	Mirror string
+}
 
-	return sb.String()
+func generatePomXml(ts *templateSet, jsonLib string, junit5 bool, versions map[string]string, mirror dependencyMirror) string {
+	return ts.renderString("java/pom.xml.tmpl", pomView{JSONLib: jsonLib, JUnit5: junit5, Versions: versions, Mirror: mirror.URL("maven")})
 }
 
 // Keep references to helper functions that are intentionally retained
@@ -2,8 +2,10 @@ package generator
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/coopernurse/pulserpc/pkg/parser"
@@ -181,3 +183,266 @@ func TestJavaGeneratorTestFilesDisabled(t *testing.T) {
 		t.Fatalf("TestClient.java should NOT be generated when -generate-test-files=false")
 	}
 }
+
+func TestJavaGeneratorLegacyRootCopies(t *testing.T) {
+	idl := &parser.IDL{
+		Interfaces: []*parser.Interface{
+			{
+				Name:      "A",
+				Namespace: "inc",
+				Methods: []*parser.Method{
+					{
+						Name:       "add",
+						Parameters: []*parser.Parameter{{Name: "a", Type: &parser.Type{BuiltIn: "int"}}},
+						ReturnType: &parser.Type{BuiltIn: "int"},
+					},
+				},
+			},
+		},
+	}
+
+	generate := func(legacy string) string {
+		tmpDir := t.TempDir()
+		p := NewJavaClientServer()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		p.RegisterFlags(fs)
+		if err := fs.Set("dir", tmpDir); err != nil {
+			t.Fatalf("failed to set dir flag: %v", err)
+		}
+		if err := fs.Set("base-package", "com.example"); err != nil {
+			t.Fatalf("failed to set base-package flag: %v", err)
+		}
+		if legacy != "" {
+			if err := fs.Set("legacy-root-copies", legacy); err != nil {
+				t.Fatalf("failed to set legacy-root-copies flag: %v", err)
+			}
+		}
+		if err := p.Generate(idl, fs); err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		return tmpDir
+	}
+
+	// Default: only the packaged variants are emitted
+	dir := generate("")
+	for _, name := range []string{"Server.java", "Client.java"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			t.Fatalf("%s should NOT be generated at the output root by default", name)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "src", "main", "java", "com", "example", name)); err != nil {
+			t.Fatalf("expected packaged %s, missing: %v", name, err)
+		}
+	}
+
+	// Opt-in: un-packaged copies are written at the output root
+	dir = generate("true")
+	for _, name := range []string{"Server.java", "Client.java"} {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("expected root %s with -legacy-root-copies, missing: %v", name, err)
+		}
+		if strings.Contains(string(content), "package ") {
+			t.Fatalf("root %s should not declare a package", name)
+		}
+	}
+}
+
+func TestJavaGeneratorRequestExecutor(t *testing.T) {
+	idl := &parser.IDL{
+		Interfaces: []*parser.Interface{
+			{
+				Name:      "A",
+				Namespace: "inc",
+				Methods: []*parser.Method{
+					{
+						Name:       "add",
+						Parameters: []*parser.Parameter{{Name: "a", Type: &parser.Type{BuiltIn: "int"}}},
+						ReturnType: &parser.Type{BuiltIn: "int"},
+					},
+				},
+			},
+		},
+	}
+
+	generate := func(executor string) string {
+		tmpDir := t.TempDir()
+		p := NewJavaClientServer()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		p.RegisterFlags(fs)
+		if err := fs.Set("dir", tmpDir); err != nil {
+			t.Fatalf("failed to set dir flag: %v", err)
+		}
+		if err := fs.Set("base-package", "com.example"); err != nil {
+			t.Fatalf("failed to set base-package flag: %v", err)
+		}
+		if executor != "" {
+			if err := fs.Set("request-executor", executor); err != nil {
+				t.Fatalf("failed to set request-executor flag: %v", err)
+			}
+		}
+		if err := p.Generate(idl, fs); err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		content, err := os.ReadFile(filepath.Join(tmpDir, "src", "main", "java", "com", "example", "Server.java"))
+		if err != nil {
+			t.Fatalf("expected Server.java: %v", err)
+		}
+		return string(content)
+	}
+
+	// Default: the HttpServer's own executor, but an Executor or HttpServer can be passed in
+	server := generate("")
+	for _, want := range []string{
+		"public Server(int port, JsonParser jsonParser, java.util.concurrent.Executor executor)",
+		"public Server(HttpServer server, JsonParser jsonParser, java.util.concurrent.Executor executor)",
+		"this(HttpServer.create(new InetSocketAddress(port), 0), jsonParser, null);",
+	} {
+		if !strings.Contains(server, want) {
+			t.Errorf("Server.java missing %q", want)
+		}
+	}
+	if strings.Contains(server, "defaultExecutor") {
+		t.Error("Server.java should not have a defaultExecutor without -request-executor")
+	}
+
+	// Opt-in: requests run on virtual threads where available
+	server = generate("true")
+	for _, want := range []string{
+		"this(HttpServer.create(new InetSocketAddress(port), 0), jsonParser, defaultExecutor());",
+		".getMethod(\"newVirtualThreadPerTaskExecutor\")",
+		"java.util.concurrent.Executors.newCachedThreadPool()",
+		"if (ownsExecutor && server.getExecutor() instanceof java.util.concurrent.ExecutorService)",
+	} {
+		if !strings.Contains(server, want) {
+			t.Errorf("Server.java with -request-executor missing %q", want)
+		}
+	}
+}
+
+func TestJavaGeneratorWireNames(t *testing.T) {
+	idl, err := parser.ParseIDL("users.pulse", `namespace users
+interface Users [wire="v1.user"] {
+  get(id string) string
+  remove(id string) bool [wire="v1.user.delete"]
+}`)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	p := NewJavaClientServer()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("dir", "", "output dir")
+	p.RegisterFlags(fs)
+	if err := fs.Set("dir", tmpDir); err != nil {
+		t.Fatalf("failed to set dir flag: %v", err)
+	}
+	if err := fs.Set("base-package", "com.example"); err != nil {
+		t.Fatalf("failed to set base-package flag: %v", err)
+	}
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	server, err := os.ReadFile(filepath.Join(tmpDir, "src", "main", "java", "com", "example", "Server.java"))
+	if err != nil {
+		t.Fatalf("expected Server.java: %v", err)
+	}
+	for _, want := range []string{
+		"private static final Map<String, String> WIRE_METHODS = Map.ofEntries(",
+		`Map.entry("v1.user.get", "Users.get"),`,
+		`Map.entry("v1.user.delete", "Users.remove")`,
+		"method = WIRE_METHODS.getOrDefault(method, method);",
+	} {
+		if !strings.Contains(string(server), want) {
+			t.Errorf("Server.java missing %q", want)
+		}
+	}
+
+	client, err := os.ReadFile(filepath.Join(tmpDir, "src", "main", "java", "com", "example", "users", "UsersClient.java"))
+	if err != nil {
+		t.Fatalf("expected UsersClient.java: %v", err)
+	}
+	for _, want := range []string{
+		`String method = "v1.user.get";`,
+		`String method = "v1.user.delete";`,
+	} {
+		if !strings.Contains(string(client), want) {
+			t.Errorf("UsersClient.java missing %q", want)
+		}
+	}
+}
+
+// largeIDL returns an IDL with the given number of structs and methods, so the
+// metadata tables of the generated code are big enough to matter at startup
+func largeIDL(t *testing.T, structs, methods int) *parser.IDL {
+	t.Helper()
+	var sb strings.Builder
+	sb.WriteString("namespace big\n\nenum Status {\n  active\n  retired\n}\n\n")
+	for i := 0; i < structs; i++ {
+		fmt.Fprintf(&sb, "struct Item%d {\n  id int\n  name string\n  status Status\n  tags []string [optional]\n}\n\n", i)
+	}
+	sb.WriteString("interface Catalog {\n")
+	for i := 0; i < methods; i++ {
+		fmt.Fprintf(&sb, "  get%d(id int, limit int [default=\"10\"], status Status [default=\"active\"]) Item%d\n", i, i%structs)
+	}
+	sb.WriteString("}\n")
+	idl, err := parser.ParseIDL("big.pulse", sb.String())
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	return idl
+}
+
+func TestJavaGeneratorLazyMetadata(t *testing.T) {
+	idl := largeIDL(t, 500, 500)
+
+	tmpDir := t.TempDir()
+	p := NewJavaClientServer()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("dir", "", "output dir")
+	p.RegisterFlags(fs)
+	if err := fs.Set("dir", tmpDir); err != nil {
+		t.Fatalf("failed to set dir flag: %v", err)
+	}
+	if err := fs.Set("base-package", "com.example"); err != nil {
+		t.Fatalf("failed to set base-package flag: %v", err)
+	}
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	base := filepath.Join(tmpDir, "src", "main", "java", "com", "example")
+
+	// The client must not touch the metadata, so creating one doesn't load bigIdl
+	client, err := os.ReadFile(filepath.Join(base, "Client.java"))
+	if err != nil {
+		t.Fatalf("expected Client.java: %v", err)
+	}
+	if strings.Contains(string(client), "ALL_STRUCTS") || strings.Contains(string(client), "ALL_ENUMS") {
+		t.Errorf("Client.java should not read the IDL metadata")
+	}
+
+	// The server's tables live in holder classes that the JVM initializes on first
+	// use, rather than in static blocks that run when Server is loaded
+	server, err := os.ReadFile(filepath.Join(base, "Server.java"))
+	if err != nil {
+		t.Fatalf("expected Server.java: %v", err)
+	}
+	if strings.Contains(string(server), "\n    static {\n") {
+		t.Errorf("Server.java should not build tables in a static block of Server")
+	}
+	for _, want := range []string{
+		"private static final class ParamNames {",
+		"String[] names = ParamNames.BY_METHOD.get(method);",
+		"OptionalParams optional = OptionalParams.BY_METHOD.get(method);",
+	} {
+		if !strings.Contains(string(server), want) {
+			t.Errorf("Server.java missing %q", want)
+		}
+	}
+	if got := strings.Count(string(server), "names.put(\"Catalog.get"); got != 500 {
+		t.Errorf("expected 500 param name entries in ParamNames, got %d", got)
+	}
+}
@@ -0,0 +1,614 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Async methods: a method marked [async] is run by servers as a background job.
+// A call validates its params, starts the job and returns {"jobId", "state"} at
+// once; the built-in pulserpc-job method takes a job id and returns the job's
+// state ("running", "succeeded" or "failed") with its result or error once it
+// has finished. Finished jobs are kept for jobRetention and purged when another
+// job starts. Client methods for [async] methods poll pulserpc-job until the job
+// finishes and return its result as if the call had been synchronous, reporting
+// each poll to an optional progress callback; the call's timeout bounds the whole
+// wait. There is no push channel, so clients always poll. The job support is
+// only generated when the IDL has [async] methods.
+
+// jobRetention is how long servers keep a finished job's outcome, in minutes
+const jobRetention = 10
+
+// usesAsyncMethods reports whether any method is [async]
+func usesAsyncMethods(interfaces []*parser.Interface) bool {
+	for _, iface := range interfaces {
+		for _, method := range iface.Methods {
+			if method.IsAsync() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeJobsServerGo writes the job store, startJob and jobStatus of the Go server
+func writeJobsServerGo(sb *strings.Builder) {
+	sb.WriteString(`// jobRequestID is the request id of the background run of an [async] method. Decoded
+// requests never carry one, so the run is not started as another job.
+type jobRequestID string
+
+// jobRetention is how long the outcome of a finished job can be fetched with pulserpc-job
+const jobRetention = ` + strconv.Itoa(jobRetention) + ` * time.Minute
+
+// serverJob is the state of a background run of an [async] method
+type serverJob struct {
+	state    string
+	response *rpcResponse
+	finished time.Time
+}
+
+// jobStore holds the jobs started by [async] methods
+type jobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*serverJob
+}
+
+// startJob runs an [async] call in the background and returns its job status
+func (s *PulseRPCServer) startJob(requestJson map[string]interface{}, requestID interface{}, isNotification bool) *rpcResponse {
+	idBytes := make([]byte, 16)
+	rand.Read(idBytes)
+	jobID := hex.EncodeToString(idBytes)
+	job := &serverJob{state: "running"}
+
+	s.jobs.mu.Lock()
+	if s.jobs.jobs == nil {
+		s.jobs.jobs = make(map[string]*serverJob)
+	}
+	for id, j := range s.jobs.jobs {
+		if j.state != "running" && time.Since(j.finished) > jobRetention {
+			delete(s.jobs.jobs, id)
+		}
+	}
+	s.jobs.jobs[jobID] = job
+	s.jobs.mu.Unlock()
+
+	run := make(map[string]interface{}, len(requestJson))
+	for k, v := range requestJson {
+		run[k] = v
+	}
+	run["id"] = jobRequestID(jobID)
+	go func() {
+		var response *rpcResponse
+		defer func() {
+			if r := recover(); r != nil {
+				response = s.errorResponse(run["id"], -32603, "Internal error", fmt.Sprintf("%v", r))
+			}
+			s.jobs.mu.Lock()
+			defer s.jobs.mu.Unlock()
+			job.response = response
+			job.finished = time.Now()
+			job.state = "succeeded"
+			if response != nil && response.Error != nil {
+				job.state = "failed"
+			}
+		}()
+		response = s.handleSingleRequest(context.Background(), run)
+	}()
+
+	if isNotification {
+		return nil
+	}
+	return &rpcResponse{ID: requestID, Result: map[string]interface{}{"jobId": jobID, "state": "running"}}
+}
+
+// jobStatus returns the state of a job with its result or error once it has
+// finished, or nil if the job is unknown
+func (s *PulseRPCServer) jobStatus(jobID string) map[string]interface{} {
+	s.jobs.mu.Lock()
+	defer s.jobs.mu.Unlock()
+	job, ok := s.jobs.jobs[jobID]
+	if !ok {
+		return nil
+	}
+	status := map[string]interface{}{"jobId": jobID, "state": job.state}
+	switch job.state {
+	case "succeeded":
+		status["result"] = job.response.Result
+	case "failed":
+		status["error"] = job.response.Error
+	}
+	return status
+}
+
+`)
+}
+
+// writeJobsClientGo writes the job options and the awaitJob poller of the Go client
+func writeJobsClientGo(sb *strings.Builder) {
+	sb.WriteString(`// JobStatus is the state of the job an [async] method started, as passed to the
+// WithJobProgress callback after each poll
+type JobStatus struct {
+	JobID string
+	// State is "running", "succeeded" or "failed"
+	State string
+}
+
+// WithJobPollInterval sets how often an [async] method polls its job; the default is a second
+func WithJobPollInterval(interval time.Duration) CallOption {
+	return func(o *CallOptions) { o.JobPollInterval = interval }
+}
+
+// WithJobProgress calls progress with the job status each time an [async] method polls its job
+func WithJobProgress(progress func(JobStatus)) CallOption {
+	return func(o *CallOptions) { o.JobProgress = progress }
+}
+
+// awaitJob polls pulserpc-job until the job an [async] method started has finished,
+// and returns a response holding the job's result, or the job's error as an
+// *RPCError. options.Timeout bounds the whole wait.
+func awaitJob(transport Transport, response map[string]interface{}, options CallOptions) (map[string]interface{}, error) {
+	started := time.Now()
+	interval := options.JobPollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	for {
+		status, _ := response["result"].(map[string]interface{})
+		jobID, _ := status["jobId"].(string)
+		state, _ := status["state"].(string)
+		if options.JobProgress != nil {
+			options.JobProgress(JobStatus{JobID: jobID, State: state})
+		}
+		switch state {
+		case "succeeded":
+			return map[string]interface{}{"jsonrpc": "2.0", "result": status["result"], "id": response["id"]}, nil
+		case "failed":
+			errObj, _ := status["error"].(map[string]interface{})
+			code, _ := errObj["code"].(float64)
+			message, _ := errObj["message"].(string)
+			return nil, &RPCError{Code: int(code), Message: message, Data: errObj["data"]}
+		case "running":
+		default:
+			return nil, fmt.Errorf("invalid job status: %v", response["result"])
+		}
+		if options.Timeout > 0 && time.Since(started)+interval > options.Timeout {
+			return nil, fmt.Errorf("job %s did not finish within %v", jobID, options.Timeout)
+		}
+		time.Sleep(interval)
+		var err error
+		response, err = callTransport(transport, "pulserpc-job", []interface{}{jobID}, CallOptions{Timeout: options.Timeout, Headers: options.Headers})
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+`)
+}
+
+// writeJobsServerPy writes the _start_job and _job_status methods of the Python server
+func writeJobsServerPy(sb *strings.Builder) {
+	sb.WriteString(`    def _start_job(self, request_json: Dict[str, Any], request_id: Any, is_notification: bool) -> Optional[Dict[str, Any]]:
+        """Run an [async] call in a background thread and return its job status"""
+        job_id = secrets.token_hex(16)
+        with self._jobs_lock:
+            now = time.monotonic()
+            for stale in [jid for jid, job in self._jobs.items() if job['state'] != 'running' and now - job['finished'] > JOB_RETENTION]:
+                del self._jobs[stale]
+            self._jobs[job_id] = {'state': 'running'}
+
+        def run() -> None:
+            try:
+                response = self.handle_request({**request_json, 'id': _JobRequestId(job_id)})
+            except Exception as e:
+                response = self._error_response(job_id, -32603, "Internal error", str(e))
+            with self._jobs_lock:
+                self._jobs[job_id] = {
+                    'state': 'failed' if 'error' in response else 'succeeded',
+                    'response': response,
+                    'finished': time.monotonic(),
+                }
+
+        threading.Thread(target=run, daemon=True).start()
+        if is_notification:
+            return None
+        return {'jsonrpc': '2.0', 'result': {'jobId': job_id, 'state': 'running'}, 'id': request_id}
+
+    def _job_status(self, job_id: Any) -> Optional[Dict[str, Any]]:
+        """The state of a job with its result or error once it has finished, or None if
+        the job is unknown"""
+        with self._jobs_lock:
+            job = self._jobs.get(job_id) if isinstance(job_id, str) else None
+            if job is None:
+                return None
+            status = {'jobId': job_id, 'state': job['state']}
+            if job['state'] == 'succeeded':
+                status['result'] = job['response'].get('result')
+            elif job['state'] == 'failed':
+                status['error'] = job['response']['error']
+            return status
+
+`)
+}
+
+// writeJobsClientPy writes JobStatus and the _await_job poller of the Python client
+func writeJobsClientPy(sb *strings.Builder) {
+	sb.WriteString(`@dataclass
+class JobStatus:
+    """The state of the job an [async] method started, as passed to on_progress after
+    each poll. state is 'running', 'succeeded' or 'failed'."""
+    job_id: str
+    state: str
+
+
+def _await_job(transport: 'Transport', response: dict, options: CallOptions, poll_interval: float,
+               on_progress: Optional[Callable[[JobStatus], None]]) -> dict:
+    """Poll pulserpc-job until the job an [async] method started has finished, and return
+    a response holding the job's result or error. options.timeout bounds the whole wait."""
+    started = time.monotonic()
+    while 'error' not in response:
+        status = response.get('result') or {}
+        job_id, state = status.get('jobId'), status.get('state')
+        if on_progress is not None:
+            on_progress(JobStatus(job_id, state))
+        if state == 'succeeded':
+            return {'jsonrpc': '2.0', 'result': status.get('result'), 'id': response.get('id')}
+        if state == 'failed':
+            return {'jsonrpc': '2.0', 'error': status.get('error') or {}, 'id': response.get('id')}
+        if state != 'running':
+            raise ValueError(f"Invalid job status: {status}")
+        if options.timeout is not None and time.monotonic() - started + poll_interval > options.timeout:
+            raise TimeoutError(f"job {job_id} did not finish within {options.timeout}s")
+        time.sleep(poll_interval)
+        response = transport.call_with_options('pulserpc-job', [job_id],
+                                               CallOptions(timeout=options.timeout, headers=options.headers))
+    return response
+
+
+`)
+}
+
+// writeJobsTypesTs writes the job types of the TypeScript server
+func writeJobsTypesTs(sb *strings.Builder) {
+	sb.WriteString(`// Milliseconds the outcome of a finished job can be fetched with pulserpc-job
+const JOB_RETENTION_MS = ` + strconv.Itoa(jobRetention*60*1000) + `;
+
+// Request id of the background run of an [async] method. Decoded requests never
+// carry one, so the run is not started as another job.
+class JobRequestId {
+  constructor(readonly jobId: string) {}
+}
+
+// The state of a background run of an [async] method
+interface ServerJob {
+  state: 'running' | 'succeeded' | 'failed';
+  response?: any;
+  finished?: number;
+}
+
+`)
+}
+
+// writeJobsServerTs writes the startJob and jobStatus methods of the TypeScript server
+func writeJobsServerTs(sb *strings.Builder) {
+	sb.WriteString(`  // Runs an [async] call in the background and returns its job status
+  private startJob(requestJson: any, requestId: any, isNotification: boolean): any {
+    const jobId = randomBytes(16).toString('hex');
+    const now = Date.now();
+    for (const [id, job] of this.jobs) {
+      if (job.state !== 'running' && now - (job.finished ?? now) > JOB_RETENTION_MS) {
+        this.jobs.delete(id);
+      }
+    }
+    const job: ServerJob = { state: 'running' };
+    this.jobs.set(jobId, job);
+    setImmediate(() => {
+      const response = this.handleRequest({ ...requestJson, id: new JobRequestId(jobId) });
+      job.state = response.error ? 'failed' : 'succeeded';
+      job.response = response;
+      job.finished = Date.now();
+    });
+    if (isNotification) {
+      return null;
+    }
+    return { jsonrpc: '2.0', result: { jobId, state: 'running' }, id: requestId };
+  }
+
+  // The state of a job with its result or error once it has finished, or null if
+  // the job is unknown
+  private jobStatus(jobId: any): any {
+    const job = typeof jobId === 'string' ? this.jobs.get(jobId) : undefined;
+    if (!job) {
+      return null;
+    }
+    const status: any = { jobId, state: job.state };
+    if (job.state === 'succeeded') {
+      status.result = job.response.result;
+    } else if (job.state === 'failed') {
+      status.error = job.response.error;
+    }
+    return status;
+  }
+
+`)
+}
+
+// writeJobsClientTs writes the JobStatus type and the awaitJob poller of the
+// TypeScript client
+func writeJobsClientTs(sb *strings.Builder, packagePrefix string) {
+	statusName := applyPackagePrefix("JobStatus", packagePrefix)
+	optionsName := applyPackagePrefix("CallOptions", packagePrefix)
+	transportName := applyPackagePrefix("Transport", packagePrefix)
+	sb.WriteString(`/**
+ * The state of the job an [async] method started, as passed to onJobProgress
+ * after each poll
+ */
+export interface ` + statusName + ` {
+  jobId: string;
+  state: 'running' | 'succeeded' | 'failed';
+}
+
+/**
+ * Polls pulserpc-job until the job an [async] method started has finished, and
+ * returns a response holding the job's result or error. options.timeoutMs bounds
+ * the whole wait.
+ */
+async function awaitJob(transport: ` + transportName + `, response: any, options: ` + optionsName + `): Promise<any> {
+  const started = Date.now();
+  const interval = options.jobPollMs ?? 1000;
+  while (!response.error) {
+    const status = response.result ?? {};
+    options.onJobProgress?.({ jobId: status.jobId, state: status.state });
+    if (status.state === 'succeeded') {
+      return { jsonrpc: '2.0', result: status.result, id: response.id };
+    }
+    if (status.state === 'failed') {
+      return { jsonrpc: '2.0', error: status.error ?? {}, id: response.id };
+    }
+    if (status.state !== 'running') {
+      throw new Error(` + "`Invalid job status: ${JSON.stringify(status)}`" + `);
+    }
+    if (options.timeoutMs !== undefined && Date.now() - started + interval > options.timeoutMs) {
+      throw new Error(` + "`job ${status.jobId} did not finish within ${options.timeoutMs}ms`" + `);
+    }
+    await new Promise((resolve) => setTimeout(resolve, interval));
+    response = await transport.callWithOptions('pulserpc-job', [status.jobId], { timeoutMs: options.timeoutMs, headers: options.headers });
+  }
+  return response;
+}
+
+`)
+}
+
+// writeJobsServerCs writes the job store, StartJob and GetJobStatus of the C# server
+func writeJobsServerCs(sb *strings.Builder) {
+	sb.WriteString(`    // Request id of the background run of an [async] method. Decoded requests never
+    // carry one, so the run is not started as another job.
+    private sealed record JobRequestId(string JobId);
+
+    // The state of a background run of an [async] method
+    private sealed record ServerJob(string State, RpcResponse? Response, DateTime Finished);
+
+    // How long the outcome of a finished job can be fetched with pulserpc-job
+    private static readonly TimeSpan JobRetention = TimeSpan.FromMinutes(` + strconv.Itoa(jobRetention) + `);
+
+    // Jobs started by [async] methods, by job id
+    private readonly System.Collections.Concurrent.ConcurrentDictionary<string, ServerJob> _jobs = new System.Collections.Concurrent.ConcurrentDictionary<string, ServerJob>();
+
+    // Runs an [async] call in the background and returns its job status
+    private RpcResponse? StartJob(Dictionary<string, object?> requestJson, object? requestId, bool isNotification)
+    {
+        var jobId = Convert.ToHexString(System.Security.Cryptography.RandomNumberGenerator.GetBytes(16)).ToLowerInvariant();
+        foreach (var entry in _jobs)
+        {
+            if (entry.Value.State != "running" && DateTime.UtcNow - entry.Value.Finished > JobRetention)
+            {
+                _jobs.TryRemove(entry.Key, out _);
+            }
+        }
+        _jobs[jobId] = new ServerJob("running", null, DateTime.UtcNow);
+
+        var run = new Dictionary<string, object?>(requestJson) { ["id"] = new JobRequestId(jobId) };
+        _ = Task.Run(async () =>
+        {
+            RpcResponse? response;
+            try
+            {
+                response = await HandleSingleRequest(run);
+            }
+            catch (Exception e)
+            {
+                response = ErrorResponse(run["id"], -32603, "Internal error", e.Message);
+            }
+            var failed = response == null || response.Error != null;
+            _jobs[jobId] = new ServerJob(failed ? "failed" : "succeeded", response, DateTime.UtcNow);
+        });
+
+        if (isNotification) return null;
+        return new RpcResponse(requestId, new Dictionary<string, object?> { { "jobId", jobId }, { "state", "running" } });
+    }
+
+    // The state of a job with its result or error once it has finished, or null if the
+    // job is unknown
+    private Dictionary<string, object?>? GetJobStatus(string? jobId)
+    {
+        if (jobId == null || !_jobs.TryGetValue(jobId, out var job))
+        {
+            return null;
+        }
+        var status = new Dictionary<string, object?> { { "jobId", jobId }, { "state", job.State } };
+        if (job.State == "succeeded")
+        {
+            status["result"] = job.Response?.Result;
+        }
+        else if (job.State == "failed")
+        {
+            status["error"] = job.Response?.Error;
+        }
+        return status;
+    }
+
+`)
+}
+
+// writeJobsClientCs writes JobStatus and the JobPoller of the C# client
+func writeJobsClientCs(sb *strings.Builder) {
+	sb.WriteString(`/// <summary>
+/// The state of the job an [async] method started, as passed to JobProgress after each
+/// poll. State is "running", "succeeded" or "failed".
+/// </summary>
+public sealed record JobStatus(string JobId, string State);
+
+internal static class JobPoller
+{
+    /// <summary>
+    /// Polls pulserpc-job until the job an [async] method started has finished, and returns
+    /// a response holding the job's result, or throws the job's error as an RPCError.
+    /// options.Timeout bounds the whole wait.
+    /// </summary>
+    public static async Task<Dictionary<string, object?>> AwaitAsync(ITransport transport, Dictionary<string, object?> response, CallOptions options)
+    {
+        var started = System.Diagnostics.Stopwatch.StartNew();
+        var interval = options.JobPollInterval ?? TimeSpan.FromSeconds(1);
+        while (true)
+        {
+            var status = response.TryGetValue("result", out var result) && result is JsonElement element ? element : default;
+            var jobId = Text(status, "jobId");
+            var state = Text(status, "state");
+            options.JobProgress?.Invoke(new JobStatus(jobId, state));
+            switch (state)
+            {
+                case "succeeded":
+                    return new Dictionary<string, object?> { { "jsonrpc", "2.0" }, { "result", status.TryGetProperty("result", out var value) ? value : null } };
+                case "failed":
+                    var error = status.TryGetProperty("error", out var e) ? e : default;
+                    var code = error.ValueKind == JsonValueKind.Object && error.TryGetProperty("code", out var c) ? c.GetInt32() : -32603;
+                    throw new RPCError(code, Text(error, "message"), error.ValueKind == JsonValueKind.Object && error.TryGetProperty("data", out var data) ? data : null);
+                case "running":
+                    break;
+                default:
+                    throw new RPCError(-32603, "Internal error", $"Invalid job status: {result}");
+            }
+            if (options.Timeout is TimeSpan timeout && started.Elapsed + interval > timeout)
+            {
+                throw new TimeoutException($"job {jobId} did not finish within {timeout}");
+            }
+            await Task.Delay(interval);
+            response = await transport.CallAsync("pulserpc-job", new object[] { jobId }, CallOptions.None with { Timeout = options.Timeout, Headers = options.Headers });
+        }
+    }
+
+    private static string Text(JsonElement element, string name) =>
+        element.ValueKind == JsonValueKind.Object && element.TryGetProperty(name, out var property) && property.ValueKind == JsonValueKind.String
+            ? property.GetString()!
+            : "";
+}
+
+`)
+}
+
+// writeJobsServerJava writes the table of [async] methods, the job store, startJob
+// and jobStatus of the Java server
+func writeJobsServerJava(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("    // [async] methods, by JSON-RPC method name, built on first use\n")
+	sb.WriteString("    private static final class AsyncMethods {\n")
+	sb.WriteString("        static final Set<String> NAMES;\n")
+	sb.WriteString("        static {\n")
+	sb.WriteString("            Set<String> names = new HashSet<>();\n")
+	for _, iface := range interfaces {
+		for _, method := range iface.Methods {
+			if method.IsAsync() {
+				fmt.Fprintf(sb, "            names.add(\"%s.%s\");\n", iface.Name, method.Name)
+			}
+		}
+	}
+	sb.WriteString("            NAMES = Collections.unmodifiableSet(names);\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("    }\n\n")
+	sb.WriteString(`    // Request id of the background run of an [async] method. Decoded requests never
+    // carry one, so the run is not started as another job.
+    private static final class JobRequestId {
+        final String jobId;
+
+        JobRequestId(String jobId) {
+            this.jobId = jobId;
+        }
+    }
+
+    // The state of a background run of an [async] method
+    private static final class ServerJob {
+        final String state;
+        final Map<String, Object> response;
+        final long finished;
+
+        ServerJob(String state, Map<String, Object> response, long finished) {
+            this.state = state;
+            this.response = response;
+            this.finished = finished;
+        }
+    }
+
+    // How long the outcome of a finished job can be fetched with pulserpc-job
+    private static final java.time.Duration JOB_RETENTION = java.time.Duration.ofMinutes(` + strconv.Itoa(jobRetention) + `);
+
+    // Jobs started by [async] methods, by job id
+    private final Map<String, ServerJob> jobs = new java.util.concurrent.ConcurrentHashMap<>();
+
+    // Runs an [async] call in the background and returns its job status
+    private Map<String, Object> startJob(Map<String, Object> request, Object id) {
+        byte[] idBytes = new byte[16];
+        new java.security.SecureRandom().nextBytes(idBytes);
+        StringBuilder hex = new StringBuilder();
+        for (byte b : idBytes) {
+            hex.append(String.format("%02x", b));
+        }
+        String jobId = hex.toString();
+        long now = System.currentTimeMillis();
+        jobs.entrySet().removeIf(e -> !"running".equals(e.getValue().state) && now - e.getValue().finished > JOB_RETENTION.toMillis());
+        jobs.put(jobId, new ServerJob("running", null, now));
+
+        Map<String, Object> run = new HashMap<>(request);
+        run.put("id", new JobRequestId(jobId));
+        Thread thread = new Thread(() -> {
+            Map<String, Object> response;
+            try {
+                response = handleJsonRpcRequest(run);
+            } catch (RuntimeException e) {
+                response = Map.of("error", Map.of("code", -32603, "message", "Internal error: " + e.getMessage()));
+            }
+            String state = response.containsKey("error") ? "failed" : "succeeded";
+            jobs.put(jobId, new ServerJob(state, response, System.currentTimeMillis()));
+        });
+        thread.setDaemon(true);
+        thread.start();
+
+        return Map.of(
+            "jsonrpc", "2.0",
+            "result", Map.of("jobId", jobId, "state", "running"),
+            "id", id
+        );
+    }
+
+    // The state of a job with its result or error once it has finished, or null if the
+    // job is unknown
+    private Map<String, Object> jobStatus(Object jobId) {
+        ServerJob job = jobId instanceof String ? jobs.get(jobId) : null;
+        if (job == null) {
+            return null;
+        }
+        Map<String, Object> status = new HashMap<>();
+        status.put("jobId", jobId);
+        status.put("state", job.state);
+        if ("succeeded".equals(job.state)) {
+            status.put("result", job.response.get("result"));
+        } else if ("failed".equals(job.state)) {
+            status.put("error", job.response.get("error"));
+        }
+        return status;
+    }
+
+`)
+}
@@ -0,0 +1,340 @@
+package generator
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// The js-browser-client plugin writes a client for web apps that loads without a
+// bundler or any npm packages: plain ES modules that call the server with fetch
+// and take request ids from Web Crypto. Types are described in JSDoc, which
+// editors and `tsc --checkJs` read, rather than checked at runtime, keeping the
+// modules small:
+//
+//	pulserpc.js      RPCError, TransportError and HTTPTransport
+//	<namespace>.js   the namespace's enums (frozen objects), structs and typedefs
+//	                 (JSDoc typedefs) and a <Interface>Client class per interface
+//	index.js         re-exports pulserpc.js and each namespace module by name
+//
+// Types of other namespaces are referred to as import('./<namespace>.js').Name, so
+// the modules only import each other for types, which costs nothing at runtime.
+
+// jsRuntimeModule is the module holding the browser client's transport
+const jsRuntimeModule = "pulserpc.js"
+
+// JSBrowserClient generates dependency-free ES module clients for browsers
+type JSBrowserClient struct {
+}
+
+// NewJSBrowserClient creates a new JSBrowserClient plugin instance
+func NewJSBrowserClient() *JSBrowserClient {
+	return &JSBrowserClient{}
+}
+
+// Name returns the plugin identifier
+func (p *JSBrowserClient) Name() string {
+	return "js-browser-client"
+}
+
+// RegisterFlags registers CLI flags for this plugin
+func (p *JSBrowserClient) RegisterFlags(fs *flag.FlagSet) {
+}
+
+// Generate writes pulserpc.js, a module per namespace and index.js
+func (p *JSBrowserClient) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
+	start := repoFilesStart()
+
+	outputDir := ""
+	if dirFlag := fs.Lookup("dir"); dirFlag != nil {
+		outputDir = dirFlag.Value.String()
+	}
+	if usesEncryptedFields(idl) {
+		// Sending the fields without a FieldCipher would send them in plaintext
+		return fmt.Errorf("[encrypted] fields are not supported by the js-browser-client plugin")
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	runtimeCode := embeddedTemplates.renderString("js/pulserpc.js.tmpl", nil)
+	if err := writeGeneratedFile(filepath.Join(outputDir, jsRuntimeModule), []byte(runtimeCode)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", jsRuntimeModule, err)
+	}
+
+	modules := buildJSModules(idl)
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		if name+".js" == jsRuntimeModule || name == "index" {
+			return fmt.Errorf("namespace %s cannot be generated by the js-browser-client plugin: %s.js is taken by the runtime", name, name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		file := name + ".js"
+		if err := writeGeneratedFile(filepath.Join(outputDir, file), []byte(modules[name])); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+	if err := writeGeneratedFile(filepath.Join(outputDir, "index.js"), []byte(generateJSIndex(names))); err != nil {
+		return fmt.Errorf("failed to write index.js: %w", err)
+	}
+
+	if repoFilesRequested(fs) {
+		return writeRepoFiles("js", codeStyle{Indent: 2}, start, outputDir)
+	}
+	return nil
+}
+
+// Verify checks the syntax of the generated modules with node --check. Node reads
+// each module from stdin as an ES module, since without a package.json declaring
+// "type": "module" it would take a .js file for CommonJS.
+func (p *JSBrowserClient) Verify(fs *flag.FlagSet) error {
+	outputDir := verifyOutputDir(fs)
+	files, err := findGeneratedFiles(outputDir, ".js")
+	if err != nil {
+		return fmt.Errorf("failed to list generated JavaScript files: %w", err)
+	}
+	node, err := exec.LookPath("node")
+	if err != nil {
+		return fmt.Errorf("-verify requires node on PATH: %w", err)
+	}
+	for _, file := range files {
+		src, err := os.ReadFile(filepath.Join(outputDir, file))
+		if err != nil {
+			return err
+		}
+		cmd := exec.Command(node, "--check", "--input-type=module")
+		cmd.Stdin = strings.NewReader(string(src))
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("generated code failed to compile (node --check %s): %w\n%s",
+				file, err, strings.TrimSpace(strings.ReplaceAll(string(out), "[stdin]", file)))
+		}
+	}
+	return nil
+}
+
+// jsModuleBuilder writes the namespace modules of an IDL
+type jsModuleBuilder struct {
+	idl     *parser.IDL
+	structs map[string]*parser.Struct
+	// modules maps each struct, enum and typedef name to the module declaring it
+	modules map[string]string
+}
+
+// buildJSModules returns the source of each namespace module by module name
+func buildJSModules(idl *parser.IDL) map[string]string {
+	b := &jsModuleBuilder{idl: idl, structs: make(map[string]*parser.Struct), modules: make(map[string]string)}
+	for _, s := range idl.Structs {
+		b.structs[s.Name] = s
+		b.modules[s.Name] = b.module(s.Namespace)
+	}
+	for _, e := range idl.Enums {
+		b.modules[e.Name] = b.module(e.Namespace)
+	}
+	for _, td := range idl.Typedefs {
+		b.modules[td.Name] = b.module(td.Namespace)
+	}
+
+	// Declarations without a namespace share the module of the root namespace
+	grouped := GroupTypesByNamespace(idl)
+	namespaces := make([]string, 0, len(grouped))
+	for namespace := range grouped {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	byModule := make(map[string]*NamespaceTypes)
+	for _, namespace := range namespaces {
+		name, types := b.module(namespace), grouped[namespace]
+		if merged := byModule[name]; merged != nil {
+			merged.Structs = append(merged.Structs, types.Structs...)
+			merged.Enums = append(merged.Enums, types.Enums...)
+			merged.Typedefs = append(merged.Typedefs, types.Typedefs...)
+			merged.Interfaces = append(merged.Interfaces, types.Interfaces...)
+		} else {
+			byModule[name] = types
+		}
+	}
+
+	modules := make(map[string]string)
+	for name, types := range byModule {
+		var sb strings.Builder
+		sb.WriteString("// Generated by pulserpc - do not edit\n")
+		for _, e := range types.Enums {
+			b.writeEnum(&sb, e)
+		}
+		for _, s := range types.Structs {
+			b.writeStruct(&sb, s, name)
+		}
+		for _, td := range types.Typedefs {
+			sb.WriteString("\n")
+			writeJSDoc(&sb, "", td.Comment, fmt.Sprintf("@typedef {%s} %s", b.typeExpr(td.Type, name), localTypeName(td.Name)))
+		}
+		for _, iface := range types.Interfaces {
+			b.writeClient(&sb, iface, name)
+		}
+		modules[name] = sb.String()
+	}
+	return modules
+}
+
+// module returns the name of the module of namespace; declarations of files
+// without a namespace belong to the root namespace
+func (b *jsModuleBuilder) module(namespace string) string {
+	if namespace == "" {
+		namespace = b.idl.RootNamespace
+	}
+	if namespace == "" {
+		return "api"
+	}
+	return namespace
+}
+
+// writeEnum writes an enum as a frozen object mapping each value to itself, which
+// JSDoc's @enum lets editors use as a type
+func (b *jsModuleBuilder) writeEnum(sb *strings.Builder, e *parser.Enum) {
+	sb.WriteString("\n")
+	writeJSDoc(sb, "", e.Comment, "@readonly", "@enum {string}")
+	fmt.Fprintf(sb, "export const %s = Object.freeze({\n", localTypeName(e.Name))
+	for _, v := range e.Values {
+		if v.Comment != "" {
+			fmt.Fprintf(sb, "  /** %s */\n", jsDocText(strings.ReplaceAll(v.Comment, "\n", " ")))
+		}
+		fmt.Fprintf(sb, "  %s: '%s',\n", v.Name, v.Name)
+	}
+	sb.WriteString("});\n")
+}
+
+// writeStruct writes a struct as a JSDoc typedef. The fields of the structs it
+// extends come first, as on the wire.
+func (b *jsModuleBuilder) writeStruct(sb *strings.Builder, s *parser.Struct, module string) {
+	tags := []string{"@typedef {Object} " + localTypeName(s.Name)}
+	for _, field := range parser.ResolveStructFields(s, b.structs) {
+		typ := b.typeExpr(field.Type, module)
+		name := field.Name
+		if field.Optional {
+			typ += " | null"
+			name = "[" + name + "]"
+		}
+		tag := fmt.Sprintf("@property {%s} %s", typ, name)
+		if field.Comment != "" {
+			tag += " " + strings.ReplaceAll(field.Comment, "\n", " ")
+		}
+		tags = append(tags, tag)
+	}
+	sb.WriteString("\n")
+	writeJSDoc(sb, "", s.Comment, tags...)
+}
+
+// writeClient writes the client class of an interface, with a method per method of
+// the interface, including those it inherits
+func (b *jsModuleBuilder) writeClient(sb *strings.Builder, iface *parser.Interface, module string) {
+	name := localTypeName(iface.Name) + "Client"
+	sb.WriteString("\n")
+	writeJSDoc(sb, "", iface.Comment)
+	fmt.Fprintf(sb, "export class %s {\n", name)
+	fmt.Fprintf(sb, "  /** @param {import('./%s').Transport} transport */\n", jsRuntimeModule)
+	sb.WriteString("  constructor(transport) {\n    this.transport = transport;\n  }\n")
+	for _, method := range iface.Methods {
+		var tags, params []string
+		for _, param := range method.Parameters {
+			typ := b.typeExpr(param.Type, module)
+			pname := param.Name
+			if param.Optional {
+				typ += " | null"
+				pname = "[" + pname + "]"
+			}
+			tags = append(tags, fmt.Sprintf("@param {%s} %s", typ, pname))
+			params = append(params, param.Name)
+		}
+		tags = append(tags, fmt.Sprintf("@param {import('./%s').CallOptions} [options]", jsRuntimeModule))
+		result := b.typeExpr(method.ReturnType, module)
+		if method.ReturnOptional {
+			result += " | null"
+		}
+		tags = append(tags, fmt.Sprintf("@returns {Promise<%s>}", result))
+		sb.WriteString("\n")
+		writeJSDoc(sb, "  ", "", tags...)
+		fmt.Fprintf(sb, "  %s(%s) {\n", method.Name, strings.Join(append(params, "options"), ", "))
+		fmt.Fprintf(sb, "    return this.transport.call('%s', [%s], options);\n", iface.RPCName(method), strings.Join(params, ", "))
+		sb.WriteString("  }\n")
+	}
+	sb.WriteString("}\n")
+}
+
+// typeExpr returns the JSDoc type of a value of type t in module
+func (b *jsModuleBuilder) typeExpr(t *parser.Type, module string) string {
+	switch {
+	case t == nil:
+		return "*"
+	case t.IsArray():
+		return "Array<" + b.typeExpr(t.Array, module) + ">"
+	case t.IsMap():
+		return "Record<string, " + b.typeExpr(t.MapValue, module) + ">"
+	case t.IsUserDefined():
+		target, ok := b.modules[t.UserDefined]
+		if !ok {
+			// An unresolved name, such as an interface, holds any value
+			return "*"
+		}
+		if target == module {
+			return localTypeName(t.UserDefined)
+		}
+		return fmt.Sprintf("import('./%s.js').%s", target, localTypeName(t.UserDefined))
+	}
+	switch t.BuiltIn {
+	case "int", "float":
+		return "number"
+	case "bool":
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// writeJSDoc writes a JSDoc comment holding the lines of comment followed by tags,
+// each line prefixed by indent. It writes nothing when both are empty.
+func writeJSDoc(sb *strings.Builder, indent, comment string, tags ...string) {
+	var lines []string
+	if comment != "" {
+		lines = append(lines, strings.Split(comment, "\n")...)
+	}
+	lines = append(lines, tags...)
+	if len(lines) == 0 {
+		return
+	}
+	sb.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		line = jsDocText(line)
+		if line == "" {
+			sb.WriteString(indent + " *\n")
+		} else {
+			sb.WriteString(indent + " * " + line + "\n")
+		}
+	}
+	sb.WriteString(indent + " */\n")
+}
+
+// jsDocText returns text with trailing space removed and comment terminators
+// escaped, so it can be written inside a JSDoc comment
+func jsDocText(text string) string {
+	return strings.ReplaceAll(strings.TrimRight(text, " \t"), "*/", "*\\/")
+}
+
+// generateJSIndex returns index.js, re-exporting the runtime and each module by name
+func generateJSIndex(modules []string) string {
+	var sb strings.Builder
+	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
+	fmt.Fprintf(&sb, "export * from './%s';\n", jsRuntimeModule)
+	for _, name := range modules {
+		fmt.Fprintf(&sb, "export * as %s from './%s.js';\n", name, name)
+	}
+	return sb.String()
+}
@@ -0,0 +1,164 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func jsBrowserTestIDL() *parser.IDL {
+	return &parser.IDL{
+		RootNamespace: "shop",
+		Enums: []*parser.Enum{
+			{Name: "inc.Status", Namespace: "inc", Values: []*parser.EnumValue{{Name: "ok"}, {Name: "err"}}},
+		},
+		Structs: []*parser.Struct{
+			{Name: "inc.Response", Namespace: "inc", Fields: []*parser.Field{
+				{Name: "status", Type: &parser.Type{UserDefined: "inc.Status"}},
+			}},
+			{Name: "Order", Namespace: "shop", Extends: "inc.Response", Comment: "An order */ of items", Fields: []*parser.Field{
+				{Name: "items", Type: &parser.Type{Array: &parser.Type{BuiltIn: "string"}}},
+				{Name: "note", Type: &parser.Type{BuiltIn: "string"}, Optional: true},
+			}},
+		},
+		Interfaces: []*parser.Interface{
+			{Name: "Orders", Namespace: "shop", Methods: []*parser.Method{
+				{
+					Name:           "find",
+					Parameters:     []*parser.Parameter{{Name: "id", Type: &parser.Type{BuiltIn: "int"}}},
+					ReturnType:     &parser.Type{UserDefined: "Order"},
+					ReturnOptional: true,
+				},
+			}},
+		},
+	}
+}
+
+func generateJSBrowserClient(t *testing.T, idl *parser.IDL) string {
+	t.Helper()
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("dir", dir, "output dir")
+	plugin := NewJSBrowserClient()
+	plugin.RegisterFlags(fs)
+	if err := plugin.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	return dir
+}
+
+func TestJSBrowserClient(t *testing.T) {
+	dir := generateJSBrowserClient(t, jsBrowserTestIDL())
+
+	shop, err := os.ReadFile(filepath.Join(dir, "shop.js"))
+	if err != nil {
+		t.Fatalf("expected shop.js: %v", err)
+	}
+	for _, want := range []string{
+		" * An order *\\/ of items\n",
+		// Inherited fields come first and refer to their namespace's module
+		" * @property {import('./inc.js').Status} status\n * @property {Array<string>} items\n * @property {string | null} [note]\n",
+		"export class OrdersClient {\n",
+		" * @returns {Promise<Order | null>}\n",
+		"  find(id, options) {\n    return this.transport.call('Orders.find', [id], options);\n",
+	} {
+		if !strings.Contains(string(shop), want) {
+			t.Errorf("shop.js lacks %q:\n%s", want, shop)
+		}
+	}
+	if strings.Contains(string(shop), "import ") && strings.Contains(string(shop), "from '") {
+		t.Errorf("shop.js imports other modules at runtime:\n%s", shop)
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.js"))
+	if err != nil {
+		t.Fatalf("expected index.js: %v", err)
+	}
+	if !strings.Contains(string(index), "export * as inc from './inc.js';\n") {
+		t.Errorf("index.js does not re-export inc.js:\n%s", index)
+	}
+}
+
+func TestJSBrowserClientRejectsEncryptedFields(t *testing.T) {
+	idl := jsBrowserTestIDL()
+	idl.Structs[1].Fields[1].Annotations = []*parser.Annotation{{Name: parser.AnnotationEncrypted}}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("dir", t.TempDir(), "output dir")
+	err := NewJSBrowserClient().Generate(idl, fs)
+	if err == nil || !strings.Contains(err.Error(), "[encrypted]") {
+		t.Fatalf("expected an [encrypted] error, got %v", err)
+	}
+}
+
+// TestJSBrowserClientCalls runs a generated client in Node.js against a fetch stub
+func TestJSBrowserClientCalls(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node not available")
+	}
+	dir := generateJSBrowserClient(t, jsBrowserTestIDL())
+	fs := newVerifyFlagSet(t, dir)
+	if err := NewJSBrowserClient().Verify(fs); err != nil {
+		t.Fatalf("generated modules failed verification: %v", err)
+	}
+
+	script := `
+import { HTTPTransport, RPCError, TransportError, shop } from './index.js';
+
+const requests = [];
+let reply = { status: 200, body: '' };
+const transport = new HTTPTransport('/rpc', {
+  headers: { Authorization: 'Bearer t' },
+  fetch: async (url, init) => {
+    requests.push({ url, init });
+    return new Response(reply.body, { status: reply.status });
+  },
+});
+const client = new shop.OrdersClient(transport);
+
+reply.body = JSON.stringify({ jsonrpc: '2.0', id: '1', result: { status: 'ok', items: ['a'] } });
+const order = await client.find(7, { timeoutMs: 1500, idempotencyKey: 'k1' });
+if (order.items[0] !== 'a') throw new Error('unexpected result ' + JSON.stringify(order));
+const sent = JSON.parse(requests[0].init.body);
+if (sent.method !== 'Orders.find' || sent.params[0] !== 7 || !/^[0-9a-f-]{36}$/.test(sent.id)) {
+  throw new Error('unexpected request ' + requests[0].init.body);
+}
+const headers = requests[0].init.headers;
+if (headers['X-PulseRPC-Deadline'] !== '1500' || headers['Idempotency-Key'] !== 'k1' || headers.Authorization !== 'Bearer t') {
+  throw new Error('unexpected headers ' + JSON.stringify(headers));
+}
+
+reply.body = JSON.stringify({ jsonrpc: '2.0', id: '2', error: { code: 1001, message: 'no such order', data: { id: 7 } } });
+try {
+  await client.find(7);
+  throw new Error('expected an RPCError');
+} catch (err) {
+  if (!(err instanceof RPCError) || err instanceof TransportError || err.code !== 1001 || err.data.id !== 7) throw err;
+}
+
+reply = { status: 503, body: 'unavailable' };
+try {
+  await client.find(7);
+  throw new Error('expected a TransportError');
+} catch (err) {
+  if (!(err instanceof TransportError) || err.status !== 503 || !err.retryable) throw err;
+}
+console.log('ok');
+`
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"type": "module"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "run.js"), []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("node", "run.js")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil || strings.TrimSpace(string(out)) != "ok" {
+		t.Fatalf("client calls failed: %v\n%s", err, out)
+	}
+}
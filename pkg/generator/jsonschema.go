@@ -0,0 +1,260 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// The json-schema plugin describes the structs and enums of the IDL as JSON Schema
+// (draft 2020-12) documents, so consumers without a PulseRPC runtime can validate
+// payloads: <namespace>.schema.json for each namespace, whose $defs are keyed by
+// the type's name in its namespace and refer to the types of other namespaces
+// through their documents, and schema.json holding every type keyed by
+// "namespace.Name". Struct schemas do not forbid additional properties, since
+// PulseRPC servers ignore unknown fields; a struct that extends another lists the
+// parent's schema in allOf.
+
+// jsonSchemaDialect is the $schema of the documents the json-schema plugin writes
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchemaCombinedFile is the document holding the types of every namespace
+const jsonSchemaCombinedFile = "schema.json"
+
+// JSONSchemaDocument is a schema document written by the json-schema plugin
+type JSONSchemaDocument struct {
+	Schema string `json:"$schema"`
+	ID     string `json:"$id"`
+	Title  string `json:"title,omitempty"`
+	// Defs holds the schema of each type by name
+	Defs map[string]map[string]any `json:"$defs"`
+}
+
+// JSONSchema generates JSON Schema documents for the structs and enums of the IDL
+type JSONSchema struct {
+}
+
+// NewJSONSchema creates a new JSONSchema plugin instance
+func NewJSONSchema() *JSONSchema {
+	return &JSONSchema{}
+}
+
+// Name returns the plugin identifier
+func (p *JSONSchema) Name() string {
+	return "json-schema"
+}
+
+// RegisterFlags registers CLI flags for this plugin
+func (p *JSONSchema) RegisterFlags(fs *flag.FlagSet) {
+	fs.String("json-schema-base-uri", "", "Base URI the schema documents are published under (e.g., https://schemas.example.com/api/); their $id is relative when empty")
+}
+
+// Generate writes a schema document per namespace and the combined schema.json
+func (p *JSONSchema) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
+	outputDir := ""
+	if dirFlag := fs.Lookup("dir"); dirFlag != nil {
+		outputDir = dirFlag.Value.String()
+	}
+	baseURI := fs.Lookup("json-schema-base-uri").Value.String()
+	if baseURI != "" && !strings.HasSuffix(baseURI, "/") {
+		baseURI += "/"
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	docs := BuildJSONSchemas(idl, baseURI)
+	names := make([]string, 0, len(docs))
+	for name := range docs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(docs[name]); err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", name, err)
+		}
+		if err := writeGeneratedFile(filepath.Join(outputDir, name), buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// BuildJSONSchemas returns the schema documents of the IDL by file name:
+// <namespace>.schema.json for each namespace with structs or enums, and
+// schema.json. Their $id is the file name resolved against baseURI, which is
+// empty or ends in a slash.
+func BuildJSONSchemas(idl *parser.IDL, baseURI string) map[string]*JSONSchemaDocument {
+	b := &jsonSchemaBuilder{idl: idl, structs: make(map[string]*parser.Struct), namespaces: make(map[string]string)}
+	for _, s := range idl.Structs {
+		b.structs[s.Name] = s
+		b.namespaces[s.Name] = b.namespace(s.Namespace)
+	}
+	for _, e := range idl.Enums {
+		b.namespaces[e.Name] = b.namespace(e.Namespace)
+	}
+
+	combined := &JSONSchemaDocument{Schema: jsonSchemaDialect, ID: baseURI + jsonSchemaCombinedFile, Defs: make(map[string]map[string]any)}
+	docs := map[string]*JSONSchemaDocument{jsonSchemaCombinedFile: combined}
+	addDef := func(name, namespace string, schema func(combined bool) map[string]any) {
+		file := jsonSchemaFile(namespace)
+		if docs[file] == nil {
+			docs[file] = &JSONSchemaDocument{Schema: jsonSchemaDialect, ID: baseURI + file, Title: namespace, Defs: make(map[string]map[string]any)}
+		}
+		docs[file].Defs[localTypeName(name)] = schema(false)
+		combined.Defs[namespace+"."+localTypeName(name)] = schema(true)
+	}
+	for _, s := range idl.Structs {
+		s := s
+		addDef(s.Name, b.namespaces[s.Name], func(combined bool) map[string]any { return b.structSchema(s, combined) })
+	}
+	for _, e := range idl.Enums {
+		e := e
+		addDef(e.Name, b.namespaces[e.Name], func(bool) map[string]any { return enumSchema(e) })
+	}
+	return docs
+}
+
+// jsonSchemaBuilder turns the types of an IDL into schemas
+type jsonSchemaBuilder struct {
+	idl     *parser.IDL
+	structs map[string]*parser.Struct
+	// namespaces maps each struct and enum name to its namespace
+	namespaces map[string]string
+}
+
+// namespace returns the namespace a type declared in namespace belongs to; types
+// of files without a namespace belong to the root namespace
+func (b *jsonSchemaBuilder) namespace(namespace string) string {
+	if namespace == "" {
+		return b.idl.RootNamespace
+	}
+	return namespace
+}
+
+// jsonSchemaFile returns the name of the schema document of a namespace
+func jsonSchemaFile(namespace string) string {
+	return namespace + ".schema.json"
+}
+
+// localTypeName returns the name of a type in its namespace, without the
+// namespace prefix of types from imported files
+func localTypeName(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// ref returns a reference to the definition of a struct or enum, from the document
+// of namespace or, if combined, from schema.json
+func (b *jsonSchemaBuilder) ref(name, namespace string, combined bool) map[string]any {
+	target := b.namespaces[name]
+	switch {
+	case combined:
+		return map[string]any{"$ref": "#/$defs/" + target + "." + localTypeName(name)}
+	case target == namespace:
+		return map[string]any{"$ref": "#/$defs/" + localTypeName(name)}
+	default:
+		return map[string]any{"$ref": jsonSchemaFile(target) + "#/$defs/" + localTypeName(name)}
+	}
+}
+
+// structSchema returns the schema of a struct: an object with a property per
+// field, requiring those that are not [optional]
+func (b *jsonSchemaBuilder) structSchema(s *parser.Struct, combined bool) map[string]any {
+	namespace := b.namespaces[s.Name]
+	properties := make(map[string]any)
+	required := make([]string, 0)
+	for _, field := range s.Fields {
+		var schema map[string]any
+		if field.IsEncrypted() {
+			// The payload holds the FieldCipher's ciphertext, not the value
+			schema = map[string]any{"type": "string"}
+		} else {
+			schema = b.typeSchema(field.Type, namespace, combined)
+		}
+		if field.Optional {
+			schema = nullable(schema)
+		} else {
+			required = append(required, field.Name)
+		}
+		if field.Comment != "" {
+			schema["description"] = field.Comment
+		}
+		properties[field.Name] = schema
+	}
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if s.Extends != "" {
+		if _, ok := b.structs[s.Extends]; ok {
+			schema["allOf"] = []any{b.ref(s.Extends, namespace, combined)}
+		}
+	}
+	if s.Comment != "" {
+		schema["description"] = s.Comment
+	}
+	return schema
+}
+
+// enumSchema returns the schema of an enum: a string holding one of its values
+func enumSchema(e *parser.Enum) map[string]any {
+	values := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		values[i] = v.Name
+	}
+	schema := map[string]any{"type": "string", "enum": values}
+	if e.Comment != "" {
+		schema["description"] = e.Comment
+	}
+	return schema
+}
+
+// typeSchema returns the schema of a value of type t in the document of namespace
+func (b *jsonSchemaBuilder) typeSchema(t *parser.Type, namespace string, combined bool) map[string]any {
+	switch {
+	case t.IsArray():
+		return map[string]any{"type": "array", "items": b.typeSchema(t.Array, namespace, combined)}
+	case t.IsMap():
+		return map[string]any{"type": "object", "additionalProperties": b.typeSchema(t.MapValue, namespace, combined)}
+	case t.IsUserDefined():
+		if _, ok := b.namespaces[t.UserDefined]; ok {
+			return b.ref(t.UserDefined, namespace, combined)
+		}
+		// An unresolved name, such as an interface, holds any value
+		return map[string]any{}
+	}
+	switch t.BuiltIn {
+	case "int":
+		return map[string]any{"type": "integer"}
+	case "float":
+		return map[string]any{"type": "number"}
+	case "bool":
+		return map[string]any{"type": "boolean"}
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+// nullable returns schema extended to also allow null, as [optional] fields may be
+// sent as null
+func nullable(schema map[string]any) map[string]any {
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+		return schema
+	}
+	return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+}
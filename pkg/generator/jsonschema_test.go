@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestJSONSchemaGenerated(t *testing.T) {
+	srcDir := t.TempDir()
+	common := "namespace common\n\nstruct Entity {\n  id string\n}\n\nenum Currency {\n  usd\n  eur\n}\n"
+	if err := os.WriteFile(filepath.Join(srcDir, "common.pulse"), []byte(common), 0644); err != nil {
+		t.Fatal(err)
+	}
+	main := `import "common.pulse"
+namespace shop
+
+// A priced item
+struct Item extends common.Entity {
+  price    float
+  currency common.Currency [optional]
+  tags     []string
+  attrs    map[string]Attr [optional]
+}
+
+struct Attr {
+  value int
+}
+`
+	idl, err := parser.ParseIDL(filepath.Join(srcDir, "shop.pulse"), main)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+
+	outDir := t.TempDir()
+	plugin := NewJSONSchema()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("dir", outDir, "output dir")
+	plugin.RegisterFlags(fs)
+	if err := fs.Set("json-schema-base-uri", "https://schemas.example.com/api"); err != nil {
+		t.Fatal(err)
+	}
+	if err := plugin.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	read := func(name string) map[string]any {
+		content, err := os.ReadFile(filepath.Join(outDir, name))
+		if err != nil {
+			t.Fatalf("expected %s: %v", name, err)
+		}
+		var doc map[string]any
+		if err := json.Unmarshal(content, &doc); err != nil {
+			t.Fatalf("%s is not JSON: %v", name, err)
+		}
+		return doc
+	}
+	shop := read("shop.schema.json")
+	if shop["$id"] != "https://schemas.example.com/api/shop.schema.json" || shop["$schema"] != jsonSchemaDialect {
+		t.Errorf("unexpected $id or $schema: %v, %v", shop["$id"], shop["$schema"])
+	}
+	want := map[string]any{
+		"type":        "object",
+		"description": "A priced item",
+		"allOf":       []any{map[string]any{"$ref": "common.schema.json#/$defs/Entity"}},
+		"required":    []any{"price", "tags"},
+		"properties": map[string]any{
+			"price":    map[string]any{"type": "number"},
+			"currency": map[string]any{"anyOf": []any{map[string]any{"$ref": "common.schema.json#/$defs/Currency"}, map[string]any{"type": "null"}}},
+			"tags":     map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"attrs":    map[string]any{"type": []any{"object", "null"}, "additionalProperties": map[string]any{"$ref": "#/$defs/Attr"}},
+		},
+	}
+	if got := shop["$defs"].(map[string]any)["Item"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected Item schema:\n%v\nwant:\n%v", got, want)
+	}
+
+	common2 := read("common.schema.json")["$defs"].(map[string]any)
+	if got := common2["Currency"]; !reflect.DeepEqual(got, map[string]any{"type": "string", "enum": []any{"usd", "eur"}}) {
+		t.Errorf("unexpected Currency schema: %v", got)
+	}
+
+	// The combined schema refers to every type within itself
+	combined := read("schema.json")["$defs"].(map[string]any)
+	for _, name := range []string{"shop.Item", "shop.Attr", "common.Entity", "common.Currency"} {
+		if combined[name] == nil {
+			t.Errorf("schema.json is missing %s", name)
+		}
+	}
+	item := combined["shop.Item"].(map[string]any)
+	if got := item["allOf"]; !reflect.DeepEqual(got, []any{map[string]any{"$ref": "#/$defs/common.Entity"}}) {
+		t.Errorf("unexpected combined allOf: %v", got)
+	}
+}
@@ -0,0 +1,175 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Legacy encodings: a method annotated [accepts="form"], [accepts="xml"] or both
+// can also be called by POSTing an application/x-www-form-urlencoded or XML body
+// to /<Interface>/<method>, for partners that cannot send JSON. The Go and Python
+// servers bind each form field, or each child element of the XML root, to the
+// parameter of that name the way the GET bridge binds query parameters, then
+// dispatch the call through the normal JSON-RPC path, so validation, hooks and
+// deduplication all apply. The response is always the JSON-RPC response
+// envelope, with a non-2xx status for errors. XML bodies with a document type
+// declaration are rejected. The TypeScript, C# and Java servers do not serve
+// legacy encodings.
+
+// legacyRoute is a method with an [accepts] annotation
+type legacyRoute struct {
+	restRoute
+	Accepts []string
+}
+
+// collectLegacyRoutes returns the methods with an [accepts] annotation in declaration order
+func collectLegacyRoutes(interfaces []*parser.Interface) []legacyRoute {
+	var routes []legacyRoute
+	for _, iface := range interfaces {
+		for _, method := range iface.Methods {
+			if accepts := method.Accepts(); len(accepts) > 0 {
+				routes = append(routes, legacyRoute{restRoute: restRoute{Interface: iface, Method: method}, Accepts: accepts})
+			}
+		}
+	}
+	return routes
+}
+
+// usesLegacyEncodings reports whether any method has an [accepts] annotation
+func usesLegacyEncodings(interfaces []*parser.Interface) bool {
+	return len(collectLegacyRoutes(interfaces)) > 0
+}
+
+// legacyRouteView is the view model of a method with an [accepts] annotation
+type legacyRouteView struct {
+	restRouteView
+	Accepts []string
+}
+
+// newLegacyRouteViews returns the views of the methods of interfaces with an [accepts]
+// annotation. typeDict writes a type definition as a literal of the target language.
+func newLegacyRouteViews(interfaces []*parser.Interface, typeDict func(*strings.Builder, *parser.Type)) []legacyRouteView {
+	var views []legacyRouteView
+	for _, route := range collectLegacyRoutes(interfaces) {
+		rv := legacyRouteView{restRouteView: restRouteView{Path: route.Path(), RPCMethod: route.RPCMethod()}, Accepts: route.Accepts}
+		for _, param := range route.Method.Parameters {
+			rv.Params = append(rv.Params, restParamView{
+				Name:     param.Name,
+				Type:     capture(func(sb *strings.Builder) { typeDict(sb, param.Type) }),
+				Optional: param.Optional,
+			})
+		}
+		views = append(views, rv)
+	}
+	return views
+}
+
+// writeLegacyBridgePy writes the LEGACY_ROUTES table and the functions that decode form
+// and XML encoded calls on the Python server
+func writeLegacyBridgePy(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("# POST paths (/<Interface>/<method>) of methods that also accept form or XML encoded calls\n")
+	sb.WriteString("LEGACY_ROUTES = {\n")
+	for _, route := range collectLegacyRoutes(interfaces) {
+		fmt.Fprintf(sb, "    '%s': {\n", route.Path())
+		fmt.Fprintf(sb, "        'method': '%s',\n", route.RPCMethod())
+		sb.WriteString("        'accepts': frozenset([")
+		for i, encoding := range route.Accepts {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(sb, "'%s'", encoding)
+		}
+		sb.WriteString("]),\n")
+		sb.WriteString("        'params': [\n")
+		for _, param := range route.Method.Parameters {
+			fmt.Fprintf(sb, "            {'name': '%s', 'type': ", param.Name)
+			writeTypeDict(sb, param.Type)
+			if param.Optional {
+				sb.WriteString(", 'optional': True")
+			}
+			sb.WriteString("},\n")
+		}
+		sb.WriteString("        ],\n")
+		sb.WriteString("    },\n")
+	}
+	sb.WriteString("}\n\n\n")
+
+	sb.WriteString(`def _legacy_encoding(header: Optional[str]) -> Optional[str]:
+    """Return the legacy encoding of a request Content-Type, 'form' or 'xml', or None for any other"""
+    if not header:
+        return None
+    media_type = header.split(';', 1)[0].strip().lower()
+    if media_type == 'application/x-www-form-urlencoded':
+        return 'form'
+    if media_type in ('application/xml', 'text/xml') or media_type.endswith('+xml'):
+        return 'xml'
+    return None
+
+
+def _decode_xml_params(body: bytes) -> Dict[str, List[str]]:
+    """Read the parameters of an XML encoded call. The root element, whatever its name, holds one
+    element of text per parameter, repeated for arrays. Namespace prefixes are ignored and
+    document type declarations are rejected."""
+    if b'<!DOCTYPE' in body.upper():
+        raise ValueError("document type declarations are not allowed")
+    root = ElementTree.fromstring(body)
+    values: Dict[str, List[str]] = {}
+    for child in root:
+        name = child.tag.rsplit('}', 1)[-1]
+        if len(child) > 0:
+            raise ValueError(f"element <{child[0].tag.rsplit('}', 1)[-1]}> in <{name}> must hold text only")
+        values.setdefault(name, []).append(child.text or '')
+    return values
+
+
+`)
+}
+
+// writeLegacyHandlerPy writes _handle_legacy of the Python server, which calls the server
+// method named by dispatch
+func writeLegacyHandlerPy(sb *strings.Builder, dispatch string) {
+	sb.WriteString(`    def _handle_legacy(self, route: Dict[str, Any], encoding: str, headers: Any, body: bytes) -> Tuple[int, Dict[str, str], bytes]:
+        """Serve a form or XML encoded call of an [accepts] method. Each form field, or child element
+        of the XML root, is one parameter, bound like a GET query parameter. The response body is
+        the JSON-RPC response envelope; errors use a non-2xx status."""
+        json_headers = {'Content-Type': 'application/json'}
+        if encoding not in route['accepts']:
+            problem = f"{route['method']} does not accept {encoding} encoded calls"
+            return 415, json_headers, json.dumps(self._error_response(None, -32600, "Invalid Request", problem)).encode('utf-8')
+        rejection = self._verify(headers, body)
+        if rejection is not None:
+            return 401, json_headers, rejection
+
+        response = None
+        try:
+            if encoding == 'xml':
+                values = _decode_xml_params(body)
+            else:
+                values = parse_qs(body.decode('utf-8'), keep_blank_values=True)
+        except (ValueError, ElementTree.ParseError) as e:
+            response = self._error_response(None, -32700, "Parse error", f"Invalid {encoding} body: {e}")
+        else:
+            params = []
+            for param_def in route['params']:
+                if param_def.get('optional') and not values.get(param_def['name']):
+                    params.append(None)
+                    continue
+                try:
+                    params.append(_bind_query_param(values.get(param_def['name'], []), param_def['type']))
+                except ValueError as e:
+                    response = self._error_response(None, -32602, "Invalid params", f"Field {param_def['name']}: {e}")
+                    break
+            if response is None:
+`)
+	fmt.Fprintf(sb, "                response = self.%s({'jsonrpc': '2.0', 'method': route['method'], 'params': params, 'id': None})\n", dispatch)
+	sb.WriteString(`        response, encoded = self._encode_response(route['method'], len(body), response)
+
+        status = 200
+        if 'error' in response:
+            status = _rest_error_status(response['error']['code'])
+        return status, json_headers, encoded
+
+`)
+}
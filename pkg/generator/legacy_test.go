@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestLegacyBridgeGenerated(t *testing.T) {
+	tests := []struct {
+		name    string
+		idl     string
+		enabled bool
+	}{
+		{"accepts methods", "interface Orders {\n  submit(sku string, tags []string) bool [accepts=\"form,xml\"]\n  cancel(id string) bool\n}", true},
+		{"no accepts methods", "interface Orders {\n  cancel(id string) bool\n}", false},
+	}
+	plugins := []struct {
+		plugin Plugin
+		file   string
+		want   []string
+	}{
+		{
+			plugin: NewGoClientServer(),
+			file:   "server.go",
+			want: []string{
+				"\t\"encoding/xml\"\n",
+				"\t\"/Orders/submit\": {\n\t\tmethod:  \"Orders.submit\",\n\t\taccepts: map[string]bool{\"form\": true, \"xml\": true},\n",
+				"if route, ok := legacyRoutes[r.URL.Path]; ok {",
+				"func decodeXMLParams(body []byte) (map[string][]string, error) {",
+			},
+		},
+		{
+			plugin: NewPythonClientServer(),
+			file:   "server.py",
+			want: []string{
+				"from xml.etree import ElementTree\n",
+				"    '/Orders/submit': {\n        'method': 'Orders.submit',\n        'accepts': frozenset(['form', 'xml']),\n",
+				"return self._handle_legacy(route, encoding, headers, body)",
+				"def _decode_xml_params(body: bytes) -> Dict[str, List[str]]:",
+			},
+		},
+	}
+	for _, tt := range tests {
+		idl, err := parser.ParseIDL("shop.pulse", tt.idl)
+		if err != nil {
+			t.Fatalf("%s: ParseIDL failed: %v", tt.name, err)
+		}
+		for _, p := range plugins {
+			tmpDir := t.TempDir()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.String("dir", "", "output dir")
+			p.plugin.RegisterFlags(fs)
+			if err := fs.Set("dir", tmpDir); err != nil {
+				t.Fatalf("failed to set dir flag: %v", err)
+			}
+			if err := p.plugin.Generate(idl, fs); err != nil {
+				t.Fatalf("%s: %s: Generate failed: %v", tt.name, p.plugin.Name(), err)
+			}
+			content, err := os.ReadFile(filepath.Join(tmpDir, p.file))
+			if err != nil {
+				t.Fatalf("%s: expected %s: %v", p.plugin.Name(), p.file, err)
+			}
+			for _, want := range p.want {
+				if got := strings.Contains(string(content), want); got != tt.enabled {
+					t.Errorf("%s: %s: %s contains %q = %v, want %v", tt.name, p.plugin.Name(), p.file, want, got, tt.enabled)
+				}
+			}
+		}
+	}
+}
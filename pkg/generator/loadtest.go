@@ -0,0 +1,143 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// LoadTest generates load testing scripts that call every method of the IDL with
+// IDL-valid params: a k6 script, or vegeta targets and a script that runs them.
+type LoadTest struct {
+}
+
+// NewLoadTest creates a new LoadTest plugin instance
+func NewLoadTest() *LoadTest {
+	return &LoadTest{}
+}
+
+// Name returns the plugin identifier
+func (p *LoadTest) Name() string {
+	return "load-test"
+}
+
+// RegisterFlags registers CLI flags for this plugin
+func (p *LoadTest) RegisterFlags(fs *flag.FlagSet) {
+	fs.String("load-test-tool", "k6", "Load testing tool to generate scripts for: 'k6' or 'vegeta'")
+	fs.String("load-test-url", "http://localhost:8080", "Default URL of the server under test")
+	fs.Int("load-test-rate", 10, "Default number of requests per second for each method")
+	fs.String("load-test-duration", "30s", "Default duration of the load test (e.g., 30s, 5m)")
+}
+
+// loadTestView is the view model for the load testing templates
+type loadTestView struct {
+	URL      string
+	Rate     int
+	Duration string
+	Requests []loadTestRequest
+}
+
+// loadTestRequest is the request sent for one method
+type loadTestRequest struct {
+	// Scenario identifies the method in k6 scenario names and environment variables
+	Scenario string
+	Method   string
+	JSON     string
+}
+
+var nonScenarioChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Generate writes loadtest.js for k6, or targets.json and loadtest.sh for vegeta
+func (p *LoadTest) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
+	outputDir := ""
+	if dirFlag := fs.Lookup("dir"); dirFlag != nil {
+		outputDir = dirFlag.Value.String()
+	}
+	tool := fs.Lookup("load-test-tool").Value.String()
+	if tool != "k6" && tool != "vegeta" {
+		return fmt.Errorf("invalid load-test-tool value: %s (must be 'k6' or 'vegeta')", tool)
+	}
+	rate, err := strconv.Atoi(fs.Lookup("load-test-rate").Value.String())
+	if err != nil || rate < 1 {
+		return fmt.Errorf("invalid load-test-rate value: %s (must be a positive number of requests per second)", fs.Lookup("load-test-rate").Value.String())
+	}
+
+	view := loadTestView{
+		URL:      fs.Lookup("load-test-url").Value.String(),
+		Rate:     rate,
+		Duration: fs.Lookup("load-test-duration").Value.String(),
+	}
+	for _, iface := range buildHarnessInterfaces(idl, func(s string) string { return s }) {
+		for _, call := range iface.Calls {
+			view.Requests = append(view.Requests, loadTestRequest{
+				Scenario: nonScenarioChars.ReplaceAllString(iface.Name+"_"+call.Method, "_"),
+				Method:   iface.Name + "." + call.Method,
+				JSON:     call.JSON,
+			})
+		}
+	}
+	if len(view.Requests) == 0 {
+		return fmt.Errorf("the IDL has no interface methods to load test")
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if tool == "k6" {
+		script := embeddedTemplates.renderString("loadtest/k6.js.tmpl", view)
+		if err := writeGeneratedFile(filepath.Join(outputDir, "loadtest.js"), []byte(script)); err != nil {
+			return fmt.Errorf("failed to write loadtest.js: %w", err)
+		}
+		return nil
+	}
+
+	targets, err := vegetaTargets(view)
+	if err != nil {
+		return err
+	}
+	if err := writeGeneratedFile(filepath.Join(outputDir, "targets.json"), targets); err != nil {
+		return fmt.Errorf("failed to write targets.json: %w", err)
+	}
+	scriptPath := filepath.Join(outputDir, "loadtest.sh")
+	if err := writeGeneratedFile(scriptPath, []byte(embeddedTemplates.renderString("loadtest/vegeta.sh.tmpl", view))); err != nil {
+		return fmt.Errorf("failed to write loadtest.sh: %w", err)
+	}
+	return os.Chmod(scriptPath, 0755)
+}
+
+// vegetaTarget is one line of vegeta's JSON target format. Body is base64 encoded
+// by encoding/json, as vegeta expects.
+type vegetaTarget struct {
+	Method string              `json:"method"`
+	URL    string              `json:"url"`
+	Body   []byte              `json:"body"`
+	Header map[string][]string `json:"header"`
+}
+
+// vegetaTargets returns the requests in vegeta's JSON target format, one per line.
+// HTML escaping is off so the URL appears verbatim for loadtest.sh to replace.
+func vegetaTargets(view loadTestView) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	for _, req := range view.Requests {
+		err := enc.Encode(vegetaTarget{
+			Method: "POST",
+			URL:    view.URL,
+			Body:   []byte(req.JSON),
+			Header: map[string][]string{"Content-Type": {"application/json"}},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode vegeta target for %s: %w", req.Method, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+var loadTestIDL = &parser.IDL{
+	RootNamespace: "catalog",
+	Interfaces: []*parser.Interface{
+		{
+			Name: "Catalog",
+			Methods: []*parser.Method{
+				{
+					Name:       "find_product",
+					Parameters: []*parser.Parameter{{Name: "id", Type: &parser.Type{BuiltIn: "string"}}},
+					ReturnType: &parser.Type{BuiltIn: "string"},
+				},
+				{
+					Name:       "ping",
+					ReturnType: &parser.Type{BuiltIn: "bool"},
+				},
+			},
+		},
+	},
+}
+
+func TestLoadTestVegeta(t *testing.T) {
+	tmpDir := t.TempDir()
+	p := NewLoadTest()
+	fs := newTestFlagSet(t, p, tmpDir, "-load-test-tool=vegeta", "-load-test-url=http://api.test/rpc?a=1&b=2", "-load-test-rate=25")
+	if err := p.Generate(loadTestIDL, fs); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+
+	f, err := os.Open(filepath.Join(tmpDir, "targets.json"))
+	if err != nil {
+		t.Fatalf("failed to open targets.json: %v", err)
+	}
+	defer f.Close()
+	var methods []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if !strings.Contains(scanner.Text(), `"url":"http://api.test/rpc?a=1&b=2"`) {
+			t.Errorf("target URL is not verbatim: %s", scanner.Text())
+		}
+		var target vegetaTarget
+		if err := json.Unmarshal(scanner.Bytes(), &target); err != nil {
+			t.Fatalf("invalid target %s: %v", scanner.Text(), err)
+		}
+		var request map[string]interface{}
+		if err := json.Unmarshal(target.Body, &request); err != nil {
+			t.Fatalf("invalid target body %s: %v", target.Body, err)
+		}
+		methods = append(methods, request["method"].(string))
+	}
+	if got := strings.Join(methods, ","); got != "Catalog.find_product,Catalog.ping" {
+		t.Errorf("targets cover %s, want every method", got)
+	}
+
+	script, err := os.ReadFile(filepath.Join(tmpDir, "loadtest.sh"))
+	if err != nil {
+		t.Fatalf("failed to read loadtest.sh: %v", err)
+	}
+	for _, want := range []string{`RATE="${RATE:-25}"`, "METHODS=2", `-rate="$((RATE * METHODS))/1s"`} {
+		if !strings.Contains(string(script), want) {
+			t.Errorf("loadtest.sh missing %q", want)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(tmpDir, "loadtest.sh")); err != nil || info.Mode()&0100 == 0 {
+		t.Errorf("loadtest.sh is not executable")
+	}
+}
+
+func TestLoadTestInvalidFlags(t *testing.T) {
+	for name, arg := range map[string]string{
+		"tool": "-load-test-tool=jmeter",
+		"rate": "-load-test-rate=0",
+	} {
+		p := NewLoadTest()
+		fs := newTestFlagSet(t, p, t.TempDir(), arg)
+		if err := p.Generate(loadTestIDL, fs); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Method tables: the Go, Python and TypeScript code holds the parameters and
+// return type of every method in one table, keyed by interface and method name,
+// written once per IDL to its own file (methods.go, methods.py, methods.ts). The
+// server looks calls up in it and each client class validates its arguments
+// against its interface's entry, so a large IDL's definitions are no longer
+// inlined in the server's dispatch function and again in every client class. C#
+// clients don't validate arguments, so the C# table is a static property of IdlData,
+// which the server dispatches with and clients log calls with. Java servers
+// deserialize parameters into their declared types instead.
+
+// generateMethodTableGo generates methods.go with the methodDefs table
+func generateMethodTableGo(packageName string, interfaces []*parser.Interface) string {
+	var sb strings.Builder
+
+	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
+	fmt.Fprintf(&sb, "package %s\n\n", packageName)
+	sb.WriteString("// methodDefs holds the parameters and return type of every method by interface and\n")
+	sb.WriteString("// method name. The server validates calls against it, and clients their arguments.\n")
+	sb.WriteString("var methodDefs = map[string]map[string]map[string]interface{}{\n")
+	for _, iface := range interfaces {
+		fmt.Fprintf(&sb, "	\"%s\": {\n", iface.Name)
+		for _, method := range iface.Methods {
+			fmt.Fprintf(&sb, "		\"%s\": {\n", method.Name)
+			sb.WriteString("			\"parameters\": []interface{}{\n")
+			for _, param := range method.Parameters {
+				sb.WriteString("				map[string]interface{}{\n")
+				fmt.Fprintf(&sb, "					\"name\": \"%s\",\n", param.Name)
+				sb.WriteString("					\"type\": ")
+				writeTypeDictGo(&sb, param.Type)
+				sb.WriteString(",\n")
+				writeParamOptionsGo(&sb, "					", param)
+				sb.WriteString("				},\n")
+			}
+			sb.WriteString("			},\n")
+			sb.WriteString("			\"returnType\": ")
+			writeTypeDictGo(&sb, method.ReturnType)
+			sb.WriteString(",\n")
+			fmt.Fprintf(&sb, "			\"returnOptional\": %t,\n", method.ReturnOptional)
+			if method.IsAsync() {
+				sb.WriteString("			\"async\": true,\n")
+			}
+			if method.IsChunked() {
+				sb.WriteString("			\"chunked\": true,\n")
+			}
+			sb.WriteString("		},\n")
+		}
+		sb.WriteString("	},\n")
+	}
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// generateMethodTablePy generates methods.py with the METHOD_DEFS table
+func generateMethodTablePy(interfaces []*parser.Interface) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Generated by pulserpc - do not edit\n\n")
+	sb.WriteString("# Parameters and return type of every method by interface and method name. The\n")
+	sb.WriteString("# server validates calls against them, and clients their arguments.\n")
+	sb.WriteString("METHOD_DEFS = {\n")
+	for _, iface := range interfaces {
+		fmt.Fprintf(&sb, "    '%s': {\n", iface.Name)
+		for _, method := range iface.Methods {
+			fmt.Fprintf(&sb, "        '%s': {\n", method.Name)
+			sb.WriteString("            'parameters': [\n")
+			for _, param := range method.Parameters {
+				sb.WriteString("                {\n")
+				fmt.Fprintf(&sb, "                    'name': '%s',\n", param.Name)
+				sb.WriteString("                    'type': ")
+				writeTypeDict(&sb, param.Type)
+				sb.WriteString(",\n")
+				writeParamOptionsPy(&sb, "                    ", param)
+				sb.WriteString("                },\n")
+			}
+			sb.WriteString("            ],\n")
+			sb.WriteString("            'returnType': ")
+			writeTypeDict(&sb, method.ReturnType)
+			sb.WriteString(",\n")
+			if method.ReturnOptional {
+				sb.WriteString("            'returnOptional': True,\n")
+			} else {
+				sb.WriteString("            'returnOptional': False,\n")
+			}
+			if method.IsAsync() {
+				sb.WriteString("            'async': True,\n")
+			}
+			if method.IsChunked() {
+				sb.WriteString("            'chunked': True,\n")
+			}
+			sb.WriteString("        },\n")
+		}
+		sb.WriteString("    },\n")
+	}
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// generateMethodTableTs generates methods.ts with the METHOD_DEFS table
+func generateMethodTableTs(interfaces []*parser.Interface, packagePrefix string) string {
+	var sb strings.Builder
+
+	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
+	sb.WriteString("// Parameters and return type of every method by interface and method name. The\n")
+	sb.WriteString("// server validates calls against them, and clients their arguments.\n")
+	fmt.Fprintf(&sb, "export const %s: any = {\n", applyPackagePrefix("METHOD_DEFS", packagePrefix))
+	for _, iface := range interfaces {
+		fmt.Fprintf(&sb, "  '%s': {\n", iface.Name)
+		for _, method := range iface.Methods {
+			fmt.Fprintf(&sb, "    '%s': {\n", method.Name)
+			sb.WriteString("      parameters: [\n")
+			for _, param := range method.Parameters {
+				sb.WriteString("        {\n")
+				fmt.Fprintf(&sb, "          name: '%s',\n", param.Name)
+				sb.WriteString("          type: ")
+				writeTypeDictTs(&sb, param.Type)
+				sb.WriteString(",\n")
+				writeParamOptionsTs(&sb, "          ", param)
+				sb.WriteString("        },\n")
+			}
+			sb.WriteString("      ],\n")
+			sb.WriteString("      returnType: ")
+			writeTypeDictTs(&sb, method.ReturnType)
+			sb.WriteString(",\n")
+			fmt.Fprintf(&sb, "      returnOptional: %t,\n", method.ReturnOptional)
+			if method.IsAsync() {
+				sb.WriteString("      async: true,\n")
+			}
+			if method.IsChunked() {
+				sb.WriteString("      chunked: true,\n")
+			}
+			sb.WriteString("    },\n")
+		}
+		sb.WriteString("  },\n")
+	}
+	sb.WriteString("};\n")
+
+	return sb.String()
+}
+
+// writeMethodTableCs writes the METHOD_DEFS table of the C# IdlData class, built on first use
+func writeMethodTableCs(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("        // Parameters and return type of every method by interface and method name, built\n")
+	sb.WriteString("        // on first use. The server dispatches calls with it and clients log them.\n")
+	sb.WriteString("        public static Dictionary<string, Dictionary<string, Dictionary<string, object>>> METHOD_DEFS => _methodDefs.Value;\n\n")
+	sb.WriteString("        private static readonly System.Lazy<Dictionary<string, Dictionary<string, Dictionary<string, object>>>> _methodDefs = new System.Lazy<Dictionary<string, Dictionary<string, Dictionary<string, object>>>>(() => new Dictionary<string, Dictionary<string, Dictionary<string, object>>>\n")
+	sb.WriteString("        {\n")
+	for _, iface := range interfaces {
+		fmt.Fprintf(sb, "            { \"%s\", new Dictionary<string, Dictionary<string, object>>\n", iface.Name)
+		sb.WriteString("            {\n")
+		for _, method := range iface.Methods {
+			fmt.Fprintf(sb, "                { \"%s\", new Dictionary<string, object>\n", method.Name)
+			sb.WriteString("                {\n")
+			sb.WriteString("                    { \"parameters\", new List<Dictionary<string, object>>\n")
+			sb.WriteString("                    {\n")
+			for _, param := range method.Parameters {
+				sb.WriteString("                        new Dictionary<string, object>\n")
+				sb.WriteString("                        {\n")
+				fmt.Fprintf(sb, "                            { \"name\", \"%s\" },\n", param.Name)
+				sb.WriteString("                            { \"type\", ")
+				writeTypeDictCs(sb, param.Type)
+				sb.WriteString(" },\n")
+				writeParamOptionsCs(sb, "                        ", param)
+				sb.WriteString("                        },\n")
+			}
+			sb.WriteString("                    }},\n")
+			sb.WriteString("                    { \"returnType\", ")
+			writeTypeDictCs(sb, method.ReturnType)
+			sb.WriteString(" },\n")
+			fmt.Fprintf(sb, "                    { \"returnOptional\", %t },\n", method.ReturnOptional)
+			if method.IsAsync() {
+				sb.WriteString("                    { \"async\", true },\n")
+			}
+			sb.WriteString("                }},\n")
+		}
+		sb.WriteString("            }},\n")
+	}
+	sb.WriteString("        });\n")
+}
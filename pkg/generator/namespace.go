@@ -1,16 +1,19 @@
 package generator
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/coopernurse/pulserpc/pkg/parser"
 )
 
-// NamespaceTypes groups all types (structs, enums, interfaces) for a single namespace
+// NamespaceTypes groups all types (structs, enums, interfaces, typedefs) for a single namespace
 type NamespaceTypes struct {
 	Structs    []*parser.Struct
 	Enums      []*parser.Enum
 	Interfaces []*parser.Interface
+	Typedefs   []*parser.Typedef
 }
 
 // GroupTypesByNamespace groups all types in the IDL by their namespace
@@ -56,6 +59,19 @@ func GroupTypesByNamespace(idl *parser.IDL) map[string]*NamespaceTypes {
 		namespaceMap[ns].Interfaces = append(namespaceMap[ns].Interfaces, i)
 	}
 
+	// Group typedefs by namespace
+	for _, td := range idl.Typedefs {
+		ns := GetNamespaceFromType(td.Name, td.Namespace)
+		if namespaceMap[ns] == nil {
+			namespaceMap[ns] = &NamespaceTypes{
+				Structs:    make([]*parser.Struct, 0),
+				Enums:      make([]*parser.Enum, 0),
+				Interfaces: make([]*parser.Interface, 0),
+			}
+		}
+		namespaceMap[ns].Typedefs = append(namespaceMap[ns].Typedefs, td)
+	}
+
 	return namespaceMap
 }
 
@@ -90,3 +106,63 @@ func GetBaseName(typeName string) string {
 	return typeName
 }
 
+// BaseNameCollision describes user-defined types from different namespaces that
+// share the same base name once the namespace prefix is stripped
+type BaseNameCollision struct {
+	BaseName       string
+	QualifiedNames []string
+}
+
+// FindBaseNameCollisions returns every base name that is defined by more than one
+// struct, enum, interface, or typedef in the IDL. Results are sorted by base name so error
+// messages are stable across runs.
+func FindBaseNameCollisions(idl *parser.IDL) []BaseNameCollision {
+	byBaseName := make(map[string][]string)
+	add := func(name, namespace string) {
+		qualified := name
+		if !strings.Contains(name, ".") && namespace != "" {
+			qualified = namespace + "." + name
+		}
+		baseName := GetBaseName(name)
+		byBaseName[baseName] = append(byBaseName[baseName], qualified)
+	}
+	for _, s := range idl.Structs {
+		add(s.Name, s.Namespace)
+	}
+	for _, e := range idl.Enums {
+		add(e.Name, e.Namespace)
+	}
+	for _, i := range idl.Interfaces {
+		add(i.Name, i.Namespace)
+	}
+	for _, td := range idl.Typedefs {
+		add(td.Name, td.Namespace)
+	}
+
+	collisions := make([]BaseNameCollision, 0)
+	for baseName, names := range byBaseName {
+		if len(names) > 1 {
+			sort.Strings(names)
+			collisions = append(collisions, BaseNameCollision{BaseName: baseName, QualifiedNames: names})
+		}
+	}
+	sort.Slice(collisions, func(i, j int) bool {
+		return collisions[i].BaseName < collisions[j].BaseName
+	})
+	return collisions
+}
+
+// CheckBaseNameCollisions returns an error if any types collide on their base name.
+// Generators that strip namespaces from type names (Go, C#) call this before writing
+// output so that colliding classes are reported instead of silently generated.
+func CheckBaseNameCollisions(idl *parser.IDL) error {
+	collisions := FindBaseNameCollisions(idl)
+	if len(collisions) == 0 {
+		return nil
+	}
+	msgs := make([]string, 0, len(collisions))
+	for _, c := range collisions {
+		msgs = append(msgs, fmt.Sprintf("%s (%s)", c.BaseName, strings.Join(c.QualifiedNames, ", ")))
+	}
+	return fmt.Errorf("type names collide across namespaces: %s; rename one of the types so base names are unique", strings.Join(msgs, "; "))
+}
@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestFindBaseNameCollisions(t *testing.T) {
+	idl := &parser.IDL{
+		Structs: []*parser.Struct{
+			{Name: "User", Namespace: "auth"},
+			{Name: "billing.User", Namespace: "billing"},
+			{Name: "Invoice", Namespace: "auth"},
+		},
+		Enums: []*parser.Enum{
+			{Name: "billing.Status", Namespace: "billing"},
+		},
+		Interfaces: []*parser.Interface{
+			{Name: "Status", Namespace: "auth"},
+		},
+	}
+
+	collisions := FindBaseNameCollisions(idl)
+	if len(collisions) != 2 {
+		t.Fatalf("expected 2 collisions, got %d: %+v", len(collisions), collisions)
+	}
+	if collisions[0].BaseName != "Status" || collisions[1].BaseName != "User" {
+		t.Fatalf("unexpected collision order: %+v", collisions)
+	}
+	if got := strings.Join(collisions[1].QualifiedNames, ","); got != "auth.User,billing.User" {
+		t.Fatalf("unexpected qualified names: %s", got)
+	}
+}
+
+func TestGeneratorsRejectBaseNameCollisions(t *testing.T) {
+	idl := &parser.IDL{
+		Structs: []*parser.Struct{
+			{Name: "User", Namespace: "auth"},
+			{Name: "billing.User", Namespace: "billing"},
+		},
+	}
+
+	for _, p := range []Plugin{NewGoClientServer(), NewCSharpClientServer()} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		p.RegisterFlags(fs)
+		if err := fs.Set("dir", t.TempDir()); err != nil {
+			t.Fatalf("failed to set dir flag: %v", err)
+		}
+		err := p.Generate(idl, fs)
+		if err == nil || !strings.Contains(err.Error(), "User (auth.User, billing.User)") {
+			t.Fatalf("%s: expected collision error, got %v", p.Name(), err)
+		}
+	}
+}
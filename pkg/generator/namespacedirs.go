@@ -0,0 +1,137 @@
+package generator
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// -namespace-dirs names a JSON file mapping namespaces to the directories the
+// Python, TypeScript and C# plugins write their modules to, so one run can put
+// shared namespaces such as inc into a common library checkout and the service's
+// own namespaces into the service repo:
+//
+//	{"inc": "../shared-lib/gen", "billing": "gen/billing"}
+//
+// Relative directories are relative to the file's own directory. Namespaces the
+// file does not name are written to -base-dir as before. The server, client and
+// runtime stay in -dir and import mapped modules by relative path (TypeScript)
+// or from sys.path (Python); C# files are compiled by whichever project includes
+// their directory.
+
+// namespaceDirs maps each namespace to the directory its module is written to
+type namespaceDirs struct {
+	base string
+	dirs map[string]string
+}
+
+// registerNamespaceDirsFlag registers -namespace-dirs unless another plugin has
+func registerNamespaceDirsFlag(fs *flag.FlagSet) {
+	if fs.Lookup("namespace-dirs") == nil {
+		fs.String("namespace-dirs", "", "JSON file mapping IDL namespaces to the directories their modules are written to (Python, TypeScript, C#), e.g. {\"inc\": \"../shared-lib/gen\"}; relative paths are relative to the file")
+	}
+}
+
+// loadNamespaceDirs reads the file -namespace-dirs names. Namespaces it does not
+// map are written to baseDir. Every namespace it maps must be in namespaceMap, so
+// a typo fails generation instead of writing to the wrong place.
+func loadNamespaceDirs(fs *flag.FlagSet, baseDir string, namespaceMap map[string]*NamespaceTypes) (namespaceDirs, error) {
+	d := namespaceDirs{base: baseDir, dirs: map[string]string{}}
+	f := fs.Lookup("namespace-dirs")
+	if f == nil || f.Value.String() == "" {
+		return d, nil
+	}
+	file := f.Value.String()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return d, fmt.Errorf("failed to read namespace-dirs file: %w", err)
+	}
+	var mapping map[string]string
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return d, fmt.Errorf("invalid namespace-dirs file %s: %w (want a JSON object mapping namespaces to directories)", file, err)
+	}
+	for namespace, dir := range mapping {
+		if _, ok := namespaceMap[namespace]; !ok || namespace == "" {
+			return d, fmt.Errorf("invalid namespace-dirs file %s: the IDL has no namespace %q (namespaces: %s)", file, namespace, strings.Join(sortedNamespaces(namespaceMap), ", "))
+		}
+		if dir == "" {
+			return d, fmt.Errorf("invalid namespace-dirs file %s: namespace %q has an empty directory", file, namespace)
+		}
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(file), dir)
+		}
+		d.dirs[namespace] = filepath.Clean(dir)
+	}
+	return d, nil
+}
+
+// Mapped reports whether the -namespace-dirs file maps any namespace
+func (d namespaceDirs) Mapped() bool {
+	return len(d.dirs) > 0
+}
+
+// Dir returns the directory the module of namespace is written to
+func (d namespaceDirs) Dir(namespace string) string {
+	if dir, ok := d.dirs[namespace]; ok {
+		return dir
+	}
+	return d.base
+}
+
+// All returns the base directory and every mapped directory, once each
+func (d namespaceDirs) All() []string {
+	dirs := []string{d.base}
+	seen := map[string]bool{filepath.Clean(d.base): true}
+	mapped := make([]string, 0, len(d.dirs))
+	for _, dir := range d.dirs {
+		mapped = append(mapped, dir)
+	}
+	sort.Strings(mapped)
+	for _, dir := range mapped {
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// RelDir returns the slash separated path of the directory of namespace relative
+// to outputDir: "." for outputDir itself, or the absolute path when there is no
+// relative one
+func (d namespaceDirs) RelDir(outputDir, namespace string) string {
+	dir := d.Dir(namespace)
+	if filepath.Clean(dir) == filepath.Clean(outputDir) {
+		return "."
+	}
+	rel, err := filepath.Rel(absPath(outputDir), absPath(dir))
+	if err != nil {
+		return filepath.ToSlash(absPath(dir))
+	}
+	return filepath.ToSlash(rel)
+}
+
+// absPath returns p made absolute, or p itself if that fails
+func absPath(p string) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		return abs
+	}
+	return p
+}
+
+// ModulePath returns the path TypeScript imports the module of namespace by from
+// a file in outputDir: relative, starting with "./" or "../", and without an
+// extension
+func (d namespaceDirs) ModulePath(outputDir, namespace string) string {
+	rel := d.RelDir(outputDir, namespace)
+	modulePath := path.Join(rel, namespace)
+	if filepath.IsAbs(filepath.FromSlash(rel)) || strings.HasPrefix(modulePath, "../") {
+		return modulePath
+	}
+	return "./" + modulePath
+}
@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// generateWithNamespaceDirs runs plugin on shop.pulse, which imports the inc
+// namespace, with inc mapped to <root>/shared by a -namespace-dirs file in <root>.
+// It returns root; the output directory is <root>/service/gen.
+func generateWithNamespaceDirs(t *testing.T, plugin Plugin) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"inc.pulse":  "namespace inc\n\nstruct Money {\n    amount int\n}\n",
+		"shop.pulse": "namespace shop\n\nimport \"inc.pulse\"\n\nstruct Order {\n    total inc.Money\n}\n\ninterface Orders {\n    get(id string) Order\n}\n",
+		"dirs.json":  `{"inc": "shared"}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	generateFileForTest(t, plugin, filepath.Join(root, "shop.pulse"), filepath.Join(root, "service", "gen"), "-namespace-dirs="+filepath.Join(root, "dirs.json"))
+	return root
+}
+
+func TestNamespaceDirs(t *testing.T) {
+	tests := []struct {
+		plugin Plugin
+		shared string
+		own    string
+		file   string
+		want   string
+	}{
+		{NewPythonClientServer(), "inc.py", "shop.py", "server.py", "sys.path.insert(0, str(Path(__file__).parent / '../../shared'))\nfrom inc import ALL_STRUCTS as INC_STRUCTS"},
+		{NewTSClientServer(), "inc.ts", "shop.ts", "server.ts", "from '../../shared/inc';"},
+		{NewCSharpClientServer(), "Inc.cs", "Shop.cs", "", ""},
+	}
+	for _, tt := range tests {
+		root := generateWithNamespaceDirs(t, tt.plugin)
+		gen := filepath.Join(root, "service", "gen")
+		if _, err := os.Stat(filepath.Join(root, "shared", tt.shared)); err != nil {
+			t.Errorf("%s: expected the mapped namespace in shared/: %v", tt.plugin.Name(), err)
+		}
+		if _, err := os.Stat(filepath.Join(gen, tt.shared)); err == nil {
+			t.Errorf("%s: %s was also written to -dir", tt.plugin.Name(), tt.shared)
+		}
+		if _, err := os.Stat(filepath.Join(gen, tt.own)); err != nil {
+			t.Errorf("%s: expected the unmapped namespace in -dir: %v", tt.plugin.Name(), err)
+		}
+		if tt.file == "" {
+			continue
+		}
+		if data := readGenerated(t, gen, tt.file); !strings.Contains(data, tt.want) {
+			t.Errorf("%s: %s does not contain %q", tt.plugin.Name(), tt.file, tt.want)
+		}
+	}
+}
+
+// TestNamespaceDirsPythonImports imports the generated client, which loads the
+// mapped namespace module from outside -dir
+func TestNamespaceDirsPythonImports(t *testing.T) {
+	root := generateWithNamespaceDirs(t, NewPythonClientServer())
+	if out := runPythonCheck(t, filepath.Join(root, "service", "gen"), "import client; print(sorted(client.ALL_STRUCTS))"); out != "['Order', 'inc.Money']" {
+		t.Fatalf("importing the client printed:\n%s", out)
+	}
+}
+
+func TestNamespaceDirsUnknownNamespace(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "dirs.json")
+	if err := os.WriteFile(file, []byte(`{"incc": "shared"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	registerNamespaceDirsFlag(fs)
+	if err := fs.Set("namespace-dirs", file); err != nil {
+		t.Fatal(err)
+	}
+	_, err := loadNamespaceDirs(fs, dir, map[string]*NamespaceTypes{"inc": {}})
+	if err == nil || !strings.Contains(err.Error(), `no namespace "incc"`) {
+		t.Fatalf("expected an unknown namespace error, got %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package generator
+
+import (
+	"flag"
+)
+
+// The -generate-outbox-client flag emits an OutboxTransport next to each client for
+// intermittently connected clients. It wraps a transport and is given the methods
+// that may be queued, typically fire-and-forget notifications. When a call to one
+// of them fails because the server is unreachable, it is appended to a file and the
+// caller gets a "queued" error; queued calls are sent in order, each with the same
+// idempotency key, before the next queued method call or when Drain is called.
+
+// outboxClientRequested reports whether the -generate-outbox-client flag is set
+func outboxClientRequested(fs *flag.FlagSet) bool {
+	f := fs.Lookup("generate-outbox-client")
+	return f != nil && f.Value.String() == "true"
+}
+
+// outboxView is the view model for the OutboxTransport templates
+type outboxView struct {
+	// Package is the Go or Java package of the generated client
+	Package string
+	// Packaged is true when Python output is a package using relative imports
+	Packaged bool
+	// The TypeScript names below carry the -package prefix
+	CallOptions string
+	Transport   string
+	ClassName   string
+	QueuedError string
+}
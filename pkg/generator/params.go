@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Optional parameters: a method's trailing parameters may be [optional], with or
+// without a [default]. Servers accept params arrays that leave optional
+// parameters out, pad them with null, and replace a null parameter that has a
+// default with the default, so adding a parameter to a method no longer breaks
+// existing callers. Clients take optional parameters as nullable arguments that
+// default to null where the language allows it. The extra server logic is only
+// generated when the IDL has optional parameters.
+
+// usesOptionalParams reports whether any method has an optional parameter
+func usesOptionalParams(interfaces []*parser.Interface) bool {
+	for _, iface := range interfaces {
+		for _, method := range iface.Methods {
+			if method.RequiredParams() < len(method.Parameters) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// defaultJSON returns the [default] of a parameter as JSON text, and false if the
+// parameter has none. The IDL validator has already checked the value against the
+// parameter's type, and enum defaults are the value's name.
+func defaultJSON(param *parser.Parameter) (string, bool) {
+	value, ok := param.Default()
+	if !ok {
+		return "", false
+	}
+	if param.Type.IsBuiltIn() {
+		switch param.Type.BuiltIn {
+		case "int":
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return strconv.FormatInt(n, 10), true
+			}
+		case "float":
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				return strconv.FormatFloat(f, 'g', -1, 64), true
+			}
+		case "bool":
+			return value, true
+		}
+	}
+	var quoted bytes.Buffer
+	enc := json.NewEncoder(&quoted)
+	enc.SetEscapeHTML(false)
+	enc.Encode(value)
+	return strings.TrimSpace(quoted.String()), true
+}
+
+// defaultPython returns the [default] of a parameter as a Python literal
+func defaultPython(param *parser.Parameter) (string, bool) {
+	value, ok := defaultJSON(param)
+	switch {
+	case !ok:
+		return "", false
+	case value == "true":
+		return "True", true
+	case value == "false":
+		return "False", true
+	}
+	return value, true
+}
@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"flag"
+)
+
+// The -generate-patch-helpers flag emits helpers that compute a field-level diff
+// between two values of an IDL struct and apply such a diff as a partial update.
+// A patch is keyed by IDL field name and holds the changed fields only: an absent
+// field is left unchanged, while an explicit null clears an optional field.
+// Applying a patch rejects unknown fields and cleared required fields, so servers
+// can accept "changed fields only" payloads without hand-written merge code.
+
+// patchHelpersRequested reports whether the -generate-patch-helpers flag is set
+func patchHelpersRequested(fs *flag.FlagSet) bool {
+	f := fs.Lookup("generate-patch-helpers")
+	return f != nil && f.Value.String() == "true"
+}
+
+// patchView is the view model for the patch helper templates
+type patchView struct {
+	// Package is the Go package of the generated code
+	Package string
+	// RuntimeImport is the Go runtime package to dot-import when namespaces are
+	// split into packages, empty when the runtime is copied into Package
+	RuntimeImport string
+	// Packaged is true when Python output is a package using relative imports
+	Packaged bool
+	// Registries are the namespace modules whose struct definitions are merged (TS)
+	Registries []patchRegistry
+	// IdlClasses are the fully qualified per-namespace IDL classes (Java)
+	IdlClasses []string
+	// Package-prefixed TypeScript type name
+	Patch string
+}
+
+// patchRegistry is a TypeScript namespace module exporting ALL_STRUCTS and ALL_ENUMS
+type patchRegistry struct {
+	// Alias prefixes the imported names, e.g. INC for INC_STRUCTS
+	Alias string
+	// Path is the module path relative to patch.ts
+	Path string
+}
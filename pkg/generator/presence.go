@@ -0,0 +1,18 @@
+package generator
+
+import (
+	"flag"
+)
+
+// The -optional-presence flag makes optional struct fields tri-state, so that a
+// field left out of a value can be told apart from one set to an explicit null.
+// Go fields become Optional[T] from the runtime, tagged omitzero, and C#
+// properties become Optional<T>, which is omitted from JSON while absent. Python
+// dicts and TypeScript objects already tell a missing key from a null one, so
+// those generators need no change. Java is not supported yet.
+
+// optionalPresenceRequested reports whether the -optional-presence flag is set
+func optionalPresenceRequested(fs *flag.FlagSet) bool {
+	f := fs.Lookup("optional-presence")
+	return f != nil && f.Value.String() == "true"
+}
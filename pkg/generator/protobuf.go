@@ -0,0 +1,481 @@
+package generator
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/naming"
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// The protobuf plugin translates the IDL into proto3 for services moving to gRPC:
+// <namespace>.proto per namespace, whose package is the namespace, and
+// proto-mapping.md, a report of what does not translate cleanly.
+//
+//   - Structs become messages, numbered in declaration order. Protobuf has no
+//     inheritance, so a struct that extends another repeats the parent's fields
+//     first, as on the wire.
+//   - Enums become enums whose values are prefixed by the enum's name, as values
+//     share the package's scope, behind an <ENUM>_UNSPECIFIED zero value.
+//   - Interfaces become services. An rpc takes and returns a single message, so
+//     each method gets a <Interface><Method>Request message holding its params and
+//     a <Interface><Method>Response message holding its result.
+//   - A list or map inside a list or map, which protobuf cannot declare, is
+//     wrapped in a message such as Int64List { repeated int64 values = 1; }.
+//   - [optional] scalars and enums become proto3 optional fields. Lists and maps
+//     cannot be optional, so for them the absent and empty values merge.
+
+// protoMappingFile is the report of the constructs that did not translate cleanly
+const protoMappingFile = "proto-mapping.md"
+
+// ProtoNote is a construct of the IDL that protobuf cannot express as it is,
+// and how it was translated
+type ProtoNote struct {
+	// Decl names the declaration, e.g. "struct conform.Person" or "method A.add";
+	// it is empty for notes about the translation as a whole
+	Decl string
+	Msg  string
+}
+
+// Protobuf translates the IDL into proto3 files
+type Protobuf struct {
+}
+
+// NewProtobuf creates a new Protobuf plugin instance
+func NewProtobuf() *Protobuf {
+	return &Protobuf{}
+}
+
+// Name returns the plugin identifier
+func (p *Protobuf) Name() string {
+	return "protobuf"
+}
+
+// RegisterFlags registers CLI flags for this plugin
+func (p *Protobuf) RegisterFlags(fs *flag.FlagSet) {
+	fs.String("proto-package", "", "Package prefix of the generated .proto files (e.g., acme.api.v1); each namespace becomes <prefix>.<namespace>")
+}
+
+// Generate writes a .proto file per namespace and proto-mapping.md
+func (p *Protobuf) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
+	outputDir := ""
+	if dirFlag := fs.Lookup("dir"); dirFlag != nil {
+		outputDir = dirFlag.Value.String()
+	}
+	prefix := strings.Trim(fs.Lookup("proto-package").Value.String(), ".")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	files, notes := BuildProto(idl, prefix)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := writeGeneratedFile(filepath.Join(outputDir, name), []byte(files[name])); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	if err := writeGeneratedFile(filepath.Join(outputDir, protoMappingFile), []byte(protoMappingReport(names, notes))); err != nil {
+		return fmt.Errorf("failed to write %s: %w", protoMappingFile, err)
+	}
+	return nil
+}
+
+// BuildProto returns the .proto files of the IDL by file name, one per namespace,
+// and the notes on what did not translate cleanly. Each namespace's package is
+// prefix.<namespace>, or just the namespace when prefix is empty.
+func BuildProto(idl *parser.IDL, prefix string) (map[string]string, []ProtoNote) {
+	b := &protoBuilder{
+		idl:        idl,
+		prefix:     prefix,
+		structs:    make(map[string]*parser.Struct),
+		namespaces: make(map[string]string),
+		files:      make(map[string]*protoFile),
+		noted:      make(map[string]bool),
+	}
+	for _, s := range idl.Structs {
+		b.structs[s.Name] = s
+		b.namespaces[s.Name] = b.namespace(s.Namespace)
+	}
+	for _, e := range idl.Enums {
+		b.namespaces[e.Name] = b.namespace(e.Namespace)
+	}
+
+	b.note("", "Fields that are required in the IDL are proto3 fields with a default value: a receiver cannot tell a missing field from one holding its zero value.")
+	b.note("", "int is translated to int64, which the protobuf JSON mapping writes as a string.")
+	b.note("", "Field numbers follow the declaration order of the fields. Once a .proto file is in use, keep its numbers when the IDL changes: adding or removing a field other than the last renumbers the fields after it.")
+
+	for _, e := range idl.Enums {
+		b.writeEnum(e)
+	}
+	for _, s := range idl.Structs {
+		b.writeStruct(s)
+	}
+	for _, iface := range idl.Interfaces {
+		b.writeService(iface)
+	}
+
+	files := make(map[string]string)
+	for namespace, f := range b.files {
+		files[namespace+".proto"] = b.render(namespace, f)
+	}
+	return files, b.notes
+}
+
+// protoBuilder translates the declarations of an IDL into protoFiles
+type protoBuilder struct {
+	idl     *parser.IDL
+	prefix  string
+	structs map[string]*parser.Struct
+	// namespaces maps each struct and enum name to its namespace
+	namespaces map[string]string
+	files      map[string]*protoFile
+	notes      []ProtoNote
+	// noted holds the notes already made, so a construct used many times is
+	// reported once
+	noted map[string]bool
+}
+
+// protoFile is the content of the .proto file of a namespace
+type protoFile struct {
+	imports map[string]bool
+	// decls are the top-level messages, enums and services in IDL order
+	decls []string
+	// names are the names declared in the file, including wrapper messages
+	names map[string]bool
+	// wrappers are the wrapper messages, declared after decls in name order
+	wrappers map[string]string
+}
+
+// namespace returns the namespace a declaration of namespace belongs to;
+// declarations of files without a namespace belong to the root namespace
+func (b *protoBuilder) namespace(namespace string) string {
+	if namespace == "" {
+		namespace = b.idl.RootNamespace
+	}
+	if namespace == "" {
+		return "api"
+	}
+	return namespace
+}
+
+// file returns the protoFile of namespace
+func (b *protoBuilder) file(namespace string) *protoFile {
+	f := b.files[namespace]
+	if f == nil {
+		f = &protoFile{imports: make(map[string]bool), names: make(map[string]bool), wrappers: make(map[string]string)}
+		b.files[namespace] = f
+	}
+	return f
+}
+
+func (b *protoBuilder) note(decl, msg string) {
+	if key := decl + "\x00" + msg; !b.noted[key] {
+		b.noted[key] = true
+		b.notes = append(b.notes, ProtoNote{Decl: decl, Msg: msg})
+	}
+}
+
+// render returns the .proto source of a namespace's file
+func (b *protoBuilder) render(namespace string, f *protoFile) string {
+	var sb strings.Builder
+	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
+	sb.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&sb, "package %s;\n", b.protoPackage(namespace))
+	if len(f.imports) > 0 {
+		sb.WriteString("\n")
+		imports := make([]string, 0, len(f.imports))
+		for imp := range f.imports {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+		for _, imp := range imports {
+			fmt.Fprintf(&sb, "import %q;\n", imp)
+		}
+	}
+	for _, decl := range f.decls {
+		sb.WriteString("\n" + decl)
+	}
+	wrappers := make([]string, 0, len(f.wrappers))
+	for name := range f.wrappers {
+		wrappers = append(wrappers, name)
+	}
+	sort.Strings(wrappers)
+	for _, name := range wrappers {
+		sb.WriteString("\n" + f.wrappers[name])
+	}
+	return sb.String()
+}
+
+// protoPackage returns the package of namespace's file
+func (b *protoBuilder) protoPackage(namespace string) string {
+	if b.prefix == "" {
+		return namespace
+	}
+	return b.prefix + "." + namespace
+}
+
+// writeEnum adds an enum, whose values are prefixed by its name behind a zero value
+func (b *protoBuilder) writeEnum(e *parser.Enum) {
+	namespace := b.namespaces[e.Name]
+	name := localTypeName(e.Name)
+	prefix := strings.ToUpper(naming.ToSnake(name)) + "_"
+	b.note("enum "+namespace+"."+name, fmt.Sprintf("Values are prefixed with %s, as protobuf enum values share the package's scope, and %sUNSPECIFIED = 0 comes first, as proto3 requires a zero value. The protobuf JSON mapping writes the prefixed names.", prefix, prefix))
+
+	var sb strings.Builder
+	writeProtoComment(&sb, "", e.Comment)
+	fmt.Fprintf(&sb, "enum %s {\n", name)
+	fmt.Fprintf(&sb, "  %sUNSPECIFIED = 0;\n", prefix)
+	for i, v := range e.Values {
+		writeProtoComment(&sb, "  ", v.Comment)
+		fmt.Fprintf(&sb, "  %s%s = %d;\n", prefix, strings.ToUpper(naming.ToSnake(v.Name)), i+1)
+	}
+	sb.WriteString("}\n")
+	f := b.file(namespace)
+	f.names[name] = true
+	f.decls = append(f.decls, sb.String())
+}
+
+// writeStruct adds a struct as a message holding its fields and those of the
+// structs it extends
+func (b *protoBuilder) writeStruct(s *parser.Struct) {
+	namespace := b.namespaces[s.Name]
+	name := localTypeName(s.Name)
+	decl := "struct " + namespace + "." + name
+
+	if parser.ExtendedStruct(s, b.structs) != nil {
+		b.note(decl, fmt.Sprintf("Extends %s: protobuf has no inheritance, so the message repeats the parent's fields first.", s.Extends))
+	}
+
+	var sb strings.Builder
+	writeProtoComment(&sb, "", s.Comment)
+	fmt.Fprintf(&sb, "message %s {\n", name)
+	number := 1
+	for _, field := range parser.ResolveStructFields(s, b.structs) {
+		writeProtoComment(&sb, "  ", field.Comment)
+		typ := b.fieldType(field.Type, namespace, decl+" field "+field.Name, field.Optional)
+		fmt.Fprintf(&sb, "  %s %s = %d;\n", typ, field.Name, number)
+		number++
+	}
+	sb.WriteString("}\n")
+	f := b.file(namespace)
+	f.names[name] = true
+	f.decls = append(f.decls, sb.String())
+}
+
+// writeService adds an interface as a service with the request and response
+// messages of its methods
+func (b *protoBuilder) writeService(iface *parser.Interface) {
+	namespace := b.namespace(iface.Namespace)
+	name := localTypeName(iface.Name)
+	f := b.file(namespace)
+	if len(iface.Extends) > 0 {
+		b.note("interface "+name, fmt.Sprintf("Extends %s: services cannot extend each other, so the service declares the inherited methods too.", strings.Join(iface.Extends, ", ")))
+	}
+
+	var messages []string
+	var sb strings.Builder
+	writeProtoComment(&sb, "", iface.Comment)
+	fmt.Fprintf(&sb, "service %s {\n", name)
+	for _, method := range iface.Methods {
+		decl := "method " + iface.RPCName(method)
+		base := naming.SnakeToPascal(name) + naming.SnakeToPascal(method.Name)
+		request := b.messageName(f, base+"Request", decl)
+		response := b.messageName(f, base+"Response", decl)
+		if method.ErrorData() != "" {
+			b.note(decl, fmt.Sprintf("[errordata=%q] has no protobuf counterpart; send the struct as a detail of the gRPC status.", method.ErrorData()))
+		}
+
+		var req strings.Builder
+		fmt.Fprintf(&req, "message %s {\n", request)
+		for i, param := range method.Parameters {
+			typ := b.fieldType(param.Type, namespace, decl+" param "+param.Name, param.Optional)
+			fmt.Fprintf(&req, "  %s %s = %d;\n", typ, param.Name, i+1)
+		}
+		req.WriteString("}\n")
+		var resp strings.Builder
+		fmt.Fprintf(&resp, "message %s {\n", response)
+		fmt.Fprintf(&resp, "  %s result = 1;\n", b.fieldType(method.ReturnType, namespace, decl+" result", method.ReturnOptional))
+		resp.WriteString("}\n")
+		messages = append(messages, req.String(), resp.String())
+
+		fmt.Fprintf(&sb, "  rpc %s(%s) returns (%s);\n", naming.SnakeToPascal(method.Name), request, response)
+	}
+	sb.WriteString("}\n")
+	f.decls = append(f.decls, sb.String())
+	f.decls = append(f.decls, messages...)
+}
+
+// messageName returns name, or name with a number appended when f already
+// declares name, such as a struct called AddRequest
+func (b *protoBuilder) messageName(f *protoFile, name, decl string) string {
+	unique := name
+	for i := 2; f.names[unique] || b.declaredElsewhere(unique); i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	if unique != name {
+		b.note(decl, fmt.Sprintf("Its message is called %s, as %s is taken.", unique, name))
+	}
+	f.names[unique] = true
+	return unique
+}
+
+// declaredElsewhere reports whether a struct or enum that has not been added yet
+// has the given name in any namespace
+func (b *protoBuilder) declaredElsewhere(name string) bool {
+	for full := range b.namespaces {
+		if localTypeName(full) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldType returns the type of a field of type t in the file of namespace, with
+// its label: repeated for lists and optional for optional scalars and enums
+func (b *protoBuilder) fieldType(t *parser.Type, namespace, decl string, optional bool) string {
+	switch {
+	case t.IsArray():
+		if optional {
+			b.note(decl, "[optional] list: repeated fields cannot be optional, so an absent list reads as empty.")
+		}
+		return "repeated " + b.elementType(t.Array, namespace, decl)
+	case t.IsMap():
+		if optional {
+			b.note(decl, "[optional] map: map fields cannot be optional, so an absent map reads as empty.")
+		}
+		return "map<string, " + b.elementType(t.MapValue, namespace, decl) + ">"
+	}
+	typ := b.scalarType(t, namespace, decl)
+	if optional && (t.IsBuiltIn() || b.isEnum(t)) {
+		return "optional " + typ
+	}
+	return typ
+}
+
+// elementType returns the type of the elements of a list or values of a map,
+// wrapping a nested list or map in a message
+func (b *protoBuilder) elementType(t *parser.Type, namespace, decl string) string {
+	if !t.IsArray() && !t.IsMap() {
+		return b.scalarType(t, namespace, decl)
+	}
+	name := protoWrapperName(t)
+	f := b.file(namespace)
+	if _, ok := f.wrappers[name]; !ok {
+		f.wrappers[name] = "" // reserve the name while the nested types are wrapped
+		var field string
+		if t.IsArray() {
+			field = "repeated " + b.elementType(t.Array, namespace, decl)
+		} else {
+			field = "map<string, " + b.elementType(t.MapValue, namespace, decl) + ">"
+		}
+		f.wrappers[name] = fmt.Sprintf("message %s {\n  %s values = 1;\n}\n", name, field)
+		f.names[name] = true
+	}
+	b.note(decl, fmt.Sprintf("A list or map nested in a list or map is wrapped in message %s, whose JSON form is {\"values\": ...} rather than the bare value.", name))
+	return name
+}
+
+// scalarType returns the type of a built-in, struct or enum value
+func (b *protoBuilder) scalarType(t *parser.Type, namespace, decl string) string {
+	if t.IsUserDefined() {
+		target, ok := b.namespaces[t.UserDefined]
+		if !ok {
+			// An unresolved name, such as an interface, holds any value
+			b.file(namespace).imports["google/protobuf/struct.proto"] = true
+			b.note(decl, fmt.Sprintf("%s is not a struct or enum and is translated to google.protobuf.Value.", t.UserDefined))
+			return "google.protobuf.Value"
+		}
+		if target == namespace {
+			return localTypeName(t.UserDefined)
+		}
+		b.file(namespace).imports[target+".proto"] = true
+		return b.protoPackage(target) + "." + localTypeName(t.UserDefined)
+	}
+	switch t.BuiltIn {
+	case "int":
+		return "int64"
+	case "float":
+		return "double"
+	case "bool":
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+func (b *protoBuilder) isEnum(t *parser.Type) bool {
+	if !t.IsUserDefined() {
+		return false
+	}
+	_, isStruct := b.structs[t.UserDefined]
+	_, known := b.namespaces[t.UserDefined]
+	return known && !isStruct
+}
+
+// protoWrapperName returns the name of the message wrapping a list or map of t's
+// elements, e.g. Int64List for []int and StringListMap for map[string][]string
+func protoWrapperName(t *parser.Type) string {
+	switch {
+	case t.IsArray():
+		return protoWrapperName(t.Array) + "List"
+	case t.IsMap():
+		return protoWrapperName(t.MapValue) + "Map"
+	case t.IsUserDefined():
+		return localTypeName(t.UserDefined)
+	}
+	switch t.BuiltIn {
+	case "int":
+		return "Int64"
+	case "float":
+		return "Double"
+	case "bool":
+		return "Bool"
+	default:
+		return "String"
+	}
+}
+
+// writeProtoComment writes comment as // lines prefixed by indent
+func writeProtoComment(sb *strings.Builder, indent, comment string) {
+	if comment == "" {
+		return
+	}
+	for _, line := range strings.Split(comment, "\n") {
+		sb.WriteString(strings.TrimRight(indent+"// "+line, " ") + "\n")
+	}
+}
+
+// protoMappingReport returns proto-mapping.md, listing the generated files and the
+// notes on the translation
+func protoMappingReport(files []string, notes []ProtoNote) string {
+	var sb strings.Builder
+	sb.WriteString("<!-- Generated by pulserpc - do not edit -->\n\n")
+	sb.WriteString("# Protobuf mapping report\n\n")
+	sb.WriteString("Files: " + strings.Join(files, ", ") + "\n\n")
+	sb.WriteString("## General\n\n")
+	for _, n := range notes {
+		if n.Decl == "" {
+			sb.WriteString("- " + n.Msg + "\n")
+		}
+	}
+	sb.WriteString("\n## Declarations\n\n")
+	count := 0
+	for _, n := range notes {
+		if n.Decl != "" {
+			fmt.Fprintf(&sb, "- `%s`: %s\n", n.Decl, n.Msg)
+			count++
+		}
+	}
+	if count == 0 {
+		sb.WriteString("Every declaration translates as it is.\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestBuildProto(t *testing.T) {
+	idl := &parser.IDL{
+		RootNamespace: "shop",
+		Enums: []*parser.Enum{
+			{Name: "inc.OrderState", Namespace: "inc", Values: []*parser.EnumValue{{Name: "open"}, {Name: "shipped"}}},
+		},
+		Structs: []*parser.Struct{
+			{Name: "OrdersFindRequest", Namespace: "shop", Fields: []*parser.Field{
+				{Name: "id", Type: &parser.Type{BuiltIn: "int"}},
+			}},
+			{Name: "Order", Namespace: "shop", Fields: []*parser.Field{
+				{Name: "state", Type: &parser.Type{UserDefined: "inc.OrderState"}, Optional: true},
+				{Name: "grid", Type: &parser.Type{Array: &parser.Type{Array: &parser.Type{BuiltIn: "int"}}}},
+				{Name: "tags", Type: &parser.Type{MapValue: &parser.Type{Array: &parser.Type{BuiltIn: "string"}}}, Optional: true},
+			}},
+		},
+		Interfaces: []*parser.Interface{
+			{Name: "Orders", Namespace: "shop", Methods: []*parser.Method{
+				{
+					Name:       "find",
+					Parameters: []*parser.Parameter{{Name: "id", Type: &parser.Type{BuiltIn: "int"}}},
+					ReturnType: &parser.Type{UserDefined: "Order"},
+				},
+			}},
+		},
+	}
+	files, notes := BuildProto(idl, "acme.v1")
+
+	shop := files["shop.proto"]
+	for _, want := range []string{
+		"package acme.v1.shop;\n",
+		"import \"inc.proto\";\n",
+		"  optional acme.v1.inc.OrderState state = 1;\n",
+		"  repeated Int64List grid = 2;\n",
+		"  map<string, StringList> tags = 3;\n",
+		"message Int64List {\n  repeated int64 values = 1;\n}\n",
+		"message StringList {\n  repeated string values = 1;\n}\n",
+		// OrdersFindRequest is taken by a struct
+		"service Orders {\n  rpc Find(OrdersFindRequest2) returns (OrdersFindResponse);\n}\n",
+		"message OrdersFindResponse {\n  Order result = 1;\n}\n",
+	} {
+		if !strings.Contains(shop, want) {
+			t.Errorf("shop.proto lacks %q:\n%s", want, shop)
+		}
+	}
+	if !strings.Contains(files["inc.proto"], "enum OrderState {\n  ORDER_STATE_UNSPECIFIED = 0;\n  ORDER_STATE_OPEN = 1;\n  ORDER_STATE_SHIPPED = 2;\n}\n") {
+		t.Errorf("unexpected inc.proto:\n%s", files["inc.proto"])
+	}
+
+	var report []string
+	for _, n := range notes {
+		report = append(report, n.Decl+": "+n.Msg)
+	}
+	joined := strings.Join(report, "\n")
+	for _, want := range []string{
+		"struct shop.Order field grid: A list or map nested in a list or map is wrapped in message Int64List",
+		"struct shop.Order field tags: [optional] map: map fields cannot be optional",
+		"method Orders.find: Its message is called OrdersFindRequest2, as OrdersFindRequest is taken.",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("notes lack %q:\n%s", want, joined)
+		}
+	}
+}
@@ -1,18 +1,22 @@
 package generator
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 
+	"github.com/coopernurse/pulserpc/pkg/naming"
 	"github.com/coopernurse/pulserpc/pkg/parser"
 	"github.com/coopernurse/pulserpc/pkg/runtime"
 )
 
+// pythonIdentifierRegex matches names that are valid Python package identifiers
+var pythonIdentifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 // PythonClientServer is a plugin that generates Python HTTP server and client code from IDL
 type PythonClientServer struct {
 }
@@ -33,10 +37,20 @@ func (p *PythonClientServer) RegisterFlags(fs *flag.FlagSet) {
 	if fs.Lookup("base-dir") == nil {
 		fs.String("base-dir", "", "Base directory for namespace packages/modules (defaults to -dir if not specified)")
 	}
+	registerNamespaceDirsFlag(fs)
+	// Register py-packages flag for generating one package per namespace
+	fs.Bool("py-packages", false, "Generate each IDL namespace as a Python package and make -dir an importable package using relative imports")
 }
 
 // Generate generates Python HTTP server and client code from the parsed IDL
-func (p *PythonClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
+func (p *PythonClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) (err error) {
+	ts, err := templatesFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	defer recoverTemplateError(&err)
+	start := repoFilesStart()
+
 	// Access the -dir flag value
 	dirFlag := fs.Lookup("dir")
 	outputDir := ""
@@ -50,6 +64,31 @@ func (p *PythonClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 	if baseDirFlag != nil && baseDirFlag.Value.String() != "" {
 		baseDir = baseDirFlag.Value.String()
 	}
+	style, err := parseCodeStyle(fs, "python")
+	if err != nil {
+		return err
+	}
+
+	// Get py-packages flag. In package mode the output directory itself becomes
+	// a package, so its name must be importable.
+	pyPackagesFlag := fs.Lookup("py-packages")
+	packageName := ""
+	if pyPackagesFlag != nil && pyPackagesFlag.Value.String() == "true" {
+		if baseDir != outputDir {
+			return fmt.Errorf("base-dir cannot be used with py-packages (namespace packages are written under -dir)")
+		}
+		absOutputDir, err := filepath.Abs(outputDir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve output directory: %w", err)
+		}
+		packageName = filepath.Base(absOutputDir)
+		if !pythonIdentifierRegex.MatchString(packageName) {
+			return fmt.Errorf("output directory name %q is not a valid Python package name", packageName)
+		}
+	}
+	if indexFilesRequested(fs) && packageName == "" {
+		return fmt.Errorf("generate-index-files requires py-packages for Python output (the index is the package's __init__.py)")
+	}
 
 	// Build type registries
 	structMap := make(map[string]*parser.Struct)
@@ -73,41 +112,145 @@ func (p *PythonClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 
 	// Group types by namespace
 	namespaceMap := GroupTypesByNamespace(idl)
+	nsDirs, err := loadNamespaceDirs(fs, baseDir, namespaceMap)
+	if err != nil {
+		return err
+	}
+	if packageName != "" && nsDirs.Mapped() {
+		return fmt.Errorf("namespace-dirs cannot be used with py-packages (namespace packages are written under -dir)")
+	}
 
-	// Generate one file per namespace
+	// Generate one file per namespace, or one package per namespace
 	for namespace, types := range namespaceMap {
 		if namespace == "" {
 			continue // Skip types without namespace (shouldn't happen with required namespaces)
 		}
-		namespaceCode := generateNamespacePy(namespace, types)
-		namespacePath := filepath.Join(baseDir, namespace+".py")
-		if err := os.WriteFile(namespacePath, []byte(namespaceCode), 0644); err != nil {
-			return fmt.Errorf("failed to write %s.py: %w", namespace, err)
+		namespaceCode := generateNamespacePy(ts, namespace, types, structMap, packageName != "")
+		namespacePath := filepath.Join(nsDirs.Dir(namespace), namespace+".py")
+		if packageName != "" {
+			namespacePath = filepath.Join(outputDir, namespace, "__init__.py")
+		}
+		if err := os.MkdirAll(filepath.Dir(namespacePath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", namespacePath, err)
+		}
+		if err := writeGeneratedFile(namespacePath, []byte(namespaceCode)); err != nil {
+			return fmt.Errorf("failed to write %s: %w", namespacePath, err)
 		}
 	}
 
+	// Make the output directory a package so server and client can use relative imports.
+	// With -generate-index-files the package re-exports the generated API.
+	if packageName != "" {
+		initCode := "# Generated by pulserpc - do not edit\n"
+		if indexFilesRequested(fs) {
+			initCode = generateIndexPy(buildNamespaceIndex(namespaceMap), usesAPIClientFacade(idl.Interfaces), errorDataStructs(idl.Interfaces))
+		}
+		initPath := filepath.Join(outputDir, "__init__.py")
+		if err := writeGeneratedFile(initPath, []byte(initCode)); err != nil {
+			return fmt.Errorf("failed to write __init__.py: %w", err)
+		}
+	}
+
+	idlDoc, err := newIDLJSONDocument(idl, fs)
+	if err != nil {
+		return err
+	}
+
 	// Generate server.py
-	serverCode := generateServerPy(idl, structMap, enumMap, interfaceMap, namespaceMap, baseDir, outputDir)
+	serverCode := generateServerPy(ts, idl, namespaceMap, nsDirs, outputDir, packageName != "", faultInjectionRequested(fs), adminEndpointRequested(fs), idlDoc)
 	serverPath := filepath.Join(outputDir, "server.py")
-	if err := os.WriteFile(serverPath, []byte(serverCode), 0644); err != nil {
+	if err := writeGeneratedFile(serverPath, []byte(serverCode)); err != nil {
 		return fmt.Errorf("failed to write server.py: %w", err)
 	}
 
+	// Generate methods.py, used by both the client and the server
+	methodsCode := generateMethodTablePy(idl.Interfaces)
+	if err := writeGeneratedFile(filepath.Join(outputDir, "methods.py"), []byte(methodsCode)); err != nil {
+		return fmt.Errorf("failed to write methods.py: %w", err)
+	}
+
 	// Generate client.py
-	clientCode := generateClientPy(idl, structMap, enumMap, interfaceMap, namespaceMap, baseDir, outputDir)
+	clientCode := generateClientPy(ts, idl, structMap, namespaceMap, nsDirs, outputDir, packageName != "")
 	clientPath := filepath.Join(outputDir, "client.py")
-	if err := os.WriteFile(clientPath, []byte(clientCode), 0644); err != nil {
+	if err := writeGeneratedFile(clientPath, []byte(clientCode)); err != nil {
 		return fmt.Errorf("failed to write client.py: %w", err)
 	}
 
+	// Generate discovery.py next to the client
+	discoveryCode := ts.renderString("python/discovery.py.tmpl", discoveryView{Packaged: packageName != ""})
+	if err := writeGeneratedFile(filepath.Join(outputDir, "discovery.py"), []byte(discoveryCode)); err != nil {
+		return fmt.Errorf("failed to write discovery.py: %w", err)
+	}
+
+	// Generate signing.py, used by both the client and the server
+	signingCode := ts.renderString("python/signing.py.tmpl", signingView{})
+	if err := writeGeneratedFile(filepath.Join(outputDir, "signing.py"), []byte(signingCode)); err != nil {
+		return fmt.Errorf("failed to write signing.py: %w", err)
+	}
+
+	// Generate retry.py next to the client
+	retryCode := ts.renderString("python/retry.py.tmpl", retryView{Packaged: packageName != "", Methods: idempotentMethods(idl)})
+	if err := writeGeneratedFile(filepath.Join(outputDir, "retry.py"), []byte(retryCode)); err != nil {
+		return fmt.Errorf("failed to write retry.py: %w", err)
+	}
+
+	// Generate shadow.py next to the client
+	if shadowClientRequested(fs) {
+		shadowCode := ts.renderString("python/shadow.py.tmpl", shadowView{Packaged: packageName != ""})
+		if err := writeGeneratedFile(filepath.Join(outputDir, "shadow.py"), []byte(shadowCode)); err != nil {
+			return fmt.Errorf("failed to write shadow.py: %w", err)
+		}
+	}
+
+	// Generate outbox.py next to the client
+	if outboxClientRequested(fs) {
+		outboxCode := ts.renderString("python/outbox.py.tmpl", outboxView{Packaged: packageName != ""})
+		if err := writeGeneratedFile(filepath.Join(outputDir, "outbox.py"), []byte(outboxCode)); err != nil {
+			return fmt.Errorf("failed to write outbox.py: %w", err)
+		}
+	}
+
+	// Generate broker.py next to the client
+	if brokerTransportRequested(fs) {
+		brokerCode := ts.renderString("python/broker.py.tmpl", brokerView{Packaged: packageName != ""})
+		if err := writeGeneratedFile(filepath.Join(outputDir, "broker.py"), []byte(brokerCode)); err != nil {
+			return fmt.Errorf("failed to write broker.py: %w", err)
+		}
+	}
+
+	// Generate patch.py next to the client
+	if patchHelpersRequested(fs) {
+		patchCode := ts.renderString("python/patch.py.tmpl", patchView{Packaged: packageName != ""})
+		if err := writeGeneratedFile(filepath.Join(outputDir, "patch.py"), []byte(patchCode)); err != nil {
+			return fmt.Errorf("failed to write patch.py: %w", err)
+		}
+	}
+
+	// Generate builders.py with a test data builder per struct
+	if testBuildersRequested(fs) {
+		buildersCode := generateTestBuildersPy(namespaceMap, structMap, enumMap)
+		if err := writeGeneratedFile(filepath.Join(outputDir, "builders.py"), []byte(buildersCode)); err != nil {
+			return fmt.Errorf("failed to write builders.py: %w", err)
+		}
+	}
+
+	// Generate serverless.py next to the server
+	if serverlessAdapterRequested(fs) {
+		serverlessCode := ts.renderString("python/serverless.py.tmpl", serverlessView{})
+		if err := writeGeneratedFile(filepath.Join(outputDir, "serverless.py"), []byte(serverlessCode)); err != nil {
+			return fmt.Errorf("failed to write serverless.py: %w", err)
+		}
+	}
+
 	// Write IDL JSON document for pulserpc-idl RPC method
-	jsonData, err := json.MarshalIndent(idl, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal IDL to JSON: %w", err)
+	if err := idlDoc.Write(outputDir); err != nil {
+		return err
 	}
-	jsonPath := filepath.Join(outputDir, "idl.json")
-	if err := os.WriteFile(jsonPath, jsonData, 0644); err != nil {
-		return fmt.Errorf("failed to write idl.json: %w", err)
+
+	// Write testvectors.json if -generate-test-vectors is set
+	hasTestVectors, err := writeTestVectorsIfRequested(idl, fs, outputDir)
+	if err != nil {
+		return err
 	}
 
 	// Check if generate-test-files flag is set
@@ -117,82 +260,180 @@ func (p *PythonClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 	// Generate test server and client if flag is set
 	if generateTestServer {
 		// Generate test_server.py
-		testServerCode := generateTestServerPy(idl, structMap, enumMap, interfaceMap, namespaceMap, baseDir, outputDir)
+		testServerCode := generateTestServerPy(idl, structMap, enumMap, interfaceMap, namespaceMap, packageName, outputDir, faultInjectionRequested(fs), adminEndpointRequested(fs))
 		testServerPath := filepath.Join(outputDir, "test_server.py")
-		if err := os.WriteFile(testServerPath, []byte(testServerCode), 0644); err != nil {
+		if err := writeGeneratedFile(testServerPath, []byte(testServerCode)); err != nil {
 			return fmt.Errorf("failed to write test_server.py: %w", err)
 		}
 
 		// Generate test_client.py
-		testClientCode := generateTestClientPy(idl, structMap, enumMap, interfaceMap, namespaceMap, baseDir, outputDir)
+		testClientCode := generateTestClientPy(idl, structMap, enumMap, interfaceMap, namespaceMap, packageName, outputDir, hasTestVectors)
 		testClientPath := filepath.Join(outputDir, "test_client.py")
-		if err := os.WriteFile(testClientPath, []byte(testClientCode), 0644); err != nil {
+		if err := writeGeneratedFile(testClientPath, []byte(testClientCode)); err != nil {
 			return fmt.Errorf("failed to write test_client.py: %w", err)
 		}
 	}
 
+	// Generate the pytest handler harness
+	if testHarnessRequested(fs) {
+		view := pythonHarnessView{
+			ServerModule:   "server",
+			HandlersModule: "harness_handlers",
+			Interfaces:     buildHarnessInterfaces(idl, naming.ToSnake),
+		}
+		if packageName != "" {
+			view.ServerModule = packageName + ".server"
+			view.HandlersModule = packageName + ".harness_handlers"
+		}
+		harnessCode := ts.renderString("python/test_harness.py.tmpl", view)
+		if err := writeGeneratedFile(filepath.Join(outputDir, "test_harness.py"), []byte(harnessCode)); err != nil {
+			return fmt.Errorf("failed to write test_harness.py: %w", err)
+		}
+		handlersCode := ts.renderString("python/harness_handlers.py.tmpl", view)
+		if err := writeSkeletonFile(filepath.Join(outputDir, "harness_handlers.py"), []byte(handlersCode)); err != nil {
+			return fmt.Errorf("failed to write harness_handlers.py: %w", err)
+		}
+	}
+
+	// Generate pytest contract tests from the IDL examples
+	contractView := buildContractView(idl, naming.ToSnake)
+	contracts := contractTestsRequested(fs) && len(contractView.Interfaces) > 0
+	if contracts {
+		clientModule := "client"
+		if packageName != "" {
+			clientModule = packageName + ".client"
+		}
+		contractCode := ts.renderString("python/test_contract.py.tmpl", pythonContractView{
+			ClientModule: clientModule,
+			contractView: contractView,
+		})
+		if err := writeGeneratedFile(filepath.Join(outputDir, "test_contract.py"), []byte(contractCode)); err != nil {
+			return fmt.Errorf("failed to write test_contract.py: %w", err)
+		}
+	}
+
+	if err := restyleGeneratedFiles("python", style, append([]string{outputDir}, nsDirs.All()...)...); err != nil {
+		return err
+	}
+
+	if dependencyManifestRequested(fs) || sbomRequested(fs) {
+		versions, err := dependencyVersions(fs)
+		if err != nil {
+			return err
+		}
+		mirror, err := dependencyMirrorFlag(fs)
+		if err != nil {
+			return err
+		}
+		deps := pythonDependencies(versions, testHarnessRequested(fs) || contracts)
+		if dependencyManifestRequested(fs) {
+			if err := writePythonDependencyManifest(outputDir, deps, mirror); err != nil {
+				return err
+			}
+		}
+		if sbomRequested(fs) {
+			if err := writeSBOM(outputDir, idl, "python", "pulserpc", deps); err != nil {
+				return err
+			}
+		}
+	}
+
+	if repoFilesRequested(fs) {
+		return writeRepoFiles("python", style, start, append([]string{outputDir}, nsDirs.All()...)...)
+	}
 	return nil
 }
 
+// pythonHarnessView is the view model for test_harness.py and harness_handlers.py
+type pythonHarnessView struct {
+	ServerModule   string
+	HandlersModule string
+	Interfaces     []harnessInterface
+}
+
+// pythonContractView is the view model for test_contract.py
+type pythonContractView struct {
+	ClientModule string
+	contractView
+}
+
 // copyRuntimeFiles copies the Python runtime library files to the output directory
 // Uses embedded runtime files from the binary
 func (p *PythonClientServer) copyRuntimeFiles(outputDir string) error {
 	return runtime.CopyRuntimeFiles("python", outputDir)
 }
 
-// generateNamespacePy generates a Python file for a single namespace
-func generateNamespacePy(namespace string, types *NamespaceTypes) string {
-	var sb strings.Builder
+// generateNamespacePy generates a Python file for a single namespace.
+// Packaged namespaces live one level below the runtime and import it relatively.
+func generateNamespacePy(ts *templateSet, namespace string, types *NamespaceTypes, structMap map[string]*parser.Struct, packaged bool) string {
+	view := newTypeRegistryView(namespace, types, writeTypeDict)
+	view.Packaged = packaged
+	var classes strings.Builder
+	writeImmutableClassesPy(&classes, namespace, types.Structs, structMap)
+	view.Dataclasses = classes.String()
+	return ts.renderString("python/namespace.py.tmpl", view)
+}
 
-	sb.WriteString("# Generated by pulserpc - do not edit\n\n")
-	sb.WriteString("from pulserpc import (\n")
-	sb.WriteString("    RPCError,\n")
-	sb.WriteString("    validate_type,\n")
-	sb.WriteString("    validate_struct,\n")
-	sb.WriteString("    validate_enum,\n")
-	sb.WriteString("    find_struct,\n")
-	sb.WriteString("    find_enum,\n")
-	sb.WriteString("    get_struct_fields,\n")
-	sb.WriteString(")\n\n")
-
-	// Generate IDL-specific type definitions for this namespace
-	sb.WriteString(fmt.Sprintf("# IDL-specific type definitions for namespace: %s\n", namespace))
-	sb.WriteString("ALL_STRUCTS = {\n")
-	for _, s := range types.Structs {
-		sb.WriteString(fmt.Sprintf("    '%s': {\n", s.Name))
-		if s.Extends != "" {
-			sb.WriteString(fmt.Sprintf("        'extends': '%s',\n", s.Extends))
+// writeNamespaceImportsPy writes the runtime, method table and namespace registry imports shared by
+// server.py and client.py and returns the sorted namespaces that were imported.
+// Packaged output uses relative imports so it works regardless of the current directory.
+// runtimeNames are imported from the runtime next to RPCError and validate_type.
+func writeNamespaceImportsPy(sb *strings.Builder, namespaceMap map[string]*NamespaceTypes, nsDirs namespaceDirs, outputDir string, packaged bool, runtimeNames []string) []string {
+	runtimeNames = append([]string{"RPCError", "validate_type"}, runtimeNames...)
+	sort.Strings(runtimeNames)
+	runtimeImports := strings.Join(runtimeNames, ", ")
+	// Import from namespace modules
+	namespaces := make([]string, 0, len(namespaceMap))
+	for ns := range namespaceMap {
+		if ns != "" {
+			namespaces = append(namespaces, ns)
 		}
-		sb.WriteString("        'fields': [\n")
-		for _, field := range s.Fields {
-			sb.WriteString("            {\n")
-			sb.WriteString(fmt.Sprintf("                'name': '%s',\n", field.Name))
-			sb.WriteString("                'type': ")
-			writeTypeDict(&sb, field.Type)
-			sb.WriteString(",\n")
-			if field.Optional {
-				sb.WriteString("                'optional': True,\n")
-			}
-			sb.WriteString("            },\n")
+	}
+	// Sort namespaces for consistent output
+	sort.Strings(namespaces)
+
+	if packaged {
+		fmt.Fprintf(sb, "from .pulserpc import %s\n", runtimeImports)
+		sb.WriteString("from .methods import METHOD_DEFS\n")
+		for _, ns := range namespaces {
+			fmt.Fprintf(sb, "from .%s import ALL_STRUCTS as %s_STRUCTS, ALL_ENUMS as %s_ENUMS\n", ns, strings.ToUpper(ns), strings.ToUpper(ns))
 		}
-		sb.WriteString("        ],\n")
-		sb.WriteString("    },\n")
+		sb.WriteString("\n")
+		return namespaces
 	}
-	sb.WriteString("}\n\n")
 
-	sb.WriteString("ALL_ENUMS = {\n")
-	for _, e := range types.Enums {
-		sb.WriteString(fmt.Sprintf("    '%s': {\n", e.Name))
-		sb.WriteString("        'values': [\n")
-		for _, val := range e.Values {
-			sb.WriteString(fmt.Sprintf("            {'name': '%s'},\n", val.Name))
+	fmt.Fprintf(sb, "from pulserpc import %s\n", runtimeImports)
+	sb.WriteString("from methods import METHOD_DEFS\n")
+
+	// Modules in a subdirectory of outputDir are imported by dotted path, those
+	// elsewhere after putting their directory on sys.path
+	pathDirs := make(map[string]bool)
+	for _, ns := range namespaces {
+		rel := nsDirs.RelDir(outputDir, ns)
+		switch {
+		case rel == ".":
+			fmt.Fprintf(sb, "from %s import ALL_STRUCTS as %s_STRUCTS, ALL_ENUMS as %s_ENUMS\n", ns, strings.ToUpper(ns), strings.ToUpper(ns))
+		case !filepath.IsAbs(filepath.FromSlash(rel)) && rel != ".." && !strings.HasPrefix(rel, "../"):
+			fmt.Fprintf(sb, "from %s.%s import ALL_STRUCTS as %s_STRUCTS, ALL_ENUMS as %s_ENUMS\n", strings.ReplaceAll(rel, "/", "."), ns, strings.ToUpper(ns), strings.ToUpper(ns))
+		default:
+			if !pathDirs[rel] {
+				pathDirs[rel] = true
+				fmt.Fprintf(sb, "sys.path.insert(0, str(Path(__file__).parent / '%s'))\n", rel)
+			}
+			fmt.Fprintf(sb, "from %s import ALL_STRUCTS as %s_STRUCTS, ALL_ENUMS as %s_ENUMS\n", ns, strings.ToUpper(ns), strings.ToUpper(ns))
 		}
-		sb.WriteString("        ],\n")
-		sb.WriteString("    },\n")
 	}
-	sb.WriteString("}\n")
+	sb.WriteString("\n")
+	return namespaces
+}
 
-	return sb.String()
+// writePackageBootstrapPy writes the preamble used by generated test scripts in
+// package mode: it puts the parent of the package on sys.path so the scripts can
+// be run directly from any directory.
+func writePackageBootstrapPy(sb *strings.Builder) {
+	sb.WriteString("import os\n")
+	sb.WriteString("import sys\n")
+	sb.WriteString("sys.path.insert(0, os.path.dirname(os.path.dirname(os.path.abspath(__file__))))\n")
 }
 
 // writeTypeDict writes a type definition as a Python dict
@@ -212,702 +453,386 @@ func writeTypeDict(sb *strings.Builder, t *parser.Type) {
 	sb.WriteString("}")
 }
 
-// generateServerPy generates the server.py file with HTTP server and interface stubs
-func generateServerPy(idl *parser.IDL, _ map[string]*parser.Struct, _ map[string]*parser.Enum, _ map[string]*parser.Interface, namespaceMap map[string]*NamespaceTypes, baseDir string, outputDir string) string {
-	var sb strings.Builder
+// pyServerView is the view model for server.py
+type pyServerView struct {
+	// NamespaceImports are the imports of the runtime and the namespace modules
+	NamespaceImports string
+	// Registries are the prefixes of the namespaces' _STRUCTS and _ENUMS
+	Registries []string
+	// Interfaces are in parents-first order, as Python defines base classes first
+	Interfaces []pyInterfaceView
+	// DispatchMethod is the server method that handles one decoded request
+	DispatchMethod string
+	AdminPath      string
+	SubInterfaces  []subInterfaceView
+	RESTRoutes     []restRouteView
+	// ExtraParams are the trailing keyword arguments that only some servers take
+	ExtraParams         string
+	JobRetentionSeconds int
+
+	// Options and IDL features that add code to the server
+	Faults               bool
+	Admin                bool
+	AsyncJobs            bool
+	Cached               bool
+	Compressed           bool
+	LegacyEncodings      bool
+	EncryptedFields      bool
+	Idempotent           bool
+	Chunked              bool
+	ImmutableStructs     bool
+	InterfaceInheritance bool
+	OptionalParams       bool
+	WireNames            bool
+
+	// Sections written by the generators of optional features, empty when unused
+	LegacyBridge     string
+	WireMethods      string
+	Compression      string
+	Capabilities     string
+	EncryptedMethods string
+	DedupeMethods    string
+	AdminConstants   string
+	Compose          string
+	AdminServer      string
+	LegacyHandler    string
+	DedupeServer     string
+	CanonicalDumps   string
+	JobsServer       string
+	CacheHelpers     string
+}
 
-	sb.WriteString("# Generated by pulserpc - do not edit\n\n")
-	sb.WriteString("import abc\n")
-	sb.WriteString("import json\n")
-	sb.WriteString("import os\n")
-	sb.WriteString("import sys\n")
-	sb.WriteString("from http.server import HTTPServer, BaseHTTPRequestHandler\n")
-	sb.WriteString("from typing import Any, Dict, List, Optional\n")
-	sb.WriteString("from pathlib import Path\n\n")
-	sb.WriteString("from pulserpc import RPCError, validate_type\n")
+// pyInterfaceView is the view model for the abstract base class of an IDL interface
+type pyInterfaceView struct {
+	Name         string
+	CommentLines []string
+	// Bases are the extended interfaces, which declare the inherited methods, or abc.ABC
+	Bases   string
+	Doc     string
+	Methods []pyMethodView
+}
 
-	// Import from namespace modules
-	namespaces := make([]string, 0, len(namespaceMap))
-	for ns := range namespaceMap {
-		if ns != "" {
-			namespaces = append(namespaces, ns)
-		}
-	}
-	// Sort namespaces for consistent output
-	sort.Strings(namespaces)
+type pyMethodView struct {
+	Name   string
+	Params []string
+	// Chunked methods may return any iterable of the array's elements
+	Chunked bool
+}
 
-	// Calculate relative path from outputDir to baseDir for imports
-	if baseDir != outputDir {
-		relPath, err := filepath.Rel(outputDir, baseDir)
-		if err == nil && relPath != "." {
-			// Use relative import path
-			for _, ns := range namespaces {
-				importPath := filepath.ToSlash(filepath.Join(relPath, ns))
-				sb.WriteString(fmt.Sprintf("from %s import ALL_STRUCTS as %s_STRUCTS, ALL_ENUMS as %s_ENUMS\n", strings.ReplaceAll(importPath, "/", "."), strings.ToUpper(ns), strings.ToUpper(ns)))
-			}
-		} else {
-			// Fallback: add to sys.path
-			sb.WriteString(fmt.Sprintf("sys.path.insert(0, str(Path(__file__).parent / '%s'))\n", filepath.Base(baseDir)))
-			for _, ns := range namespaces {
-				sb.WriteString(fmt.Sprintf("from %s import ALL_STRUCTS as %s_STRUCTS, ALL_ENUMS as %s_ENUMS\n", ns, strings.ToUpper(ns), strings.ToUpper(ns)))
-			}
-		}
-	} else {
-		// Same directory - direct imports
-		for _, ns := range namespaces {
-			sb.WriteString(fmt.Sprintf("from %s import ALL_STRUCTS as %s_STRUCTS, ALL_ENUMS as %s_ENUMS\n", ns, strings.ToUpper(ns), strings.ToUpper(ns)))
-		}
+// newPyInterfaceView returns the view of the abstract base class of iface
+func newPyInterfaceView(iface *parser.Interface) pyInterfaceView {
+	view := pyInterfaceView{Name: iface.Name, Bases: "abc.ABC"}
+	if iface.Comment != "" {
+		view.CommentLines = strings.Split(strings.TrimSpace(iface.Comment), "\n")
+		view.Doc = strings.TrimSpace(iface.Comment)
 	}
-	sb.WriteString("\n")
-
-	// Merge ALL_STRUCTS and ALL_ENUMS from all namespaces
-	sb.WriteString("# Merge ALL_STRUCTS and ALL_ENUMS from all namespaces\n")
-	sb.WriteString("ALL_STRUCTS = {}\n")
-	for _, ns := range namespaces {
-		sb.WriteString(fmt.Sprintf("ALL_STRUCTS.update(%s_STRUCTS)\n", strings.ToUpper(ns)))
+	if len(iface.Extends) > 0 {
+		view.Bases = strings.Join(iface.Extends, ", ")
 	}
-	sb.WriteString("\n")
-	sb.WriteString("ALL_ENUMS = {}\n")
-	for _, ns := range namespaces {
-		sb.WriteString(fmt.Sprintf("ALL_ENUMS.update(%s_ENUMS)\n", strings.ToUpper(ns)))
+	for _, method := range iface.OwnMethods() {
+		mv := pyMethodView{Name: method.Name, Chunked: method.IsChunked()}
+		for _, param := range method.Parameters {
+			mv.Params = append(mv.Params, param.Name)
+		}
+		view.Methods = append(view.Methods, mv)
 	}
-	sb.WriteString("\n")
-
-	// Generate interface stub classes
-	for _, iface := range idl.Interfaces {
-		writeInterfaceStub(&sb, iface)
-	}
-
-	// Generate PulseRPCServer class
-	sb.WriteString("class PulseRPCServer:\n")
-	sb.WriteString("    \"\"\"HTTP server for JSON-RPC 2.0 requests using Python's built-in http.server\"\"\"\n\n")
-	sb.WriteString("    def __init__(self, host: str = 'localhost', port: int = 8080):\n")
-	sb.WriteString("        self.host = host\n")
-	sb.WriteString("        self.port = port\n")
-	sb.WriteString("        self.handlers: Dict[str, Any] = {}\n")
-	sb.WriteString("        self._server: Optional[HTTPServer] = None\n\n")
-
-	sb.WriteString("    def register(self, interface_name: str, instance: Any) -> None:\n")
-	sb.WriteString("        \"\"\"Register an interface implementation instance\"\"\"\n")
-	sb.WriteString("        self.handlers[interface_name] = instance\n\n")
-
-	// Generate handler class
-	sb.WriteString("    def _create_handler_class(self):\n")
-	sb.WriteString("        handlers = self.handlers\n")
-	sb.WriteString("        server_instance = self\n\n")
-	sb.WriteString("        class PulseRPCHandler(BaseHTTPRequestHandler):\n")
-	sb.WriteString("            def do_POST(self):\n")
-	sb.WriteString("                # Read request body\n")
-	sb.WriteString("                content_length = int(self.headers.get('Content-Length', 0))\n")
-	sb.WriteString("                if content_length == 0:\n")
-	sb.WriteString("                    self._send_error_response(None, -32700, \"Parse error\", \"Empty request body\")\n")
-	sb.WriteString("                    return\n\n")
-	sb.WriteString("                body = self.rfile.read(content_length)\n")
-	sb.WriteString("                \n")
-	sb.WriteString("                try:\n")
-	sb.WriteString("                    data = json.loads(body.decode('utf-8'))\n")
-	sb.WriteString("                except (json.JSONDecodeError, UnicodeDecodeError) as e:\n")
-	sb.WriteString("                    self._send_error_response(None, -32700, \"Parse error\", f\"Invalid JSON: {e}\")\n")
-	sb.WriteString("                    return\n\n")
-	sb.WriteString("                # Handle batch requests\n")
-	sb.WriteString("                if isinstance(data, list):\n")
-	sb.WriteString("                    if len(data) == 0:\n")
-	sb.WriteString("                        self._send_error_response(None, -32600, \"Invalid Request\", \"Empty batch array\")\n")
-	sb.WriteString("                        return\n")
-	sb.WriteString("                    responses = []\n")
-	sb.WriteString("                    for req in data:\n")
-	sb.WriteString("                        response = server_instance.handle_request(req)\n")
-	sb.WriteString("                        if response is not None:\n")
-	sb.WriteString("                            responses.append(response)\n")
-	sb.WriteString("                    if len(responses) == 0:\n")
-	sb.WriteString("                        self._send_response(204, b'')\n")
-	sb.WriteString("                    else:\n")
-	sb.WriteString("                        self._send_json_response(200, responses)\n")
-	sb.WriteString("                else:\n")
-	sb.WriteString("                    response = server_instance.handle_request(data)\n")
-	sb.WriteString("                    if response is None:\n")
-	sb.WriteString("                        self._send_response(204, b'')\n")
-	sb.WriteString("                    else:\n")
-	sb.WriteString("                        self._send_json_response(200, response)\n\n")
-
-	sb.WriteString("            def _send_json_response(self, status: int, data: Any) -> None:\n")
-	sb.WriteString("                \"\"\"Send a JSON response\"\"\"\n")
-	sb.WriteString("                response_body = json.dumps(data).encode('utf-8')\n")
-	sb.WriteString("                self.send_response(status)\n")
-	sb.WriteString("                self.send_header('Content-Type', 'application/json')\n")
-	sb.WriteString("                self.send_header('Content-Length', str(len(response_body)))\n")
-	sb.WriteString("                self.end_headers()\n")
-	sb.WriteString("                self.wfile.write(response_body)\n\n")
-
-	sb.WriteString("            def _send_response(self, status: int, body: bytes) -> None:\n")
-	sb.WriteString("                \"\"\"Send a response with raw body\"\"\"\n")
-	sb.WriteString("                self.send_response(status)\n")
-	sb.WriteString("                if len(body) > 0:\n")
-	sb.WriteString("                    self.send_header('Content-Length', str(len(body)))\n")
-	sb.WriteString("                self.end_headers()\n")
-	sb.WriteString("                if len(body) > 0:\n")
-	sb.WriteString("                    self.wfile.write(body)\n\n")
-
-	sb.WriteString("            def _send_error_response(self, request_id: Any, code: int, message: str, data: Any = None) -> None:\n")
-	sb.WriteString("                \"\"\"Send a JSON-RPC 2.0 error response\"\"\"\n")
-	sb.WriteString("                error = {'code': code, 'message': message}\n")
-	sb.WriteString("                if data is not None:\n")
-	sb.WriteString("                    error['data'] = data\n")
-	sb.WriteString("                response = {'jsonrpc': '2.0', 'error': error, 'id': request_id}\n")
-	sb.WriteString("                self._send_json_response(200, response)\n\n")
-
-	sb.WriteString("            def log_message(self, format: str, *args: Any) -> None:\n")
-	sb.WriteString("                \"\"\"Override to customize logging if needed\"\"\"\n")
-	sb.WriteString("                # Suppress default logging, or customize as needed\n")
-	sb.WriteString("                pass\n\n")
-
-	sb.WriteString("        return PulseRPCHandler\n\n")
-
-	sb.WriteString("    def handle_request(self, request_json: Dict[str, Any]) -> Optional[Dict[str, Any]]:\n")
-	sb.WriteString("        \"\"\"Handle a single JSON-RPC 2.0 request\"\"\"\n")
-	sb.WriteString("        # Validate JSON-RPC 2.0 structure\n")
-	sb.WriteString("        if not isinstance(request_json, dict):\n")
-	sb.WriteString("            return self._error_response(None, -32600, \"Invalid Request\", \"Request must be an object\")\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        jsonrpc = request_json.get('jsonrpc')\n")
-	sb.WriteString("        if jsonrpc != '2.0':\n")
-	sb.WriteString("            return self._error_response(None, -32600, \"Invalid Request\", \"jsonrpc must be '2.0'\")\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        method = request_json.get('method')\n")
-	sb.WriteString("        if not isinstance(method, str):\n")
-	sb.WriteString("            return self._error_response(None, -32600, \"Invalid Request\", \"method must be a string\")\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        params = request_json.get('params')\n")
-	sb.WriteString("        request_id = request_json.get('id')\n")
-	sb.WriteString("        is_notification = 'id' not in request_json\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        # Special case: pulserpc-idl method returns the IDL JSON document\n")
-	sb.WriteString("        if method == \"pulserpc-idl\":\n")
-	sb.WriteString("            try:\n")
-	sb.WriteString("                # Get the directory where server.py is located\n")
-	sb.WriteString("                server_dir = os.path.dirname(os.path.abspath(__file__))\n")
-	sb.WriteString("                idl_json_path = os.path.join(server_dir, \"idl.json\")\n")
-	sb.WriteString("                \n")
-	sb.WriteString("                with open(idl_json_path, 'r', encoding='utf-8') as f:\n")
-	sb.WriteString("                    idl_doc = json.load(f)\n")
-	sb.WriteString("                \n")
-	sb.WriteString("                # Return success response\n")
-	sb.WriteString("                if is_notification:\n")
-	sb.WriteString("                    return None\n")
-	sb.WriteString("                return {\n")
-	sb.WriteString("                    'jsonrpc': '2.0',\n")
-	sb.WriteString("                    'result': idl_doc,\n")
-	sb.WriteString("                    'id': request_id\n")
-	sb.WriteString("                }\n")
-	sb.WriteString("            except FileNotFoundError:\n")
-	sb.WriteString("                return self._error_response(request_id, -32603, \"Internal error\", \"IDL JSON file not found\")\n")
-	sb.WriteString("            except json.JSONDecodeError as e:\n")
-	sb.WriteString("                return self._error_response(request_id, -32603, \"Internal error\", f\"Failed to parse IDL JSON: {e}\")\n")
-	sb.WriteString("            except Exception as e:\n")
-	sb.WriteString("                return self._error_response(request_id, -32603, \"Internal error\", f\"Failed to load IDL JSON: {e}\")\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        # Parse method name: interface.method\n")
-	sb.WriteString("        parts = method.split('.', 1)\n")
-	sb.WriteString("        if len(parts) != 2:\n")
-	sb.WriteString("            return self._error_response(request_id, -32601, \"Method not found\", f\"Invalid method format: {method}\")\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        interface_name, method_name = parts\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        # Find handler\n")
-	sb.WriteString("        handler = self.handlers.get(interface_name)\n")
-	sb.WriteString("        if handler is None:\n")
-	sb.WriteString("            return self._error_response(request_id, -32601, \"Method not found\", f\"Interface '{interface_name}' not registered\")\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        # Find method on handler\n")
-	sb.WriteString("        if not hasattr(handler, method_name):\n")
-	sb.WriteString("            return self._error_response(request_id, -32601, \"Method not found\", f\"Method '{method_name}' not found on interface '{interface_name}'\")\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        method_func = getattr(handler, method_name)\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        # Find interface and method definition\n")
-	writeInterfaceMethodLookup(&sb, idl.Interfaces)
-	sb.WriteString("        \n")
-	sb.WriteString("        if method_def is None:\n")
-	sb.WriteString("            return self._error_response(request_id, -32601, \"Method not found\", f\"Method '{method_name}' not found in interface '{interface_name}'\")\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        # Validate params\n")
-	sb.WriteString("        if params is None:\n")
-	sb.WriteString("            params = []\n")
-	sb.WriteString("        if not isinstance(params, list):\n")
-	sb.WriteString("            return self._error_response(request_id, -32602, \"Invalid params\", \"params must be an array\")\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        # Validate param count\n")
-	sb.WriteString("        expected_params = method_def.get('parameters', [])\n")
-	sb.WriteString("        if len(params) != len(expected_params):\n")
-	sb.WriteString("            return self._error_response(request_id, -32602, \"Invalid params\", f\"Expected {len(expected_params)} parameters, got {len(params)}\")\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        # Validate each param\n")
-	sb.WriteString("        for i, (param_value, param_def) in enumerate(zip(params, expected_params)):\n")
-	sb.WriteString("            try:\n")
-	sb.WriteString("                validate_type(param_value, param_def['type'], ALL_STRUCTS, ALL_ENUMS, False)\n")
-	sb.WriteString("            except Exception as e:\n")
-	sb.WriteString("                return self._error_response(request_id, -32602, \"Invalid params\", f\"Parameter {i} ({param_def['name']}) validation failed: {e}\")\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        # Invoke handler\n")
-	sb.WriteString("        try:\n")
-	sb.WriteString("            result = method_func(*params)\n")
-	sb.WriteString("        except RPCError as e:\n")
-	sb.WriteString("            return self._error_response(request_id, e.code, e.message, e.data)\n")
-	sb.WriteString("        except Exception as e:\n")
-	sb.WriteString("            return self._error_response(request_id, -32603, \"Internal error\", str(e))\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        # Validate response\n")
-	sb.WriteString("        return_type = method_def.get('returnType')\n")
-	sb.WriteString("        return_optional = method_def.get('returnOptional', False)\n")
-	sb.WriteString("        if return_type:\n")
-	sb.WriteString("            try:\n")
-	sb.WriteString("                validate_type(result, return_type, ALL_STRUCTS, ALL_ENUMS, return_optional)\n")
-	sb.WriteString("            except Exception as e:\n")
-	sb.WriteString("                return self._error_response(request_id, -32603, \"Internal error\", f\"Response validation failed: {e}\")\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        # Return success response\n")
-	sb.WriteString("        if is_notification:\n")
-	sb.WriteString("            return None\n")
-	sb.WriteString("        return {\n")
-	sb.WriteString("            'jsonrpc': '2.0',\n")
-	sb.WriteString("            'result': result,\n")
-	sb.WriteString("            'id': request_id\n")
-	sb.WriteString("        }\n\n")
-
-	sb.WriteString("    def _error_response(self, request_id: Any, code: int, message: str, data: Any = None) -> Dict[str, Any]:\n")
-	sb.WriteString("        \"\"\"Create a JSON-RPC 2.0 error response\"\"\"\n")
-	sb.WriteString("        error = {\n")
-	sb.WriteString("            'code': code,\n")
-	sb.WriteString("            'message': message\n")
-	sb.WriteString("        }\n")
-	sb.WriteString("        if data is not None:\n")
-	sb.WriteString("            error['data'] = data\n")
-	sb.WriteString("        return {\n")
-	sb.WriteString("            'jsonrpc': '2.0',\n")
-	sb.WriteString("            'error': error,\n")
-	sb.WriteString("            'id': request_id\n")
-	sb.WriteString("        }\n\n")
-
-	sb.WriteString("    def serve_forever(self) -> None:\n")
-	sb.WriteString("        \"\"\"Start the HTTP server and serve forever\"\"\"\n")
-	sb.WriteString("        handler_class = self._create_handler_class()\n")
-	sb.WriteString("        self._server = HTTPServer((self.host, self.port), handler_class)\n")
-	sb.WriteString("        print(f\"PulseRPC server listening on http://{self.host}:{self.port}\")\n")
-	sb.WriteString("        self._server.serve_forever()\n\n")
-
-	sb.WriteString("    def shutdown(self) -> None:\n")
-	sb.WriteString("        \"\"\"Shutdown the HTTP server\"\"\"\n")
-	sb.WriteString("        if self._server:\n")
-	sb.WriteString("            self._server.shutdown()\n")
-
-	return sb.String()
+	return view
 }
 
-// generateClientPy generates the client.py file with transport abstraction and client classes
-func generateClientPy(idl *parser.IDL, _ map[string]*parser.Struct, _ map[string]*parser.Enum, _ map[string]*parser.Interface, namespaceMap map[string]*NamespaceTypes, baseDir string, outputDir string) string {
-	var sb strings.Builder
-
-	sb.WriteString("# Generated by pulserpc - do not edit\n\n")
-	sb.WriteString("from abc import ABC, abstractmethod\n")
-	sb.WriteString("from typing import Dict, Any, Optional, List\n")
-	sb.WriteString("import json\n")
-	sb.WriteString("import sys\n")
-	sb.WriteString("import urllib.request\n")
-	sb.WriteString("import urllib.error\n")
-	sb.WriteString("import uuid\n")
-	sb.WriteString("from pathlib import Path\n\n")
-	sb.WriteString("from pulserpc import RPCError, validate_type\n")
-
-	// Import from namespace modules
-	namespaces := make([]string, 0, len(namespaceMap))
-	for ns := range namespaceMap {
-		if ns != "" {
-			namespaces = append(namespaces, ns)
-		}
+// generateServerPy generates the server.py file with HTTP server and interface stubs
+func generateServerPy(ts *templateSet, idl *parser.IDL, namespaceMap map[string]*NamespaceTypes, nsDirs namespaceDirs, outputDir string, packaged bool, faults bool, admin bool, idlDoc idlJSONDocument) string {
+	interfaces := idl.Interfaces
+	view := pyServerView{
+		DispatchMethod:       "handle_request",
+		AdminPath:            adminPath,
+		RESTRoutes:           newRESTRouteViews(interfaces, writeTypeDict),
+		JobRetentionSeconds:  jobRetention * 60,
+		Faults:               faults,
+		Admin:                admin,
+		AsyncJobs:            usesAsyncMethods(interfaces),
+		Cached:               usesCachedMethods(interfaces),
+		Compressed:           usesCompressedMethods(interfaces),
+		LegacyEncodings:      usesLegacyEncodings(interfaces),
+		EncryptedFields:      usesEncryptedFields(idl),
+		Idempotent:           usesIdempotentMethods(interfaces),
+		Chunked:              usesChunkedMethods(interfaces),
+		ImmutableStructs:     usesImmutableStructs(idl.Structs),
+		InterfaceInheritance: usesInterfaceInheritance(interfaces),
+		OptionalParams:       usesOptionalParams(interfaces),
+		WireNames:            usesWireNames(interfaces),
 	}
-	// Sort namespaces for consistent output
-	sort.Strings(namespaces)
 
-	// Calculate relative path from outputDir to baseDir for imports
-	if baseDir != outputDir {
-		relPath, err := filepath.Rel(outputDir, baseDir)
-		if err == nil && relPath != "." {
-			// Use relative import path
-			for _, ns := range namespaces {
-				importPath := filepath.ToSlash(filepath.Join(relPath, ns))
-				sb.WriteString(fmt.Sprintf("from %s import ALL_STRUCTS as %s_STRUCTS, ALL_ENUMS as %s_ENUMS\n", strings.ReplaceAll(importPath, "/", "."), strings.ToUpper(ns), strings.ToUpper(ns)))
-			}
-		} else {
-			// Fallback: add to sys.path
-			sb.WriteString(fmt.Sprintf("sys.path.insert(0, str(Path(__file__).parent / '%s'))\n", filepath.Base(baseDir)))
-			for _, ns := range namespaces {
-				sb.WriteString(fmt.Sprintf("from %s import ALL_STRUCTS as %s_STRUCTS, ALL_ENUMS as %s_ENUMS\n", ns, strings.ToUpper(ns), strings.ToUpper(ns)))
-			}
-		}
-	} else {
-		// Same directory - direct imports
-		for _, ns := range namespaces {
-			sb.WriteString(fmt.Sprintf("from %s import ALL_STRUCTS as %s_STRUCTS, ALL_ENUMS as %s_ENUMS\n", ns, strings.ToUpper(ns), strings.ToUpper(ns)))
-		}
+	runtimeNames := []string{"Composition", "DEADLINE_HEADER", "LENIENT", "STRICT", "canonical_json", "check_int_literals", "deadline_scope", "normalize_ints"}
+	if view.EncryptedFields {
+		runtimeNames = append(runtimeNames, encryptionRuntimeNamesPy...)
 	}
-	sb.WriteString("\n")
-
-	// Merge ALL_STRUCTS and ALL_ENUMS from all namespaces
-	sb.WriteString("# Merge ALL_STRUCTS and ALL_ENUMS from all namespaces\n")
-	sb.WriteString("ALL_STRUCTS = {}\n")
-	for _, ns := range namespaces {
-		sb.WriteString(fmt.Sprintf("ALL_STRUCTS.update(%s_STRUCTS)\n", strings.ToUpper(ns)))
+	if faults {
+		runtimeNames = append(runtimeNames, "FaultConfig")
 	}
-	sb.WriteString("\n")
-	sb.WriteString("ALL_ENUMS = {}\n")
+	if view.Idempotent {
+		runtimeNames = append(runtimeNames, "InFlight", "request_hash")
+	}
+	if admin {
+		runtimeNames = append(runtimeNames, "MethodMetrics")
+	}
+	if view.ImmutableStructs {
+		runtimeNames = append(runtimeNames, "plain_value")
+	}
+	var namespaces []string
+	view.NamespaceImports = capture(func(sb *strings.Builder) {
+		namespaces = writeNamespaceImportsPy(sb, namespaceMap, nsDirs, outputDir, packaged, runtimeNames)
+	})
 	for _, ns := range namespaces {
-		sb.WriteString(fmt.Sprintf("ALL_ENUMS.update(%s_ENUMS)\n", strings.ToUpper(ns)))
+		view.Registries = append(view.Registries, strings.ToUpper(ns))
+	}
+	for _, iface := range interfacesParentsFirst(interfaces) {
+		view.Interfaces = append(view.Interfaces, newPyInterfaceView(iface))
+	}
+	for _, inh := range subInterfaces(interfaces) {
+		view.SubInterfaces = append(view.SubInterfaces, subInterfaceView{Name: inh.Name, Subs: "'" + strings.Join(inh.Subs, "', '") + "'"})
 	}
-	sb.WriteString("\n")
-
-	// Generate Transport ABC
-	writeTransportABC(&sb)
-
-	// Generate HTTPTransport
-	writeHTTPTransport(&sb)
 
-	// Generate client classes for each interface
-	for _, iface := range idl.Interfaces {
-		writeInterfaceClient(&sb, iface, idl.Interfaces)
+	// Trailing keyword arguments that only some servers take
+	var extraParams []string
+	if view.EncryptedFields {
+		extraParams = append(extraParams, "field_cipher: Optional[FieldCipher] = None")
+	}
+	if view.Idempotent {
+		extraParams = append(extraParams, "deduplicate_in_flight: bool = False")
+	}
+	view.ExtraParams = strings.Join(extraParams, ", ")
+
+	view.Capabilities = capture(func(sb *strings.Builder) { writeServerCapabilitiesPy(sb, interfaces) })
+	view.Compose = capture(func(sb *strings.Builder) { writeComposeServerPy(sb, interfaces, idlDoc) })
+	view.CanonicalDumps = capture(func(sb *strings.Builder) { writeCanonicalDumpsPy(sb, "a response") })
+	if view.Idempotent {
+		// Calls from HTTP and handle_message go through deduplication
+		view.DispatchMethod = "_handle_deduplicated"
+		view.DedupeMethods = capture(func(sb *strings.Builder) { writeDedupeMethodsPy(sb, idl) })
+		view.DedupeServer = capture(writeDedupeServerPy)
+	}
+	if view.LegacyEncodings {
+		view.LegacyBridge = capture(func(sb *strings.Builder) { writeLegacyBridgePy(sb, interfaces) })
+		view.LegacyHandler = capture(func(sb *strings.Builder) { writeLegacyHandlerPy(sb, view.DispatchMethod) })
+	}
+	if view.WireNames {
+		view.WireMethods = capture(func(sb *strings.Builder) { writeWireMethodsPy(sb, interfaces) })
+	}
+	if view.Compressed {
+		view.Compression = capture(func(sb *strings.Builder) { writeCompressionPy(sb, interfaces) })
+	}
+	if view.EncryptedFields {
+		view.EncryptedMethods = capture(func(sb *strings.Builder) { writeEncryptedMethodsPy(sb, idl) })
+	}
+	if admin {
+		view.AdminConstants = capture(func(sb *strings.Builder) { writeAdminConstantsPy(sb, idl) })
+		view.AdminServer = capture(writeAdminServerPy)
+	}
+	if view.AsyncJobs {
+		view.JobsServer = capture(writeJobsServerPy)
 	}
+	if view.Cached {
+		view.CacheHelpers = capture(writeCacheHelpersPy)
+	}
+	return ts.renderString("python/server.py.tmpl", view)
+}
 
-	return sb.String()
+// pyClientView is the view model for client.py
+type pyClientView struct {
+	// NamespaceImports are the imports of the runtime and the namespace modules
+	NamespaceImports string
+	// Registries are the prefixes of the namespaces' _STRUCTS and _ENUMS
+	Registries []string
+	Interfaces []pyClientInterfaceView
+
+	// IDL features that add code to the client
+	Cached     bool
+	Compressed bool
+
+	// Sections written by the generators of optional features, empty when unused
+	JobsClient            string
+	CachedMethods         string
+	DecodeBody            string
+	LogCall               string
+	LoggedCall            string
+	CanonicalDumps        string
+	CallBatch             string
+	CapabilitiesTransport string
+	ConditionalGet        string
+	CapabilitiesClient    string
+	BatchClient           string
+	ErrorDataClasses      string
+	APIClient             string
 }
 
-// writeTransportABC generates the Transport abstract base class
-func writeTransportABC(sb *strings.Builder) {
-	sb.WriteString("class Transport(ABC):\n")
-	sb.WriteString("    \"\"\"Abstract base class for transport implementations.\n")
-	sb.WriteString("    \n")
-	sb.WriteString("    Transports handle the roundtrip of sending requests to the server\n")
-	sb.WriteString("    and decoding responses. Different transports can use different\n")
-	sb.WriteString("    protocols (HTTP, ZeroMQ, etc.) and serialization formats (JSON, MessagePack, etc.).\n")
-	sb.WriteString("    \"\"\"\n\n")
-	sb.WriteString("    @abstractmethod\n")
-	sb.WriteString("    def call(self, method: str, params: list) -> dict:\n")
-	sb.WriteString("        \"\"\"Perform a JSON-RPC 2.0 call and return the response.\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        Args:\n")
-	sb.WriteString("            method: The method name in format 'interface.method'\n")
-	sb.WriteString("            params: List of parameters to pass to the method\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        Returns:\n")
-	sb.WriteString("            dict: The JSON-RPC 2.0 response dictionary\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        Raises:\n")
-	sb.WriteString("            RPCError: If the JSON-RPC call returns an error\n")
-	sb.WriteString("            Exception: For transport-level errors (network, etc.)\n")
-	sb.WriteString("        \"\"\"\n")
-	sb.WriteString("        pass\n\n\n")
+// pyClientInterfaceView is the view model for the client class of an IDL interface
+type pyClientInterfaceView struct {
+	Name         string
+	CommentLines []string
+	Doc          string
+	// Encrypted clients take a field cipher for the [encrypted] fields of their calls
+	Encrypted bool
+	Methods   []pyClientMethodView
 }
 
-// writeHTTPTransport generates the HTTPTransport class
-func writeHTTPTransport(sb *strings.Builder) {
-	sb.WriteString("class HTTPTransport(Transport):\n")
-	sb.WriteString("    \"\"\"HTTP transport implementation using JSON-RPC 2.0 over HTTP.\n")
-	sb.WriteString("    \n")
-	sb.WriteString("    Uses Python's standard library urllib.request for HTTP requests.\n")
-	sb.WriteString("    Supports configurable headers for authentication and other purposes.\n")
-	sb.WriteString("    \"\"\"\n\n")
-	sb.WriteString("    def __init__(self, base_url: str, headers: Optional[Dict[str, str]] = None):\n")
-	sb.WriteString("        \"\"\"Initialize HTTP transport.\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        Args:\n")
-	sb.WriteString("            base_url: Base URL of the server (e.g., 'http://localhost:8080')\n")
-	sb.WriteString("            headers: Optional dictionary of HTTP headers to include with each request\n")
-	sb.WriteString("        \"\"\"\n")
-	sb.WriteString("        self.base_url = base_url.rstrip('/')\n")
-	sb.WriteString("        self.headers = headers.copy() if headers else {}\n\n")
-	sb.WriteString("    def call(self, method: str, params: list) -> dict:\n")
-	sb.WriteString("        \"\"\"Perform a JSON-RPC 2.0 call over HTTP.\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        Args:\n")
-	sb.WriteString("            method: The method name in format 'interface.method'\n")
-	sb.WriteString("            params: List of parameters to pass to the method\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        Returns:\n")
-	sb.WriteString("            dict: The JSON-RPC 2.0 response dictionary\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        Raises:\n")
-	sb.WriteString("            RPCError: If the JSON-RPC call returns an error\n")
-	sb.WriteString("            urllib.error.HTTPError: For HTTP errors\n")
-	sb.WriteString("            urllib.error.URLError: For network errors\n")
-	sb.WriteString("        \"\"\"\n")
-	sb.WriteString("        # Generate request ID\n")
-	sb.WriteString("        request_id = str(uuid.uuid4())\n\n")
-	sb.WriteString("        # Build JSON-RPC 2.0 request\n")
-	sb.WriteString("        request_data = {\n")
-	sb.WriteString("            'jsonrpc': '2.0',\n")
-	sb.WriteString("            'method': method,\n")
-	sb.WriteString("            'params': params,\n")
-	sb.WriteString("            'id': request_id\n")
-	sb.WriteString("        }\n\n")
-	sb.WriteString("        # Serialize to JSON\n")
-	sb.WriteString("        json_data = json.dumps(request_data).encode('utf-8')\n\n")
-	sb.WriteString("        # Prepare request\n")
-	sb.WriteString("        req = urllib.request.Request(self.base_url, data=json_data, method='POST')\n")
-	sb.WriteString("        req.add_header('Content-Type', 'application/json')\n")
-	sb.WriteString("        req.add_header('Content-Length', str(len(json_data)))\n\n")
-	sb.WriteString("        # Add custom headers\n")
-	sb.WriteString("        for key, value in self.headers.items():\n")
-	sb.WriteString("            req.add_header(key, value)\n\n")
-	sb.WriteString("        try:\n")
-	sb.WriteString("            # Send request\n")
-	sb.WriteString("            with urllib.request.urlopen(req) as response:\n")
-	sb.WriteString("                response_body = response.read().decode('utf-8')\n")
-	sb.WriteString("                response_data = json.loads(response_body)\n\n")
-	sb.WriteString("                # Check for JSON-RPC error\n")
-	sb.WriteString("                if 'error' in response_data:\n")
-	sb.WriteString("                    error = response_data['error']\n")
-	sb.WriteString("                    code = error.get('code', -32603)\n")
-	sb.WriteString("                    message = error.get('message', 'Internal error')\n")
-	sb.WriteString("                    data = error.get('data')\n")
-	sb.WriteString("                    raise RPCError(code, message, data)\n\n")
-	sb.WriteString("                # Return response\n")
-	sb.WriteString("                return response_data\n\n")
-	sb.WriteString("        except urllib.error.HTTPError as e:\n")
-	sb.WriteString("            # Try to parse error response as JSON-RPC\n")
-	sb.WriteString("            try:\n")
-	sb.WriteString("                error_body = e.read().decode('utf-8')\n")
-	sb.WriteString("                error_data = json.loads(error_body)\n")
-	sb.WriteString("                if 'error' in error_data:\n")
-	sb.WriteString("                    error = error_data['error']\n")
-	sb.WriteString("                    code = error.get('code', -32603)\n")
-	sb.WriteString("                    message = error.get('message', 'Internal error')\n")
-	sb.WriteString("                    data = error.get('data')\n")
-	sb.WriteString("                    raise RPCError(code, message, data)\n")
-	sb.WriteString("            except (json.JSONDecodeError, UnicodeDecodeError):\n")
-	sb.WriteString("                pass\n")
-	sb.WriteString("            # If not JSON-RPC error, raise HTTP error\n")
-	sb.WriteString("            raise RPCError(-32603, f\"HTTP error: {e.code} {e.reason}\", None)\n")
-	sb.WriteString("        except urllib.error.URLError as e:\n")
-	sb.WriteString("            raise RPCError(-32603, f\"Network error: {e.reason}\", None)\n\n\n")
+// pyClientMethodView is the view model for a method of a client class
+type pyClientMethodView struct {
+	Name      string
+	Interface string
+	RPCName   string
+	Params    []pyClientParamView
+	// ParamNames is a quoted list of the parameter names, e.g. 'id', 'limit'
+	ParamNames string
+	// Indent aligns the continuation lines of the signature
+	Indent         string
+	Async          bool
+	OptionalParams bool
+	// PlainParams reports that a parameter can be an [immutable] struct's dataclass
+	PlainParams   bool
+	EncryptParams bool
+	DecryptResult bool
+	// ErrorClass is the RPCError subclass of the method's [errordata] struct, if any
+	ErrorClass string
 }
 
-// writeInterfaceClient generates a client class for an interface
-func writeInterfaceClient(sb *strings.Builder, iface *parser.Interface, _ []*parser.Interface) {
-	// Write interface comment if present
-	if iface.Comment != "" {
-		lines := strings.Split(strings.TrimSpace(iface.Comment), "\n")
-		for _, line := range lines {
-			fmt.Fprintf(sb, "# %s\n", line)
-		}
-	}
+type pyClientParamView struct {
+	Name     string
+	Optional bool
+	// DocSuffix follows the parameter's description in the docstring
+	DocSuffix string
+}
 
-	clientClassName := iface.Name + "Client"
-	fmt.Fprintf(sb, "class %s:\n", clientClassName)
+// newPyClientInterfaceView returns the view of the client class of iface
+func newPyClientInterfaceView(iface *parser.Interface, structMap map[string]*parser.Struct) pyClientInterfaceView {
+	view := pyClientInterfaceView{Name: iface.Name, Encrypted: interfaceNeedsEncryption(iface, structMap)}
 	if iface.Comment != "" {
-		fmt.Fprintf(sb, "    \"\"\"Client for %s interface.\n\n", iface.Name)
-		fmt.Fprintf(sb, "    %s\n", strings.TrimSpace(iface.Comment))
-		sb.WriteString("    \"\"\"\n\n")
-	} else {
-		fmt.Fprintf(sb, "    \"\"\"Client for %s interface.\"\"\"\n\n", iface.Name)
+		view.CommentLines = strings.Split(strings.TrimSpace(iface.Comment), "\n")
+		view.Doc = strings.TrimSpace(iface.Comment)
 	}
-
-	sb.WriteString("    def __init__(self, transport: Transport):\n")
-	sb.WriteString("        \"\"\"Initialize client with a transport.\n\n")
-	sb.WriteString("        Args:\n")
-	sb.WriteString("            transport: Transport instance to use for RPC calls\n")
-	sb.WriteString("        \"\"\"\n")
-	sb.WriteString("        self.transport = transport\n\n")
-
-	// Generate method lookup for this interface
-	sb.WriteString("        # Method definitions for validation\n")
-	sb.WriteString("        self._method_defs = {\n")
 	for _, method := range iface.Methods {
-		fmt.Fprintf(sb, "            '%s': {\n", method.Name)
-		sb.WriteString("                'parameters': [\n")
-		for _, param := range method.Parameters {
-			sb.WriteString("                    {\n")
-			fmt.Fprintf(sb, "                        'name': '%s',\n", param.Name)
-			sb.WriteString("                        'type': ")
-			writeTypeDict(sb, param.Type)
-			sb.WriteString(",\n")
-			sb.WriteString("                    },\n")
-		}
-		sb.WriteString("                ],\n")
-		sb.WriteString("                'returnType': ")
-		writeTypeDict(sb, method.ReturnType)
-		sb.WriteString(",\n")
-		if method.ReturnOptional {
-			sb.WriteString("                'returnOptional': True,\n")
-		} else {
-			sb.WriteString("                'returnOptional': False,\n")
-		}
-		sb.WriteString("            },\n")
-	}
-	sb.WriteString("        }\n\n")
-
-	// Generate methods
-	for _, method := range iface.Methods {
-		writeClientMethod(sb, iface, method)
+		view.Methods = append(view.Methods, newPyClientMethodView(iface, method, structMap))
 	}
-	sb.WriteString("\n")
+	return view
 }
 
-// writeClientMethod generates a method implementation for a client class
-func writeClientMethod(sb *strings.Builder, iface *parser.Interface, method *parser.Method) {
-	// Method signature
-	fmt.Fprintf(sb, "    def %s(self", method.Name)
-	for _, param := range method.Parameters {
-		fmt.Fprintf(sb, ", %s", param.Name)
+// newPyClientMethodView returns the view of the client method for method
+func newPyClientMethodView(iface *parser.Interface, method *parser.Method, structMap map[string]*parser.Struct) pyClientMethodView {
+	view := pyClientMethodView{
+		Name:           method.Name,
+		Interface:      iface.Name,
+		RPCName:        iface.RPCName(method),
+		Indent:         strings.Repeat(" ", len(method.Name)+9),
+		Async:          method.IsAsync(),
+		OptionalParams: method.RequiredParams() < len(method.Parameters),
+		EncryptParams:  paramsNeedEncryption(method, structMap),
+		DecryptResult:  typeNeedsEncryption(method.ReturnType, structMap),
 	}
-	sb.WriteString("):\n")
-
-	// Method docstring
-	if len(method.Parameters) > 0 {
-		sb.WriteString("        \"\"\"Call ")
-		fmt.Fprintf(sb, "%s.%s", iface.Name, method.Name)
-		sb.WriteString(".\n\n")
-		sb.WriteString("        Args:\n")
-		for _, param := range method.Parameters {
-			fmt.Fprintf(sb, "            %s: Parameter %s\n", param.Name, param.Name)
+	if name := method.ErrorData(); name != "" {
+		// Raise errors whose data is the [errordata] struct as its RPCError subclass
+		view.ErrorClass = errorDataClass(name)
+	}
+	names := make([]string, len(method.Parameters))
+	for i, param := range method.Parameters {
+		names[i] = "'" + param.Name + "'"
+		pv := pyClientParamView{Name: param.Name, Optional: param.Optional}
+		if value, ok := defaultPython(param); ok {
+			pv.DocSuffix = ", optional; the server uses " + value + " when None"
+		} else if param.Optional {
+			pv.DocSuffix = ", optional"
 		}
-		sb.WriteString("\n        Returns:\n")
-		sb.WriteString("            The method return value\n\n")
-		sb.WriteString("        Raises:\n")
-		sb.WriteString("            RPCError: If the RPC call fails\n")
-		sb.WriteString("        \"\"\"\n")
-	} else {
-		sb.WriteString("        \"\"\"Call ")
-		fmt.Fprintf(sb, "%s.%s", iface.Name, method.Name)
-		sb.WriteString(".\n\n")
-		sb.WriteString("        Returns:\n")
-		sb.WriteString("            The method return value\n\n")
-		sb.WriteString("        Raises:\n")
-		sb.WriteString("            RPCError: If the RPC call fails\n")
-		sb.WriteString("        \"\"\"\n")
-	}
-
-	// Get method definition
-	fmt.Fprintf(sb, "        method_def = self._method_defs['%s']\n", method.Name)
-	sb.WriteString("        params = [\n")
-	for _, param := range method.Parameters {
-		fmt.Fprintf(sb, "            %s,\n", param.Name)
-	}
-	sb.WriteString("        ]\n\n")
-
-	// Validate parameters
-	sb.WriteString("        # Validate parameters\n")
-	sb.WriteString("        expected_params = method_def.get('parameters', [])\n")
-	sb.WriteString("        for i, (param_value, param_def) in enumerate(zip(params, expected_params)):\n")
-	sb.WriteString("            try:\n")
-	sb.WriteString("                validate_type(param_value, param_def['type'], ALL_STRUCTS, ALL_ENUMS, False)\n")
-	sb.WriteString("            except Exception as e:\n")
-	sb.WriteString("                raise ValueError(f\"Parameter {i} ({param_def['name']}) validation failed: {e}\")\n\n")
-
-	// Call transport
-	fmt.Fprintf(sb, "        # Call transport\n")
-	fmt.Fprintf(sb, "        method_name = '%s.%s'\n", iface.Name, method.Name)
-	sb.WriteString("        response = self.transport.call(method_name, params)\n\n")
-
-	// Extract result
-	sb.WriteString("        # Extract result from JSON-RPC response\n")
-	sb.WriteString("        if 'error' in response:\n")
-	sb.WriteString("            error = response['error']\n")
-	sb.WriteString("            code = error.get('code', -32603)\n")
-	sb.WriteString("            message = error.get('message', 'Internal error')\n")
-	sb.WriteString("            data = error.get('data')\n")
-	sb.WriteString("            raise RPCError(code, message, data)\n\n")
-	sb.WriteString("        result = response.get('result')\n\n")
-
-	// Validate result
-	sb.WriteString("        # Validate result\n")
-	sb.WriteString("        return_type = method_def.get('returnType')\n")
-	sb.WriteString("        return_optional = method_def.get('returnOptional', False)\n")
-	sb.WriteString("        if return_type:\n")
-	sb.WriteString("            try:\n")
-	sb.WriteString("                validate_type(result, return_type, ALL_STRUCTS, ALL_ENUMS, return_optional)\n")
-	sb.WriteString("            except Exception as e:\n")
-	sb.WriteString("                raise ValueError(f\"Response validation failed: {e}\")\n\n")
-
-	// Return result
-	sb.WriteString("        return result\n\n")
+		view.Params = append(view.Params, pv)
+		if typeReachesImmutable(param.Type, structMap, map[string]bool{}) {
+			view.PlainParams = true
+		}
+	}
+	view.ParamNames = strings.Join(names, ", ")
+	return view
 }
 
-// writeInterfaceStub writes an abstract base class for an interface
-func writeInterfaceStub(sb *strings.Builder, iface *parser.Interface) {
-	if iface.Comment != "" {
-		lines := strings.Split(strings.TrimSpace(iface.Comment), "\n")
-		for _, line := range lines {
-			fmt.Fprintf(sb, "# %s\n", line)
-		}
+// generateClientPy generates the client.py file with transport abstraction and client classes
+func generateClientPy(ts *templateSet, idl *parser.IDL, structMap map[string]*parser.Struct, namespaceMap map[string]*NamespaceTypes, nsDirs namespaceDirs, outputDir string, packaged bool) string {
+	interfaces := idl.Interfaces
+	view := pyClientView{
+		Cached:                usesCachedMethods(interfaces),
+		Compressed:            usesCompressedMethods(interfaces),
+		LogCall:               capture(writeLogCallPy),
+		LoggedCall:            capture(writeLoggedCallPy),
+		CanonicalDumps:        capture(func(sb *strings.Builder) { writeCanonicalDumpsPy(sb, "a request body") }),
+		CallBatch:             capture(writeCallBatchPy),
+		CapabilitiesTransport: capture(writeCapabilitiesTransportPy),
+		CapabilitiesClient:    capture(writeCapabilitiesClientPy),
+		BatchClient:           capture(writeBatchClientPy),
 	}
-	fmt.Fprintf(sb, "class %s(abc.ABC):\n", iface.Name)
-	if iface.Comment != "" {
-		fmt.Fprintf(sb, "    \"\"\"%s\"\"\"\n", strings.TrimSpace(iface.Comment))
+
+	runtimeNames := []string{"DEADLINE_HEADER", "canonical_json", "deadline_header_value", "normalize_ints", "redact_value", "remaining_time"}
+	if usesEncryptedFields(idl) {
+		runtimeNames = append(runtimeNames, encryptionRuntimeNamesPy...)
+	}
+	if usesImmutableStructs(idl.Structs) {
+		runtimeNames = append(runtimeNames, "plain_value")
+	}
+	var namespaces []string
+	view.NamespaceImports = capture(func(sb *strings.Builder) {
+		namespaces = writeNamespaceImportsPy(sb, namespaceMap, nsDirs, outputDir, packaged, runtimeNames)
+	})
+	for _, ns := range namespaces {
+		view.Registries = append(view.Registries, strings.ToUpper(ns))
+	}
+	for _, iface := range interfaces {
+		view.Interfaces = append(view.Interfaces, newPyClientInterfaceView(iface, structMap))
 	}
-	sb.WriteString("\n")
 
-	for _, method := range iface.Methods {
-		sb.WriteString("    @abc.abstractmethod\n")
-		fmt.Fprintf(sb, "    def %s(self", method.Name)
-		for _, param := range method.Parameters {
-			fmt.Fprintf(sb, ", %s", param.Name)
-		}
-		sb.WriteString("):\n")
-		sb.WriteString("        pass\n\n")
+	if usesAsyncMethods(interfaces) {
+		view.JobsClient = capture(writeJobsClientPy)
 	}
-	sb.WriteString("\n")
+	if view.Cached {
+		view.CachedMethods = capture(func(sb *strings.Builder) { writeCachedMethodsPy(sb, interfaces) })
+		view.ConditionalGet = capture(writeConditionalGetPy)
+	}
+	if view.Compressed {
+		view.DecodeBody = capture(writeDecodeBodyPy)
+	}
+	if structs := errorDataStructs(interfaces); len(structs) > 0 {
+		view.ErrorDataClasses = capture(func(sb *strings.Builder) { writeErrorDataClassesPy(sb, structs) })
+	}
+	if usesAPIClientFacade(interfaces) {
+		view.APIClient = capture(func(sb *strings.Builder) { writeAPIClientPy(sb, interfaces) })
+	}
+	return ts.renderString("python/client.py.tmpl", view)
 }
 
-// writeInterfaceMethodLookup generates code to find method definitions
-func writeInterfaceMethodLookup(sb *strings.Builder, interfaces []*parser.Interface) {
-	sb.WriteString("        method_def = None\n")
-	sb.WriteString("        \n")
-	sb.WriteString("        # Interface method lookup\n")
-	for i, iface := range interfaces {
-		if i == 0 {
-			fmt.Fprintf(sb, "        if interface_name == '%s':\n", iface.Name)
-		} else {
-			fmt.Fprintf(sb, "        elif interface_name == '%s':\n", iface.Name)
-		}
-		sb.WriteString("            interface_methods = {\n")
-		for _, method := range iface.Methods {
-			fmt.Fprintf(sb, "                '%s': {\n", method.Name)
-			sb.WriteString("                    'parameters': [\n")
-			for _, param := range method.Parameters {
-				sb.WriteString("                        {\n")
-				fmt.Fprintf(sb, "                            'name': '%s',\n", param.Name)
-				sb.WriteString("                            'type': ")
-				writeTypeDict(sb, param.Type)
-				sb.WriteString(",\n")
-				sb.WriteString("                        },\n")
-			}
-			sb.WriteString("                    ],\n")
-			sb.WriteString("                    'returnType': ")
-			writeTypeDict(sb, method.ReturnType)
-			sb.WriteString(",\n")
-			if method.ReturnOptional {
-				sb.WriteString("                    'returnOptional': True,\n")
-			} else {
-				sb.WriteString("                    'returnOptional': False,\n")
-			}
-			sb.WriteString("                },\n")
-		}
-		sb.WriteString("            }\n")
-		sb.WriteString("            method_def = interface_methods.get(method_name)\n")
+// writeParamOptionsPy writes the optional flag and default of an optional
+// parameter as entries of its parameter definition
+func writeParamOptionsPy(sb *strings.Builder, indent string, param *parser.Parameter) {
+	if !param.Optional {
+		return
+	}
+	fmt.Fprintf(sb, "%s'optional': True,\n", indent)
+	if value, ok := defaultPython(param); ok {
+		fmt.Fprintf(sb, "%s'default': %s,\n", indent, value)
 	}
-	sb.WriteString("\n")
 }
 
 // generateTestServerPy generates test_server.py with concrete implementations of all interfaces
-func generateTestServerPy(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, _ map[string]*parser.Interface, _ map[string]*NamespaceTypes, _ string, _ string) string {
+func generateTestServerPy(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, _ map[string]*parser.Interface, _ map[string]*NamespaceTypes, packageName string, _ string, faults bool, admin bool) string {
 	var sb strings.Builder
 
 	sb.WriteString("# Generated by pulserpc - do not edit\n")
 	sb.WriteString("# Test server implementation for integration testing\n\n")
 	sb.WriteString("import math\n")
-	sb.WriteString("from server import PulseRPCServer\n")
+	if faults || admin {
+		sb.WriteString("import os\n")
+	}
+	serverModule := "server"
+	if packageName != "" {
+		writePackageBootstrapPy(&sb)
+		serverModule = packageName + ".server"
+	}
+	fmt.Fprintf(&sb, "from %s import PulseRPCServer\n", serverModule)
 
 	// Import interface stubs
 	for _, iface := range idl.Interfaces {
-		fmt.Fprintf(&sb, "from server import %s\n", iface.Name)
+		fmt.Fprintf(&sb, "from %s import %s\n", serverModule, iface.Name)
 	}
 	sb.WriteString("\n")
 
@@ -923,6 +848,14 @@ func generateTestServerPy(idl *parser.IDL, structMap map[string]*parser.Struct,
 		implName := iface.Name + "Impl"
 		fmt.Fprintf(&sb, "    server.register(\"%s\", %s())\n", iface.Name, implName)
 	}
+	if faults {
+		fmt.Fprintf(&sb, "    if os.environ.get(\"%s\"):\n", faultsEnvVar)
+		fmt.Fprintf(&sb, "        server.load_faults(os.environ[\"%s\"])\n", faultsEnvVar)
+	}
+	if admin {
+		fmt.Fprintf(&sb, "    if os.environ.get(\"%s\"):\n", adminTokenEnvVar)
+		fmt.Fprintf(&sb, "        server.enable_admin(os.environ[\"%s\"])\n", adminTokenEnvVar)
+	}
 	sb.WriteString("    server.serve_forever()\n")
 
 	return sb.String()
@@ -1047,9 +980,8 @@ func writeDefaultTestReturn(sb *strings.Builder, returnType *parser.Type, struct
 		if structMap[returnType.UserDefined] != nil {
 			s := structMap[returnType.UserDefined]
 			sb.WriteString("        return {\n")
-			// Handle inheritance - get all fields including parent
-			// For now, just use the struct's direct fields
-			for _, field := range s.Fields {
+			// Include the fields of every struct it extends
+			for _, field := range parser.ResolveStructFields(s, structMap) {
 				if field.Optional {
 					continue // Skip optional fields in default return
 				}
@@ -1057,25 +989,6 @@ func writeDefaultTestReturn(sb *strings.Builder, returnType *parser.Type, struct
 				writeDefaultTestValue(sb, field.Type, structMap, enumMap)
 				sb.WriteString(",\n")
 			}
-			// If extends, add parent fields
-			if s.Extends != "" {
-				// Extract base struct name (handle qualified names)
-				baseName := s.Extends
-				if strings.Contains(baseName, ".") {
-					parts := strings.Split(baseName, ".")
-					baseName = parts[len(parts)-1]
-				}
-				if baseStruct := structMap[baseName]; baseStruct != nil {
-					for _, field := range baseStruct.Fields {
-						if field.Optional {
-							continue
-						}
-						fmt.Fprintf(sb, "            '%s': ", field.Name)
-						writeDefaultTestValue(sb, field.Type, structMap, enumMap)
-						sb.WriteString(",\n")
-					}
-				}
-			}
 			sb.WriteString("        }\n\n")
 		} else if enumMap[returnType.UserDefined] != nil {
 			// Return first enum value
@@ -1130,22 +1043,33 @@ func writeDefaultTestValue(sb *strings.Builder, t *parser.Type, structMap map[st
 	}
 }
 
-// generateTestClientPy generates test_client.py that exercises all client methods
-func generateTestClientPy(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, _ map[string]*parser.Interface, _ map[string]*NamespaceTypes, _ string, _ string) string {
+// generateTestClientPy generates test_client.py that exercises all client methods.
+// When testVectors is true the client also replays testvectors.json.
+func generateTestClientPy(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, _ map[string]*parser.Interface, _ map[string]*NamespaceTypes, packageName string, _ string, testVectors bool) string {
 	var sb strings.Builder
 
 	sb.WriteString("# Generated by pulserpc - do not edit\n")
 	sb.WriteString("# Test client for integration testing\n\n")
-	sb.WriteString("import sys\n")
+	clientModule := "client"
+	if packageName != "" {
+		writePackageBootstrapPy(&sb)
+		clientModule = packageName + ".client"
+	} else {
+		sb.WriteString("import sys\n")
+	}
+	if testVectors {
+		sb.WriteString("import json\n")
+	}
 	sb.WriteString("import time\n")
 	sb.WriteString("import urllib.request\n")
-	sb.WriteString("from client import HTTPTransport\n")
+	sb.WriteString("from concurrent.futures import ThreadPoolExecutor\n")
+	fmt.Fprintf(&sb, "from %s import HTTPTransport\n", clientModule)
 	sb.WriteString("\n")
 
 	// Generate client imports
 	for _, iface := range idl.Interfaces {
 		clientName := iface.Name + "Client"
-		fmt.Fprintf(&sb, "from client import %s\n", clientName)
+		fmt.Fprintf(&sb, "from %s import %s\n", clientModule, clientName)
 	}
 	sb.WriteString("\n")
 
@@ -1164,6 +1088,11 @@ func generateTestClientPy(idl *parser.IDL, structMap map[string]*parser.Struct,
 	sb.WriteString("            time.sleep(0.5)\n")
 	sb.WriteString("    return False\n\n")
 
+	writeConcurrentCallsTestPy(&sb)
+	if testVectors {
+		writeTestVectorReplayPy(&sb)
+	}
+
 	sb.WriteString("def main():\n")
 	sb.WriteString("    server_url = \"http://localhost:8080\"\n")
 	sb.WriteString("    \n")
@@ -1195,6 +1124,13 @@ func generateTestClientPy(idl *parser.IDL, structMap map[string]*parser.Struct,
 		}
 	}
 
+	sb.WriteString("    errors.extend(test_concurrent_calls(transport))\n")
+	sb.WriteString("    \n")
+	if testVectors {
+		sb.WriteString("    errors.extend(run_test_vectors(server_url))\n")
+		sb.WriteString("    \n")
+	}
+
 	sb.WriteString("    # Report results\n")
 	sb.WriteString("    print()\n")
 	sb.WriteString("    if errors:\n")
@@ -1212,6 +1148,67 @@ func generateTestClientPy(idl *parser.IDL, structMap map[string]*parser.Struct,
 	return sb.String()
 }
 
+// writeTestVectorReplayPy generates the functions that replay testvectors.json
+// against the server and compare each response with the expected one
+func writeTestVectorReplayPy(sb *strings.Builder) {
+	sb.WriteString("def vector_values_equal(expected, actual) -> bool:\n")
+	sb.WriteString("    \"\"\"Compare JSON values ignoring key order; numbers compare numerically\"\"\"\n")
+	sb.WriteString("    if isinstance(expected, bool) or isinstance(actual, bool):\n")
+	sb.WriteString("        return type(expected) is type(actual) and expected == actual\n")
+	sb.WriteString("    if isinstance(expected, dict) and isinstance(actual, dict):\n")
+	sb.WriteString("        return expected.keys() == actual.keys() and all(vector_values_equal(v, actual[k]) for k, v in expected.items())\n")
+	sb.WriteString("    if isinstance(expected, list) and isinstance(actual, list):\n")
+	sb.WriteString("        return len(expected) == len(actual) and all(vector_values_equal(e, a) for e, a in zip(expected, actual))\n")
+	sb.WriteString("    return expected == actual\n\n")
+
+	sb.WriteString("def compare_test_vector(expected: dict, actual: dict) -> str:\n")
+	sb.WriteString("    \"\"\"Return an empty string if actual satisfies expected\"\"\"\n")
+	sb.WriteString("    if not vector_values_equal(expected.get('id'), actual.get('id')):\n")
+	sb.WriteString("        return f\"expected id {expected.get('id')}, got {actual.get('id')}\"\n")
+	sb.WriteString("    if 'error' in expected:\n")
+	sb.WriteString("        code = (actual.get('error') or {}).get('code')\n")
+	sb.WriteString("        if code != expected['error']['code']:\n")
+	sb.WriteString("            return f\"expected error code {expected['error']['code']}, got {actual}\"\n")
+	sb.WriteString("        return ''\n")
+	sb.WriteString("    if 'error' in actual:\n")
+	sb.WriteString("        return f\"unexpected error {actual['error']}\"\n")
+	sb.WriteString("    if 'result' in expected and not vector_values_equal(expected['result'], actual.get('result')):\n")
+	sb.WriteString("        return f\"expected result {expected['result']}, got {actual.get('result')}\"\n")
+	sb.WriteString("    return ''\n\n")
+
+	sb.WriteString("def run_test_vectors(server_url: str) -> list:\n")
+	sb.WriteString("    \"\"\"Replay testvectors.json and return a message for each mismatch\"\"\"\n")
+	sb.WriteString("    import urllib.error\n")
+	sb.WriteString("    try:\n")
+	sb.WriteString("        with open('testvectors.json') as f:\n")
+	sb.WriteString("            vectors = json.load(f)['vectors']\n")
+	sb.WriteString("    except (OSError, ValueError, KeyError) as e:\n")
+	sb.WriteString("        return [f\"testvectors.json: {e}\"]\n")
+	sb.WriteString("    failures = []\n")
+	sb.WriteString("    for v in vectors:\n")
+	sb.WriteString("        req = urllib.request.Request(server_url, data=json.dumps(v['request']).encode('utf-8'), method='POST')\n")
+	sb.WriteString("        req.add_header('Content-Type', 'application/json')\n")
+	sb.WriteString("        try:\n")
+	sb.WriteString("            with urllib.request.urlopen(req, timeout=10) as resp:\n")
+	sb.WriteString("                body = resp.read()\n")
+	sb.WriteString("        except urllib.error.HTTPError as e:\n")
+	sb.WriteString("            body = e.read()\n")
+	sb.WriteString("        except urllib.error.URLError as e:\n")
+	sb.WriteString("            failures.append(f\"vector {v['name']}: {e}\")\n")
+	sb.WriteString("            continue\n")
+	sb.WriteString("        try:\n")
+	sb.WriteString("            actual = json.loads(body)\n")
+	sb.WriteString("        except ValueError as e:\n")
+	sb.WriteString("            failures.append(f\"vector {v['name']}: invalid response: {e}\")\n")
+	sb.WriteString("            continue\n")
+	sb.WriteString("        msg = compare_test_vector(v['response'], actual)\n")
+	sb.WriteString("        if msg:\n")
+	sb.WriteString("            failures.append(f\"vector {v['name']}: {msg}\")\n")
+	sb.WriteString("        else:\n")
+	sb.WriteString("            print(f\"✓ vector {v['name']} passed\")\n")
+	sb.WriteString("    return failures\n\n")
+}
+
 // writeTestClientCall generates a test call for a method
 func writeTestClientCall(sb *strings.Builder, iface *parser.Interface, method *parser.Method, clientVar string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) {
 	testName := fmt.Sprintf("%s.%s", iface.Name, method.Name)
@@ -1316,7 +1313,8 @@ func generateTestParamValue(t *parser.Type, paramName string, structMap map[stri
 			s := structMap[t.UserDefined]
 			// Build struct dict
 			fields := []string{}
-			for _, field := range s.Fields {
+			// Include the fields of every struct it extends
+			for _, field := range parser.ResolveStructFields(s, structMap) {
 				if field.Optional && field.Name == "email" {
 					// Special case: set email to None for putPerson test
 					fields = append(fields, fmt.Sprintf("'%s': None", field.Name))
@@ -1325,22 +1323,6 @@ func generateTestParamValue(t *parser.Type, paramName string, structMap map[stri
 					fields = append(fields, fmt.Sprintf("'%s': %s", field.Name, fieldValue))
 				}
 			}
-			// Handle inheritance
-			if s.Extends != "" {
-				baseName := s.Extends
-				if strings.Contains(baseName, ".") {
-					parts := strings.Split(baseName, ".")
-					baseName = parts[len(parts)-1]
-				}
-				if baseStruct := structMap[baseName]; baseStruct != nil {
-					for _, field := range baseStruct.Fields {
-						if !field.Optional {
-							fieldValue := generateTestParamValue(field.Type, field.Name, structMap, enumMap)
-							fields = append(fields, fmt.Sprintf("'%s': %s", field.Name, fieldValue))
-						}
-					}
-				}
-			}
 			// Special handling for RepeatRequest
 			if t.UserDefined == "RepeatRequest" {
 				return "{'to_repeat': 'hello', 'count': 3, 'force_uppercase': False}"
@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestPythonGeneratorNamespacePackages(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "api")
+
+	idl := &parser.IDL{
+		Interfaces: []*parser.Interface{
+			{
+				Name:      "Echo",
+				Namespace: "conform",
+				Methods: []*parser.Method{
+					{Name: "echo", Parameters: []*parser.Parameter{{Name: "r", Type: &parser.Type{UserDefined: "inc.Response"}}}, ReturnType: &parser.Type{UserDefined: "inc.Response"}},
+				},
+			},
+		},
+		Structs: []*parser.Struct{
+			{
+				Name:      "inc.Response",
+				Namespace: "inc",
+				Fields:    []*parser.Field{{Name: "status", Type: &parser.Type{BuiltIn: "string"}}},
+			},
+		},
+	}
+
+	p := NewPythonClientServer()
+	fs := newTestFlagSet(t, p, outputDir)
+	if err := fs.Set("py-packages", "true"); err != nil {
+		t.Fatalf("failed to set py-packages flag: %v", err)
+	}
+	if err := fs.Set("generate-test-files", "true"); err != nil {
+		t.Fatalf("failed to set generate-test-files flag: %v", err)
+	}
+	if err := p.Generate(idl, fs); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	for _, f := range []string{"__init__.py", "inc/__init__.py", "conform/__init__.py", "pulserpc/__init__.py"} {
+		if _, err := os.Stat(filepath.Join(outputDir, f)); err != nil {
+			t.Errorf("expected %s to be generated: %v", f, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "inc.py")); err == nil {
+		t.Error("flat inc.py should not be generated with -py-packages")
+	}
+
+	checks := map[string][]string{
+		"inc/__init__.py": {"from ..pulserpc import ("},
+		"server.py":       {"from .pulserpc import Composition, DEADLINE_HEADER, LENIENT, RPCError, STRICT, canonical_json, check_int_literals, deadline_scope, normalize_ints, validate_type", "from .methods import METHOD_DEFS", "from .inc import ALL_STRUCTS as INC_STRUCTS"},
+		"client.py":       {"from .pulserpc import DEADLINE_HEADER, RPCError, canonical_json, deadline_header_value, normalize_ints, redact_value, remaining_time, validate_type", "from .methods import METHOD_DEFS", "from .conform import ALL_STRUCTS as CONFORM_STRUCTS"},
+		"test_server.py":  {"from api.server import PulseRPCServer"},
+		"test_client.py":  {"from api.client import HTTPTransport", "from api.client import EchoClient"},
+	}
+	for file, wants := range checks {
+		content, err := os.ReadFile(filepath.Join(outputDir, file))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q", file, want)
+			}
+		}
+	}
+}
+
+func TestPythonGeneratorNamespacePackagesInvalidDirName(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "my-api")
+
+	p := NewPythonClientServer()
+	fs := newTestFlagSet(t, p, outputDir)
+	if err := fs.Set("py-packages", "true"); err != nil {
+		t.Fatalf("failed to set py-packages flag: %v", err)
+	}
+	err := p.Generate(&parser.IDL{}, fs)
+	if err == nil || !strings.Contains(err.Error(), "not a valid Python package name") {
+		t.Fatalf("expected invalid package name error, got %v", err)
+	}
+}
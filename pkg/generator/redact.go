@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/naming"
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Fields annotated [sensitive] keep their wire format but are masked wherever
+// generated code formats a value for people to read. Every type registry marks
+// them with sensitive: true, Go tags them pulse:"sensitive", and structs that hold
+// a sensitive value, directly or through a parent or nested struct, format
+// themselves with the value replaced by "***": a String method in Go, ToString in
+// C# and toString in Java. Python and TypeScript structs are plain dicts and
+// objects, so their runtimes provide redact_struct and redactStruct instead.
+
+// needsRedaction reports whether formatting a value of struct s could reveal a
+// sensitive field
+func needsRedaction(s *parser.Struct, structMap map[string]*parser.Struct) bool {
+	return structNeedsRedaction(s, structMap, map[string]bool{})
+}
+
+func structNeedsRedaction(s *parser.Struct, structMap map[string]*parser.Struct, seen map[string]bool) bool {
+	if seen[s.Name] {
+		return false
+	}
+	seen[s.Name] = true
+	if s.Extends != "" {
+		if parent := lookupStruct(s.Extends, structMap); parent != nil && structNeedsRedaction(parent, structMap, seen) {
+			return true
+		}
+	}
+	for _, field := range s.Fields {
+		if field.IsSensitive() || typeNeedsRedaction(field.Type, structMap, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func typeNeedsRedaction(t *parser.Type, structMap map[string]*parser.Struct, seen map[string]bool) bool {
+	switch {
+	case t.IsArray():
+		return typeNeedsRedaction(t.Array, structMap, seen)
+	case t.IsMap():
+		return typeNeedsRedaction(t.MapValue, structMap, seen)
+	case t.IsUserDefined():
+		if s := lookupStruct(t.UserDefined, structMap); s != nil {
+			return structNeedsRedaction(s, structMap, seen)
+		}
+	}
+	return false
+}
+
+// lookupStruct finds a struct by qualified or base name
+func lookupStruct(name string, structMap map[string]*parser.Struct) *parser.Struct {
+	if s, ok := structMap[name]; ok {
+		return s
+	}
+	return structMap[GetBaseName(name)]
+}
+
+// writeStringMethodGo writes the String method of a Go struct that needs
+// redaction
+func writeStringMethodGo(sb *strings.Builder, s *parser.Struct) {
+	structName := GetBaseName(s.Name)
+	sb.WriteString("// String returns v as JSON with its sensitive fields masked\n")
+	fmt.Fprintf(sb, "func (v %s) String() string {\n", structName)
+	sb.WriteString("\treturn RedactedString(v)\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeToStringCs writes the ToString override of a C# class that needs
+// redaction
+func writeToStringCs(sb *strings.Builder, s *parser.Struct, prefix string) {
+	fmt.Fprintf(sb, "%s    // Returns this value as JSON with its sensitive fields masked\n", prefix)
+	fmt.Fprintf(sb, "%s    public override string ToString() => Redaction.ToJson(this, \"%s\", IdlData.ALL_STRUCTS);\n", prefix, s.Name)
+}
+
+// writeToStringJava writes the toString method of a Java struct class that needs
+// redaction. Inherited fields are printed through their getters, and null
+// sensitive fields print as null.
+func writeToStringJava(sb *strings.Builder, s *parser.Struct, structMap map[string]*parser.Struct, getters string) {
+	expr := "\"" + GetBaseName(s.Name) + "{"
+	for i, field := range parser.ResolveStructFields(s, structMap) {
+		if i > 0 {
+			expr += ", "
+		}
+		getter := getGetterName(naming.LowerFirst(field.Name), getters) + "()"
+		if field.IsSensitive() {
+			getter = "(" + getter + " == null ? null : \"***\")"
+		}
+		expr += naming.LowerFirst(field.Name) + "=\" + " + getter + " + \""
+	}
+	expr += "}\""
+	sb.WriteString("    // Returns the fields of this value with sensitive ones masked\n")
+	sb.WriteString("    @Override\n")
+	sb.WriteString("    public String toString() {\n")
+	fmt.Fprintf(sb, "        return %s;\n", expr)
+	sb.WriteString("    }\n\n")
+}
@@ -35,3 +35,26 @@ func List() []string {
 	return names
 }
 
+
+// Builtin returns new instances of the plugins that ship with PulseRPC, which the
+// CLI registers and pkg/pulserpc generates with
+func Builtin() []Plugin {
+	return []Plugin{
+		NewPythonClientServer(),
+		NewTSClientServer(),
+		NewCSharpClientServer(),
+		NewJavaClientServer(),
+		NewGoClientServer(),
+		NewRustClientServer(),
+		NewLoadTest(),
+		NewCollection(),
+		NewExamples(),
+		NewRoutes(),
+		NewJSONSchema(),
+		NewJSBrowserClient(),
+		NewProtobuf(),
+		NewGraphQL(),
+		NewDocs(),
+		// Add more plugins here as they are implemented
+	}
+}
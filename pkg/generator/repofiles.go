@@ -0,0 +1,165 @@
+package generator
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// -generate-repo-files writes two files into the output directory so the generated
+// code is handled as such once it is committed: .gitattributes marks each file the
+// run wrote linguist-generated, which GitHub and other review tools collapse in
+// diffs, and .editorconfig describes the layout the code was emitted in, including
+// the -style overrides, so editors keep it when a file is opened. Files are listed
+// by path rather than by pattern, so files of your own next to the generated ones
+// are not collapsed; neither are the starting-point files written once for you to
+// edit, such as handlers_test.go.
+
+// repoFilesHeader marks the repo files as written by pulse, so a later run may
+// replace them
+const repoFilesHeader = "# Generated by pulserpc - do not edit"
+
+// skeletonFileNames are the files plugins write only when they do not exist yet,
+// for the user to fill in
+var skeletonFileNames = map[string]bool{
+	"handlers_test.go":    true,
+	"harness_handlers.py": true,
+	"HarnessHandlers.cs":  true,
+	"Handlers.java":       true,
+}
+
+// repoFilesRequested reports whether -generate-repo-files is set
+func repoFilesRequested(fs *flag.FlagSet) bool {
+	f := fs.Lookup("generate-repo-files")
+	return f != nil && f.Value.String() == "true"
+}
+
+// repoFilesStart returns the time from which files count as written by the current
+// run. File systems may store modification times more coarsely than time.Now, so
+// it is rounded down to the second.
+func repoFilesStart() time.Time {
+	return time.Now().Truncate(time.Second)
+}
+
+// writeRepoFiles writes .gitattributes and .editorconfig into each of dirs for the
+// files under it that were modified since start, the beginning of the run that
+// generated them. It does not replace such files written by someone else.
+func writeRepoFiles(language string, style codeStyle, start time.Time, dirs ...string) error {
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		if dir == "" {
+			dir = "."
+		}
+		dir = filepath.Clean(dir)
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		paths, err := filesWrittenSince(dir, start)
+		if err != nil {
+			return fmt.Errorf("failed to list generated files: %w", err)
+		}
+		if err := writeRepoFile(filepath.Join(dir, ".gitattributes"), gitAttributes(paths)); err != nil {
+			return err
+		}
+		if err := writeRepoFile(filepath.Join(dir, ".editorconfig"), editorConfig(language, style)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filesWrittenSince returns the slash separated paths, relative to dir, of the
+// files under dir modified since start, sorted. Skeleton files and the repo files
+// themselves are left out.
+func filesWrittenSince(dir string, start time.Time) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		name := d.Name()
+		if skeletonFileNames[name] || name == ".gitattributes" || name == ".editorconfig" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().Before(start) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	sort.Strings(paths)
+	return paths, err
+}
+
+// writeRepoFile writes content to path unless a file there lacks repoFilesHeader
+func writeRepoFile(path, content string) error {
+	existing, err := os.ReadFile(path)
+	if err == nil && !strings.HasPrefix(string(existing), repoFilesHeader) {
+		return fmt.Errorf("%s exists and was not written by pulse; remove it or generate without -generate-repo-files", path)
+	} else if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// gitAttributes returns a .gitattributes marking paths as generated. Paths are
+// anchored with a leading slash so they do not match files in subdirectories.
+func gitAttributes(paths []string) string {
+	var b strings.Builder
+	b.WriteString(repoFilesHeader + "\n")
+	b.WriteString("# Files written by pulse, collapsed in diffs by code review tools\n")
+	for _, path := range paths {
+		fmt.Fprintf(&b, "/%s linguist-generated=true\n", path)
+	}
+	return b.String()
+}
+
+// editorConfig returns an .editorconfig describing the layout of language's
+// generated code in style
+func editorConfig(language string, style codeStyle) string {
+	var b strings.Builder
+	b.WriteString(repoFilesHeader + "\n")
+	b.WriteString("# The layout pulse generated the code in\n\n")
+	b.WriteString("[*]\ncharset = utf-8\nend_of_line = lf\ninsert_final_newline = true\n")
+
+	switch language {
+	case "go":
+		// gofmt indents with tabs
+		b.WriteString("\n[*.go]\nindent_style = tab\n")
+	case "rust":
+		b.WriteString("\n[*.rs]\nindent_style = space\nindent_size = 4\n")
+	case "js":
+		b.WriteString("\n[*.js]\nindent_style = space\nindent_size = 2\n")
+	default:
+		fmt.Fprintf(&b, "\n[*%s]\nindent_style = space\nindent_size = %d\n", codeStyleExtensions[language], style.Indent)
+		if language == "csharp" {
+			// Read by the .NET code style analyzers
+			if style.Braces == "next-line" {
+				b.WriteString("csharp_new_line_before_open_brace = all\n")
+			} else {
+				b.WriteString("csharp_new_line_before_open_brace = none\n")
+			}
+		}
+	}
+	// idl.json, testvectors.json and the other JSON files are indented by two spaces
+	b.WriteString("\n[*.json]\nindent_style = space\nindent_size = 2\n")
+	return b.String()
+}
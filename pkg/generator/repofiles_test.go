@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestRepoFiles(t *testing.T) {
+	idl := &parser.IDL{
+		RootNamespace: "catalog",
+		Interfaces: []*parser.Interface{
+			{
+				Name: "Catalog",
+				Methods: []*parser.Method{
+					{Name: "ping", ReturnType: &parser.Type{BuiltIn: "string"}},
+				},
+			},
+		},
+	}
+
+	generate := func(t *testing.T, plugin Plugin, dir string, values map[string]string) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		fs.Bool("generate-test-harness", false, "generate test harness")
+		fs.Bool("generate-repo-files", false, "write repo files")
+		fs.String("style", "", "code style")
+		plugin.RegisterFlags(fs)
+		values["dir"] = dir
+		values["generate-repo-files"] = "true"
+		for name, value := range values {
+			if err := fs.Set(name, value); err != nil {
+				t.Fatalf("failed to set %s flag: %v", name, err)
+			}
+		}
+		if err := plugin.Generate(idl, fs); err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+	}
+	read := func(t *testing.T, path string) string {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("expected %s: %v", filepath.Base(path), err)
+		}
+		return string(content)
+	}
+
+	t.Run("go", func(t *testing.T) {
+		dir := t.TempDir()
+		// A file of the user's, older than the run, is not marked
+		own := filepath.Join(dir, "main.go")
+		if err := os.WriteFile(own, []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		past := time.Now().Add(-time.Hour)
+		if err := os.Chtimes(own, past, past); err != nil {
+			t.Fatal(err)
+		}
+		generate(t, NewGoClientServer(), dir, map[string]string{"generate-test-harness": "true"})
+
+		attrs := read(t, filepath.Join(dir, ".gitattributes"))
+		for _, want := range []string{"/server.go linguist-generated=true\n", "/idl.json linguist-generated=true\n", "/rpc.go linguist-generated=true\n"} {
+			if !strings.Contains(attrs, want) {
+				t.Errorf(".gitattributes lacks %q:\n%s", want, attrs)
+			}
+		}
+		for _, unwanted := range []string{"/main.go", "/handlers_test.go", "/.editorconfig"} {
+			if strings.Contains(attrs, unwanted) {
+				t.Errorf(".gitattributes marks %s:\n%s", unwanted, attrs)
+			}
+		}
+		if editor := read(t, filepath.Join(dir, ".editorconfig")); !strings.Contains(editor, "[*.go]\nindent_style = tab\n") {
+			t.Errorf(".editorconfig lacks the Go section:\n%s", editor)
+		}
+
+		// Regenerating replaces the files written by the previous run
+		generate(t, NewGoClientServer(), dir, map[string]string{"generate-test-harness": "true"})
+		if again := read(t, filepath.Join(dir, ".gitattributes")); !strings.Contains(again, "/server.go linguist-generated=true\n") {
+			t.Errorf("regenerated .gitattributes lacks server.go:\n%s", again)
+		}
+	})
+
+	t.Run("csharp style", func(t *testing.T) {
+		dir := t.TempDir()
+		generate(t, NewCSharpClientServer(), dir, map[string]string{"style": "indent=2,braces=same-line"})
+		editor := read(t, filepath.Join(dir, ".editorconfig"))
+		if !strings.Contains(editor, "[*.cs]\nindent_style = space\nindent_size = 2\ncsharp_new_line_before_open_brace = none\n") {
+			t.Errorf(".editorconfig does not follow -style:\n%s", editor)
+		}
+	})
+
+	t.Run("foreign gitattributes", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte("*.png binary\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", dir, "output dir")
+		fs.Bool("generate-repo-files", true, "write repo files")
+		plugin := NewRustClientServer()
+		plugin.RegisterFlags(fs)
+		err := plugin.Generate(idl, fs)
+		if err == nil || !strings.Contains(err.Error(), "was not written by pulse") {
+			t.Fatalf("expected an error for the existing .gitattributes, got %v", err)
+		}
+		if got := read(t, filepath.Join(dir, ".gitattributes")); got != "*.png binary\n" {
+			t.Errorf("existing .gitattributes was replaced:\n%s", got)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package generator
+
+import "strings"
+
+// Request metadata: handlers can read the headers and remote address of the HTTP
+// request a call arrived in. Go servers put a RequestMeta in the context of every
+// call (see meta.go in the Go runtime), and the server interfaces take that context
+// as their first parameter. Java servers begin a RequestMeta for each request on the
+// thread handling it (RequestMeta.java in the Java runtime), and C# servers set the
+// RequestMeta written here for the async flow handling it, since the C# runtime does
+// not depend on ASP.NET Core.
+
+// writeRequestMetaCs writes the RequestMeta class of the C# server
+func writeRequestMetaCs(sb *strings.Builder) {
+	sb.WriteString(`/// <summary>
+/// The HTTP request of the call being handled. The server sets it for each request, and
+/// handlers read the caller's headers, such as an authorization token or a trace id, and
+/// address from it. It is null for calls that did not arrive over HTTP, such as those of
+/// HandleRequestAsync. Handlers must not read the request body or write the response.
+/// </summary>
+public static class RequestMeta
+{
+    private sealed class Scope : IDisposable
+    {
+        private readonly HttpContext? _previous;
+
+        public Scope(HttpContext? previous)
+        {
+            _previous = previous;
+        }
+
+        public void Dispose()
+        {
+            Current.Value = _previous;
+        }
+    }
+
+    private static readonly System.Threading.AsyncLocal<HttpContext?> Current = new();
+
+    /// <summary>The HttpContext the call arrived in</summary>
+    public static HttpContext? HttpContext => Current.Value;
+
+    /// <summary>The request headers</summary>
+    public static IHeaderDictionary? Headers => Current.Value?.Request.Headers;
+
+    /// <summary>The address of the caller</summary>
+    public static IPAddress? RemoteAddress => Current.Value?.Connection.RemoteIpAddress;
+
+    /// <summary>
+    /// Makes context the request of the call handled by the calling async method, until
+    /// the returned scope is disposed
+    /// </summary>
+    public static IDisposable Begin(HttpContext context)
+    {
+        var scope = new Scope(Current.Value);
+        Current.Value = context;
+        return scope;
+    }
+}
+
+`)
+}
@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestRequestMetaGenerated(t *testing.T) {
+	idl, err := parser.ParseIDL("shop.pulse", "namespace shop\n\ninterface Orders {\n  place(sku string) string\n}")
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	tests := []struct {
+		plugin Plugin
+		files  map[string][]string
+	}{
+		{NewGoClientServer(), map[string][]string{
+			"meta.go":   {"func RequestMetaFromContext(ctx context.Context) (meta RequestMeta, ok bool) {"},
+			"server.go": {"type Orders interface {\n\tPlace(ctx context.Context, sku string) (string, error)\n}"},
+		}},
+		{NewCSharpClientServer(), map[string][]string{
+			"Server.cs": {"public static class RequestMeta\n", "using var meta = RequestMeta.Begin(context);"},
+		}},
+		{NewJavaClientServer(), map[string][]string{
+			"src/main/java/com/bitmechanic/pulserpc/RequestMeta.java": {"public static RequestMeta current() {"},
+			"src/main/java/com/example/Server.java":                   {"RequestMeta.Scope meta = RequestMeta.begin(exchange)) {"},
+		}},
+	}
+	for _, tt := range tests {
+		tmpDir := t.TempDir()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		tt.plugin.RegisterFlags(fs)
+		for name, value := range map[string]string{"dir": tmpDir, "base-package": "com.example"} {
+			if fs.Lookup(name) != nil {
+				if err := fs.Set(name, value); err != nil {
+					t.Fatalf("failed to set %s flag: %v", name, err)
+				}
+			}
+		}
+		if err := tt.plugin.Generate(idl, fs); err != nil {
+			t.Fatalf("%s: Generate failed: %v", tt.plugin.Name(), err)
+		}
+		for file, wants := range tt.files {
+			content, err := os.ReadFile(filepath.Join(tmpDir, file))
+			if err != nil {
+				t.Fatalf("%s: expected %s: %v", tt.plugin.Name(), file, err)
+			}
+			for _, want := range wants {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("%s: %s does not contain %q", tt.plugin.Name(), file, want)
+				}
+			}
+		}
+	}
+}
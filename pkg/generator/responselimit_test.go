@@ -0,0 +1,192 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+const responseLimitIDL = `namespace catalog
+
+interface Catalog {
+  tags(n int) []string
+  names(n int) []string
+}`
+
+// The checks below give every method a 100 byte response limit ("*") and
+// Catalog.names its own 1000 byte limit, call tags(1), tags(20), names(20) and
+// names(100), each returning n ten-letter strings, and print the outcome of each call
+// and whether the OnCall hook saw a response over 100 bytes
+const responseLimitWant = `Catalog.tags ok small
+Catalog.tags -32001 Response too large large
+Catalog.names ok large
+Catalog.names -32001 Response too large large`
+
+const responseLimitGoMain = `package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+
+	catalog "example.com/catalog"
+)
+
+type handler struct{}
+
+func (handler) Tags(ctx context.Context, n int) ([]string, error) {
+	return words(n), nil
+}
+
+func (handler) Names(ctx context.Context, n int) ([]string, error) {
+	return words(n), nil
+}
+
+func words(n int) []string {
+	result := make([]string, n)
+	for i := range result {
+		result[i] = "abcdefghij"
+	}
+	return result
+}
+
+func main() {
+	server := catalog.NewPulseRPCServer("localhost", 0)
+	server.RegisterCatalog(handler{})
+	server.SetMaxResponseBytes("*", 100)
+	server.SetMaxResponseBytes("Catalog.names", 1000)
+	var stats catalog.CallStats
+	server.OnCall(func(c catalog.CallStats) { stats = c })
+	calls := []struct {
+		method string
+		n      int
+	}{{"Catalog.tags", 1}, {"Catalog.tags", 20}, {"Catalog.names", 20}, {"Catalog.names", 100}}
+	for _, call := range calls {
+		body := fmt.Sprintf(` + "`" + `{"jsonrpc":"2.0","method":"%s","params":[%d],"id":1}` + "`" + `, call.method, call.n)
+		r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, r)
+		var response struct {
+			Error *struct {
+				Code    int
+				Message string
+			} ` + "`json:\"error\"`" + `
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		outcome := "ok"
+		if response.Error != nil {
+			outcome = fmt.Sprintf("%d %s", response.Error.Code, response.Error.Message)
+		}
+		size := "small"
+		if stats.ResponseBytes > 100 {
+			size = "large"
+		}
+		fmt.Println(stats.Method, outcome, size)
+	}
+}
+`
+
+func TestResponseLimitGo(t *testing.T) {
+	dir := generateForTest(t, NewGoClientServer(), responseLimitIDL)
+	if out := runGoCheck(t, dir, "example.com/catalog", responseLimitGoMain); out != responseLimitWant {
+		t.Errorf("got:\n%s\nwant:\n%s", out, responseLimitWant)
+	}
+}
+
+const responseLimitPythonCheck = `import http.client
+import json
+from server import PulseRPCServer
+
+class Catalog:
+    def tags(self, n):
+        return ['abcdefghij'] * n
+
+    def names(self, n):
+        return ['abcdefghij'] * n
+
+stats = []
+server = PulseRPCServer(max_response_bytes={'*': 100, 'Catalog.names': 1000}, on_call=stats.append)
+server.register('Catalog', Catalog())
+for method, n in [('Catalog.tags', 1), ('Catalog.tags', 20), ('Catalog.names', 20), ('Catalog.names', 100)]:
+    headers = http.client.HTTPMessage()
+    headers['Content-Type'] = 'application/json'
+    body = json.dumps({'jsonrpc': '2.0', 'method': method, 'params': [n], 'id': 1}).encode('utf-8')
+    _, _, response = server.handle_http('POST', '/', headers, body)
+    error = json.loads(response).get('error')
+    outcome = f"{error['code']} {error['message']}" if error else 'ok'
+    print(stats[-1].method, outcome, 'large' if stats[-1].response_bytes > 100 else 'small')
+`
+
+func TestResponseLimitPython(t *testing.T) {
+	dir := generateForTest(t, NewPythonClientServer(), responseLimitIDL)
+	if out := runPythonCheck(t, dir, responseLimitPythonCheck); out != responseLimitWant {
+		t.Errorf("got:\n%s\nwant:\n%s", out, responseLimitWant)
+	}
+}
+
+const responseLimitCSharpProgram = `using System.Text.Json;
+using PulseRPC;
+
+var builder = WebApplication.CreateBuilder();
+builder.WebHost.UseUrls("http://127.0.0.1:0");
+builder.Logging.ClearProviders();
+var app = builder.Build();
+CallStats? stats = null;
+var server = new PulseRPCServer { OnCall = c => stats = c };
+server.MaxResponseBytes["*"] = 100;
+server.MaxResponseBytes["Catalog.names"] = 1000;
+server.RegisterCatalog(new Catalog());
+server.MapEndpoints(app);
+await app.StartAsync();
+
+using var http = new HttpClient { BaseAddress = new Uri(app.Urls.First()) };
+foreach (var (method, n) in new[] { ("Catalog.tags", 1), ("Catalog.tags", 20), ("Catalog.names", 20), ("Catalog.names", 100) })
+{
+    var request = JsonSerializer.Serialize(new { jsonrpc = "2.0", method, @params = new[] { n }, id = 1 });
+    var response = await http.PostAsync("/", new StringContent(request, System.Text.Encoding.UTF8, "application/json"));
+    using var body = JsonDocument.Parse(await response.Content.ReadAsStringAsync());
+    var outcome = body.RootElement.TryGetProperty("error", out var error)
+        ? $"{error.GetProperty("code").GetInt32()} {error.GetProperty("message").GetString()}"
+        : "ok";
+    Console.WriteLine($"{stats!.Method} {outcome} {(stats.ResponseBytes > 100 ? "large" : "small")}");
+}
+await app.StopAsync();
+
+class Catalog : ICatalog
+{
+    public List<string> tags(int n) => Enumerable.Repeat("abcdefghij", n).ToList();
+
+    public List<string> names(int n) => Enumerable.Repeat("abcdefghij", n).ToList();
+}
+`
+
+func TestResponseLimitCSharp(t *testing.T) {
+	dir := generateForTest(t, NewCSharpClientServer(), responseLimitIDL)
+	if out := runDotnetCheck(t, dir, responseLimitCSharpProgram); out != responseLimitWant {
+		t.Errorf("got:\n%s\nwant:\n%s", out, responseLimitWant)
+	}
+}
+
+// TestResponseLimitGenerated checks that the servers that cannot be run by these tests
+// fall back to the "*" limit for methods without their own
+func TestResponseLimitGenerated(t *testing.T) {
+	tests := []struct {
+		plugin Plugin
+		args   []string
+		file   string
+		want   string
+	}{
+		{NewJavaClientServer(), []string{"-base-package=com.example"}, "src/main/java/com/example/Server.java",
+			`Integer limit = maxResponseBytes.getOrDefault(method, maxResponseBytes.get("*"));`},
+		{NewTSClientServer(), nil, "server.ts",
+			"const limit = this.maxResponseBytes.get(method) ?? this.maxResponseBytes.get('*');"},
+	}
+	for _, tt := range tests {
+		dir := generateForTest(t, tt.plugin, responseLimitIDL, tt.args...)
+		if content := readGenerated(t, dir, tt.file); !strings.Contains(content, tt.want) {
+			t.Errorf("%s: %s does not contain %q", tt.plugin.Name(), tt.file, tt.want)
+		}
+	}
+}
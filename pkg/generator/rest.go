@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// restRoute is a [readonly] method that generated servers also expose via HTTP GET
+type restRoute struct {
+	Interface *parser.Interface
+	Method    *parser.Method
+}
+
+// Path returns the GET path for the route: /<Interface>/<method>
+func (r restRoute) Path() string {
+	return "/" + r.Interface.Name + "/" + r.Method.Name
+}
+
+// RPCMethod returns the JSON-RPC method name the route dispatches to
+func (r restRoute) RPCMethod() string {
+	return r.Interface.RPCName(r.Method)
+}
+
+// collectRESTRoutes returns the [readonly] methods of all interfaces in declaration order
+func collectRESTRoutes(interfaces []*parser.Interface) []restRoute {
+	var routes []restRoute
+	for _, iface := range interfaces {
+		for _, method := range iface.Methods {
+			if method.IsReadOnly() {
+				routes = append(routes, restRoute{Interface: iface, Method: method})
+			}
+		}
+	}
+	return routes
+}
+
+// restRouteView is the view model for the GET route of a [readonly] method
+type restRouteView struct {
+	Path         string
+	RPCMethod    string
+	Params       []restParamView
+	CacheControl string
+}
+
+type restParamView struct {
+	Name string
+	// Type is the parameter's type definition as a literal of the target language
+	Type     string
+	Optional bool
+}
+
+// newRESTRouteViews returns the views of the GET routes of interfaces. typeDict
+// writes a type definition as a literal of the target language.
+func newRESTRouteViews(interfaces []*parser.Interface, typeDict func(*strings.Builder, *parser.Type)) []restRouteView {
+	var views []restRouteView
+	for _, route := range collectRESTRoutes(interfaces) {
+		rv := restRouteView{Path: route.Path(), RPCMethod: route.RPCMethod(), CacheControl: cacheControl(route.Method)}
+		for _, param := range route.Method.Parameters {
+			rv.Params = append(rv.Params, restParamView{
+				Name:     param.Name,
+				Type:     capture(func(sb *strings.Builder) { typeDict(sb, param.Type) }),
+				Optional: param.Optional,
+			})
+		}
+		views = append(views, rv)
+	}
+	return views
+}
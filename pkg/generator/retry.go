@@ -0,0 +1,41 @@
+package generator
+
+import "github.com/coopernurse/pulserpc/pkg/parser"
+
+// Every client gets a RetryTransport, written next to it as retry.* (or Retry.cs,
+// RetryTransport.java). It wraps another transport and retries the methods marked
+// [idempotent] or [readonly] when the call failed in a way that a server being
+// drained or restarted produces, with exponential backoff and full jitter.
+// The Go and Java ones can also hedge a slow idempotent call with a second
+// attempt, and limit retries and hedges with a budget shared by every client.
+
+// retryView is the view model for the retry templates
+type retryView struct {
+	// Package is the Go or Java package of the generated client
+	Package string
+	// Packaged is true when Python output is a package using relative imports
+	Packaged bool
+	// Methods are the qualified names (Interface.method) of the idempotent methods
+	Methods []string
+	// The TypeScript names below carry the -package prefix
+	CallOptions        string
+	Transport          string
+	TransportError     string
+	IdempotentMethods  string
+	RetryPolicy        string
+	DefaultRetryPolicy string
+	ClassName          string
+}
+
+// idempotentMethods returns the JSON-RPC names of the methods that may be retried
+func idempotentMethods(idl *parser.IDL) []string {
+	var methods []string
+	for _, iface := range idl.Interfaces {
+		for _, method := range iface.Methods {
+			if method.IsIdempotent() {
+				methods = append(methods, iface.RPCName(method))
+			}
+		}
+	}
+	return methods
+}
@@ -0,0 +1,90 @@
+package generator
+
+import "testing"
+
+const retryTestIDL = `namespace shop
+
+interface Catalog {
+    count() int [idempotent]
+    reset() bool
+}
+`
+
+const retryGoMain = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	shop "example.com/shop"
+)
+
+// failing answers every call with HTTP 503
+type failing struct{ calls int }
+
+func (f *failing) Call(method string, params []interface{}) (map[string]interface{}, error) {
+	f.calls++
+	return nil, &shop.TransportError{StatusCode: 503}
+}
+
+func main() {
+	// The first request hangs until it is cancelled; the hedge answers at once
+	cancelled := make(chan struct{})
+	var once sync.Once
+	first := true
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		mu.Lock()
+		hang := first
+		first = false
+		mu.Unlock()
+		if hang {
+			<-r.Context().Done()
+			once.Do(func() { close(cancelled) })
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "result": 7, "id": request["id"]})
+	}))
+	defer server.Close()
+
+	hedged := shop.NewRetryTransport(shop.NewHTTPTransport(server.URL, nil), shop.RetryPolicy{
+		MaxAttempts: 1,
+		Hedge:       &shop.HedgePolicy{Percentile: 0.95, MinDelay: 50 * time.Millisecond},
+		Budget:      shop.NewRetryBudget(0.1, 10),
+	})
+	response, err := hedged.Call("Catalog.count", nil)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(response["result"])
+	select {
+	case <-cancelled:
+		fmt.Println("cancelled")
+	case <-time.After(5 * time.Second):
+		fmt.Println("not cancelled")
+	}
+
+	// A budget of one token allows a single retry, then none
+	f := &failing{}
+	limited := shop.NewRetryTransport(f, shop.RetryPolicy{MaxAttempts: 4, Budget: shop.NewRetryBudget(0, 1)})
+	limited.Call("Catalog.count", nil)
+	firstCalls := f.calls
+	limited.Call("Catalog.count", nil)
+	fmt.Println(firstCalls, f.calls-firstCalls)
+}
+`
+
+// TestRetryHedgingGo builds a program that hedges a slow idempotent call against
+// an HTTP server and runs a RetryTransport out of budget
+func TestRetryHedgingGo(t *testing.T) {
+	dir := generateForTest(t, NewGoClientServer(), retryTestIDL)
+	if out, want := runGoCheck(t, dir, "example.com/shop", retryGoMain), "7\ncancelled\n2 1"; out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// routesFileName is the file written by the routes plugin
+const routesFileName = "routes.json"
+
+// routesVersion is bumped whenever the file layout changes incompatibly
+const routesVersion = 1
+
+// RouteTable is the routing manifest written by the routes plugin, for API
+// gateway configuration (Envoy, Kong) to be derived from the IDL
+type RouteTable struct {
+	Version int     `json:"version"`
+	Routes  []Route `json:"routes"`
+}
+
+// Route describes how a gateway should route one JSON-RPC method
+type Route struct {
+	// Method is the JSON-RPC method name, e.g. UserService.get
+	Method    string `json:"method"`
+	Interface string `json:"interface"`
+	// ParamsSchema is a JSON pointer to the method's parameters in idl.json
+	ParamsSchema string `json:"paramsSchema"`
+	// GetPath is the HTTP GET path of a [readonly] method
+	GetPath string `json:"getPath,omitempty"`
+	// CacheControl is the Cache-Control header servers send on the GET responses of a [cache] method
+	CacheControl string `json:"cacheControl,omitempty"`
+	// PostPath is the HTTP POST path of the form or XML encoded calls of an [accepts] method
+	PostPath string `json:"postPath,omitempty"`
+	// Accepts are the legacy encodings from the [accepts] annotation
+	Accepts []string `json:"accepts,omitempty"`
+	// Scopes are the auth scopes from the [scopes] annotation
+	Scopes []string `json:"scopes"`
+	// Timeout is the [timeout] annotation, or -routes-default-timeout if the method has none
+	Timeout string `json:"timeout,omitempty"`
+	// Owner is the team from the [owner] annotation of the method or its interface
+	Owner string `json:"owner,omitempty"`
+	// Stability is the [stability] annotation of the method or its interface
+	Stability string `json:"stability,omitempty"`
+}
+
+// Routes generates routes.json mapping every method to its interface, params
+// schema, auth scopes and timeout
+type Routes struct {
+}
+
+// NewRoutes creates a new Routes plugin instance
+func NewRoutes() *Routes {
+	return &Routes{}
+}
+
+// Name returns the plugin identifier
+func (p *Routes) Name() string {
+	return "routes"
+}
+
+// RegisterFlags registers CLI flags for this plugin
+func (p *Routes) RegisterFlags(fs *flag.FlagSet) {
+	fs.String("routes-default-timeout", "", "Timeout for methods without a [timeout] annotation (e.g., 30s); empty leaves it to the gateway")
+}
+
+// Generate writes routes.json to the output directory
+func (p *Routes) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
+	outputDir := ""
+	if dirFlag := fs.Lookup("dir"); dirFlag != nil {
+		outputDir = dirFlag.Value.String()
+	}
+	defaultTimeout := fs.Lookup("routes-default-timeout").Value.String()
+	if defaultTimeout != "" {
+		if d, err := time.ParseDuration(defaultTimeout); err != nil || d <= 0 {
+			return fmt.Errorf("invalid routes-default-timeout value: %s (must be a positive duration such as 30s)", defaultTimeout)
+		}
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(BuildRouteTable(idl, defaultTimeout)); err != nil {
+		return fmt.Errorf("failed to marshal routes: %w", err)
+	}
+	if err := writeGeneratedFile(filepath.Join(outputDir, routesFileName), buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", routesFileName, err)
+	}
+	return nil
+}
+
+// BuildRouteTable returns a route for every method in the IDL. defaultTimeout is
+// used for methods without a [timeout] annotation and may be empty.
+func BuildRouteTable(idl *parser.IDL, defaultTimeout string) *RouteTable {
+	table := &RouteTable{Version: routesVersion, Routes: []Route{}}
+	for i, iface := range idl.Interfaces {
+		for j, method := range iface.Methods {
+			route := Route{
+				Method:       iface.RPCName(method),
+				Interface:    iface.Name,
+				ParamsSchema: fmt.Sprintf("idl.json#/interfaces/%d/methods/%d/parameters", i, j),
+				Scopes:       method.Scopes(),
+				Timeout:      defaultTimeout,
+				Owner:        idl.MethodOwner(iface, method),
+				Stability:    idl.MethodStability(iface, method),
+			}
+			if route.Scopes == nil {
+				route.Scopes = []string{}
+			}
+			if method.IsReadOnly() {
+				route.GetPath = restRoute{Interface: iface, Method: method}.Path()
+				route.CacheControl = cacheControl(method)
+			}
+			if accepts := method.Accepts(); len(accepts) > 0 {
+				route.PostPath = restRoute{Interface: iface, Method: method}.Path()
+				route.Accepts = accepts
+			}
+			if a := method.Annotation(parser.AnnotationTimeout); a != nil {
+				route.Timeout = a.Value
+			}
+			table.Routes = append(table.Routes, route)
+		}
+	}
+	return table
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestBuildRouteTableAnnotations(t *testing.T) {
+	idl := &parser.IDL{
+		Interfaces: []*parser.Interface{{
+			Name:        "Catalog",
+			Annotations: []*parser.Annotation{{Name: parser.AnnotationOwner, Value: "team-catalog"}},
+			Methods: []*parser.Method{
+				{
+					Name:       "get",
+					Parameters: []*parser.Parameter{{Name: "id", Type: &parser.Type{BuiltIn: "string"}}},
+					ReturnType: &parser.Type{BuiltIn: "string"},
+					Annotations: []*parser.Annotation{
+						{Name: parser.AnnotationReadOnly},
+						{Name: parser.AnnotationCache, Value: "5m"},
+						{Name: parser.AnnotationScopes, Value: "catalog:read, admin"},
+						{Name: parser.AnnotationStability, Value: parser.StabilityExperimental},
+					},
+				},
+				{
+					Name:       "save",
+					ReturnType: &parser.Type{BuiltIn: "bool"},
+					Annotations: []*parser.Annotation{
+						{Name: parser.AnnotationTimeout, Value: "5s"},
+						{Name: parser.AnnotationAccepts, Value: "form, xml"},
+					},
+				},
+			},
+		}},
+	}
+
+	want := []Route{
+		{
+			Method:       "Catalog.get",
+			Interface:    "Catalog",
+			ParamsSchema: "idl.json#/interfaces/0/methods/0/parameters",
+			GetPath:      "/Catalog/get",
+			CacheControl: "max-age=300",
+			Scopes:       []string{"catalog:read", "admin"},
+			Timeout:      "30s",
+			Owner:        "team-catalog",
+			Stability:    "experimental",
+		},
+		{
+			Method:       "Catalog.save",
+			Interface:    "Catalog",
+			ParamsSchema: "idl.json#/interfaces/0/methods/1/parameters",
+			PostPath:     "/Catalog/save",
+			Accepts:      []string{"form", "xml"},
+			Scopes:       []string{},
+			Timeout:      "5s",
+			Owner:        "team-catalog",
+		},
+	}
+	if got := BuildRouteTable(idl, "30s").Routes; !reflect.DeepEqual(got, want) {
+		t.Errorf("routes = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,220 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestRustGeneratorCrateLayout(t *testing.T) {
+	dir := generateForTest(t, NewRustClientServer(), `namespace billing
+
+interface Invoices {
+  total(id string) float
+}`, "-generate-test-files=true", "-dependency-versions=serde_json=1.0.140")
+
+	for _, name := range []string{
+		"idl.json",
+		"src/lib.rs",
+		"src/billing.rs",
+		"src/server.rs",
+		"src/client.rs",
+		"src/pulserpc/mod.rs",
+		"src/pulserpc/dispatch.rs",
+		"src/bin/test_server.rs",
+		"src/bin/test_client.rs",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s: %v", name, err)
+		}
+	}
+
+	cargo := readGenerated(t, dir, "Cargo.toml")
+	for _, want := range []string{
+		"name = \"billing\"\n",
+		"serde = { version = \"1.0.215\", features = [\"derive\"] }\n",
+		"serde_json = \"1.0.140\"\n",
+		"reqwest = { version = \"0.12.19\", default-features = false, features = [\"json\", \"blocking\"] }\n",
+	} {
+		if !strings.Contains(cargo, want) {
+			t.Errorf("Cargo.toml missing %q:\n%s", want, cargo)
+		}
+	}
+
+	lib := readGenerated(t, dir, "src/lib.rs")
+	for _, want := range []string{
+		"pub mod pulserpc;\n",
+		"pub mod billing;\n",
+		"pub use server::*;\n",
+		"pub const IDL_JSON: &str = include_str!(\"../idl.json\");\n",
+	} {
+		if !strings.Contains(lib, want) {
+			t.Errorf("lib.rs missing %q:\n%s", want, lib)
+		}
+	}
+}
+
+func TestRustGeneratorCrateFlag(t *testing.T) {
+	dir := generateForTest(t, NewRustClientServer(), `namespace AcmeBilling
+
+interface Invoices {
+  total(id string) float
+}`, "-rust-crate=billing-api", "-generate-test-files=true")
+
+	cargo := readGenerated(t, dir, "Cargo.toml")
+	if !strings.Contains(cargo, "name = \"billing-api\"\n") {
+		t.Errorf("Cargo.toml should use the -rust-crate name:\n%s", cargo)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "src", "acme_billing.rs")); err != nil {
+		t.Errorf("expected namespace module acme_billing.rs: %v", err)
+	}
+	if server := readGenerated(t, dir, "src/bin/test_server.rs"); !strings.Contains(server, "use ::billing_api::*;\n") {
+		t.Errorf("test_server.rs should refer to the crate as billing_api:\n%s", server)
+	}
+}
+
+func TestRustGeneratorTypes(t *testing.T) {
+	dir := generateForTest(t, NewRustClientServer(), `namespace shop
+
+enum Status {
+  in_stock
+  sold_out
+}
+
+// An item of the catalog
+struct Item {
+  itemId   string
+  type     string
+  tags     map[string]int
+  parent   Item   [optional]
+  status   Status
+}
+
+struct Account {
+  login    string
+  password string [sensitive]
+}
+
+interface Catalog {
+  get(id string) Item [optional]
+}`)
+
+	code := readGenerated(t, dir, "src/shop.rs")
+	for _, want := range []string{
+		"use serde::{Deserialize, Serialize};\n",
+		"use std::collections::HashMap;\n",
+		"use std::fmt;\n",
+		"#[derive(Debug, Clone, Copy, PartialEq, Eq, Hash, Default, Serialize, Deserialize)]\npub enum Status {\n    #[default]\n    #[serde(rename = \"in_stock\")]\n    InStock,\n",
+		"/// An item of the catalog\n",
+		"    #[serde(rename = \"itemId\")]\n    pub item_id: String,\n",
+		"    pub item_id: String,\n    pub r#type: String,\n",
+		"    pub tags: HashMap<String, i64>,\n",
+		"    #[serde(default, skip_serializing_if = \"Option::is_none\")]\n    pub parent: Option<Box<Item>>,\n",
+		"#[derive(Clone, PartialEq, Default, Serialize, Deserialize)]\npub struct Account {\n",
+		"            .field(\"password\", &\"***\")\n",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("shop.rs missing %q:\n%s", want, code)
+		}
+	}
+}
+
+func TestRustGeneratorInterfaceInheritance(t *testing.T) {
+	dir := generateForTest(t, NewRustClientServer(), `namespace app
+
+interface Base {
+  ping() string
+}
+
+interface Users extends Base {
+  get(id string) string
+}`)
+
+	server := readGenerated(t, dir, "src/server.rs")
+	for _, want := range []string{
+		"pub trait Base: Send + Sync {\n    fn ping(&self) -> Result<String, RpcError>;\n}\n",
+		"pub trait Users: Base {\n    fn get(&self, id: String) -> Result<String, RpcError>;\n}\n",
+		"impl<T: Users> Handler for UsersHandler<T> {\n",
+		"            \"ping\" => {\n",
+		"    pub fn register_users(&mut self, handler: impl Users + 'static) {\n",
+	} {
+		if !strings.Contains(server, want) {
+			t.Errorf("server.rs missing %q:\n%s", want, server)
+		}
+	}
+
+	client := readGenerated(t, dir, "src/client.rs")
+	for _, want := range []string{
+		"    pub fn ping(&self) -> Result<String, Error> {\n        call_method(self.transport.as_ref(), \"Users.ping\", Vec::new())\n",
+		"call_method(self.transport.as_ref(), \"Users.get\", vec![encode_param(&id)?])\n",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.rs missing %q:\n%s", want, client)
+		}
+	}
+}
+
+func TestRustGeneratorWireNames(t *testing.T) {
+	dir := generateForTest(t, NewRustClientServer(), `namespace users
+interface Users [wire="v1.user"] {
+  get(id string) string
+  remove(id string) bool [wire="v1.user.delete"]
+}`)
+
+	client := readGenerated(t, dir, "src/client.rs")
+	for _, want := range []string{`"v1.user.get"`, `"v1.user.delete"`} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client.rs missing %q:\n%s", want, client)
+		}
+	}
+}
+
+func TestRustGeneratorReservedNamespace(t *testing.T) {
+	idl, err := parser.ParseIDL("test.pulse", `namespace server
+
+interface Status {
+  ping() string
+}`)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	p := NewRustClientServer()
+	fs := newTestFlagSet(t, p, t.TempDir())
+	err = p.Generate(idl, fs)
+	if err == nil || !strings.Contains(err.Error(), "conflicts with the server module") {
+		t.Fatalf("expected a module conflict error, got %v", err)
+	}
+}
+
+// cargoFetchErrors are the cargo messages of a dependency that could not be fetched
+var cargoFetchErrors = []string{"failed to download", "failed to get `", "failed to update registry", "failed to load source for dependency"}
+
+// TestRustCrateCompiles runs cargo check on the crate generated from the conform
+// fixture. It is skipped when cargo is missing or can't fetch the dependencies.
+func TestRustCrateCompiles(t *testing.T) {
+	if _, err := exec.LookPath("cargo"); err != nil {
+		t.Skip("cargo not available")
+	}
+	if testing.Short() {
+		t.Skip("cargo check is slow")
+	}
+	dir := t.TempDir()
+	generateFileForTest(t, NewRustClientServer(), filepath.Join("..", "..", "examples", "conform.pulse"), dir, "-generate-test-files=true")
+	cmd := exec.Command("cargo", "check", "--quiet", "--all-targets")
+	cmd.Dir = dir
+	// Fail fast rather than retry when the registry can't be reached
+	cmd.Env = append(os.Environ(), "CARGO_NET_RETRY=0")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		for _, msg := range cargoFetchErrors {
+			if strings.Contains(string(out), msg) {
+				t.Skipf("cargo could not fetch the dependencies:\n%s", out)
+			}
+		}
+		t.Fatalf("cargo check failed: %v\n%s", err, out)
+	}
+}
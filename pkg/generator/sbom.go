@@ -0,0 +1,204 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+	"github.com/coopernurse/pulserpc/pkg/runtime"
+)
+
+// SBOM: with -sbom every plugin also writes sbom.cdx.json, a CycloneDX 1.5 JSON
+// bill of materials of the code it ships that pulse did not derive from the IDL:
+// the runtime library copied into the output, one file component per runtime
+// file with its SHA-256 hash, and the third-party packages of the dependency
+// manifests (see deps.go) with their versions and package URLs. Packages only
+// the generated tests need have the scope "excluded". The document depends only
+// on its input, so regenerating unchanged code gives the same serial number.
+
+// sbomFile is the name of the SBOM each plugin writes into its output directory
+const sbomFile = "sbom.cdx.json"
+
+type cdxBOM struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Tools     cdxTools     `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type        string         `json:"type"`
+	BOMRef      string         `json:"bom-ref,omitempty"`
+	Group       string         `json:"group,omitempty"`
+	Name        string         `json:"name"`
+	Version     string         `json:"version,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Scope       string         `json:"scope,omitempty"`
+	Hashes      []cdxHash      `json:"hashes,omitempty"`
+	Purl        string         `json:"purl,omitempty"`
+	Components  []cdxComponent `json:"components,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// sbomRequested reports whether the -sbom flag is set
+func sbomRequested(fs *flag.FlagSet) bool {
+	f := fs.Lookup("sbom")
+	return f != nil && f.Value.String() == "true"
+}
+
+// sbomLanguages maps a runtime language to its display name and package URL type
+var sbomLanguages = map[string]struct{ Name, PurlType string }{
+	"go":     {"Go", "golang"},
+	"python": {"Python", "pypi"},
+	"ts":     {"TypeScript", "npm"},
+	"csharp": {"C#", "nuget"},
+	"java":   {"Java", "maven"},
+	"rust":   {"Rust", "cargo"},
+}
+
+// writeSBOM writes sbom.cdx.json into outputDir. runtimeDir is the directory,
+// relative to outputDir, the lang runtime files were copied to; files the plugin
+// removed after copying them are left out. frameworks are required platform
+// components that are not packages, such as the ASP.NET Core shared framework.
+func writeSBOM(outputDir string, idl *parser.IDL, lang, runtimeDir string, deps []dependency, frameworks ...string) error {
+	language, ok := sbomLanguages[lang]
+	if !ok {
+		return fmt.Errorf("no SBOM support for language %q", lang)
+	}
+	runtimeFiles, err := runtime.GetRuntimeFiles(lang)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(runtimeFiles))
+	for name := range runtimeFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	runtimeComponent := cdxComponent{
+		Type:        "library",
+		BOMRef:      "pulserpc-runtime",
+		Name:        "pulserpc-runtime-" + lang,
+		Description: "PulseRPC " + language.Name + " runtime library copied into the generated code",
+	}
+	for _, name := range names {
+		// Read the files as written, since some plugins rewrite them while copying
+		data, err := os.ReadFile(filepath.Join(outputDir, runtimeDir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read runtime file %s: %w", name, err)
+		}
+		sum := sha256.Sum256(data)
+		runtimeComponent.Components = append(runtimeComponent.Components, cdxComponent{
+			Type:   "file",
+			Name:   path.Join(filepath.ToSlash(runtimeDir), name),
+			Hashes: []cdxHash{{Alg: "SHA-256", Content: hex.EncodeToString(sum[:])}},
+		})
+	}
+
+	components := []cdxComponent{runtimeComponent}
+	dependsOn := []string{runtimeComponent.BOMRef}
+	for _, name := range frameworks {
+		components = append(components, cdxComponent{Type: "framework", BOMRef: name, Name: name, Scope: "required"})
+		dependsOn = append(dependsOn, name)
+	}
+	for _, d := range deps {
+		c := cdxComponent{Type: "library", Name: d.Name, Version: d.Version, Scope: "required"}
+		if language.PurlType == "maven" {
+			c.Group, c.Name = d.GroupID(), d.ArtifactID()
+		}
+		c.Purl = dependencyPurl(language.PurlType, d)
+		c.BOMRef = c.Purl
+		if d.Test {
+			c.Scope = "excluded"
+		}
+		components = append(components, c)
+		dependsOn = append(dependsOn, c.BOMRef)
+	}
+
+	name := idl.RootNamespace
+	if name == "" {
+		name = "pulserpc-generated"
+	}
+	bom := cdxBOM{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: cdxMetadata{
+			Tools: cdxTools{Components: []cdxComponent{{Type: "application", Name: "pulse", Description: "PulseRPC code generator"}}},
+			Component: cdxComponent{
+				Type:        "library",
+				BOMRef:      "generated",
+				Name:        name,
+				Description: language.Name + " code generated by pulse",
+			},
+		},
+		Components:   components,
+		Dependencies: []cdxDependency{{Ref: "generated", DependsOn: dependsOn}},
+	}
+	content, err := json.Marshal(bom)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", sbomFile, err)
+	}
+	bom.SerialNumber = sbomSerialNumber(content)
+	if content, err = json.MarshalIndent(bom, "", "  "); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", sbomFile, err)
+	}
+	if err := writeGeneratedFile(filepath.Join(outputDir, sbomFile), append(content, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s: %w", sbomFile, err)
+	}
+	return nil
+}
+
+// dependencyPurl returns the package URL of a dependency
+func dependencyPurl(purlType string, d dependency) string {
+	switch purlType {
+	case "maven":
+		return fmt.Sprintf("pkg:maven/%s/%s@%s", d.GroupID(), d.ArtifactID(), d.Version)
+	case "pypi":
+		// PyPI names are case insensitive and package URLs use the lowercase form
+		return fmt.Sprintf("pkg:pypi/%s@%s", strings.ToLower(d.Name), d.Version)
+	default:
+		return fmt.Sprintf("pkg:%s/%s@%s", purlType, d.Name, d.Version)
+	}
+}
+
+// sbomSerialNumber derives a version 5 style UUID URN from the document content
+func sbomSerialNumber(content []byte) string {
+	sum := sha256.Sum256(content)
+	b := sum[:16]
+	b[6] = b[6]&0x0f | 0x50
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
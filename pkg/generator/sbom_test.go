@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestSBOM(t *testing.T) {
+	idl := &parser.IDL{
+		RootNamespace: "catalog",
+		Interfaces: []*parser.Interface{
+			{
+				Name: "Catalog",
+				Methods: []*parser.Method{
+					{Name: "ping", ReturnType: &parser.Type{BuiltIn: "string"}},
+				},
+			},
+		},
+	}
+
+	generate := func(t *testing.T, dir string) cdxBOM {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		fs.Bool("generate-test-harness", false, "generate test harness")
+		fs.Bool("sbom", false, "write an SBOM")
+		fs.String("dependency-versions", "", "dependency versions")
+		plugin := NewJavaClientServer()
+		plugin.RegisterFlags(fs)
+		for name, value := range map[string]string{
+			"dir":                   dir,
+			"sbom":                  "true",
+			"base-package":          "com.example",
+			"json-lib":              "gson",
+			"generate-test-harness": "true",
+			"dependency-versions":   "com.google.code.gson:gson=2.11.0",
+		} {
+			if err := fs.Set(name, value); err != nil {
+				t.Fatalf("failed to set %s flag: %v", name, err)
+			}
+		}
+		if err := plugin.Generate(idl, fs); err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		content, err := os.ReadFile(filepath.Join(dir, sbomFile))
+		if err != nil {
+			t.Fatalf("expected %s: %v", sbomFile, err)
+		}
+		var bom cdxBOM
+		if err := json.Unmarshal(content, &bom); err != nil {
+			t.Fatalf("invalid %s: %v", sbomFile, err)
+		}
+		return bom
+	}
+
+	bom := generate(t, t.TempDir())
+	if bom.BOMFormat != "CycloneDX" || bom.SpecVersion != "1.5" || !strings.HasPrefix(bom.SerialNumber, "urn:uuid:") {
+		t.Errorf("unexpected header: %+v", bom)
+	}
+	if bom.Metadata.Component.Name != "catalog" {
+		t.Errorf("expected the metadata component to be named after the root namespace, got %q", bom.Metadata.Component.Name)
+	}
+
+	runtimeFiles := map[string]bool{}
+	for _, f := range bom.Components[0].Components {
+		if len(f.Hashes) != 1 || f.Hashes[0].Alg != "SHA-256" || len(f.Hashes[0].Content) != 64 {
+			t.Errorf("expected a SHA-256 hash for %s, got %+v", f.Name, f.Hashes)
+		}
+		runtimeFiles[f.Name] = true
+	}
+	if !runtimeFiles["src/main/java/com/bitmechanic/pulserpc/GsonJsonParser.java"] {
+		t.Errorf("expected the Gson parser in the runtime files: %v", runtimeFiles)
+	}
+	if runtimeFiles["src/main/java/com/bitmechanic/pulserpc/JacksonJsonParser.java"] {
+		t.Error("the removed Jackson parser must not be listed")
+	}
+
+	scopes := map[string]string{}
+	for _, c := range bom.Components[1:] {
+		scopes[c.Purl] = c.Scope
+	}
+	if scopes["pkg:maven/com.google.code.gson/gson@2.11.0"] != "required" {
+		t.Errorf("expected gson 2.11.0 as a required component, got %v", scopes)
+	}
+	if scopes["pkg:maven/org.junit.jupiter/junit-jupiter@5.10.2"] != "excluded" {
+		t.Errorf("expected junit-jupiter as an excluded component, got %v", scopes)
+	}
+	if len(bom.Dependencies) != 1 || len(bom.Dependencies[0].DependsOn) != len(bom.Components) {
+		t.Errorf("expected the generated code to depend on every component, got %+v", bom.Dependencies)
+	}
+
+	if again := generate(t, t.TempDir()); again.SerialNumber != bom.SerialNumber {
+		t.Errorf("expected regenerating to keep the serial number, got %s and %s", bom.SerialNumber, again.SerialNumber)
+	}
+}
+
+func TestDependencyPurl(t *testing.T) {
+	tests := map[string]struct {
+		purlType string
+		dep      dependency
+	}{
+		"pkg:golang/go.uber.org/mock@v0.5.0":               {"golang", dependency{Name: "go.uber.org/mock", Version: "v0.5.0"}},
+		"pkg:pypi/pytest@8.3.3":                            {"pypi", dependency{Name: "PyTest", Version: "8.3.3"}},
+		"pkg:nuget/xunit.v3@2.0.3":                         {"nuget", dependency{Name: "xunit.v3", Version: "2.0.3"}},
+		"pkg:maven/com.google.code.gson/gson@2.10.1":       {"maven", dependency{Name: "com.google.code.gson:gson", Version: "2.10.1"}},
+		"pkg:maven/org.junit.jupiter/junit-jupiter@5.10.2": {"maven", dependency{Name: "org.junit.jupiter:junit-jupiter", Version: "5.10.2"}},
+	}
+	for want, tt := range tests {
+		if got := dependencyPurl(tt.purlType, tt.dep); got != want {
+			t.Errorf("dependencyPurl(%s, %s) = %q, want %q", tt.purlType, tt.dep.Name, got, want)
+		}
+	}
+}
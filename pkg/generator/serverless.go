@@ -0,0 +1,24 @@
+package generator
+
+import (
+	"flag"
+)
+
+// The -generate-serverless-adapter flag emits adapters that run the Go, Python and
+// C# servers as serverless functions: an AWS Lambda handler for API Gateway proxy
+// events and a Cloud Functions HTTP entry point. Both hand the request to the same
+// code path as the HTTP server, so content type checks, request verification,
+// [readonly] GET routes and batches behave the same. The Lambda event types are
+// declared in the generated code, so no cloud SDK is required.
+
+// serverlessAdapterRequested reports whether the -generate-serverless-adapter flag is set
+func serverlessAdapterRequested(fs *flag.FlagSet) bool {
+	f := fs.Lookup("generate-serverless-adapter")
+	return f != nil && f.Value.String() == "true"
+}
+
+// serverlessView is the view model for the serverless adapter templates
+type serverlessView struct {
+	// Package is the Go package of the generated server
+	Package string
+}
@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"flag"
+)
+
+// The -generate-shadow-client flag emits a ShadowTransport next to each client.
+// It wraps two transports: every call goes to the primary, whose outcome is
+// returned to the caller, and is mirrored asynchronously to the shadow. The two
+// outcomes are compared and differences are reported to a user callback, so a new
+// server implementation can be validated against the old one with real traffic.
+
+// shadowClientRequested reports whether the -generate-shadow-client flag is set
+func shadowClientRequested(fs *flag.FlagSet) bool {
+	f := fs.Lookup("generate-shadow-client")
+	return f != nil && f.Value.String() == "true"
+}
+
+// shadowView is the view model for the ShadowTransport templates
+type shadowView struct {
+	// Package is the Go or Java package of the generated client
+	Package string
+	// RuntimeImport is the Go runtime package to dot-import when namespaces are
+	// split into packages
+	RuntimeImport string
+	// Packaged is true when Python output is a package using relative imports
+	Packaged bool
+	// Transport, ClassName and Mismatch are the TypeScript class names, which
+	// carry the -package prefix
+	Transport string
+	ClassName string
+	Mismatch  string
+}
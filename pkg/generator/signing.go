@@ -0,0 +1,17 @@
+package generator
+
+// Every client and server gets request signing helpers, written next to them as
+// signing.* (or Signing.cs, RequestSigning.java). Transports accept a signer that
+// computes headers from the serialized request body and servers accept a verifier
+// that checks them before dispatching. The built-in pair signs with HMAC-SHA256
+// over the timestamp and body so partners sharing a secret can authenticate
+// webhook-style requests; any language's signer works with any language's verifier.
+
+// signingView is the view model for the signing templates
+type signingView struct {
+	// Package is the Go or Java package of the generated code
+	Package string
+	// The TypeScript names below carry the -package prefix
+	RequestSigner   string
+	RequestVerifier string
+}
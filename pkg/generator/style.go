@@ -0,0 +1,618 @@
+package generator
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Code style: -style takes comma separated key=value pairs that restyle the
+// generated Python, TypeScript, Java and C# files so they pass a team's linters
+// as they are:
+//
+//	indent=N                     spaces per indentation level (all four)
+//	quotes=single|double         the quote of Python string literals
+//	braces=same-line|next-line   K&R or Allman braces (Java, C#)
+//	getters=get|record           getX() or record-style x() accessors (Java structs)
+//
+// The files are restyled after they are written, by a lexer that knows each
+// language's strings and comments, so literals are never changed: lines that
+// start inside a multi-line string keep their indentation, and a Python literal
+// keeps its quote when the body contains either quote character. Only files
+// marked "Generated by pulserpc - do not edit" are restyled; the runtime library
+// and the starting-point files written once for you keep their layout. Go output
+// is formatted by gofmt, so -style has no Go keys, and line length is not
+// restyled.
+
+// codeStyle is the layout of one language's generated code
+type codeStyle struct {
+	// Indent is the number of spaces per indentation level
+	Indent int
+	// Braces is "same-line" or "next-line" (Java and C#)
+	Braces string
+	// Quotes is "single", "double" or empty to keep the quotes as generated (Python)
+	Quotes string
+	// Getters is "get" or "record" (Java)
+	Getters string
+}
+
+// defaultCodeStyles is the layout each language is generated in
+var defaultCodeStyles = map[string]codeStyle{
+	"go":     {},
+	"python": {Indent: 4},
+	"ts":     {Indent: 2},
+	"java":   {Indent: 4, Braces: "same-line", Getters: "get"},
+	"csharp": {Indent: 4, Braces: "next-line"},
+}
+
+// codeStyleKeys are the -style keys each language supports
+var codeStyleKeys = map[string][]string{
+	"go":     nil,
+	"python": {"indent", "quotes"},
+	"ts":     {"indent"},
+	"java":   {"braces", "getters", "indent"},
+	"csharp": {"braces", "indent"},
+}
+
+// parseCodeStyle returns the default style of language with the overrides of -style
+func parseCodeStyle(flags *flag.FlagSet, language string) (codeStyle, error) {
+	style := defaultCodeStyles[language]
+	f := flags.Lookup("style")
+	if f == nil {
+		return style, nil
+	}
+	for _, pair := range strings.Split(f.Value.String(), ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return style, fmt.Errorf("invalid style entry %q (expected key=value)", pair)
+		}
+		if !containsString(codeStyleKeys[language], key) {
+			if len(codeStyleKeys[language]) == 0 {
+				return style, fmt.Errorf("style %q is not supported for %s output, which is formatted by gofmt", key, language)
+			}
+			return style, fmt.Errorf("style %q is not supported for %s output (supported: %s)", key, language, strings.Join(codeStyleKeys[language], ", "))
+		}
+		switch key {
+		case "indent":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 8 {
+				return style, fmt.Errorf("invalid style indent=%s (expected 1 to 8 spaces)", value)
+			}
+			style.Indent = n
+		case "quotes":
+			if value != "single" && value != "double" {
+				return style, fmt.Errorf("invalid style quotes=%s (expected single or double)", value)
+			}
+			style.Quotes = value
+		case "braces":
+			if value != "same-line" && value != "next-line" {
+				return style, fmt.Errorf("invalid style braces=%s (expected same-line or next-line)", value)
+			}
+			style.Braces = value
+		case "getters":
+			if value != "get" && value != "record" {
+				return style, fmt.Errorf("invalid style getters=%s (expected get or record)", value)
+			}
+			style.Getters = value
+		}
+	}
+	return style, nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// codeStyleExtensions are the extensions of each language's generated files
+var codeStyleExtensions = map[string]string{
+	"python": ".py",
+	"ts":     ".ts",
+	"java":   ".java",
+	"csharp": ".cs",
+}
+
+// restyleGeneratedFiles restyles the generated files of language under dirs. It does
+// nothing when style is the language's default layout.
+func restyleGeneratedFiles(language string, style codeStyle, dirs ...string) error {
+	if style == defaultCodeStyles[language] {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || seen[path] || filepath.Ext(path) != codeStyleExtensions[language] {
+				return err
+			}
+			seen[path] = true
+			generated, err := isDoNotEditFile(path)
+			if err != nil || !generated {
+				return err
+			}
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(path, []byte(restyleSource(language, defaultCodeStyles[language], style, string(src))), 0644)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to restyle generated code: %w", err)
+		}
+	}
+	return nil
+}
+
+// isDoNotEditFile reports whether the first line of the file at path marks it as
+// generated code that is rewritten on every run
+func isDoNotEditFile(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && line == "" {
+		return false, nil
+	}
+	return strings.Contains(line, "Generated by pulserpc - do not edit"), nil
+}
+
+// restyleSource converts src from the from layout to the to layout
+func restyleSource(language string, from, to codeStyle, src string) string {
+	if to.Braces != from.Braces {
+		if to.Braces == "next-line" {
+			src = bracesToNextLine(language, src)
+		} else {
+			src = bracesToSameLine(language, src)
+		}
+	}
+	if to.Quotes != "" && to.Quotes != from.Quotes {
+		src = requotePython(src, to.Quotes)
+	}
+	if to.Indent != from.Indent {
+		src = reindent(language, src, from.Indent, to.Indent)
+	}
+	return src
+}
+
+// sourceLine is one line of src and where it starts
+type sourceLine struct {
+	text  string
+	start int
+}
+
+func splitSourceLines(src string) []sourceLine {
+	var lines []sourceLine
+	start := 0
+	for i := 0; i < len(src); i++ {
+		if src[i] == '\n' {
+			lines = append(lines, sourceLine{src[start:i], start})
+			start = i + 1
+		}
+	}
+	return append(lines, sourceLine{src[start:], start})
+}
+
+// inString reports whether the line starting at offset continues a multi-line string
+func inString(class []byte, offset int) bool {
+	return offset > 0 && class[offset-1] == classString
+}
+
+// reindent rescales indentation from width from to width to. Lines indented by a
+// multiple of from are rescaled; other lines, which align with something on an
+// earlier line, move by as much as the last rescaled line did. Lines inside
+// multi-line strings are left alone, except the body of Python docstrings.
+func reindent(language, src string, from, to int) string {
+	class := classifySource(language, src)
+	lines := splitSourceLines(src)
+	var sb strings.Builder
+	delta := 0
+	for i, line := range lines {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		text := line.text
+		body := strings.TrimLeft(text, " ")
+		n := len(text) - len(body)
+		if body == "" || body[0] == '\t' || (inString(class, line.start) && !(language == "python" && startsDocstring(src, class, line.start))) {
+			sb.WriteString(text)
+			continue
+		}
+		indent := n + delta
+		if n%from == 0 {
+			indent = n / from * to
+			delta = indent - n
+		}
+		if indent < 0 {
+			indent = 0
+		}
+		sb.WriteString(strings.Repeat(" ", indent))
+		sb.WriteString(body)
+	}
+	return sb.String()
+}
+
+// startsDocstring reports whether the string that the line at offset continues
+// begins its line, as a docstring does
+func startsDocstring(src string, class []byte, offset int) bool {
+	start := offset - 1
+	for start > 0 && class[start-1] == classString {
+		start--
+	}
+	lineStart := strings.LastIndexByte(src[:start], '\n') + 1
+	return strings.TrimSpace(src[lineStart:start]) == ""
+}
+
+// lastCodeByte returns the last non-blank byte of the line at offset when it is
+// code rather than string or comment, or 0
+func lastCodeByte(line sourceLine, class []byte) byte {
+	trimmed := strings.TrimRight(line.text, " \t\r")
+	if trimmed == "" || class[line.start+len(trimmed)-1] != classCode {
+		return 0
+	}
+	return trimmed[len(trimmed)-1]
+}
+
+// startsWithKeyword reports whether the code s begins with one of the words
+func startsWithKeyword(s string, words ...string) bool {
+	for _, word := range words {
+		if strings.HasPrefix(s, word) && (len(s) == len(word) || !isIdentByte(s[len(word)])) {
+			return true
+		}
+	}
+	return false
+}
+
+// bracesToNextLine moves every opening brace that ends a line onto a line of its
+// own, and splits "} else {", "} catch (...) {" and "} finally {" into three lines
+func bracesToNextLine(language, src string) string {
+	class := classifySource(language, src)
+	var out []string
+	for _, line := range splitSourceLines(src) {
+		if inString(class, line.start) || lastCodeByte(line, class) != '{' {
+			out = append(out, line.text)
+			continue
+		}
+		body := strings.TrimLeft(line.text, " ")
+		indent := line.text[:len(line.text)-len(body)]
+		head := strings.TrimRight(strings.TrimSuffix(strings.TrimRight(body, " \t\r"), "{"), " ")
+		if head == "" {
+			out = append(out, line.text)
+			continue
+		}
+		if rest := strings.TrimPrefix(head, "} "); rest != head && startsWithKeyword(rest, "else", "catch", "finally") {
+			out = append(out, indent+"}")
+			head = rest
+		}
+		out = append(out, indent+head, indent+"{")
+	}
+	return strings.Join(out, "\n")
+}
+
+// bracesToSameLine joins every line that is only an opening brace onto the line
+// before it, and "else", "catch" and "finally" onto the closing brace before them
+func bracesToSameLine(language, src string) string {
+	class := classifySource(language, src)
+	var out []string
+	var outLast []byte // the last code byte of each line of out, or 0
+	for _, line := range splitSourceLines(src) {
+		body := strings.TrimSpace(line.text)
+		if len(out) > 0 && !inString(class, line.start) && body != "" && class[line.start+strings.Index(line.text, body)] == classCode {
+			prev := len(out) - 1
+			prevBody := strings.TrimSpace(out[prev])
+			switch {
+			case body == "{" && !strings.ContainsRune("\x00;{},", rune(outLast[prev])) && !strings.HasPrefix(prevBody, "#"):
+				out[prev] = strings.TrimRight(out[prev], " \t\r") + " {"
+				outLast[prev] = '{'
+				continue
+			case prevBody == "}" && startsWithKeyword(body, "else", "catch", "finally"):
+				out[prev] = strings.TrimRight(out[prev], " \t\r") + " " + strings.TrimRight(body, "\r")
+				outLast[prev] = lastCodeByte(line, class)
+				continue
+			}
+		}
+		out = append(out, line.text)
+		outLast = append(outLast, lastCodeByte(line, class))
+	}
+	return strings.Join(out, "\n")
+}
+
+// requotePython changes the quote of single-line Python string literals to quotes
+// ("single" or "double") where the body has no quote character to escape
+func requotePython(src, quotes string) string {
+	class := classifySource("python", src)
+	target, other := byte('"'), byte('\'')
+	if quotes == "single" {
+		target, other = '\'', '"'
+	}
+	out := []byte(src)
+	for i := 0; i < len(src); {
+		if class[i] != classString {
+			i++
+			continue
+		}
+		end := i
+		for end < len(src) && class[end] == classString {
+			end++
+		}
+		token := src[i:end]
+		q := strings.IndexAny(token, "'\"")
+		if q >= 0 && token[q] == other && len(token) >= q+2 && token[len(token)-1] == other && !strings.HasPrefix(token[q:], strings.Repeat(string(other), 3)) {
+			if body := token[q+1 : len(token)-1]; !strings.ContainsAny(body, "'\"") {
+				out[i+q] = target
+				out[end-1] = target
+			}
+		}
+		i = end
+	}
+	return string(out)
+}
+
+// Source classes of classifySource
+const (
+	classCode    = 'c'
+	classString  = 's'
+	classComment = 'm'
+)
+
+// classifySource returns the class of every byte of src: code, string (string,
+// character and regular expression literals, prefixes and quotes included) or
+// comment. Interpolated expressions inside template and interpolated strings are
+// code.
+func classifySource(language, src string) []byte {
+	l := &sourceLexer{language: language, src: src, class: make([]byte, len(src))}
+	l.code(0, false)
+	return l.class
+}
+
+type sourceLexer struct {
+	language string
+	src      string
+	class    []byte
+}
+
+func (l *sourceLexer) mark(from, to int, class byte) {
+	if to > len(l.src) {
+		to = len(l.src)
+	}
+	for i := from; i < to; i++ {
+		l.class[i] = class
+	}
+}
+
+// code classifies code from i to the end of src or, when hole is set, to the '}'
+// that closes an interpolated expression, whose index it returns
+func (l *sourceLexer) code(i int, hole bool) int {
+	depth := 0
+	for i < len(l.src) {
+		if end := l.literal(i); end > i {
+			i = end
+			continue
+		}
+		switch l.src[i] {
+		case '{':
+			depth++
+		case '}':
+			if hole && depth == 0 {
+				return i
+			}
+			depth--
+		}
+		l.class[i] = classCode
+		i++
+	}
+	return i
+}
+
+// literal classifies the comment or literal starting at i and returns its end, or i
+func (l *sourceLexer) literal(i int) int {
+	s := l.src[i:]
+	if l.language == "python" {
+		switch s[0] {
+		case '#':
+			return l.lineComment(i)
+		case '\'', '"':
+			return l.pythonString(i)
+		}
+		return i
+	}
+	switch {
+	case strings.HasPrefix(s, "//"):
+		return l.lineComment(i)
+	case strings.HasPrefix(s, "/*"):
+		end := strings.Index(s[2:], "*/")
+		if end < 0 {
+			end = len(s)
+		} else {
+			end += 4
+		}
+		l.mark(i, i+end, classComment)
+		return i + end
+	}
+	switch l.language {
+	case "ts":
+		switch s[0] {
+		case '\'', '"':
+			return l.quoted(i, i+1, s[0], true)
+		case '`':
+			return l.interpolated(i, i+1, '`', "${", true)
+		case '/':
+			if l.regexAllowed(i) {
+				return l.regex(i)
+			}
+		}
+	case "java":
+		switch {
+		case strings.HasPrefix(s, `"""`):
+			return l.closedBy(i, i+3, `"""`)
+		case s[0] == '"' || s[0] == '\'':
+			return l.quoted(i, i+1, s[0], true)
+		}
+	case "csharp":
+		switch {
+		case strings.HasPrefix(s, `$@"`) || strings.HasPrefix(s, `@$"`):
+			return l.interpolated(i, i+3, '"', "{", false)
+		case strings.HasPrefix(s, `$"`):
+			return l.interpolated(i, i+2, '"', "{", true)
+		case strings.HasPrefix(s, `@"`):
+			return l.quoted(i, i+2, '"', false)
+		case strings.HasPrefix(s, `"""`):
+			return l.closedBy(i, i+3, `"""`)
+		case s[0] == '"' || s[0] == '\'':
+			return l.quoted(i, i+1, s[0], true)
+		}
+	}
+	return i
+}
+
+func (l *sourceLexer) lineComment(i int) int {
+	end := strings.IndexByte(l.src[i:], '\n')
+	if end < 0 {
+		end = len(l.src) - i
+	}
+	l.mark(i, i+end, classComment)
+	return i + end
+}
+
+// closedBy classifies a string whose body starts at j and ends with delim
+func (l *sourceLexer) closedBy(i, j int, delim string) int {
+	for j < len(l.src) && !strings.HasPrefix(l.src[j:], delim) {
+		if l.src[j] == '\\' {
+			j++
+		}
+		j++
+	}
+	end := j + len(delim)
+	l.mark(i, end, classString)
+	return min(end, len(l.src))
+}
+
+// quoted classifies a string whose body starts at j and ends with q. With escapes,
+// a backslash escapes the next byte and the string ends at the end of the line;
+// without (C# verbatim strings) a doubled quote is a quote.
+func (l *sourceLexer) quoted(i, j int, q byte, escapes bool) int {
+	for j < len(l.src) {
+		c := l.src[j]
+		switch {
+		case escapes && c == '\\':
+			j += 2
+			continue
+		case escapes && c == '\n':
+			l.mark(i, j, classString)
+			return j
+		case c == q && !escapes && j+1 < len(l.src) && l.src[j+1] == q:
+			j += 2
+			continue
+		case c == q:
+			l.mark(i, j+1, classString)
+			return j + 1
+		}
+		j++
+	}
+	l.mark(i, j, classString)
+	return len(l.src)
+}
+
+// interpolated classifies a template (TypeScript) or interpolated (C#) string whose
+// body starts at j and ends with q, and the code of the expressions in it
+func (l *sourceLexer) interpolated(i, j int, q byte, open string, escapes bool) int {
+	for j < len(l.src) {
+		c := l.src[j]
+		switch {
+		case escapes && c == '\\':
+			j += 2
+			continue
+		case !escapes && c == q && j+1 < len(l.src) && l.src[j+1] == q:
+			j += 2
+			continue
+		case c == q:
+			l.mark(i, j+1, classString)
+			return j + 1
+		case open == "{" && strings.HasPrefix(l.src[j:], "{{"):
+			j += 2
+			continue
+		case strings.HasPrefix(l.src[j:], open):
+			l.mark(i, j+len(open), classString)
+			end := l.code(j+len(open), true)
+			if end >= len(l.src) {
+				return end
+			}
+			i, j = end, end+1
+			continue
+		}
+		j++
+	}
+	l.mark(i, j, classString)
+	return len(l.src)
+}
+
+// pythonString classifies a Python string literal and its prefix
+func (l *sourceLexer) pythonString(i int) int {
+	start := i
+	for start > 0 && i-start < 2 && strings.IndexByte("rRbBuUfF", l.src[start-1]) >= 0 {
+		start--
+	}
+	if start > 0 && isIdentByte(l.src[start-1]) {
+		start = i
+	}
+	q := l.src[i]
+	if strings.HasPrefix(l.src[i:], strings.Repeat(string(q), 3)) {
+		return l.closedBy(start, i+3, strings.Repeat(string(q), 3))
+	}
+	return l.quoted(start, i+1, q, true)
+}
+
+// regexAllowed reports whether a '/' at i starts a regular expression rather than
+// dividing, judging by the code before it
+func (l *sourceLexer) regexAllowed(i int) bool {
+	j := i - 1
+	for j >= 0 && (l.class[j] == classComment || l.src[j] == ' ' || l.src[j] == '\t' || l.src[j] == '\n') {
+		j--
+	}
+	if j < 0 || strings.IndexByte("(,=:[!&|?{};+-*%<>~^", l.src[j]) >= 0 {
+		return true
+	}
+	return strings.HasSuffix(l.src[:j+1], "return") && (j < 6 || !isIdentByte(l.src[j-6]))
+}
+
+func (l *sourceLexer) regex(i int) int {
+	j, inClass := i+1, false
+	for j < len(l.src) && l.src[j] != '\n' {
+		c := l.src[j]
+		switch {
+		case c == '\\':
+			j++
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			j++
+			for j < len(l.src) && isIdentByte(l.src[j]) {
+				j++
+			}
+			l.mark(i, j, classString)
+			return j
+		}
+		j++
+	}
+	l.mark(i, j, classString)
+	return min(j, len(l.src))
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,138 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func TestParseCodeStyle(t *testing.T) {
+	tests := []struct {
+		language string
+		value    string
+		want     codeStyle
+		wantErr  string
+	}{
+		{"java", "", codeStyle{Indent: 4, Braces: "same-line", Getters: "get"}, ""},
+		{"java", " indent=2 , braces=next-line,getters=record", codeStyle{Indent: 2, Braces: "next-line", Getters: "record"}, ""},
+		{"python", "quotes=double", codeStyle{Indent: 4, Quotes: "double"}, ""},
+		{"csharp", "braces=same-line", codeStyle{Indent: 4, Braces: "same-line"}, ""},
+		{"ts", "quotes=single", codeStyle{}, `style "quotes" is not supported for ts output (supported: indent)`},
+		{"go", "indent=2", codeStyle{}, "formatted by gofmt"},
+		{"python", "indent=0", codeStyle{}, "invalid style indent=0"},
+		{"java", "braces=allman", codeStyle{}, "invalid style braces=allman"},
+		{"java", "indent", codeStyle{}, `invalid style entry "indent"`},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("style", "", "code style")
+		if err := fs.Set("style", tt.value); err != nil {
+			t.Fatalf("failed to set style flag: %v", err)
+		}
+		got, err := parseCodeStyle(fs, tt.language)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s %q: error = %v, want %q", tt.language, tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s %q = %+v, %v, want %+v", tt.language, tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestRestyleSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		to       codeStyle
+		src      string
+		want     string
+	}{
+		{
+			name:     "python indent and quotes",
+			language: "python",
+			to:       codeStyle{Indent: 2, Quotes: "double"},
+			src: "class A:\n    def f(self, x: int,\n          y: str) -> str:\n        \"\"\"Doc\n        more\"\"\"\n" +
+				"        s = '''keep\n    this'''\n        return f'{x}' + 'it\"s' + b'k' + 'a # b'  # 'comment'\n",
+			want: "class A:\n  def f(self, x: int,\n        y: str) -> str:\n    \"\"\"Doc\n    more\"\"\"\n" +
+				"    s = '''keep\n    this'''\n    return f\"{x}\" + 'it\"s' + b\"k\" + \"a # b\"  # 'comment'\n",
+		},
+		{
+			name:     "typescript template literals and regular expressions",
+			language: "ts",
+			to:       codeStyle{Indent: 4},
+			src:      "function f(s: string) {\n  const t = `a ${s.replace(/'/g, '')} {\n  b`;\n  /**\n   * Doc\n   */\n  return t;\n}\n",
+			want:     "function f(s: string) {\n    const t = `a ${s.replace(/'/g, '')} {\n  b`;\n    /**\n     * Doc\n     */\n    return t;\n}\n",
+		},
+		{
+			name:     "java braces to next line",
+			language: "java",
+			to:       codeStyle{Indent: 4, Braces: "next-line", Getters: "get"},
+			src:      "class A {\n    void f() {\n        if (x) {\n            g(\"{\");\n        } else if (y) {\n            h('{');\n        } else {\n            return;\n        }\n    }\n}\n",
+			want: "class A\n{\n    void f()\n    {\n        if (x)\n        {\n            g(\"{\");\n        }\n        else if (y)\n        {\n            h('{');\n        }\n" +
+				"        else\n        {\n            return;\n        }\n    }\n}\n",
+		},
+		{
+			name:     "csharp braces to same line",
+			language: "csharp",
+			to:       codeStyle{Indent: 2, Braces: "same-line"},
+			src: "namespace N\n{\n    class A\n    {\n        string s = @\"{\n    \"\"x\"\": 1\n}\";\n        void F()\n        {\n" +
+				"            try\n            {\n                G($\"{(a ? $\"[{b}]\" : \"}\")}\");\n            }\n            catch (E e)\n            {\n            }\n        }\n    }\n}\n",
+			want: "namespace N {\n  class A {\n    string s = @\"{\n    \"\"x\"\": 1\n}\";\n    void F() {\n" +
+				"      try {\n        G($\"{(a ? $\"[{b}]\" : \"}\")}\");\n      } catch (E e) {\n      }\n    }\n  }\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := restyleSource(tt.language, defaultCodeStyles[tt.language], tt.to, tt.src); got != tt.want {
+			t.Errorf("%s:\ngot:\n%s\nwant:\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCodeStyleGenerated(t *testing.T) {
+	idl, err := parser.ParseIDL("shop.pulse", "namespace shop\n\nstruct Item {\n  itemId string\n}\n\ninterface Catalog {\n  get(itemId string) Item\n}")
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	tests := []struct {
+		plugin Plugin
+		style  string
+		file   string
+		want   string
+	}{
+		{NewJavaClientServer(), "getters=record,braces=next-line", "src/main/java/com/example/shop/Item.java", "    public String itemId()\n    {\n"},
+		{NewPythonClientServer(), "indent=2", "server.py", "\n  def register(self"},
+		{NewTSClientServer(), "indent=4", "server.ts", "\n    register("},
+		{NewCSharpClientServer(), "braces=same-line", "Server.cs", "public partial class PulseRPCServer {\n"},
+	}
+	for _, tt := range tests {
+		tmpDir := t.TempDir()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("dir", "", "output dir")
+		fs.String("style", "", "code style")
+		tt.plugin.RegisterFlags(fs)
+		for name, value := range map[string]string{"dir": tmpDir, "style": tt.style, "base-package": "com.example"} {
+			if fs.Lookup(name) != nil {
+				if err := fs.Set(name, value); err != nil {
+					t.Fatalf("failed to set %s flag: %v", name, err)
+				}
+			}
+		}
+		if err := tt.plugin.Generate(idl, fs); err != nil {
+			t.Fatalf("%s: Generate failed: %v", tt.plugin.Name(), err)
+		}
+		content, err := os.ReadFile(filepath.Join(tmpDir, tt.file))
+		if err != nil {
+			t.Fatalf("%s: expected %s: %v", tt.plugin.Name(), tt.file, err)
+		}
+		if !strings.Contains(string(content), tt.want) {
+			t.Errorf("%s: %s does not contain %q", tt.plugin.Name(), tt.file, tt.want)
+		}
+	}
+}
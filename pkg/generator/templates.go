@@ -0,0 +1,247 @@
+package generator
+
+import (
+	"embed"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Generated artifacts are rendered from embedded text/template files under
+// templates/<language>/. Each template is executed with a typed view model built by
+// the generator, so the template only deals with layout and the generator keeps all
+// naming and type-mapping decisions. Large artifacts, such as go/server.go.tmpl, split
+// their sections into {{define}} blocks named after the file, e.g.
+// "go/server.handleRequest".
+//
+// Users can replace any template with -template-dir: a file there at the same path
+// relative to the directory, such as go/server.go.tmpl, is parsed in place of the
+// embedded one. It gets the same view model, so a copy of the embedded template is the
+// starting point for an override (see 'pulse templates export').
+//
+//go:embed templates
+var templateFiles embed.FS
+
+// templateSet is the set of templates one generation renders from
+type templateSet struct {
+	root *template.Template
+	// overridden reports that templates were loaded from a -template-dir, whose
+	// failures are the user's errors rather than the generator's
+	overridden bool
+}
+
+// embeddedTemplates holds every embedded template, named by its path relative to
+// templates/ (e.g. "java/pom.xml.tmpl")
+var embeddedTemplates = &templateSet{root: parseTemplates()}
+
+func parseTemplates() *template.Template {
+	root := template.New("")
+	for _, name := range TemplateNames() {
+		content, err := templateFiles.ReadFile("templates/" + name)
+		if err == nil {
+			_, err = root.New(name).Parse(string(content))
+		}
+		if err != nil {
+			panic(fmt.Sprintf("failed to parse embedded template %s: %v", name, err))
+		}
+	}
+	return root
+}
+
+// TemplateNames returns the names of the embedded templates in sorted order
+func TemplateNames() []string {
+	var names []string
+	fs.WalkDir(templateFiles, "templates", func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			names = append(names, strings.TrimPrefix(path, "templates/"))
+		}
+		return err
+	})
+	sort.Strings(names)
+	return names
+}
+
+// EmbeddedTemplate returns the source of the named embedded template
+func EmbeddedTemplate(name string) ([]byte, error) {
+	return templateFiles.ReadFile("templates/" + name)
+}
+
+// loadTemplateSet returns the embedded templates with those under dir parsed over
+// them. A file under dir that doesn't name an embedded template is an error, so a
+// misspelled override isn't silently ignored. An empty dir returns the embedded set.
+func loadTemplateSet(dir string) (*templateSet, error) {
+	if dir == "" {
+		return embeddedTemplates, nil
+	}
+	root, err := embeddedTemplates.root.Clone()
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if root.Lookup(name) == nil {
+			return fmt.Errorf("template override %s does not match an embedded template (see 'pulse templates')", path)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if _, err := root.New(name).Parse(string(content)); err != nil {
+			return fmt.Errorf("template override %s: %w", path, err)
+		}
+		found = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("template directory %s holds no templates", dir)
+	}
+	return &templateSet{root: root, overridden: true}, nil
+}
+
+// templatesFromFlags returns the templates selected by the -template-dir flag
+func templatesFromFlags(fs *flag.FlagSet) (*templateSet, error) {
+	if f := fs.Lookup("template-dir"); f != nil {
+		return loadTemplateSet(f.Value.String())
+	}
+	return embeddedTemplates, nil
+}
+
+// templateError is the panic value of a failed render of an overridden template
+type templateError struct {
+	err error
+}
+
+// recoverTemplateError turns a templateError panic into the error err, for the
+// Generate method of a plugin that accepts -template-dir
+func recoverTemplateError(err *error) {
+	if r := recover(); r != nil {
+		te, ok := r.(templateError)
+		if !ok {
+			panic(r)
+		}
+		*err = te.err
+	}
+}
+
+// render executes the named template with data and appends the output to sb.
+// Embedded templates and their view models are both part of this package, so their
+// failure is a programming error; an overridden template's failure is reported as
+// the error of the generation.
+func (ts *templateSet) render(sb *strings.Builder, name string, data interface{}) {
+	if err := ts.root.ExecuteTemplate(sb, name, data); err != nil {
+		if ts.overridden {
+			panic(templateError{fmt.Errorf("failed to render template %s: %w", name, err)})
+		}
+		panic(fmt.Sprintf("failed to render template %s: %v", name, err))
+	}
+}
+
+// renderString executes the named template with data and returns the output
+func (ts *templateSet) renderString(name string, data interface{}) string {
+	var sb strings.Builder
+	ts.render(&sb, name, data)
+	return sb.String()
+}
+
+// capture returns the code write appends to a builder, for the view model fields
+// that hold a section emitted by another generator function
+func capture(write func(sb *strings.Builder)) string {
+	var sb strings.Builder
+	write(&sb)
+	return sb.String()
+}
+
+// enumView is the view model for a single enum
+type enumView struct {
+	// Name is the enum name as written in the IDL, possibly namespace qualified
+	Name string
+	// TypeName is the enum's type name in the target language
+	TypeName string
+	// CommentLines holds the enum's doc comment, one entry per line
+	CommentLines []string
+	Values       []enumValueView
+}
+
+// enumValueView is the view model for a single enum value
+type enumValueView struct {
+	Name string
+	// Ident is the value's identifier in the target language
+	Ident string
+}
+
+// newEnumView builds the view for an enum. ident maps an IDL value name to its
+// identifier in the target language.
+func newEnumView(e *parser.Enum, typeName string, ident func(value string) string) enumView {
+	view := enumView{Name: e.Name, TypeName: typeName}
+	if e.Comment != "" {
+		view.CommentLines = strings.Split(strings.TrimSpace(e.Comment), "\n")
+	}
+	for _, v := range e.Values {
+		view.Values = append(view.Values, enumValueView{Name: v.Name, Ident: ident(v.Name)})
+	}
+	return view
+}
+
+// typeRegistryView is the view model for a namespace's struct and enum registry
+type typeRegistryView struct {
+	Namespace string
+	Packaged  bool
+	Structs   []structRegistryView
+	Enums     []enumView
+	// Dataclasses holds the Python classes of the namespace's [immutable] structs
+	Dataclasses string
+}
+
+// structRegistryView is the view model for a struct entry in a type registry
+type structRegistryView struct {
+	Name    string
+	Extends string
+	Fields  []fieldRegistryView
+}
+
+// fieldRegistryView is the view model for a struct field in a type registry
+type fieldRegistryView struct {
+	Name string
+	// Type is the field's type definition rendered as a literal in the target language
+	Type      string
+	Optional  bool
+	Sensitive bool
+	Encrypted bool
+}
+
+// newTypeRegistryView builds the registry view for a namespace. typeLiteral renders
+// a field's type definition as a literal in the target language.
+func newTypeRegistryView(namespace string, types *NamespaceTypes, typeLiteral func(sb *strings.Builder, t *parser.Type)) typeRegistryView {
+	view := typeRegistryView{Namespace: namespace}
+	for _, s := range types.Structs {
+		sv := structRegistryView{Name: s.Name, Extends: s.Extends}
+		for _, field := range s.Fields {
+			var sb strings.Builder
+			typeLiteral(&sb, field.Type)
+			sv.Fields = append(sv.Fields, fieldRegistryView{Name: field.Name, Type: sb.String(), Optional: field.Optional, Sensitive: field.IsSensitive(), Encrypted: field.IsEncrypted()})
+		}
+		view.Structs = append(view.Structs, sv)
+	}
+	for _, e := range types.Enums {
+		view.Enums = append(view.Enums, newEnumView(e, GetBaseName(e.Name), func(v string) string { return v }))
+	}
+	return view
+}
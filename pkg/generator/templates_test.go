@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+const templatesTestIDL = `namespace shop
+
+interface Orders {
+    get(id string) string
+}
+`
+
+// generateWithTemplateDir runs the Go plugin with -template-dir set to a directory
+// holding files, keyed by template name. It returns the output directory and the
+// error of Generate.
+func generateWithTemplateDir(t *testing.T, files map[string]string) (string, error) {
+	t.Helper()
+	templateDir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(templateDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idl, err := parser.ParseIDL("shop.pulse", templatesTestIDL)
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	dir := t.TempDir()
+	plugin := NewGoClientServer()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("dir", dir, "output dir")
+	fs.String("template-dir", templateDir, "template dir")
+	plugin.RegisterFlags(fs)
+	return dir, plugin.Generate(idl, fs)
+}
+
+func TestTemplateDirOverride(t *testing.T) {
+	embedded, err := EmbeddedTemplate("go/client.go.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	override := strings.Replace(string(embedded), "// Transport is an interface for making JSON-RPC 2.0 calls", "// Transport is our company transport", 1)
+	dir, err := generateWithTemplateDir(t, map[string]string{"go/client.go.tmpl": override})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	client, err := os.ReadFile(filepath.Join(dir, "client.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(client), "// Transport is our company transport") {
+		t.Error("client.go was not rendered from the override")
+	}
+	if !strings.Contains(string(client), "func (c *OrdersClient) Get(id string, opts ...CallOption) (string, error) {") {
+		t.Error("client.go is missing the Orders client rendered by the overridden template")
+	}
+	// Templates without an override still come from the embedded set
+	server, err := os.ReadFile(filepath.Join(dir, "server.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(server), "type Orders interface {") {
+		t.Error("server.go is missing the Orders interface")
+	}
+}
+
+func TestTemplateDirErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"unknown template", map[string]string{"go/clinet.go.tmpl": "x"}, "does not match an embedded template"},
+		{"parse error", map[string]string{"go/client.go.tmpl": "{{if}}"}, "template override"},
+		{"execute error", map[string]string{"go/client.go.tmpl": "{{.NoSuchField}}"}, "failed to render template go/client.go.tmpl"},
+	}
+	for _, tt := range tests {
+		_, err := generateWithTemplateDir(t, tt.files)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got error %v, want one containing %q", tt.name, err, tt.want)
+		}
+	}
+}
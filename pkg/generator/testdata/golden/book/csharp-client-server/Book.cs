@@ -0,0 +1,998 @@
+// Generated by pulserpc - do not edit
+
+using System.Collections.Generic;
+using System.Linq;
+using System.Text.Json.Serialization;
+using PulseRPC;
+
+namespace book
+{
+    // The book selling platforms we support
+    public enum Platform
+    {
+        kindle,
+        nook
+    }
+
+    public enum BookUserStatus
+    {
+        none,
+        want,
+        have,
+        dislike
+    }
+
+    // These are the status codes that interface functions may return.
+    public enum Status
+    {
+        success,
+        fatal,
+        invalid,
+        notfound,
+        denied
+    }
+
+
+    public class Book
+    {
+        public Book() { }
+
+        [JsonPropertyName("productId")]
+        public string ProductId { get; set; }
+
+        [JsonPropertyName("dateCreated")]
+        public int DateCreated { get; set; }
+
+        [JsonPropertyName("dateUpdated")]
+        public int DateUpdated { get; set; }
+
+        [JsonPropertyName("platform")]
+        public Platform Platform { get; set; }
+
+        [JsonPropertyName("author")]
+        public string Author { get; set; }
+
+        [JsonPropertyName("title")]
+        public string Title { get; set; }
+
+        [JsonPropertyName("productUrl")]
+        public string ProductUrl { get; set; }
+
+        [JsonPropertyName("imageUrl")]
+        public string ImageUrl { get; set; }
+
+        [JsonPropertyName("lendable")]
+        public bool Lendable { get; set; }
+
+        protected Book(Book other)
+        {
+            ProductId = other.ProductId;
+            DateCreated = other.DateCreated;
+            DateUpdated = other.DateUpdated;
+            Platform = other.Platform;
+            Author = other.Author;
+            Title = other.Title;
+            ProductUrl = other.ProductUrl;
+            ImageUrl = other.ImageUrl;
+            Lendable = other.Lendable;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public virtual Book Clone() => new Book(this);
+    }
+
+    public class BookWithStatus : Book
+    {
+        public BookWithStatus() { }
+
+        [JsonPropertyName("userStatus")]
+        public BookUserStatus UserStatus { get; set; }
+
+        protected BookWithStatus(BookWithStatus other) : base(other)
+        {
+            UserStatus = other.UserStatus;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public override BookWithStatus Clone() => new BookWithStatus(this);
+    }
+
+    public class BookWithScore : BookWithStatus
+    {
+        public BookWithScore() { }
+
+        [JsonPropertyName("score")]
+        public double Score { get; set; }
+
+        protected BookWithScore(BookWithScore other) : base(other)
+        {
+            Score = other.Score;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public override BookWithScore Clone() => new BookWithScore(this);
+    }
+
+    public class User
+    {
+        public User() { }
+
+        [JsonPropertyName("userId")]
+        public string UserId { get; set; }
+
+        [JsonPropertyName("name")]
+        public string Name { get; set; }
+
+        [JsonPropertyName("points")]
+        public int Points { get; set; }
+
+        [JsonPropertyName("dateCreated")]
+        public int DateCreated { get; set; }
+
+        [JsonPropertyName("email")]
+        public string Email { get; set; }
+
+        [JsonPropertyName("kindleEmail")]
+        public string KindleEmail { get; set; }
+
+        [JsonPropertyName("nookEmail")]
+        public string NookEmail { get; set; }
+
+        [JsonPropertyName("emailOptIn")]
+        public bool EmailOptIn { get; set; }
+
+        protected User(User other)
+        {
+            UserId = other.UserId;
+            Name = other.Name;
+            Points = other.Points;
+            DateCreated = other.DateCreated;
+            Email = other.Email;
+            KindleEmail = other.KindleEmail;
+            NookEmail = other.NookEmail;
+            EmailOptIn = other.EmailOptIn;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public virtual User Clone() => new User(this);
+    }
+
+    public class UserUpdate
+    {
+        public UserUpdate() { }
+
+        [JsonPropertyName("userId")]
+        public string UserId { get; set; }
+
+        [JsonPropertyName("name")]
+        public string Name { get; set; }
+
+        [JsonPropertyName("email")]
+        public string Email { get; set; }
+
+        [JsonPropertyName("kindleEmail")]
+        public string KindleEmail { get; set; }
+
+        [JsonPropertyName("nookEmail")]
+        public string NookEmail { get; set; }
+
+        [JsonPropertyName("emailOptIn")]
+        public bool EmailOptIn { get; set; }
+
+        protected UserUpdate(UserUpdate other)
+        {
+            UserId = other.UserId;
+            Name = other.Name;
+            Email = other.Email;
+            KindleEmail = other.KindleEmail;
+            NookEmail = other.NookEmail;
+            EmailOptIn = other.EmailOptIn;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public virtual UserUpdate Clone() => new UserUpdate(this);
+    }
+
+    public class SearchRequest
+    {
+        public SearchRequest() { }
+
+        [JsonPropertyName("platforms")]
+        public List<Platform> Platforms { get; set; }
+
+        [JsonPropertyName("userId")]
+        public string UserId { get; set; }
+
+        [JsonPropertyName("keyword")]
+        public string Keyword { get; set; }
+
+        [JsonPropertyName("offset")]
+        public int Offset { get; set; }
+
+        [JsonPropertyName("limit")]
+        public int Limit { get; set; }
+
+        protected SearchRequest(SearchRequest other)
+        {
+            Platforms = other.Platforms?.ToList()!;
+            UserId = other.UserId;
+            Keyword = other.Keyword;
+            Offset = other.Offset;
+            Limit = other.Limit;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public virtual SearchRequest Clone() => new SearchRequest(this);
+    }
+
+    public class Recipient
+    {
+        public Recipient() { }
+
+        [JsonPropertyName("userId")]
+        public string UserId { get; set; }
+
+        [JsonPropertyName("email")]
+        public string Email { get; set; }
+
+        protected Recipient(Recipient other)
+        {
+            UserId = other.UserId;
+            Email = other.Email;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public virtual Recipient Clone() => new Recipient(this);
+    }
+
+    public class ToLoanTask
+    {
+        public ToLoanTask() { }
+
+        [JsonPropertyName("book")]
+        public Book Book { get; set; }
+
+        [JsonPropertyName("recipients")]
+        public List<Recipient> Recipients { get; set; }
+
+        protected ToLoanTask(ToLoanTask other)
+        {
+            Book = other.Book?.Clone()!;
+            Recipients = other.Recipients?.Select(e0 => e0?.Clone()!).ToList()!;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public virtual ToLoanTask Clone() => new ToLoanTask(this);
+    }
+
+    public class ToAckTask
+    {
+        public ToAckTask() { }
+
+        [JsonPropertyName("book")]
+        public Book Book { get; set; }
+
+        [JsonPropertyName("fromEmail")]
+        public string FromEmail { get; set; }
+
+        [JsonPropertyName("loanId")]
+        public string LoanId { get; set; }
+
+        [JsonPropertyName("dateLoaned")]
+        public int DateLoaned { get; set; }
+
+        protected ToAckTask(ToAckTask other)
+        {
+            Book = other.Book?.Clone()!;
+            FromEmail = other.FromEmail;
+            LoanId = other.LoanId;
+            DateLoaned = other.DateLoaned;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public virtual ToAckTask Clone() => new ToAckTask(this);
+    }
+
+    public class BaseResponse
+    {
+        public BaseResponse() { }
+
+        [JsonPropertyName("status")]
+        public Status Status { get; set; }
+
+        [JsonPropertyName("message")]
+        public string Message { get; set; }
+
+        protected BaseResponse(BaseResponse other)
+        {
+            Status = other.Status;
+            Message = other.Message;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public virtual BaseResponse Clone() => new BaseResponse(this);
+    }
+
+    public class UserResponse : BaseResponse
+    {
+        public UserResponse() { }
+
+        [JsonPropertyName("user")]
+        public User User { get; set; }
+
+        protected UserResponse(UserResponse other) : base(other)
+        {
+            User = other.User?.Clone()!;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public override UserResponse Clone() => new UserResponse(this);
+    }
+
+    public class BookResponse : BaseResponse
+    {
+        public BookResponse() { }
+
+        [JsonPropertyName("userId")]
+        public string UserId { get; set; }
+
+        [JsonPropertyName("book")]
+        public BookWithStatus Book { get; set; }
+
+        protected BookResponse(BookResponse other) : base(other)
+        {
+            UserId = other.UserId;
+            Book = other.Book?.Clone()!;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public override BookResponse Clone() => new BookResponse(this);
+    }
+
+    public class BooksResponse : BaseResponse
+    {
+        public BooksResponse() { }
+
+        [JsonPropertyName("userId")]
+        public string UserId { get; set; }
+
+        [JsonPropertyName("totalRows")]
+        public int TotalRows { get; set; }
+
+        [JsonPropertyName("offset")]
+        public int Offset { get; set; }
+
+        [JsonPropertyName("books")]
+        public List<BookWithStatus> Books { get; set; }
+
+        protected BooksResponse(BooksResponse other) : base(other)
+        {
+            UserId = other.UserId;
+            TotalRows = other.TotalRows;
+            Offset = other.Offset;
+            Books = other.Books?.Select(e0 => e0?.Clone()!).ToList()!;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public override BooksResponse Clone() => new BooksResponse(this);
+    }
+
+    public class DeleteResponse : BaseResponse
+    {
+        public DeleteResponse() { }
+
+        [JsonPropertyName("deleteCount")]
+        public int DeleteCount { get; set; }
+
+        protected DeleteResponse(DeleteResponse other) : base(other)
+        {
+            DeleteCount = other.DeleteCount;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public override DeleteResponse Clone() => new DeleteResponse(this);
+    }
+
+    public class RecommendationsResponse : BaseResponse
+    {
+        public RecommendationsResponse() { }
+
+        [JsonPropertyName("userId")]
+        public string UserId { get; set; }
+
+        [JsonPropertyName("books")]
+        public List<BookWithScore> Books { get; set; }
+
+        protected RecommendationsResponse(RecommendationsResponse other) : base(other)
+        {
+            UserId = other.UserId;
+            Books = other.Books?.Select(e0 => e0?.Clone()!).ToList()!;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public override RecommendationsResponse Clone() => new RecommendationsResponse(this);
+    }
+
+    public class UserBooksResponse : BaseResponse
+    {
+        public UserBooksResponse() { }
+
+        [JsonPropertyName("userId")]
+        public string UserId { get; set; }
+
+        [JsonPropertyName("want")]
+        public List<Book> Want { get; set; }
+
+        [JsonPropertyName("have")]
+        public List<Book> Have { get; set; }
+
+        [JsonPropertyName("dislike")]
+        public List<Book> Dislike { get; set; }
+
+        protected UserBooksResponse(UserBooksResponse other) : base(other)
+        {
+            UserId = other.UserId;
+            Want = other.Want?.Select(e0 => e0?.Clone()!).ToList()!;
+            Have = other.Have?.Select(e0 => e0?.Clone()!).ToList()!;
+            Dislike = other.Dislike?.Select(e0 => e0?.Clone()!).ToList()!;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public override UserBooksResponse Clone() => new UserBooksResponse(this);
+    }
+
+    public class TasksResponse : BaseResponse
+    {
+        public TasksResponse() { }
+
+        [JsonPropertyName("userId")]
+        public string UserId { get; set; }
+
+        [JsonPropertyName("toLoan")]
+        public List<ToLoanTask> ToLoan { get; set; }
+
+        [JsonPropertyName("toAck")]
+        public List<ToAckTask> ToAck { get; set; }
+
+        protected TasksResponse(TasksResponse other) : base(other)
+        {
+            UserId = other.UserId;
+            ToLoan = other.ToLoan?.Select(e0 => e0?.Clone()!).ToList()!;
+            ToAck = other.ToAck?.Select(e0 => e0?.Clone()!).ToList()!;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public override TasksResponse Clone() => new TasksResponse(this);
+    }
+
+    public class LoanResponse : BaseResponse
+    {
+        public LoanResponse() { }
+
+        [JsonPropertyName("loanId")]
+        public string LoanId { get; set; }
+
+        protected LoanResponse(LoanResponse other) : base(other)
+        {
+            LoanId = other.LoanId;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public override LoanResponse Clone() => new LoanResponse(this);
+    }
+
+    public class ActivityResponse : BaseResponse
+    {
+        public ActivityResponse() { }
+
+        [JsonPropertyName("activity")]
+        public List<BookWithStatus> Activity { get; set; }
+
+        protected ActivityResponse(ActivityResponse other) : base(other)
+        {
+            Activity = other.Activity?.Select(e0 => e0?.Clone()!).ToList()!;
+        }
+
+        // Returns a deep copy that shares no lists, dictionaries or structs with this value
+        public override ActivityResponse Clone() => new ActivityResponse(this);
+    }
+
+
+    // IDL-specific type definitions for namespace: book
+    public static class bookIdl
+    {
+        // Built on first use, so loading the assembly doesn't pay for a large IDL
+        public static Dictionary<string, Dictionary<string, object>> ALL_STRUCTS => _allStructs.Value;
+        public static Dictionary<string, Dictionary<string, object>> ALL_ENUMS => _allEnums.Value;
+
+        private static readonly System.Lazy<Dictionary<string, Dictionary<string, object>>> _allStructs = new System.Lazy<Dictionary<string, Dictionary<string, object>>>(() => new Dictionary<string, Dictionary<string, object>>
+        {
+            { "Book", new Dictionary<string, object>
+            {
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "productId" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "dateCreated" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "int" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "dateUpdated" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "int" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "platform" },
+                        { "type", new Dictionary<string, object> { { "userDefined", "Platform" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "author" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "title" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "productUrl" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "imageUrl" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "lendable" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "bool" } } },
+                    },
+                }},
+            }},
+            { "BookWithStatus", new Dictionary<string, object>
+            {
+                { "extends", "Book" },
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "userStatus" },
+                        { "type", new Dictionary<string, object> { { "userDefined", "BookUserStatus" } } },
+                    },
+                }},
+            }},
+            { "BookWithScore", new Dictionary<string, object>
+            {
+                { "extends", "BookWithStatus" },
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "score" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "float" } } },
+                    },
+                }},
+            }},
+            { "User", new Dictionary<string, object>
+            {
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "userId" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "name" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "points" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "int" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "dateCreated" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "int" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "email" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "kindleEmail" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "nookEmail" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "emailOptIn" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "bool" } } },
+                    },
+                }},
+            }},
+            { "UserUpdate", new Dictionary<string, object>
+            {
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "userId" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "name" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "email" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "kindleEmail" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "nookEmail" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "emailOptIn" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "bool" } } },
+                    },
+                }},
+            }},
+            { "SearchRequest", new Dictionary<string, object>
+            {
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "platforms" },
+                        { "type", new Dictionary<string, object> { { "array", new Dictionary<string, object> { { "userDefined", "Platform" } } } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "userId" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "keyword" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "offset" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "int" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "limit" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "int" } } },
+                    },
+                }},
+            }},
+            { "Recipient", new Dictionary<string, object>
+            {
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "userId" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "email" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                }},
+            }},
+            { "ToLoanTask", new Dictionary<string, object>
+            {
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "book" },
+                        { "type", new Dictionary<string, object> { { "userDefined", "Book" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "recipients" },
+                        { "type", new Dictionary<string, object> { { "array", new Dictionary<string, object> { { "userDefined", "Recipient" } } } } },
+                    },
+                }},
+            }},
+            { "ToAckTask", new Dictionary<string, object>
+            {
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "book" },
+                        { "type", new Dictionary<string, object> { { "userDefined", "Book" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "fromEmail" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "loanId" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "dateLoaned" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "int" } } },
+                    },
+                }},
+            }},
+            { "BaseResponse", new Dictionary<string, object>
+            {
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "status" },
+                        { "type", new Dictionary<string, object> { { "userDefined", "Status" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "message" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                }},
+            }},
+            { "UserResponse", new Dictionary<string, object>
+            {
+                { "extends", "BaseResponse" },
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "user" },
+                        { "type", new Dictionary<string, object> { { "userDefined", "User" } } },
+                    },
+                }},
+            }},
+            { "BookResponse", new Dictionary<string, object>
+            {
+                { "extends", "BaseResponse" },
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "userId" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "book" },
+                        { "type", new Dictionary<string, object> { { "userDefined", "BookWithStatus" } } },
+                    },
+                }},
+            }},
+            { "BooksResponse", new Dictionary<string, object>
+            {
+                { "extends", "BaseResponse" },
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "userId" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "totalRows" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "int" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "offset" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "int" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "books" },
+                        { "type", new Dictionary<string, object> { { "array", new Dictionary<string, object> { { "userDefined", "BookWithStatus" } } } } },
+                    },
+                }},
+            }},
+            { "DeleteResponse", new Dictionary<string, object>
+            {
+                { "extends", "BaseResponse" },
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "deleteCount" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "int" } } },
+                    },
+                }},
+            }},
+            { "RecommendationsResponse", new Dictionary<string, object>
+            {
+                { "extends", "BaseResponse" },
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "userId" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "books" },
+                        { "type", new Dictionary<string, object> { { "array", new Dictionary<string, object> { { "userDefined", "BookWithScore" } } } } },
+                    },
+                }},
+            }},
+            { "UserBooksResponse", new Dictionary<string, object>
+            {
+                { "extends", "BaseResponse" },
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "userId" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "want" },
+                        { "type", new Dictionary<string, object> { { "array", new Dictionary<string, object> { { "userDefined", "Book" } } } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "have" },
+                        { "type", new Dictionary<string, object> { { "array", new Dictionary<string, object> { { "userDefined", "Book" } } } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "dislike" },
+                        { "type", new Dictionary<string, object> { { "array", new Dictionary<string, object> { { "userDefined", "Book" } } } } },
+                    },
+                }},
+            }},
+            { "TasksResponse", new Dictionary<string, object>
+            {
+                { "extends", "BaseResponse" },
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "userId" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "toLoan" },
+                        { "type", new Dictionary<string, object> { { "array", new Dictionary<string, object> { { "userDefined", "ToLoanTask" } } } } },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "toAck" },
+                        { "type", new Dictionary<string, object> { { "array", new Dictionary<string, object> { { "userDefined", "ToAckTask" } } } } },
+                    },
+                }},
+            }},
+            { "LoanResponse", new Dictionary<string, object>
+            {
+                { "extends", "BaseResponse" },
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "loanId" },
+                        { "type", new Dictionary<string, object> { { "builtIn", "string" } } },
+                    },
+                }},
+            }},
+            { "ActivityResponse", new Dictionary<string, object>
+            {
+                { "extends", "BaseResponse" },
+                { "fields", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "activity" },
+                        { "type", new Dictionary<string, object> { { "array", new Dictionary<string, object> { { "userDefined", "BookWithStatus" } } } } },
+                    },
+                }},
+            }},
+        });
+
+        private static readonly System.Lazy<Dictionary<string, Dictionary<string, object>>> _allEnums = new System.Lazy<Dictionary<string, Dictionary<string, object>>>(() => new Dictionary<string, Dictionary<string, object>>
+        {
+            { "Platform", new Dictionary<string, object>
+            {
+                { "values", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "kindle" },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "nook" },
+                    },
+                }},
+            }},
+            { "BookUserStatus", new Dictionary<string, object>
+            {
+                { "values", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "none" },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "want" },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "have" },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "dislike" },
+                    },
+                }},
+            }},
+            { "Status", new Dictionary<string, object>
+            {
+                { "values", new List<Dictionary<string, object>>
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "name", "success" },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "fatal" },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "invalid" },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "notfound" },
+                    },
+                    new Dictionary<string, object>
+                    {
+                        { "name", "denied" },
+                    },
+                }},
+            }},
+        });
+    }
+}
@@ -0,0 +1,2130 @@
+// Generated by pulserpc - do not edit
+
+using System;
+using System.Collections.Generic;
+using System.Globalization;
+using System.Linq;
+using System.Net;
+using System.Text.Json;
+using System.Text.Json.Serialization;
+using System.Threading.Tasks;
+using Microsoft.AspNetCore.Builder;
+using Microsoft.AspNetCore.Http;
+using Microsoft.AspNetCore.Routing;
+using Microsoft.Extensions.Logging;
+using Microsoft.Extensions.DependencyInjection;
+using PulseRPC;
+
+using static book.bookIdl;
+using book;
+
+namespace PulseRPC
+{
+/// <summary>
+/// The HTTP request of the call being handled. The server sets it for each request, and
+/// handlers read the caller's headers, such as an authorization token or a trace id, and
+/// address from it. It is null for calls that did not arrive over HTTP, such as those of
+/// HandleRequestAsync. Handlers must not read the request body or write the response.
+/// </summary>
+public static class RequestMeta
+{
+    private sealed class Scope : IDisposable
+    {
+        private readonly HttpContext? _previous;
+
+        public Scope(HttpContext? previous)
+        {
+            _previous = previous;
+        }
+
+        public void Dispose()
+        {
+            Current.Value = _previous;
+        }
+    }
+
+    private static readonly System.Threading.AsyncLocal<HttpContext?> Current = new();
+
+    /// <summary>The HttpContext the call arrived in</summary>
+    public static HttpContext? HttpContext => Current.Value;
+
+    /// <summary>The request headers</summary>
+    public static IHeaderDictionary? Headers => Current.Value?.Request.Headers;
+
+    /// <summary>The address of the caller</summary>
+    public static IPAddress? RemoteAddress => Current.Value?.Connection.RemoteIpAddress;
+
+    /// <summary>
+    /// Makes context the request of the call handled by the calling async method, until
+    /// the returned scope is disposed
+    /// </summary>
+    public static IDisposable Begin(HttpContext context)
+    {
+        var scope = new Scope(Current.Value);
+        Current.Value = context;
+        return scope;
+    }
+}
+
+/// <summary>
+/// Payload sizes of one JSON-RPC call, as passed to the OnCall hook. RequestBytes is the
+/// size of the JSON request (the query string for GET requests); ResponseBytes is the size
+/// of the JSON response the method produced, before any response size limit is applied,
+/// or 0 for notifications.
+/// </summary>
+public sealed record CallStats(string Method, int RequestBytes, int ResponseBytes);
+
+/// <summary>
+/// A successful call, as passed to the ResponseMeta hook. Params are the validated JSON
+/// params by position, Result is the value the handler returned and Elapsed is the time
+/// the handler took.
+/// </summary>
+public sealed record ResponseMetaCall(string Method, IReadOnlyList<object?> Params, object? Result, TimeSpan Elapsed);
+
+public partial class PulseRPCServer
+{
+    private static readonly string _idlJson = @"{
+  ""idlVersion"": 2,
+  ""rootNamespace"": ""book"",
+  ""interfaces"": [
+    {
+      ""name"": ""UserService"",
+      ""namespace"": ""book"",
+      ""methods"": [
+        {
+          ""name"": ""createIfNew"",
+          ""parameters"": [
+            {
+              ""name"": ""userId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            },
+            {
+              ""name"": ""name"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""BaseResponse""
+          }
+        },
+        {
+          ""name"": ""get"",
+          ""parameters"": [
+            {
+              ""name"": ""userId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""UserResponse""
+          }
+        },
+        {
+          ""name"": ""update"",
+          ""parameters"": [
+            {
+              ""name"": ""user"",
+              ""type"": {
+                ""userDefined"": ""UserUpdate""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""BaseResponse""
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""BookService"",
+      ""namespace"": ""book"",
+      ""methods"": [
+        {
+          ""name"": ""put"",
+          ""parameters"": [
+            {
+              ""name"": ""book"",
+              ""type"": {
+                ""userDefined"": ""Book""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""BaseResponse""
+          }
+        },
+        {
+          ""name"": ""get"",
+          ""parameters"": [
+            {
+              ""name"": ""productId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            },
+            {
+              ""name"": ""userId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""BookResponse""
+          }
+        },
+        {
+          ""name"": ""delete"",
+          ""parameters"": [
+            {
+              ""name"": ""productIds"",
+              ""type"": {
+                ""array"": {
+                  ""builtIn"": ""string""
+                }
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""DeleteResponse""
+          }
+        },
+        {
+          ""name"": ""cancelUserStatus"",
+          ""parameters"": [
+            {
+              ""name"": ""productId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            },
+            {
+              ""name"": ""userId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""BaseResponse""
+          }
+        },
+        {
+          ""name"": ""setUserStatus"",
+          ""parameters"": [
+            {
+              ""name"": ""productId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            },
+            {
+              ""name"": ""userId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            },
+            {
+              ""name"": ""status"",
+              ""type"": {
+                ""userDefined"": ""BookUserStatus""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""BaseResponse""
+          }
+        },
+        {
+          ""name"": ""getAvailable"",
+          ""parameters"": [
+            {
+              ""name"": ""platforms"",
+              ""type"": {
+                ""array"": {
+                  ""userDefined"": ""Platform""
+                }
+              }
+            },
+            {
+              ""name"": ""userId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            },
+            {
+              ""name"": ""offset"",
+              ""type"": {
+                ""builtIn"": ""int""
+              }
+            },
+            {
+              ""name"": ""limit"",
+              ""type"": {
+                ""builtIn"": ""int""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""BooksResponse""
+          }
+        },
+        {
+          ""name"": ""getRecentActivity"",
+          ""parameters"": [
+            {
+              ""name"": ""limit"",
+              ""type"": {
+                ""builtIn"": ""int""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""ActivityResponse""
+          }
+        },
+        {
+          ""name"": ""getRecommendations"",
+          ""parameters"": [
+            {
+              ""name"": ""userId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""RecommendationsResponse""
+          }
+        },
+        {
+          ""name"": ""search"",
+          ""parameters"": [
+            {
+              ""name"": ""request"",
+              ""type"": {
+                ""userDefined"": ""SearchRequest""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""BooksResponse""
+          }
+        },
+        {
+          ""name"": ""getUserBooks"",
+          ""parameters"": [
+            {
+              ""name"": ""userId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""UserBooksResponse""
+          }
+        },
+        {
+          ""name"": ""getUserTasks"",
+          ""parameters"": [
+            {
+              ""name"": ""userId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""TasksResponse""
+          }
+        },
+        {
+          ""name"": ""ackLoan"",
+          ""parameters"": [
+            {
+              ""name"": ""userId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            },
+            {
+              ""name"": ""loanId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            },
+            {
+              ""name"": ""success"",
+              ""type"": {
+                ""builtIn"": ""bool""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""BaseResponse""
+          }
+        },
+        {
+          ""name"": ""bookNotLendable"",
+          ""parameters"": [
+            {
+              ""name"": ""productId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            },
+            {
+              ""name"": ""userId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""BaseResponse""
+          }
+        },
+        {
+          ""name"": ""createLoan"",
+          ""parameters"": [
+            {
+              ""name"": ""productId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            },
+            {
+              ""name"": ""fromUserId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            },
+            {
+              ""name"": ""toUserId"",
+              ""type"": {
+                ""builtIn"": ""string""
+              }
+            }
+          ],
+          ""returnType"": {
+            ""userDefined"": ""LoanResponse""
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""CronJobs"",
+      ""namespace"": ""book"",
+      ""methods"": [
+        {
+          ""name"": ""refreshRecommendCache"",
+          ""returnType"": {
+            ""userDefined"": ""BaseResponse""
+          }
+        },
+        {
+          ""name"": ""sendBooksAvailable"",
+          ""returnType"": {
+            ""userDefined"": ""BaseResponse""
+          }
+        },
+        {
+          ""name"": ""sendBooksToLoan"",
+          ""returnType"": {
+            ""userDefined"": ""BaseResponse""
+          }
+        },
+        {
+          ""name"": ""sendAvailableBookTweet"",
+          ""returnType"": {
+            ""userDefined"": ""BaseResponse""
+          }
+        }
+      ]
+    }
+  ],
+  ""structs"": [
+    {
+      ""name"": ""Book"",
+      ""namespace"": ""book"",
+      ""fields"": [
+        {
+          ""name"": ""productId"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""dateCreated"",
+          ""type"": {
+            ""builtIn"": ""int""
+          }
+        },
+        {
+          ""name"": ""dateUpdated"",
+          ""type"": {
+            ""builtIn"": ""int""
+          }
+        },
+        {
+          ""name"": ""platform"",
+          ""type"": {
+            ""userDefined"": ""Platform""
+          }
+        },
+        {
+          ""name"": ""author"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""title"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""productUrl"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""imageUrl"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""lendable"",
+          ""type"": {
+            ""builtIn"": ""bool""
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""BookWithStatus"",
+      ""namespace"": ""book"",
+      ""extends"": ""Book"",
+      ""fields"": [
+        {
+          ""name"": ""userStatus"",
+          ""type"": {
+            ""userDefined"": ""BookUserStatus""
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""BookWithScore"",
+      ""namespace"": ""book"",
+      ""extends"": ""BookWithStatus"",
+      ""fields"": [
+        {
+          ""name"": ""score"",
+          ""type"": {
+            ""builtIn"": ""float""
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""User"",
+      ""namespace"": ""book"",
+      ""fields"": [
+        {
+          ""name"": ""userId"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""name"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""points"",
+          ""type"": {
+            ""builtIn"": ""int""
+          }
+        },
+        {
+          ""name"": ""dateCreated"",
+          ""type"": {
+            ""builtIn"": ""int""
+          }
+        },
+        {
+          ""name"": ""email"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""kindleEmail"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""nookEmail"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""emailOptIn"",
+          ""type"": {
+            ""builtIn"": ""bool""
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""UserUpdate"",
+      ""namespace"": ""book"",
+      ""fields"": [
+        {
+          ""name"": ""userId"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""name"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""email"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""kindleEmail"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""nookEmail"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""emailOptIn"",
+          ""type"": {
+            ""builtIn"": ""bool""
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""SearchRequest"",
+      ""namespace"": ""book"",
+      ""fields"": [
+        {
+          ""name"": ""platforms"",
+          ""type"": {
+            ""array"": {
+              ""userDefined"": ""Platform""
+            }
+          }
+        },
+        {
+          ""name"": ""userId"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""keyword"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""offset"",
+          ""type"": {
+            ""builtIn"": ""int""
+          }
+        },
+        {
+          ""name"": ""limit"",
+          ""type"": {
+            ""builtIn"": ""int""
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""Recipient"",
+      ""namespace"": ""book"",
+      ""fields"": [
+        {
+          ""name"": ""userId"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""email"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""ToLoanTask"",
+      ""namespace"": ""book"",
+      ""fields"": [
+        {
+          ""name"": ""book"",
+          ""type"": {
+            ""userDefined"": ""Book""
+          }
+        },
+        {
+          ""name"": ""recipients"",
+          ""type"": {
+            ""array"": {
+              ""userDefined"": ""Recipient""
+            }
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""ToAckTask"",
+      ""namespace"": ""book"",
+      ""fields"": [
+        {
+          ""name"": ""book"",
+          ""type"": {
+            ""userDefined"": ""Book""
+          }
+        },
+        {
+          ""name"": ""fromEmail"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""loanId"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""dateLoaned"",
+          ""type"": {
+            ""builtIn"": ""int""
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""BaseResponse"",
+      ""namespace"": ""book"",
+      ""fields"": [
+        {
+          ""name"": ""status"",
+          ""type"": {
+            ""userDefined"": ""Status""
+          }
+        },
+        {
+          ""name"": ""message"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""UserResponse"",
+      ""namespace"": ""book"",
+      ""extends"": ""BaseResponse"",
+      ""fields"": [
+        {
+          ""name"": ""user"",
+          ""type"": {
+            ""userDefined"": ""User""
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""BookResponse"",
+      ""namespace"": ""book"",
+      ""extends"": ""BaseResponse"",
+      ""fields"": [
+        {
+          ""name"": ""userId"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""book"",
+          ""type"": {
+            ""userDefined"": ""BookWithStatus""
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""BooksResponse"",
+      ""namespace"": ""book"",
+      ""extends"": ""BaseResponse"",
+      ""fields"": [
+        {
+          ""name"": ""userId"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""totalRows"",
+          ""type"": {
+            ""builtIn"": ""int""
+          }
+        },
+        {
+          ""name"": ""offset"",
+          ""type"": {
+            ""builtIn"": ""int""
+          }
+        },
+        {
+          ""name"": ""books"",
+          ""type"": {
+            ""array"": {
+              ""userDefined"": ""BookWithStatus""
+            }
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""DeleteResponse"",
+      ""namespace"": ""book"",
+      ""extends"": ""BaseResponse"",
+      ""fields"": [
+        {
+          ""name"": ""deleteCount"",
+          ""type"": {
+            ""builtIn"": ""int""
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""RecommendationsResponse"",
+      ""namespace"": ""book"",
+      ""extends"": ""BaseResponse"",
+      ""fields"": [
+        {
+          ""name"": ""userId"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""books"",
+          ""type"": {
+            ""array"": {
+              ""userDefined"": ""BookWithScore""
+            }
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""UserBooksResponse"",
+      ""namespace"": ""book"",
+      ""extends"": ""BaseResponse"",
+      ""fields"": [
+        {
+          ""name"": ""userId"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""want"",
+          ""type"": {
+            ""array"": {
+              ""userDefined"": ""Book""
+            }
+          }
+        },
+        {
+          ""name"": ""have"",
+          ""type"": {
+            ""array"": {
+              ""userDefined"": ""Book""
+            }
+          }
+        },
+        {
+          ""name"": ""dislike"",
+          ""type"": {
+            ""array"": {
+              ""userDefined"": ""Book""
+            }
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""TasksResponse"",
+      ""namespace"": ""book"",
+      ""extends"": ""BaseResponse"",
+      ""fields"": [
+        {
+          ""name"": ""userId"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        },
+        {
+          ""name"": ""toLoan"",
+          ""type"": {
+            ""array"": {
+              ""userDefined"": ""ToLoanTask""
+            }
+          }
+        },
+        {
+          ""name"": ""toAck"",
+          ""type"": {
+            ""array"": {
+              ""userDefined"": ""ToAckTask""
+            }
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""LoanResponse"",
+      ""namespace"": ""book"",
+      ""extends"": ""BaseResponse"",
+      ""fields"": [
+        {
+          ""name"": ""loanId"",
+          ""type"": {
+            ""builtIn"": ""string""
+          }
+        }
+      ]
+    },
+    {
+      ""name"": ""ActivityResponse"",
+      ""namespace"": ""book"",
+      ""extends"": ""BaseResponse"",
+      ""fields"": [
+        {
+          ""name"": ""activity"",
+          ""type"": {
+            ""array"": {
+              ""userDefined"": ""BookWithStatus""
+            }
+          }
+        }
+      ]
+    }
+  ],
+  ""enums"": [
+    {
+      ""name"": ""Platform"",
+      ""namespace"": ""book"",
+      ""comment"": ""The book selling platforms we support"",
+      ""values"": [
+        {
+          ""name"": ""kindle""
+        },
+        {
+          ""name"": ""nook""
+        }
+      ]
+    },
+    {
+      ""name"": ""BookUserStatus"",
+      ""namespace"": ""book"",
+      ""values"": [
+        {
+          ""name"": ""none""
+        },
+        {
+          ""name"": ""want""
+        },
+        {
+          ""name"": ""have""
+        },
+        {
+          ""name"": ""dislike""
+        }
+      ]
+    },
+    {
+      ""name"": ""Status"",
+      ""namespace"": ""book"",
+      ""comment"": ""These are the status codes that interface functions may return."",
+      ""values"": [
+        {
+          ""name"": ""success"",
+          ""comment"": ""Request successful""
+        },
+        {
+          ""name"": ""fatal"",
+          ""comment"": ""Request failed due to some non-recoverable backend error\nsuch as the database was down.  This was not due to an invalid\nrequest""
+        },
+        {
+          ""name"": ""invalid"",
+          ""comment"": ""Request failed because input was invalid""
+        },
+        {
+          ""name"": ""notfound"",
+          ""comment"": ""Returned by query-style functions if no data is found for\nthe given parameters""
+        },
+        {
+          ""name"": ""denied"",
+          ""comment"": ""Requesting user does not have permission to perform the requested\naction""
+        }
+      ]
+    }
+  ]
+}";
+
+    private sealed record ReadOnlyRoute(string Method, List<(string Name, Dictionary<string, object> Type)> Params);
+
+    // GET paths (/<Interface>/<method>) of [readonly] methods
+    private static readonly Dictionary<string, ReadOnlyRoute> ReadOnlyRoutes = new Dictionary<string, ReadOnlyRoute>
+    {
+    };
+
+    // The result of the built-in pulserpc-capabilities method: the optional protocol
+    // features this server supports
+    private static readonly Dictionary<string, object> ServerCapabilities = new Dictionary<string, object>
+    {
+        { "protocolVersion", "2.0" },
+        { "batch", true },
+        { "notifications", true },
+        { "compression", false },
+        { "msgpack", false },
+        { "streaming", false },
+    };
+
+    private Dictionary<string, object> _handlers = new Dictionary<string, object>();
+    private WebApplication? _app;
+    private ILogger<PulseRPCServer>? _logger;
+    private readonly Composition _composition = new Composition();
+    private readonly List<(string Path, Action<IEndpointRouteBuilder, string> MapEndpoints)> _mounts = new List<(string Path, Action<IEndpointRouteBuilder, string> MapEndpoints)>();
+
+    /// <summary>
+    /// When true, POST requests must declare application/json with no charset other than
+    /// utf-8, or get HTTP 415. Otherwise, the default, any Content-Type is accepted.
+    /// </summary>
+    public bool StrictContentType { get; set; }
+
+    /// <summary>
+    /// Whether int params written with a fraction or exponent, such as 2.0, are accepted
+    /// (Lenient, the default) or rejected (Strict)
+    /// </summary>
+    public NumberPolicy NumberPolicy { get; set; }
+
+    /// <summary>
+    /// When true, responses are encoded as canonical JSON (RFC 8785, see PulseRPC.CanonicalJson),
+    /// so equal responses are equal bytes whatever language the server is written in.
+    /// </summary>
+    public bool CanonicalJson { get; set; }
+
+    /// <summary>
+    /// Checks every request, with its raw body (empty for GET), before it is dispatched,
+    /// such as RequestSigning.HmacVerifier. Throwing rejects the request with HTTP 401.
+    /// </summary>
+    public Action<HttpRequest, byte[]>? Verifier { get; set; }
+
+    /// <summary>
+    /// Per-method ("Interface.method") response size limits, with "*" for every method without
+    /// its own. Larger responses are replaced by a -32001 "Response too large" error.
+    /// </summary>
+    public Dictionary<string, int> MaxResponseBytes { get; } = new Dictionary<string, int>();
+
+    /// <summary>
+    /// Invoked after every call with its request and response sizes.
+    /// </summary>
+    public Action<CallStats>? OnCall { get; set; }
+
+    /// <summary>
+    /// Returns metadata for a successful call, such as timings, pagination hints or warnings.
+    /// Non-empty metadata is sent in the reserved "meta" member of the response, next to the
+    /// result, and clients read it with CallResult.CaptureAsync.
+    /// </summary>
+    public Func<ResponseMetaCall, IDictionary<string, object?>?>? ResponseMeta { get; set; }
+
+    // Matches the defaults ASP.NET Core uses for WriteAsJsonAsync
+    private static readonly JsonSerializerOptions ResponseJsonOptions = new JsonSerializerOptions(JsonSerializerDefaults.Web);
+
+    // Handler arguments are deserialized and results serialized with these
+    private static readonly JsonSerializerOptions HandlerJsonOptions = new JsonSerializerOptions
+    {
+        PropertyNameCaseInsensitive = true,
+        Converters = { new JsonStringEnumConverter(), new WholeNumberConverter() }
+    };
+
+    public PulseRPCServer(ILogger<PulseRPCServer>? logger = null)
+    {
+        _logger = logger;
+    }
+
+    public void Register<T>(string interfaceName, T implementation) where T : class
+    {
+        _handlers[interfaceName] = implementation!;
+        _logger?.LogInformation("Registered handler for interface: {InterfaceName}", interfaceName);
+    }
+
+    public void RegisterUserService(IUserService implementation)
+    {
+        this.Register("UserService", implementation);
+    }
+
+    public void RegisterBookService(IBookService implementation)
+    {
+        this.Register("BookService", implementation);
+    }
+
+    public void RegisterCronJobs(ICronJobs implementation)
+    {
+        this.Register("CronJobs", implementation);
+    }
+
+    public async Task RunAsync(string host = "localhost", int port = 8080)
+    {
+        var builder = WebApplication.CreateBuilder(new WebApplicationOptions
+        {
+            WebRootPath = null,
+            Args = new[] { $"--urls=http://{host}:{port}" }
+        });
+        _app = builder.Build();
+        // Get logger from app services if not already set
+        if (_logger == null)
+        {
+            _logger = _app.Services.GetService<ILogger<PulseRPCServer>>();
+        }
+
+        MapEndpoints(_app, "/");
+        foreach (var mount in _mounts)
+        {
+            mount.MapEndpoints(_app, mount.Path);
+        }
+
+        Console.WriteLine($"PulseRPC server listening on http://{host}:{port}");
+        await _app.RunAsync();
+    }
+
+    /// <summary>
+    /// Maps the JSON-RPC endpoint to a POST at path, and the [readonly] GET routes below it,
+    /// for serving from an application that already has a WebApplication.
+    /// </summary>
+    public void MapEndpoints(IEndpointRouteBuilder endpoints, string path = "/")
+    {
+        var prefix = path.TrimEnd('/');
+        endpoints.MapPost(prefix.Length == 0 ? "/" : prefix, async (HttpContext context) =>
+        {
+            await HandleRequest(context);
+        });
+        foreach (var route in ReadOnlyRoutes)
+        {
+            var readOnlyRoute = route.Value;
+            endpoints.MapGet(prefix + route.Key, async (HttpContext context) =>
+            {
+                await HandleGetRequest(context, readOnlyRoute);
+            });
+        }
+    }
+
+    /// <summary>
+    /// Serves the interfaces of another server, such as the PulseRPCServer of another IDL,
+    /// from this server's endpoint, so the services share one port:
+    /// Compose(other.Handles, other.HandleRequestAsync, () => other.IdlJson). Calls for an
+    /// interface registered here stay here, and pulserpc-idl answers with the IDLs of both merged.
+    /// </summary>
+    public void Compose(Func<string, bool> handles, Func<JsonElement, Task<Dictionary<string, object?>?>> handleRequestAsync, Func<string> idlJson)
+    {
+        _composition.Add(new ComposedService(handles, handleRequestAsync, idlJson));
+    }
+
+    /// <summary>
+    /// Serves another server under path when RunAsync starts, given its MapEndpoints, such as
+    /// Mount("/users", users.MapEndpoints). Call before RunAsync.
+    /// </summary>
+    public void Mount(string path, Action<IEndpointRouteBuilder, string> mapEndpoints)
+    {
+        _mounts.Add(("/" + path.Trim('/'), mapEndpoints));
+    }
+
+    /// <summary>
+    /// Whether method is served by this server: a method of a registered interface, or of a
+    /// composed server.
+    /// </summary>
+    public bool Handles(string method)
+    {
+        return HandlesOwn(method) || _composition.Find(method) != null;
+    }
+
+    // Whether method is a method of an interface registered with this server
+    private bool HandlesOwn(string method)
+    {
+        var parts = method.Split('.', 2);
+        if (parts.Length != 2 || !IdlData.METHOD_DEFS.TryGetValue(parts[0], out var methods) || !methods.ContainsKey(parts[1]))
+        {
+            return false;
+        }
+        return _handlers.ContainsKey(parts[0]);
+    }
+
+    /// <summary>
+    /// The idl.json document of this server, merged with those of composed servers.
+    /// </summary>
+    public string IdlJson => _composition.MergeIdl(_idlJson);
+
+    // A response HandleRequestAsync returned, such as a composed server's, as the response of one call
+    private static RpcResponse? ResponseFromDictionary(Dictionary<string, object?>? response)
+    {
+        if (response == null)
+        {
+            return null;
+        }
+        response.TryGetValue("id", out var id);
+        response.TryGetValue("result", out var result);
+        var meta = response.TryGetValue("meta", out var metaObj) ? metaObj as IDictionary<string, object?> : null;
+        if (response.TryGetValue("error", out var errorObj) && errorObj is Dictionary<string, object?> error)
+        {
+            error.TryGetValue("data", out var data);
+            var code = error.TryGetValue("code", out var codeObj) ? Convert.ToInt32(codeObj, CultureInfo.InvariantCulture) : -32603;
+            var message = error.TryGetValue("message", out var messageObj) ? messageObj?.ToString() ?? "" : "";
+            return new RpcResponse(id, Error: new RpcError(code, message, data));
+        }
+        return new RpcResponse(id, result, Meta: meta);
+    }
+
+    /// <summary>
+    /// Handles one JSON-RPC request in-process, without HTTP, and returns its response, or null
+    /// for notifications. Tests use it to call registered handlers directly.
+    /// </summary>
+    public async Task<Dictionary<string, object?>?> HandleRequestAsync(JsonElement request)
+    {
+        return (await HandleSingleRequest(ConvertJsonElementToDict(request), RawParams(request)))?.ToDictionary();
+    }
+
+    private async Task HandleRequest(HttpContext context)
+    {
+        using var deadline = Deadline.Begin(context.Request.Headers[Deadline.Header]);
+        using var meta = RequestMeta.Begin(context);
+        if (context.Request.Method != "POST")
+        {
+            context.Response.StatusCode = 405;
+            await context.Response.WriteAsJsonAsync(new { error = "Method Not Allowed" });
+            return;
+        }
+
+        var problem = StrictContentType ? CheckContentType(context.Request.ContentType) : null;
+        if (problem != null)
+        {
+            context.Response.StatusCode = 415;
+            await WriteErrorResponse(context, null, -32600, "Invalid Request", problem);
+            return;
+        }
+
+        using var bodyStream = new System.IO.MemoryStream();
+        await context.Request.Body.CopyToAsync(bodyStream);
+        var body = bodyStream.ToArray();
+        if (!await VerifyRequest(context, body))
+        {
+            return;
+        }
+
+        JsonElement requestJson;
+        try
+        {
+            requestJson = JsonSerializer.Deserialize<JsonElement>(body);
+        }
+        catch (Exception e)
+        {
+            await WriteErrorResponse(context, null, -32700, "Parse error", $"Invalid JSON: {e.Message}");
+            return;
+        }
+
+        using var output = new System.IO.MemoryStream();
+        if (requestJson.ValueKind == JsonValueKind.Array)
+        {
+            // Batch request
+            var written = 0;
+            output.WriteByte((byte)'[');
+            foreach (var req in requestJson.EnumerateArray())
+            {
+                var mark = output.Length;
+                if (written > 0) output.WriteByte((byte)',');
+                var reqDict = ConvertJsonElementToDict(req);
+                if (await HandleCall(output, reqDict, System.Text.Encoding.UTF8.GetByteCount(req.GetRawText()), RawParams(req)))
+                {
+                    written++;
+                }
+                else
+                {
+                    output.SetLength(mark);
+                }
+            }
+            if (written == 0)
+            {
+                context.Response.StatusCode = 204;
+            }
+            else
+            {
+                output.WriteByte((byte)']');
+                await WriteJsonBytes(context, output);
+            }
+        }
+        else
+        {
+            var reqDict = ConvertJsonElementToDict(requestJson);
+            if (!await HandleCall(output, reqDict, body.Length, RawParams(requestJson)))
+            {
+                context.Response.StatusCode = 204;
+            }
+            else
+            {
+                await WriteJsonBytes(context, output);
+            }
+        }
+    }
+
+    // Handles one JSON-RPC request and writes its response to output, returning false for notifications
+    private async Task<bool> HandleCall(System.IO.MemoryStream output, Dictionary<string, object?> requestJson, int requestBytes, JsonElement? rawParams)
+    {
+        var method = requestJson.TryGetValue("method", out var m) && m is string s ? s : "";
+        return WriteResponse(output, method, requestBytes, await HandleSingleRequest(requestJson, rawParams)) != null;
+    }
+
+    // The params array of a parsed request. Handler arguments are deserialized from it
+    // directly rather than from an encoded copy of the validated params.
+    private static JsonElement? RawParams(JsonElement request)
+    {
+        if (request.ValueKind == JsonValueKind.Object && request.TryGetProperty("params", out var rawParams) && rawParams.ValueKind == JsonValueKind.Array)
+        {
+            return rawParams;
+        }
+        return null;
+    }
+
+    // Writes the response of one call to output, replacing it with a -32001 error if it exceeds
+    // the method's response size limit, and reports the payload sizes to the OnCall hook.
+    // Returns the response actually sent.
+    private RpcResponse? WriteResponse(System.IO.MemoryStream output, string method, int requestBytes, RpcResponse? response)
+    {
+        var size = 0;
+        if (response != null)
+        {
+            var start = output.Length;
+            WriteMessage(output, response);
+            size = (int)(output.Length - start);
+            if ((MaxResponseBytes.TryGetValue(method, out var limit) || MaxResponseBytes.TryGetValue("*", out limit)) && size > limit)
+            {
+                output.SetLength(start);
+                response = ErrorResponse(response.Id, -32001, "Response too large",
+                    $"Response of {size} bytes exceeds the {limit} byte limit for {method}");
+                WriteMessage(output, response);
+            }
+        }
+        OnCall?.Invoke(new CallStats(method, requestBytes, size));
+        return response;
+    }
+
+    // Writes the envelope of a response, as canonical JSON if CanonicalJson is on
+    private void WriteMessage(System.IO.MemoryStream output, RpcResponse response)
+    {
+        if (!CanonicalJson)
+        {
+            WriteEnvelope(output, response);
+            return;
+        }
+        using var envelope = new System.IO.MemoryStream();
+        WriteEnvelope(envelope, response);
+        using var document = JsonDocument.Parse(envelope.ToArray());
+        output.Write(System.Text.Encoding.UTF8.GetBytes(global::PulseRPC.CanonicalJson.Encode(document.RootElement)));
+    }
+
+    // Writes the JSON-RPC envelope of a response member by member, serializing the result
+    // straight from the handler's return value
+    private static void WriteEnvelope(System.IO.Stream output, RpcResponse response)
+    {
+        using var writer = new Utf8JsonWriter(output);
+        writer.WriteStartObject();
+        writer.WriteString("jsonrpc", "2.0");
+        if (response.Error != null)
+        {
+            writer.WritePropertyName("error");
+            JsonSerializer.Serialize(writer, response.Error, ResponseJsonOptions);
+        }
+        else
+        {
+            writer.WritePropertyName("result");
+            JsonSerializer.Serialize(writer, response.Result, HandlerJsonOptions);
+        }
+        writer.WritePropertyName("id");
+        JsonSerializer.Serialize(writer, response.Id, ResponseJsonOptions);
+        if (response.Error == null && response.Meta != null && response.Meta.Count > 0)
+        {
+            writer.WritePropertyName("meta");
+            JsonSerializer.Serialize(writer, response.Meta, ResponseJsonOptions);
+        }
+        writer.WriteEndObject();
+    }
+
+    private static async Task WriteJsonBytes(HttpContext context, System.IO.MemoryStream body)
+    {
+        context.Response.ContentType = "application/json; charset=utf-8";
+        context.Response.ContentLength = body.Length;
+        await context.Response.Body.WriteAsync(body.GetBuffer().AsMemory(0, (int)body.Length));
+    }
+
+    // Orders by-name params as the method declares them; optional parameters that are
+    // left out are null. Returns null with an error for missing and unknown parameters.
+    private static List<object?>? ParamsByName(Dictionary<string, object?> named, System.Collections.IList expectedParams, out string? error)
+    {
+        var declared = expectedParams.Cast<Dictionary<string, object>>().Select(p => (string)p["name"]).ToList();
+        var unknown = named.Keys.FirstOrDefault(name => !declared.Contains(name));
+        if (unknown != null)
+        {
+            error = $"unknown parameter '{unknown}'";
+            return null;
+        }
+        var byPosition = new List<object?>();
+        foreach (Dictionary<string, object> paramDef in expectedParams)
+        {
+            var name = (string)paramDef["name"];
+            if (!named.TryGetValue(name, out var value) && !paramDef.ContainsKey("optional"))
+            {
+                error = $"missing parameter '{name}'";
+                return null;
+            }
+            byPosition.Add(value);
+        }
+        error = null;
+        return byPosition;
+    }
+
+    // Validates the Content-Type of a JSON-RPC POST request in strict mode; returns a description of the problem or null
+    private static string? CheckContentType(string? header)
+    {
+        if (string.IsNullOrWhiteSpace(header))
+        {
+            return "Missing Content-Type header; expected application/json";
+        }
+        var parts = header.Split(';').Select(p => p.Trim()).ToArray();
+        var mediaType = parts[0].ToLowerInvariant();
+        if (mediaType != "application/json" && !(mediaType.StartsWith("application/") && mediaType.EndsWith("+json")))
+        {
+            return $"Unsupported Content-Type '{mediaType}'; expected application/json";
+        }
+        foreach (var param in parts.Skip(1))
+        {
+            var eq = param.IndexOf('=');
+            if (eq >= 0 && param.Substring(0, eq).Trim().Equals("charset", StringComparison.OrdinalIgnoreCase))
+            {
+                var charset = param.Substring(eq + 1).Trim().Trim('"').ToLowerInvariant();
+                if (charset != "utf-8" && charset != "utf8")
+                {
+                    return $"Unsupported charset '{charset}'; expected utf-8";
+                }
+            }
+        }
+        return null;
+    }
+
+    // Serves a [readonly] method over HTTP GET. The response body is the JSON-RPC
+    // response envelope; errors use a non-2xx status so they are not cached.
+    private async Task HandleGetRequest(HttpContext context, ReadOnlyRoute route)
+    {
+        using var deadline = Deadline.Begin(context.Request.Headers[Deadline.Header]);
+        using var meta = RequestMeta.Begin(context);
+        if (!await VerifyRequest(context, Array.Empty<byte>()))
+        {
+            return;
+        }
+        var paramsList = new List<object?>();
+        RpcResponse? response = null;
+        foreach (var (name, type) in route.Params)
+        {
+            try
+            {
+                paramsList.Add(BindQueryParam(context.Request.Query[name].ToArray(), type));
+            }
+            catch (FormatException e)
+            {
+                response = ErrorResponse(null, -32602, "Invalid params", $"Query parameter {name}: {e.Message}");
+                break;
+            }
+        }
+        response ??= await HandleSingleRequest(new Dictionary<string, object?>
+        {
+            { "jsonrpc", "2.0" },
+            { "method", route.Method },
+            { "params", paramsList },
+            { "id", null }
+        });
+        using var output = new System.IO.MemoryStream();
+        var sent = WriteResponse(output, route.Method, System.Text.Encoding.UTF8.GetByteCount(context.Request.QueryString.Value?.TrimStart('?') ?? ""), response);
+        if (sent?.Error != null)
+        {
+            context.Response.StatusCode = RestErrorStatus(sent.Error.Code);
+        }
+        await WriteJsonBytes(context, output);
+    }
+
+    // Converts the query string values of one parameter; arrays use repeated keys (?id=1&id=2)
+    private static object? BindQueryParam(string?[] values, Dictionary<string, object> typeDef)
+    {
+        if (typeDef.TryGetValue("array", out var elementObj) && elementObj is Dictionary<string, object> elementType)
+        {
+            return values.Select(v => ParseQueryValue(v ?? "", elementType)).ToList();
+        }
+        if (values.Length == 0)
+        {
+            throw new FormatException("missing value");
+        }
+        if (values.Length > 1)
+        {
+            throw new FormatException($"expected a single value, got {values.Length}");
+        }
+        return ParseQueryValue(values[0] ?? "", typeDef);
+    }
+
+    // Converts a single query string value; enum membership is checked by parameter validation
+    private static object? ParseQueryValue(string value, Dictionary<string, object> typeDef)
+    {
+        typeDef.TryGetValue("builtIn", out var builtIn);
+        switch (builtIn as string)
+        {
+            case "int":
+                if (!long.TryParse(value, NumberStyles.Integer, CultureInfo.InvariantCulture, out var longVal))
+                    throw new FormatException($"invalid int: '{value}'");
+                return longVal >= int.MinValue && longVal <= int.MaxValue ? (object)(int)longVal : longVal;
+            case "float":
+                if (!double.TryParse(value, NumberStyles.Float, CultureInfo.InvariantCulture, out var doubleVal))
+                    throw new FormatException($"invalid float: '{value}'");
+                return doubleVal;
+            case "bool":
+                if (value == "true") return true;
+                if (value == "false") return false;
+                throw new FormatException($"invalid bool: '{value}' (expected true or false)");
+        }
+        return value;
+    }
+
+    // Maps a JSON-RPC error code to the HTTP status of a GET response
+    private static int RestErrorStatus(int code)
+    {
+        if (code == -32700 || code == -32600 || code == -32602) return 400;
+        if (code == -32601) return 404;
+        if (code >= -32768 && code <= -32000) return 500;
+        // Application-defined error codes
+        return 422;
+    }
+
+    private Dictionary<string, object?> ConvertJsonElementToDict(JsonElement element)
+    {
+        var dict = new Dictionary<string, object?>();
+        foreach (var prop in element.EnumerateObject())
+        {
+            dict[prop.Name] = ConvertJsonElementValue(prop.Value);
+        }
+        return dict;
+    }
+
+    private string? ExtractStringValue(object? value)
+    {
+        if (value is string str)
+            return str;
+        if (value is JsonElement jsonElement && jsonElement.ValueKind == JsonValueKind.String)
+            return jsonElement.GetString();
+        return null;
+    }
+
+    private object? ConvertJsonElementValue(JsonElement element)
+    {
+        switch (element.ValueKind)
+        {
+            case JsonValueKind.String:
+                return element.GetString();
+            case JsonValueKind.Number:
+                if (element.TryGetInt32(out var intVal))
+                    return intVal;
+                if (element.TryGetInt64(out var longVal))
+                    return longVal;
+                return element.GetDouble();
+            case JsonValueKind.True:
+                return true;
+            case JsonValueKind.False:
+                return false;
+            case JsonValueKind.Null:
+                return null;
+            case JsonValueKind.Array:
+                var list = new List<object?>();
+                foreach (var item in element.EnumerateArray())
+                {
+                    list.Add(ConvertJsonElementValue(item));
+                }
+                return list;
+            case JsonValueKind.Object:
+                return ConvertJsonElementToDict(element);
+            default:
+                return element;
+        }
+    }
+
+    private void ConvertEnumIntsToStrings(Dictionary<string, object?> dict, string structName, Dictionary<string, Dictionary<string, object>> allStructs, Dictionary<string, Dictionary<string, object>> allEnums)
+    {
+        var structDef = Types.FindStruct(structName, allStructs);
+        foreach (var kvp in dict.ToList())
+        {
+            var key = kvp.Key;
+            var value = kvp.Value;
+            if (value is Dictionary<string, object?> nestedDict)
+            {
+                // Determine the nested struct name
+                string nestedStructName = null;
+                if (structDef != null && structDef.TryGetValue("fields", out var fieldsObj) && fieldsObj is System.Collections.IList fields)
+                {
+                    foreach (Dictionary<string, object> field in fields)
+                    {
+                        if (field.TryGetValue("name", out var nameObj) && nameObj?.ToString() == key)
+                        {
+                            if (field.TryGetValue("type", out var typeObj) && typeObj is Dictionary<string, object> typeDict && typeDict.TryGetValue("userDefined", out var userTypeObj) && userTypeObj is string userType)
+                            {
+                                nestedStructName = Types.FindStruct(userType, allStructs) != null ? userType : null;
+                            }
+                            break;
+                        }
+                    }
+                }
+                if (nestedStructName != null)
+                {
+                    ConvertEnumIntsToStrings(nestedDict, nestedStructName, allStructs, allEnums);
+                }
+                else
+                {
+                    ConvertEnumIntsToStrings(nestedDict, null, allStructs, allEnums);
+                }
+            }
+            else if (value is System.Collections.IList list)
+            {
+                for (int i = 0; i < list.Count; i++)
+                {
+                    if (list[i] is Dictionary<string, object?> listDict)
+                    {
+                        ConvertEnumIntsToStrings(listDict, structName, allStructs, allEnums);
+                    }
+                }
+            }
+            else if (value is int intVal && structDef != null)
+            {
+                // Check if this field is an enum type before converting
+                string enumTypeName = null;
+                if (structDef.TryGetValue("fields", out var fieldsObj) && fieldsObj is System.Collections.IList fields)
+                {
+                    foreach (Dictionary<string, object> field in fields)
+                    {
+                        if (field.TryGetValue("name", out var nameObj) && nameObj?.ToString() == key)
+                        {
+                            if (field.TryGetValue("type", out var typeObj) && typeObj is Dictionary<string, object> typeDict && typeDict.TryGetValue("userDefined", out var userTypeObj) && userTypeObj is string userType)
+                            {
+                                if (Types.FindEnum(userType, allEnums) != null)
+                                {
+                                    enumTypeName = userType;
+                                }
+                            }
+                            break;
+                        }
+                    }
+                }
+                if (enumTypeName != null && allEnums.TryGetValue(enumTypeName, out var enumDef))
+                {
+                    if (enumDef.TryGetValue("values", out var valuesObj) && valuesObj is System.Collections.IList enumValues && intVal >= 0 && intVal < enumValues.Count)
+                    {
+                        var enumValue = enumValues[intVal];
+                        if (enumValue is Dictionary<string, object> enumValueDict && enumValueDict.TryGetValue("name", out var nameObj))
+                        {
+                            dict[key] = nameObj?.ToString();
+                        }
+                        else if (enumValue is string enumName)
+                        {
+                            dict[key] = enumName;
+                        }
+                    }
+                }
+            }
+        }
+    }
+
+    private async Task<RpcResponse?> HandleSingleRequest(Dictionary<string, object?> requestJson, JsonElement? rawParams = null)
+    {
+        // Validate JSON-RPC 2.0 structure
+        if (!requestJson.TryGetValue("jsonrpc", out var jsonrpcObj))
+        {
+            _logger?.LogWarning("Missing jsonrpc field");
+            return ErrorResponse(null, -32600, "Invalid Request", "jsonrpc field is required");
+        }
+        var jsonrpc = ExtractStringValue(jsonrpcObj);
+        if (jsonrpc != "2.0")
+        {
+            _logger?.LogWarning("Invalid JSON-RPC version: {JsonRpc}", jsonrpc ?? "null");
+            return ErrorResponse(null, -32600, "Invalid Request", "jsonrpc must be '2.0'");
+        }
+
+        if (!requestJson.TryGetValue("method", out var methodObj))
+        {
+            _logger?.LogWarning("Missing method field");
+            return ErrorResponse(null, -32600, "Invalid Request", "method field is required");
+        }
+        var method = ExtractStringValue(methodObj);
+        if (method == null)
+        {
+            _logger?.LogWarning("Invalid method in request: {Method}", methodObj?.ToString() ?? "null");
+            return ErrorResponse(null, -32600, "Invalid Request", "method must be a string");
+        }
+
+        requestJson.TryGetValue("params", out var paramsObj);
+        requestJson.TryGetValue("id", out var requestId);
+        bool isNotification = !requestJson.ContainsKey("id");
+        _logger?.LogInformation("Received request: method={Method}, id={RequestId}, isNotification={IsNotification}", method, requestId, isNotification);
+
+        // Special case: pulserpc-idl method
+        if (method == "pulserpc-idl")
+        {
+            _logger?.LogDebug("Handling pulserpc-idl request");
+            try
+            {
+                var idlDoc = JsonSerializer.Deserialize<object>(IdlJson);
+                if (isNotification) return null;
+                return new RpcResponse(requestId, idlDoc);
+            }
+            catch (Exception e)
+            {
+                _logger?.LogError(e, "Failed to deserialize IDL JSON");
+                return ErrorResponse(requestId, -32603, "Internal error", $"Failed to deserialize IDL JSON: {e.Message}");
+            }
+        }
+
+        // Special case: pulserpc-capabilities method reports the optional protocol features
+        if (method == "pulserpc-capabilities")
+        {
+            if (isNotification) return null;
+            return new RpcResponse(requestId, ServerCapabilities);
+        }
+
+        // Calls for the interfaces of a composed server are handled by that server
+        if (!HandlesOwn(method) && _composition.Find(method) is ComposedService composed)
+        {
+            return ResponseFromDictionary(await composed.HandleRequestAsync(JsonSerializer.SerializeToElement(requestJson)));
+        }
+
+        // Parse method name: interface.method
+        var parts = method.Split('.', 2);
+        if (parts.Length != 2)
+        {
+            _logger?.LogWarning("Invalid method format: {Method}", method);
+            return ErrorResponse(requestId, -32601, "Method not found", $"Invalid method format: {method}");
+        }
+
+        var interfaceName = parts[0];
+        var methodName = parts[1];
+        _logger?.LogDebug("Parsed method: interface={InterfaceName}, method={MethodName}", interfaceName, methodName);
+
+        // Find handler
+        if (!_handlers.TryGetValue(interfaceName, out var handler))
+        {
+            _logger?.LogWarning("Interface not registered: {InterfaceName}", interfaceName);
+            return ErrorResponse(requestId, -32601, "Method not found", $"Interface '{interfaceName}' not registered");
+        }
+
+        // Find method definition
+        Dictionary<string, object>? methodDef = null;
+
+        if (IdlData.METHOD_DEFS.TryGetValue(interfaceName, out var interfaceMethods))
+        {
+            methodDef = interfaceMethods.TryGetValue(methodName, out var def) ? def : null;
+        }
+
+        if (methodDef == null)
+        {
+            _logger?.LogWarning("Method not found: {InterfaceName}.{MethodName}", interfaceName, methodName);
+            return ErrorResponse(requestId, -32601, "Method not found", $"Method '{methodName}' not found in interface '{interfaceName}'");
+        }
+
+        // Validate params
+        var paramsList = paramsObj as System.Collections.IList ?? new List<object>();
+        var expectedParams = (methodDef["parameters"] as System.Collections.IList) ?? new List<object>();
+        if (paramsObj is Dictionary<string, object?> namedParams)
+        {
+            var byPosition = ParamsByName(namedParams, expectedParams, out var namedError);
+            if (byPosition == null)
+            {
+                return ErrorResponse(requestId, -32602, "Invalid params", namedError);
+            }
+            paramsObj = byPosition;
+            paramsList = byPosition;
+        }
+        // Params are logged once their types are known so that sensitive fields can be masked
+        _logger?.LogDebug("Request params: {Params}", Redaction.ParamsToJson(paramsObj, expectedParams, IdlData.ALL_STRUCTS));
+        _logger?.LogDebug("Validating params: expected={ExpectedCount}, got={ActualCount}", expectedParams.Count, paramsList.Count);
+        if (paramsList.Count != expectedParams.Count)
+        {
+            _logger?.LogWarning("Parameter count mismatch: expected={ExpectedCount}, got={ActualCount}", expectedParams.Count, paramsList.Count);
+            return ErrorResponse(requestId, -32602, "Invalid params", $"Expected {expectedParams.Count} parameters, got {paramsList.Count}");
+        }
+
+        // Validate each param
+        for (int i = 0; i < paramsList.Count; i++)
+        {
+            var paramValue = paramsList[i];
+            var paramDef = (expectedParams[i] as Dictionary<string, object>)!;
+            var paramName = paramDef.TryGetValue("name", out var name) ? name?.ToString() : $"parameter {i}";
+            _logger?.LogDebug("Validating parameter {Index} ({ParamName})", i, paramName);
+            try
+            {
+                var typeDef = (Dictionary<string, object>)paramDef["type"];
+                // Convert enum objects/values to strings for validation
+                object? valueToValidate = paramValue;
+                if (typeDef.TryGetValue("userDefined", out var userTypeObj) && userTypeObj is string userType)
+                {
+                    var enumDef = Types.FindEnum(userType, IdlData.ALL_ENUMS);
+                    if (enumDef != null && paramValue != null)
+                    {
+                        if (paramValue is System.Text.Json.JsonElement jsonElem)
+                        {
+                            // Handle JsonElement enum values (could be string or number)
+                            if (jsonElem.ValueKind == System.Text.Json.JsonValueKind.String)
+                            {
+                                valueToValidate = jsonElem.GetString();
+                            }
+                            else if (jsonElem.ValueKind == System.Text.Json.JsonValueKind.Number && jsonElem.TryGetInt32(out var enumInt))
+                            {
+                                // Convert integer enum value to string by looking up in enum definition
+                                if (enumDef.TryGetValue("values", out var valuesObj) && valuesObj is System.Collections.IList enumValues && enumInt >= 0 && enumInt < enumValues.Count)
+                                {
+                                    var enumValue = enumValues[enumInt];
+                                    if (enumValue is Dictionary<string, object> enumValueDict && enumValueDict.TryGetValue("name", out var nameObj))
+                                    {
+                                        valueToValidate = nameObj?.ToString();
+                                    }
+                                    else if (enumValue is string enumName)
+                                    {
+                                        valueToValidate = enumName;
+                                    }
+                                    else
+                                    {
+                                        // Fallback: use the integer as string
+                                        valueToValidate = enumInt.ToString();
+                                    }
+                                }
+                                else
+                                {
+                                    // Enum definition structure doesn't match expected format, use integer as string
+                                    valueToValidate = enumInt.ToString();
+                                }
+                            }
+                        }
+                        else if (paramValue is int enumIntVal)
+                        {
+                            // Convert integer enum value to string by looking up in enum definition
+                            if (enumDef.TryGetValue("values", out var valuesObj) && valuesObj is System.Collections.IList enumValues && enumIntVal >= 0 && enumIntVal < enumValues.Count)
+                            {
+                                var enumValue = enumValues[enumIntVal];
+                                if (enumValue is Dictionary<string, object> enumValueDict && enumValueDict.TryGetValue("name", out var nameObj))
+                                {
+                                    valueToValidate = nameObj?.ToString();
+                                }
+                                else if (enumValue is string enumName)
+                                {
+                                    valueToValidate = enumName;
+                                }
+                                else
+                                {
+                                    // Fallback: use the integer as string
+                                    valueToValidate = enumIntVal.ToString();
+                                }
+                            }
+                            else
+                            {
+                                // Enum definition structure doesn't match expected format, use integer as string
+                                valueToValidate = enumIntVal.ToString();
+                            }
+                        }
+                        else if (!(paramValue is string))
+                        {
+                            // Convert enum object to string representation
+                            valueToValidate = paramValue.ToString();
+                        }
+                    }
+                }
+                Validation.ValidateType(valueToValidate, typeDef, IdlData.ALL_STRUCTS, IdlData.ALL_ENUMS, false);
+                if (NumberPolicy == NumberPolicy.Strict)
+                {
+                    Numbers.CheckIntLiterals(valueToValidate, typeDef, IdlData.ALL_STRUCTS);
+                }
+            }
+            catch (Exception e)
+            {
+                _logger?.LogError(e, "Parameter validation failed: parameter {Index} ({ParamName})", i, paramName);
+                return ErrorResponse(requestId, -32602, "Invalid params", $"Parameter {i} ({paramName}) validation failed: {e.Message}");
+            }
+        }
+
+        // Invoke handler using reflection
+        var jsonOptions = HandlerJsonOptions;
+        object? result;
+        var started = System.Diagnostics.Stopwatch.StartNew();
+        try
+        {
+            _logger?.LogDebug("Invoking method {InterfaceName}.{MethodName}", interfaceName, methodName);
+            var handlerType = handler.GetType();
+            var methodInfo = handlerType.GetMethod(methodName);
+            if (methodInfo == null)
+            {
+                _logger?.LogError("Method not found via reflection: {InterfaceName}.{MethodName}", interfaceName, methodName);
+                return ErrorResponse(requestId, -32601, "Method not found", $"Method '{methodName}' not found on interface '{interfaceName}'");
+            }
+            // Deserialize parameters to expected types using method parameter types
+            var paramInfos = methodInfo.GetParameters();
+            var deserializedParams = new object[paramsList.Count];
+            var rawItems = rawParams?.EnumerateArray().ToList();
+            for (int i = 0; i < paramsList.Count; i++)
+            {
+                var paramValue = paramsList[i];
+                var paramType = paramInfos[i].ParameterType;
+                _logger?.LogDebug("Deserializing parameter {Index} to type {ParamType}", i, paramType.Name);
+                // A param as sent is read from the parsed request; only params filled in by
+                // defaults are encoded from their validated form
+                if (rawItems != null && i < rawItems.Count && rawItems[i].ValueKind != JsonValueKind.Null)
+                {
+                    deserializedParams[i] = JsonSerializer.Deserialize(rawItems[i], paramType, jsonOptions);
+                    continue;
+                }
+                string paramJson;
+                if (paramValue is System.Text.Json.JsonElement jsonElement)
+                {
+                    paramJson = jsonElement.GetRawText();
+                }
+                else
+                {
+                    paramJson = JsonSerializer.Serialize(paramValue);
+                }
+                deserializedParams[i] = JsonSerializer.Deserialize(paramJson, paramType, jsonOptions);
+            }
+            _logger?.LogDebug("Calling method {InterfaceName}.{MethodName} with {ParamCount} parameters", interfaceName, methodName, deserializedParams.Length);
+            result = methodInfo.Invoke(handler, deserializedParams);
+            if (result is Task task)
+            {
+                await task;
+                var resultProperty = task.GetType().GetProperty("Result");
+                result = resultProperty?.GetValue(task);
+            }
+            _logger?.LogDebug("Method {InterfaceName}.{MethodName} completed successfully", interfaceName, methodName);
+        }
+        catch (RPCError rpcErr)
+        {
+            _logger?.LogWarning("RPCError from {InterfaceName}.{MethodName}: {Code} - {Message}", interfaceName, methodName, rpcErr.Code, rpcErr.Message);
+            return ErrorResponse(requestId, rpcErr.Code, rpcErr.Message, rpcErr.Data);
+        }
+        catch (Exception e)
+        {
+            _logger?.LogError(e, "Exception invoking {InterfaceName}.{MethodName}: {Message}", interfaceName, methodName, e.Message);
+            return ErrorResponse(requestId, -32603, "Internal error", $"Exception: {e.Message}\nStackTrace: {e.StackTrace}");
+        }
+
+        // Validate response
+        if (methodDef.TryGetValue("returnType", out var returnTypeObj) && returnTypeObj is Dictionary<string, object> returnType)
+        {
+            _logger?.LogDebug("Validating response for {InterfaceName}.{MethodName}", interfaceName, methodName);
+            try
+            {
+                var returnOptional = methodDef.TryGetValue("returnOptional", out var opt) && opt is bool optBool && optBool;
+                // Convert struct objects to dictionaries and enum objects to strings for validation
+                object? valueToValidate = result;
+                if (returnType.TryGetValue("userDefined", out var returnUserTypeObj) && returnUserTypeObj is string returnUserType)
+                {
+                    var structDef = Types.FindStruct(returnUserType, IdlData.ALL_STRUCTS);
+                    if (structDef != null && result != null && !(result is Dictionary<string, object?>))
+                    {
+                        // Serialize struct object to JSON, then convert JsonElement to dictionary with proper type conversion
+                        var structResultJson = JsonSerializer.Serialize(result, jsonOptions);
+                        var structJsonElement = JsonSerializer.Deserialize<JsonElement>(structResultJson);
+                        valueToValidate = ConvertJsonElementToDict(structJsonElement);
+                        // Convert enum integers to strings for validation
+                        if (valueToValidate is Dictionary<string, object?> structDict)
+                        {
+                            ConvertEnumIntsToStrings(structDict, returnUserType, IdlData.ALL_STRUCTS, IdlData.ALL_ENUMS);
+                        }
+                    }
+                    else
+                    {
+                        var enumDef = Types.FindEnum(returnUserType, IdlData.ALL_ENUMS);
+                        if (enumDef != null && result != null && !(result is string) && !(result is System.Text.Json.JsonElement))
+                        {
+                            // Convert enum object to string representation
+                            valueToValidate = result.ToString();
+                        }
+                    }
+                }
+                // Handle arrays of structs or enums - convert elements to dictionaries/strings for validation
+                else if (returnType.TryGetValue("array", out var arrayObj) && arrayObj is Dictionary<string, object> elementType)
+                {
+                    if (result != null && elementType.TryGetValue("userDefined", out var elementUserTypeObj) && elementUserTypeObj is string elementUserType)
+                    {
+                        var structDef = Types.FindStruct(elementUserType, IdlData.ALL_STRUCTS);
+                        if (structDef != null && result is System.Collections.IList resultEnum)
+                        {
+                            // Convert each struct object in the array to a dictionary for validation
+                            var convertedList = new List<object?>();
+                            foreach (var item in resultEnum)
+                            {
+                                if (item != null && !(item is Dictionary<string, object?>))
+                                {
+                                    var itemJson = JsonSerializer.Serialize(item, jsonOptions);
+                                    var itemJsonElement = JsonSerializer.Deserialize<JsonElement>(itemJson);
+                                    var itemDict = ConvertJsonElementToDict(itemJsonElement);
+                                    if (itemDict is Dictionary<string, object?> dict)
+                                    {
+                                        ConvertEnumIntsToStrings(dict, elementUserType, IdlData.ALL_STRUCTS, IdlData.ALL_ENUMS);
+                                    }
+                                    convertedList.Add(itemDict);
+                                }
+                                else
+                                {
+                                    convertedList.Add(item);
+                                }
+                            }
+                            valueToValidate = convertedList;
+                        }
+                        else
+                        {
+                            var enumDef = Types.FindEnum(elementUserType, IdlData.ALL_ENUMS);
+                            if (enumDef != null && result is System.Collections.IList enumList)
+                            {
+                                // Convert each enum object in the array to a string for validation
+                                var convertedEnumList = new List<object?>();
+                                foreach (var item in enumList)
+                                {
+                                    if (item != null && !(item is string) && !(item is System.Text.Json.JsonElement))
+                                    {
+                                        convertedEnumList.Add(item.ToString());
+                                    }
+                                    else
+                                    {
+                                        convertedEnumList.Add(item);
+                                    }
+                                }
+                                valueToValidate = convertedEnumList;
+                            }
+                        }
+                    }
+                }
+                Validation.ValidateType(valueToValidate, returnType, IdlData.ALL_STRUCTS, IdlData.ALL_ENUMS, returnOptional);
+                _logger?.LogDebug("Response validation passed for {InterfaceName}.{MethodName}", interfaceName, methodName);
+            }
+            catch (Exception e)
+            {
+                _logger?.LogError(e, "Response validation failed for {InterfaceName}.{MethodName}", interfaceName, methodName);
+                return ErrorResponse(requestId, -32603, "Internal error", $"Response validation failed: {e.Message}");
+            }
+        }
+
+        // Return success response
+        if (isNotification) return null;
+        var meta = ResponseMeta?.Invoke(new ResponseMetaCall(method, paramsList.Cast<object?>().ToList(), result, started.Elapsed));
+        return new RpcResponse(requestId, result, Meta: meta);
+    }
+
+    private static RpcResponse ErrorResponse(object? requestId, int code, string message, object? data = null)
+    {
+        return new RpcResponse(requestId, Error: new RpcError(code, message, data));
+    }
+
+    // The response to one call; Error is null on success
+    private sealed record RpcResponse(object? Id, object? Result = null, RpcError? Error = null, IDictionary<string, object?>? Meta = null)
+    {
+        // The response as HandleRequestAsync returns it, with the result as a JsonElement
+        public Dictionary<string, object?> ToDictionary()
+        {
+            var response = new Dictionary<string, object?> { { "jsonrpc", "2.0" } };
+            if (Error != null)
+            {
+                var error = new Dictionary<string, object?> { { "code", Error.Code }, { "message", Error.Message } };
+                if (Error.Data != null) error["data"] = Error.Data;
+                response["error"] = error;
+            }
+            else
+            {
+                response["result"] = JsonSerializer.SerializeToElement(Result, HandlerJsonOptions);
+            }
+            response["id"] = Id;
+            if (Error == null && Meta != null && Meta.Count > 0)
+            {
+                response["meta"] = Meta;
+            }
+            return response;
+        }
+    }
+
+    private sealed record RpcError(
+        [property: JsonPropertyName("code")] int Code,
+        [property: JsonPropertyName("message")] string Message,
+        [property: JsonPropertyName("data"), JsonIgnore(Condition = JsonIgnoreCondition.WhenWritingNull)] object? Data);
+
+    // Runs the Verifier, if any, answering 401 when it rejects the request
+    private async Task<bool> VerifyRequest(HttpContext context, byte[] body)
+    {
+        if (Verifier == null)
+        {
+            return true;
+        }
+        try
+        {
+            Verifier(context.Request, body);
+            return true;
+        }
+        catch (Exception e)
+        {
+            context.Response.StatusCode = 401;
+            await WriteErrorResponse(context, null, -32600, "Invalid Request", e.Message);
+            return false;
+        }
+    }
+
+    private async Task WriteErrorResponse(HttpContext context, object? requestId, int code, string message, object? data = null)
+    {
+        using var output = new System.IO.MemoryStream();
+        WriteMessage(output, ErrorResponse(requestId, code, message, data));
+        await WriteJsonBytes(context, output);
+    }
+}
+}
@@ -0,0 +1,777 @@
+// Generated by pulserpc - do not edit
+
+package book
+
+// The book selling platforms we support
+type Platform string
+
+const (
+	PlatformKindle Platform = "kindle"
+	PlatformNook   Platform = "nook"
+)
+
+type BookUserStatus string
+
+const (
+	BookUserStatusNone    BookUserStatus = "none"
+	BookUserStatusWant    BookUserStatus = "want"
+	BookUserStatusHave    BookUserStatus = "have"
+	BookUserStatusDislike BookUserStatus = "dislike"
+)
+
+// These are the status codes that interface functions may return.
+type Status string
+
+const (
+	StatusSuccess  Status = "success"
+	StatusFatal    Status = "fatal"
+	StatusInvalid  Status = "invalid"
+	StatusNotfound Status = "notfound"
+	StatusDenied   Status = "denied"
+)
+
+type Book struct {
+	ProductId   string   `json:"productId"`
+	DateCreated int      `json:"dateCreated"`
+	DateUpdated int      `json:"dateUpdated"`
+	Platform    Platform `json:"platform"`
+	Author      string   `json:"author"`
+	Title       string   `json:"title"`
+	ProductUrl  string   `json:"productUrl"`
+	ImageUrl    string   `json:"imageUrl"`
+	Lendable    bool     `json:"lendable"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *Book) Clone() *Book {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+type BookWithStatus struct {
+	Book
+	UserStatus BookUserStatus `json:"userStatus"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *BookWithStatus) Clone() *BookWithStatus {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	c.Book = *v.Book.Clone()
+	return &c
+}
+
+type BookWithScore struct {
+	BookWithStatus
+	Score float64 `json:"score"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *BookWithScore) Clone() *BookWithScore {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	c.BookWithStatus = *v.BookWithStatus.Clone()
+	return &c
+}
+
+type User struct {
+	UserId      string `json:"userId"`
+	Name        string `json:"name"`
+	Points      int    `json:"points"`
+	DateCreated int    `json:"dateCreated"`
+	Email       string `json:"email"`
+	KindleEmail string `json:"kindleEmail"`
+	NookEmail   string `json:"nookEmail"`
+	EmailOptIn  bool   `json:"emailOptIn"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *User) Clone() *User {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+type UserUpdate struct {
+	UserId      string `json:"userId"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	KindleEmail string `json:"kindleEmail"`
+	NookEmail   string `json:"nookEmail"`
+	EmailOptIn  bool   `json:"emailOptIn"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *UserUpdate) Clone() *UserUpdate {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+type SearchRequest struct {
+	Platforms []Platform `json:"platforms"`
+	UserId    string     `json:"userId"`
+	Keyword   string     `json:"keyword"`
+	Offset    int        `json:"offset"`
+	Limit     int        `json:"limit"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *SearchRequest) Clone() *SearchRequest {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	if v.Platforms != nil {
+		c.Platforms = make([]Platform, len(v.Platforms))
+		copy(c.Platforms, v.Platforms)
+	}
+	return &c
+}
+
+type Recipient struct {
+	UserId string `json:"userId"`
+	Email  string `json:"email"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *Recipient) Clone() *Recipient {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+type ToLoanTask struct {
+	Book       Book        `json:"book"`
+	Recipients []Recipient `json:"recipients"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *ToLoanTask) Clone() *ToLoanTask {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	c.Book = *v.Book.Clone()
+	if v.Recipients != nil {
+		c.Recipients = make([]Recipient, len(v.Recipients))
+		for i0 := range v.Recipients {
+			c.Recipients[i0] = *v.Recipients[i0].Clone()
+		}
+	}
+	return &c
+}
+
+type ToAckTask struct {
+	Book       Book   `json:"book"`
+	FromEmail  string `json:"fromEmail"`
+	LoanId     string `json:"loanId"`
+	DateLoaned int    `json:"dateLoaned"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *ToAckTask) Clone() *ToAckTask {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	c.Book = *v.Book.Clone()
+	return &c
+}
+
+type BaseResponse struct {
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *BaseResponse) Clone() *BaseResponse {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+type UserResponse struct {
+	BaseResponse
+	User User `json:"user"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *UserResponse) Clone() *UserResponse {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	c.BaseResponse = *v.BaseResponse.Clone()
+	c.User = *v.User.Clone()
+	return &c
+}
+
+type BookResponse struct {
+	BaseResponse
+	UserId string         `json:"userId"`
+	Book   BookWithStatus `json:"book"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *BookResponse) Clone() *BookResponse {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	c.BaseResponse = *v.BaseResponse.Clone()
+	c.Book = *v.Book.Clone()
+	return &c
+}
+
+type BooksResponse struct {
+	BaseResponse
+	UserId    string           `json:"userId"`
+	TotalRows int              `json:"totalRows"`
+	Offset    int              `json:"offset"`
+	Books     []BookWithStatus `json:"books"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *BooksResponse) Clone() *BooksResponse {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	c.BaseResponse = *v.BaseResponse.Clone()
+	if v.Books != nil {
+		c.Books = make([]BookWithStatus, len(v.Books))
+		for i0 := range v.Books {
+			c.Books[i0] = *v.Books[i0].Clone()
+		}
+	}
+	return &c
+}
+
+type DeleteResponse struct {
+	BaseResponse
+	DeleteCount int `json:"deleteCount"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *DeleteResponse) Clone() *DeleteResponse {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	c.BaseResponse = *v.BaseResponse.Clone()
+	return &c
+}
+
+type RecommendationsResponse struct {
+	BaseResponse
+	UserId string          `json:"userId"`
+	Books  []BookWithScore `json:"books"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *RecommendationsResponse) Clone() *RecommendationsResponse {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	c.BaseResponse = *v.BaseResponse.Clone()
+	if v.Books != nil {
+		c.Books = make([]BookWithScore, len(v.Books))
+		for i0 := range v.Books {
+			c.Books[i0] = *v.Books[i0].Clone()
+		}
+	}
+	return &c
+}
+
+type UserBooksResponse struct {
+	BaseResponse
+	UserId  string `json:"userId"`
+	Want    []Book `json:"want"`
+	Have    []Book `json:"have"`
+	Dislike []Book `json:"dislike"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *UserBooksResponse) Clone() *UserBooksResponse {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	c.BaseResponse = *v.BaseResponse.Clone()
+	if v.Want != nil {
+		c.Want = make([]Book, len(v.Want))
+		for i0 := range v.Want {
+			c.Want[i0] = *v.Want[i0].Clone()
+		}
+	}
+	if v.Have != nil {
+		c.Have = make([]Book, len(v.Have))
+		for i0 := range v.Have {
+			c.Have[i0] = *v.Have[i0].Clone()
+		}
+	}
+	if v.Dislike != nil {
+		c.Dislike = make([]Book, len(v.Dislike))
+		for i0 := range v.Dislike {
+			c.Dislike[i0] = *v.Dislike[i0].Clone()
+		}
+	}
+	return &c
+}
+
+type TasksResponse struct {
+	BaseResponse
+	UserId string       `json:"userId"`
+	ToLoan []ToLoanTask `json:"toLoan"`
+	ToAck  []ToAckTask  `json:"toAck"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *TasksResponse) Clone() *TasksResponse {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	c.BaseResponse = *v.BaseResponse.Clone()
+	if v.ToLoan != nil {
+		c.ToLoan = make([]ToLoanTask, len(v.ToLoan))
+		for i0 := range v.ToLoan {
+			c.ToLoan[i0] = *v.ToLoan[i0].Clone()
+		}
+	}
+	if v.ToAck != nil {
+		c.ToAck = make([]ToAckTask, len(v.ToAck))
+		for i0 := range v.ToAck {
+			c.ToAck[i0] = *v.ToAck[i0].Clone()
+		}
+	}
+	return &c
+}
+
+type LoanResponse struct {
+	BaseResponse
+	LoanId string `json:"loanId"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *LoanResponse) Clone() *LoanResponse {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	c.BaseResponse = *v.BaseResponse.Clone()
+	return &c
+}
+
+type ActivityResponse struct {
+	BaseResponse
+	Activity []BookWithStatus `json:"activity"`
+}
+
+// Clone returns a deep copy of v that shares no pointers, slices or maps with it
+func (v *ActivityResponse) Clone() *ActivityResponse {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	c.BaseResponse = *v.BaseResponse.Clone()
+	if v.Activity != nil {
+		c.Activity = make([]BookWithStatus, len(v.Activity))
+		for i0 := range v.Activity {
+			c.Activity[i0] = *v.Activity[i0].Clone()
+		}
+	}
+	return &c
+}
+
+// IDL-specific type definitions for namespace: book
+var BOOK_ALL_STRUCTS = StructMap{
+	"Book": StructDef{
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "productId",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "dateCreated",
+				"type": map[string]interface{}{"builtIn": "int"},
+			},
+			map[string]interface{}{
+				"name": "dateUpdated",
+				"type": map[string]interface{}{"builtIn": "int"},
+			},
+			map[string]interface{}{
+				"name": "platform",
+				"type": map[string]interface{}{"userDefined": "Platform"},
+			},
+			map[string]interface{}{
+				"name": "author",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "title",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "productUrl",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "imageUrl",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "lendable",
+				"type": map[string]interface{}{"builtIn": "bool"},
+			},
+		},
+	},
+	"BookWithStatus": StructDef{
+		"extends": "Book",
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "userStatus",
+				"type": map[string]interface{}{"userDefined": "BookUserStatus"},
+			},
+		},
+	},
+	"BookWithScore": StructDef{
+		"extends": "BookWithStatus",
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "score",
+				"type": map[string]interface{}{"builtIn": "float"},
+			},
+		},
+	},
+	"User": StructDef{
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "userId",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "name",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "points",
+				"type": map[string]interface{}{"builtIn": "int"},
+			},
+			map[string]interface{}{
+				"name": "dateCreated",
+				"type": map[string]interface{}{"builtIn": "int"},
+			},
+			map[string]interface{}{
+				"name": "email",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "kindleEmail",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "nookEmail",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "emailOptIn",
+				"type": map[string]interface{}{"builtIn": "bool"},
+			},
+		},
+	},
+	"UserUpdate": StructDef{
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "userId",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "name",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "email",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "kindleEmail",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "nookEmail",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "emailOptIn",
+				"type": map[string]interface{}{"builtIn": "bool"},
+			},
+		},
+	},
+	"SearchRequest": StructDef{
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "platforms",
+				"type": map[string]interface{}{"array": map[string]interface{}{"userDefined": "Platform"}},
+			},
+			map[string]interface{}{
+				"name": "userId",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "keyword",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "offset",
+				"type": map[string]interface{}{"builtIn": "int"},
+			},
+			map[string]interface{}{
+				"name": "limit",
+				"type": map[string]interface{}{"builtIn": "int"},
+			},
+		},
+	},
+	"Recipient": StructDef{
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "userId",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "email",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+		},
+	},
+	"ToLoanTask": StructDef{
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "book",
+				"type": map[string]interface{}{"userDefined": "Book"},
+			},
+			map[string]interface{}{
+				"name": "recipients",
+				"type": map[string]interface{}{"array": map[string]interface{}{"userDefined": "Recipient"}},
+			},
+		},
+	},
+	"ToAckTask": StructDef{
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "book",
+				"type": map[string]interface{}{"userDefined": "Book"},
+			},
+			map[string]interface{}{
+				"name": "fromEmail",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "loanId",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "dateLoaned",
+				"type": map[string]interface{}{"builtIn": "int"},
+			},
+		},
+	},
+	"BaseResponse": StructDef{
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "status",
+				"type": map[string]interface{}{"userDefined": "Status"},
+			},
+			map[string]interface{}{
+				"name": "message",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+		},
+	},
+	"UserResponse": StructDef{
+		"extends": "BaseResponse",
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "user",
+				"type": map[string]interface{}{"userDefined": "User"},
+			},
+		},
+	},
+	"BookResponse": StructDef{
+		"extends": "BaseResponse",
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "userId",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "book",
+				"type": map[string]interface{}{"userDefined": "BookWithStatus"},
+			},
+		},
+	},
+	"BooksResponse": StructDef{
+		"extends": "BaseResponse",
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "userId",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "totalRows",
+				"type": map[string]interface{}{"builtIn": "int"},
+			},
+			map[string]interface{}{
+				"name": "offset",
+				"type": map[string]interface{}{"builtIn": "int"},
+			},
+			map[string]interface{}{
+				"name": "books",
+				"type": map[string]interface{}{"array": map[string]interface{}{"userDefined": "BookWithStatus"}},
+			},
+		},
+	},
+	"DeleteResponse": StructDef{
+		"extends": "BaseResponse",
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "deleteCount",
+				"type": map[string]interface{}{"builtIn": "int"},
+			},
+		},
+	},
+	"RecommendationsResponse": StructDef{
+		"extends": "BaseResponse",
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "userId",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "books",
+				"type": map[string]interface{}{"array": map[string]interface{}{"userDefined": "BookWithScore"}},
+			},
+		},
+	},
+	"UserBooksResponse": StructDef{
+		"extends": "BaseResponse",
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "userId",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "want",
+				"type": map[string]interface{}{"array": map[string]interface{}{"userDefined": "Book"}},
+			},
+			map[string]interface{}{
+				"name": "have",
+				"type": map[string]interface{}{"array": map[string]interface{}{"userDefined": "Book"}},
+			},
+			map[string]interface{}{
+				"name": "dislike",
+				"type": map[string]interface{}{"array": map[string]interface{}{"userDefined": "Book"}},
+			},
+		},
+	},
+	"TasksResponse": StructDef{
+		"extends": "BaseResponse",
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "userId",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+			map[string]interface{}{
+				"name": "toLoan",
+				"type": map[string]interface{}{"array": map[string]interface{}{"userDefined": "ToLoanTask"}},
+			},
+			map[string]interface{}{
+				"name": "toAck",
+				"type": map[string]interface{}{"array": map[string]interface{}{"userDefined": "ToAckTask"}},
+			},
+		},
+	},
+	"LoanResponse": StructDef{
+		"extends": "BaseResponse",
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "loanId",
+				"type": map[string]interface{}{"builtIn": "string"},
+			},
+		},
+	},
+	"ActivityResponse": StructDef{
+		"extends": "BaseResponse",
+		"fields": []interface{}{
+			map[string]interface{}{
+				"name": "activity",
+				"type": map[string]interface{}{"array": map[string]interface{}{"userDefined": "BookWithStatus"}},
+			},
+		},
+	},
+}
+
+var BOOK_ALL_ENUMS = EnumMap{
+	"Platform": EnumDef{
+		"values": []interface{}{
+			map[string]interface{}{
+				"name": "kindle",
+			},
+			map[string]interface{}{
+				"name": "nook",
+			},
+		},
+	},
+	"BookUserStatus": EnumDef{
+		"values": []interface{}{
+			map[string]interface{}{
+				"name": "none",
+			},
+			map[string]interface{}{
+				"name": "want",
+			},
+			map[string]interface{}{
+				"name": "have",
+			},
+			map[string]interface{}{
+				"name": "dislike",
+			},
+		},
+	},
+	"Status": EnumDef{
+		"values": []interface{}{
+			map[string]interface{}{
+				"name": "success",
+			},
+			map[string]interface{}{
+				"name": "fatal",
+			},
+			map[string]interface{}{
+				"name": "invalid",
+			},
+			map[string]interface{}{
+				"name": "notfound",
+			},
+			map[string]interface{}{
+				"name": "denied",
+			},
+		},
+	},
+}
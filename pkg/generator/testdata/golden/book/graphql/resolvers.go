@@ -0,0 +1,364 @@
+// Generated by pulserpc - do not edit
+
+// Package graphqlapi resolves the Query and Mutation fields of schema.graphql by calling
+// the handlers of the PulseRPC server. It does not depend on a GraphQL library:
+// register the functions Query and Mutation return as the resolvers of the fields
+// of the GraphQL server you use.
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	api "example.com/gen/api"
+)
+
+// FieldResolver resolves a field from its arguments by name, as decoded by the
+// GraphQL server
+type FieldResolver func(ctx context.Context, args map[string]interface{}) (interface{}, error)
+
+// Resolver resolves the fields with the handlers of the interfaces. The fields of an
+// interface without a handler resolve to an error.
+type Resolver struct {
+	UserService api.UserService
+	BookService api.BookService
+	CronJobs    api.CronJobs
+}
+
+// Query returns the resolvers of the Query fields by field name
+func (r *Resolver) Query() map[string]FieldResolver {
+	return map[string]FieldResolver{}
+}
+
+// Mutation returns the resolvers of the Mutation fields by field name
+func (r *Resolver) Mutation() map[string]FieldResolver {
+	return map[string]FieldResolver{
+		"userServiceCreateIfNew":         r.userServiceCreateIfNew,
+		"userServiceGet":                 r.userServiceGet,
+		"userServiceUpdate":              r.userServiceUpdate,
+		"bookServicePut":                 r.bookServicePut,
+		"bookServiceGet":                 r.bookServiceGet,
+		"bookServiceDelete":              r.bookServiceDelete,
+		"bookServiceCancelUserStatus":    r.bookServiceCancelUserStatus,
+		"bookServiceSetUserStatus":       r.bookServiceSetUserStatus,
+		"bookServiceGetAvailable":        r.bookServiceGetAvailable,
+		"bookServiceGetRecentActivity":   r.bookServiceGetRecentActivity,
+		"bookServiceGetRecommendations":  r.bookServiceGetRecommendations,
+		"bookServiceSearch":              r.bookServiceSearch,
+		"bookServiceGetUserBooks":        r.bookServiceGetUserBooks,
+		"bookServiceGetUserTasks":        r.bookServiceGetUserTasks,
+		"bookServiceAckLoan":             r.bookServiceAckLoan,
+		"bookServiceBookNotLendable":     r.bookServiceBookNotLendable,
+		"bookServiceCreateLoan":          r.bookServiceCreateLoan,
+		"cronJobsRefreshRecommendCache":  r.cronJobsRefreshRecommendCache,
+		"cronJobsSendBooksAvailable":     r.cronJobsSendBooksAvailable,
+		"cronJobsSendBooksToLoan":        r.cronJobsSendBooksToLoan,
+		"cronJobsSendAvailableBookTweet": r.cronJobsSendAvailableBookTweet,
+	}
+}
+
+// userServiceCreateIfNew resolves the userServiceCreateIfNew field by calling UserService.createIfNew
+func (r *Resolver) userServiceCreateIfNew(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.UserService == nil {
+		return nil, fmt.Errorf("no handler for interface UserService")
+	}
+	var userId string
+	if err := decodeArg(args, "userId", &userId); err != nil {
+		return nil, err
+	}
+	var name string
+	if err := decodeArg(args, "name", &name); err != nil {
+		return nil, err
+	}
+	return r.UserService.CreateIfNew(ctx, userId, name)
+}
+
+// userServiceGet resolves the userServiceGet field by calling UserService.get
+func (r *Resolver) userServiceGet(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.UserService == nil {
+		return nil, fmt.Errorf("no handler for interface UserService")
+	}
+	var userId string
+	if err := decodeArg(args, "userId", &userId); err != nil {
+		return nil, err
+	}
+	return r.UserService.Get(ctx, userId)
+}
+
+// userServiceUpdate resolves the userServiceUpdate field by calling UserService.update
+func (r *Resolver) userServiceUpdate(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.UserService == nil {
+		return nil, fmt.Errorf("no handler for interface UserService")
+	}
+	var user api.UserUpdate
+	if err := decodeArg(args, "user", &user); err != nil {
+		return nil, err
+	}
+	return r.UserService.Update(ctx, user)
+}
+
+// bookServicePut resolves the bookServicePut field by calling BookService.put
+func (r *Resolver) bookServicePut(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.BookService == nil {
+		return nil, fmt.Errorf("no handler for interface BookService")
+	}
+	var book api.Book
+	if err := decodeArg(args, "book", &book); err != nil {
+		return nil, err
+	}
+	return r.BookService.Put(ctx, book)
+}
+
+// bookServiceGet resolves the bookServiceGet field by calling BookService.get
+func (r *Resolver) bookServiceGet(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.BookService == nil {
+		return nil, fmt.Errorf("no handler for interface BookService")
+	}
+	var productId string
+	if err := decodeArg(args, "productId", &productId); err != nil {
+		return nil, err
+	}
+	var userId string
+	if err := decodeArg(args, "userId", &userId); err != nil {
+		return nil, err
+	}
+	return r.BookService.Get(ctx, productId, userId)
+}
+
+// bookServiceDelete resolves the bookServiceDelete field by calling BookService.delete
+func (r *Resolver) bookServiceDelete(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.BookService == nil {
+		return nil, fmt.Errorf("no handler for interface BookService")
+	}
+	var productIds []string
+	if err := decodeArg(args, "productIds", &productIds); err != nil {
+		return nil, err
+	}
+	return r.BookService.Delete(ctx, productIds)
+}
+
+// bookServiceCancelUserStatus resolves the bookServiceCancelUserStatus field by calling BookService.cancelUserStatus
+func (r *Resolver) bookServiceCancelUserStatus(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.BookService == nil {
+		return nil, fmt.Errorf("no handler for interface BookService")
+	}
+	var productId string
+	if err := decodeArg(args, "productId", &productId); err != nil {
+		return nil, err
+	}
+	var userId string
+	if err := decodeArg(args, "userId", &userId); err != nil {
+		return nil, err
+	}
+	return r.BookService.CancelUserStatus(ctx, productId, userId)
+}
+
+// bookServiceSetUserStatus resolves the bookServiceSetUserStatus field by calling BookService.setUserStatus
+func (r *Resolver) bookServiceSetUserStatus(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.BookService == nil {
+		return nil, fmt.Errorf("no handler for interface BookService")
+	}
+	var productId string
+	if err := decodeArg(args, "productId", &productId); err != nil {
+		return nil, err
+	}
+	var userId string
+	if err := decodeArg(args, "userId", &userId); err != nil {
+		return nil, err
+	}
+	var status api.BookUserStatus
+	if err := decodeArg(args, "status", &status); err != nil {
+		return nil, err
+	}
+	return r.BookService.SetUserStatus(ctx, productId, userId, status)
+}
+
+// bookServiceGetAvailable resolves the bookServiceGetAvailable field by calling BookService.getAvailable
+func (r *Resolver) bookServiceGetAvailable(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.BookService == nil {
+		return nil, fmt.Errorf("no handler for interface BookService")
+	}
+	var platforms []api.Platform
+	if err := decodeArg(args, "platforms", &platforms); err != nil {
+		return nil, err
+	}
+	var userId string
+	if err := decodeArg(args, "userId", &userId); err != nil {
+		return nil, err
+	}
+	var offset int
+	if err := decodeArg(args, "offset", &offset); err != nil {
+		return nil, err
+	}
+	var limit int
+	if err := decodeArg(args, "limit", &limit); err != nil {
+		return nil, err
+	}
+	return r.BookService.GetAvailable(ctx, platforms, userId, offset, limit)
+}
+
+// bookServiceGetRecentActivity resolves the bookServiceGetRecentActivity field by calling BookService.getRecentActivity
+func (r *Resolver) bookServiceGetRecentActivity(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.BookService == nil {
+		return nil, fmt.Errorf("no handler for interface BookService")
+	}
+	var limit int
+	if err := decodeArg(args, "limit", &limit); err != nil {
+		return nil, err
+	}
+	return r.BookService.GetRecentActivity(ctx, limit)
+}
+
+// bookServiceGetRecommendations resolves the bookServiceGetRecommendations field by calling BookService.getRecommendations
+func (r *Resolver) bookServiceGetRecommendations(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.BookService == nil {
+		return nil, fmt.Errorf("no handler for interface BookService")
+	}
+	var userId string
+	if err := decodeArg(args, "userId", &userId); err != nil {
+		return nil, err
+	}
+	return r.BookService.GetRecommendations(ctx, userId)
+}
+
+// bookServiceSearch resolves the bookServiceSearch field by calling BookService.search
+func (r *Resolver) bookServiceSearch(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.BookService == nil {
+		return nil, fmt.Errorf("no handler for interface BookService")
+	}
+	var request api.SearchRequest
+	if err := decodeArg(args, "request", &request); err != nil {
+		return nil, err
+	}
+	return r.BookService.Search(ctx, request)
+}
+
+// bookServiceGetUserBooks resolves the bookServiceGetUserBooks field by calling BookService.getUserBooks
+func (r *Resolver) bookServiceGetUserBooks(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.BookService == nil {
+		return nil, fmt.Errorf("no handler for interface BookService")
+	}
+	var userId string
+	if err := decodeArg(args, "userId", &userId); err != nil {
+		return nil, err
+	}
+	return r.BookService.GetUserBooks(ctx, userId)
+}
+
+// bookServiceGetUserTasks resolves the bookServiceGetUserTasks field by calling BookService.getUserTasks
+func (r *Resolver) bookServiceGetUserTasks(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.BookService == nil {
+		return nil, fmt.Errorf("no handler for interface BookService")
+	}
+	var userId string
+	if err := decodeArg(args, "userId", &userId); err != nil {
+		return nil, err
+	}
+	return r.BookService.GetUserTasks(ctx, userId)
+}
+
+// bookServiceAckLoan resolves the bookServiceAckLoan field by calling BookService.ackLoan
+func (r *Resolver) bookServiceAckLoan(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.BookService == nil {
+		return nil, fmt.Errorf("no handler for interface BookService")
+	}
+	var userId string
+	if err := decodeArg(args, "userId", &userId); err != nil {
+		return nil, err
+	}
+	var loanId string
+	if err := decodeArg(args, "loanId", &loanId); err != nil {
+		return nil, err
+	}
+	var success bool
+	if err := decodeArg(args, "success", &success); err != nil {
+		return nil, err
+	}
+	return r.BookService.AckLoan(ctx, userId, loanId, success)
+}
+
+// bookServiceBookNotLendable resolves the bookServiceBookNotLendable field by calling BookService.bookNotLendable
+func (r *Resolver) bookServiceBookNotLendable(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.BookService == nil {
+		return nil, fmt.Errorf("no handler for interface BookService")
+	}
+	var productId string
+	if err := decodeArg(args, "productId", &productId); err != nil {
+		return nil, err
+	}
+	var userId string
+	if err := decodeArg(args, "userId", &userId); err != nil {
+		return nil, err
+	}
+	return r.BookService.BookNotLendable(ctx, productId, userId)
+}
+
+// bookServiceCreateLoan resolves the bookServiceCreateLoan field by calling BookService.createLoan
+func (r *Resolver) bookServiceCreateLoan(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.BookService == nil {
+		return nil, fmt.Errorf("no handler for interface BookService")
+	}
+	var productId string
+	if err := decodeArg(args, "productId", &productId); err != nil {
+		return nil, err
+	}
+	var fromUserId string
+	if err := decodeArg(args, "fromUserId", &fromUserId); err != nil {
+		return nil, err
+	}
+	var toUserId string
+	if err := decodeArg(args, "toUserId", &toUserId); err != nil {
+		return nil, err
+	}
+	return r.BookService.CreateLoan(ctx, productId, fromUserId, toUserId)
+}
+
+// cronJobsRefreshRecommendCache resolves the cronJobsRefreshRecommendCache field by calling CronJobs.refreshRecommendCache
+func (r *Resolver) cronJobsRefreshRecommendCache(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.CronJobs == nil {
+		return nil, fmt.Errorf("no handler for interface CronJobs")
+	}
+	return r.CronJobs.RefreshRecommendCache(ctx)
+}
+
+// cronJobsSendBooksAvailable resolves the cronJobsSendBooksAvailable field by calling CronJobs.sendBooksAvailable
+func (r *Resolver) cronJobsSendBooksAvailable(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.CronJobs == nil {
+		return nil, fmt.Errorf("no handler for interface CronJobs")
+	}
+	return r.CronJobs.SendBooksAvailable(ctx)
+}
+
+// cronJobsSendBooksToLoan resolves the cronJobsSendBooksToLoan field by calling CronJobs.sendBooksToLoan
+func (r *Resolver) cronJobsSendBooksToLoan(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.CronJobs == nil {
+		return nil, fmt.Errorf("no handler for interface CronJobs")
+	}
+	return r.CronJobs.SendBooksToLoan(ctx)
+}
+
+// cronJobsSendAvailableBookTweet resolves the cronJobsSendAvailableBookTweet field by calling CronJobs.sendAvailableBookTweet
+func (r *Resolver) cronJobsSendAvailableBookTweet(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if r.CronJobs == nil {
+		return nil, fmt.Errorf("no handler for interface CronJobs")
+	}
+	return r.CronJobs.SendAvailableBookTweet(ctx)
+}
+
+// decodeArg decodes the argument called name into v through its JSON form, leaving v
+// unchanged when the argument is absent or null
+func decodeArg(args map[string]interface{}, name string, v interface{}) error {
+	value, ok := args[name]
+	if !ok || value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(data, v)
+	}
+	if err != nil {
+		return fmt.Errorf("argument %s: %w", name, err)
+	}
+	return nil
+}
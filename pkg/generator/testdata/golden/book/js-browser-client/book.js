@@ -0,0 +1,431 @@
+// Generated by pulserpc - do not edit
+
+/**
+ * The book selling platforms we support
+ * @readonly
+ * @enum {string}
+ */
+export const Platform = Object.freeze({
+  kindle: 'kindle',
+  nook: 'nook',
+});
+
+/**
+ * @readonly
+ * @enum {string}
+ */
+export const BookUserStatus = Object.freeze({
+  none: 'none',
+  want: 'want',
+  have: 'have',
+  dislike: 'dislike',
+});
+
+/**
+ * These are the status codes that interface functions may return.
+ * @readonly
+ * @enum {string}
+ */
+export const Status = Object.freeze({
+  /** Request successful */
+  success: 'success',
+  /** Request failed due to some non-recoverable backend error such as the database was down.  This was not due to an invalid request */
+  fatal: 'fatal',
+  /** Request failed because input was invalid */
+  invalid: 'invalid',
+  /** Returned by query-style functions if no data is found for the given parameters */
+  notfound: 'notfound',
+  /** Requesting user does not have permission to perform the requested action */
+  denied: 'denied',
+});
+
+/**
+ * @typedef {Object} Book
+ * @property {string} productId
+ * @property {number} dateCreated
+ * @property {number} dateUpdated
+ * @property {Platform} platform
+ * @property {string} author
+ * @property {string} title
+ * @property {string} productUrl
+ * @property {string} imageUrl
+ * @property {boolean} lendable
+ */
+
+/**
+ * @typedef {Object} BookWithStatus
+ * @property {string} productId
+ * @property {number} dateCreated
+ * @property {number} dateUpdated
+ * @property {Platform} platform
+ * @property {string} author
+ * @property {string} title
+ * @property {string} productUrl
+ * @property {string} imageUrl
+ * @property {boolean} lendable
+ * @property {BookUserStatus} userStatus
+ */
+
+/**
+ * @typedef {Object} BookWithScore
+ * @property {string} productId
+ * @property {number} dateCreated
+ * @property {number} dateUpdated
+ * @property {Platform} platform
+ * @property {string} author
+ * @property {string} title
+ * @property {string} productUrl
+ * @property {string} imageUrl
+ * @property {boolean} lendable
+ * @property {BookUserStatus} userStatus
+ * @property {number} score
+ */
+
+/**
+ * @typedef {Object} User
+ * @property {string} userId
+ * @property {string} name
+ * @property {number} points
+ * @property {number} dateCreated
+ * @property {string} email
+ * @property {string} kindleEmail
+ * @property {string} nookEmail
+ * @property {boolean} emailOptIn
+ */
+
+/**
+ * @typedef {Object} UserUpdate
+ * @property {string} userId
+ * @property {string} name
+ * @property {string} email
+ * @property {string} kindleEmail
+ * @property {string} nookEmail
+ * @property {boolean} emailOptIn
+ */
+
+/**
+ * @typedef {Object} SearchRequest
+ * @property {Array<Platform>} platforms
+ * @property {string} userId
+ * @property {string} keyword
+ * @property {number} offset
+ * @property {number} limit
+ */
+
+/**
+ * @typedef {Object} Recipient
+ * @property {string} userId
+ * @property {string} email
+ */
+
+/**
+ * @typedef {Object} ToLoanTask
+ * @property {Book} book
+ * @property {Array<Recipient>} recipients
+ */
+
+/**
+ * @typedef {Object} ToAckTask
+ * @property {Book} book
+ * @property {string} fromEmail
+ * @property {string} loanId
+ * @property {number} dateLoaned
+ */
+
+/**
+ * @typedef {Object} BaseResponse
+ * @property {Status} status
+ * @property {string} message
+ */
+
+/**
+ * @typedef {Object} UserResponse
+ * @property {Status} status
+ * @property {string} message
+ * @property {User} user
+ */
+
+/**
+ * @typedef {Object} BookResponse
+ * @property {Status} status
+ * @property {string} message
+ * @property {string} userId
+ * @property {BookWithStatus} book
+ */
+
+/**
+ * @typedef {Object} BooksResponse
+ * @property {Status} status
+ * @property {string} message
+ * @property {string} userId
+ * @property {number} totalRows
+ * @property {number} offset
+ * @property {Array<BookWithStatus>} books
+ */
+
+/**
+ * @typedef {Object} DeleteResponse
+ * @property {Status} status
+ * @property {string} message
+ * @property {number} deleteCount
+ */
+
+/**
+ * @typedef {Object} RecommendationsResponse
+ * @property {Status} status
+ * @property {string} message
+ * @property {string} userId
+ * @property {Array<BookWithScore>} books
+ */
+
+/**
+ * @typedef {Object} UserBooksResponse
+ * @property {Status} status
+ * @property {string} message
+ * @property {string} userId
+ * @property {Array<Book>} want
+ * @property {Array<Book>} have
+ * @property {Array<Book>} dislike
+ */
+
+/**
+ * @typedef {Object} TasksResponse
+ * @property {Status} status
+ * @property {string} message
+ * @property {string} userId
+ * @property {Array<ToLoanTask>} toLoan
+ * @property {Array<ToAckTask>} toAck
+ */
+
+/**
+ * @typedef {Object} LoanResponse
+ * @property {Status} status
+ * @property {string} message
+ * @property {string} loanId
+ */
+
+/**
+ * @typedef {Object} ActivityResponse
+ * @property {Status} status
+ * @property {string} message
+ * @property {Array<BookWithStatus>} activity
+ */
+
+export class UserServiceClient {
+  /** @param {import('./pulserpc.js').Transport} transport */
+  constructor(transport) {
+    this.transport = transport;
+  }
+
+  /**
+   * @param {string} userId
+   * @param {string} name
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<BaseResponse>}
+   */
+  createIfNew(userId, name, options) {
+    return this.transport.call('UserService.createIfNew', [userId, name], options);
+  }
+
+  /**
+   * @param {string} userId
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<UserResponse>}
+   */
+  get(userId, options) {
+    return this.transport.call('UserService.get', [userId], options);
+  }
+
+  /**
+   * @param {UserUpdate} user
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<BaseResponse>}
+   */
+  update(user, options) {
+    return this.transport.call('UserService.update', [user], options);
+  }
+}
+
+export class BookServiceClient {
+  /** @param {import('./pulserpc.js').Transport} transport */
+  constructor(transport) {
+    this.transport = transport;
+  }
+
+  /**
+   * @param {Book} book
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<BaseResponse>}
+   */
+  put(book, options) {
+    return this.transport.call('BookService.put', [book], options);
+  }
+
+  /**
+   * @param {string} productId
+   * @param {string} userId
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<BookResponse>}
+   */
+  get(productId, userId, options) {
+    return this.transport.call('BookService.get', [productId, userId], options);
+  }
+
+  /**
+   * @param {Array<string>} productIds
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<DeleteResponse>}
+   */
+  delete(productIds, options) {
+    return this.transport.call('BookService.delete', [productIds], options);
+  }
+
+  /**
+   * @param {string} productId
+   * @param {string} userId
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<BaseResponse>}
+   */
+  cancelUserStatus(productId, userId, options) {
+    return this.transport.call('BookService.cancelUserStatus', [productId, userId], options);
+  }
+
+  /**
+   * @param {string} productId
+   * @param {string} userId
+   * @param {BookUserStatus} status
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<BaseResponse>}
+   */
+  setUserStatus(productId, userId, status, options) {
+    return this.transport.call('BookService.setUserStatus', [productId, userId, status], options);
+  }
+
+  /**
+   * @param {Array<Platform>} platforms
+   * @param {string} userId
+   * @param {number} offset
+   * @param {number} limit
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<BooksResponse>}
+   */
+  getAvailable(platforms, userId, offset, limit, options) {
+    return this.transport.call('BookService.getAvailable', [platforms, userId, offset, limit], options);
+  }
+
+  /**
+   * @param {number} limit
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<ActivityResponse>}
+   */
+  getRecentActivity(limit, options) {
+    return this.transport.call('BookService.getRecentActivity', [limit], options);
+  }
+
+  /**
+   * @param {string} userId
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<RecommendationsResponse>}
+   */
+  getRecommendations(userId, options) {
+    return this.transport.call('BookService.getRecommendations', [userId], options);
+  }
+
+  /**
+   * @param {SearchRequest} request
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<BooksResponse>}
+   */
+  search(request, options) {
+    return this.transport.call('BookService.search', [request], options);
+  }
+
+  /**
+   * @param {string} userId
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<UserBooksResponse>}
+   */
+  getUserBooks(userId, options) {
+    return this.transport.call('BookService.getUserBooks', [userId], options);
+  }
+
+  /**
+   * @param {string} userId
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<TasksResponse>}
+   */
+  getUserTasks(userId, options) {
+    return this.transport.call('BookService.getUserTasks', [userId], options);
+  }
+
+  /**
+   * @param {string} userId
+   * @param {string} loanId
+   * @param {boolean} success
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<BaseResponse>}
+   */
+  ackLoan(userId, loanId, success, options) {
+    return this.transport.call('BookService.ackLoan', [userId, loanId, success], options);
+  }
+
+  /**
+   * @param {string} productId
+   * @param {string} userId
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<BaseResponse>}
+   */
+  bookNotLendable(productId, userId, options) {
+    return this.transport.call('BookService.bookNotLendable', [productId, userId], options);
+  }
+
+  /**
+   * @param {string} productId
+   * @param {string} fromUserId
+   * @param {string} toUserId
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<LoanResponse>}
+   */
+  createLoan(productId, fromUserId, toUserId, options) {
+    return this.transport.call('BookService.createLoan', [productId, fromUserId, toUserId], options);
+  }
+}
+
+export class CronJobsClient {
+  /** @param {import('./pulserpc.js').Transport} transport */
+  constructor(transport) {
+    this.transport = transport;
+  }
+
+  /**
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<BaseResponse>}
+   */
+  refreshRecommendCache(options) {
+    return this.transport.call('CronJobs.refreshRecommendCache', [], options);
+  }
+
+  /**
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<BaseResponse>}
+   */
+  sendBooksAvailable(options) {
+    return this.transport.call('CronJobs.sendBooksAvailable', [], options);
+  }
+
+  /**
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<BaseResponse>}
+   */
+  sendBooksToLoan(options) {
+    return this.transport.call('CronJobs.sendBooksToLoan', [], options);
+  }
+
+  /**
+   * @param {import('./pulserpc.js').CallOptions} [options]
+   * @returns {Promise<BaseResponse>}
+   */
+  sendAvailableBookTweet(options) {
+    return this.transport.call('CronJobs.sendAvailableBookTweet', [], options);
+  }
+}
@@ -0,0 +1,27 @@
+// Generated by pulserpc as a starting point - edit this file.
+// pulserpc only writes it if it does not exist.
+
+using PulseRPC;
+using conform;
+using inc;
+
+public static class HarnessHandlers
+{
+    /// <summary>
+    /// Returns the A implementation exercised by AHandlerTests. Its tests are
+    /// skipped while this returns null.
+    /// </summary>
+    public static IA? NewAHandler()
+    {
+        return null;
+    }
+
+    /// <summary>
+    /// Returns the B implementation exercised by BHandlerTests. Its tests are
+    /// skipped while this returns null.
+    /// </summary>
+    public static IB? NewBHandler()
+    {
+        return null;
+    }
+}
@@ -0,0 +1,20 @@
+// Generated by pulserpc as a starting point - edit this file.
+// pulserpc only writes it if it does not exist.
+
+package conform_test
+
+import (
+	conform "pulserpc_test_go"
+)
+
+// newAHandler returns the A implementation exercised by TestAHandler.
+// Its tests are skipped while it returns nil.
+func newAHandler() conform.A {
+	return nil
+}
+
+// newBHandler returns the B implementation exercised by TestBHandler.
+// Its tests are skipped while it returns nil.
+func newBHandler() conform.B {
+	return nil
+}
@@ -0,0 +1,152 @@
+// Generated by pulserpc - do not edit
+
+package com.example.server.conform;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.util.Map;
+import java.util.List;
+
+// IDL-specific type definitions for namespace: conform
+public final class conformIdl {
+    public static final java.util.Map<String, java.util.Map<String, Object>> ALL_STRUCTS;
+    public static final java.util.Map<String, java.util.Map<String, Object>> ALL_ENUMS;
+
+    static {
+        java.util.Map<String, java.util.Map<String, Object>> structs = new java.util.HashMap<>();
+        java.util.Map<String, java.util.Map<String, Object>> enums = new java.util.HashMap<>();
+
+        {
+            java.util.Map<String, Object> def = new java.util.HashMap<>();
+            def.put("extends", "inc.Response");
+            java.util.List<java.util.Map<String, Object>> fields = new java.util.ArrayList<>();
+            {
+                java.util.Map<String, Object> f = new java.util.HashMap<>();
+                f.put("name", "count");
+                java.util.Map<String, Object> typeDef = new java.util.HashMap<>();
+                typeDef.put("builtIn", "int");
+                f.put("type", typeDef);
+                fields.add(f);
+            }
+            {
+                java.util.Map<String, Object> f = new java.util.HashMap<>();
+                f.put("name", "items");
+                java.util.Map<String, Object> typeDef = new java.util.HashMap<>();
+                java.util.Map<String, Object> inner = new java.util.HashMap<>();
+                typeDef.put("builtIn", "string");
+                typeDef.put("array", inner);
+                f.put("type", typeDef);
+                fields.add(f);
+            }
+            def.put("fields", fields);
+            structs.put("RepeatResponse", def);
+        }
+        {
+            java.util.Map<String, Object> def = new java.util.HashMap<>();
+            java.util.List<java.util.Map<String, Object>> fields = new java.util.ArrayList<>();
+            {
+                java.util.Map<String, Object> f = new java.util.HashMap<>();
+                f.put("name", "hi");
+                java.util.Map<String, Object> typeDef = new java.util.HashMap<>();
+                typeDef.put("builtIn", "string");
+                f.put("type", typeDef);
+                fields.add(f);
+            }
+            def.put("fields", fields);
+            structs.put("HiResponse", def);
+        }
+        {
+            java.util.Map<String, Object> def = new java.util.HashMap<>();
+            java.util.List<java.util.Map<String, Object>> fields = new java.util.ArrayList<>();
+            {
+                java.util.Map<String, Object> f = new java.util.HashMap<>();
+                f.put("name", "to_repeat");
+                java.util.Map<String, Object> typeDef = new java.util.HashMap<>();
+                typeDef.put("builtIn", "string");
+                f.put("type", typeDef);
+                fields.add(f);
+            }
+            {
+                java.util.Map<String, Object> f = new java.util.HashMap<>();
+                f.put("name", "count");
+                java.util.Map<String, Object> typeDef = new java.util.HashMap<>();
+                typeDef.put("builtIn", "int");
+                f.put("type", typeDef);
+                fields.add(f);
+            }
+            {
+                java.util.Map<String, Object> f = new java.util.HashMap<>();
+                f.put("name", "force_uppercase");
+                java.util.Map<String, Object> typeDef = new java.util.HashMap<>();
+                typeDef.put("builtIn", "bool");
+                f.put("type", typeDef);
+                fields.add(f);
+            }
+            def.put("fields", fields);
+            structs.put("RepeatRequest", def);
+        }
+        {
+            java.util.Map<String, Object> def = new java.util.HashMap<>();
+            java.util.List<java.util.Map<String, Object>> fields = new java.util.ArrayList<>();
+            {
+                java.util.Map<String, Object> f = new java.util.HashMap<>();
+                f.put("name", "personId");
+                java.util.Map<String, Object> typeDef = new java.util.HashMap<>();
+                typeDef.put("builtIn", "string");
+                f.put("type", typeDef);
+                fields.add(f);
+            }
+            {
+                java.util.Map<String, Object> f = new java.util.HashMap<>();
+                f.put("name", "firstName");
+                java.util.Map<String, Object> typeDef = new java.util.HashMap<>();
+                typeDef.put("builtIn", "string");
+                f.put("type", typeDef);
+                fields.add(f);
+            }
+            {
+                java.util.Map<String, Object> f = new java.util.HashMap<>();
+                f.put("name", "lastName");
+                java.util.Map<String, Object> typeDef = new java.util.HashMap<>();
+                typeDef.put("builtIn", "string");
+                f.put("type", typeDef);
+                fields.add(f);
+            }
+            {
+                java.util.Map<String, Object> f = new java.util.HashMap<>();
+                f.put("name", "email");
+                java.util.Map<String, Object> typeDef = new java.util.HashMap<>();
+                typeDef.put("builtIn", "string");
+                f.put("type", typeDef);
+                f.put("optional", true);
+                f.put("sensitive", true);
+                fields.add(f);
+            }
+            def.put("fields", fields);
+            structs.put("Person", def);
+        }
+        {
+            java.util.Map<String, Object> def = new java.util.HashMap<>();
+            java.util.List<java.util.Map<String, Object>> fields = new java.util.ArrayList<>();
+            {
+                java.util.Map<String, Object> f = new java.util.HashMap<>();
+                f.put("name", "a");
+                java.util.Map<String, Object> typeDef = new java.util.HashMap<>();
+                typeDef.put("builtIn", "float");
+                f.put("type", typeDef);
+                fields.add(f);
+            }
+            {
+                java.util.Map<String, Object> f = new java.util.HashMap<>();
+                f.put("name", "reason");
+                java.util.Map<String, Object> typeDef = new java.util.HashMap<>();
+                typeDef.put("builtIn", "string");
+                f.put("type", typeDef);
+                fields.add(f);
+            }
+            def.put("fields", fields);
+            structs.put("NegativeInput", def);
+        }
+        ALL_STRUCTS = java.util.Collections.unmodifiableMap(structs);
+        ALL_ENUMS = java.util.Collections.unmodifiableMap(enums);
+    }
+}
@@ -0,0 +1,75 @@
+// Generated by pulserpc - do not edit
+
+package com.example.server;
+
+import com.bitmechanic.pulserpc.*;
+import java.util.Map;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertNotNull;
+import static org.junit.jupiter.api.Assumptions.assumeTrue;
+
+/**
+ * Calls every method of your A handler in-process. Return your handler from
+ * Handlers.newAHandler().
+ */
+class AHandlerTest {
+    private final JsonParser jsonParser = new JacksonJsonParser();
+    private Server server;
+
+    @BeforeEach
+    void setUp() throws Exception {
+        Object handler = Handlers.newAHandler();
+        assumeTrue(handler != null, "Handlers.newAHandler() returns null");
+        server = new Server(0, jsonParser);
+        server.register("A", handler);
+    }
+
+    /**
+     * Sends a JSON-RPC request to the server in-process and fails if the response is an
+     * error, including a result that does not match the IDL.
+     */
+    @SuppressWarnings("unchecked")
+    private void call(String request) {
+        Map<String, Object> response = server.handleRequest(jsonParser.fromJson(request, Map.class));
+        assertNotNull(response, "no response to " + request);
+        assertFalse(response.containsKey("error"), () -> "request " + request + " failed: " + response.get("error"));
+    }
+
+    @Test
+    void testAdd() {
+        call("{\"id\":1,\"jsonrpc\":\"2.0\",\"method\":\"A.add\",\"params\":[1,1]}");
+    }
+
+    @Test
+    void testCalc() {
+        call("{\"id\":2,\"jsonrpc\":\"2.0\",\"method\":\"A.calc\",\"params\":[[1.5],\"add\"]}");
+    }
+
+    @Test
+    void testSqrt() {
+        call("{\"id\":3,\"jsonrpc\":\"2.0\",\"method\":\"A.sqrt\",\"params\":[1.5]}");
+    }
+
+    @Test
+    void testRepeat() {
+        call("{\"id\":4,\"jsonrpc\":\"2.0\",\"method\":\"A.repeat\",\"params\":[{\"count\":1,\"force_uppercase\":true,\"to_repeat\":\"test\"}]}");
+    }
+
+    @Test
+    void testSayHi() {
+        call("{\"id\":5,\"jsonrpc\":\"2.0\",\"method\":\"A.say_hi\",\"params\":[]}");
+    }
+
+    @Test
+    void testRepeatNum() {
+        call("{\"id\":6,\"jsonrpc\":\"2.0\",\"method\":\"A.repeat_num\",\"params\":[1,1]}");
+    }
+
+    @Test
+    void testPutPerson() {
+        call("{\"id\":7,\"jsonrpc\":\"2.0\",\"method\":\"A.putPerson\",\"params\":[{\"firstName\":\"test\",\"lastName\":\"test\",\"personId\":\"test\"}]}");
+    }
+}
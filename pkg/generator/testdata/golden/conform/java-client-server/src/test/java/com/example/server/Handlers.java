@@ -0,0 +1,25 @@
+// Generated by pulserpc as a starting point - edit this file.
+// pulserpc only writes it if it does not exist.
+
+package com.example.server;
+
+final class Handlers {
+    private Handlers() {
+    }
+
+    /**
+     * Returns the A implementation exercised by AHandlerTest. Its tests are
+     * skipped while this returns null.
+     */
+    static Object newAHandler() {
+        return null;
+    }
+
+    /**
+     * Returns the B implementation exercised by BHandlerTest. Its tests are
+     * skipped while this returns null.
+     */
+    static Object newBHandler() {
+        return null;
+    }
+}
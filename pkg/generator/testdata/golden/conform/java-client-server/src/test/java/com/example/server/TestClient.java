@@ -0,0 +1,215 @@
+// Generated by pulserpc - do not edit
+
+package com.example.server;
+
+import com.bitmechanic.pulserpc.*;
+import com.example.server.conform.AClient;
+import com.example.server.conform.BClient;
+import com.example.server.conform.HiResponse;
+import com.example.server.conform.NegativeInput;
+import com.example.server.conform.Person;
+import com.example.server.conform.RepeatRequest;
+import com.example.server.conform.RepeatResponse;
+import com.example.server.inc.Response;
+
+public class TestClient {
+    public static void main(String[] args) throws Exception {
+        JsonParser jsonParser = new JacksonJsonParser();
+        String baseUrl = args.length > 0 ? args[0] : "http://localhost:8080";
+        Transport transport = new HTTPTransport(baseUrl, jsonParser);
+
+        com.example.server.conform.AClient aClient = new com.example.server.conform.AClient(transport, jsonParser);
+        try {
+            var result = aClient.add(42, 42);
+            System.out.println("✓ A.add passed");
+        } catch (Exception e) {
+            System.err.println("✗ A.add failed: " + e.getMessage());
+        }
+        try {
+            var result = aClient.calc(java.util.Arrays.asList(1.1, 2.2, 3.3), com.example.server.inc.MathOp.add);
+            System.out.println("✓ A.calc passed");
+        } catch (Exception e) {
+            System.err.println("✗ A.calc failed: " + e.getMessage());
+        }
+        try {
+            var result = aClient.sqrt(3.14);
+            System.out.println("✓ A.sqrt passed");
+        } catch (Exception e) {
+            System.err.println("✗ A.sqrt failed: " + e.getMessage());
+        }
+        try {
+            var result = aClient.repeat(new RepeatRequest());
+            System.out.println("✓ A.repeat passed");
+        } catch (Exception e) {
+            System.err.println("✗ A.repeat failed: " + e.getMessage());
+        }
+        try {
+            var result = aClient.say_hi();
+            System.out.println("✓ A.say_hi passed");
+        } catch (Exception e) {
+            System.err.println("✗ A.say_hi failed: " + e.getMessage());
+        }
+        try {
+            var result = aClient.repeat_num(42, 42);
+            System.out.println("✓ A.repeat_num passed");
+        } catch (Exception e) {
+            System.err.println("✗ A.repeat_num failed: " + e.getMessage());
+        }
+        try {
+            var result = aClient.putPerson(new Person());
+            System.out.println("✓ A.putPerson passed");
+        } catch (Exception e) {
+            System.err.println("✗ A.putPerson failed: " + e.getMessage());
+        }
+
+        com.example.server.conform.BClient bClient = new com.example.server.conform.BClient(transport, jsonParser);
+        try {
+            var result = bClient.echo("tests");
+            System.out.println("✓ B.echo passed");
+        } catch (Exception e) {
+            System.err.println("✗ B.echo failed: " + e.getMessage());
+        }
+
+        if (!testConcurrentCalls(transport)) {
+            System.exit(1);
+        }
+        int vectorFailures = runTestVectors(baseUrl, jsonParser);
+        if (vectorFailures > 0) {
+            System.err.println("FAILED: " + vectorFailures + " test vector(s) failed");
+            System.exit(1);
+        }
+        System.out.println("Test client completed");
+    }
+
+    // Calls pulserpc-idl from many threads at once through one transport and returns
+    // whether every call succeeded with a response id of its own
+    private static boolean testConcurrentCalls(Transport transport) {
+        final int calls = 32;
+        java.util.concurrent.ExecutorService executor = java.util.concurrent.Executors.newFixedThreadPool(calls);
+        try {
+            java.util.List<java.util.concurrent.Future<Response>> futures = new java.util.ArrayList<>();
+            for (int i = 0; i < calls; i++) {
+                futures.add(executor.submit(() -> transport.call(
+                    new Request("pulserpc-idl", new Object[0], java.util.UUID.randomUUID().toString()))));
+            }
+            java.util.Set<Object> ids = new java.util.HashSet<>();
+            for (java.util.concurrent.Future<Response> future : futures) {
+                Object id = future.get().getId();
+                if (id == null || !ids.add(id)) {
+                    System.err.println("✗ concurrent calls: response id " + id + " is missing or shared");
+                    return false;
+                }
+            }
+        } catch (Exception e) {
+            System.err.println("✗ concurrent calls failed: " + e.getMessage());
+            return false;
+        } finally {
+            executor.shutdown();
+        }
+        System.out.println("✓ " + calls + " concurrent calls passed");
+        return true;
+    }
+
+    // Replays testvectors.json and returns the number of mismatches
+    @SuppressWarnings("unchecked")
+    private static int runTestVectors(String baseUrl, JsonParser jsonParser) throws Exception {
+        String data = new String(java.nio.file.Files.readAllBytes(java.nio.file.Paths.get("testvectors.json")), java.nio.charset.StandardCharsets.UTF_8);
+        java.util.Map<String, Object> file = jsonParser.fromJson(data, java.util.Map.class);
+        java.net.http.HttpClient http = java.net.http.HttpClient.newHttpClient();
+        int failures = 0;
+        for (Object item : (java.util.List<Object>) file.get("vectors")) {
+            java.util.Map<String, Object> v = (java.util.Map<String, Object>) item;
+            String name = (String) v.get("name");
+            String msg;
+            try {
+                java.net.http.HttpRequest request = java.net.http.HttpRequest.newBuilder(java.net.URI.create(baseUrl))
+                    .header("Content-Type", "application/json")
+                    .POST(java.net.http.HttpRequest.BodyPublishers.ofString(jsonParser.toJson(normalizeVectorValue(v.get("request")))))
+                    .build();
+                String body = http.send(request, java.net.http.HttpResponse.BodyHandlers.ofString()).body();
+                msg = compareTestVector((java.util.Map<String, Object>) v.get("response"), jsonParser.fromJson(body, java.util.Map.class));
+            } catch (Exception e) {
+                msg = e.toString();
+            }
+            if (msg.isEmpty()) {
+                System.out.println("✓ vector " + name + " passed");
+            } else {
+                System.err.println("✗ vector " + name + ": " + msg);
+                failures++;
+            }
+        }
+        return failures;
+    }
+
+    // Returns an empty string if actual satisfies expected
+    @SuppressWarnings("unchecked")
+    private static String compareTestVector(java.util.Map<String, Object> expected, java.util.Map<String, Object> actual) {
+        if (!vectorValuesEqual(expected.get("id"), actual.get("id"))) {
+            return "expected id " + expected.get("id") + ", got " + actual.get("id");
+        }
+        if (expected.containsKey("error")) {
+            Object expectedCode = ((java.util.Map<String, Object>) expected.get("error")).get("code");
+            Object actualError = actual.get("error");
+            if (!(actualError instanceof java.util.Map) || !vectorValuesEqual(expectedCode, ((java.util.Map<String, Object>) actualError).get("code"))) {
+                return "expected error code " + expectedCode + ", got " + actual;
+            }
+            return "";
+        }
+        if (actual.containsKey("error")) {
+            return "unexpected error " + actual.get("error");
+        }
+        if (expected.containsKey("result") && !vectorValuesEqual(expected.get("result"), actual.get("result"))) {
+            return "expected result " + expected.get("result") + ", got " + actual.get("result");
+        }
+        return "";
+    }
+
+    // Compares JSON values ignoring key order; numbers compare numerically
+    @SuppressWarnings("unchecked")
+    private static boolean vectorValuesEqual(Object expected, Object actual) {
+        if (expected instanceof Number && actual instanceof Number) {
+            return ((Number) expected).doubleValue() == ((Number) actual).doubleValue();
+        }
+        if (expected instanceof java.util.List && actual instanceof java.util.List) {
+            java.util.List<Object> e = (java.util.List<Object>) expected;
+            java.util.List<Object> a = (java.util.List<Object>) actual;
+            if (e.size() != a.size()) return false;
+            for (int i = 0; i < e.size(); i++) {
+                if (!vectorValuesEqual(e.get(i), a.get(i))) return false;
+            }
+            return true;
+        }
+        if (expected instanceof java.util.Map && actual instanceof java.util.Map) {
+            java.util.Map<String, Object> e = (java.util.Map<String, Object>) expected;
+            java.util.Map<String, Object> a = (java.util.Map<String, Object>) actual;
+            if (!e.keySet().equals(a.keySet())) return false;
+            for (java.util.Map.Entry<String, Object> entry : e.entrySet()) {
+                if (!vectorValuesEqual(entry.getValue(), a.get(entry.getKey()))) return false;
+            }
+            return true;
+        }
+        return java.util.Objects.equals(expected, actual);
+    }
+
+    // Some JSON libraries read every number as a double; restore integral values
+    // so requests are sent exactly as written in testvectors.json
+    @SuppressWarnings("unchecked")
+    private static Object normalizeVectorValue(Object value) {
+        if (value instanceof Double && (Double) value == Math.rint((Double) value) && !((Double) value).isInfinite()) {
+            return ((Double) value).longValue();
+        }
+        if (value instanceof java.util.List) {
+            java.util.List<Object> out = new java.util.ArrayList<>();
+            for (Object item : (java.util.List<Object>) value) out.add(normalizeVectorValue(item));
+            return out;
+        }
+        if (value instanceof java.util.Map) {
+            java.util.Map<String, Object> out = new java.util.LinkedHashMap<>();
+            for (java.util.Map.Entry<String, Object> entry : ((java.util.Map<String, Object>) value).entrySet()) {
+                out.put(entry.getKey(), normalizeVectorValue(entry.getValue()));
+            }
+            return out;
+        }
+        return value;
+    }
+}
@@ -0,0 +1,14 @@
+# Generated by pulserpc as a starting point - edit this file.
+# pulserpc only writes it if it does not exist.
+
+
+def new_a_handler():
+    """Return the A implementation exercised by test_harness.py.
+    Its tests are skipped while this returns None."""
+    return None
+
+
+def new_b_handler():
+    """Return the B implementation exercised by test_harness.py.
+    Its tests are skipped while this returns None."""
+    return None
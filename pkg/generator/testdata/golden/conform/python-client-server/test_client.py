@@ -0,0 +1,225 @@
+# Generated by pulserpc - do not edit
+# Test client for integration testing
+
+import sys
+import json
+import time
+import urllib.request
+from concurrent.futures import ThreadPoolExecutor
+from client import HTTPTransport
+
+from client import AClient
+from client import BClient
+
+def wait_for_server(url: str, timeout: int = 10) -> bool:
+    """Wait for server to be ready"""
+    import urllib.error
+    start_time = time.time()
+    while time.time() - start_time < timeout:
+        try:
+            req = urllib.request.Request(url, method='POST')
+            req.add_header('Content-Type', 'application/json')
+            urllib.request.urlopen(req, data=b'{}', timeout=1)
+            return True
+        except (urllib.error.URLError, urllib.error.HTTPError):
+            time.sleep(0.5)
+    return False
+
+def test_concurrent_calls(transport) -> list:
+    """Call pulserpc-idl from many threads at once through one transport and return a
+    message if a call fails or two responses share an id"""
+    calls = 32
+    try:
+        with ThreadPoolExecutor(max_workers=calls) as executor:
+            responses = list(executor.map(lambda _: transport.call('pulserpc-idl', []), range(calls)))
+    except Exception as e:
+        return [f"concurrent calls failed: {e}"]
+    ids = [response.get('id') for response in responses]
+    if None in ids or len(set(ids)) != calls:
+        return [f"concurrent calls: response ids are missing or shared: {ids}"]
+    print(f"✓ {calls} concurrent calls passed")
+    return []
+
+
+def vector_values_equal(expected, actual) -> bool:
+    """Compare JSON values ignoring key order; numbers compare numerically"""
+    if isinstance(expected, bool) or isinstance(actual, bool):
+        return type(expected) is type(actual) and expected == actual
+    if isinstance(expected, dict) and isinstance(actual, dict):
+        return expected.keys() == actual.keys() and all(vector_values_equal(v, actual[k]) for k, v in expected.items())
+    if isinstance(expected, list) and isinstance(actual, list):
+        return len(expected) == len(actual) and all(vector_values_equal(e, a) for e, a in zip(expected, actual))
+    return expected == actual
+
+def compare_test_vector(expected: dict, actual: dict) -> str:
+    """Return an empty string if actual satisfies expected"""
+    if not vector_values_equal(expected.get('id'), actual.get('id')):
+        return f"expected id {expected.get('id')}, got {actual.get('id')}"
+    if 'error' in expected:
+        code = (actual.get('error') or {}).get('code')
+        if code != expected['error']['code']:
+            return f"expected error code {expected['error']['code']}, got {actual}"
+        return ''
+    if 'error' in actual:
+        return f"unexpected error {actual['error']}"
+    if 'result' in expected and not vector_values_equal(expected['result'], actual.get('result')):
+        return f"expected result {expected['result']}, got {actual.get('result')}"
+    return ''
+
+def run_test_vectors(server_url: str) -> list:
+    """Replay testvectors.json and return a message for each mismatch"""
+    import urllib.error
+    try:
+        with open('testvectors.json') as f:
+            vectors = json.load(f)['vectors']
+    except (OSError, ValueError, KeyError) as e:
+        return [f"testvectors.json: {e}"]
+    failures = []
+    for v in vectors:
+        req = urllib.request.Request(server_url, data=json.dumps(v['request']).encode('utf-8'), method='POST')
+        req.add_header('Content-Type', 'application/json')
+        try:
+            with urllib.request.urlopen(req, timeout=10) as resp:
+                body = resp.read()
+        except urllib.error.HTTPError as e:
+            body = e.read()
+        except urllib.error.URLError as e:
+            failures.append(f"vector {v['name']}: {e}")
+            continue
+        try:
+            actual = json.loads(body)
+        except ValueError as e:
+            failures.append(f"vector {v['name']}: invalid response: {e}")
+            continue
+        msg = compare_test_vector(v['response'], actual)
+        if msg:
+            failures.append(f"vector {v['name']}: {msg}")
+        else:
+            print(f"✓ vector {v['name']} passed")
+    return failures
+
+def main():
+    server_url = "http://localhost:8080"
+
+    # Wait for server to be ready
+    print("Waiting for server to be ready...")
+    if not wait_for_server(server_url, timeout=10):
+        print("ERROR: Server did not become ready in time")
+        sys.exit(1)
+
+    print("Server is ready. Running tests...")
+    print()
+
+    # Create transport and clients
+    transport = HTTPTransport(server_url)
+    a_client = AClient(transport)
+    b_client = BClient(transport)
+
+    errors = []
+
+    # Test A.add
+    try:
+        result = a_client.add(2, 3)
+        assert result == 5, f"Expected 5, got {result}"
+        print("✓ A.add passed")
+    except Exception as e:
+        error_msg = "A.add failed: {}".format(str(e))
+        errors.append(error_msg)
+        print(f"✗ {error_msg}")
+
+    # Test A.calc
+    try:
+        result = a_client.calc([1.0, 2.0, 3.0], "add")
+        assert isinstance(result, float), f"Expected float, got {type(result)}"
+        print("✓ A.calc passed")
+    except Exception as e:
+        error_msg = "A.calc failed: {}".format(str(e))
+        errors.append(error_msg)
+        print(f"✗ {error_msg}")
+
+    # Test A.sqrt
+    try:
+        result = a_client.sqrt(4.0)
+        assert abs(result - 2.0) < 0.001, f"Expected ~2.0, got {result}"
+        print("✓ A.sqrt passed")
+    except Exception as e:
+        error_msg = "A.sqrt failed: {}".format(str(e))
+        errors.append(error_msg)
+        print(f"✗ {error_msg}")
+
+    # Test A.repeat
+    try:
+        result = a_client.repeat({'to_repeat': 'hello', 'count': 3, 'force_uppercase': False})
+        assert isinstance(result, dict), f"Expected dict, got {type(result)}"
+        assert 'items' in result, "Result missing 'items' field"
+        assert len(result['items']) == 3, f"Expected 3 items, got {len(result['items'])}"
+        print("✓ A.repeat passed")
+    except Exception as e:
+        error_msg = "A.repeat failed: {}".format(str(e))
+        errors.append(error_msg)
+        print(f"✗ {error_msg}")
+
+    # Test A.say_hi
+    try:
+        result = a_client.say_hi()
+        assert isinstance(result, dict), f"Expected dict, got {type(result)}"
+        assert result.get('hi') == 'hi', f"Expected hi='hi', got {result}"
+        print("✓ A.say_hi passed")
+    except Exception as e:
+        error_msg = "A.say_hi failed: {}".format(str(e))
+        errors.append(error_msg)
+        print(f"✗ {error_msg}")
+
+    # Test A.repeat_num
+    try:
+        result = a_client.repeat_num(2, 2)
+        assert isinstance(result, list), f"Expected list, got {type(result)}"
+        assert len(result) == 2, f"Expected 2 items, got {len(result)}"
+        print("✓ A.repeat_num passed")
+    except Exception as e:
+        error_msg = "A.repeat_num failed: {}".format(str(e))
+        errors.append(error_msg)
+        print(f"✗ {error_msg}")
+
+    # Test A.putPerson
+    try:
+        result = a_client.putPerson({'personId': 'person123', 'firstName': 'John', 'lastName': 'Doe', 'email': None})
+        assert isinstance(result, str), f"Expected str, got {type(result)}"
+        assert result == "person123", f"Expected 'person123', got {result}"
+        print("✓ A.putPerson passed")
+    except Exception as e:
+        error_msg = "A.putPerson failed: {}".format(str(e))
+        errors.append(error_msg)
+        print(f"✗ {error_msg}")
+
+    # Test B.echo
+    try:
+        result = b_client.echo("test")
+        # Test normal return
+        assert result == "test", f"Expected 'test', got {result}"
+        # Test null return
+        result_null = b_client.echo("return-null")
+        assert result_null is None, f"Expected None, got {result_null}"
+        print("✓ B.echo passed")
+    except Exception as e:
+        error_msg = "B.echo failed: {}".format(str(e))
+        errors.append(error_msg)
+        print(f"✗ {error_msg}")
+
+    errors.extend(test_concurrent_calls(transport))
+
+    errors.extend(run_test_vectors(server_url))
+
+    # Report results
+    print()
+    if errors:
+        print(f"FAILED: {len(errors)} test(s) failed:")
+        for error in errors:
+            print(f"  - {error}")
+        sys.exit(1)
+    else:
+        print("SUCCESS: All tests passed!")
+        sys.exit(0)
+
+if __name__ == "__main__":
+    main()
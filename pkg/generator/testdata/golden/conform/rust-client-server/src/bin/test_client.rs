@@ -0,0 +1,214 @@
+// Generated by pulserpc - do not edit
+// Test client for integration testing
+
+use ::conform::pulserpc::{HttpTransport, Transport};
+use ::conform::*;
+use serde_json::Value;
+use std::process;
+use std::sync::Arc;
+use std::thread;
+use std::time::{Duration, Instant};
+
+fn wait_for_server(url: &str, timeout: Duration) -> bool {
+    let client = reqwest::blocking::Client::new();
+    let start = Instant::now();
+    while start.elapsed() < timeout {
+        let response = client
+            .post(url)
+            .header("Content-Type", "application/json")
+            .body(r#"{"jsonrpc":"2.0","method":"pulserpc-idl","id":1}"#)
+            .send();
+        if matches!(response, Ok(ref r) if r.status().is_success()) {
+            return true;
+        }
+        thread::sleep(Duration::from_millis(500));
+    }
+    false
+}
+
+fn main() {
+    let server_url = std::env::args().nth(1).unwrap_or_else(|| "http://localhost:8080".to_string());
+
+    println!("Waiting for server to be ready...");
+    if !wait_for_server(&server_url, Duration::from_secs(10)) {
+        eprintln!("ERROR: Server did not become ready in time");
+        process::exit(1);
+    }
+
+    println!("Server is ready. Running tests...");
+    println!();
+
+    let transport: Arc<dyn Transport> = Arc::new(HttpTransport::new(server_url.clone()));
+    let a_client = AClient::new(transport.clone());
+    let b_client = BClient::new(transport.clone());
+
+    let mut errors: Vec<String> = Vec::new();
+
+    // Test A.add
+    match a_client.add(2, 3) {
+        Ok(result) if result != 5 => {
+            errors.push(format!("A.add: unexpected result {:?}", result));
+        }
+        Ok(_) => println!("✓ A.add passed"),
+        Err(err) => errors.push(format!("A.add failed: {}", err)),
+    }
+
+    // Test A.calc
+    match a_client.calc(vec![1.0, 2.0, 3.0], MathOp::Add) {
+        Ok(_) => println!("✓ A.calc passed"),
+        Err(err) => errors.push(format!("A.calc failed: {}", err)),
+    }
+
+    // Test A.sqrt
+    match a_client.sqrt(4.0) {
+        Ok(result) if !(1.99..=2.01).contains(&result) => {
+            errors.push(format!("A.sqrt: unexpected result {:?}", result));
+        }
+        Ok(_) => println!("✓ A.sqrt passed"),
+        Err(err) => errors.push(format!("A.sqrt failed: {}", err)),
+    }
+
+    // Test A.repeat
+    match a_client.repeat(RepeatRequest { to_repeat: "hello".to_string(), count: 3, force_uppercase: false }) {
+        Ok(_) => println!("✓ A.repeat passed"),
+        Err(err) => errors.push(format!("A.repeat failed: {}", err)),
+    }
+
+    // Test A.say_hi
+    match a_client.say_hi() {
+        Ok(_) => println!("✓ A.say_hi passed"),
+        Err(err) => errors.push(format!("A.say_hi failed: {}", err)),
+    }
+
+    // Test A.repeat_num
+    match a_client.repeat_num(2, 2) {
+        Ok(_) => println!("✓ A.repeat_num passed"),
+        Err(err) => errors.push(format!("A.repeat_num failed: {}", err)),
+    }
+
+    // Test A.putPerson
+    match a_client.put_person(Person { person_id: "person123".to_string(), first_name: "John".to_string(), last_name: "Doe".to_string(), email: None }) {
+        Ok(_) => println!("✓ A.putPerson passed"),
+        Err(err) => errors.push(format!("A.putPerson failed: {}", err)),
+    }
+
+    // Test B.echo
+    match b_client.echo("test".to_string()) {
+        Ok(result) if result.as_deref() != Some("test") => {
+            errors.push(format!("B.echo: unexpected result {:?}", result));
+        }
+        Ok(_) => println!("✓ B.echo passed"),
+        Err(err) => errors.push(format!("B.echo failed: {}", err)),
+    }
+    // Test null return
+    match b_client.echo("return-null".to_string()) {
+        Ok(None) => {}
+        Ok(result) => errors.push(format!("B.echo (null): expected None, got {:?}", result)),
+        Err(err) => errors.push(format!("B.echo (null) failed: {}", err)),
+    }
+
+    errors.extend(test_concurrent_calls(transport.clone()));
+    errors.extend(run_test_vectors(&server_url));
+
+    println!();
+    if !errors.is_empty() {
+        eprintln!("FAILED: {} test(s) failed:", errors.len());
+        for err in &errors {
+            eprintln!("  - {}", err);
+        }
+        process::exit(1);
+    }
+    println!("SUCCESS: All tests passed!");
+}
+
+/// Calls pulserpc-idl from many threads at once through one transport and returns
+/// a message if a call fails
+fn test_concurrent_calls(transport: Arc<dyn Transport>) -> Vec<String> {
+    let calls = 32;
+    let handles: Vec<_> = (0..calls)
+        .map(|_| {
+            let transport = transport.clone();
+            thread::spawn(move || transport.call("pulserpc-idl", Vec::new()))
+        })
+        .collect();
+    for handle in handles {
+        match handle.join() {
+            Ok(Ok(_)) => {}
+            Ok(Err(err)) => return vec![format!("concurrent calls failed: {}", err)],
+            Err(_) => return vec!["concurrent calls failed: a thread panicked".to_string()],
+        }
+    }
+    println!("✓ {} concurrent calls passed", calls);
+    Vec::new()
+}
+
+/// Replays testvectors.json and returns a message for each mismatch
+fn run_test_vectors(server_url: &str) -> Vec<String> {
+    let data = match std::fs::read_to_string("testvectors.json") {
+        Ok(data) => data,
+        Err(err) => return vec![format!("testvectors.json: {}", err)],
+    };
+    let file: Value = match serde_json::from_str(&data) {
+        Ok(file) => file,
+        Err(err) => return vec![format!("testvectors.json: {}", err)],
+    };
+
+    let client = reqwest::blocking::Client::new();
+    let mut failures = Vec::new();
+    for vector in file["vectors"].as_array().into_iter().flatten() {
+        let name = vector["name"].as_str().unwrap_or_default();
+        let response = client
+            .post(server_url)
+            .header("Content-Type", "application/json")
+            .body(vector["request"].to_string())
+            .send()
+            .and_then(|response| response.json::<Value>());
+        let actual = match response {
+            Ok(actual) => actual,
+            Err(err) => {
+                failures.push(format!("vector {}: {}", name, err));
+                continue;
+            }
+        };
+        if let Some(message) = compare_test_vector(&vector["response"], &actual) {
+            failures.push(format!("vector {}: {}", name, message));
+            continue;
+        }
+        println!("✓ vector {} passed", name);
+    }
+    failures
+}
+
+/// Returns a message if actual does not satisfy expected
+fn compare_test_vector(expected: &Value, actual: &Value) -> Option<String> {
+    if !json_equal(&expected["id"], &actual["id"]) {
+        return Some(format!("expected id {}, got {}", expected["id"], actual["id"]));
+    }
+    if let Some(expected_err) = expected.get("error") {
+        if !json_equal(&expected_err["code"], &actual["error"]["code"]) {
+            return Some(format!("expected error code {}, got {}", expected_err["code"], actual));
+        }
+        return None;
+    }
+    if let Some(actual_err) = actual.get("error") {
+        return Some(format!("unexpected error {}", actual_err));
+    }
+    if let Some(expected_result) = expected.get("result") {
+        if !json_equal(expected_result, &actual["result"]) {
+            return Some(format!("expected result {}, got {}", expected_result, actual["result"]));
+        }
+    }
+    None
+}
+
+/// Compares JSON values with numbers compared by value, so 3 equals 3.0
+fn json_equal(a: &Value, b: &Value) -> bool {
+    match (a, b) {
+        (Value::Number(x), Value::Number(y)) => x.as_f64() == y.as_f64(),
+        (Value::Array(x), Value::Array(y)) => x.len() == y.len() && x.iter().zip(y).all(|(x, y)| json_equal(x, y)),
+        (Value::Object(x), Value::Object(y)) => {
+            x.len() == y.len() && x.iter().all(|(k, v)| y.get(k).is_some_and(|w| json_equal(v, w)))
+        }
+        _ => a == b,
+    }
+}
@@ -0,0 +1,1052 @@
+// Generated by pulserpc - do not edit
+
+/// <reference types="node" />
+
+import * as crypto from 'crypto';
+import { canonicalJson } from './pulserpc/canonical';
+import { DEADLINE_HEADER, remainingTimeMs } from './pulserpc/deadline';
+import { RPCError } from './pulserpc/rpc';
+import { METHOD_DEFS } from './methods';
+import { ALL_STRUCTS as CONFORM_STRUCTS, ALL_ENUMS as CONFORM_ENUMS } from './conform';
+import { ALL_STRUCTS as INC_STRUCTS, ALL_ENUMS as INC_ENUMS } from './inc';
+
+import { redactValue } from './pulserpc/types';
+import { validateType } from './pulserpc/validation';
+
+// Inline type definitions
+interface TypeDef {
+  builtIn?: string;
+  array?: TypeDef;
+  mapValue?: TypeDef;
+  userDefined?: string;
+}
+interface StructDef {
+  extends?: string;
+  fields: Array<{ name: string; type: TypeDef; optional?: boolean }>;
+}
+interface EnumDef {
+  values: Array<{ name: string }>;
+}
+type StructMap = { [key: string]: StructDef };
+type EnumMap = { [key: string]: EnumDef };
+
+// Merge ALL_STRUCTS and ALL_ENUMS from all namespaces
+const ALL_STRUCTS: StructMap = {
+  ...CONFORM_STRUCTS,
+  ...INC_STRUCTS,
+};
+
+const ALL_ENUMS: EnumMap = {
+  ...CONFORM_ENUMS,
+  ...INC_ENUMS,
+};
+
+/**
+ * Per-call settings, passed as the last argument of a client method. headers are
+ * added to the request, overriding the transport's headers. idempotencyKey is sent
+ * as the Idempotency-Key header so the server can recognize a repeated request.
+ * namedParams sends params as an object keyed by the parameter names, which client
+ * methods set in paramNames. responseMeta receives the metadata the server attached
+ * to the response.
+ */
+export interface CallOptions {
+  timeoutMs?: number;
+  headers?: Record<string, string>;
+  idempotencyKey?: string;
+  namedParams?: boolean;
+  paramNames?: string[];
+  responseMeta?: Record<string, any>;
+  // The batch the call was added to, set by Batch.add
+  batch?: Batch;
+}
+
+/**
+ * The result of a call together with the metadata the server attached to the
+ * response, such as timings, pagination hints or warnings.
+ */
+export interface CallResult<T> {
+  result: T;
+  meta: Record<string, any>;
+}
+
+/**
+ * Makes a client call with options that capture the response metadata:
+ *
+ *   const res = await callWithMeta((options) => client.getProduct('p-1', options));
+ */
+export async function callWithMeta<T>(call: (options: CallOptions) => Promise<T>): Promise<CallResult<T>> {
+  const meta: Record<string, any> = {};
+  const result = await call({ responseMeta: meta });
+  return { result, meta };
+}
+
+/**
+ * Returns params as sent in a request: by name when options ask for it and the
+ * parameter names are known, otherwise by position.
+ */
+function requestParams(params: any[], options: CallOptions): any {
+  const names = options.paramNames;
+  if (!options.namedParams || !names || names.length !== params.length) {
+    return params;
+  }
+  return Object.fromEntries(names.map((name, i) => [name, params[i]]));
+}
+
+/**
+ * Calls transport with options, or queues the request in the batch the call was added to.
+ */
+function callTransport(transport: Transport, method: string, params: any[], options: CallOptions): Promise<any> {
+  if (options.batch) {
+    return options.batch.queue(method, params, options);
+  }
+  return transport.callWithOptions(method, params, options);
+}
+
+/**
+ * Returns the timeout of a call: its own, else the time left before the deadline of the
+ * call being handled, if any. A call with a timeout sends it in the X-PulseRPC-Deadline
+ * header so the server can pass the budget on.
+ */
+function callTimeoutMs(options: CallOptions, headers: Record<string, string>): number | undefined {
+  const timeoutMs = options.timeoutMs || remainingTimeMs();
+  if (timeoutMs !== undefined) {
+    headers[DEADLINE_HEADER] = String(Math.floor(timeoutMs));
+  }
+  return timeoutMs;
+}
+
+/**
+ * Returns headers that authenticate a request, computed from the serialized JSON-RPC
+ * request exactly as it is sent.
+ */
+export type RequestSigner = (body: string) => Record<string, string> | Promise<Record<string, string>>;
+
+export abstract class Transport {
+  /**
+   * Perform a JSON-RPC 2.0 call and return the response.
+   * @param method The method name in format 'interface.method'
+   * @param params List of parameters to pass to the method
+   * @returns Promise that resolves to the JSON-RPC 2.0 response
+   * @throws RPCError If the JSON-RPC call returns an error
+   */
+  abstract call(method: string, params: any[]): Promise<any>;
+
+  /**
+   * Perform a JSON-RPC 2.0 call with per-call options. Transports that honor the
+   * options override this; the default ignores them.
+   */
+  callWithOptions(method: string, params: any[], options: CallOptions): Promise<any> {
+    return this.call(method, params);
+  }
+}
+
+/** The optional protocol features a server reports from the built-in pulserpc-capabilities method */
+export interface Capabilities {
+  protocolVersion: string;
+  batch: boolean;
+  notifications: boolean;
+  compression: boolean;
+  msgpack: boolean;
+  streaming: boolean;
+}
+
+// The capabilities of a server without pulserpc-capabilities: the batches and
+// notifications of JSON-RPC 2.0
+const BASELINE_CAPABILITIES: Capabilities = {
+  protocolVersion: '2.0',
+  batch: true,
+  notifications: true,
+  compression: false,
+  msgpack: false,
+  streaming: false,
+};
+
+// Whether the calls of a Batch go to transport in one batch request: unless the server
+// reports it takes no batches. When the server cannot be asked the batch request is
+// sent anyway, so its error is the batch's.
+async function sendsBatches(transport: any): Promise<boolean> {
+  if (typeof transport.capabilities !== 'function') {
+    return true;
+  }
+  try {
+    return (await transport.capabilities()).batch;
+  } catch {
+    return true;
+  }
+}
+
+/** A call sent as a member of a batch request */
+export interface BatchRequest {
+  method: string;
+  params: any[];
+  options: CallOptions;
+}
+
+/**
+ * The outcome of one call of a batch: its typed result, or the error the call threw,
+ * such as an RPCError from its own response.
+ */
+export interface BatchResult<T> {
+  result?: T;
+  error?: Error;
+}
+
+/**
+ * Collects client calls and sends them in one JSON-RPC batch request. add() makes a
+ * call with options that queue its request, as callWithMeta does with its options,
+ * and returns a promise of the call's result that settles once send() has run:
+ *
+ *   const batch = new Batch(transport);
+ *   const sum = batch.add((options) => calc.add(1, 2, options));
+ *   await batch.send();
+ *   const { result, error } = await sum;
+ *
+ * A call that fails does not fail the others.
+ */
+export class Batch {
+  private queued: { request: BatchRequest; resolve: (response: any) => void; reject: (err: any) => void }[] = [];
+
+  constructor(private transport: Transport) {}
+
+  /** The number of calls waiting to be sent */
+  get length(): number {
+    return this.queued.length;
+  }
+
+  /**
+   * Adds a client call to the batch. A call that fails before sending anything, such as
+   * on invalid params, settles right away and is not sent.
+   */
+  add<T>(call: (options: CallOptions) => Promise<T>): Promise<BatchResult<T>> {
+    return call({ batch: this }).then((result) => ({ result }), (error) => ({ error }));
+  }
+
+  /**
+   * Queues the request of a call added with add(). The promise resolves to the response
+   * matched to the request once the batch is sent.
+   */
+  queue(method: string, params: any[], options: CallOptions): Promise<any> {
+    const { batch, ...requestOptions } = options;
+    return new Promise((resolve, reject) => {
+      this.queued.push({ request: { method, params, options: requestOptions }, resolve, reject });
+    });
+  }
+
+  /**
+   * Sends the queued calls in one request and settles the result of each from its own
+   * response. options apply to the request as a whole, such as its timeout and headers.
+   * If the request as a whole fails, send rejects with its error, which also becomes the
+   * error of every call. A transport without callBatch, or whose server reports it takes
+   * no batches, makes the calls one at a time. The batch is empty afterwards.
+   */
+  async send(options: CallOptions = {}): Promise<void> {
+    const queued = this.queued;
+    this.queued = [];
+    const transport: any = this.transport;
+    if (typeof transport.callBatch !== 'function' || (queued.length > 0 && !(await sendsBatches(transport)))) {
+      for (const q of queued) {
+        await this.transport.callWithOptions(q.request.method, q.request.params, q.request.options).then(q.resolve, q.reject);
+      }
+      return;
+    }
+    if (queued.length === 0) {
+      return;
+    }
+    let responses: any[];
+    try {
+      responses = await transport.callBatch(queued.map((q) => q.request), options);
+    } catch (err) {
+      queued.forEach((q) => q.reject(err));
+      throw err;
+    }
+    queued.forEach((q, i) => {
+      if (responses[i] === undefined) {
+        q.reject(new RPCError(-32603, `No response to ${q.request.method} in batch`, undefined));
+      } else {
+        q.resolve(responses[i]);
+      }
+    });
+  }
+}
+
+/**
+ * Thrown when no JSON-RPC response was received: the connection failed, or the
+ * server answered with an HTTP error status and a body that is not a JSON-RPC
+ * response. status is 0 if no response was received. retryable is true for
+ * failures that draining or restarting servers produce: connection refused,
+ * connection reset or closed before a response, HTTP 502/503.
+ */
+export class TransportError extends RPCError {
+  public readonly retryable: boolean;
+
+  constructor(message: string, public readonly status: number = 0, retryable: boolean = false) {
+    super(-32603, message, undefined);
+    this.retryable = retryable || status === 502 || status === 503;
+  }
+}
+
+// GET paths of the [cache] methods, which HTTPTransport can call with conditional requests
+const CACHED_METHODS: { [method: string]: string } = {
+  'A.calc': '/A/calc',
+};
+
+// The number of responses an HTTPTransport keeps for conditional requests
+const MAX_CACHED_RESPONSES = 256;
+
+/**
+ * Logs a call with its method, duration, and request and response JSON, with the
+ * params and result redacted according to the method's parameter and return types.
+ */
+function logCall(log: (line: string) => void, method: string, params: any[], options: CallOptions,
+                 response: any, error: any, elapsedMs: number): void {
+  const dot = method.lastIndexOf('.');
+  const methodDef = dot >= 0 ? METHOD_DEFS[method.slice(0, dot)]?.[method.slice(dot + 1)] : undefined;
+  const paramDefs: any[] = methodDef?.parameters ?? [];
+  const redacted = params.map((param, i) => (i < paramDefs.length ? redactValue(param, paramDefs[i].type, ALL_STRUCTS) : param));
+  const line = `pulserpc call method=${method} duration=${elapsedMs.toFixed(3)}ms request=${JSON.stringify(requestParams(redacted, options))}`;
+  if (error !== undefined) {
+    log(`${line} error=${error instanceof Error ? error.message : String(error)}`);
+    return;
+  }
+  const logged = { ...response, result: redactValue(response?.result, methodDef?.returnType ?? {}, ALL_STRUCTS) };
+  log(`${line} response=${JSON.stringify(logged)}`);
+}
+
+export class HTTPTransport extends Transport {
+  private baseUrl: string;
+  private headers: Record<string, string>;
+  private signer: RequestSigner | null = null;
+  private cache: Map<string, { etag: string; body: string }> | null = null;
+  private canonical = false;
+  private debugLog: ((line: string) => void) | null = null;
+  private capabilitiesPromise: Promise<Capabilities> | undefined;
+
+  constructor(baseUrl: string, headers?: Record<string, string>) {
+    super();
+    this.baseUrl = baseUrl.replace(/\/$/, '');
+    this.headers = headers ? { ...headers } : {};
+  }
+
+  /** Installs a hook that signs every request, such as hmacSigner from signing.ts. */
+  setSigner(signer: RequestSigner | null): void {
+    this.signer = signer;
+  }
+
+  /**
+   * Controls whether requests are encoded as canonical JSON (RFC 8785, see
+   * canonicalJson), so equal messages are equal bytes in every language. Off by default.
+   */
+  setCanonicalJson(enabled: boolean): void {
+    this.canonical = enabled;
+  }
+
+  // Encodes value as JSON, or as canonical JSON when setCanonicalJson is on
+  private stringify(value: any): string {
+    return this.canonical ? canonicalJson(value) : JSON.stringify(value);
+  }
+
+  /**
+   * Resolves the server's host and opens a connection to it, including the TLS
+   * handshake, which fetch keeps for the next call. Call it at process start to take
+   * the connection setup out of the first call's latency. With ping, it also calls the
+   * built-in pulserpc-idl method, which wakes a server that scaled to zero; any
+   * JSON-RPC response counts. timeoutMs bounds the warm-up.
+   */
+  async warmup(options: { ping?: boolean; timeoutMs?: number } = {}): Promise<void> {
+    if (options.ping) {
+      try {
+        await this.callWithOptions('pulserpc-idl', [], { timeoutMs: options.timeoutMs });
+      } catch (err) {
+        if (!(err instanceof RPCError) || err instanceof TransportError) {
+          throw err;
+        }
+      }
+      return;
+    }
+    try {
+      // Any HTTP status will do: the connection is open either way
+      const response = await fetch(this.baseUrl, {
+        method: 'OPTIONS',
+        signal: options.timeoutMs ? AbortSignal.timeout(options.timeoutMs) : undefined,
+      });
+      await response.arrayBuffer();
+    } catch (err: any) {
+      throw new TransportError(`Network error: ${err.message || String(err)}`);
+    }
+  }
+
+  async call(method: string, params: any[]): Promise<any> {
+    return this.callWithOptions(method, params, {});
+  }
+
+  /**
+   * Logs every call with its method, duration, and request and response JSON, in which
+   * [sensitive] fields are masked as "***". log defaults to console.debug; null turns
+   * logging off, which is the default.
+   */
+  setDebugLog(log: ((line: string) => void) | null = console.debug): void {
+    this.debugLog = log;
+  }
+
+  async callWithOptions(method: string, params: any[], options: CallOptions): Promise<any> {
+    const log = this.debugLog;
+    if (log === null) {
+      return this.sendCall(method, params, options);
+    }
+    const start = performance.now();
+    let response: any;
+    try {
+      response = await this.sendCall(method, params, options);
+    } catch (err) {
+      logCall(log, method, params, options, undefined, err, performance.now() - start);
+      throw err;
+    }
+    logCall(log, method, params, options, response, undefined, performance.now() - start);
+    return response;
+  }
+
+  private async sendCall(method: string, params: any[], options: CallOptions): Promise<any> {
+    const cachedPath = CACHED_METHODS[method];
+    if (cachedPath !== undefined && this.cache !== null && options.paramNames?.length === params.length) {
+      return this.conditionalGet(cachedPath, params, options);
+    }
+
+    // Generate request ID
+    const requestId = crypto.randomUUID();
+
+    // Build JSON-RPC 2.0 request
+    const requestData = {
+      jsonrpc: '2.0',
+      method: method,
+      params: requestParams(params, options),
+      id: requestId,
+    };
+
+    const [response, responseBody] = await this.post(this.stringify(requestData), options);
+    return this.decodeResponse(response.status, response.statusText, responseBody);
+  }
+
+  /**
+   * Sends requests as one JSON-RPC batch request and returns the response to each,
+   * matched by request id, undefined where the server sent none. options apply to the
+   * batch request as a whole.
+   */
+  async callBatch(requests: BatchRequest[], options: CallOptions): Promise<any[]> {
+    const ids = requests.map(() => crypto.randomUUID());
+    const batch = requests.map((r, i) => ({
+      jsonrpc: '2.0',
+      method: r.method,
+      params: requestParams(r.params, r.options),
+      id: ids[i],
+    }));
+    const [response, responseBody] = await this.post(this.stringify(batch), options);
+    let members: any;
+    try {
+      members = JSON.parse(responseBody);
+    } catch {
+      members = undefined;
+    }
+    if (!Array.isArray(members)) {
+      // A batch rejected as a whole gets a single error response
+      this.decodeResponse(response.status, response.statusText, responseBody);
+      throw new TransportError('Unexpected response to batch request');
+    }
+    const byId = new Map(members.map((member: any) => [member?.id, member]));
+    return ids.map((id) => byId.get(id));
+  }
+
+  /**
+   * Asks the server for its optional protocol features the first time, and returns the
+   * same answer afterwards. A server that does not know pulserpc-capabilities is taken to
+   * have baseline JSON-RPC 2.0 ones. Other errors, such as for a server that cannot be
+   * reached, reject and are not remembered.
+   */
+  capabilities(): Promise<Capabilities> {
+    if (this.capabilitiesPromise === undefined) {
+      this.capabilitiesPromise = this.call('pulserpc-capabilities', []).then(
+        (response) => ({ ...BASELINE_CAPABILITIES, ...(response?.result ?? {}) }),
+        (err) => {
+          if (err instanceof RPCError && !(err instanceof TransportError)) {
+            return BASELINE_CAPABILITIES;
+          }
+          this.capabilitiesPromise = undefined;
+          throw err;
+        },
+      );
+    }
+    return this.capabilitiesPromise;
+  }
+
+  // POSTs body to the server with the transport's headers and the headers options ask for,
+  // signed if the transport has a signer
+  private async post(body: string, options: CallOptions): Promise<[Response, string]> {
+    // Prepare fetch options
+    const headers: Record<string, string> = {
+      'Content-Type': 'application/json; charset=utf-8',
+      ...this.headers,
+      ...options.headers,
+    };
+    if (options.idempotencyKey) {
+      headers['Idempotency-Key'] = options.idempotencyKey;
+    }
+    const timeoutMs = callTimeoutMs(options, headers);
+    if (this.signer !== null) {
+      Object.assign(headers, await this.signer(body));
+    }
+
+    return this.send(this.baseUrl, {
+      method: 'POST',
+      headers: headers,
+      body: body,
+      signal: timeoutMs !== undefined ? AbortSignal.timeout(timeoutMs) : undefined,
+    });
+  }
+
+  /**
+   * Controls conditional requests for [cache] methods. When on, their calls are sent as
+   * HTTP GET with the params in the query string, and the transport keeps the last
+   * response and ETag of each URL, sends the ETag in If-None-Match and reuses the kept
+   * response when the server answers 304 Not Modified.
+   */
+  setConditionalRequests(enabled: boolean): void {
+    this.cache = enabled ? new Map() : null;
+  }
+
+  // Calls a [cache] method over HTTP GET, revalidating the response kept for the same URL
+  private async conditionalGet(path: string, params: any[], options: CallOptions): Promise<any> {
+    const query = new URLSearchParams();
+    (options.paramNames || []).forEach((name, i) => {
+      for (const value of Array.isArray(params[i]) ? params[i] : [params[i]]) {
+        if (value !== null && value !== undefined) {
+          query.append(name, String(value));
+        }
+      }
+    });
+    const search = query.toString();
+    const target = this.baseUrl + path + (search ? '?' + search : '');
+
+    const headers: Record<string, string> = { ...this.headers, ...options.headers };
+    if (this.signer !== null) {
+      // Servers verify GET requests with an empty body
+      Object.assign(headers, await this.signer(''));
+    }
+    const cache = this.cache as Map<string, { etag: string; body: string }>;
+    const cached = cache.get(target);
+    if (cached !== undefined) {
+      headers['If-None-Match'] = cached.etag;
+    }
+    const timeoutMs = callTimeoutMs(options, headers);
+
+    const [response, responseBody] = await this.send(target, {
+      method: 'GET',
+      headers: headers,
+      signal: timeoutMs !== undefined ? AbortSignal.timeout(timeoutMs) : undefined,
+    });
+    if (response.status === 304 && cached !== undefined) {
+      return this.decodeResponse(200, '', cached.body);
+    }
+    const etag = response.headers.get('ETag');
+    if (response.status === 200 && etag) {
+      if (!cache.has(target) && cache.size >= MAX_CACHED_RESPONSES) {
+        cache.delete(cache.keys().next().value as string);
+      }
+      cache.set(target, { etag, body: responseBody });
+    }
+    return this.decodeResponse(response.status, response.statusText, responseBody);
+  }
+
+  // Sends a request and reads the response body, reporting network failures as transport errors
+  private async send(url: string, init: RequestInit): Promise<[Response, string]> {
+    try {
+      // Send request using native fetch (Node.js 18+)
+      const response = await fetch(url, init);
+      return [response, await response.text()];
+    } catch (err: any) {
+      // fetch reports the socket error code as the cause
+      const cause = err?.cause?.code;
+      const retryable = cause === 'ECONNREFUSED' || cause === 'ECONNRESET' || cause === 'UND_ERR_SOCKET';
+      throw new TransportError(`Network error: ${err.message || String(err)}`, 0, retryable);
+    }
+  }
+
+  // Decodes a JSON-RPC response, throwing its error as an RPCError
+  private decodeResponse(status: number, statusText: string, responseBody: string): any {
+    let responseData: any;
+    try {
+      responseData = JSON.parse(responseBody);
+    } catch (err) {
+      if (status < 200 || status > 299) {
+        throw new TransportError(`HTTP error: ${status} ${statusText}`, status);
+      }
+      throw new RPCError(-32700, 'Parse error', `Invalid JSON response: ${err}`);
+    }
+
+    // Check for JSON-RPC error
+    if (responseData.error) {
+      const error = responseData.error;
+      const code = error.code || -32603;
+      const message = error.message || 'Internal error';
+      const data = error.data;
+      throw new RPCError(code, message, data);
+    }
+
+    // Return response
+    return responseData;
+  }
+}
+
+// Thrown by methods annotated [errordata="NegativeInput"] when the error data is a valid
+// NegativeInput, which data holds
+export class NegativeInputError extends RPCError {
+  static readonly structName = 'NegativeInput';
+
+  constructor(cause: RPCError) {
+    super(cause.code, '', cause.data);
+    this.message = cause.message;
+  }
+}
+
+type ErrorDataClass = { new (cause: RPCError): RPCError; readonly structName: string };
+
+// Returns err as an errorClass when it is an RPCError whose data validates against the
+// struct named by errorClass.structName, otherwise err
+function bindErrorData(err: unknown, errorClass: ErrorDataClass): unknown {
+  if (!(err instanceof RPCError) || err.data === undefined || err.data === null) {
+    return err;
+  }
+  try {
+    validateType(err.data, { userDefined: errorClass.structName }, ALL_STRUCTS, ALL_ENUMS, false);
+  } catch {
+    return err;
+  }
+  return new errorClass(err);
+}
+
+export class AClient {
+  private transport: Transport;
+  private methodDefs: any;
+
+  constructor(transport: Transport) {
+    this.transport = transport;
+    // Method definitions for validation
+    this.methodDefs = METHOD_DEFS['A'];
+  }
+
+  async add(a: any, b: any, options: CallOptions = {}): Promise<any> {
+    const methodDef = this.methodDefs['add'];
+    const params: any[] = [
+      a,
+      b,
+    ];
+
+    // Validate parameters
+    const expectedParams = methodDef.parameters || [];
+    for (let i = 0; i < params.length; i++) {
+      try {
+        validateType(params[i], expectedParams[i].type, ALL_STRUCTS, ALL_ENUMS, false);
+      } catch (err: any) {
+        throw new Error(`Parameter ${i} (${expectedParams[i].name}) validation failed: ${err.message}`);
+      }
+    }
+
+    // Call transport
+    const methodName = 'A.add';
+    const response = await callTransport(this.transport, methodName, params, { ...options, paramNames: ['a', 'b'] });
+    if (options.responseMeta && response.meta) {
+      Object.assign(options.responseMeta, response.meta);
+    }
+
+    // Extract result from JSON-RPC response
+    if (response.error) {
+      const error = response.error;
+      const code = error.code || -32603;
+      const message = error.message || 'Internal error';
+      const data = error.data;
+      throw new RPCError(code, message, data);
+    }
+
+    const result = response.result;
+
+    // Validate result
+    const returnType = methodDef.returnType;
+    const returnOptional = methodDef.returnOptional || false;
+    if (returnType) {
+      try {
+        validateType(result, returnType, ALL_STRUCTS, ALL_ENUMS, returnOptional);
+      } catch (err: any) {
+        throw new Error(`Response validation failed: ${err.message}`);
+      }
+    }
+
+    return result;
+  }
+
+  async calc(nums: any, operation: any, options: CallOptions = {}): Promise<any> {
+    const methodDef = this.methodDefs['calc'];
+    const params: any[] = [
+      nums,
+      operation,
+    ];
+
+    // Validate parameters
+    const expectedParams = methodDef.parameters || [];
+    for (let i = 0; i < params.length; i++) {
+      try {
+        validateType(params[i], expectedParams[i].type, ALL_STRUCTS, ALL_ENUMS, false);
+      } catch (err: any) {
+        throw new Error(`Parameter ${i} (${expectedParams[i].name}) validation failed: ${err.message}`);
+      }
+    }
+
+    // Call transport
+    const methodName = 'A.calc';
+    const response = await callTransport(this.transport, methodName, params, { ...options, paramNames: ['nums', 'operation'] });
+    if (options.responseMeta && response.meta) {
+      Object.assign(options.responseMeta, response.meta);
+    }
+
+    // Extract result from JSON-RPC response
+    if (response.error) {
+      const error = response.error;
+      const code = error.code || -32603;
+      const message = error.message || 'Internal error';
+      const data = error.data;
+      throw new RPCError(code, message, data);
+    }
+
+    const result = response.result;
+
+    // Validate result
+    const returnType = methodDef.returnType;
+    const returnOptional = methodDef.returnOptional || false;
+    if (returnType) {
+      try {
+        validateType(result, returnType, ALL_STRUCTS, ALL_ENUMS, returnOptional);
+      } catch (err: any) {
+        throw new Error(`Response validation failed: ${err.message}`);
+      }
+    }
+
+    return result;
+  }
+
+  async sqrt(a: any, options: CallOptions = {}): Promise<any> {
+    const methodDef = this.methodDefs['sqrt'];
+    const params: any[] = [
+      a,
+    ];
+
+    // Validate parameters
+    const expectedParams = methodDef.parameters || [];
+    for (let i = 0; i < params.length; i++) {
+      try {
+        validateType(params[i], expectedParams[i].type, ALL_STRUCTS, ALL_ENUMS, false);
+      } catch (err: any) {
+        throw new Error(`Parameter ${i} (${expectedParams[i].name}) validation failed: ${err.message}`);
+      }
+    }
+
+    // Call transport
+    const methodName = 'A.sqrt';
+    let response: any;
+    try {
+      response = await callTransport(this.transport, methodName, params, { ...options, paramNames: ['a'] });
+    } catch (err) {
+      throw bindErrorData(err, NegativeInputError);
+    }
+    if (options.responseMeta && response.meta) {
+      Object.assign(options.responseMeta, response.meta);
+    }
+
+    // Extract result from JSON-RPC response
+    if (response.error) {
+      const error = response.error;
+      const code = error.code || -32603;
+      const message = error.message || 'Internal error';
+      const data = error.data;
+      throw bindErrorData(new RPCError(code, message, data), NegativeInputError);
+    }
+
+    const result = response.result;
+
+    // Validate result
+    const returnType = methodDef.returnType;
+    const returnOptional = methodDef.returnOptional || false;
+    if (returnType) {
+      try {
+        validateType(result, returnType, ALL_STRUCTS, ALL_ENUMS, returnOptional);
+      } catch (err: any) {
+        throw new Error(`Response validation failed: ${err.message}`);
+      }
+    }
+
+    return result;
+  }
+
+  async repeat(req1: any, options: CallOptions = {}): Promise<any> {
+    const methodDef = this.methodDefs['repeat'];
+    const params: any[] = [
+      req1,
+    ];
+
+    // Validate parameters
+    const expectedParams = methodDef.parameters || [];
+    for (let i = 0; i < params.length; i++) {
+      try {
+        validateType(params[i], expectedParams[i].type, ALL_STRUCTS, ALL_ENUMS, false);
+      } catch (err: any) {
+        throw new Error(`Parameter ${i} (${expectedParams[i].name}) validation failed: ${err.message}`);
+      }
+    }
+
+    // Call transport
+    const methodName = 'A.repeat';
+    const response = await callTransport(this.transport, methodName, params, { ...options, paramNames: ['req1'] });
+    if (options.responseMeta && response.meta) {
+      Object.assign(options.responseMeta, response.meta);
+    }
+
+    // Extract result from JSON-RPC response
+    if (response.error) {
+      const error = response.error;
+      const code = error.code || -32603;
+      const message = error.message || 'Internal error';
+      const data = error.data;
+      throw new RPCError(code, message, data);
+    }
+
+    const result = response.result;
+
+    // Validate result
+    const returnType = methodDef.returnType;
+    const returnOptional = methodDef.returnOptional || false;
+    if (returnType) {
+      try {
+        validateType(result, returnType, ALL_STRUCTS, ALL_ENUMS, returnOptional);
+      } catch (err: any) {
+        throw new Error(`Response validation failed: ${err.message}`);
+      }
+    }
+
+    return result;
+  }
+
+  async say_hi(options: CallOptions = {}): Promise<any> {
+    const methodDef = this.methodDefs['say_hi'];
+    const params: any[] = [
+    ];
+
+    // Validate parameters
+    const expectedParams = methodDef.parameters || [];
+    for (let i = 0; i < params.length; i++) {
+      try {
+        validateType(params[i], expectedParams[i].type, ALL_STRUCTS, ALL_ENUMS, false);
+      } catch (err: any) {
+        throw new Error(`Parameter ${i} (${expectedParams[i].name}) validation failed: ${err.message}`);
+      }
+    }
+
+    // Call transport
+    const methodName = 'A.say_hi';
+    const response = await callTransport(this.transport, methodName, params, { ...options, paramNames: [] });
+    if (options.responseMeta && response.meta) {
+      Object.assign(options.responseMeta, response.meta);
+    }
+
+    // Extract result from JSON-RPC response
+    if (response.error) {
+      const error = response.error;
+      const code = error.code || -32603;
+      const message = error.message || 'Internal error';
+      const data = error.data;
+      throw new RPCError(code, message, data);
+    }
+
+    const result = response.result;
+
+    // Validate result
+    const returnType = methodDef.returnType;
+    const returnOptional = methodDef.returnOptional || false;
+    if (returnType) {
+      try {
+        validateType(result, returnType, ALL_STRUCTS, ALL_ENUMS, returnOptional);
+      } catch (err: any) {
+        throw new Error(`Response validation failed: ${err.message}`);
+      }
+    }
+
+    return result;
+  }
+
+  async repeat_num(num: any, count: any, options: CallOptions = {}): Promise<any> {
+    const methodDef = this.methodDefs['repeat_num'];
+    const params: any[] = [
+      num,
+      count,
+    ];
+
+    // Validate parameters
+    const expectedParams = methodDef.parameters || [];
+    for (let i = 0; i < params.length; i++) {
+      try {
+        validateType(params[i], expectedParams[i].type, ALL_STRUCTS, ALL_ENUMS, false);
+      } catch (err: any) {
+        throw new Error(`Parameter ${i} (${expectedParams[i].name}) validation failed: ${err.message}`);
+      }
+    }
+
+    // Call transport
+    const methodName = 'A.repeat_num';
+    const response = await callTransport(this.transport, methodName, params, { ...options, paramNames: ['num', 'count'] });
+    if (options.responseMeta && response.meta) {
+      Object.assign(options.responseMeta, response.meta);
+    }
+
+    // Extract result from JSON-RPC response
+    if (response.error) {
+      const error = response.error;
+      const code = error.code || -32603;
+      const message = error.message || 'Internal error';
+      const data = error.data;
+      throw new RPCError(code, message, data);
+    }
+
+    const result = response.result;
+
+    // Validate result
+    const returnType = methodDef.returnType;
+    const returnOptional = methodDef.returnOptional || false;
+    if (returnType) {
+      try {
+        validateType(result, returnType, ALL_STRUCTS, ALL_ENUMS, returnOptional);
+      } catch (err: any) {
+        throw new Error(`Response validation failed: ${err.message}`);
+      }
+    }
+
+    return result;
+  }
+
+  async putPerson(p: any, options: CallOptions = {}): Promise<any> {
+    const methodDef = this.methodDefs['putPerson'];
+    const params: any[] = [
+      p,
+    ];
+
+    // Validate parameters
+    const expectedParams = methodDef.parameters || [];
+    for (let i = 0; i < params.length; i++) {
+      try {
+        validateType(params[i], expectedParams[i].type, ALL_STRUCTS, ALL_ENUMS, false);
+      } catch (err: any) {
+        throw new Error(`Parameter ${i} (${expectedParams[i].name}) validation failed: ${err.message}`);
+      }
+    }
+
+    // Call transport
+    const methodName = 'A.putPerson';
+    const response = await callTransport(this.transport, methodName, params, { ...options, paramNames: ['p'] });
+    if (options.responseMeta && response.meta) {
+      Object.assign(options.responseMeta, response.meta);
+    }
+
+    // Extract result from JSON-RPC response
+    if (response.error) {
+      const error = response.error;
+      const code = error.code || -32603;
+      const message = error.message || 'Internal error';
+      const data = error.data;
+      throw new RPCError(code, message, data);
+    }
+
+    const result = response.result;
+
+    // Validate result
+    const returnType = methodDef.returnType;
+    const returnOptional = methodDef.returnOptional || false;
+    if (returnType) {
+      try {
+        validateType(result, returnType, ALL_STRUCTS, ALL_ENUMS, returnOptional);
+      } catch (err: any) {
+        throw new Error(`Response validation failed: ${err.message}`);
+      }
+    }
+
+    return result;
+  }
+
+}
+
+// a second interface to prove that the server dispatcher
+// understands how to distinguish between interfaces in a contract
+export class BClient {
+  private transport: Transport;
+  private methodDefs: any;
+
+  constructor(transport: Transport) {
+    this.transport = transport;
+    // Method definitions for validation
+    this.methodDefs = METHOD_DEFS['B'];
+  }
+
+  async echo(s: any, options: CallOptions = {}): Promise<any> {
+    const methodDef = this.methodDefs['echo'];
+    const params: any[] = [
+      s,
+    ];
+
+    // Validate parameters
+    const expectedParams = methodDef.parameters || [];
+    for (let i = 0; i < params.length; i++) {
+      try {
+        validateType(params[i], expectedParams[i].type, ALL_STRUCTS, ALL_ENUMS, false);
+      } catch (err: any) {
+        throw new Error(`Parameter ${i} (${expectedParams[i].name}) validation failed: ${err.message}`);
+      }
+    }
+
+    // Call transport
+    const methodName = 'B.echo';
+    const response = await callTransport(this.transport, methodName, params, { ...options, paramNames: ['s'] });
+    if (options.responseMeta && response.meta) {
+      Object.assign(options.responseMeta, response.meta);
+    }
+
+    // Extract result from JSON-RPC response
+    if (response.error) {
+      const error = response.error;
+      const code = error.code || -32603;
+      const message = error.message || 'Internal error';
+      const data = error.data;
+      throw new RPCError(code, message, data);
+    }
+
+    const result = response.result;
+
+    // Validate result
+    const returnType = methodDef.returnType;
+    const returnOptional = methodDef.returnOptional || false;
+    if (returnType) {
+      try {
+        validateType(result, returnType, ALL_STRUCTS, ALL_ENUMS, returnOptional);
+      } catch (err: any) {
+        throw new Error(`Response validation failed: ${err.message}`);
+      }
+    }
+
+    return result;
+  }
+
+}
+
+/** A client for every interface of the IDL, all calling through one transport. */
+export class ApiClient {
+  readonly A: AClient;
+  readonly B: BClient;
+
+  constructor(transport: Transport) {
+    this.A = new AClient(transport);
+    this.B = new BClient(transport);
+  }
+}
+
@@ -0,0 +1,823 @@
+// Generated by pulserpc - do not edit
+
+/// <reference types="node" />
+
+import * as http from 'http';
+import * as fs from 'fs';
+import * as path from 'path';
+import { canonicalJson } from './pulserpc/canonical';
+import { runWithDeadline } from './pulserpc/deadline';
+import { NumberPolicy, attachRequestLiterals, checkRequestIntLiterals, supportsNumberLiterals } from './pulserpc/numbers';
+import { RPCError } from './pulserpc/rpc';
+import { validateType } from './pulserpc/validation';
+import { Fault, FaultConfig } from './pulserpc/faults';
+import { MethodMetrics } from './pulserpc/metrics';
+import { METHOD_DEFS } from './methods';
+import { timingSafeEqual } from 'crypto';
+import { createHash } from 'crypto';
+import { gzipSync } from 'zlib';
+import { ALL_STRUCTS as CONFORM_STRUCTS, ALL_ENUMS as CONFORM_ENUMS } from './conform';
+import { ALL_STRUCTS as INC_STRUCTS, ALL_ENUMS as INC_ENUMS } from './inc';
+
+// Inline type definitions
+interface TypeDef {
+  builtIn?: string;
+  array?: TypeDef;
+  mapValue?: TypeDef;
+  userDefined?: string;
+}
+interface StructDef {
+  extends?: string;
+  fields: Array<{ name: string; type: TypeDef; optional?: boolean }>;
+}
+interface EnumDef {
+  values: Array<{ name: string }>;
+}
+type StructMap = { [key: string]: StructDef };
+type EnumMap = { [key: string]: EnumDef };
+
+// Merge ALL_STRUCTS and ALL_ENUMS from all namespaces
+const ALL_STRUCTS: StructMap = {
+  ...CONFORM_STRUCTS,
+  ...INC_STRUCTS,
+};
+
+const ALL_ENUMS: EnumMap = {
+  ...CONFORM_ENUMS,
+  ...INC_ENUMS,
+};
+
+// Validates the Content-Type of a JSON-RPC POST request in strict mode; returns a description of the problem or null
+function checkContentType(header: string | undefined): string | null {
+  if (!header) {
+    return 'Missing Content-Type header; expected application/json';
+  }
+  const parts = header.split(';').map((part) => part.trim());
+  const mediaType = parts[0].toLowerCase();
+  if (mediaType !== 'application/json' && !(mediaType.startsWith('application/') && mediaType.endsWith('+json'))) {
+    return `Unsupported Content-Type '${mediaType}'; expected application/json`;
+  }
+  for (const param of parts.slice(1)) {
+    const eq = param.indexOf('=');
+    if (eq >= 0 && param.slice(0, eq).trim().toLowerCase() === 'charset') {
+      const charset = param.slice(eq + 1).trim().replace(/^"|"$/g, '').toLowerCase();
+      if (charset !== 'utf-8' && charset !== 'utf8') {
+        return `Unsupported charset '${charset}'; expected utf-8`;
+      }
+    }
+  }
+  return null;
+}
+
+interface ReadOnlyRoute {
+  method: string;
+  params: Array<{ name: string; type: TypeDef }>;
+  // Cache-Control of the responses of a [cache] method
+  cacheControl?: string;
+}
+
+// GET paths (/<Interface>/<method>) of [readonly] methods
+const READONLY_ROUTES: { [path: string]: ReadOnlyRoute } = {
+  '/A/add': {
+    method: 'A.add',
+    params: [
+      { name: 'a', type: {builtIn: 'int'} },
+      { name: 'b', type: {builtIn: 'int'} },
+    ],
+  },
+  '/A/calc': {
+    method: 'A.calc',
+    params: [
+      { name: 'nums', type: {array: {builtIn: 'float'}} },
+      { name: 'operation', type: {userDefined: 'inc.MathOp'} },
+    ],
+    cacheControl: 'max-age=60',
+  },
+  '/B/echo': {
+    method: 'B.echo',
+    params: [
+      { name: 's', type: {builtIn: 'string'} },
+    ],
+  },
+};
+
+// Converts the query string values of one parameter; arrays use repeated keys (?id=1&id=2)
+function bindQueryParam(values: string[], typeDef: TypeDef): any {
+  if (typeDef.array) {
+    const elementType = typeDef.array;
+    return values.map((v) => parseQueryValue(v, elementType));
+  }
+  if (values.length === 0) {
+    throw new Error('missing value');
+  }
+  if (values.length > 1) {
+    throw new Error(`expected a single value, got ${values.length}`);
+  }
+  return parseQueryValue(values[0], typeDef);
+}
+
+// Converts a single query string value; enum membership is checked by parameter validation
+function parseQueryValue(value: string, typeDef: TypeDef): any {
+  switch (typeDef.builtIn) {
+    case 'int':
+      if (!/^[-+]?\d+$/.test(value)) {
+        throw new Error(`invalid int: '${value}'`);
+      }
+      return parseInt(value, 10);
+    case 'float': {
+      const f = Number(value);
+      if (value.trim() === '' || isNaN(f)) {
+        throw new Error(`invalid float: '${value}'`);
+      }
+      return f;
+    }
+    case 'bool':
+      if (value !== 'true' && value !== 'false') {
+        throw new Error(`invalid bool: '${value}' (expected true or false)`);
+      }
+      return value === 'true';
+  }
+  return value;
+}
+
+// Maps a JSON-RPC error code to the HTTP status of a GET response
+function restErrorStatus(code: number): number {
+  if (code === -32700 || code === -32600 || code === -32602) {
+    return 400;
+  }
+  if (code === -32601) {
+    return 404;
+  }
+  if (code >= -32768 && code <= -32000) {
+    return 500;
+  }
+  // Application-defined error codes
+  return 422;
+}
+
+// Returns the strong ETag of a response body: its quoted hex SHA-256
+function responseETag(body: string): string {
+  return '"' + createHash('sha256').update(body, 'utf8').digest('hex') + '"';
+}
+
+// Reports whether an If-None-Match header lists etag, or is "*". Weak validators (W/"...")
+// match by their opaque tag, as the weak comparison requires.
+function etagMatches(header: string | undefined, etag: string): boolean {
+  if (!header) {
+    return false;
+  }
+  return header.split(',').some((candidate) => {
+    candidate = candidate.trim().replace(/^W\//, '');
+    return candidate === '*' || candidate === etag;
+  });
+}
+
+export abstract class A {
+  abstract add(a: any, b: any): any;
+  abstract calc(nums: any, operation: any): any;
+  abstract sqrt(a: any): any;
+  abstract repeat(req1: any): any;
+  abstract say_hi(): any;
+  abstract repeat_num(num: any, count: any): any;
+  abstract putPerson(p: any): any;
+}
+
+// a second interface to prove that the server dispatcher
+// understands how to distinguish between interfaces in a contract
+export abstract class B {
+  abstract echo(s: any): any;
+}
+
+// Names [compress] methods are called by, mapped to the response size in bytes from
+// which their responses are gzipped
+const COMPRESSED_METHODS: Record<string, number> = {
+  'A.repeat_num': 1024,
+};
+
+// The smallest gzip threshold of the [compress] methods a request or batch calls, or
+// null if it calls none
+function compressThreshold(data: any): number | null {
+  let threshold: number | null = null;
+  for (const call of Array.isArray(data) ? data : [data]) {
+    const methodThreshold = call && typeof call.method === 'string' ? COMPRESSED_METHODS[call.method] : undefined;
+    if (methodThreshold !== undefined && (threshold === null || methodThreshold < threshold)) {
+      threshold = methodThreshold;
+    }
+  }
+  return threshold;
+}
+
+// Whether an Accept-Encoding header lists gzip, or *, with a non-zero q value
+function acceptsGzip(header: string | undefined): boolean {
+  for (const part of (header || '').split(',')) {
+    const [coding, ...params] = part.split(';').map((s) => s.trim());
+    if (coding.toLowerCase() !== 'gzip' && coding !== '*') {
+      continue;
+    }
+    const q = params.find((p) => p.startsWith('q='));
+    return q === undefined || parseFloat(q.slice(2)) > 0;
+  }
+  return false;
+}
+
+// Writes a JSON response, gzipped with Content-Encoding if threshold is not null, body
+// is at least threshold bytes and the request accepts gzip. Responses of [compress]
+// methods vary by Accept-Encoding either way.
+function writeCompressible(requestHeaders: http.IncomingHttpHeaders, res: http.ServerResponse, status: number, headers: http.OutgoingHttpHeaders, body: string, threshold: number | null): void {
+  if (threshold !== null) {
+    headers = { ...headers, 'Vary': 'Accept-Encoding' };
+    const bytes = Buffer.from(body, 'utf8');
+    if (bytes.length >= threshold && acceptsGzip(requestHeaders['accept-encoding'] as string | undefined)) {
+      res.writeHead(status, { ...headers, 'Content-Encoding': 'gzip' });
+      res.end(gzipSync(bytes));
+      return;
+    }
+  }
+  res.writeHead(status, headers);
+  res.end(body);
+}
+
+// The result of the built-in pulserpc-capabilities method: the optional protocol
+// features this server supports
+const SERVER_CAPABILITIES = {
+  protocolVersion: '2.0',
+  batch: true,
+  notifications: true,
+  compression: true,
+  msgpack: false,
+  streaming: false,
+};
+
+// The methods the admin endpoint reports calls of
+const ADMIN_METHODS = [
+  'A.add',
+  'A.calc',
+  'A.sqrt',
+  'A.repeat',
+  'A.say_hi',
+  'A.repeat_num',
+  'A.putPerson',
+  'B.echo',
+];
+
+// The SHA-256 of the idl.json this server was generated with
+const IDL_CHECKSUM = 'sha256:ca5b74d76f145aa5855b78e564e6f5794643a49ded33b92fb1d98c15df181a2a';
+
+// Payload sizes of one JSON-RPC call, as passed to the onCall hook
+export interface CallStats {
+  method: string;
+  // Size of the JSON request (the query string for GET requests)
+  requestBytes: number;
+  // Size of the JSON response the method produced, before any response size limit
+  // is applied, or 0 for notifications
+  responseBytes: number;
+}
+
+// A successful call, as passed to the setResponseMeta hook
+export interface ResponseMetaCall {
+  method: string;
+  // The validated params, by position
+  params: any[];
+  // The value the handler returned
+  result: any;
+  // Milliseconds the handler took
+  elapsedMs: number;
+}
+
+// Checks an incoming request before it is dispatched. body is the raw request body,
+// empty for GET requests. Throwing rejects the request with HTTP 401.
+export type RequestVerifier = (headers: http.IncomingHttpHeaders, body: Buffer) => void;
+
+export class PulseRPCServer {
+  private host: string;
+  private port: number;
+  private handlers: Map<string, any>;
+  private server: http.Server | null;
+  private strictContentType: boolean;
+  private numberPolicy: NumberPolicy = 'lenient';
+  private canonical = false;
+  private maxResponseBytes: Map<string, number>;
+  private callHook: ((stats: CallStats) => void) | null;
+  private metaHook: ((call: ResponseMetaCall) => Record<string, any> | null | undefined) | null;
+  private verifier: RequestVerifier | null;
+  private faults: FaultConfig | null = null;
+  // Faults drawn for the calls of the message being handled, in order
+  private pendingFaults: Fault[] = [];
+  // Set by enableAdmin
+  private metrics: MethodMetrics | null = null;
+  private adminToken = '';
+
+  constructor(host: string = 'localhost', port: number = 8080) {
+    this.host = host;
+    this.port = port;
+    this.handlers = new Map();
+    this.server = null;
+    this.strictContentType = false;
+    this.maxResponseBytes = new Map();
+    this.callHook = null;
+    this.metaHook = null;
+    this.verifier = null;
+  }
+
+  // When strict, POST requests must declare application/json with no charset other than
+  // utf-8, or get HTTP 415. Otherwise, the default, any Content-Type is accepted.
+  setStrictContentType(strict: boolean): void {
+    this.strictContentType = strict;
+  }
+
+  // 'lenient' accepts int params written with a fraction or exponent, such as 2.0;
+  // 'strict' rejects them, which needs JSON.parse source text access (Node 21 and later)
+  setNumberPolicy(policy: NumberPolicy): void {
+    if (policy === 'strict' && !supportsNumberLiterals()) {
+      throw new Error('strict number policy needs JSON.parse source text access (Node 21 and later)');
+    }
+    this.numberPolicy = policy;
+  }
+
+  /**
+   * Controls whether responses are encoded as canonical JSON (RFC 8785, see
+   * canonicalJson), so equal messages are equal bytes in every language. Off by default.
+   */
+  setCanonicalJson(enabled: boolean): void {
+    this.canonical = enabled;
+  }
+
+  // Encodes value as JSON, or as canonical JSON when setCanonicalJson is on
+  private stringify(value: any): string {
+    return this.canonical ? canonicalJson(value) : JSON.stringify(value);
+  }
+
+  // Limits the encoded response size of method ('Interface.method'), or of every method
+  // without its own limit if method is '*'. Larger responses are replaced by a -32001
+  // 'Response too large' error.
+  setMaxResponseBytes(method: string, limit: number): void {
+    this.maxResponseBytes.set(method, limit);
+  }
+
+  // Registers a hook that is invoked after every call with its request and response sizes
+  onCall(hook: (stats: CallStats) => void): void {
+    this.callHook = hook;
+  }
+
+  // Registers a hook that returns metadata for a successful call, such as timings,
+  // pagination hints or warnings. Non-empty metadata is sent in the reserved 'meta'
+  // member of the response, next to the result, and clients read it with callWithMeta.
+  setResponseMeta(hook: (call: ResponseMetaCall) => Record<string, any> | null | undefined): void {
+    this.metaHook = hook;
+  }
+
+  // Installs a check that every request must pass before it is dispatched, such as
+  // hmacVerifier from signing.ts. Rejected requests get HTTP 401.
+  setVerifier(verifier: RequestVerifier): void {
+    this.verifier = verifier;
+  }
+
+  // Reads a fault config (see FaultConfig in pulserpc/faults.ts) and injects its latency,
+  // errors and malformed responses into every later call, so clients can be tested against
+  // a slow or unreliable server. [readonly] GET routes are served without faults.
+  loadFaults(file: string): void {
+    this.faults = FaultConfig.load(file);
+  }
+
+  // Serves the admin endpoint, GET /_pulserpc/admin, to requests that carry
+  // "Authorization: Bearer <token>". It reports the registered interfaces and the types
+  // of their handlers, the calls, errors and handler latency of every method since
+  // enableAdmin was called, and the checksum of the IDL the server was generated with.
+  enableAdmin(token: string): void {
+    if (!token) {
+      throw new Error('admin token must not be empty');
+    }
+    this.adminToken = token;
+    this.metrics = new MethodMetrics(ADMIN_METHODS);
+  }
+
+  private handleAdmin(headers: http.IncomingHttpHeaders, res: http.ServerResponse): void {
+    const given = Buffer.from(headers.authorization || '');
+    const expected = Buffer.from('Bearer ' + this.adminToken);
+    if (given.length !== expected.length || !timingSafeEqual(given, expected)) {
+      res.writeHead(401, { 'WWW-Authenticate': 'Bearer' });
+      res.end('Unauthorized');
+      return;
+    }
+    const metrics = this.metrics!;
+    const interfaces = [...this.handlers.keys()].sort().map((name) => ({
+      name,
+      handler: this.handlers.get(name)?.constructor?.name || typeof this.handlers.get(name),
+    }));
+    res.writeHead(200, { 'Content-Type': 'application/json', 'Cache-Control': 'no-store' });
+    res.end(JSON.stringify({
+      idlChecksum: IDL_CHECKSUM,
+      startedAt: metrics.startedAt.toISOString().replace(/\.\d{3}Z$/, 'Z'),
+      uptimeSeconds: (Date.now() - metrics.startedAt.getTime()) / 1000,
+      interfaces,
+      methods: metrics.snapshot(),
+    }));
+  }
+
+  register(interfaceName: string, instance: any): void {
+    this.handlers.set(interfaceName, instance);
+  }
+
+  handleRequest(requestJson: any): any {
+    // Validate JSON-RPC 2.0 structure
+    if (typeof requestJson !== 'object' || requestJson === null || Array.isArray(requestJson)) {
+      return this.errorResponse(null, -32600, 'Invalid Request', 'Request must be an object');
+    }
+
+    const jsonrpc = requestJson.jsonrpc;
+    if (jsonrpc !== '2.0') {
+      return this.errorResponse(null, -32600, 'Invalid Request', "jsonrpc must be '2.0'");
+    }
+
+    const method = requestJson.method;
+    if (typeof method !== 'string') {
+      return this.errorResponse(null, -32600, 'Invalid Request', 'method must be a string');
+    }
+
+    let params = requestJson.params;
+    const requestId = requestJson.id;
+    const isNotification = !('id' in requestJson);
+
+    // Special case: pulserpc-idl method returns the IDL JSON document
+    if (method === 'pulserpc-idl') {
+      try {
+        const serverDir = __dirname;
+        const idlJsonPath = path.join(serverDir, 'idl.json');
+        const idlDoc = JSON.parse(fs.readFileSync(idlJsonPath, 'utf-8'));
+
+        if (isNotification) {
+          return null;
+        }
+        return {
+          jsonrpc: '2.0',
+          result: idlDoc,
+          id: requestId,
+        };
+      } catch (err: any) {
+        return this.errorResponse(requestId, -32603, 'Internal error', `Failed to load IDL JSON: ${err.message}`);
+      }
+    }
+
+    // Special case: pulserpc-capabilities method reports the optional protocol features
+    if (method === 'pulserpc-capabilities') {
+      if (isNotification) {
+        return null;
+      }
+      return { jsonrpc: '2.0', result: SERVER_CAPABILITIES, id: requestId };
+    }
+
+    // Parse method name: interface.method
+    const parts = method.split('.', 2);
+    if (parts.length !== 2) {
+      return this.errorResponse(requestId, -32601, 'Method not found', `Invalid method format: ${method}`);
+    }
+
+    const interfaceName = parts[0];
+    const methodName = parts[1];
+
+    // Find handler
+    const handler = this.handlers.get(interfaceName);
+    if (!handler) {
+      return this.errorResponse(requestId, -32601, 'Method not found', `Interface '${interfaceName}' not registered`);
+    }
+
+    // Find method on handler
+    if (typeof handler[methodName] !== 'function') {
+      return this.errorResponse(requestId, -32601, 'Method not found', `Method '${methodName}' not found on interface '${interfaceName}'`);
+    }
+
+    const methodFunc = handler[methodName];
+
+    // Find interface and method definition
+    const methodDef = METHOD_DEFS[interfaceName]?.[methodName];
+    if (!methodDef) {
+      return this.errorResponse(requestId, -32601, 'Method not found', `Method '${methodName}' not found in interface '${interfaceName}'`);
+    }
+
+    // Validate params
+    if (params === null || params === undefined) {
+      params = [];
+    }
+    if (typeof params === 'object' && !Array.isArray(params)) {
+      try {
+        params = this.paramsByName(params, methodDef.parameters || []);
+      } catch (err: any) {
+        return this.errorResponse(requestId, -32602, 'Invalid params', err.message);
+      }
+    }
+    if (!Array.isArray(params)) {
+      return this.errorResponse(requestId, -32602, 'Invalid params', 'params must be an array');
+    }
+
+    // Validate param count
+    const expectedParams = methodDef.parameters || [];
+    if (params.length !== expectedParams.length) {
+      return this.errorResponse(requestId, -32602, 'Invalid params', `Expected ${expectedParams.length} parameters, got ${params.length}`);
+    }
+
+    // Validate each param
+    for (let i = 0; i < params.length; i++) {
+      try {
+        validateType(params[i], expectedParams[i].type, ALL_STRUCTS, ALL_ENUMS, false);
+      } catch (err: any) {
+        return this.errorResponse(requestId, -32602, 'Invalid params', `Parameter ${i} (${expectedParams[i].name}) validation failed: ${err.message}`);
+      }
+    }
+    try {
+      checkRequestIntLiterals(requestJson, expectedParams, ALL_STRUCTS);
+    } catch (err: any) {
+      return this.errorResponse(requestId, -32602, 'Invalid params', err.message);
+    }
+
+    // Invoke handler
+    const started = Date.now();
+    let result: any;
+    let failed = true;
+    try {
+      result = methodFunc.apply(handler, params);
+      failed = false;
+    } catch (err: any) {
+      if (err instanceof RPCError) {
+        return this.errorResponse(requestId, err.code, err.message, err.data);
+      }
+      return this.errorResponse(requestId, -32603, 'Internal error', err.message || String(err));
+    } finally {
+      this.metrics?.record(`${interfaceName}.${methodName}`, Date.now() - started, failed);
+    }
+
+    // Validate response
+    const returnType = methodDef.returnType;
+    const returnOptional = methodDef.returnOptional || false;
+    if (returnType) {
+      try {
+        validateType(result, returnType, ALL_STRUCTS, ALL_ENUMS, returnOptional);
+      } catch (err: any) {
+        return this.errorResponse(requestId, -32603, 'Internal error', `Response validation failed: ${err.message}`);
+      }
+    }
+
+    // Return success response
+    if (isNotification) {
+      return null;
+    }
+    const response: any = {
+      jsonrpc: '2.0',
+      result: result,
+      id: requestId,
+    };
+    if (this.metaHook) {
+      const meta = this.metaHook({ method, params, result, elapsedMs: Date.now() - started });
+      if (meta && Object.keys(meta).length > 0) {
+        response.meta = meta;
+      }
+    }
+    return response;
+  }
+
+  // Orders by-name params as the method declares them. Optional parameters that
+  // are left out are null.
+  private paramsByName(named: Record<string, any>, expectedParams: any[]): any[] {
+    const declared = expectedParams.map((p: any) => p.name);
+    for (const name of Object.keys(named)) {
+      if (!declared.includes(name)) {
+        throw new Error(`unknown parameter '${name}'`);
+      }
+    }
+    return expectedParams.map((p: any) => {
+      if (Object.prototype.hasOwnProperty.call(named, p.name)) {
+        return named[p.name];
+      }
+      if (!p.optional) {
+        throw new Error(`missing parameter '${p.name}'`);
+      }
+      return null;
+    });
+  }
+
+  private errorResponse(requestId: any, code: number, message: string, data?: any): any {
+    const error: any = { code, message };
+    if (data !== null && data !== undefined) {
+      error.data = data;
+    }
+    return {
+      jsonrpc: '2.0',
+      error: error,
+      id: requestId,
+    };
+  }
+
+  // Serves a [readonly] method over HTTP GET. The response body is the JSON-RPC
+  // response envelope; errors use a non-2xx status so they are not cached.
+  private handleGetRequest(url: URL, route: ReadOnlyRoute, headers: http.IncomingHttpHeaders, res: http.ServerResponse): void {
+    const params: any[] = [];
+    let response: any = null;
+    for (const paramDef of route.params) {
+      try {
+        params.push(bindQueryParam(url.searchParams.getAll(paramDef.name), paramDef.type));
+      } catch (err: any) {
+        response = this.errorResponse(null, -32602, 'Invalid params', `Query parameter ${paramDef.name}: ${err.message}`);
+        break;
+      }
+    }
+    if (response === null) {
+      response = this.handleRequest({ jsonrpc: '2.0', method: route.method, params, id: null });
+    }
+    const [sent, encoded] = this.encodeResponse(route.method, Buffer.byteLength(url.search.replace(/^\?/, '')), response);
+    const status = sent.error ? restErrorStatus(sent.error.code) : 200;
+    if (status === 200 && route.cacheControl && encoded !== null) {
+      const etag = responseETag(encoded);
+      const cacheHeaders = { 'ETag': etag, 'Cache-Control': route.cacheControl };
+      if (etagMatches(headers['if-none-match'], etag)) {
+        res.writeHead(304, cacheHeaders);
+        res.end();
+        return;
+      }
+      writeCompressible(headers, res, 200, { ...cacheHeaders, 'Content-Type': 'application/json' }, encoded, COMPRESSED_METHODS[route.method] ?? null);
+      return;
+    }
+    if (encoded !== null) {
+      writeCompressible(headers, res, status, { 'Content-Type': 'application/json' }, encoded, COMPRESSED_METHODS[route.method] ?? null);
+      return;
+    }
+    res.writeHead(status, { 'Content-Type': 'application/json' });
+    res.end(encoded);
+  }
+
+  // Handles one JSON-RPC request and returns its encoded response, or null for notifications
+  private handleCall(requestJson: any, requestBytes: number): string | null {
+    const method = requestJson && typeof requestJson.method === 'string' ? requestJson.method : '';
+    const fault = this.pendingFaults.shift();
+    if (fault === undefined) {
+      return this.encodeResponse(method, requestBytes, this.handleRequest(requestJson))[1];
+    }
+    let response: any = null;
+    if (!fault.error) {
+      response = this.handleRequest(requestJson);
+    } else if (requestJson && typeof requestJson === 'object' && 'id' in requestJson) {
+      response = this.errorResponse(requestJson.id, fault.error.code, fault.error.message);
+    }
+    const encoded = this.encodeResponse(method, requestBytes, response)[1];
+    if (encoded !== null && fault.malformed) {
+      return encoded.slice(0, Math.floor(encoded.length / 2));
+    }
+    return encoded;
+  }
+
+  // Draws the faults of the calls in a message and handles it after their combined latency.
+  // handleCall applies the drawn errors and malformed responses in order.
+  private withInjectedLatency(chunks: Buffer[], handle: () => void): void {
+    if (this.faults === null) {
+      handle();
+      return;
+    }
+    const faults: Fault[] = [];
+    try {
+      const data = JSON.parse(Buffer.concat(chunks).toString('utf8'));
+      for (const call of Array.isArray(data) ? data : [data]) {
+        faults.push(this.faults.draw(call && typeof call.method === 'string' ? call.method : ''));
+      }
+    } catch {
+      // Messages that are not valid JSON are answered without faults
+    }
+    const delayMs = faults.reduce((sum, fault) => sum + fault.delayMs, 0);
+    setTimeout(() => {
+      this.pendingFaults = faults;
+      try {
+        handle();
+      } finally {
+        this.pendingFaults = [];
+      }
+    }, delayMs);
+  }
+
+  // Encodes the response of one call, replacing it with a -32001 error if it exceeds the
+  // method's response size limit, and reports the payload sizes to the onCall hook
+  private encodeResponse(method: string, requestBytes: number, response: any): [any, string | null] {
+    let encoded: string | null = null;
+    let size = 0;
+    if (response !== null && response !== undefined) {
+      encoded = this.stringify(response);
+      size = Buffer.byteLength(encoded);
+      const limit = this.maxResponseBytes.get(method) ?? this.maxResponseBytes.get('*');
+      if (limit !== undefined && size > limit) {
+        response = this.errorResponse(response.id, -32001, 'Response too large',
+          `Response of ${size} bytes exceeds the ${limit} byte limit for ${method}`);
+        encoded = this.stringify(response);
+      }
+    }
+    if (this.callHook !== null) {
+      this.callHook({ method, requestBytes, responseBytes: size });
+    }
+    return [response, encoded];
+  }
+
+  // Runs the verifier, if any, answering 401 when it rejects the request
+  private verify(headers: http.IncomingHttpHeaders, body: Buffer, res: http.ServerResponse): boolean {
+    if (this.verifier === null) {
+      return true;
+    }
+    try {
+      this.verifier(headers, body);
+      return true;
+    } catch (err: any) {
+      res.writeHead(401, { 'Content-Type': 'application/json' });
+      res.end(JSON.stringify(this.errorResponse(null, -32600, 'Invalid Request', err?.message || String(err))));
+      return false;
+    }
+  }
+
+  serveForever(): void {
+    this.server = http.createServer((req, res) => {
+      if (req.method === 'GET') {
+        const url = new URL(req.url || '/', 'http://localhost');
+        if (url.pathname === '/_pulserpc/admin' && this.metrics) {
+          this.handleAdmin(req.headers, res);
+          return;
+        }
+        const route = READONLY_ROUTES[url.pathname];
+        if (route) {
+          if (!this.verify(req.headers, Buffer.alloc(0), res)) {
+            return;
+          }
+          runWithDeadline(req.headers, () => this.handleGetRequest(url, route, req.headers, res));
+          return;
+        }
+      }
+      if (req.method !== 'POST') {
+        res.writeHead(405, { 'Content-Type': 'application/json' });
+        res.end(JSON.stringify({ error: 'Method Not Allowed' }));
+        return;
+      }
+
+      const problem = this.strictContentType ? checkContentType(req.headers['content-type']) : null;
+      if (problem !== null) {
+        res.writeHead(415, { 'Content-Type': 'application/json' });
+        res.end(JSON.stringify(this.errorResponse(null, -32600, 'Invalid Request', problem)));
+        return;
+      }
+
+      const chunks: Buffer[] = [];
+      req.on('data', (chunk: Buffer) => { chunks.push(chunk); });
+      req.on('end', () => runWithDeadline(req.headers, () => this.withInjectedLatency(chunks, () => {
+        try {
+          // Decode once so multi-byte UTF-8 sequences split across chunks stay intact
+          const rawBody = Buffer.concat(chunks);
+          if (!this.verify(req.headers, rawBody, res)) {
+            return;
+          }
+          const body = rawBody.toString('utf8');
+          const data = JSON.parse(body);
+          if (this.numberPolicy === 'strict') {
+            attachRequestLiterals(data, body);
+          }
+
+          // Handle batch requests
+          if (Array.isArray(data)) {
+            if (data.length === 0) {
+              res.writeHead(400, { 'Content-Type': 'application/json' });
+              res.end(JSON.stringify({ error: 'Empty batch array' }));
+              return;
+            }
+            const responses: string[] = [];
+            for (const req of data) {
+              // Batch members are measured by their own JSON encoding
+              const response = this.handleCall(req, Buffer.byteLength(JSON.stringify(req)));
+              if (response !== null) {
+                responses.push(response);
+              }
+            }
+            if (responses.length === 0) {
+              res.writeHead(204);
+              res.end();
+            } else {
+              writeCompressible(req.headers, res, 200, { 'Content-Type': 'application/json' }, '[' + responses.join(',') + ']', compressThreshold(data));
+            }
+          } else {
+            const response = this.handleCall(data, rawBody.length);
+            if (response === null) {
+              res.writeHead(204);
+              res.end();
+            } else {
+              writeCompressible(req.headers, res, 200, { 'Content-Type': 'application/json' }, response, compressThreshold(data));
+            }
+          }
+        } catch (err: any) {
+          const errorResponse = this.errorResponse(null, -32700, 'Parse error', err.message);
+          res.writeHead(200, { 'Content-Type': 'application/json' });
+          res.end(JSON.stringify(errorResponse));
+        }
+      })));
+    });
+
+    this.server.listen(this.port, this.host, () => {
+      console.log(`PulseRPCServer server listening on http://${this.host}:${this.port}`);
+    });
+  }
+
+  shutdown(): void {
+    if (this.server) {
+      this.server.close();
+      this.server = null;
+    }
+  }
+}
@@ -0,0 +1,59 @@
+// Generated by pulserpc - do not edit
+
+import { createHmac, timingSafeEqual } from 'crypto';
+import type { RequestSigner } from './client';
+import type { RequestVerifier } from './server';
+
+/** Carries the HMAC-SHA256 of a signed request as "sha256=<hex>". */
+export const SIGNATURE_HEADER = 'X-PulseRPC-Signature';
+
+/** Carries the Unix time, in seconds, a request was signed at. */
+export const SIGNATURE_TIMESTAMP_HEADER = 'X-PulseRPC-Timestamp';
+
+/**
+ * How far a signed request's timestamp may be from the server's clock before
+ * hmacVerifier rejects it as a replay.
+ */
+export const DEFAULT_SIGNATURE_TOLERANCE_MS = 5 * 60 * 1000;
+
+/** Returns "sha256=" and the hex HMAC-SHA256 of timestamp + "." + body. */
+export function hmacSignature(secret: string | Buffer, timestamp: string, body: string | Buffer): string {
+  return 'sha256=' + createHmac('sha256', secret).update(timestamp + '.').update(body).digest('hex');
+}
+
+/** Returns a signer for HTTPTransport that signs requests with secret. */
+export function hmacSigner(secret: string | Buffer): RequestSigner {
+  return (body: string) => {
+    const timestamp = String(Math.floor(Date.now() / 1000));
+    return {
+      [SIGNATURE_TIMESTAMP_HEADER]: timestamp,
+      [SIGNATURE_HEADER]: hmacSignature(secret, timestamp, body),
+    };
+  };
+}
+
+/**
+ * Returns a verifier for the server that accepts requests signed by hmacSigner with
+ * secret whose timestamp is within toleranceMs of the server's clock.
+ */
+export function hmacVerifier(secret: string | Buffer, toleranceMs: number = DEFAULT_SIGNATURE_TOLERANCE_MS): RequestVerifier {
+  return (headers, body) => {
+    const timestamp = headers[SIGNATURE_TIMESTAMP_HEADER.toLowerCase()];
+    const signature = headers[SIGNATURE_HEADER.toLowerCase()];
+    if (typeof timestamp !== 'string' || typeof signature !== 'string') {
+      throw new Error('missing request signature');
+    }
+    const signedAt = Number(timestamp);
+    if (!Number.isInteger(signedAt)) {
+      throw new Error('invalid signature timestamp');
+    }
+    if (Math.abs(Date.now() - signedAt * 1000) > toleranceMs) {
+      throw new Error('signature timestamp outside tolerance');
+    }
+    const expected = Buffer.from(hmacSignature(secret, timestamp, body));
+    const actual = Buffer.from(signature);
+    if (actual.length !== expected.length || !timingSafeEqual(actual, expected)) {
+      throw new Error('invalid request signature');
+    }
+  };
+}
@@ -0,0 +1,304 @@
+// Generated by pulserpc - do not edit
+// Test client for integration testing
+
+/// <reference types="node" />
+
+import { HTTPTransport, AClient, BClient } from './client';
+import * as http from 'http';
+import * as fs from 'fs';
+
+async function waitForServer(url: string, timeout: number = 10000): Promise<boolean> {
+  const startTime = Date.now();
+  let retryDelay = 200; // Start with 200ms delay, will increase exponentially
+
+  while (Date.now() - startTime < timeout) {
+    try {
+      const controller = new AbortController();
+      const timeoutId = setTimeout(() => controller.abort(), 2000);
+
+      const response = await fetch(url, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: '{"jsonrpc":"2.0","method":"pulserpc-idl","id":1}',
+        signal: controller.signal,
+      });
+
+      clearTimeout(timeoutId);
+
+      // Accept 2xx status codes as success
+      if (response.ok) {
+        return true;
+      } else {
+        // Server responded but with error status - fail fast
+        console.error(`Server responded with status ${response.status}`);
+        return false;
+      }
+    } catch (err: any) {
+      // Check if this is a network/connection error (retry) or abort (stop)
+      if (err.name === 'AbortError') {
+        console.error('Request timed out, server may not be ready');
+      } else if (err.code === 'ECONNREFUSED' || err.code === 'ENOTFOUND') {
+        // Connection refused or host not found - server not ready yet
+      } else {
+        // Other errors - log and continue trying
+        console.error(`Connection error: ${err.message}`);
+      }
+    }
+
+    // Wait before retrying with exponential backoff
+    await new Promise(resolve => setTimeout(resolve, retryDelay));
+    retryDelay = Math.min(retryDelay * 1.5, 1000); // Cap at 1 second
+  }
+
+  return false;
+}
+
+// Calls pulserpc-idl many times at once through one transport and returns a message
+// if a call fails or two responses share an id
+async function testConcurrentCalls(transport: { call(method: string, params: any[]): Promise<any> }): Promise<string[]> {
+  const calls = 32;
+  let responses: any[];
+  try {
+    responses = await Promise.all(Array.from({ length: calls }, () => transport.call('pulserpc-idl', [])));
+  } catch (err: any) {
+    return [`concurrent calls failed: ${err.message}`];
+  }
+  const ids = responses.map((response) => response.id);
+  if (ids.some((id) => id === undefined || id === null) || new Set(ids).size !== calls) {
+    return [`concurrent calls: response ids are missing or shared: ${ids}`];
+  }
+  console.log(`✓ ${calls} concurrent calls passed`);
+  return [];
+}
+
+async function main() {
+  const serverUrl = 'http://localhost:8080';
+
+  // Wait for server to be ready (shell script already waited, but do a final check)
+  if (!(await waitForServer(serverUrl, 10000))) {
+    console.error('ERROR: Server did not become ready in time');
+    process.exit(1);
+  }
+
+  console.log('Server is ready. Running tests...');
+  console.log();
+
+  // Create transport and clients
+  const transport = new HTTPTransport(serverUrl);
+  const aClient = new AClient(transport);
+  const bClient = new BClient(transport);
+
+  const errors: string[] = [];
+
+  // Test A.add
+  try {
+    const result = await aClient.add(2, 3);
+    if (result !== 5) {
+      throw new Error(`Expected 5, got ${result}`);
+    }
+    console.log('✓ A.add passed');
+  } catch (err: any) {
+    const errorMsg = `A.add failed: ${err.message || err}`;
+    errors.push(errorMsg);
+    console.error(`✗ ${errorMsg}`);
+  }
+
+  // Test A.calc
+  try {
+    const result = await aClient.calc([1.0, 2.0, 3.0], 'add');
+    if (typeof result !== 'number') {
+      throw new Error(`Expected number, got ${typeof result}`);
+    }
+    console.log('✓ A.calc passed');
+  } catch (err: any) {
+    const errorMsg = `A.calc failed: ${err.message || err}`;
+    errors.push(errorMsg);
+    console.error(`✗ ${errorMsg}`);
+  }
+
+  // Test A.sqrt
+  try {
+    const result = await aClient.sqrt(4.0);
+    if (globalThis.Math.abs(result - 2.0) >= 0.001) {
+      throw new Error(`Expected ~2.0, got ${result}`);
+    }
+    console.log('✓ A.sqrt passed');
+  } catch (err: any) {
+    const errorMsg = `A.sqrt failed: ${err.message || err}`;
+    errors.push(errorMsg);
+    console.error(`✗ ${errorMsg}`);
+  }
+
+  // Test A.repeat
+  try {
+    const result = await aClient.repeat({ to_repeat: 'hello', count: 3, force_uppercase: false });
+    if (typeof result !== 'object' || !result) {
+      throw new Error(`Expected object, got ${typeof result}`);
+    }
+    if (!('items' in result)) {
+      throw new Error("Result missing 'items' field");
+    }
+    if (result.items.length !== 3) {
+      throw new Error(`Expected 3 items, got ${result.items.length}`);
+    }
+    console.log('✓ A.repeat passed');
+  } catch (err: any) {
+    const errorMsg = `A.repeat failed: ${err.message || err}`;
+    errors.push(errorMsg);
+    console.error(`✗ ${errorMsg}`);
+  }
+
+  // Test A.say_hi
+  try {
+    const result = await aClient.say_hi();
+    if (typeof result !== 'object' || !result) {
+      throw new Error(`Expected object, got ${typeof result}`);
+    }
+    if (result.hi !== 'hi') {
+      throw new Error(`Expected hi='hi', got ${JSON.stringify(result)}`);
+    }
+    console.log('✓ A.say_hi passed');
+  } catch (err: any) {
+    const errorMsg = `A.say_hi failed: ${err.message || err}`;
+    errors.push(errorMsg);
+    console.error(`✗ ${errorMsg}`);
+  }
+
+  // Test A.repeat_num
+  try {
+    const result = await aClient.repeat_num(2, 2);
+    if (!Array.isArray(result)) {
+      throw new Error(`Expected array, got ${typeof result}`);
+    }
+    if (result.length !== 2) {
+      throw new Error(`Expected 2 items, got ${result.length}`);
+    }
+    console.log('✓ A.repeat_num passed');
+  } catch (err: any) {
+    const errorMsg = `A.repeat_num failed: ${err.message || err}`;
+    errors.push(errorMsg);
+    console.error(`✗ ${errorMsg}`);
+  }
+
+  // Test A.putPerson
+  try {
+    const result = await aClient.putPerson({ personId: 'person123', firstName: 'John', lastName: 'Doe', email: null });
+    if (typeof result !== 'string') {
+      throw new Error(`Expected string, got ${typeof result}`);
+    }
+    if (result !== 'person123') {
+      throw new Error(`Expected 'person123', got ${result}`);
+    }
+    console.log('✓ A.putPerson passed');
+  } catch (err: any) {
+    const errorMsg = `A.putPerson failed: ${err.message || err}`;
+    errors.push(errorMsg);
+    console.error(`✗ ${errorMsg}`);
+  }
+
+  // Test B.echo
+  try {
+    const result = await bClient.echo('test');
+    // Test normal return
+    if (result !== 'test') {
+      throw new Error(`Expected 'test', got ${result}`);
+    }
+    // Test null return
+    const resultNull = await bClient.echo('return-null');
+    if (resultNull !== null) {
+      throw new Error(`Expected null, got ${resultNull}`);
+    }
+    console.log('✓ B.echo passed');
+  } catch (err: any) {
+    const errorMsg = `B.echo failed: ${err.message || err}`;
+    errors.push(errorMsg);
+    console.error(`✗ ${errorMsg}`);
+  }
+
+  errors.push(...(await testConcurrentCalls(transport)));
+
+  errors.push(...(await runTestVectors(serverUrl)));
+
+  // Report results
+  console.log();
+  if (errors.length > 0) {
+    console.error(`FAILED: ${errors.length} test(s) failed:`);
+    for (const error of errors) {
+      console.error(`  - ${error}`);
+    }
+    process.exit(1);
+  } else {
+    console.log('SUCCESS: All tests passed!');
+    process.exit(0);
+  }
+}
+
+// Compares JSON values ignoring key order; a missing value equals null
+function vectorValuesEqual(expected: any, actual: any): boolean {
+  if (expected === undefined) expected = null;
+  if (actual === undefined) actual = null;
+  if (Array.isArray(expected) || Array.isArray(actual)) {
+    return Array.isArray(expected) && Array.isArray(actual) &&
+      expected.length === actual.length && expected.every((e, i) => vectorValuesEqual(e, actual[i]));
+  }
+  if (expected !== null && actual !== null && typeof expected === 'object' && typeof actual === 'object') {
+    const keys = Object.keys(expected);
+    return keys.length === Object.keys(actual).length &&
+      keys.every((k) => k in actual && vectorValuesEqual(expected[k], actual[k]));
+  }
+  return expected === actual;
+}
+
+// Returns an empty string if actual satisfies expected
+function compareTestVector(expected: any, actual: any): string {
+  if (!vectorValuesEqual(expected.id, actual.id)) {
+    return `expected id ${JSON.stringify(expected.id)}, got ${JSON.stringify(actual.id)}`;
+  }
+  if ('error' in expected) {
+    if (!actual.error || actual.error.code !== expected.error.code) {
+      return `expected error code ${expected.error.code}, got ${JSON.stringify(actual)}`;
+    }
+    return '';
+  }
+  if ('error' in actual) {
+    return `unexpected error ${JSON.stringify(actual.error)}`;
+  }
+  if ('result' in expected && !vectorValuesEqual(expected.result, actual.result)) {
+    return `expected result ${JSON.stringify(expected.result)}, got ${JSON.stringify(actual.result)}`;
+  }
+  return '';
+}
+
+// Replays testvectors.json and returns a message for each mismatch
+async function runTestVectors(serverUrl: string): Promise<string[]> {
+  let vectors: any[];
+  try {
+    vectors = JSON.parse(fs.readFileSync('testvectors.json', 'utf8')).vectors;
+  } catch (err: any) {
+    return [`testvectors.json: ${err.message}`];
+  }
+  const failures: string[] = [];
+  for (const v of vectors) {
+    try {
+      const response = await fetch(serverUrl, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(v.request),
+      });
+      const msg = compareTestVector(v.response, await response.json());
+      if (msg) {
+        failures.push(`vector ${v.name}: ${msg}`);
+      } else {
+        console.log(`✓ vector ${v.name} passed`);
+      }
+    } catch (err: any) {
+      failures.push(`vector ${v.name}: ${err.message}`);
+    }
+  }
+  return failures;
+}
+
+main().catch((err) => {
+  console.error('Fatal error:', err);
+  process.exit(1);
+});
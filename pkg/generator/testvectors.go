@@ -0,0 +1,309 @@
+package generator
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// testVectorsFileName is the file written to the output directory when
+// -generate-test-vectors is set. Generated test clients replay it from their
+// working directory.
+const testVectorsFileName = "testvectors.json"
+
+// testVectorsVersion is bumped whenever the file layout changes incompatibly
+const testVectorsVersion = 1
+
+// TestVectorFile is the language-neutral document of canonical request/response pairs
+type TestVectorFile struct {
+	Version int          `json:"version"`
+	Vectors []TestVector `json:"vectors"`
+}
+
+// TestVector is a single JSON-RPC request and the response every server must produce.
+//
+// Responses are matched as follows: if the expected response has an "error", only
+// error.code is compared. If it has a "result", the result is compared by value
+// (object key order is ignored and numbers compare numerically). If it has neither,
+// any successful response is accepted. The response id must always match.
+type TestVector struct {
+	Name     string                 `json:"name"`
+	Request  map[string]interface{} `json:"request"`
+	Response map[string]interface{} `json:"response"`
+}
+
+// conformResult is a known input/output pair for the conformance contract
+// (examples/conform.pulse) that every generated test server implements
+type conformResult struct {
+	name   string
+	params []interface{}
+	result interface{}
+}
+
+// conformResults is keyed by "Interface.method". Entries are only used when the
+// method's parameter count matches, so unrelated IDLs that reuse a name are not
+// held to conformance behavior.
+var conformResults = map[string][]conformResult{
+	"A.add":  {{"add", []interface{}{2, 3}, 5}},
+	"A.calc": {{"multiply", []interface{}{[]interface{}{1.5, 2}, "multiply"}, 3}},
+	"A.sqrt": {{"sqrt", []interface{}{16}, 4}},
+	"A.repeat": {{"uppercase", []interface{}{map[string]interface{}{"to_repeat": "ab", "count": 2, "force_uppercase": true}},
+		map[string]interface{}{"status": "ok", "count": 2, "items": []interface{}{"AB", "AB"}}}},
+	"A.say_hi":     {{"hi", []interface{}{}, map[string]interface{}{"hi": "hi"}}},
+	"A.repeat_num": {{"repeat", []interface{}{7, 3}, []interface{}{7, 7, 7}}},
+	"A.putPerson": {{"null-optional-field", []interface{}{map[string]interface{}{"personId": "p1", "firstName": "Ada", "lastName": "Lovelace", "email": nil}},
+		"p1"}},
+	"B.echo": {
+		{"echo", []interface{}{"hello"}, "hello"},
+		{"return-null", []interface{}{"return-null"}, nil},
+	},
+}
+
+// testVectorBuilder derives canonical values for IDL types
+type testVectorBuilder struct {
+	structs map[string]*parser.Struct
+	enums   map[string]*parser.Enum
+	vectors []TestVector
+	nextID  int
+}
+
+// BuildTestVectors returns request/response pairs covering every method in the IDL,
+// including the error cases that all servers must reject the same way
+func BuildTestVectors(idl *parser.IDL) *TestVectorFile {
+	b := newTestVectorBuilder(idl)
+	for _, iface := range idl.Interfaces {
+		for _, method := range iface.Methods {
+			b.addMethodVectors(iface, method)
+		}
+	}
+
+	if len(idl.Interfaces) > 0 {
+		b.addErrorVector("unknown-method", idl.Interfaces[0].Name+".noSuchMethod", []interface{}{}, -32601)
+	}
+	b.addErrorVector("unknown-interface", "NoSuchInterface.method", []interface{}{}, -32601)
+	b.add("invalid-jsonrpc-version", map[string]interface{}{
+		"jsonrpc": "1.0",
+		"method":  "pulserpc-idl",
+		"params":  []interface{}{},
+		"id":      b.id(),
+	}, map[string]interface{}{"error": map[string]interface{}{"code": -32600}}, true)
+
+	return &TestVectorFile{Version: testVectorsVersion, Vectors: b.vectors}
+}
+
+// newTestVectorBuilder indexes the IDL's structs and enums by full and base name
+func newTestVectorBuilder(idl *parser.IDL) *testVectorBuilder {
+	b := &testVectorBuilder{
+		structs: make(map[string]*parser.Struct),
+		enums:   make(map[string]*parser.Enum),
+	}
+	for _, s := range idl.Structs {
+		b.structs[s.Name] = s
+		b.structs[GetBaseName(s.Name)] = s
+	}
+	for _, e := range idl.Enums {
+		b.enums[e.Name] = e
+		b.enums[GetBaseName(e.Name)] = e
+	}
+	return b
+}
+
+// writeTestVectorsIfRequested writes testvectors.json to outputDir when the
+// -generate-test-vectors flag is set, and reports whether it did
+func writeTestVectorsIfRequested(idl *parser.IDL, fs *flag.FlagSet, outputDir string) (bool, error) {
+	f := fs.Lookup("generate-test-vectors")
+	if f == nil || f.Value.String() != "true" {
+		return false, nil
+	}
+
+	data, err := json.MarshalIndent(BuildTestVectors(idl), "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal test vectors: %w", err)
+	}
+	path := filepath.Join(outputDir, testVectorsFileName)
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", testVectorsFileName, err)
+	}
+	return true, nil
+}
+
+func (b *testVectorBuilder) id() int {
+	b.nextID++
+	return b.nextID
+}
+
+func (b *testVectorBuilder) add(name string, request map[string]interface{}, response map[string]interface{}, nullID bool) {
+	response["jsonrpc"] = "2.0"
+	if nullID {
+		response["id"] = nil
+	} else {
+		response["id"] = request["id"]
+	}
+	b.vectors = append(b.vectors, TestVector{Name: name, Request: request, Response: response})
+}
+
+func (b *testVectorBuilder) request(method string, params []interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  params,
+		"id":      b.id(),
+	}
+}
+
+func (b *testVectorBuilder) addErrorVector(name string, method string, params []interface{}, code int) {
+	b.add(name, b.request(method, params), map[string]interface{}{
+		"error": map[string]interface{}{"code": code},
+	}, false)
+}
+
+func (b *testVectorBuilder) addMethodVectors(iface *parser.Interface, method *parser.Method) {
+	rpcMethod := iface.RPCName(method)
+
+	valid := make([]interface{}, len(method.Parameters))
+	for i, param := range method.Parameters {
+		valid[i] = b.validValue(param.Type)
+	}
+
+	// Success cases: exact results for the conformance contract, otherwise any result
+	known := false
+	for _, cr := range conformResults[iface.Name+"."+method.Name] {
+		if len(cr.params) != len(method.Parameters) {
+			continue
+		}
+		known = true
+		b.add(rpcMethod+"/"+cr.name, b.request(rpcMethod, cr.params),
+			map[string]interface{}{"result": cr.result}, false)
+	}
+	if !known {
+		b.add(rpcMethod+"/valid", b.request(rpcMethod, valid), map[string]interface{}{}, false)
+	}
+
+	// Parameter count mismatches
+	tooMany := append(append([]interface{}{}, valid...), nil)
+	b.addErrorVector(rpcMethod+"/too-many-params", rpcMethod, tooMany, -32602)
+	if len(method.Parameters) == 0 {
+		return
+	}
+	if required := method.RequiredParams(); required < len(method.Parameters) {
+		// Trailing optional parameters may be left out
+		b.add(rpcMethod+"/omitted-optional-params", b.request(rpcMethod, valid[:required]), map[string]interface{}{}, false)
+	}
+	if method.RequiredParams() > 0 {
+		b.addErrorVector(rpcMethod+"/missing-params", rpcMethod, []interface{}{}, -32602)
+	}
+
+	// Invalid first parameter
+	first := method.Parameters[0]
+	if !first.Optional {
+		b.addErrorVector(rpcMethod+"/null-param", rpcMethod, replaceParam(valid, 0, nil), -32602)
+	}
+	if wrong, ok := b.wrongValue(first.Type); ok {
+		b.addErrorVector(rpcMethod+"/wrong-type", rpcMethod, replaceParam(valid, 0, wrong), -32602)
+	}
+	if first.Type.IsBuiltIn() && first.Type.BuiltIn == "int" {
+		// Under the default lenient number policy an int may be written as 1.0
+		b.add(rpcMethod+"/int-written-as-float", b.request(rpcMethod, replaceParam(valid, 0, json.Number("1.0"))), map[string]interface{}{}, false)
+	}
+	if s := b.structFor(first.Type); s != nil {
+		for _, field := range parser.ResolveStructFields(s, b.structs) {
+			if field.Optional {
+				continue
+			}
+			obj := b.validValue(first.Type).(map[string]interface{})
+			delete(obj, field.Name)
+			b.addErrorVector(rpcMethod+"/missing-field-"+field.Name, rpcMethod, replaceParam(valid, 0, obj), -32602)
+			break
+		}
+	}
+}
+
+func replaceParam(params []interface{}, i int, value interface{}) []interface{} {
+	out := append([]interface{}{}, params...)
+	out[i] = value
+	return out
+}
+
+func (b *testVectorBuilder) structFor(t *parser.Type) *parser.Struct {
+	if !t.IsUserDefined() {
+		return nil
+	}
+	return b.structs[t.UserDefined]
+}
+
+// validValue returns a canonical value of type t. Optional struct fields are omitted.
+func (b *testVectorBuilder) validValue(t *parser.Type) interface{} {
+	switch {
+	case t.IsBuiltIn():
+		switch t.BuiltIn {
+		case "string":
+			return "test"
+		case "int":
+			return 1
+		case "float":
+			return 1.5
+		case "bool":
+			return true
+		}
+	case t.IsArray():
+		return []interface{}{b.validValue(t.Array)}
+	case t.IsMap():
+		return map[string]interface{}{"key": b.validValue(t.MapValue)}
+	case t.IsUserDefined():
+		if e, ok := b.enums[t.UserDefined]; ok {
+			if len(e.Values) > 0 {
+				return e.Values[0].Name
+			}
+			return ""
+		}
+		if s, ok := b.structs[t.UserDefined]; ok {
+			obj := make(map[string]interface{})
+			for _, field := range parser.ResolveStructFields(s, b.structs) {
+				if !field.Optional {
+					obj[field.Name] = b.validValue(field.Type)
+				}
+			}
+			return obj
+		}
+	}
+	return nil
+}
+
+// wrongValue returns a value that must fail validation against t
+func (b *testVectorBuilder) wrongValue(t *parser.Type) (interface{}, bool) {
+	switch {
+	case t.IsBuiltIn():
+		switch t.BuiltIn {
+		case "string":
+			return 123, true
+		case "int":
+			return 1.5, true
+		case "float":
+			return "1.5", true
+		case "bool":
+			return "true", true
+		}
+	case t.IsArray():
+		return "not-an-array", true
+	case t.IsMap():
+		return []interface{}{"not-a-map"}, true
+	case t.IsUserDefined():
+		if e, ok := b.enums[t.UserDefined]; ok {
+			invalid := "not-an-enum-value"
+			for _, v := range e.Values {
+				if v.Name == invalid {
+					return nil, false
+				}
+			}
+			return invalid, true
+		}
+		if _, ok := b.structs[t.UserDefined]; ok {
+			return "not-a-struct", true
+		}
+	}
+	return nil, false
+}
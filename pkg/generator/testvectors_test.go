@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func testVectorIDL() *parser.IDL {
+	return &parser.IDL{
+		Interfaces: []*parser.Interface{
+			{
+				Name: "A",
+				Methods: []*parser.Method{
+					{
+						Name: "add",
+						Parameters: []*parser.Parameter{
+							{Name: "a", Type: &parser.Type{BuiltIn: "int"}},
+							{Name: "b", Type: &parser.Type{BuiltIn: "int"}},
+						},
+						ReturnType: &parser.Type{BuiltIn: "int"},
+					},
+					{
+						Name:       "save",
+						Parameters: []*parser.Parameter{{Name: "p", Type: &parser.Type{UserDefined: "Person"}}},
+						ReturnType: &parser.Type{BuiltIn: "bool"},
+					},
+				},
+			},
+		},
+		Structs: []*parser.Struct{
+			{
+				Name:   "Base",
+				Fields: []*parser.Field{{Name: "id", Type: &parser.Type{BuiltIn: "string"}}},
+			},
+			{
+				Name:    "Person",
+				Extends: "Base",
+				Fields: []*parser.Field{
+					{Name: "role", Type: &parser.Type{UserDefined: "Role"}},
+					{Name: "email", Type: &parser.Type{BuiltIn: "string"}, Optional: true},
+				},
+			},
+		},
+		Enums: []*parser.Enum{
+			{Name: "Role", Values: []*parser.EnumValue{{Name: "admin"}, {Name: "user"}}},
+		},
+	}
+}
+
+func findTestVector(t *testing.T, file *TestVectorFile, name string) TestVector {
+	t.Helper()
+	for _, v := range file.Vectors {
+		if v.Name == name {
+			return v
+		}
+	}
+	t.Fatalf("vector %s not found", name)
+	return TestVector{}
+}
+
+func TestBuildTestVectors(t *testing.T) {
+	file := BuildTestVectors(testVectorIDL())
+
+	add := findTestVector(t, file, "A.add/add")
+	if add.Response["result"] != 5 {
+		t.Errorf("expected conformance result 5 for A.add, got %v", add.Response["result"])
+	}
+	if add.Response["id"] != add.Request["id"] {
+		t.Errorf("expected response id to match request id")
+	}
+
+	wrong := findTestVector(t, file, "A.add/wrong-type")
+	if params := wrong.Request["params"].([]interface{}); params[0] != 1.5 {
+		t.Errorf("expected fractional number for int param, got %v", params[0])
+	}
+
+	asFloat := findTestVector(t, file, "A.add/int-written-as-float")
+	if body, _ := json.Marshal(asFloat.Request["params"]); string(body) != "[1.0,1]" {
+		t.Errorf("expected int param written as 1.0, got %s", body)
+	}
+	if _, ok := asFloat.Response["error"]; ok {
+		t.Errorf("expected int written as float to be accepted, got %v", asFloat.Response)
+	}
+
+	valid := findTestVector(t, file, "A.save/valid")
+	if _, ok := valid.Response["result"]; ok {
+		t.Errorf("expected no exact result for a method outside the conformance contract")
+	}
+	person := valid.Request["params"].([]interface{})[0].(map[string]interface{})
+	if person["id"] != "test" || person["role"] != "admin" {
+		t.Errorf("expected inherited and enum fields to be populated, got %v", person)
+	}
+	if _, ok := person["email"]; ok {
+		t.Errorf("expected optional field to be omitted, got %v", person)
+	}
+
+	missing := findTestVector(t, file, "A.save/missing-field-id")
+	if code := missing.Response["error"].(map[string]interface{})["code"]; code != -32602 {
+		t.Errorf("expected -32602 for missing field, got %v", code)
+	}
+
+	version := findTestVector(t, file, "invalid-jsonrpc-version")
+	if id, ok := version.Response["id"]; !ok || id != nil {
+		t.Errorf("expected null id for invalid request, got %v", id)
+	}
+
+	ids := make(map[interface{}]bool)
+	for _, v := range file.Vectors {
+		if ids[v.Request["id"]] {
+			t.Errorf("duplicate request id %v", v.Request["id"])
+		}
+		ids[v.Request["id"]] = true
+	}
+}
+
+func TestWriteTestVectorsIfRequested(t *testing.T) {
+	tmpDir := t.TempDir()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("generate-test-vectors", false, "generate test vectors")
+
+	written, err := writeTestVectorsIfRequested(testVectorIDL(), fs, tmpDir)
+	if err != nil || written {
+		t.Fatalf("expected no file without the flag, got written=%v err=%v", written, err)
+	}
+
+	if err := fs.Set("generate-test-vectors", "true"); err != nil {
+		t.Fatalf("failed to set generate-test-vectors flag: %v", err)
+	}
+	if _, err := writeTestVectorsIfRequested(testVectorIDL(), fs, tmpDir); err != nil {
+		t.Fatalf("failed to write test vectors: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, testVectorsFileName))
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", testVectorsFileName, err)
+	}
+	var file TestVectorFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("invalid JSON in %s: %v", testVectorsFileName, err)
+	}
+	if file.Version != testVectorsVersion || len(file.Vectors) == 0 {
+		t.Errorf("unexpected test vector file: version=%d vectors=%d", file.Version, len(file.Vectors))
+	}
+}
@@ -1,7 +1,6 @@
 package generator
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -9,6 +8,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/coopernurse/pulserpc/pkg/naming"
 	"github.com/coopernurse/pulserpc/pkg/parser"
 	"github.com/coopernurse/pulserpc/pkg/runtime"
 )
@@ -34,10 +34,18 @@ func (p *TSClientServer) RegisterFlags(fs *flag.FlagSet) {
 	if fs.Lookup("base-dir") == nil {
 		fs.String("base-dir", "", "Base directory for namespace packages/modules (defaults to -dir if not specified)")
 	}
+	registerNamespaceDirsFlag(fs)
 }
 
 // Generate generates TypeScript HTTP server and client code from the parsed IDL
-func (p *TSClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
+func (p *TSClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) (err error) {
+	ts, err := templatesFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	defer recoverTemplateError(&err)
+	start := repoFilesStart()
+
 	// Access the -dir flag value
 	dirFlag := fs.Lookup("dir")
 	outputDir := ""
@@ -58,6 +66,10 @@ func (p *TSClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 	if baseDirFlag != nil && baseDirFlag.Value.String() != "" {
 		baseDir = baseDirFlag.Value.String()
 	}
+	style, err := parseCodeStyle(fs, "ts")
+	if err != nil {
+		return err
+	}
 
 	// Build type registries
 	structMap := make(map[string]*parser.Struct)
@@ -81,54 +93,142 @@ func (p *TSClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 
 	// Group types by namespace
 	namespaceMap := GroupTypesByNamespace(idl)
+	nsDirs, err := loadNamespaceDirs(fs, baseDir, namespaceMap)
+	if err != nil {
+		return err
+	}
 
 	// Generate one file per namespace
 	for namespace, types := range namespaceMap {
 		if namespace == "" {
 			continue // Skip types without namespace (shouldn't happen with required namespaces)
 		}
-		namespaceCode := generateNamespaceTs(namespace, types)
-		namespacePath := filepath.Join(baseDir, namespace+".ts")
-		if err := os.WriteFile(namespacePath, []byte(namespaceCode), 0644); err != nil {
+		namespaceCode := generateNamespaceTs(ts, namespace, types)
+		namespacePath := filepath.Join(nsDirs.Dir(namespace), namespace+".ts")
+		if err := os.MkdirAll(filepath.Dir(namespacePath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s.ts: %w", namespace, err)
+		}
+		if err := writeGeneratedFile(namespacePath, []byte(namespaceCode)); err != nil {
 			return fmt.Errorf("failed to write %s.ts: %w", namespace, err)
 		}
 	}
 
-	// Calculate relative path from outputDir to baseDir for imports
-	relPathToBase, err := filepath.Rel(outputDir, baseDir)
-	if err != nil {
-		relPathToBase = baseDir // Fallback to absolute path if relative calculation fails
-	}
-	// Normalize the path (use forward slashes for TypeScript imports)
-	relPathToBase = filepath.ToSlash(relPathToBase)
-	if relPathToBase == "." {
-		relPathToBase = ""
-	} else if relPathToBase != "" {
-		relPathToBase = relPathToBase + "/"
+	// Import paths of the namespace modules from the files in outputDir
+	nsImports := make(map[string]string, len(namespaceMap))
+	for namespace := range namespaceMap {
+		nsImports[namespace] = nsDirs.ModulePath(outputDir, namespace)
 	}
 
 	// Generate server.ts
-	serverCode := generateServerTs(idl, structMap, enumMap, interfaceMap, packagePrefix, namespaceMap, relPathToBase)
+	idlDoc, err := newIDLJSONDocument(idl, fs)
+	if err != nil {
+		return err
+	}
+	serverCode := generateServerTs(idl, structMap, enumMap, interfaceMap, packagePrefix, namespaceMap, nsImports, faultInjectionRequested(fs), adminEndpointRequested(fs), idlDoc)
 	serverPath := filepath.Join(outputDir, "server.ts")
-	if err := os.WriteFile(serverPath, []byte(serverCode), 0644); err != nil {
+	if err := writeGeneratedFile(serverPath, []byte(serverCode)); err != nil {
 		return fmt.Errorf("failed to write server.ts: %w", err)
 	}
 
+	// Generate methods.ts, shared by the client and the server
+	methodsCode := generateMethodTableTs(idl.Interfaces, packagePrefix)
+	if err := writeGeneratedFile(filepath.Join(outputDir, "methods.ts"), []byte(methodsCode)); err != nil {
+		return fmt.Errorf("failed to write methods.ts: %w", err)
+	}
+
 	// Generate client.ts
-	clientCode := generateClientTs(idl, structMap, enumMap, interfaceMap, packagePrefix, namespaceMap, relPathToBase)
+	clientCode := generateClientTs(idl, structMap, enumMap, interfaceMap, packagePrefix, namespaceMap, nsImports)
 	clientPath := filepath.Join(outputDir, "client.ts")
-	if err := os.WriteFile(clientPath, []byte(clientCode), 0644); err != nil {
+	if err := writeGeneratedFile(clientPath, []byte(clientCode)); err != nil {
 		return fmt.Errorf("failed to write client.ts: %w", err)
 	}
 
 	// Write IDL JSON document for pulserpc-idl RPC method
-	jsonData, err := json.MarshalIndent(idl, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal IDL to JSON: %w", err)
+	if err := idlDoc.Write(outputDir); err != nil {
+		return err
 	}
-	jsonPath := filepath.Join(outputDir, "idl.json")
-	if err := os.WriteFile(jsonPath, jsonData, 0644); err != nil {
-		return fmt.Errorf("failed to write idl.json: %w", err)
+
+	// Generate discovery.ts next to the client
+	discoveryCode := ts.renderString("ts/discovery.ts.tmpl", discoveryView{
+		Transport:      applyPackagePrefix("Transport", packagePrefix),
+		HTTPTransport:  applyPackagePrefix("HTTPTransport", packagePrefix),
+		CallOptions:    applyPackagePrefix("CallOptions", packagePrefix),
+		RequestSigner:  applyPackagePrefix("RequestSigner", packagePrefix),
+		TransportError: applyPackagePrefix("TransportError", packagePrefix),
+		Endpoint:       applyPackagePrefix("Endpoint", packagePrefix),
+		Resolver:       applyPackagePrefix("Resolver", packagePrefix),
+		SRVResolver:    applyPackagePrefix("SRVResolver", packagePrefix),
+		ClassName:      applyPackagePrefix("DiscoveryTransport", packagePrefix),
+	})
+	if err := writeGeneratedFile(filepath.Join(outputDir, "discovery.ts"), []byte(discoveryCode)); err != nil {
+		return fmt.Errorf("failed to write discovery.ts: %w", err)
+	}
+
+	// Generate signing.ts, used by both the client and the server
+	signingCode := ts.renderString("ts/signing.ts.tmpl", signingView{
+		RequestSigner:   applyPackagePrefix("RequestSigner", packagePrefix),
+		RequestVerifier: applyPackagePrefix("RequestVerifier", packagePrefix),
+	})
+	if err := writeGeneratedFile(filepath.Join(outputDir, "signing.ts"), []byte(signingCode)); err != nil {
+		return fmt.Errorf("failed to write signing.ts: %w", err)
+	}
+
+	// Generate retry.ts next to the client
+	retryCode := ts.renderString("ts/retry.ts.tmpl", retryView{
+		Methods:            idempotentMethods(idl),
+		CallOptions:        applyPackagePrefix("CallOptions", packagePrefix),
+		Transport:          applyPackagePrefix("Transport", packagePrefix),
+		TransportError:     applyPackagePrefix("TransportError", packagePrefix),
+		IdempotentMethods:  applyPackagePrefix("IDEMPOTENT_METHODS", packagePrefix),
+		RetryPolicy:        applyPackagePrefix("RetryPolicy", packagePrefix),
+		DefaultRetryPolicy: applyPackagePrefix("DEFAULT_RETRY_POLICY", packagePrefix),
+		ClassName:          applyPackagePrefix("RetryTransport", packagePrefix),
+	})
+	if err := writeGeneratedFile(filepath.Join(outputDir, "retry.ts"), []byte(retryCode)); err != nil {
+		return fmt.Errorf("failed to write retry.ts: %w", err)
+	}
+
+	// Generate shadow.ts next to the client
+	if shadowClientRequested(fs) {
+		shadowCode := ts.renderString("ts/shadow.ts.tmpl", shadowView{
+			Transport: applyPackagePrefix("Transport", packagePrefix),
+			ClassName: applyPackagePrefix("ShadowTransport", packagePrefix),
+			Mismatch:  applyPackagePrefix("ShadowMismatch", packagePrefix),
+		})
+		if err := writeGeneratedFile(filepath.Join(outputDir, "shadow.ts"), []byte(shadowCode)); err != nil {
+			return fmt.Errorf("failed to write shadow.ts: %w", err)
+		}
+	}
+
+	// Generate outbox.ts next to the client
+	if outboxClientRequested(fs) {
+		outboxCode := ts.renderString("ts/outbox.ts.tmpl", outboxView{
+			CallOptions: applyPackagePrefix("CallOptions", packagePrefix),
+			Transport:   applyPackagePrefix("Transport", packagePrefix),
+			ClassName:   applyPackagePrefix("OutboxTransport", packagePrefix),
+			QueuedError: applyPackagePrefix("CallQueuedError", packagePrefix),
+		})
+		if err := writeGeneratedFile(filepath.Join(outputDir, "outbox.ts"), []byte(outboxCode)); err != nil {
+			return fmt.Errorf("failed to write outbox.ts: %w", err)
+		}
+	}
+
+	// Generate patch.ts next to the client
+	if patchHelpersRequested(fs) {
+		view := patchView{Patch: applyPackagePrefix("Patch", packagePrefix)}
+		for _, ns := range sortedNamespaces(namespaceMap) {
+			view.Registries = append(view.Registries, patchRegistry{Alias: strings.ToUpper(ns), Path: nsImports[ns]})
+		}
+		patchCode := ts.renderString("ts/patch.ts.tmpl", view)
+		if err := writeGeneratedFile(filepath.Join(outputDir, "patch.ts"), []byte(patchCode)); err != nil {
+			return fmt.Errorf("failed to write patch.ts: %w", err)
+		}
+	}
+
+	// Write testvectors.json if -generate-test-vectors is set
+	hasTestVectors, err := writeTestVectorsIfRequested(idl, fs, outputDir)
+	if err != nil {
+		return err
 	}
 
 	// Check if generate-test-files flag is set
@@ -138,23 +238,54 @@ func (p *TSClientServer) Generate(idl *parser.IDL, fs *flag.FlagSet) error {
 	// Generate test server and client if flag is set
 	if generateTestServer {
 		// Generate test_server.ts
-		testServerCode := generateTestServerTs(idl, structMap, enumMap, interfaceMap, packagePrefix, namespaceMap, relPathToBase)
+		testServerCode := generateTestServerTs(idl, structMap, enumMap, interfaceMap, packagePrefix, namespaceMap, nsImports, faultInjectionRequested(fs), adminEndpointRequested(fs))
 		testServerPath := filepath.Join(outputDir, "test_server.ts")
-		if err := os.WriteFile(testServerPath, []byte(testServerCode), 0644); err != nil {
+		if err := writeGeneratedFile(testServerPath, []byte(testServerCode)); err != nil {
 			return fmt.Errorf("failed to write test_server.ts: %w", err)
 		}
 
 		// Generate test_client.ts
-		testClientCode := generateTestClientTs(idl, structMap, enumMap, interfaceMap, packagePrefix, namespaceMap, relPathToBase)
+		testClientCode := generateTestClientTs(idl, structMap, enumMap, interfaceMap, packagePrefix, namespaceMap, nsImports, hasTestVectors)
 		testClientPath := filepath.Join(outputDir, "test_client.ts")
-		if err := os.WriteFile(testClientPath, []byte(testClientCode), 0644); err != nil {
+		if err := writeGeneratedFile(testClientPath, []byte(testClientCode)); err != nil {
 			return fmt.Errorf("failed to write test_client.ts: %w", err)
 		}
 	}
 
+	// Generate node:test contract tests from the IDL examples
+	if view := buildContractView(idl, naming.LowerFirst); contractTestsRequested(fs) && len(view.Interfaces) > 0 {
+		contractCode := ts.renderString("ts/contract.test.ts.tmpl", tsContractView{
+			HTTPTransport: applyPackagePrefix("HTTPTransport", packagePrefix),
+			contractView:  view,
+		})
+		if err := writeGeneratedFile(filepath.Join(outputDir, "contract.test.ts"), []byte(contractCode)); err != nil {
+			return fmt.Errorf("failed to write contract.test.ts: %w", err)
+		}
+	}
+
+	if err := restyleGeneratedFiles("ts", style, append([]string{outputDir}, nsDirs.All()...)...); err != nil {
+		return err
+	}
+
+	// The generated TypeScript code needs only Node.js, so the SBOM lists just the runtime
+	if sbomRequested(fs) {
+		if err := writeSBOM(outputDir, idl, "ts", "pulserpc", nil); err != nil {
+			return err
+		}
+	}
+
+	if repoFilesRequested(fs) {
+		return writeRepoFiles("ts", style, start, append([]string{outputDir}, nsDirs.All()...)...)
+	}
 	return nil
 }
 
+// tsContractView is the view model for contract.test.ts
+type tsContractView struct {
+	HTTPTransport string
+	contractView
+}
+
 // copyRuntimeFiles copies the TypeScript runtime library files to the output directory
 // Uses embedded runtime files from the binary
 func (p *TSClientServer) copyRuntimeFiles(outputDir string) error {
@@ -162,67 +293,8 @@ func (p *TSClientServer) copyRuntimeFiles(outputDir string) error {
 }
 
 // generateNamespaceTs generates a TypeScript file for a single namespace
-func generateNamespaceTs(namespace string, types *NamespaceTypes) string {
-	var sb strings.Builder
-
-	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
-	sb.WriteString("// Type definitions (TypeScript types, erased at runtime)\n")
-	sb.WriteString("interface TypeDef {\n")
-	sb.WriteString("  builtIn?: string;\n")
-	sb.WriteString("  array?: TypeDef;\n")
-	sb.WriteString("  mapValue?: TypeDef;\n")
-	sb.WriteString("  userDefined?: string;\n")
-	sb.WriteString("}\n")
-	sb.WriteString("interface StructDef {\n")
-	sb.WriteString("  extends?: string;\n")
-	sb.WriteString("  fields: Array<{ name: string; type: TypeDef; optional?: boolean }>;\n")
-	sb.WriteString("}\n")
-	sb.WriteString("interface EnumDef {\n")
-	sb.WriteString("  values: Array<{ name: string }>;\n")
-	sb.WriteString("}\n")
-	sb.WriteString("type StructMap = { [key: string]: StructDef };\n")
-	sb.WriteString("type EnumMap = { [key: string]: EnumDef };\n\n")
-
-	// Generate IDL-specific type definitions for this namespace
-	sb.WriteString(fmt.Sprintf("// IDL-specific type definitions for namespace: %s\n", namespace))
-	sb.WriteString("const ALL_STRUCTS: StructMap = {\n")
-	for _, s := range types.Structs {
-		sb.WriteString(fmt.Sprintf("  '%s': {\n", s.Name))
-		if s.Extends != "" {
-			sb.WriteString(fmt.Sprintf("    extends: '%s',\n", s.Extends))
-		}
-		sb.WriteString("    fields: [\n")
-		for _, field := range s.Fields {
-			sb.WriteString("      {\n")
-			sb.WriteString(fmt.Sprintf("        name: '%s',\n", field.Name))
-			sb.WriteString("        type: ")
-			writeTypeDictTs(&sb, field.Type)
-			sb.WriteString(",\n")
-			if field.Optional {
-				sb.WriteString("        optional: true,\n")
-			}
-			sb.WriteString("      },\n")
-		}
-		sb.WriteString("    ],\n")
-		sb.WriteString("  },\n")
-	}
-	sb.WriteString("};\n\n")
-
-	sb.WriteString("const ALL_ENUMS: EnumMap = {\n")
-	for _, e := range types.Enums {
-		sb.WriteString(fmt.Sprintf("  '%s': {\n", e.Name))
-		sb.WriteString("    values: [\n")
-		for _, val := range e.Values {
-			sb.WriteString(fmt.Sprintf("      { name: '%s' },\n", val.Name))
-		}
-		sb.WriteString("    ],\n")
-		sb.WriteString("  },\n")
-	}
-	sb.WriteString("};\n\n")
-	sb.WriteString("// Export for CommonJS compatibility\n")
-	sb.WriteString("export { ALL_STRUCTS, ALL_ENUMS };\n")
-
-	return sb.String()
+func generateNamespaceTs(ts *templateSet, namespace string, types *NamespaceTypes) string {
+	return ts.renderString("ts/namespace.ts.tmpl", newTypeRegistryView(namespace, types, writeTypeDictTs))
 }
 
 // writeTypeDictTs writes a type definition as a TypeScript object
@@ -251,7 +323,7 @@ func applyPackagePrefix(name, prefix string) string {
 }
 
 // generateServerTs generates the server.ts file with HTTP server and interface stubs
-func generateServerTs(idl *parser.IDL, _ map[string]*parser.Struct, _ map[string]*parser.Enum, _ map[string]*parser.Interface, packagePrefix string, namespaceMap map[string]*NamespaceTypes, relPathToBase string) string {
+func generateServerTs(idl *parser.IDL, _ map[string]*parser.Struct, _ map[string]*parser.Enum, _ map[string]*parser.Interface, packagePrefix string, namespaceMap map[string]*NamespaceTypes, nsImports map[string]string, faults bool, admin bool, idlDoc idlJSONDocument) string {
 	var sb strings.Builder
 
 	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
@@ -259,8 +331,34 @@ func generateServerTs(idl *parser.IDL, _ map[string]*parser.Struct, _ map[string
 	sb.WriteString("import * as http from 'http';\n")
 	sb.WriteString("import * as fs from 'fs';\n")
 	sb.WriteString("import * as path from 'path';\n")
+	sb.WriteString("import { canonicalJson } from './pulserpc/canonical';\n")
+	sb.WriteString("import { runWithDeadline } from './pulserpc/deadline';\n")
+	sb.WriteString("import { NumberPolicy, attachRequestLiterals, checkRequestIntLiterals, supportsNumberLiterals } from './pulserpc/numbers';\n")
 	sb.WriteString("import { RPCError } from './pulserpc/rpc';\n")
 	sb.WriteString("import { validateType } from './pulserpc/validation';\n")
+	if usesEncryptedFields(idl) {
+		sb.WriteString("import { FieldCipher, decryptFields, encryptFields } from './pulserpc/encryption';\n")
+	}
+	if faults {
+		sb.WriteString("import { Fault, FaultConfig } from './pulserpc/faults';\n")
+	}
+	if admin {
+		sb.WriteString("import { MethodMetrics } from './pulserpc/metrics';\n")
+	}
+	fmt.Fprintf(&sb, "import { %s } from './methods';\n", applyPackagePrefix("METHOD_DEFS", packagePrefix))
+	if usesAsyncMethods(idl.Interfaces) {
+		sb.WriteString("import { randomBytes } from 'crypto';\n")
+	}
+	if admin {
+		sb.WriteString("import { timingSafeEqual } from 'crypto';\n")
+	}
+	if usesCachedMethods(idl.Interfaces) {
+		sb.WriteString("import { createHash } from 'crypto';\n")
+	}
+	compressed := usesCompressedMethods(idl.Interfaces)
+	if compressed {
+		sb.WriteString("import { gzipSync } from 'zlib';\n")
+	}
 
 	// Import from namespace files
 	namespaces := make([]string, 0, len(namespaceMap))
@@ -272,13 +370,7 @@ func generateServerTs(idl *parser.IDL, _ map[string]*parser.Struct, _ map[string
 	// Sort namespaces for consistent output
 	sort.Strings(namespaces)
 	for _, ns := range namespaces {
-		importPath := relPathToBase + ns
-		if importPath == "" {
-			importPath = "./" + ns
-		} else if !strings.HasPrefix(importPath, ".") {
-			importPath = "./" + importPath
-		}
-		sb.WriteString(fmt.Sprintf("import { ALL_STRUCTS as %s_STRUCTS, ALL_ENUMS as %s_ENUMS } from '%s';\n", strings.ToUpper(ns), strings.ToUpper(ns), importPath))
+		sb.WriteString(fmt.Sprintf("import { ALL_STRUCTS as %s_STRUCTS, ALL_ENUMS as %s_ENUMS } from '%s';\n", strings.ToUpper(ns), strings.ToUpper(ns), nsImports[ns]))
 	}
 	sb.WriteString("\n")
 	sb.WriteString("// Inline type definitions\n")
@@ -312,10 +404,68 @@ func generateServerTs(idl *parser.IDL, _ map[string]*parser.Struct, _ map[string
 	}
 	sb.WriteString("};\n\n")
 
+	writeContentTypeCheckTs(&sb)
+
+	// Generate GET bridge for [readonly] methods
+	writeRESTBridgeTs(&sb, idl.Interfaces)
+
 	// Generate interface stub abstract classes
 	for _, iface := range idl.Interfaces {
 		writeInterfaceStubTs(&sb, iface, packagePrefix)
 	}
+	if usesInterfaceInheritance(idl.Interfaces) {
+		sb.WriteString("// For each extended interface, the interfaces that inherit its methods\n")
+		sb.WriteString("const SUB_INTERFACES: Record<string, string[]> = {\n")
+		for _, inh := range subInterfaces(idl.Interfaces) {
+			fmt.Fprintf(&sb, "  '%s': ['%s'],\n", inh.Name, strings.Join(inh.Subs, "', '"))
+		}
+		sb.WriteString("};\n\n")
+	}
+	if usesWireNames(idl.Interfaces) {
+		writeWireMethodsTs(&sb, idl.Interfaces)
+	}
+	if compressed {
+		writeCompressionTs(&sb, idl.Interfaces)
+	}
+	writeServerCapabilitiesTs(&sb, idl.Interfaces)
+	if usesEncryptedFields(idl) {
+		writeEncryptedMethodsTs(&sb, idl)
+	}
+	if admin {
+		writeAdminConstantsTs(&sb, idl)
+	}
+
+	if usesAsyncMethods(idl.Interfaces) {
+		writeJobsTypesTs(&sb)
+	}
+
+	callStatsName := applyPackagePrefix("CallStats", packagePrefix)
+	sb.WriteString("// Payload sizes of one JSON-RPC call, as passed to the onCall hook\n")
+	fmt.Fprintf(&sb, "export interface %s {\n", callStatsName)
+	sb.WriteString("  method: string;\n")
+	sb.WriteString("  // Size of the JSON request (the query string for GET requests)\n")
+	sb.WriteString("  requestBytes: number;\n")
+	sb.WriteString("  // Size of the JSON response the method produced, before any response size limit\n")
+	sb.WriteString("  // is applied, or 0 for notifications\n")
+	sb.WriteString("  responseBytes: number;\n")
+	sb.WriteString("}\n\n")
+
+	metaCallName := applyPackagePrefix("ResponseMetaCall", packagePrefix)
+	sb.WriteString("// A successful call, as passed to the setResponseMeta hook\n")
+	fmt.Fprintf(&sb, "export interface %s {\n", metaCallName)
+	sb.WriteString("  method: string;\n")
+	sb.WriteString("  // The validated params, by position\n")
+	sb.WriteString("  params: any[];\n")
+	sb.WriteString("  // The value the handler returned\n")
+	sb.WriteString("  result: any;\n")
+	sb.WriteString("  // Milliseconds the handler took\n")
+	sb.WriteString("  elapsedMs: number;\n")
+	sb.WriteString("}\n\n")
+
+	verifierName := applyPackagePrefix("RequestVerifier", packagePrefix)
+	sb.WriteString("// Checks an incoming request before it is dispatched. body is the raw request body,\n")
+	sb.WriteString("// empty for GET requests. Throwing rejects the request with HTTP 401.\n")
+	fmt.Fprintf(&sb, "export type %s = (headers: http.IncomingHttpHeaders, body: Buffer) => void;\n\n", verifierName)
 
 	// Generate PulseRPCServer class
 	serverClassName := applyPackagePrefix("PulseRPCServer", packagePrefix)
@@ -323,35 +473,312 @@ func generateServerTs(idl *parser.IDL, _ map[string]*parser.Struct, _ map[string
 	sb.WriteString("  private host: string;\n")
 	sb.WriteString("  private port: number;\n")
 	sb.WriteString("  private handlers: Map<string, any>;\n")
-	sb.WriteString("  private server: http.Server | null;\n\n")
+	sb.WriteString("  private server: http.Server | null;\n")
+	sb.WriteString("  private strictContentType: boolean;\n")
+	sb.WriteString("  private numberPolicy: NumberPolicy = 'lenient';\n")
+	sb.WriteString("  private canonical = false;\n")
+	sb.WriteString("  private maxResponseBytes: Map<string, number>;\n")
+	fmt.Fprintf(&sb, "  private callHook: ((stats: %s) => void) | null;\n", callStatsName)
+	fmt.Fprintf(&sb, "  private metaHook: ((call: %s) => Record<string, any> | null | undefined) | null;\n", metaCallName)
+	fmt.Fprintf(&sb, "  private verifier: %s | null;\n", verifierName)
+	if usesEncryptedFields(idl) {
+		sb.WriteString("  private fieldCipher: FieldCipher | null = null;\n")
+	}
+	if faults {
+		sb.WriteString("  private faults: FaultConfig | null = null;\n")
+		sb.WriteString("  // Faults drawn for the calls of the message being handled, in order\n")
+		sb.WriteString("  private pendingFaults: Fault[] = [];\n")
+	}
+	if admin {
+		sb.WriteString("  // Set by enableAdmin\n")
+		sb.WriteString("  private metrics: MethodMetrics | null = null;\n")
+		sb.WriteString("  private adminToken = '';\n")
+	}
+	if usesAsyncMethods(idl.Interfaces) {
+		sb.WriteString("  // Jobs started by [async] methods, by job id\n")
+		sb.WriteString("  private jobs: Map<string, ServerJob> = new Map();\n")
+	}
+	sb.WriteString("\n")
 
 	sb.WriteString("  constructor(host: string = 'localhost', port: number = 8080) {\n")
 	sb.WriteString("    this.host = host;\n")
 	sb.WriteString("    this.port = port;\n")
 	sb.WriteString("    this.handlers = new Map();\n")
 	sb.WriteString("    this.server = null;\n")
+	sb.WriteString("    this.strictContentType = false;\n")
+	sb.WriteString("    this.maxResponseBytes = new Map();\n")
+	sb.WriteString("    this.callHook = null;\n")
+	sb.WriteString("    this.metaHook = null;\n")
+	sb.WriteString("    this.verifier = null;\n")
+	sb.WriteString("  }\n\n")
+
+	sb.WriteString("  // When strict, POST requests must declare application/json with no charset other than\n")
+	sb.WriteString("  // utf-8, or get HTTP 415. Otherwise, the default, any Content-Type is accepted.\n")
+	sb.WriteString("  setStrictContentType(strict: boolean): void {\n")
+	sb.WriteString("    this.strictContentType = strict;\n")
 	sb.WriteString("  }\n\n")
 
+	sb.WriteString("  // 'lenient' accepts int params written with a fraction or exponent, such as 2.0;\n")
+	sb.WriteString("  // 'strict' rejects them, which needs JSON.parse source text access (Node 21 and later)\n")
+	sb.WriteString("  setNumberPolicy(policy: NumberPolicy): void {\n")
+	sb.WriteString("    if (policy === 'strict' && !supportsNumberLiterals()) {\n")
+	sb.WriteString("      throw new Error('strict number policy needs JSON.parse source text access (Node 21 and later)');\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    this.numberPolicy = policy;\n")
+	sb.WriteString("  }\n\n")
+
+	writeCanonicalTs(&sb, "responses")
+
+	sb.WriteString("  // Limits the encoded response size of method ('Interface.method'), or of every method\n")
+	sb.WriteString("  // without its own limit if method is '*'. Larger responses are replaced by a -32001\n")
+	sb.WriteString("  // 'Response too large' error.\n")
+	sb.WriteString("  setMaxResponseBytes(method: string, limit: number): void {\n")
+	sb.WriteString("    this.maxResponseBytes.set(method, limit);\n")
+	sb.WriteString("  }\n\n")
+
+	sb.WriteString("  // Registers a hook that is invoked after every call with its request and response sizes\n")
+	fmt.Fprintf(&sb, "  onCall(hook: (stats: %s) => void): void {\n", callStatsName)
+	sb.WriteString("    this.callHook = hook;\n")
+	sb.WriteString("  }\n\n")
+
+	sb.WriteString("  // Registers a hook that returns metadata for a successful call, such as timings,\n")
+	sb.WriteString("  // pagination hints or warnings. Non-empty metadata is sent in the reserved 'meta'\n")
+	sb.WriteString("  // member of the response, next to the result, and clients read it with callWithMeta.\n")
+	fmt.Fprintf(&sb, "  setResponseMeta(hook: (call: %s) => Record<string, any> | null | undefined): void {\n", metaCallName)
+	sb.WriteString("    this.metaHook = hook;\n")
+	sb.WriteString("  }\n\n")
+
+	sb.WriteString("  // Installs a check that every request must pass before it is dispatched, such as\n")
+	sb.WriteString("  // hmacVerifier from signing.ts. Rejected requests get HTTP 401.\n")
+	fmt.Fprintf(&sb, "  setVerifier(verifier: %s): void {\n", verifierName)
+	sb.WriteString("    this.verifier = verifier;\n")
+	sb.WriteString("  }\n\n")
+
+	if usesEncryptedFields(idl) {
+		sb.WriteString("  // Sets the cipher that decrypts the [encrypted] fields of params and encrypts those\n")
+		sb.WriteString("  // of results. Calls that carry such fields fail without one.\n")
+		sb.WriteString("  setFieldCipher(cipher: FieldCipher): void {\n")
+		sb.WriteString("    this.fieldCipher = cipher;\n")
+		sb.WriteString("  }\n\n")
+	}
+
+	if faults {
+		sb.WriteString("  // Reads a fault config (see FaultConfig in pulserpc/faults.ts) and injects its latency,\n")
+		sb.WriteString("  // errors and malformed responses into every later call, so clients can be tested against\n")
+		sb.WriteString("  // a slow or unreliable server. [readonly] GET routes are served without faults.\n")
+		sb.WriteString("  loadFaults(file: string): void {\n")
+		sb.WriteString("    this.faults = FaultConfig.load(file);\n")
+		sb.WriteString("  }\n\n")
+	}
+
+	if admin {
+		writeAdminServerTs(&sb)
+	}
+
 	sb.WriteString("  register(interfaceName: string, instance: any): void {\n")
 	sb.WriteString("    this.handlers.set(interfaceName, instance);\n")
 	sb.WriteString("  }\n\n")
 
 	// Generate handleRequest method
-	writeServerHandleRequestTs(&sb, idl.Interfaces)
+	writeServerHandleRequestTs(&sb, idl, packagePrefix, admin, idlDoc)
+
+	sb.WriteString("  // Serves a [readonly] method over HTTP GET. The response body is the JSON-RPC\n")
+	sb.WriteString("  // response envelope; errors use a non-2xx status so they are not cached.\n")
+	if usesCachedMethods(idl.Interfaces) || compressed {
+		sb.WriteString("  private handleGetRequest(url: URL, route: ReadOnlyRoute, headers: http.IncomingHttpHeaders, res: http.ServerResponse): void {\n")
+	} else {
+		sb.WriteString("  private handleGetRequest(url: URL, route: ReadOnlyRoute, res: http.ServerResponse): void {\n")
+	}
+	sb.WriteString("    const params: any[] = [];\n")
+	sb.WriteString("    let response: any = null;\n")
+	sb.WriteString("    for (const paramDef of route.params) {\n")
+	if usesOptionalParams(idl.Interfaces) {
+		sb.WriteString("      if (paramDef.optional && !url.searchParams.has(paramDef.name)) {\n")
+		sb.WriteString("        params.push(null);\n")
+		sb.WriteString("        continue;\n")
+		sb.WriteString("      }\n")
+	}
+	sb.WriteString("      try {\n")
+	sb.WriteString("        params.push(bindQueryParam(url.searchParams.getAll(paramDef.name), paramDef.type));\n")
+	sb.WriteString("      } catch (err: any) {\n")
+	sb.WriteString("        response = this.errorResponse(null, -32602, 'Invalid params', `Query parameter ${paramDef.name}: ${err.message}`);\n")
+	sb.WriteString("        break;\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    if (response === null) {\n")
+	sb.WriteString("      response = this.handleRequest({ jsonrpc: '2.0', method: route.method, params, id: null });\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    const [sent, encoded] = this.encodeResponse(route.method, Buffer.byteLength(url.search.replace(/^\\?/, '')), response);\n")
+	sb.WriteString("    const status = sent.error ? restErrorStatus(sent.error.code) : 200;\n")
+	if usesCachedMethods(idl.Interfaces) {
+		sb.WriteString("    if (status === 200 && route.cacheControl && encoded !== null) {\n")
+		sb.WriteString("      const etag = responseETag(encoded);\n")
+		sb.WriteString("      const cacheHeaders = { 'ETag': etag, 'Cache-Control': route.cacheControl };\n")
+		sb.WriteString("      if (etagMatches(headers['if-none-match'], etag)) {\n")
+		sb.WriteString("        res.writeHead(304, cacheHeaders);\n")
+		sb.WriteString("        res.end();\n")
+		sb.WriteString("        return;\n")
+		sb.WriteString("      }\n")
+		if compressed {
+			sb.WriteString("      writeCompressible(headers, res, 200, { ...cacheHeaders, 'Content-Type': 'application/json' }, encoded, COMPRESSED_METHODS[route.method] ?? null);\n")
+		} else {
+			sb.WriteString("      res.writeHead(200, { ...cacheHeaders, 'Content-Type': 'application/json' });\n")
+			sb.WriteString("      res.end(encoded);\n")
+		}
+		sb.WriteString("      return;\n")
+		sb.WriteString("    }\n")
+	}
+	if compressed {
+		sb.WriteString("    if (encoded !== null) {\n")
+		sb.WriteString("      writeCompressible(headers, res, status, { 'Content-Type': 'application/json' }, encoded, COMPRESSED_METHODS[route.method] ?? null);\n")
+		sb.WriteString("      return;\n")
+		sb.WriteString("    }\n")
+	}
+	sb.WriteString("    res.writeHead(status, { 'Content-Type': 'application/json' });\n")
+	sb.WriteString("    res.end(encoded);\n")
+	sb.WriteString("  }\n\n")
+
+	sb.WriteString("  // Handles one JSON-RPC request and returns its encoded response, or null for notifications\n")
+	sb.WriteString("  private handleCall(requestJson: any, requestBytes: number): string | null {\n")
+	sb.WriteString("    const method = requestJson && typeof requestJson.method === 'string' ? requestJson.method : '';\n")
+	if faults {
+		sb.WriteString("    const fault = this.pendingFaults.shift();\n")
+		sb.WriteString("    if (fault === undefined) {\n")
+		sb.WriteString("      return this.encodeResponse(method, requestBytes, this.handleRequest(requestJson))[1];\n")
+		sb.WriteString("    }\n")
+		sb.WriteString("    let response: any = null;\n")
+		sb.WriteString("    if (!fault.error) {\n")
+		sb.WriteString("      response = this.handleRequest(requestJson);\n")
+		sb.WriteString("    } else if (requestJson && typeof requestJson === 'object' && 'id' in requestJson) {\n")
+		sb.WriteString("      response = this.errorResponse(requestJson.id, fault.error.code, fault.error.message);\n")
+		sb.WriteString("    }\n")
+		sb.WriteString("    const encoded = this.encodeResponse(method, requestBytes, response)[1];\n")
+		sb.WriteString("    if (encoded !== null && fault.malformed) {\n")
+		sb.WriteString("      return encoded.slice(0, Math.floor(encoded.length / 2));\n")
+		sb.WriteString("    }\n")
+		sb.WriteString("    return encoded;\n")
+		sb.WriteString("  }\n\n")
+
+		sb.WriteString("  // Draws the faults of the calls in a message and handles it after their combined latency.\n")
+		sb.WriteString("  // handleCall applies the drawn errors and malformed responses in order.\n")
+		sb.WriteString("  private withInjectedLatency(chunks: Buffer[], handle: () => void): void {\n")
+		sb.WriteString("    if (this.faults === null) {\n")
+		sb.WriteString("      handle();\n")
+		sb.WriteString("      return;\n")
+		sb.WriteString("    }\n")
+		sb.WriteString("    const faults: Fault[] = [];\n")
+		sb.WriteString("    try {\n")
+		sb.WriteString("      const data = JSON.parse(Buffer.concat(chunks).toString('utf8'));\n")
+		sb.WriteString("      for (const call of Array.isArray(data) ? data : [data]) {\n")
+		sb.WriteString("        faults.push(this.faults.draw(call && typeof call.method === 'string' ? call.method : ''));\n")
+		sb.WriteString("      }\n")
+		sb.WriteString("    } catch {\n")
+		sb.WriteString("      // Messages that are not valid JSON are answered without faults\n")
+		sb.WriteString("    }\n")
+		sb.WriteString("    const delayMs = faults.reduce((sum, fault) => sum + fault.delayMs, 0);\n")
+		sb.WriteString("    setTimeout(() => {\n")
+		sb.WriteString("      this.pendingFaults = faults;\n")
+		sb.WriteString("      try {\n")
+		sb.WriteString("        handle();\n")
+		sb.WriteString("      } finally {\n")
+		sb.WriteString("        this.pendingFaults = [];\n")
+		sb.WriteString("      }\n")
+		sb.WriteString("    }, delayMs);\n")
+		sb.WriteString("  }\n\n")
+	} else {
+		sb.WriteString("    return this.encodeResponse(method, requestBytes, this.handleRequest(requestJson))[1];\n")
+		sb.WriteString("  }\n\n")
+	}
+
+	sb.WriteString("  // Encodes the response of one call, replacing it with a -32001 error if it exceeds the\n")
+	sb.WriteString("  // method's response size limit, and reports the payload sizes to the onCall hook\n")
+	sb.WriteString("  private encodeResponse(method: string, requestBytes: number, response: any): [any, string | null] {\n")
+	sb.WriteString("    let encoded: string | null = null;\n")
+	sb.WriteString("    let size = 0;\n")
+	sb.WriteString("    if (response !== null && response !== undefined) {\n")
+	sb.WriteString("      encoded = this.stringify(response);\n")
+	sb.WriteString("      size = Buffer.byteLength(encoded);\n")
+	sb.WriteString("      const limit = this.maxResponseBytes.get(method) ?? this.maxResponseBytes.get('*');\n")
+	sb.WriteString("      if (limit !== undefined && size > limit) {\n")
+	sb.WriteString("        response = this.errorResponse(response.id, -32001, 'Response too large',\n")
+	sb.WriteString("          `Response of ${size} bytes exceeds the ${limit} byte limit for ${method}`);\n")
+	sb.WriteString("        encoded = this.stringify(response);\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    if (this.callHook !== null) {\n")
+	sb.WriteString("      this.callHook({ method, requestBytes, responseBytes: size });\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    return [response, encoded];\n")
+	sb.WriteString("  }\n\n")
+
+	sb.WriteString("  // Runs the verifier, if any, answering 401 when it rejects the request\n")
+	sb.WriteString("  private verify(headers: http.IncomingHttpHeaders, body: Buffer, res: http.ServerResponse): boolean {\n")
+	sb.WriteString("    if (this.verifier === null) {\n")
+	sb.WriteString("      return true;\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    try {\n")
+	sb.WriteString("      this.verifier(headers, body);\n")
+	sb.WriteString("      return true;\n")
+	sb.WriteString("    } catch (err: any) {\n")
+	sb.WriteString("      res.writeHead(401, { 'Content-Type': 'application/json' });\n")
+	sb.WriteString("      res.end(JSON.stringify(this.errorResponse(null, -32600, 'Invalid Request', err?.message || String(err))));\n")
+	sb.WriteString("      return false;\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("  }\n\n")
 
 	// Generate serveForever and shutdown methods
 	sb.WriteString("  serveForever(): void {\n")
 	sb.WriteString("    this.server = http.createServer((req, res) => {\n")
+	sb.WriteString("      if (req.method === 'GET') {\n")
+	sb.WriteString("        const url = new URL(req.url || '/', 'http://localhost');\n")
+	if admin {
+		fmt.Fprintf(&sb, "        if (url.pathname === '%s' && this.metrics) {\n", adminPath)
+		sb.WriteString("          this.handleAdmin(req.headers, res);\n")
+		sb.WriteString("          return;\n")
+		sb.WriteString("        }\n")
+	}
+	sb.WriteString("        const route = READONLY_ROUTES[url.pathname];\n")
+	sb.WriteString("        if (route) {\n")
+	sb.WriteString("          if (!this.verify(req.headers, Buffer.alloc(0), res)) {\n")
+	sb.WriteString("            return;\n")
+	sb.WriteString("          }\n")
+	if usesCachedMethods(idl.Interfaces) || compressed {
+		sb.WriteString("          runWithDeadline(req.headers, () => this.handleGetRequest(url, route, req.headers, res));\n")
+	} else {
+		sb.WriteString("          runWithDeadline(req.headers, () => this.handleGetRequest(url, route, res));\n")
+	}
+	sb.WriteString("          return;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("      }\n")
 	sb.WriteString("      if (req.method !== 'POST') {\n")
 	sb.WriteString("        res.writeHead(405, { 'Content-Type': 'application/json' });\n")
 	sb.WriteString("        res.end(JSON.stringify({ error: 'Method Not Allowed' }));\n")
 	sb.WriteString("        return;\n")
 	sb.WriteString("      }\n\n")
-	sb.WriteString("      let body = '';\n")
-	sb.WriteString("      req.on('data', (chunk) => { body += chunk.toString(); });\n")
-	sb.WriteString("      req.on('end', () => {\n")
+	sb.WriteString("      const problem = this.strictContentType ? checkContentType(req.headers['content-type']) : null;\n")
+	sb.WriteString("      if (problem !== null) {\n")
+	sb.WriteString("        res.writeHead(415, { 'Content-Type': 'application/json' });\n")
+	sb.WriteString("        res.end(JSON.stringify(this.errorResponse(null, -32600, 'Invalid Request', problem)));\n")
+	sb.WriteString("        return;\n")
+	sb.WriteString("      }\n\n")
+	sb.WriteString("      const chunks: Buffer[] = [];\n")
+	sb.WriteString("      req.on('data', (chunk: Buffer) => { chunks.push(chunk); });\n")
+	if faults {
+		sb.WriteString("      req.on('end', () => runWithDeadline(req.headers, () => this.withInjectedLatency(chunks, () => {\n")
+	} else {
+		sb.WriteString("      req.on('end', () => runWithDeadline(req.headers, () => {\n")
+	}
 	sb.WriteString("        try {\n")
-	sb.WriteString("          const data = JSON.parse(body);\n\n")
+	sb.WriteString("          // Decode once so multi-byte UTF-8 sequences split across chunks stay intact\n")
+	sb.WriteString("          const rawBody = Buffer.concat(chunks);\n")
+	sb.WriteString("          if (!this.verify(req.headers, rawBody, res)) {\n")
+	sb.WriteString("            return;\n")
+	sb.WriteString("          }\n")
+	sb.WriteString("          const body = rawBody.toString('utf8');\n")
+	sb.WriteString("          const data = JSON.parse(body);\n")
+	sb.WriteString("          if (this.numberPolicy === 'strict') {\n")
+	sb.WriteString("            attachRequestLiterals(data, body);\n")
+	sb.WriteString("          }\n\n")
 	sb.WriteString("          // Handle batch requests\n")
 	sb.WriteString("          if (Array.isArray(data)) {\n")
 	sb.WriteString("            if (data.length === 0) {\n")
@@ -359,10 +786,11 @@ func generateServerTs(idl *parser.IDL, _ map[string]*parser.Struct, _ map[string
 	sb.WriteString("              res.end(JSON.stringify({ error: 'Empty batch array' }));\n")
 	sb.WriteString("              return;\n")
 	sb.WriteString("            }\n")
-	sb.WriteString("            const responses: any[] = [];\n")
+	sb.WriteString("            const responses: string[] = [];\n")
 	sb.WriteString("            for (const req of data) {\n")
-	sb.WriteString("              const response = this.handleRequest(req);\n")
-	sb.WriteString("              if (response !== null && response !== undefined) {\n")
+	sb.WriteString("              // Batch members are measured by their own JSON encoding\n")
+	sb.WriteString("              const response = this.handleCall(req, Buffer.byteLength(JSON.stringify(req)));\n")
+	sb.WriteString("              if (response !== null) {\n")
 	sb.WriteString("                responses.push(response);\n")
 	sb.WriteString("              }\n")
 	sb.WriteString("            }\n")
@@ -370,17 +798,25 @@ func generateServerTs(idl *parser.IDL, _ map[string]*parser.Struct, _ map[string
 	sb.WriteString("              res.writeHead(204);\n")
 	sb.WriteString("              res.end();\n")
 	sb.WriteString("            } else {\n")
-	sb.WriteString("              res.writeHead(200, { 'Content-Type': 'application/json' });\n")
-	sb.WriteString("              res.end(JSON.stringify(responses));\n")
+	if compressed {
+		sb.WriteString("              writeCompressible(req.headers, res, 200, { 'Content-Type': 'application/json' }, '[' + responses.join(',') + ']', compressThreshold(data));\n")
+	} else {
+		sb.WriteString("              res.writeHead(200, { 'Content-Type': 'application/json' });\n")
+		sb.WriteString("              res.end('[' + responses.join(',') + ']');\n")
+	}
 	sb.WriteString("            }\n")
 	sb.WriteString("          } else {\n")
-	sb.WriteString("            const response = this.handleRequest(data);\n")
-	sb.WriteString("            if (response === null || response === undefined) {\n")
+	sb.WriteString("            const response = this.handleCall(data, rawBody.length);\n")
+	sb.WriteString("            if (response === null) {\n")
 	sb.WriteString("              res.writeHead(204);\n")
 	sb.WriteString("              res.end();\n")
 	sb.WriteString("            } else {\n")
-	sb.WriteString("              res.writeHead(200, { 'Content-Type': 'application/json' });\n")
-	sb.WriteString("              res.end(JSON.stringify(response));\n")
+	if compressed {
+		sb.WriteString("              writeCompressible(req.headers, res, 200, { 'Content-Type': 'application/json' }, response, compressThreshold(data));\n")
+	} else {
+		sb.WriteString("              res.writeHead(200, { 'Content-Type': 'application/json' });\n")
+		sb.WriteString("              res.end(response);\n")
+	}
 	sb.WriteString("            }\n")
 	sb.WriteString("          }\n")
 	sb.WriteString("        } catch (err: any) {\n")
@@ -388,7 +824,11 @@ func generateServerTs(idl *parser.IDL, _ map[string]*parser.Struct, _ map[string
 	sb.WriteString("          res.writeHead(200, { 'Content-Type': 'application/json' });\n")
 	sb.WriteString("          res.end(JSON.stringify(errorResponse));\n")
 	sb.WriteString("        }\n")
-	sb.WriteString("      });\n")
+	if faults {
+		sb.WriteString("      })));\n")
+	} else {
+		sb.WriteString("      }));\n")
+	}
 	sb.WriteString("    });\n\n")
 	sb.WriteString("    this.server.listen(this.port, this.host, () => {\n")
 	sb.WriteString(fmt.Sprintf("      console.log(`%s server listening on http://${this.host}:${this.port}`);\n", serverClassName))
@@ -406,6 +846,139 @@ func generateServerTs(idl *parser.IDL, _ map[string]*parser.Struct, _ map[string
 	return sb.String()
 }
 
+// writeContentTypeCheckTs generates the Content-Type validation used for POST requests
+func writeContentTypeCheckTs(sb *strings.Builder) {
+	sb.WriteString("// Validates the Content-Type of a JSON-RPC POST request in strict mode; returns a description of the problem or null\n")
+	sb.WriteString("function checkContentType(header: string | undefined): string | null {\n")
+	sb.WriteString("  if (!header) {\n")
+	sb.WriteString("    return 'Missing Content-Type header; expected application/json';\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  const parts = header.split(';').map((part) => part.trim());\n")
+	sb.WriteString("  const mediaType = parts[0].toLowerCase();\n")
+	sb.WriteString("  if (mediaType !== 'application/json' && !(mediaType.startsWith('application/') && mediaType.endsWith('+json'))) {\n")
+	sb.WriteString("    return `Unsupported Content-Type '${mediaType}'; expected application/json`;\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  for (const param of parts.slice(1)) {\n")
+	sb.WriteString("    const eq = param.indexOf('=');\n")
+	sb.WriteString("    if (eq >= 0 && param.slice(0, eq).trim().toLowerCase() === 'charset') {\n")
+	sb.WriteString("      const charset = param.slice(eq + 1).trim().replace(/^\"|\"$/g, '').toLowerCase();\n")
+	sb.WriteString("      if (charset !== 'utf-8' && charset !== 'utf8') {\n")
+	sb.WriteString("        return `Unsupported charset '${charset}'; expected utf-8`;\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  return null;\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeParamOptionsTs writes the optional flag and default of an optional
+// parameter as properties of its parameter definition
+func writeParamOptionsTs(sb *strings.Builder, indent string, param *parser.Parameter) {
+	if !param.Optional {
+		return
+	}
+	fmt.Fprintf(sb, "%soptional: true,\n", indent)
+	if value, ok := defaultJSON(param); ok {
+		fmt.Fprintf(sb, "%sdefault: %s,\n", indent, value)
+	}
+}
+
+// writeRESTBridgeTs generates the route table and helpers that serve [readonly] methods
+// over HTTP GET, binding parameters from the query string
+func writeRESTBridgeTs(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("interface ReadOnlyRoute {\n")
+	sb.WriteString("  method: string;\n")
+	if usesOptionalParams(interfaces) {
+		sb.WriteString("  params: Array<{ name: string; type: TypeDef; optional?: boolean }>;\n")
+	} else {
+		sb.WriteString("  params: Array<{ name: string; type: TypeDef }>;\n")
+	}
+	if usesCachedMethods(interfaces) {
+		sb.WriteString("  // Cache-Control of the responses of a [cache] method\n")
+		sb.WriteString("  cacheControl?: string;\n")
+	}
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// GET paths (/<Interface>/<method>) of [readonly] methods\n")
+	sb.WriteString("const READONLY_ROUTES: { [path: string]: ReadOnlyRoute } = {\n")
+	for _, route := range collectRESTRoutes(interfaces) {
+		fmt.Fprintf(sb, "  '%s': {\n", route.Path())
+		fmt.Fprintf(sb, "    method: '%s',\n", route.RPCMethod())
+		sb.WriteString("    params: [\n")
+		for _, param := range route.Method.Parameters {
+			fmt.Fprintf(sb, "      { name: '%s', type: ", param.Name)
+			writeTypeDictTs(sb, param.Type)
+			if param.Optional {
+				sb.WriteString(", optional: true")
+			}
+			sb.WriteString(" },\n")
+		}
+		sb.WriteString("    ],\n")
+		if control := cacheControl(route.Method); control != "" {
+			fmt.Fprintf(sb, "    cacheControl: '%s',\n", control)
+		}
+		sb.WriteString("  },\n")
+	}
+	sb.WriteString("};\n\n")
+
+	sb.WriteString("// Converts the query string values of one parameter; arrays use repeated keys (?id=1&id=2)\n")
+	sb.WriteString("function bindQueryParam(values: string[], typeDef: TypeDef): any {\n")
+	sb.WriteString("  if (typeDef.array) {\n")
+	sb.WriteString("    const elementType = typeDef.array;\n")
+	sb.WriteString("    return values.map((v) => parseQueryValue(v, elementType));\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  if (values.length === 0) {\n")
+	sb.WriteString("    throw new Error('missing value');\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  if (values.length > 1) {\n")
+	sb.WriteString("    throw new Error(`expected a single value, got ${values.length}`);\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  return parseQueryValue(values[0], typeDef);\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// Converts a single query string value; enum membership is checked by parameter validation\n")
+	sb.WriteString("function parseQueryValue(value: string, typeDef: TypeDef): any {\n")
+	sb.WriteString("  switch (typeDef.builtIn) {\n")
+	sb.WriteString("    case 'int':\n")
+	sb.WriteString("      if (!/^[-+]?\\d+$/.test(value)) {\n")
+	sb.WriteString("        throw new Error(`invalid int: '${value}'`);\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("      return parseInt(value, 10);\n")
+	sb.WriteString("    case 'float': {\n")
+	sb.WriteString("      const f = Number(value);\n")
+	sb.WriteString("      if (value.trim() === '' || isNaN(f)) {\n")
+	sb.WriteString("        throw new Error(`invalid float: '${value}'`);\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("      return f;\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    case 'bool':\n")
+	sb.WriteString("      if (value !== 'true' && value !== 'false') {\n")
+	sb.WriteString("        throw new Error(`invalid bool: '${value}' (expected true or false)`);\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("      return value === 'true';\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  return value;\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// Maps a JSON-RPC error code to the HTTP status of a GET response\n")
+	sb.WriteString("function restErrorStatus(code: number): number {\n")
+	sb.WriteString("  if (code === -32700 || code === -32600 || code === -32602) {\n")
+	sb.WriteString("    return 400;\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  if (code === -32601) {\n")
+	sb.WriteString("    return 404;\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  if (code >= -32768 && code <= -32000) {\n")
+	sb.WriteString("    return 500;\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  // Application-defined error codes\n")
+	sb.WriteString("  return 422;\n")
+	sb.WriteString("}\n\n")
+	if usesCachedMethods(interfaces) {
+		writeCacheHelpersTs(sb)
+	}
+}
+
 // writeInterfaceStubTs generates an abstract class for an interface
 func writeInterfaceStubTs(sb *strings.Builder, iface *parser.Interface, packagePrefix string) {
 	if iface.Comment != "" {
@@ -415,9 +988,22 @@ func writeInterfaceStubTs(sb *strings.Builder, iface *parser.Interface, packageP
 		}
 	}
 	className := applyPackagePrefix(iface.Name, packagePrefix)
-	fmt.Fprintf(sb, "export abstract class %s {\n", className)
+	if len(iface.Extends) > 0 {
+		// A class extends at most one class, so parents are implemented as types and
+		// the inherited methods are declared again
+		parents := make([]string, len(iface.Extends))
+		for i, parent := range iface.Extends {
+			parents[i] = applyPackagePrefix(parent, packagePrefix)
+		}
+		fmt.Fprintf(sb, "export abstract class %s implements %s {\n", className, strings.Join(parents, ", "))
+	} else {
+		fmt.Fprintf(sb, "export abstract class %s {\n", className)
+	}
 
 	for _, method := range iface.Methods {
+		if method.IsChunked() {
+			sb.WriteString("  // [chunked]: may return any Iterable of the elements, such as a generator\n")
+		}
 		fmt.Fprintf(sb, "  abstract %s(", method.Name)
 		for i, param := range method.Parameters {
 			if i > 0 {
@@ -430,8 +1016,10 @@ func writeInterfaceStubTs(sb *strings.Builder, iface *parser.Interface, packageP
 	sb.WriteString("}\n\n")
 }
 
-// writeServerHandleRequestTs generates the handleRequest method for the server
-func writeServerHandleRequestTs(sb *strings.Builder, interfaces []*parser.Interface) {
+// writeServerHandleRequestTs generates the handleRequest method for the server. admin
+// records the calls of every handler for the admin endpoint.
+func writeServerHandleRequestTs(sb *strings.Builder, idl *parser.IDL, packagePrefix string, admin bool, idlDoc idlJSONDocument) {
+	interfaces := idl.Interfaces
 	sb.WriteString("  handleRequest(requestJson: any): any {\n")
 	sb.WriteString("    // Validate JSON-RPC 2.0 structure\n")
 	sb.WriteString("    if (typeof requestJson !== 'object' || requestJson === null || Array.isArray(requestJson)) {\n")
@@ -443,7 +1031,12 @@ func writeServerHandleRequestTs(sb *strings.Builder, interfaces []*parser.Interf
 	sb.WriteString("      return this.errorResponse(null, -32600, 'Invalid Request', \"jsonrpc must be '2.0'\");\n")
 	sb.WriteString("    }\n\n")
 
-	sb.WriteString("    const method = requestJson.method;\n")
+	if usesWireNames(interfaces) {
+		// Reassigned below when the name was set by [wire]
+		sb.WriteString("    let method = requestJson.method;\n")
+	} else {
+		sb.WriteString("    const method = requestJson.method;\n")
+	}
 	sb.WriteString("    if (typeof method !== 'string') {\n")
 	sb.WriteString("      return this.errorResponse(null, -32600, 'Invalid Request', 'method must be a string');\n")
 	sb.WriteString("    }\n\n")
@@ -456,9 +1049,13 @@ func writeServerHandleRequestTs(sb *strings.Builder, interfaces []*parser.Interf
 	sb.WriteString("    // Special case: pulserpc-idl method returns the IDL JSON document\n")
 	sb.WriteString("    if (method === 'pulserpc-idl') {\n")
 	sb.WriteString("      try {\n")
-	sb.WriteString("        const serverDir = __dirname;\n")
-	sb.WriteString("        const idlJsonPath = path.join(serverDir, 'idl.json');\n")
-	sb.WriteString("        const idlDoc = JSON.parse(fs.readFileSync(idlJsonPath, 'utf-8'));\n\n")
+	if idlDoc.Embedded() {
+		sb.WriteString("        const idlDoc = JSON.parse(" + idlDoc.StringLiteral() + ");\n\n")
+	} else {
+		sb.WriteString("        const serverDir = __dirname;\n")
+		sb.WriteString("        const idlJsonPath = path.join(serverDir, '" + strings.Join(strings.Split(idlDoc.Path, "/"), "', '") + "');\n")
+		sb.WriteString("        const idlDoc = JSON.parse(fs.readFileSync(idlJsonPath, 'utf-8'));\n\n")
+	}
 	sb.WriteString("        if (isNotification) {\n")
 	sb.WriteString("          return null;\n")
 	sb.WriteString("        }\n")
@@ -471,6 +1068,33 @@ func writeServerHandleRequestTs(sb *strings.Builder, interfaces []*parser.Interf
 	sb.WriteString("        return this.errorResponse(requestId, -32603, 'Internal error', `Failed to load IDL JSON: ${err.message}`);\n")
 	sb.WriteString("      }\n")
 	sb.WriteString("    }\n\n")
+	sb.WriteString("    // Special case: pulserpc-capabilities method reports the optional protocol features\n")
+	sb.WriteString("    if (method === 'pulserpc-capabilities') {\n")
+	sb.WriteString("      if (isNotification) {\n")
+	sb.WriteString("        return null;\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("      return { jsonrpc: '2.0', result: SERVER_CAPABILITIES, id: requestId };\n")
+	sb.WriteString("    }\n\n")
+
+	if usesAsyncMethods(interfaces) {
+		sb.WriteString("    // Special case: pulserpc-job method reports the state of an [async] method's job\n")
+		sb.WriteString("    if (method === 'pulserpc-job') {\n")
+		sb.WriteString("      const jobId = Array.isArray(params) && params.length === 1 ? params[0] : null;\n")
+		sb.WriteString("      const status = this.jobStatus(jobId);\n")
+		sb.WriteString("      if (!status) {\n")
+		sb.WriteString("        return this.errorResponse(requestId, -32602, 'Invalid params', `unknown job '${jobId}'`);\n")
+		sb.WriteString("      }\n")
+		sb.WriteString("      if (isNotification) {\n")
+		sb.WriteString("        return null;\n")
+		sb.WriteString("      }\n")
+		sb.WriteString("      return { jsonrpc: '2.0', result: status, id: requestId };\n")
+		sb.WriteString("    }\n\n")
+	}
+
+	if usesWireNames(interfaces) {
+		sb.WriteString("    // A name set by [wire] is dispatched by its interface.method name\n")
+		sb.WriteString("    method = WIRE_METHODS[method] ?? method;\n\n")
+	}
 
 	// Parse method name
 	sb.WriteString("    // Parse method name: interface.method\n")
@@ -483,7 +1107,16 @@ func writeServerHandleRequestTs(sb *strings.Builder, interfaces []*parser.Interf
 	sb.WriteString("    const methodName = parts[1];\n\n")
 
 	sb.WriteString("    // Find handler\n")
-	sb.WriteString("    const handler = this.handlers.get(interfaceName);\n")
+	if usesInterfaceInheritance(interfaces) {
+		sb.WriteString("    let handler = this.handlers.get(interfaceName);\n")
+		sb.WriteString("    if (!handler) {\n")
+		sb.WriteString("      // An extended interface's methods are also served by the handler of an interface that extends it\n")
+		sb.WriteString("      const sub = (SUB_INTERFACES[interfaceName] ?? []).find((name) => this.handlers.has(name));\n")
+		sb.WriteString("      handler = sub ? this.handlers.get(sub) : undefined;\n")
+		sb.WriteString("    }\n")
+	} else {
+		sb.WriteString("    const handler = this.handlers.get(interfaceName);\n")
+	}
 	sb.WriteString("    if (!handler) {\n")
 	sb.WriteString("      return this.errorResponse(requestId, -32601, 'Method not found', `Interface '${interfaceName}' not registered`);\n")
 	sb.WriteString("    }\n\n")
@@ -497,8 +1130,7 @@ func writeServerHandleRequestTs(sb *strings.Builder, interfaces []*parser.Interf
 
 	// Find method definition
 	sb.WriteString("    // Find interface and method definition\n")
-	sb.WriteString("    let methodDef: any = null;\n\n")
-	writeInterfaceMethodLookupTs(sb, interfaces)
+	fmt.Fprintf(sb, "    const methodDef = %s[interfaceName]?.[methodName];\n", applyPackagePrefix("METHOD_DEFS", packagePrefix))
 	sb.WriteString("    if (!methodDef) {\n")
 	sb.WriteString("      return this.errorResponse(requestId, -32601, 'Method not found', `Method '${methodName}' not found in interface '${interfaceName}'`);\n")
 	sb.WriteString("    }\n\n")
@@ -508,35 +1140,98 @@ func writeServerHandleRequestTs(sb *strings.Builder, interfaces []*parser.Interf
 	sb.WriteString("    if (params === null || params === undefined) {\n")
 	sb.WriteString("      params = [];\n")
 	sb.WriteString("    }\n")
+	sb.WriteString("    if (typeof params === 'object' && !Array.isArray(params)) {\n")
+	sb.WriteString("      try {\n")
+	sb.WriteString("        params = this.paramsByName(params, methodDef.parameters || []);\n")
+	sb.WriteString("      } catch (err: any) {\n")
+	sb.WriteString("        return this.errorResponse(requestId, -32602, 'Invalid params', err.message);\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("    }\n")
 	sb.WriteString("    if (!Array.isArray(params)) {\n")
 	sb.WriteString("      return this.errorResponse(requestId, -32602, 'Invalid params', 'params must be an array');\n")
 	sb.WriteString("    }\n\n")
 
 	sb.WriteString("    // Validate param count\n")
 	sb.WriteString("    const expectedParams = methodDef.parameters || [];\n")
-	sb.WriteString("    if (params.length !== expectedParams.length) {\n")
-	sb.WriteString("      return this.errorResponse(requestId, -32602, 'Invalid params', `Expected ${expectedParams.length} parameters, got ${params.length}`);\n")
-	sb.WriteString("    }\n\n")
+	if usesOptionalParams(interfaces) {
+		sb.WriteString("    const required = expectedParams.filter((p: any) => !p.optional).length;\n")
+		sb.WriteString("    if (params.length < required || params.length > expectedParams.length) {\n")
+		sb.WriteString("      const expected = required < expectedParams.length ? `${required} to ${expectedParams.length}` : `${expectedParams.length}`;\n")
+		sb.WriteString("      return this.errorResponse(requestId, -32602, 'Invalid params', `Expected ${expected} parameters, got ${params.length}`);\n")
+		sb.WriteString("    }\n\n")
+
+		sb.WriteString("    // Optional parameters that were left out or are null take their default, if any\n")
+		sb.WriteString("    params = expectedParams.map((p: any, i: number) =>\n")
+		sb.WriteString("      (params[i] === null || params[i] === undefined) && 'default' in p ? p.default : params[i] ?? null);\n\n")
+	} else {
+		sb.WriteString("    if (params.length !== expectedParams.length) {\n")
+		sb.WriteString("      return this.errorResponse(requestId, -32602, 'Invalid params', `Expected ${expectedParams.length} parameters, got ${params.length}`);\n")
+		sb.WriteString("    }\n\n")
+	}
+
+	if usesEncryptedFields(idl) {
+		sb.WriteString("    // Decrypt the [encrypted] fields of the params before validating them\n")
+		sb.WriteString("    if (ENCRYPTED_METHODS.has(method)) {\n")
+		sb.WriteString("      try {\n")
+		sb.WriteString("        params = params.map((value: any, i: number) => decryptFields(value, expectedParams[i].type, ALL_STRUCTS, this.fieldCipher));\n")
+		sb.WriteString("      } catch (err: any) {\n")
+		sb.WriteString("        return this.errorResponse(requestId, -32602, 'Invalid params', err.message);\n")
+		sb.WriteString("      }\n")
+		sb.WriteString("    }\n\n")
+	}
 
 	sb.WriteString("    // Validate each param\n")
 	sb.WriteString("    for (let i = 0; i < params.length; i++) {\n")
 	sb.WriteString("      try {\n")
-	sb.WriteString("        validateType(params[i], expectedParams[i].type, ALL_STRUCTS, ALL_ENUMS, false);\n")
+	if usesOptionalParams(interfaces) {
+		sb.WriteString("        validateType(params[i], expectedParams[i].type, ALL_STRUCTS, ALL_ENUMS, expectedParams[i].optional === true);\n")
+	} else {
+		sb.WriteString("        validateType(params[i], expectedParams[i].type, ALL_STRUCTS, ALL_ENUMS, false);\n")
+	}
 	sb.WriteString("      } catch (err: any) {\n")
 	sb.WriteString("        return this.errorResponse(requestId, -32602, 'Invalid params', `Parameter ${i} (${expectedParams[i].name}) validation failed: ${err.message}`);\n")
 	sb.WriteString("      }\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    try {\n")
+	sb.WriteString("      checkRequestIntLiterals(requestJson, expectedParams, ALL_STRUCTS);\n")
+	sb.WriteString("    } catch (err: any) {\n")
+	sb.WriteString("      return this.errorResponse(requestId, -32602, 'Invalid params', err.message);\n")
 	sb.WriteString("    }\n\n")
 
+	if usesAsyncMethods(interfaces) {
+		sb.WriteString("    // [async] methods run as background jobs; the job's own run has a JobRequestId\n")
+		sb.WriteString("    if (methodDef.async && !(requestId instanceof JobRequestId)) {\n")
+		sb.WriteString("      return this.startJob(requestJson, requestId, isNotification);\n")
+		sb.WriteString("    }\n\n")
+	}
+
 	// Invoke handler
 	sb.WriteString("    // Invoke handler\n")
+	sb.WriteString("    const started = Date.now();\n")
 	sb.WriteString("    let result: any;\n")
+	if admin {
+		sb.WriteString("    let failed = true;\n")
+	}
 	sb.WriteString("    try {\n")
 	sb.WriteString("      result = methodFunc.apply(handler, params);\n")
+	if usesChunkedMethods(idl.Interfaces) {
+		sb.WriteString("      if (methodDef.chunked) {\n")
+		sb.WriteString("        // [chunked] handlers may return any Iterable, such as a generator\n")
+		sb.WriteString("        result = Array.from(result);\n")
+		sb.WriteString("      }\n")
+	}
+	if admin {
+		sb.WriteString("      failed = false;\n")
+	}
 	sb.WriteString("    } catch (err: any) {\n")
 	sb.WriteString("      if (err instanceof RPCError) {\n")
 	sb.WriteString("        return this.errorResponse(requestId, err.code, err.message, err.data);\n")
 	sb.WriteString("      }\n")
 	sb.WriteString("      return this.errorResponse(requestId, -32603, 'Internal error', err.message || String(err));\n")
+	if admin {
+		sb.WriteString("    } finally {\n")
+		sb.WriteString("      this.metrics?.record(`${interfaceName}.${methodName}`, Date.now() - started, failed);\n")
+	}
 	sb.WriteString("    }\n\n")
 
 	// Validate response
@@ -555,13 +1250,59 @@ func writeServerHandleRequestTs(sb *strings.Builder, interfaces []*parser.Interf
 	sb.WriteString("    if (isNotification) {\n")
 	sb.WriteString("      return null;\n")
 	sb.WriteString("    }\n")
-	sb.WriteString("    return {\n")
-	sb.WriteString("      jsonrpc: '2.0',\n")
-	sb.WriteString("      result: result,\n")
+	if usesEncryptedFields(idl) {
+		sb.WriteString("    // Encrypt the [encrypted] fields of the validated result\n")
+		sb.WriteString("    let wireResult = result;\n")
+		sb.WriteString("    if (returnType && ENCRYPTED_METHODS.has(method)) {\n")
+		sb.WriteString("      try {\n")
+		sb.WriteString("        wireResult = encryptFields(result, returnType, ALL_STRUCTS, this.fieldCipher);\n")
+		sb.WriteString("      } catch (err: any) {\n")
+		sb.WriteString("        return this.errorResponse(requestId, -32603, 'Internal error', `Response encryption failed: ${err.message}`);\n")
+		sb.WriteString("      }\n")
+		sb.WriteString("    }\n")
+		sb.WriteString("    const response: any = {\n")
+		sb.WriteString("      jsonrpc: '2.0',\n")
+		sb.WriteString("      result: wireResult,\n")
+	} else {
+		sb.WriteString("    const response: any = {\n")
+		sb.WriteString("      jsonrpc: '2.0',\n")
+		sb.WriteString("      result: result,\n")
+	}
 	sb.WriteString("      id: requestId,\n")
 	sb.WriteString("    };\n")
+	sb.WriteString("    if (this.metaHook) {\n")
+	sb.WriteString("      const meta = this.metaHook({ method, params, result, elapsedMs: Date.now() - started });\n")
+	sb.WriteString("      if (meta && Object.keys(meta).length > 0) {\n")
+	sb.WriteString("        response.meta = meta;\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    return response;\n")
+	sb.WriteString("  }\n\n")
+
+	sb.WriteString("  // Orders by-name params as the method declares them. Optional parameters that\n")
+	sb.WriteString("  // are left out are null.\n")
+	sb.WriteString("  private paramsByName(named: Record<string, any>, expectedParams: any[]): any[] {\n")
+	sb.WriteString("    const declared = expectedParams.map((p: any) => p.name);\n")
+	sb.WriteString("    for (const name of Object.keys(named)) {\n")
+	sb.WriteString("      if (!declared.includes(name)) {\n")
+	sb.WriteString("        throw new Error(`unknown parameter '${name}'`);\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    return expectedParams.map((p: any) => {\n")
+	sb.WriteString("      if (Object.prototype.hasOwnProperty.call(named, p.name)) {\n")
+	sb.WriteString("        return named[p.name];\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("      if (!p.optional) {\n")
+	sb.WriteString("        throw new Error(`missing parameter '${p.name}'`);\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("      return null;\n")
+	sb.WriteString("    });\n")
 	sb.WriteString("  }\n\n")
 
+	if usesAsyncMethods(interfaces) {
+		writeJobsServerTs(sb)
+	}
+
 	// errorResponse helper
 	sb.WriteString("  private errorResponse(requestId: any, code: number, message: string, data?: any): any {\n")
 	sb.WriteString("    const error: any = { code, message };\n")
@@ -576,51 +1317,17 @@ func writeServerHandleRequestTs(sb *strings.Builder, interfaces []*parser.Interf
 	sb.WriteString("  }\n\n")
 }
 
-// writeInterfaceMethodLookupTs generates code to find method definitions
-func writeInterfaceMethodLookupTs(sb *strings.Builder, interfaces []*parser.Interface) {
-	for i, iface := range interfaces {
-		if i == 0 {
-			fmt.Fprintf(sb, "    if (interfaceName === '%s') {\n", iface.Name)
-		} else {
-			fmt.Fprintf(sb, "    } else if (interfaceName === '%s') {\n", iface.Name)
-		}
-		sb.WriteString("      const interfaceMethods: any = {\n")
-		for _, method := range iface.Methods {
-			fmt.Fprintf(sb, "        '%s': {\n", method.Name)
-			sb.WriteString("          parameters: [\n")
-			for _, param := range method.Parameters {
-				sb.WriteString("            {\n")
-				fmt.Fprintf(sb, "              name: '%s',\n", param.Name)
-				sb.WriteString("              type: ")
-				writeTypeDictTs(sb, param.Type)
-				sb.WriteString(",\n")
-				sb.WriteString("            },\n")
-			}
-			sb.WriteString("          ],\n")
-			sb.WriteString("          returnType: ")
-			writeTypeDictTs(sb, method.ReturnType)
-			sb.WriteString(",\n")
-			if method.ReturnOptional {
-				sb.WriteString("          returnOptional: true,\n")
-			} else {
-				sb.WriteString("          returnOptional: false,\n")
-			}
-			sb.WriteString("        },\n")
-		}
-		sb.WriteString("      };\n")
-		sb.WriteString("      methodDef = interfaceMethods[methodName];\n")
-	}
-	sb.WriteString("    }\n\n")
-}
-
 // generateClientTs generates the client.ts file with transport abstraction and client classes
-func generateClientTs(idl *parser.IDL, _ map[string]*parser.Struct, _ map[string]*parser.Enum, _ map[string]*parser.Interface, packagePrefix string, namespaceMap map[string]*NamespaceTypes, relPathToBase string) string {
+func generateClientTs(idl *parser.IDL, structMap map[string]*parser.Struct, _ map[string]*parser.Enum, _ map[string]*parser.Interface, packagePrefix string, namespaceMap map[string]*NamespaceTypes, nsImports map[string]string) string {
 	var sb strings.Builder
 
 	sb.WriteString("// Generated by pulserpc - do not edit\n\n")
 	sb.WriteString("/// <reference types=\"node\" />\n\n")
 	sb.WriteString("import * as crypto from 'crypto';\n")
+	sb.WriteString("import { canonicalJson } from './pulserpc/canonical';\n")
+	sb.WriteString("import { DEADLINE_HEADER, remainingTimeMs } from './pulserpc/deadline';\n")
 	sb.WriteString("import { RPCError } from './pulserpc/rpc';\n")
+	fmt.Fprintf(&sb, "import { %s } from './methods';\n", applyPackagePrefix("METHOD_DEFS", packagePrefix))
 
 	// Import from namespace files
 	namespaces := make([]string, 0, len(namespaceMap))
@@ -632,16 +1339,15 @@ func generateClientTs(idl *parser.IDL, _ map[string]*parser.Struct, _ map[string
 	// Sort namespaces for consistent output
 	sort.Strings(namespaces)
 	for _, ns := range namespaces {
-		importPath := relPathToBase + ns
-		if importPath == "" {
-			importPath = "./" + ns
-		} else if !strings.HasPrefix(importPath, ".") {
-			importPath = "./" + importPath
-		}
-		sb.WriteString(fmt.Sprintf("import { ALL_STRUCTS as %s_STRUCTS, ALL_ENUMS as %s_ENUMS } from '%s';\n", strings.ToUpper(ns), strings.ToUpper(ns), importPath))
+		sb.WriteString(fmt.Sprintf("import { ALL_STRUCTS as %s_STRUCTS, ALL_ENUMS as %s_ENUMS } from '%s';\n", strings.ToUpper(ns), strings.ToUpper(ns), nsImports[ns]))
+	}
+	sb.WriteString("\n")
+	sb.WriteString("import { redactValue } from './pulserpc/types';\n")
+	sb.WriteString("import { validateType } from './pulserpc/validation';\n")
+	if usesEncryptedFields(idl) {
+		sb.WriteString("import { FieldCipher, decryptFields, encryptFields } from './pulserpc/encryption';\n")
 	}
 	sb.WriteString("\n")
-	sb.WriteString("import { validateType } from './pulserpc/validation';\n\n")
 	sb.WriteString("// Inline type definitions\n")
 	sb.WriteString("interface TypeDef {\n")
 	sb.WriteString("  builtIn?: string;\n")
@@ -674,21 +1380,119 @@ func generateClientTs(idl *parser.IDL, _ map[string]*parser.Struct, _ map[string
 	sb.WriteString("};\n\n")
 
 	// Generate Transport abstract class
-	writeTransportAbstractTs(&sb, packagePrefix)
+	writeTransportAbstractTs(&sb, packagePrefix, usesAsyncMethods(idl.Interfaces))
 
 	// Generate HTTPTransport
-	writeHTTPTransportTs(&sb, packagePrefix)
+	if usesCachedMethods(idl.Interfaces) {
+		writeCachedMethodsTs(&sb, idl.Interfaces)
+	}
+	writeLogCallTs(&sb, packagePrefix)
+	writeHTTPTransportTs(&sb, packagePrefix, usesCachedMethods(idl.Interfaces))
+	if structs := errorDataStructs(idl.Interfaces); len(structs) > 0 {
+		writeErrorDataClassesTs(&sb, structs, packagePrefix)
+	}
 
 	// Generate client classes for each interface
 	for _, iface := range idl.Interfaces {
-		writeInterfaceClientTs(&sb, iface, idl.Interfaces, packagePrefix)
+		writeInterfaceClientTs(&sb, iface, structMap, packagePrefix)
+	}
+	if usesAPIClientFacade(idl.Interfaces) {
+		writeAPIClientTs(&sb, idl.Interfaces, packagePrefix)
 	}
 
 	return sb.String()
 }
 
 // writeTransportAbstractTs generates the Transport abstract class
-func writeTransportAbstractTs(sb *strings.Builder, packagePrefix string) {
+func writeTransportAbstractTs(sb *strings.Builder, packagePrefix string, asyncJobs bool) {
+	optionsName := applyPackagePrefix("CallOptions", packagePrefix)
+	sb.WriteString("/**\n")
+	sb.WriteString(" * Per-call settings, passed as the last argument of a client method. headers are\n")
+	sb.WriteString(" * added to the request, overriding the transport's headers. idempotencyKey is sent\n")
+	sb.WriteString(" * as the Idempotency-Key header so the server can recognize a repeated request.\n")
+	sb.WriteString(" * namedParams sends params as an object keyed by the parameter names, which client\n")
+	sb.WriteString(" * methods set in paramNames. responseMeta receives the metadata the server attached\n")
+	sb.WriteString(" * to the response.\n")
+	sb.WriteString(" */\n")
+	fmt.Fprintf(sb, "export interface %s {\n", optionsName)
+	sb.WriteString("  timeoutMs?: number;\n")
+	sb.WriteString("  headers?: Record<string, string>;\n")
+	sb.WriteString("  idempotencyKey?: string;\n")
+	sb.WriteString("  namedParams?: boolean;\n")
+	sb.WriteString("  paramNames?: string[];\n")
+	sb.WriteString("  responseMeta?: Record<string, any>;\n")
+	sb.WriteString("  // The batch the call was added to, set by Batch.add\n")
+	fmt.Fprintf(sb, "  batch?: %s;\n", applyPackagePrefix("Batch", packagePrefix))
+	if asyncJobs {
+		sb.WriteString("  // How often an [async] method polls its job; the default is a second\n")
+		sb.WriteString("  jobPollMs?: number;\n")
+		sb.WriteString("  // Called with the job status after each poll of an [async] method's job\n")
+		fmt.Fprintf(sb, "  onJobProgress?: (status: %s) => void;\n", applyPackagePrefix("JobStatus", packagePrefix))
+	}
+	sb.WriteString("}\n\n")
+
+	resultName := applyPackagePrefix("CallResult", packagePrefix)
+	sb.WriteString("/**\n")
+	sb.WriteString(" * The result of a call together with the metadata the server attached to the\n")
+	sb.WriteString(" * response, such as timings, pagination hints or warnings.\n")
+	sb.WriteString(" */\n")
+	fmt.Fprintf(sb, "export interface %s<T> {\n", resultName)
+	sb.WriteString("  result: T;\n")
+	sb.WriteString("  meta: Record<string, any>;\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("/**\n")
+	sb.WriteString(" * Makes a client call with options that capture the response metadata:\n")
+	sb.WriteString(" *\n")
+	sb.WriteString(" *   const res = await callWithMeta((options) => client.getProduct('p-1', options));\n")
+	sb.WriteString(" */\n")
+	fmt.Fprintf(sb, "export async function callWithMeta<T>(call: (options: %s) => Promise<T>): Promise<%s<T>> {\n", optionsName, resultName)
+	sb.WriteString("  const meta: Record<string, any> = {};\n")
+	sb.WriteString("  const result = await call({ responseMeta: meta });\n")
+	sb.WriteString("  return { result, meta };\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("/**\n")
+	sb.WriteString(" * Returns params as sent in a request: by name when options ask for it and the\n")
+	sb.WriteString(" * parameter names are known, otherwise by position.\n")
+	sb.WriteString(" */\n")
+	fmt.Fprintf(sb, "function requestParams(params: any[], options: %s): any {\n", optionsName)
+	sb.WriteString("  const names = options.paramNames;\n")
+	sb.WriteString("  if (!options.namedParams || !names || names.length !== params.length) {\n")
+	sb.WriteString("    return params;\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  return Object.fromEntries(names.map((name, i) => [name, params[i]]));\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("/**\n")
+	sb.WriteString(" * Calls transport with options, or queues the request in the batch the call was added to.\n")
+	sb.WriteString(" */\n")
+	fmt.Fprintf(sb, "function callTransport(transport: %s, method: string, params: any[], options: %s): Promise<any> {\n", applyPackagePrefix("Transport", packagePrefix), optionsName)
+	sb.WriteString("  if (options.batch) {\n")
+	sb.WriteString("    return options.batch.queue(method, params, options);\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  return transport.callWithOptions(method, params, options);\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("/**\n")
+	sb.WriteString(" * Returns the timeout of a call: its own, else the time left before the deadline of the\n")
+	sb.WriteString(" * call being handled, if any. A call with a timeout sends it in the X-PulseRPC-Deadline\n")
+	sb.WriteString(" * header so the server can pass the budget on.\n")
+	sb.WriteString(" */\n")
+	fmt.Fprintf(sb, "function callTimeoutMs(options: %s, headers: Record<string, string>): number | undefined {\n", optionsName)
+	sb.WriteString("  const timeoutMs = options.timeoutMs || remainingTimeMs();\n")
+	sb.WriteString("  if (timeoutMs !== undefined) {\n")
+	sb.WriteString("    headers[DEADLINE_HEADER] = String(Math.floor(timeoutMs));\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  return timeoutMs;\n")
+	sb.WriteString("}\n\n")
+
+	signerName := applyPackagePrefix("RequestSigner", packagePrefix)
+	sb.WriteString("/**\n")
+	sb.WriteString(" * Returns headers that authenticate a request, computed from the serialized JSON-RPC\n")
+	sb.WriteString(" * request exactly as it is sent.\n")
+	sb.WriteString(" */\n")
+	fmt.Fprintf(sb, "export type %s = (body: string) => Record<string, string> | Promise<Record<string, string>>;\n\n", signerName)
+
 	className := applyPackagePrefix("Transport", packagePrefix)
 	fmt.Fprintf(sb, "export abstract class %s {\n", className)
 	sb.WriteString("  /**\n")
@@ -698,17 +1502,57 @@ func writeTransportAbstractTs(sb *strings.Builder, packagePrefix string) {
 	sb.WriteString("   * @returns Promise that resolves to the JSON-RPC 2.0 response\n")
 	sb.WriteString("   * @throws RPCError If the JSON-RPC call returns an error\n")
 	sb.WriteString("   */\n")
-	sb.WriteString("  abstract call(method: string, params: any[]): Promise<any>;\n")
+	sb.WriteString("  abstract call(method: string, params: any[]): Promise<any>;\n\n")
+	sb.WriteString("  /**\n")
+	sb.WriteString("   * Perform a JSON-RPC 2.0 call with per-call options. Transports that honor the\n")
+	sb.WriteString("   * options override this; the default ignores them.\n")
+	sb.WriteString("   */\n")
+	fmt.Fprintf(sb, "  callWithOptions(method: string, params: any[], options: %s): Promise<any> {\n", optionsName)
+	sb.WriteString("    return this.call(method, params);\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("}\n\n")
+
+	writeCapabilitiesClientTs(sb, packagePrefix)
+	writeBatchClientTs(sb, packagePrefix)
+
+	if asyncJobs {
+		writeJobsClientTs(sb, packagePrefix)
+	}
+
+	errorClassName := applyPackagePrefix("TransportError", packagePrefix)
+	sb.WriteString("/**\n")
+	sb.WriteString(" * Thrown when no JSON-RPC response was received: the connection failed, or the\n")
+	sb.WriteString(" * server answered with an HTTP error status and a body that is not a JSON-RPC\n")
+	sb.WriteString(" * response. status is 0 if no response was received. retryable is true for\n")
+	sb.WriteString(" * failures that draining or restarting servers produce: connection refused,\n")
+	sb.WriteString(" * connection reset or closed before a response, HTTP 502/503.\n")
+	sb.WriteString(" */\n")
+	fmt.Fprintf(sb, "export class %s extends RPCError {\n", errorClassName)
+	sb.WriteString("  public readonly retryable: boolean;\n\n")
+	sb.WriteString("  constructor(message: string, public readonly status: number = 0, retryable: boolean = false) {\n")
+	sb.WriteString("    super(-32603, message, undefined);\n")
+	sb.WriteString("    this.retryable = retryable || status === 502 || status === 503;\n")
+	sb.WriteString("  }\n")
 	sb.WriteString("}\n\n")
 }
 
 // writeHTTPTransportTs generates the HTTPTransport class
-func writeHTTPTransportTs(sb *strings.Builder, packagePrefix string) {
+func writeHTTPTransportTs(sb *strings.Builder, packagePrefix string, conditional bool) {
 	transportClassName := applyPackagePrefix("Transport", packagePrefix)
+	errorClassName := applyPackagePrefix("TransportError", packagePrefix)
+	optionsName := applyPackagePrefix("CallOptions", packagePrefix)
 	className := applyPackagePrefix("HTTPTransport", packagePrefix)
 	fmt.Fprintf(sb, "export class %s extends %s {\n", className, transportClassName)
 	sb.WriteString("  private baseUrl: string;\n")
-	sb.WriteString("  private headers: Record<string, string>;\n\n")
+	sb.WriteString("  private headers: Record<string, string>;\n")
+	fmt.Fprintf(sb, "  private signer: %s | null = null;\n", applyPackagePrefix("RequestSigner", packagePrefix))
+	if conditional {
+		sb.WriteString("  private cache: Map<string, { etag: string; body: string }> | null = null;\n")
+	}
+	sb.WriteString("  private canonical = false;\n")
+	sb.WriteString("  private debugLog: ((line: string) => void) | null = null;\n")
+	fmt.Fprintf(sb, "  private capabilitiesPromise: Promise<%s> | undefined;\n", applyPackagePrefix("Capabilities", packagePrefix))
+	sb.WriteString("\n")
 
 	sb.WriteString("  constructor(baseUrl: string, headers?: Record<string, string>) {\n")
 	sb.WriteString("    super();\n")
@@ -716,7 +1560,56 @@ func writeHTTPTransportTs(sb *strings.Builder, packagePrefix string) {
 	sb.WriteString("    this.headers = headers ? { ...headers } : {};\n")
 	sb.WriteString("  }\n\n")
 
+	sb.WriteString("  /** Installs a hook that signs every request, such as hmacSigner from signing.ts. */\n")
+	fmt.Fprintf(sb, "  setSigner(signer: %s | null): void {\n", applyPackagePrefix("RequestSigner", packagePrefix))
+	sb.WriteString("    this.signer = signer;\n")
+	sb.WriteString("  }\n\n")
+
+	writeCanonicalTs(sb, "requests")
+
+	sb.WriteString("  /**\n")
+	sb.WriteString("   * Resolves the server's host and opens a connection to it, including the TLS\n")
+	sb.WriteString("   * handshake, which fetch keeps for the next call. Call it at process start to take\n")
+	sb.WriteString("   * the connection setup out of the first call's latency. With ping, it also calls the\n")
+	sb.WriteString("   * built-in pulserpc-idl method, which wakes a server that scaled to zero; any\n")
+	sb.WriteString("   * JSON-RPC response counts. timeoutMs bounds the warm-up.\n")
+	sb.WriteString("   */\n")
+	sb.WriteString("  async warmup(options: { ping?: boolean; timeoutMs?: number } = {}): Promise<void> {\n")
+	sb.WriteString("    if (options.ping) {\n")
+	sb.WriteString("      try {\n")
+	sb.WriteString("        await this.callWithOptions('pulserpc-idl', [], { timeoutMs: options.timeoutMs });\n")
+	sb.WriteString("      } catch (err) {\n")
+	fmt.Fprintf(sb, "        if (!(err instanceof RPCError) || err instanceof %s) {\n", errorClassName)
+	sb.WriteString("          throw err;\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("      return;\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    try {\n")
+	sb.WriteString("      // Any HTTP status will do: the connection is open either way\n")
+	sb.WriteString("      const response = await fetch(this.baseUrl, {\n")
+	sb.WriteString("        method: 'OPTIONS',\n")
+	sb.WriteString("        signal: options.timeoutMs ? AbortSignal.timeout(options.timeoutMs) : undefined,\n")
+	sb.WriteString("      });\n")
+	sb.WriteString("      await response.arrayBuffer();\n")
+	sb.WriteString("    } catch (err: any) {\n")
+	fmt.Fprintf(sb, "      throw new %s(`Network error: ${err.message || String(err)}`);\n", errorClassName)
+	sb.WriteString("    }\n")
+	sb.WriteString("  }\n\n")
+
 	sb.WriteString("  async call(method: string, params: any[]): Promise<any> {\n")
+	sb.WriteString("    return this.callWithOptions(method, params, {});\n")
+	sb.WriteString("  }\n\n")
+
+	writeLoggedCallTs(sb, optionsName)
+
+	fmt.Fprintf(sb, "  private async sendCall(method: string, params: any[], options: %s): Promise<any> {\n", optionsName)
+	if conditional {
+		sb.WriteString("    const cachedPath = CACHED_METHODS[method];\n")
+		sb.WriteString("    if (cachedPath !== undefined && this.cache !== null && options.paramNames?.length === params.length) {\n")
+		sb.WriteString("      return this.conditionalGet(cachedPath, params, options);\n")
+		sb.WriteString("    }\n\n")
+	}
 	sb.WriteString("    // Generate request ID\n")
 	sb.WriteString("    const requestId = crypto.randomUUID();\n\n")
 
@@ -724,55 +1617,89 @@ func writeHTTPTransportTs(sb *strings.Builder, packagePrefix string) {
 	sb.WriteString("    const requestData = {\n")
 	sb.WriteString("      jsonrpc: '2.0',\n")
 	sb.WriteString("      method: method,\n")
-	sb.WriteString("      params: params,\n")
+	sb.WriteString("      params: requestParams(params, options),\n")
 	sb.WriteString("      id: requestId,\n")
 	sb.WriteString("    };\n\n")
 
+	sb.WriteString("    const [response, responseBody] = await this.post(this.stringify(requestData), options);\n")
+	sb.WriteString("    return this.decodeResponse(response.status, response.statusText, responseBody);\n")
+	sb.WriteString("  }\n\n")
+
+	writeCallBatchTs(sb, packagePrefix)
+	writeCapabilitiesTransportTs(sb, packagePrefix)
+
+	sb.WriteString("  // POSTs body to the server with the transport's headers and the headers options ask for,\n")
+	sb.WriteString("  // signed if the transport has a signer\n")
+	fmt.Fprintf(sb, "  private async post(body: string, options: %s): Promise<[Response, string]> {\n", optionsName)
 	sb.WriteString("    // Prepare fetch options\n")
 	sb.WriteString("    const headers: Record<string, string> = {\n")
-	sb.WriteString("      'Content-Type': 'application/json',\n")
+	sb.WriteString("      'Content-Type': 'application/json; charset=utf-8',\n")
 	sb.WriteString("      ...this.headers,\n")
-	sb.WriteString("    };\n\n")
+	sb.WriteString("      ...options.headers,\n")
+	sb.WriteString("    };\n")
+	sb.WriteString("    if (options.idempotencyKey) {\n")
+	sb.WriteString("      headers['Idempotency-Key'] = options.idempotencyKey;\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    const timeoutMs = callTimeoutMs(options, headers);\n")
+	sb.WriteString("    if (this.signer !== null) {\n")
+	sb.WriteString("      Object.assign(headers, await this.signer(body));\n")
+	sb.WriteString("    }\n\n")
 
+	sb.WriteString("    return this.send(this.baseUrl, {\n")
+	sb.WriteString("      method: 'POST',\n")
+	sb.WriteString("      headers: headers,\n")
+	sb.WriteString("      body: body,\n")
+	sb.WriteString("      signal: timeoutMs !== undefined ? AbortSignal.timeout(timeoutMs) : undefined,\n")
+	sb.WriteString("    });\n")
+	sb.WriteString("  }\n\n")
+
+	if conditional {
+		writeConditionalRequestsTs(sb, optionsName)
+	}
+
+	sb.WriteString("  // Sends a request and reads the response body, reporting network failures as transport errors\n")
+	sb.WriteString("  private async send(url: string, init: RequestInit): Promise<[Response, string]> {\n")
 	sb.WriteString("    try {\n")
 	sb.WriteString("      // Send request using native fetch (Node.js 18+)\n")
-	sb.WriteString("      const response = await fetch(this.baseUrl, {\n")
-	sb.WriteString("        method: 'POST',\n")
-	sb.WriteString("        headers: headers,\n")
-	sb.WriteString("        body: JSON.stringify(requestData),\n")
-	sb.WriteString("      });\n\n")
+	sb.WriteString("      const response = await fetch(url, init);\n")
+	sb.WriteString("      return [response, await response.text()];\n")
+	sb.WriteString("    } catch (err: any) {\n")
+	sb.WriteString("      // fetch reports the socket error code as the cause\n")
+	sb.WriteString("      const cause = err?.cause?.code;\n")
+	sb.WriteString("      const retryable = cause === 'ECONNREFUSED' || cause === 'ECONNRESET' || cause === 'UND_ERR_SOCKET';\n")
+	fmt.Fprintf(sb, "      throw new %s(`Network error: ${err.message || String(err)}`, 0, retryable);\n", errorClassName)
+	sb.WriteString("    }\n")
+	sb.WriteString("  }\n\n")
 
-	sb.WriteString("      const responseBody = await response.text();\n")
-	sb.WriteString("      let responseData: any;\n")
-	sb.WriteString("      try {\n")
-	sb.WriteString("        responseData = JSON.parse(responseBody);\n")
-	sb.WriteString("      } catch (err) {\n")
-	sb.WriteString("        throw new RPCError(-32700, 'Parse error', `Invalid JSON response: ${err}`);\n")
-	sb.WriteString("      }\n\n")
+	sb.WriteString("  // Decodes a JSON-RPC response, throwing its error as an RPCError\n")
+	sb.WriteString("  private decodeResponse(status: number, statusText: string, responseBody: string): any {\n")
+	sb.WriteString("    let responseData: any;\n")
+	sb.WriteString("    try {\n")
+	sb.WriteString("      responseData = JSON.parse(responseBody);\n")
+	sb.WriteString("    } catch (err) {\n")
+	sb.WriteString("      if (status < 200 || status > 299) {\n")
+	fmt.Fprintf(sb, "        throw new %s(`HTTP error: ${status} ${statusText}`, status);\n", errorClassName)
+	sb.WriteString("      }\n")
+	sb.WriteString("      throw new RPCError(-32700, 'Parse error', `Invalid JSON response: ${err}`);\n")
+	sb.WriteString("    }\n\n")
 
-	sb.WriteString("      // Check for JSON-RPC error\n")
-	sb.WriteString("      if (responseData.error) {\n")
-	sb.WriteString("        const error = responseData.error;\n")
-	sb.WriteString("        const code = error.code || -32603;\n")
-	sb.WriteString("        const message = error.message || 'Internal error';\n")
-	sb.WriteString("        const data = error.data;\n")
-	sb.WriteString("        throw new RPCError(code, message, data);\n")
-	sb.WriteString("      }\n\n")
+	sb.WriteString("    // Check for JSON-RPC error\n")
+	sb.WriteString("    if (responseData.error) {\n")
+	sb.WriteString("      const error = responseData.error;\n")
+	sb.WriteString("      const code = error.code || -32603;\n")
+	sb.WriteString("      const message = error.message || 'Internal error';\n")
+	sb.WriteString("      const data = error.data;\n")
+	sb.WriteString("      throw new RPCError(code, message, data);\n")
+	sb.WriteString("    }\n\n")
 
-	sb.WriteString("      // Return response\n")
-	sb.WriteString("      return responseData;\n")
-	sb.WriteString("    } catch (err: any) {\n")
-	sb.WriteString("      if (err instanceof RPCError) {\n")
-	sb.WriteString("        throw err;\n")
-	sb.WriteString("      }\n")
-	sb.WriteString("      throw new RPCError(-32603, `Network error: ${err.message || String(err)}`, undefined);\n")
-	sb.WriteString("    }\n")
+	sb.WriteString("    // Return response\n")
+	sb.WriteString("    return responseData;\n")
 	sb.WriteString("  }\n")
 	sb.WriteString("}\n\n")
 }
 
 // writeInterfaceClientTs generates a client class for an interface
-func writeInterfaceClientTs(sb *strings.Builder, iface *parser.Interface, _ []*parser.Interface, packagePrefix string) {
+func writeInterfaceClientTs(sb *strings.Builder, iface *parser.Interface, structMap map[string]*parser.Struct, packagePrefix string) {
 	if iface.Comment != "" {
 		lines := strings.Split(strings.TrimSpace(iface.Comment), "\n")
 		for _, line := range lines {
@@ -784,59 +1711,56 @@ func writeInterfaceClientTs(sb *strings.Builder, iface *parser.Interface, _ []*p
 	clientClassName := applyPackagePrefix(iface.Name+"Client", packagePrefix)
 	fmt.Fprintf(sb, "export class %s {\n", clientClassName)
 	sb.WriteString("  private transport: " + transportClassName + ";\n")
-	sb.WriteString("  private methodDefs: any;\n\n")
+	sb.WriteString("  private methodDefs: any;\n")
+	encrypted := interfaceNeedsEncryption(iface, structMap)
+	if encrypted {
+		sb.WriteString("  private fieldCipher: FieldCipher | null = null;\n")
+	}
+	sb.WriteString("\n")
 
 	fmt.Fprintf(sb, "  constructor(transport: %s) {\n", transportClassName)
 	sb.WriteString("    this.transport = transport;\n")
 	sb.WriteString("    // Method definitions for validation\n")
-	sb.WriteString("    this.methodDefs = {\n")
-	for _, method := range iface.Methods {
-		fmt.Fprintf(sb, "      '%s': {\n", method.Name)
-		sb.WriteString("        parameters: [\n")
-		for _, param := range method.Parameters {
-			sb.WriteString("          {\n")
-			fmt.Fprintf(sb, "            name: '%s',\n", param.Name)
-			sb.WriteString("            type: ")
-			writeTypeDictTs(sb, param.Type)
-			sb.WriteString(",\n")
-			sb.WriteString("          },\n")
-		}
-		sb.WriteString("        ],\n")
-		sb.WriteString("        returnType: ")
-		writeTypeDictTs(sb, method.ReturnType)
-		sb.WriteString(",\n")
-		if method.ReturnOptional {
-			sb.WriteString("        returnOptional: true,\n")
-		} else {
-			sb.WriteString("        returnOptional: false,\n")
-		}
-		sb.WriteString("      },\n")
-	}
-	sb.WriteString("    };\n")
+	fmt.Fprintf(sb, "    this.methodDefs = %s['%s'];\n", applyPackagePrefix("METHOD_DEFS", packagePrefix), iface.Name)
 	sb.WriteString("  }\n\n")
 
+	if encrypted {
+		sb.WriteString("  // Sets the cipher that encrypts the [encrypted] fields of params and decrypts those\n")
+		sb.WriteString("  // of results. Calls that carry such fields fail without one.\n")
+		sb.WriteString("  setFieldCipher(cipher: FieldCipher): void {\n")
+		sb.WriteString("    this.fieldCipher = cipher;\n")
+		sb.WriteString("  }\n\n")
+	}
+
 	// Generate methods
 	for _, method := range iface.Methods {
-		writeClientMethodTs(sb, iface, method)
+		writeClientMethodTs(sb, iface, method, structMap, packagePrefix)
 	}
 	sb.WriteString("}\n\n")
 }
 
 // writeClientMethodTs generates a method implementation for a client class
-func writeClientMethodTs(sb *strings.Builder, iface *parser.Interface, method *parser.Method) {
+func writeClientMethodTs(sb *strings.Builder, iface *parser.Interface, method *parser.Method, structMap map[string]*parser.Struct, packagePrefix string) {
 	// Method signature
 	fmt.Fprintf(sb, "  async %s(", method.Name)
-	for i, param := range method.Parameters {
-		if i > 0 {
-			sb.WriteString(", ")
+	for _, param := range method.Parameters {
+		if param.Optional {
+			// Left as null, the server uses the parameter's default
+			fmt.Fprintf(sb, "%s: any = null, ", param.Name)
+		} else {
+			fmt.Fprintf(sb, "%s: any, ", param.Name)
 		}
-		fmt.Fprintf(sb, "%s: any", param.Name)
 	}
-	sb.WriteString("): Promise<any> {\n")
+	fmt.Fprintf(sb, "options: %s = {}): Promise<any> {\n", applyPackagePrefix("CallOptions", packagePrefix))
 
 	// Get method definition
 	fmt.Fprintf(sb, "    const methodDef = this.methodDefs['%s'];\n", method.Name)
-	sb.WriteString("    const params: any[] = [\n")
+	if paramsNeedEncryption(method, structMap) {
+		// Replaced below by the params with their [encrypted] fields encrypted
+		sb.WriteString("    let params: any[] = [\n")
+	} else {
+		sb.WriteString("    const params: any[] = [\n")
+	}
 	for _, param := range method.Parameters {
 		fmt.Fprintf(sb, "      %s,\n", param.Name)
 	}
@@ -847,16 +1771,49 @@ func writeClientMethodTs(sb *strings.Builder, iface *parser.Interface, method *p
 	sb.WriteString("    const expectedParams = methodDef.parameters || [];\n")
 	sb.WriteString("    for (let i = 0; i < params.length; i++) {\n")
 	sb.WriteString("      try {\n")
-	sb.WriteString("        validateType(params[i], expectedParams[i].type, ALL_STRUCTS, ALL_ENUMS, false);\n")
+	if method.RequiredParams() < len(method.Parameters) {
+		sb.WriteString("        validateType(params[i], expectedParams[i].type, ALL_STRUCTS, ALL_ENUMS, expectedParams[i].optional === true);\n")
+	} else {
+		sb.WriteString("        validateType(params[i], expectedParams[i].type, ALL_STRUCTS, ALL_ENUMS, false);\n")
+	}
 	sb.WriteString("      } catch (err: any) {\n")
 	sb.WriteString("        throw new Error(`Parameter ${i} (${expectedParams[i].name}) validation failed: ${err.message}`);\n")
 	sb.WriteString("      }\n")
 	sb.WriteString("    }\n\n")
+	if paramsNeedEncryption(method, structMap) {
+		sb.WriteString("    // Send the params with their [encrypted] fields encrypted\n")
+		sb.WriteString("    params = params.map((value, i) => encryptFields(value, expectedParams[i].type, ALL_STRUCTS, this.fieldCipher));\n\n")
+	}
 
 	// Call transport
 	fmt.Fprintf(sb, "    // Call transport\n")
-	fmt.Fprintf(sb, "    const methodName = '%s.%s';\n", iface.Name, method.Name)
-	sb.WriteString("    const response = await this.transport.call(methodName, params);\n\n")
+	fmt.Fprintf(sb, "    const methodName = '%s';\n", iface.RPCName(method))
+	names := make([]string, len(method.Parameters))
+	for i, param := range method.Parameters {
+		names[i] = "'" + param.Name + "'"
+	}
+	errorClass := ""
+	if name := method.ErrorData(); name != "" {
+		// Throw errors whose data is the [errordata] struct as its RPCError subclass
+		errorClass = applyPackagePrefix(errorDataClass(name), packagePrefix)
+		sb.WriteString("    let response: any;\n")
+		sb.WriteString("    try {\n")
+		fmt.Fprintf(sb, "      response = await callTransport(this.transport, methodName, params, { ...options, paramNames: [%s] });\n", strings.Join(names, ", "))
+		if method.IsAsync() {
+			sb.WriteString("      response = await awaitJob(this.transport, response, options);\n")
+		}
+		sb.WriteString("    } catch (err) {\n")
+		fmt.Fprintf(sb, "      throw bindErrorData(err, %s);\n", errorClass)
+		sb.WriteString("    }\n")
+	} else if method.IsAsync() {
+		fmt.Fprintf(sb, "    let response = await callTransport(this.transport, methodName, params, { ...options, paramNames: [%s] });\n", strings.Join(names, ", "))
+		sb.WriteString("    response = await awaitJob(this.transport, response, options);\n")
+	} else {
+		fmt.Fprintf(sb, "    const response = await callTransport(this.transport, methodName, params, { ...options, paramNames: [%s] });\n", strings.Join(names, ", "))
+	}
+	sb.WriteString("    if (options.responseMeta && response.meta) {\n")
+	sb.WriteString("      Object.assign(options.responseMeta, response.meta);\n")
+	sb.WriteString("    }\n\n")
 
 	// Extract result
 	sb.WriteString("    // Extract result from JSON-RPC response\n")
@@ -865,9 +1822,18 @@ func writeClientMethodTs(sb *strings.Builder, iface *parser.Interface, method *p
 	sb.WriteString("      const code = error.code || -32603;\n")
 	sb.WriteString("      const message = error.message || 'Internal error';\n")
 	sb.WriteString("      const data = error.data;\n")
-	sb.WriteString("      throw new RPCError(code, message, data);\n")
+	if errorClass != "" {
+		fmt.Fprintf(sb, "      throw bindErrorData(new RPCError(code, message, data), %s);\n", errorClass)
+	} else {
+		sb.WriteString("      throw new RPCError(code, message, data);\n")
+	}
 	sb.WriteString("    }\n\n")
-	sb.WriteString("    const result = response.result;\n\n")
+	if typeNeedsEncryption(method.ReturnType, structMap) {
+		sb.WriteString("    // Decrypt the [encrypted] fields of the result before validating it\n")
+		sb.WriteString("    const result = decryptFields(response.result, methodDef.returnType, ALL_STRUCTS, this.fieldCipher);\n\n")
+	} else {
+		sb.WriteString("    const result = response.result;\n\n")
+	}
 
 	// Validate result
 	sb.WriteString("    // Validate result\n")
@@ -887,10 +1853,10 @@ func writeClientMethodTs(sb *strings.Builder, iface *parser.Interface, method *p
 }
 
 // generateTestServerTs generates test_server.ts with concrete implementations of all interfaces
-func generateTestServerTs(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, _ map[string]*parser.Interface, packagePrefix string, _ map[string]*NamespaceTypes, _ string) string {
+func generateTestServerTs(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, _ map[string]*parser.Interface, packagePrefix string, _ map[string]*NamespaceTypes, _ map[string]string, faults bool, admin bool) string {
 	var sb strings.Builder
 
-	sb.WriteString("// Generated by barrister - do not edit\n")
+	sb.WriteString("// Generated by pulserpc - do not edit\n")
 	sb.WriteString("// Test server implementation for integration testing\n\n")
 	serverClassName := applyPackagePrefix("PulseRPCServer", packagePrefix)
 	fmt.Fprintf(&sb, "import { %s", serverClassName)
@@ -913,6 +1879,16 @@ func generateTestServerTs(idl *parser.IDL, structMap map[string]*parser.Struct,
 		implName := applyPackagePrefix(iface.Name+"Impl", packagePrefix)
 		fmt.Fprintf(&sb, "server.register('%s', new %s());\n", iface.Name, implName)
 	}
+	if faults {
+		fmt.Fprintf(&sb, "if (process.env.%s) {\n", faultsEnvVar)
+		fmt.Fprintf(&sb, "  server.loadFaults(process.env.%s);\n", faultsEnvVar)
+		sb.WriteString("}\n")
+	}
+	if admin {
+		fmt.Fprintf(&sb, "if (process.env.%s) {\n", adminTokenEnvVar)
+		fmt.Fprintf(&sb, "  server.enableAdmin(process.env.%s);\n", adminTokenEnvVar)
+		sb.WriteString("}\n")
+	}
 	sb.WriteString("server.serveForever();\n")
 
 	return sb.String()
@@ -1039,8 +2015,8 @@ func writeDefaultTestReturnTs(sb *strings.Builder, returnType *parser.Type, stru
 		if structMap[returnType.UserDefined] != nil {
 			s := structMap[returnType.UserDefined]
 			sb.WriteString("    return {\n")
-			// Handle inheritance - get all fields including parent
-			for _, field := range s.Fields {
+			// Include the fields of every struct it extends
+			for _, field := range parser.ResolveStructFields(s, structMap) {
 				if field.Optional {
 					continue // Skip optional fields in default return
 				}
@@ -1048,24 +2024,6 @@ func writeDefaultTestReturnTs(sb *strings.Builder, returnType *parser.Type, stru
 				writeDefaultTestValueTs(sb, field.Type, structMap, enumMap)
 				sb.WriteString(",\n")
 			}
-			// If extends, add parent fields
-			if s.Extends != "" {
-				baseName := s.Extends
-				if strings.Contains(baseName, ".") {
-					parts := strings.Split(baseName, ".")
-					baseName = parts[len(parts)-1]
-				}
-				if baseStruct := structMap[baseName]; baseStruct != nil {
-					for _, field := range baseStruct.Fields {
-						if field.Optional {
-							continue
-						}
-						fmt.Fprintf(sb, "      %s: ", field.Name)
-						writeDefaultTestValueTs(sb, field.Type, structMap, enumMap)
-						sb.WriteString(",\n")
-					}
-				}
-			}
 			sb.WriteString("    };\n")
 		} else if enumMap[returnType.UserDefined] != nil {
 			// Return first enum value
@@ -1121,10 +2079,10 @@ func writeDefaultTestValueTs(sb *strings.Builder, t *parser.Type, structMap map[
 }
 
 // generateTestClientTs generates test_client.ts that exercises all client methods
-func generateTestClientTs(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, _ map[string]*parser.Interface, packagePrefix string, _ map[string]*NamespaceTypes, _ string) string {
+func generateTestClientTs(idl *parser.IDL, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum, _ map[string]*parser.Interface, packagePrefix string, _ map[string]*NamespaceTypes, _ map[string]string, testVectors bool) string {
 	var sb strings.Builder
 
-	sb.WriteString("// Generated by barrister - do not edit\n")
+	sb.WriteString("// Generated by pulserpc - do not edit\n")
 	sb.WriteString("// Test client for integration testing\n\n")
 	sb.WriteString("/// <reference types=\"node\" />\n\n")
 	transportClassName := applyPackagePrefix("HTTPTransport", packagePrefix)
@@ -1134,7 +2092,11 @@ func generateTestClientTs(idl *parser.IDL, structMap map[string]*parser.Struct,
 		fmt.Fprintf(&sb, ", %s", clientName)
 	}
 	fmt.Fprintf(&sb, " } from './client';\n")
-	sb.WriteString("import * as http from 'http';\n\n")
+	sb.WriteString("import * as http from 'http';\n")
+	if testVectors {
+		sb.WriteString("import * as fs from 'fs';\n")
+	}
+	sb.WriteString("\n")
 
 	// Generate wait for server function
 	sb.WriteString("async function waitForServer(url: string, timeout: number = 10000): Promise<boolean> {\n")
@@ -1183,6 +2145,8 @@ func generateTestClientTs(idl *parser.IDL, structMap map[string]*parser.Struct,
 	sb.WriteString("  return false;\n")
 	sb.WriteString("}\n\n")
 
+	writeConcurrentCallsTestTs(&sb)
+
 	// Generate main test function
 	sb.WriteString("async function main() {\n")
 	sb.WriteString("  const serverUrl = 'http://localhost:8080';\n\n")
@@ -1212,6 +2176,11 @@ func generateTestClientTs(idl *parser.IDL, structMap map[string]*parser.Struct,
 		}
 	}
 
+	sb.WriteString("  errors.push(...(await testConcurrentCalls(transport)));\n\n")
+	if testVectors {
+		sb.WriteString("  errors.push(...(await runTestVectors(serverUrl)));\n\n")
+	}
+
 	sb.WriteString("  // Report results\n")
 	sb.WriteString("  console.log();\n")
 	sb.WriteString("  if (errors.length > 0) {\n")
@@ -1226,6 +2195,10 @@ func generateTestClientTs(idl *parser.IDL, structMap map[string]*parser.Struct,
 	sb.WriteString("  }\n")
 	sb.WriteString("}\n\n")
 
+	if testVectors {
+		writeTestVectorReplayTs(&sb)
+	}
+
 	sb.WriteString("main().catch((err) => {\n")
 	sb.WriteString("  console.error('Fatal error:', err);\n")
 	sb.WriteString("  process.exit(1);\n")
@@ -1234,6 +2207,75 @@ func generateTestClientTs(idl *parser.IDL, structMap map[string]*parser.Struct,
 	return sb.String()
 }
 
+// writeTestVectorReplayTs generates the functions that replay testvectors.json
+// against the server and compare each response with the expected one
+func writeTestVectorReplayTs(sb *strings.Builder) {
+	sb.WriteString("// Compares JSON values ignoring key order; a missing value equals null\n")
+	sb.WriteString("function vectorValuesEqual(expected: any, actual: any): boolean {\n")
+	sb.WriteString("  if (expected === undefined) expected = null;\n")
+	sb.WriteString("  if (actual === undefined) actual = null;\n")
+	sb.WriteString("  if (Array.isArray(expected) || Array.isArray(actual)) {\n")
+	sb.WriteString("    return Array.isArray(expected) && Array.isArray(actual) &&\n")
+	sb.WriteString("      expected.length === actual.length && expected.every((e, i) => vectorValuesEqual(e, actual[i]));\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  if (expected !== null && actual !== null && typeof expected === 'object' && typeof actual === 'object') {\n")
+	sb.WriteString("    const keys = Object.keys(expected);\n")
+	sb.WriteString("    return keys.length === Object.keys(actual).length &&\n")
+	sb.WriteString("      keys.every((k) => k in actual && vectorValuesEqual(expected[k], actual[k]));\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  return expected === actual;\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// Returns an empty string if actual satisfies expected\n")
+	sb.WriteString("function compareTestVector(expected: any, actual: any): string {\n")
+	sb.WriteString("  if (!vectorValuesEqual(expected.id, actual.id)) {\n")
+	sb.WriteString("    return `expected id ${JSON.stringify(expected.id)}, got ${JSON.stringify(actual.id)}`;\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  if ('error' in expected) {\n")
+	sb.WriteString("    if (!actual.error || actual.error.code !== expected.error.code) {\n")
+	sb.WriteString("      return `expected error code ${expected.error.code}, got ${JSON.stringify(actual)}`;\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("    return '';\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  if ('error' in actual) {\n")
+	sb.WriteString("    return `unexpected error ${JSON.stringify(actual.error)}`;\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  if ('result' in expected && !vectorValuesEqual(expected.result, actual.result)) {\n")
+	sb.WriteString("    return `expected result ${JSON.stringify(expected.result)}, got ${JSON.stringify(actual.result)}`;\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  return '';\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// Replays testvectors.json and returns a message for each mismatch\n")
+	sb.WriteString("async function runTestVectors(serverUrl: string): Promise<string[]> {\n")
+	sb.WriteString("  let vectors: any[];\n")
+	sb.WriteString("  try {\n")
+	sb.WriteString("    vectors = JSON.parse(fs.readFileSync('testvectors.json', 'utf8')).vectors;\n")
+	sb.WriteString("  } catch (err: any) {\n")
+	sb.WriteString("    return [`testvectors.json: ${err.message}`];\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  const failures: string[] = [];\n")
+	sb.WriteString("  for (const v of vectors) {\n")
+	sb.WriteString("    try {\n")
+	sb.WriteString("      const response = await fetch(serverUrl, {\n")
+	sb.WriteString("        method: 'POST',\n")
+	sb.WriteString("        headers: { 'Content-Type': 'application/json' },\n")
+	sb.WriteString("        body: JSON.stringify(v.request),\n")
+	sb.WriteString("      });\n")
+	sb.WriteString("      const msg = compareTestVector(v.response, await response.json());\n")
+	sb.WriteString("      if (msg) {\n")
+	sb.WriteString("        failures.push(`vector ${v.name}: ${msg}`);\n")
+	sb.WriteString("      } else {\n")
+	sb.WriteString("        console.log(`✓ vector ${v.name} passed`);\n")
+	sb.WriteString("      }\n")
+	sb.WriteString("    } catch (err: any) {\n")
+	sb.WriteString("      failures.push(`vector ${v.name}: ${err.message}`);\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("  }\n")
+	sb.WriteString("  return failures;\n")
+	sb.WriteString("}\n\n")
+}
+
 // writeTestClientCallTs generates a test call for a method
 func writeTestClientCallTs(sb *strings.Builder, iface *parser.Interface, method *parser.Method, clientVar string, structMap map[string]*parser.Struct, enumMap map[string]*parser.Enum) {
 	testName := fmt.Sprintf("%s.%s", iface.Name, method.Name)
@@ -1368,7 +2410,8 @@ func generateTestParamValueTs(t *parser.Type, paramName string, structMap map[st
 			s := structMap[t.UserDefined]
 			// Build struct object
 			fields := []string{}
-			for _, field := range s.Fields {
+			// Include the fields of every struct it extends
+			for _, field := range parser.ResolveStructFields(s, structMap) {
 				if field.Optional && field.Name == "email" {
 					// Special case: set email to null for putPerson test
 					fields = append(fields, fmt.Sprintf("%s: null", field.Name))
@@ -1377,22 +2420,6 @@ func generateTestParamValueTs(t *parser.Type, paramName string, structMap map[st
 					fields = append(fields, fmt.Sprintf("%s: %s", field.Name, fieldValue))
 				}
 			}
-			// Handle inheritance
-			if s.Extends != "" {
-				baseName := s.Extends
-				if strings.Contains(baseName, ".") {
-					parts := strings.Split(baseName, ".")
-					baseName = parts[len(parts)-1]
-				}
-				if baseStruct := structMap[baseName]; baseStruct != nil {
-					for _, field := range baseStruct.Fields {
-						if !field.Optional {
-							fieldValue := generateTestParamValueTs(field.Type, field.Name, structMap, enumMap)
-							fields = append(fields, fmt.Sprintf("%s: %s", field.Name, fieldValue))
-						}
-					}
-				}
-			}
 			// Special handling for RepeatRequest
 			if t.UserDefined == "RepeatRequest" {
 				return "{ to_repeat: 'hello', count: 3, force_uppercase: false }"
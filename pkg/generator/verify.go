@@ -0,0 +1,276 @@
+package generator
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// -offline asserts that -verify needs no network access, for builds in air-gapped
+// environments: the toolchains run in their offline modes (GOPROXY=off, mvn -o,
+// dotnet restore from an empty local source, cargo --offline), so a build that
+// would download a module, package or crate fails instead. Python, tsc and node
+// never download anything here. Generation itself needs no network either, since
+// templates and runtime files are embedded in pulse.
+
+// Verifier is implemented by plugins that can compile their generated output with
+// the target language's toolchain. It is used by the -verify flag and is called
+// after Generate with the same FlagSet.
+type Verifier interface {
+	Verify(fs *flag.FlagSet) error
+}
+
+// Verify compiles the output of a plugin that has already generated code.
+// It fails if the plugin does not implement Verifier.
+func Verify(p Plugin, fs *flag.FlagSet) error {
+	v, ok := p.(Verifier)
+	if !ok {
+		return fmt.Errorf("plugin %q does not support -verify", p.Name())
+	}
+	if err := v.Verify(fs); err != nil {
+		if verifyOffline(fs) {
+			return fmt.Errorf("%w\n(-offline: dependencies must already be in the local toolchain caches)", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// verifyOffline reports whether the -offline flag is set
+func verifyOffline(fs *flag.FlagSet) bool {
+	f := fs.Lookup("offline")
+	return f != nil && f.Value.String() == "true"
+}
+
+// goVerifyEnv returns the environment go runs with: without a module proxy or a
+// toolchain download when offline
+func goVerifyEnv(fs *flag.FlagSet) []string {
+	if verifyOffline(fs) {
+		return []string{"GOPROXY=off", "GOTOOLCHAIN=local"}
+	}
+	return nil
+}
+
+// verifyOutputDir returns the -dir value used by Generate
+func verifyOutputDir(fs *flag.FlagSet) string {
+	if dirFlag := fs.Lookup("dir"); dirFlag != nil && dirFlag.Value.String() != "" {
+		return dirFlag.Value.String()
+	}
+	return "."
+}
+
+// runToolchain runs a compiler command in dir and turns a failure into an error
+// carrying the compiler's output
+func runToolchain(dir string, env []string, name string, args ...string) error {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return fmt.Errorf("-verify requires %s on PATH: %w", name, err)
+	}
+	cmd := exec.Command(path, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("generated code failed to compile (%s %s): %w\n%s",
+			name, strings.Join(args, " "), err, strings.TrimSpace(out.String()))
+	}
+	return nil
+}
+
+// findGeneratedFiles returns the paths relative to dir of the files with the given
+// extension, skipping build output directories
+func findGeneratedFiles(dir, ext string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case "bin", "obj", "node_modules", "target", "__pycache__":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ext {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// Verify builds and vets the generated Go code. Output that is not inside a Go
+// module is copied to a temporary module named after -go-module first.
+func (p *GoClientServer) Verify(fs *flag.FlagSet) error {
+	outputDir := verifyOutputDir(fs)
+	env := goVerifyEnv(fs)
+
+	goEnv := exec.Command("go", "env", "-C", outputDir, "GOMOD")
+	goEnv.Env = append(os.Environ(), env...)
+	gomod, err := goEnv.Output()
+	if err == nil && strings.TrimSpace(string(gomod)) != "" && strings.TrimSpace(string(gomod)) != os.DevNull {
+		if err := runToolchain(outputDir, env, "go", "build", "./..."); err != nil {
+			return err
+		}
+		return runToolchain(outputDir, env, "go", "vet", "./...")
+	}
+
+	modulePath := defaultGoTestModule
+	if goModuleFlag := fs.Lookup("go-module"); goModuleFlag != nil && goModuleFlag.Value.String() != "" {
+		modulePath = goModuleFlag.Value.String()
+	}
+	tmpDir, err := os.MkdirTemp("", "pulserpc-verify-go-")
+	if err != nil {
+		return fmt.Errorf("failed to create verify directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := os.CopyFS(tmpDir, os.DirFS(outputDir)); err != nil {
+		return fmt.Errorf("failed to copy generated code to %s: %w", tmpDir, err)
+	}
+	if err := runToolchain(tmpDir, env, "go", "mod", "init", modulePath); err != nil {
+		return err
+	}
+	if err := runToolchain(tmpDir, env, "go", "build", "./..."); err != nil {
+		return err
+	}
+	return runToolchain(tmpDir, env, "go", "vet", "./...")
+}
+
+// Verify byte-compiles the generated Python code. Bytecode is written to a
+// temporary directory so the output stays clean.
+func (p *PythonClientServer) Verify(fs *flag.FlagSet) error {
+	python := "python3"
+	if _, err := exec.LookPath(python); err != nil {
+		python = "python"
+	}
+	cacheDir, err := os.MkdirTemp("", "pulserpc-verify-py-")
+	if err != nil {
+		return fmt.Errorf("failed to create verify directory: %w", err)
+	}
+	defer os.RemoveAll(cacheDir)
+	return runToolchain(verifyOutputDir(fs), []string{"PYTHONPYCACHEPREFIX=" + cacheDir}, python, "-m", "compileall", "-q", ".")
+}
+
+// Verify type checks the generated TypeScript code with tsc
+func (p *TSClientServer) Verify(fs *flag.FlagSet) error {
+	outputDir := verifyOutputDir(fs)
+	files, err := findGeneratedFiles(outputDir, ".ts")
+	if err != nil {
+		return fmt.Errorf("failed to list generated TypeScript files: %w", err)
+	}
+	args := []string{"--noEmit", "--target", "ES2020", "--module", "commonjs", "--moduleResolution", "node",
+		"--esModuleInterop", "--skipLibCheck", "--resolveJsonModule", "--types", "node"}
+	return runToolchain(outputDir, nil, "tsc", append(args, files...)...)
+}
+
+// csharpVerifyProject is the view model for a temporary C# verification project
+type csharpVerifyProject struct {
+	Dir        string
+	OutputType string
+	Exclude    []string
+}
+
+// Verify builds the generated C# code with dotnet build, using temporary projects
+// so build output does not land in the output directory. The test server and test
+// client each define Program, so they are built as separate projects like the
+// generated TestServer.csproj and TestClient.csproj.
+func (p *CSharpClientServer) Verify(fs *flag.FlagSet) error {
+	outputDir, err := filepath.Abs(verifyOutputDir(fs))
+	if err != nil {
+		return err
+	}
+	dir := filepath.ToSlash(outputDir)
+
+	projects := []csharpVerifyProject{{Dir: dir, OutputType: "Library", Exclude: []string{"TestServer.cs", "TestClient.cs"}}}
+	if _, err := os.Stat(filepath.Join(outputDir, "TestServer.cs")); err == nil {
+		projects = []csharpVerifyProject{
+			{Dir: dir, OutputType: "Exe", Exclude: append(append([]string{}, csharpClientFiles...), "TestClient.cs")},
+			{Dir: dir, OutputType: "Exe", Exclude: []string{"Server.cs", "TestServer.cs"}},
+		}
+	}
+	// The xUnit harness needs NuGet packages, which verification does not restore
+	for i := range projects {
+		projects[i].Exclude = append(projects[i].Exclude, "HarnessTests.cs")
+	}
+
+	for _, project := range projects {
+		if err := buildCSharpVerifyProject(project, verifyOffline(fs)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildCSharpVerifyProject builds project in a temporary directory. Offline, that
+// directory is the only package source, so a restore that needs a package fails.
+func buildCSharpVerifyProject(project csharpVerifyProject, offline bool) error {
+	tmpDir, err := os.MkdirTemp("", "pulserpc-verify-cs-")
+	if err != nil {
+		return fmt.Errorf("failed to create verify directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	content := embeddedTemplates.renderString("csharp/verify.csproj.tmpl", project)
+	if err := os.WriteFile(filepath.Join(tmpDir, "Verify.csproj"), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write verify project: %w", err)
+	}
+	args := []string{"build", "Verify.csproj", "-nologo", "-v", "quiet"}
+	if offline {
+		args = append(args, "--source", tmpDir)
+	}
+	return runToolchain(tmpDir, []string{"DOTNET_CLI_TELEMETRY_OPTOUT=1", "DOTNET_NOLOGO=1"}, "dotnet", args...)
+}
+
+// Verify compiles the generated Java code. With a pom.xml and Maven available it
+// runs mvn compile; otherwise it runs javac, which needs the -json-lib library on
+// CLASSPATH.
+func (p *JavaClientServer) Verify(fs *flag.FlagSet) error {
+	outputDir := verifyOutputDir(fs)
+	if _, err := os.Stat(filepath.Join(outputDir, "pom.xml")); err == nil {
+		if _, err := exec.LookPath("mvn"); err == nil {
+			args := []string{"-q", "test-compile"}
+			if verifyOffline(fs) {
+				args = append([]string{"-o"}, args...)
+			}
+			return runToolchain(outputDir, nil, "mvn", args...)
+		}
+	}
+
+	files, err := findGeneratedFiles(outputDir, ".java")
+	if err != nil {
+		return fmt.Errorf("failed to list generated Java files: %w", err)
+	}
+	classDir, err := os.MkdirTemp("", "pulserpc-verify-java-")
+	if err != nil {
+		return fmt.Errorf("failed to create verify directory: %w", err)
+	}
+	defer os.RemoveAll(classDir)
+	return runToolchain(outputDir, nil, "javac", append([]string{"-d", classDir}, files...)...)
+}
+
+// Verify type checks the generated Rust crate with cargo check. Build output goes
+// to a temporary target directory so the output stays clean.
+func (p *RustClientServer) Verify(fs *flag.FlagSet) error {
+	targetDir, err := os.MkdirTemp("", "pulserpc-verify-rust-")
+	if err != nil {
+		return fmt.Errorf("failed to create verify directory: %w", err)
+	}
+	defer os.RemoveAll(targetDir)
+	args := []string{"check", "--quiet", "--all-targets"}
+	if verifyOffline(fs) {
+		args = append(args, "--offline")
+	}
+	return runToolchain(verifyOutputDir(fs), []string{"CARGO_TARGET_DIR=" + targetDir}, "cargo", args...)
+}
@@ -0,0 +1,110 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+type noVerifyPlugin struct{}
+
+func (noVerifyPlugin) Name() string                                     { return "no-verify" }
+func (noVerifyPlugin) RegisterFlags(fs *flag.FlagSet)                   {}
+func (noVerifyPlugin) Generate(idl *parser.IDL, fs *flag.FlagSet) error { return nil }
+
+func newVerifyFlagSet(t *testing.T, dir string) *flag.FlagSet {
+	t.Helper()
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	fs.String("dir", "", "output dir")
+	fs.String("go-module", "", "go module")
+	if err := fs.Set("dir", dir); err != nil {
+		t.Fatalf("failed to set dir flag: %v", err)
+	}
+	return fs
+}
+
+func TestVerifyUnsupportedPlugin(t *testing.T) {
+	err := Verify(noVerifyPlugin{}, newVerifyFlagSet(t, t.TempDir()))
+	if err == nil || !strings.Contains(err.Error(), "does not support -verify") {
+		t.Errorf("expected unsupported plugin error, got %v", err)
+	}
+}
+
+func TestPythonVerify(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+	dir := t.TempDir()
+	fs := newVerifyFlagSet(t, dir)
+	p := NewPythonClientServer()
+
+	if err := os.WriteFile(filepath.Join(dir, "ok.py"), []byte("x = 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Verify(fs); err != nil {
+		t.Fatalf("expected valid Python to verify: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "__pycache__")); !os.IsNotExist(err) {
+		t.Errorf("expected no __pycache__ in the output directory")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.py"), []byte("def broken(:\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := p.Verify(fs)
+	if err == nil || !strings.Contains(err.Error(), "broken.py") {
+		t.Errorf("expected compile error naming broken.py, got %v", err)
+	}
+}
+
+func TestGoVerifyOutsideModule(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not available")
+	}
+	dir := t.TempDir()
+	fs := newVerifyFlagSet(t, dir)
+	p := NewGoClientServer()
+
+	src := "package generated\n\nfunc Answer() int { return missing }\n"
+	if err := os.WriteFile(filepath.Join(dir, "types.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := p.Verify(fs)
+	if err == nil || !strings.Contains(err.Error(), "undefined: missing") {
+		t.Errorf("expected type error from go build, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); !os.IsNotExist(err) {
+		t.Errorf("expected no go.mod to be written to the output directory")
+	}
+}
+
+func TestGoVerifyOffline(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not available")
+	}
+	dir := t.TempDir()
+	fs := newVerifyFlagSet(t, dir)
+	fs.Bool("offline", false, "offline")
+	if err := fs.Set("offline", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"go.mod":   "module example.com/generated\n\ngo 1.21\n\nrequire example.com/notcached v1.0.0\n",
+		"types.go": "package generated\n\nimport _ \"example.com/notcached\"\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := Verify(NewGoClientServer(), fs)
+	if err == nil || !strings.Contains(err.Error(), "GOPROXY=off") || !strings.Contains(err.Error(), "-offline") {
+		t.Errorf("expected the module download to be refused, got %v", err)
+	}
+}
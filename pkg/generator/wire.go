@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Wire names: a [wire] annotation gives a method, or every method of an
+// interface, a JSON-RPC name other than "Interface.method" (see
+// parser.Interface.RPCName). Clients send the mapped name. Servers keep a table
+// of the mapped names and translate a call's method back to Interface.method
+// before dispatching it, so handlers, method tables and response meta hooks see
+// the same names as without the mapping. Calls by the Interface.method name are
+// still served. The table is only written when the IDL maps a name, so other
+// servers are unchanged.
+
+// wireMethod pairs the JSON-RPC name of a mapped method with its Interface.method name
+type wireMethod struct {
+	Wire      string
+	Canonical string
+}
+
+// wireMethods returns the methods, inherited ones included, whose JSON-RPC name
+// differs from Interface.method, in declaration order
+func wireMethods(interfaces []*parser.Interface) []wireMethod {
+	var methods []wireMethod
+	for _, iface := range interfaces {
+		for _, method := range iface.Methods {
+			canonical := iface.Name + "." + method.Name
+			if wire := iface.RPCName(method); wire != canonical {
+				methods = append(methods, wireMethod{Wire: wire, Canonical: canonical})
+			}
+		}
+	}
+	return methods
+}
+
+// usesWireNames returns true if any method has a JSON-RPC name set by [wire]
+func usesWireNames(interfaces []*parser.Interface) bool {
+	return len(wireMethods(interfaces)) > 0
+}
+
+// writeWireMethodsGo writes the wireMethods table of the Go server
+func writeWireMethodsGo(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("// wireMethods maps the JSON-RPC names set by [wire] to the interface.method\n")
+	sb.WriteString("// names calls are dispatched by\n")
+	sb.WriteString("var wireMethods = map[string]string{\n")
+	for _, m := range wireMethods(interfaces) {
+		fmt.Fprintf(sb, "	%q: %q,\n", m.Wire, m.Canonical)
+	}
+	sb.WriteString("}\n\n")
+}
+
+// writeWireMethodsPy writes the WIRE_METHODS table of the Python server
+func writeWireMethodsPy(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("# JSON-RPC names set by [wire], mapped to the interface.method names calls are\n")
+	sb.WriteString("# dispatched by\n")
+	sb.WriteString("WIRE_METHODS = {\n")
+	for _, m := range wireMethods(interfaces) {
+		fmt.Fprintf(sb, "    '%s': '%s',\n", m.Wire, m.Canonical)
+	}
+	sb.WriteString("}\n\n\n")
+}
+
+// writeWireMethodsTs writes the WIRE_METHODS table of the TypeScript server
+func writeWireMethodsTs(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("// JSON-RPC names set by [wire], mapped to the interface.method names calls are\n")
+	sb.WriteString("// dispatched by\n")
+	sb.WriteString("const WIRE_METHODS: Record<string, string> = {\n")
+	for _, m := range wireMethods(interfaces) {
+		fmt.Fprintf(sb, "  '%s': '%s',\n", m.Wire, m.Canonical)
+	}
+	sb.WriteString("};\n\n")
+}
+
+// writeWireMethodsCs writes the WireMethods table of the C# server
+func writeWireMethodsCs(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("    // JSON-RPC names set by [wire], mapped to the interface.method names calls are\n")
+	sb.WriteString("    // dispatched by\n")
+	sb.WriteString("    private static readonly Dictionary<string, string> WireMethods = new Dictionary<string, string>\n")
+	sb.WriteString("    {\n")
+	for _, m := range wireMethods(interfaces) {
+		fmt.Fprintf(sb, "        { %q, %q },\n", m.Wire, m.Canonical)
+	}
+	sb.WriteString("    };\n\n")
+}
+
+// writeWireMethodsJava writes the WIRE_METHODS table of the Java server
+func writeWireMethodsJava(sb *strings.Builder, interfaces []*parser.Interface) {
+	sb.WriteString("    // JSON-RPC names set by [wire], mapped to the interface.method names calls are\n")
+	sb.WriteString("    // dispatched by\n")
+	sb.WriteString("    private static final Map<String, String> WIRE_METHODS = Map.ofEntries(\n")
+	methods := wireMethods(interfaces)
+	for i, m := range methods {
+		sep := ","
+		if i == len(methods)-1 {
+			sep = ""
+		}
+		fmt.Fprintf(sb, "        Map.entry(%q, %q)%s\n", m.Wire, m.Canonical, sep)
+	}
+	sb.WriteString("    );\n\n")
+}
@@ -0,0 +1,293 @@
+// Package idl builds IDL models in code and serializes them to IDL text or idl.json.
+//
+// A Builder produces the same parser.IDL model that parser.ParseIDL returns for an
+// equivalent .pulse file:
+//
+//	b := idl.New("catalog")
+//	b.Enum("Status", "active", "retired")
+//	b.Struct("Book").
+//		Field("isbn", idl.String()).
+//		Field("status", idl.Ref("Status")).
+//		Field("subtitle", idl.String(), idl.Optional())
+//	b.Interface("BookService").
+//		Method("getBook").Param("isbn", idl.String()).Returns(idl.Ref("Book"), idl.Optional()).Annotate("readonly", "").
+//		Method("listBooks").Returns(idl.Array(idl.Ref("Book")))
+//	text, err := b.Text()
+package idl
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Builder constructs the IDL for a single namespace. Elements are kept in the
+// order they are added.
+type Builder struct {
+	idl *parser.IDL
+}
+
+// New returns a Builder for the given namespace
+func New(namespace string) *Builder {
+	return &Builder{idl: &parser.IDL{RootNamespace: namespace}}
+}
+
+// Interface adds an interface and returns a builder for its methods
+func (b *Builder) Interface(name string) *InterfaceBuilder {
+	iface := &parser.Interface{Name: name, Namespace: b.idl.RootNamespace}
+	b.idl.Interfaces = append(b.idl.Interfaces, iface)
+	return &InterfaceBuilder{iface: iface}
+}
+
+// Struct adds a struct and returns a builder for its fields
+func (b *Builder) Struct(name string) *StructBuilder {
+	s := &parser.Struct{Name: name, Namespace: b.idl.RootNamespace}
+	b.idl.Structs = append(b.idl.Structs, s)
+	return &StructBuilder{s: s}
+}
+
+// Enum adds an enum with the given values and returns a builder for it
+func (b *Builder) Enum(name string, values ...string) *EnumBuilder {
+	e := &parser.Enum{Name: name, Namespace: b.idl.RootNamespace}
+	b.idl.Enums = append(b.idl.Enums, e)
+	eb := &EnumBuilder{e: e}
+	for _, v := range values {
+		eb.Value(v)
+	}
+	return eb
+}
+
+// Typedef adds a named array or map type, which fields, parameters and return
+// types refer to with Ref. Pass Doc() to document it.
+func (b *Builder) Typedef(name string, t *parser.Type, opts ...Option) {
+	td := &parser.Typedef{Name: name, Namespace: b.idl.RootNamespace, Type: t, Comment: applyOptions(opts).comment}
+	b.idl.Typedefs = append(b.idl.Typedefs, td)
+}
+
+// Build resolves typedefs and interface inheritance, validates the model and
+// returns it. The returned IDL is shared with the Builder, so later calls on the
+// Builder modify it.
+func (b *Builder) Build() (*parser.IDL, error) {
+	parser.ResolveTypedefs(b.idl)
+	parser.ResolveInterfaceInheritance(b.idl)
+	for _, iface := range b.idl.Interfaces {
+		for _, m := range iface.Methods {
+			if m.ReturnType == nil {
+				return nil, fmt.Errorf("method %s.%s has no return type", iface.Name, m.Name)
+			}
+		}
+	}
+	if err := parser.ValidateIDL(b.idl); err != nil {
+		return nil, err
+	}
+	return b.idl, nil
+}
+
+// Text validates the model and returns it as IDL source
+func (b *Builder) Text() (string, error) {
+	doc, err := b.Build()
+	if err != nil {
+		return "", err
+	}
+	return Format(doc), nil
+}
+
+// JSON validates the model and returns it in the idl.json format
+func (b *Builder) JSON() ([]byte, error) {
+	doc, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// InterfaceBuilder adds methods to an interface
+type InterfaceBuilder struct {
+	iface *parser.Interface
+}
+
+// Comment sets the interface's doc comment
+func (ib *InterfaceBuilder) Comment(comment string) *InterfaceBuilder {
+	ib.iface.Comment = comment
+	return ib
+}
+
+// Extends appends interfaces whose methods this interface inherits
+func (ib *InterfaceBuilder) Extends(parents ...string) *InterfaceBuilder {
+	ib.iface.Extends = append(ib.iface.Extends, parents...)
+	return ib
+}
+
+// Annotate adds an interface annotation such as Annotate("wire", "v1.user")
+func (ib *InterfaceBuilder) Annotate(name, value string) *InterfaceBuilder {
+	ib.iface.Annotations = append(ib.iface.Annotations, &parser.Annotation{Name: name, Value: value})
+	return ib
+}
+
+// Method adds a method. Every method needs a return type set with Returns.
+func (ib *InterfaceBuilder) Method(name string) *MethodBuilder {
+	m := &parser.Method{Name: name}
+	ib.iface.Methods = append(ib.iface.Methods, m)
+	return &MethodBuilder{iface: ib, m: m}
+}
+
+// MethodBuilder sets a method's parameters, return type and annotations
+type MethodBuilder struct {
+	iface *InterfaceBuilder
+	m     *parser.Method
+}
+
+// Param appends a parameter. Pass Optional() or Default() to let calls leave it
+// out; optional parameters must come last.
+func (mb *MethodBuilder) Param(name string, t *parser.Type, opts ...Option) *MethodBuilder {
+	o := applyOptions(opts)
+	param := &parser.Parameter{Name: name, Type: t, Optional: o.optional}
+	if o.defaultValue != nil {
+		param.Optional = true
+		param.Annotations = append(param.Annotations, &parser.Annotation{Name: parser.AnnotationDefault, Value: *o.defaultValue})
+	}
+	mb.m.Parameters = append(mb.m.Parameters, param)
+	return mb
+}
+
+// Returns sets the return type. Pass Optional() to allow a null result.
+func (mb *MethodBuilder) Returns(t *parser.Type, opts ...Option) *MethodBuilder {
+	mb.m.ReturnType = t
+	mb.m.ReturnOptional = applyOptions(opts).optional
+	return mb
+}
+
+// Annotate adds an annotation such as ("readonly", "") or ("name", "value")
+func (mb *MethodBuilder) Annotate(name, value string) *MethodBuilder {
+	mb.m.Annotations = append(mb.m.Annotations, &parser.Annotation{Name: name, Value: value})
+	return mb
+}
+
+// Example adds a sample call, with params and result as JSON text such as
+// Example(`["978-0"]`, `{"isbn": "978-0"}`). Build checks them against the
+// method's types.
+func (mb *MethodBuilder) Example(params, result string) *MethodBuilder {
+	mb.m.Examples = append(mb.m.Examples, &parser.Example{Params: json.RawMessage(params), Result: json.RawMessage(result)})
+	return mb
+}
+
+// Method adds another method to the same interface
+func (mb *MethodBuilder) Method(name string) *MethodBuilder {
+	return mb.iface.Method(name)
+}
+
+// StructBuilder adds fields to a struct
+type StructBuilder struct {
+	s *parser.Struct
+}
+
+// Comment sets the struct's doc comment
+func (sb *StructBuilder) Comment(comment string) *StructBuilder {
+	sb.s.Comment = comment
+	return sb
+}
+
+// Extends sets the parent struct
+func (sb *StructBuilder) Extends(parent string) *StructBuilder {
+	sb.s.Extends = parent
+	return sb
+}
+
+// Field appends a field. Pass Optional(), Sensitive(), Encrypted() and Doc() to mark it
+// optional, sensitive or encrypted, or to document it.
+func (sb *StructBuilder) Field(name string, t *parser.Type, opts ...Option) *StructBuilder {
+	o := applyOptions(opts)
+	field := &parser.Field{Name: name, Type: t, Optional: o.optional, Comment: o.comment}
+	if o.sensitive {
+		field.Annotations = append(field.Annotations, &parser.Annotation{Name: parser.AnnotationSensitive})
+	}
+	if o.encrypted {
+		field.Annotations = append(field.Annotations, &parser.Annotation{Name: parser.AnnotationEncrypted})
+	}
+	sb.s.Fields = append(sb.s.Fields, field)
+	return sb
+}
+
+// EnumBuilder adds values to an enum
+type EnumBuilder struct {
+	e *parser.Enum
+}
+
+// Comment sets the enum's doc comment
+func (eb *EnumBuilder) Comment(comment string) *EnumBuilder {
+	eb.e.Comment = comment
+	return eb
+}
+
+// Value appends a value. Pass Doc() to document it.
+func (eb *EnumBuilder) Value(name string, opts ...Option) *EnumBuilder {
+	eb.e.Values = append(eb.e.Values, &parser.EnumValue{Name: name, Comment: applyOptions(opts).comment})
+	return eb
+}
+
+// Option modifies a field, parameter, enum value, typedef or return type
+type Option func(*options)
+
+type options struct {
+	optional     bool
+	sensitive    bool
+	encrypted    bool
+	comment      string
+	defaultValue *string
+}
+
+// Optional marks a field, parameter or return type [optional]
+func Optional() Option {
+	return func(o *options) { o.optional = true }
+}
+
+// Default gives an optional parameter the value it takes when a call leaves it
+// out, written as in the IDL, e.g. Default("10")
+func Default(value string) Option {
+	return func(o *options) { o.defaultValue = &value }
+}
+
+// Sensitive marks a field [sensitive]
+func Sensitive() Option {
+	return func(o *options) { o.sensitive = true }
+}
+
+// Encrypted marks a field [encrypted]
+func Encrypted() Option {
+	return func(o *options) { o.encrypted = true }
+}
+
+// Doc sets the comment on a field or enum value
+func Doc(comment string) Option {
+	return func(o *options) { o.comment = comment }
+}
+
+func applyOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// String returns the built-in string type
+func String() *parser.Type { return &parser.Type{BuiltIn: "string"} }
+
+// Int returns the built-in int type
+func Int() *parser.Type { return &parser.Type{BuiltIn: "int"} }
+
+// Float returns the built-in float type
+func Float() *parser.Type { return &parser.Type{BuiltIn: "float"} }
+
+// Bool returns the built-in bool type
+func Bool() *parser.Type { return &parser.Type{BuiltIn: "bool"} }
+
+// Array returns an array of elem
+func Array(elem *parser.Type) *parser.Type { return &parser.Type{Array: elem} }
+
+// Map returns a map from string to value
+func Map(value *parser.Type) *parser.Type { return &parser.Type{MapValue: value} }
+
+// Ref returns a reference to a struct or enum, e.g. "Book" or "inc.Status"
+func Ref(name string) *parser.Type { return &parser.Type{UserDefined: name} }
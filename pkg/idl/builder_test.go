@@ -0,0 +1,160 @@
+package idl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+func catalogBuilder() *Builder {
+	b := New("catalog")
+	b.Enum("Status", "active").Comment("Lifecycle of a book").Value("retired", Doc("No longer sold"))
+	b.Typedef("Tags", Array(String()), Doc("Free-form labels"))
+	b.Struct("Entity").Field("id", String())
+	b.Struct("Book").
+		Extends("Entity").
+		Comment("A book in the catalog").
+		Field("title", String(), Doc("Display title")).
+		Field("status", Ref("Status")).
+		Field("tags", Ref("Tags")).
+		Field("prices", Map(Float())).
+		Field("pages", Int(), Optional()).
+		Field("licenseKey", String(), Optional(), Sensitive(), Encrypted())
+	b.Interface("BookService").
+		Comment("Book lookups").
+		Method("getBook").Param("id", String()).Returns(Ref("Book"), Optional()).Annotate("readonly", "").
+		Method("listBooks").Param("limit", Int(), Default("20")).Param("status", Ref("Status"), Optional()).Returns(Array(Ref("Book"))).
+		Method("ping").Returns(Bool())
+	b.Interface("AdminService").
+		Extends("BookService").
+		Annotate("wire", "v1.admin").
+		Method("purge").Returns(Int())
+	return b
+}
+
+func TestBuilderTextRoundTrip(t *testing.T) {
+	b := catalogBuilder()
+	text, err := b.Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	for _, want := range []string{
+		"namespace catalog\n",
+		"  getBook(id string) Book [optional] [readonly]\n",
+		"  listBooks(limit int [default=\"20\"], status Status [optional]) []Book\n",
+		"struct Book extends Entity {\n",
+		"  // Display title\n  title string\n",
+		"  pages int [optional]\n",
+		"  licenseKey string [optional] [sensitive] [encrypted]\n",
+		"  // No longer sold\n  retired\n",
+		"interface AdminService extends BookService [wire=\"v1.admin\"] {\n  purge() int\n}\n",
+		"// Free-form labels\ntypedef Tags []string\n",
+		"  tags Tags\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected IDL text to contain %q:\n%s", want, text)
+		}
+	}
+
+	parsed, err := parser.ParseIDL("catalog.pulse", text)
+	if err != nil {
+		t.Fatalf("generated IDL does not parse: %v\n%s", err, text)
+	}
+	built, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if got, want := mustJSON(t, parsed), mustJSON(t, built); got != want {
+		t.Errorf("parsed model differs from built model:\nparsed: %s\nbuilt:  %s", got, want)
+	}
+}
+
+func TestBuilderJSON(t *testing.T) {
+	data, err := catalogBuilder().JSON()
+	if err != nil {
+		t.Fatalf("JSON failed: %v", err)
+	}
+	var doc parser.IDL
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid idl.json: %v", err)
+	}
+	if !strings.Contains(string(data), `"idlVersion": 2`) {
+		t.Errorf("expected idlVersion in idl.json:\n%s", data)
+	}
+	if doc.RootNamespace != "catalog" || len(doc.Interfaces) != 2 || len(doc.Interfaces[0].Methods) != 3 {
+		t.Errorf("unexpected model: %+v", doc)
+	}
+}
+
+func TestBuilderValidation(t *testing.T) {
+	b := New("catalog")
+	b.Interface("BookService").Method("getBook").Param("id", String())
+	if _, err := b.Build(); err == nil || !strings.Contains(err.Error(), "BookService.getBook has no return type") {
+		t.Errorf("expected missing return type error, got %v", err)
+	}
+
+	b = New("catalog")
+	b.Struct("Book").Field("author", Ref("Author"))
+	if _, err := b.Text(); err == nil {
+		t.Error("expected validation error for an undefined type")
+	}
+
+	b = New("catalog")
+	b.Interface("AdminService").Extends("BookService").Method("purge").Returns(Int())
+	if _, err := b.Build(); err == nil || !strings.Contains(err.Error(), "extends unknown interface BookService") {
+		t.Errorf("expected unknown parent interface error, got %v", err)
+	}
+}
+
+func TestFormatParsedIDL(t *testing.T) {
+	doc := &parser.IDL{
+		Structs: []*parser.Struct{
+			{Name: "inc.Response", Namespace: "inc", Fields: []*parser.Field{{Name: "ok", Type: Bool()}}},
+			{Name: "Order", Namespace: "shop", Extends: "inc.Response"},
+		},
+	}
+	text := Format(doc)
+	if !strings.Contains(text, "namespace inc\n\nstruct Response {\n") {
+		t.Errorf("expected unqualified declaration in its namespace:\n%s", text)
+	}
+	if strings.Index(text, "namespace inc") > strings.Index(text, "namespace shop") {
+		t.Errorf("expected namespaces in name order:\n%s", text)
+	}
+}
+
+func TestExamplesRoundTrip(t *testing.T) {
+	b := New("catalog")
+	b.Interface("BookService").
+		Method("count").Param("shelf", String()).Returns(Int()).Example(`["fiction"]`, `12`)
+	text, err := b.Text()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(text, `  @example(params=["fiction"], result=12)
+  count(shelf string) int`) {
+		t.Errorf("expected the example above the method:\n%s", text)
+	}
+	doc, err := parser.ParseIDL("catalog.pulse", text)
+	if err != nil {
+		t.Fatalf("formatted IDL does not parse: %v\n%s", err, text)
+	}
+	if got := string(doc.Interfaces[0].Methods[0].Examples[0].Result); got != "12" {
+		t.Errorf("expected result 12, got %s", got)
+	}
+
+	b.Interface("ShelfService").Method("size").Returns(Int()).Example(`[]`, `"big"`)
+	if _, err := b.Build(); err == nil || !strings.Contains(err.Error(), "result is not a valid int") {
+		t.Errorf("expected invalid example result error, got %v", err)
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,200 @@
+package idl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Format renders an IDL model as IDL source. Elements without a namespace come
+// first, followed by each namespace in name order with its typedefs, interfaces,
+// structs and enums. Declarations use the unqualified name, so a model from parser.ParseIDL or
+// a Builder formats back to source that parses to the same model.
+func Format(doc *parser.IDL) string {
+	var sb strings.Builder
+
+	// Group elements by namespace
+	namespaceInterfaces := make(map[string][]*parser.Interface)
+	namespaceStructs := make(map[string][]*parser.Struct)
+	namespaceEnums := make(map[string][]*parser.Enum)
+	namespaceTypedefs := make(map[string][]*parser.Typedef)
+	allNamespaces := make(map[string]bool)
+
+	for _, iface := range doc.Interfaces {
+		namespaceInterfaces[iface.Namespace] = append(namespaceInterfaces[iface.Namespace], iface)
+		allNamespaces[iface.Namespace] = true
+	}
+	for _, s := range doc.Structs {
+		namespaceStructs[s.Namespace] = append(namespaceStructs[s.Namespace], s)
+		allNamespaces[s.Namespace] = true
+	}
+	for _, e := range doc.Enums {
+		namespaceEnums[e.Namespace] = append(namespaceEnums[e.Namespace], e)
+		allNamespaces[e.Namespace] = true
+	}
+	for _, td := range doc.Typedefs {
+		namespaceTypedefs[td.Namespace] = append(namespaceTypedefs[td.Namespace], td)
+		allNamespaces[td.Namespace] = true
+	}
+
+	namespaces := make([]string, 0, len(allNamespaces))
+	for ns := range allNamespaces {
+		namespaces = append(namespaces, ns)
+	}
+	// The empty namespace sorts first, so elements without one come before any declaration
+	sort.Strings(namespaces)
+
+	for _, ns := range namespaces {
+		if ns != "" {
+			fmt.Fprintf(&sb, "namespace %s\n\n", ns)
+		}
+		for _, td := range namespaceTypedefs[ns] {
+			writeTypedef(&sb, td)
+		}
+		for _, iface := range namespaceInterfaces[ns] {
+			writeInterface(&sb, iface)
+		}
+		for _, s := range namespaceStructs[ns] {
+			writeStruct(&sb, s)
+		}
+		for _, e := range namespaceEnums[ns] {
+			writeEnum(&sb, e)
+		}
+	}
+
+	return sb.String()
+}
+
+// declName strips the namespace qualifier the parser adds to imported elements
+func declName(name, namespace string) string {
+	return strings.TrimPrefix(name, namespace+".")
+}
+
+// typeText returns a type as written in IDL source, using typedef names
+func typeText(t *parser.Type) string {
+	switch {
+	case t.Alias != "":
+		return t.Alias
+	case t.IsArray():
+		return "[]" + typeText(t.Array)
+	case t.IsMap():
+		return "map[string]" + typeText(t.MapValue)
+	}
+	return t.String()
+}
+
+func writeTypedef(sb *strings.Builder, td *parser.Typedef) {
+	if td.Comment != "" {
+		writeComment(sb, "", td.Comment)
+	}
+	fmt.Fprintf(sb, "typedef %s %s\n\n", declName(td.Name, td.Namespace), typeText(td.Type))
+}
+
+func writeInterface(sb *strings.Builder, iface *parser.Interface) {
+	if iface.Comment != "" {
+		writeComment(sb, "", iface.Comment)
+	}
+	fmt.Fprintf(sb, "interface %s", declName(iface.Name, iface.Namespace))
+	if len(iface.Extends) > 0 {
+		fmt.Fprintf(sb, " extends %s", strings.Join(iface.Extends, ", "))
+	}
+	for _, a := range iface.Annotations {
+		fmt.Fprintf(sb, " [%s=\"%s\"]", a.Name, a.Value)
+	}
+	sb.WriteString(" {\n")
+	// Inherited methods are declared by the parent interfaces
+	for _, method := range iface.OwnMethods() {
+		for _, example := range method.Examples {
+			fmt.Fprintf(sb, "  @example(params=%s, result=%s)\n", compactJSON(example.Params), compactJSON(example.Result))
+		}
+		fmt.Fprintf(sb, "  %s(", method.Name)
+		for i, param := range method.Parameters {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(sb, "%s %s", param.Name, typeText(param.Type))
+			// A default makes the parameter optional on its own
+			if _, hasDefault := param.Default(); param.Optional && !hasDefault {
+				sb.WriteString(" [optional]")
+			}
+			for _, a := range param.Annotations {
+				fmt.Fprintf(sb, " [%s=\"%s\"]", a.Name, a.Value)
+			}
+		}
+		fmt.Fprintf(sb, ") %s", typeText(method.ReturnType))
+		if method.ReturnOptional {
+			sb.WriteString(" [optional]")
+		}
+		for _, a := range method.Annotations {
+			if a.Value != "" {
+				fmt.Fprintf(sb, " [%s=\"%s\"]", a.Name, a.Value)
+			} else {
+				fmt.Fprintf(sb, " [%s]", a.Name)
+			}
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("}\n\n")
+}
+
+// compactJSON returns data without insignificant whitespace, as idl.json may be indented
+func compactJSON(data []byte) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return string(data)
+	}
+	return buf.String()
+}
+
+func writeStruct(sb *strings.Builder, s *parser.Struct) {
+	if s.Comment != "" {
+		writeComment(sb, "", s.Comment)
+	}
+	name := declName(s.Name, s.Namespace)
+	fmt.Fprintf(sb, "struct %s", name)
+	if s.Extends != "" {
+		fmt.Fprintf(sb, " extends %s", s.Extends)
+	}
+	for _, a := range s.Annotations {
+		fmt.Fprintf(sb, " [%s=\"%s\"]", a.Name, a.Value)
+	}
+	sb.WriteString(" {\n")
+	for _, field := range s.Fields {
+		if field.Comment != "" {
+			writeComment(sb, "  ", field.Comment)
+		}
+		fmt.Fprintf(sb, "  %s %s", field.Name, typeText(field.Type))
+		if field.Optional {
+			sb.WriteString(" [optional]")
+		}
+		for _, a := range field.Annotations {
+			fmt.Fprintf(sb, " [%s]", a.Name)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("}\n\n")
+}
+
+func writeEnum(sb *strings.Builder, enum *parser.Enum) {
+	if enum.Comment != "" {
+		writeComment(sb, "", enum.Comment)
+	}
+	fmt.Fprintf(sb, "enum %s {\n", declName(enum.Name, enum.Namespace))
+	for _, value := range enum.Values {
+		if value.Comment != "" {
+			writeComment(sb, "  ", value.Comment)
+		}
+		fmt.Fprintf(sb, "  %s\n", value.Name)
+	}
+	sb.WriteString("}\n\n")
+}
+
+func writeComment(sb *strings.Builder, indent, comment string) {
+	for _, line := range strings.Split(comment, "\n") {
+		fmt.Fprintf(sb, "%s// %s\n", indent, line)
+	}
+}
@@ -0,0 +1,64 @@
+// Package naming converts IDL names between the cases the generators use for
+// identifiers: snake_case for Python functions, PascalCase for Go and C#
+// members and camelCase for Java fields.
+//
+// The conversions decide the names generated code is found by, and some of them
+// are repeated at runtime (the generated Go server maps method names to handler
+// methods the same way SnakeToPascal does), so their results are pinned by
+// tests. Changing one renames identifiers in generated code and breaks calls and
+// JSON round-trips with code generated before.
+package naming
+
+import (
+	"strings"
+	"unicode"
+)
+
+// LowerFirst lowercases the first letter of s and keeps the rest as it is.
+// Underscores are not removed: "PersonId" -> "personId", "to_repeat" -> "to_repeat".
+func LowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// UpperFirst uppercases the first letter of s and keeps the rest as it is:
+// "personId" -> "PersonId", "to_repeat" -> "To_repeat".
+func UpperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// SnakeToPascal converts snake_case to PascalCase by uppercasing the first letter
+// of every part between underscores and dropping the underscores. The rest of
+// each part keeps its case, so camelCase input only gains a capital:
+// "to_repeat" -> "ToRepeat", "putPerson" -> "PutPerson", "get_URL" -> "GetURL".
+func SnakeToPascal(s string) string {
+	var sb strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		sb.WriteString(UpperFirst(part))
+	}
+	return sb.String()
+}
+
+// ToSnake converts camelCase or PascalCase to snake_case. A run of capitals is one
+// word, split before its last capital when a lowercase letter follows, and digits
+// stay with the word before them: "UserService" -> "user_service",
+// "HTTPServer" -> "http_server", "user2Id" -> "user2_id". Underscores are kept.
+func ToSnake(s string) string {
+	var sb strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '_' && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
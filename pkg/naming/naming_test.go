@@ -0,0 +1,129 @@
+package naming
+
+import "testing"
+
+// The expected names below are the identifiers generated code already uses;
+// changing one breaks compatibility with code generated before.
+
+func TestLowerFirst(t *testing.T) {
+	cases := map[string]string{
+		"":          "",
+		"a":         "a",
+		"A":         "a",
+		"PersonId":  "personId",
+		"personId":  "personId",
+		"to_repeat": "to_repeat",
+		"To_repeat": "to_repeat",
+		"URL":       "uRL",
+		"ID":        "iD",
+		"HTTPCode":  "hTTPCode",
+		"2fa":       "2fa",
+		"_private":  "_private",
+	}
+	for in, want := range cases {
+		if got := LowerFirst(in); got != want {
+			t.Errorf("LowerFirst(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestUpperFirst(t *testing.T) {
+	cases := map[string]string{
+		"":          "",
+		"a":         "A",
+		"personId":  "PersonId",
+		"PersonId":  "PersonId",
+		"to_repeat": "To_repeat",
+		"url":       "Url",
+		"uRL":       "URL",
+		"v2":        "V2",
+		"2fa":       "2fa",
+		"_private":  "_private",
+	}
+	for in, want := range cases {
+		if got := UpperFirst(in); got != want {
+			t.Errorf("UpperFirst(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSnakeToPascal(t *testing.T) {
+	cases := map[string]string{
+		"":                "",
+		"add":             "Add",
+		"to_repeat":       "ToRepeat",
+		"force_uppercase": "ForceUppercase",
+		"say_hi":          "SayHi",
+		"repeat_num":      "RepeatNum",
+		"putPerson":       "PutPerson",
+		"PutPerson":       "PutPerson",
+		"get_URL":         "GetURL",
+		"get_url":         "GetUrl",
+		"user_id":         "UserId",
+		"userID":          "UserID",
+		"ID":              "ID",
+		"v2_api":          "V2Api",
+		"user2_id":        "User2Id",
+		"retry_2":         "Retry2",
+		"item_2fa":        "Item2fa",
+		"a__b":            "AB",
+		"_leading":        "Leading",
+		"trailing_":       "Trailing",
+		"_":               "",
+		"put_Person":      "PutPerson",
+		"order_items":     "OrderItems",
+	}
+	for in, want := range cases {
+		if got := SnakeToPascal(in); got != want {
+			t.Errorf("SnakeToPascal(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestToSnake(t *testing.T) {
+	cases := map[string]string{
+		"":                   "",
+		"A":                  "a",
+		"a":                  "a",
+		"UserService":        "user_service",
+		"putPerson":          "put_person",
+		"say_hi":             "say_hi",
+		"HTTPServer":         "http_server",
+		"getURL":             "get_url",
+		"URL":                "url",
+		"ID":                 "id",
+		"userID":             "user_id",
+		"userId":             "user_id",
+		"URLForID":           "url_for_id",
+		"user2Id":            "user2_id",
+		"v2Api":              "v2_api",
+		"HTTP2Server":        "http2_server",
+		"item2fa":            "item2fa",
+		"Put_Person":         "put_person",
+		"already_snake_case": "already_snake_case",
+	}
+	for in, want := range cases {
+		if got := ToSnake(in); got != want {
+			t.Errorf("ToSnake(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// Names that are lowercase words joined by underscores, each starting with a
+// letter, survive a round trip through PascalCase.
+func TestSnakeRoundTrip(t *testing.T) {
+	for _, name := range []string{"add", "to_repeat", "force_uppercase", "repeat_num", "say_hi", "user_id", "v2_api", "user2_id"} {
+		if got := ToSnake(SnakeToPascal(name)); got != name {
+			t.Errorf("ToSnake(SnakeToPascal(%q)) = %q", name, got)
+		}
+	}
+	for _, name := range []string{"UserService", "PutPerson", "User2Id"} {
+		if got := SnakeToPascal(ToSnake(name)); got != name {
+			t.Errorf("SnakeToPascal(ToSnake(%q)) = %q", name, got)
+		}
+	}
+	// A run of capitals is one word, so acronyms do not survive the trip
+	if got := SnakeToPascal(ToSnake("HTTPServer")); got != "HttpServer" {
+		t.Errorf("SnakeToPascal(ToSnake(%q)) = %q, want %q", "HTTPServer", got, "HttpServer")
+	}
+}
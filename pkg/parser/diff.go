@@ -0,0 +1,277 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Diff compares two versions of an IDL as deployed clients and servers see them
+// on the wire. A change is breaking when a peer built from the old IDL can fail
+// against one built from the new: a struct, field, enum value, interface or
+// method went away, a type changed, a field or parameter became required, or a
+// result may now be null. Adding declarations, enum values, optional fields and
+// optional trailing parameters is additive. Struct fields include those of the
+// structs they extend, and methods are matched by their JSON-RPC name, so a
+// rename that keeps its [wire] name is no change. Typedefs are compared by the
+// types they stand for. Breaking changes to a method that was
+// [stability="experimental"], on itself or its interface, are only warnings,
+// as its callers were told it may still change.
+
+// DiffChange is a difference between the old and the new version of an IDL
+type DiffChange struct {
+	// Pos is where the change is, in the new IDL, or in the old one for removals
+	Pos      lexer.Position
+	Breaking bool
+	// Warning is set instead of Breaking for a change that would break callers of
+	// an experimental method
+	Warning bool
+	Msg     string
+}
+
+func (c *DiffChange) String() string {
+	kind := "additive"
+	switch {
+	case c.Breaking:
+		kind = "breaking"
+	case c.Warning:
+		kind = "warning"
+	}
+	return fmt.Sprintf("%s: %s: %s", c.Pos, kind, c.Msg)
+}
+
+// HasBreakingChanges reports whether any of the changes is breaking
+func HasBreakingChanges(changes []*DiffChange) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Diff returns the changes from oldIDL to newIDL: changed and removed
+// declarations in the order of the old IDL, then added ones in the order of the
+// new IDL
+func Diff(oldIDL, newIDL *IDL) []*DiffChange {
+	d := &differ{changes: make([]*DiffChange, 0)}
+	d.diffStructs(oldIDL, newIDL)
+	d.diffEnums(oldIDL, newIDL)
+	d.diffMethods(oldIDL, newIDL)
+	return d.changes
+}
+
+// differ collects the changes Diff finds
+type differ struct {
+	changes []*DiffChange
+}
+
+func (d *differ) breaking(pos lexer.Position, format string, args ...interface{}) {
+	d.changes = append(d.changes, &DiffChange{Pos: pos, Breaking: true, Msg: fmt.Sprintf(format, args...)})
+}
+
+// breakingIn reports a breaking change to method m, which is a warning if the old
+// version of m was experimental
+func (d *differ) breakingIn(m rpcMethod, pos lexer.Position, format string, args ...interface{}) {
+	if m.stability != StabilityExperimental {
+		d.breaking(pos, format, args...)
+		return
+	}
+	d.changes = append(d.changes, &DiffChange{Pos: pos, Warning: true, Msg: fmt.Sprintf(format, args...) + " (experimental)"})
+}
+
+func (d *differ) additive(pos lexer.Position, format string, args ...interface{}) {
+	d.changes = append(d.changes, &DiffChange{Pos: pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (d *differ) diffStructs(oldIDL, newIDL *IDL) {
+	oldStructs := structsByName(oldIDL)
+	newStructs := structsByName(newIDL)
+	for _, oldStruct := range oldIDL.Structs {
+		newStruct := newStructs[oldStruct.Name]
+		if newStruct == nil {
+			d.breaking(oldStruct.Pos, "struct %s was removed", oldStruct.Name)
+			continue
+		}
+		oldFields := ResolveStructFields(oldStruct, oldStructs)
+		newFields := ResolveStructFields(newStruct, newStructs)
+		newByName := make(map[string]*Field, len(newFields))
+		for _, f := range newFields {
+			newByName[f.Name] = f
+		}
+		oldByName := make(map[string]*Field, len(oldFields))
+		for _, oldField := range oldFields {
+			oldByName[oldField.Name] = oldField
+			newField := newByName[oldField.Name]
+			switch {
+			case newField == nil:
+				d.breaking(oldField.Pos, "field %s.%s was removed", oldStruct.Name, oldField.Name)
+			case oldField.Type.String() != newField.Type.String():
+				d.breaking(newField.Pos, "field %s.%s changed type from %s to %s", oldStruct.Name, oldField.Name, oldField.Type, newField.Type)
+			case oldField.Optional && !newField.Optional:
+				d.breaking(newField.Pos, "field %s.%s is now required", oldStruct.Name, oldField.Name)
+			case !oldField.Optional && newField.Optional:
+				d.breaking(newField.Pos, "field %s.%s is now optional, so readers may find it missing", oldStruct.Name, oldField.Name)
+			}
+		}
+		for _, newField := range newFields {
+			if oldByName[newField.Name] != nil {
+				continue
+			}
+			if newField.Optional {
+				d.additive(newField.Pos, "optional field %s.%s was added", newStruct.Name, newField.Name)
+			} else {
+				d.breaking(newField.Pos, "required field %s.%s was added", newStruct.Name, newField.Name)
+			}
+		}
+	}
+	for _, newStruct := range newIDL.Structs {
+		if oldStructs[newStruct.Name] == nil {
+			d.additive(newStruct.Pos, "struct %s was added", newStruct.Name)
+		}
+	}
+}
+
+func (d *differ) diffEnums(oldIDL, newIDL *IDL) {
+	oldEnums := make(map[string]*Enum, len(oldIDL.Enums))
+	for _, e := range oldIDL.Enums {
+		oldEnums[e.Name] = e
+	}
+	newEnums := make(map[string]*Enum, len(newIDL.Enums))
+	for _, e := range newIDL.Enums {
+		newEnums[e.Name] = e
+	}
+	for _, oldEnum := range oldIDL.Enums {
+		newEnum := newEnums[oldEnum.Name]
+		if newEnum == nil {
+			d.breaking(oldEnum.Pos, "enum %s was removed", oldEnum.Name)
+			continue
+		}
+		newValues := make(map[string]bool, len(newEnum.Values))
+		for _, v := range newEnum.Values {
+			newValues[v.Name] = true
+		}
+		oldValues := make(map[string]bool, len(oldEnum.Values))
+		for _, v := range oldEnum.Values {
+			oldValues[v.Name] = true
+			if !newValues[v.Name] {
+				d.breaking(newEnum.Pos, "value %s of enum %s was removed", v.Name, oldEnum.Name)
+			}
+		}
+		for _, v := range newEnum.Values {
+			if !oldValues[v.Name] {
+				d.additive(newEnum.Pos, "value %s was added to enum %s", v.Name, newEnum.Name)
+			}
+		}
+	}
+	for _, newEnum := range newIDL.Enums {
+		if oldEnums[newEnum.Name] == nil {
+			d.additive(newEnum.Pos, "enum %s was added", newEnum.Name)
+		}
+	}
+}
+
+// rpcMethod is a method under the JSON-RPC name clients call it by, with its
+// [stability]
+type rpcMethod struct {
+	name      string
+	method    *Method
+	stability string
+}
+
+func (d *differ) diffMethods(oldIDL, newIDL *IDL) {
+	oldMethods := rpcMethods(oldIDL)
+	newMethods := rpcMethods(newIDL)
+	newByName := make(map[string]*Method, len(newMethods))
+	for _, m := range newMethods {
+		newByName[m.name] = m.method
+	}
+	oldByName := make(map[string]*Method, len(oldMethods))
+	for _, m := range oldMethods {
+		oldByName[m.name] = m.method
+		newMethod := newByName[m.name]
+		if newMethod == nil {
+			d.breakingIn(m, m.method.Pos, "method %s was removed", m.name)
+			continue
+		}
+		d.diffSignature(m, newMethod)
+	}
+	for _, m := range newMethods {
+		if oldByName[m.name] == nil {
+			d.additive(m.method.Pos, "method %s was added", m.name)
+		}
+	}
+}
+
+// diffSignature compares the parameters and result of a method. Parameters are
+// compared by position, as clients pass them, and by name, which clients may pass
+// them by.
+func (d *differ) diffSignature(old rpcMethod, newMethod *Method) {
+	name, oldMethod := old.name, old.method
+	for i, oldParam := range oldMethod.Parameters {
+		if i >= len(newMethod.Parameters) {
+			d.breakingIn(old, newMethod.Pos, "parameter %s of method %s was removed", oldParam.Name, name)
+			continue
+		}
+		newParam := newMethod.Parameters[i]
+		switch {
+		case oldParam.Name != newParam.Name:
+			d.breakingIn(old, newParam.Pos, "parameter %d of method %s was renamed from %s to %s", i+1, name, oldParam.Name, newParam.Name)
+		case oldParam.Type.String() != newParam.Type.String():
+			d.breakingIn(old, newParam.Pos, "parameter %s of method %s changed type from %s to %s", oldParam.Name, name, oldParam.Type, newParam.Type)
+		case oldParam.Optional && !newParam.Optional:
+			d.breakingIn(old, newParam.Pos, "parameter %s of method %s is now required", oldParam.Name, name)
+		case !oldParam.Optional && newParam.Optional:
+			d.additive(newParam.Pos, "parameter %s of method %s is now optional", oldParam.Name, name)
+		}
+	}
+	for _, newParam := range newMethod.Parameters[min(len(oldMethod.Parameters), len(newMethod.Parameters)):] {
+		if newParam.Optional {
+			d.additive(newParam.Pos, "optional parameter %s was added to method %s", newParam.Name, name)
+		} else {
+			d.breakingIn(old, newParam.Pos, "required parameter %s was added to method %s", newParam.Name, name)
+		}
+	}
+	switch {
+	case resultType(oldMethod) != resultType(newMethod):
+		d.breakingIn(old, newMethod.Pos, "method %s changed its result type from %s to %s", name, resultType(oldMethod), resultType(newMethod))
+	case !oldMethod.ReturnOptional && newMethod.ReturnOptional:
+		d.breakingIn(old, newMethod.Pos, "method %s may now return null", name)
+	case oldMethod.ReturnOptional && !newMethod.ReturnOptional:
+		d.additive(newMethod.Pos, "method %s no longer returns null", name)
+	}
+}
+
+// resultType returns the result type of m as written in the IDL
+func resultType(m *Method) string {
+	if m.ReturnType == nil {
+		return "nothing"
+	}
+	return m.ReturnType.String()
+}
+
+// rpcMethods returns the methods of every interface of the IDL, inherited ones
+// included, under their JSON-RPC names
+func rpcMethods(idl *IDL) []rpcMethod {
+	methods := make([]rpcMethod, 0)
+	seen := make(map[string]bool)
+	for _, iface := range idl.Interfaces {
+		for _, m := range iface.Methods {
+			name := iface.RPCName(m)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			methods = append(methods, rpcMethod{name: name, method: m, stability: idl.MethodStability(iface, m)})
+		}
+	}
+	return methods
+}
+
+func structsByName(idl *IDL) map[string]*Struct {
+	structs := make(map[string]*Struct, len(idl.Structs))
+	for _, s := range idl.Structs {
+		structs[s.Name] = s
+	}
+	return structs
+}
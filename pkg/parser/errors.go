@@ -17,12 +17,16 @@ func (e *ParseError) Error() string {
 
 // ValidationError represents a validation error with position information
 type ValidationError struct {
+	File   string // the IDL file, empty if the IDL was not parsed from one
 	Line   int
 	Column int
 	Msg    string
 }
 
 func (e *ValidationError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+	}
 	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
 }
 
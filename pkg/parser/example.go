@@ -0,0 +1,389 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Example is a sample call of a method, written as an @example block above it:
+//
+//	@example(params=[1, 2], result=3)
+//	add(a int, b int) int
+//
+// Params holds the arguments as a JSON array in parameter order, or as a JSON
+// object keyed by parameter name. Result is the value the call returns. Both are
+// JSON and are checked against the method's types by ValidateIDL.
+type Example struct {
+	Pos    lexer.Position  `json:"-"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+}
+
+// Arguments of an @example block
+const (
+	exampleParams = "params"
+	exampleResult = "result"
+)
+
+// ParamList returns the params of an example of method m by position. Params
+// given by name are put in parameter order, with null for an optional parameter
+// left out before one that is given.
+func (e *Example) ParamList(m *Method) []json.RawMessage {
+	var list []json.RawMessage
+	if err := json.Unmarshal(e.Params, &list); err == nil {
+		return list
+	}
+	var named map[string]json.RawMessage
+	if err := json.Unmarshal(e.Params, &named); err != nil {
+		return nil
+	}
+	last := -1
+	for i, p := range m.Parameters {
+		if _, ok := named[p.Name]; ok {
+			last = i
+		}
+	}
+	list = make([]json.RawMessage, last+1)
+	for i := range list {
+		if value, ok := named[m.Parameters[i].Name]; ok {
+			list[i] = value
+		} else {
+			list[i] = json.RawMessage("null")
+		}
+	}
+	return list
+}
+
+// convertExamples converts the @example blocks of a method to Examples. Each
+// block must have exactly the params and result arguments.
+func convertExamples(defs []*ExampleDef) ([]*Example, error) {
+	var examples []*Example
+	for _, def := range defs {
+		example := &Example{Pos: def.Pos}
+		for _, arg := range def.Args {
+			value, err := arg.Value.toJSON()
+			if err != nil {
+				return nil, err
+			}
+			var target *json.RawMessage
+			switch arg.Name {
+			case exampleParams:
+				target = &example.Params
+			case exampleResult:
+				target = &example.Result
+			default:
+				return nil, &ParseError{Line: arg.Pos.Line, Column: arg.Pos.Column, Msg: fmt.Sprintf("unknown @example argument %s: expected params or result", arg.Name)}
+			}
+			if *target != nil {
+				return nil, &ParseError{Line: arg.Pos.Line, Column: arg.Pos.Column, Msg: fmt.Sprintf("duplicate @example argument %s", arg.Name)}
+			}
+			*target = value
+		}
+		for _, required := range []struct {
+			name  string
+			value json.RawMessage
+		}{{exampleParams, example.Params}, {exampleResult, example.Result}} {
+			if required.value == nil {
+				return nil, &ParseError{Line: def.Pos.Line, Column: def.Pos.Column, Msg: fmt.Sprintf("@example is missing %s", required.name)}
+			}
+		}
+		examples = append(examples, example)
+	}
+	return examples, nil
+}
+
+// toJSON returns the value as JSON text
+func (v *JSONValueDef) toJSON() (json.RawMessage, error) {
+	var buf bytes.Buffer
+	if err := v.writeJSON(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (v *JSONValueDef) writeJSON(buf *bytes.Buffer) error {
+	switch {
+	case v.String != nil:
+		s, err := decodeJSONString(*v.String, v.Pos)
+		if err != nil {
+			return err
+		}
+		encoded, _ := json.Marshal(s)
+		buf.Write(encoded)
+	case v.Number != nil:
+		if !json.Valid([]byte(*v.Number)) {
+			return &ParseError{Line: v.Pos.Line, Column: v.Pos.Column, Msg: fmt.Sprintf("invalid number %s", *v.Number)}
+		}
+		buf.WriteString(*v.Number)
+	case v.Literal != nil:
+		buf.WriteString(*v.Literal)
+	case v.Object != nil:
+		seen := make(map[string]bool)
+		buf.WriteByte('{')
+		for i, member := range v.Object.Members {
+			key, err := decodeJSONString(member.Key, member.Value.Pos)
+			if err != nil {
+				return err
+			}
+			if seen[key] {
+				return &ParseError{Line: member.Value.Pos.Line, Column: member.Value.Pos.Column, Msg: fmt.Sprintf("duplicate key %q", key)}
+			}
+			seen[key] = true
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			encoded, _ := json.Marshal(key)
+			buf.Write(encoded)
+			buf.WriteByte(':')
+			if err := member.Value.writeJSON(buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case v.Array != nil:
+		buf.WriteByte('[')
+		for i, element := range v.Array.Elements {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := element.writeJSON(buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	}
+	return nil
+}
+
+// decodeJSONString decodes a StringLiteral token, which keeps its quotes, as a
+// JSON string so that escapes such as \n work
+func decodeJSONString(literal string, pos lexer.Position) (string, error) {
+	var s string
+	if err := json.Unmarshal([]byte(literal), &s); err != nil {
+		return "", &ParseError{Line: pos.Line, Column: pos.Column, Msg: fmt.Sprintf("invalid string %s", literal)}
+	}
+	return s, nil
+}
+
+// validateExamples checks that the params and result of every @example of a
+// method are valid for its parameter and return types
+func validateExamples(checker *exampleChecker, method *Method, errors *ValidationErrors) {
+	for _, example := range method.Examples {
+		report := func(msg string) {
+			errors.Add(&ValidationError{
+				File:   example.Pos.Filename,
+				Line:   example.Pos.Line,
+				Column: example.Pos.Column,
+				Msg:    fmt.Sprintf("@example of method %s: %s", method.Name, msg),
+			})
+		}
+		if msg := checker.checkParams(example.Params, method); msg != "" {
+			report(msg)
+		}
+		result, err := decodeExampleValue(example.Result)
+		if err != nil {
+			report(err.Error())
+			continue
+		}
+		if result == nil {
+			if !method.ReturnOptional {
+				report("result is null but the return type is not [optional]")
+			}
+		} else if msg := checker.check(result, method.ReturnType); msg != "" {
+			report("result " + msg)
+		}
+	}
+}
+
+// exampleChecker checks example values against IDL types
+type exampleChecker struct {
+	structs map[string]*Struct
+	enums   map[string]*Enum
+}
+
+func newExampleChecker(idl *IDL) *exampleChecker {
+	c := &exampleChecker{structs: make(map[string]*Struct), enums: make(map[string]*Enum)}
+	for _, s := range idl.Structs {
+		c.structs[s.Name] = s
+	}
+	for _, e := range idl.Enums {
+		c.enums[e.Name] = e
+	}
+	return c
+}
+
+// decodeExampleValue decodes example JSON keeping numbers as json.Number
+func decodeExampleValue(data json.RawMessage) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// checkParams returns why params are not valid arguments of method, or an empty
+// string if they are
+func (c *exampleChecker) checkParams(params json.RawMessage, method *Method) string {
+	value, err := decodeExampleValue(params)
+	if err != nil {
+		return err.Error()
+	}
+	checkParam := func(p *Parameter, v interface{}) string {
+		if v == nil {
+			if p.Optional {
+				return ""
+			}
+			return fmt.Sprintf("parameter %s is null but not [optional]", p.Name)
+		}
+		if msg := c.check(v, p.Type); msg != "" {
+			return fmt.Sprintf("parameter %s %s", p.Name, msg)
+		}
+		return ""
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		if len(v) > len(method.Parameters) {
+			return fmt.Sprintf("params has %d values but the method takes %d", len(v), len(method.Parameters))
+		}
+		if len(v) < method.RequiredParams() {
+			return fmt.Sprintf("params has %d values but the method requires %d", len(v), method.RequiredParams())
+		}
+		for i, p := range method.Parameters[:len(v)] {
+			if msg := checkParam(p, v[i]); msg != "" {
+				return msg
+			}
+		}
+	case map[string]interface{}:
+		known := make(map[string]bool)
+		for _, p := range method.Parameters {
+			known[p.Name] = true
+			arg, ok := v[p.Name]
+			if !ok {
+				if !p.Optional {
+					return fmt.Sprintf("params is missing required parameter %s", p.Name)
+				}
+				continue
+			}
+			if msg := checkParam(p, arg); msg != "" {
+				return msg
+			}
+		}
+		for _, name := range sortedKeys(v) {
+			if !known[name] {
+				return fmt.Sprintf("params has unknown parameter %s", name)
+			}
+		}
+	default:
+		return "params must be a JSON array or object"
+	}
+	return ""
+}
+
+// check returns why value is not valid for type t, starting with "is" or with
+// the path to the invalid part, or an empty string if it is valid
+func (c *exampleChecker) check(value interface{}, t *Type) string {
+	if t == nil {
+		return ""
+	}
+	switch {
+	case t.IsBuiltIn():
+		ok := false
+		switch t.BuiltIn {
+		case "string":
+			_, ok = value.(string)
+		case "bool":
+			_, ok = value.(bool)
+		case "float":
+			_, ok = value.(json.Number)
+		case "int":
+			n, isNumber := value.(json.Number)
+			_, err := n.Int64()
+			ok = isNumber && err == nil
+		}
+		if !ok {
+			return fmt.Sprintf("is not a valid %s: %s", t.BuiltIn, exampleText(value))
+		}
+	case t.IsArray():
+		items, ok := value.([]interface{})
+		if !ok {
+			return "is not an array: " + exampleText(value)
+		}
+		for i, item := range items {
+			if msg := c.check(item, t.Array); msg != "" {
+				return fmt.Sprintf("[%d] %s", i, msg)
+			}
+		}
+	case t.IsMap():
+		items, ok := value.(map[string]interface{})
+		if !ok {
+			return "is not a map: " + exampleText(value)
+		}
+		for _, key := range sortedKeys(items) {
+			if msg := c.check(items[key], t.MapValue); msg != "" {
+				return fmt.Sprintf("[%q] %s", key, msg)
+			}
+		}
+	case c.enums[t.UserDefined] != nil:
+		s, _ := value.(string)
+		for _, v := range c.enums[t.UserDefined].Values {
+			if v.Name == s {
+				return ""
+			}
+		}
+		return fmt.Sprintf("is not a value of enum %s: %s", t.UserDefined, exampleText(value))
+	case c.structs[t.UserDefined] != nil:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Sprintf("is not a %s object: %s", t.UserDefined, exampleText(value))
+		}
+		known := make(map[string]bool)
+		for _, field := range ResolveStructFields(c.structs[t.UserDefined], c.structs) {
+			known[field.Name] = true
+			fieldValue, present := obj[field.Name]
+			if !present || fieldValue == nil {
+				if !field.Optional {
+					return fmt.Sprintf("is missing required field %s of %s", field.Name, t.UserDefined)
+				}
+				continue
+			}
+			if msg := c.check(fieldValue, field.Type); msg != "" {
+				return fmt.Sprintf(".%s %s", field.Name, msg)
+			}
+		}
+		for _, name := range sortedKeys(obj) {
+			if !known[name] {
+				return fmt.Sprintf("has unknown field %s of %s", name, t.UserDefined)
+			}
+		}
+	}
+	return ""
+}
+
+// exampleText returns value as compact JSON for error messages
+func exampleText(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	text := string(data)
+	if len(text) > 40 {
+		text = text[:37] + "..."
+	}
+	return strings.TrimSpace(text)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
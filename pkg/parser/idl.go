@@ -1,15 +1,21 @@
 package parser
 
 import (
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/alecthomas/participle/v2/lexer"
 )
 
-// IDL represents the root structure containing all parsed IDL elements
+// IDL represents the root structure containing all parsed IDL elements.
+// Its JSON encoding is the idl.json format; see IDLVersion and JSONSchema.
 type IDL struct {
 	RootNamespace string       `json:"rootNamespace,omitempty"` // Namespace of the root file being parsed
 	Interfaces    []*Interface `json:"interfaces,omitempty"`
 	Structs       []*Struct    `json:"structs,omitempty"`
 	Enums         []*Enum      `json:"enums,omitempty"`
+	Typedefs      []*Typedef   `json:"typedefs,omitempty"`
 }
 
 // Interface represents a service interface with methods
@@ -18,7 +24,87 @@ type Interface struct {
 	Name      string         `json:"name"`
 	Namespace string         `json:"namespace,omitempty"`
 	Comment   string         `json:"comment,omitempty"`
-	Methods   []*Method      `json:"methods,omitempty"`
+	// Extends lists the interfaces whose methods this interface inherits
+	Extends     []string      `json:"extends,omitempty"`
+	Annotations []*Annotation `json:"annotations,omitempty"`
+	// Methods are the interface's own methods followed by the inherited ones;
+	// see ResolveInterfaceInheritance
+	Methods []*Method `json:"methods,omitempty"`
+}
+
+// OwnMethods returns the methods declared by the interface itself
+func (i *Interface) OwnMethods() []*Method {
+	own := make([]*Method, 0, len(i.Methods))
+	for _, m := range i.Methods {
+		if m.InheritedFrom == "" {
+			own = append(own, m)
+		}
+	}
+	return own
+}
+
+// Annotation returns the annotation with the given name, or nil if the interface does not have it
+func (i *Interface) Annotation(name string) *Annotation {
+	for _, a := range i.Annotations {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// RPCName returns the JSON-RPC method name that clients send for a method of the
+// interface, "Interface.method" unless the IDL maps it with [wire]. A method's own
+// [wire="v1.user.get"] is used as-is, and an interface's [wire="v1.user"] prefixes
+// the name of each of its methods. Inherited methods take the prefix of the
+// interface that inherits them, as their own mapping belongs to their declaring interface.
+func (i *Interface) RPCName(m *Method) string {
+	if m.InheritedFrom == "" {
+		if a := m.Annotation(AnnotationWire); a != nil {
+			return a.Value
+		}
+	}
+	if a := i.Annotation(AnnotationWire); a != nil {
+		return a.Value + "." + m.Name
+	}
+	return i.Name + "." + m.Name
+}
+
+// MethodOwner returns the [owner] of method m of iface: the method's own, or else
+// that of the interface that declares it, or "" if neither has one
+func (idl *IDL) MethodOwner(iface *Interface, m *Method) string {
+	return idl.methodAnnotationValue(iface, m, AnnotationOwner)
+}
+
+// MethodStability returns the [stability] of method m of iface: the method's own,
+// or else that of the interface that declares it, or "" if neither has one
+func (idl *IDL) MethodStability(iface *Interface, m *Method) string {
+	return idl.methodAnnotationValue(iface, m, AnnotationStability)
+}
+
+// methodAnnotationValue returns the value of a method annotation that defaults to
+// the one on the interface declaring the method, so inherited methods keep the
+// annotations of their parent interface
+func (idl *IDL) methodAnnotationValue(iface *Interface, m *Method, name string) string {
+	if a := m.Annotation(name); a != nil {
+		return a.Value
+	}
+	declaring := iface
+	if m.InheritedFrom != "" {
+		declaring = nil
+		for _, candidate := range idl.Interfaces {
+			if candidate.Name == m.InheritedFrom {
+				declaring = candidate
+				break
+			}
+		}
+	}
+	if declaring != nil {
+		if a := declaring.Annotation(name); a != nil {
+			return a.Value
+		}
+	}
+	return ""
 }
 
 // Method represents an interface method with parameters and return type
@@ -28,13 +114,223 @@ type Method struct {
 	Parameters     []*Parameter   `json:"parameters,omitempty"`
 	ReturnType     *Type          `json:"returnType"`
 	ReturnOptional bool           `json:"returnOptional,omitempty"`
+	Annotations    []*Annotation  `json:"annotations,omitempty"`
+	// Examples are the sample calls of the method from its @example blocks
+	Examples []*Example `json:"examples,omitempty"`
+	// InheritedFrom names the interface that declares the method when it was
+	// inherited through extends, and is empty for the interface's own methods
+	InheritedFrom string `json:"inheritedFrom,omitempty"`
+}
+
+// Method annotation names
+const (
+	// AnnotationReadOnly marks a method as side-effect free so servers may expose it via HTTP GET
+	AnnotationReadOnly = "readonly"
+	// AnnotationIdempotent marks a method as safe to repeat, so clients may retry it after transport failures
+	AnnotationIdempotent = "idempotent"
+	// AnnotationScopes lists the comma separated auth scopes a caller needs, e.g. [scopes="books:read"]
+	AnnotationScopes = "scopes"
+	// AnnotationTimeout is the upstream timeout for the method as a Go duration, e.g. [timeout="5s"]
+	AnnotationTimeout = "timeout"
+	// AnnotationAsync marks a slow method that servers run as a background job, which
+	// clients poll until it completes
+	AnnotationAsync = "async"
+	// AnnotationWire sets the JSON-RPC method name of a method, e.g.
+	// [wire="v1.user.get"], or on an interface the prefix of its method names
+	AnnotationWire = "wire"
+	// AnnotationOwner names the team that owns a method, e.g. [owner="team-payments"],
+	// or on an interface the owner of its methods
+	AnnotationOwner = "owner"
+	// AnnotationStability is [stability="experimental"] or [stability="stable"] on a
+	// method, or on an interface the stability of its methods
+	AnnotationStability = "stability"
+	// AnnotationAccepts lists the comma separated legacy encodings a method also accepts
+	// requests in, besides JSON-RPC, e.g. [accepts="form,xml"]
+	AnnotationAccepts = "accepts"
+	// AnnotationCache is the max-age of GET responses of a [readonly] method as a Go
+	// duration in whole seconds, e.g. [cache="60s"]; servers also tag them with an ETag
+	AnnotationCache = "cache"
+	// AnnotationErrorData names the struct the data of the method's error responses
+	// holds, e.g. [errordata="ValidationFailure"]; clients decode it into that struct
+	AnnotationErrorData = "errordata"
+	// AnnotationCompress marks a method whose results are large: servers gzip its
+	// responses for clients that accept gzip, which advertise it on its calls. A value
+	// is the size in bytes below which responses are sent as-is, e.g. [compress="4096"]
+	AnnotationCompress = "compress"
+	// AnnotationChunked marks a method returning an array whose handlers produce the
+	// elements one at a time instead of returning the whole array
+	AnnotationChunked = "chunked"
+)
+
+// Encodings the [accepts] annotation may list
+const (
+	// AcceptsForm is an application/x-www-form-urlencoded request body
+	AcceptsForm = "form"
+	// AcceptsXML is an XML request body whose root element holds one element per parameter
+	AcceptsXML = "xml"
+)
+
+// Values of the [stability] annotation
+const (
+	// StabilityExperimental marks methods that may still change incompatibly
+	StabilityExperimental = "experimental"
+	// StabilityStable marks methods that must stay backward compatible
+	StabilityStable = "stable"
+)
+
+// Annotation represents a bracketed interface, method, parameter or field annotation such as [readonly] or [name="value"]
+type Annotation struct {
+	Pos   lexer.Position `json:"-"`
+	Name  string         `json:"name"`
+	Value string         `json:"value,omitempty"`
+}
+
+// Annotation returns the annotation with the given name, or nil if the method does not have it
+func (m *Method) Annotation(name string) *Annotation {
+	for _, a := range m.Annotations {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// IsReadOnly returns true if the method is annotated [readonly]
+func (m *Method) IsReadOnly() bool {
+	return m.Annotation(AnnotationReadOnly) != nil
+}
+
+// IsIdempotent returns true if the method is annotated [idempotent] or [readonly]
+func (m *Method) IsIdempotent() bool {
+	return m.IsReadOnly() || m.Annotation(AnnotationIdempotent) != nil
+}
+
+// IsAsync returns true if the method is annotated [async]
+func (m *Method) IsAsync() bool {
+	return m.Annotation(AnnotationAsync) != nil
 }
 
-// Parameter represents a method parameter
+// IsChunked returns true if the method is annotated [chunked]
+func (m *Method) IsChunked() bool {
+	return m.Annotation(AnnotationChunked) != nil
+}
+
+// Scopes returns the auth scopes listed by the [scopes] annotation, or nil if there is none
+func (m *Method) Scopes() []string {
+	a := m.Annotation(AnnotationScopes)
+	if a == nil {
+		return nil
+	}
+	var scopes []string
+	for _, scope := range strings.Split(a.Value, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// CacheMaxAge returns the max-age from the [cache] annotation, and false if the method
+// has none or it is not a valid duration
+func (m *Method) CacheMaxAge() (time.Duration, bool) {
+	a := m.Annotation(AnnotationCache)
+	if a == nil {
+		return 0, false
+	}
+	d, err := time.ParseDuration(a.Value)
+	if err != nil {
+		return 0, false
+	}
+	return d, true
+}
+
+// CompressThreshold returns the response size in bytes from which responses of a
+// [compress] method are gzipped, 0 when the annotation has no value, and false if the
+// method has none or its value is not a byte count
+func (m *Method) CompressThreshold() (int, bool) {
+	a := m.Annotation(AnnotationCompress)
+	if a == nil {
+		return 0, false
+	}
+	if a.Value == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(a.Value)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// ErrorData returns the struct named by the [errordata] annotation, or "" if there is none
+func (m *Method) ErrorData() string {
+	if a := m.Annotation(AnnotationErrorData); a != nil {
+		return a.Value
+	}
+	return ""
+}
+
+// Accepts returns the encodings listed by the [accepts] annotation, or nil if there is none
+func (m *Method) Accepts() []string {
+	a := m.Annotation(AnnotationAccepts)
+	if a == nil {
+		return nil
+	}
+	var encodings []string
+	for _, encoding := range strings.Split(a.Value, ",") {
+		if encoding = strings.TrimSpace(encoding); encoding != "" {
+			encodings = append(encodings, encoding)
+		}
+	}
+	return encodings
+}
+
+// Parameter represents a method parameter. Optional parameters come after the
+// required ones and may be left out of a call, in which case the server passes
+// their default value, or null when they have none.
 type Parameter struct {
-	Pos  lexer.Position `json:"-"`
-	Name string         `json:"name"`
-	Type *Type          `json:"type"`
+	Pos         lexer.Position `json:"-"`
+	Name        string         `json:"name"`
+	Type        *Type          `json:"type"`
+	Optional    bool           `json:"optional,omitempty"`
+	Annotations []*Annotation  `json:"annotations,omitempty"`
+}
+
+// Parameter annotation names
+const (
+	// AnnotationDefault is the value an optional parameter takes when a call leaves it
+	// out, written as IDL text, e.g. [default="10"] or [default="asc"]. It makes the
+	// parameter optional.
+	AnnotationDefault = "default"
+)
+
+// Annotation returns the annotation with the given name, or nil if the parameter does not have it
+func (p *Parameter) Annotation(name string) *Annotation {
+	for _, a := range p.Annotations {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// Default returns the [default] value of the parameter, and false if it has none
+func (p *Parameter) Default() (string, bool) {
+	if a := p.Annotation(AnnotationDefault); a != nil {
+		return a.Value, true
+	}
+	return "", false
+}
+
+// RequiredParams returns the number of parameters a call must pass, which is the
+// number of parameters before the first optional one
+func (m *Method) RequiredParams() int {
+	for i, p := range m.Parameters {
+		if p.Optional {
+			return i
+		}
+	}
+	return len(m.Parameters)
 }
 
 // Struct represents a struct definition with fields and optional extends
@@ -44,16 +340,128 @@ type Struct struct {
 	Namespace string         `json:"namespace,omitempty"`
 	Extends   string         `json:"extends,omitempty"` // Empty if no extends, can be qualified (e.g., "inc.Response")
 	Comment   string         `json:"comment,omitempty"`
-	Fields    []*Field       `json:"fields,omitempty"`
+	// Annotations such as [nolint="max-fields"]
+	Annotations []*Annotation `json:"annotations,omitempty"`
+	Fields      []*Field      `json:"fields,omitempty"`
+}
+
+// Struct annotation names
+const (
+	// AnnotationImmutable marks a struct as a value object: generated code sets its
+	// fields when a value is constructed or decoded and offers no way to change them
+	AnnotationImmutable = "immutable"
+)
+
+// Annotation returns the annotation with the given name, or nil if the struct does not have it
+func (s *Struct) Annotation(name string) *Annotation {
+	for _, a := range s.Annotations {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// IsImmutable returns true if the struct is annotated [immutable]
+func (s *Struct) IsImmutable() bool {
+	return s.Annotation(AnnotationImmutable) != nil
+}
+
+// ResolvedFields returns every field a value of the named struct has: those of
+// the struct at the top of its extends chain first, then those of each struct
+// below it, ending with its own, each in declaration order. It returns nil if
+// the IDL has no such struct.
+func (idl *IDL) ResolvedFields(structName string) []*Field {
+	structs := make(map[string]*Struct, len(idl.Structs))
+	for _, s := range idl.Structs {
+		structs[s.Name] = s
+	}
+	s := structs[structName]
+	if s == nil {
+		return nil
+	}
+	return ResolveStructFields(s, structs)
 }
 
-// Field represents a struct field with type, optional flag, and comments
+// ResolveStructFields is ResolvedFields for callers that already hold the
+// structs of the IDL by name. A cycle in extends, which validation reports,
+// ends the chain instead of looping.
+func ResolveStructFields(s *Struct, structs map[string]*Struct) []*Field {
+	var chain []*Struct
+	seen := make(map[string]bool)
+	for cur := s; cur != nil && !seen[cur.Name]; cur = ExtendedStruct(cur, structs) {
+		seen[cur.Name] = true
+		chain = append([]*Struct{cur}, chain...)
+	}
+	fields := make([]*Field, 0)
+	for _, cur := range chain {
+		fields = append(fields, cur.Fields...)
+	}
+	return fields
+}
+
+// ExtendedStruct returns the struct s extends, or nil if it extends none or the
+// parent is not in structs. Extends is usually the name the parent has in the
+// merged IDL, but the root namespace keeps its types unqualified, so
+// "inc.Response" also finds Response of namespace inc, and an unqualified name
+// in an IDL that was not merged finds the parent in the namespace of s.
+func ExtendedStruct(s *Struct, structs map[string]*Struct) *Struct {
+	if s.Extends == "" {
+		return nil
+	}
+	if parent := structs[s.Extends]; parent != nil {
+		return parent
+	}
+	if i := strings.LastIndex(s.Extends, "."); i >= 0 {
+		if parent := structs[s.Extends[i+1:]]; parent != nil && parent.Namespace == s.Extends[:i] {
+			return parent
+		}
+		return nil
+	}
+	if s.Namespace != "" {
+		return structs[s.Namespace+"."+s.Extends]
+	}
+	return nil
+}
+
+// Field represents a struct field with type, optional flag, annotations and comments
 type Field struct {
-	Pos      lexer.Position `json:"-"`
-	Name     string         `json:"name"`
-	Type     *Type          `json:"type"`
-	Optional bool           `json:"optional,omitempty"`
-	Comment  string         `json:"comment,omitempty"`
+	Pos         lexer.Position `json:"-"`
+	Name        string         `json:"name"`
+	Type        *Type          `json:"type"`
+	Optional    bool           `json:"optional,omitempty"`
+	Comment     string         `json:"comment,omitempty"`
+	Annotations []*Annotation  `json:"annotations,omitempty"`
+}
+
+// Field annotation names
+const (
+	// AnnotationSensitive marks a field such as a password or token whose value is
+	// masked by generated string formatting and logging, but sent as-is on the wire
+	AnnotationSensitive = "sensitive"
+	// AnnotationEncrypted marks a field whose value generated clients and servers
+	// encrypt into the JSON-RPC payload with an application supplied cipher
+	AnnotationEncrypted = "encrypted"
+)
+
+// Annotation returns the annotation with the given name, or nil if the field does not have it
+func (f *Field) Annotation(name string) *Annotation {
+	for _, a := range f.Annotations {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// IsSensitive returns true if the field is annotated [sensitive]
+func (f *Field) IsSensitive() bool {
+	return f.Annotation(AnnotationSensitive) != nil
+}
+
+// IsEncrypted returns true if the field is annotated [encrypted]
+func (f *Field) IsEncrypted() bool {
+	return f.Annotation(AnnotationEncrypted) != nil
 }
 
 // EnumValue represents a single enum value with optional comment
@@ -71,6 +479,15 @@ type Enum struct {
 	Values    []*EnumValue   `json:"values,omitempty"`
 }
 
+// Typedef names an array or map type so that it can be reused and documented
+type Typedef struct {
+	Pos       lexer.Position `json:"-"`
+	Name      string         `json:"name"`
+	Namespace string         `json:"namespace,omitempty"`
+	Comment   string         `json:"comment,omitempty"`
+	Type      *Type          `json:"type"`
+}
+
 // Type represents a type (built-in, array, map, or user-defined)
 type Type struct {
 	Pos lexer.Position `json:"-"`
@@ -86,6 +503,10 @@ type Type struct {
 
 	// For user-defined types (interfaces, structs, enums)
 	UserDefined string `json:"userDefined,omitempty"`
+
+	// Alias names the typedef this type was written as. The type itself holds the
+	// typedef's array or map type; see ResolveTypedefs.
+	Alias string `json:"alias,omitempty"`
 }
 
 // IsBuiltIn returns true if this is a built-in type
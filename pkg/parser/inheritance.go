@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResolveInterfaceInheritance copies the methods an interface inherits through
+// extends into its Methods, after its own methods and marked with the interface
+// that declares them, so code that serves or calls an interface sees its full
+// method set. Earlier copies are replaced, so calling it again after the model
+// changes is safe. Unknown parents and cycles are skipped here and reported by
+// ValidateIDL.
+func ResolveInterfaceInheritance(idl *IDL) {
+	byName := make(map[string]*Interface, len(idl.Interfaces))
+	for _, iface := range idl.Interfaces {
+		byName[iface.Name] = iface
+		iface.Methods = iface.OwnMethods()
+	}
+
+	for _, iface := range idl.Interfaces {
+		// seen starts with the interface itself so that a cycle ends the walk, and
+		// an ancestor reached through two parents is only copied once
+		seen := map[string]bool{iface.Name: true}
+		var walk func(parents []string)
+		walk = func(parents []string) {
+			for _, name := range parents {
+				parent := byName[name]
+				if parent == nil || seen[name] {
+					continue
+				}
+				seen[name] = true
+				for _, m := range parent.OwnMethods() {
+					copied := *m
+					copied.InheritedFrom = parent.Name
+					iface.Methods = append(iface.Methods, &copied)
+				}
+				walk(parent.Extends)
+			}
+		}
+		walk(iface.Extends)
+	}
+}
+
+// validateInterfaceInheritance reports extends clauses that name something other
+// than an interface, circular inheritance, and method names an interface gets
+// from more than one place
+func validateInterfaceInheritance(idl *IDL, typeNames map[string]string, errors *ValidationErrors) {
+	byName := make(map[string]*Interface, len(idl.Interfaces))
+	for _, iface := range idl.Interfaces {
+		byName[iface.Name] = iface
+	}
+
+	for _, iface := range idl.Interfaces {
+		for _, parent := range iface.Extends {
+			if kind := typeNames[parent]; kind != "interface" {
+				msg := fmt.Sprintf("interface %s extends unknown interface %s", iface.Name, parent)
+				if kind != "" {
+					msg = fmt.Sprintf("interface %s extends %s %s, which is not an interface", iface.Name, kind, parent)
+				}
+				errors.Add(&ValidationError{File: iface.Pos.Filename, Line: iface.Pos.Line, Column: iface.Pos.Column, Msg: msg})
+			}
+		}
+	}
+
+	// Cycles: a DFS over the extends graph, reported once per cycle
+	visited := make(map[string]bool)
+	onPath := make(map[string]bool)
+	var dfs func(name string, path []string)
+	dfs = func(name string, path []string) {
+		iface := byName[name]
+		if iface == nil || visited[name] {
+			return
+		}
+		path = append(path, name)
+		onPath[name] = true
+		for _, parent := range iface.Extends {
+			if onPath[parent] {
+				start := 0
+				for i, n := range path {
+					if n == parent {
+						start = i
+						break
+					}
+				}
+				errors.Add(&ValidationError{
+					File:   iface.Pos.Filename,
+					Line:   iface.Pos.Line,
+					Column: iface.Pos.Column,
+					Msg:    fmt.Sprintf("circular interface inheritance: %s -> %s", strings.Join(path[start:], " -> "), parent),
+				})
+				continue
+			}
+			dfs(parent, path)
+		}
+		delete(onPath, name)
+		visited[name] = true
+	}
+	for _, iface := range idl.Interfaces {
+		dfs(iface.Name, nil)
+	}
+
+	// Method name conflicts between own and inherited methods, or between
+	// methods inherited from different interfaces
+	for _, iface := range idl.Interfaces {
+		if len(iface.Extends) == 0 {
+			continue
+		}
+		declaredBy := make(map[string]string)
+		for _, m := range iface.Methods {
+			from := m.InheritedFrom
+			if from == "" {
+				from = iface.Name
+			}
+			prev, exists := declaredBy[m.Name]
+			if !exists {
+				declaredBy[m.Name] = from
+				continue
+			}
+			if prev != from {
+				errors.Add(&ValidationError{
+					File:   iface.Pos.Filename,
+					Line:   iface.Pos.Line,
+					Column: iface.Pos.Column,
+					Msg:    fmt.Sprintf("interface %s has conflicting definitions of method %s from %s and %s", iface.Name, m.Name, prev, from),
+				})
+			}
+		}
+	}
+}
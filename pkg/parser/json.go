@@ -0,0 +1,51 @@
+package parser
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+// IDLVersion is the version of the idl.json format written by this release.
+//
+// The JSON encoding of IDL and the types it contains is a public contract: field
+// names and meanings do not change within a version, and new fields are only added
+// as optional. Version 1 is the unversioned format written by earlier releases,
+// which is read as-is. Anything that would break an existing reader bumps the version.
+const IDLVersion = 2
+
+//go:embed idl.schema.json
+var jsonSchema []byte
+
+// JSONSchema returns the JSON Schema (draft 2020-12) describing idl.json
+func JSONSchema() []byte {
+	return append([]byte(nil), jsonSchema...)
+}
+
+// idlJSON has the fields of IDL without its JSON methods
+type idlJSON IDL
+
+// MarshalJSON encodes the IDL with an "idlVersion" field set to IDLVersion
+func (idl *IDL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Version int `json:"idlVersion"`
+		idlJSON
+	}{IDLVersion, idlJSON(*idl)})
+}
+
+// UnmarshalJSON decodes an idl.json document. A missing "idlVersion" is read as
+// version 1; documents from a newer version than IDLVersion are rejected.
+func (idl *IDL) UnmarshalJSON(data []byte) error {
+	var doc struct {
+		Version int `json:"idlVersion"`
+		idlJSON
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if doc.Version > IDLVersion {
+		return fmt.Errorf("idl.json has idlVersion %d but this release supports up to %d", doc.Version, IDLVersion)
+	}
+	*idl = IDL(doc.idlJSON)
+	return nil
+}
@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestIDLJSONVersion(t *testing.T) {
+	idl, err := ParseIDL("test.pulse", "namespace test\n\nstruct User {\n  name string\n}\n")
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+
+	data, err := json.Marshal(idl)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.HasPrefix(string(data), `{"idlVersion":2,`) {
+		t.Errorf("expected idlVersion first, got %s", data)
+	}
+
+	var decoded IDL
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(decoded.Structs) != 1 || decoded.Structs[0].Name != "User" || decoded.RootNamespace != "test" {
+		t.Errorf("round trip lost data: %+v", decoded)
+	}
+
+	var legacy IDL
+	if err := json.Unmarshal([]byte(`{"structs":[{"name":"User"}]}`), &legacy); err != nil {
+		t.Errorf("expected unversioned idl.json to be accepted: %v", err)
+	}
+
+	var future IDL
+	err = json.Unmarshal([]byte(`{"idlVersion":3}`), &future)
+	if err == nil || !strings.Contains(err.Error(), "idlVersion 3") {
+		t.Errorf("expected newer idlVersion to be rejected, got %v", err)
+	}
+}
+
+// TestJSONSchemaMatchesModel keeps idl.schema.json in step with the JSON tags on the model types
+func TestJSONSchemaMatchesModel(t *testing.T) {
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		Defs       map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(JSONSchema(), &schema); err != nil {
+		t.Fatalf("idl.schema.json is not valid JSON: %v", err)
+	}
+
+	check := func(name string, props map[string]json.RawMessage, model interface{}, extra ...string) {
+		want := append(jsonFieldNames(reflect.TypeOf(model)), extra...)
+		sort.Strings(want)
+		got := make([]string, 0, len(props))
+		for p := range props {
+			got = append(got, p)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("%s: schema properties %v do not match model fields %v", name, got, want)
+		}
+	}
+
+	check("root", schema.Properties, IDL{}, "idlVersion")
+	models := map[string]interface{}{
+		"interface":  Interface{},
+		"method":     Method{},
+		"parameter":  Parameter{},
+		"annotation": Annotation{},
+		"struct":     Struct{},
+		"field":      Field{},
+		"enum":       Enum{},
+		"enumValue":  EnumValue{},
+		"example":    Example{},
+		"typedef":    Typedef{},
+		"type":       Type{},
+	}
+	for name, model := range models {
+		def, ok := schema.Defs[name]
+		if !ok {
+			t.Errorf("schema is missing $defs/%s", name)
+			continue
+		}
+		check(name, def.Properties, model)
+	}
+}
+
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Lint rules keep the API surface of an IDL that many teams contribute to within
+// budgets: how many methods an interface declares, how many fields a struct
+// declares and how many parameters a method takes. A declaration opts out of a
+// rule with [nolint="max-params"], or several with [nolint="max-methods,max-params"].
+// On an interface, [nolint] also covers the parameters of its methods.
+
+// Lint rule names, as written in -lint and [nolint]
+const (
+	LintMaxMethods = "max-methods"
+	LintMaxFields  = "max-fields"
+	LintMaxParams  = "max-params"
+)
+
+// AnnotationNoLint lists the comma separated lint rules that do not apply to an
+// interface, method or struct, e.g. [nolint="max-fields"]
+const AnnotationNoLint = "nolint"
+
+// lintRules lists the rules [nolint] may name on each kind of declaration
+var lintRules = map[string][]string{
+	"interface": {LintMaxMethods, LintMaxParams},
+	"method":    {LintMaxParams},
+	"struct":    {LintMaxFields},
+}
+
+// LintConfig holds the budgets Lint enforces. A budget of zero turns its rule off.
+type LintConfig struct {
+	MaxMethods int // methods an interface declares, not counting inherited ones
+	MaxFields  int // fields a struct declares, not counting those of the struct it extends
+	MaxParams  int // parameters of a method
+}
+
+// ParseLintConfig parses comma separated rule=budget pairs such as
+// "max-methods=20,max-fields=30,max-params=5"
+func ParseLintConfig(spec string) (LintConfig, error) {
+	var cfg LintConfig
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		rule, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return cfg, fmt.Errorf("lint rule %q needs a budget, e.g. %s=5", pair, pair)
+		}
+		budget, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || budget < 0 {
+			return cfg, fmt.Errorf("lint rule %s: budget must be a whole number (got %q)", rule, value)
+		}
+		switch strings.TrimSpace(rule) {
+		case LintMaxMethods:
+			cfg.MaxMethods = budget
+		case LintMaxFields:
+			cfg.MaxFields = budget
+		case LintMaxParams:
+			cfg.MaxParams = budget
+		default:
+			return cfg, fmt.Errorf("unknown lint rule %q (known rules: %s, %s, %s)", rule, LintMaxMethods, LintMaxFields, LintMaxParams)
+		}
+	}
+	return cfg, nil
+}
+
+// LintIssue is a declaration that exceeds the budget of a lint rule
+type LintIssue struct {
+	Pos  lexer.Position
+	Rule string
+	Msg  string
+}
+
+func (i *LintIssue) String() string {
+	return fmt.Sprintf("%d:%d: %s (%s)", i.Pos.Line, i.Pos.Column, i.Msg, i.Rule)
+}
+
+// Lint checks the interfaces, methods and structs of the IDL against the budgets
+// of cfg and returns the declarations that exceed them, in source order
+func Lint(idl *IDL, cfg LintConfig) []*LintIssue {
+	issues := make([]*LintIssue, 0)
+	for _, iface := range idl.Interfaces {
+		ifaceOff := noLint(iface.Annotations)
+		own := iface.OwnMethods()
+		if cfg.MaxMethods > 0 && len(own) > cfg.MaxMethods && !ifaceOff[LintMaxMethods] {
+			issues = append(issues, &LintIssue{
+				Pos:  iface.Pos,
+				Rule: LintMaxMethods,
+				Msg:  fmt.Sprintf("interface %s declares %d methods, more than the budget of %d", iface.Name, len(own), cfg.MaxMethods),
+			})
+		}
+		if cfg.MaxParams == 0 || ifaceOff[LintMaxParams] {
+			continue
+		}
+		for _, method := range own {
+			if len(method.Parameters) > cfg.MaxParams && !noLint(method.Annotations)[LintMaxParams] {
+				issues = append(issues, &LintIssue{
+					Pos:  method.Pos,
+					Rule: LintMaxParams,
+					Msg:  fmt.Sprintf("method %s.%s takes %d parameters, more than the budget of %d", iface.Name, method.Name, len(method.Parameters), cfg.MaxParams),
+				})
+			}
+		}
+	}
+	for _, s := range idl.Structs {
+		if cfg.MaxFields > 0 && len(s.Fields) > cfg.MaxFields && !noLint(s.Annotations)[LintMaxFields] {
+			issues = append(issues, &LintIssue{
+				Pos:  s.Pos,
+				Rule: LintMaxFields,
+				Msg:  fmt.Sprintf("struct %s declares %d fields, more than the budget of %d", s.Name, len(s.Fields), cfg.MaxFields),
+			})
+		}
+	}
+	// Structs from imported files may come before the interfaces that use them
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i].Pos, issues[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	return issues
+}
+
+// noLint returns the rules listed by the [nolint] annotation among annotations
+func noLint(annotations []*Annotation) map[string]bool {
+	off := make(map[string]bool)
+	for _, a := range annotations {
+		if a.Name != AnnotationNoLint {
+			continue
+		}
+		for _, rule := range strings.Split(a.Value, ",") {
+			off[strings.TrimSpace(rule)] = true
+		}
+	}
+	return off
+}
+
+// validateNoLint checks that a [nolint] annotation on the named declaration of the
+// given kind ("interface", "method" or "struct") names rules that apply to it
+func validateNoLint(a *Annotation, kind, name string, errors *ValidationErrors) {
+	allowed := lintRules[kind]
+	msg := ""
+	if strings.TrimSpace(a.Value) == "" {
+		msg = fmt.Sprintf("needs the rules to turn off, e.g. [nolint=\"%s\"]", allowed[0])
+	}
+	for _, rule := range strings.Split(a.Value, ",") {
+		rule = strings.TrimSpace(rule)
+		if msg != "" || rule == "" {
+			continue
+		}
+		known := false
+		for _, r := range allowed {
+			known = known || r == rule
+		}
+		if !known {
+			msg = fmt.Sprintf("names rule %q, which does not apply to a %s (rules: %s)", rule, kind, strings.Join(allowed, ", "))
+		}
+	}
+	if msg != "" {
+		errors.Add(&ValidationError{
+			File:   a.Pos.Filename,
+			Line:   a.Pos.Line,
+			Column: a.Pos.Column,
+			Msg:    fmt.Sprintf("annotation [nolint] on %s %s %s", kind, name, msg),
+		})
+	}
+}
@@ -28,8 +28,9 @@ var (
 		{Name: "Bool", Pattern: `bool`},
 		{Name: "Int", Pattern: `int`},
 		{Name: "Ident", Pattern: `[a-zA-Z][a-zA-Z0-9_]*`},
+		{Name: "Number", Pattern: `-?[0-9]+(\.[0-9]+)?([eE][+-]?[0-9]+)?`},
 		{Name: "Dot", Pattern: `\.`},
-		{Name: "Punct", Pattern: `[{}[\]();,]`},
+		{Name: "Punct", Pattern: `[{}[\]();,=@:]`},
 	})
 
 	parser = participle.MustBuild[IDLFile](
@@ -52,6 +53,7 @@ type IDLElement struct {
 	Interface *InterfaceDef `parser:"| 'interface' @@"`
 	Struct    *StructDef    `parser:"| 'struct' @@"`
 	Enum      *EnumDef      `parser:"| 'enum' @@"`
+	Typedef   *TypedefDef   `parser:"| 'typedef' @@"`
 }
 
 // ImportString is a custom type for parsing import paths
@@ -76,33 +78,97 @@ type NamespaceDef struct {
 
 // InterfaceDef represents an interface definition
 type InterfaceDef struct {
-	Pos     lexer.Position
-	Name    string       `parser:"@Ident '{'"`
-	Methods []*MethodDef `parser:"@@* '}'"`
+	Pos       lexer.Position
+	Name      string           `parser:"@Ident"`
+	Extends   []*QualifiedName `parser:"( 'extends' @@ ( ',' @@ )* )?"`
+	Modifiers []*ModifierDef   `parser:"@@*"`
+	Methods   []*MethodDef     `parser:"'{' @@* '}'"`
 }
 
-// MethodDef represents a method definition
+// MethodDef represents a method definition and the @example blocks above it
 type MethodDef struct {
-	Pos            lexer.Position
-	Name           string          `parser:"@Ident '('"`
-	Parameters     []*ParameterDef `parser:"( @@ (',' @@)* )? ')'"`
-	ReturnType     *TypeExpr       `parser:"@@"`
-	ReturnOptional bool            `parser:"( @Optional )?"`
+	Examples   []*ExampleDef   `parser:"@@*"`
+	Name       *MethodNameDef  `parser:"@@ '('"`
+	Parameters []*ParameterDef `parser:"( @@ (',' @@)* )? ')'"`
+	ReturnType *TypeExpr       `parser:"@@"`
+	Modifiers  []*ModifierDef  `parser:"@@*"`
+}
+
+// MethodNameDef is the name of a method, kept apart from MethodDef so that its
+// position is that of the name rather than of any @example block before it
+type MethodNameDef struct {
+	Pos  lexer.Position
+	Name string `parser:"@Ident"`
+}
+
+// ExampleDef represents an @example(params=..., result=...) block before a method
+type ExampleDef struct {
+	Pos  lexer.Position
+	Args []*ExampleArgDef `parser:"'@' 'example' '(' ( @@ ( ',' @@ )* )? ')'"`
+}
+
+// ExampleArgDef is a name=value argument of an @example block, with a JSON value
+type ExampleArgDef struct {
+	Pos   lexer.Position
+	Name  string        `parser:"@Ident '='"`
+	Value *JSONValueDef `parser:"@@"`
+}
+
+// JSONValueDef represents a JSON value in an @example block. Strings are
+// StringLiterals, so they cannot contain a double quote.
+type JSONValueDef struct {
+	Pos     lexer.Position
+	String  *string        `parser:"  @StringLiteral"`
+	Number  *string        `parser:"| @Number"`
+	Literal *string        `parser:"| @( 'true' | 'false' | 'null' )"`
+	Object  *JSONObjectDef `parser:"| @@"`
+	Array   *JSONArrayDef  `parser:"| @@"`
+}
+
+// JSONObjectDef represents a JSON object. Open is always true; it makes sure an
+// empty object is captured.
+type JSONObjectDef struct {
+	Open    bool             `parser:"@'{'"`
+	Members []*JSONMemberDef `parser:"( @@ ( ',' @@ )* )? '}'"`
+}
+
+// JSONMemberDef is a "key": value member of a JSON object
+type JSONMemberDef struct {
+	Key   string        `parser:"@StringLiteral ':'"`
+	Value *JSONValueDef `parser:"@@"`
+}
+
+// JSONArrayDef represents a JSON array. Open is always true; it makes sure an
+// empty array is captured.
+type JSONArrayDef struct {
+	Open     bool            `parser:"@'['"`
+	Elements []*JSONValueDef `parser:"( @@ ( ',' @@ )* )? ']'"`
+}
+
+// ModifierDef represents a bracketed modifier following an interface or struct name, a method
+// return type, a parameter type or a field type: either [optional] or an annotation such as [readonly] or [name="value"]
+type ModifierDef struct {
+	Pos      lexer.Position
+	Optional bool    `parser:"  @Optional"`
+	Name     string  `parser:"| '[' @Ident"`
+	Value    *string `parser:"  ( '=' @StringLiteral )? ']'"`
 }
 
 // ParameterDef represents a parameter definition
 type ParameterDef struct {
-	Pos  lexer.Position
-	Name string    `parser:"@Ident"`
-	Type *TypeExpr `parser:"@@"`
+	Pos       lexer.Position
+	Name      string         `parser:"@Ident"`
+	Type      *TypeExpr      `parser:"@@"`
+	Modifiers []*ModifierDef `parser:"@@*"`
 }
 
 // StructDef represents a struct definition
 type StructDef struct {
-	Pos     lexer.Position
-	Name    string         `parser:"@Ident"`
-	Extends *QualifiedName `parser:"( 'extends' @@ )?"`
-	Fields  []*FieldDef    `parser:"'{' @@* '}'"`
+	Pos       lexer.Position
+	Name      string         `parser:"@Ident"`
+	Extends   *QualifiedName `parser:"( 'extends' @@ )?"`
+	Modifiers []*ModifierDef `parser:"@@*"`
+	Fields    []*FieldDef    `parser:"'{' @@* '}'"`
 }
 
 // QualifiedName represents a qualified type name (e.g., "inc.Response" or "Response")
@@ -118,10 +184,10 @@ func (q *QualifiedName) String() string {
 
 // FieldDef represents a field definition
 type FieldDef struct {
-	Pos      lexer.Position
-	Name     string    `parser:"@Ident"`
-	Type     *TypeExpr `parser:"@@"`
-	Optional bool      `parser:"( @Optional )?"`
+	Pos       lexer.Position
+	Name      string         `parser:"@Ident"`
+	Type      *TypeExpr      `parser:"@@"`
+	Modifiers []*ModifierDef `parser:"@@*"`
 }
 
 // EnumDef represents an enum definition
@@ -131,6 +197,14 @@ type EnumDef struct {
 	Values []string `parser:"@Ident* '}'"`
 }
 
+// TypedefDef represents a typedef declaration. typedef is not a lexer keyword, so
+// identifiers such as typedefs keep working.
+type TypedefDef struct {
+	Pos  lexer.Position
+	Name string    `parser:"@Ident"`
+	Type *TypeExpr `parser:"@@"`
+}
+
 // TypeExpr represents a type expression
 type TypeExpr struct {
 	Pos         lexer.Position
@@ -320,25 +394,66 @@ func extractEnumValueComments(input string, enumPos lexer.Position, values []str
 // ParseIDL parses an IDL file string and returns the parsed IDL structure
 // filename is used for resolving relative imports
 func ParseIDL(filename string, input string) (*IDL, error) {
-	visited := make(map[string]bool)
-	return parseIDLWithImports(filename, input, visited)
+	var files []*IDL
+	if err := loadIDLFiles(filename, input, make(map[string]bool), make(map[string]bool), &files); err != nil {
+		return nil, err
+	}
+	idl := mergeIDLFiles(files)
+	ResolveTypedefs(idl)
+	ResolveInterfaceInheritance(idl)
+	return idl, nil
 }
 
-// parseIDLWithImports parses an IDL file and resolves imports recursively
-func parseIDLWithImports(filename string, input string, visited map[string]bool) (*IDL, error) {
+// loadIDLFiles parses an IDL file and, recursively, the files it imports, appending
+// each to files before its imports. A file imported by several others is parsed
+// once; loaded holds the files parsed so far and importing those whose imports are
+// being loaded, which a cycle leads back to.
+func loadIDLFiles(filename string, input string, loaded, importing map[string]bool, files *[]*IDL) error {
 	// Normalize filename path
 	absPath, err := filepath.Abs(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve absolute path for %s: %w", filename, err)
+		return fmt.Errorf("failed to resolve absolute path for %s: %w", filename, err)
 	}
 
 	// Check for import cycles
-	if visited[absPath] {
-		return nil, fmt.Errorf("import cycle detected: file %s is already being processed", filename)
+	if importing[absPath] {
+		return fmt.Errorf("import cycle detected: file %s is already being processed", filename)
+	}
+	if loaded[absPath] {
+		return nil
+	}
+	loaded[absPath] = true
+	importing[absPath] = true
+	defer delete(importing, absPath)
+
+	idl, imports, err := parseIDLFile(filename, input)
+	if err != nil {
+		return err
 	}
-	visited[absPath] = true
-	defer delete(visited, absPath)
+	*files = append(*files, idl)
 
+	// Resolve imports relative to the current file's directory
+	baseDir := filepath.Dir(absPath)
+	for _, importPath := range imports {
+		resolvedPath := importPath
+		if !filepath.IsAbs(importPath) {
+			resolvedPath = filepath.Join(baseDir, importPath)
+		}
+		importedContent, err := os.ReadFile(resolvedPath)
+		if err != nil {
+			return fmt.Errorf("failed to read import file %s (resolved from %s): %w", resolvedPath, importPath, err)
+		}
+		if err := loadIDLFiles(resolvedPath, string(importedContent), loaded, importing, files); err != nil {
+			return fmt.Errorf("failed to parse imported file %s: %w", resolvedPath, err)
+		}
+	}
+	return nil
+}
+
+// parseIDLFile parses a single IDL file into an IDL whose RootNamespace is the
+// file's namespace and whose declarations have unqualified names, and returns
+// the paths the file imports
+func parseIDLFile(filename string, input string) (*IDL, []string, error) {
 	// Pre-process: extract imports manually using regex
 	importRegex := regexp.MustCompile(`(?m)^\s*import\s+"([^"]+)"`)
 	importMatches := importRegex.FindAllStringSubmatch(input, -1)
@@ -362,7 +477,7 @@ func parseIDLWithImports(filename string, input string, visited map[string]bool)
 	// Parse the file
 	file, err := parser.ParseString(filename, filteredInput)
 	if err != nil {
-		return nil, fmt.Errorf("parse error: %w", err)
+		return nil, nil, fmt.Errorf("parse error: %w", err)
 	}
 
 	// Extract namespace
@@ -370,77 +485,12 @@ func parseIDLWithImports(filename string, input string, visited map[string]bool)
 	for _, elem := range file.Elements {
 		if elem.Namespace != nil {
 			if namespace != "" {
-				return nil, fmt.Errorf("multiple namespace declarations in file %s", filename)
+				return nil, nil, fmt.Errorf("multiple namespace declarations in file %s", filename)
 			}
 			namespace = elem.Namespace.Name
 		}
 	}
 
-	// Resolve imports
-	baseDir := filepath.Dir(absPath)
-	importedIDLs := make([]struct {
-		namespace string
-		idl       *IDL
-	}, 0)
-	namespaceMap := make(map[string]string) // namespace -> file path
-
-	for _, importPath := range imports {
-		// Resolve import path relative to current file's directory
-		resolvedPath := importPath
-		if !filepath.IsAbs(importPath) {
-			resolvedPath = filepath.Join(baseDir, importPath)
-		}
-
-		// Read and parse imported file
-		importedContent, err := os.ReadFile(resolvedPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read import file %s (resolved from %s): %w", resolvedPath, importPath, err)
-		}
-
-		importedIDL, err := parseIDLWithImports(resolvedPath, string(importedContent), visited)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse imported file %s: %w", resolvedPath, err)
-		}
-
-		// Get namespace from imported file
-		importedNamespace := ""
-		for _, s := range importedIDL.Structs {
-			if s.Namespace != "" {
-				importedNamespace = s.Namespace
-				break
-			}
-		}
-		if importedNamespace == "" {
-			for _, e := range importedIDL.Enums {
-				if e.Namespace != "" {
-					importedNamespace = e.Namespace
-					break
-				}
-			}
-		}
-		if importedNamespace == "" {
-			for _, i := range importedIDL.Interfaces {
-				if i.Namespace != "" {
-					importedNamespace = i.Namespace
-					break
-				}
-			}
-		}
-
-		// Check for duplicate namespace
-		if importedNamespace != "" {
-			if existingFile, exists := namespaceMap[importedNamespace]; exists {
-				return nil, fmt.Errorf("duplicate namespace %s: already used in %s, also used in %s", importedNamespace, existingFile, resolvedPath)
-			}
-			namespaceMap[importedNamespace] = resolvedPath
-		}
-
-		importedIDLs = append(importedIDLs, struct {
-			namespace string
-			idl       *IDL
-		}{namespace: importedNamespace, idl: importedIDL})
-	}
-
 	// Post-process to parse nested types recursively
 	var processTypeExpr = func(expr *TypeExpr) error {
 		if expr == nil {
@@ -453,20 +503,24 @@ func parseIDLWithImports(filename string, input string, visited map[string]bool)
 		if elem.Interface != nil {
 			for _, m := range elem.Interface.Methods {
 				if err := processTypeExpr(m.ReturnType); err != nil {
-					return nil, fmt.Errorf("error processing return type: %w", err)
+					return nil, nil, fmt.Errorf("error processing return type: %w", err)
 				}
 				for _, p := range m.Parameters {
 					if err := processTypeExpr(p.Type); err != nil {
-						return nil, fmt.Errorf("error processing parameter type: %w", err)
+						return nil, nil, fmt.Errorf("error processing parameter type: %w", err)
 					}
 				}
 			}
 		} else if elem.Struct != nil {
 			for _, f := range elem.Struct.Fields {
 				if err := processTypeExpr(f.Type); err != nil {
-					return nil, fmt.Errorf("error processing field type: %w", err)
+					return nil, nil, fmt.Errorf("error processing field type: %w", err)
 				}
 			}
+		} else if elem.Typedef != nil {
+			if err := processTypeExpr(elem.Typedef.Type); err != nil {
+				return nil, nil, fmt.Errorf("error processing typedef type: %w", err)
+			}
 		}
 	}
 
@@ -489,20 +543,65 @@ func parseIDLWithImports(filename string, input string, visited map[string]bool)
 				Comment:   interfaceComment,
 				Methods:   make([]*Method, 0),
 			}
+			for _, parent := range elem.Interface.Extends {
+				iface.Extends = append(iface.Extends, parent.String())
+			}
+			for _, mod := range elem.Interface.Modifiers {
+				// [optional] means nothing on an interface, so it is kept as an
+				// annotation for the validator to reject
+				annotation := &Annotation{Pos: mod.Pos, Name: mod.Name}
+				if mod.Optional {
+					annotation.Name = "optional"
+				}
+				if mod.Value != nil {
+					annotation.Value = strings.Trim(*mod.Value, `"`)
+				}
+				iface.Annotations = append(iface.Annotations, annotation)
+			}
 			for _, m := range elem.Interface.Methods {
+				examples, err := convertExamples(m.Examples)
+				if err != nil {
+					return nil, nil, fmt.Errorf("parse error: %s:%w", filename, err)
+				}
 				method := &Method{
-					Pos:            m.Pos,
-					Name:           m.Name,
-					Parameters:     make([]*Parameter, 0),
-					ReturnType:     convertTypeExpr(m.ReturnType),
-					ReturnOptional: m.ReturnOptional,
+					Pos:        m.Name.Pos,
+					Name:       m.Name.Name,
+					Parameters: make([]*Parameter, 0),
+					ReturnType: convertTypeExpr(m.ReturnType),
+					Examples:   examples,
+				}
+				for _, mod := range m.Modifiers {
+					if mod.Optional {
+						method.ReturnOptional = true
+						continue
+					}
+					annotation := &Annotation{Pos: mod.Pos, Name: mod.Name}
+					if mod.Value != nil {
+						annotation.Value = strings.Trim(*mod.Value, `"`)
+					}
+					method.Annotations = append(method.Annotations, annotation)
 				}
 				for _, p := range m.Parameters {
-					method.Parameters = append(method.Parameters, &Parameter{
+					param := &Parameter{
 						Pos:  p.Pos,
 						Name: p.Name,
 						Type: convertTypeExpr(p.Type),
-					})
+					}
+					for _, mod := range p.Modifiers {
+						if mod.Optional {
+							param.Optional = true
+							continue
+						}
+						annotation := &Annotation{Pos: mod.Pos, Name: mod.Name}
+						if mod.Value != nil {
+							annotation.Value = strings.Trim(*mod.Value, `"`)
+						}
+						if annotation.Name == AnnotationDefault {
+							param.Optional = true
+						}
+						param.Annotations = append(param.Annotations, annotation)
+					}
+					method.Parameters = append(method.Parameters, param)
 				}
 				iface.Methods = append(iface.Methods, method)
 			}
@@ -521,16 +620,38 @@ func parseIDLWithImports(filename string, input string, visited map[string]bool)
 			if elem.Struct.Extends != nil {
 				s.Extends = elem.Struct.Extends.String()
 			}
+			for _, mod := range elem.Struct.Modifiers {
+				// As on interfaces, [optional] is left for the validator to reject
+				annotation := &Annotation{Pos: mod.Pos, Name: mod.Name}
+				if mod.Optional {
+					annotation.Name = "optional"
+				}
+				if mod.Value != nil {
+					annotation.Value = strings.Trim(*mod.Value, `"`)
+				}
+				s.Annotations = append(s.Annotations, annotation)
+			}
 			for _, f := range elem.Struct.Fields {
 				// Extract field comment
 				fieldComment := extractPrecedingComments(filteredInput, f.Pos)
-				s.Fields = append(s.Fields, &Field{
-					Pos:      f.Pos,
-					Name:     f.Name,
-					Type:     convertTypeExpr(f.Type),
-					Optional: f.Optional,
-					Comment:  fieldComment,
-				})
+				field := &Field{
+					Pos:     f.Pos,
+					Name:    f.Name,
+					Type:    convertTypeExpr(f.Type),
+					Comment: fieldComment,
+				}
+				for _, mod := range f.Modifiers {
+					if mod.Optional {
+						field.Optional = true
+						continue
+					}
+					annotation := &Annotation{Pos: mod.Pos, Name: mod.Name}
+					if mod.Value != nil {
+						annotation.Value = strings.Trim(*mod.Value, `"`)
+					}
+					field.Annotations = append(field.Annotations, annotation)
+				}
+				s.Fields = append(s.Fields, field)
 			}
 			idl.Structs = append(idl.Structs, s)
 		} else if elem.Enum != nil {
@@ -560,92 +681,115 @@ func parseIDLWithImports(filename string, input string, visited map[string]bool)
 				Comment:   enumComment,
 				Values:    enumValues,
 			})
+		} else if elem.Typedef != nil {
+			idl.Typedefs = append(idl.Typedefs, &Typedef{
+				Pos:       elem.Typedef.Pos,
+				Name:      elem.Typedef.Name,
+				Namespace: namespace,
+				Comment:   extractPrecedingComments(filteredInput, elem.Typedef.Pos),
+				Type:      convertTypeExpr(elem.Typedef.Type),
+			})
 		}
 	}
 
-	// Merge imported IDLs with namespace prefixes
-	for _, imported := range importedIDLs {
-		importedNamespace := imported.namespace
-		importedIDL := imported.idl
-		if importedNamespace != "" {
-			// Build a map of unqualified to qualified names for this namespace
-			typeMap := make(map[string]string)
-			for _, s := range importedIDL.Structs {
-				if s.Namespace == importedNamespace {
-					typeMap[s.Name] = importedNamespace + "." + s.Name
-				}
+	return idl, imports, nil
+}
+
+// mergeIDLFiles merges the files of an IDL, the root file first, into a single IDL.
+// A namespace may be split across files. Declarations outside the root namespace
+// are qualified with their namespace, e.g. inc.Response, and so are the
+// unqualified names they reference that any file of their namespace declares.
+func mergeIDLFiles(files []*IDL) *IDL {
+	root := files[0].RootNamespace
+
+	// names holds the names each namespace declares across its files
+	names := make(map[string]map[string]bool)
+	for _, file := range files {
+		declared := names[file.RootNamespace]
+		if declared == nil {
+			declared = make(map[string]bool)
+			names[file.RootNamespace] = declared
+		}
+		for _, s := range file.Structs {
+			declared[s.Name] = true
+		}
+		for _, e := range file.Enums {
+			declared[e.Name] = true
+		}
+		for _, i := range file.Interfaces {
+			declared[i.Name] = true
+		}
+		for _, td := range file.Typedefs {
+			declared[td.Name] = true
+		}
+	}
+
+	idl := &IDL{
+		RootNamespace: root,
+		Interfaces:    make([]*Interface, 0),
+		Structs:       make([]*Struct, 0),
+		Enums:         make([]*Enum, 0),
+	}
+	for _, file := range files {
+		namespace := file.RootNamespace
+		// Types of the root namespace, and of files without one, keep their names
+		qualify := func(name string) string {
+			if namespace != "" && namespace != root && names[namespace][name] {
+				return namespace + "." + name
 			}
-			for _, e := range importedIDL.Enums {
-				if e.Namespace == importedNamespace {
-					typeMap[e.Name] = importedNamespace + "." + e.Name
-				}
+			return name
+		}
+		// Update type references, including array elements and map values
+		var updateTypeRefs func(t *Type)
+		updateTypeRefs = func(t *Type) {
+			if t == nil {
+				return
 			}
-			for _, i := range importedIDL.Interfaces {
-				if i.Namespace == importedNamespace {
-					typeMap[i.Name] = importedNamespace + "." + i.Name
-				}
+			if t.IsUserDefined() {
+				t.UserDefined = qualify(t.UserDefined)
 			}
+			updateTypeRefs(t.Array)
+			updateTypeRefs(t.MapValue)
+		}
 
-			// Update type references within the same namespace to use qualified names
-			updateTypeRefs := func(t *Type) {
-				if t != nil && t.IsUserDefined() {
-					if qualified, exists := typeMap[t.UserDefined]; exists {
-						t.UserDefined = qualified
-					}
-				}
+		for _, s := range file.Structs {
+			s.Name = qualify(s.Name)
+			for _, f := range s.Fields {
+				updateTypeRefs(f.Type)
 			}
-
-			// Prefix types from the imported file with the imported namespace
-			// Types from nested imports already have their namespace prefix
-			for _, s := range importedIDL.Structs {
-				// If the type's namespace matches the imported file's namespace, it's a local type - prefix it
-				// If it has a different namespace, it's from a nested import - keep it as-is
-				if s.Namespace == importedNamespace {
-					// Local type from imported file - prefix it
-					s.Name = importedNamespace + "." + s.Name
-					// Update field type references
-					for _, f := range s.Fields {
-						updateTypeRefs(f.Type)
-					}
-					// Update extends reference
-					if s.Extends != "" {
-						if qualified, exists := typeMap[s.Extends]; exists {
-							s.Extends = qualified
-						}
-					}
-				}
-				idl.Structs = append(idl.Structs, s)
+			if s.Extends != "" {
+				s.Extends = qualify(s.Extends)
 			}
-			for _, e := range importedIDL.Enums {
-				if e.Namespace == importedNamespace {
-					// Local type from imported file - prefix it
-					e.Name = importedNamespace + "." + e.Name
-				}
-				idl.Enums = append(idl.Enums, e)
+			idl.Structs = append(idl.Structs, s)
+		}
+		for _, e := range file.Enums {
+			e.Name = qualify(e.Name)
+			idl.Enums = append(idl.Enums, e)
+		}
+		for _, i := range file.Interfaces {
+			i.Name = qualify(i.Name)
+			for j, parent := range i.Extends {
+				i.Extends[j] = qualify(parent)
 			}
-			for _, i := range importedIDL.Interfaces {
-				if i.Namespace == importedNamespace {
-					// Local type from imported file - prefix it
-					i.Name = importedNamespace + "." + i.Name
-					// Update method parameter and return type references
-					for _, m := range i.Methods {
-						updateTypeRefs(m.ReturnType)
-						for _, p := range m.Parameters {
-							updateTypeRefs(p.Type)
-						}
-					}
+			// Update method parameter, return type and [errordata] references
+			for _, m := range i.Methods {
+				updateTypeRefs(m.ReturnType)
+				for _, p := range m.Parameters {
+					updateTypeRefs(p.Type)
+				}
+				if a := m.Annotation(AnnotationErrorData); a != nil {
+					a.Value = qualify(a.Value)
 				}
-				idl.Interfaces = append(idl.Interfaces, i)
 			}
-		} else {
-			// No namespace - add types as-is
-			idl.Structs = append(idl.Structs, importedIDL.Structs...)
-			idl.Enums = append(idl.Enums, importedIDL.Enums...)
-			idl.Interfaces = append(idl.Interfaces, importedIDL.Interfaces...)
+			idl.Interfaces = append(idl.Interfaces, i)
+		}
+		for _, td := range file.Typedefs {
+			td.Name = qualify(td.Name)
+			updateTypeRefs(td.Type)
+			idl.Typedefs = append(idl.Typedefs, td)
 		}
 	}
-
-	return idl, nil
+	return idl
 }
 
 // convertTypeExpr converts a TypeExpr from the grammar to a Type in the IDL structure
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 // Helper function to parse and validate in one call
@@ -1181,6 +1182,63 @@ struct ExtendedStruct extends base.BaseStruct {
 	}
 }
 
+func TestResolvedFields(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestFile(t, tmpDir, "base.pulse", `namespace base
+
+struct Entity {
+    id string
+}`)
+	mainFile := createTestFile(t, tmpDir, "main.pulse", `namespace shop
+
+import "base.pulse"
+
+struct Named extends base.Entity {
+    name string
+}
+
+struct Product extends Named {
+    price float
+    sku   string
+}`)
+	idl, err := parseIDLFromFile(t, mainFile)
+	if err != nil {
+		t.Fatalf("Expected valid parse, got error: %v", err)
+	}
+
+	fieldNames := func(fields []*Field) string {
+		names := make([]string, len(fields))
+		for i, f := range fields {
+			names[i] = f.Name
+		}
+		return strings.Join(names, ",")
+	}
+	if got := fieldNames(idl.ResolvedFields("Product")); got != "id,name,price,sku" {
+		t.Errorf("Product: expected id,name,price,sku, got %s", got)
+	}
+	if got := fieldNames(idl.ResolvedFields("base.Entity")); got != "id" {
+		t.Errorf("base.Entity: expected id, got %s", got)
+	}
+	if fields := idl.ResolvedFields("Missing"); fields != nil {
+		t.Errorf("Expected nil for an unknown struct, got %v", fields)
+	}
+
+	// The root namespace keeps its types unqualified, and an IDL that was not
+	// merged leaves extends unqualified within a namespace
+	unmerged := &IDL{Structs: []*Struct{
+		{Name: "Response", Namespace: "inc", Fields: []*Field{{Name: "status"}}},
+		{Name: "app.RepeatResponse", Namespace: "app", Extends: "inc.Response", Fields: []*Field{{Name: "items"}}},
+		{Name: "app.Paged", Namespace: "app", Extends: "RepeatResponse", Fields: []*Field{{Name: "page"}}},
+		{Name: "Loop", Extends: "Loop", Fields: []*Field{{Name: "next"}}},
+	}}
+	if got := fieldNames(unmerged.ResolvedFields("app.Paged")); got != "status,items,page" {
+		t.Errorf("app.Paged: expected status,items,page, got %s", got)
+	}
+	if got := fieldNames(unmerged.ResolvedFields("Loop")); got != "next" {
+		t.Errorf("Loop: expected next, got %s", got)
+	}
+}
+
 // Test qualified type in method parameters/returns
 func TestQualifiedTypeInMethodSignature(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -1298,38 +1356,121 @@ struct CStruct {
 	}
 }
 
-// Test duplicate namespace names across different files
-func TestDuplicateNamespace(t *testing.T) {
+// Test a namespace split across files, including the root namespace
+func TestNamespaceSplitAcrossFiles(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	// Both files use namespace "inc"
-	file1Content := `namespace inc
+	// Both files use namespace "inc" and reference each other's types
+	createTestFile(t, tmpDir, "file1.pulse", `namespace inc
 
 struct Struct1 {
-    value string
-}`
-	createTestFile(t, tmpDir, "file1.pulse", file1Content)
-
-	file2Content := `namespace inc
+    other Struct2
+}`)
+	createTestFile(t, tmpDir, "file2.pulse", `namespace inc
 
 struct Struct2 {
-    value int
-}`
-	createTestFile(t, tmpDir, "file2.pulse", file2Content)
+    value Kind
+}
+
+enum Kind {
+    a
+}`)
+	createTestFile(t, tmpDir, "types.pulse", `namespace main
+
+struct Local {
+    value inc.Struct1
+}`)
 
 	mainContent := `import "file1.pulse"
 import "file2.pulse"
+import "types.pulse"
+namespace main
 
-struct MainStruct {
-    value bool
+interface Service {
+    get() Local
 }`
 	mainFile := createTestFile(t, tmpDir, "main.pulse", mainContent)
 
-	_, err := parseIDLFromFile(t, mainFile)
-	if err == nil {
-		t.Error("Expected error for duplicate namespace, got nil")
-	} else if !strings.Contains(err.Error(), "namespace") && !strings.Contains(err.Error(), "duplicate") {
-		t.Errorf("Expected namespace-related error, got: %v", err)
+	idl, err := parseIDLFromFile(t, mainFile)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+	var got []string
+	for _, s := range idl.Structs {
+		for _, f := range s.Fields {
+			got = append(got, s.Name+"."+f.Name+" "+f.Type.UserDefined)
+		}
+	}
+	// Declarations are merged in import order, each file after the one importing it
+	want := "inc.Struct1.other inc.Struct2, inc.Struct2.value inc.Kind, Local.value inc.Struct1"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("Expected fields %s, got %s", want, strings.Join(got, ", "))
+	}
+
+	// A name declared by two files of a namespace is a duplicate
+	createTestFile(t, tmpDir, "file3.pulse", `namespace inc
+
+struct Struct1 {
+    value int
+}`)
+	dupFile := createTestFile(t, tmpDir, "dup.pulse", `import "file1.pulse"
+import "file2.pulse"
+import "file3.pulse"
+namespace main`)
+	idl, err = parseIDLFromFile(t, dupFile)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err == nil || !strings.Contains(err.Error(), "duplicate type name: inc.Struct1") {
+		t.Errorf("Expected duplicate type error, got %v", err)
+	}
+}
+
+// Test a file imported by several files (A → B → D, A → C → D)
+func TestDiamondImport(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	createTestFile(t, tmpDir, "d.pulse", `namespace common
+
+struct Money {
+    cents int
+}`)
+	createTestFile(t, tmpDir, "b.pulse", `import "d.pulse"
+namespace billing
+
+struct Invoice {
+    total common.Money
+}`)
+	createTestFile(t, tmpDir, "c.pulse", `import "d.pulse"
+namespace orders
+
+struct Order {
+    total common.Money
+}`)
+	aFile := createTestFile(t, tmpDir, "a.pulse", `import "b.pulse"
+import "c.pulse"
+namespace shop
+
+interface Shop {
+    place(order orders.Order) billing.Invoice
+}`)
+
+	idl, err := parseIDLFromFile(t, aFile)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+	var names []string
+	for _, s := range idl.Structs {
+		names = append(names, s.Name)
+	}
+	if got := strings.Join(names, ","); got != "billing.Invoice,common.Money,orders.Order" {
+		t.Errorf("Expected each struct once in import order, got %s", got)
 	}
 }
 
@@ -1955,3 +2096,1112 @@ func TestNoCommentWhenNonePresent(t *testing.T) {
 		t.Errorf("Expected empty comment, got '%s'", s.Comment)
 	}
 }
+
+// ============================================================================
+// Method Annotation Tests
+// ============================================================================
+
+func TestMethodAnnotations(t *testing.T) {
+	input := `enum Status {
+  active
+}
+interface Catalog {
+  getProduct(productId string) string [optional] [readonly]
+  listByStatus(status Status, ids []int) []string [readonly]
+  save(name string) bool
+}`
+	idl, err := parseAndValidate(input)
+	if err != nil {
+		t.Fatalf("Expected valid parsing, got error: %v", err)
+	}
+	methods := idl.Interfaces[0].Methods
+	if !methods[0].ReturnOptional || !methods[0].IsReadOnly() {
+		t.Errorf("getProduct: expected optional return and [readonly], got optional=%v readonly=%v", methods[0].ReturnOptional, methods[0].IsReadOnly())
+	}
+	if methods[1].ReturnOptional || !methods[1].IsReadOnly() {
+		t.Errorf("listByStatus: expected non-optional [readonly] method")
+	}
+	if methods[2].IsReadOnly() || len(methods[2].Annotations) != 0 {
+		t.Errorf("save: expected no annotations, got %v", methods[2].Annotations)
+	}
+}
+
+func TestMethodAnnotationOrderAndValue(t *testing.T) {
+	input := `namespace test
+interface Catalog {
+  getProduct(productId string) string [readonly] [optional]
+}`
+	idl, err := ParseIDL("test.pulse", input)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	method := idl.Interfaces[0].Methods[0]
+	if !method.ReturnOptional || !method.IsReadOnly() {
+		t.Errorf("Expected [optional] and [readonly] in either order, got optional=%v readonly=%v", method.ReturnOptional, method.IsReadOnly())
+	}
+
+	input = `namespace test
+interface Catalog {
+  getProduct(productId string) string [readonly="yes"]
+}`
+	idl, err = ParseIDL("test.pulse", input)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if a := idl.Interfaces[0].Methods[0].Annotation("readonly"); a == nil || a.Value != "yes" {
+		t.Errorf("Expected annotation value 'yes', got %+v", a)
+	}
+}
+
+func TestInvalidUnknownMethodAnnotation(t *testing.T) {
+	input := `interface Catalog {
+  getProduct(productId string) string [readnoly]
+}`
+	assertValidationError(t, input, "unknown annotation [readnoly]")
+}
+
+func TestInvalidDuplicateMethodAnnotation(t *testing.T) {
+	input := `interface Catalog {
+  getProduct(productId string) string [readonly] [readonly]
+}`
+	assertValidationError(t, input, "duplicate annotation [readonly]")
+}
+
+func TestWireNames(t *testing.T) {
+	input := `interface Base [wire="v1.base"] {
+  ping() string
+}
+interface Users extends Base [wire="v1.user"] {
+  get(id string) string
+  remove(id string) bool [wire="v1.user.delete"]
+}
+interface Plain {
+  echo(s string) string [wire="echo"]
+  hello(s string) string
+}`
+	idl, err := parseAndValidate(input)
+	if err != nil {
+		t.Fatalf("Expected valid parsing, got error: %v", err)
+	}
+	want := map[string][]string{
+		"Base":  {"v1.base.ping"},
+		"Users": {"v1.user.get", "v1.user.delete", "v1.user.ping"},
+		"Plain": {"echo", "Plain.hello"},
+	}
+	for _, iface := range idl.Interfaces {
+		var got []string
+		for _, m := range iface.Methods {
+			got = append(got, iface.RPCName(m))
+		}
+		if strings.Join(got, " ") != strings.Join(want[iface.Name], " ") {
+			t.Errorf("%s: expected JSON-RPC names %v, got %v", iface.Name, want[iface.Name], got)
+		}
+	}
+}
+
+func TestInvalidWireNames(t *testing.T) {
+	assertValidationError(t, `interface Users [readonly] {
+  get(id string) string
+}`, "unknown annotation [readonly] on interface Users")
+	assertValidationError(t, `interface Users [optional] {
+  get(id string) string
+}`, "unknown annotation [optional] on interface Users")
+	assertValidationError(t, `interface Users [wire="v1"] [wire="v2"] {
+  get(id string) string
+}`, "duplicate annotation [wire] on interface Users")
+	assertValidationError(t, `interface Users [wire] {
+  get(id string) string
+}`, "annotation [wire] on interface Users needs a name")
+	assertValidationError(t, `interface Users {
+  get(id string) string [wire="v1 user"]
+}`, "annotation [wire] on method get may only contain")
+	assertValidationError(t, `interface Users {
+  get(id string) string [wire="pulserpc-get"]
+}`, "reserved prefix pulserpc-")
+	assertValidationError(t, `interface Users {
+  get(id string) string [wire="v1.get"]
+  find(id string) string [wire="v1.get"]
+}`, "method Users.find has the JSON-RPC name v1.get, which is already used by Users.get")
+	assertValidationError(t, `interface Users {
+  get(id string) string
+}
+interface Accounts [wire="Users"] {
+  get(id string) string
+}`, "method Accounts.get has the JSON-RPC name Users.get, which is already used by Users.get")
+}
+
+func TestOwnershipAnnotations(t *testing.T) {
+	input := `interface Base [owner="team-core"] [stability="stable"] {
+  ping() string
+  probe() string [stability="experimental"]
+}
+interface Users extends Base [owner="team-users"] {
+  get(id string) string
+  purge() int [owner="team-ops"] [stability="experimental"]
+}`
+	idl, err := parseAndValidate(input)
+	if err != nil {
+		t.Fatalf("Expected valid parsing, got error: %v", err)
+	}
+	want := map[string][]string{
+		"Base":  {"ping team-core stable", "probe team-core experimental"},
+		"Users": {"get team-users ", "purge team-ops experimental", "ping team-core stable", "probe team-core experimental"},
+	}
+	for _, iface := range idl.Interfaces {
+		var got []string
+		for _, m := range iface.Methods {
+			got = append(got, m.Name+" "+idl.MethodOwner(iface, m)+" "+idl.MethodStability(iface, m))
+		}
+		if strings.Join(got, ", ") != strings.Join(want[iface.Name], ", ") {
+			t.Errorf("%s: expected %v, got %v", iface.Name, want[iface.Name], got)
+		}
+	}
+}
+
+func TestInvalidOwnershipAnnotations(t *testing.T) {
+	assertValidationError(t, `interface Users [owner=""] {
+  get(id string) string
+}`, "annotation [owner] on interface Users needs a team name")
+	assertValidationError(t, `interface Users [stability="beta"] {
+  get(id string) string
+}`, `annotation [stability] on interface Users must be "experimental" or "stable" (got "beta")`)
+	assertValidationError(t, `interface Users {
+  get(id string) string [stability]
+}`, `annotation [stability] on method get must be "experimental" or "stable" (got "")`)
+	assertValidationError(t, `interface Users {
+  get(id string) string [owner="a"] [owner="b"]
+}`, "duplicate annotation [owner] on method get")
+}
+
+func TestFieldAnnotations(t *testing.T) {
+	input := `namespace test
+struct Login {
+  user     string
+  password string [sensitive]
+  token    string [sensitive] [optional]
+  ssn      string [encrypted] [sensitive]
+}`
+	idl, err := parseAndValidate(input)
+	if err != nil {
+		t.Fatalf("Expected valid parsing, got error: %v", err)
+	}
+	fields := idl.Structs[0].Fields
+	if fields[0].IsSensitive() || len(fields[0].Annotations) != 0 {
+		t.Errorf("user: expected no annotations, got %v", fields[0].Annotations)
+	}
+	if !fields[1].IsSensitive() || fields[1].Optional {
+		t.Errorf("password: expected required [sensitive] field")
+	}
+	if !fields[2].IsSensitive() || !fields[2].Optional {
+		t.Errorf("token: expected [sensitive] and [optional] in either order")
+	}
+	if fields[2].IsEncrypted() || !fields[3].IsEncrypted() || !fields[3].IsSensitive() {
+		t.Errorf("ssn: expected the only [encrypted] field, also [sensitive]")
+	}
+}
+
+func TestInvalidFieldAnnotations(t *testing.T) {
+	assertValidationError(t, `struct Login {
+  password string [readonly]
+}`, "unknown annotation [readonly] on field Login.password")
+	assertValidationError(t, `struct Login {
+  password string [sensitive] [sensitive]
+}`, "duplicate annotation [sensitive] on field Login.password")
+	assertValidationError(t, `struct Login {
+  password string [sensitive="yes"]
+}`, "annotation [sensitive] on field Login.password does not take a value")
+	assertValidationError(t, `struct Login {
+  password string [encrypted="kms"]
+}`, "annotation [encrypted] on field Login.password does not take a value")
+}
+
+func TestInvalidReadOnlyStructParameter(t *testing.T) {
+	input := `struct Filter {
+  name string
+}
+interface Catalog {
+  search(filter Filter) []string [readonly]
+}`
+	assertValidationError(t, input, "parameter filter of [readonly] method search")
+}
+
+func TestInvalidReadOnlyMapParameter(t *testing.T) {
+	input := `interface Catalog {
+  search(tags map[string]string) []string [readonly]
+}`
+	assertValidationError(t, input, "parameter tags of [readonly] method search")
+}
+
+func TestMethodScopesAndTimeout(t *testing.T) {
+	input := `namespace test
+interface Catalog {
+  save(name string) string [scopes="catalog:write, admin"] [timeout="2s"]
+}`
+	idl, err := ParseIDL("test.pulse", input)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+	method := idl.Interfaces[0].Methods[0]
+	if got := method.Scopes(); len(got) != 2 || got[0] != "catalog:write" || got[1] != "admin" {
+		t.Errorf("Expected scopes [catalog:write admin], got %v", got)
+	}
+	if a := method.Annotation(AnnotationTimeout); a == nil || a.Value != "2s" {
+		t.Errorf("Expected timeout annotation 2s, got %+v", a)
+	}
+}
+
+func TestInvalidEmptyScopes(t *testing.T) {
+	input := `interface Catalog {
+  save(name string) string [scopes=""]
+}`
+	assertValidationError(t, input, "annotation [scopes] on method save must list at least one scope")
+}
+
+func TestInvalidTimeout(t *testing.T) {
+	input := `interface Catalog {
+  save(name string) string [timeout="soon"]
+}`
+	assertValidationError(t, input, "annotation [timeout] on method save must be a positive duration")
+}
+
+func TestMethodAccepts(t *testing.T) {
+	input := `namespace test
+interface Orders {
+  submit(sku string, qty int, tags []string) bool [accepts="form, xml"]
+  cancel(id string) bool
+}`
+	idl, err := ParseIDL("test.pulse", input)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+	methods := idl.Interfaces[0].Methods
+	if got := methods[0].Accepts(); len(got) != 2 || got[0] != AcceptsForm || got[1] != AcceptsXML {
+		t.Errorf("Expected accepts [form xml], got %v", got)
+	}
+	if got := methods[1].Accepts(); got != nil {
+		t.Errorf("Expected no accepts on cancel, got %v", got)
+	}
+}
+
+func TestInvalidAccepts(t *testing.T) {
+	assertValidationError(t, `interface Orders {
+  submit(sku string) bool [accepts=""]
+}`, "annotation [accepts] on method submit must list")
+	assertValidationError(t, `interface Orders {
+  submit(sku string) bool [accepts="form,soap"]
+}`, "annotation [accepts] on method submit lists unknown encoding \"soap\"")
+	assertValidationError(t, `struct Line {
+  sku string
+}
+interface Orders {
+  submit(lines []Line) bool [accepts="xml"]
+}`, "parameter lines of [accepts] method submit")
+}
+
+func TestMethodCache(t *testing.T) {
+	input := `namespace test
+interface Catalog {
+  get(id string) string [readonly] [cache="90s"]
+  list() []string [readonly]
+}`
+	idl, err := ParseIDL("test.pulse", input)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+	methods := idl.Interfaces[0].Methods
+	if got, ok := methods[0].CacheMaxAge(); !ok || got != 90*time.Second {
+		t.Errorf("Expected cache max-age 90s, got %v, %v", got, ok)
+	}
+	if _, ok := methods[1].CacheMaxAge(); ok {
+		t.Errorf("Expected no cache max-age on list")
+	}
+}
+
+func TestInvalidCache(t *testing.T) {
+	assertValidationError(t, `interface Catalog {
+  get(id string) string [readonly] [cache="1.5s"]
+}`, "annotation [cache] on method get must be a duration in whole seconds")
+	assertValidationError(t, `interface Catalog {
+  get(id string) string [readonly] [cache="soon"]
+}`, "annotation [cache] on method get must be a duration in whole seconds")
+	assertValidationError(t, `interface Catalog {
+  save(id string) bool [cache="60s"]
+}`, "annotation [cache] on method save requires [readonly]")
+}
+
+func TestMethodCompress(t *testing.T) {
+	input := `namespace test
+interface Reports {
+  export(id string) string [compress]
+  summary(id string) string [compress="4096"]
+  ping() bool
+}`
+	idl, err := ParseIDL("test.pulse", input)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+	methods := idl.Interfaces[0].Methods
+	if got, ok := methods[0].CompressThreshold(); !ok || got != 0 {
+		t.Errorf("Expected threshold 0 on export, got %d, %v", got, ok)
+	}
+	if got, ok := methods[1].CompressThreshold(); !ok || got != 4096 {
+		t.Errorf("Expected threshold 4096 on summary, got %d, %v", got, ok)
+	}
+	if _, ok := methods[2].CompressThreshold(); ok {
+		t.Errorf("Expected no [compress] on ping")
+	}
+}
+
+func TestInvalidCompress(t *testing.T) {
+	assertValidationError(t, `interface Reports {
+  export(id string) string [compress="4kb"]
+}`, "annotation [compress] on method export must have no value or a size in bytes")
+	assertValidationError(t, `interface Reports {
+  export(id string) string [compress="-1"]
+}`, "annotation [compress] on method export must have no value or a size in bytes")
+}
+
+func TestMethodChunked(t *testing.T) {
+	input := `namespace test
+typedef Names []string
+interface Feed {
+  items(count int) []string [chunked]
+  names() Names [chunked]
+  ping() bool
+}`
+	idl, err := ParseIDL("test.pulse", input)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+	methods := idl.Interfaces[0].Methods
+	if !methods[0].IsChunked() || !methods[1].IsChunked() || methods[2].IsChunked() {
+		t.Errorf("Expected items and names to be [chunked] and ping not")
+	}
+
+	assertValidationError(t, `interface Feed {
+  count() int [chunked]
+}`, "annotation [chunked] on method count requires an array result")
+	assertValidationError(t, `interface Feed {
+  items() []string [optional] [chunked]
+}`, "method items cannot be [chunked] with an [optional] result")
+}
+
+func TestMethodErrorData(t *testing.T) {
+	input := `namespace test
+struct OutOfStock {
+  sku       string
+  available int
+}
+interface Orders {
+  place(sku string) string [errordata="OutOfStock"]
+  cancel(id string) bool
+}`
+	idl, err := ParseIDL("test.pulse", input)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+	methods := idl.Interfaces[0].Methods
+	if methods[0].ErrorData() != "OutOfStock" || methods[1].ErrorData() != "" {
+		t.Errorf("Expected only place to declare OutOfStock, got %q and %q", methods[0].ErrorData(), methods[1].ErrorData())
+	}
+}
+
+func TestImportedErrorData(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestFile(t, tmpDir, "orders.pulse", `namespace orders
+
+struct OutOfStock {
+    sku string
+}
+
+interface Orders {
+    place(sku string) string [errordata="OutOfStock"]
+}`)
+	mainFile := createTestFile(t, tmpDir, "main.pulse", `namespace shop
+
+import "orders.pulse"
+
+interface Shop {
+    buy(sku string) string [errordata="orders.OutOfStock"]
+}`)
+
+	idl, err := parseIDLFromFile(t, mainFile)
+	if err != nil {
+		t.Fatalf("Expected valid parse, got error: %v", err)
+	}
+	for _, iface := range idl.Interfaces {
+		if got := iface.Methods[0].ErrorData(); got != "orders.OutOfStock" {
+			t.Errorf("Expected %s to declare orders.OutOfStock, got %q", iface.Name, got)
+		}
+	}
+}
+
+func TestInvalidErrorData(t *testing.T) {
+	assertValidationError(t, `interface Orders {
+  place(sku string) string [errordata="OutOfStock"]
+}`, "annotation [errordata] on method place must name a struct")
+	assertValidationError(t, `enum Reason {
+  missing
+}
+interface Orders {
+  place(sku string) string [errordata="Reason"]
+}`, "annotation [errordata] on method place must name a struct")
+}
+
+func TestMethodAsync(t *testing.T) {
+	input := `namespace test
+interface Reports {
+  export(month string) string [async]
+  status(id string) string
+}`
+	idl, err := ParseIDL("test.pulse", input)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+	methods := idl.Interfaces[0].Methods
+	if !methods[0].IsAsync() || methods[1].IsAsync() {
+		t.Errorf("Expected only export to be async, got %v and %v", methods[0].IsAsync(), methods[1].IsAsync())
+	}
+}
+
+func TestInvalidAsyncReadOnly(t *testing.T) {
+	input := `interface Reports {
+  export(month string) string [async] [readonly]
+}`
+	assertValidationError(t, input, "method export cannot be both [async] and [readonly]")
+}
+
+func TestMethodIdempotent(t *testing.T) {
+	input := `namespace test
+interface Catalog {
+  get(id string) string [readonly]
+  put(id string) string [idempotent]
+  add(id string) string
+}`
+	idl, err := ParseIDL("test.pulse", input)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+	for i, want := range []bool{true, true, false} {
+		method := idl.Interfaces[0].Methods[i]
+		if method.IsIdempotent() != want {
+			t.Errorf("%s: expected IsIdempotent() = %v", method.Name, want)
+		}
+	}
+}
+
+func TestInterfaceExtends(t *testing.T) {
+	input := `namespace test
+interface Health {
+  ping() string
+}
+interface Named {
+  name() string
+}
+interface Catalog extends Health, Named {
+  get(id string) string
+}
+interface Store extends Catalog {
+  buy(id string) bool
+}`
+	idl, err := ParseIDL("test.pulse", input)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+	store := idl.Interfaces[3]
+	if len(store.Extends) != 1 || store.Extends[0] != "Catalog" {
+		t.Errorf("Expected Store to extend [Catalog], got %v", store.Extends)
+	}
+	want := []struct{ name, from string }{{"buy", ""}, {"get", "Catalog"}, {"ping", "Health"}, {"name", "Named"}}
+	if len(store.Methods) != len(want) {
+		t.Fatalf("Expected %d methods, got %d", len(want), len(store.Methods))
+	}
+	for i, w := range want {
+		if m := store.Methods[i]; m.Name != w.name || m.InheritedFrom != w.from {
+			t.Errorf("Method %d: expected %s from %q, got %s from %q", i, w.name, w.from, m.Name, m.InheritedFrom)
+		}
+	}
+	if own := store.OwnMethods(); len(own) != 1 || own[0].Name != "buy" {
+		t.Errorf("Expected own methods [buy], got %v", own)
+	}
+}
+
+func TestInvalidInterfaceExtends(t *testing.T) {
+	assertValidationError(t, `interface A extends Missing {
+  a() string
+}`, "interface A extends unknown interface Missing")
+
+	assertValidationError(t, `struct S {
+  a string
+}
+interface A extends S {
+  a() string
+}`, "interface A extends struct S, which is not an interface")
+
+	assertValidationError(t, `interface A extends B {
+  a() string
+}
+interface B extends A {
+  b() string
+}`, "circular interface inheritance: A -> B -> A")
+
+	assertValidationError(t, `interface A {
+  get() string
+}
+interface B extends A {
+  get() int
+}`, "interface B has conflicting definitions of method get from B and A")
+}
+
+func TestTypedef(t *testing.T) {
+	input := `namespace test
+// Tag names
+typedef StringList []string
+typedef Headers map[string]string
+typedef HeaderSets []Headers
+struct Request {
+  tags StringList
+  headers Headers [optional]
+}
+interface Api {
+  send(sets HeaderSets) StringList
+}`
+	idl, err := ParseIDL("test.pulse", input)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+	if len(idl.Typedefs) != 3 || idl.Typedefs[0].Comment != "Tag names" {
+		t.Fatalf("Expected 3 typedefs with a comment on the first, got %+v", idl.Typedefs)
+	}
+
+	tags := idl.Structs[0].Fields[0].Type
+	if tags.Alias != "StringList" || !tags.IsArray() || tags.Array.BuiltIn != "string" {
+		t.Errorf("Expected tags to be []string aliased as StringList, got %+v", tags)
+	}
+	sets := idl.Interfaces[0].Methods[0].Parameters[0].Type
+	if sets.Alias != "HeaderSets" || sets.String() != "[]map[string]string" || sets.Array.Alias != "Headers" {
+		t.Errorf("Expected sets to be []Headers aliased as HeaderSets, got %s (alias %q)", sets.String(), sets.Alias)
+	}
+}
+
+func TestInvalidTypedef(t *testing.T) {
+	assertValidationError(t, `typedef Name string`, "typedef Name must be an array or map type")
+	assertValidationError(t, `typedef Names []string
+typedef MoreNames Names`, "typedef MoreNames must be an array or map type")
+	assertValidationError(t, `typedef Tree map[string]Tree`, "circular typedef: Tree refers to itself")
+	assertValidationError(t, `typedef Items []Item`, "unknown type: Item")
+	assertValidationError(t, `typedef Items []string
+struct Items {
+  a string
+}`, "duplicate type name: Items")
+}
+
+func TestOptionalParameters(t *testing.T) {
+	input := `namespace test
+enum Order {
+  asc
+  desc
+}
+interface Search {
+  find(query string, limit int [default="10"], order Order [default="desc"], cursor string [optional]) []string
+}`
+	idl, err := ParseIDL("test.pulse", input)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+
+	method := idl.Interfaces[0].Methods[0]
+	if got := method.RequiredParams(); got != 1 {
+		t.Errorf("Expected 1 required parameter, got %d", got)
+	}
+	limit := method.Parameters[1]
+	if value, ok := limit.Default(); !limit.Optional || !ok || value != "10" {
+		t.Errorf("Expected limit to be optional with default 10, got optional=%v default=%q", limit.Optional, value)
+	}
+	cursor := method.Parameters[3]
+	if _, ok := cursor.Default(); !cursor.Optional || ok {
+		t.Errorf("Expected cursor to be optional without a default, got %+v", cursor)
+	}
+}
+
+func TestInvalidOptionalParameters(t *testing.T) {
+	assertValidationError(t, `interface Api {
+  find(limit int [optional], query string) string
+}`, "parameter query of method find must be [optional]: it follows optional parameter limit")
+	assertValidationError(t, `interface Api {
+  find(limit int [default="ten"]) string
+}`, `default value "ten" of parameter limit of method find is not a valid int`)
+	assertValidationError(t, `enum Order {
+  asc
+}
+interface Api {
+  find(order Order [default="up"]) string
+}`, `default value "up" of parameter order of method find is not a value of enum Order`)
+	assertValidationError(t, `interface Api {
+  find(tags []string [default="a"]) string
+}`, "is not allowed: only built-in types and enums can have a default")
+	assertValidationError(t, `interface Api {
+  find(limit int [sensitive]) string
+}`, "unknown annotation [sensitive] on parameter limit of method find")
+}
+
+func TestMethodExamples(t *testing.T) {
+	input := `namespace test
+enum Order {
+  asc
+  desc
+}
+struct Page {
+  items []string
+  next  string [optional]
+}
+interface Search {
+  // Finds matching items
+  @example(params=["books", 2], result={"items": ["a", "b"], "next": "c2"})
+  @example(params={"query": "none", "order": "desc"}, result={"items": []})
+  find(query string, limit int [default="10"], order Order [optional]) Page
+  @example(params=[], result=null)
+  last() string [optional]
+}`
+	idl, err := ParseIDL("test.pulse", input)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+
+	find := idl.Interfaces[0].Methods[0]
+	if find.Pos.Line != 14 {
+		t.Errorf("Expected find at line 14, got %d", find.Pos.Line)
+	}
+	if len(find.Examples) != 2 {
+		t.Fatalf("Expected 2 examples, got %d", len(find.Examples))
+	}
+	if got := string(find.Examples[0].Params); got != `["books",2]` {
+		t.Errorf("Unexpected params %s", got)
+	}
+	if got := string(find.Examples[0].Result); got != `{"items":["a","b"],"next":"c2"}` {
+		t.Errorf("Unexpected result %s", got)
+	}
+	// Params by name are sent by position, with null for those left out
+	var params []string
+	for _, p := range find.Examples[1].ParamList(find) {
+		params = append(params, string(p))
+	}
+	if got := strings.Join(params, ","); got != `"none",null,"desc"` {
+		t.Errorf("Unexpected param list %s", got)
+	}
+	if got := string(idl.Interfaces[0].Methods[1].Examples[0].Result); got != "null" {
+		t.Errorf("Unexpected result %s", got)
+	}
+}
+
+func TestInvalidMethodExamples(t *testing.T) {
+	assertValidationError(t, `interface Api {
+  @example(params=[1.5], result=1)
+  add(a int) int
+}`, "@example of method add: parameter a is not a valid int: 1.5")
+	assertValidationError(t, `interface Api {
+  @example(params=[1, 2], result=1)
+  add(a int) int
+}`, "@example of method add: params has 2 values but the method takes 1")
+	assertValidationError(t, `interface Api {
+  @example(params={"b": 1}, result=1)
+  add(a int) int
+}`, "@example of method add: params is missing required parameter a")
+	assertValidationError(t, `interface Api {
+  @example(params=[1], result="one")
+  add(a int) int
+}`, "@example of method add: result is not a valid int: \"one\"")
+	assertValidationError(t, `interface Api {
+  @example(params=[1], result=null)
+  add(a int) int
+}`, "@example of method add: result is null but the return type is not [optional]")
+	assertValidationError(t, `struct Point {
+  x int
+}
+interface Api {
+  @example(params=[], result={"x": 1, "y": 2})
+  origin() Point
+}`, "@example of method origin: result has unknown field y of Point")
+	assertValidationError(t, `struct Point {
+  x int
+}
+interface Api {
+  @example(params=[], result=[{"x": "1"}])
+  origins() []Point
+}`, "@example of method origins: result [0] .x is not a valid int: \"1\"")
+
+	for input, want := range map[string]string{
+		`@example(params=[1])`:                        "@example is missing result",
+		`@example(params=[1], result=1, result=2)`:    "duplicate @example argument result",
+		`@example(params=[1], result=1, error=2)`:     "unknown @example argument error",
+		`@example(params={"a": 1, "a": 2}, result=1)`: `duplicate key "a"`,
+	} {
+		_, err := ParseIDL("test.pulse", "namespace test\ninterface Api {\n  "+input+"\n  add(a int) int\n}")
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error containing %q for %s, got %v", want, input, err)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	input := `namespace test
+struct Big [nolint="max-fields"] {
+  a string
+  b string
+}
+struct Wide extends Big {
+  c string
+  d string
+}
+interface Base {
+  ping() bool
+}
+interface Orders extends Base {
+  place(a string, b string, c string) string
+  cancel(a string, b string, c string) bool [nolint="max-params"]
+}
+interface Legacy [nolint="max-methods,max-params"] {
+  one(a string, b string, c string) bool
+  two() bool
+}`
+	idl, err := ParseIDL("test.pulse", input)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if err := ValidateIDL(idl); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+
+	var got []string
+	for _, issue := range Lint(idl, LintConfig{MaxMethods: 1, MaxFields: 1, MaxParams: 2}) {
+		got = append(got, issue.String())
+	}
+	// Inherited methods and fields do not count
+	want := []string{
+		"6:8: struct Wide declares 2 fields, more than the budget of 1 (max-fields)",
+		"13:11: interface Orders declares 2 methods, more than the budget of 1 (max-methods)",
+		"14:3: method Orders.place takes 3 parameters, more than the budget of 2 (max-params)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected lint issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if issues := Lint(idl, LintConfig{}); len(issues) != 0 {
+		t.Errorf("Expected no issues without budgets, got %v", issues)
+	}
+}
+
+func TestParseLintConfig(t *testing.T) {
+	cfg, err := ParseLintConfig("max-methods=20, max-fields=30,max-params=5")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg != (LintConfig{MaxMethods: 20, MaxFields: 30, MaxParams: 5}) {
+		t.Errorf("Unexpected config %+v", cfg)
+	}
+	for spec, want := range map[string]string{
+		"max-methods":    "needs a budget",
+		"max-params=few": "budget must be a whole number",
+		"max-enums=3":    `unknown lint rule "max-enums"`,
+	} {
+		if _, err := ParseLintConfig(spec); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error containing %q for %s, got %v", want, spec, err)
+		}
+	}
+}
+
+func TestInvalidNoLint(t *testing.T) {
+	assertValidationError(t, `struct Point [nolint="max-params"] {
+  x int
+}`, `annotation [nolint] on struct Point names rule "max-params", which does not apply to a struct`)
+	assertValidationError(t, `struct Point [readonly] {
+  x int
+}`, "unknown annotation [readonly] on struct Point")
+	assertValidationError(t, `interface Api {
+  add(a int) int [nolint="max-methods"]
+}`, `annotation [nolint] on method add names rule "max-methods", which does not apply to a method`)
+	assertValidationError(t, `interface Api [nolint=""] {
+  add(a int) int
+}`, "annotation [nolint] on interface Api needs the rules to turn off")
+}
+
+func TestImmutableStruct(t *testing.T) {
+	idl, err := ParseIDL("test.pulse", `namespace test
+struct Money [immutable] {
+  amount int
+}
+struct Price extends Money [immutable] {
+  currency string
+}
+struct Cart {
+  total Price
+}`)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if !idl.Structs[0].IsImmutable() || !idl.Structs[1].IsImmutable() || idl.Structs[2].IsImmutable() {
+		t.Errorf("Unexpected [immutable] structs: %v, %v, %v", idl.Structs[0].IsImmutable(), idl.Structs[1].IsImmutable(), idl.Structs[2].IsImmutable())
+	}
+
+	assertValidationError(t, `struct Money [immutable] {
+  amount int
+}
+struct Price extends Money {
+  currency string
+}`, "struct Price extends [immutable] struct Money and must be [immutable] too")
+	assertValidationError(t, `struct Money {
+  amount int
+}
+struct Price extends Money [immutable] {
+  currency string
+}`, "struct Price is [immutable] but extends Money, which is not")
+}
+
+func TestDiff(t *testing.T) {
+	oldInput := `namespace test
+struct Base {
+  id string
+}
+struct User extends Base {
+  name string
+  email string
+  age int [optional]
+  nick string [optional]
+}
+struct Gone {
+  x string
+}
+enum Role {
+  admin
+  guest
+}
+interface Users {
+  get(id string, full bool) User
+  find(name string) []User
+  drop(id string) bool
+  rename(id string, name string) User [wire="v1.users.rename"]
+}`
+	newInput := `namespace test
+struct Base {
+  id int
+}
+struct User extends Base {
+  name string
+  age int
+  nick string [optional]
+  phone string
+  note string [optional]
+}
+enum Role {
+  admin
+  member
+}
+enum Plan {
+  free
+}
+interface Users {
+  get(id string, full bool, cached bool [optional]) User [optional]
+  find(query string) []User
+  setName(id string, name string) User [wire="v1.users.rename"]
+  count() int
+}`
+	oldIDL, err := ParseIDL("old.pulse", oldInput)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	newIDL, err := ParseIDL("new.pulse", newInput)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+
+	var got []string
+	for _, change := range Diff(oldIDL, newIDL) {
+		kind := "+"
+		if change.Breaking {
+			kind = "!"
+		}
+		got = append(got, kind+" "+change.Msg)
+	}
+	// Fields of Base count as fields of User, and a method keeping its [wire]
+	// name is unchanged
+	want := []string{
+		"! field Base.id changed type from string to int",
+		"! field User.id changed type from string to int",
+		"! field User.email was removed",
+		"! field User.age is now required",
+		"! required field User.phone was added",
+		"+ optional field User.note was added",
+		"! struct Gone was removed",
+		"! value guest of enum Role was removed",
+		"+ value member was added to enum Role",
+		"+ enum Plan was added",
+		"+ optional parameter cached was added to method Users.get",
+		"! method Users.get may now return null",
+		"! parameter 1 of method Users.find was renamed from name to query",
+		"! method Users.drop was removed",
+		"+ method Users.count was added",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected changes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !HasBreakingChanges(Diff(oldIDL, newIDL)) {
+		t.Error("Expected breaking changes")
+	}
+	if changes := Diff(oldIDL, oldIDL); len(changes) != 0 {
+		t.Errorf("Expected no changes between identical IDLs, got %v", changes)
+	}
+}
+
+func TestDiffExperimental(t *testing.T) {
+	oldInput := `namespace test
+interface Lab [stability="experimental"] {
+  try(x string) string
+}
+interface Users [stability="stable"] {
+  get(id string) string
+  preview(id string) string [stability="experimental"]
+  label(id string) string
+}`
+	newInput := `namespace test
+interface Users [stability="stable"] {
+  get(id int) string
+  preview(key string) string [stability="experimental"]
+  label(id string, lang string) string [stability="experimental"]
+}`
+	oldIDL, err := ParseIDL("old.pulse", oldInput)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	newIDL, err := ParseIDL("new.pulse", newInput)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+
+	var got []string
+	for _, change := range Diff(oldIDL, newIDL) {
+		got = append(got, strings.SplitN(change.String(), ": ", 2)[1])
+	}
+	// Stability is taken from the old IDL, so marking a stable method experimental
+	// while changing it is still breaking
+	want := []string{
+		"warning: method Lab.try was removed (experimental)",
+		"breaking: parameter id of method Users.get changed type from string to int",
+		"warning: parameter 1 of method Users.preview was renamed from id to key (experimental)",
+		"breaking: required parameter lang was added to method Users.label",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected changes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !HasBreakingChanges(Diff(oldIDL, newIDL)) {
+		t.Error("Expected breaking changes to stable methods")
+	}
+
+	// Changes to experimental methods alone do not fail the diff
+	experimentalOnly, err := ParseIDL("new.pulse", `namespace test
+interface Users [stability="stable"] {
+  get(id string) string
+  preview(key string) string [stability="experimental"]
+  label(id string) string
+}`)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	changes := Diff(oldIDL, experimentalOnly)
+	if len(changes) != 2 {
+		t.Errorf("Expected 2 warnings, got %v", changes)
+	}
+	for _, c := range changes {
+		if !c.Warning || c.Breaking {
+			t.Errorf("Expected a warning, got %v", c)
+		}
+	}
+	if HasBreakingChanges(changes) {
+		t.Error("Expected no breaking changes when only experimental methods change")
+	}
+}
+
+func TestDuplicateDeclarations(t *testing.T) {
+	assertValidationError(t, `struct User {
+  name string
+  name int
+}`, "duplicate field name in struct User (previously declared at 4:3)")
+	assertValidationError(t, `struct Base {
+  id string
+}
+struct User extends Base {
+  id int
+}`, "field id of struct User is already declared by Base, which it extends")
+	assertValidationError(t, `struct Base {
+  id string
+}
+struct Mid extends Base {
+  name string
+}
+struct User extends Mid {
+  id string
+}`, "field id of struct User is already declared by Base, which it extends")
+	assertValidationError(t, `enum Role {
+  admin
+}
+struct User extends Role {
+  name string
+}`, "struct User extends enum Role, which is not a struct")
+	assertValidationError(t, `interface Users {
+  get(id string) string
+  get(id int) string
+}`, "duplicate method get in interface Users (previously declared at 4:3)")
+	assertValidationError(t, `interface Users {
+  rename(id string, id string) bool
+}`, "duplicate parameter id of method rename")
+	assertValidationError(t, `enum Role {
+  admin
+  guest
+  admin
+}`, "duplicate value admin in enum Role")
+}
+
+func TestValidationErrorFile(t *testing.T) {
+	idl, err := ParseIDL("users.pulse", "namespace test\n\nstruct User {\n  manager Person\n}\n")
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	err = ValidateIDL(idl)
+	if err == nil || err.Error() != "users.pulse:4:11: unknown type: Person" {
+		t.Errorf("Expected the error to name the file and position, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// ResolveTypedefs replaces every reference to a typedef with a copy of the
+// typedef's type, with Alias set to the typedef's name, so code that does not
+// know about typedefs sees plain array and map types. Typedefs that refer to
+// other typedefs are resolved the same way. Resolved types no longer refer to the
+// typedef, so calling it again is safe. Circular typedefs are left unresolved here
+// and reported by ValidateIDL.
+func ResolveTypedefs(idl *IDL) {
+	if len(idl.Typedefs) == 0 {
+		return
+	}
+	byName := make(map[string]*Typedef, len(idl.Typedefs))
+	for _, td := range idl.Typedefs {
+		byName[td.Name] = td
+	}
+
+	// resolving holds the typedefs being expanded, which ends the walk on cycles
+	resolving := make(map[string]bool)
+	var resolve func(t *Type)
+	resolve = func(t *Type) {
+		if t == nil {
+			return
+		}
+		if td := byName[t.UserDefined]; td != nil {
+			if resolving[td.Name] {
+				return
+			}
+			resolving[td.Name] = true
+			resolve(td.Type)
+			delete(resolving, td.Name)
+			pos := t.Pos
+			*t = *copyType(td.Type)
+			t.Pos = pos
+			t.Alias = td.Name
+			return
+		}
+		resolve(t.Array)
+		resolve(t.MapValue)
+	}
+
+	for _, td := range idl.Typedefs {
+		resolving[td.Name] = true
+		resolve(td.Type)
+		delete(resolving, td.Name)
+	}
+	for _, iface := range idl.Interfaces {
+		for _, m := range iface.Methods {
+			resolve(m.ReturnType)
+			for _, p := range m.Parameters {
+				resolve(p.Type)
+			}
+		}
+	}
+	for _, s := range idl.Structs {
+		for _, f := range s.Fields {
+			resolve(f.Type)
+		}
+	}
+}
+
+// copyType returns a deep copy of t
+func copyType(t *Type) *Type {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	c.Array = copyType(t.Array)
+	c.MapValue = copyType(t.MapValue)
+	return &c
+}
+
+// validateTypedefs reports typedefs of anything but an array or map type, and
+// typedefs that refer to themselves
+func validateTypedefs(idl *IDL, typeRegistry map[string]lexer.Position, errors *ValidationErrors) {
+	byName := make(map[string]bool, len(idl.Typedefs))
+	for _, td := range idl.Typedefs {
+		byName[td.Name] = true
+	}
+
+	// refersToTypedef reports whether t still names a typedef, which after
+	// resolution only happens for circular typedefs
+	var refersToTypedef func(t *Type) bool
+	refersToTypedef = func(t *Type) bool {
+		if t == nil {
+			return false
+		}
+		return byName[t.UserDefined] || refersToTypedef(t.Array) || refersToTypedef(t.MapValue)
+	}
+
+	for _, td := range idl.Typedefs {
+		if td.Type == nil || td.Type.Alias != "" || (!td.Type.IsArray() && !td.Type.IsMap()) {
+			errors.Add(&ValidationError{
+				File:   td.Pos.Filename,
+				Line:   td.Pos.Line,
+				Column: td.Pos.Column,
+				Msg:    fmt.Sprintf("typedef %s must be an array or map type", td.Name),
+			})
+			continue
+		}
+		if refersToTypedef(td.Type) {
+			errors.Add(&ValidationError{
+				File:   td.Pos.Filename,
+				Line:   td.Pos.Line,
+				Column: td.Pos.Column,
+				Msg:    fmt.Sprintf("circular typedef: %s refers to itself", td.Name),
+			})
+			continue
+		}
+		validateType(td.Type, typeRegistry, errors)
+	}
+}
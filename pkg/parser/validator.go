@@ -3,7 +3,9 @@ package parser
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/alecthomas/participle/v2/lexer"
 )
@@ -17,6 +19,52 @@ var (
 	}
 
 	identifierRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+	// wireNameRegex matches the JSON-RPC names [wire] may set
+	wireNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_.:/-]+$`)
+
+	// methodAnnotations lists the annotations allowed on interface methods
+	methodAnnotations = map[string]bool{
+		AnnotationReadOnly:   true,
+		AnnotationIdempotent: true,
+		AnnotationScopes:     true,
+		AnnotationTimeout:    true,
+		AnnotationAsync:      true,
+		AnnotationWire:       true,
+		AnnotationOwner:      true,
+		AnnotationStability:  true,
+		AnnotationAccepts:    true,
+		AnnotationCache:      true,
+		AnnotationErrorData:  true,
+		AnnotationCompress:   true,
+		AnnotationChunked:    true,
+		AnnotationNoLint:     true,
+	}
+
+	// interfaceAnnotations lists the annotations allowed on interfaces
+	interfaceAnnotations = map[string]bool{
+		AnnotationWire:      true,
+		AnnotationOwner:     true,
+		AnnotationStability: true,
+		AnnotationNoLint:    true,
+	}
+
+	// structAnnotations lists the annotations allowed on structs
+	structAnnotations = map[string]bool{
+		AnnotationImmutable: true,
+		AnnotationNoLint:    true,
+	}
+
+	// fieldAnnotations lists the annotations allowed on struct fields
+	fieldAnnotations = map[string]bool{
+		AnnotationSensitive: true,
+		AnnotationEncrypted: true,
+	}
+
+	// parameterAnnotations lists the annotations allowed on method parameters
+	parameterAnnotations = map[string]bool{
+		AnnotationDefault: true,
+	}
 )
 
 // ValidateIDL validates the parsed IDL and returns any validation errors
@@ -25,7 +73,7 @@ func ValidateIDL(idl *IDL) error {
 
 	// Validate that the root file has a namespace declaration
 	// Exception: empty files (no types defined) are allowed without a namespace
-	isEmpty := len(idl.Interfaces) == 0 && len(idl.Structs) == 0 && len(idl.Enums) == 0
+	isEmpty := len(idl.Interfaces) == 0 && len(idl.Structs) == 0 && len(idl.Enums) == 0 && len(idl.Typedefs) == 0
 	if idl.RootNamespace == "" && !isEmpty {
 		errors.Add(&ValidationError{
 			Line:   0,
@@ -40,17 +88,18 @@ func ValidateIDL(idl *IDL) error {
 
 	// Build type registry and track positions for duplicate detection
 	typeRegistry := make(map[string]lexer.Position)
-	typeNames := make(map[string]string) // type name -> "interface", "struct", or "enum"
+	typeNames := make(map[string]string) // type name -> "interface", "struct", "enum" or "typedef"
 
 	// First pass: register all types and check for duplicates
 	// For qualified names (namespace.Type), validate the base name part
 	for _, iface := range idl.Interfaces {
 		baseName := getBaseName(iface.Name)
-		if !validateIdentifierName(baseName, errors, iface.Pos.Line, iface.Pos.Column) {
+		if !validateIdentifierName(baseName, errors, iface.Pos) {
 			continue
 		}
 		if existingPos, exists := typeRegistry[iface.Name]; exists {
 			errors.Add(&ValidationError{
+				File:   iface.Pos.Filename,
 				Line:   iface.Pos.Line,
 				Column: iface.Pos.Column,
 				Msg:    fmt.Sprintf("duplicate type name: %s (previously defined as %s at %d:%d)", iface.Name, typeNames[iface.Name], existingPos.Line, existingPos.Column),
@@ -64,11 +113,12 @@ func ValidateIDL(idl *IDL) error {
 	// Register all structs
 	for _, s := range idl.Structs {
 		baseName := getBaseName(s.Name)
-		if !validateIdentifierName(baseName, errors, s.Pos.Line, s.Pos.Column) {
+		if !validateIdentifierName(baseName, errors, s.Pos) {
 			continue
 		}
 		if existingPos, exists := typeRegistry[s.Name]; exists {
 			errors.Add(&ValidationError{
+				File:   s.Pos.Filename,
 				Line:   s.Pos.Line,
 				Column: s.Pos.Column,
 				Msg:    fmt.Sprintf("duplicate type name: %s (previously defined as %s at %d:%d)", s.Name, typeNames[s.Name], existingPos.Line, existingPos.Column),
@@ -82,11 +132,12 @@ func ValidateIDL(idl *IDL) error {
 	// Register all enums
 	for _, enum := range idl.Enums {
 		baseName := getBaseName(enum.Name)
-		if !validateIdentifierName(baseName, errors, enum.Pos.Line, enum.Pos.Column) {
+		if !validateIdentifierName(baseName, errors, enum.Pos) {
 			continue
 		}
 		if existingPos, exists := typeRegistry[enum.Name]; exists {
 			errors.Add(&ValidationError{
+				File:   enum.Pos.Filename,
 				Line:   enum.Pos.Line,
 				Column: enum.Pos.Column,
 				Msg:    fmt.Sprintf("duplicate type name: %s (previously defined as %s at %d:%d)", enum.Name, typeNames[enum.Name], existingPos.Line, existingPos.Column),
@@ -97,39 +148,78 @@ func ValidateIDL(idl *IDL) error {
 		}
 	}
 
+	// Register all typedefs
+	for _, td := range idl.Typedefs {
+		baseName := getBaseName(td.Name)
+		if !validateIdentifierName(baseName, errors, td.Pos) {
+			continue
+		}
+		if existingPos, exists := typeRegistry[td.Name]; exists {
+			errors.Add(&ValidationError{
+				File:   td.Pos.Filename,
+				Line:   td.Pos.Line,
+				Column: td.Pos.Column,
+				Msg:    fmt.Sprintf("duplicate type name: %s (previously defined as %s at %d:%d)", td.Name, typeNames[td.Name], existingPos.Line, existingPos.Column),
+			})
+		} else {
+			typeRegistry[td.Name] = td.Pos
+			typeNames[td.Name] = "typedef"
+		}
+	}
+
 	// Second pass: validate everything now that all types are registered
+	enums := make(map[string]*Enum, len(idl.Enums))
+	for _, e := range idl.Enums {
+		enums[e.Name] = e
+	}
+	examples := newExampleChecker(idl)
 	for _, iface := range idl.Interfaces {
-		// Validate method names and types
-		for _, method := range iface.Methods {
-			if !validateIdentifierName(method.Name, errors, method.Pos.Line, method.Pos.Column) {
+		// Validate method names and types. Inherited methods are validated on the
+		// interface that declares them.
+		for _, method := range iface.OwnMethods() {
+			if !validateIdentifierName(method.Name, errors, method.Pos) {
 				continue
 			}
 			validateType(method.ReturnType, typeRegistry, errors)
 			for _, param := range method.Parameters {
-				if !validateIdentifierName(param.Name, errors, param.Pos.Line, param.Pos.Column) {
+				if !validateIdentifierName(param.Name, errors, param.Pos) {
 					continue
 				}
 				validateType(param.Type, typeRegistry, errors)
 			}
+			validateParameters(method, enums, errors)
+			validateMethodAnnotations(method, typeNames, errors)
+			validateExamples(examples, method, errors)
 		}
 	}
 
 	for _, s := range idl.Structs {
-		if s.Extends != "" {
-			_, exists := typeRegistry[s.Extends]
-			if !exists && !builtInTypes[s.Extends] {
-				errors.Add(&ValidationError{
-					Line:   s.Pos.Line,
-					Column: s.Pos.Column,
-					Msg:    fmt.Sprintf("struct %s extends unknown type %s", s.Name, s.Extends),
-				})
+		if kind := typeNames[s.Extends]; s.Extends != "" && kind != "struct" {
+			msg := fmt.Sprintf("struct %s extends unknown type %s", s.Name, s.Extends)
+			if kind != "" {
+				msg = fmt.Sprintf("struct %s extends %s %s, which is not a struct", s.Name, kind, s.Extends)
 			}
+			errors.Add(&ValidationError{
+				File:   s.Pos.Filename,
+				Line:   s.Pos.Line,
+				Column: s.Pos.Column,
+				Msg:    msg,
+			})
 		}
+		validateStructAnnotations(s, errors)
 		for _, field := range s.Fields {
 			validateType(field.Type, typeRegistry, errors)
+			validateFieldAnnotations(s, field, errors)
 		}
 	}
 
+	validateStructFields(idl, errors)
+	validateUniqueNames(idl, errors)
+	validateImmutableStructs(idl, errors)
+	validateInterfaceInheritance(idl, typeNames, errors)
+	validateWireNames(idl, errors)
+	validateTypedefs(idl, typeRegistry, errors)
+
 	// Third pass: cycle detection
 	detectCycles(idl, errors)
 
@@ -151,14 +241,12 @@ func validateType(t *Type, typeRegistry map[string]lexer.Position, errors *Valid
 		return
 	}
 
-	line := t.Pos.Line
-	column := t.Pos.Column
-
 	if t.IsBuiltIn() {
 		if !builtInTypes[t.BuiltIn] {
 			errors.Add(&ValidationError{
-				Line:   line,
-				Column: column,
+				File:   t.Pos.Filename,
+				Line:   t.Pos.Line,
+				Column: t.Pos.Column,
 				Msg:    fmt.Sprintf("unknown built-in type: %s", t.BuiltIn),
 			})
 		}
@@ -180,8 +268,9 @@ func validateType(t *Type, typeRegistry map[string]lexer.Position, errors *Valid
 		typeName := t.UserDefined
 		if _, exists := typeRegistry[typeName]; !exists && !builtInTypes[typeName] {
 			errors.Add(&ValidationError{
-				Line:   line,
-				Column: column,
+				File:   t.Pos.Filename,
+				Line:   t.Pos.Line,
+				Column: t.Pos.Column,
 				Msg:    fmt.Sprintf("unknown type: %s", typeName),
 			})
 		}
@@ -189,18 +278,574 @@ func validateType(t *Type, typeRegistry map[string]lexer.Position, errors *Valid
 	}
 
 	errors.Add(&ValidationError{
-		Line:   line,
-		Column: column,
+		File:   t.Pos.Filename,
+		Line:   t.Pos.Line,
+		Column: t.Pos.Column,
 		Msg:    "invalid type expression",
 	})
 }
 
+// validateStructAnnotations validates the annotations on a struct
+func validateStructAnnotations(s *Struct, errors *ValidationErrors) {
+	seen := make(map[string]bool)
+	for _, a := range s.Annotations {
+		switch {
+		case !structAnnotations[a.Name]:
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("unknown annotation [%s] on struct %s", a.Name, s.Name),
+			})
+			continue
+		case seen[a.Name]:
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("duplicate annotation [%s] on struct %s", a.Name, s.Name),
+			})
+		}
+		seen[a.Name] = true
+		if a.Name == AnnotationNoLint {
+			validateNoLint(a, "struct", s.Name, errors)
+		}
+	}
+}
+
+// validateStructFields reports fields a struct declares twice, and fields that
+// repeat one of a struct it extends, which would appear twice on the wire
+func validateStructFields(idl *IDL, errors *ValidationErrors) {
+	structs := make(map[string]*Struct, len(idl.Structs))
+	for _, s := range idl.Structs {
+		structs[s.Name] = s
+	}
+	for _, s := range idl.Structs {
+		own := make(map[string]*Field, len(s.Fields))
+		for _, field := range s.Fields {
+			if prev, exists := own[field.Name]; exists {
+				errors.Add(&ValidationError{
+					File:   field.Pos.Filename,
+					Line:   field.Pos.Line,
+					Column: field.Pos.Column,
+					Msg:    fmt.Sprintf("duplicate field %s in struct %s (previously declared at %d:%d)", field.Name, s.Name, prev.Pos.Line, prev.Pos.Column),
+				})
+				continue
+			}
+			own[field.Name] = field
+		}
+
+		// Walk the extends chain; seen ends it on cycles, which detectCycles reports
+		seen := map[string]bool{s.Name: true}
+		for parent := ExtendedStruct(s, structs); parent != nil && !seen[parent.Name]; parent = ExtendedStruct(parent, structs) {
+			seen[parent.Name] = true
+			for _, inherited := range parent.Fields {
+				if field, exists := own[inherited.Name]; exists {
+					errors.Add(&ValidationError{
+						File:   field.Pos.Filename,
+						Line:   field.Pos.Line,
+						Column: field.Pos.Column,
+						Msg:    fmt.Sprintf("field %s of struct %s is already declared by %s, which it extends", field.Name, s.Name, parent.Name),
+					})
+				}
+			}
+		}
+	}
+}
+
+// validateUniqueNames reports methods an interface declares twice, parameters a
+// method declares twice and values an enum declares twice
+func validateUniqueNames(idl *IDL, errors *ValidationErrors) {
+	for _, iface := range idl.Interfaces {
+		methods := make(map[string]*Method)
+		for _, method := range iface.OwnMethods() {
+			if prev, exists := methods[method.Name]; exists {
+				errors.Add(&ValidationError{
+					File:   method.Pos.Filename,
+					Line:   method.Pos.Line,
+					Column: method.Pos.Column,
+					Msg:    fmt.Sprintf("duplicate method %s in interface %s (previously declared at %d:%d)", method.Name, iface.Name, prev.Pos.Line, prev.Pos.Column),
+				})
+			} else {
+				methods[method.Name] = method
+			}
+			params := make(map[string]bool, len(method.Parameters))
+			for _, param := range method.Parameters {
+				if params[param.Name] {
+					errors.Add(&ValidationError{
+						File:   param.Pos.Filename,
+						Line:   param.Pos.Line,
+						Column: param.Pos.Column,
+						Msg:    fmt.Sprintf("duplicate parameter %s of method %s", param.Name, method.Name),
+					})
+				}
+				params[param.Name] = true
+			}
+		}
+	}
+	for _, enum := range idl.Enums {
+		values := make(map[string]bool, len(enum.Values))
+		for _, v := range enum.Values {
+			if values[v.Name] {
+				errors.Add(&ValidationError{
+					File:   enum.Pos.Filename,
+					Line:   enum.Pos.Line,
+					Column: enum.Pos.Column,
+					Msg:    fmt.Sprintf("duplicate value %s in enum %s", v.Name, enum.Name),
+				})
+			}
+			values[v.Name] = true
+		}
+	}
+}
+
+// validateImmutableStructs checks that a struct and the struct it extends agree
+// on [immutable], as generated code cannot make the fields of a mutable parent
+// read-only, nor let a subclass set those of an immutable one
+func validateImmutableStructs(idl *IDL, errors *ValidationErrors) {
+	structs := make(map[string]*Struct, len(idl.Structs))
+	for _, s := range idl.Structs {
+		structs[s.Name] = s
+	}
+	for _, s := range idl.Structs {
+		parent := ExtendedStruct(s, structs)
+		if parent == nil || parent.IsImmutable() == s.IsImmutable() {
+			continue
+		}
+		msg := fmt.Sprintf("struct %s is [immutable] but extends %s, which is not", s.Name, parent.Name)
+		if parent.IsImmutable() {
+			msg = fmt.Sprintf("struct %s extends [immutable] struct %s and must be [immutable] too", s.Name, parent.Name)
+		}
+		errors.Add(&ValidationError{
+			File:   s.Pos.Filename,
+			Line:   s.Pos.Line,
+			Column: s.Pos.Column,
+			Msg:    msg,
+		})
+	}
+}
+
+// validateFieldAnnotations validates the annotation names on a struct field
+func validateFieldAnnotations(s *Struct, field *Field, errors *ValidationErrors) {
+	seen := make(map[string]bool)
+	for _, a := range field.Annotations {
+		switch {
+		case !fieldAnnotations[a.Name]:
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("unknown annotation [%s] on field %s.%s", a.Name, s.Name, field.Name),
+			})
+		case seen[a.Name]:
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("duplicate annotation [%s] on field %s.%s", a.Name, s.Name, field.Name),
+			})
+		case a.Value != "":
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("annotation [%s] on field %s.%s does not take a value", a.Name, s.Name, field.Name),
+			})
+		}
+		seen[a.Name] = true
+	}
+}
+
+// validateParameters checks that optional parameters come last and that their
+// annotations and default values are valid
+func validateParameters(method *Method, enums map[string]*Enum, errors *ValidationErrors) {
+	firstOptional := ""
+	for _, param := range method.Parameters {
+		if param.Optional {
+			if firstOptional == "" {
+				firstOptional = param.Name
+			}
+		} else if firstOptional != "" {
+			errors.Add(&ValidationError{
+				File:   param.Pos.Filename,
+				Line:   param.Pos.Line,
+				Column: param.Pos.Column,
+				Msg:    fmt.Sprintf("parameter %s of method %s must be [optional]: it follows optional parameter %s", param.Name, method.Name, firstOptional),
+			})
+		}
+
+		seen := make(map[string]bool)
+		for _, a := range param.Annotations {
+			switch {
+			case !parameterAnnotations[a.Name]:
+				errors.Add(&ValidationError{
+					File:   a.Pos.Filename,
+					Line:   a.Pos.Line,
+					Column: a.Pos.Column,
+					Msg:    fmt.Sprintf("unknown annotation [%s] on parameter %s of method %s", a.Name, param.Name, method.Name),
+				})
+			case seen[a.Name]:
+				errors.Add(&ValidationError{
+					File:   a.Pos.Filename,
+					Line:   a.Pos.Line,
+					Column: a.Pos.Column,
+					Msg:    fmt.Sprintf("duplicate annotation [%s] on parameter %s of method %s", a.Name, param.Name, method.Name),
+				})
+			}
+			seen[a.Name] = true
+		}
+
+		if value, ok := param.Default(); ok {
+			if msg := checkDefaultValue(value, param.Type, enums); msg != "" {
+				a := param.Annotation(AnnotationDefault)
+				errors.Add(&ValidationError{
+					File:   a.Pos.Filename,
+					Line:   a.Pos.Line,
+					Column: a.Pos.Column,
+					Msg:    fmt.Sprintf("default value %q of parameter %s of method %s %s", value, param.Name, method.Name, msg),
+				})
+			}
+		}
+	}
+}
+
+// checkDefaultValue returns why value is not a valid default for a parameter of
+// type t, or an empty string if it is valid
+func checkDefaultValue(value string, t *Type, enums map[string]*Enum) string {
+	if t == nil {
+		return ""
+	}
+	switch {
+	case t.IsBuiltIn():
+		var err error
+		switch t.BuiltIn {
+		case "int":
+			_, err = strconv.ParseInt(value, 10, 64)
+		case "float":
+			_, err = strconv.ParseFloat(value, 64)
+		case "bool":
+			if value != "true" && value != "false" {
+				err = fmt.Errorf("not a bool")
+			}
+		}
+		if err != nil {
+			return "is not a valid " + t.BuiltIn
+		}
+	case t.IsUserDefined() && enums[t.UserDefined] != nil:
+		for _, v := range enums[t.UserDefined].Values {
+			if v.Name == value {
+				return ""
+			}
+		}
+		return "is not a value of enum " + t.UserDefined
+	default:
+		return "is not allowed: only built-in types and enums can have a default"
+	}
+	return ""
+}
+
+// validateMethodAnnotations validates annotation names and the constraints they place on a method
+func validateMethodAnnotations(method *Method, typeNames map[string]string, errors *ValidationErrors) {
+	seen := make(map[string]bool)
+	for _, a := range method.Annotations {
+		if !methodAnnotations[a.Name] {
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("unknown annotation [%s] on method %s", a.Name, method.Name),
+			})
+			continue
+		}
+		if seen[a.Name] {
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("duplicate annotation [%s] on method %s", a.Name, method.Name),
+			})
+		}
+		seen[a.Name] = true
+	}
+
+	if a := method.Annotation(AnnotationScopes); a != nil && len(method.Scopes()) == 0 {
+		errors.Add(&ValidationError{
+			File:   a.Pos.Filename,
+			Line:   a.Pos.Line,
+			Column: a.Pos.Column,
+			Msg:    fmt.Sprintf("annotation [scopes] on method %s must list at least one scope, e.g. [scopes=\"users:read\"]", method.Name),
+		})
+	}
+	if a := method.Annotation(AnnotationTimeout); a != nil {
+		if d, err := time.ParseDuration(a.Value); err != nil || d <= 0 {
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("annotation [timeout] on method %s must be a positive duration such as \"5s\" (got %q)", method.Name, a.Value),
+			})
+		}
+	}
+
+	if a := method.Annotation(AnnotationWire); a != nil {
+		if msg := checkWireName(a.Value); msg != "" {
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("annotation [wire] on method %s %s", method.Name, msg),
+			})
+		}
+	}
+	validateOwnershipAnnotations(method.Annotations, "method "+method.Name, errors)
+	if a := method.Annotation(AnnotationNoLint); a != nil {
+		validateNoLint(a, "method", method.Name, errors)
+	}
+
+	// A GET of a read-only method returns its result, which an async method does not have yet
+	if a := method.Annotation(AnnotationAsync); a != nil && method.IsReadOnly() {
+		errors.Add(&ValidationError{
+			File:   a.Pos.Filename,
+			Line:   a.Pos.Line,
+			Column: a.Pos.Column,
+			Msg:    fmt.Sprintf("method %s cannot be both [async] and [readonly]", method.Name),
+		})
+	}
+
+	// Only GET responses carry caching headers
+	if a := method.Annotation(AnnotationCache); a != nil {
+		if d, err := time.ParseDuration(a.Value); err != nil || d < 0 || d%time.Second != 0 {
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("annotation [cache] on method %s must be a duration in whole seconds such as \"60s\" (got %q)", method.Name, a.Value),
+			})
+		}
+		if !method.IsReadOnly() {
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("annotation [cache] on method %s requires [readonly]", method.Name),
+			})
+		}
+	}
+
+	if a := method.Annotation(AnnotationCompress); a != nil {
+		if _, ok := method.CompressThreshold(); !ok {
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("annotation [compress] on method %s must have no value or a size in bytes such as \"4096\" (got %q)", method.Name, a.Value),
+			})
+		}
+	}
+
+	// Handlers of a [chunked] method emit array elements, and emitting none gives []
+	if a := method.Annotation(AnnotationChunked); a != nil {
+		if method.ReturnType == nil || method.ReturnType.Array == nil {
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("annotation [chunked] on method %s requires an array result", method.Name),
+			})
+		} else if method.ReturnOptional {
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("method %s cannot be [chunked] with an [optional] result", method.Name),
+			})
+		}
+	}
+
+	// Clients decode error data into the struct, so it must name one
+	if a := method.Annotation(AnnotationErrorData); a != nil && typeNames[a.Value] != "struct" {
+		errors.Add(&ValidationError{
+			File:   a.Pos.Filename,
+			Line:   a.Pos.Line,
+			Column: a.Pos.Column,
+			Msg:    fmt.Sprintf("annotation [errordata] on method %s must name a struct, e.g. [errordata=\"ValidationFailure\"] (got %q)", method.Name, a.Value),
+		})
+	}
+
+	if a := method.Annotation(AnnotationAccepts); a != nil {
+		validateAccepts(method, a, typeNames, errors)
+	}
+
+	// Read-only methods are served over HTTP GET, so every parameter must bind from a query string
+	if method.IsReadOnly() {
+		for _, param := range method.Parameters {
+			if !isQueryBindable(param.Type, typeNames) {
+				errors.Add(&ValidationError{
+					File:   param.Pos.Filename,
+					Line:   param.Pos.Line,
+					Column: param.Pos.Column,
+					Msg:    fmt.Sprintf("parameter %s of [readonly] method %s must be a built-in type, an enum, or an array of these (got %s)", param.Name, method.Name, param.Type.String()),
+				})
+			}
+		}
+	}
+}
+
+// validateAccepts checks the encodings listed by a method's [accepts] annotation. Form
+// fields and XML elements carry text like a query string, so every parameter must
+// bind from one.
+func validateAccepts(method *Method, a *Annotation, typeNames map[string]string, errors *ValidationErrors) {
+	encodings := method.Accepts()
+	if len(encodings) == 0 {
+		errors.Add(&ValidationError{
+			File:   a.Pos.Filename,
+			Line:   a.Pos.Line,
+			Column: a.Pos.Column,
+			Msg:    fmt.Sprintf("annotation [accepts] on method %s must list \"%s\", \"%s\" or both, e.g. [accepts=\"form,xml\"]", method.Name, AcceptsForm, AcceptsXML),
+		})
+		return
+	}
+	for _, encoding := range encodings {
+		if encoding != AcceptsForm && encoding != AcceptsXML {
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("annotation [accepts] on method %s lists unknown encoding %q (expected \"%s\" or \"%s\")", method.Name, encoding, AcceptsForm, AcceptsXML),
+			})
+		}
+	}
+	for _, param := range method.Parameters {
+		if !isQueryBindable(param.Type, typeNames) {
+			errors.Add(&ValidationError{
+				File:   param.Pos.Filename,
+				Line:   param.Pos.Line,
+				Column: param.Pos.Column,
+				Msg:    fmt.Sprintf("parameter %s of [accepts] method %s must be a built-in type, an enum, or an array of these (got %s)", param.Name, method.Name, param.Type.String()),
+			})
+		}
+	}
+}
+
+// validateWireNames validates interface annotations and reports methods whose
+// JSON-RPC names, after [wire] mappings, collide on the servers that dispatch them
+func validateWireNames(idl *IDL, errors *ValidationErrors) {
+	for _, iface := range idl.Interfaces {
+		seen := make(map[string]bool)
+		for _, a := range iface.Annotations {
+			switch {
+			case !interfaceAnnotations[a.Name]:
+				errors.Add(&ValidationError{
+					File:   a.Pos.Filename,
+					Line:   a.Pos.Line,
+					Column: a.Pos.Column,
+					Msg:    fmt.Sprintf("unknown annotation [%s] on interface %s", a.Name, iface.Name),
+				})
+				continue
+			case seen[a.Name]:
+				errors.Add(&ValidationError{
+					File:   a.Pos.Filename,
+					Line:   a.Pos.Line,
+					Column: a.Pos.Column,
+					Msg:    fmt.Sprintf("duplicate annotation [%s] on interface %s", a.Name, iface.Name),
+				})
+			}
+			seen[a.Name] = true
+		}
+		if a := iface.Annotation(AnnotationWire); a != nil {
+			if msg := checkWireName(a.Value); msg != "" {
+				errors.Add(&ValidationError{
+					File:   a.Pos.Filename,
+					Line:   a.Pos.Line,
+					Column: a.Pos.Column,
+					Msg:    fmt.Sprintf("annotation [wire] on interface %s %s", iface.Name, msg),
+				})
+			}
+		}
+		validateOwnershipAnnotations(iface.Annotations, "interface "+iface.Name, errors)
+		if a := iface.Annotation(AnnotationNoLint); a != nil {
+			validateNoLint(a, "interface", iface.Name, errors)
+		}
+	}
+
+	// Every interface, with its inherited methods, is served under its own names
+	declared := make(map[string]string)
+	for _, iface := range idl.Interfaces {
+		for _, m := range iface.Methods {
+			name := iface.RPCName(m)
+			if prev, exists := declared[name]; exists {
+				if prev == iface.Name+"."+m.Name && m.InheritedFrom == "" {
+					continue // a duplicate method, reported by validateUniqueNames
+				}
+				errors.Add(&ValidationError{
+					File:   iface.Pos.Filename,
+					Line:   iface.Pos.Line,
+					Column: iface.Pos.Column,
+					Msg:    fmt.Sprintf("method %s.%s has the JSON-RPC name %s, which is already used by %s", iface.Name, m.Name, name, prev),
+				})
+				continue
+			}
+			declared[name] = iface.Name + "." + m.Name
+		}
+	}
+}
+
+// validateOwnershipAnnotations checks the values of the [owner] and [stability]
+// annotations of an interface or method, described by where
+func validateOwnershipAnnotations(annotations []*Annotation, where string, errors *ValidationErrors) {
+	for _, a := range annotations {
+		msg := ""
+		switch {
+		case a.Name == AnnotationOwner && strings.TrimSpace(a.Value) == "":
+			msg = "needs a team name, e.g. [owner=\"team-payments\"]"
+		case a.Name == AnnotationStability && a.Value != StabilityExperimental && a.Value != StabilityStable:
+			msg = fmt.Sprintf("must be \"%s\" or \"%s\" (got %q)", StabilityExperimental, StabilityStable, a.Value)
+		}
+		if msg != "" {
+			errors.Add(&ValidationError{
+				File:   a.Pos.Filename,
+				Line:   a.Pos.Line,
+				Column: a.Pos.Column,
+				Msg:    fmt.Sprintf("annotation [%s] on %s %s", a.Name, where, msg),
+			})
+		}
+	}
+}
+
+// checkWireName returns why a [wire] value cannot name methods, or "" if it can
+func checkWireName(name string) string {
+	switch {
+	case name == "":
+		return "needs a name, e.g. [wire=\"v1.user.get\"]"
+	case !wireNameRegex.MatchString(name):
+		return fmt.Sprintf("may only contain letters, digits and . _ - : / (got %q)", name)
+	case strings.HasPrefix(name, "pulserpc-"):
+		return fmt.Sprintf("must not start with the reserved prefix pulserpc- (got %q)", name)
+	}
+	return ""
+}
+
+// isQueryBindable returns true if values of the type can be carried as URL query parameters
+func isQueryBindable(t *Type, typeNames map[string]string) bool {
+	if t == nil {
+		return false
+	}
+	if t.IsArray() {
+		return !t.Array.IsArray() && isQueryBindable(t.Array, typeNames)
+	}
+	if t.IsBuiltIn() {
+		return true
+	}
+	return t.IsUserDefined() && typeNames[t.UserDefined] == "enum"
+}
+
 // validateIdentifierName validates that an identifier matches the naming rules
-func validateIdentifierName(name string, errors *ValidationErrors, line, column int) bool {
+func validateIdentifierName(name string, errors *ValidationErrors, pos lexer.Position) bool {
 	if !identifierRegex.MatchString(name) {
 		errors.Add(&ValidationError{
-			Line:   line,
-			Column: column,
+			File:   pos.Filename,
+			Line:   pos.Line,
+			Column: pos.Column,
 			Msg:    fmt.Sprintf("invalid identifier: %s (must start with a letter, followed by letters, numbers, or underscores)", name),
 		})
 		return false
@@ -272,6 +917,7 @@ func detectCycles(idl *IDL, errors *ValidationErrors) {
 			s := structMap[structName]
 			if s != nil {
 				errors.Add(&ValidationError{
+					File:   s.Pos.Filename,
 					Line:   s.Pos.Line,
 					Column: s.Pos.Column,
 					Msg:    fmt.Sprintf("circular type reference detected: %s", cyclePath),
@@ -81,6 +81,9 @@ func (m *Manager) Generate(idl string, runtime string) (*Session, error) {
 	if err != nil {
 		return nil, fmt.Errorf("IDL parse error: %w", err)
 	}
+	if err := parser.ValidateIDL(parsedIDL); err != nil {
+		return nil, fmt.Errorf("IDL validation error: %w", err)
+	}
 
 	// Generate ULID for session
 	t := time.Now()
@@ -0,0 +1,207 @@
+// Package pulserpc is the Go API for generating code from PulseRPC IDL in other
+// Go programs, such as build tools, without running the pulse CLI:
+//
+//	idl, err := pulserpc.ParseIDL("service.pulse")
+//	if err != nil {
+//		return err
+//	}
+//	files, err := pulserpc.Generate(idl, pulserpc.PluginOptions{
+//		Plugin: "go-client-server",
+//		Flags:  map[string]string{"generate-test-files": "true"},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	return files.Write("gen")
+//
+// Errors are returned as values: parse and validation errors are the
+// *parser.ParseError and *parser.ValidationErrors the CLI prints.
+package pulserpc
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/generator"
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// ParseIDL reads the IDL file at path and the files it imports, and returns the
+// IDL once it is valid
+func ParseIDL(path string) (*parser.IDL, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	idl, err := parser.ParseIDL(path, string(content))
+	if err != nil {
+		return nil, err
+	}
+	if err := parser.ValidateIDL(idl); err != nil {
+		return nil, err
+	}
+	return idl, nil
+}
+
+// Plugins returns the names of the plugins Generate can run, sorted
+func Plugins() []string {
+	var names []string
+	for _, p := range generator.Builtin() {
+		names = append(names, p.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PluginOptions selects the plugin Generate runs and the flags it runs with
+type PluginOptions struct {
+	// Plugin is the name of the plugin, e.g. "go-client-server"; see Plugins
+	Plugin string
+	// Flags are the values of the plugin's flags by name, without the leading
+	// dash, as they are written on the command line, e.g.
+	// {"base-package": "com.example", "generate-test-files": "true"}. Flags the
+	// plugin does not read are an error; `pulse help <plugin>` lists them. Leave
+	// out -dir and write the files with Files.Write instead.
+	Flags map[string]string
+}
+
+// File is a generated file
+type File struct {
+	// Path is the file's path relative to the output directory, with forward slashes
+	Path    string
+	Content []byte
+	Mode    fs.FileMode
+}
+
+// Files are generated files, sorted by path
+type Files []*File
+
+// Get returns the file with the given path, or nil if there is none
+func (files Files) Get(path string) *File {
+	for _, f := range files {
+		if f.Path == path {
+			return f
+		}
+	}
+	return nil
+}
+
+// Write writes the files into dir, creating the directories they are in
+func (files Files) Write(dir string) error {
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, f.Content, f.Mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Generate runs a plugin on the IDL and returns the files it generates. The plugin
+// writes them into a temporary directory that is removed before Generate returns.
+func Generate(idl *parser.IDL, opts PluginOptions) (Files, error) {
+	var plugin generator.Plugin
+	for _, p := range generator.Builtin() {
+		if p.Name() == opts.Plugin {
+			plugin = p
+		}
+	}
+	if plugin == nil {
+		return nil, fmt.Errorf("unknown plugin %q (available plugins: %s)", opts.Plugin, strings.Join(Plugins(), ", "))
+	}
+
+	dir, err := os.MkdirTemp("", "pulserpc-generate-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	flags, err := pluginFlags(plugin, opts.Flags, dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := plugin.Generate(idl, flags); err != nil {
+		return nil, fmt.Errorf("plugin %q failed: %w", plugin.Name(), err)
+	}
+	if flags.Lookup("verify").Value.String() == "true" {
+		if err := generator.Verify(plugin, flags); err != nil {
+			return nil, fmt.Errorf("verification failed: %w", err)
+		}
+	}
+	return readFiles(dir)
+}
+
+// pluginFlags returns the FlagSet the plugin generates into dir with: the flags it
+// registers and the shared flags it reads, set to values
+func pluginFlags(plugin generator.Plugin, values map[string]string, dir string) (*flag.FlagSet, error) {
+	flags := flag.NewFlagSet(plugin.Name(), flag.ContinueOnError)
+	plugin.RegisterFlags(flags)
+	// Shared flags are defined by the CLI; plugins read their values as strings
+	supported := generator.PluginFlags(plugin)
+	reads := make(map[string]bool)
+	for _, name := range supported {
+		reads[name] = true
+	}
+	for _, name := range append(supported, "dir", "verify") {
+		if flags.Lookup(name) == nil {
+			flags.String(name, "", "")
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch {
+		case name == "dir":
+			return nil, fmt.Errorf("flag %s cannot be set: Generate returns the files, which Files.Write writes into a directory", name)
+		case !reads[name]:
+			return nil, fmt.Errorf("flag %s is not supported by plugin %q (flags: %s)", name, plugin.Name(), strings.Join(supported, ", "))
+		}
+		if err := flags.Set(name, values[name]); err != nil {
+			return nil, fmt.Errorf("flag %s: %w", name, err)
+		}
+	}
+	if err := flags.Set("dir", dir); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// readFiles returns the files under dir, sorted by path
+func readFiles(dir string) (Files, error) {
+	var files Files
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, &File{Path: filepath.ToSlash(rel), Content: content, Mode: info.Mode().Perm()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
@@ -0,0 +1,72 @@
+package pulserpc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeIDL(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "shop.pulse")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write IDL: %v", err)
+	}
+	return path
+}
+
+func TestGenerate(t *testing.T) {
+	idl, err := ParseIDL(writeIDL(t, "namespace shop\n\ninterface Orders {\n  place(sku string) string\n}\n"))
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	files, err := Generate(idl, PluginOptions{
+		Plugin: "java-client-server",
+		Flags:  map[string]string{"base-package": "com.example", "generate-test-files": "true"},
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	server := files.Get("src/main/java/com/example/Server.java")
+	if server == nil || !strings.Contains(string(server.Content), "package com.example;") {
+		t.Fatalf("expected Server.java in package com.example, got %v", server)
+	}
+	for i := 1; i < len(files); i++ {
+		if files[i-1].Path >= files[i].Path {
+			t.Errorf("files are not sorted: %s before %s", files[i-1].Path, files[i].Path)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := files.Write(dir); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "src/main/java/com/example/Server.java"))
+	if err != nil || string(content) != string(server.Content) {
+		t.Errorf("Write did not write Server.java: %v", err)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	idl, err := ParseIDL(writeIDL(t, "namespace shop\n\nstruct Order {\n  sku string\n}\n"))
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	for _, tt := range []struct {
+		opts PluginOptions
+		want string
+	}{
+		{PluginOptions{Plugin: "cobol-client-server"}, `unknown plugin "cobol-client-server"`},
+		{PluginOptions{Plugin: "go-client-server", Flags: map[string]string{"base-package": "com.example"}}, `flag base-package is not supported by plugin "go-client-server"`},
+		{PluginOptions{Plugin: "go-client-server", Flags: map[string]string{"dir": "gen"}}, "flag dir cannot be set"},
+	} {
+		if _, err := Generate(idl, tt.opts); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("expected error containing %q, got %v", tt.want, err)
+		}
+	}
+
+	if _, err := ParseIDL(writeIDL(t, "namespace shop\n\nstruct Order {\n  sku Sku\n}\n")); err == nil || !strings.Contains(err.Error(), "unknown type: Sku") {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
@@ -37,6 +37,11 @@ var javaRuntimeFiles embed.FS
 //go:embed all:runtimes/go/pulserpc
 var goRuntimeFiles embed.FS
 
+// Embed all Rust runtime files
+//
+//go:embed all:runtimes/rust/pulserpc
+var rustRuntimeFiles embed.FS
+
 // runtimeMap maps language names to their embedded file systems
 var runtimeMap = map[string]embed.FS{
 	"python": pythonRuntimeFiles,
@@ -44,6 +49,7 @@ var runtimeMap = map[string]embed.FS{
 	"csharp": csharpRuntimeFiles,
 	"java":   javaRuntimeFiles,
 	"go":     goRuntimeFiles,
+	"rust":   rustRuntimeFiles,
 }
 
 // ListRuntimes returns a list of all available embedded runtimes
@@ -94,6 +100,9 @@ func GetRuntimeFiles(lang string) (map[string][]byte, error) {
 		if lang == "go" && !strings.HasSuffix(entry.Name(), ".go") {
 			continue
 		}
+		if lang == "rust" && !strings.HasSuffix(entry.Name(), ".rs") {
+			continue
+		}
 
 		filePath := filepath.Join(basePath, entry.Name())
 		data, err := fs.ReadFile(filePath)
@@ -110,7 +119,8 @@ func GetRuntimeFiles(lang string) (map[string][]byte, error) {
 
 // CopyRuntimeFiles copies all runtime files for the specified language to the output directory
 // The files are copied to outputDir/{runtimePackageName}/ where runtimePackageName is typically
-// "pulserpc" for most languages, "PulseRPC" for C#, "com/bitmechanic/pulserpc" for Java
+// "pulserpc" for most languages, "PulseRPC" for C#, "com/bitmechanic/pulserpc" for Java,
+// "src/pulserpc" for Rust
 func CopyRuntimeFiles(lang string, outputDir string) error {
 	return CopyRuntimeFilesToPackage(lang, outputDir, getRuntimePackageName(lang))
 }
@@ -154,6 +164,8 @@ func getRuntimePackageName(lang string) string {
 		return "com/bitmechanic/pulserpc"
 	case "csharp":
 		return "PulseRPC"
+	case "rust":
+		return "src/pulserpc"
 	default:
 		return "pulserpc"
 	}
@@ -163,7 +175,7 @@ func getRuntimePackageName(lang string) string {
 // This is the path used in //go:embed directives and must match the actual directory structure
 func getRuntimeEmbedPath(lang string) string {
 	switch lang {
-	case "go", "python", "ts", "java":
+	case "go", "python", "ts", "java", "rust":
 		return fmt.Sprintf("runtimes/%s/pulserpc", lang)
 	case "csharp":
 		return "runtimes/csharp/PulseRPC"
@@ -0,0 +1,102 @@
+package runtime
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// FileInfo describes a file of an embedded runtime
+type FileInfo struct {
+	// Name is the file name, as copied into the runtime package directory
+	Name string
+	Size int
+	// SHA256 is the hex encoded SHA-256 checksum of the file's content
+	SHA256 string
+}
+
+// ListLanguages returns the languages that have an embedded runtime, sorted
+func ListLanguages() []string {
+	langs := ListRuntimes()
+	sort.Strings(langs)
+	return langs
+}
+
+// PackageDir returns the directory, relative to a plugin's output directory, that
+// CopyRuntimeFiles copies the runtime of lang into
+func PackageDir(lang string) string {
+	return getRuntimePackageName(lang)
+}
+
+// ListFiles returns the files of the runtime of lang, sorted by name
+func ListFiles(lang string) ([]FileInfo, error) {
+	files, err := GetRuntimeFiles(lang)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]FileInfo, 0, len(files))
+	for name, data := range files {
+		sum := sha256.Sum256(data)
+		infos = append(infos, FileInfo{Name: name, Size: len(data), SHA256: hex.EncodeToString(sum[:])})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// ReadFile returns the content of the runtime file of lang with the given name
+func ReadFile(lang, name string) ([]byte, error) {
+	files, err := GetRuntimeFiles(lang)
+	if err != nil {
+		return nil, err
+	}
+	data, ok := files[name]
+	if !ok {
+		return nil, fmt.Errorf("runtime for language %q has no file %q", lang, name)
+	}
+	return data, nil
+}
+
+// Checksum returns a checksum of the whole runtime of lang: the hex encoded
+// SHA-256 of the sha256sum style listing of its files, "<sha256>  <name>" per
+// line in name order. It changes whenever a file is added, removed or edited.
+func Checksum(lang string) (string, error) {
+	infos, err := ListFiles(lang)
+	if err != nil {
+		return "", err
+	}
+	var listing bytes.Buffer
+	for _, info := range infos {
+		fmt.Fprintf(&listing, "%s  %s\n", info.SHA256, info.Name)
+	}
+	sum := sha256.Sum256(listing.Bytes())
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// StaleFiles compares a vendored copy of the runtime of lang in dir, such as
+// PackageDir(lang) of a plugin's output directory, with the embedded runtime and
+// returns the names of the files that are missing from dir or differ, sorted.
+// Files in dir that the runtime does not have are ignored. Plugins that adapt a
+// runtime file while copying it, such as the Go plugin setting the package name,
+// make that file differ too.
+func StaleFiles(lang, dir string) ([]string, error) {
+	files, err := GetRuntimeFiles(lang)
+	if err != nil {
+		return nil, err
+	}
+	var stale []string
+	for name, data := range files {
+		vendored, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read vendored runtime file %s: %w", name, err)
+		}
+		if err != nil || !bytes.Equal(vendored, data) {
+			stale = append(stale, name)
+		}
+	}
+	sort.Strings(stale)
+	return stale, nil
+}
@@ -0,0 +1,75 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestListFiles(t *testing.T) {
+	if got := ListLanguages(); !reflect.DeepEqual(got, []string{"csharp", "go", "java", "python", "rust", "ts"}) {
+		t.Errorf("unexpected languages %v", got)
+	}
+
+	infos, err := ListFiles("python")
+	if err != nil {
+		t.Fatalf("ListFiles failed: %v", err)
+	}
+	var names []string
+	var init FileInfo
+	for _, info := range infos {
+		names = append(names, info.Name)
+		if len(info.SHA256) != 64 || info.Size == 0 {
+			t.Errorf("unexpected info %+v", info)
+		}
+		if info.Name == "__init__.py" {
+			init = info
+		}
+	}
+	if !sort.StringsAreSorted(names) || init.Name == "" {
+		t.Errorf("expected sorted names including __init__.py, got %v", names)
+	}
+
+	data, err := ReadFile("python", "__init__.py")
+	if err != nil || len(data) != init.Size {
+		t.Errorf("ReadFile returned %d bytes, %v; want %d bytes", len(data), err, init.Size)
+	}
+	if _, err := ReadFile("python", "missing.py"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	if _, err := ListFiles("cobol"); err == nil {
+		t.Error("expected an error for an unknown language")
+	}
+}
+
+func TestStaleFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := CopyRuntimeFilesToPackage("ts", dir, ""); err != nil {
+		t.Fatalf("CopyRuntimeFilesToPackage failed: %v", err)
+	}
+	stale, err := StaleFiles("ts", dir)
+	if err != nil || len(stale) != 0 {
+		t.Fatalf("expected a fresh copy, got %v, %v", stale, err)
+	}
+	before, err := Checksum("ts")
+	if err != nil {
+		t.Fatalf("Checksum failed: %v", err)
+	}
+
+	infos, _ := ListFiles("ts")
+	if err := os.WriteFile(filepath.Join(dir, infos[0].Name), []byte("// edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, infos[1].Name)); err != nil {
+		t.Fatal(err)
+	}
+	stale, err = StaleFiles("ts", dir)
+	if err != nil || !reflect.DeepEqual(stale, []string{infos[0].Name, infos[1].Name}) {
+		t.Errorf("expected %s and %s to be stale, got %v, %v", infos[0].Name, infos[1].Name, stale, err)
+	}
+	if after, _ := Checksum("ts"); after != before {
+		t.Errorf("Checksum is not stable: %s != %s", before, after)
+	}
+}
@@ -0,0 +1,176 @@
+using System;
+using System.Globalization;
+using System.Linq;
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.Json;
+
+namespace PulseRPC
+{
+    /// <summary>
+    /// Canonical JSON and request hashes that match across runtimes
+    /// </summary>
+    public static class CanonicalJson
+    {
+        /// <summary>
+        /// Returns the SHA-256, in lowercase hex, of the canonical JSON of
+        /// {"method": method, "params": params}. Every runtime computes the same hash for
+        /// the same call, so it can key idempotency records, caches and deduplication across
+        /// services written in different languages. Params sent by position and by name hash
+        /// differently.
+        /// </summary>
+        public static string RequestHash(string method, object? parameters)
+        {
+            var canonical = Encode(new { method, @params = parameters });
+            return Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes(canonical))).ToLowerInvariant();
+        }
+
+        /// <summary>
+        /// Encodes value as canonical JSON (RFC 8785): no whitespace, object keys sorted by
+        /// their UTF-16 code units, numbers formatted as JavaScript does and strings escaped
+        /// only where JSON requires it. value is first serialized with System.Text.Json, so
+        /// objects are canonicalized by their JSON property names.
+        /// </summary>
+        /// <exception cref="ArgumentException">For NaN and infinities</exception>
+        public static string Encode(object? value, JsonSerializerOptions? options = null)
+        {
+            using var document = JsonDocument.Parse(JsonSerializer.SerializeToUtf8Bytes(value, options));
+            var sb = new StringBuilder();
+            Write(sb, document.RootElement);
+            return sb.ToString();
+        }
+
+        private static void Write(StringBuilder sb, JsonElement element)
+        {
+            switch (element.ValueKind)
+            {
+                case JsonValueKind.Object:
+                    sb.Append('{');
+                    // Ordinal comparison orders by UTF-16 code units, as RFC 8785 sorts keys
+                    var first = true;
+                    foreach (var property in element.EnumerateObject().OrderBy(p => p.Name, StringComparer.Ordinal))
+                    {
+                        if (!first)
+                        {
+                            sb.Append(',');
+                        }
+                        first = false;
+                        WriteString(sb, property.Name);
+                        sb.Append(':');
+                        Write(sb, property.Value);
+                    }
+                    sb.Append('}');
+                    break;
+                case JsonValueKind.Array:
+                    sb.Append('[');
+                    var firstItem = true;
+                    foreach (var item in element.EnumerateArray())
+                    {
+                        if (!firstItem)
+                        {
+                            sb.Append(',');
+                        }
+                        firstItem = false;
+                        Write(sb, item);
+                    }
+                    sb.Append(']');
+                    break;
+                case JsonValueKind.String:
+                    WriteString(sb, element.GetString()!);
+                    break;
+                case JsonValueKind.Number:
+                    sb.Append(Number(element.GetDouble()));
+                    break;
+                case JsonValueKind.True:
+                    sb.Append("true");
+                    break;
+                case JsonValueKind.False:
+                    sb.Append("false");
+                    break;
+                default:
+                    sb.Append("null");
+                    break;
+            }
+        }
+
+        /// <summary>
+        /// Formats f as JavaScript's Number.prototype.toString does
+        /// </summary>
+        internal static string Number(double f)
+        {
+            if (double.IsNaN(f) || double.IsInfinity(f))
+            {
+                throw new ArgumentException($"cannot canonicalize {f}");
+            }
+            if (f == 0)
+            {
+                return "0";
+            }
+            var sign = f < 0 ? "-" : "";
+            // "R" gives the shortest digits that round-trip; n is the position of the
+            // decimal point relative to them: f = 0.digits * 10^n
+            var formatted = Math.Abs(f).ToString("R", CultureInfo.InvariantCulture);
+            var exponent = 0;
+            var e = formatted.IndexOf('E');
+            if (e >= 0)
+            {
+                exponent = int.Parse(formatted.Substring(e + 1), CultureInfo.InvariantCulture);
+                formatted = formatted.Substring(0, e);
+            }
+            var point = formatted.IndexOf('.');
+            var digits = point >= 0 ? formatted.Remove(point, 1) : formatted;
+            var n = (point >= 0 ? point : formatted.Length) + exponent;
+            var leading = digits.Length - digits.TrimStart('0').Length;
+            digits = digits.Trim('0');
+            n -= leading;
+            var k = digits.Length;
+            if (k <= n && n <= 21)
+            {
+                return sign + digits + new string('0', n - k);
+            }
+            if (0 < n && n <= 21)
+            {
+                return sign + digits.Substring(0, n) + "." + digits.Substring(n);
+            }
+            if (-6 < n && n <= 0)
+            {
+                return sign + "0." + new string('0', -n) + digits;
+            }
+            var exp = n - 1;
+            var mantissa = k == 1 ? digits : digits.Substring(0, 1) + "." + digits.Substring(1);
+            return sign + mantissa + "e" + (exp < 0 ? "-" : "+") + Math.Abs(exp).ToString(CultureInfo.InvariantCulture);
+        }
+
+        /// <summary>
+        /// Escapes only quotes, backslashes and control characters
+        /// </summary>
+        private static void WriteString(StringBuilder sb, string s)
+        {
+            sb.Append('"');
+            foreach (var c in s)
+            {
+                switch (c)
+                {
+                    case '"': sb.Append("\\\""); break;
+                    case '\\': sb.Append("\\\\"); break;
+                    case '\b': sb.Append("\\b"); break;
+                    case '\f': sb.Append("\\f"); break;
+                    case '\n': sb.Append("\\n"); break;
+                    case '\r': sb.Append("\\r"); break;
+                    case '\t': sb.Append("\\t"); break;
+                    default:
+                        if (c < 0x20)
+                        {
+                            sb.Append("\\u").Append(((int)c).ToString("x4", CultureInfo.InvariantCulture));
+                        }
+                        else
+                        {
+                            sb.Append(c);
+                        }
+                        break;
+                }
+            }
+            sb.Append('"');
+        }
+    }
+}
@@ -0,0 +1,109 @@
+using System;
+using System.Collections.Generic;
+using System.Linq;
+using System.Text.Json;
+using System.Text.Json.Nodes;
+using System.Threading.Tasks;
+
+namespace PulseRPC
+{
+    /// <summary>
+    /// A server that another server serves calls for, so services generated from different
+    /// IDLs can share one port. It is made of delegates rather than an interface so a server
+    /// composes servers generated into other assemblies, each with its own copy of this
+    /// runtime: host.Compose(other.Handles, other.HandleRequestAsync, () => other.IdlJson).
+    /// </summary>
+    public sealed record ComposedService(
+        Func<string, bool> Handles,
+        Func<JsonElement, Task<Dictionary<string, object?>?>> HandleRequestAsync,
+        Func<string> IdlJson);
+
+    /// <summary>
+    /// The servers a server serves calls for besides its own interfaces, routed by interface
+    /// name from its own endpoint. Empty when created.
+    /// </summary>
+    public sealed class Composition
+    {
+        private static readonly string[] IdlSections = { "interfaces", "structs", "enums", "typedefs" };
+
+        private readonly List<ComposedService> _services = new List<ComposedService>();
+
+        /// <summary>
+        /// Routes calls for the interfaces of service to it. A method the composing server
+        /// handles itself stays with it, and of the added services the first that handles a
+        /// method gets it.
+        /// </summary>
+        public void Add(ComposedService service)
+        {
+            lock (_services)
+            {
+                _services.Add(service);
+            }
+        }
+
+        /// <summary>The added service that handles method, or null.</summary>
+        public ComposedService? Find(string method)
+        {
+            lock (_services)
+            {
+                return _services.FirstOrDefault(service => service.Handles(method));
+            }
+        }
+
+        /// <summary>Returns idlJson with the idl.json documents of every added service merged in.</summary>
+        public string MergeIdl(string idlJson)
+        {
+            List<string> docs;
+            lock (_services)
+            {
+                if (_services.Count == 0)
+                {
+                    return idlJson;
+                }
+                docs = new List<string> { idlJson };
+                docs.AddRange(_services.Select(service => service.IdlJson()));
+            }
+            return MergeIdlDocuments(docs);
+        }
+
+        /// <summary>
+        /// Merges idl.json documents into one that lists the interfaces, structs, enums and
+        /// typedefs of all of them, in order, each name once, so a type from an IDL both
+        /// include is listed once. The rootNamespace of the first document is kept.
+        /// </summary>
+        public static string MergeIdlDocuments(IEnumerable<string> idlJsons)
+        {
+            var docs = idlJsons.Select(json => JsonNode.Parse(json) as JsonObject ?? new JsonObject()).ToList();
+            var merged = new JsonObject();
+            if (docs.Count > 0 && docs[0]["rootNamespace"] is JsonNode root)
+            {
+                merged["rootNamespace"] = root.DeepClone();
+            }
+            foreach (var section in IdlSections)
+            {
+                var seen = new HashSet<string>();
+                var elements = new JsonArray();
+                foreach (var doc in docs)
+                {
+                    if (doc[section] is not JsonArray list)
+                    {
+                        continue;
+                    }
+                    foreach (var element in list)
+                    {
+                        var name = (element as JsonObject)?["name"]?.GetValue<string>() ?? "";
+                        if (seen.Add(name))
+                        {
+                            elements.Add(element?.DeepClone());
+                        }
+                    }
+                }
+                if (elements.Count > 0)
+                {
+                    merged[section] = elements;
+                }
+            }
+            return merged.ToJsonString();
+        }
+    }
+}
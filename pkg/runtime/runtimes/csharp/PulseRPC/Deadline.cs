@@ -0,0 +1,88 @@
+using System;
+using System.Globalization;
+using System.Threading;
+
+namespace PulseRPC
+{
+    /// <summary>
+    /// The deadline of the call being handled: the time its caller allows for it, sent in
+    /// the X-PulseRPC-Deadline header as milliseconds. Servers begin a deadline for each
+    /// request, handlers pass Token to the work they start so it stops when the caller
+    /// has given up, and clients use Remaining as the timeout of calls made while
+    /// handling it, so downstream calls fit in the caller's budget.
+    /// </summary>
+    public static class Deadline
+    {
+        public const string Header = "X-PulseRPC-Deadline";
+
+        private sealed class Scope : IDisposable
+        {
+            public Scope(DateTime expires, TimeSpan budget)
+            {
+                Expires = expires;
+                Cancellation = new CancellationTokenSource(budget > TimeSpan.Zero ? budget : TimeSpan.Zero);
+            }
+
+            public DateTime Expires { get; }
+            public CancellationTokenSource Cancellation { get; }
+
+            public void Dispose()
+            {
+                if (Current.Value == this)
+                {
+                    Current.Value = null;
+                }
+                Cancellation.Dispose();
+            }
+        }
+
+        private static readonly AsyncLocal<Scope?> Current = new();
+
+        /// <summary>
+        /// Cancelled when the deadline of the call being handled passes; CancellationToken.None
+        /// when the call has no deadline
+        /// </summary>
+        public static CancellationToken Token => Current.Value?.Cancellation.Token ?? CancellationToken.None;
+
+        /// <summary>
+        /// The time left before the deadline of the call being handled, zero once it has
+        /// passed, or null when the call has no deadline
+        /// </summary>
+        public static TimeSpan? Remaining
+        {
+            get
+            {
+                var scope = Current.Value;
+                if (scope == null)
+                {
+                    return null;
+                }
+                var remaining = scope.Expires - DateTime.UtcNow;
+                return remaining > TimeSpan.Zero ? remaining : TimeSpan.Zero;
+            }
+        }
+
+        /// <summary>
+        /// Sets the deadline of the call handled by the calling async method from its
+        /// X-PulseRPC-Deadline header value, until the returned scope is disposed. A missing
+        /// or malformed value leaves the call without a deadline.
+        /// </summary>
+        public static IDisposable? Begin(string? headerValue)
+        {
+            if (!long.TryParse(headerValue, NumberStyles.AllowLeadingSign, CultureInfo.InvariantCulture, out var budgetMs))
+            {
+                return null;
+            }
+            var budget = TimeSpan.FromMilliseconds(budgetMs);
+            var scope = new Scope(DateTime.UtcNow + budget, budget);
+            Current.Value = scope;
+            return scope;
+        }
+
+        /// <summary>
+        /// Formats timeout as an X-PulseRPC-Deadline header value
+        /// </summary>
+        public static string HeaderValue(TimeSpan timeout) =>
+            ((long)timeout.TotalMilliseconds).ToString(CultureInfo.InvariantCulture);
+    }
+}
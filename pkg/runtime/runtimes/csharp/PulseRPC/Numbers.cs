@@ -0,0 +1,119 @@
+using System;
+using System.Collections;
+using System.Collections.Generic;
+using System.Text.Json;
+using System.Text.Json.Serialization;
+
+namespace PulseRPC
+{
+    /// <summary>
+    /// The policy for int values written with a fraction or exponent, such as 2.0. A float
+    /// accepts any number under either policy, and an int with a fractional part, such as
+    /// 2.5, is always rejected.
+    /// </summary>
+    public enum NumberPolicy
+    {
+        /// <summary>Accept any number with no fractional part for int, so 2.0 is taken as 2 (the default)</summary>
+        Lenient,
+        /// <summary>Accept only integer literals, such as 2, for int</summary>
+        Strict
+    }
+
+    /// <summary>
+    /// Checks and converts int values according to the NumberPolicy
+    /// </summary>
+    public static class Numbers
+    {
+        /// <summary>
+        /// Throw if a value of type int in a decoded JSON value was written with a fraction
+        /// or exponent, which the server decodes as a double
+        /// </summary>
+        public static void CheckIntLiterals(object? value, Dictionary<string, object> typeDef, Dictionary<string, Dictionary<string, object>> allStructs)
+        {
+            if (value == null)
+            {
+                return;
+            }
+            if (typeDef.TryGetValue("builtIn", out var builtIn) && builtIn?.ToString() == "int")
+            {
+                if (value is double || value is float)
+                {
+                    throw new ArgumentException($"Expected int, got {value} written as a float");
+                }
+            }
+            else if (typeDef.TryGetValue("array", out var arrayObj) && arrayObj is Dictionary<string, object> elementType && value is IList list)
+            {
+                for (int i = 0; i < list.Count; i++)
+                {
+                    try
+                    {
+                        CheckIntLiterals(list[i], elementType, allStructs);
+                    }
+                    catch (ArgumentException e)
+                    {
+                        throw new ArgumentException($"Array element at index {i} validation failed: {e.Message}", e);
+                    }
+                }
+            }
+            else if (typeDef.TryGetValue("mapValue", out var mapValueObj) && mapValueObj is Dictionary<string, object> valueType && value is IDictionary map)
+            {
+                foreach (DictionaryEntry entry in map)
+                {
+                    try
+                    {
+                        CheckIntLiterals(entry.Value, valueType, allStructs);
+                    }
+                    catch (ArgumentException e)
+                    {
+                        throw new ArgumentException($"Map value for key '{entry.Key}' validation failed: {e.Message}", e);
+                    }
+                }
+            }
+            else if (typeDef.TryGetValue("userDefined", out var userDefined) && userDefined is string structName && value is Dictionary<string, object?> dict)
+            {
+                foreach (var field in Types.GetStructFields(structName, allStructs))
+                {
+                    var fieldName = field["name"].ToString() ?? "";
+                    if (dict.TryGetValue(fieldName, out var fieldValue) && field["type"] is Dictionary<string, object> fieldType)
+                    {
+                        try
+                        {
+                            CheckIntLiterals(fieldValue, fieldType, allStructs);
+                        }
+                        catch (ArgumentException e)
+                        {
+                            throw new ArgumentException($"Field '{fieldName}' in struct {structName} validation failed: {e.Message}", e);
+                        }
+                    }
+                }
+            }
+        }
+    }
+
+    /// <summary>
+    /// Reads an int from any JSON number with no fractional part, such as 2.0, which the
+    /// built-in converter rejects. Handler arguments and client results use it, so the
+    /// lenient NumberPolicy holds for typed values too.
+    /// </summary>
+    public sealed class WholeNumberConverter : JsonConverter<int>
+    {
+        public override int Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options)
+        {
+            if (reader.TokenType == JsonTokenType.Number && !reader.TryGetInt32(out _))
+            {
+                var d = reader.GetDouble();
+                if (Math.Floor(d) != d || d < int.MinValue || d > int.MaxValue)
+                {
+                    throw new JsonException($"Expected int, got {d}");
+                }
+                return (int)d;
+            }
+            return reader.GetInt32();
+        }
+
+        public override void Write(Utf8JsonWriter writer, int value, JsonSerializerOptions options)
+        {
+            writer.WriteNumberValue(value);
+        }
+    }
+}
@@ -0,0 +1,103 @@
+using System;
+using System.Text.Json;
+using System.Text.Json.Serialization;
+
+namespace PulseRPC
+{
+    /// <summary>
+    /// Type of an optional struct property generated with -optional-presence. Unlike a
+    /// nullable property it tells a field that was left out from one set to an explicit
+    /// null, which patch-style APIs use to clear a value. The default value is absent and
+    /// is not written to JSON. Assigning a value, or null, makes the field present.
+    /// </summary>
+    [JsonConverter(typeof(OptionalConverterFactory))]
+    public readonly struct Optional<T>
+    {
+        private Optional(T value, bool isNull)
+        {
+            Value = value;
+            IsPresent = true;
+            IsNull = isNull;
+        }
+
+        /// <summary>
+        /// The value of a present, non-null field
+        /// </summary>
+        public T Value { get; }
+
+        /// <summary>
+        /// False when the field is absent
+        /// </summary>
+        public bool IsPresent { get; }
+
+        /// <summary>
+        /// True when the field is present and set to null
+        /// </summary>
+        public bool IsNull { get; }
+
+        /// <summary>
+        /// True when the field holds a value, that is, it is neither absent nor null
+        /// </summary>
+        public bool HasValue => IsPresent && !IsNull;
+
+        /// <summary>
+        /// An absent field
+        /// </summary>
+        public static Optional<T> Absent => default;
+
+        /// <summary>
+        /// A field set to an explicit null
+        /// </summary>
+        public static Optional<T> Null => new Optional<T>(default!, true);
+
+        public static implicit operator Optional<T>(T value) => value is null ? Null : new Optional<T>(value, false);
+
+        public override string ToString() => !IsPresent ? "<absent>" : IsNull ? "null" : Value?.ToString() ?? "null";
+    }
+
+    /// <summary>
+    /// Creates the JSON converter of an Optional type
+    /// </summary>
+    public class OptionalConverterFactory : JsonConverterFactory
+    {
+        public override bool CanConvert(Type typeToConvert)
+        {
+            return typeToConvert.IsGenericType && typeToConvert.GetGenericTypeDefinition() == typeof(Optional<>);
+        }
+
+        public override JsonConverter CreateConverter(Type typeToConvert, JsonSerializerOptions options)
+        {
+            var converterType = typeof(OptionalConverter<>).MakeGenericType(typeToConvert.GetGenericArguments()[0]);
+            return (JsonConverter)Activator.CreateInstance(converterType)!;
+        }
+    }
+
+    /// <summary>
+    /// Reads and writes an Optional as its value. The serializer only reads properties
+    /// found in the JSON, so a missing property stays absent while null becomes
+    /// Optional.Null.
+    /// </summary>
+    public class OptionalConverter<T> : JsonConverter<Optional<T>>
+    {
+        public override bool HandleNull => true;
+
+        public override Optional<T> Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options)
+        {
+            if (reader.TokenType == JsonTokenType.Null)
+            {
+                return Optional<T>.Null;
+            }
+            return JsonSerializer.Deserialize<T>(ref reader, options)!;
+        }
+
+        public override void Write(Utf8JsonWriter writer, Optional<T> value, JsonSerializerOptions options)
+        {
+            if (!value.HasValue)
+            {
+                writer.WriteNullValue();
+                return;
+            }
+            JsonSerializer.Serialize(writer, value.Value, options);
+        }
+    }
+}
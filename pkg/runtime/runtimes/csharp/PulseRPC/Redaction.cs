@@ -0,0 +1,102 @@
+using System;
+using System.Collections;
+using System.Collections.Generic;
+using System.Text.Json;
+using System.Text.Json.Nodes;
+using System.Text.Json.Serialization;
+
+namespace PulseRPC
+{
+    /// <summary>
+    /// Masks [sensitive] struct fields in values formatted for logs. The JSON sent on
+    /// the wire is not affected.
+    /// </summary>
+    public static class Redaction
+    {
+        /// <summary>
+        /// Replaces the value of a sensitive field in redacted output
+        /// </summary>
+        public const string Redacted = "***";
+
+        private static readonly JsonSerializerOptions Options = new JsonSerializerOptions
+        {
+            Converters = { new JsonStringEnumConverter() }
+        };
+
+        /// <summary>
+        /// Serialize a struct value to JSON with its sensitive fields, including those
+        /// of nested structs, masked
+        /// </summary>
+        public static string ToJson(object? value, string structName, Dictionary<string, Dictionary<string, object>> allStructs)
+        {
+            var typeDef = new Dictionary<string, object> { { "userDefined", structName } };
+            return Redact(JsonSerializer.SerializeToNode(value, Options), typeDef, allStructs)?.ToJsonString() ?? "null";
+        }
+
+        /// <summary>
+        /// Serialize JSON-RPC params to JSON, masking the sensitive fields of each
+        /// parameter according to the method's parameter definitions
+        /// </summary>
+        public static string ParamsToJson(object? paramsObj, IList expectedParams, Dictionary<string, Dictionary<string, object>> allStructs)
+        {
+            var node = JsonSerializer.SerializeToNode(paramsObj, Options);
+            if (node is JsonArray array)
+            {
+                for (int i = 0; i < array.Count && i < expectedParams.Count; i++)
+                {
+                    if (expectedParams[i] is Dictionary<string, object> paramDef && paramDef["type"] is Dictionary<string, object> typeDef)
+                    {
+                        Redact(array[i], typeDef, allStructs);
+                    }
+                }
+            }
+            return node?.ToJsonString() ?? "null";
+        }
+
+        /// <summary>
+        /// Mask the sensitive fields of a JSON value of the given type in place and
+        /// return it
+        /// </summary>
+        public static JsonNode? Redact(JsonNode? node, Dictionary<string, object> typeDef, Dictionary<string, Dictionary<string, object>> allStructs)
+        {
+            if (node == null)
+            {
+                return null;
+            }
+            if (typeDef.TryGetValue("array", out var arrayObj) && arrayObj is Dictionary<string, object> elementType && node is JsonArray array)
+            {
+                foreach (var item in array)
+                {
+                    Redact(item, elementType, allStructs);
+                }
+            }
+            else if (typeDef.TryGetValue("mapValue", out var mapValueObj) && mapValueObj is Dictionary<string, object> valueType && node is JsonObject map)
+            {
+                foreach (var kv in map)
+                {
+                    Redact(kv.Value, valueType, allStructs);
+                }
+            }
+            else if (typeDef.TryGetValue("userDefined", out var userDefinedObj) && userDefinedObj is string userType && node is JsonObject obj)
+            {
+                foreach (var field in Types.GetStructFields(userType, allStructs))
+                {
+                    var name = field["name"].ToString() ?? "";
+                    if (obj[name] == null)
+                    {
+                        continue;
+                    }
+                    if (field.TryGetValue("sensitive", out var sensitive) && sensitive is true)
+                    {
+                        obj[name] = Redacted;
+                    }
+                    else if (field["type"] is Dictionary<string, object> fieldType)
+                    {
+                        Redact(obj[name], fieldType, allStructs);
+                    }
+                }
+            }
+            return node;
+        }
+    }
+}
@@ -22,10 +22,15 @@ namespace PulseRPC
         }
 
         /// <summary>
-        /// Validate that value is an int
+        /// Validate that value is an int or a double with no fractional part, such as 2.0
+        /// (see NumberPolicy)
         /// </summary>
         public static void ValidateInt(object? value)
         {
+            if (value is double d && Math.Floor(d) == d && d >= int.MinValue && d <= int.MaxValue)
+            {
+                return;
+            }
             if (value is not int)
             {
                 throw new ArgumentException($"Expected int, got {value?.GetType().Name ?? "null"}");
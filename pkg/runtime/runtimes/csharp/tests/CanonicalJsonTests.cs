@@ -0,0 +1,91 @@
+using System;
+using System.Collections.Generic;
+using Xunit;
+using PulseRPC;
+
+namespace PulseRPC.Tests
+{
+    public class CanonicalJsonTests
+    {
+        // The same vectors are checked by every other runtime's tests, so every runtime
+        // hashes a call the same way, byte for byte
+        public static IEnumerable<object?[]> RequestHashVectors => new List<object?[]>
+        {
+            new object?[]
+            {
+                "Catalog.get",
+                new object[] { "p-1", 2 },
+                "{\"method\":\"Catalog.get\",\"params\":[\"p-1\",2]}",
+                "c83d6e2a3e0908ddb5e94fe05cffd450756e92caab52d017ad51be7b97fcb715"
+            },
+            new object?[]
+            {
+                "Catalog.search",
+                new object?[]
+                {
+                    new Dictionary<string, object>
+                    {
+                        { "query", "café \"x\"\n" },
+                        { "limit", 10 },
+                        { "tags", new[] { "a", "b" } },
+                        { "price", new Dictionary<string, double> { { "max", 99.5 }, { "min", 0.1 } } }
+                    },
+                    null
+                },
+                "{\"method\":\"Catalog.search\",\"params\":[{\"limit\":10,\"price\":{\"max\":99.5,\"min\":0.1},\"query\":\"café \\\"x\\\"\\n\",\"tags\":[\"a\",\"b\"]},null]}",
+                "bac87c8eb687a2e88fb49b08c30f0668d849c06613310acd110c66d42acc56ae"
+            },
+            new object?[]
+            {
+                "Catalog.bulk",
+                new Dictionary<string, object>
+                {
+                    { "ids", new[] { 1e21, 1e-7, -0.0, 1L << 60 } },
+                    { "é", true },
+                    { "a", false },
+                    { "\U0001F600", 1 },
+                    { "｡", 2 }
+                },
+                "{\"method\":\"Catalog.bulk\",\"params\":{\"a\":false,\"ids\":[1e+21,1e-7,0,1152921504606847000],\"é\":true,\"\U0001F600\":1,\"｡\":2}}",
+                "53bd8c11499fccf79fedaff23d160964bba118c23421f68f3167c40eb2a7750b"
+            },
+            // 2^53+1 is not a double: canonical JSON writes the nearest one, 2^53
+            new object?[]
+            {
+                "Catalog.edges",
+                new object[] { 1e21, 1e-7, -0.0, (1L << 53) + 1 },
+                "{\"method\":\"Catalog.edges\",\"params\":[1e+21,1e-7,0,9007199254740992]}",
+                "379f1ac102b547987989aaba1ce4f935c28a1d5b880068e36ffd2b017f2f98e5"
+            }
+        };
+
+        [Theory]
+        [MemberData(nameof(RequestHashVectors))]
+        public void RequestHash_MatchesVectors(string method, object parameters, string canonical, string hash)
+        {
+            var call = new Dictionary<string, object> { { "method", method }, { "params", parameters } };
+            Assert.Equal(canonical, CanonicalJson.Encode(call));
+            Assert.Equal(hash, CanonicalJson.RequestHash(method, parameters));
+        }
+
+        [Theory]
+        [InlineData(0.0, "0")]
+        [InlineData(-1.5, "-1.5")]
+        [InlineData(123.456, "123.456")]
+        [InlineData(1e20, "100000000000000000000")]
+        [InlineData(1e-6, "0.000001")]
+        [InlineData(1.5e300, "1.5e+300")]
+        [InlineData(5e-324, "5e-324")]
+        [InlineData(-2.5e-10, "-2.5e-10")]
+        public void Encode_FormatsNumbersAsJavaScript(double value, string expected)
+        {
+            Assert.Equal(expected, CanonicalJson.Encode(value));
+        }
+
+        [Fact]
+        public void Encode_RejectsNaN()
+        {
+            Assert.Throws<ArgumentException>(() => CanonicalJson.Encode(double.NaN));
+        }
+    }
+}
@@ -1,5 +1,6 @@
 using System;
 using System.Collections.Generic;
+using System.Text.Json;
 using Xunit;
 using PulseRPC;
 
@@ -36,6 +37,14 @@ namespace PulseRPC.Tests
             Assert.Throws<ArgumentException>(() => Validation.ValidateInt(3.14));
         }
 
+        [Fact]
+        public void ValidateInt_WholeDouble()
+        {
+            // The lenient number policy takes 2.0 as 2
+            Validation.ValidateInt(2.0);
+            Assert.Throws<ArgumentException>(() => Validation.ValidateInt(2.5));
+        }
+
         [Fact]
         public void ValidateFloat_Success()
         {
@@ -361,5 +370,31 @@ namespace PulseRPC.Tests
                 Validation.ValidateType(new Dictionary<string, object?> { { "a", "not int" } }, typeDef, allStructs, allEnums));
         }
     }
-}
 
+    public class NumberPolicyTests
+    {
+        private static readonly Dictionary<string, object> IntType = new() { { "builtIn", "int" } };
+
+        [Fact]
+        public void CheckIntLiterals_RejectsDoubles()
+        {
+            Numbers.CheckIntLiterals(2, IntType, new Dictionary<string, Dictionary<string, object>>());
+            Numbers.CheckIntLiterals(2.0, new Dictionary<string, object> { { "builtIn", "float" } }, new Dictionary<string, Dictionary<string, object>>());
+            Assert.Throws<ArgumentException>(() =>
+                Numbers.CheckIntLiterals(2.0, IntType, new Dictionary<string, Dictionary<string, object>>()));
+            var ex = Assert.Throws<ArgumentException>(() =>
+                Numbers.CheckIntLiterals(new List<object?> { 1, 2.0 }, new Dictionary<string, object> { { "array", IntType } }, new Dictionary<string, Dictionary<string, object>>()));
+            Assert.Contains("index 1", ex.Message);
+        }
+
+        [Fact]
+        public void WholeNumberConverter_ReadsWholeNumbers()
+        {
+            var options = new JsonSerializerOptions();
+            options.Converters.Add(new WholeNumberConverter());
+            Assert.Equal(2, JsonSerializer.Deserialize<int>("2.0", options));
+            Assert.Equal(3, JsonSerializer.Deserialize<int>("3", options));
+            Assert.Throws<JsonException>(() => JsonSerializer.Deserialize<int>("2.5", options));
+        }
+    }
+}
@@ -0,0 +1,130 @@
+package pulserpc
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// ComposedServer is a server that another server serves calls for, so services
+// generated from different IDLs can share one port. The PulseRPCServer of every
+// generated package implements it, and it only uses standard library types, so a
+// server composes servers generated into other packages.
+type ComposedServer interface {
+	// Handles reports whether method, "Interface.method" or a [wire] name, is a
+	// method of an interface registered with the server
+	Handles(method string) bool
+	// HandleRequestContext handles one decoded JSON-RPC request and returns its
+	// response, or nil for a notification
+	HandleRequestContext(ctx context.Context, request map[string]interface{}) map[string]interface{}
+	// IDLDocument returns the idl.json document of the server
+	IDLDocument() (map[string]interface{}, error)
+}
+
+// Composition holds what a server serves besides its own interfaces: servers whose
+// calls it routes by interface name from its own endpoint, and handlers it serves
+// under a path. The zero value is empty.
+type Composition struct {
+	servers []ComposedServer
+	mounts  []mountedHandler
+}
+
+type mountedHandler struct {
+	path    string
+	handler http.Handler
+}
+
+// Add routes calls for the interfaces of server to it. A method the composing
+// server handles itself stays with it, and of the added servers the first that
+// handles a method gets it.
+func (c *Composition) Add(server ComposedServer) {
+	c.servers = append(c.servers, server)
+}
+
+// Mount serves handler under path: requests to path and below go to handler with
+// path removed from the front of their URL path, so a generated server mounted at
+// "/billing" sees a POST to "/billing" as a POST to "/".
+func (c *Composition) Mount(path string, handler http.Handler) {
+	c.mounts = append(c.mounts, mountedHandler{path: "/" + strings.Trim(path, "/"), handler: handler})
+}
+
+// Find returns the added server that handles method, or nil
+func (c *Composition) Find(method string) ComposedServer {
+	for _, server := range c.servers {
+		if server.Handles(method) {
+			return server
+		}
+	}
+	return nil
+}
+
+// Route returns the handler of the mount with the longest path that r is under,
+// with r's path made relative to the mount, or false if r is under none
+func (c *Composition) Route(r *http.Request) (http.Handler, *http.Request, bool) {
+	var best *mountedHandler
+	for i, m := range c.mounts {
+		if r.URL.Path != m.path && !strings.HasPrefix(r.URL.Path, m.path+"/") {
+			continue
+		}
+		if best == nil || len(m.path) > len(best.path) {
+			best = &c.mounts[i]
+		}
+	}
+	if best == nil {
+		return nil, nil, false
+	}
+	routed := r.Clone(r.Context())
+	routed.URL.Path = "/" + strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, best.path), "/")
+	routed.URL.RawPath = ""
+	return best.handler, routed, true
+}
+
+// MergeIDL returns doc with the interfaces, structs, enums and typedefs of every
+// added server's idl.json appended. A type or interface that more than one IDL
+// declares, such as one from a shared include, is listed once.
+func (c *Composition) MergeIDL(doc map[string]interface{}) (map[string]interface{}, error) {
+	if len(c.servers) == 0 {
+		return doc, nil
+	}
+	docs := []map[string]interface{}{doc}
+	for _, server := range c.servers {
+		composed, err := server.IDLDocument()
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, composed)
+	}
+	return MergeIDLDocuments(docs...), nil
+}
+
+// MergeIDLDocuments merges idl.json documents into one that lists the interfaces,
+// structs, enums and typedefs of all of them, in order, each name once. The
+// rootNamespace of the first document is kept.
+func MergeIDLDocuments(docs ...map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{})
+	if len(docs) > 0 {
+		if root, ok := docs[0]["rootNamespace"]; ok {
+			merged["rootNamespace"] = root
+		}
+	}
+	for _, section := range []string{"interfaces", "structs", "enums", "typedefs"} {
+		seen := make(map[string]bool)
+		var elements []interface{}
+		for _, doc := range docs {
+			list, _ := doc[section].([]interface{})
+			for _, element := range list {
+				entry, _ := element.(map[string]interface{})
+				name, _ := entry["name"].(string)
+				if seen[name] {
+					continue
+				}
+				seen[name] = true
+				elements = append(elements, element)
+			}
+		}
+		if len(elements) > 0 {
+			merged[section] = elements
+		}
+	}
+	return merged
+}
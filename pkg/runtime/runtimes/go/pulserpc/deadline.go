@@ -0,0 +1,33 @@
+package pulserpc
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DeadlineHeader carries the time a caller allows for a call, in milliseconds.
+// Clients send it with every call that has a timeout, and servers give the
+// call's handler a context that expires when that time is up. The budget is
+// relative rather than a timestamp so the clocks of caller and server need not
+// agree; the time the request spends in transit is not deducted.
+const DeadlineHeader = "X-PulseRPC-Deadline"
+
+// DeadlineHeaderValue formats timeout as a DeadlineHeader value
+func DeadlineHeaderValue(timeout time.Duration) string {
+	return strconv.FormatInt(timeout.Milliseconds(), 10)
+}
+
+// RequestContext returns the context of an HTTP request, carrying its
+// RequestMeta and bounded by the deadline its caller sent in the DeadlineHeader.
+// A missing or malformed header leaves the call without a deadline; a budget of
+// zero or less has already expired.
+func RequestContext(r *http.Request) (context.Context, context.CancelFunc) {
+	ctx := WithRequestMeta(r.Context(), RequestMeta{Headers: r.Header, RemoteAddr: r.RemoteAddr})
+	budget, err := strconv.ParseInt(r.Header.Get(DeadlineHeader), 10, 64)
+	if err != nil {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(budget)*time.Millisecond)
+}
@@ -0,0 +1,137 @@
+package pulserpc
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// FieldCipher encrypts and decrypts the values of struct fields annotated
+// [encrypted], typically with a data key from a KMS. field names the field as
+// "Struct.field" so a cipher can choose keys per field. plaintext is the JSON
+// encoding of the field's value, and the ciphertext is sent as a JSON string.
+type FieldCipher interface {
+	Encrypt(field string, plaintext []byte) (string, error)
+	Decrypt(field string, ciphertext string) ([]byte, error)
+}
+
+// EncryptFields returns a copy of value, a value of type typeDef in the form
+// JSONValue returns, in which every non-null [encrypted] field holds its
+// ciphertext. value itself is not changed.
+func EncryptFields(value interface{}, typeDef map[string]interface{}, allStructs StructMap, cipher FieldCipher) (interface{}, error) {
+	return transformFields(value, typeDef, allStructs, func(field string, v interface{}) (interface{}, error) {
+		if cipher == nil {
+			return nil, fmt.Errorf("no FieldCipher set to encrypt field %s", field)
+		}
+		plaintext, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode field %s: %w", field, err)
+		}
+		ciphertext, err := cipher.Encrypt(field, plaintext)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt field %s: %w", field, err)
+		}
+		return ciphertext, nil
+	})
+}
+
+// DecryptFields reverses EncryptFields
+func DecryptFields(value interface{}, typeDef map[string]interface{}, allStructs StructMap, cipher FieldCipher) (interface{}, error) {
+	return transformFields(value, typeDef, allStructs, func(field string, v interface{}) (interface{}, error) {
+		if cipher == nil {
+			return nil, fmt.Errorf("no FieldCipher set to decrypt field %s", field)
+		}
+		ciphertext, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("encrypted field %s must be a string, got %T", field, v)
+		}
+		plaintext, err := cipher.Decrypt(field, ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt field %s: %w", field, err)
+		}
+		var out interface{}
+		if err := json.Unmarshal(plaintext, &out); err != nil {
+			return nil, fmt.Errorf("failed to decode field %s: %w", field, err)
+		}
+		return out, nil
+	})
+}
+
+// transformFields applies fn to the non-null [encrypted] fields of the structs in
+// value, following typeDef
+func transformFields(value interface{}, typeDef map[string]interface{}, allStructs StructMap, fn func(field string, v interface{}) (interface{}, error)) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if elementType, ok := typeDef["array"].(map[string]interface{}); ok {
+		list, ok := value.([]interface{})
+		if !ok {
+			return value, nil
+		}
+		out := make([]interface{}, len(list))
+		for i, v := range list {
+			converted, err := transformFields(v, elementType, allStructs, fn)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = converted
+		}
+		return out, nil
+	}
+	if valueType, ok := typeDef["mapValue"].(map[string]interface{}); ok {
+		dict, ok := value.(map[string]interface{})
+		if !ok {
+			return value, nil
+		}
+		out := make(map[string]interface{}, len(dict))
+		for k, v := range dict {
+			converted, err := transformFields(v, valueType, allStructs, fn)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = converted
+		}
+		return out, nil
+	}
+	structName, ok := typeDef["userDefined"].(string)
+	if !ok || FindStruct(structName, allStructs) == nil {
+		return value, nil
+	}
+	dict, ok := value.(map[string]interface{})
+	if !ok {
+		return value, nil
+	}
+	out := make(map[string]interface{}, len(dict))
+	for k, v := range dict {
+		out[k] = v
+	}
+	// Walk the struct and its parents, naming each field after the struct that declares it
+	for name := structName; name != ""; {
+		structDef := FindStruct(name, allStructs)
+		if structDef == nil {
+			break
+		}
+		fields, _ := structDef["fields"].([]interface{})
+		for _, f := range fields {
+			field, _ := f.(map[string]interface{})
+			fieldName, _ := field["name"].(string)
+			fieldValue, exists := out[fieldName]
+			if !exists || fieldValue == nil {
+				continue
+			}
+			var converted interface{}
+			var err error
+			if encrypted, _ := field["encrypted"].(bool); encrypted {
+				converted, err = fn(name+"."+fieldName, fieldValue)
+			} else {
+				fieldType, _ := field["type"].(map[string]interface{})
+				converted, err = transformFields(fieldValue, fieldType, allStructs, fn)
+			}
+			if err != nil {
+				return nil, err
+			}
+			out[fieldName] = converted
+		}
+		name, _ = structDef["extends"].(string)
+	}
+	return out, nil
+}
@@ -0,0 +1,120 @@
+package pulserpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+)
+
+// FaultConfig describes the faults a server injects into its calls, so client
+// retry, timeout and error handling can be tested against a slow or unreliable
+// server. It is read from a JSON file such as:
+//
+//	{
+//	  "seed": 7,
+//	  "methods": {
+//	    "*": {"latencyMs": 20},
+//	    "Catalog.getProduct": {"latencyMs": 200, "jitterMs": 100, "errorRate": 0.1, "malformedRate": 0.05}
+//	  }
+//	}
+//
+// Methods are keyed by the name the client sends, and "*" applies to methods
+// without an entry of their own.
+type FaultConfig struct {
+	// Seed makes the injected faults repeatable; 0 seeds from the clock
+	Seed    int64                   `json:"seed"`
+	Methods map[string]MethodFaults `json:"methods"`
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// MethodFaults are the faults injected into the calls of one method
+type MethodFaults struct {
+	// LatencyMs delays every call by this many milliseconds
+	LatencyMs int `json:"latencyMs"`
+	// JitterMs adds a random delay of up to this many milliseconds
+	JitterMs int `json:"jitterMs"`
+	// ErrorRate is the probability, from 0 to 1, that a call fails without reaching its handler
+	ErrorRate float64 `json:"errorRate"`
+	// ErrorCode is the JSON-RPC error code of injected errors, -32603 if 0
+	ErrorCode int `json:"errorCode"`
+	// ErrorMessage is the message of injected errors, "Injected fault" if empty
+	ErrorMessage string `json:"errorMessage"`
+	// MalformedRate is the probability, from 0 to 1, that a response is cut short so it is not valid JSON
+	MalformedRate float64 `json:"malformedRate"`
+}
+
+// Fault is what happens to one call
+type Fault struct {
+	Delay time.Duration
+	// Error is returned instead of calling the handler, or nil
+	Error *RPCError
+	// Malformed means the encoded response is cut short
+	Malformed bool
+}
+
+// LoadFaultConfig reads a FaultConfig from a JSON file. Unknown keys, negative
+// delays and rates outside 0 to 1 are errors.
+func LoadFaultConfig(path string) (*FaultConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fault config: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	config := &FaultConfig{}
+	if err := dec.Decode(config); err != nil {
+		return nil, fmt.Errorf("invalid fault config %s: %w", path, err)
+	}
+	for method, f := range config.Methods {
+		switch {
+		case f.LatencyMs < 0 || f.JitterMs < 0:
+			return nil, fmt.Errorf("invalid fault config %s: %s: latencyMs and jitterMs must not be negative", path, method)
+		case f.ErrorRate < 0 || f.ErrorRate > 1 || f.MalformedRate < 0 || f.MalformedRate > 1:
+			return nil, fmt.Errorf("invalid fault config %s: %s: errorRate and malformedRate must be between 0 and 1", path, method)
+		}
+	}
+	return config, nil
+}
+
+// Draw picks the fault for one call of method. It is safe for concurrent use.
+func (c *FaultConfig) Draw(method string) Fault {
+	f, ok := c.Methods[method]
+	if !ok {
+		if f, ok = c.Methods["*"]; !ok {
+			return Fault{}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rng == nil {
+		seed := c.Seed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		c.rng = rand.New(rand.NewSource(seed))
+	}
+	fault := Fault{Delay: time.Duration(f.LatencyMs) * time.Millisecond}
+	if f.JitterMs > 0 {
+		fault.Delay += time.Duration(c.rng.Intn(f.JitterMs+1)) * time.Millisecond
+	}
+	if f.ErrorRate > 0 && c.rng.Float64() < f.ErrorRate {
+		code, message := f.ErrorCode, f.ErrorMessage
+		if code == 0 {
+			code = -32603
+		}
+		if message == "" {
+			message = "Injected fault"
+		}
+		fault.Error = NewRPCError(code, message)
+	} else if f.MalformedRate > 0 && c.rng.Float64() < f.MalformedRate {
+		fault.Malformed = true
+	}
+	return fault
+}
@@ -0,0 +1,44 @@
+package pulserpc
+
+import "sync"
+
+// InFlight deduplicates identical calls that are in flight at the same time:
+// while one caller runs the call for a key, later callers with the same key
+// wait for it and share its result instead of running the call again. Keys are
+// typically RequestHash values. The zero value is ready to use.
+type InFlight struct {
+	mu    sync.Mutex
+	calls map[string]*inFlightCall
+}
+
+type inFlightCall struct {
+	done  chan struct{}
+	value interface{}
+}
+
+// Do runs fn for key unless a call for key is already running, in which case it
+// waits for that call and returns its result. shared reports whether the result
+// came from another caller's call.
+func (g *InFlight) Do(key string, fn func() interface{}) (value interface{}, shared bool) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-call.done
+		return call.value, true
+	}
+	if g.calls == nil {
+		g.calls = make(map[string]*inFlightCall)
+	}
+	call := &inFlightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+	call.value = fn()
+	return call.value, false
+}
@@ -0,0 +1,431 @@
+package pulserpc
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// optionalValuer is implemented by Optional
+type optionalValuer interface {
+	optionalValue() (interface{}, bool)
+}
+
+// fieldsStruct is implemented by generated [immutable] structs. Their fields are
+// unexported, so JSONFields returns them in a struct whose fields are exported and
+// tagged as they are encoded, which JSONValue and Redact walk instead.
+type fieldsStruct interface {
+	JSONFields() interface{}
+}
+
+var (
+	optionalValuerType = reflect.TypeOf((*optionalValuer)(nil)).Elem()
+	fieldsStructType   = reflect.TypeOf((*fieldsStruct)(nil)).Elem()
+	jsonMarshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// JSONValue returns v in the form json.Unmarshal into an interface{} gives its
+// JSON encoding: maps, []interface{}, float64, string, bool and nil. Servers use
+// it to validate handler results without encoding and decoding them. Values whose
+// types have their own MarshalJSON or MarshalText, apart from Optional and
+// [immutable] structs, still take that round trip, and values that can't be encoded give nil.
+func JSONValue(v interface{}) interface{} {
+	return jsonValue(reflect.ValueOf(v))
+}
+
+func jsonValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface {
+		if v.Type().Implements(optionalValuerType) {
+			value, ok := v.Interface().(optionalValuer).optionalValue()
+			if !ok {
+				return nil
+			}
+			return jsonValue(reflect.ValueOf(value))
+		}
+		if v.Type().Implements(fieldsStructType) {
+			return jsonValue(reflect.ValueOf(v.Interface().(fieldsStruct).JSONFields()))
+		}
+		if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+			return jsonRoundTrip(v.Interface())
+		}
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Ptr && (v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType)) {
+			return jsonRoundTrip(v.Interface())
+		}
+		return jsonValue(v.Elem())
+	case reflect.Struct:
+		out := make(map[string]interface{}, v.NumField())
+		jsonStructInto(out, v)
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes())
+		}
+		fallthrough
+	case reflect.Array:
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = jsonValue(v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Key().Kind() != reflect.String && v.Type().Key().Implements(textMarshalerType) {
+			return jsonRoundTrip(v.Interface())
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key()
+			if key.Kind() == reflect.String {
+				out[key.String()] = jsonValue(iter.Value())
+			} else {
+				out[fmt.Sprint(key.Interface())] = jsonValue(iter.Value())
+			}
+		}
+		return out
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return nil
+}
+
+// jsonStructInto adds the fields of struct v to out, named and omitted as
+// encoding/json does. Embedded structs, which generated code uses for
+// inheritance, are flattened.
+func jsonStructInto(out map[string]interface{}, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get("json") == "" {
+			jsonStructInto(out, v.Field(i))
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fv := v.Field(i)
+		if hasTagOption(opts, "omitempty") && isEmptyJSONValue(fv) {
+			continue
+		}
+		if hasTagOption(opts, "omitzero") {
+			if z, ok := fv.Interface().(interface{ IsZero() bool }); ok {
+				if z.IsZero() {
+					continue
+				}
+			} else if fv.IsZero() {
+				continue
+			}
+		}
+		if hasTagOption(opts, "string") {
+			out[name] = jsonRoundTrip(fv.Interface())
+			continue
+		}
+		out[name] = jsonValue(fv)
+	}
+}
+
+// hasTagOption reports whether the comma-separated json tag options include option
+func hasTagOption(opts string, option string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyJSONValue reports whether omitempty leaves out v
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Ptr:
+		return v.IsZero()
+	}
+	return false
+}
+
+// jsonRoundTrip encodes v and decodes it into an interface{}
+func jsonRoundTrip(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// DecodeJSONValue stores v, a value in the form JSONValue returns, in the value
+// dst points to, as json.Unmarshal would store its encoding. Servers use it to
+// turn validated params into handler arguments without encoding them again.
+// Types with their own UnmarshalJSON or UnmarshalText, such as Optional, are
+// still given the encoding of their value.
+func DecodeJSONValue(v interface{}, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("DecodeJSONValue: dst must be a non-nil pointer, got %T", dst)
+	}
+	return decodeJSONValue(v, rv.Elem())
+}
+
+func decodeJSONValue(v interface{}, dst reflect.Value) error {
+	if dst.Kind() != reflect.Ptr && dst.CanAddr() {
+		if pt := dst.Addr().Type(); pt.Implements(jsonUnmarshalerType) || pt.Implements(textUnmarshalerType) {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal(data, dst.Addr().Interface())
+		}
+	}
+	if v == nil {
+		// null leaves non-pointer values unchanged, as with json.Unmarshal
+		switch dst.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			dst.Set(reflect.Zero(dst.Type()))
+		}
+		return nil
+	}
+	switch dst.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(dst.Type().Elem())
+		if err := decodeJSONValue(v, elem.Elem()); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	case reflect.Interface:
+		if dst.NumMethod() != 0 {
+			return jsonTypeError(v, dst.Type())
+		}
+		dst.Set(reflect.ValueOf(v))
+		return nil
+	case reflect.Struct:
+		fields, ok := v.(map[string]interface{})
+		if !ok {
+			return jsonTypeError(v, dst.Type())
+		}
+		return decodeJSONStruct(fields, dst)
+	case reflect.Slice:
+		if s, ok := v.(string); ok && dst.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return err
+			}
+			dst.SetBytes(b)
+			return nil
+		}
+		items, ok := v.([]interface{})
+		if !ok {
+			return jsonTypeError(v, dst.Type())
+		}
+		slice := reflect.MakeSlice(dst.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeJSONValue(item, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(slice)
+		return nil
+	case reflect.Array:
+		items, ok := v.([]interface{})
+		if !ok {
+			return jsonTypeError(v, dst.Type())
+		}
+		for i := 0; i < dst.Len(); i++ {
+			if i >= len(items) {
+				dst.Index(i).Set(reflect.Zero(dst.Type().Elem()))
+				continue
+			}
+			if err := decodeJSONValue(items[i], dst.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		entries, ok := v.(map[string]interface{})
+		if !ok {
+			return jsonTypeError(v, dst.Type())
+		}
+		if dst.Type().Key().Kind() != reflect.String {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal(data, dst.Addr().Interface())
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(dst.Type(), len(entries)))
+		}
+		for key, entry := range entries {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := decodeJSONValue(entry, elem); err != nil {
+				return err
+			}
+			dst.SetMapIndex(reflect.ValueOf(key).Convert(dst.Type().Key()), elem)
+		}
+		return nil
+	case reflect.String:
+		s, ok := v.(string)
+		if !ok {
+			return jsonTypeError(v, dst.Type())
+		}
+		dst.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return jsonTypeError(v, dst.Type())
+		}
+		dst.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f, ok := v.(float64)
+		if !ok || f != float64(int64(f)) || dst.OverflowInt(int64(f)) {
+			return jsonTypeError(v, dst.Type())
+		}
+		dst.SetInt(int64(f))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f, ok := v.(float64)
+		if !ok || f < 0 || f != float64(uint64(f)) || dst.OverflowUint(uint64(f)) {
+			return jsonTypeError(v, dst.Type())
+		}
+		dst.SetUint(uint64(f))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, ok := v.(float64)
+		if !ok || dst.OverflowFloat(f) {
+			return jsonTypeError(v, dst.Type())
+		}
+		dst.SetFloat(f)
+		return nil
+	}
+	return jsonTypeError(v, dst.Type())
+}
+
+// decodeJSONStruct stores the members of a JSON object in the fields of struct
+// dst. Members are matched to field names exactly, then case-insensitively as
+// encoding/json does, and members without a field are ignored.
+func decodeJSONStruct(members map[string]interface{}, dst reflect.Value) error {
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get("json") == "" {
+			if err := decodeJSONStruct(members, dst.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		value, ok := members[name]
+		if !ok {
+			for key, member := range members {
+				if strings.EqualFold(key, name) {
+					value, ok = member, true
+					break
+				}
+			}
+			if !ok {
+				continue
+			}
+		}
+		if hasTagOption(opts, "string") {
+			data, err := json.Marshal(map[string]interface{}{name: value})
+			if err != nil {
+				return err
+			}
+			holder := reflect.New(reflect.StructOf([]reflect.StructField{{Name: "V", Type: f.Type, Tag: f.Tag}}))
+			if err := json.Unmarshal(data, holder.Interface()); err != nil {
+				return err
+			}
+			dst.Field(i).Set(holder.Elem().Field(0))
+			continue
+		}
+		if err := decodeJSONValue(value, dst.Field(i)); err != nil {
+			return fmt.Errorf("field %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// jsonTypeError describes a JSON value that doesn't fit a Go type the way
+// json.Unmarshal does
+func jsonTypeError(v interface{}, t reflect.Type) error {
+	kind := "object"
+	switch v := v.(type) {
+	case string:
+		kind = "string"
+	case bool:
+		kind = "bool"
+	case float64:
+		kind = "number " + strconv.FormatFloat(v, 'g', -1, 64)
+	case []interface{}:
+		kind = "array"
+	default:
+		switch reflect.ValueOf(v).Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32:
+			kind = fmt.Sprintf("number %v", v)
+		}
+	}
+	return &json.UnmarshalTypeError{Value: kind, Type: t}
+}
@@ -0,0 +1,30 @@
+package pulserpc
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestMeta describes the HTTP request a call arrived in. Servers add it to the
+// context handlers receive, so a handler can read the caller's headers, such as an
+// authorization token or a trace id, and its address. Handlers must not modify
+// Headers.
+type RequestMeta struct {
+	Headers    http.Header
+	RemoteAddr string
+}
+
+type requestMetaKey struct{}
+
+// WithRequestMeta returns a copy of ctx that carries meta
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// RequestMetaFromContext returns the RequestMeta of the call ctx belongs to. ok is
+// false for calls that did not arrive over HTTP, such as broker messages and
+// HandleMessage.
+func RequestMetaFromContext(ctx context.Context) (meta RequestMeta, ok bool) {
+	meta, ok = ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta, ok
+}
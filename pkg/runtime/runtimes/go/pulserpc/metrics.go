@@ -0,0 +1,72 @@
+package pulserpc
+
+import (
+	"sync"
+	"time"
+)
+
+// MethodMetrics counts the calls of each method of a server and how long their
+// handlers took, for the admin endpoint. Only the methods it was created with
+// are counted, so calls to unknown methods cannot grow it.
+type MethodMetrics struct {
+	started time.Time
+	mu      sync.Mutex
+	methods map[string]*MethodMetric
+}
+
+// MethodMetric summarizes the calls of one method
+type MethodMetric struct {
+	Calls int64 `json:"calls"`
+	// Errors counts the calls whose handler returned an error
+	Errors int64 `json:"errors"`
+	// MeanMs and MaxMs are the mean and longest handler time in milliseconds
+	MeanMs float64 `json:"meanMs"`
+	MaxMs  float64 `json:"maxMs"`
+
+	totalMs float64
+}
+
+// NewMethodMetrics creates a MethodMetrics for methods ("Interface.method"), starting now
+func NewMethodMetrics(methods []string) *MethodMetrics {
+	m := &MethodMetrics{started: time.Now(), methods: make(map[string]*MethodMetric, len(methods))}
+	for _, method := range methods {
+		m.methods[method] = &MethodMetric{}
+	}
+	return m
+}
+
+// Started returns the time the metrics started counting
+func (m *MethodMetrics) Started() time.Time {
+	return m.started
+}
+
+// Record counts one call of method whose handler took elapsed
+func (m *MethodMetrics) Record(method string, elapsed time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metric, ok := m.methods[method]
+	if !ok {
+		return
+	}
+	ms := float64(elapsed) / float64(time.Millisecond)
+	metric.Calls++
+	if failed {
+		metric.Errors++
+	}
+	metric.totalMs += ms
+	metric.MeanMs = metric.totalMs / float64(metric.Calls)
+	if ms > metric.MaxMs {
+		metric.MaxMs = ms
+	}
+}
+
+// Snapshot returns a copy of the metrics of every method, keyed by method
+func (m *MethodMetrics) Snapshot() map[string]MethodMetric {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make(map[string]MethodMetric, len(m.methods))
+	for method, metric := range m.methods {
+		snapshot[method] = *metric
+	}
+	return snapshot
+}
@@ -0,0 +1,102 @@
+package pulserpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// NumberPolicy controls whether a server accepts an int param written with a fraction
+// or exponent, such as 2.0. A float accepts any number under either policy, and an int
+// with a fractional part, such as 2.5, is always rejected.
+type NumberPolicy int
+
+const (
+	// LenientNumbers accepts any number with no fractional part for int, so 2.0 is
+	// taken as 2. It is the default.
+	LenientNumbers NumberPolicy = iota
+	// StrictNumbers accepts only integer literals, such as 2, for int
+	StrictNumbers
+)
+
+type requestLiteralsKey struct{}
+
+// WithRequestLiterals returns ctx carrying the params of data, a JSON-RPC request,
+// with their numbers kept as written. Servers with StrictNumbers add them for
+// CheckRequestIntLiterals. ctx is returned as is if data is not a request object.
+func WithRequestLiterals(ctx context.Context, data []byte) context.Context {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var request map[string]interface{}
+	if err := decoder.Decode(&request); err != nil || request == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, requestLiteralsKey{}, request["params"])
+}
+
+// CheckRequestIntLiterals returns an error naming the first param value of type int
+// that is written with a fraction or exponent, if ctx carries params added by
+// WithRequestLiterals. Params may be sent by position or by name.
+func CheckRequestIntLiterals(ctx context.Context, expectedParams []interface{}, allStructs StructMap) error {
+	params := ctx.Value(requestLiteralsKey{})
+	for i, p := range expectedParams {
+		paramDef, _ := p.(map[string]interface{})
+		paramName, _ := paramDef["name"].(string)
+		paramType, _ := paramDef["type"].(map[string]interface{})
+		var value interface{}
+		switch v := params.(type) {
+		case []interface{}:
+			if i < len(v) {
+				value = v[i]
+			}
+		case map[string]interface{}:
+			value = v[paramName]
+		}
+		if err := CheckIntLiterals(value, paramType, allStructs); err != nil {
+			return fmt.Errorf("Parameter %d (%s) validation failed: %w", i, paramName, err)
+		}
+	}
+	return nil
+}
+
+// CheckIntLiterals returns an error if a value of type int in value, decoded with
+// json.Decoder.UseNumber, is written with a fraction or exponent. Values that are not
+// json.Number are left to ValidateType.
+func CheckIntLiterals(value interface{}, typeDef map[string]interface{}, allStructs StructMap) error {
+	switch v := value.(type) {
+	case json.Number:
+		if typeDef["builtIn"] == "int" && strings.ContainsAny(v.String(), ".eE") {
+			return fmt.Errorf("expected int, got %s", v)
+		}
+	case []interface{}:
+		elemType, ok := typeDef["array"].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for i, elem := range v {
+			if err := CheckIntLiterals(elem, elemType, allStructs); err != nil {
+				return fmt.Errorf("array element at index %d validation failed: %w", i, err)
+			}
+		}
+	case map[string]interface{}:
+		if valueType, ok := typeDef["mapValue"].(map[string]interface{}); ok {
+			for k, elem := range v {
+				if err := CheckIntLiterals(elem, valueType, allStructs); err != nil {
+					return fmt.Errorf("map value for key '%s' validation failed: %w", k, err)
+				}
+			}
+			return nil
+		}
+		structName, _ := typeDef["userDefined"].(string)
+		for _, field := range GetStructFields(structName, allStructs) {
+			fieldName, _ := field["name"].(string)
+			fieldType, _ := field["type"].(map[string]interface{})
+			if err := CheckIntLiterals(v[fieldName], fieldType, allStructs); err != nil {
+				return fmt.Errorf("field '%s' in struct %s validation failed: %w", fieldName, structName, err)
+			}
+		}
+	}
+	return nil
+}
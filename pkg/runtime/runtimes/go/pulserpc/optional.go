@@ -0,0 +1,66 @@
+package pulserpc
+
+import "encoding/json"
+
+// Optional is the type of an optional struct field generated with
+// -optional-presence. Unlike a pointer it tells a field that was left out from
+// one set to an explicit null, which patch-style APIs use to clear a value. The
+// zero value is absent; fields are tagged omitzero so that absent fields are not
+// encoded (Go 1.24 or later).
+type Optional[T any] struct {
+	Value T
+	// Present is false when the field is absent
+	Present bool
+	// Null is true when the field is present and set to null
+	Null bool
+}
+
+// Some returns a present Optional holding v
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Value: v, Present: true}
+}
+
+// Null returns a present Optional set to an explicit null
+func Null[T any]() Optional[T] {
+	return Optional[T]{Present: true, Null: true}
+}
+
+// Get returns the value and whether the field holds one, that is, whether it is
+// neither absent nor null
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Present && !o.Null
+}
+
+// IsZero reports whether the field is absent, for the omitzero tag option
+func (o Optional[T]) IsZero() bool {
+	return !o.Present
+}
+
+// MarshalJSON encodes the value, or null when the field is null
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Present || o.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+// UnmarshalJSON decodes a present field. encoding/json only calls it for keys
+// found in the input, so fields missing from the input stay absent.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*o = Null[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*o = Some(v)
+	return nil
+}
+
+// optionalValue lets Redact see through an Optional
+func (o Optional[T]) optionalValue() (interface{}, bool) {
+	v, ok := o.Get()
+	return v, ok
+}
@@ -0,0 +1,159 @@
+package pulserpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Redacted replaces the value of a [sensitive] field in redacted output
+const Redacted = "***"
+
+// Redact returns a JSON-shaped copy of v in which every non-null struct field
+// tagged pulse:"sensitive" holds Redacted, for logging. Structs become maps keyed by
+// their JSON field names, so the result marshals like v apart from the masked
+// values.
+func Redact(v interface{}) interface{} {
+	return redactValue(reflect.ValueOf(v))
+}
+
+// RedactedString returns the JSON encoding of Redact(v). Generated structs with
+// sensitive fields use it as their String method.
+func RedactedString(v interface{}) string {
+	b, err := json.Marshal(Redact(v))
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	return string(b)
+}
+
+func redactValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem())
+	case reflect.Struct:
+		if o, ok := v.Interface().(interface{ optionalValue() (interface{}, bool) }); ok {
+			value, ok := o.optionalValue()
+			if !ok {
+				return nil
+			}
+			return redactValue(reflect.ValueOf(value))
+		}
+		if f, ok := v.Interface().(fieldsStruct); ok {
+			return redactValue(reflect.ValueOf(f.JSONFields()))
+		}
+		out := make(map[string]interface{})
+		redactStructInto(out, v)
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		fallthrough
+	case reflect.Array:
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = redactValue(v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = redactValue(iter.Value())
+		}
+		return out
+	}
+	return v.Interface()
+}
+
+// redactStructInto adds the fields of struct v to out. Embedded structs, which
+// generated code uses for inheritance, are flattened as encoding/json does.
+func redactStructInto(out map[string]interface{}, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			redactStructInto(out, v.Field(i))
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fv := v.Field(i)
+		if strings.Contains(opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+		if z, ok := fv.Interface().(interface{ IsZero() bool }); ok && strings.Contains(opts, "omitzero") && z.IsZero() {
+			continue
+		}
+		value := redactValue(fv)
+		if value != nil && f.Tag.Get("pulse") == "sensitive" {
+			value = Redacted
+		}
+		out[name] = value
+	}
+}
+
+// RedactJSON returns a copy of a decoded JSON value of the given type in which every
+// non-null [sensitive] struct field holds Redacted, for logging values that are not
+// held in generated structs, such as raw requests and responses
+func RedactJSON(value interface{}, typeDef map[string]interface{}, allStructs StructMap) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		elemType, ok := typeDef["array"].(map[string]interface{})
+		if !ok {
+			return v
+		}
+		out := make([]interface{}, len(v))
+		for i, elem := range v {
+			out[i] = RedactJSON(elem, elemType, allStructs)
+		}
+		return out
+	case map[string]interface{}:
+		if valueType, ok := typeDef["mapValue"].(map[string]interface{}); ok {
+			out := make(map[string]interface{}, len(v))
+			for k, elem := range v {
+				out[k] = RedactJSON(elem, valueType, allStructs)
+			}
+			return out
+		}
+		structName, _ := typeDef["userDefined"].(string)
+		if FindStruct(structName, allStructs) == nil {
+			return v
+		}
+		out := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			out[k] = elem
+		}
+		for _, field := range GetStructFields(structName, allStructs) {
+			name, _ := field["name"].(string)
+			if out[name] == nil {
+				continue
+			}
+			if sensitive, _ := field["sensitive"].(bool); sensitive {
+				out[name] = Redacted
+			} else if fieldType, ok := field["type"].(map[string]interface{}); ok {
+				out[name] = RedactJSON(out[name], fieldType, allStructs)
+			}
+		}
+		return out
+	}
+	return value
+}
@@ -2,6 +2,7 @@ package pulserpc
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 )
 
@@ -16,8 +17,12 @@ func ValidateString(value interface{}) error {
 // ValidateInt validates that value is an int
 func ValidateInt(value interface{}) error {
 	if _, ok := value.(int); !ok {
-		if _, ok := value.(float64); ok {
+		if f, ok := value.(float64); ok {
 			// JSON numbers are decoded as float64, but we accept them for int
+			// as long as they have no fractional part
+			if f != math.Trunc(f) {
+				return fmt.Errorf("expected int, got %v", f)
+			}
 			return nil
 		}
 		return fmt.Errorf("expected int, got %T", value)
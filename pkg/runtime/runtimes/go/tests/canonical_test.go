@@ -0,0 +1,156 @@
+package main
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pulserpc-go-runtime/pulserpc"
+)
+
+// The same vectors are checked by every other runtime's tests, so every runtime
+// hashes a call the same way, byte for byte
+var requestHashVectors = []struct {
+	method    string
+	params    interface{}
+	canonical string
+	hash      string
+}{
+	{
+		method:    "Catalog.get",
+		params:    []interface{}{"p-1", 2},
+		canonical: `{"method":"Catalog.get","params":["p-1",2]}`,
+		hash:      "c83d6e2a3e0908ddb5e94fe05cffd450756e92caab52d017ad51be7b97fcb715",
+	},
+	{
+		method: "Catalog.search",
+		params: []interface{}{
+			map[string]interface{}{"query": "café \"x\"\n", "limit": 10, "tags": []string{"a", "b"}, "price": map[string]float64{"max": 99.5, "min": 0.1}},
+			nil,
+		},
+		canonical: `{"method":"Catalog.search","params":[{"limit":10,"price":{"max":99.5,"min":0.1},"query":"café \"x\"\n","tags":["a","b"]},null]}`,
+		hash:      "bac87c8eb687a2e88fb49b08c30f0668d849c06613310acd110c66d42acc56ae",
+	},
+	{
+		method:    "Catalog.bulk",
+		params:    map[string]interface{}{"ids": []float64{1e21, 1e-7, math.Copysign(0, -1), 1 << 60}, "é": true, "a": false, "😀": 1, "｡": 2},
+		canonical: `{"method":"Catalog.bulk","params":{"a":false,"ids":[1e+21,1e-7,0,1152921504606847000],"é":true,"😀":1,"｡":2}}`,
+		hash:      "53bd8c11499fccf79fedaff23d160964bba118c23421f68f3167c40eb2a7750b",
+	},
+	{
+		// 2^53+1 is not a double: canonical JSON writes the nearest one, 2^53
+		method:    "Catalog.edges",
+		params:    []interface{}{1e21, 1e-7, math.Copysign(0, -1), int64(1<<53 + 1)},
+		canonical: `{"method":"Catalog.edges","params":[1e+21,1e-7,0,9007199254740992]}`,
+		hash:      "379f1ac102b547987989aaba1ce4f935c28a1d5b880068e36ffd2b017f2f98e5",
+	},
+}
+
+func TestRequestHashVectors(t *testing.T) {
+	for _, v := range requestHashVectors {
+		canonical, err := pulserpc.CanonicalJSON(map[string]interface{}{"method": v.method, "params": v.params})
+		if err != nil {
+			t.Fatalf("%s: CanonicalJSON failed: %v", v.method, err)
+		}
+		if string(canonical) != v.canonical {
+			t.Errorf("%s: canonical JSON\n got %s\nwant %s", v.method, canonical, v.canonical)
+		}
+		hash, err := pulserpc.RequestHash(v.method, v.params)
+		if err != nil {
+			t.Fatalf("%s: RequestHash failed: %v", v.method, err)
+		}
+		if hash != v.hash {
+			t.Errorf("%s: hash = %s, want %s", v.method, hash, v.hash)
+		}
+	}
+}
+
+func TestCanonicalJSONNumbers(t *testing.T) {
+	tests := map[float64]string{
+		0:        "0",
+		-1.5:     "-1.5",
+		123.456:  "123.456",
+		1e20:     "100000000000000000000",
+		1e-6:     "0.000001",
+		1.5e300:  "1.5e+300",
+		5e-324:   "5e-324",
+		-2.5e-10: "-2.5e-10",
+	}
+	for f, want := range tests {
+		got, err := pulserpc.CanonicalJSON(f)
+		if err != nil {
+			t.Fatalf("%v: CanonicalJSON failed: %v", f, err)
+		}
+		if string(got) != want {
+			t.Errorf("%v: got %s, want %s", f, got, want)
+		}
+	}
+}
+
+func TestCanonicalJSONStructs(t *testing.T) {
+	type item struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	got, err := pulserpc.CanonicalJSON(item{Name: "<b>", Count: 3})
+	if err != nil {
+		t.Fatalf("CanonicalJSON failed: %v", err)
+	}
+	if string(got) != `{"count":3,"name":"<b>"}` {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestInFlightSharesResult(t *testing.T) {
+	var group pulserpc.InFlight
+	var runs int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 3)
+	shared := make([]bool, 3)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], shared[0] = group.Do("k", func() interface{} {
+			atomic.AddInt32(&runs, 1)
+			close(started)
+			<-release
+			return "value"
+		})
+	}()
+	<-started
+	for i := 1; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], shared[i] = group.Do("k", func() interface{} {
+				atomic.AddInt32(&runs, 1)
+				return "other"
+			})
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if runs != 1 {
+		t.Errorf("expected one run, got %d", runs)
+	}
+	for i, result := range results {
+		if result != "value" {
+			t.Errorf("caller %d got %v", i, result)
+		}
+	}
+	if shared[0] || !shared[1] || !shared[2] {
+		t.Errorf("unexpected shared flags %v", shared)
+	}
+
+	// Once the call is done, the key runs again
+	if value, wasShared := group.Do("k", func() interface{} { return "again" }); value != "again" || wasShared {
+		t.Errorf("expected a new run, got %v (shared %v)", value, wasShared)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"pulserpc-go-runtime/pulserpc"
+)
+
+// stubServer handles the methods it lists and answers every call with its name
+type stubServer struct {
+	name    string
+	methods map[string]bool
+	idl     map[string]interface{}
+}
+
+func (s *stubServer) Handles(method string) bool {
+	return s.methods[method]
+}
+
+func (s *stubServer) HandleRequestContext(ctx context.Context, request map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"jsonrpc": "2.0", "id": request["id"], "result": s.name}
+}
+
+func (s *stubServer) IDLDocument() (map[string]interface{}, error) {
+	return s.idl, nil
+}
+
+func TestCompositionFind(t *testing.T) {
+	var c pulserpc.Composition
+	if c.Find("Users.get") != nil {
+		t.Fatalf("an empty composition should find no server")
+	}
+	users := &stubServer{name: "users", methods: map[string]bool{"Users.get": true}}
+	shadow := &stubServer{name: "shadow", methods: map[string]bool{"Users.get": true, "Orders.list": true}}
+	c.Add(users)
+	c.Add(shadow)
+	if got := c.Find("Users.get"); got != users {
+		t.Errorf("expected the first server that handles the method, got %v", got)
+	}
+	if got := c.Find("Orders.list"); got != shadow {
+		t.Errorf("expected the shadow server, got %v", got)
+	}
+	if got := c.Find("Nope.x"); got != nil {
+		t.Errorf("expected no server, got %v", got)
+	}
+}
+
+func TestCompositionRoute(t *testing.T) {
+	var c pulserpc.Composition
+	var paths []string
+	record := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, name+" "+r.URL.Path)
+		})
+	}
+	c.Mount("/billing/", record("billing"))
+	c.Mount("/billing/admin", record("admin"))
+
+	for _, path := range []string{"/billing", "/billing/x", "/billing/admin/stats", "/billingx", "/"} {
+		r := httptest.NewRequest(http.MethodPost, path, nil)
+		if handler, routed, ok := c.Route(r); ok {
+			handler.ServeHTTP(httptest.NewRecorder(), routed)
+		}
+	}
+	want := []string{"billing /", "billing /x", "admin /stats"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("expected %v, got %v", want, paths)
+	}
+}
+
+func TestMergeIDLDocuments(t *testing.T) {
+	shared := map[string]interface{}{"name": "Money"}
+	billing := map[string]interface{}{
+		"rootNamespace": "billing",
+		"interfaces":    []interface{}{map[string]interface{}{"name": "Invoices"}},
+		"structs":       []interface{}{shared},
+	}
+	users := map[string]interface{}{
+		"rootNamespace": "users",
+		"interfaces":    []interface{}{map[string]interface{}{"name": "Users"}},
+		"structs":       []interface{}{shared, map[string]interface{}{"name": "User"}},
+		"enums":         []interface{}{map[string]interface{}{"name": "Role"}},
+	}
+
+	merged := pulserpc.MergeIDLDocuments(billing, users)
+	if merged["rootNamespace"] != "billing" {
+		t.Errorf("expected the first rootNamespace, got %v", merged["rootNamespace"])
+	}
+	names := func(section string) []string {
+		var result []string
+		list, _ := merged[section].([]interface{})
+		for _, element := range list {
+			result = append(result, element.(map[string]interface{})["name"].(string))
+		}
+		return result
+	}
+	for section, want := range map[string][]string{
+		"interfaces": {"Invoices", "Users"},
+		"structs":    {"Money", "User"},
+		"enums":      {"Role"},
+	} {
+		if got := names(section); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %v, got %v", section, want, got)
+		}
+	}
+	if _, ok := merged["typedefs"]; ok {
+		t.Errorf("expected no typedefs section when no document has one")
+	}
+
+	var c pulserpc.Composition
+	c.Add(&stubServer{idl: users})
+	doc, err := c.MergeIDL(billing)
+	if err != nil || !reflect.DeepEqual(doc, merged) {
+		t.Errorf("MergeIDL should merge the composed documents, got %v, %v", doc, err)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"pulserpc-go-runtime/pulserpc"
+)
+
+func TestRequestContext(t *testing.T) {
+	r, _ := http.NewRequest("POST", "http://localhost/", nil)
+	ctx, cancel := pulserpc.RequestContext(r)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Errorf("expected no deadline without the header")
+	}
+
+	r.Header.Set(pulserpc.DeadlineHeader, pulserpc.DeadlineHeaderValue(5*time.Second))
+	ctx, cancel = pulserpc.RequestContext(r)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	if remaining := time.Until(deadline); !ok || remaining <= 4*time.Second || remaining > 5*time.Second {
+		t.Errorf("expected a deadline about 5s away, got %v", remaining)
+	}
+
+	// A budget that has run out gives a context that is already done
+	r.Header.Set(pulserpc.DeadlineHeader, "-10")
+	ctx, cancel = pulserpc.RequestContext(r)
+	defer cancel()
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Errorf("expected an expired context, got %v", ctx.Err())
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pulserpc-go-runtime/pulserpc"
+)
+
+func writeFaultConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "faults.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write fault config: %v", err)
+	}
+	return path
+}
+
+func TestFaultConfigDraw(t *testing.T) {
+	path := writeFaultConfig(t, `{
+		"seed": 7,
+		"methods": {
+			"*": {"latencyMs": 20, "jitterMs": 10},
+			"Catalog.fail": {"errorRate": 1, "errorCode": -32050, "errorMessage": "Catalog down"},
+			"Catalog.garble": {"malformedRate": 1}
+		}
+	}`)
+	config, err := pulserpc.LoadFaultConfig(path)
+	if err != nil {
+		t.Fatalf("LoadFaultConfig failed: %v", err)
+	}
+
+	fault := config.Draw("Catalog.list")
+	if fault.Delay < 20*time.Millisecond || fault.Delay > 30*time.Millisecond {
+		t.Errorf("expected a delay of 20-30ms, got %v", fault.Delay)
+	}
+	if fault.Error != nil || fault.Malformed {
+		t.Errorf("expected only latency, got %+v", fault)
+	}
+
+	// A method's own entry replaces "*"
+	fault = config.Draw("Catalog.fail")
+	if fault.Delay != 0 {
+		t.Errorf("expected no delay, got %v", fault.Delay)
+	}
+	if fault.Error == nil || fault.Error.Code != -32050 || fault.Error.Message != "Catalog down" {
+		t.Errorf("expected the configured error, got %+v", fault.Error)
+	}
+
+	if fault = config.Draw("Catalog.garble"); !fault.Malformed {
+		t.Error("expected a malformed response")
+	}
+}
+
+func TestFaultConfigDefaultsAndSeed(t *testing.T) {
+	path := writeFaultConfig(t, `{"seed": 42, "methods": {"*": {"errorRate": 0.5, "jitterMs": 100}}}`)
+	draws := func() []pulserpc.Fault {
+		config, err := pulserpc.LoadFaultConfig(path)
+		if err != nil {
+			t.Fatalf("LoadFaultConfig failed: %v", err)
+		}
+		faults := make([]pulserpc.Fault, 20)
+		for i := range faults {
+			faults[i] = config.Draw("Any.method")
+		}
+		return faults
+	}
+	first, second := draws(), draws()
+	errors := 0
+	for i := range first {
+		if first[i].Delay != second[i].Delay || (first[i].Error == nil) != (second[i].Error == nil) {
+			t.Fatalf("draw %d differs with the same seed: %+v vs %+v", i, first[i], second[i])
+		}
+		if first[i].Error != nil {
+			errors++
+			if first[i].Error.Code != -32603 || first[i].Error.Message != "Injected fault" {
+				t.Errorf("expected the default error, got %+v", first[i].Error)
+			}
+		}
+	}
+	if errors == 0 || errors == len(first) {
+		t.Errorf("expected some of %d draws to fail with errorRate 0.5, got %d", len(first), errors)
+	}
+}
+
+func TestLoadFaultConfigInvalid(t *testing.T) {
+	tests := []struct {
+		config string
+		want   string
+	}{
+		{`{"methods": {"*": {"errorRate": 1.5}}}`, "errorRate and malformedRate must be between 0 and 1"},
+		{`{"methods": {"*": {"latencyMs": -1}}}`, "latencyMs and jitterMs must not be negative"},
+		{`{"methods": {"*": {"latency": 10}}}`, "unknown field"},
+	}
+	for _, tt := range tests {
+		_, err := pulserpc.LoadFaultConfig(writeFaultConfig(t, tt.config))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.config, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"pulserpc-go-runtime/pulserpc"
+)
+
+type Status string
+
+type Base struct {
+	ID string `json:"id"`
+}
+
+type Item struct {
+	Base
+	Name     string                     `json:"name"`
+	Count    int                        `json:"count"`
+	Price    float64                    `json:"price"`
+	Status   Status                     `json:"status"`
+	Tags     []string                   `json:"tags"`
+	Attrs    map[string]int             `json:"attrs"`
+	Parent   *Item                      `json:"parent,omitempty"`
+	Note     pulserpc.Optional[string]  `json:"note,omitzero"`
+	Cleared  pulserpc.Optional[string]  `json:"cleared,omitzero"`
+	Created  time.Time                  `json:"created"`
+	Children []Item                     `json:"children"`
+	Extra    map[string]json.RawMessage `json:"extra,omitempty"`
+	hidden   string
+}
+
+func sampleItem() Item {
+	return Item{
+		Base:    Base{ID: "i1"},
+		Name:    "widget",
+		Count:   3,
+		Price:   9.5,
+		Status:  "active",
+		Tags:    []string{"a", "b"},
+		Attrs:   map[string]int{"w": 1},
+		Note:    pulserpc.Some("hello"),
+		Cleared: pulserpc.Null[string](),
+		Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Children: []Item{
+			{Base: Base{ID: "c1"}, Name: "child", Parent: &Item{Name: "p"}},
+		},
+		hidden: "x",
+	}
+}
+
+func roundTrip(v interface{}) interface{} {
+	data, _ := json.Marshal(v)
+	var out interface{}
+	json.Unmarshal(data, &out)
+	return out
+}
+
+func TestJSONValueMatchesRoundTrip(t *testing.T) {
+	values := []interface{}{
+		nil,
+		"s",
+		42,
+		uint8(7),
+		1.25,
+		true,
+		Status("active"),
+		[]int{1, 2},
+		[]string(nil),
+		[]byte("raw"),
+		map[string]interface{}{"k": []interface{}{1.0, "x"}},
+		map[int]string{1: "one"},
+		sampleItem(),
+		&[]Item{sampleItem()},
+		(*Item)(nil),
+	}
+	for _, v := range values {
+		got := pulserpc.JSONValue(v)
+		want := roundTrip(v)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("JSONValue(%#v) = %#v, want %#v", v, got, want)
+		}
+	}
+}
+
+func BenchmarkJSONValue(b *testing.B) {
+	item := sampleItem()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		pulserpc.JSONValue(item)
+	}
+}
+
+func BenchmarkJSONRoundTrip(b *testing.B) {
+	item := sampleItem()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		roundTrip(item)
+	}
+}
+
+func TestDecodeJSONValueMatchesUnmarshal(t *testing.T) {
+	item := sampleItem()
+	data, _ := json.Marshal(item)
+	var generic interface{}
+	json.Unmarshal(data, &generic)
+
+	var want, got Item
+	json.Unmarshal(data, &want)
+	if err := pulserpc.DecodeJSONValue(generic, &got); err != nil {
+		t.Fatalf("DecodeJSONValue failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeJSONValue = %#v, want %#v", got, want)
+	}
+	if got.Cleared.Present != true || got.Cleared.Null != true {
+		t.Errorf("expected an explicit null Optional, got %#v", got.Cleared)
+	}
+}
+
+func TestDecodeJSONValueTypeErrors(t *testing.T) {
+	var n int
+	if err := pulserpc.DecodeJSONValue(1.5, &n); err == nil {
+		t.Error("expected an error for a fractional number into int")
+	}
+	var s string
+	if err := pulserpc.DecodeJSONValue(1.0, &s); err == nil {
+		t.Error("expected an error for a number into string")
+	}
+	if err := pulserpc.DecodeJSONValue(20, &s); err == nil || !strings.Contains(err.Error(), "number 20") {
+		t.Errorf("expected a Go int to be described as a number, got %v", err)
+	}
+	var item Item
+	if err := pulserpc.DecodeJSONValue(map[string]interface{}{"count": "3"}, &item); err == nil {
+		t.Error("expected an error for a string into an int field")
+	}
+	if err := pulserpc.DecodeJSONValue(nil, &n); err != nil || n != 0 {
+		t.Errorf("expected null to leave int unchanged, got %d, %v", n, err)
+	}
+}
+
+func BenchmarkDecodeJSONValue(b *testing.B) {
+	generic := pulserpc.JSONValue(sampleItem())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var item Item
+		pulserpc.DecodeJSONValue(generic, &item)
+	}
+}
+
+func BenchmarkDecodeJSONRoundTrip(b *testing.B) {
+	generic := pulserpc.JSONValue(sampleItem())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var item Item
+		data, _ := json.Marshal(generic)
+		json.Unmarshal(data, &item)
+	}
+}
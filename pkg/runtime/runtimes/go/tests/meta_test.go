@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"pulserpc-go-runtime/pulserpc"
+)
+
+func TestRequestMeta(t *testing.T) {
+	if _, ok := pulserpc.RequestMetaFromContext(context.Background()); ok {
+		t.Errorf("expected no RequestMeta in a context without one")
+	}
+
+	r, _ := http.NewRequest("POST", "http://localhost/", nil)
+	r.RemoteAddr = "10.0.0.1:5000"
+	r.Header.Set("Authorization", "Bearer abc")
+	ctx, cancel := pulserpc.RequestContext(r)
+	defer cancel()
+	meta, ok := pulserpc.RequestMetaFromContext(ctx)
+	if !ok {
+		t.Fatalf("expected RequestContext to carry the RequestMeta")
+	}
+	if got := meta.Headers.Get("Authorization"); got != "Bearer abc" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer abc")
+	}
+	if meta.RemoteAddr != "10.0.0.1:5000" {
+		t.Errorf("RemoteAddr = %q, want %q", meta.RemoteAddr, "10.0.0.1:5000")
+	}
+}
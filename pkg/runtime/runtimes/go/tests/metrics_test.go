@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"pulserpc-go-runtime/pulserpc"
+)
+
+func TestMethodMetrics(t *testing.T) {
+	metrics := pulserpc.NewMethodMetrics([]string{"Catalog.get", "Catalog.list"})
+	metrics.Record("Catalog.get", 10*time.Millisecond, false)
+	metrics.Record("Catalog.get", 30*time.Millisecond, true)
+	// Methods it was not created with are not counted
+	metrics.Record("Catalog.unknown", time.Millisecond, false)
+
+	snapshot := metrics.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("expected 2 methods, got %v", snapshot)
+	}
+	want := pulserpc.MethodMetric{Calls: 2, Errors: 1, MeanMs: 20, MaxMs: 30}
+	if got := snapshot["Catalog.get"]; got.Calls != want.Calls || got.Errors != want.Errors || got.MeanMs != want.MeanMs || got.MaxMs != want.MaxMs {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if got := snapshot["Catalog.list"]; got.Calls != 0 || got.MeanMs != 0 {
+		t.Errorf("expected no calls, got %+v", got)
+	}
+	if time.Since(metrics.Started()) > time.Minute {
+		t.Errorf("unexpected start time %v", metrics.Started())
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"pulserpc-go-runtime/pulserpc"
+)
+
+func TestCheckIntLiterals(t *testing.T) {
+	intType := map[string]interface{}{"builtIn": "int"}
+	floatType := map[string]interface{}{"builtIn": "float"}
+	if err := pulserpc.CheckIntLiterals(json.Number("2"), intType, nil); err != nil {
+		t.Errorf("expected 2 to pass as int, got %v", err)
+	}
+	if err := pulserpc.CheckIntLiterals(json.Number("2.0"), floatType, nil); err != nil {
+		t.Errorf("expected 2.0 to pass as float, got %v", err)
+	}
+	for _, literal := range []string{"2.0", "2e0", "2E0"} {
+		if err := pulserpc.CheckIntLiterals(json.Number(literal), intType, nil); err == nil {
+			t.Errorf("expected %s to fail as int", literal)
+		}
+	}
+
+	// Nested values are checked against their element and field types
+	arrayType := map[string]interface{}{"array": intType}
+	err := pulserpc.CheckIntLiterals([]interface{}{json.Number("1"), json.Number("2.0")}, arrayType, nil)
+	if err == nil || !strings.Contains(err.Error(), "index 1") {
+		t.Errorf("expected an error naming index 1, got %v", err)
+	}
+	structs := pulserpc.StructMap{
+		"Point": {"fields": []interface{}{
+			map[string]interface{}{"name": "x", "type": intType},
+		}},
+	}
+	structType := map[string]interface{}{"userDefined": "Point"}
+	err = pulserpc.CheckIntLiterals(map[string]interface{}{"x": json.Number("1.0")}, structType, structs)
+	if err == nil || !strings.Contains(err.Error(), "field 'x'") {
+		t.Errorf("expected an error naming field x, got %v", err)
+	}
+}
+
+func TestCheckRequestIntLiterals(t *testing.T) {
+	expectedParams := []interface{}{
+		map[string]interface{}{"name": "count", "type": map[string]interface{}{"builtIn": "int"}},
+	}
+
+	// Without the literals in the context nothing is checked
+	if err := pulserpc.CheckRequestIntLiterals(context.Background(), expectedParams, nil); err != nil {
+		t.Errorf("expected no error without literals, got %v", err)
+	}
+
+	ctx := pulserpc.WithRequestLiterals(context.Background(), []byte(`{"jsonrpc":"2.0","method":"m","params":[3],"id":1}`))
+	if err := pulserpc.CheckRequestIntLiterals(ctx, expectedParams, nil); err != nil {
+		t.Errorf("expected 3 to pass, got %v", err)
+	}
+	ctx = pulserpc.WithRequestLiterals(context.Background(), []byte(`{"jsonrpc":"2.0","method":"m","params":[3.0],"id":1}`))
+	err := pulserpc.CheckRequestIntLiterals(ctx, expectedParams, nil)
+	if err == nil || !strings.Contains(err.Error(), "Parameter 0 (count)") {
+		t.Errorf("expected an error naming the param, got %v", err)
+	}
+	ctx = pulserpc.WithRequestLiterals(context.Background(), []byte(`{"jsonrpc":"2.0","method":"m","params":{"count":3e0},"id":1}`))
+	if err := pulserpc.CheckRequestIntLiterals(ctx, expectedParams, nil); err == nil {
+		t.Errorf("expected 3e0 by name to fail")
+	}
+}
@@ -92,3 +92,26 @@ func TestGetStructFields(t *testing.T) {
 		t.Error("Expected to find childField in fields")
 	}
 }
+
+func TestRedactJSON(t *testing.T) {
+	allStructs := pulserpc.StructMap{
+		"Login": pulserpc.StructDef{
+			"fields": []interface{}{
+				map[string]interface{}{"name": "user", "type": map[string]interface{}{"builtIn": "string"}},
+				map[string]interface{}{"name": "password", "type": map[string]interface{}{"builtIn": "string"}, "sensitive": true},
+				map[string]interface{}{"name": "token", "type": map[string]interface{}{"builtIn": "string"}, "optional": true, "sensitive": true},
+			},
+		},
+	}
+	typeDef := map[string]interface{}{"array": map[string]interface{}{"userDefined": "Login"}}
+	original := []interface{}{map[string]interface{}{"user": "ann", "password": "hunter2", "token": nil}}
+
+	redacted := pulserpc.RedactJSON(original, typeDef, allStructs).([]interface{})
+	login := redacted[0].(map[string]interface{})
+	if login["user"] != "ann" || login["password"] != pulserpc.Redacted || login["token"] != nil {
+		t.Errorf("unexpected redacted value: %v", login)
+	}
+	if original[0].(map[string]interface{})["password"] != "hunter2" {
+		t.Error("RedactJSON changed its argument")
+	}
+}
@@ -26,6 +26,10 @@ func TestValidateInt(t *testing.T) {
 		t.Errorf("Expected nil error for float64 representing int, got %v", err)
 	}
 
+	if err := pulserpc.ValidateInt(1.5); err == nil {
+		t.Error("Expected error for float64 with a fractional part")
+	}
+
 	if err := pulserpc.ValidateInt("123"); err == nil {
 		t.Error("Expected error for non-int value")
 	}
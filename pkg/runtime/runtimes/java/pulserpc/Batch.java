@@ -0,0 +1,160 @@
+package com.bitmechanic.pulserpc;
+
+import java.util.ArrayList;
+import java.util.List;
+import java.util.function.Supplier;
+
+/**
+ * Collects client calls and sends them in one JSON-RPC batch request. Calls are made on
+ * a client returned by withBatch and added with add, which returns the BatchResult the
+ * call's typed result or error is stored in once send returns:
+ * {@code BatchResult<Integer> sum = batch.add(() -> calc.withBatch(batch).add(1, 2)); batch.send();}
+ * A call that fails does not fail the others. A Batch is not safe for concurrent use.
+ */
+public final class Batch implements Transport {
+
+    // A call added to the batch: the request it queued, and once the batch is sent, the
+    // response matched to it
+    private static final class Call {
+        Runnable replay;
+        Request request;
+        boolean answered;
+        Response response;
+        Exception error;
+    }
+
+    // Stops a client call once its request is queued
+    private static final class Queued extends Exception {
+        Queued() {
+            super("call queued in batch");
+        }
+    }
+
+    private final Transport transport;
+    private final List<Call> calls = new ArrayList<>();
+    // The call being added or replayed, whose requests this transport receives
+    private Call current;
+
+    /**
+     * Creates an empty batch whose calls are sent through transport
+     */
+    public Batch(Transport transport) {
+        this.transport = transport;
+    }
+
+    /**
+     * The number of calls waiting to be sent
+     */
+    public int size() {
+        return calls.size();
+    }
+
+    /**
+     * Adds a client call to the batch. The call is made once to queue its request, and again
+     * once the batch is sent to decode its response. A call that fails before sending
+     * anything has its BatchResult set right away and is not sent.
+     */
+    public <T> BatchResult<T> add(Supplier<T> call) {
+        BatchResult<T> result = new BatchResult<>();
+        Call c = new Call();
+        c.replay = () -> run(c, call, result);
+        run(c, call, result);
+        if (c.request != null) {
+            result.set(null, null);
+            calls.add(c);
+        }
+        return result;
+    }
+
+    private <T> void run(Call c, Supplier<T> call, BatchResult<T> result) {
+        current = c;
+        try {
+            result.set(call.get(), null);
+        } catch (RuntimeException e) {
+            result.set(null, e);
+        } finally {
+            current = null;
+        }
+    }
+
+    @Override
+    public Response call(Request request) throws Exception {
+        return call(request, CallOptions.NONE);
+    }
+
+    /**
+     * Queues the request of the call being added and stops the call. When the call is made
+     * again after the batch is sent, returns the response matched to the request instead.
+     * Other requests, such as the polls of an [async] method, go to the transport.
+     */
+    @Override
+    public Response call(Request request, CallOptions options) throws Exception {
+        Call c = current;
+        if (c == null) {
+            return transport.call(request, options);
+        }
+        if (c.request == null) {
+            c.request = request;
+            throw new Queued();
+        }
+        if (!c.answered) {
+            return transport.call(request, options);
+        }
+        c.answered = false;
+        if (c.error != null) {
+            throw c.error;
+        }
+        if (c.response == null) {
+            throw new RPCError(-32603, "Internal error", "No response to " + c.request.getMethod() + " in batch");
+        }
+        return HTTPTransport.checkResponse(c.response);
+    }
+
+    /**
+     * Sends the queued calls with no batch-wide options
+     * @see #send(CallOptions)
+     */
+    public void send() throws Exception {
+        send(CallOptions.NONE);
+    }
+
+    /**
+     * Sends the queued calls in one request and sets the BatchResult of each from its own
+     * response. A transport that is not a BatchTransport makes the calls one at a time.
+     * The batch is empty afterwards.
+     * @param options Timeout and headers of the request as a whole
+     * @throws Exception if the request as a whole failed; it is also the error of every call
+     */
+    public void send(CallOptions options) throws Exception {
+        List<Call> queued = new ArrayList<>(calls);
+        calls.clear();
+        Exception failure = null;
+        if (transport instanceof BatchTransport && !queued.isEmpty()) {
+            List<Request> requests = new ArrayList<>();
+            for (Call c : queued) {
+                requests.add(c.request);
+            }
+            List<Response> responses = null;
+            try {
+                responses = ((BatchTransport) transport).callBatch(requests, options);
+            } catch (Exception e) {
+                failure = e;
+            }
+            for (int i = 0; i < queued.size(); i++) {
+                Call c = queued.get(i);
+                c.answered = true;
+                if (failure != null) {
+                    c.error = failure;
+                } else {
+                    c.response = responses.get(i);
+                }
+            }
+        }
+        for (Call c : queued) {
+            c.replay.run();
+        }
+        if (failure != null) {
+            throw failure;
+        }
+    }
+}
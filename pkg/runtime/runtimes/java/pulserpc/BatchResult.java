@@ -0,0 +1,29 @@
+package com.bitmechanic.pulserpc;
+
+/**
+ * The outcome of one call of a Batch: its typed result, or the exception the call
+ * threw, such as an RPCError from its own response
+ */
+public final class BatchResult<T> {
+    private T result;
+    private RuntimeException error;
+
+    void set(T result, RuntimeException error) {
+        this.result = result;
+        this.error = error;
+    }
+
+    /**
+     * The result of the call, or null if it failed
+     */
+    public T getResult() {
+        return result;
+    }
+
+    /**
+     * The exception the call threw, or null if it succeeded
+     */
+    public RuntimeException getError() {
+        return error;
+    }
+}
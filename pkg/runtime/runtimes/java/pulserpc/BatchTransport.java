@@ -0,0 +1,18 @@
+package com.bitmechanic.pulserpc;
+
+import java.util.List;
+
+/**
+ * Implemented by transports that send several calls in one JSON-RPC batch request
+ */
+public interface BatchTransport {
+    /**
+     * Send requests as one batch request
+     * @param requests The JSON-RPC requests, each with its own id
+     * @param options Timeout and headers of the batch request as a whole
+     * @return The response to each request in the order of requests, null where the
+     *         server sent none
+     * @throws Exception if the batch request as a whole fails
+     */
+    List<Response> callBatch(List<Request> requests, CallOptions options) throws Exception;
+}
@@ -0,0 +1,71 @@
+package com.bitmechanic.pulserpc;
+
+import java.util.ArrayList;
+import java.util.Collections;
+import java.util.HashMap;
+import java.util.List;
+import java.util.Map;
+import java.util.logging.Logger;
+
+/**
+ * Logs the calls of HTTPTransport at FINE level with their method, duration, and request
+ * and response JSON, in which [sensitive] fields are masked as "***". The logger is named
+ * after HTTPTransport, and its level is the verbosity switch. The parameter and return
+ * types of each method and the sensitive fields of each struct are read from the
+ * /idl.json resource the generator writes; without it, calls are logged without their
+ * JSON so that no sensitive value is written.
+ */
+final class CallLog {
+
+    static final Logger LOGGER = Logger.getLogger(HTTPTransport.class.getName());
+
+    private CallLog() {
+    }
+
+    /**
+     * Logs a call with its params and result redacted according to the method's parameter
+     * and return types. response is null if the call failed with error.
+     */
+    @SuppressWarnings("unchecked")
+    static void log(JsonParser jsonParser, Request request, Response response, Exception error, long elapsedNanos) {
+        IdlTypes types = IdlTypes.get(jsonParser);
+        StringBuilder line = new StringBuilder("pulserpc call method=").append(request.getMethod())
+            .append(String.format(" duration=%.3fms", elapsedNanos / 1e6));
+        Map<String, Object> methodDef = types.methods != null ? types.methods.get(request.getMethod()) : null;
+        if (types.structs != null) {
+            Object params = jsonParser.fromJson(jsonParser.toJson(request.getParams()), Object.class);
+            line.append(" request=").append(jsonParser.toJson(redactParams(params, methodDef, types.structs)));
+        }
+        if (error != null) {
+            line.append(" error=").append(error.getMessage());
+        } else if (types.structs != null) {
+            Map<String, Object> logged = jsonParser.fromJson(jsonParser.toJson(response), Map.class);
+            Map<String, Object> returnType = methodDef != null ? (Map<String, Object>) methodDef.get("returnType") : null;
+            logged.put("result", Redaction.redact(logged.get("result"), returnType, types.structs));
+            line.append(" response=").append(jsonParser.toJson(logged));
+        }
+        LOGGER.fine(line.toString());
+    }
+
+    // Redacts params sent by position or by name according to the method's parameters
+    @SuppressWarnings("unchecked")
+    private static Object redactParams(Object params, Map<String, Object> methodDef, Map<String, Map<String, Object>> structs) {
+        List<Map<String, Object>> paramDefs = methodDef != null ? IdlTypes.list(methodDef.get("parameters")) : Collections.emptyList();
+        if (params instanceof List) {
+            List<Object> redacted = new ArrayList<>((List<Object>) params);
+            for (int i = 0; i < redacted.size() && i < paramDefs.size(); i++) {
+                redacted.set(i, Redaction.redact(redacted.get(i), (Map<String, Object>) paramDefs.get(i).get("type"), structs));
+            }
+            return redacted;
+        }
+        if (params instanceof Map) {
+            Map<String, Object> redacted = new HashMap<>((Map<String, Object>) params);
+            for (Map<String, Object> paramDef : paramDefs) {
+                String name = (String) paramDef.get("name");
+                redacted.put(name, Redaction.redact(redacted.get(name), (Map<String, Object>) paramDef.get("type"), structs));
+            }
+            return redacted;
+        }
+        return params;
+    }
+}
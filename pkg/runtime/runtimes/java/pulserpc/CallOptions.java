@@ -0,0 +1,169 @@
+package com.bitmechanic.pulserpc;
+
+import java.time.Duration;
+import java.util.Collections;
+import java.util.LinkedHashMap;
+import java.util.Map;
+import java.util.function.Consumer;
+
+/**
+ * Immutable per-call settings, applied through a client's withOptions, withTimeout,
+ * withHeader, withIdempotencyKey, withNamedParams, withResponseMeta, withJobPollInterval
+ * and withJobProgress. Headers are
+ * added to the request, overriding the transport's headers. The idempotency key is
+ * sent as the Idempotency-Key header so the server can recognize a repeated request.
+ * Named params are sent as an object keyed by parameter name instead of an array.
+ * The response meta map receives the metadata the server attached to the response.
+ * The job settings apply to [async] methods, whose clients poll the job the call
+ * started until it finishes.
+ */
+public final class CallOptions {
+
+    /**
+     * Options that change nothing
+     */
+    public static final CallOptions NONE = new CallOptions(null, Collections.emptyMap(), null, false, null, null, null);
+
+    private final Duration timeout;
+    private final Map<String, String> headers;
+    private final String idempotencyKey;
+    private final boolean namedParams;
+    private final Map<String, Object> responseMeta;
+    private final Duration jobPollInterval;
+    private final Consumer<JobStatus> jobProgress;
+
+    private CallOptions(Duration timeout, Map<String, String> headers, String idempotencyKey, boolean namedParams,
+                        Map<String, Object> responseMeta, Duration jobPollInterval, Consumer<JobStatus> jobProgress) {
+        this.timeout = timeout;
+        this.headers = headers;
+        this.idempotencyKey = idempotencyKey;
+        this.namedParams = namedParams;
+        this.responseMeta = responseMeta;
+        this.jobPollInterval = jobPollInterval;
+        this.jobProgress = jobProgress;
+    }
+
+    /**
+     * Returns a copy with the call bounded to timeout
+     */
+    public CallOptions withTimeout(Duration timeout) {
+        return new CallOptions(timeout, headers, idempotencyKey, namedParams, responseMeta, jobPollInterval, jobProgress);
+    }
+
+    /**
+     * Returns a copy that also sends the given HTTP header
+     */
+    public CallOptions withHeader(String name, String value) {
+        Map<String, String> copy = new LinkedHashMap<>(headers);
+        copy.put(name, value);
+        return new CallOptions(timeout, Collections.unmodifiableMap(copy), idempotencyKey, namedParams, responseMeta, jobPollInterval, jobProgress);
+    }
+
+    /**
+     * Returns a copy that sends key as the Idempotency-Key header
+     */
+    public CallOptions withIdempotencyKey(String key) {
+        return new CallOptions(timeout, headers, key, namedParams, responseMeta, jobPollInterval, jobProgress);
+    }
+
+    /**
+     * Returns a copy that sends params by name
+     */
+    public CallOptions withNamedParams() {
+        return new CallOptions(timeout, headers, idempotencyKey, true, responseMeta, jobPollInterval, jobProgress);
+    }
+
+    /**
+     * Returns a copy that copies the metadata the server attached to the response into meta
+     */
+    public CallOptions withResponseMeta(Map<String, Object> meta) {
+        return new CallOptions(timeout, headers, idempotencyKey, namedParams, meta, jobPollInterval, jobProgress);
+    }
+
+    /**
+     * Returns a copy that polls the job of an [async] method every interval
+     */
+    public CallOptions withJobPollInterval(Duration interval) {
+        return new CallOptions(timeout, headers, idempotencyKey, namedParams, responseMeta, interval, jobProgress);
+    }
+
+    /**
+     * Returns a copy that passes the status of an [async] method's job to progress after each poll
+     */
+    public CallOptions withJobProgress(Consumer<JobStatus> progress) {
+        return new CallOptions(timeout, headers, idempotencyKey, namedParams, responseMeta, jobPollInterval, progress);
+    }
+
+    /**
+     * Timeout of the call, or null for the transport's default
+     */
+    public Duration getTimeout() {
+        return timeout;
+    }
+
+    /**
+     * Headers added to the request
+     */
+    public Map<String, String> getHeaders() {
+        return headers;
+    }
+
+    /**
+     * Idempotency key, or null if none
+     */
+    public String getIdempotencyKey() {
+        return idempotencyKey;
+    }
+
+    /**
+     * True if params are sent by name
+     */
+    public boolean isNamedParams() {
+        return namedParams;
+    }
+
+    /**
+     * The map that receives response metadata, or null
+     */
+    public Map<String, Object> getResponseMeta() {
+        return responseMeta;
+    }
+
+    /**
+     * Interval between polls of an [async] method's job, or null for one second
+     */
+    public Duration getJobPollInterval() {
+        return jobPollInterval;
+    }
+
+    /**
+     * Receives the status of an [async] method's job after each poll, or null
+     */
+    public Consumer<JobStatus> getJobProgress() {
+        return jobProgress;
+    }
+
+    /**
+     * Copies the metadata of response into the response meta map, if both are present
+     */
+    public void captureMeta(Response response) {
+        if (responseMeta != null && response.getMeta() != null) {
+            responseMeta.putAll(response.getMeta());
+        }
+    }
+
+    /**
+     * Returns params as sent in a request: an object keyed by names when params are
+     * sent by name, otherwise the array itself
+     */
+    public Object requestParams(Object[] params, String... names) {
+        if (!namedParams || names.length != params.length) {
+            return params;
+        }
+        Map<String, Object> named = new LinkedHashMap<>();
+        for (int i = 0; i < params.length; i++) {
+            named.put(names[i], params[i]);
+        }
+        return named;
+    }
+}
@@ -0,0 +1,38 @@
+package com.bitmechanic.pulserpc;
+
+import java.util.Collections;
+import java.util.LinkedHashMap;
+import java.util.Map;
+import java.util.function.Function;
+
+/**
+ * The result of a call together with the metadata the server attached to the
+ * response, such as timings, pagination hints or warnings
+ */
+public final class CallResult<T> {
+    private final T result;
+    private final Map<String, Object> meta;
+
+    public CallResult(T result, Map<String, Object> meta) {
+        this.result = result;
+        this.meta = Collections.unmodifiableMap(meta);
+    }
+
+    /**
+     * Makes a client call with a map that captures the response metadata:
+     * {@code CallResult.capture(meta -> catalog.withResponseMeta(meta).getProduct("p-1"))}
+     */
+    public static <T> CallResult<T> capture(Function<Map<String, Object>, T> call) {
+        Map<String, Object> meta = new LinkedHashMap<>();
+        T result = call.apply(meta);
+        return new CallResult<>(result, meta);
+    }
+
+    public T getResult() {
+        return result;
+    }
+
+    public Map<String, Object> getMeta() {
+        return meta;
+    }
+}
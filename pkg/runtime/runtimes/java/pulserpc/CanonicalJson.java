@@ -0,0 +1,159 @@
+package com.bitmechanic.pulserpc;
+
+import java.math.BigDecimal;
+import java.nio.charset.StandardCharsets;
+import java.security.MessageDigest;
+import java.security.NoSuchAlgorithmException;
+import java.util.ArrayList;
+import java.util.Collections;
+import java.util.LinkedHashMap;
+import java.util.List;
+import java.util.Map;
+
+/**
+ * Canonical JSON and request hashes that match across runtimes
+ */
+public final class CanonicalJson {
+
+    private CanonicalJson() {
+    }
+
+    /**
+     * Returns the SHA-256, in lowercase hex, of the canonical JSON of
+     * {"method": method, "params": params}. Every runtime computes the same hash for the
+     * same call, so it can key idempotency records, caches and deduplication across
+     * services written in different languages. Params sent by position and by name hash
+     * differently.
+     */
+    public static String requestHash(String method, Object params) {
+        Map<String, Object> call = new LinkedHashMap<>();
+        call.put("method", method);
+        call.put("params", params);
+        try {
+            byte[] sum = MessageDigest.getInstance("SHA-256").digest(encode(call).getBytes(StandardCharsets.UTF_8));
+            StringBuilder hex = new StringBuilder(sum.length * 2);
+            for (byte b : sum) {
+                hex.append(Character.forDigit((b >> 4) & 0xf, 16)).append(Character.forDigit(b & 0xf, 16));
+            }
+            return hex.toString();
+        } catch (NoSuchAlgorithmException e) {
+            throw new IllegalStateException("SHA-256 is not available", e);
+        }
+    }
+
+    /**
+     * Encodes a decoded JSON value (Map, List, String, Number, Boolean or null) as
+     * canonical JSON (RFC 8785): no whitespace, object keys sorted by their UTF-16 code
+     * units, numbers formatted as JavaScript does and strings escaped only where JSON
+     * requires it.
+     * @throws IllegalArgumentException for NaN, infinities and values of other types
+     */
+    public static String encode(Object value) {
+        StringBuilder sb = new StringBuilder();
+        write(sb, value);
+        return sb.toString();
+    }
+
+    private static void write(StringBuilder sb, Object value) {
+        if (value == null) {
+            sb.append("null");
+        } else if (value instanceof Boolean) {
+            sb.append(value.toString());
+        } else if (value instanceof Number) {
+            sb.append(number(((Number) value).doubleValue()));
+        } else if (value instanceof String) {
+            writeString(sb, (String) value);
+        } else if (value instanceof List) {
+            sb.append('[');
+            boolean first = true;
+            for (Object item : (List<?>) value) {
+                if (!first) {
+                    sb.append(',');
+                }
+                first = false;
+                write(sb, item);
+            }
+            sb.append(']');
+        } else if (value instanceof Map) {
+            Map<?, ?> map = (Map<?, ?>) value;
+            List<String> keys = new ArrayList<>();
+            for (Object key : map.keySet()) {
+                keys.add(String.valueOf(key));
+            }
+            // String.compareTo orders by UTF-16 code units, as RFC 8785 sorts keys
+            Collections.sort(keys);
+            sb.append('{');
+            boolean first = true;
+            for (String key : keys) {
+                if (!first) {
+                    sb.append(',');
+                }
+                first = false;
+                writeString(sb, key);
+                sb.append(':');
+                write(sb, map.get(key));
+            }
+            sb.append('}');
+        } else {
+            throw new IllegalArgumentException("cannot canonicalize " + value.getClass().getName());
+        }
+    }
+
+    /**
+     * Formats f as JavaScript's Number.prototype.toString does
+     */
+    static String number(double f) {
+        if (Double.isNaN(f) || Double.isInfinite(f)) {
+            throw new IllegalArgumentException("cannot canonicalize " + f);
+        }
+        if (f == 0) {
+            return "0";
+        }
+        String sign = f < 0 ? "-" : "";
+        // Double.toString gives the shortest digits that round-trip (on Java 19 and later;
+        // earlier releases print extra digits for a few values); n is the position of the
+        // decimal point relative to them: f = 0.digits * 10^n
+        BigDecimal decimal = new BigDecimal(Double.toString(Math.abs(f))).stripTrailingZeros();
+        String digits = decimal.unscaledValue().toString();
+        int k = digits.length();
+        int n = k - decimal.scale();
+        if (k <= n && n <= 21) {
+            return sign + digits + "0".repeat(n - k);
+        }
+        if (0 < n && n <= 21) {
+            return sign + digits.substring(0, n) + "." + digits.substring(n);
+        }
+        if (-6 < n && n <= 0) {
+            return sign + "0." + "0".repeat(-n) + digits;
+        }
+        int exp = n - 1;
+        String mantissa = k == 1 ? digits : digits.charAt(0) + "." + digits.substring(1);
+        return sign + mantissa + "e" + (exp < 0 ? "-" : "+") + Math.abs(exp);
+    }
+
+    /**
+     * Escapes only quotes, backslashes and control characters
+     */
+    private static void writeString(StringBuilder sb, String s) {
+        sb.append('"');
+        for (int i = 0; i < s.length(); i++) {
+            char c = s.charAt(i);
+            switch (c) {
+                case '"': sb.append("\\\""); break;
+                case '\\': sb.append("\\\\"); break;
+                case '\b': sb.append("\\b"); break;
+                case '\f': sb.append("\\f"); break;
+                case '\n': sb.append("\\n"); break;
+                case '\r': sb.append("\\r"); break;
+                case '\t': sb.append("\\t"); break;
+                default:
+                    if (c < 0x20) {
+                        sb.append(String.format("\\u%04x", (int) c));
+                    } else {
+                        sb.append(c);
+                    }
+            }
+        }
+        sb.append('"');
+    }
+}
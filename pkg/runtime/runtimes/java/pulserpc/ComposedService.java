@@ -0,0 +1,28 @@
+package com.bitmechanic.pulserpc;
+
+import java.util.Map;
+
+/**
+ * A server that another server serves calls for, so services generated from
+ * different IDLs can share one port. The Server of every generated package
+ * implements it, so a Server composes the Servers of other packages with
+ * compose().
+ */
+public interface ComposedService {
+
+    /**
+     * Whether method, "Interface.method" or a [wire] name, is a method of an
+     * interface registered with the server.
+     */
+    boolean handles(String method);
+
+    /**
+     * Handles one decoded JSON-RPC request and returns its response.
+     */
+    Map<String, Object> handleRequest(Map<String, Object> request);
+
+    /**
+     * The idl.json document of the server.
+     */
+    Map<String, Object> idlDocument() throws java.io.IOException;
+}
@@ -0,0 +1,90 @@
+package com.bitmechanic.pulserpc;
+
+import java.io.IOException;
+import java.util.ArrayList;
+import java.util.HashSet;
+import java.util.LinkedHashMap;
+import java.util.List;
+import java.util.Map;
+import java.util.Set;
+import java.util.concurrent.CopyOnWriteArrayList;
+
+/**
+ * The servers a server serves calls for besides its own interfaces, routed by
+ * interface name from its own endpoint. Empty when created.
+ */
+public final class Composition {
+
+    private static final String[] IDL_SECTIONS = {"interfaces", "structs", "enums", "typedefs"};
+
+    private final List<ComposedService> services = new CopyOnWriteArrayList<>();
+
+    /**
+     * Routes calls for the interfaces of service to it. A method the composing
+     * server handles itself stays with it, and of the added services the first
+     * that handles a method gets it.
+     */
+    public void add(ComposedService service) {
+        services.add(service);
+    }
+
+    /**
+     * The added service that handles method, or null.
+     */
+    public ComposedService find(String method) {
+        for (ComposedService service : services) {
+            if (service.handles(method)) {
+                return service;
+            }
+        }
+        return null;
+    }
+
+    /**
+     * Returns doc with the idl.json documents of every added service merged in.
+     */
+    public Map<String, Object> mergeIdl(Map<String, Object> doc) throws IOException {
+        if (services.isEmpty()) {
+            return doc;
+        }
+        List<Map<String, Object>> docs = new ArrayList<>();
+        docs.add(doc);
+        for (ComposedService service : services) {
+            docs.add(service.idlDocument());
+        }
+        return mergeIdlDocuments(docs);
+    }
+
+    /**
+     * Merges idl.json documents into one that lists the interfaces, structs,
+     * enums and typedefs of all of them, in order, each name once, so a type
+     * from an IDL both include is listed once. The rootNamespace of the first
+     * document is kept.
+     */
+    public static Map<String, Object> mergeIdlDocuments(List<Map<String, Object>> docs) {
+        Map<String, Object> merged = new LinkedHashMap<>();
+        if (!docs.isEmpty() && docs.get(0).containsKey("rootNamespace")) {
+            merged.put("rootNamespace", docs.get(0).get("rootNamespace"));
+        }
+        for (String section : IDL_SECTIONS) {
+            Set<Object> seen = new HashSet<>();
+            List<Object> elements = new ArrayList<>();
+            for (Map<String, Object> doc : docs) {
+                Object list = doc.get(section);
+                if (!(list instanceof List)) {
+                    continue;
+                }
+                for (Object element : (List<?>) list) {
+                    Object name = element instanceof Map ? ((Map<?, ?>) element).get("name") : null;
+                    if (seen.add(name)) {
+                        elements.add(element);
+                    }
+                }
+            }
+            if (!elements.isEmpty()) {
+                merged.put(section, elements);
+            }
+        }
+        return merged;
+    }
+}
@@ -0,0 +1,77 @@
+package com.bitmechanic.pulserpc;
+
+import java.time.Duration;
+
+/**
+ * The deadline of the call being handled: the time its caller allows for it, sent in
+ * the X-PulseRPC-Deadline header as milliseconds. Servers begin a deadline for each
+ * request on the thread that handles it, handlers read what is left of it with
+ * remaining(), and HTTPTransport uses that as the timeout of calls made while handling
+ * it, so downstream calls fit in the caller's budget.
+ */
+public final class Deadline {
+
+    public static final String HEADER = "X-PulseRPC-Deadline";
+
+    // The System.nanoTime() value at which the call being handled expires
+    private static final ThreadLocal<Long> CURRENT = new ThreadLocal<>();
+
+    private Deadline() {
+    }
+
+    /**
+     * Ends the deadline begun for a request, restoring the one before it
+     */
+    public static final class Scope implements AutoCloseable {
+        private final Long previous;
+
+        private Scope(Long previous) {
+            this.previous = previous;
+        }
+
+        @Override
+        public void close() {
+            if (previous == null) {
+                CURRENT.remove();
+            } else {
+                CURRENT.set(previous);
+            }
+        }
+    }
+
+    /**
+     * Sets the deadline of the call handled on this thread from its X-PulseRPC-Deadline
+     * header value until the returned scope is closed. A missing or malformed value
+     * leaves the call without a deadline.
+     */
+    public static Scope begin(String headerValue) {
+        Scope scope = new Scope(CURRENT.get());
+        try {
+            long budgetMs = Long.parseLong(headerValue);
+            CURRENT.set(System.nanoTime() + Duration.ofMillis(budgetMs).toNanos());
+        } catch (NumberFormatException e) {
+            CURRENT.remove();
+        }
+        return scope;
+    }
+
+    /**
+     * Returns the time left before the deadline of the call being handled, zero once it
+     * has passed, or null if the call has no deadline
+     */
+    public static Duration remaining() {
+        Long deadline = CURRENT.get();
+        if (deadline == null) {
+            return null;
+        }
+        long remaining = deadline - System.nanoTime();
+        return Duration.ofNanos(Math.max(0, remaining));
+    }
+
+    /**
+     * Formats timeout as an X-PulseRPC-Deadline header value
+     */
+    public static String headerValue(Duration timeout) {
+        return Long.toString(timeout.toMillis());
+    }
+}
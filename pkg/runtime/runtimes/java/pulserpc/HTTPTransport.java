@@ -1,20 +1,32 @@
 package com.bitmechanic.pulserpc;
 
 import java.io.IOException;
+import java.io.InputStream;
+import java.net.ConnectException;
 import java.net.URI;
 import java.net.http.HttpClient;
 import java.net.http.HttpRequest;
 import java.net.http.HttpResponse;
+import java.net.http.HttpTimeoutException;
+import java.nio.charset.StandardCharsets;
 import java.time.Duration;
+import java.util.ArrayList;
+import java.util.HashMap;
+import java.util.List;
 import java.util.Map;
+import java.util.logging.Level;
+import java.util.zip.GZIPInputStream;
 
 /**
- * HTTP implementation of Transport that makes HTTP POST requests
+ * HTTP implementation of Transport that makes HTTP POST requests. Every request accepts
+ * gzip, which servers use for the responses of [compress] methods.
  */
-public class HTTPTransport implements Transport {
+public class HTTPTransport implements Transport, BatchTransport {
     private final HttpClient httpClient;
     private final String baseUrl;
     private final JsonParser jsonParser;
+    private volatile RequestSigner signer;
+    private volatile boolean canonicalJson;
 
     public HTTPTransport(String baseUrl, JsonParser jsonParser) {
         this.baseUrl = baseUrl.endsWith("/") ? baseUrl.substring(0, baseUrl.length() - 1) : baseUrl;
@@ -24,25 +36,189 @@ public class HTTPTransport implements Transport {
             .build();
     }
 
-    @Override
-    public Response call(Request request) throws Exception {
-        String requestJson = jsonParser.toJson(request);
+    /**
+     * Installs a hook that signs every request, such as RequestSigning.hmacSigner.
+     * @param signer The signer, or null to send requests unsigned
+     */
+    public void setSigner(RequestSigner signer) {
+        this.signer = signer;
+    }
 
+    /**
+     * Controls whether requests are encoded as canonical JSON (RFC 8785, see CanonicalJson),
+     * so a server in any language can recompute the signed body from the request it decoded
+     */
+    public void setCanonicalJson(boolean enabled) {
+        this.canonicalJson = enabled;
+    }
+
+    /**
+     * Resolves the server's host and opens a connection to it, including the TLS handshake,
+     * which the HttpClient keeps for the next call. Call it at process start to take the
+     * connection setup out of the first call's latency.
+     * @param ping Also call the built-in pulserpc-idl method, which wakes a server that scaled
+     *             to zero; any JSON-RPC response counts
+     * @param timeout Bound on the warm-up, or null for the default of 30 seconds
+     * @throws Exception if the server cannot be reached
+     */
+    public void warmup(boolean ping, Duration timeout) throws Exception {
+        if (ping) {
+            try {
+                call(new Request("pulserpc-idl", new Object[0], java.util.UUID.randomUUID().toString()),
+                    CallOptions.NONE.withTimeout(timeout));
+            } catch (RPCError e) {
+                // the server answered
+            }
+            return;
+        }
         HttpRequest httpRequest = HttpRequest.newBuilder()
             .uri(URI.create(baseUrl))
-            .header("Content-Type", "application/json")
-            .POST(HttpRequest.BodyPublishers.ofString(requestJson))
-            .timeout(Duration.ofSeconds(30))
+            .method("OPTIONS", HttpRequest.BodyPublishers.noBody())
+            .timeout(timeout != null ? timeout : Duration.ofSeconds(30))
             .build();
+        try {
+            // Any HTTP status will do: the connection is open either way
+            httpClient.send(httpRequest, HttpResponse.BodyHandlers.discarding());
+        } catch (ConnectException e) {
+            throw new TransportException("Connection failed: " + e.getMessage(), 0, true, e);
+        }
+    }
 
-        HttpResponse<String> httpResponse = httpClient.send(httpRequest, HttpResponse.BodyHandlers.ofString());
+    @Override
+    public Response call(Request request) throws Exception {
+        return call(request, CallOptions.NONE);
+    }
+
+    /**
+     * Sends request and returns its response. When the logger named after this class is
+     * enabled at FINE, each call is logged with its duration and redacted JSON (see CallLog).
+     */
+    @Override
+    public Response call(Request request, CallOptions options) throws Exception {
+        if (!CallLog.LOGGER.isLoggable(Level.FINE)) {
+            return send(request, options);
+        }
+        long start = System.nanoTime();
+        try {
+            Response response = send(request, options);
+            CallLog.log(jsonParser, request, response, null, System.nanoTime() - start);
+            return response;
+        } catch (Exception e) {
+            CallLog.log(jsonParser, request, null, e, System.nanoTime() - start);
+            throw e;
+        }
+    }
+
+    // Performs a call without logging it
+    private Response send(Request request, CallOptions options) throws Exception {
+        String body = post(encode(request), options);
+        return checkResponse(jsonParser.fromJson(body, Response.class));
+    }
+
+    /**
+     * Sends requests as one JSON-RPC batch request and returns the response to each,
+     * matched by request id
+     */
+    @Override
+    public List<Response> callBatch(List<Request> requests, CallOptions options) throws Exception {
+        String body = post(encode(requests), options);
+        Object members = jsonParser.fromJson(body, Object.class);
+        if (!(members instanceof List)) {
+            // A batch rejected as a whole gets a single error response
+            checkResponse(jsonParser.fromJson(body, Response.class));
+            throw new RPCError(-32603, "Internal error", "Unexpected response to batch request");
+        }
+        Map<Object, Response> byId = new HashMap<>();
+        for (Object member : (List<?>) members) {
+            Response response = jsonParser.convert(member, Response.class);
+            if (response.getId() != null) {
+                byId.put(response.getId(), response);
+            }
+        }
+        List<Response> responses = new ArrayList<>();
+        for (Request request : requests) {
+            responses.add(byId.get(request.getId()));
+        }
+        return responses;
+    }
+
+    // Encodes a request body as JSON, or as canonical JSON if setCanonicalJson is on
+    private byte[] encode(Object value) {
+        String json = jsonParser.toJson(value);
+        if (canonicalJson) {
+            json = CanonicalJson.encode(jsonParser.fromJson(json, Object.class));
+        }
+        return json.getBytes(StandardCharsets.UTF_8);
+    }
+
+    // Posts body with the headers options ask for, signed if the transport has a signer,
+    // and returns the response body
+    private String post(byte[] body, CallOptions options) throws Exception {
+        // Without a timeout of its own, a call made while handling another fits in what is
+        // left of that call's deadline
+        Duration timeout = options.getTimeout() != null ? options.getTimeout() : Deadline.remaining();
+
+        HttpRequest.Builder builder = HttpRequest.newBuilder()
+            .uri(URI.create(baseUrl))
+            .header("Content-Type", "application/json; charset=utf-8")
+            .header("Accept-Encoding", "gzip")
+            .POST(HttpRequest.BodyPublishers.ofByteArray(body))
+            .timeout(timeout != null ? timeout : Duration.ofSeconds(30));
+        for (Map.Entry<String, String> header : options.getHeaders().entrySet()) {
+            builder.setHeader(header.getKey(), header.getValue());
+        }
+        if (options.getIdempotencyKey() != null) {
+            builder.setHeader("Idempotency-Key", options.getIdempotencyKey());
+        }
+        if (timeout != null) {
+            builder.setHeader(Deadline.HEADER, Deadline.headerValue(timeout));
+        }
+        RequestSigner requestSigner = signer;
+        if (requestSigner != null) {
+            for (Map.Entry<String, String> header : requestSigner.sign(body).entrySet()) {
+                builder.setHeader(header.getKey(), header.getValue());
+            }
+        }
+        HttpRequest httpRequest = builder.build();
+
+        HttpResponse<InputStream> httpResponse;
+        String responseBody;
+        try {
+            httpResponse = httpClient.send(httpRequest, HttpResponse.BodyHandlers.ofInputStream());
+            responseBody = readBody(httpResponse);
+        } catch (HttpTimeoutException e) {
+            // the server may still be processing the request
+            throw e;
+        } catch (ConnectException e) {
+            throw new TransportException("Connection failed: " + e.getMessage(), 0, true, e);
+        } catch (IOException e) {
+            // the connection was reset or closed before a response was received
+            throw new TransportException("HTTP request failed: " + e.getMessage(), 0, true, e);
+        }
 
         if (httpResponse.statusCode() != 200) {
-            throw new IOException("HTTP error: " + httpResponse.statusCode() + " - " + httpResponse.body());
+            throw new TransportException("HTTP error: " + httpResponse.statusCode() + " - " + responseBody,
+                httpResponse.statusCode(), false, null);
         }
 
-        Response response = jsonParser.fromJson(httpResponse.body(), Response.class);
+        return responseBody;
+    }
+
+    // Reads a response body as UTF-8, gunzipping it if its Content-Encoding is gzip
+    private static String readBody(HttpResponse<InputStream> httpResponse) throws IOException {
+        InputStream in = httpResponse.body();
+        if ("gzip".equalsIgnoreCase(httpResponse.headers().firstValue("Content-Encoding").orElse(""))) {
+            in = new GZIPInputStream(in);
+        }
+        try (InputStream body = in) {
+            return new String(body.readAllBytes(), StandardCharsets.UTF_8);
+        }
+    }
 
+    /**
+     * Returns response, throwing its error as an RPCError if it is an error response
+     */
+    static Response checkResponse(Response response) {
         if (response.hasError()) {
             Map<String, Object> error = response.getError();
             int code = error.containsKey("code") ? ((Number) error.get("code")).intValue() : -32603;
@@ -0,0 +1,99 @@
+package com.bitmechanic.pulserpc;
+
+import java.io.InputStream;
+import java.nio.charset.StandardCharsets;
+import java.util.ArrayList;
+import java.util.Collections;
+import java.util.HashMap;
+import java.util.List;
+import java.util.Map;
+import java.util.logging.Level;
+import java.util.logging.Logger;
+
+/**
+ * The method and struct definitions of the /idl.json resource the generator writes,
+ * read on first use. Call logging and the server's int checks use them.
+ */
+final class IdlTypes {
+
+    private static volatile IdlTypes instance;
+
+    /** Method definitions by "Interface.method", or null if /idl.json cannot be read */
+    final Map<String, Map<String, Object>> methods;
+
+    /**
+     * Struct definitions by simple and qualified name, each field marked "sensitive" if it
+     * is annotated so, or null if /idl.json cannot be read
+     */
+    final Map<String, Map<String, Object>> structs;
+
+    private IdlTypes(Map<String, Map<String, Object>> methods, Map<String, Map<String, Object>> structs) {
+        this.methods = methods;
+        this.structs = structs;
+    }
+
+    /**
+     * Returns the definitions, reading /idl.json with jsonParser on first use
+     */
+    static IdlTypes get(JsonParser jsonParser) {
+        IdlTypes types = instance;
+        if (types == null) {
+            types = load(jsonParser);
+            instance = types;
+        }
+        return types;
+    }
+
+    @SuppressWarnings("unchecked")
+    private static IdlTypes load(JsonParser jsonParser) {
+        try (InputStream in = IdlTypes.class.getResourceAsStream("/idl.json")) {
+            if (in == null) {
+                return new IdlTypes(null, null);
+            }
+            Map<String, Object> doc = jsonParser.fromJson(new String(in.readAllBytes(), StandardCharsets.UTF_8), Map.class);
+            Map<String, Map<String, Object>> methods = new HashMap<>();
+            for (Map<String, Object> iface : list(doc.get("interfaces"))) {
+                for (Map<String, Object> method : list(iface.get("methods"))) {
+                    methods.put(iface.get("name") + "." + method.get("name"), method);
+                }
+            }
+            Map<String, Map<String, Object>> structs = new HashMap<>();
+            for (Map<String, Object> struct : list(doc.get("structs"))) {
+                List<Map<String, Object>> fields = new ArrayList<>();
+                for (Map<String, Object> field : list(struct.get("fields"))) {
+                    Map<String, Object> fieldDef = new HashMap<>(field);
+                    fieldDef.put("sensitive", hasAnnotation(field, "sensitive"));
+                    fields.add(fieldDef);
+                }
+                Map<String, Object> structDef = new HashMap<>();
+                structDef.put("extends", struct.get("extends"));
+                structDef.put("fields", fields);
+                structs.put((String) struct.get("name"), structDef);
+                if (struct.get("namespace") != null) {
+                    structs.put(struct.get("namespace") + "." + struct.get("name"), structDef);
+                }
+            }
+            return new IdlTypes(methods, structs);
+        } catch (Exception e) {
+            Logger.getLogger(IdlTypes.class.getName()).log(Level.FINE, "Cannot read /idl.json", e);
+            return new IdlTypes(null, null);
+        }
+    }
+
+    /**
+     * Returns value as a list of JSON objects, or an empty list if it is not a list
+     */
+    @SuppressWarnings("unchecked")
+    static List<Map<String, Object>> list(Object value) {
+        return value instanceof List ? (List<Map<String, Object>>) value : Collections.emptyList();
+    }
+
+    private static boolean hasAnnotation(Map<String, Object> field, String name) {
+        for (Map<String, Object> annotation : list(field.get("annotations"))) {
+            if (name.equals(annotation.get("name"))) {
+                return true;
+            }
+        }
+        return false;
+    }
+}
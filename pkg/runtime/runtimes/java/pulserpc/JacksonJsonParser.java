@@ -66,6 +66,15 @@ public class JacksonJsonParser implements JsonParser {
         }
     }
 
+    @Override
+    public <T> T convert(Object value, Type type) {
+        try {
+            return objectMapper.convertValue(value, objectMapper.getTypeFactory().constructType(type));
+        } catch (Exception e) {
+            throw new RuntimeException("Failed to convert value", e);
+        }
+    }
+
     /**
      * Get the underlying ObjectMapper for advanced usage
      * @return The ObjectMapper instance
@@ -0,0 +1,67 @@
+package com.bitmechanic.pulserpc;
+
+import java.time.Duration;
+import java.util.Map;
+import java.util.concurrent.TimeoutException;
+
+/**
+ * Waits for the job an [async] method started by polling the built-in pulserpc-job
+ * method. Clients of [async] methods use it so that the call returns the job's
+ * result as if it had been synchronous.
+ */
+public final class JobPoller {
+
+    private JobPoller() {
+    }
+
+    /**
+     * Polls until the job in response has finished, and returns a response holding the
+     * job's result, or throws the job's error as an RPCError. The options' timeout
+     * bounds the whole wait.
+     */
+    public static Response await(Transport transport, Response response, CallOptions options) throws Exception {
+        long started = System.nanoTime();
+        Duration interval = options.getJobPollInterval() != null ? options.getJobPollInterval() : Duration.ofSeconds(1);
+        CallOptions pollOptions = CallOptions.NONE.withTimeout(options.getTimeout());
+        for (Map.Entry<String, String> header : options.getHeaders().entrySet()) {
+            pollOptions = pollOptions.withHeader(header.getKey(), header.getValue());
+        }
+        while (true) {
+            if (!(response.getResult() instanceof Map)) {
+                throw new RPCError(-32603, "Internal error", "Invalid job status: " + response.getResult());
+            }
+            Map<?, ?> status = (Map<?, ?>) response.getResult();
+            String jobId = String.valueOf(status.get("jobId"));
+            String state = String.valueOf(status.get("state"));
+            if (options.getJobProgress() != null) {
+                options.getJobProgress().accept(new JobStatus(jobId, state));
+            }
+            switch (state) {
+                case "succeeded":
+                    Response done = new Response();
+                    done.setResult(status.get("result"));
+                    done.setId(response.getId());
+                    done.setMeta(response.getMeta());
+                    return done;
+                case "failed":
+                    Object error = status.get("error");
+                    if (!(error instanceof Map)) {
+                        throw new RPCError(-32603, "Internal error", "Job " + jobId + " failed");
+                    }
+                    Map<?, ?> fields = (Map<?, ?>) error;
+                    int code = fields.get("code") instanceof Number ? ((Number) fields.get("code")).intValue() : -32603;
+                    throw new RPCError(code, String.valueOf(fields.get("message")), fields.get("data"));
+                case "running":
+                    break;
+                default:
+                    throw new RPCError(-32603, "Internal error", "Invalid job status: " + status);
+            }
+            Duration elapsed = Duration.ofNanos(System.nanoTime() - started);
+            if (options.getTimeout() != null && elapsed.plus(interval).compareTo(options.getTimeout()) > 0) {
+                throw new TimeoutException("job " + jobId + " did not finish within " + options.getTimeout());
+            }
+            Thread.sleep(interval.toMillis());
+            response = transport.call(new Request("pulserpc-job", new Object[] { jobId }, java.util.UUID.randomUUID().toString()), pollOptions);
+        }
+    }
+}
@@ -0,0 +1,28 @@
+package com.bitmechanic.pulserpc;
+
+/**
+ * The state of the job an [async] method started, as passed to the job progress
+ * callback after each poll. The state is "running", "succeeded" or "failed".
+ */
+public final class JobStatus {
+    private final String jobId;
+    private final String state;
+
+    public JobStatus(String jobId, String state) {
+        this.jobId = jobId;
+        this.state = state;
+    }
+
+    public String getJobId() {
+        return jobId;
+    }
+
+    public String getState() {
+        return state;
+    }
+
+    @Override
+    public String toString() {
+        return "JobStatus{jobId=" + jobId + ", state=" + state + "}";
+    }
+}
@@ -40,4 +40,17 @@ public interface JsonParser {
      * @return The deserialized object
      */
     <T> T fromJson(String json, Type type);
+
+    /**
+     * Convert a value read by this parser, such as the Maps and Lists fromJson returns,
+     * into an object of the specified type. The default encodes the value and parses it
+     * again; parsers that can convert without the JSON text override it.
+     * @param value The value to convert
+     * @param type The target type
+     * @param <T> The type of the target object
+     * @return The converted object
+     */
+    default <T> T convert(Object value, Type type) {
+        return fromJson(toJson(value), type);
+    }
 }
@@ -0,0 +1,17 @@
+package com.bitmechanic.pulserpc;
+
+/**
+ * The policy for int values written with a fraction or exponent, such as 2.0. A float
+ * accepts any number under either policy, and an int with a fractional part, such as 2.5,
+ * is always rejected.
+ */
+public enum NumberPolicy {
+    /** Accept any number with no fractional part for int, so 2.0 is taken as 2 (the default) */
+    LENIENT,
+    /**
+     * Accept only integer literals, such as 2, for int. The JsonParser must read integer
+     * literals as Integer or Long and others as Double, as JacksonJsonParser does; Gson
+     * does so with ToNumberPolicy.LONG_OR_DOUBLE.
+     */
+    STRICT
+}
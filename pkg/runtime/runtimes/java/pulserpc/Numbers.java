@@ -0,0 +1,87 @@
+package com.bitmechanic.pulserpc;
+
+import java.math.BigInteger;
+import java.util.List;
+import java.util.Map;
+
+/**
+ * Checks the int values of decoded params against a NumberPolicy
+ */
+public final class Numbers {
+
+    private Numbers() {
+    }
+
+    /**
+     * Checks the params of a call to method ("Interface.method"), as decoded from JSON, in
+     * order, with the parameter types read from /idl.json. Calls are not checked if it
+     * cannot be read.
+     * @throws IllegalArgumentException naming the first param that breaks the policy
+     */
+    @SuppressWarnings("unchecked")
+    public static void checkParams(JsonParser jsonParser, String method, List<?> params, NumberPolicy policy) {
+        IdlTypes types = IdlTypes.get(jsonParser);
+        Map<String, Object> methodDef = types.methods != null ? types.methods.get(method) : null;
+        if (methodDef == null) {
+            return;
+        }
+        List<Map<String, Object>> paramDefs = IdlTypes.list(methodDef.get("parameters"));
+        for (int i = 0; i < params.size() && i < paramDefs.size(); i++) {
+            String name = (String) paramDefs.get(i).get("name");
+            try {
+                checkInts(params.get(i), (Map<String, Object>) paramDefs.get(i).get("type"), types.structs, policy);
+            } catch (IllegalArgumentException e) {
+                throw new IllegalArgumentException("Parameter " + i + " (" + name + ") validation failed: " + e.getMessage(), e);
+            }
+        }
+    }
+
+    /**
+     * Checks every value of type int in a decoded JSON value of the given type: it must have
+     * no fractional part and, under STRICT, be written as an integer literal
+     * @throws IllegalArgumentException naming the first value that breaks the policy
+     */
+    @SuppressWarnings("unchecked")
+    public static void checkInts(Object value, Map<String, Object> typeDef, Map<String, Map<String, Object>> allStructs, NumberPolicy policy) {
+        if (value == null || typeDef == null) {
+            return;
+        }
+        if ("int".equals(typeDef.get("builtIn"))) {
+            if (value instanceof Integer || value instanceof Long || value instanceof BigInteger || !(value instanceof Number)) {
+                return;
+            }
+            double d = ((Number) value).doubleValue();
+            if (Math.floor(d) != d || policy == NumberPolicy.STRICT) {
+                throw new IllegalArgumentException("Expected int, got " + value);
+            }
+        } else if (typeDef.get("array") instanceof Map && value instanceof List) {
+            List<?> items = (List<?>) value;
+            for (int i = 0; i < items.size(); i++) {
+                try {
+                    checkInts(items.get(i), (Map<String, Object>) typeDef.get("array"), allStructs, policy);
+                } catch (IllegalArgumentException e) {
+                    throw new IllegalArgumentException("Array element at index " + i + " validation failed: " + e.getMessage(), e);
+                }
+            }
+        } else if (typeDef.get("mapValue") instanceof Map && value instanceof Map) {
+            for (Map.Entry<?, ?> entry : ((Map<?, ?>) value).entrySet()) {
+                try {
+                    checkInts(entry.getValue(), (Map<String, Object>) typeDef.get("mapValue"), allStructs, policy);
+                } catch (IllegalArgumentException e) {
+                    throw new IllegalArgumentException("Map value for key '" + entry.getKey() + "' validation failed: " + e.getMessage(), e);
+                }
+            }
+        } else if (typeDef.get("userDefined") instanceof String && value instanceof Map && allStructs != null
+                && Types.findStruct((String) typeDef.get("userDefined"), allStructs) != null) {
+            String structName = (String) typeDef.get("userDefined");
+            for (Map<String, Object> field : Types.getStructFields(structName, allStructs)) {
+                String fieldName = (String) field.get("name");
+                try {
+                    checkInts(((Map<?, ?>) value).get(fieldName), (Map<String, Object>) field.get("type"), allStructs, policy);
+                } catch (IllegalArgumentException e) {
+                    throw new IllegalArgumentException("Field '" + fieldName + "' in struct " + structName + " validation failed: " + e.getMessage(), e);
+                }
+            }
+        }
+    }
+}
@@ -0,0 +1,67 @@
+package com.bitmechanic.pulserpc;
+
+import java.util.ArrayList;
+import java.util.LinkedHashMap;
+import java.util.List;
+import java.util.Map;
+
+/**
+ * Masks [sensitive] struct fields in JSON values formatted for logs, held as the Maps and
+ * Lists a JsonParser reads. The JSON sent on the wire is not affected.
+ */
+public final class Redaction {
+
+    /**
+     * Replaces the value of a sensitive field in redacted output
+     */
+    public static final String REDACTED = "***";
+
+    private Redaction() {
+    }
+
+    /**
+     * Returns a copy of a JSON value of the given type in which every non-null sensitive
+     * struct field, including those of nested structs, holds REDACTED. The value itself is
+     * not changed.
+     */
+    @SuppressWarnings("unchecked")
+    public static Object redact(Object value, Map<String, Object> typeDef, Map<String, Map<String, Object>> allStructs) {
+        if (value == null || typeDef == null) {
+            return value;
+        }
+        if (typeDef.get("array") instanceof Map && value instanceof List) {
+            Map<String, Object> elementType = (Map<String, Object>) typeDef.get("array");
+            List<Object> copy = new ArrayList<>();
+            for (Object item : (List<?>) value) {
+                copy.add(redact(item, elementType, allStructs));
+            }
+            return copy;
+        }
+        if (typeDef.get("mapValue") instanceof Map && value instanceof Map) {
+            Map<String, Object> valueType = (Map<String, Object>) typeDef.get("mapValue");
+            Map<String, Object> copy = new LinkedHashMap<>();
+            for (Map.Entry<?, ?> entry : ((Map<?, ?>) value).entrySet()) {
+                copy.put(String.valueOf(entry.getKey()), redact(entry.getValue(), valueType, allStructs));
+            }
+            return copy;
+        }
+        Object structName = typeDef.get("userDefined");
+        if (structName instanceof String && value instanceof Map && Types.findStruct((String) structName, allStructs) != null) {
+            Map<String, Object> copy = new LinkedHashMap<>((Map<String, Object>) value);
+            for (Map<String, Object> field : Types.getStructFields((String) structName, allStructs)) {
+                String name = (String) field.get("name");
+                Object item = copy.get(name);
+                if (item == null) {
+                    continue;
+                }
+                if (Boolean.TRUE.equals(field.get("sensitive"))) {
+                    copy.put(name, REDACTED);
+                } else if (field.get("type") instanceof Map) {
+                    copy.put(name, redact(item, (Map<String, Object>) field.get("type"), allStructs));
+                }
+            }
+            return copy;
+        }
+        return value;
+    }
+}
@@ -0,0 +1,81 @@
+package com.bitmechanic.pulserpc;
+
+import com.sun.net.httpserver.Headers;
+import com.sun.net.httpserver.HttpExchange;
+import java.net.InetSocketAddress;
+
+/**
+ * The HTTP request of the call being handled. Servers begin it for each request on the
+ * thread that handles it, and handlers read the caller's headers, such as an
+ * authorization token or a trace id, and address with current(). Handlers must not
+ * read the request body or send a response through the exchange.
+ */
+public final class RequestMeta {
+
+    private static final ThreadLocal<RequestMeta> CURRENT = new ThreadLocal<>();
+
+    private final HttpExchange exchange;
+
+    private RequestMeta(HttpExchange exchange) {
+        this.exchange = exchange;
+    }
+
+    /**
+     * Ends the request begun on a thread, restoring the one before it
+     */
+    public static final class Scope implements AutoCloseable {
+        private final RequestMeta previous;
+
+        private Scope(RequestMeta previous) {
+            this.previous = previous;
+        }
+
+        @Override
+        public void close() {
+            if (previous == null) {
+                CURRENT.remove();
+            } else {
+                CURRENT.set(previous);
+            }
+        }
+    }
+
+    /**
+     * Makes exchange the request of the call handled on this thread until the returned
+     * scope is closed
+     */
+    public static Scope begin(HttpExchange exchange) {
+        Scope scope = new Scope(CURRENT.get());
+        CURRENT.set(new RequestMeta(exchange));
+        return scope;
+    }
+
+    /**
+     * Returns the request of the call being handled, or null for calls that did not
+     * arrive over HTTP, such as those of handleRequest
+     */
+    public static RequestMeta current() {
+        return CURRENT.get();
+    }
+
+    /**
+     * The HTTP exchange the call arrived in
+     */
+    public HttpExchange exchange() {
+        return exchange;
+    }
+
+    /**
+     * The request headers
+     */
+    public Headers headers() {
+        return exchange.getRequestHeaders();
+    }
+
+    /**
+     * The address of the caller
+     */
+    public InetSocketAddress remoteAddress() {
+        return exchange.getRemoteAddress();
+    }
+}
@@ -0,0 +1,18 @@
+package com.bitmechanic.pulserpc;
+
+import java.util.Map;
+
+/**
+ * Computes authentication headers for an outgoing request, such as an HMAC
+ * signature, from the serialized request body
+ */
+@FunctionalInterface
+public interface RequestSigner {
+    /**
+     * Sign a request
+     * @param body The JSON-RPC request exactly as it is sent
+     * @return Headers to add to the HTTP request
+     * @throws Exception if the request cannot be signed
+     */
+    Map<String, String> sign(byte[] body) throws Exception;
+}
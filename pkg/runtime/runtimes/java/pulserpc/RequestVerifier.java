@@ -0,0 +1,17 @@
+package com.bitmechanic.pulserpc;
+
+import com.sun.net.httpserver.Headers;
+
+/**
+ * Checks an incoming request before the server dispatches it
+ */
+@FunctionalInterface
+public interface RequestVerifier {
+    /**
+     * Verify a request
+     * @param headers The HTTP request headers
+     * @param body The raw request body, empty for GET requests
+     * @throws Exception to reject the request with HTTP 401
+     */
+    void verify(Headers headers, byte[] body) throws Exception;
+}
@@ -10,6 +10,7 @@ public class Response {
     private Object result;
     private Map<String, Object> error;
     private Object id;
+    private Map<String, Object> meta;
 
     public Response() {
         this.jsonrpc = "2.0";
@@ -47,6 +48,18 @@ public class Response {
         this.id = id;
     }
 
+    /**
+     * Metadata the server attached to the response, such as timings, pagination hints
+     * or warnings, or null
+     */
+    public Map<String, Object> getMeta() {
+        return meta;
+    }
+
+    public void setMeta(Map<String, Object> meta) {
+        this.meta = meta;
+    }
+
     public boolean hasError() {
         return error != null;
     }
@@ -11,5 +11,17 @@ public interface Transport {
      * @throws Exception if the call fails
      */
     Response call(Request request) throws Exception;
+
+    /**
+     * Make an RPC call with per-call options. Transports that honor the options
+     * override this; the default ignores them.
+     * @param request The JSON-RPC request
+     * @param options Timeout, headers and idempotency key for this call
+     * @return The JSON-RPC response
+     * @throws Exception if the call fails
+     */
+    default Response call(Request request, CallOptions options) throws Exception {
+        return call(request);
+    }
 }
 
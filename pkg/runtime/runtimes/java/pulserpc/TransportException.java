@@ -0,0 +1,41 @@
+package com.bitmechanic.pulserpc;
+
+import java.io.IOException;
+
+/**
+ * Thrown when no JSON-RPC response was received: the connection failed, or the
+ * server answered with an HTTP error status
+ */
+public class TransportException extends IOException {
+
+    private final int statusCode;
+    private final boolean retryable;
+
+    /**
+     * Creates a new TransportException instance
+     * @param message Error message
+     * @param statusCode HTTP status code, or 0 if no response was received
+     * @param retryable True for failures that draining or restarting servers produce
+     * @param cause Underlying I/O failure, or null
+     */
+    public TransportException(String message, int statusCode, boolean retryable, Throwable cause) {
+        super(message, cause);
+        this.statusCode = statusCode;
+        this.retryable = retryable || statusCode == 502 || statusCode == 503;
+    }
+
+    /**
+     * HTTP status code, or 0 if no response was received
+     */
+    public int getStatusCode() {
+        return statusCode;
+    }
+
+    /**
+     * True for connection refused, connection reset or closed before a response,
+     * and HTTP 502/503
+     */
+    public boolean isRetryable() {
+        return retryable;
+    }
+}
@@ -21,14 +21,22 @@ public class Validation {
     }
 
     /**
-     * Validate that value is an int
+     * Validate that value is an int, or a Long or Double holding a whole number in int
+     * range, such as 2.0 (see NumberPolicy)
      */
     public static void validateInt(Object value) {
+        if ((value instanceof Long || value instanceof Double) && isWholeInt(((Number) value).doubleValue())) {
+            return;
+        }
         if (!(value instanceof Integer)) {
             throw new IllegalArgumentException("Expected int, got " + getTypeName(value));
         }
     }
 
+    private static boolean isWholeInt(double d) {
+        return Math.floor(d) == d && d >= Integer.MIN_VALUE && d <= Integer.MAX_VALUE;
+    }
+
     /**
      * Validate that value is a float or int
      */
@@ -0,0 +1,98 @@
+import com.bitmechanic.pulserpc.*;
+import org.junit.Test;
+import org.junit.Assert;
+
+import java.util.ArrayList;
+import java.util.List;
+import java.util.Map;
+import java.util.UUID;
+
+public class BatchTest {
+
+    // Answers each member of a batch with the quotient of its params, or an error for a
+    // division by zero
+    private static class DivideTransport implements Transport, BatchTransport {
+        int batches;
+
+        @Override
+        public Response call(Request request) {
+            throw new AssertionError("calls should be sent in one batch");
+        }
+
+        @Override
+        public List<Response> callBatch(List<Request> requests, CallOptions options) {
+            batches++;
+            List<Response> responses = new ArrayList<>();
+            for (Request request : requests) {
+                List<?> params = (List<?>) request.getParams();
+                int a = (Integer) params.get(0);
+                int b = (Integer) params.get(1);
+                Response response = new Response();
+                response.setId(request.getId());
+                if (b == 0) {
+                    response.setError(Map.of("code", 1001, "message", "division by zero"));
+                } else {
+                    response.setResult(a / b);
+                }
+                responses.add(response);
+            }
+            return responses;
+        }
+    }
+
+    // Makes a call as a generated client method does, wrapping anything but an RPCError
+    private static int divide(Transport transport, int a, int b) {
+        try {
+            Response response = transport.call(new Request("Calc.divide", List.of(a, b), UUID.randomUUID().toString()));
+            return (Integer) response.getResult();
+        } catch (Exception e) {
+            if (e instanceof RPCError) {
+                throw (RPCError) e;
+            }
+            throw new RPCError(-32603, "Internal error", e.getMessage());
+        }
+    }
+
+    @Test
+    public void testPerCallResultsAndErrors() throws Exception {
+        DivideTransport transport = new DivideTransport();
+        Batch batch = new Batch(transport);
+        BatchResult<Integer> half = batch.add(() -> divide(batch, 8, 2));
+        BatchResult<Integer> byZero = batch.add(() -> divide(batch, 1, 0));
+        BatchResult<Integer> third = batch.add(() -> divide(batch, 9, 3));
+        Assert.assertEquals(3, batch.size());
+
+        batch.send();
+        Assert.assertEquals(1, transport.batches);
+        Assert.assertEquals(0, batch.size());
+
+        Assert.assertEquals(Integer.valueOf(4), half.getResult());
+        Assert.assertNull(half.getError());
+        Assert.assertNull(byZero.getResult());
+        Assert.assertTrue(byZero.getError() instanceof RPCError);
+        Assert.assertEquals(1001, ((RPCError) byZero.getError()).getCode());
+        Assert.assertEquals(Integer.valueOf(3), third.getResult());
+        Assert.assertNull(third.getError());
+    }
+
+    @Test
+    public void testFailedBatchIsTheErrorOfEveryCall() {
+        Batch batch = new Batch(new DivideTransport() {
+            @Override
+            public List<Response> callBatch(List<Request> requests, CallOptions options) {
+                throw new RPCError(-32000, "unavailable");
+            }
+        });
+        BatchResult<Integer> first = batch.add(() -> divide(batch, 8, 2));
+        BatchResult<Integer> second = batch.add(() -> divide(batch, 9, 3));
+
+        try {
+            batch.send();
+            Assert.fail("send should throw the error of the batch request");
+        } catch (Exception e) {
+            Assert.assertEquals("unavailable", e.getMessage());
+        }
+        Assert.assertEquals("unavailable", first.getError().getMessage());
+        Assert.assertEquals("unavailable", second.getError().getMessage());
+    }
+}
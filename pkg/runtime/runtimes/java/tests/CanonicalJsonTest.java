@@ -0,0 +1,72 @@
+import com.bitmechanic.pulserpc.*;
+import org.junit.Test;
+import org.junit.Assert;
+import java.util.*;
+
+public class CanonicalJsonTest {
+
+    // The same vectors are checked by every other runtime's tests, so every runtime
+    // hashes a call the same way, byte for byte
+    private static final Object[][] REQUEST_HASH_VECTORS = {
+        {
+            "Catalog.get",
+            List.of("p-1", 2),
+            "{\"method\":\"Catalog.get\",\"params\":[\"p-1\",2]}",
+            "c83d6e2a3e0908ddb5e94fe05cffd450756e92caab52d017ad51be7b97fcb715",
+        },
+        {
+            "Catalog.search",
+            Arrays.asList(
+                Map.of("query", "café \"x\"\n", "limit", 10, "tags", List.of("a", "b"), "price", Map.of("max", 99.5, "min", 0.1)),
+                null),
+            "{\"method\":\"Catalog.search\",\"params\":[{\"limit\":10,\"price\":{\"max\":99.5,\"min\":0.1},\"query\":\"café \\\"x\\\"\\n\",\"tags\":[\"a\",\"b\"]},null]}",
+            "bac87c8eb687a2e88fb49b08c30f0668d849c06613310acd110c66d42acc56ae",
+        },
+        {
+            "Catalog.bulk",
+            Map.of("ids", List.of(1e21, 1e-7, -0.0, 1L << 60), "é", true, "a", false, "\uD83D\uDE00", 1, "｡", 2),
+            "{\"method\":\"Catalog.bulk\",\"params\":{\"a\":false,\"ids\":[1e+21,1e-7,0,1152921504606847000],\"é\":true,\"\uD83D\uDE00\":1,\"｡\":2}}",
+            "53bd8c11499fccf79fedaff23d160964bba118c23421f68f3167c40eb2a7750b",
+        },
+        // 2^53+1 is not a double: canonical JSON writes the nearest one, 2^53
+        {
+            "Catalog.edges",
+            List.of(1e21, 1e-7, -0.0, (1L << 53) + 1),
+            "{\"method\":\"Catalog.edges\",\"params\":[1e+21,1e-7,0,9007199254740992]}",
+            "379f1ac102b547987989aaba1ce4f935c28a1d5b880068e36ffd2b017f2f98e5",
+        },
+    };
+
+    @Test
+    public void testRequestHashVectors() {
+        for (Object[] vector : REQUEST_HASH_VECTORS) {
+            String method = (String) vector[0];
+            Map<String, Object> call = new HashMap<>();
+            call.put("method", method);
+            call.put("params", vector[1]);
+            Assert.assertEquals(method, vector[2], CanonicalJson.encode(call));
+            Assert.assertEquals(method, vector[3], CanonicalJson.requestHash(method, vector[1]));
+        }
+    }
+
+    @Test
+    public void testCanonicalJsonNumbers() {
+        Map<Double, String> cases = new LinkedHashMap<>();
+        cases.put(0.0, "0");
+        cases.put(-1.5, "-1.5");
+        cases.put(123.456, "123.456");
+        cases.put(1e20, "100000000000000000000");
+        cases.put(1e-6, "0.000001");
+        cases.put(1.5e300, "1.5e+300");
+        cases.put(5e-324, "5e-324");
+        cases.put(-2.5e-10, "-2.5e-10");
+        for (Map.Entry<Double, String> c : cases.entrySet()) {
+            Assert.assertEquals(c.getValue(), CanonicalJson.encode(c.getKey()));
+        }
+    }
+
+    @Test(expected = IllegalArgumentException.class)
+    public void testCanonicalJsonRejectsNaN() {
+        CanonicalJson.encode(Double.NaN);
+    }
+}
@@ -77,6 +77,16 @@ public class JsonParserTest {
         Assert.assertEquals(true, result.get("active"));
     }
 
+    @Test
+    public void testConvert() {
+        for (JsonParser parser : List.of(new JacksonJsonParser(), new GsonJsonParser())) {
+            Map<String, Object> data = parser.fromJson("{\"nums\": [1, 2, 3]}", Map.class);
+            java.lang.reflect.Type type = new com.google.gson.reflect.TypeToken<List<Integer>>(){}.getType();
+            List<Integer> nums = parser.convert(data.get("nums"), type);
+            Assert.assertEquals(List.of(1, 2, 3), nums);
+        }
+    }
+
     @Test
     public void testJacksonCustomObjectMapper() {
         ObjectMapper customMapper = new ObjectMapper();
@@ -117,4 +117,44 @@ public class ValidationTest {
             Assert.assertTrue(e.getMessage().contains("Expected string"));
         }
     }
+
+    @Test
+    public void testValidateIntWholeNumbers() {
+        // The lenient number policy takes 2.0 as 2
+        Validation.validateInt(2.0);
+        Validation.validateInt(2L);
+
+        try {
+            Validation.validateInt(2.5);
+            Assert.fail("Expected IllegalArgumentException");
+        } catch (IllegalArgumentException e) {
+            Assert.assertTrue(e.getMessage().contains("Expected int"));
+        }
+    }
+
+    @Test
+    public void testCheckInts() {
+        Map<String, Object> intType = new HashMap<>();
+        intType.put("builtIn", "int");
+        Numbers.checkInts(2, intType, null, NumberPolicy.STRICT);
+        Numbers.checkInts(2.0, intType, null, NumberPolicy.LENIENT);
+
+        // Strict accepts only integer literals
+        try {
+            Numbers.checkInts(2.0, intType, null, NumberPolicy.STRICT);
+            Assert.fail("Expected IllegalArgumentException");
+        } catch (IllegalArgumentException e) {
+            Assert.assertTrue(e.getMessage().contains("Expected int"));
+        }
+
+        // A fractional part is rejected under either policy
+        Map<String, Object> arrayType = new HashMap<>();
+        arrayType.put("array", intType);
+        try {
+            Numbers.checkInts(Arrays.asList(1, 2.5), arrayType, null, NumberPolicy.LENIENT);
+            Assert.fail("Expected IllegalArgumentException");
+        } catch (IllegalArgumentException e) {
+            Assert.assertTrue(e.getMessage().contains("index 1"));
+        }
+    }
 }
@@ -20,6 +20,42 @@ from .types import (
     find_struct,
     find_enum,
     get_struct_fields,
+    clone_struct,
+    plain_value,
+    redact_value,
+    redact_struct,
+    REDACTED,
+)
+from .encryption import (
+    FieldCipher,
+    encrypt_fields,
+    decrypt_fields,
+)
+from .canonical import (
+    canonical_json,
+    request_hash,
+)
+from .inflight import InFlight
+from .metrics import MethodMetrics
+from .faults import (
+    Fault,
+    FaultConfig,
+)
+from .numbers import (
+    LENIENT,
+    STRICT,
+    check_int_literals,
+    normalize_ints,
+)
+from .deadline import (
+    DEADLINE_HEADER,
+    deadline_header_value,
+    deadline_scope,
+    remaining_time,
+)
+from .compose import (
+    Composition,
+    merge_idl_documents,
 )
 
 __all__ = [
@@ -36,5 +72,29 @@ __all__ = [
     "find_struct",
     "find_enum",
     "get_struct_fields",
+    "clone_struct",
+    "plain_value",
+    "redact_value",
+    "redact_struct",
+    "REDACTED",
+    "FieldCipher",
+    "encrypt_fields",
+    "decrypt_fields",
+    "Fault",
+    "FaultConfig",
+    "canonical_json",
+    "request_hash",
+    "InFlight",
+    "MethodMetrics",
+    "LENIENT",
+    "STRICT",
+    "check_int_literals",
+    "normalize_ints",
+    "DEADLINE_HEADER",
+    "deadline_header_value",
+    "deadline_scope",
+    "remaining_time",
+    "Composition",
+    "merge_idl_documents",
 ]
 
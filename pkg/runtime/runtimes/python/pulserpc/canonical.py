@@ -0,0 +1,65 @@
+"""Canonical JSON and request hashes that match across runtimes"""
+
+import hashlib
+import json
+import math
+from decimal import Decimal
+from typing import Any
+
+
+def request_hash(method: str, params: Any) -> str:
+    """Return the SHA-256, in lowercase hex, of the canonical JSON of
+    {"method": method, "params": params}.
+
+    Every runtime computes the same hash for the same call, so it can key
+    idempotency records, caches and deduplication across services written in
+    different languages. Params sent by position and by name hash differently.
+    """
+    canonical = canonical_json({'method': method, 'params': params})
+    return hashlib.sha256(canonical.encode('utf-8')).hexdigest()
+
+
+def canonical_json(value: Any) -> str:
+    """Encode value as canonical JSON (RFC 8785): no whitespace, object keys sorted
+    by their UTF-16 code units, numbers formatted as JavaScript does and strings
+    escaped only where JSON requires it"""
+    if value is None:
+        return 'null'
+    if value is True:
+        return 'true'
+    if value is False:
+        return 'false'
+    if isinstance(value, (int, float)):
+        return _canonical_number(float(value))
+    if isinstance(value, str):
+        return json.dumps(value, ensure_ascii=False)
+    if isinstance(value, (list, tuple)):
+        return '[' + ','.join(canonical_json(item) for item in value) + ']'
+    if isinstance(value, dict):
+        keys = sorted(value, key=lambda key: key.encode('utf-16-be'))
+        return '{' + ','.join(json.dumps(key, ensure_ascii=False) + ':' + canonical_json(value[key])
+                              for key in keys) + '}'
+    raise TypeError(f"cannot canonicalize {type(value).__name__}")
+
+
+def _canonical_number(f: float) -> str:
+    """Format f as JavaScript's Number.prototype.toString does"""
+    if math.isnan(f) or math.isinf(f):
+        raise ValueError(f"cannot canonicalize {f}")
+    if f == 0:
+        return '0'
+    sign = '-' if f < 0 else ''
+    # repr gives the shortest digits that round-trip; n is the position of the
+    # decimal point relative to them: f = 0.digits * 10^n
+    _, digit_tuple, exponent = Decimal(repr(abs(f))).as_tuple()
+    digits = ''.join(str(d) for d in digit_tuple).rstrip('0')
+    n = len(digit_tuple) + exponent
+    k = len(digits)
+    if k <= n <= 21:
+        return sign + digits + '0' * (n - k)
+    if 0 < n <= 21:
+        return sign + digits[:n] + '.' + digits[n:]
+    if -6 < n <= 0:
+        return sign + '0.' + '0' * -n + digits
+    mantissa = digits if k == 1 else digits[0] + '.' + digits[1:]
+    return f"{sign}{mantissa}e{'+' if n - 1 >= 0 else '-'}{abs(n - 1)}"
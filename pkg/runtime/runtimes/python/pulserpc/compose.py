@@ -0,0 +1,81 @@
+"""Server composition: serving services generated from different IDLs on one
+port. A server composes others, whose calls it routes by interface name from
+its own endpoint, and mounts others under a path, where they serve their whole
+HTTP handling. Composed servers only need handles(), handle_request() and
+idl_document(), and mounted ones handle_http(), as every generated
+PulseRPCServer has."""
+
+from typing import Any, Dict, List, Optional, Tuple
+from urllib.parse import urlsplit
+
+_IDL_SECTIONS = ('interfaces', 'structs', 'enums', 'typedefs')
+
+
+class Composition:
+    """What a server serves besides its own interfaces. Empty when created."""
+
+    def __init__(self) -> None:
+        self._servers: List[Any] = []
+        self._mounts: List[Tuple[str, Any]] = []
+
+    def add(self, server: Any) -> None:
+        """Route calls for the interfaces of server to it. A method the composing
+        server handles itself stays with it, and of the added servers the first
+        that handles a method gets it."""
+        self._servers.append(server)
+
+    def mount(self, path: str, server: Any) -> None:
+        """Serve server under path: requests to path and below go to its
+        handle_http() with path removed from the front of their target, so a
+        server mounted at '/billing' sees a POST to '/billing' as a POST to '/'."""
+        self._mounts.append(('/' + path.strip('/'), server))
+
+    def find(self, method: str) -> Optional[Any]:
+        """Return the added server that handles method, or None"""
+        return next((server for server in self._servers if server.handles(method)), None)
+
+    def route(self, target: str) -> Optional[Tuple[Any, str]]:
+        """Return the server of the mount with the longest path that target is
+        under and target made relative to the mount, or None if it is under none"""
+        url = urlsplit(target)
+        best: Optional[Tuple[str, Any]] = None
+        for path, server in self._mounts:
+            if url.path != path and not url.path.startswith(path + '/'):
+                continue
+            if best is None or len(path) > len(best[0]):
+                best = (path, server)
+        if best is None:
+            return None
+        routed = '/' + url.path[len(best[0]):].lstrip('/')
+        if url.query:
+            routed += '?' + url.query
+        return best[1], routed
+
+    def merge_idl(self, doc: Dict[str, Any]) -> Dict[str, Any]:
+        """Return doc with the idl.json documents of every added server merged in"""
+        if not self._servers:
+            return doc
+        return merge_idl_documents(doc, *(server.idl_document() for server in self._servers))
+
+
+def merge_idl_documents(*docs: Dict[str, Any]) -> Dict[str, Any]:
+    """Merge idl.json documents into one that lists the interfaces, structs,
+    enums and typedefs of all of them, in order, each name once, so a type from
+    an IDL both include is listed once. The rootNamespace of the first document
+    is kept."""
+    merged: Dict[str, Any] = {}
+    if docs and 'rootNamespace' in docs[0]:
+        merged['rootNamespace'] = docs[0]['rootNamespace']
+    for section in _IDL_SECTIONS:
+        seen = set()
+        elements = []
+        for doc in docs:
+            for element in doc.get(section) or []:
+                name = element.get('name') if isinstance(element, dict) else None
+                if name in seen:
+                    continue
+                seen.add(name)
+                elements.append(element)
+        if elements:
+            merged[section] = elements
+    return merged
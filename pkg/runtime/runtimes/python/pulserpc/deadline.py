@@ -0,0 +1,45 @@
+"""Request deadlines: the time a caller allows for a call, sent in the
+X-PulseRPC-Deadline header as milliseconds. Servers note the deadline of the call
+being handled, handlers read what is left of it with remaining_time(), and clients
+use that as the timeout of calls made while handling it, so downstream calls fit
+in the caller's budget."""
+
+import time
+from contextlib import contextmanager
+from contextvars import ContextVar
+from typing import Iterator, Optional
+
+DEADLINE_HEADER = 'X-PulseRPC-Deadline'
+
+# The time.monotonic() value at which the call being handled expires
+_deadline: ContextVar[Optional[float]] = ContextVar('pulserpc_deadline', default=None)
+
+
+def deadline_header_value(timeout: float) -> str:
+    """Format a timeout in seconds as an X-PulseRPC-Deadline header value"""
+    return str(int(timeout * 1000))
+
+
+@contextmanager
+def deadline_scope(header_value: Optional[str]) -> Iterator[None]:
+    """Set the deadline of the call handled inside the block from its
+    X-PulseRPC-Deadline header value. A missing or malformed value leaves the
+    call without a deadline."""
+    try:
+        deadline: Optional[float] = time.monotonic() + int(header_value or '') / 1000
+    except ValueError:
+        deadline = None
+    token = _deadline.set(deadline)
+    try:
+        yield
+    finally:
+        _deadline.reset(token)
+
+
+def remaining_time() -> Optional[float]:
+    """Return the seconds left before the deadline of the call being handled, zero
+    once it has passed, or None if the call has no deadline"""
+    deadline = _deadline.get()
+    if deadline is None:
+        return None
+    return max(0.0, deadline - time.monotonic())
@@ -0,0 +1,76 @@
+"""Encryption of struct fields annotated [encrypted]"""
+
+import json
+from typing import Any, Callable, Dict, Optional, Protocol
+
+from .types import find_struct
+
+
+class FieldCipher(Protocol):
+    """Encrypts and decrypts the values of struct fields annotated [encrypted],
+    typically with a data key from a KMS.
+
+    field names the field as "Struct.field" so a cipher can choose keys per
+    field. plaintext is the JSON encoding of the field's value, and the
+    ciphertext is sent as a JSON string.
+    """
+
+    def encrypt(self, field: str, plaintext: bytes) -> str:
+        ...
+
+    def decrypt(self, field: str, ciphertext: str) -> bytes:
+        ...
+
+
+def encrypt_fields(value: Any, type_def: Dict[str, Any], all_structs: Dict[str, Any],
+                   cipher: Optional[FieldCipher]) -> Any:
+    """Return a copy of a value of the given type in which every non-None
+    [encrypted] struct field holds its ciphertext. The value itself is not changed."""
+    def encrypt(field: str, item: Any) -> Any:
+        if cipher is None:
+            raise ValueError(f"No field cipher set to encrypt field {field}")
+        plaintext = json.dumps(item, separators=(',', ':')).encode('utf-8')
+        return cipher.encrypt(field, plaintext)
+    return _transform_fields(value, type_def, all_structs, encrypt)
+
+
+def decrypt_fields(value: Any, type_def: Dict[str, Any], all_structs: Dict[str, Any],
+                   cipher: Optional[FieldCipher]) -> Any:
+    """Reverse encrypt_fields"""
+    def decrypt(field: str, item: Any) -> Any:
+        if cipher is None:
+            raise ValueError(f"No field cipher set to decrypt field {field}")
+        if not isinstance(item, str):
+            raise ValueError(f"Encrypted field {field} must be a string, got {type(item).__name__}")
+        return json.loads(cipher.decrypt(field, item))
+    return _transform_fields(value, type_def, all_structs, decrypt)
+
+
+def _transform_fields(value: Any, type_def: Dict[str, Any], all_structs: Dict[str, Any],
+                      fn: Callable[[str, Any], Any]) -> Any:
+    """Apply fn to the non-None [encrypted] fields of the structs in value"""
+    if value is None:
+        return None
+    if 'array' in type_def and isinstance(value, list):
+        return [_transform_fields(item, type_def['array'], all_structs, fn) for item in value]
+    if 'mapValue' in type_def and isinstance(value, dict):
+        return {key: _transform_fields(item, type_def['mapValue'], all_structs, fn) for key, item in value.items()}
+    struct_name = type_def.get('userDefined')
+    if not struct_name or not find_struct(struct_name, all_structs) or not isinstance(value, dict):
+        return value
+    copy = dict(value)
+    # Walk the struct and its parents, naming each field after the struct that declares it
+    while struct_name:
+        struct_def = find_struct(struct_name, all_structs)
+        if not struct_def:
+            break
+        for field in struct_def.get('fields', []):
+            item = copy.get(field['name'])
+            if item is None:
+                continue
+            if field.get('encrypted'):
+                copy[field['name']] = fn(f"{struct_name}.{field['name']}", item)
+            else:
+                copy[field['name']] = _transform_fields(item, field['type'], all_structs, fn)
+        struct_name = struct_def.get('extends')
+    return copy
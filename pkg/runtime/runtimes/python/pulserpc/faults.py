@@ -0,0 +1,92 @@
+"""Fault injection for testing clients against a slow or unreliable server"""
+
+import json
+import random
+import threading
+from dataclasses import dataclass
+from typing import Any, Dict, Optional
+
+from .rpc import RPCError
+
+
+_METHOD_KEYS = {'latencyMs', 'jitterMs', 'errorRate', 'errorCode', 'errorMessage', 'malformedRate'}
+
+
+@dataclass
+class Fault:
+    """What happens to one call"""
+    delay_ms: int = 0
+    # Returned instead of calling the handler
+    error: Optional[RPCError] = None
+    # The encoded response is cut short
+    malformed: bool = False
+
+
+class FaultConfig:
+    """The faults a server injects into its calls, read from a JSON file such as:
+
+        {
+          "seed": 7,
+          "methods": {
+            "*": {"latencyMs": 20},
+            "Catalog.getProduct": {"latencyMs": 200, "jitterMs": 100, "errorRate": 0.1, "malformedRate": 0.05}
+          }
+        }
+
+    Methods are keyed by the name the client sends, and "*" applies to methods
+    without an entry of their own. A seed makes the injected faults repeatable.
+    """
+
+    def __init__(self, methods: Dict[str, Dict[str, Any]], seed: int = 0):
+        self.methods = methods
+        self.seed = seed
+        self._lock = threading.Lock()
+        self._rng = random.Random(seed if seed else None)
+
+    @classmethod
+    def from_dict(cls, config: Dict[str, Any]) -> 'FaultConfig':
+        """Build a FaultConfig, rejecting unknown keys, negative delays and
+        rates outside 0 to 1"""
+        unknown = set(config) - {'seed', 'methods'}
+        if unknown:
+            raise ValueError(f"unknown fault config keys: {', '.join(sorted(unknown))}")
+        methods = config.get('methods') or {}
+        for method, faults in methods.items():
+            unknown = set(faults) - _METHOD_KEYS
+            if unknown:
+                raise ValueError(f"{method}: unknown fault keys: {', '.join(sorted(unknown))}")
+            if faults.get('latencyMs', 0) < 0 or faults.get('jitterMs', 0) < 0:
+                raise ValueError(f"{method}: latencyMs and jitterMs must not be negative")
+            for rate in ('errorRate', 'malformedRate'):
+                if not 0 <= faults.get(rate, 0) <= 1:
+                    raise ValueError(f"{method}: errorRate and malformedRate must be between 0 and 1")
+        return cls(methods, config.get('seed', 0))
+
+    @classmethod
+    def load(cls, path: str) -> 'FaultConfig':
+        """Read a FaultConfig from a JSON file"""
+        with open(path) as f:
+            config = json.load(f)
+        try:
+            return cls.from_dict(config)
+        except ValueError as e:
+            raise ValueError(f"invalid fault config {path}: {e}") from e
+
+    def draw(self, method: str) -> Fault:
+        """Pick the fault for one call of method. Safe to call from several threads."""
+        faults = self.methods.get(method, self.methods.get('*'))
+        if faults is None:
+            return Fault()
+        with self._lock:
+            fault = Fault(delay_ms=faults.get('latencyMs', 0))
+            jitter = faults.get('jitterMs', 0)
+            if jitter > 0:
+                fault.delay_ms += self._rng.randint(0, jitter)
+            error_rate = faults.get('errorRate', 0)
+            malformed_rate = faults.get('malformedRate', 0)
+            if error_rate > 0 and self._rng.random() < error_rate:
+                fault.error = RPCError(faults.get('errorCode') or -32603,
+                                       faults.get('errorMessage') or 'Injected fault')
+            elif malformed_rate > 0 and self._rng.random() < malformed_rate:
+                fault.malformed = True
+            return fault
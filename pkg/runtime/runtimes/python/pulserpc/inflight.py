@@ -0,0 +1,42 @@
+"""Deduplication of identical calls that are in flight at the same time"""
+
+import threading
+from typing import Any, Callable, Dict, Tuple
+
+
+class _Call:
+    def __init__(self):
+        self.done = threading.Event()
+        self.value: Any = None
+
+
+class InFlight:
+    """While one thread runs the call for a key, later threads with the same key
+    wait for it and share its result instead of running the call again. Keys are
+    typically request_hash values."""
+
+    def __init__(self):
+        self._lock = threading.Lock()
+        self._calls: Dict[str, _Call] = {}
+
+    def do(self, key: str, fn: Callable[[], Any]) -> Tuple[Any, bool]:
+        """Run fn for key unless a call for key is already running, in which case
+        wait for that call and return its result. Returns the result and whether it
+        came from another thread's call."""
+        with self._lock:
+            call = self._calls.get(key)
+            if call is None:
+                call = self._calls[key] = _Call()
+                leader = True
+            else:
+                leader = False
+        if not leader:
+            call.done.wait()
+            return call.value, True
+        try:
+            call.value = fn()
+            return call.value, False
+        finally:
+            with self._lock:
+                del self._calls[key]
+            call.done.set()
@@ -0,0 +1,46 @@
+"""Per-method call counts and handler latency for the admin endpoint"""
+
+import threading
+import time
+from typing import Any, Dict, Iterable
+
+
+class MethodMetrics:
+    """Counts the calls of each method of a server and how long their handlers
+    took. Only the methods it was created with are counted, so calls to unknown
+    methods cannot grow it."""
+
+    def __init__(self, methods: Iterable[str]):
+        # Seconds since the epoch when the metrics started counting
+        self.started = time.time()
+        self._lock = threading.Lock()
+        self._methods: Dict[str, Dict[str, Any]] = {
+            method: {'calls': 0, 'errors': 0, 'totalMs': 0.0, 'maxMs': 0.0} for method in methods
+        }
+
+    def record(self, method: str, elapsed: float, failed: bool) -> None:
+        """Count one call of method ("Interface.method") whose handler took elapsed seconds"""
+        with self._lock:
+            metric = self._methods.get(method)
+            if metric is None:
+                return
+            ms = elapsed * 1000
+            metric['calls'] += 1
+            if failed:
+                metric['errors'] += 1
+            metric['totalMs'] += ms
+            metric['maxMs'] = max(metric['maxMs'], ms)
+
+    def snapshot(self) -> Dict[str, Dict[str, Any]]:
+        """Return the calls, errors and mean and longest handler time in milliseconds of
+        every method, keyed by method"""
+        with self._lock:
+            return {
+                method: {
+                    'calls': m['calls'],
+                    'errors': m['errors'],
+                    'meanMs': m['totalMs'] / m['calls'] if m['calls'] else 0.0,
+                    'maxMs': m['maxMs'],
+                }
+                for method, m in self._methods.items()
+            }
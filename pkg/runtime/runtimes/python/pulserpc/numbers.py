@@ -0,0 +1,69 @@
+"""The policy for int values written with a fraction or exponent, such as 2.0.
+
+A float accepts any number under either policy, and an int with a fractional
+part, such as 2.5, is always rejected. Under LENIENT, the default, an int also
+accepts a whole number written as a float, and normalize_ints turns it into an
+int before it reaches a handler or caller. Under STRICT, check_int_literals
+rejects it.
+"""
+
+from typing import Any, Dict
+
+from .types import get_struct_fields
+
+LENIENT = 'lenient'
+STRICT = 'strict'
+
+
+def check_int_literals(value: Any, type_def: Dict[str, Any], all_structs: Dict[str, Any]) -> None:
+    """Raise TypeError if a value of type int in a decoded JSON value was
+    written with a fraction or exponent, which json decodes as a float"""
+    if value is None:
+        return
+    if type_def.get('builtIn') == 'int':
+        if isinstance(value, float):
+            raise TypeError(f"Expected int, got float {value!r}")
+    elif 'array' in type_def and isinstance(value, list):
+        for i, item in enumerate(value):
+            try:
+                check_int_literals(item, type_def['array'], all_structs)
+            except TypeError as e:
+                raise TypeError(f"Array element at index {i}: {e}")
+    elif 'mapValue' in type_def and isinstance(value, dict):
+        for key, item in value.items():
+            try:
+                check_int_literals(item, type_def['mapValue'], all_structs)
+            except TypeError as e:
+                raise TypeError(f"Map value for key '{key}': {e}")
+    elif 'userDefined' in type_def and isinstance(value, dict):
+        for field in get_struct_fields(type_def['userDefined'], all_structs):
+            try:
+                check_int_literals(value.get(field['name']), field['type'], all_structs)
+            except TypeError as e:
+                raise TypeError(f"Field '{field['name']}' in struct {type_def['userDefined']}: {e}")
+
+
+def normalize_ints(value: Any, type_def: Dict[str, Any], all_structs: Dict[str, Any]) -> Any:
+    """Return a validated value with every whole float of type int in it turned
+    into an int. Containers are copied only if they hold such a value."""
+    if value is None:
+        return None
+    if type_def.get('builtIn') == 'int':
+        return int(value) if isinstance(value, float) else value
+    if 'array' in type_def and isinstance(value, list):
+        items = [normalize_ints(item, type_def['array'], all_structs) for item in value]
+        return items if any(a is not b for a, b in zip(items, value)) else value
+    if 'mapValue' in type_def and isinstance(value, dict):
+        items = {key: normalize_ints(item, type_def['mapValue'], all_structs) for key, item in value.items()}
+        return items if any(items[key] is not item for key, item in value.items()) else value
+    if 'userDefined' in type_def and isinstance(value, dict):
+        copy = None
+        for field in get_struct_fields(type_def['userDefined'], all_structs):
+            item = value.get(field['name'])
+            normalized = normalize_ints(item, field['type'], all_structs)
+            if normalized is not item:
+                if copy is None:
+                    copy = dict(value)
+                copy[field['name']] = normalized
+        return value if copy is None else copy
+    return value
@@ -41,3 +41,66 @@ def get_struct_fields(struct_name: str, all_structs: Dict[str, Any]) -> List[Dic
     
     return fields
 
+
+def clone_struct(value: Any) -> Any:
+    """Return a deep copy of a struct, list or map value.
+
+    Structs are plain dicts, so copy.deepcopy works too; this is faster because
+    values only hold dicts, lists and immutable scalars.
+    """
+    if isinstance(value, dict):
+        return {key: clone_struct(item) for key, item in value.items()}
+    if isinstance(value, list):
+        return [clone_struct(item) for item in value]
+    return value
+
+
+def plain_value(value: Any) -> Any:
+    """Return value with the frozen dataclasses of [immutable] structs, which have a
+    to_dict method, turned into dicts, so it can be validated and sent as JSON."""
+    if hasattr(value, 'to_dict'):
+        return value.to_dict()
+    if isinstance(value, dict):
+        return {key: plain_value(item) for key, item in value.items()}
+    if isinstance(value, list):
+        return [plain_value(item) for item in value]
+    return value
+
+
+# Replaces the value of a [sensitive] field in redacted output
+REDACTED = '***'
+
+
+def redact_value(value: Any, type_def: Dict[str, Any], all_structs: Dict[str, Any]) -> Any:
+    """Return a copy of a value of the given type in which every [sensitive]
+    struct field holds REDACTED, for logging.
+
+    Absent and None fields are kept as they are, and the value itself is not
+    changed.
+    """
+    if value is None:
+        return None
+    if 'array' in type_def and isinstance(value, list):
+        return [redact_value(item, type_def['array'], all_structs) for item in value]
+    if 'mapValue' in type_def and isinstance(value, dict):
+        return {key: redact_value(item, type_def['mapValue'], all_structs) for key, item in value.items()}
+    if 'userDefined' in type_def and find_struct(type_def['userDefined'], all_structs):
+        return redact_struct(type_def['userDefined'], value, all_structs)
+    return value
+
+
+def redact_struct(struct_name: str, value: Any, all_structs: Dict[str, Any]) -> Any:
+    """Return a copy of a struct value with its [sensitive] fields, including
+    those of nested structs, replaced by REDACTED"""
+    if not isinstance(value, dict):
+        return value
+    copy = dict(value)
+    for field in get_struct_fields(struct_name, all_structs):
+        item = copy.get(field['name'])
+        if item is None:
+            continue
+        if field.get('sensitive'):
+            copy[field['name']] = REDACTED
+        else:
+            copy[field['name']] = redact_value(item, field['type'], all_structs)
+    return copy
@@ -12,7 +12,10 @@ def validate_string(value: Any) -> None:
 
 
 def validate_int(value: Any) -> None:
-    """Validate that value is an int"""
+    """Validate that value is an int or a float with no fractional part, such
+    as 2.0 (see numbers.py)"""
+    if isinstance(value, float) and value.is_integer():
+        return
     if not isinstance(value, int):
         raise TypeError(f"Expected int, got {type(value).__name__}")
 
@@ -0,0 +1,95 @@
+"""Tests for canonical JSON, request hashes and in-flight deduplication"""
+
+import threading
+import time
+
+import pytest
+
+from pulserpc import InFlight, canonical_json, request_hash
+
+
+# The same vectors are checked by every other runtime's tests, so every runtime
+# hashes a call the same way, byte for byte
+REQUEST_HASH_VECTORS = [
+    (
+        'Catalog.get',
+        ['p-1', 2],
+        '{"method":"Catalog.get","params":["p-1",2]}',
+        'c83d6e2a3e0908ddb5e94fe05cffd450756e92caab52d017ad51be7b97fcb715',
+    ),
+    (
+        'Catalog.search',
+        [{'query': 'café "x"\n', 'limit': 10, 'tags': ['a', 'b'], 'price': {'max': 99.5, 'min': 0.1}}, None],
+        '{"method":"Catalog.search","params":[{"limit":10,"price":{"max":99.5,"min":0.1},"query":"café \\"x\\"\\n","tags":["a","b"]},null]}',
+        'bac87c8eb687a2e88fb49b08c30f0668d849c06613310acd110c66d42acc56ae',
+    ),
+    (
+        'Catalog.bulk',
+        {'ids': [1e21, 1e-7, -0.0, 2 ** 60], 'é': True, 'a': False, '\U0001F600': 1, '｡': 2},
+        '{"method":"Catalog.bulk","params":{"a":false,"ids":[1e+21,1e-7,0,1152921504606847000],"é":true,"\U0001F600":1,"｡":2}}',
+        '53bd8c11499fccf79fedaff23d160964bba118c23421f68f3167c40eb2a7750b',
+    ),
+    # 2^53+1 is not a double: canonical JSON writes the nearest one, 2^53
+    (
+        'Catalog.edges',
+        [1e21, 1e-7, -0.0, 2 ** 53 + 1],
+        '{"method":"Catalog.edges","params":[1e+21,1e-7,0,9007199254740992]}',
+        '379f1ac102b547987989aaba1ce4f935c28a1d5b880068e36ffd2b017f2f98e5',
+    ),
+]
+
+
+@pytest.mark.parametrize('method,params,canonical,hash', REQUEST_HASH_VECTORS)
+def test_request_hash_vectors(method, params, canonical, hash):
+    assert canonical_json({'method': method, 'params': params}) == canonical
+    assert request_hash(method, params) == hash
+
+
+def test_canonical_json_numbers():
+    cases = {
+        0: '0',
+        -1.5: '-1.5',
+        123.456: '123.456',
+        1e20: '100000000000000000000',
+        1e-6: '0.000001',
+        1.5e300: '1.5e+300',
+        5e-324: '5e-324',
+        -2.5e-10: '-2.5e-10',
+    }
+    for value, expected in cases.items():
+        assert canonical_json(value) == expected
+    with pytest.raises(ValueError):
+        canonical_json(float('nan'))
+
+
+def test_in_flight_shares_result():
+    group = InFlight()
+    runs = []
+    started = threading.Event()
+    release = threading.Event()
+    results = [None] * 3
+
+    def leader_fn():
+        runs.append('leader')
+        started.set()
+        release.wait()
+        return 'value'
+
+    def run(i, fn):
+        results[i] = group.do('k', fn)
+
+    threads = [threading.Thread(target=run, args=(0, leader_fn))]
+    threads[0].start()
+    started.wait()
+    for i in (1, 2):
+        threads.append(threading.Thread(target=run, args=(i, lambda: runs.append('other') or 'other')))
+        threads[-1].start()
+    time.sleep(0.02)
+    release.set()
+    for thread in threads:
+        thread.join()
+
+    assert runs == ['leader']
+    assert results == [('value', False), ('value', True), ('value', True)]
+    # Once the call is done, the key runs again
+    assert group.do('k', lambda: 'again') == ('again', False)
@@ -0,0 +1,69 @@
+"""Tests for server composition"""
+
+from pulserpc import Composition, merge_idl_documents
+
+
+class StubServer:
+    def __init__(self, methods, idl=None):
+        self.methods = methods
+        self.idl = idl or {}
+
+    def handles(self, method):
+        return method in self.methods
+
+    def idl_document(self):
+        return self.idl
+
+
+def test_find():
+    composition = Composition()
+    assert composition.find('Users.get') is None
+    users = StubServer({'Users.get'})
+    shadow = StubServer({'Users.get', 'Orders.list'})
+    composition.add(users)
+    composition.add(shadow)
+    # The first server that handles a method gets it
+    assert composition.find('Users.get') is users
+    assert composition.find('Orders.list') is shadow
+    assert composition.find('Nope.x') is None
+
+
+def test_route():
+    composition = Composition()
+    billing = StubServer(set())
+    admin = StubServer(set())
+    composition.mount('/billing/', billing)
+    composition.mount('billing/admin', admin)
+    assert composition.route('/billing') == (billing, '/')
+    assert composition.route('/billing/Invoices.get?id=1') == (billing, '/Invoices.get?id=1')
+    # The longest mount path wins
+    assert composition.route('/billing/admin/stats') == (admin, '/stats')
+    assert composition.route('/billingx') is None
+    assert composition.route('/') is None
+
+
+def test_merge_idl_documents():
+    shared = {'name': 'Money'}
+    billing = {
+        'rootNamespace': 'billing',
+        'interfaces': [{'name': 'Invoices'}],
+        'structs': [shared],
+    }
+    users = {
+        'rootNamespace': 'users',
+        'interfaces': [{'name': 'Users'}],
+        'structs': [shared, {'name': 'User'}],
+        'enums': [{'name': 'Role'}],
+    }
+    merged = merge_idl_documents(billing, users)
+    assert merged == {
+        'rootNamespace': 'billing',
+        'interfaces': [{'name': 'Invoices'}, {'name': 'Users'}],
+        'structs': [{'name': 'Money'}, {'name': 'User'}],
+        'enums': [{'name': 'Role'}],
+    }
+
+    composition = Composition()
+    assert composition.merge_idl(billing) is billing
+    composition.add(StubServer(set(), users))
+    assert composition.merge_idl(billing) == merged
@@ -0,0 +1,27 @@
+"""Tests for request deadlines"""
+
+from pulserpc import deadline_header_value, deadline_scope, remaining_time
+
+
+def test_deadline_scope():
+    assert remaining_time() is None
+    with deadline_scope('5000'):
+        remaining = remaining_time()
+        assert remaining is not None and 4.0 < remaining <= 5.0
+        # Scopes nest and restore the outer deadline
+        with deadline_scope(None):
+            assert remaining_time() is None
+        assert remaining_time() is not None
+    assert remaining_time() is None
+
+
+def test_expired_and_malformed_deadlines():
+    # A budget that has run out leaves no time
+    with deadline_scope('-10'):
+        assert remaining_time() == 0.0
+    with deadline_scope('soon'):
+        assert remaining_time() is None
+
+
+def test_deadline_header_value():
+    assert deadline_header_value(2.5) == '2500'
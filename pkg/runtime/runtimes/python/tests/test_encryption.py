@@ -0,0 +1,67 @@
+"""Tests for [encrypted] field encryption"""
+
+import base64
+
+import pytest
+
+from pulserpc import encrypt_fields, decrypt_fields
+
+
+class Base64Cipher:
+    def encrypt(self, field, plaintext):
+        return field + ':' + base64.b64encode(plaintext).decode('ascii')
+
+    def decrypt(self, field, ciphertext):
+        assert ciphertext.startswith(field + ':')
+        return base64.b64decode(ciphertext[len(field) + 1:])
+
+
+ALL_STRUCTS = {
+    'Base': {
+        'fields': [
+            {'name': 'ssn', 'type': {'builtIn': 'string'}, 'encrypted': True},
+        ]
+    },
+    'Patient': {
+        'extends': 'Base',
+        'fields': [
+            {'name': 'name', 'type': {'builtIn': 'string'}},
+            {'name': 'age', 'type': {'builtIn': 'int'}, 'encrypted': True},
+            {'name': 'notes', 'type': {'array': {'builtIn': 'string'}}, 'optional': True, 'encrypted': True},
+            {'name': 'contacts', 'type': {'array': {'userDefined': 'Contact'}}},
+        ]
+    },
+    'Contact': {
+        'fields': [
+            {'name': 'email', 'type': {'builtIn': 'string'}, 'encrypted': True},
+        ]
+    },
+}
+
+
+def test_encrypt_fields_round_trip():
+    original = {'ssn': '123', 'name': 'Ann', 'age': 40, 'notes': None, 'contacts': [{'email': 'a@example.com'}]}
+    encrypted = encrypt_fields(original, {'userDefined': 'Patient'}, ALL_STRUCTS, Base64Cipher())
+    assert encrypted == {
+        'ssn': 'Base.ssn:' + base64.b64encode(b'"123"').decode('ascii'),
+        'name': 'Ann',
+        'age': 'Patient.age:' + base64.b64encode(b'40').decode('ascii'),
+        'notes': None,
+        'contacts': [{'email': 'Contact.email:' + base64.b64encode(b'"a@example.com"').decode('ascii')}],
+    }
+    assert original['ssn'] == '123' and original['contacts'][0]['email'] == 'a@example.com'
+
+    decrypted = decrypt_fields([encrypted], {'array': {'userDefined': 'Patient'}}, ALL_STRUCTS, Base64Cipher())
+    assert decrypted == [original]
+
+
+def test_encrypt_fields_requires_cipher():
+    with pytest.raises(ValueError, match='No field cipher set to encrypt field Base.ssn'):
+        encrypt_fields({'ssn': '123'}, {'userDefined': 'Base'}, ALL_STRUCTS, None)
+    # Values without encrypted fields need no cipher
+    assert encrypt_fields('x', {'builtIn': 'string'}, ALL_STRUCTS, None) == 'x'
+
+
+def test_decrypt_fields_rejects_plaintext():
+    with pytest.raises(ValueError, match='Encrypted field Patient.age must be a string'):
+        decrypt_fields({'age': 40}, {'userDefined': 'Patient'}, ALL_STRUCTS, Base64Cipher())
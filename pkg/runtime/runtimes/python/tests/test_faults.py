@@ -0,0 +1,58 @@
+"""Tests for fault injection"""
+
+import json
+
+import pytest
+
+from pulserpc import FaultConfig
+
+
+def write_config(tmp_path, config):
+    path = tmp_path / 'faults.json'
+    path.write_text(json.dumps(config))
+    return str(path)
+
+
+def test_fault_config_draw(tmp_path):
+    config = FaultConfig.load(write_config(tmp_path, {
+        'seed': 7,
+        'methods': {
+            '*': {'latencyMs': 20, 'jitterMs': 10},
+            'Catalog.fail': {'errorRate': 1, 'errorCode': -32050, 'errorMessage': 'Catalog down'},
+            'Catalog.garble': {'malformedRate': 1},
+        },
+    }))
+
+    fault = config.draw('Catalog.list')
+    assert 20 <= fault.delay_ms <= 30
+    assert fault.error is None and not fault.malformed
+
+    # A method's own entry replaces "*"
+    fault = config.draw('Catalog.fail')
+    assert fault.delay_ms == 0
+    assert (fault.error.code, fault.error.message) == (-32050, 'Catalog down')
+
+    assert config.draw('Catalog.garble').malformed
+
+
+def test_fault_config_defaults_and_seed():
+    def draws():
+        config = FaultConfig.from_dict({'seed': 42, 'methods': {'*': {'errorRate': 0.5, 'jitterMs': 100}}})
+        return [config.draw('Any.method') for _ in range(20)]
+
+    first, second = draws(), draws()
+    assert [(f.delay_ms, f.error is None) for f in first] == [(f.delay_ms, f.error is None) for f in second]
+    errors = [f.error for f in first if f.error is not None]
+    assert 0 < len(errors) < len(first)
+    assert all((e.code, e.message) == (-32603, 'Injected fault') for e in errors)
+
+
+@pytest.mark.parametrize('config,message', [
+    ({'methods': {'*': {'errorRate': 1.5}}}, 'errorRate and malformedRate must be between 0 and 1'),
+    ({'methods': {'*': {'latencyMs': -1}}}, 'latencyMs and jitterMs must not be negative'),
+    ({'methods': {'*': {'latency': 10}}}, 'unknown fault keys: latency'),
+    ({'method': {}}, 'unknown fault config keys: method'),
+])
+def test_fault_config_invalid(config, message):
+    with pytest.raises(ValueError, match=message):
+        FaultConfig.from_dict(config)
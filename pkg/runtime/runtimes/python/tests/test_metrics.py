@@ -0,0 +1,18 @@
+"""Tests for per-method metrics"""
+
+from pulserpc import MethodMetrics
+
+
+def test_method_metrics():
+    metrics = MethodMetrics(['Catalog.get', 'Catalog.list'])
+    metrics.record('Catalog.get', 0.010, False)
+    metrics.record('Catalog.get', 0.030, True)
+    # Methods it was not created with are not counted
+    metrics.record('Catalog.unknown', 0.001, False)
+
+    snapshot = metrics.snapshot()
+    assert set(snapshot) == {'Catalog.get', 'Catalog.list'}
+    get = snapshot['Catalog.get']
+    assert (get['calls'], get['errors']) == (2, 1)
+    assert abs(get['meanMs'] - 20) < 1e-9 and abs(get['maxMs'] - 30) < 1e-9
+    assert snapshot['Catalog.list'] == {'calls': 0, 'errors': 0, 'meanMs': 0.0, 'maxMs': 0.0}
@@ -0,0 +1,36 @@
+"""Tests for the int/float number policy"""
+
+import pytest
+
+from pulserpc import check_int_literals, normalize_ints, validate_int
+
+INT = {'builtIn': 'int'}
+POINT = {'Point': {'fields': [{'name': 'x', 'type': INT}]}}
+
+
+def test_validate_int_accepts_whole_floats():
+    validate_int(2.0)
+    with pytest.raises(TypeError, match="Expected int"):
+        validate_int(2.5)
+
+
+def test_check_int_literals():
+    check_int_literals(2, INT, {})
+    check_int_literals(2.0, {'builtIn': 'float'}, {})
+    with pytest.raises(TypeError, match="Expected int"):
+        check_int_literals(2.0, INT, {})
+    with pytest.raises(TypeError, match="index 1"):
+        check_int_literals([1, 2.0], {'array': INT}, {})
+    with pytest.raises(TypeError, match="Field 'x'"):
+        check_int_literals({'x': 1.0}, {'userDefined': 'Point'}, POINT)
+
+
+def test_normalize_ints():
+    assert normalize_ints(2.0, INT, {}) == 2
+    assert isinstance(normalize_ints(2.0, INT, {}), int)
+    assert normalize_ints(2.0, {'builtIn': 'float'}, {}) == 2.0
+    assert normalize_ints({'x': 1.0}, {'userDefined': 'Point'}, POINT) == {'x': 1}
+    # Values with nothing to convert are returned as is
+    items = [1, 2]
+    assert normalize_ints(items, {'array': INT}, {}) is items
+    assert normalize_ints({'a': 1.0}, {'mapValue': INT}, {}) == {'a': 1}
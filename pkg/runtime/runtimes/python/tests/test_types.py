@@ -1,6 +1,6 @@
 """Tests for type helper functions"""
 
-from pulserpc import find_struct, find_enum, get_struct_fields
+from pulserpc import find_struct, find_enum, get_struct_fields, clone_struct, redact_struct, REDACTED
 
 
 def test_find_struct():
@@ -77,3 +77,53 @@ def test_get_struct_fields_override_parent():
     assert fields[0]['type']['builtIn'] == 'int'
     assert fields[1]['name'] == 'name'
 
+
+def test_clone_struct_copies_nested_values():
+    original = {
+        'id': 'u1',
+        'tags': ['a', 'b'],
+        'address': {'city': 'Oslo'},
+        'scores': {'math': [1, 2]},
+    }
+    copy = clone_struct(original)
+    assert copy == original
+    copy['tags'].append('c')
+    copy['address']['city'] = 'Bergen'
+    copy['scores']['math'][0] = 9
+    assert original['tags'] == ['a', 'b']
+    assert original['address'] == {'city': 'Oslo'}
+    assert original['scores'] == {'math': [1, 2]}
+    assert clone_struct(None) is None
+
+
+def test_redact_struct_masks_sensitive_fields():
+    all_structs = {
+        'Credentials': {
+            'fields': [
+                {'name': 'password', 'type': {'builtIn': 'string'}, 'sensitive': True},
+            ],
+        },
+        'Login': {
+            'extends': 'Credentials',
+            'fields': [
+                {'name': 'username', 'type': {'builtIn': 'string'}},
+                {'name': 'token', 'type': {'builtIn': 'string'}, 'optional': True, 'sensitive': True},
+                {'name': 'backups', 'type': {'array': {'userDefined': 'Credentials'}}},
+            ],
+        },
+    }
+    original = {
+        'username': 'ann',
+        'password': 'secret',
+        'token': None,
+        'backups': [{'password': 'old'}],
+    }
+    redacted = redact_struct('Login', original, all_structs)
+    assert redacted == {
+        'username': 'ann',
+        'password': REDACTED,
+        'token': None,
+        'backups': [{'password': REDACTED}],
+    }
+    assert original['password'] == 'secret'
+    assert original['backups'][0]['password'] == 'old'
@@ -0,0 +1,170 @@
+//! Canonical JSON and request hashes that match across runtimes
+
+use serde_json::{json, Value};
+
+/// Returns the SHA-256, in lowercase hex, of the canonical JSON of
+/// `{"method": method, "params": params}`. Every runtime computes the same hash
+/// for the same call, so it can key idempotency records, caches and
+/// deduplication across services written in different languages. Params sent by
+/// position and by name hash differently.
+pub fn request_hash(method: &str, params: &Value) -> Result<String, String> {
+    let canonical = canonical_json(&json!({"method": method, "params": params}))?;
+    Ok(sha256(canonical.as_bytes()).iter().map(|b| format!("{:02x}", b)).collect())
+}
+
+/// Encodes value as canonical JSON (RFC 8785): no whitespace, object keys sorted
+/// by their UTF-16 code units, numbers formatted as JavaScript does and strings
+/// escaped only where JSON requires it. Fails for numbers that are not finite.
+pub fn canonical_json(value: &Value) -> Result<String, String> {
+    let mut out = String::new();
+    write_value(&mut out, value)?;
+    Ok(out)
+}
+
+fn write_value(out: &mut String, value: &Value) -> Result<(), String> {
+    match value {
+        Value::Null => out.push_str("null"),
+        Value::Bool(b) => out.push_str(if *b { "true" } else { "false" }),
+        Value::Number(n) => {
+            let f = n.as_f64().ok_or_else(|| format!("cannot canonicalize {}", n))?;
+            out.push_str(&canonical_number(f)?);
+        }
+        Value::String(s) => write_string(out, s),
+        Value::Array(items) => {
+            out.push('[');
+            for (i, item) in items.iter().enumerate() {
+                if i > 0 {
+                    out.push(',');
+                }
+                write_value(out, item)?;
+            }
+            out.push(']');
+        }
+        Value::Object(entries) => {
+            // serde_json orders keys by their UTF-8 bytes; RFC 8785 orders them by
+            // their UTF-16 code units
+            let mut keys: Vec<&String> = entries.keys().collect();
+            keys.sort_by(|a, b| a.encode_utf16().cmp(b.encode_utf16()));
+            out.push('{');
+            for (i, key) in keys.into_iter().enumerate() {
+                if i > 0 {
+                    out.push(',');
+                }
+                write_string(out, key);
+                out.push(':');
+                write_value(out, &entries[key])?;
+            }
+            out.push('}');
+        }
+    }
+    Ok(())
+}
+
+/// Formats f as JavaScript's Number.prototype.toString does
+fn canonical_number(f: f64) -> Result<String, String> {
+    if !f.is_finite() {
+        return Err(format!("cannot canonicalize {}", f));
+    }
+    if f == 0.0 {
+        return Ok("0".to_string());
+    }
+    let sign = if f < 0.0 { "-" } else { "" };
+    // {:e} gives the shortest digits that round-trip; n is the position of the
+    // decimal point relative to them: f = 0.digits * 10^n
+    let scientific = format!("{:e}", f.abs());
+    let (mantissa, exponent) = scientific.split_once('e').unwrap_or((&scientific, "0"));
+    let digits = mantissa.replace('.', "");
+    let n = exponent.parse::<i32>().unwrap_or(0) + 1;
+    let k = digits.len() as i32;
+    if k <= n && n <= 21 {
+        return Ok(format!("{}{}{}", sign, digits, "0".repeat((n - k) as usize)));
+    }
+    if 0 < n && n <= 21 {
+        let (int_part, frac_part) = digits.split_at(n as usize);
+        return Ok(format!("{}{}.{}", sign, int_part, frac_part));
+    }
+    if -6 < n && n <= 0 {
+        return Ok(format!("{}0.{}{}", sign, "0".repeat((-n) as usize), digits));
+    }
+    let exp = n - 1;
+    let mantissa = if k == 1 { digits.clone() } else { format!("{}.{}", &digits[..1], &digits[1..]) };
+    Ok(format!("{}{}e{}{}", sign, mantissa, if exp < 0 { "-" } else { "+" }, exp.abs()))
+}
+
+/// Escapes only quotes, backslashes and control characters
+fn write_string(out: &mut String, s: &str) {
+    out.push('"');
+    for c in s.chars() {
+        match c {
+            '"' => out.push_str("\\\""),
+            '\\' => out.push_str("\\\\"),
+            '\u{08}' => out.push_str("\\b"),
+            '\u{0c}' => out.push_str("\\f"),
+            '\n' => out.push_str("\\n"),
+            '\r' => out.push_str("\\r"),
+            '\t' => out.push_str("\\t"),
+            c if (c as u32) < 0x20 => out.push_str(&format!("\\u{:04x}", c as u32)),
+            c => out.push(c),
+        }
+    }
+    out.push('"');
+}
+
+/// SHA-256 (FIPS 180-4), so the runtime needs no crate for request_hash
+fn sha256(data: &[u8]) -> [u8; 32] {
+    const K: [u32; 64] = [
+        0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+        0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+        0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+        0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+        0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+        0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+        0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+        0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
+    ];
+    let mut h: [u32; 8] = [
+        0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+    ];
+    let mut message = data.to_vec();
+    message.push(0x80);
+    while message.len() % 64 != 56 {
+        message.push(0);
+    }
+    message.extend_from_slice(&((data.len() as u64) * 8).to_be_bytes());
+    for block in message.chunks(64) {
+        let mut w = [0u32; 64];
+        for i in 0..16 {
+            w[i] = u32::from_be_bytes([block[4 * i], block[4 * i + 1], block[4 * i + 2], block[4 * i + 3]]);
+        }
+        for i in 16..64 {
+            let s0 = w[i - 15].rotate_right(7) ^ w[i - 15].rotate_right(18) ^ (w[i - 15] >> 3);
+            let s1 = w[i - 2].rotate_right(17) ^ w[i - 2].rotate_right(19) ^ (w[i - 2] >> 10);
+            w[i] = w[i - 16].wrapping_add(s0).wrapping_add(w[i - 7]).wrapping_add(s1);
+        }
+        let [mut a, mut b, mut c, mut d, mut e, mut f, mut g, mut hh] = h;
+        for i in 0..64 {
+            let s1 = e.rotate_right(6) ^ e.rotate_right(11) ^ e.rotate_right(25);
+            let ch = (e & f) ^ (!e & g);
+            let t1 = hh.wrapping_add(s1).wrapping_add(ch).wrapping_add(K[i]).wrapping_add(w[i]);
+            let s0 = a.rotate_right(2) ^ a.rotate_right(13) ^ a.rotate_right(22);
+            let maj = (a & b) ^ (a & c) ^ (b & c);
+            let t2 = s0.wrapping_add(maj);
+            hh = g;
+            g = f;
+            f = e;
+            e = d.wrapping_add(t1);
+            d = c;
+            c = b;
+            b = a;
+            a = t1.wrapping_add(t2);
+        }
+        for (state, value) in h.iter_mut().zip([a, b, c, d, e, f, g, hh]) {
+            *state = state.wrapping_add(value);
+        }
+    }
+    let mut digest = [0u8; 32];
+    for (i, word) in h.iter().enumerate() {
+        digest[4 * i..4 * i + 4].copy_from_slice(&word.to_be_bytes());
+    }
+    digest
+}
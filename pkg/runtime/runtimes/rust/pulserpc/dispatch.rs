@@ -0,0 +1,364 @@
+//! JSON-RPC request handling: validation of calls against idl.json and dispatch to
+//! the handler of an interface
+
+use super::canonical::canonical_json;
+use super::numbers::{check_int_literals, normalize_ints, NumberPolicy};
+use super::rpc::{RpcError, INTERNAL_ERROR, INVALID_PARAMS, INVALID_REQUEST, METHOD_NOT_FOUND, PARSE_ERROR};
+use super::types::IdlTypes;
+use super::validation::validate_type;
+use serde::de::DeserializeOwned;
+use serde::Serialize;
+use serde_json::{json, Map, Value};
+use std::collections::HashMap;
+use std::panic::{catch_unwind, AssertUnwindSafe};
+
+/// Handler calls the methods of one interface. Generated servers implement it for
+/// each interface trait; params have been validated against the method's
+/// parameters, with defaults filled in, before `call` sees them.
+pub trait Handler: Send + Sync {
+    fn call(&self, method: &str, params: Vec<Value>) -> Result<Value, RpcError>;
+}
+
+/// Decodes the next param of a call into a handler argument
+pub fn decode_param<T: DeserializeOwned>(params: &mut impl Iterator<Item = Value>) -> Result<T, RpcError> {
+    serde_json::from_value(params.next().unwrap_or(Value::Null))
+        .map_err(|err| failure(INVALID_PARAMS, err.to_string()))
+}
+
+/// Encodes the result a handler returned
+pub fn encode_result<T: Serialize>(result: T) -> Result<Value, RpcError> {
+    serde_json::to_value(result)
+        .map_err(|err| failure(INTERNAL_ERROR, format!("Failed to encode result: {}", err)))
+}
+
+/// Dispatcher validates JSON-RPC requests against idl.json and calls the
+/// registered handlers. It answers single requests, batches and notifications, and
+/// the built-in pulserpc-idl and pulserpc-capabilities methods.
+pub struct Dispatcher {
+    types: IdlTypes,
+    handlers: HashMap<String, Box<dyn Handler>>,
+    // RPC names set by [wire], mapped to Interface.method
+    wire_methods: HashMap<String, String>,
+    // For each extended interface, the interfaces that inherit its methods
+    sub_interfaces: HashMap<String, Vec<String>>,
+    number_policy: NumberPolicy,
+    canonical_json: bool,
+}
+
+impl Dispatcher {
+    /// Creates a dispatcher for the IDL of an idl.json document. It panics if the
+    /// document is not valid JSON, which is a bug in the generated code.
+    pub fn new(idl_json: &str) -> Self {
+        let types = IdlTypes::parse(idl_json).expect("invalid idl.json");
+        let mut wire_methods = HashMap::new();
+        let mut sub_interfaces: HashMap<String, Vec<String>> = HashMap::new();
+        for iface in types.interfaces() {
+            let iface_name = iface.get("name").and_then(Value::as_str).unwrap_or_default();
+            let prefix = annotation(iface, "wire");
+            for method in iface.get("methods").and_then(Value::as_array).into_iter().flatten() {
+                let method_name = method.get("name").and_then(Value::as_str).unwrap_or_default();
+                let inherited = method.get("inheritedFrom").and_then(Value::as_str).is_some();
+                let rpc_name = match (annotation(method, "wire"), prefix) {
+                    (Some(name), _) if !inherited => name.to_string(),
+                    (_, Some(prefix)) => format!("{}.{}", prefix, method_name),
+                    _ => continue,
+                };
+                wire_methods.insert(rpc_name, format!("{}.{}", iface_name, method_name));
+            }
+
+            let mut pending: Vec<String> = extends(iface);
+            let mut seen = vec![iface_name.to_string()];
+            while let Some(parent) = pending.pop() {
+                if seen.contains(&parent) {
+                    continue;
+                }
+                if let Some(parent_def) = types.find_interface(&parent) {
+                    pending.extend(extends(parent_def));
+                }
+                sub_interfaces.entry(parent.clone()).or_default().push(iface_name.to_string());
+                seen.push(parent);
+            }
+        }
+        for subs in sub_interfaces.values_mut() {
+            subs.sort();
+        }
+        Dispatcher {
+            types,
+            handlers: HashMap::new(),
+            wire_methods,
+            sub_interfaces,
+            number_policy: NumberPolicy::default(),
+            canonical_json: false,
+        }
+    }
+
+    /// Registers the handler of an interface, replacing any earlier one
+    pub fn register(&mut self, interface: &str, handler: Box<dyn Handler>) {
+        self.handlers.insert(interface.to_string(), handler);
+    }
+
+    /// Sets whether int params written as 2.0 are accepted
+    pub fn set_number_policy(&mut self, policy: NumberPolicy) {
+        self.number_policy = policy;
+    }
+
+    /// Sets whether responses are encoded as canonical JSON (RFC 8785, see
+    /// `canonical_json`), so equal responses are equal bytes whatever language the
+    /// server is written in. It is off by default.
+    pub fn set_canonical_json(&mut self, enabled: bool) {
+        self.canonical_json = enabled;
+    }
+
+    /// Returns the IDL types the dispatcher validates with
+    pub fn types(&self) -> &IdlTypes {
+        &self.types
+    }
+
+    /// Handles a raw JSON-RPC message, a single request or a batch, and returns the
+    /// encoded response, or None if the message held only notifications
+    pub fn handle_message(&self, body: &[u8]) -> Option<Vec<u8>> {
+        let message: Value = match serde_json::from_slice(body) {
+            Ok(message) => message,
+            Err(err) => {
+                let error = failure(PARSE_ERROR, format!("Invalid JSON: {}", err));
+                return Some(self.encode(&error_response(Value::Null, &error)));
+            }
+        };
+        let response = match &message {
+            Value::Array(requests) if requests.is_empty() => Some(error_response(
+                Value::Null,
+                &failure(INVALID_REQUEST, "Empty batch array"),
+            )),
+            Value::Array(requests) => {
+                let responses: Vec<Value> = requests
+                    .iter()
+                    .filter(|request| request.is_object())
+                    .filter_map(|request| self.handle_request(request))
+                    .collect();
+                if responses.is_empty() {
+                    None
+                } else {
+                    Some(Value::Array(responses))
+                }
+            }
+            Value::Object(_) => self.handle_request(&message),
+            _ => Some(error_response(
+                Value::Null,
+                &failure(INVALID_REQUEST, "Request must be an object or array"),
+            )),
+        };
+        response.map(|response| self.encode(&response))
+    }
+
+    // Encodes a response, as canonical JSON if set_canonical_json is on. A Value
+    // holds no NaN or infinity, the only numbers canonical JSON rejects.
+    fn encode(&self, response: &Value) -> Vec<u8> {
+        if self.canonical_json {
+            if let Ok(canonical) = canonical_json(response) {
+                return canonical.into_bytes();
+            }
+        }
+        response.to_string().into_bytes()
+    }
+
+    /// Handles one decoded JSON-RPC request and returns its response, or None for a
+    /// notification that succeeded. Tests use it to call handlers in-process.
+    pub fn handle_request(&self, request: &Value) -> Option<Value> {
+        let id = request.get("id").cloned();
+        match self.call(request) {
+            Ok(result) => id.map(|id| json!({"jsonrpc": "2.0", "result": result, "id": id})),
+            Err((error, valid)) => {
+                // Requests that are not valid JSON-RPC get a null id
+                let id = if valid { id.unwrap_or(Value::Null) } else { Value::Null };
+                Some(error_response(id, &error))
+            }
+        }
+    }
+
+    // call validates a request and calls its handler. The flag of an error is false
+    // when the request itself was invalid.
+    fn call(&self, request: &Value) -> Result<Value, (RpcError, bool)> {
+        if request.get("jsonrpc").and_then(Value::as_str) != Some("2.0") {
+            return Err((failure(INVALID_REQUEST, "jsonrpc must be '2.0'"), false));
+        }
+        let method = match request.get("method").and_then(Value::as_str) {
+            Some(method) => method,
+            None => return Err((failure(INVALID_REQUEST, "method must be a string"), false)),
+        };
+        self.call_method(method, request.get("params")).map_err(|err| (err, true))
+    }
+
+    fn call_method(&self, method: &str, params: Option<&Value>) -> Result<Value, RpcError> {
+        if method == "pulserpc-idl" {
+            return Ok(self.types.document().clone());
+        }
+        if method == "pulserpc-capabilities" {
+            // The optional protocol features this server supports; it does not compress
+            return Ok(json!({
+                "protocolVersion": "2.0",
+                "batch": true,
+                "notifications": true,
+                "compression": false,
+                "msgpack": false,
+                "streaming": false,
+            }));
+        }
+
+        // A name set by [wire] is dispatched by its interface.method name
+        let method = self.wire_methods.get(method).map(String::as_str).unwrap_or(method);
+        let (interface_name, method_name) = match method.split_once('.') {
+            Some((iface, name)) if !name.contains('.') => (iface, name),
+            _ => {
+                return Err(failure(METHOD_NOT_FOUND, format!("Invalid method format: {}", method)))
+            }
+        };
+
+        // An extended interface's methods are also served by the handler of an
+        // interface that extends it
+        let handler = self.handlers.get(interface_name).or_else(|| {
+            self.sub_interfaces
+                .get(interface_name)?
+                .iter()
+                .find_map(|sub| self.handlers.get(sub))
+        });
+        let handler = handler.ok_or_else(|| {
+            failure(METHOD_NOT_FOUND, format!("Interface '{}' not registered", interface_name))
+        })?;
+        let method_def = self.types.find_method(interface_name, method_name).ok_or_else(|| {
+            failure(
+                METHOD_NOT_FOUND,
+                format!("Method '{}' not found in interface '{}'", method_name, interface_name),
+            )
+        })?;
+
+        let expected: &[Value] = method_def
+            .get("parameters")
+            .and_then(Value::as_array)
+            .map(Vec::as_slice)
+            .unwrap_or_default();
+        let params = self.validate_params(params, expected)?;
+
+        let result = catch_unwind(AssertUnwindSafe(|| handler.call(method_name, params)))
+            .map_err(|_| failure(INTERNAL_ERROR, format!("Handler of {} panicked", method)))??;
+
+        if let Some(return_type) = method_def.get("returnType").filter(|t| !t.is_null()) {
+            let optional = method_def.get("returnOptional").and_then(Value::as_bool).unwrap_or(false);
+            validate_type(&result, return_type, &self.types, optional).map_err(|err| {
+                failure(INTERNAL_ERROR, format!("Response validation failed: {}", err))
+            })?;
+        }
+        Ok(result)
+    }
+
+    // validate_params orders params sent by name, checks their count, fills in the
+    // defaults of optional parameters and validates every param against its type
+    fn validate_params(&self, params: Option<&Value>, expected: &[Value]) -> Result<Vec<Value>, RpcError> {
+        let invalid = |detail: String| failure(INVALID_PARAMS, detail);
+        let mut params = match params {
+            Some(Value::Object(named)) => params_by_name(named, expected).map_err(invalid)?,
+            Some(Value::Array(params)) => params.clone(),
+            _ => Vec::new(),
+        };
+
+        let required = expected.iter().filter(|p| !is_optional(p)).count();
+        if params.len() < required || params.len() > expected.len() {
+            let count = if required < expected.len() {
+                format!("{} to {}", required, expected.len())
+            } else {
+                expected.len().to_string()
+            };
+            return Err(invalid(format!("Expected {} parameters, got {}", count, params.len())));
+        }
+
+        params.resize(expected.len(), Value::Null);
+        for (i, (param, def)) in params.iter_mut().zip(expected).enumerate() {
+            let name = def.get("name").and_then(Value::as_str).unwrap_or_default();
+            let param_type = def.get("type").unwrap_or(&Value::Null);
+            if param.is_null() {
+                if let Some(default) = default_value(def) {
+                    *param = default;
+                }
+            }
+            validate_type(param, param_type, &self.types, is_optional(def))
+                .map_err(|err| invalid(format!("Parameter {} ({}) validation failed: {}", i, name, err)))?;
+            if self.number_policy == NumberPolicy::Strict {
+                check_int_literals(param, param_type, &self.types)
+                    .map_err(|err| invalid(format!("Parameter {} ({}) validation failed: {}", i, name, err)))?;
+            }
+            normalize_ints(param, param_type, &self.types);
+        }
+        Ok(params)
+    }
+}
+
+/// Returns an error with the standard message of its code and the detail as data
+fn failure(code: i64, detail: impl Into<String>) -> RpcError {
+    let message = match code {
+        PARSE_ERROR => "Parse error",
+        INVALID_REQUEST => "Invalid Request",
+        METHOD_NOT_FOUND => "Method not found",
+        INVALID_PARAMS => "Invalid params",
+        _ => "Internal error",
+    };
+    RpcError::with_data(code, message, Value::String(detail.into()))
+}
+
+/// Returns an error response
+fn error_response(id: Value, error: &RpcError) -> Value {
+    json!({"jsonrpc": "2.0", "error": error.to_value(), "id": id})
+}
+
+/// Orders params sent by name as the method declares them. Optional parameters
+/// that are left out are null.
+fn params_by_name(named: &Map<String, Value>, expected: &[Value]) -> Result<Vec<Value>, String> {
+    let mut params = Vec::with_capacity(expected.len());
+    for def in expected {
+        let name = def.get("name").and_then(Value::as_str).unwrap_or_default();
+        match named.get(name) {
+            Some(value) => params.push(value.clone()),
+            None if is_optional(def) => params.push(Value::Null),
+            None => return Err(format!("missing parameter '{}'", name)),
+        }
+    }
+    for name in named.keys() {
+        if !expected.iter().any(|def| def.get("name").and_then(Value::as_str) == Some(name)) {
+            return Err(format!("unknown parameter '{}'", name));
+        }
+    }
+    Ok(params)
+}
+
+fn is_optional(param_def: &Value) -> bool {
+    param_def.get("optional").and_then(Value::as_bool).unwrap_or(false)
+}
+
+/// Returns the [default] of a parameter as JSON. The IDL writes it as text, which
+/// is the value itself for strings and enums.
+fn default_value(param_def: &Value) -> Option<Value> {
+    let text = annotation(param_def, "default")?;
+    let param_type = param_def.get("type")?;
+    if param_type.get("builtIn").and_then(Value::as_str) == Some("string") || param_type.get("userDefined").is_some() {
+        return Some(Value::String(text.to_string()));
+    }
+    serde_json::from_str(text).ok()
+}
+
+/// Returns the value of an annotation of an idl.json definition
+fn annotation<'a>(def: &'a Value, name: &str) -> Option<&'a str> {
+    def.get("annotations")?
+        .as_array()?
+        .iter()
+        .find(|a| a.get("name").and_then(Value::as_str) == Some(name))
+        .map(|a| a.get("value").and_then(Value::as_str).unwrap_or_default())
+}
+
+/// Returns the interfaces an interface definition extends
+fn extends(iface: &Value) -> Vec<String> {
+    iface
+        .get("extends")
+        .and_then(Value::as_array)
+        .into_iter()
+        .flatten()
+        .filter_map(|name| name.as_str().map(String::from))
+        .collect()
+}
@@ -0,0 +1,22 @@
+//! PulseRPC runtime library for generated Rust code.
+//!
+//! Generated crates include this directory as their `pulserpc` module. Type
+//! definitions are read from the crate's idl.json, which the server uses to
+//! validate params and results before they reach or leave a handler.
+
+pub mod canonical;
+pub mod dispatch;
+pub mod numbers;
+pub mod rpc;
+pub mod serve;
+pub mod transport;
+pub mod types;
+pub mod validation;
+
+pub use canonical::{canonical_json, request_hash};
+pub use dispatch::{decode_param, encode_result, Dispatcher, Handler};
+pub use numbers::NumberPolicy;
+pub use rpc::{Error, RpcError};
+pub use transport::{call_method, encode_param, HttpTransport, Transport};
+pub use types::IdlTypes;
+pub use validation::validate_type;
@@ -0,0 +1,98 @@
+//! The number policy of int params
+
+use super::types::IdlTypes;
+use serde_json::{Number, Value};
+
+/// NumberPolicy controls whether a server accepts an int param written with a
+/// fraction or exponent, such as 2.0. A float accepts any number under either
+/// policy, and an int with a fractional part, such as 2.5, is always rejected.
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Default)]
+pub enum NumberPolicy {
+    /// Accepts any number with no fractional part for int, so 2.0 is taken as 2.
+    /// It is the default.
+    #[default]
+    Lenient,
+    /// Accepts only integer literals, such as 2, for int
+    Strict,
+}
+
+/// Returns an error if a value of type int in value is written with a fraction or
+/// exponent. serde_json keeps such numbers as floats, so the literal is known
+/// without parsing the request again. Values of the wrong type are left to
+/// `validate_type`.
+pub fn check_int_literals(value: &Value, type_def: &Value, types: &IdlTypes) -> Result<(), String> {
+    match value {
+        Value::Number(n) => {
+            if type_def.get("builtIn").and_then(Value::as_str) == Some("int") && n.is_f64() {
+                return Err(format!("expected int, got {}", n));
+            }
+        }
+        Value::Array(elements) => {
+            if let Some(element_type) = type_def.get("array") {
+                for (i, element) in elements.iter().enumerate() {
+                    check_int_literals(element, element_type, types)
+                        .map_err(|err| format!("array element at index {} validation failed: {}", i, err))?;
+                }
+            }
+        }
+        Value::Object(entries) => {
+            if let Some(value_type) = type_def.get("mapValue") {
+                for (key, entry) in entries {
+                    check_int_literals(entry, value_type, types)
+                        .map_err(|err| format!("map value for key '{}' validation failed: {}", key, err))?;
+                }
+                return Ok(());
+            }
+            let struct_name = type_def.get("userDefined").and_then(Value::as_str).unwrap_or_default();
+            for field in types.struct_fields(struct_name) {
+                let field_name = field.get("name").and_then(Value::as_str).unwrap_or_default();
+                if let (Some(entry), Some(field_type)) = (entries.get(field_name), field.get("type")) {
+                    check_int_literals(entry, field_type, types).map_err(|err| {
+                        format!("field '{}' in struct {} validation failed: {}", field_name, struct_name, err)
+                    })?;
+                }
+            }
+        }
+        _ => {}
+    }
+    Ok(())
+}
+
+/// Rewrites the whole numbers written with a fraction, such as 2.0, at the int
+/// positions of a validated value as integers, so that serde decodes them into i64
+pub fn normalize_ints(value: &mut Value, type_def: &Value, types: &IdlTypes) {
+    match value {
+        Value::Number(n) => {
+            if type_def.get("builtIn").and_then(Value::as_str) == Some("int") && n.is_f64() {
+                if let Some(f) = n.as_f64() {
+                    if f.fract() == 0.0 && f >= i64::MIN as f64 && f <= i64::MAX as f64 {
+                        *value = Value::Number(Number::from(f as i64));
+                    }
+                }
+            }
+        }
+        Value::Array(elements) => {
+            if let Some(element_type) = type_def.get("array") {
+                for element in elements {
+                    normalize_ints(element, element_type, types);
+                }
+            }
+        }
+        Value::Object(entries) => {
+            if let Some(value_type) = type_def.get("mapValue") {
+                for entry in entries.values_mut() {
+                    normalize_ints(entry, value_type, types);
+                }
+                return;
+            }
+            let struct_name = type_def.get("userDefined").and_then(Value::as_str).unwrap_or_default();
+            for field in types.struct_fields(struct_name) {
+                let field_name = field.get("name").and_then(Value::as_str).unwrap_or_default();
+                if let (Some(entry), Some(field_type)) = (entries.get_mut(field_name), field.get("type")) {
+                    normalize_ints(entry, field_type, types);
+                }
+            }
+        }
+        _ => {}
+    }
+}
@@ -0,0 +1,107 @@
+//! JSON-RPC errors
+
+use serde_json::{json, Value};
+use std::fmt;
+
+/// Invalid JSON was received
+pub const PARSE_ERROR: i64 = -32700;
+/// The JSON sent is not a valid request object
+pub const INVALID_REQUEST: i64 = -32600;
+/// The method does not exist or its interface has no handler
+pub const METHOD_NOT_FOUND: i64 = -32601;
+/// The params do not match the method's parameters
+pub const INVALID_PARAMS: i64 = -32602;
+/// The handler failed or returned a result that does not match the return type
+pub const INTERNAL_ERROR: i64 = -32603;
+
+/// A JSON-RPC 2.0 error. Handlers return it to send an error response, and clients
+/// return it inside `Error::Rpc` when the server answers with one.
+#[derive(Debug, Clone, PartialEq)]
+pub struct RpcError {
+    pub code: i64,
+    pub message: String,
+    pub data: Option<Value>,
+}
+
+impl RpcError {
+    /// Creates an error with the given code and message
+    pub fn new(code: i64, message: impl Into<String>) -> Self {
+        RpcError {
+            code,
+            message: message.into(),
+            data: None,
+        }
+    }
+
+    /// Creates an error with the given code, message and data
+    pub fn with_data(code: i64, message: impl Into<String>, data: Value) -> Self {
+        RpcError {
+            code,
+            message: message.into(),
+            data: Some(data),
+        }
+    }
+
+    /// Reads the error member of a response. Missing members become a code of 0
+    /// and an empty message.
+    pub fn from_value(value: &Value) -> Self {
+        RpcError {
+            code: value.get("code").and_then(Value::as_i64).unwrap_or(0),
+            message: value
+                .get("message")
+                .and_then(Value::as_str)
+                .unwrap_or_default()
+                .to_string(),
+            data: value.get("data").filter(|data| !data.is_null()).cloned(),
+        }
+    }
+
+    /// Returns the error member of a response
+    pub fn to_value(&self) -> Value {
+        let mut error = json!({"code": self.code, "message": self.message});
+        if let Some(data) = &self.data {
+            error["data"] = data.clone();
+        }
+        error
+    }
+}
+
+impl fmt::Display for RpcError {
+    fn fmt(&self, f: &mut fmt::Formatter<'_>) -> fmt::Result {
+        match &self.data {
+            Some(data) => write!(f, "RpcError {}: {} (data: {})", self.code, self.message, data),
+            None => write!(f, "RpcError {}: {}", self.code, self.message),
+        }
+    }
+}
+
+impl std::error::Error for RpcError {}
+
+/// The error of a client call
+#[derive(Debug)]
+pub enum Error {
+    /// The server answered with a JSON-RPC error
+    Rpc(RpcError),
+    /// The request could not be sent or the response could not be read
+    Transport(String),
+    /// The params could not be encoded or the result could not be decoded
+    Decode(String),
+}
+
+impl fmt::Display for Error {
+    fn fmt(&self, f: &mut fmt::Formatter<'_>) -> fmt::Result {
+        match self {
+            Error::Rpc(err) => err.fmt(f),
+            Error::Transport(message) => write!(f, "transport error: {}", message),
+            Error::Decode(message) => write!(f, "decode error: {}", message),
+        }
+    }
+}
+
+impl std::error::Error for Error {}
+
+impl From<RpcError> for Error {
+    fn from(err: RpcError) -> Self {
+        Error::Rpc(err)
+    }
+}
@@ -0,0 +1,116 @@
+//! The HTTP server of a dispatcher
+
+use super::dispatch::Dispatcher;
+use super::rpc::{RpcError, INVALID_REQUEST};
+use bytes::Bytes;
+use http_body_util::{BodyExt, Full};
+use hyper::body::Incoming;
+use hyper::header::{ALLOW, CONTENT_TYPE};
+use hyper::server::conn::http1;
+use hyper::service::service_fn;
+use hyper::{Method, Request, Response, StatusCode};
+use hyper_util::rt::TokioIo;
+use serde_json::json;
+use std::convert::Infallible;
+use std::io;
+use std::sync::Arc;
+use tokio::net::TcpListener;
+
+/// Serves JSON-RPC requests over HTTP POST on host:port until the process exits.
+/// Handlers run on a blocking thread pool, so they may block. Any Content-Type is
+/// accepted unless strict_content_type is set, in which case requests that are not
+/// application/json (or application/*+json) in utf-8 are answered with 415.
+pub fn serve(host: &str, port: u16, dispatcher: Arc<Dispatcher>, strict_content_type: bool) -> io::Result<()> {
+    let runtime = tokio::runtime::Builder::new_multi_thread().enable_all().build()?;
+    runtime.block_on(async move {
+        let listener = TcpListener::bind((host, port)).await?;
+        loop {
+            let (stream, _) = listener.accept().await?;
+            let dispatcher = dispatcher.clone();
+            tokio::spawn(async move {
+                let service = service_fn(move |request| handle(dispatcher.clone(), strict_content_type, request));
+                // Connection errors only affect the client that caused them
+                let _ = http1::Builder::new().serve_connection(TokioIo::new(stream), service).await;
+            });
+        }
+    })
+}
+
+async fn handle(
+    dispatcher: Arc<Dispatcher>,
+    strict_content_type: bool,
+    request: Request<Incoming>,
+) -> Result<Response<Full<Bytes>>, Infallible> {
+    if request.method() != Method::POST {
+        let mut response = plain(StatusCode::METHOD_NOT_ALLOWED, "Method not allowed");
+        response.headers_mut().insert(ALLOW, "POST".parse().unwrap());
+        return Ok(response);
+    }
+    let problem = if strict_content_type {
+        check_content_type(request.headers().get(CONTENT_TYPE).map(|h| h.to_str().unwrap_or("?")))
+    } else {
+        None
+    };
+    if let Some(problem) = problem {
+        let error = RpcError::with_data(INVALID_REQUEST, "Invalid Request", json!(problem));
+        let body = json!({"jsonrpc": "2.0", "error": error.to_value(), "id": null});
+        return Ok(json_response(StatusCode::UNSUPPORTED_MEDIA_TYPE, body.to_string().into_bytes()));
+    }
+
+    let body = match request.into_body().collect().await {
+        Ok(body) => body.to_bytes(),
+        Err(_) => return Ok(plain(StatusCode::BAD_REQUEST, "Failed to read request body")),
+    };
+    let response = tokio::task::spawn_blocking(move || dispatcher.handle_message(&body)).await;
+    Ok(match response {
+        Ok(Some(body)) => json_response(StatusCode::OK, body),
+        Ok(None) => Response::builder()
+            .status(StatusCode::NO_CONTENT)
+            .body(Full::new(Bytes::new()))
+            .unwrap(),
+        Err(_) => plain(StatusCode::INTERNAL_SERVER_ERROR, "Internal server error"),
+    })
+}
+
+/// Returns the problem with the Content-Type of a request in strict mode, if any
+fn check_content_type(header: Option<&str>) -> Option<String> {
+    let header = match header {
+        Some(header) if !header.trim().is_empty() => header,
+        _ => return Some("Missing Content-Type header; expected application/json".to_string()),
+    };
+    let mut parts = header.split(';');
+    let media_type = parts.next().unwrap_or_default().trim().to_ascii_lowercase();
+    let is_json = media_type == "application/json"
+        || (media_type.starts_with("application/") && media_type.ends_with("+json"));
+    if !is_json {
+        return Some(format!("Unsupported Content-Type '{}'; expected application/json", media_type));
+    }
+    for param in parts {
+        if let Some((name, value)) = param.split_once('=') {
+            let charset = value.trim().trim_matches('"');
+            if name.trim().eq_ignore_ascii_case("charset")
+                && !charset.eq_ignore_ascii_case("utf-8")
+                && !charset.eq_ignore_ascii_case("utf8")
+            {
+                return Some(format!("Unsupported charset '{}'; expected utf-8", charset));
+            }
+        }
+    }
+    None
+}
+
+fn json_response(status: StatusCode, body: Vec<u8>) -> Response<Full<Bytes>> {
+    Response::builder()
+        .status(status)
+        .header(CONTENT_TYPE, "application/json")
+        .body(Full::new(Bytes::from(body)))
+        .unwrap()
+}
+
+fn plain(status: StatusCode, message: &str) -> Response<Full<Bytes>> {
+    Response::builder()
+        .status(status)
+        .header(CONTENT_TYPE, "text/plain")
+        .body(Full::new(Bytes::from(message.to_string())))
+        .unwrap()
+}
@@ -0,0 +1,96 @@
+//! Client transports
+
+use super::canonical::canonical_json;
+use super::rpc::{Error, RpcError};
+use serde::de::DeserializeOwned;
+use serde::Serialize;
+use serde_json::{json, Value};
+use std::collections::HashMap;
+
+/// Transport sends a JSON-RPC call and returns its result. Generated clients call
+/// methods through it, so tests can replace HTTP with an in-process transport.
+pub trait Transport: Send + Sync {
+    fn call(&self, method: &str, params: Vec<Value>) -> Result<Value, Error>;
+}
+
+/// HttpTransport sends calls as HTTP POST requests to a single endpoint
+pub struct HttpTransport {
+    url: String,
+    headers: HashMap<String, String>,
+    client: reqwest::blocking::Client,
+    canonical_json: bool,
+}
+
+impl HttpTransport {
+    /// Creates a transport for the endpoint at url
+    pub fn new(url: impl Into<String>) -> Self {
+        Self::with_headers(url, HashMap::new())
+    }
+
+    /// Creates a transport that adds headers, such as Authorization, to every request
+    pub fn with_headers(url: impl Into<String>, headers: HashMap<String, String>) -> Self {
+        HttpTransport {
+            url: url.into(),
+            headers,
+            client: reqwest::blocking::Client::new(),
+            canonical_json: false,
+        }
+    }
+
+    /// Sets whether requests are encoded as canonical JSON (RFC 8785, see
+    /// `canonical_json`), so a server in any language can recompute the signed body
+    /// from the request it decoded
+    pub fn set_canonical_json(&mut self, enabled: bool) {
+        self.canonical_json = enabled;
+    }
+}
+
+impl Transport for HttpTransport {
+    fn call(&self, method: &str, params: Vec<Value>) -> Result<Value, Error> {
+        let id = uuid::Uuid::new_v4().to_string();
+        let request = json!({"jsonrpc": "2.0", "method": method, "params": params, "id": id});
+
+        let body = if self.canonical_json {
+            canonical_json(&request).map_err(Error::Decode)?
+        } else {
+            request.to_string()
+        };
+        let mut builder = self.client.post(&self.url).header("Content-Type", "application/json").body(body);
+        for (name, value) in &self.headers {
+            builder = builder.header(name, value);
+        }
+        let response = builder.send().map_err(|err| Error::Transport(err.to_string()))?;
+        let status = response.status();
+        let body = response.text().map_err(|err| Error::Transport(err.to_string()))?;
+        let response: Value = serde_json::from_str(&body).map_err(|err| {
+            if status.is_success() {
+                Error::Decode(format!("invalid JSON response: {}", err))
+            } else {
+                Error::Transport(format!("HTTP {}: {}", status, body))
+            }
+        })?;
+
+        if let Some(error) = response.get("error").filter(|error| !error.is_null()) {
+            return Err(Error::Rpc(RpcError::from_value(error)));
+        }
+        if response.get("id").and_then(Value::as_str) != Some(id.as_str()) {
+            return Err(Error::Decode(format!(
+                "response id {} does not match request id {}",
+                response.get("id").unwrap_or(&Value::Null),
+                id
+            )));
+        }
+        Ok(response.get("result").cloned().unwrap_or(Value::Null))
+    }
+}
+
+/// Calls a method through a transport and decodes its result
+pub fn call_method<T: DeserializeOwned>(transport: &dyn Transport, method: &str, params: Vec<Value>) -> Result<T, Error> {
+    let result = transport.call(method, params)?;
+    serde_json::from_value(result).map_err(|err| Error::Decode(format!("result of {}: {}", method, err)))
+}
+
+/// Encodes a param of a call
+pub fn encode_param<T: Serialize>(param: &T) -> Result<Value, Error> {
+    serde_json::to_value(param).map_err(|err| Error::Decode(err.to_string()))
+}
@@ -0,0 +1,106 @@
+//! Type definitions read from an idl.json document
+
+use serde_json::{Map, Value};
+use std::collections::HashMap;
+
+/// The structs, enums and interfaces of an idl.json document, indexed by name.
+/// Structs and enums are found by their base name, which is unique across the
+/// namespaces of an IDL, so "inc.Response" and "Response" name the same struct.
+#[derive(Debug, Clone, Default)]
+pub struct IdlTypes {
+    document: Value,
+    structs: HashMap<String, Value>,
+    enums: HashMap<String, Value>,
+    interfaces: HashMap<String, Value>,
+}
+
+impl IdlTypes {
+    /// Parses an idl.json document
+    pub fn parse(idl_json: &str) -> Result<Self, serde_json::Error> {
+        Ok(Self::from_document(serde_json::from_str(idl_json)?))
+    }
+
+    /// Indexes a decoded idl.json document
+    pub fn from_document(document: Value) -> Self {
+        let index = |key: &str, base: bool| -> HashMap<String, Value> {
+            let mut defs = HashMap::new();
+            for def in document.get(key).and_then(Value::as_array).into_iter().flatten() {
+                if let Some(name) = def.get("name").and_then(Value::as_str) {
+                    let name = if base { base_name(name) } else { name };
+                    defs.insert(name.to_string(), def.clone());
+                }
+            }
+            defs
+        };
+        IdlTypes {
+            structs: index("structs", true),
+            enums: index("enums", true),
+            interfaces: index("interfaces", false),
+            document,
+        }
+    }
+
+    /// Returns the idl.json document
+    pub fn document(&self) -> &Value {
+        &self.document
+    }
+
+    /// Finds a struct definition by qualified or base name
+    pub fn find_struct(&self, name: &str) -> Option<&Value> {
+        self.structs.get(base_name(name))
+    }
+
+    /// Finds an enum definition by qualified or base name
+    pub fn find_enum(&self, name: &str) -> Option<&Value> {
+        self.enums.get(base_name(name))
+    }
+
+    /// Finds an interface definition by name
+    pub fn find_interface(&self, name: &str) -> Option<&Value> {
+        self.interfaces.get(name)
+    }
+
+    /// Returns the interface definitions, in no particular order
+    pub fn interfaces(&self) -> impl Iterator<Item = &Value> {
+        self.interfaces.values()
+    }
+
+    /// Finds the definition of a method of an interface, including inherited methods
+    pub fn find_method(&self, interface: &str, method: &str) -> Option<&Value> {
+        self.find_interface(interface)?
+            .get("methods")?
+            .as_array()?
+            .iter()
+            .find(|m| m.get("name").and_then(Value::as_str) == Some(method))
+    }
+
+    /// Returns the fields of a struct, parent fields first. A field a struct
+    /// declares again replaces the parent's field of that name.
+    pub fn struct_fields(&self, name: &str) -> Vec<&Map<String, Value>> {
+        let def = match self.find_struct(name) {
+            Some(def) => def,
+            None => return Vec::new(),
+        };
+        let mut fields = match def.get("extends").and_then(Value::as_str) {
+            Some(parent) if !parent.is_empty() => self.struct_fields(parent),
+            _ => Vec::new(),
+        };
+        for field in def.get("fields").and_then(Value::as_array).into_iter().flatten() {
+            let field = match field.as_object() {
+                Some(field) => field,
+                None => continue,
+            };
+            let field_name = field.get("name");
+            match fields.iter().position(|f| f.get("name") == field_name) {
+                Some(i) => fields[i] = field,
+                None => fields.push(field),
+            }
+        }
+        fields
+    }
+}
+
+/// Returns the name without its namespace: "inc.Response" -> "Response"
+pub fn base_name(name: &str) -> &str {
+    name.rsplit('.').next().unwrap_or(name)
+}
@@ -0,0 +1,161 @@
+//! Validation of JSON values against IDL types
+
+use super::types::IdlTypes;
+use serde_json::Value;
+
+/// Returns the JSON type of a value, for error messages
+pub fn json_type(value: &Value) -> &'static str {
+    match value {
+        Value::Null => "null",
+        Value::Bool(_) => "bool",
+        Value::Number(_) => "number",
+        Value::String(_) => "string",
+        Value::Array(_) => "array",
+        Value::Object(_) => "object",
+    }
+}
+
+/// Validates that value is a string
+pub fn validate_string(value: &Value) -> Result<(), String> {
+    match value {
+        Value::String(_) => Ok(()),
+        _ => Err(format!("expected string, got {}", json_type(value))),
+    }
+}
+
+/// Validates that value is an int. Numbers written with a fraction are accepted
+/// as long as the fraction is zero, so 2.0 is an int; see `NumberPolicy`.
+pub fn validate_int(value: &Value) -> Result<(), String> {
+    match value {
+        Value::Number(n) if n.is_i64() || n.is_u64() => Ok(()),
+        Value::Number(n) => match n.as_f64() {
+            Some(f) if f.fract() == 0.0 => Ok(()),
+            _ => Err(format!("expected int, got {}", n)),
+        },
+        _ => Err(format!("expected int, got {}", json_type(value))),
+    }
+}
+
+/// Validates that value is a number
+pub fn validate_float(value: &Value) -> Result<(), String> {
+    match value {
+        Value::Number(_) => Ok(()),
+        _ => Err(format!("expected float, got {}", json_type(value))),
+    }
+}
+
+/// Validates that value is a bool
+pub fn validate_bool(value: &Value) -> Result<(), String> {
+    match value {
+        Value::Bool(_) => Ok(()),
+        _ => Err(format!("expected bool, got {}", json_type(value))),
+    }
+}
+
+/// Validates that value is one of the values of an enum definition
+pub fn validate_enum(value: &Value, enum_name: &str, enum_def: &Value) -> Result<(), String> {
+    let text = match value {
+        Value::String(text) => text,
+        _ => return Err(format!("expected string for enum {}, got {}", enum_name, json_type(value))),
+    };
+    let allowed: Vec<&str> = enum_def
+        .get("values")
+        .and_then(Value::as_array)
+        .into_iter()
+        .flatten()
+        .filter_map(|v| v.get("name").and_then(Value::as_str))
+        .collect();
+    if allowed.contains(&text.as_str()) {
+        Ok(())
+    } else {
+        Err(format!(
+            "invalid value for enum {}: '{}'. Allowed values: {:?}",
+            enum_name, text, allowed
+        ))
+    }
+}
+
+/// Validates that value is an object with the fields of a struct, including the
+/// fields it inherits. Fields the struct does not declare are ignored.
+pub fn validate_struct(value: &Value, struct_name: &str, types: &IdlTypes) -> Result<(), String> {
+    let object = match value {
+        Value::Object(object) => object,
+        _ => return Err(format!("expected object for struct {}, got {}", struct_name, json_type(value))),
+    };
+    for field in types.struct_fields(struct_name) {
+        let field_name = field.get("name").and_then(Value::as_str).unwrap_or_default();
+        let optional = field.get("optional").and_then(Value::as_bool).unwrap_or(false);
+        match object.get(field_name) {
+            None if !optional => {
+                return Err(format!("missing required field '{}' in struct {}", field_name, struct_name))
+            }
+            Some(Value::Null) if !optional => {
+                return Err(format!("field '{}' in struct {} cannot be null", field_name, struct_name))
+            }
+            None | Some(Value::Null) => {}
+            Some(field_value) => {
+                let field_type = field.get("type").unwrap_or(&Value::Null);
+                validate_type(field_value, field_type, types, false).map_err(|err| {
+                    format!("field '{}' in struct {} validation failed: {}", field_name, struct_name, err)
+                })?;
+            }
+        }
+    }
+    Ok(())
+}
+
+/// Validates a value against a type definition of idl.json. Null is only valid
+/// when optional is true.
+pub fn validate_type(value: &Value, type_def: &Value, types: &IdlTypes, optional: bool) -> Result<(), String> {
+    if value.is_null() {
+        return if optional {
+            Ok(())
+        } else {
+            Err("value cannot be null for non-optional type".to_string())
+        };
+    }
+
+    if let Some(built_in) = type_def.get("builtIn").and_then(Value::as_str) {
+        return match built_in {
+            "string" => validate_string(value),
+            "int" => validate_int(value),
+            "float" => validate_float(value),
+            "bool" => validate_bool(value),
+            _ => Err(format!("unknown built-in type: {}", built_in)),
+        };
+    }
+
+    if let Some(element_type) = type_def.get("array") {
+        let elements = value
+            .as_array()
+            .ok_or_else(|| format!("expected array, got {}", json_type(value)))?;
+        for (i, element) in elements.iter().enumerate() {
+            validate_type(element, element_type, types, false)
+                .map_err(|err| format!("array element at index {} validation failed: {}", i, err))?;
+        }
+        return Ok(());
+    }
+
+    if let Some(value_type) = type_def.get("mapValue") {
+        let entries = value
+            .as_object()
+            .ok_or_else(|| format!("expected map, got {}", json_type(value)))?;
+        for (key, entry) in entries {
+            validate_type(entry, value_type, types, false)
+                .map_err(|err| format!("map value for key '{}' validation failed: {}", key, err))?;
+        }
+        return Ok(());
+    }
+
+    if let Some(user_defined) = type_def.get("userDefined").and_then(Value::as_str) {
+        if types.find_struct(user_defined).is_some() {
+            return validate_struct(value, user_defined, types);
+        }
+        if let Some(enum_def) = types.find_enum(user_defined) {
+            return validate_enum(value, user_defined, enum_def);
+        }
+        return Err(format!("unknown user-defined type: {}", user_defined));
+    }
+
+    Err(format!("invalid type definition: {}", type_def))
+}
@@ -0,0 +1,59 @@
+use pulserpc::{canonical_json, request_hash};
+use serde_json::{json, Value};
+
+// The same vectors are checked by every other runtime's tests, so every runtime
+// hashes a call the same way, byte for byte
+fn request_hash_vectors() -> Vec<(&'static str, Value, &'static str, &'static str)> {
+    vec![
+        (
+            "Catalog.get",
+            json!(["p-1", 2]),
+            r#"{"method":"Catalog.get","params":["p-1",2]}"#,
+            "c83d6e2a3e0908ddb5e94fe05cffd450756e92caab52d017ad51be7b97fcb715",
+        ),
+        (
+            "Catalog.search",
+            json!([{"query": "café \"x\"\n", "limit": 10, "tags": ["a", "b"], "price": {"max": 99.5, "min": 0.1}}, null]),
+            r#"{"method":"Catalog.search","params":[{"limit":10,"price":{"max":99.5,"min":0.1},"query":"café \"x\"\n","tags":["a","b"]},null]}"#,
+            "bac87c8eb687a2e88fb49b08c30f0668d849c06613310acd110c66d42acc56ae",
+        ),
+        (
+            "Catalog.bulk",
+            json!({"ids": [1e21, 1e-7, -0.0, 1u64 << 60], "é": true, "a": false, "\u{1F600}": 1, "｡": 2}),
+            "{\"method\":\"Catalog.bulk\",\"params\":{\"a\":false,\"ids\":[1e+21,1e-7,0,1152921504606847000],\"é\":true,\"\u{1F600}\":1,\"｡\":2}}",
+            "53bd8c11499fccf79fedaff23d160964bba118c23421f68f3167c40eb2a7750b",
+        ),
+        // 2^53+1 is not a double: canonical JSON writes the nearest one, 2^53
+        (
+            "Catalog.edges",
+            json!([1e21, 1e-7, -0.0, (1u64 << 53) + 1]),
+            r#"{"method":"Catalog.edges","params":[1e+21,1e-7,0,9007199254740992]}"#,
+            "379f1ac102b547987989aaba1ce4f935c28a1d5b880068e36ffd2b017f2f98e5",
+        ),
+    ]
+}
+
+#[test]
+fn request_hash_matches_vectors() {
+    for (method, params, canonical, hash) in request_hash_vectors() {
+        assert_eq!(canonical_json(&json!({"method": method, "params": params})).unwrap(), canonical, "{}", method);
+        assert_eq!(request_hash(method, &params).unwrap(), hash, "{}", method);
+    }
+}
+
+#[test]
+fn formats_numbers_as_javascript() {
+    let cases = [
+        (0.0, "0"),
+        (-1.5, "-1.5"),
+        (123.456, "123.456"),
+        (1e20, "100000000000000000000"),
+        (1e-6, "0.000001"),
+        (1.5e300, "1.5e+300"),
+        (5e-324, "5e-324"),
+        (-2.5e-10, "-2.5e-10"),
+    ];
+    for (value, expected) in cases {
+        assert_eq!(canonical_json(&json!(value)).unwrap(), expected);
+    }
+}
@@ -0,0 +1,140 @@
+use pulserpc::{decode_param, encode_result, Dispatcher, Handler, NumberPolicy, RpcError};
+use serde_json::{json, Value};
+
+const IDL: &str = r#"{
+  "structs": [],
+  "enums": [],
+  "interfaces": [
+    {"name": "Calc", "annotations": [{"name": "wire", "value": "calc"}], "methods": [
+      {"name": "add", "parameters": [
+        {"name": "a", "type": {"builtIn": "int"}},
+        {"name": "b", "type": {"builtIn": "int"}, "optional": true,
+         "annotations": [{"name": "default", "value": "10"}]}
+      ], "returnType": {"builtIn": "int"}},
+      {"name": "broken", "parameters": [], "returnType": {"builtIn": "int"}},
+      {"name": "big", "parameters": [], "returnType": {"builtIn": "float"}}
+    ]}
+  ]
+}"#;
+
+struct Calc;
+
+impl Handler for Calc {
+    fn call(&self, method: &str, params: Vec<Value>) -> Result<Value, RpcError> {
+        let mut params = params.into_iter();
+        match method {
+            "add" => {
+                let a: i64 = decode_param(&mut params)?;
+                let b: i64 = decode_param(&mut params)?;
+                encode_result(a + b)
+            }
+            "broken" => encode_result("not an int"),
+            "big" => encode_result(1e21),
+            _ => Err(RpcError::new(-32601, "Method not found")),
+        }
+    }
+}
+
+fn dispatcher() -> Dispatcher {
+    let mut dispatcher = Dispatcher::new(IDL);
+    dispatcher.register("Calc", Box::new(Calc));
+    dispatcher
+}
+
+fn call(dispatcher: &Dispatcher, request: Value) -> Value {
+    dispatcher.handle_request(&request).expect("expected a response")
+}
+
+fn error_code(response: &Value) -> i64 {
+    response["error"]["code"].as_i64().expect("expected an error")
+}
+
+#[test]
+fn calls_the_handler() {
+    let response = call(&dispatcher(), json!({"jsonrpc": "2.0", "method": "Calc.add", "params": [2, 3], "id": 1}));
+    assert_eq!(response["result"], json!(5));
+    assert_eq!(response["id"], json!(1));
+}
+
+#[test]
+fn fills_in_defaults_and_named_params() {
+    let dispatcher = dispatcher();
+    let response = call(&dispatcher, json!({"jsonrpc": "2.0", "method": "Calc.add", "params": [2], "id": 1}));
+    assert_eq!(response["result"], json!(12));
+    let response = call(&dispatcher, json!({"jsonrpc": "2.0", "method": "Calc.add", "params": {"b": 1, "a": 2}, "id": 2}));
+    assert_eq!(response["result"], json!(3));
+}
+
+#[test]
+fn dispatches_wire_names() {
+    let response = call(&dispatcher(), json!({"jsonrpc": "2.0", "method": "calc.add", "params": [1, 1], "id": 1}));
+    assert_eq!(response["result"], json!(2));
+}
+
+#[test]
+fn applies_the_number_policy() {
+    let mut dispatcher = dispatcher();
+    let request = json!({"jsonrpc": "2.0", "method": "Calc.add", "params": [2.0, 3], "id": 1});
+    assert_eq!(call(&dispatcher, request.clone())["result"], json!(5));
+    dispatcher.set_number_policy(NumberPolicy::Strict);
+    assert_eq!(error_code(&call(&dispatcher, request)), -32602);
+}
+
+#[test]
+fn rejects_invalid_calls() {
+    let dispatcher = dispatcher();
+    let cases = [
+        (json!({"jsonrpc": "1.0", "method": "Calc.add", "id": 1}), -32600),
+        (json!({"jsonrpc": "2.0", "method": "Calc.nope", "id": 1}), -32601),
+        (json!({"jsonrpc": "2.0", "method": "Other.add", "id": 1}), -32601),
+        (json!({"jsonrpc": "2.0", "method": "Calc.add", "params": [], "id": 1}), -32602),
+        (json!({"jsonrpc": "2.0", "method": "Calc.add", "params": [1, 2, 3], "id": 1}), -32602),
+        (json!({"jsonrpc": "2.0", "method": "Calc.add", "params": ["x"], "id": 1}), -32602),
+        (json!({"jsonrpc": "2.0", "method": "Calc.broken", "id": 1}), -32603),
+    ];
+    for (request, code) in cases {
+        assert_eq!(error_code(&call(&dispatcher, request.clone())), code, "{}", request);
+    }
+}
+
+#[test]
+fn handles_batches_and_notifications() {
+    let dispatcher = dispatcher();
+    let notification = br#"{"jsonrpc": "2.0", "method": "Calc.add", "params": [1, 2]}"#;
+    assert!(dispatcher.handle_message(notification).is_none());
+
+    let batch = br#"[{"jsonrpc": "2.0", "method": "Calc.add", "params": [1, 2], "id": 1},
+                     {"jsonrpc": "2.0", "method": "Calc.add", "params": [1, 2]}]"#;
+    let response: Value = serde_json::from_slice(&dispatcher.handle_message(batch).unwrap()).unwrap();
+    assert_eq!(response, json!([{"jsonrpc": "2.0", "result": 3, "id": 1}]));
+
+    let response: Value = serde_json::from_slice(&dispatcher.handle_message(b"{").unwrap()).unwrap();
+    assert_eq!(error_code(&response), -32700);
+}
+
+#[test]
+fn encodes_canonical_json() {
+    let mut dispatcher = dispatcher();
+    let request = br#"[{"jsonrpc": "2.0", "method": "Calc.big", "id": 1}]"#;
+    let response = String::from_utf8(dispatcher.handle_message(request).unwrap()).unwrap();
+    assert_eq!(response, r#"[{"id":1,"jsonrpc":"2.0","result":1e21}]"#);
+
+    // Numbers are formatted as JavaScript does, as every canonical server writes them
+    dispatcher.set_canonical_json(true);
+    let response = String::from_utf8(dispatcher.handle_message(request).unwrap()).unwrap();
+    assert_eq!(response, r#"[{"id":1,"jsonrpc":"2.0","result":1e+21}]"#);
+}
+
+#[test]
+fn serves_the_idl() {
+    let response = call(&dispatcher(), json!({"jsonrpc": "2.0", "method": "pulserpc-idl", "id": 1}));
+    assert_eq!(response["result"]["interfaces"][0]["name"], json!("Calc"));
+}
+
+#[test]
+fn reports_capabilities() {
+    let response = call(&dispatcher(), json!({"jsonrpc": "2.0", "method": "pulserpc-capabilities", "id": 1}));
+    assert_eq!(response["result"]["protocolVersion"], json!("2.0"));
+    assert_eq!(response["result"]["batch"], json!(true));
+    assert_eq!(response["result"]["compression"], json!(false));
+}
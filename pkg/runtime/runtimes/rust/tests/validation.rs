@@ -0,0 +1,83 @@
+use pulserpc::{validate_type, IdlTypes, NumberPolicy};
+use serde_json::json;
+
+fn types() -> IdlTypes {
+    IdlTypes::from_document(json!({
+        "structs": [
+            {"name": "Base", "fields": [
+                {"name": "id", "type": {"builtIn": "string"}}
+            ]},
+            {"name": "inc.Person", "extends": "Base", "fields": [
+                {"name": "age", "type": {"builtIn": "int"}},
+                {"name": "email", "type": {"builtIn": "string"}, "optional": true},
+                {"name": "status", "type": {"userDefined": "Status"}}
+            ]}
+        ],
+        "enums": [
+            {"name": "inc.Status", "values": [{"name": "ok"}, {"name": "err"}]}
+        ]
+    }))
+}
+
+#[test]
+fn validates_built_in_types() {
+    let types = types();
+    assert!(validate_type(&json!("a"), &json!({"builtIn": "string"}), &types, false).is_ok());
+    assert!(validate_type(&json!(1), &json!({"builtIn": "string"}), &types, false).is_err());
+    assert!(validate_type(&json!(2), &json!({"builtIn": "int"}), &types, false).is_ok());
+    assert!(validate_type(&json!(2.0), &json!({"builtIn": "int"}), &types, false).is_ok());
+    assert!(validate_type(&json!(2.5), &json!({"builtIn": "int"}), &types, false).is_err());
+    assert!(validate_type(&json!(2), &json!({"builtIn": "float"}), &types, false).is_ok());
+    assert!(validate_type(&json!(true), &json!({"builtIn": "bool"}), &types, false).is_ok());
+    assert!(validate_type(&json!("true"), &json!({"builtIn": "bool"}), &types, false).is_err());
+}
+
+#[test]
+fn null_requires_optional() {
+    let types = types();
+    assert!(validate_type(&json!(null), &json!({"builtIn": "int"}), &types, true).is_ok());
+    let err = validate_type(&json!(null), &json!({"builtIn": "int"}), &types, false).unwrap_err();
+    assert_eq!(err, "value cannot be null for non-optional type");
+}
+
+#[test]
+fn validates_arrays_and_maps() {
+    let types = types();
+    let ints = json!({"array": {"builtIn": "int"}});
+    assert!(validate_type(&json!([1, 2]), &ints, &types, false).is_ok());
+    let err = validate_type(&json!([1, "x"]), &ints, &types, false).unwrap_err();
+    assert!(err.starts_with("array element at index 1"), "{}", err);
+
+    let map = json!({"mapValue": {"builtIn": "bool"}});
+    assert!(validate_type(&json!({"a": true}), &map, &types, false).is_ok());
+    assert!(validate_type(&json!({"a": 1}), &map, &types, false).is_err());
+}
+
+#[test]
+fn validates_structs_with_inherited_fields() {
+    let types = types();
+    let person = json!({"userDefined": "inc.Person"});
+    assert!(validate_type(&json!({"id": "p1", "age": 3, "status": "ok"}), &person, &types, false).is_ok());
+
+    let err = validate_type(&json!({"age": 3, "status": "ok"}), &person, &types, false).unwrap_err();
+    assert_eq!(err, "missing required field 'id' in struct inc.Person");
+
+    let err = validate_type(&json!({"id": "p1", "age": 3, "status": "bad"}), &person, &types, false).unwrap_err();
+    assert!(err.contains("invalid value for enum Status: 'bad'"), "{}", err);
+}
+
+#[test]
+fn struct_fields_put_parent_fields_first() {
+    let types = types();
+    let names: Vec<&str> = types
+        .struct_fields("Person")
+        .iter()
+        .filter_map(|f| f.get("name").and_then(|n| n.as_str()))
+        .collect();
+    assert_eq!(names, vec!["id", "age", "email", "status"]);
+}
+
+#[test]
+fn number_policy_defaults_to_lenient() {
+    assert_eq!(NumberPolicy::default(), NumberPolicy::Lenient);
+}
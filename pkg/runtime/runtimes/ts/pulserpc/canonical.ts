@@ -0,0 +1,47 @@
+/**
+ * Canonical JSON and request hashes that match across runtimes
+ */
+
+import { createHash } from "crypto";
+
+/**
+ * Returns the SHA-256, in lowercase hex, of the canonical JSON of
+ * {"method": method, "params": params}. Every runtime computes the same hash for
+ * the same call, so it can key idempotency records, caches and deduplication
+ * across services written in different languages. Params sent by position and
+ * by name hash differently.
+ */
+export function requestHash(method: string, params: unknown): string {
+  return createHash("sha256").update(canonicalJson({ method, params }), "utf8").digest("hex");
+}
+
+/**
+ * Encodes value as canonical JSON (RFC 8785): no whitespace, object keys sorted
+ * by their UTF-16 code units, numbers formatted as JavaScript does and strings
+ * escaped only where JSON requires it. Properties that are undefined are left
+ * out, as JSON.stringify does.
+ */
+export function canonicalJson(value: unknown): string {
+  if (value === null || value === undefined) {
+    return "null";
+  }
+  if (typeof value === "number") {
+    if (!Number.isFinite(value)) {
+      throw new Error(`cannot canonicalize ${value}`);
+    }
+    return String(value);
+  }
+  if (typeof value === "boolean" || typeof value === "string") {
+    return JSON.stringify(value);
+  }
+  if (Array.isArray(value)) {
+    return "[" + value.map((item) => canonicalJson(item)).join(",") + "]";
+  }
+  if (typeof value === "object") {
+    const entries = Object.entries(value as { [key: string]: unknown })
+      .filter(([, item]) => item !== undefined)
+      .sort(([a], [b]) => (a < b ? -1 : a > b ? 1 : 0));
+    return "{" + entries.map(([key, item]) => JSON.stringify(key) + ":" + canonicalJson(item)).join(",") + "}";
+  }
+  throw new Error(`cannot canonicalize ${typeof value}`);
+}
@@ -0,0 +1,37 @@
+/**
+ * Request deadlines: the time a caller allows for a call, sent in the
+ * X-PulseRPC-Deadline header as milliseconds. Servers run each call's handler
+ * with its deadline, handlers read what is left of it with remainingTimeMs(),
+ * and clients use that as the timeout of calls made while handling it, so
+ * downstream calls fit in the caller's budget.
+ */
+
+import { AsyncLocalStorage } from 'async_hooks';
+
+export const DEADLINE_HEADER = 'X-PulseRPC-Deadline';
+
+/** The Date.now() value at which the call being handled expires */
+const deadlines = new AsyncLocalStorage<number | undefined>();
+
+/**
+ * Runs fn with the deadline its request's headers carry. Header names are
+ * lowercase, as Node gives them. A missing or malformed header leaves the call
+ * without a deadline.
+ */
+export function runWithDeadline<T>(headers: Record<string, string | string[] | undefined>, fn: () => T): T {
+  const value = headers[DEADLINE_HEADER.toLowerCase()];
+  const budgetMs = typeof value === 'string' && /^-?\d+$/.test(value) ? Number(value) : undefined;
+  return deadlines.run(budgetMs === undefined ? undefined : Date.now() + budgetMs, fn);
+}
+
+/**
+ * Returns the milliseconds left before the deadline of the call being handled,
+ * zero once it has passed, or undefined if the call has no deadline
+ */
+export function remainingTimeMs(): number | undefined {
+  const deadline = deadlines.getStore();
+  if (deadline === undefined) {
+    return undefined;
+  }
+  return Math.max(0, deadline - Date.now());
+}
@@ -0,0 +1,84 @@
+/**
+ * Encryption of struct fields annotated [encrypted]
+ */
+
+import { TypeDef, StructMap, findStruct } from "./types";
+
+/**
+ * Encrypts and decrypts the values of struct fields annotated [encrypted],
+ * typically with a data key from a KMS. field names the field as
+ * "Struct.field" so a cipher can choose keys per field. plaintext is the JSON
+ * encoding of the field's value, and the ciphertext is sent as a JSON string.
+ * Both methods are synchronous because they run inside request handling, so
+ * a KMS backed cipher should cache its data keys.
+ */
+export interface FieldCipher {
+  encrypt(field: string, plaintext: string): string;
+  decrypt(field: string, ciphertext: string): string;
+}
+
+/**
+ * Returns a copy of a value of the given type in which every non-null
+ * [encrypted] struct field holds its ciphertext. The value itself is not changed.
+ */
+export function encryptFields(value: unknown, typeDef: TypeDef, allStructs: StructMap, cipher: FieldCipher | null | undefined): unknown {
+  return transformFields(value, typeDef, allStructs, (field, item) => {
+    if (!cipher) {
+      throw new Error(`No field cipher set to encrypt field ${field}`);
+    }
+    return cipher.encrypt(field, JSON.stringify(item));
+  });
+}
+
+/** Reverses encryptFields */
+export function decryptFields(value: unknown, typeDef: TypeDef, allStructs: StructMap, cipher: FieldCipher | null | undefined): unknown {
+  return transformFields(value, typeDef, allStructs, (field, item) => {
+    if (!cipher) {
+      throw new Error(`No field cipher set to decrypt field ${field}`);
+    }
+    if (typeof item !== "string") {
+      throw new Error(`Encrypted field ${field} must be a string, got ${typeof item}`);
+    }
+    return JSON.parse(cipher.decrypt(field, item));
+  });
+}
+
+/** Applies fn to the non-null [encrypted] fields of the structs in value */
+function transformFields(value: unknown, typeDef: TypeDef, allStructs: StructMap, fn: (field: string, item: unknown) => unknown): unknown {
+  if (value === null || value === undefined) {
+    return value;
+  }
+  if (typeDef.array && Array.isArray(value)) {
+    return value.map((item) => transformFields(item, typeDef.array!, allStructs, fn));
+  }
+  if (typeDef.mapValue && typeof value === "object") {
+    const copy: { [key: string]: unknown } = {};
+    for (const [key, item] of Object.entries(value)) {
+      copy[key] = transformFields(item, typeDef.mapValue, allStructs, fn);
+    }
+    return copy;
+  }
+  let structName = typeDef.userDefined;
+  if (!structName || !findStruct(structName, allStructs) || typeof value !== "object" || Array.isArray(value)) {
+    return value;
+  }
+  const copy: { [key: string]: unknown } = { ...(value as { [key: string]: unknown }) };
+  // Walk the struct and its parents, naming each field after the struct that declares it
+  while (structName) {
+    const structDef = findStruct(structName, allStructs);
+    if (!structDef) {
+      break;
+    }
+    for (const field of structDef.fields) {
+      const item = copy[field.name];
+      if (item === null || item === undefined) {
+        continue;
+      }
+      copy[field.name] = field.encrypted
+        ? fn(`${structName}.${field.name}`, item)
+        : transformFields(item, field.type, allStructs, fn);
+    }
+    structName = structDef.extends;
+  }
+  return copy;
+}
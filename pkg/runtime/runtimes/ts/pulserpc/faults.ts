@@ -0,0 +1,112 @@
+/**
+ * Fault injection for testing clients against a slow or unreliable server
+ */
+
+import * as fs from "fs";
+
+/** The faults injected into the calls of one method */
+export interface MethodFaults {
+  latencyMs?: number;
+  jitterMs?: number;
+  errorRate?: number;
+  errorCode?: number;
+  errorMessage?: string;
+  malformedRate?: number;
+}
+
+/** What happens to one call */
+export interface Fault {
+  delayMs: number;
+  /** Returned instead of calling the handler */
+  error?: { code: number; message: string };
+  /** The encoded response is cut short */
+  malformed: boolean;
+}
+
+const METHOD_KEYS = new Set(["latencyMs", "jitterMs", "errorRate", "errorCode", "errorMessage", "malformedRate"]);
+
+/**
+ * The faults a server injects into its calls, read from a JSON file such as:
+ *
+ *   {
+ *     "seed": 7,
+ *     "methods": {
+ *       "*": {"latencyMs": 20},
+ *       "Catalog.getProduct": {"latencyMs": 200, "jitterMs": 100, "errorRate": 0.1, "malformedRate": 0.05}
+ *     }
+ *   }
+ *
+ * Methods are keyed by the name the client sends, and "*" applies to methods
+ * without an entry of their own. A seed makes the injected faults repeatable.
+ */
+export class FaultConfig {
+  private random: () => number;
+
+  constructor(public methods: { [method: string]: MethodFaults }, seed: number = 0) {
+    this.random = seed ? mulberry32(seed) : Math.random;
+  }
+
+  /** Builds a FaultConfig, rejecting unknown keys, negative delays and rates outside 0 to 1 */
+  static fromObject(config: any): FaultConfig {
+    const unknown = Object.keys(config).filter((key) => key !== "seed" && key !== "methods");
+    if (unknown.length > 0) {
+      throw new Error(`unknown fault config keys: ${unknown.sort().join(", ")}`);
+    }
+    const methods: { [method: string]: MethodFaults } = config.methods || {};
+    for (const [method, faults] of Object.entries(methods)) {
+      const unknownFaults = Object.keys(faults).filter((key) => !METHOD_KEYS.has(key));
+      if (unknownFaults.length > 0) {
+        throw new Error(`${method}: unknown fault keys: ${unknownFaults.sort().join(", ")}`);
+      }
+      if ((faults.latencyMs || 0) < 0 || (faults.jitterMs || 0) < 0) {
+        throw new Error(`${method}: latencyMs and jitterMs must not be negative`);
+      }
+      for (const rate of [faults.errorRate || 0, faults.malformedRate || 0]) {
+        if (rate < 0 || rate > 1) {
+          throw new Error(`${method}: errorRate and malformedRate must be between 0 and 1`);
+        }
+      }
+    }
+    return new FaultConfig(methods, config.seed || 0);
+  }
+
+  /** Reads a FaultConfig from a JSON file */
+  static load(path: string): FaultConfig {
+    const config = JSON.parse(fs.readFileSync(path, "utf8"));
+    try {
+      return FaultConfig.fromObject(config);
+    } catch (e) {
+      throw new Error(`invalid fault config ${path}: ${(e as Error).message}`);
+    }
+  }
+
+  /** Picks the fault for one call of method */
+  draw(method: string): Fault {
+    const faults = this.methods[method] || this.methods["*"];
+    if (!faults) {
+      return { delayMs: 0, malformed: false };
+    }
+    const fault: Fault = { delayMs: faults.latencyMs || 0, malformed: false };
+    if (faults.jitterMs && faults.jitterMs > 0) {
+      fault.delayMs += Math.floor(this.random() * (faults.jitterMs + 1));
+    }
+    if (faults.errorRate && this.random() < faults.errorRate) {
+      fault.error = { code: faults.errorCode || -32603, message: faults.errorMessage || "Injected fault" };
+    } else if (faults.malformedRate && this.random() < faults.malformedRate) {
+      fault.malformed = true;
+    }
+    return fault;
+  }
+}
+
+/** A small seeded PRNG, since Math.random cannot be seeded */
+function mulberry32(seed: number): () => number {
+  let state = seed >>> 0;
+  return () => {
+    state = (state + 0x6d2b79f5) >>> 0;
+    let t = state;
+    t = Math.imul(t ^ (t >>> 15), t | 1);
+    t ^= t + Math.imul(t ^ (t >>> 7), t | 61);
+    return ((t ^ (t >>> 14)) >>> 0) / 4294967296;
+  };
+}
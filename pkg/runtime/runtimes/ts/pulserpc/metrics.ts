@@ -0,0 +1,53 @@
+/**
+ * Per-method call counts and handler latency for the admin endpoint
+ */
+
+/** A summary of the calls of one method */
+export interface MethodMetric {
+  calls: number;
+  /** Calls whose handler threw */
+  errors: number;
+  /** Mean and longest handler time in milliseconds */
+  meanMs: number;
+  maxMs: number;
+}
+
+/**
+ * Counts the calls of each method of a server and how long their handlers took.
+ * Only the methods it was created with are counted, so calls to unknown methods
+ * cannot grow it.
+ */
+export class MethodMetrics {
+  /** When the metrics started counting */
+  readonly startedAt = new Date();
+  private methods = new Map<string, { calls: number; errors: number; totalMs: number; maxMs: number }>();
+
+  constructor(methods: string[]) {
+    for (const method of methods) {
+      this.methods.set(method, { calls: 0, errors: 0, totalMs: 0, maxMs: 0 });
+    }
+  }
+
+  /** Counts one call of method ("Interface.method") whose handler took elapsedMs */
+  record(method: string, elapsedMs: number, failed: boolean): void {
+    const metric = this.methods.get(method);
+    if (!metric) {
+      return;
+    }
+    metric.calls++;
+    if (failed) {
+      metric.errors++;
+    }
+    metric.totalMs += elapsedMs;
+    metric.maxMs = Math.max(metric.maxMs, elapsedMs);
+  }
+
+  /** Returns the metrics of every method, keyed by method */
+  snapshot(): Record<string, MethodMetric> {
+    const snapshot: Record<string, MethodMetric> = {};
+    for (const [method, m] of this.methods) {
+      snapshot[method] = { calls: m.calls, errors: m.errors, meanMs: m.calls ? m.totalMs / m.calls : 0, maxMs: m.maxMs };
+    }
+    return snapshot;
+  }
+}
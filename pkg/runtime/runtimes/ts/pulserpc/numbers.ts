@@ -0,0 +1,107 @@
+/**
+ * The policy for int values written with a fraction or exponent, such as 2.0.
+ * A float accepts any number under either policy, and an int with a fractional
+ * part, such as 2.5, is always rejected. 'lenient', the default, takes 2.0 as 2;
+ * 'strict' rejects it. JSON.parse gives 2 for both, so strict servers parse the
+ * request a second time with JSON.parse source text access (Node 21 and later)
+ * to see how each number was written.
+ */
+
+import { TypeDef, StructMap, getStructFields } from './types';
+
+export type NumberPolicy = 'lenient' | 'strict';
+
+/** A number as written in the JSON text */
+class NumberLiteral {
+  constructor(readonly source: string) {}
+}
+
+/** The params of each request object, with their numbers as NumberLiterals */
+const requestLiterals = new WeakMap<object, any>();
+
+/** Reports whether JSON.parse gives revivers the source text of numbers */
+export function supportsNumberLiterals(): boolean {
+  let supported = false;
+  JSON.parse('1', (_key: string, value: any, context?: { source?: string }) => {
+    supported = context?.source === '1';
+    return value;
+  });
+  return supported;
+}
+
+/**
+ * Parses text, the JSON-RPC request or batch that data was parsed from, again
+ * keeping how each number was written, and records the params of each request
+ * in data for checkRequestIntLiterals
+ */
+export function attachRequestLiterals(data: any, text: string): void {
+  const literals = JSON.parse(text, (_key: string, value: any, context?: { source?: string }) =>
+    typeof value === 'number' && context?.source !== undefined ? new NumberLiteral(context.source) : value);
+  const requests = Array.isArray(data) ? data : [data];
+  const literalRequests = Array.isArray(literals) ? literals : [literals];
+  requests.forEach((request, i) => {
+    if (request && typeof request === 'object' && literalRequests[i] && typeof literalRequests[i] === 'object') {
+      requestLiterals.set(request, literalRequests[i].params);
+    }
+  });
+}
+
+/**
+ * Throws a TypeError naming the first param value of type int that is written
+ * with a fraction or exponent, if attachRequestLiterals recorded the params of
+ * request. Params may be sent by position or by name.
+ */
+export function checkRequestIntLiterals(request: any, expectedParams: Array<{ name: string; type: TypeDef }>, allStructs: StructMap): void {
+  if (!request || typeof request !== 'object' || !requestLiterals.has(request)) {
+    return;
+  }
+  const params = requestLiterals.get(request);
+  expectedParams.forEach((param, i) => {
+    const value = Array.isArray(params) ? params[i] : params && typeof params === 'object' ? params[param.name] : undefined;
+    try {
+      checkIntLiterals(value, param.type, allStructs);
+    } catch (e: any) {
+      throw new TypeError(`Parameter ${i} (${param.name}) validation failed: ${e.message}`);
+    }
+  });
+}
+
+/**
+ * Throws a TypeError if a value of type int in value, parsed by
+ * attachRequestLiterals, is written with a fraction or exponent
+ */
+export function checkIntLiterals(value: any, typeDef: TypeDef, allStructs: StructMap): void {
+  if (value instanceof NumberLiteral) {
+    if (typeDef.builtIn === 'int' && /[.eE]/.test(value.source)) {
+      throw new TypeError(`Expected int, got ${value.source}`);
+    }
+  } else if (Array.isArray(value)) {
+    if (typeDef.array) {
+      value.forEach((item, i) => {
+        try {
+          checkIntLiterals(item, typeDef.array!, allStructs);
+        } catch (e: any) {
+          throw new TypeError(`Array element at index ${i} validation failed: ${e.message}`);
+        }
+      });
+    }
+  } else if (value && typeof value === 'object') {
+    if (typeDef.mapValue) {
+      for (const [key, item] of Object.entries(value)) {
+        try {
+          checkIntLiterals(item, typeDef.mapValue, allStructs);
+        } catch (e: any) {
+          throw new TypeError(`Map value for key '${key}' validation failed: ${e.message}`);
+        }
+      }
+    } else if (typeDef.userDefined) {
+      for (const field of getStructFields(typeDef.userDefined, allStructs)) {
+        try {
+          checkIntLiterals(value[field.name], field.type, allStructs);
+        } catch (e: any) {
+          throw new TypeError(`Field '${field.name}' in struct ${typeDef.userDefined} validation failed: ${e.message}`);
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,55 @@
+/**
+ * Tests for canonical JSON and request hashes
+ */
+
+import { strict as assert } from "assert";
+import { canonicalJson, requestHash } from "../canonical";
+
+// The same vectors are checked by every other runtime's tests, so every runtime
+// hashes a call the same way, byte for byte
+const requestHashVectors: [string, unknown, string, string][] = [
+  [
+    "Catalog.get",
+    ["p-1", 2],
+    '{"method":"Catalog.get","params":["p-1",2]}',
+    "c83d6e2a3e0908ddb5e94fe05cffd450756e92caab52d017ad51be7b97fcb715",
+  ],
+  [
+    "Catalog.search",
+    [{ query: 'café "x"\n', limit: 10, tags: ["a", "b"], price: { max: 99.5, min: 0.1 } }, null],
+    '{"method":"Catalog.search","params":[{"limit":10,"price":{"max":99.5,"min":0.1},"query":"café \\"x\\"\\n","tags":["a","b"]},null]}',
+    "bac87c8eb687a2e88fb49b08c30f0668d849c06613310acd110c66d42acc56ae",
+  ],
+  [
+    "Catalog.bulk",
+    { ids: [1e21, 1e-7, -0, 2 ** 60], "é": true, a: false, "\u{1F600}": 1, "｡": 2 },
+    '{"method":"Catalog.bulk","params":{"a":false,"ids":[1e+21,1e-7,0,1152921504606847000],"é":true,"\u{1F600}":1,"｡":2}}',
+    "53bd8c11499fccf79fedaff23d160964bba118c23421f68f3167c40eb2a7750b",
+  ],
+  // 2^53+1 is not a double: canonical JSON writes the nearest one, 2^53
+  [
+    "Catalog.edges",
+    [1e21, 1e-7, -0, 2 ** 53 + 1],
+    '{"method":"Catalog.edges","params":[1e+21,1e-7,0,9007199254740992]}',
+    "379f1ac102b547987989aaba1ce4f935c28a1d5b880068e36ffd2b017f2f98e5",
+  ],
+];
+
+function testRequestHashVectors() {
+  for (const [method, params, canonical, hash] of requestHashVectors) {
+    assert.strictEqual(canonicalJson({ method, params }), canonical);
+    assert.strictEqual(requestHash(method, params), hash);
+  }
+  console.log("✓ testRequestHashVectors");
+}
+
+function testCanonicalJsonSkipsUndefined() {
+  assert.strictEqual(canonicalJson({ b: undefined, a: [undefined] }), '{"a":[null]}');
+  assert.throws(() => canonicalJson(NaN), /cannot canonicalize NaN/);
+  console.log("✓ testCanonicalJsonSkipsUndefined");
+}
+
+// Run tests
+testRequestHashVectors();
+testCanonicalJsonSkipsUndefined();
+console.log("\nAll canonical JSON tests passed!");
@@ -0,0 +1,36 @@
+/**
+ * Tests for request deadlines
+ */
+
+import { strict as assert } from "assert";
+import { remainingTimeMs, runWithDeadline } from "../deadline";
+
+function testRunWithDeadline() {
+  assert.equal(remainingTimeMs(), undefined);
+  runWithDeadline({ "x-pulserpc-deadline": "5000" }, () => {
+    const remaining = remainingTimeMs() as number;
+    assert(remaining > 4000 && remaining <= 5000);
+  });
+  // A budget that has run out leaves no time
+  runWithDeadline({ "x-pulserpc-deadline": "-10" }, () => {
+    assert.equal(remainingTimeMs(), 0);
+  });
+  // A missing or malformed header leaves the call without a deadline
+  runWithDeadline({}, () => assert.equal(remainingTimeMs(), undefined));
+  runWithDeadline({ "x-pulserpc-deadline": "soon" }, () => assert.equal(remainingTimeMs(), undefined));
+  assert.equal(remainingTimeMs(), undefined);
+  console.log("✓ testRunWithDeadline");
+}
+
+async function testDeadlineFollowsAsyncWork() {
+  await runWithDeadline({ "x-pulserpc-deadline": "5000" }, async () => {
+    await new Promise((resolve) => setTimeout(resolve, 10));
+    const remaining = remainingTimeMs() as number;
+    assert(remaining > 4000 && remaining < 5000);
+  });
+  console.log("✓ testDeadlineFollowsAsyncWork");
+}
+
+// Run tests
+testRunWithDeadline();
+testDeadlineFollowsAsyncWork().then(() => console.log("\nAll deadline tests passed!"));
@@ -0,0 +1,81 @@
+/**
+ * Tests for [encrypted] field encryption
+ */
+
+import { strict as assert } from "assert";
+import { encryptFields, decryptFields, FieldCipher } from "../encryption";
+import { StructMap } from "../types";
+
+const base64Cipher: FieldCipher = {
+  encrypt(field: string, plaintext: string): string {
+    return field + ":" + Buffer.from(plaintext).toString("base64");
+  },
+  decrypt(field: string, ciphertext: string): string {
+    assert(ciphertext.startsWith(field + ":"));
+    return Buffer.from(ciphertext.slice(field.length + 1), "base64").toString();
+  },
+};
+
+const allStructs: StructMap = {
+  Base: {
+    fields: [{ name: "ssn", type: { builtIn: "string" }, encrypted: true }],
+  },
+  Patient: {
+    extends: "Base",
+    fields: [
+      { name: "name", type: { builtIn: "string" } },
+      { name: "age", type: { builtIn: "int" }, encrypted: true },
+      { name: "notes", type: { array: { builtIn: "string" } }, optional: true, encrypted: true },
+      { name: "contacts", type: { array: { userDefined: "Contact" } } },
+    ],
+  },
+  Contact: {
+    fields: [{ name: "email", type: { builtIn: "string" }, encrypted: true }],
+  },
+};
+
+function b64(text: string): string {
+  return Buffer.from(text).toString("base64");
+}
+
+function testEncryptFieldsRoundTrip() {
+  const original = { ssn: "123", name: "Ann", age: 40, notes: null, contacts: [{ email: "a@example.com" }] };
+  const encrypted = encryptFields(original, { userDefined: "Patient" }, allStructs, base64Cipher);
+  assert.deepStrictEqual(encrypted, {
+    ssn: "Base.ssn:" + b64('"123"'),
+    name: "Ann",
+    age: "Patient.age:" + b64("40"),
+    notes: null,
+    contacts: [{ email: "Contact.email:" + b64('"a@example.com"') }],
+  });
+  assert.strictEqual(original.ssn, "123");
+  assert.strictEqual(original.contacts[0].email, "a@example.com");
+
+  const decrypted = decryptFields([encrypted], { array: { userDefined: "Patient" } }, allStructs, base64Cipher);
+  assert.deepStrictEqual(decrypted, [original]);
+  console.log("✓ testEncryptFieldsRoundTrip");
+}
+
+function testEncryptFieldsRequiresCipher() {
+  assert.throws(
+    () => encryptFields({ ssn: "123" }, { userDefined: "Base" }, allStructs, null),
+    /No field cipher set to encrypt field Base.ssn/
+  );
+  // Values without encrypted fields need no cipher
+  assert.strictEqual(encryptFields("x", { builtIn: "string" }, allStructs, null), "x");
+  console.log("✓ testEncryptFieldsRequiresCipher");
+}
+
+function testDecryptFieldsRejectsPlaintext() {
+  assert.throws(
+    () => decryptFields({ age: 40 }, { userDefined: "Patient" }, allStructs, base64Cipher),
+    /Encrypted field Patient.age must be a string/
+  );
+  console.log("✓ testDecryptFieldsRejectsPlaintext");
+}
+
+// Run tests
+testEncryptFieldsRoundTrip();
+testEncryptFieldsRequiresCipher();
+testDecryptFieldsRejectsPlaintext();
+console.log("\nAll encryption tests passed!");
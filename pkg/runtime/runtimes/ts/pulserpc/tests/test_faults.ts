@@ -0,0 +1,64 @@
+/**
+ * Tests for fault injection
+ */
+
+import { strict as assert } from "assert";
+import * as fs from "fs";
+import * as os from "os";
+import * as path from "path";
+import { FaultConfig } from "../faults";
+
+function testFaultConfigDraw() {
+  const file = path.join(fs.mkdtempSync(path.join(os.tmpdir(), "faults-")), "faults.json");
+  fs.writeFileSync(file, JSON.stringify({
+    seed: 7,
+    methods: {
+      "*": { latencyMs: 20, jitterMs: 10 },
+      "Catalog.fail": { errorRate: 1, errorCode: -32050, errorMessage: "Catalog down" },
+      "Catalog.garble": { malformedRate: 1 },
+    },
+  }));
+  const config = FaultConfig.load(file);
+
+  let fault = config.draw("Catalog.list");
+  assert(fault.delayMs >= 20 && fault.delayMs <= 30);
+  assert.strictEqual(fault.error, undefined);
+  assert.strictEqual(fault.malformed, false);
+
+  // A method's own entry replaces "*"
+  fault = config.draw("Catalog.fail");
+  assert.strictEqual(fault.delayMs, 0);
+  assert.deepStrictEqual(fault.error, { code: -32050, message: "Catalog down" });
+
+  assert.strictEqual(config.draw("Catalog.garble").malformed, true);
+  console.log("✓ testFaultConfigDraw");
+}
+
+function testFaultConfigDefaultsAndSeed() {
+  const draws = () => {
+    const config = FaultConfig.fromObject({ seed: 42, methods: { "*": { errorRate: 0.5, jitterMs: 100 } } });
+    return Array.from({ length: 20 }, () => config.draw("Any.method"));
+  };
+  const first = draws();
+  assert.deepStrictEqual(first, draws());
+  const errors = first.filter((fault) => fault.error !== undefined);
+  assert(errors.length > 0 && errors.length < first.length);
+  for (const fault of errors) {
+    assert.deepStrictEqual(fault.error, { code: -32603, message: "Injected fault" });
+  }
+  console.log("✓ testFaultConfigDefaultsAndSeed");
+}
+
+function testFaultConfigInvalid() {
+  assert.throws(() => FaultConfig.fromObject({ methods: { "*": { errorRate: 1.5 } } }), /errorRate and malformedRate must be between 0 and 1/);
+  assert.throws(() => FaultConfig.fromObject({ methods: { "*": { latencyMs: -1 } } }), /latencyMs and jitterMs must not be negative/);
+  assert.throws(() => FaultConfig.fromObject({ methods: { "*": { latency: 10 } } }), /unknown fault keys: latency/);
+  assert.throws(() => FaultConfig.fromObject({ method: {} }), /unknown fault config keys: method/);
+  console.log("✓ testFaultConfigInvalid");
+}
+
+// Run tests
+testFaultConfigDraw();
+testFaultConfigDefaultsAndSeed();
+testFaultConfigInvalid();
+console.log("\nAll fault injection tests passed!");
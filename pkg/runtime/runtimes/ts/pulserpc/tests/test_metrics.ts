@@ -0,0 +1,25 @@
+/**
+ * Tests for per-method metrics
+ */
+
+import { strict as assert } from "assert";
+import { MethodMetrics } from "../metrics";
+
+function testMethodMetrics() {
+  const metrics = new MethodMetrics(["Catalog.get", "Catalog.list"]);
+  metrics.record("Catalog.get", 10, false);
+  metrics.record("Catalog.get", 30, true);
+  // Methods it was not created with are not counted
+  metrics.record("Catalog.unknown", 1, false);
+
+  assert.deepStrictEqual(metrics.snapshot(), {
+    "Catalog.get": { calls: 2, errors: 1, meanMs: 20, maxMs: 30 },
+    "Catalog.list": { calls: 0, errors: 0, meanMs: 0, maxMs: 0 },
+  });
+  assert(metrics.startedAt.getTime() <= Date.now());
+  console.log("✓ testMethodMetrics");
+}
+
+// Run tests
+testMethodMetrics();
+console.log("\nAll metrics tests passed!");
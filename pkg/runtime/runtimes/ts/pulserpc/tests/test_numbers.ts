@@ -0,0 +1,42 @@
+/**
+ * Tests for the int/float number policy
+ */
+
+import { strict as assert } from "assert";
+import { attachRequestLiterals, checkRequestIntLiterals, supportsNumberLiterals } from "../numbers";
+
+const expectedParams = [{ name: "count", type: { builtIn: "int" } }];
+
+function check(text: string): void {
+  const data = JSON.parse(text);
+  attachRequestLiterals(data, text);
+  checkRequestIntLiterals(data, expectedParams, {});
+}
+
+function testCheckRequestIntLiterals() {
+  if (!supportsNumberLiterals()) {
+    console.log("- testCheckRequestIntLiterals skipped: JSON.parse has no source text access");
+    return;
+  }
+  check('{"jsonrpc":"2.0","method":"m","params":[3],"id":1}');
+  assert.throws(() => check('{"jsonrpc":"2.0","method":"m","params":[3.0],"id":1}'), /Parameter 0 \(count\)/);
+  assert.throws(() => check('{"jsonrpc":"2.0","method":"m","params":{"count":3e0},"id":1}'), /Expected int/);
+  // Each member of a batch is checked on its own
+  const text = '[{"jsonrpc":"2.0","method":"m","params":[1],"id":1},{"jsonrpc":"2.0","method":"m","params":[1.0],"id":2}]';
+  const batch = JSON.parse(text);
+  attachRequestLiterals(batch, text);
+  checkRequestIntLiterals(batch[0], expectedParams, {});
+  assert.throws(() => checkRequestIntLiterals(batch[1], expectedParams, {}), /Expected int, got 1.0/);
+  console.log("✓ testCheckRequestIntLiterals");
+}
+
+function testUncheckedRequest() {
+  // Requests without attached literals are not checked
+  checkRequestIntLiterals(JSON.parse('{"params":[3.0]}'), expectedParams, {});
+  console.log("✓ testUncheckedRequest");
+}
+
+// Run tests
+testCheckRequestIntLiterals();
+testUncheckedRequest();
+console.log("\nAll number tests passed!");
@@ -7,6 +7,9 @@ import {
   findStruct,
   findEnum,
   getStructFields,
+  cloneStruct,
+  redactStruct,
+  REDACTED,
   StructMap,
   EnumMap,
 } from "../types";
@@ -85,10 +88,57 @@ function testGetStructFieldsOverrideParent() {
   console.log("✓ testGetStructFieldsOverrideParent");
 }
 
+function testCloneStruct() {
+  const original = {
+    id: "u1",
+    tags: ["a", "b"],
+    address: { city: "Oslo" },
+    scores: { math: [1, 2] } as { [key: string]: number[] },
+  };
+  const copy = cloneStruct(original);
+  assert.deepStrictEqual(copy, original);
+  copy.tags.push("c");
+  copy.address.city = "Bergen";
+  copy.scores.math[0] = 9;
+  assert.deepStrictEqual(original.tags, ["a", "b"]);
+  assert.deepStrictEqual(original.address, { city: "Oslo" });
+  assert.deepStrictEqual(original.scores, { math: [1, 2] });
+  assert.strictEqual(cloneStruct(null), null);
+  console.log("✓ testCloneStruct");
+}
+
 // Run tests
+function testRedactStruct() {
+  const allStructs: StructMap = {
+    Credentials: {
+      fields: [{ name: "password", type: { builtIn: "string" }, sensitive: true }],
+    },
+    Login: {
+      extends: "Credentials",
+      fields: [
+        { name: "username", type: { builtIn: "string" } },
+        { name: "token", type: { builtIn: "string" }, optional: true, sensitive: true },
+        { name: "backups", type: { array: { userDefined: "Credentials" } } },
+      ],
+    },
+  };
+  const original = { username: "ann", password: "secret", backups: [{ password: "old" }] };
+  const redacted = redactStruct("Login", original, allStructs);
+  assert.deepStrictEqual(redacted, {
+    username: "ann",
+    password: REDACTED,
+    backups: [{ password: REDACTED }],
+  });
+  assert.strictEqual(original.password, "secret");
+  assert.strictEqual(original.backups[0].password, "old");
+  console.log("✓ testRedactStruct");
+}
+
 testFindStruct();
 testFindEnum();
 testGetStructFieldsSimple();
 testGetStructFieldsWithExtends();
 testGetStructFieldsOverrideParent();
+testCloneStruct();
+testRedactStruct();
 console.log("\nAll type tests passed!");
@@ -13,6 +13,8 @@ export interface FieldDef {
   name: string;
   type: TypeDef;
   optional?: boolean;
+  sensitive?: boolean;
+  encrypted?: boolean;
 }
 
 export interface StructDef {
@@ -66,3 +68,68 @@ export function getStructFields(structName: string, allStructs: StructMap): Fiel
 
   return fields;
 }
+
+/**
+ * Returns a deep copy of a struct, array or map value. Structs are plain
+ * objects, so nested objects and arrays are copied and scalars are shared.
+ */
+export function cloneStruct<T>(value: T): T {
+  if (Array.isArray(value)) {
+    return value.map((item) => cloneStruct(item)) as T;
+  }
+  if (value !== null && typeof value === "object") {
+    const copy: { [key: string]: unknown } = {};
+    for (const [key, item] of Object.entries(value)) {
+      copy[key] = cloneStruct(item);
+    }
+    return copy as T;
+  }
+  return value;
+}
+
+/** Replaces the value of a [sensitive] field in redacted output */
+export const REDACTED = "***";
+
+/**
+ * Returns a copy of a value of the given type in which every [sensitive]
+ * struct field holds REDACTED, for logging. Absent and null fields are kept
+ * as they are, and the value itself is not changed.
+ */
+export function redactValue(value: unknown, typeDef: TypeDef, allStructs: StructMap): unknown {
+  if (value === null || value === undefined) {
+    return value;
+  }
+  if (typeDef.array && Array.isArray(value)) {
+    return value.map((item) => redactValue(item, typeDef.array!, allStructs));
+  }
+  if (typeDef.mapValue && typeof value === "object") {
+    const copy: { [key: string]: unknown } = {};
+    for (const [key, item] of Object.entries(value)) {
+      copy[key] = redactValue(item, typeDef.mapValue, allStructs);
+    }
+    return copy;
+  }
+  if (typeDef.userDefined && findStruct(typeDef.userDefined, allStructs)) {
+    return redactStruct(typeDef.userDefined, value, allStructs);
+  }
+  return value;
+}
+
+/**
+ * Returns a copy of a struct value with its [sensitive] fields, including those
+ * of nested structs, replaced by REDACTED
+ */
+export function redactStruct(structName: string, value: unknown, allStructs: StructMap): unknown {
+  if (value === null || typeof value !== "object" || Array.isArray(value)) {
+    return value;
+  }
+  const copy: { [key: string]: unknown } = { ...(value as { [key: string]: unknown }) };
+  for (const field of getStructFields(structName, allStructs)) {
+    const item = copy[field.name];
+    if (item === null || item === undefined) {
+      continue;
+    }
+    copy[field.name] = field.sensitive ? REDACTED : redactValue(item, field.type, allStructs);
+  }
+  return copy;
+}
@@ -0,0 +1,245 @@
+package sqlimport
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	createTableRegex = regexp.MustCompile(`(?is)^create\s+(?:(?:global\s+|local\s+)?(?:temporary|temp)\s+|unlogged\s+)?table\s+(?:if\s+not\s+exists\s+)?`)
+	commentRegex     = regexp.MustCompile(`(?i)\bcomment\s+'((?:[^']|'')*)'`)
+	primaryKeyRegex  = regexp.MustCompile(`(?i)^primary\s+key\s*\(([^)]*)\)`)
+	constraintWords  = []string{"constraint", "primary", "unique", "foreign", "check", "index", "key", "fulltext", "spatial", "exclude", "like"}
+	columnEndWords   = []string{"not", "null", "default", "primary", "unique", "references", "check", "constraint",
+		"collate", "comment", "auto_increment", "autoincrement", "generated", "identity", "on", "character", "charset"}
+)
+
+// ParseDDL extracts the tables from CREATE TABLE statements in SQL DDL. Other
+// statements are ignored. A column is nullable unless it is NOT NULL or part of
+// the primary key.
+func ParseDDL(ddl string) ([]Table, error) {
+	var tables []Table
+	for _, stmt := range splitStatements(stripComments(ddl)) {
+		loc := createTableRegex.FindStringIndex(stmt)
+		if loc == nil {
+			continue
+		}
+		table, err := parseCreateTable(stmt[loc[1]:])
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// parseCreateTable parses the part of a CREATE TABLE statement after TABLE
+func parseCreateTable(rest string) (Table, error) {
+	open := strings.Index(rest, "(")
+	if open < 0 {
+		return Table{}, fmt.Errorf("CREATE TABLE %s: missing column list", strings.TrimSpace(rest))
+	}
+	name := unquoteName(strings.TrimSpace(rest[:open]))
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	table := Table{Name: name}
+
+	body, ok := matchingParen(rest[open:])
+	if !ok {
+		return Table{}, fmt.Errorf("CREATE TABLE %s: unbalanced parentheses", name)
+	}
+
+	primaryKey := make(map[string]bool)
+	for _, def := range splitTopLevel(body, ',') {
+		def = strings.TrimSpace(def)
+		if def == "" {
+			continue
+		}
+		if m := primaryKeyRegex.FindStringSubmatch(def); m != nil {
+			for _, col := range strings.Split(m[1], ",") {
+				primaryKey[unquoteName(strings.TrimSpace(col))] = true
+			}
+			continue
+		}
+		if isConstraint(def) {
+			continue
+		}
+		column, err := parseColumn(def)
+		if err != nil {
+			return Table{}, fmt.Errorf("CREATE TABLE %s: %w", name, err)
+		}
+		table.Columns = append(table.Columns, column)
+	}
+	for i := range table.Columns {
+		if primaryKey[table.Columns[i].Name] {
+			table.Columns[i].Nullable = false
+		}
+	}
+	return table, nil
+}
+
+// parseColumn parses a column definition such as `price DECIMAL(10, 2) NOT NULL`
+func parseColumn(def string) (Column, error) {
+	name, rest := splitName(def)
+	if name == "" || rest == "" {
+		return Column{}, fmt.Errorf("invalid column definition %q", def)
+	}
+
+	words := strings.Fields(rest)
+	typeEnd := len(words)
+	for i, w := range words {
+		if i > 0 && containsWord(columnEndWords, w) {
+			typeEnd = i
+			break
+		}
+	}
+	column := Column{
+		Name: name,
+		Type: strings.Join(words[:typeEnd], " "),
+	}
+
+	constraints := " " + strings.ToLower(strings.Join(words[typeEnd:], " ")) + " "
+	column.Nullable = !strings.Contains(constraints, " not null ") && !strings.Contains(constraints, " primary key ")
+	if m := commentRegex.FindStringSubmatch(rest); m != nil {
+		column.Comment = strings.ReplaceAll(m[1], "''", "'")
+	}
+	return column, nil
+}
+
+// splitName splits a leading, possibly quoted, identifier from the rest of def
+func splitName(def string) (string, string) {
+	if def == "" {
+		return "", ""
+	}
+	if closing, ok := map[byte]byte{'"': '"', '`': '`', '[': ']'}[def[0]]; ok {
+		end := strings.IndexByte(def[1:], closing)
+		if end < 0 {
+			return "", ""
+		}
+		return def[1 : end+1], strings.TrimSpace(def[end+2:])
+	}
+	fields := strings.SplitN(def, " ", 2)
+	if len(fields) < 2 {
+		return fields[0], ""
+	}
+	return fields[0], strings.TrimSpace(fields[1])
+}
+
+func isConstraint(def string) bool {
+	first := strings.Fields(def)[0]
+	return containsWord(constraintWords, first)
+}
+
+func containsWord(words []string, w string) bool {
+	w = strings.ToLower(w)
+	for _, candidate := range words {
+		if w == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// unquoteName removes identifier quoting from each part of a possibly qualified name
+func unquoteName(name string) string {
+	return strings.NewReplacer(`"`, "", "`", "", "[", "", "]", "").Replace(name)
+}
+
+// matchingParen returns the text inside the parenthesis that s starts with
+func matchingParen(s string) (string, bool) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return s[1:i], true
+			}
+		}
+	}
+	return "", false
+}
+
+// splitTopLevel splits s on sep outside of parentheses and quotes
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// splitStatements splits DDL into statements on semicolons outside of quotes,
+// collapsing whitespace in each
+func splitStatements(ddl string) []string {
+	var stmts []string
+	for _, stmt := range splitTopLevel(ddl, ';') {
+		if stmt = strings.Join(strings.Fields(stmt), " "); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+	}
+	return stmts
+}
+
+// stripComments removes -- line comments and /* */ block comments outside of quotes
+func stripComments(ddl string) string {
+	var sb strings.Builder
+	var quote byte
+	for i := 0; i < len(ddl); i++ {
+		c := ddl[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			sb.WriteByte(c)
+			continue
+		}
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '-' && i+1 < len(ddl) && ddl[i+1] == '-':
+			for i < len(ddl) && ddl[i] != '\n' {
+				i++
+			}
+			c = '\n'
+		case c == '/' && i+1 < len(ddl) && ddl[i+1] == '*':
+			end := strings.Index(ddl[i+2:], "*/")
+			if end < 0 {
+				return sb.String()
+			}
+			i += end + 3
+			c = ' '
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
@@ -0,0 +1,40 @@
+package sqlimport
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Introspect reads the tables of a database schema from information_schema, which
+// Postgres, MySQL, MariaDB and SQL Server all provide. db must be opened with the
+// caller's driver. placeholder is the driver's bind parameter for the schema name:
+// "$1" for Postgres, "?" for MySQL, "@p1" for SQL Server.
+func Introspect(ctx context.Context, db *sql.DB, schema, placeholder string) ([]Table, error) {
+	query := `SELECT table_name, column_name, data_type, is_nullable
+		FROM information_schema.columns
+		WHERE table_schema = ` + placeholder + `
+		ORDER BY table_name, ordinal_position`
+	rows, err := db.QueryContext(ctx, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query information_schema.columns: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []Table
+	for rows.Next() {
+		var tableName, columnName, dataType, isNullable string
+		if err := rows.Scan(&tableName, &columnName, &dataType, &isNullable); err != nil {
+			return nil, fmt.Errorf("failed to read information_schema.columns: %w", err)
+		}
+		if len(tables) == 0 || tables[len(tables)-1].Name != tableName {
+			tables = append(tables, Table{Name: tableName})
+		}
+		t := &tables[len(tables)-1]
+		t.Columns = append(t.Columns, Column{Name: columnName, Type: dataType, Nullable: isNullable == "YES"})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read information_schema.columns: %w", err)
+	}
+	return tables, nil
+}
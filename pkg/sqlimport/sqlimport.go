@@ -0,0 +1,137 @@
+// Package sqlimport bootstraps IDL structs from a relational database schema.
+//
+// Tables are read from SQL DDL (CREATE TABLE statements) or from a live database's
+// information_schema, and each table becomes a struct with one field per column.
+// Nullable columns become [optional] fields.
+package sqlimport
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/coopernurse/pulserpc/pkg/idl"
+	"github.com/coopernurse/pulserpc/pkg/naming"
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+// Table is a database table and its columns in declaration order
+type Table struct {
+	Name    string
+	Columns []Column
+}
+
+// Column is a table column
+type Column struct {
+	Name string
+	// Type is the SQL type as declared, e.g. "varchar(255)" or "text[]"
+	Type     string
+	Nullable bool
+	Comment  string
+}
+
+// Builder returns an IDL builder with a struct for each table
+func Builder(namespace string, tables []Table) *idl.Builder {
+	b := idl.New(namespace)
+	for _, t := range tables {
+		s := b.Struct(StructName(t.Name))
+		for _, c := range t.Columns {
+			var opts []idl.Option
+			if c.Nullable {
+				opts = append(opts, idl.Optional())
+			}
+			if c.Comment != "" {
+				opts = append(opts, idl.Doc(c.Comment))
+			}
+			s.Field(FieldName(c.Name), MapType(c.Type), opts...)
+		}
+	}
+	return b
+}
+
+// MapType maps a SQL column type to an IDL type. Integer types map to int,
+// decimal and floating point types to float, boolean types to bool, and Postgres
+// arrays to IDL arrays. Everything else, including dates, UUIDs and JSON, is
+// carried as a string.
+func MapType(sqlType string) *parser.Type {
+	t := strings.ToLower(strings.TrimSpace(sqlType))
+	if strings.HasSuffix(t, "[]") {
+		return idl.Array(MapType(strings.TrimSuffix(t, "[]")))
+	}
+	if strings.HasPrefix(t, "_") {
+		// information_schema reports Postgres array element types as _int4, _text, ...
+		return idl.Array(MapType(t[1:]))
+	}
+	if t == "tinyint(1)" {
+		// MySQL's BOOLEAN
+		return idl.Bool()
+	}
+
+	base := t
+	if i := strings.IndexAny(base, "( "); i >= 0 {
+		base = base[:i]
+	}
+	switch base {
+	case "int", "integer", "int2", "int4", "int8", "smallint", "bigint", "tinyint", "mediumint",
+		"serial", "smallserial", "bigserial", "serial4", "serial8":
+		return idl.Int()
+	case "real", "float", "float4", "float8", "double", "numeric", "decimal", "money":
+		return idl.Float()
+	case "bool", "boolean", "bit":
+		return idl.Bool()
+	case "array":
+		// information_schema.columns.data_type for a Postgres array, without the element type
+		return idl.Array(idl.String())
+	}
+	return idl.String()
+}
+
+var nonIdentRegex = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// StructName converts a table name such as "order_items" to a struct name such
+// as "OrderItem". Plural table names are singularized with simple English rules.
+func StructName(table string) string {
+	return ensureLetter(singular(naming.SnakeToPascal(nonIdentRegex.ReplaceAllString(table, "_"))), "T")
+}
+
+// FieldName converts a column name to a valid IDL field name, keeping its case
+func FieldName(column string) string {
+	return ensureLetter(strings.Trim(nonIdentRegex.ReplaceAllString(column, "_"), "_"), "c")
+}
+
+func singular(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss") && !strings.HasSuffix(lower, "us") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+	return name
+}
+
+// ensureLetter prefixes name if it does not start with a letter, since IDL
+// identifiers must
+func ensureLetter(name, prefix string) string {
+	if name == "" {
+		return prefix
+	}
+	if c := name[0]; (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+		return name
+	}
+	return prefix + name
+}
+
+// Import parses DDL and returns the IDL text for its tables
+func Import(namespace, ddl string) (string, error) {
+	tables, err := ParseDDL(ddl)
+	if err != nil {
+		return "", err
+	}
+	if len(tables) == 0 {
+		return "", fmt.Errorf("no CREATE TABLE statements found")
+	}
+	return Builder(namespace, tables).Text()
+}
@@ -0,0 +1,134 @@
+package sqlimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/coopernurse/pulserpc/pkg/parser"
+)
+
+const testDDL = `
+-- accounts
+CREATE TABLE IF NOT EXISTS public."user_accounts" (
+  id BIGSERIAL PRIMARY KEY,
+  email VARCHAR(255) NOT NULL UNIQUE,
+  display_name text,
+  balance DECIMAL(10, 2) DEFAULT 0.00 NOT NULL,
+  tags text[],
+  is_admin BOOLEAN NOT NULL DEFAULT false, /* flag; not a statement end */
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT balance_positive CHECK (balance >= 0)
+);
+
+CREATE INDEX idx_email ON user_accounts(email);
+
+CREATE TABLE ` + "`order_items`" + ` (
+  ` + "`order_id`" + ` int NOT NULL,
+  ` + "`line`" + ` int NOT NULL,
+  ` + "`note`" + ` varchar(100) COMMENT 'Customer''s note; optional',
+  PRIMARY KEY (` + "`order_id`, `line`" + `),
+  FOREIGN KEY (order_id) REFERENCES orders(id)
+) ENGINE=InnoDB;
+`
+
+func TestParseDDL(t *testing.T) {
+	tables, err := ParseDDL(testDDL)
+	if err != nil {
+		t.Fatalf("ParseDDL failed: %v", err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("expected 2 tables, got %d: %+v", len(tables), tables)
+	}
+
+	accounts := tables[0]
+	if accounts.Name != "user_accounts" || len(accounts.Columns) != 7 {
+		t.Fatalf("unexpected table: %+v", accounts)
+	}
+	want := []Column{
+		{Name: "id", Type: "BIGSERIAL"},
+		{Name: "email", Type: "VARCHAR(255)"},
+		{Name: "display_name", Type: "text", Nullable: true},
+		{Name: "balance", Type: "DECIMAL(10, 2)"},
+		{Name: "tags", Type: "text[]", Nullable: true},
+		{Name: "is_admin", Type: "BOOLEAN"},
+		{Name: "created_at", Type: "timestamp with time zone"},
+	}
+	for i, c := range want {
+		if accounts.Columns[i] != c {
+			t.Errorf("column %d: expected %+v, got %+v", i, c, accounts.Columns[i])
+		}
+	}
+
+	items := tables[1]
+	if items.Name != "order_items" || len(items.Columns) != 3 {
+		t.Fatalf("unexpected table: %+v", items)
+	}
+	if items.Columns[0].Nullable || items.Columns[1].Nullable {
+		t.Errorf("expected primary key columns to be required: %+v", items.Columns)
+	}
+	if note := items.Columns[2]; !note.Nullable || note.Comment != "Customer's note; optional" {
+		t.Errorf("unexpected note column: %+v", note)
+	}
+}
+
+func TestImport(t *testing.T) {
+	text, err := Import("shop", testDDL)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	for _, want := range []string{
+		"namespace shop\n",
+		"struct UserAccount {\n  id int\n  email string\n  display_name string [optional]\n  balance float\n  tags []string [optional]\n  is_admin bool\n  created_at string\n}",
+		"struct OrderItem {\n",
+		"  // Customer's note; optional\n  note string [optional]\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, text)
+		}
+	}
+	if _, err := parser.ParseIDL("shop.pulse", text); err != nil {
+		t.Errorf("generated IDL does not parse: %v\n%s", err, text)
+	}
+
+	if _, err := Import("shop", "CREATE INDEX idx ON t(c);"); err == nil {
+		t.Error("expected an error for DDL without tables")
+	}
+}
+
+func TestMapType(t *testing.T) {
+	cases := map[string]string{
+		"INTEGER":          "int",
+		"bigint unsigned":  "int",
+		"tinyint(1)":       "bool",
+		"tinyint(4)":       "int",
+		"double precision": "float",
+		"NUMERIC(12,4)":    "float",
+		"bit":              "bool",
+		"uuid":             "string",
+		"jsonb":            "string",
+		"int4[]":           "[]int",
+		"_text":            "[]string",
+		"ARRAY":            "[]string",
+	}
+	for sqlType, want := range cases {
+		if got := MapType(sqlType).String(); got != want {
+			t.Errorf("MapType(%q) = %s, want %s", sqlType, got, want)
+		}
+	}
+}
+
+func TestStructName(t *testing.T) {
+	cases := map[string]string{
+		"users":       "User",
+		"order_items": "OrderItem",
+		"categories":  "Category",
+		"addresses":   "Address",
+		"status":      "Status",
+		"2fa-codes":   "T2faCode",
+	}
+	for table, want := range cases {
+		if got := StructName(table); got != want {
+			t.Errorf("StructName(%q) = %s, want %s", table, got, want)
+		}
+	}
+}
@@ -10,6 +10,9 @@ import JsonViewer from './components/JsonViewer.js';
 // Import services
 import { callMethod } from './services/api.js';
 
+// Import utilities
+import { rpcMethodName } from './utils/types.js';
+
 // Application state
 const AppState = {
     currentEndpoint: null,
@@ -210,7 +213,7 @@ const App = {
                             
                             AppState.requestJson = {
                                 jsonrpc: '2.0',
-                                method: `${AppState.selectedInterface.name}.${AppState.selectedMethod.name}`,
+                                method: rpcMethodName(AppState.selectedInterface, AppState.selectedMethod),
                                 params: paramsArray,
                                 id: Date.now()
                             };
@@ -226,8 +229,7 @@ const App = {
 
                                 const response = await callMethod(
                                     AppState.currentEndpoint,
-                                    AppState.selectedInterface.name,
-                                    AppState.selectedMethod.name,
+                                    AppState.requestJson.method,
                                     paramsArray,
                                     headersMap
                                 );
@@ -84,7 +84,13 @@ export default {
                                 m('div.small.mt-1', [
                                     m('span.text-muted', 'Response: '),
                                     m('code', this.formatType(method.returnType))
-                                ])
+                                ]),
+                                method.examples && method.examples.map(example =>
+                                    m('div.small.mt-1.method-example', [
+                                        m('span.text-muted', 'Example: '),
+                                        m('code', JSON.stringify(example.params) + ' → ' + JSON.stringify(example.result))
+                                    ])
+                                )
                             ])
                         ])
                     )
@@ -20,7 +20,10 @@ describe('InterfaceBrowser Component', () => {
                             parameters: [
                                 { name: 'id', type: { builtIn: 'int' } }
                             ],
-                            returnType: { userDefined: 'User' }
+                            returnType: { userDefined: 'User' },
+                            examples: [
+                                { params: [1], result: { id: 1, name: 'Ann' } }
+                            ]
                         },
                         {
                             name: 'createUser',
@@ -82,6 +85,17 @@ describe('InterfaceBrowser Component', () => {
         });
     });
 
+    describe('Method examples', () => {
+        it('should show the IDL examples of a method', () => {
+            container = mountComponent(InterfaceBrowser, {
+                idl: idl,
+                onMethodSelect: onMethodSelectCallback
+            });
+
+            expect(screen.getByText('[1] → {"id":1,"name":"Ann"}')).toBeInTheDocument();
+        });
+    });
+
     describe('Expand/collapse interfaces', () => {
         it('should toggle interface expansion', () => {
             // Test that clicking toggle button changes expanded state
@@ -51,8 +51,7 @@ export async function discoverIDL(endpoint, customHeaders) {
 /**
  * Make an RPC call to a method
  */
-export async function callMethod(endpoint, interfaceName, methodName, params, customHeaders) {
-    const method = `${interfaceName}.${methodName}`;
+export async function callMethod(endpoint, method, params, customHeaders) {
     const request = {
         jsonrpc: '2.0',
         method: method,
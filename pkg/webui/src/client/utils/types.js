@@ -131,3 +131,17 @@ export function resolveType(typeDef) {
     return { kind: 'unknown' };
 }
 
+
+/**
+ * JSON-RPC method name of a method of an interface, following [wire] annotations
+ * the same way the generated clients do
+ */
+export function rpcMethodName(iface, method) {
+    const wire = (annotations) => (annotations || []).find(a => a.name === 'wire');
+    const own = wire(method.annotations);
+    if (own && !method.inheritedFrom) {
+        return own.value;
+    }
+    const prefix = wire(iface.annotations);
+    return `${prefix ? prefix.value : iface.name}.${method.name}`;
+}
@@ -4,7 +4,8 @@ import {
     findStruct,
     findEnum,
     getStructFields,
-    resolveType
+    resolveType,
+    rpcMethodName
 } from './types.js';
 
 describe('buildTypeRegistry', () => {
@@ -227,3 +228,24 @@ describe('resolveType', () => {
     });
 });
 
+
+describe('rpcMethodName', () => {
+    it('should join interface and method names by default', () => {
+        expect(rpcMethodName({ name: 'UserService' }, { name: 'get' })).toBe('UserService.get');
+    });
+
+    it('should use a method [wire] name as-is', () => {
+        const method = { name: 'get', annotations: [{ name: 'wire', value: 'v1.user.get' }] };
+        expect(rpcMethodName({ name: 'UserService' }, method)).toBe('v1.user.get');
+    });
+
+    it('should prefix methods with an interface [wire] name', () => {
+        const iface = { name: 'UserService', annotations: [{ name: 'wire', value: 'v1.user' }] };
+        expect(rpcMethodName(iface, { name: 'get' })).toBe('v1.user.get');
+    });
+
+    it('should ignore the [wire] name of an inherited method', () => {
+        const method = { name: 'ping', inheritedFrom: 'Base', annotations: [{ name: 'wire', value: 'v1.ping' }] };
+        expect(rpcMethodName({ name: 'UserService' }, method)).toBe('UserService.ping');
+    });
+});
@@ -67,9 +67,9 @@ fi
 echo -e "${YELLOW}Creating output directory: $OUTPUT_DIR${NC}"
 mkdir -p "$OUTPUT_DIR"
 
-# Step 3: Generate code with -generate-test-files flag
+# Step 3: Generate code with -generate-test-files and -generate-test-vectors flags
 echo -e "${YELLOW}Generating code from $TEST_IDL...${NC}"
-if ! "$BINARY_PATH" -plugin go-client-server -generate-test-files -dir "$OUTPUT_DIR" "$TEST_IDL"; then
+if ! "$BINARY_PATH" -plugin go-client-server -generate-test-files -generate-test-vectors -dir "$OUTPUT_DIR" "$TEST_IDL"; then
     echo -e "${RED}ERROR: Code generation failed${NC}"
     exit 1
 fi